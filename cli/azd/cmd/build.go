@@ -166,7 +166,7 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}
 
 	for _, svc := range stableServices {
-		stepMessage := fmt.Sprintf("Building service %s", svc.Name)
+		stepMessage := fmt.Sprintf("Building service %s", svc.TargetName())
 		ba.console.ShowSpinner(ctx, stepMessage, input.Step)
 
 		// Skip this service if both cases are true:
@@ -179,7 +179,7 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 		buildResult, err := async.RunWithProgress(
 			func(buildProgress project.ServiceProgress) {
-				progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, buildProgress.Message)
+				progressMessage := fmt.Sprintf("Building service %s (%s)", svc.TargetName(), buildProgress.Message)
 				ba.console.ShowSpinner(ctx, progressMessage, input.Step)
 			},
 			func(progress *async.Progress[project.ServiceProgress]) (*project.ServiceBuildResult, error) {
@@ -193,7 +193,7 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 
 		ba.console.StopSpinner(ctx, stepMessage, input.StepDone)
-		buildResults[svc.Name] = buildResult
+		buildResults[svc.TargetName()] = buildResult
 
 		// report build outputs
 		ba.console.MessageUxItem(ctx, buildResult)
@@ -47,6 +47,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/kubelogin"
 	"github.com/azure/azure-dev/cli/azd/pkg/kustomize"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/notifications"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
 	"github.com/azure/azure-dev/cli/azd/pkg/platform"
@@ -55,6 +56,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cosign"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
@@ -62,7 +64,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/notation"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
 	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
@@ -146,6 +150,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	client := createHttpClient()
 	ioc.RegisterInstance[policy.Transporter](container, client)
 	ioc.RegisterInstance[auth.HttpClient](container, client)
+	container.MustRegisterSingleton(notifications.NewNotifier)
 
 	// Auth
 	container.MustRegisterSingleton(auth.NewLoggedInGuard)
@@ -507,6 +512,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterScoped(project.NewProjectManager)
 	// Currently caches manifest across command executions
 	container.MustRegisterSingleton(project.NewDotNetImporter)
+	container.MustRegisterSingleton(project.NewComposeImporter)
 	container.MustRegisterScoped(project.NewImportManager)
 	container.MustRegisterScoped(project.NewServiceManager)
 
@@ -568,14 +574,20 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(account.NewSubscriptionsManager)
 	container.MustRegisterSingleton(account.NewSubscriptionCredentialProvider)
 	container.MustRegisterSingleton(azcli.NewManagedClustersService)
+	container.MustRegisterSingleton(azcli.NewUserAssignedIdentityService)
 	container.MustRegisterSingleton(entraid.NewEntraIdService)
 	container.MustRegisterSingleton(azcli.NewContainerRegistryService)
 	container.MustRegisterSingleton(containerapps.NewContainerAppService)
+	container.MustRegisterSingleton(containerapps.NewContainerAppJobService)
 	container.MustRegisterSingleton(containerregistry.NewRemoteBuildManager)
 	container.MustRegisterSingleton(keyvault.NewKeyVaultService)
 	container.MustRegisterSingleton(storage.NewFileShareService)
 	container.MustRegisterScoped(project.NewContainerHelper)
 	container.MustRegisterSingleton(azcli.NewSpringService)
+	container.MustRegisterSingleton(azcli.NewVirtualMachineService)
+	container.MustRegisterSingleton(azcli.NewIotHubService)
+	container.MustRegisterSingleton(azcli.NewStorageWebsiteService)
+	container.MustRegisterSingleton(azcli.NewBatchService)
 
 	container.MustRegisterSingleton(func(subManager *account.SubscriptionsManager) account.SubscriptionTenantResolver {
 		return subManager
@@ -587,6 +599,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	// Tools
 	container.MustRegisterSingleton(azapi.NewResourceService)
 	container.MustRegisterSingleton(docker.NewCli)
+	container.MustRegisterSingleton(cosign.NewCli)
+	container.MustRegisterSingleton(notation.NewCli)
+	container.MustRegisterSingleton(oras.NewCli)
 	container.MustRegisterSingleton(dotnet.NewCli)
 	container.MustRegisterSingleton(git.NewCli)
 	container.MustRegisterSingleton(github.NewGitHubCli)
@@ -654,11 +669,17 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.AppServiceTarget:         project.NewAppServiceTarget,
 		project.AzureFunctionTarget:      project.NewFunctionAppTarget,
 		project.ContainerAppTarget:       project.NewContainerAppTarget,
+		project.ContainerAppJobTarget:    project.NewContainerAppJobTarget,
 		project.StaticWebAppTarget:       project.NewStaticWebAppTarget,
 		project.AksTarget:                project.NewAksTarget,
 		project.SpringAppTarget:          project.NewSpringAppTarget,
 		project.DotNetContainerAppTarget: project.NewDotNetContainerAppTarget,
 		project.AiEndpointTarget:         project.NewAiEndpointTarget,
+		project.VirtualMachineTarget:     project.NewVirtualMachineTarget,
+		project.ApimTarget:               project.NewApimTarget,
+		project.IotEdgeTarget:            project.NewIotEdgeTarget,
+		project.StorageWebsiteTarget:     project.NewStorageWebsiteTarget,
+		project.BatchTarget:              project.NewBatchTarget,
 	}
 
 	for target, constructor := range serviceTargetMap {
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"os"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -15,6 +18,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -22,6 +28,7 @@ import (
 type downFlags struct {
 	forceDelete bool
 	purgeDelete bool
+	preview     string
 	global      *internal.GlobalCommandOptions
 	internal.EnvFlag
 }
@@ -35,6 +42,13 @@ func (i *downFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		//nolint:lll
 		"Does not require confirmation before it permanently deletes resources that are soft-deleted by default (for example, key vaults).",
 	)
+	local.StringVar(
+		&i.preview,
+		"preview",
+		"",
+		"Deletes only the named Static Web Apps preview environment, such as a branch or a pull request number, "+
+			"or 'auto' to derive it from the current pull request or branch.",
+	)
 	i.EnvFlag.Bind(local, global)
 	i.global = global
 }
@@ -58,6 +72,11 @@ type downAction struct {
 	provisionManager    *provisioning.Manager
 	importManager       *project.ImportManager
 	env                 *environment.Environment
+	envManager          environment.Manager
+	kubectlCli          *kubectl.Cli
+	gitCli              *git.Cli
+	azCli               azcli.AzCli
+	resourceManager     project.ResourceManager
 	console             input.Console
 	projectConfig       *project.ProjectConfig
 	alphaFeatureManager *alpha.FeatureManager
@@ -67,6 +86,11 @@ func newDownAction(
 	flags *downFlags,
 	provisionManager *provisioning.Manager,
 	env *environment.Environment,
+	envManager environment.Manager,
+	kubectlCli *kubectl.Cli,
+	gitCli *git.Cli,
+	azCli azcli.AzCli,
+	resourceManager project.ResourceManager,
 	projectConfig *project.ProjectConfig,
 	console input.Console,
 	alphaFeatureManager *alpha.FeatureManager,
@@ -76,6 +100,11 @@ func newDownAction(
 		flags:               flags,
 		provisionManager:    provisionManager,
 		env:                 env,
+		envManager:          envManager,
+		kubectlCli:          kubectlCli,
+		gitCli:              gitCli,
+		azCli:               azCli,
+		resourceManager:     resourceManager,
 		console:             console,
 		projectConfig:       projectConfig,
 		importManager:       importManager,
@@ -84,6 +113,10 @@ func newDownAction(
 }
 
 func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.preview != "" {
+		return a.deletePreview(ctx)
+	}
+
 	// Command title
 	a.console.MessageUxItem(ctx, &ux.MessageTitle{
 		Title:     "Deleting all resources and deployed code on Azure (azd down)",
@@ -111,6 +144,15 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, fmt.Errorf("deleting infrastructure: %w", err)
 	}
 
+	// The kube contexts of the deleted AKS clusters are stale and removed from the default kube config
+	// Failures are not fatal since the Azure resources have already been deleted
+	if _, err := project.RemoveKubeContexts(ctx, a.env, a.envManager, a.kubectlCli); err != nil {
+		log.Printf("failed removing kube contexts of environment '%s': %v", a.env.Name(), err)
+		a.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: Failed removing kube contexts. Run 'azd env cleanup-kube' to remove them.",
+		))
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
 			Header: fmt.Sprintf("Your application was removed from Azure in %s.", ux.DurationAsText(since(startTime))),
@@ -118,6 +160,49 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}, nil
 }
 
+// deletePreview deletes the Static Web Apps preview environment specified by '--preview', leaving the production
+// environment and the other resources of the application in place
+func (a *downAction) deletePreview(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     "Deleting the preview environment of the application (azd down --preview)",
+		TitleNote: "The production environment and the other resources of the application are not deleted.",
+	})
+
+	startTime := time.Now()
+
+	previewName, err := project.StaticWebAppPreviewName(
+		ctx, a.gitCli, a.projectConfig.Path, a.flags.preview, os.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for '--preview': %w", err)
+	}
+
+	if a.env.GetSubscriptionId() == "" {
+		return nil, errors.New("infrastructure has not been provisioned. Run `azd provision`")
+	}
+
+	services, err := project.DeleteStaticWebAppPreviews(
+		ctx, a.projectConfig, a.env, a.resourceManager, a.azCli, previewName)
+	if err != nil {
+		return nil, fmt.Errorf("deleting preview environment '%s': %w", previewName, err)
+	}
+
+	for _, service := range services {
+		a.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: fmt.Sprintf("Deleted preview environment %s of service %s", previewName, service),
+		})
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"The preview environment %s was removed from Azure in %s.",
+				previewName,
+				ux.DurationAsText(since(startTime)),
+			),
+		},
+	}, nil
+}
+
 func getCmdDownHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(fmt.Sprintf(
 		"Delete Azure resources for an application. Running %s will not delete application"+
@@ -131,5 +216,7 @@ func getCmdDownHelpFooter(*cobra.Command) string {
 		"Forcibly delete all applications resources without confirmation.": output.WithHighLightFormat("azd down --force"),
 		"Permanently delete resources that are soft-deleted by default," +
 			" without confirmation.": output.WithHighLightFormat("azd down --purge"),
+		"Delete the Static Web Apps preview environment of the current pull request" +
+			" or branch.": output.WithHighLightFormat("azd down --preview auto"),
 	})
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -85,6 +86,18 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvGetValueAction,
 	})
 
+	group.Add("cleanup-kube", &actions.ActionDescriptorOptions{
+		Command:        newEnvCleanupKubeCmd(),
+		FlagsResolver:  newEnvCleanupKubeFlags,
+		ActionResolver: newEnvCleanupKubeAction,
+	})
+
+	group.Add("clean-images", &actions.ActionDescriptorOptions{
+		Command:        newEnvCleanImagesCmd(),
+		FlagsResolver:  newEnvCleanImagesFlags,
+		ActionResolver: newEnvCleanImagesAction,
+	})
+
 	return group
 }
 
@@ -696,6 +709,232 @@ func (eg *envGetValueAction) Run(ctx context.Context) (*actions.ActionResult, er
 	return nil, nil
 }
 
+func newEnvCleanupKubeFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envCleanupKubeFlags {
+	flags := &envCleanupKubeFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvCleanupKubeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cleanup-kube",
+		Short: "Remove the kube contexts created by azd for the environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envCleanupKubeFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (ec *envCleanupKubeFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	ec.EnvFlag.Bind(local, global)
+	ec.global = global
+}
+
+type envCleanupKubeAction struct {
+	azdCtx     *azdcontext.AzdContext
+	console    input.Console
+	envManager environment.Manager
+	kubectlCli *kubectl.Cli
+	flags      *envCleanupKubeFlags
+}
+
+func newEnvCleanupKubeAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	console input.Console,
+	kubectlCli *kubectl.Cli,
+	flags *envCleanupKubeFlags,
+) actions.Action {
+	return &envCleanupKubeAction{
+		azdCtx:     azdCtx,
+		console:    console,
+		envManager: envManager,
+		kubectlCli: kubectlCli,
+		flags:      flags,
+	}
+}
+
+func (ec *envCleanupKubeAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name, err := ec.azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return nil, err
+	}
+
+	if ec.flags.EnvironmentName != "" {
+		name = ec.flags.EnvironmentName
+	}
+
+	env, err := ec.envManager.Get(ctx, name)
+	if errors.Is(err, environment.ErrNotFound) {
+		return nil, fmt.Errorf(
+			`environment '%s' does not exist. You can create it with "azd env new %s"`,
+			name,
+			name,
+		)
+	} else if err != nil {
+		return nil, fmt.Errorf("ensuring environment exists: %w", err)
+	}
+
+	removed, err := project.RemoveKubeContexts(ctx, env, ec.envManager, ec.kubectlCli)
+	for _, contextName := range removed {
+		ec.console.Message(ctx, fmt.Sprintf("Removed kube context %s", output.WithHighLightFormat(contextName)))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("No kube contexts were created by azd for environment '%s'", env.Name())
+	if len(removed) > 0 {
+		header = fmt.Sprintf("Removed %d kube context(s) of environment '%s'", len(removed), env.Name())
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: header,
+		},
+	}, nil
+}
+
+func newEnvCleanImagesFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envCleanImagesFlags {
+	flags := &envCleanImagesFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvCleanImagesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean-images [<service>]",
+		Short: "Delete the container images of earlier deployments from the registry of the environment.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+type envCleanImagesFlags struct {
+	internal.EnvFlag
+	keep   int
+	dryRun bool
+	global *internal.GlobalCommandOptions
+}
+
+func (ec *envCleanImagesFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	ec.EnvFlag.Bind(local, global)
+	ec.global = global
+
+	local.IntVar(
+		&ec.keep,
+		"keep",
+		0,
+		fmt.Sprintf(
+			"The number of the most recent images kept for each service. Defaults to the retention of the service or %d.",
+			project.DefaultImageRetentionKeep,
+		),
+	)
+	local.BoolVar(&ec.dryRun, "dry-run", false, "Lists the images that would be deleted without deleting them.")
+}
+
+type envCleanImagesAction struct {
+	console         input.Console
+	projectConfig   *project.ProjectConfig
+	importManager   *project.ImportManager
+	containerHelper *project.ContainerHelper
+	flags           *envCleanImagesFlags
+	args            []string
+}
+
+func newEnvCleanImagesAction(
+	console input.Console,
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	containerHelper *project.ContainerHelper,
+	flags *envCleanImagesFlags,
+	args []string,
+) actions.Action {
+	return &envCleanImagesAction{
+		console:         console,
+		projectConfig:   projectConfig,
+		importManager:   importManager,
+		containerHelper: containerHelper,
+		flags:           flags,
+		args:            args,
+	}
+}
+
+func (ec *envCleanImagesAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if ec.flags.keep < 0 {
+		return nil, errors.New("--keep must be at least 1")
+	}
+
+	targetServiceName := ""
+	if len(ec.args) == 1 {
+		targetServiceName = ec.args[0]
+		if _, has := ec.projectConfig.Services[targetServiceName]; !has {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", targetServiceName)
+		}
+	}
+
+	stableServices, err := ec.importManager.ServiceStable(ctx, ec.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := 0
+	for _, svc := range stableServices {
+		if targetServiceName != "" && targetServiceName != svc.Name {
+			continue
+		}
+
+		// Only the services azd builds and pushes images for have repositories managed by azd
+		if !svc.Host.RequiresContainer() || svc.RelativePath == "" {
+			continue
+		}
+
+		keep := ec.flags.keep
+		if keep == 0 {
+			keep = ec.containerHelper.RetentionKeep(svc)
+		}
+
+		stepMessage := fmt.Sprintf("Cleaning images of service %s", svc.Name)
+		ec.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		result, err := ec.containerHelper.CleanImages(ctx, svc, keep, ec.flags.dryRun)
+		if err != nil {
+			ec.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			return nil, err
+		}
+
+		ec.console.StopSpinner(ctx, stepMessage, input.StepDone)
+
+		for _, tag := range result.Deleted {
+			verb := "Deleted"
+			if ec.flags.dryRun {
+				verb = "Would delete"
+			}
+
+			ec.console.Message(ctx, fmt.Sprintf("  %s %s", verb,
+				output.WithHighLightFormat("%s/%s:%s", result.Registry, result.Repository, tag)))
+		}
+
+		deleted += len(result.Deleted)
+	}
+
+	header := fmt.Sprintf("Deleted %d image tag(s)", deleted)
+	if ec.flags.dryRun {
+		header = fmt.Sprintf("%d image tag(s) would be deleted", deleted)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: header,
+		},
+	}, nil
+}
+
 func getCmdEnvHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		"Manage your application environments. With this command group, you can create a new environment or get, set,"+
@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type execFlags struct {
+	container string
+	instance  string
+	global    *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (e *execFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&e.container,
+		"container",
+		"",
+		"The container to execute the command in. Defaults to the default container of the service.",
+	)
+	local.StringVar(
+		&e.instance,
+		"instance",
+		"",
+		"The instance of the service to execute the command in, such as the name of a pod. Defaults to a running instance.",
+	)
+	e.EnvFlag.Bind(local, global)
+	e.global = global
+}
+
+func newExecFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *execFlags {
+	flags := &execFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newExecCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "exec <service> [-- <command>...]",
+		Short: fmt.Sprintf("Execute a command in a running instance of a service. %s", output.WithWarningFormat("(Beta)")),
+		Args:  cobra.MinimumNArgs(1),
+	}
+}
+
+type execAction struct {
+	flags          *execFlags
+	args           []string
+	console        input.Console
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	serviceManager project.ServiceManager
+}
+
+func newExecAction(
+	flags *execFlags,
+	args []string,
+	console input.Console,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceManager project.ServiceManager,
+) actions.Action {
+	return &execAction{
+		flags:          flags,
+		args:           args,
+		console:        console,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceManager: serviceManager,
+	}
+}
+
+func (e *execAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	serviceName := e.args[0]
+	serviceConfig, has := e.projectConfig.Services[serviceName]
+	if !has {
+		return nil, fmt.Errorf("service name '%s' doesn't exist", serviceName)
+	}
+
+	if e.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Run `azd provision`",
+		)
+	}
+
+	if err := e.projectManager.Initialize(ctx, e.projectConfig); err != nil {
+		return nil, err
+	}
+
+	if err := e.projectManager.EnsureServiceTargetTools(ctx, e.projectConfig, func(svc *project.ServiceConfig) bool {
+		return svc.Name == serviceName
+	}); err != nil {
+		return nil, err
+	}
+
+	// A TTY is only allocated when azd itself is attached to a terminal, ex) not when the output is piped
+	err := e.serviceManager.Exec(ctx, serviceConfig, project.ServiceExecOptions{
+		Command:   e.args[1:],
+		Instance:  e.flags.instance,
+		Container: e.flags.container,
+		Tty:       input.IsTerminal(os.Stdout.Fd(), os.Stdin.Fd()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getCmdExecHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf("Execute a command in a running instance of a service. %s", output.WithWarningFormat("(Beta)")),
+		[]string{
+			formatHelpNote("Supported for services hosted on AKS, where the command runs in a pod of the service" +
+				" deployment."),
+			formatHelpNote("When no command is specified, an interactive shell is opened."),
+		})
+}
+
+func getCmdExecHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Open an interactive shell in a running instance of the service.": output.WithHighLightFormat(
+			"azd exec <service>",
+		),
+		"Print the environment variables of the api container.": output.WithHighLightFormat(
+			"azd exec <service> --container api -- env",
+		),
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type kubectlFlags struct {
+	service string
+	global  *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (k *kubectlFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&k.service,
+		"service",
+		"",
+		"The service whose cluster and namespace kubectl runs against. Required when multiple services are hosted on AKS.",
+	)
+	k.EnvFlag.Bind(local, global)
+	k.global = global
+}
+
+func newKubectlFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *kubectlFlags {
+	flags := &kubectlFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newKubectlCmd() *cobra.Command {
+	return &cobra.Command{
+		Use: "kubectl -- <args>...",
+		Short: fmt.Sprintf(
+			"Run kubectl against the AKS cluster of the environment. %s", output.WithWarningFormat("(Beta)"),
+		),
+		Args: cobra.MinimumNArgs(1),
+	}
+}
+
+type kubectlAction struct {
+	flags          *kubectlFlags
+	args           []string
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	serviceManager project.ServiceManager
+}
+
+func newKubectlAction(
+	flags *kubectlFlags,
+	args []string,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceManager project.ServiceManager,
+) actions.Action {
+	return &kubectlAction{
+		flags:          flags,
+		args:           args,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceManager: serviceManager,
+	}
+}
+
+func (k *kubectlAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	serviceConfig, err := k.aksService()
+	if err != nil {
+		return nil, err
+	}
+
+	if k.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Run `azd provision`",
+		)
+	}
+
+	if err := k.projectManager.Initialize(ctx, k.projectConfig); err != nil {
+		return nil, err
+	}
+
+	if err := k.projectManager.EnsureServiceTargetTools(ctx, k.projectConfig, func(svc *project.ServiceConfig) bool {
+		return svc.Name == serviceConfig.Name
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := k.serviceManager.RunKubectl(ctx, serviceConfig, k.args); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// aksService returns the service specified with --service, or the only service of the project hosted on AKS
+func (k *kubectlAction) aksService() (*project.ServiceConfig, error) {
+	if k.flags.service != "" {
+		serviceConfig, has := k.projectConfig.Services[k.flags.service]
+		if !has {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", k.flags.service)
+		}
+
+		return serviceConfig, nil
+	}
+
+	aksServices := []string{}
+	for name, serviceConfig := range k.projectConfig.Services {
+		if serviceConfig.Host == project.AksTarget {
+			aksServices = append(aksServices, name)
+		}
+	}
+
+	switch len(aksServices) {
+	case 0:
+		return nil, errors.New("the project does not have any services hosted on AKS")
+	case 1:
+		return k.projectConfig.Services[aksServices[0]], nil
+	default:
+		slices.Sort(aksServices)
+		return nil, fmt.Errorf(
+			"multiple services are hosted on AKS (%s), specify the service with --service",
+			strings.Join(aksServices, ", "),
+		)
+	}
+}
+
+func getCmdKubectlHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf("Run kubectl against the AKS cluster of the environment. %s", output.WithWarningFormat("(Beta)")),
+		[]string{
+			formatHelpNote("The kube context of the service is configured before the arguments are passed to" +
+				" kubectl as is, so commands run against the cluster and namespace of the current environment."),
+			formatHelpNote("Arguments after -- are passed to kubectl, including flags such as --namespace."),
+		})
+}
+
+func getCmdKubectlHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"List the pods in the namespace of the service.": output.WithHighLightFormat("azd kubectl -- get pods"),
+		"Restart the deployment of the api service.": output.WithHighLightFormat(
+			"azd kubectl --service api -- rollout restart deployment/api",
+		),
+	})
+}
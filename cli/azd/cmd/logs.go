@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type logsFlags struct {
+	container string
+	follow    bool
+	since     time.Duration
+	tail      int
+	global    *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (l *logsFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&l.container,
+		"container",
+		"",
+		"The container to stream the logs of. Defaults to all containers of the service.",
+	)
+	local.BoolVarP(&l.follow, "follow", "f", false, "Streams new log entries until the command is interrupted.")
+	local.DurationVar(
+		&l.since,
+		"since",
+		0,
+		"Only returns the log entries newer than the duration, such as 10m. Not supported for Container Apps.",
+	)
+	local.IntVar(&l.tail, "tail", 0, "The number of recent log entries to return for each container.")
+	l.EnvFlag.Bind(local, global)
+	l.global = global
+}
+
+func newLogsFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *logsFlags {
+	flags := &logsFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newLogsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs <service>",
+		Short: fmt.Sprintf("Stream the logs of a deployed service. %s", output.WithWarningFormat("(Beta)")),
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type logsAction struct {
+	flags          *logsFlags
+	args           []string
+	console        input.Console
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	serviceManager project.ServiceManager
+}
+
+func newLogsAction(
+	flags *logsFlags,
+	args []string,
+	console input.Console,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceManager project.ServiceManager,
+) actions.Action {
+	return &logsAction{
+		flags:          flags,
+		args:           args,
+		console:        console,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceManager: serviceManager,
+	}
+}
+
+func (l *logsAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	serviceName := l.args[0]
+	serviceConfig, has := l.projectConfig.Services[serviceName]
+	if !has {
+		return nil, fmt.Errorf("service name '%s' doesn't exist", serviceName)
+	}
+
+	if l.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Run `azd provision`",
+		)
+	}
+
+	if err := l.projectManager.Initialize(ctx, l.projectConfig); err != nil {
+		return nil, err
+	}
+
+	if err := l.projectManager.EnsureServiceTargetTools(ctx, l.projectConfig, func(svc *project.ServiceConfig) bool {
+		return svc.Name == serviceName
+	}); err != nil {
+		return nil, err
+	}
+
+	// The log entries are written as is so the output can be piped to other tools
+	err := l.serviceManager.StreamLogs(ctx, serviceConfig, project.ServiceLogsOptions{
+		Container: l.flags.container,
+		Follow:    l.flags.follow,
+		Since:     l.flags.since,
+		Tail:      l.flags.tail,
+		Writer:    l.console.Handles().Stdout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getCmdLogsHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf("Stream the logs of a deployed service. %s", output.WithWarningFormat("(Beta)")),
+		[]string{
+			formatHelpNote("Supported for services hosted on AKS and Azure Container Apps."),
+			formatHelpNote("When the service runs multiple pods, replicas or containers, each line is prefixed" +
+				" with the name of the container it was written by."),
+		})
+}
+
+func getCmdLogsHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Print the logs of all containers of the service.": output.WithHighLightFormat("azd logs <service>"),
+		"Stream new log entries of the service.":           output.WithHighLightFormat("azd logs <service> --follow"),
+		"Print the last 100 log entries of the api container.": output.WithHighLightFormat(
+			"azd logs <service> --container api --tail 100",
+		),
+	})
+}
@@ -355,7 +355,7 @@ func runMiddleware(
 		lazyEnvManager,
 		lazyEnv,
 		lazyProjectConfig,
-		project.NewImportManager(nil),
+		project.NewImportManager(nil, nil),
 		mockContext.CommandRunner,
 		mockContext.Console,
 		runOptions,
@@ -23,6 +23,7 @@ type packageFlags struct {
 	global *internal.GlobalCommandOptions
 	*internal.EnvFlag
 	outputPath string
+	progress   string
 }
 
 func newPackageFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *packageFlags {
@@ -51,6 +52,12 @@ func (pf *packageFlags) Bind(local *pflag.FlagSet, global *internal.GlobalComman
 		"",
 		"File or folder path where the generated packages will be saved.",
 	)
+	local.StringVar(
+		&pf.progress,
+		"build-progress",
+		"",
+		"The progress reported while building container images: auto, quiet or verbose.",
+	)
 }
 
 func newPackageCmd() *cobra.Command {
@@ -132,6 +139,17 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
+	if pa.flags.progress != "" {
+		mode, err := project.ParseDockerProgressMode(pa.flags.progress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for '--build-progress': %w", err)
+		}
+
+		for _, svc := range pa.projectConfig.Services {
+			svc.Docker.Progress = mode
+		}
+	}
+
 	if err := pa.projectManager.Initialize(ctx, pa.projectConfig); err != nil {
 		return nil, err
 	}
@@ -184,7 +202,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			continue
 		}
 
-		stepMessage := fmt.Sprintf("Packaging service %s", svc.Name)
+		stepMessage := fmt.Sprintf("Packaging service %s", svc.TargetName())
 		pa.console.ShowSpinner(ctx, stepMessage, input.Step)
 
 		// Skip this service if both cases are true:
@@ -198,7 +216,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		options := &project.PackageOptions{OutputPath: pa.flags.outputPath}
 		packageResult, err := async.RunWithProgress(
 			func(packageProgress project.ServiceProgress) {
-				progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.Name, packageProgress.Message)
+				progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.TargetName(), packageProgress.Message)
 				pa.console.ShowSpinner(ctx, progressMessage, input.Step)
 			},
 			func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePackageResult, error) {
@@ -210,7 +228,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		if err != nil {
 			return nil, err
 		}
-		packageResults[svc.Name] = packageResult
+		packageResults[svc.TargetName()] = packageResult
 
 		// report package output
 		pa.console.MessageUxItem(ctx, packageResult)
@@ -0,0 +1,145 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type portForwardFlags struct {
+	ports  []string
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (p *portForwardFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringArrayVarP(
+		&p.ports,
+		"port",
+		"p",
+		nil,
+		"The port to forward in the form [LOCAL_PORT:]REMOTE_PORT. Defaults to all ports exposed by the service.",
+	)
+	p.EnvFlag.Bind(local, global)
+	p.global = global
+}
+
+func newPortForwardFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *portForwardFlags {
+	flags := &portForwardFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newPortForwardCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "port-forward <service>",
+		Short: fmt.Sprintf("Forward local ports to a deployed service. %s", output.WithWarningFormat("(Beta)")),
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type portForwardAction struct {
+	flags          *portForwardFlags
+	args           []string
+	console        input.Console
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	serviceManager project.ServiceManager
+}
+
+func newPortForwardAction(
+	flags *portForwardFlags,
+	args []string,
+	console input.Console,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceManager project.ServiceManager,
+) actions.Action {
+	return &portForwardAction{
+		flags:          flags,
+		args:           args,
+		console:        console,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceManager: serviceManager,
+	}
+}
+
+func (p *portForwardAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	serviceName := p.args[0]
+	serviceConfig, has := p.projectConfig.Services[serviceName]
+	if !has {
+		return nil, fmt.Errorf("service name '%s' doesn't exist", serviceName)
+	}
+
+	if p.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Run `azd provision`",
+		)
+	}
+
+	p.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: fmt.Sprintf("Forwarding local ports to service %s (azd port-forward)", serviceName),
+	})
+
+	if err := p.projectManager.Initialize(ctx, p.projectConfig); err != nil {
+		return nil, err
+	}
+
+	if err := p.projectManager.EnsureServiceTargetTools(ctx, p.projectConfig, func(svc *project.ServiceConfig) bool {
+		return svc.Name == serviceName
+	}); err != nil {
+		return nil, err
+	}
+
+	p.console.Message(ctx, "Press Ctrl+C to stop forwarding.\n")
+
+	// Blocks until the command is interrupted
+	err := p.serviceManager.PortForward(ctx, serviceConfig, project.PortForwardOptions{
+		Ports:  p.flags.ports,
+		Writer: p.console.Handles().Stdout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getCmdPortForwardHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf("Forward local ports to a deployed service. %s", output.WithWarningFormat("(Beta)")),
+		[]string{
+			formatHelpNote("Forwards to the Kubernetes service of services hosted on AKS, which lets you reach" +
+				" ClusterIP services locally."),
+			formatHelpNote("The port forward is reconnected when it is closed, such as after a deployment."),
+		})
+}
+
+func getCmdPortForwardHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Forward all ports exposed by the service to the same local ports.": output.WithHighLightFormat(
+			"azd port-forward <service>",
+		),
+		"Forward local port 8080 to port 80 of the service.": output.WithHighLightFormat(
+			"azd port-forward <service> --port 8080:80",
+		),
+	})
+}
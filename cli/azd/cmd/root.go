@@ -125,6 +125,7 @@ func NewRootCmd(
 	templatesActions(root)
 	authActions(root)
 	hooksActions(root)
+	trafficActions(root)
 
 	root.Add("version", &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
@@ -291,6 +292,58 @@ func NewRootCmd(
 		}).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
+	root.Add("exec", &actions.ActionDescriptorOptions{
+		Command:        newExecCmd(),
+		FlagsResolver:  newExecFlags,
+		ActionResolver: newExecAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdExecHelpDescription,
+			Footer:      getCmdExecHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	root.Add("kubectl", &actions.ActionDescriptorOptions{
+		Command:        newKubectlCmd(),
+		FlagsResolver:  newKubectlFlags,
+		ActionResolver: newKubectlAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdKubectlHelpDescription,
+			Footer:      getCmdKubectlHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	root.Add("logs", &actions.ActionDescriptorOptions{
+		Command:        newLogsCmd(),
+		FlagsResolver:  newLogsFlags,
+		ActionResolver: newLogsAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdLogsHelpDescription,
+			Footer:      getCmdLogsHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	root.Add("port-forward", &actions.ActionDescriptorOptions{
+		Command:        newPortForwardCmd(),
+		FlagsResolver:  newPortForwardFlags,
+		ActionResolver: newPortForwardAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdPortForwardHelpDescription,
+			Footer:      getCmdPortForwardHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
 	root.Add("monitor", &actions.ActionDescriptorOptions{
 		Command:        newMonitorCmd(),
 		FlagsResolver:  newMonitorFlags,
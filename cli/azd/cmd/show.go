@@ -13,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -126,6 +127,7 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 				Path: path,
 				Type: showTypeFromLanguage(svc.Language),
 			},
+			Host: string(svc.Host),
 		}
 
 		res.Services[svc.Name] = showSvc
@@ -182,7 +184,19 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 						resSvc.Target = &contracts.ShowTargetArm{
 							ResourceIds: resourceIds,
 						}
-						resSvc.IngresUrl = s.serviceEndpoint(ctx, subId, serviceConfig, env)
+
+						st, targetResource := s.serviceTarget(ctx, subId, serviceConfig)
+						if targetResource != nil {
+							resSvc.Target.ResourceId = targetResourceId(targetResource)
+						}
+
+						endpoints := s.serviceEndpoints(ctx, serviceConfig, env, st, targetResource)
+						if len(endpoints) > 0 {
+							resSvc.IngresUrl = endpoints[0]
+						}
+
+						resSvc.Endpoints = showEndpoints(endpoints)
+						resSvc.Deployment = showDeployment(ctx, serviceConfig, env, st, targetResource)
 						res.Services[svcName] = resSvc
 					} else {
 						log.Printf("ignoring error determining resource id for service %s: %v", svcName, err)
@@ -222,6 +236,10 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			Name:      serviceName,
 			IngresUrl: service.IngresUrl,
 		}
+		if service.Deployment != nil && service.Deployment.Status != nil {
+			uxServices[index].Status = service.Deployment.Status.Status
+			uxServices[index].Revision = service.Deployment.Status.Revision
+		}
 		index++
 	}
 
@@ -235,29 +253,47 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	return nil, nil
 }
 
-func (s *showAction) serviceEndpoint(
-	ctx context.Context, subId string, serviceConfig *project.ServiceConfig, env *environment.Environment) string {
+// serviceTarget returns the service target of the service along with the resource the service is deployed to.
+// Nil values are returned when they can't be determined, the details they provide are then not shown.
+func (s *showAction) serviceTarget(
+	ctx context.Context, subId string, serviceConfig *project.ServiceConfig,
+) (project.ServiceTarget, *environment.TargetResource) {
 	resourceManager, err := s.lazyResourceManager.GetValue()
 	if err != nil {
 		log.Printf("error: getting lazy target-resource. Endpoints will be empty: %v", err)
-		return ""
+		return nil, nil
 	}
 	targetResource, err := resourceManager.GetTargetResource(ctx, subId, serviceConfig)
 	if err != nil {
 		log.Printf("error: getting target-resource. Endpoints will be empty: %v", err)
-		return ""
+		return nil, nil
 	}
 
 	serviceManager, err := s.lazyServiceManager.GetValue()
 	if err != nil {
 		log.Printf("error: getting lazy service manager. Endpoints will be empty: %v", err)
-		return ""
+		return nil, targetResource
 	}
 	st, err := serviceManager.GetServiceTarget(ctx, serviceConfig)
 	if err != nil {
 		log.Printf("error: getting service target. Endpoints will be empty: %v", err)
-		return ""
+		return nil, targetResource
 	}
+
+	return st, targetResource
+}
+
+func (s *showAction) serviceEndpoints(
+	ctx context.Context,
+	serviceConfig *project.ServiceConfig,
+	env *environment.Environment,
+	st project.ServiceTarget,
+	targetResource *environment.TargetResource,
+) []string {
+	if st == nil || targetResource == nil {
+		return nil
+	}
+
 	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
 	if err != nil {
 		log.Printf("error: getting service endpoints. Endpoints might be empty: %v", err)
@@ -268,11 +304,84 @@ func (s *showAction) serviceEndpoint(
 		endpoints = overriddenEndpoints
 	}
 
-	if len(endpoints) == 0 {
+	return endpoints
+}
+
+// targetResourceId returns the id of the resource the service is deployed to
+func targetResourceId(targetResource *environment.TargetResource) string {
+	if targetResource.ResourceType() == "" || targetResource.ResourceName() == "" {
 		return ""
 	}
 
-	return endpoints[0]
+	return fmt.Sprintf(
+		"%s/providers/%s/%s",
+		azure.ResourceGroupRID(targetResource.SubscriptionId(), targetResource.ResourceGroupName()),
+		targetResource.ResourceType(),
+		targetResource.ResourceName(),
+	)
+}
+
+// showDeployment returns the last deployment of the service recorded in the environment, along with the live status
+// of the service when its target reports it
+func showDeployment(
+	ctx context.Context,
+	serviceConfig *project.ServiceConfig,
+	env *environment.Environment,
+	st project.ServiceTarget,
+	targetResource *environment.TargetResource,
+) *contracts.ShowDeployment {
+	deployment := &contracts.ShowDeployment{}
+	if serviceConfig.Host.RequiresContainer() {
+		deployment.Image = env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+		deployment.ImageDigest = env.GetServiceProperty(serviceConfig.Name, "IMAGE_DIGEST")
+	}
+
+	if deployedAt := project.ServiceDeployedAt(serviceConfig, env); !deployedAt.IsZero() {
+		deployment.DeployedAt = &deployedAt
+	}
+
+	if statusReporter, ok := st.(project.ServiceTargetStatusReporter); ok && targetResource != nil {
+		status, err := statusReporter.Status(ctx, serviceConfig, targetResource)
+		if err != nil {
+			log.Printf("error: getting the status of service '%s'. Status will be empty: %v", serviceConfig.Name, err)
+		} else {
+			deployment.Status = &contracts.ShowServiceStatus{
+				Revision: status.Revision,
+				Status:   status.Status,
+				Details:  status.Details,
+				Image:    status.Image,
+			}
+		}
+	}
+
+	if *deployment == (contracts.ShowDeployment{}) {
+		return nil
+	}
+
+	return deployment
+}
+
+// showEndpoints returns the structured details of the endpoints of a service
+func showEndpoints(endpoints []string) []contracts.ShowEndpoint {
+	details := project.ParseServiceEndpoints(endpoints)
+	if len(details) == 0 {
+		return nil
+	}
+
+	showEndpoints := make([]contracts.ShowEndpoint, len(details))
+	for idx, detail := range details {
+		showEndpoints[idx] = contracts.ShowEndpoint{
+			Url:      detail.Url,
+			Kind:     string(detail.Kind),
+			Protocol: detail.Protocol,
+			Host:     detail.Host,
+			Port:     detail.Port,
+			Path:     detail.Path,
+			Internal: detail.Internal,
+		}
+	}
+
+	return showEndpoints
 }
 
 func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowType {
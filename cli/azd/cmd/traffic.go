@@ -0,0 +1,373 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func trafficActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("traffic", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use: "traffic",
+			Short: fmt.Sprintf(
+				"Split the traffic of a service between its revisions. %s", output.WithWarningFormat("(Beta)")),
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdTrafficHelpDescription,
+			Footer:      getCmdTrafficHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	group.Add("show", &actions.ActionDescriptorOptions{
+		Command:        newTrafficShowCmd(),
+		FlagsResolver:  newTrafficShowFlags,
+		ActionResolver: newTrafficShowAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	group.Add("shift", &actions.ActionDescriptorOptions{
+		Command:        newTrafficShiftCmd(),
+		FlagsResolver:  newTrafficShiftFlags,
+		ActionResolver: newTrafficShiftAction,
+	})
+
+	group.Add("promote", &actions.ActionDescriptorOptions{
+		Command:        newTrafficPromoteCmd(),
+		FlagsResolver:  newTrafficPromoteFlags,
+		ActionResolver: newTrafficPromoteAction,
+	})
+
+	return group
+}
+
+// trafficService returns the service of the project with the name, once the project is initialized
+func trafficService(
+	ctx context.Context,
+	serviceName string,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+) (*project.ServiceConfig, error) {
+	serviceConfig, has := projectConfig.Services[serviceName]
+	if !has {
+		return nil, fmt.Errorf("service name '%s' doesn't exist", serviceName)
+	}
+
+	if env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Run `azd provision`",
+		)
+	}
+
+	if err := projectManager.Initialize(ctx, projectConfig); err != nil {
+		return nil, err
+	}
+
+	return serviceConfig, nil
+}
+
+type trafficShowFlags struct {
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *trafficShowFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newTrafficShowFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *trafficShowFlags {
+	flags := &trafficShowFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTrafficShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <service>",
+		Short: "Show the revisions of a service and the percentage of the traffic routed to them.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type trafficShowAction struct {
+	args           []string
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	serviceManager project.ServiceManager
+	formatter      output.Formatter
+	writer         io.Writer
+}
+
+func newTrafficShowAction(
+	args []string,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceManager project.ServiceManager,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &trafficShowAction{
+		args:           args,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceManager: serviceManager,
+		formatter:      formatter,
+		writer:         writer,
+	}
+}
+
+func (t *trafficShowAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	serviceConfig, err := trafficService(ctx, t.args[0], t.env, t.projectConfig, t.projectManager)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := t.serviceManager.Revisions(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.formatter.Kind() == output.TableFormat {
+		columns := []output.Column{
+			{
+				Heading:       "REVISION",
+				ValueTemplate: "{{.Name}}",
+			},
+			{
+				Heading:       "ACTIVE",
+				ValueTemplate: "{{.Active}}",
+			},
+			{
+				Heading:       "TRAFFIC",
+				ValueTemplate: "{{.Traffic}}%",
+			},
+			{
+				Heading:       "HEALTH",
+				ValueTemplate: "{{.Health}}",
+			},
+			{
+				Heading:       "IMAGE",
+				ValueTemplate: "{{.Image}}",
+			},
+		}
+
+		err = t.formatter.Format(revisions, t.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = t.formatter.Format(revisions, t.writer, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type trafficShiftFlags struct {
+	revision string
+	global   *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *trafficShiftFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.revision,
+		"revision",
+		"",
+		"The revision the traffic is shifted to. Defaults to the latest revision of the service.",
+	)
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newTrafficShiftFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *trafficShiftFlags {
+	flags := &trafficShiftFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTrafficShiftCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shift <service> <percent>",
+		Short: "Route a percentage of the traffic of a service to a revision.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+func newTrafficShiftAction(
+	flags *trafficShiftFlags,
+	args []string,
+	console input.Console,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceManager project.ServiceManager,
+) actions.Action {
+	return &trafficShiftAction{
+		revision:       flags.revision,
+		args:           args,
+		console:        console,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceManager: serviceManager,
+	}
+}
+
+type trafficPromoteFlags struct {
+	revision string
+	global   *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *trafficPromoteFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.revision,
+		"revision",
+		"",
+		"The revision that is promoted. Defaults to the latest revision of the service.",
+	)
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newTrafficPromoteFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *trafficPromoteFlags {
+	flags := &trafficPromoteFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTrafficPromoteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "promote <service>",
+		Short: "Route all the traffic of a service to a revision.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+// Promoting a revision shifts all the traffic to the revision
+func newTrafficPromoteAction(
+	flags *trafficPromoteFlags,
+	args []string,
+	console input.Console,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceManager project.ServiceManager,
+) actions.Action {
+	return &trafficShiftAction{
+		revision:       flags.revision,
+		args:           append(args, "100"),
+		console:        console,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceManager: serviceManager,
+	}
+}
+
+type trafficShiftAction struct {
+	revision       string
+	args           []string
+	console        input.Console
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	serviceManager project.ServiceManager
+}
+
+func (t *trafficShiftAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	percent, err := strconv.Atoi(t.args[1])
+	if err != nil || percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("the percentage of the traffic must be a number between 0 and 100, got '%s'", t.args[1])
+	}
+
+	serviceConfig, err := trafficService(ctx, t.args[0], t.env, t.projectConfig, t.projectManager)
+	if err != nil {
+		return nil, err
+	}
+
+	stepMessage := fmt.Sprintf("Shifting traffic of service %s", serviceConfig.Name)
+	t.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	revisions, err := async.RunWithProgress(
+		func(progress project.ServiceProgress) {
+			progressMessage := fmt.Sprintf("Shifting traffic of service %s (%s)", serviceConfig.Name, progress.Message)
+			t.console.ShowSpinner(ctx, progressMessage, input.Step)
+		},
+		func(progress *async.Progress[project.ServiceProgress]) ([]*project.ServiceRevision, error) {
+			return t.serviceManager.ShiftTraffic(ctx, serviceConfig, t.revision, percent, progress)
+		},
+	)
+
+	t.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range revisions {
+		if revision.Traffic > 0 {
+			t.console.Message(ctx, fmt.Sprintf("  %s: %d%%", output.WithHighLightFormat(revision.Name), revision.Traffic))
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Traffic of service %s was shifted.", serviceConfig.Name),
+		},
+	}, nil
+}
+
+func getCmdTrafficHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf("Split the traffic of a service between its revisions. %s", output.WithWarningFormat("(Beta)")),
+		[]string{
+			formatHelpNote("Supported for services hosted on Azure Container Apps, which are switched to the" +
+				" multiple revisions mode."),
+			formatHelpNote("The rest of the traffic is routed to the other revision serving most of the traffic." +
+				" Previous revisions remain active after a promotion, so the traffic can be shifted back."),
+			formatHelpNote(fmt.Sprintf("Configure %s of a service to deploy new revisions with a percentage of the"+
+				" traffic.", output.WithHighLightFormat("containerApp.traffic"))),
+		})
+}
+
+func getCmdTrafficHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Show the revisions of the service.": output.WithHighLightFormat("azd traffic show <service>"),
+		"Route 50% of the traffic to the latest revision.": output.WithHighLightFormat(
+			"azd traffic shift <service> 50",
+		),
+		"Route all the traffic to the latest revision.": output.WithHighLightFormat("azd traffic promote <service>"),
+		"Route all the traffic back to a previous revision.": output.WithHighLightFormat(
+			"azd traffic promote <service> --revision <revision>",
+		),
+	})
+}
@@ -8,6 +8,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -17,14 +23,18 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/notifications"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -33,6 +43,16 @@ type DeployFlags struct {
 	serviceName string
 	All         bool
 	fromPackage string
+	fromImage   string
+	fromEnv     string
+	preview     bool
+	previewEnv  string
+	noRetry     bool
+	noSwap      bool
+	rollback    string
+	progress    string
+	parallel    int
+	changed     bool
 	global      *internal.GlobalCommandOptions
 	*internal.EnvFlag
 }
@@ -71,10 +91,78 @@ func (d *DeployFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCo
 		&d.fromPackage,
 		"from-package",
 		"",
-		"Deploys the application from an existing package.",
+		"Deploys the application from an existing package, or from a container image archive created with 'docker save'.",
+	)
+	local.StringVar(
+		&d.fromImage,
+		"from-image",
+		"",
+		"Deploys the application from a prebuilt container image without building or pushing it. Supported for AKS.",
+	)
+	local.StringVar(
+		&d.fromEnv,
+		"from-env",
+		"",
+		"Deploys the container images recorded in another environment without building or pushing them.",
+	)
+	local.BoolVar(
+		&d.preview,
+		"preview",
+		false,
+		"Previews the changes the deployment would apply to the target resources without deploying.",
+	)
+	local.StringVar(
+		&d.previewEnv,
+		"preview-env",
+		"",
+		"Deploys to the named preview environment, such as a branch or a pull request number, "+
+			"or 'auto' to derive it from the current pull request or branch. Supported for Static Web Apps.",
+	)
+	local.BoolVar(
+		&d.noRetry,
+		"no-retry",
+		false,
+		"Fails the deployment on the first transient error instead of retrying the failed operation. Supported for AKS.",
+	)
+	local.BoolVar(
+		&d.noSwap,
+		"no-swap",
+		false,
+		"Keeps the new version in the deployment slot without swapping it with production. "+
+			"Supported for App Service, Azure Functions and Spring Apps blue/green deployments.",
+	)
+	local.StringVar(
+		&d.rollback,
+		"rollback",
+		"",
+		"Reapplies a recorded revision of the service, or the previous revision when unspecified. "+
+			"Supported for AKS and Spring Apps blue/green deployments.",
+	)
+	local.Lookup("rollback").NoOptDefVal = rollbackPrevious
+	local.StringVar(
+		&d.progress,
+		"build-progress",
+		"",
+		"The progress reported while building and pushing container images: auto, quiet or verbose.",
+	)
+	local.IntVar(
+		&d.parallel,
+		"parallel",
+		1,
+		"Deploys up to the given number of services at a time. "+
+			"Services are deployed after the services listed in their 'dependsOn', AKS services one at a time.",
+	)
+	local.BoolVar(
+		&d.changed,
+		"changed",
+		false,
+		"Deploys only the services whose source changed since their last successful deployment.",
 	)
 }
 
+// The value of '--rollback' when no revision is specified, reapplying the revision before the latest one
+const rollbackPrevious = "previous"
+
 func (d *DeployFlags) SetCommon(envFlag *internal.EnvFlag) {
 	d.EnvFlag = envFlag
 }
@@ -98,7 +186,15 @@ func NewDeployCmd() *cobra.Command {
 		Use:   "deploy <service>",
 		Short: "Deploy the application's code to Azure.",
 	}
-	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		// The revision of '--rollback' is parsed as an argument since the flag value is optional,
+		// ex) azd deploy api --rollback 3
+		if flag := cmd.Flags().Lookup("rollback"); flag != nil && flag.Changed {
+			return cobra.MaximumNArgs(2)(cmd, args)
+		}
+
+		return cobra.MaximumNArgs(1)(cmd, args)
+	}
 
 	return cmd
 }
@@ -109,6 +205,7 @@ type DeployAction struct {
 	projectConfig       *project.ProjectConfig
 	azdCtx              *azdcontext.AzdContext
 	env                 *environment.Environment
+	envManager          environment.Manager
 	projectManager      project.ProjectManager
 	serviceManager      project.ServiceManager
 	resourceManager     project.ResourceManager
@@ -119,8 +216,13 @@ type DeployAction struct {
 	writer              io.Writer
 	console             input.Console
 	commandRunner       exec.CommandRunner
+	gitCli              *git.Cli
 	alphaFeatureManager *alpha.FeatureManager
 	importManager       *project.ImportManager
+	containerHelper     *project.ContainerHelper
+	userConfigManager   config.UserConfigManager
+	notifier            *notifications.Notifier
+	notification        deployNotification
 }
 
 func NewDeployAction(
@@ -132,15 +234,20 @@ func NewDeployAction(
 	resourceManager project.ResourceManager,
 	azdCtx *azdcontext.AzdContext,
 	environment *environment.Environment,
+	envManager environment.Manager,
 	accountManager account.Manager,
 	cloud *cloud.Cloud,
 	azCli azcli.AzCli,
 	commandRunner exec.CommandRunner,
+	gitCli *git.Cli,
 	console input.Console,
 	formatter output.Formatter,
 	writer io.Writer,
 	alphaFeatureManager *alpha.FeatureManager,
 	importManager *project.ImportManager,
+	containerHelper *project.ContainerHelper,
+	userConfigManager config.UserConfigManager,
+	notifier *notifications.Notifier,
 ) actions.Action {
 	return &DeployAction{
 		flags:               flags,
@@ -148,6 +255,7 @@ func NewDeployAction(
 		projectConfig:       projectConfig,
 		azdCtx:              azdCtx,
 		env:                 environment,
+		envManager:          envManager,
 		projectManager:      projectManager,
 		serviceManager:      serviceManager,
 		resourceManager:     resourceManager,
@@ -158,8 +266,12 @@ func NewDeployAction(
 		writer:              writer,
 		console:             console,
 		commandRunner:       commandRunner,
+		gitCli:              gitCli,
 		alphaFeatureManager: alphaFeatureManager,
 		importManager:       importManager,
+		containerHelper:     containerHelper,
+		userConfigManager:   userConfigManager,
+		notifier:            notifier,
 	}
 }
 
@@ -168,9 +280,14 @@ type DeploymentResult struct {
 	Services  map[string]*project.ServiceDeployResult `json:"services"`
 }
 
+type DeploymentPreviewResult struct {
+	Timestamp time.Time                                `json:"timestamp"`
+	Services  map[string]*project.ServicePreviewResult `json:"services"`
+}
+
 func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	targetServiceName := da.flags.serviceName
-	if len(da.args) == 1 {
+	if len(da.args) > 0 {
 		targetServiceName = da.args[0]
 	}
 
@@ -207,6 +324,60 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		)
 	}
 
+	if da.flags.preview && da.flags.fromPackage != "" {
+		return nil, errors.New("'--from-package' cannot be specified when '--preview' is set")
+	}
+
+	if da.flags.changed && (da.flags.fromPackage != "" || da.flags.fromEnv != "") {
+		return nil, errors.New("'--changed' cannot be specified with '--from-package' or '--from-env'")
+	}
+
+	if da.flags.changed && (da.flags.preview || da.flags.rollback != "") {
+		return nil, errors.New("'--changed' cannot be specified with '--preview' or '--rollback'")
+	}
+
+	if da.flags.fromImage != "" {
+		if err := da.useImage(targetServiceName); err != nil {
+			return nil, err
+		}
+	}
+
+	var sourceEnv *environment.Environment
+	if da.flags.fromEnv != "" {
+		if sourceEnv, err = da.promotionSource(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	rollbackRevision := -1
+	if da.flags.rollback != "" {
+		if rollbackRevision, err = da.rollbackRevision(targetServiceName); err != nil {
+			return nil, err
+		}
+	}
+
+	if da.flags.noRetry {
+		da.disableRetries()
+	}
+
+	if da.flags.noSwap {
+		if err := da.skipSwap(targetServiceName); err != nil {
+			return nil, err
+		}
+	}
+
+	if da.flags.previewEnv != "" {
+		if err := da.usePreviewEnvironment(ctx, targetServiceName); err != nil {
+			return nil, err
+		}
+	}
+
+	if da.flags.progress != "" {
+		if err := setBuildProgress(da.projectConfig, da.flags.progress); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := da.projectManager.Initialize(ctx, da.projectConfig); err != nil {
 		return nil, err
 	}
@@ -217,6 +388,14 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
+	if da.flags.preview {
+		return da.preview(ctx, targetServiceName)
+	}
+
+	if rollbackRevision >= 0 {
+		return da.rollback(ctx, targetServiceName, rollbackRevision)
+	}
+
 	// Command title
 	da.console.MessageUxItem(ctx, &ux.MessageTitle{
 		Title: "Deploying services (azd deploy)",
@@ -224,14 +403,65 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	startTime := time.Now()
 
-	deployResults := map[string]*project.ServiceDeployResult{}
 	stableServices, err := da.importManager.ServiceStable(ctx, da.projectConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, svc := range stableServices {
-		stepMessage := fmt.Sprintf("Deploying service %s", svc.Name)
+	var deployResults map[string]*project.ServiceDeployResult
+	if da.flags.parallel > 1 {
+		deployResults, err = da.deployServicesInParallel(ctx, stableServices, targetServiceName, sourceEnv)
+	} else {
+		deployResults, err = da.deployServices(ctx, stableServices, targetServiceName, sourceEnv)
+	}
+
+	da.notify(ctx, startTime, err)
+	if err != nil {
+		return nil, err
+	}
+
+	aspireDashboardUrl := apphost.AspireDashboardUrl(ctx, da.env, da.alphaFeatureManager)
+	if aspireDashboardUrl != nil {
+		da.console.MessageUxItem(ctx, aspireDashboardUrl)
+	}
+
+	if da.formatter.Kind() == output.JsonFormat {
+		deployResult := DeploymentResult{
+			Timestamp: time.Now(),
+			Services:  deployResults,
+		}
+
+		if fmtErr := da.formatter.Format(deployResult, da.writer, nil); fmtErr != nil {
+			return nil, fmt.Errorf("deploy result could not be displayed: %w", fmtErr)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Your application was deployed to Azure in %s.", ux.DurationAsText(since(startTime))),
+			FollowUp: getResourceGroupFollowUp(ctx,
+				da.formatter,
+				da.portalUrlBase,
+				da.projectConfig,
+				da.resourceManager,
+				da.env,
+				false,
+			),
+		},
+	}, nil
+}
+
+// deployServices deploys the services one at a time, in the order of their dependencies
+func (da *DeployAction) deployServices(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	targetServiceName string,
+	sourceEnv *environment.Environment,
+) (map[string]*project.ServiceDeployResult, error) {
+	deployResults := map[string]*project.ServiceDeployResult{}
+
+	for _, svc := range services {
+		stepMessage := fmt.Sprintf("Deploying service %s", svc.TargetName())
 		da.console.ShowSpinner(ctx, stepMessage, input.Step)
 
 		// Skip this service if both cases are true:
@@ -248,61 +478,384 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			da.console.WarnForFeature(ctx, alphaFeatureId)
 		}
 
-		var packageResult *project.ServicePackageResult
-		if da.flags.fromPackage != "" {
-			// --from-package set, skip packaging
-			packageResult = &project.ServicePackageResult{
-				PackagePath: da.flags.fromPackage,
+		deployResult, err := da.deployService(ctx, svc, sourceEnv, func(message string) {
+			progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.TargetName(), message)
+			da.console.ShowSpinner(ctx, progressMessage, input.Step)
+		})
+
+		if errors.Is(err, errServiceUnchanged) {
+			da.console.StopSpinner(ctx, fmt.Sprintf("%s (%s)", stepMessage, err.Error()), input.StepSkipped)
+			continue
+		}
+
+		if errors.Is(err, errNotContainerService) {
+			da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			da.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf("Service %s: %s", svc.TargetName(), err.Error()),
+			})
+			continue
+		}
+
+		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+
+		deployResults[svc.TargetName()] = deployResult
+
+		// report deploy outputs
+		da.console.MessageUxItem(ctx, deployResult)
+	}
+
+	return deployResults, nil
+}
+
+// deployServicesInParallel deploys up to '--parallel' services at a time, each service once the services it depends
+// on are deployed. A single spinner reports the progress of all the services being deployed.
+// The services of targets sharing CLIs between services, ex) AKS, are still deployed one at a time by the service manager
+func (da *DeployAction) deployServicesInParallel(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	targetServiceName string,
+	sourceEnv *environment.Environment,
+) (map[string]*project.ServiceDeployResult, error) {
+	services = slices.DeleteFunc(slices.Clone(services), func(svc *project.ServiceConfig) bool {
+		return targetServiceName != "" && targetServiceName != svc.Name
+	})
+
+	for _, svc := range services {
+		if alphaFeatureId, isAlphaFeature := alpha.IsFeatureKey(string(svc.Host)); isAlphaFeature {
+			da.console.WarnForFeature(ctx, alphaFeatureId)
+		}
+	}
+
+	deployResults := map[string]*project.ServiceDeployResult{}
+
+	// The services being deployed, in the order they were started, and their latest progress
+	var mu sync.Mutex
+	running := []string{}
+	progress := map[string]string{}
+	completed := 0
+
+	showProgress := func() {
+		if len(running) == 0 {
+			return
+		}
+
+		statuses := make([]string, 0, len(running))
+		for _, name := range running {
+			statuses = append(statuses, fmt.Sprintf("%s (%s)", name, progress[name]))
+		}
+
+		da.console.ShowSpinner(ctx, fmt.Sprintf(
+			"Deploying services (%d/%d): %s", completed, len(services), strings.Join(statuses, ", ")), input.Step)
+	}
+
+	err := project.RunInDependencyOrder(ctx, services, da.flags.parallel,
+		func(ctx context.Context, svc *project.ServiceConfig) error {
+			name := svc.TargetName()
+
+			mu.Lock()
+			running = append(running, name)
+			progress[name] = "Starting"
+			showProgress()
+			mu.Unlock()
+
+			deployResult, err := da.deployService(ctx, svc, sourceEnv, func(message string) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				progress[name] = message
+				showProgress()
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			running = slices.DeleteFunc(running, func(runningName string) bool { return runningName == name })
+			delete(progress, name)
+			completed++
+
+			stepMessage := fmt.Sprintf("Deploying service %s", name)
+			if errors.Is(err, errServiceUnchanged) {
+				da.console.StopSpinner(ctx, fmt.Sprintf("%s (%s)", stepMessage, err.Error()), input.StepSkipped)
+				showProgress()
+				return nil
+			}
+
+			if errors.Is(err, errNotContainerService) {
+				da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+				da.console.MessageUxItem(ctx, &ux.WarningMessage{
+					Description: fmt.Sprintf("Service %s: %s", name, err.Error()),
+				})
+				showProgress()
+				return nil
 			}
-		} else {
-			//  --from-package not set, package the application
-			packageResult, err = async.RunWithProgress(
-				func(packageProgress project.ServiceProgress) {
-					progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, packageProgress.Message)
-					da.console.ShowSpinner(ctx, progressMessage, input.Step)
-				},
-				func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePackageResult, error) {
-					return da.serviceManager.Package(ctx, svc, nil, progress, nil)
-				},
-			)
-
-			// do not stop progress here as next step is to deploy
-			if err != nil {
-				da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
-				return nil, err
+
+			da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+			if err == nil {
+				deployResults[name] = deployResult
+
+				// report deploy outputs
+				da.console.MessageUxItem(ctx, deployResult)
 			}
+
+			showProgress()
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return deployResults, nil
+}
+
+// errServiceUnchanged is returned when deploying a service with '--changed' whose source is unchanged since the
+// last successful deployment of the service
+var errServiceUnchanged = errors.New("unchanged since the last deployment")
+
+// errNotContainerService is returned when deploying a service not deployed as a container with '--from-env'
+var errNotContainerService = errors.New("'--from-env' is only supported for services deployed as containers")
+
+// deployService packages and deploys the service, reporting the progress of packaging and deploying the service
+func (da *DeployAction) deployService(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	sourceEnv *environment.Environment,
+	showProgress func(message string),
+) (deployResult *project.ServiceDeployResult, err error) {
+	// The result of the service is sent to the notification targets, services skipped are not reported
+	startTime := time.Now()
+	defer func() {
+		if !errors.Is(err, errServiceUnchanged) && !errors.Is(err, errNotContainerService) {
+			da.recordService(svc, deployResult, err, since(startTime))
 		}
+	}()
+
+	var packageResult *project.ServicePackageResult
 
-		deployResult, err := async.RunWithProgress(
-			func(deployProgress project.ServiceProgress) {
-				progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, deployProgress.Message)
+	// The hash of the source of the service is recorded once the service is deployed from source, '--changed' skips
+	// the service until its source changes
+	deployHash := ""
+	if sourceEnv == nil && da.flags.fromPackage == "" {
+		if deployHash, err = project.ServiceDeployHash(svc, da.env); err != nil {
+			log.Printf("failed hashing the source of service '%s': %v", svc.Name, err)
+		}
+
+		if da.flags.changed && project.IsServiceUnchanged(svc, da.env, deployHash) {
+			return nil, errServiceUnchanged
+		}
+	}
+
+	if sourceEnv != nil {
+		// --from-env set, deploy the image recorded in the source environment
+		if !svc.Host.RequiresContainer() {
+			return nil, errNotContainerService
+		}
+
+		packageResult, err = da.containerHelper.PromoteImage(ctx, svc, sourceEnv)
+		if err != nil {
+			return nil, err
+		}
+	} else if da.flags.fromPackage != "" {
+		// --from-package set, skip packaging
+		packageResult = &project.ServicePackageResult{
+			PackagePath: da.flags.fromPackage,
+		}
+	} else {
+		//  --from-package not set, package the application
+		packageResult, err = async.RunWithProgress(
+			func(packageProgress project.ServiceProgress) {
+				showProgress(packageProgress.Message)
+			},
+			func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePackageResult, error) {
+				return da.serviceManager.Package(ctx, svc, nil, progress, nil)
+			},
+		)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deployResult, err = async.RunWithProgress(
+		func(deployProgress project.ServiceProgress) {
+			showProgress(deployProgress.Message)
+		},
+		func(progress *async.Progress[project.ServiceProgress]) (*project.ServiceDeployResult, error) {
+			return da.serviceManager.Deploy(ctx, svc, packageResult, progress)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The time of the deployment is reported by 'azd show'
+	project.SetServiceDeployedAt(svc, da.env, time.Now())
+	if deployHash != "" {
+		project.SetServiceDeployHash(svc, da.env, deployHash)
+	}
+
+	if err := da.envManager.Save(ctx, da.env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return deployResult, nil
+}
+
+// preview displays the changes deploying the services would apply without deploying them.
+// Services are not packaged, and services whose target does not support previews are skipped.
+func (da *DeployAction) preview(ctx context.Context, targetServiceName string) (*actions.ActionResult, error) {
+	// Command title
+	da.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Previewing service deployments (azd deploy --preview)",
+	})
+
+	startTime := time.Now()
+
+	previewResults := map[string]*project.ServicePreviewResult{}
+	stableServices, err := da.importManager.ServiceStable(ctx, da.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, svc := range stableServices {
+		stepMessage := fmt.Sprintf("Previewing service %s", svc.TargetName())
+		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		if targetServiceName != "" && targetServiceName != svc.Name {
+			da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			continue
+		}
+
+		previewResult, err := async.RunWithProgress(
+			func(previewProgress project.ServiceProgress) {
+				progressMessage := fmt.Sprintf("Previewing service %s (%s)", svc.TargetName(), previewProgress.Message)
 				da.console.ShowSpinner(ctx, progressMessage, input.Step)
 			},
-			func(progress *async.Progress[project.ServiceProgress]) (*project.ServiceDeployResult, error) {
-				return da.serviceManager.Deploy(ctx, svc, packageResult, progress)
+			func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePreviewResult, error) {
+				return da.serviceManager.Preview(ctx, svc, nil, progress)
 			},
 		)
 
+		if errors.Is(err, project.ErrPreviewNotSupported) {
+			da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			da.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf("Service %s: %s", svc.TargetName(), err.Error()),
+			})
+			continue
+		}
+
 		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
 		if err != nil {
 			return nil, err
 		}
 
-		deployResults[svc.Name] = deployResult
+		previewResults[svc.TargetName()] = previewResult
 
-		// report deploy outputs
-		da.console.MessageUxItem(ctx, deployResult)
+		// report preview outputs
+		da.console.MessageUxItem(ctx, previewResult)
 	}
 
-	aspireDashboardUrl := apphost.AspireDashboardUrl(ctx, da.env, da.alphaFeatureManager)
-	if aspireDashboardUrl != nil {
-		da.console.MessageUxItem(ctx, aspireDashboardUrl)
+	if da.formatter.Kind() == output.JsonFormat {
+		previewResult := DeploymentPreviewResult{
+			Timestamp: time.Now(),
+			Services:  previewResults,
+		}
+
+		if fmtErr := da.formatter.Format(previewResult, da.writer, nil); fmtErr != nil {
+			return nil, fmt.Errorf("deploy preview result could not be displayed: %w", fmtErr)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Your deployment preview was generated in %s. No changes were applied.",
+				ux.DurationAsText(since(startTime)),
+			),
+		},
+	}, nil
+}
+
+// rollbackRevision returns the revision specified by '--rollback', or 0 for the previous revision
+func (da *DeployAction) rollbackRevision(targetServiceName string) (int, error) {
+	if da.flags.All || targetServiceName == "" {
+		return 0, errors.New(
+			//nolint:lll
+			"'--rollback' cannot be specified when deploying all services. Specify a specific service by passing a <service>",
+		)
+	}
+
+	if da.flags.fromPackage != "" || da.flags.fromImage != "" || da.flags.fromEnv != "" || da.flags.preview {
+		return 0, errors.New(
+			"'--rollback' cannot be specified with '--from-package', '--from-image', '--from-env' or '--preview'")
+	}
+
+	value := da.flags.rollback
+	if len(da.args) > 1 {
+		if value != rollbackPrevious {
+			return 0, fmt.Errorf("unexpected argument '%s'", da.args[1])
+		}
+
+		value = da.args[1]
+	}
+
+	if value == rollbackPrevious {
+		return 0, nil
+	}
+
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision <= 0 {
+		return 0, fmt.Errorf("invalid revision '%s', the revision must be a positive number", value)
+	}
+
+	return revision, nil
+}
+
+// rollback reapplies a revision recorded within the deployment history of the target service
+func (da *DeployAction) rollback(
+	ctx context.Context,
+	targetServiceName string,
+	revision int,
+) (*actions.ActionResult, error) {
+	svc, has := da.projectConfig.Services[targetServiceName]
+	if !has {
+		return nil, fmt.Errorf("service '%s' not found", targetServiceName)
+	}
+
+	// Command title
+	da.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Rolling back service (azd deploy --rollback)",
+	})
+
+	startTime := time.Now()
+	stepMessage := fmt.Sprintf("Rolling back service %s", svc.TargetName())
+	da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	deployResult, err := async.RunWithProgress(
+		func(deployProgress project.ServiceProgress) {
+			progressMessage := fmt.Sprintf("Rolling back service %s (%s)", svc.TargetName(), deployProgress.Message)
+			da.console.ShowSpinner(ctx, progressMessage, input.Step)
+		},
+		func(progress *async.Progress[project.ServiceProgress]) (*project.ServiceDeployResult, error) {
+			return da.serviceManager.Rollback(ctx, svc, revision, progress)
+		},
+	)
+
+	da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
 	}
 
+	// report deploy outputs
+	da.console.MessageUxItem(ctx, deployResult)
+
 	if da.formatter.Kind() == output.JsonFormat {
 		deployResult := DeploymentResult{
 			Timestamp: time.Now(),
-			Services:  deployResults,
+			Services:  map[string]*project.ServiceDeployResult{svc.Name: deployResult},
 		}
 
 		if fmtErr := da.formatter.Format(deployResult, da.writer, nil); fmtErr != nil {
@@ -312,19 +865,147 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: fmt.Sprintf("Your application was deployed to Azure in %s.", ux.DurationAsText(since(startTime))),
-			FollowUp: getResourceGroupFollowUp(ctx,
-				da.formatter,
-				da.portalUrlBase,
-				da.projectConfig,
-				da.resourceManager,
-				da.env,
-				false,
-			),
+			Header: fmt.Sprintf("Your service was rolled back in %s.", ux.DurationAsText(since(startTime))),
 		},
 	}, nil
 }
 
+// useImage configures the target service to deploy the prebuilt container image specified by '--from-image'
+func (da *DeployAction) useImage(targetServiceName string) error {
+	if da.flags.All || targetServiceName == "" {
+		return errors.New(
+			//nolint:lll
+			"'--from-image' cannot be specified when deploying all services. Specify a specific service by passing a <service>",
+		)
+	}
+
+	if da.flags.fromPackage != "" {
+		return errors.New("'--from-image' cannot be specified when '--from-package' is set")
+	}
+
+	svc, has := da.projectConfig.Services[targetServiceName]
+	if !has {
+		return fmt.Errorf("service '%s' not found", targetServiceName)
+	}
+
+	if svc.Host != project.AksTarget {
+		return fmt.Errorf("'--from-image' is only supported for services hosted on '%s'", project.AksTarget)
+	}
+
+	svc.K8s.Image = osutil.NewExpandableString(da.flags.fromImage)
+
+	return nil
+}
+
+// promotionSource returns the environment specified by '--from-env' the container images are promoted from
+func (da *DeployAction) promotionSource(ctx context.Context) (*environment.Environment, error) {
+	if da.flags.fromPackage != "" || da.flags.fromImage != "" || da.flags.preview {
+		return nil, errors.New("'--from-env' cannot be specified with '--from-package', '--from-image' or '--preview'")
+	}
+
+	if da.flags.fromEnv == da.env.Name() {
+		return nil, fmt.Errorf("'--from-env' must specify an environment other than the current environment '%s'",
+			da.env.Name())
+	}
+
+	sourceEnv, err := da.envManager.Get(ctx, da.flags.fromEnv)
+	if errors.Is(err, environment.ErrNotFound) {
+		return nil, fmt.Errorf("environment '%s' does not exist", da.flags.fromEnv)
+	} else if err != nil {
+		return nil, fmt.Errorf("loading environment '%s': %w", da.flags.fromEnv, err)
+	}
+
+	return sourceEnv, nil
+}
+
+// disableRetries disables the retries of operations that fail with transient errors for the AKS services
+func (da *DeployAction) disableRetries() {
+	for _, svc := range da.projectConfig.Services {
+		if svc.Host == project.AksTarget {
+			svc.K8s.Retry = &project.AksRetryOptions{Disabled: true}
+		}
+	}
+}
+
+// skipSwap keeps the new version of the App Service and Azure Functions services deployed to a slot in the slot, and of
+// the Spring Apps services deployed blue/green in the staging deployment, without swapping them with production
+func (da *DeployAction) skipSwap(targetServiceName string) error {
+	slotDeployed := false
+	for _, svc := range da.projectConfig.Services {
+		if targetServiceName != "" && svc.Name != targetServiceName {
+			continue
+		}
+
+		switch {
+		case svc.Host == project.AppServiceTarget && !svc.AppService.Slot.Empty():
+			svc.AppService.NoSwap = true
+			slotDeployed = true
+		case svc.Host == project.AzureFunctionTarget && !svc.FunctionApp.Slot.Empty():
+			svc.FunctionApp.NoSwap = true
+			slotDeployed = true
+		case svc.Host == project.SpringAppTarget && svc.Spring.BlueGreen != nil:
+			svc.Spring.BlueGreen.NoSwap = true
+			slotDeployed = true
+		}
+	}
+
+	if !slotDeployed {
+		return fmt.Errorf(
+			"'--no-swap' requires a service hosted on '%s' or '%s' with a deployment slot, "+
+				"or on '%s' with blue/green deployments, "+
+				"configure 'appService.slot', 'functionApp.slot' or 'spring.blueGreen'",
+			project.AppServiceTarget,
+			project.AzureFunctionTarget,
+			project.SpringAppTarget,
+		)
+	}
+
+	return nil
+}
+
+// usePreviewEnvironment deploys the Static Web Apps services to the preview environment specified by '--preview-env'
+// instead of the production environment
+func (da *DeployAction) usePreviewEnvironment(ctx context.Context, targetServiceName string) error {
+	previewName, err := project.StaticWebAppPreviewName(
+		ctx, da.gitCli, da.projectConfig.Path, da.flags.previewEnv, os.Getenv)
+	if err != nil {
+		return fmt.Errorf("invalid value for '--preview-env': %w", err)
+	}
+
+	previewDeployed := false
+	for _, svc := range da.projectConfig.Services {
+		if targetServiceName != "" && svc.Name != targetServiceName {
+			continue
+		}
+
+		if svc.Host == project.StaticWebAppTarget {
+			svc.StaticWebApp.Preview = previewName
+			previewDeployed = true
+		}
+	}
+
+	if !previewDeployed {
+		return fmt.Errorf("'--preview-env' requires a service hosted on '%s'", project.StaticWebAppTarget)
+	}
+
+	return nil
+}
+
+// setBuildProgress sets the progress of the container image builds and pushes of the services to the mode specified by
+// '--build-progress'
+func setBuildProgress(projectConfig *project.ProjectConfig, value string) error {
+	mode, err := project.ParseDockerProgressMode(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for '--build-progress': %w", err)
+	}
+
+	for _, svc := range projectConfig.Services {
+		svc.Docker.Progress = mode
+	}
+
+	return nil
+}
+
 func GetCmdDeployHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription("Deploy application to Azure.", []string{
 		formatHelpNote(
@@ -351,5 +1032,20 @@ func GetCmdDeployHelpFooter(*cobra.Command) string {
 		"Deploy the service named 'api' to Azure from a previously generated package.": output.WithHighLightFormat(
 			"azd deploy api --from-package <package-path>",
 		),
+		"Deploy the images of the 'staging' environment to the current environment without rebuilding them.": output.
+			WithHighLightFormat("azd deploy --from-env staging"),
+		"Deploy the service named 'api' to Azure from a container image archive created with 'docker save'.": output.
+			WithHighLightFormat("azd deploy api --from-package <image-archive>.tar"),
+		"Preview the changes deploying the service named 'api' would apply to Azure.": output.WithHighLightFormat(
+			"azd deploy api --preview",
+		),
+		"Deploy the service named 'api' to AKS from a prebuilt container image.": output.WithHighLightFormat(
+			"azd deploy api --from-image <image>",
+		),
+		"Roll back the service named 'api' on AKS, or on Spring Apps, to its previous revision.": output.
+			WithHighLightFormat("azd deploy api --rollback"),
+		"Roll back the service named 'api' on AKS to revision 3.": output.WithHighLightFormat(
+			"azd deploy api --rollback 3",
+		),
 	})
 }
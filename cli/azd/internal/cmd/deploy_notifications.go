@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/notifications"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// deployNotification collects the results of the services deployed, sent to the notification targets once the
+// deployment completes
+type deployNotification struct {
+	mu       sync.Mutex
+	services []*notifications.ServiceResult
+}
+
+// recordService records the result of the deployment of the service
+func (da *DeployAction) recordService(
+	svc *project.ServiceConfig,
+	deployResult *project.ServiceDeployResult,
+	err error,
+	duration time.Duration,
+) {
+	serviceResult := &notifications.ServiceResult{
+		Name:     svc.Name,
+		Success:  err == nil,
+		Duration: duration.Seconds(),
+	}
+
+	if err != nil {
+		serviceResult.Error = err.Error()
+	}
+
+	if deployResult != nil {
+		serviceResult.Endpoints = deployResult.Endpoints
+	}
+
+	// The image deployed is recorded in the environment, referenced by its digest when the digest is pinned
+	if image := da.env.GetServiceProperty(svc.Name, "IMAGE_NAME"); image != "" && svc.Host.RequiresContainer() {
+		serviceResult.Image = image
+		if _, digest, has := strings.Cut(image, "@"); has {
+			serviceResult.ImageDigest = digest
+		}
+	}
+
+	da.notification.mu.Lock()
+	defer da.notification.mu.Unlock()
+	da.notification.services = append(da.notification.services, serviceResult)
+}
+
+// notify sends the result of the deployment to the notification targets of the project and the user configuration.
+// A failed notification does not fail the deployment, a warning is displayed instead.
+func (da *DeployAction) notify(ctx context.Context, startTime time.Time, deployErr error) {
+	userConfig, err := da.userConfigManager.Load()
+	if err != nil {
+		log.Printf("failed loading user config, skipping notifications: %v", err)
+		return
+	}
+
+	targets, err := notifications.Targets(da.projectConfig.Notifications, userConfig)
+	if err != nil {
+		da.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("Skipped sending deploy notifications: %s", err.Error()),
+		})
+		return
+	}
+
+	if len(targets) == 0 {
+		return
+	}
+
+	da.notification.mu.Lock()
+	result := &notifications.DeployResult{
+		Project:     da.projectConfig.Name,
+		Environment: da.env.Name(),
+		Success:     deployErr == nil,
+		Timestamp:   time.Now(),
+		Duration:    since(startTime).Seconds(),
+		Services:    da.notification.services,
+	}
+	da.notification.mu.Unlock()
+
+	if deployErr != nil {
+		result.Error = deployErr.Error()
+	}
+
+	if err := da.notifier.NotifyDeploy(ctx, targets, da.env.Getenv, result); err != nil {
+		da.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("Failed sending deploy notifications: %s", err.Error()),
+		})
+	}
+}
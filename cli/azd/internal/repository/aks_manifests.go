@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// The folder of the k8s manifests of an AKS service when the deployment path isn't configured
+const defaultAksDeploymentPath = "manifests"
+
+// scaffoldAksManifests generates starter k8s manifests for the services hosted on AKS without any manifests,
+// so that the services can be deployed instead of failing at deploy time.
+// Services deployed with helm or kustomize are skipped, since their manifests are not read from the deployment path.
+func (i *Initializer) scaffoldAksManifests(ctx context.Context, azdCtx *azdcontext.AzdContext) error {
+	prjConfig, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		return fmt.Errorf("loading project config: %w", err)
+	}
+
+	serviceNames := []string{}
+	for name, svc := range prjConfig.Services {
+		if svc.Host == project.AksTarget && svc.K8s.Helm == nil && svc.K8s.Kustomize == nil {
+			serviceNames = append(serviceNames, name)
+		}
+	}
+
+	if len(serviceNames) == 0 {
+		return nil
+	}
+
+	slices.Sort(serviceNames)
+
+	t, err := scaffold.Load()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range serviceNames {
+		svc := prjConfig.Services[name]
+		deploymentPath := svc.K8s.DeploymentPath
+		if deploymentPath == "" {
+			deploymentPath = defaultAksDeploymentPath
+		}
+
+		manifestsPath := filepath.Join(svc.Path(), deploymentPath)
+		if _, err := os.Stat(manifestsPath); !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		dockerfilePath := svc.Docker.Path
+		if dockerfilePath == "" {
+			dockerfilePath = "Dockerfile"
+		}
+
+		if !filepath.IsAbs(dockerfilePath) {
+			dockerfilePath = filepath.Join(svc.Path(), dockerfilePath)
+		}
+
+		port, err := scaffold.DockerfilePort(dockerfilePath)
+		if err != nil {
+			log.Printf("reading the exposed port of %s: %v", dockerfilePath, err)
+		}
+
+		spec := scaffold.NewK8sSpec(name, port)
+		if err := scaffold.ExecK8sManifests(t, spec, manifestsPath); err != nil {
+			return fmt.Errorf("scaffolding k8s manifests of service '%s': %w", name, err)
+		}
+
+		relativePath, err := filepath.Rel(azdCtx.ProjectDirectory(), manifestsPath)
+		if err != nil || strings.HasPrefix(relativePath, "..") {
+			relativePath = manifestsPath
+		}
+
+		i.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: fmt.Sprintf(
+				"Generated starter k8s manifests for service '%s' at: %s",
+				name,
+				output.WithHighLightFormat(relativePath),
+			),
+		})
+	}
+
+	return nil
+}
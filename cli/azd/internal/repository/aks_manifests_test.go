@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Initializer_ScaffoldAksManifests(t *testing.T) {
+	projectDir := t.TempDir()
+	azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+
+	azureYaml := `name: test
+services:
+  api:
+    project: ./src/api
+    language: js
+    host: aks
+  web:
+    project: ./src/web
+    language: js
+    host: aks
+  chart:
+    project: ./src/chart
+    host: aks
+    k8s:
+      helm:
+        releases:
+          - name: redis
+            chart: bitnami/redis
+  func:
+    project: ./src/func
+    language: js
+    host: function
+`
+	require.NoError(t, os.WriteFile(azdCtx.ProjectPath(), []byte(azureYaml), osutil.PermissionFile))
+
+	apiPath := filepath.Join(projectDir, "src", "api")
+	require.NoError(t, os.MkdirAll(apiPath, osutil.PermissionDirectory))
+	dockerfile := "FROM node:20\nEXPOSE 3100/tcp\nCMD [\"npm\", \"start\"]\n"
+	require.NoError(t, os.WriteFile(filepath.Join(apiPath, "Dockerfile"), []byte(dockerfile), osutil.PermissionFile))
+
+	// The existing manifests of the web service are left untouched
+	webManifestsPath := filepath.Join(projectDir, "src", "web", "manifests")
+	require.NoError(t, os.MkdirAll(webManifestsPath, osutil.PermissionDirectory))
+
+	mockContext := mocks.NewMockContext(context.Background())
+	i := NewInitializer(
+		mockContext.Console,
+		git.NewCli(mockContext.CommandRunner),
+		dotnet.NewCli(mockContext.CommandRunner),
+		lazy.From[environment.Manager](&mockenv.MockEnvManager{}),
+	)
+
+	err := i.scaffoldAksManifests(*mockContext.Context, azdCtx)
+	require.NoError(t, err)
+
+	manifestsPath := filepath.Join(apiPath, "manifests")
+	for _, manifest := range []string{"deployment.tmpl.yaml", "service.yaml", "ingress.yaml", "configmap.yaml"} {
+		require.FileExists(t, filepath.Join(manifestsPath, manifest))
+	}
+
+	deployment, err := os.ReadFile(filepath.Join(manifestsPath, "deployment.tmpl.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(deployment), "image: {{.Env.SERVICE_API_IMAGE_NAME}}")
+	require.Contains(t, string(deployment), "containerPort: 3100")
+	require.Contains(t, string(deployment), "name: api-config")
+
+	entries, err := os.ReadDir(webManifestsPath)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	require.NoDirExists(t, filepath.Join(projectDir, "src", "chart", "manifests"))
+	require.NoDirExists(t, filepath.Join(projectDir, "src", "func", "manifests"))
+}
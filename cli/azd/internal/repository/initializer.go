@@ -120,6 +120,10 @@ func (i *Initializer) Initialize(
 		return fmt.Errorf("initializing project: %w", err)
 	}
 
+	if err := i.scaffoldAksManifests(ctx, azdCtx); err != nil {
+		return err
+	}
+
 	err = i.gitInitialize(ctx, target, filesWithExecPerms, isEmpty)
 	if err != nil {
 		return err
@@ -0,0 +1,88 @@
+package scaffold
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The container port of the scaffolded k8s manifests when the Dockerfile doesn't expose any port
+const DefaultK8sPort = 80
+
+// K8sSpec is the spec of the starter k8s manifests scaffolded for a service hosted on AKS
+type K8sSpec struct {
+	// The name of the service, used as the name of the k8s resources
+	Name string
+	// The port the container listens on
+	Port int
+	// The azd environment variable holding the container image, ex) SERVICE_API_IMAGE_NAME
+	ImageEnvName string
+}
+
+// NewK8sSpec returns the k8s spec of the service, with the container image set to the image published by azd
+func NewK8sSpec(serviceName string, port int) K8sSpec {
+	if port <= 0 {
+		port = DefaultK8sPort
+	}
+
+	return K8sSpec{
+		Name:         strings.ToLower(serviceName),
+		Port:         port,
+		ImageEnvName: fmt.Sprintf("SERVICE_%s_IMAGE_NAME", strings.ReplaceAll(strings.ToUpper(serviceName), "-", "_")),
+	}
+}
+
+// ExecK8sManifests scaffolds the deployment, service, ingress and config map manifests of the given spec,
+// using the loaded templates in t. The resulting files are written to the target directory.
+func ExecK8sManifests(
+	t *template.Template,
+	spec K8sSpec,
+	target string) error {
+	if err := os.MkdirAll(target, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	manifests := []string{"deployment.tmpl.yaml", "service.yaml", "ingress.yaml", "configmap.yaml"}
+	for _, manifest := range manifests {
+		err := Execute(t, "k8s-"+manifest, spec, filepath.Join(target, manifest))
+		if err != nil {
+			return fmt.Errorf("scaffolding %s: %w", manifest, err)
+		}
+	}
+
+	return nil
+}
+
+// DockerfilePort returns the first port exposed by the EXPOSE instructions of the Dockerfile, or 0 when none is exposed.
+// Ports referencing build arguments, ex) EXPOSE ${PORT}, are ignored.
+func DockerfilePort(dockerfilePath string) (int, error) {
+	file, err := os.Open(dockerfilePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "EXPOSE") {
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			// The protocol is optional, ex) EXPOSE 8080/tcp
+			portValue, _, _ := strings.Cut(field, "/")
+			if port, err := strconv.Atoi(portValue); err == nil && port > 0 {
+				return port, nil
+			}
+		}
+	}
+
+	return 0, scanner.Err()
+}
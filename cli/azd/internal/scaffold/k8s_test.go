@@ -0,0 +1,72 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDockerfilePort(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		want       int
+	}{
+		{"Single", "FROM nginx\nEXPOSE 8080\n", 8080},
+		{"Protocol", "FROM nginx\nexpose 5000/tcp 5001/udp\n", 5000},
+		{"BuildArg", "FROM nginx\nARG PORT=3000\nEXPOSE ${PORT}\n", 0},
+		{"None", "FROM nginx\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerfilePath := filepath.Join(t.TempDir(), "Dockerfile")
+			require.NoError(t, os.WriteFile(dockerfilePath, []byte(tt.dockerfile), osutil.PermissionFile))
+
+			port, err := DockerfilePort(dockerfilePath)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, port)
+		})
+	}
+}
+
+func TestExecK8sManifests(t *testing.T) {
+	template, err := Load()
+	require.NoError(t, err)
+
+	spec := NewK8sSpec("todo-api", 0)
+	require.Equal(t, "SERVICE_TODO_API_IMAGE_NAME", spec.ImageEnvName)
+	require.Equal(t, DefaultK8sPort, spec.Port)
+
+	target := filepath.Join(t.TempDir(), "manifests")
+	require.NoError(t, ExecK8sManifests(template, spec, target))
+
+	expectedKinds := map[string]string{
+		"deployment.tmpl.yaml": "Deployment",
+		"service.yaml":         "Service",
+		"ingress.yaml":         "Ingress",
+		"configmap.yaml":       "ConfigMap",
+	}
+
+	for manifest, kind := range expectedKinds {
+		content, err := os.ReadFile(filepath.Join(target, manifest))
+		require.NoError(t, err)
+
+		var resource struct {
+			ApiVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Metadata   struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+		require.NoError(t, yaml.Unmarshal(content, &resource), manifest)
+		require.NotEmpty(t, resource.ApiVersion)
+		require.Equal(t, kind, resource.Kind)
+		require.True(t, strings.HasPrefix(resource.Metadata.Name, "todo-api"))
+	}
+}
@@ -9,13 +9,16 @@ const (
 	AzureResourceTypeApim                      AzureResourceType = "Microsoft.ApiManagement/service"
 	AzureResourceTypeAppConfig                 AzureResourceType = "Microsoft.AppConfiguration/configurationStores"
 	AzureResourceTypeAppInsightComponent       AzureResourceType = "Microsoft.Insights/components"
+	AzureResourceTypeBatchAccount              AzureResourceType = "Microsoft.Batch/batchAccounts"
 	AzureResourceTypeCacheForRedis             AzureResourceType = "Microsoft.Cache/redis"
 	AzureResourceTypeCDNProfile                AzureResourceType = "Microsoft.Cdn/profiles"
 	AzureResourceTypeCosmosDb                  AzureResourceType = "Microsoft.DocumentDB/databaseAccounts"
 	AzureResourceTypeContainerApp              AzureResourceType = "Microsoft.App/containerApps"
+	AzureResourceTypeContainerAppJob           AzureResourceType = "Microsoft.App/jobs"
 	AzureResourceTypeSpringApp                 AzureResourceType = "Microsoft.AppPlatform/Spring"
 	AzureResourceTypeContainerAppEnvironment   AzureResourceType = "Microsoft.App/managedEnvironments"
 	AzureResourceTypeDeployment                AzureResourceType = "Microsoft.Resources/deployments"
+	AzureResourceTypeIotHub                    AzureResourceType = "Microsoft.Devices/IotHubs"
 	AzureResourceTypeKeyVault                  AzureResourceType = "Microsoft.KeyVault/vaults"
 	AzureResourceTypeManagedHSM                AzureResourceType = "Microsoft.KeyVault/managedHSMs"
 	AzureResourceTypeLoadTest                  AzureResourceType = "Microsoft.LoadTestService/loadTests"
@@ -30,10 +33,14 @@ const (
 	AzureResourceTypeServicePlan               AzureResourceType = "Microsoft.Web/serverfarms"
 	AzureResourceTypeSqlServer                 AzureResourceType = "Microsoft.Sql/servers"
 	AzureResourceTypeVirtualNetwork            AzureResourceType = "Microsoft.Network/virtualNetworks"
+	AzureResourceTypeVirtualMachine            AzureResourceType = "Microsoft.Compute/virtualMachines"
+	AzureResourceTypeVirtualMachineScaleSet    AzureResourceType = "Microsoft.Compute/virtualMachineScaleSets"
 	AzureResourceTypeWebSite                   AzureResourceType = "Microsoft.Web/sites"
 	AzureResourceTypeContainerRegistry         AzureResourceType = "Microsoft.ContainerRegistry/registries"
 	AzureResourceTypeManagedCluster            AzureResourceType = "Microsoft.ContainerService/managedClusters"
 	AzureResourceTypeAgentPool                 AzureResourceType = "Microsoft.ContainerService/managedClusters/agentPools"
+	AzureResourceTypeOpenShiftCluster          AzureResourceType = "Microsoft.RedHatOpenShift/openShiftClusters"
+	AzureResourceTypeConnectedCluster          AzureResourceType = "Microsoft.Kubernetes/connectedClusters"
 	AzureResourceTypeCognitiveServiceAccount   AzureResourceType = "Microsoft.CognitiveServices/accounts"
 	AzureResourceTypeSearchService             AzureResourceType = "Microsoft.Search/searchServices"
 	AzureResourceTypeVideoIndexer              AzureResourceType = "Microsoft.VideoIndexer/accounts"
@@ -75,6 +82,8 @@ func GetResourceTypeDisplayName(resourceType AzureResourceType) string {
 		return "Static Web App"
 	case AzureResourceTypeContainerApp:
 		return "Container App"
+	case AzureResourceTypeContainerAppJob:
+		return "Container Apps Job"
 	case AzureResourceTypeContainerAppEnvironment:
 		return "Container Apps Environment"
 	case AzureResourceTypeServiceBusNamespace:
@@ -99,8 +108,20 @@ func GetResourceTypeDisplayName(resourceType AzureResourceType) string {
 		return "Load Tests"
 	case AzureResourceTypeVirtualNetwork:
 		return "Virtual Network"
+	case AzureResourceTypeVirtualMachine:
+		return "Virtual machine"
+	case AzureResourceTypeVirtualMachineScaleSet:
+		return "Virtual machine scale set"
+	case AzureResourceTypeIotHub:
+		return "IoT Hub"
+	case AzureResourceTypeBatchAccount:
+		return "Batch account"
 	case AzureResourceTypeContainerRegistry:
 		return "Container Registry"
+	case AzureResourceTypeOpenShiftCluster:
+		return "Azure Red Hat OpenShift"
+	case AzureResourceTypeConnectedCluster:
+		return "Kubernetes - Azure Arc"
 	case AzureResourceTypeManagedCluster:
 		return "AKS Managed Cluster"
 	case AzureResourceTypeAgentPool:
@@ -9,7 +9,10 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 )
@@ -23,14 +26,27 @@ type AccountConfig struct {
 
 var (
 	ErrContainerNotFound = errors.New("container not found")
+	// ErrBlobModified is returned when the conditions of an upload are not met, the blob was modified or created since
+	// it was last read
+	ErrBlobModified = errors.New("blob was modified since it was last read")
 )
 
+// UploadOptions are the conditions of an upload, used to detect concurrent changes to a blob
+type UploadOptions struct {
+	// The blob is only uploaded when its current ETag matches, ex) the ETag of the blob when it was downloaded
+	IfMatch string
+	// The blob is only uploaded when it doesn't exist yet
+	IfNotExists bool
+}
+
 type BlobClient interface {
-	// Download downloads a blob from the configured storage account container.
-	Download(ctx context.Context, blobPath string) (io.ReadCloser, error)
+	// Download downloads a blob from the configured storage account container, along with the ETag of the version
+	// of the blob downloaded.
+	Download(ctx context.Context, blobPath string) (io.ReadCloser, string, error)
 
-	// Upload uploads a blob to the configured storage account container.
-	Upload(ctx context.Context, blobPath string, reader io.Reader) error
+	// Upload uploads a blob to the configured storage account container and returns the ETag of the uploaded blob.
+	// ErrBlobModified is returned when the conditions of the options are not met.
+	Upload(ctx context.Context, blobPath string, reader io.Reader, options *UploadOptions) (string, error)
 
 	// Delete deletes a blob from the configured storage account container.
 	Delete(ctx context.Context, blobPath string) error
@@ -61,6 +77,7 @@ type Blob struct {
 	Path         string
 	CreationTime time.Time
 	LastModified time.Time
+	ETag         string
 }
 
 // Items returns a list of blobs in the configured storage account container.
@@ -79,44 +96,80 @@ func (bc *blobClient) Items(ctx context.Context) ([]*Blob, error) {
 		}
 
 		for _, blob := range page.Segment.BlobItems {
-			blobs = append(blobs, &Blob{
+			item := &Blob{
 				Name:         filepath.Base(*blob.Name),
 				Path:         *blob.Name,
 				CreationTime: *blob.Properties.CreationTime,
 				LastModified: *blob.Properties.LastModified,
-			})
+			}
+			if blob.Properties.ETag != nil {
+				item.ETag = string(*blob.Properties.ETag)
+			}
+
+			blobs = append(blobs, item)
 		}
 	}
 
 	return blobs, nil
 }
 
-// Download downloads a blob from the configured storage account container.
-func (bc *blobClient) Download(ctx context.Context, blobPath string) (io.ReadCloser, error) {
+// Download downloads a blob from the configured storage account container, along with the ETag of the version
+// of the blob downloaded.
+func (bc *blobClient) Download(ctx context.Context, blobPath string) (io.ReadCloser, string, error) {
 	if err := bc.ensureContainerExists(ctx); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	resp, err := bc.client.DownloadStream(ctx, bc.config.ContainerName, blobPath, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download blob '%s', %w", blobPath, err)
+		return nil, "", fmt.Errorf("failed to download blob '%s', %w", blobPath, err)
+	}
+
+	etag := ""
+	if resp.ETag != nil {
+		etag = string(*resp.ETag)
 	}
 
-	return resp.Body, nil
+	return resp.Body, etag, nil
 }
 
-// Upload uploads a blob to the configured storage account container.
-func (bc *blobClient) Upload(ctx context.Context, blobPath string, reader io.Reader) error {
+// Upload uploads a blob to the configured storage account container and returns the ETag of the uploaded blob.
+func (bc *blobClient) Upload(
+	ctx context.Context,
+	blobPath string,
+	reader io.Reader,
+	options *UploadOptions,
+) (string, error) {
 	if err := bc.ensureContainerExists(ctx); err != nil {
-		return err
+		return "", err
 	}
 
-	_, err := bc.client.UploadStream(ctx, bc.config.ContainerName, blobPath, reader, nil)
-	if err != nil {
-		return fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	uploadOptions := &azblob.UploadStreamOptions{}
+	if options != nil && (options.IfMatch != "" || options.IfNotExists) {
+		conditions := &blob.ModifiedAccessConditions{}
+		if options.IfMatch != "" {
+			conditions.IfMatch = to.Ptr(azcore.ETag(options.IfMatch))
+		}
+		if options.IfNotExists {
+			conditions.IfNoneMatch = to.Ptr(azcore.ETagAny)
+		}
+
+		uploadOptions.AccessConditions = &blob.AccessConditions{ModifiedAccessConditions: conditions}
 	}
 
-	return nil
+	resp, err := bc.client.UploadStream(ctx, bc.config.ContainerName, blobPath, reader, uploadOptions)
+	if bloberror.HasCode(err, bloberror.ConditionNotMet, bloberror.BlobAlreadyExists) {
+		return "", fmt.Errorf("failed to upload blob '%s', %w: %w", blobPath, ErrBlobModified, err)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+
+	etag := ""
+	if resp.ETag != nil {
+		etag = string(*resp.ETag)
+	}
+
+	return etag, nil
 }
 
 // Delete deletes a blob from the configured storage account container.
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrCustomDomainNotVerified is returned when the DNS records proving the ownership of a custom domain are missing
+var ErrCustomDomainNotVerified = errors.New("custom domain not verified")
+
+var managedCertificateNameRegexp = regexp.MustCompile(`[^a-z0-9-]`)
+
+// The maximum length of the name of a managed certificate
+const managedCertificateNameMaxLength = 60
+
+// ManagedCertificateName returns the name of the managed certificate of the host name of a custom domain,
+// ex) mc-api-contoso-com
+func ManagedCertificateName(hostName string) string {
+	name := "mc-" + managedCertificateNameRegexp.ReplaceAllString(strings.ToLower(hostName), "-")
+	if len(name) > managedCertificateNameMaxLength {
+		name = name[:managedCertificateNameMaxLength]
+	}
+
+	return strings.TrimRight(name, "-")
+}
+
+// NewCustomDomainNotVerifiedError returns an ErrCustomDomainNotVerified error with the DNS records to create for the
+// custom domain: the record mapping the domain to the app, ex) CNAME api.contoso.com -> contoso.azurewebsites.net,
+// and the TXT record proving the ownership of the domain with the verification id. The reason is the failure reported
+// by the verification of the domain, if any.
+func NewCustomDomainNotVerifiedError(hostName string, reason string, record string, verificationId string) error {
+	if reason != "" {
+		reason = fmt.Sprintf(" (%s)", reason)
+	}
+
+	return fmt.Errorf(
+		"%w: create the DNS records of custom domain '%s'%s:\n  %s\n  TXT asuid.%s -> %s",
+		ErrCustomDomainNotVerified,
+		hostName,
+		reason,
+		record,
+		hostName,
+		verificationId,
+	)
+}
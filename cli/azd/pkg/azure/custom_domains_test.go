@@ -0,0 +1,25 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ManagedCertificateName(t *testing.T) {
+	require.Equal(t, "mc-api-contoso-com", ManagedCertificateName("API.contoso.com"))
+
+	longHostName := "a-very-long-sub-domain-name.of-a-very-long-domain.contoso.com"
+	require.LessOrEqual(t, len(ManagedCertificateName(longHostName)), 60)
+}
+
+func Test_NewCustomDomainNotVerifiedError(t *testing.T) {
+	err := NewCustomDomainNotVerifiedError(
+		"api.contoso.com", "missing TXT record", "CNAME api.contoso.com -> app.azurewebsites.net", "VERIFICATION_ID")
+	require.ErrorIs(t, err, ErrCustomDomainNotVerified)
+	require.Equal(t,
+		"custom domain not verified: create the DNS records of custom domain 'api.contoso.com' (missing TXT record):\n"+
+			"  CNAME api.contoso.com -> app.azurewebsites.net\n"+
+			"  TXT asuid.api.contoso.com -> VERIFICATION_ID",
+		err.Error())
+}
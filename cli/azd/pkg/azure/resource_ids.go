@@ -74,6 +74,15 @@ func ContainerAppRID(subscriptionId, resourceGroupName, containerAppName string)
 	return returnValue
 }
 
+func ContainerAppJobRID(subscriptionId, resourceGroupName, jobName string) string {
+	returnValue := fmt.Sprintf(
+		"%s/providers/Microsoft.App/jobs/%s",
+		ResourceGroupRID(subscriptionId, resourceGroupName),
+		jobName,
+	)
+	return returnValue
+}
+
 func SpringAppRID(subscriptionId, resourceGroupName, springAppName string) string {
 	returnValue := fmt.Sprintf(
 		"%s/providers/Microsoft.AppPlatform/Spring/%s",
@@ -5,9 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"maps"
 	"net/http"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
@@ -30,8 +34,11 @@ const (
 	pathTemplateContainers                 = "properties.template.containers"
 	pathConfigurationActiveRevisionsMode   = "properties.configuration.activeRevisionsMode"
 	pathConfigurationSecrets               = "properties.configuration.secrets"
+	pathConfigurationRegistries            = "properties.configuration.registries"
+	pathConfigurationIngress               = "properties.configuration.ingress"
 	pathConfigurationIngressTraffic        = "properties.configuration.ingress.traffic"
 	pathConfigurationIngressFqdn           = "properties.configuration.ingress.fqdn"
+	pathConfigurationIngressTargetPort     = "properties.configuration.ingress.targetPort"
 	pathConfigurationIngressCustomDomains  = "properties.configuration.ingress.customDomains"
 	pathConfigurationIngressStickySessions = "properties.configuration.ingress.stickySessions"
 )
@@ -54,7 +61,7 @@ type ContainerAppService interface {
 		containerAppYaml []byte,
 		options *ContainerAppOptions,
 	) error
-	// Adds and activates a new revision to the specified container app
+	// Adds and activates a new revision to the specified container app and returns the name of the revision
 	AddRevision(
 		ctx context.Context,
 		subscriptionId string,
@@ -62,6 +69,75 @@ type ContainerAppService interface {
 		appName string,
 		imageName string,
 		options *ContainerAppOptions,
+	) (string, error)
+	// Lists the revisions of the specified container app, the most recently created revision first
+	ListRevisions(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		options *ContainerAppOptions,
+	) ([]*ContainerAppRevision, error)
+	// Routes the ingress traffic of the specified container app to the revisions by their percentage of the traffic
+	SetTraffic(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		weights map[string]int,
+		options *ContainerAppOptions,
+	) error
+	// Waits until the revision is provisioned and healthy, returns ErrRevisionUnhealthy when the revision failed
+	// to provision, its health probes fail or it did not become healthy within the timeout
+	WaitForRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		revisionName string,
+		timeout time.Duration,
+		options *ContainerAppOptions,
+	) (*ContainerAppRevision, error)
+	// Deactivates the revision of the specified container app
+	DeactivateRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		revisionName string,
+		options *ContainerAppOptions,
+	) error
+	// Lists the containers of the replicas of the latest ready revision whose console logs can be streamed
+	ListLogSources(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		containerName string,
+	) ([]*ContainerAppLogSource, error)
+	// Creates or updates the Dapr components in the managed environment of the specified container app
+	ApplyDaprComponents(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		components []*ContainerAppDaprComponent,
+	) error
+	// Binds the custom domains to the specified container app with managed certificates
+	BindCustomDomains(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		domains []*ContainerAppCustomDomain,
+		options *ContainerAppOptions,
+	) error
+	// Streams the console logs of a container of a replica to the writer
+	StreamLogs(
+		ctx context.Context,
+		source *ContainerAppLogSource,
+		options *ContainerAppLogsOptions,
+		writer io.Writer,
 	) error
 }
 
@@ -89,6 +165,31 @@ type containerAppService struct {
 
 type ContainerAppOptions struct {
 	ApiVersion string
+	// The credentials of the registry the image is pulled from when it is not pulled with a managed identity,
+	// ex) ghcr.io. The registry is added to, or updated in, the registries of the container app with a revision
+	Registry *ContainerAppRegistry
+	// The environment variables set on the container of the revision, ex) of a docker-compose service
+	// Variables of the container with the same name are replaced, other variables are preserved
+	Env map[string]string
+	// The port the ingress of the container app forwards traffic to, ex) 80. Left unchanged when not set or when the
+	// container app has no ingress
+	TargetPort int
+	// Keeps the previous revisions active by switching the container app to the multiple revisions mode, ex) to roll
+	// back to the previous revision when the new revision is unhealthy
+	MultipleRevisions bool
+	// The percentage of the ingress traffic routed to the new revision, ex) 10. The revision serving most of the
+	// traffic keeps the rest of the traffic and the container app is switched to the multiple revisions mode.
+	// All the traffic is routed to the new revision when not set.
+	TrafficWeight *int
+	// Enables the Dapr sidecar of the container app with a revision, ex) for the Dapr components of the service
+	Dapr *ContainerAppDapr
+}
+
+// ContainerAppRegistry is a container registry the container app pulls images from with a username and password
+type ContainerAppRegistry struct {
+	Server   string
+	Username string
+	Password string
 }
 
 type ContainerAppIngressConfiguration struct {
@@ -116,6 +217,15 @@ func (cas *containerAppService) GetIngressConfiguration(
 		hostNames = []string{}
 	}
 
+	// Custom domains are only served over https once bound with a certificate
+	customDomains, _ := containerApp.GetSlice(pathConfigurationIngressCustomDomains)
+	for _, customDomain := range customDomains {
+		binding, ok := customDomain.(map[string]any)
+		if ok && binding["name"] != nil && binding["bindingType"] != customDomainBindingDisabled {
+			hostNames = append(hostNames, fmt.Sprint(binding["name"]))
+		}
+	}
+
 	return &ContainerAppIngressConfiguration{
 		HostNames: hostNames,
 	}, nil
@@ -262,7 +372,7 @@ func (cas *containerAppService) DeployYaml(
 	return nil
 }
 
-// Adds and activates a new revision to the specified container app
+// Adds and activates a new revision to the specified container app and returns the name of the revision
 func (cas *containerAppService) AddRevision(
 	ctx context.Context,
 	subscriptionId string,
@@ -270,94 +380,188 @@ func (cas *containerAppService) AddRevision(
 	appName string,
 	imageName string,
 	options *ContainerAppOptions,
-) error {
+) (string, error) {
 	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, options)
 	if err != nil {
-		return fmt.Errorf("getting container app: %w", err)
+		return "", fmt.Errorf("getting container app: %w", err)
 	}
 
 	// Get the latest revision name
 	currentRevisionName, has := containerApp.GetString(pathLatestRevisionName)
 	if !has {
-		return fmt.Errorf("getting latest revision name: %w", err)
+		return "", fmt.Errorf("getting latest revision name: %w", err)
 	}
 
 	apiVersionPolicy := createApiVersionPolicy(options)
 	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId, apiVersionPolicy)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	var revisionResponse *http.Response
 	ctx = policy.WithCaptureResponse(ctx, &revisionResponse)
 
 	if _, err := revisionsClient.GetRevision(ctx, resourceGroupName, appName, currentRevisionName, nil); err != nil {
-		return fmt.Errorf("getting revision '%s': %w", currentRevisionName, err)
+		return "", fmt.Errorf("getting revision '%s': %w", currentRevisionName, err)
 	}
 
 	var revisionMap map[string]any
 	if err := convert.FromHttpResponse(revisionResponse, &revisionMap); err != nil {
-		return err
+		return "", err
 	}
 
 	revision := config.NewConfig(revisionMap)
 
 	// Update the revision with the new image name and suffix
 	if err := revision.Set(pathTemplateRevisionSuffix, fmt.Sprintf("azd-%d", cas.clock.Now().Unix())); err != nil {
-		return fmt.Errorf("setting revision suffix: %w", err)
+		return "", fmt.Errorf("setting revision suffix: %w", err)
 	}
 
 	var containers []map[string]any
 	if ok, err := revision.GetSection(pathTemplateContainers, &containers); !ok || err != nil {
-		return fmt.Errorf("getting containers: %w", err)
+		return "", fmt.Errorf("getting containers: %w", err)
 	}
 
 	containers[0]["image"] = imageName
+	if options != nil && len(options.Env) > 0 {
+		setContainerEnv(containers[0], options.Env)
+	}
+
 	if err := revision.Set(pathTemplateContainers, containers); err != nil {
-		return fmt.Errorf("setting containers: %w", err)
+		return "", fmt.Errorf("setting containers: %w", err)
 	}
 
 	// Update the container app with the new revision
 	revisionTemplate, ok := revision.GetMap(pathTemplate)
 	if !ok {
-		return fmt.Errorf("getting revision template: %w", err)
+		return "", fmt.Errorf("getting revision template: %w", err)
 	}
 
 	if err := containerApp.Set(pathTemplate, revisionTemplate); err != nil {
-		return fmt.Errorf("setting template: %w", err)
+		return "", fmt.Errorf("setting template: %w", err)
 	}
 
 	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
 	if err != nil {
-		return fmt.Errorf("syncing secrets: %w", err)
+		return "", fmt.Errorf("syncing secrets: %w", err)
+	}
+
+	if options != nil && options.Registry != nil {
+		if err := setRegistry(containerApp, options.Registry); err != nil {
+			return "", fmt.Errorf("setting registry: %w", err)
+		}
+	}
+
+	if options != nil && options.TargetPort > 0 {
+		if _, has := containerApp.Get(pathConfigurationIngressTargetPort); has {
+			if err := containerApp.Set(pathConfigurationIngressTargetPort, options.TargetPort); err != nil {
+				return "", fmt.Errorf("setting ingress target port: %w", err)
+			}
+		}
+	}
+
+	if options != nil && options.Dapr != nil {
+		section, _ := containerApp.GetMap(pathConfigurationDapr)
+		if err := containerApp.Set(pathConfigurationDapr, setDapr(section, options.Dapr)); err != nil {
+			return "", fmt.Errorf("setting dapr configuration: %w", err)
+		}
+	}
+
+	revisionSuffix, ok := revision.GetString(pathTemplateRevisionSuffix)
+	if !ok {
+		return "", fmt.Errorf("getting revision suffix: %w", err)
+	}
+	newRevisionName := fmt.Sprintf("%s--%s", appName, revisionSuffix)
+
+	// The traffic routed to the previous revisions before the new revision is added
+	previousTraffic, _ := containerApp.GetSlice(pathConfigurationIngressTraffic)
+
+	splitTraffic := options != nil && options.TrafficWeight != nil && *options.TrafficWeight < 100
+	if splitTraffic {
+		if _, has := containerApp.Get(pathConfigurationIngress); !has {
+			return "", fmt.Errorf("splitting the traffic of container app '%s' requires ingress", appName)
+		}
+	}
+
+	if splitTraffic || (options != nil && options.MultipleRevisions) {
+		if err := setMultipleRevisionsMode(containerApp); err != nil {
+			return "", err
+		}
 	}
 
 	// Update the container app
 	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp, options)
 	if err != nil {
-		return fmt.Errorf("updating container app revision: %w", err)
+		return "", fmt.Errorf("updating container app revision: %w", err)
 	}
 
 	revisionMode, ok := containerApp.GetString(pathConfigurationActiveRevisionsMode)
 	if !ok {
-		return fmt.Errorf("getting active revisions mode: %w", err)
+		return "", fmt.Errorf("getting active revisions mode: %w", err)
 	}
 
 	// If the container app is in multiple revision mode, update the traffic to point to the new revision
 	if revisionMode == string(armappcontainers.ActiveRevisionsModeMultiple) {
-		revisionSuffix, ok := revision.GetString(pathTemplateRevisionSuffix)
-		if !ok {
-			return fmt.Errorf("getting revision suffix: %w", err)
+		weights := map[string]int{newRevisionName: 100}
+		if splitTraffic {
+			weights = splitTrafficWeights(previousTraffic, currentRevisionName, newRevisionName, *options.TrafficWeight)
 		}
-		newRevisionName := fmt.Sprintf("%s--%s", appName, revisionSuffix)
 
-		err = cas.setTrafficWeights(ctx, subscriptionId, resourceGroupName, appName, containerApp, newRevisionName, options)
+		err = cas.setTrafficWeights(ctx, subscriptionId, resourceGroupName, appName, containerApp, weights, options)
 		if err != nil {
-			return fmt.Errorf("setting traffic weights: %w", err)
+			return "", fmt.Errorf("setting traffic weights: %w", err)
 		}
 	}
 
-	return nil
+	return newRevisionName, nil
+}
+
+// splitTrafficWeights routes the percentage of the traffic to the new revision and the rest of the traffic to the
+// revision that served most of the previous traffic, ex) the stable revision of a canary deployment. Traffic routed
+// to the latest revision is attributed to the latest revision before the new revision was added.
+func splitTrafficWeights(
+	previousTraffic []any,
+	latestRevisionName string,
+	newRevisionName string,
+	weight int,
+) map[string]int {
+	stableRevisionName := ""
+	stableWeight := -1
+	for _, traffic := range previousTraffic {
+		trafficMap, ok := traffic.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		revisionName, _ := trafficMap["revisionName"].(string)
+		if latest, _ := trafficMap["latestRevision"].(bool); latest {
+			revisionName = latestRevisionName
+		}
+
+		trafficWeight, ok := trafficMap["weight"].(float64)
+		if revisionName == "" || !ok {
+			continue
+		}
+
+		if int(trafficWeight) > stableWeight {
+			stableRevisionName = revisionName
+			stableWeight = int(trafficWeight)
+		}
+	}
+
+	// Without a previous revision serving traffic, all the traffic is routed to the new revision
+	if stableRevisionName == "" {
+		stableRevisionName = latestRevisionName
+	}
+
+	if stableRevisionName == "" || stableRevisionName == newRevisionName || weight >= 100 {
+		return map[string]int{newRevisionName: 100}
+	}
+
+	return map[string]int{
+		newRevisionName:    weight,
+		stableRevisionName: 100 - weight,
+	}
 }
 
 func (cas *containerAppService) syncSecrets(
@@ -400,20 +604,94 @@ func (cas *containerAppService) syncSecrets(
 	return containerApp, nil
 }
 
+// setRegistry adds the registry to the registries of the container app, or updates its credentials when the registry
+// is already added. The password is stored in a secret of the container app.
+func setRegistry(containerApp config.Config, registry *ContainerAppRegistry) error {
+	secretName := registrySecretName(registry.Server)
+
+	secrets, _ := containerApp.GetSlice(pathConfigurationSecrets)
+	secrets = slices.DeleteFunc(secrets, func(secret any) bool {
+		secretMap, ok := secret.(map[string]any)
+		return ok && secretMap["name"] == secretName
+	})
+	secrets = append(secrets, map[string]any{
+		"name":  secretName,
+		"value": registry.Password,
+	})
+
+	if err := containerApp.Set(pathConfigurationSecrets, secrets); err != nil {
+		return fmt.Errorf("setting secrets: %w", err)
+	}
+
+	registries, _ := containerApp.GetSlice(pathConfigurationRegistries)
+	registries = slices.DeleteFunc(registries, func(existing any) bool {
+		registryMap, ok := existing.(map[string]any)
+		return ok && registryMap["server"] == registry.Server
+	})
+	registries = append(registries, map[string]any{
+		"server":            registry.Server,
+		"username":          registry.Username,
+		"passwordSecretRef": secretName,
+	})
+
+	return containerApp.Set(pathConfigurationRegistries, registries)
+}
+
+// setContainerEnv sets the environment variables of the container, replacing the values of variables with the same
+// name, ex) variables that referenced a secret of the container app
+func setContainerEnv(container map[string]any, env map[string]string) {
+	existing, _ := container["env"].([]any)
+	existing = slices.DeleteFunc(existing, func(variable any) bool {
+		variableMap, ok := variable.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		_, has := env[fmt.Sprint(variableMap["name"])]
+		return has
+	})
+
+	names := slices.Sorted(maps.Keys(env))
+	for _, name := range names {
+		existing = append(existing, map[string]any{
+			"name":  name,
+			"value": env[name],
+		})
+	}
+
+	container["env"] = existing
+}
+
+// registrySecretName returns the name of the secret holding the password of the registry, ex) registry-ghcr-io
+// Secret names consist of lowercase alphanumeric characters and '-'.
+func registrySecretName(server string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '-'
+	}, strings.ToLower(server))
+
+	return fmt.Sprintf("registry-%s", strings.Trim(name, "-"))
+}
+
+// setTrafficWeights routes the ingress traffic of the container app to the revisions by their weight
 func (cas *containerAppService) setTrafficWeights(
 	ctx context.Context,
 	subscriptionId string,
 	resourceGroupName string,
 	appName string,
 	containerApp config.Config,
-	revisionName string,
+	weights map[string]int,
 	options *ContainerAppOptions,
 ) error {
-	trafficWeights := []*armappcontainers.TrafficWeight{
-		{
-			RevisionName: &revisionName,
-			Weight:       to.Ptr[int32](100),
-		},
+	trafficWeights := []*armappcontainers.TrafficWeight{}
+	for _, revisionName := range slices.Sorted(maps.Keys(weights)) {
+		trafficWeights = append(trafficWeights, &armappcontainers.TrafficWeight{
+			RevisionName: to.Ptr(revisionName),
+			Weight:       to.Ptr(int32(weights[revisionName])),
+		})
 	}
 
 	trafficWeightsJson, err := convert.ToJsonArray(trafficWeights)
@@ -0,0 +1,157 @@
+package containerapps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+)
+
+const (
+	pathManagedEnvironmentId = "properties.managedEnvironmentId"
+	pathEnvironmentId        = "properties.environmentId"
+	pathConfigurationDapr    = "properties.configuration.dapr"
+)
+
+// ContainerAppDapr configures the Dapr sidecar of the revisions of a container app
+type ContainerAppDapr struct {
+	// The Dapr app id of the container app, ex) api
+	AppId string
+	// The port the application listens on for Dapr, ex) 8080. Left unchanged when not set
+	AppPort int
+	// The protocol the application uses, http or grpc. Left unchanged when not set
+	AppProtocol string
+}
+
+// ContainerAppDaprComponent is a Dapr component of the managed environment of a container app,
+// ex) a pub/sub or state store component
+type ContainerAppDaprComponent struct {
+	Name string
+	// ex) pubsub.azure.servicebus.topics
+	ComponentType string
+	// ex) v1
+	Version      string
+	IgnoreErrors bool
+	// ex) 5s
+	InitTimeout string
+	Metadata    []ContainerAppDaprMetadata
+	// The name of the Dapr secret store component the secret references of the metadata are resolved from
+	SecretStoreComponent string
+	// The Dapr app ids allowed to use the component, all apps of the environment when empty
+	Scopes []string
+}
+
+// ContainerAppDaprMetadata is a metadata value of a Dapr component, set either as a value or as a secret reference
+type ContainerAppDaprMetadata struct {
+	Name      string
+	Value     string
+	SecretRef string
+}
+
+// ApplyDaprComponents creates or updates the Dapr components in the managed environment of the container app
+func (cas *containerAppService) ApplyDaprComponents(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	components []*ContainerAppDaprComponent,
+) error {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, nil)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	environmentId := managedEnvironmentId(containerApp)
+	if environmentId == "" {
+		return fmt.Errorf("container app '%s' does not reference a managed environment", appName)
+	}
+
+	// The managed environment can be in another resource group than the container app
+	environment, err := arm.ParseResourceID(environmentId)
+	if err != nil {
+		return fmt.Errorf("parsing managed environment id '%s': %w", environmentId, err)
+	}
+
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, environment.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	client, err := armappcontainers.NewDaprComponentsClient(environment.SubscriptionID, credential, cas.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating Dapr components client: %w", err)
+	}
+
+	for _, component := range components {
+		_, err := client.CreateOrUpdate(
+			ctx,
+			environment.ResourceGroupName,
+			environment.Name,
+			component.Name,
+			daprComponentResource(component),
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("applying Dapr component '%s' to environment '%s': %w", component.Name, environment.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// daprComponentResource returns the ARM resource of the Dapr component
+func daprComponentResource(component *ContainerAppDaprComponent) armappcontainers.DaprComponent {
+	properties := &armappcontainers.DaprComponentProperties{
+		ComponentType: to.Ptr(component.ComponentType),
+		Version:       to.Ptr(component.Version),
+		IgnoreErrors:  to.Ptr(component.IgnoreErrors),
+		Metadata:      []*armappcontainers.DaprMetadata{},
+		Scopes:        to.SliceOfPtrs(component.Scopes...),
+	}
+
+	if component.InitTimeout != "" {
+		properties.InitTimeout = to.Ptr(component.InitTimeout)
+	}
+
+	if component.SecretStoreComponent != "" {
+		properties.SecretStoreComponent = to.Ptr(component.SecretStoreComponent)
+	}
+
+	for _, metadata := range component.Metadata {
+		item := &armappcontainers.DaprMetadata{Name: to.Ptr(metadata.Name)}
+		if metadata.SecretRef != "" {
+			item.SecretRef = to.Ptr(metadata.SecretRef)
+		} else {
+			item.Value = to.Ptr(metadata.Value)
+		}
+
+		properties.Metadata = append(properties.Metadata, item)
+	}
+
+	return armappcontainers.DaprComponent{Properties: properties}
+}
+
+// setDapr enables the Dapr sidecar in the dapr section of the container app configuration with the app id, port
+// and protocol of the options. Settings of the sidecar that are not part of the options, ex) the log level, are kept
+func setDapr(section map[string]any, dapr *ContainerAppDapr) map[string]any {
+	if section == nil {
+		section = map[string]any{}
+	}
+
+	section["enabled"] = true
+	if dapr.AppId != "" {
+		section["appId"] = dapr.AppId
+	}
+
+	if dapr.AppPort > 0 {
+		section["appPort"] = dapr.AppPort
+	}
+
+	if dapr.AppProtocol != "" {
+		section["appProtocol"] = dapr.AppProtocol
+	}
+
+	return section
+}
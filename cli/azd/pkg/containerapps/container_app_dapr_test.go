@@ -0,0 +1,104 @@
+package containerapps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerApp_ApplyDaprComponents(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+
+	containerApp := &armappcontainers.ContainerApp{
+		Name: &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			// The managed environment is in a shared resource group
+			ManagedEnvironmentID: to.Ptr(
+				"/subscriptions/SUBSCRIPTION_ID/resourceGroups/SHARED/providers/Microsoft.App/managedEnvironments/cae-01"),
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	componentRequest := mockazsdk.MockContainerAppDaprComponentCreateOrUpdate(
+		mockContext, subscriptionId, "SHARED", "cae-01", "pubsub")
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	err := cas.ApplyDaprComponents(*mockContext.Context, subscriptionId, resourceGroup, appName,
+		[]*ContainerAppDaprComponent{
+			{
+				Name:          "pubsub",
+				ComponentType: "pubsub.azure.servicebus.topics",
+				Version:       "v1",
+				Metadata: []ContainerAppDaprMetadata{
+					{Name: "namespaceName", Value: "sb-01.servicebus.windows.net"},
+					{Name: "connectionString", SecretRef: "sb-connection"},
+				},
+				SecretStoreComponent: "secretstore",
+				Scopes:               []string{"api"},
+			},
+		})
+	require.NoError(t, err)
+
+	var component *armappcontainers.DaprComponent
+	require.NoError(t, json.NewDecoder(componentRequest.Body).Decode(&component))
+	require.Equal(t, "pubsub.azure.servicebus.topics", *component.Properties.ComponentType)
+	require.Equal(t, "v1", *component.Properties.Version)
+	require.Equal(t, "secretstore", *component.Properties.SecretStoreComponent)
+	require.Equal(t, []*string{to.Ptr("api")}, component.Properties.Scopes)
+	require.Equal(t, []*armappcontainers.DaprMetadata{
+		{Name: to.Ptr("namespaceName"), Value: to.Ptr("sb-01.servicebus.windows.net")},
+		{Name: to.Ptr("connectionString"), SecretRef: to.Ptr("sb-connection")},
+	}, component.Properties.Metadata)
+}
+
+func Test_ContainerApp_ApplyDaprComponents_NoEnvironment(t *testing.T) {
+	containerApp := &armappcontainers.ContainerApp{
+		Name:       to.Ptr("APP_NAME"),
+		Properties: &armappcontainers.ContainerAppProperties{},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppGet(mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "APP_NAME", containerApp)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	err := cas.ApplyDaprComponents(*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "APP_NAME",
+		[]*ContainerAppDaprComponent{{Name: "pubsub"}})
+	require.ErrorContains(t, err, "container app 'APP_NAME' does not reference a managed environment")
+}
+
+func Test_SetDapr(t *testing.T) {
+	section := setDapr(map[string]any{"enabled": false, "logLevel": "debug", "appPort": 80}, &ContainerAppDapr{
+		AppId:       "api",
+		AppProtocol: "grpc",
+	})
+
+	require.Equal(t, map[string]any{
+		"enabled":     true,
+		"logLevel":    "debug",
+		"appId":       "api",
+		"appPort":     80,
+		"appProtocol": "grpc",
+	}, section)
+
+	require.Equal(t, map[string]any{"enabled": true}, setDapr(nil, &ContainerAppDapr{}))
+}
@@ -0,0 +1,276 @@
+package containerapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+const (
+	pathLocation                   = "location"
+	pathCustomDomainVerificationId = "properties.customDomainVerificationId"
+
+	customDomainBindingDisabled   = "Disabled"
+	customDomainBindingSniEnabled = "SniEnabled"
+)
+
+// ContainerAppCustomDomain is a custom domain of a container app, bound with a managed certificate
+type ContainerAppCustomDomain struct {
+	// ex) api.contoso.com
+	HostName string
+	// How the managed certificate validates the ownership of the domain, CNAME, HTTP or TXT. Defaults to CNAME
+	Validation string
+}
+
+// BindCustomDomains adds the custom domains to the ingress of the container app and binds them with managed
+// certificates of the managed environment of the container app. Domains already bound with a certificate are left
+// unchanged. Returns azure.ErrCustomDomainNotVerified with the DNS records to create when the ownership of a domain can't
+// be verified.
+func (cas *containerAppService) BindCustomDomains(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	domains []*ContainerAppCustomDomain,
+	options *ContainerAppOptions,
+) error {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, options)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	fqdn, has := containerApp.GetString(pathConfigurationIngressFqdn)
+	if !has || fqdn == "" {
+		return fmt.Errorf("container app '%s' has no ingress, custom domains require an ingress", appName)
+	}
+
+	customDomains, _ := containerApp.GetSlice(pathConfigurationIngressCustomDomains)
+	pending := []*ContainerAppCustomDomain{}
+	for _, domain := range domains {
+		if binding := customDomainBinding(customDomains, domain.HostName); binding == nil ||
+			binding["bindingType"] != customDomainBindingSniEnabled {
+			pending = append(pending, domain)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	appClient, err := cas.createContainerAppsClient(ctx, subscriptionId, nil)
+	if err != nil {
+		return err
+	}
+
+	verificationId, _ := containerApp.GetString(pathCustomDomainVerificationId)
+	verificationErrs := []error{}
+	for _, domain := range pending {
+		if err := verifyCustomDomain(
+			ctx, appClient, resourceGroupName, appName, domain.HostName, fqdn, verificationId); err != nil {
+			verificationErrs = append(verificationErrs, err)
+		}
+	}
+
+	if len(verificationErrs) > 0 {
+		return errors.Join(verificationErrs...)
+	}
+
+	// The host name is added to the container app before the managed certificate is issued for it
+	for _, domain := range pending {
+		if customDomainBinding(customDomains, domain.HostName) == nil {
+			customDomains = append(customDomains, map[string]any{
+				"name":        domain.HostName,
+				"bindingType": customDomainBindingDisabled,
+			})
+		}
+	}
+
+	if err := containerApp.Set(pathConfigurationIngressCustomDomains, customDomains); err != nil {
+		return fmt.Errorf("setting custom domains: %w", err)
+	}
+
+	if err := cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp, options); err != nil {
+		return fmt.Errorf("adding custom domains: %w", err)
+	}
+
+	for _, domain := range pending {
+		certificateId, err := cas.ensureManagedCertificate(ctx, containerApp, appName, domain)
+		if err != nil {
+			return err
+		}
+
+		binding := customDomainBinding(customDomains, domain.HostName)
+		binding["bindingType"] = customDomainBindingSniEnabled
+		binding["certificateId"] = certificateId
+	}
+
+	if err := containerApp.Set(pathConfigurationIngressCustomDomains, customDomains); err != nil {
+		return fmt.Errorf("setting custom domains: %w", err)
+	}
+
+	if err := cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp, options); err != nil {
+		return fmt.Errorf("binding custom domains: %w", err)
+	}
+
+	return nil
+}
+
+// customDomainBinding returns the custom domain of the ingress configuration with the host name, nil when not found
+func customDomainBinding(customDomains []any, hostName string) map[string]any {
+	for _, customDomain := range customDomains {
+		if binding, ok := customDomain.(map[string]any); ok && strings.EqualFold(fmt.Sprint(binding["name"]), hostName) {
+			return binding
+		}
+	}
+
+	return nil
+}
+
+// verifyCustomDomain verifies the DNS records proving the ownership of the custom domain by the container app
+func verifyCustomDomain(
+	ctx context.Context,
+	appClient *armappcontainers.ContainerAppsClient,
+	resourceGroupName string,
+	appName string,
+	hostName string,
+	fqdn string,
+	verificationId string,
+) error {
+	analysis, err := appClient.ListCustomHostNameAnalysis(
+		ctx,
+		resourceGroupName,
+		appName,
+		&armappcontainers.ContainerAppsClientListCustomHostNameAnalysisOptions{CustomHostname: to.Ptr(hostName)},
+	)
+	if err != nil {
+		return fmt.Errorf("analyzing custom domain '%s': %w", hostName, err)
+	}
+
+	if analysis.HasConflictOnManagedEnvironment != nil && *analysis.HasConflictOnManagedEnvironment {
+		return fmt.Errorf(
+			"custom domain '%s' is already used by container app '%s'",
+			hostName,
+			convert.ToValueWithDefault(analysis.ConflictingContainerAppResourceID, ""),
+		)
+	}
+
+	if (analysis.IsHostnameAlreadyVerified != nil && *analysis.IsHostnameAlreadyVerified) ||
+		(analysis.CustomDomainVerificationTest != nil &&
+			*analysis.CustomDomainVerificationTest == armappcontainers.DNSVerificationTestResultPassed) {
+		return nil
+	}
+
+	reason := ""
+	if analysis.CustomDomainVerificationFailureInfo != nil {
+		reason = convert.ToValueWithDefault(analysis.CustomDomainVerificationFailureInfo.Message, "")
+	}
+
+	record := fmt.Sprintf(
+		"CNAME %s -> %s (or an A record with the static IP of the managed environment for an apex domain)", hostName, fqdn)
+	return azure.NewCustomDomainNotVerifiedError(hostName, reason, record, verificationId)
+}
+
+// ensureManagedCertificate returns the id of the managed certificate of the custom domain in the managed environment
+// of the container app, issuing the certificate when the environment has none for the domain
+func (cas *containerAppService) ensureManagedCertificate(
+	ctx context.Context,
+	containerApp config.Config,
+	appName string,
+	domain *ContainerAppCustomDomain,
+) (string, error) {
+	environmentId := managedEnvironmentId(containerApp)
+	if environmentId == "" {
+		return "", fmt.Errorf("container app '%s' does not reference a managed environment", appName)
+	}
+
+	// The managed environment can be in another resource group than the container app
+	environment, err := arm.ParseResourceID(environmentId)
+	if err != nil {
+		return "", fmt.Errorf("parsing managed environment id '%s': %w", environmentId, err)
+	}
+
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, environment.SubscriptionID)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := armappcontainers.NewManagedCertificatesClient(
+		environment.SubscriptionID, credential, cas.armClientOptions)
+	if err != nil {
+		return "", fmt.Errorf("creating managed certificates client: %w", err)
+	}
+
+	pager := client.NewListPager(environment.ResourceGroupName, environment.Name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("listing managed certificates of environment '%s': %w", environment.Name, err)
+		}
+
+		for _, certificate := range page.Value {
+			// Certificates that failed to be issued are issued again, ex) once the DNS records are fixed
+			if certificate.Properties != nil && certificate.ID != nil &&
+				strings.EqualFold(convert.ToValueWithDefault(certificate.Properties.SubjectName, ""), domain.HostName) &&
+				convert.ToValueWithDefault(certificate.Properties.ProvisioningState, "") !=
+					armappcontainers.CertificateProvisioningStateFailed {
+				log.Printf("using managed certificate '%s' for custom domain '%s'", *certificate.ID, domain.HostName)
+				return *certificate.ID, nil
+			}
+		}
+	}
+
+	validation := armappcontainers.ManagedCertificateDomainControlValidationCNAME
+	if domain.Validation != "" {
+		validation = armappcontainers.ManagedCertificateDomainControlValidation(strings.ToUpper(domain.Validation))
+	}
+
+	location, _ := containerApp.GetString(pathLocation)
+	poller, err := client.BeginCreateOrUpdate(
+		ctx,
+		environment.ResourceGroupName,
+		environment.Name,
+		azure.ManagedCertificateName(domain.HostName),
+		&armappcontainers.ManagedCertificatesClientBeginCreateOrUpdateOptions{
+			ManagedCertificateEnvelope: &armappcontainers.ManagedCertificate{
+				Location: to.Ptr(location),
+				Properties: &armappcontainers.ManagedCertificateProperties{
+					SubjectName:             to.Ptr(domain.HostName),
+					DomainControlValidation: to.Ptr(validation),
+				},
+			},
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("issuing managed certificate for custom domain '%s': %w", domain.HostName, err)
+	}
+
+	response, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("issuing managed certificate for custom domain '%s': %w", domain.HostName, err)
+	}
+
+	if response.ID == nil {
+		return "", fmt.Errorf("issuing managed certificate for custom domain '%s': missing certificate id", domain.HostName)
+	}
+
+	return *response.ID, nil
+}
+
+// managedEnvironmentId returns the id of the managed environment of the container app, empty when not set
+func managedEnvironmentId(containerApp config.Config) string {
+	environmentId, has := containerApp.GetString(pathManagedEnvironmentId)
+	if !has || environmentId == "" {
+		environmentId, _ = containerApp.GetString(pathEnvironmentId)
+	}
+
+	return environmentId
+}
@@ -0,0 +1,168 @@
+package containerapps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerApp_BindCustomDomains(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	certificateId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/SHARED/providers/Microsoft.App/managedEnvironments/" +
+		"cae-01/managedCertificates/mc-www-contoso-com"
+
+	createContainerApp := func(customDomains ...*armappcontainers.CustomDomain) *armappcontainers.ContainerApp {
+		return &armappcontainers.ContainerApp{
+			Location: to.Ptr("eastus2"),
+			Name:     &appName,
+			Properties: &armappcontainers.ContainerAppProperties{
+				// The managed environment is in a shared resource group
+				ManagedEnvironmentID: to.Ptr(
+					"/subscriptions/SUBSCRIPTION_ID/resourceGroups/SHARED/providers/Microsoft.App/" +
+						"managedEnvironments/cae-01"),
+				CustomDomainVerificationID: to.Ptr("VERIFICATION_ID"),
+				Configuration: &armappcontainers.Configuration{
+					Ingress: &armappcontainers.Ingress{
+						Fqdn:          to.Ptr("app.eastus2.azurecontainerapps.io"),
+						CustomDomains: customDomains,
+					},
+				},
+			},
+		}
+	}
+
+	createContainerAppService := func(mockContext *mocks.MockContext) ContainerAppService {
+		return NewContainerAppService(
+			mockContext.SubscriptionCredentialProvider,
+			clock.NewMock(),
+			mockContext.ArmClientOptions,
+			mockContext.AlphaFeaturesManager,
+		)
+	}
+
+	domains := []*ContainerAppCustomDomain{
+		{HostName: "api.contoso.com", Validation: "http"},
+		{HostName: "www.contoso.com"},
+	}
+
+	t.Run("Bind", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, createContainerApp())
+		mockazsdk.MockContainerAppCustomHostNameAnalysis(mockContext, subscriptionId, resourceGroup, appName,
+			&armappcontainers.CustomHostnameAnalysisResult{
+				CustomDomainVerificationTest: to.Ptr(armappcontainers.DNSVerificationTestResultPassed),
+			})
+		// The certificate of www.contoso.com was issued by a previous deployment
+		mockazsdk.MockContainerAppManagedCertificatesList(mockContext, subscriptionId, "SHARED", "cae-01",
+			[]*armappcontainers.ManagedCertificate{
+				{
+					ID: &certificateId,
+					Properties: &armappcontainers.ManagedCertificateProperties{
+						SubjectName:       to.Ptr("www.contoso.com"),
+						ProvisioningState: to.Ptr(armappcontainers.CertificateProvisioningStateSucceeded),
+					},
+				},
+			})
+		certificateRequest := mockazsdk.MockContainerAppManagedCertificateCreateOrUpdate(
+			mockContext, subscriptionId, "SHARED", "cae-01", "mc-api-contoso-com")
+		updateRequest := mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, nil)
+
+		cas := createContainerAppService(mockContext)
+		err := cas.BindCustomDomains(*mockContext.Context, subscriptionId, resourceGroup, appName, domains, nil)
+		require.NoError(t, err)
+
+		var certificate *armappcontainers.ManagedCertificate
+		require.NoError(t, json.NewDecoder(certificateRequest.Body).Decode(&certificate))
+		require.Equal(t, "eastus2", *certificate.Location)
+		require.Equal(t, "api.contoso.com", *certificate.Properties.SubjectName)
+		require.Equal(t,
+			armappcontainers.ManagedCertificateDomainControlValidationHTTP, *certificate.Properties.DomainControlValidation)
+
+		var updatedApp *armappcontainers.ContainerApp
+		require.NoError(t, json.NewDecoder(updateRequest.Body).Decode(&updatedApp))
+		customDomains := updatedApp.Properties.Configuration.Ingress.CustomDomains
+		require.Len(t, customDomains, 2)
+		require.Equal(t, "api.contoso.com", *customDomains[0].Name)
+		require.Equal(t, armappcontainers.BindingTypeSniEnabled, *customDomains[0].BindingType)
+		require.Contains(t, *customDomains[0].CertificateID, "/managedCertificates/mc-api-contoso-com")
+		require.Equal(t, "www.contoso.com", *customDomains[1].Name)
+		require.Equal(t, certificateId, *customDomains[1].CertificateID)
+	})
+
+	t.Run("AlreadyBound", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerApp := createContainerApp(
+			&armappcontainers.CustomDomain{
+				Name:          to.Ptr("api.contoso.com"),
+				BindingType:   to.Ptr(armappcontainers.BindingTypeSniEnabled),
+				CertificateID: to.Ptr("CERTIFICATE_ID"),
+			},
+		)
+		_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+		updateRequest := mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, nil)
+
+		cas := createContainerAppService(mockContext)
+		err := cas.BindCustomDomains(*mockContext.Context, subscriptionId, resourceGroup, appName, domains[:1], nil)
+		require.NoError(t, err)
+		require.Nil(t, updateRequest.URL)
+	})
+
+	t.Run("NotVerified", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, createContainerApp())
+		mockazsdk.MockContainerAppCustomHostNameAnalysis(mockContext, subscriptionId, resourceGroup, appName,
+			&armappcontainers.CustomHostnameAnalysisResult{
+				CustomDomainVerificationTest: to.Ptr(armappcontainers.DNSVerificationTestResultFailed),
+			})
+		updateRequest := mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, nil)
+
+		cas := createContainerAppService(mockContext)
+		err := cas.BindCustomDomains(*mockContext.Context, subscriptionId, resourceGroup, appName, domains[:1], nil)
+		require.ErrorIs(t, err, azure.ErrCustomDomainNotVerified)
+		require.ErrorContains(t, err, "CNAME api.contoso.com -> app.eastus2.azurecontainerapps.io")
+		require.ErrorContains(t, err, "TXT asuid.api.contoso.com -> VERIFICATION_ID")
+		require.Nil(t, updateRequest.URL)
+	})
+}
+
+func Test_ContainerApp_GetIngressConfiguration_CustomDomains(t *testing.T) {
+	containerApp := &armappcontainers.ContainerApp{
+		Name: to.Ptr("APP_NAME"),
+		Properties: &armappcontainers.ContainerAppProperties{
+			Configuration: &armappcontainers.Configuration{
+				Ingress: &armappcontainers.Ingress{
+					Fqdn: to.Ptr("app.eastus2.azurecontainerapps.io"),
+					CustomDomains: []*armappcontainers.CustomDomain{
+						{Name: to.Ptr("api.contoso.com"), BindingType: to.Ptr(armappcontainers.BindingTypeSniEnabled)},
+						// Domains not bound with a certificate are not served over https
+						{Name: to.Ptr("www.contoso.com"), BindingType: to.Ptr(armappcontainers.BindingTypeDisabled)},
+					},
+				},
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppGet(mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "APP_NAME", containerApp)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	ingressConfig, err := cas.GetIngressConfiguration(
+		*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "APP_NAME", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"app.eastus2.azurecontainerapps.io", "api.contoso.com"}, ingressConfig.HostNames)
+}
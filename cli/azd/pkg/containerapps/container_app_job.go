@@ -0,0 +1,347 @@
+package containerapps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/benbjohnson/clock"
+)
+
+const (
+	pathConfigurationTriggerType    = "properties.configuration.triggerType"
+	pathConfigurationCronExpression = "properties.configuration.scheduleTriggerConfig.cronExpression"
+)
+
+// The interval between the checks of the status of a job execution
+const jobExecutionPollInterval = 5 * time.Second
+
+// ErrJobExecutionFailed is returned when an execution of a job failed, was stopped or did not complete in time
+var ErrJobExecutionFailed = errors.New("job execution failed")
+
+// ContainerAppJobService exposes operations for managing Azure Container Apps Jobs
+type ContainerAppJobService interface {
+	// Updates the image of the first container of the specified job and returns the updated job
+	UpdateImage(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+		imageName string,
+		options *ContainerAppOptions,
+	) (*ContainerAppJob, error)
+	// Starts an execution of the specified job and returns the name of the execution
+	StartExecution(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+		options *ContainerAppOptions,
+	) (string, error)
+	// Waits until the execution of the specified job completes, returns ErrJobExecutionFailed when the execution
+	// failed, was stopped or did not complete within the timeout
+	WaitForExecution(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+		executionName string,
+		timeout time.Duration,
+	) (*ContainerAppJobExecution, error)
+}
+
+// ContainerAppJob is a Container Apps Job along with how its executions are triggered
+type ContainerAppJob struct {
+	Name string
+	// How the executions of the job are triggered, ex) Manual, Schedule or Event
+	TriggerType string
+	// The cron expression of a scheduled job, ex) */5 * * * *
+	CronExpression string
+}
+
+// ContainerAppJobExecution is an execution of a Container Apps Job
+type ContainerAppJobExecution struct {
+	Name string
+	// ex) Running, Succeeded or Failed
+	Status    string
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// NewContainerAppJobService creates a new ContainerAppJobService
+func NewContainerAppJobService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	clock clock.Clock,
+	armClientOptions *arm.ClientOptions,
+) ContainerAppJobService {
+	return &containerAppJobService{
+		credentialProvider: credentialProvider,
+		clock:              clock,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+type containerAppJobService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	clock              clock.Clock
+	armClientOptions   *arm.ClientOptions
+}
+
+// Updates the image of the first container of the specified job and returns the updated job
+// The environment variables and the registry of the options are applied to the job as for a container app revision.
+func (cjs *containerAppJobService) UpdateImage(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	imageName string,
+	options *ContainerAppOptions,
+) (*ContainerAppJob, error) {
+	jobsClient, err := cjs.createJobsClient(ctx, subscriptionId, createApiVersionPolicy(options))
+	if err != nil {
+		return nil, err
+	}
+
+	var jobResponse *http.Response
+	if _, err := jobsClient.Get(policy.WithCaptureResponse(ctx, &jobResponse), resourceGroupName, jobName, nil); err != nil {
+		return nil, fmt.Errorf("getting container app job: %w", err)
+	}
+
+	var jobMap map[string]any
+	if err := convert.FromHttpResponse(jobResponse, &jobMap); err != nil {
+		return nil, err
+	}
+
+	job := config.NewConfig(jobMap)
+
+	var containers []map[string]any
+	if ok, err := job.GetSection(pathTemplateContainers, &containers); !ok || err != nil || len(containers) == 0 {
+		return nil, fmt.Errorf("container app job '%s' does not have any containers: %w", jobName, err)
+	}
+
+	containers[0]["image"] = imageName
+	if options != nil && len(options.Env) > 0 {
+		setContainerEnv(containers[0], options.Env)
+	}
+
+	if err := job.Set(pathTemplateContainers, containers); err != nil {
+		return nil, fmt.Errorf("setting containers: %w", err)
+	}
+
+	// Secret values are not returned by the API, they are listed separately to ensure the update call succeeds
+	if existingSecrets, ok := job.GetSlice(pathConfigurationSecrets); ok && len(existingSecrets) > 0 {
+		secretsResponse, err := jobsClient.ListSecrets(ctx, resourceGroupName, jobName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing secrets: %w", err)
+		}
+
+		secretsJson, err := convert.ToJsonArray(secretsResponse.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := job.Set(pathConfigurationSecrets, secretsJson); err != nil {
+			return nil, fmt.Errorf("setting secrets: %w", err)
+		}
+	}
+
+	if options != nil && options.Registry != nil {
+		if err := setRegistry(job, options.Registry); err != nil {
+			return nil, fmt.Errorf("setting registry: %w", err)
+		}
+	}
+
+	if err := cjs.updateJob(ctx, subscriptionId, resourceGroupName, jobName, job, options); err != nil {
+		return nil, fmt.Errorf("updating container app job: %w", err)
+	}
+
+	result := &ContainerAppJob{Name: jobName}
+	result.TriggerType, _ = job.GetString(pathConfigurationTriggerType)
+	result.CronExpression, _ = job.GetString(pathConfigurationCronExpression)
+
+	return result, nil
+}
+
+// Starts an execution of the specified job with its current template and returns the name of the execution
+func (cjs *containerAppJobService) StartExecution(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	options *ContainerAppOptions,
+) (string, error) {
+	jobsClient, err := cjs.createJobsClient(ctx, subscriptionId, createApiVersionPolicy(options))
+	if err != nil {
+		return "", err
+	}
+
+	poller, err := jobsClient.BeginStart(ctx, resourceGroupName, jobName, nil)
+	if err != nil {
+		return "", fmt.Errorf("starting execution of container app job '%s': %w", jobName, err)
+	}
+
+	response, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("polling for container app job start completion: %w", err)
+	}
+
+	if response.Name == nil {
+		return "", fmt.Errorf("container app job '%s' did not return the name of the execution", jobName)
+	}
+
+	return *response.Name, nil
+}
+
+// Waits until the execution of the specified job completes, returns ErrJobExecutionFailed when the execution
+// failed, was stopped or did not complete within the timeout
+func (cjs *containerAppJobService) WaitForExecution(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	executionName string,
+	timeout time.Duration,
+) (*ContainerAppJobExecution, error) {
+	credential, err := cjs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient, err := armappcontainers.NewContainerAppsAPIClient(subscriptionId, credential, cjs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerApps API client: %w", err)
+	}
+
+	deadline := cjs.clock.Now().Add(timeout)
+	for {
+		response, err := apiClient.JobExecution(ctx, resourceGroupName, jobName, executionName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting execution '%s': %w", executionName, err)
+		}
+
+		execution := newContainerAppJobExecution(&response.JobExecution)
+		switch armappcontainers.JobExecutionRunningState(execution.Status) {
+		case armappcontainers.JobExecutionRunningStateSucceeded:
+			return execution, nil
+		case armappcontainers.JobExecutionRunningStateFailed,
+			armappcontainers.JobExecutionRunningStateStopped,
+			armappcontainers.JobExecutionRunningStateDegraded:
+			return execution, fmt.Errorf("%w: execution '%s' is %s", ErrJobExecutionFailed, executionName, execution.Status)
+		}
+
+		if !cjs.clock.Now().Before(deadline) {
+			return execution, fmt.Errorf(
+				"%w: execution '%s' did not complete within %s, status '%s'",
+				ErrJobExecutionFailed,
+				executionName,
+				timeout,
+				execution.Status,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-cjs.clock.After(jobExecutionPollInterval):
+		}
+	}
+}
+
+func newContainerAppJobExecution(execution *armappcontainers.JobExecution) *ContainerAppJobExecution {
+	result := &ContainerAppJobExecution{}
+	if execution.Name != nil {
+		result.Name = *execution.Name
+	}
+
+	if properties := execution.Properties; properties != nil {
+		if properties.Status != nil {
+			result.Status = string(*properties.Status)
+		}
+		if properties.StartTime != nil {
+			result.StartTime = *properties.StartTime
+		}
+		if properties.EndTime != nil {
+			result.EndTime = *properties.EndTime
+		}
+	}
+
+	return result
+}
+
+func (cjs *containerAppJobService) updateJob(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	job config.Config,
+	options *ContainerAppOptions,
+) error {
+	jobJson, err := json.Marshal(job.Raw())
+	if err != nil {
+		return fmt.Errorf("marshalling container app job: %w", err)
+	}
+
+	apiVersionPolicy := createApiVersionPolicy(options)
+	if apiVersionPolicy != nil {
+		apiVersionPolicy.body = (*json.RawMessage)(&jobJson)
+	}
+
+	jobsClient, err := cjs.createJobsClient(ctx, subscriptionId, apiVersionPolicy)
+	if err != nil {
+		return err
+	}
+
+	// This job BODY will be replaced by the custom policy when configured
+	var jobResource armappcontainers.JobPatchProperties
+	if apiVersionPolicy == nil {
+		if err := json.Unmarshal(jobJson, &jobResource); err != nil {
+			return fmt.Errorf("failed to unmarshal container app job: %w", err)
+		}
+	}
+
+	poller, err := jobsClient.BeginUpdate(ctx, resourceGroupName, jobName, jobResource, nil)
+	if err != nil {
+		return fmt.Errorf("begin updating container app job: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("polling for container app job update completion: %w", err)
+	}
+
+	return nil
+}
+
+func (cjs *containerAppJobService) createJobsClient(
+	ctx context.Context,
+	subscriptionId string,
+	customPolicy *containerAppCustomApiVersionAndBodyPolicy,
+) (*armappcontainers.JobsClient, error) {
+	credential, err := cjs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := *cjs.armClientOptions
+
+	if customPolicy != nil {
+		// Clone the options so we don't modify the original - we don't want to inject this custom policy into every request.
+		options.PerCallPolicies = append(slices.Clone(options.PerCallPolicies), customPolicy)
+	}
+
+	client, err := armappcontainers.NewJobsClient(subscriptionId, credential, &options)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerApps Jobs client: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,158 @@
+package containerapps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerAppJob_UpdateImage(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	jobName := "JOB_NAME"
+
+	job := &armappcontainers.Job{
+		Name: &jobName,
+		Properties: &armappcontainers.JobProperties{
+			Configuration: &armappcontainers.JobConfiguration{
+				TriggerType: to.Ptr(armappcontainers.TriggerTypeSchedule),
+				ScheduleTriggerConfig: &armappcontainers.JobConfigurationScheduleTriggerConfig{
+					CronExpression: to.Ptr("*/5 * * * *"),
+				},
+				Secrets: []*armappcontainers.Secret{
+					{Name: to.Ptr("secret")},
+				},
+			},
+			Template: &armappcontainers.JobTemplate{
+				Containers: []*armappcontainers.Container{
+					{Image: to.Ptr("contoso.azurecr.io/job:old")},
+				},
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppJobGet(mockContext, subscriptionId, resourceGroup, jobName, job)
+	_ = mockazsdk.MockContainerAppJobSecretsList(
+		mockContext,
+		subscriptionId,
+		resourceGroup,
+		jobName,
+		&armappcontainers.JobSecretsCollection{
+			Value: []*armappcontainers.Secret{
+				{Name: to.Ptr("secret"), Value: to.Ptr("value")},
+			},
+		},
+	)
+	updateRequest := mockazsdk.MockContainerAppJobUpdate(mockContext, subscriptionId, resourceGroup, jobName)
+
+	cjs := NewContainerAppJobService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+	)
+	updatedJob, err := cjs.UpdateImage(
+		*mockContext.Context,
+		subscriptionId,
+		resourceGroup,
+		jobName,
+		"contoso.azurecr.io/job:new",
+		&ContainerAppOptions{Env: map[string]string{"MODE": "batch"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Schedule", updatedJob.TriggerType)
+	require.Equal(t, "*/5 * * * *", updatedJob.CronExpression)
+
+	var patch *armappcontainers.JobPatchProperties
+	require.NoError(t, json.NewDecoder(updateRequest.Body).Decode(&patch))
+
+	container := patch.Properties.Template.Containers[0]
+	require.Equal(t, "contoso.azurecr.io/job:new", *container.Image)
+	require.Len(t, container.Env, 1)
+	require.Equal(t, "MODE", *container.Env[0].Name)
+	require.Equal(t, "batch", *container.Env[0].Value)
+
+	// The secret values are listed so the update does not clear them
+	require.Equal(t, "value", *patch.Properties.Configuration.Secrets[0].Value)
+}
+
+func Test_ContainerAppJob_StartExecution(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	startRequest := mockazsdk.MockContainerAppJobStart(
+		mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "JOB_NAME", "JOB_NAME-abc123")
+
+	cjs := NewContainerAppJobService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+	)
+	executionName, err := cjs.StartExecution(*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "JOB_NAME", nil)
+	require.NoError(t, err)
+	require.Equal(t, "JOB_NAME-abc123", executionName)
+	require.Contains(t, startRequest.URL.Path, "/jobs/JOB_NAME/start")
+}
+
+func Test_ContainerAppJob_WaitForExecution(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      armappcontainers.JobExecutionRunningState
+		expectedErr string
+	}{
+		{
+			name:   "Succeeded",
+			status: armappcontainers.JobExecutionRunningStateSucceeded,
+		},
+		{
+			name:        "Failed",
+			status:      armappcontainers.JobExecutionRunningStateFailed,
+			expectedErr: "execution 'JOB_NAME-abc123' is Failed",
+		},
+		{
+			// The timeout elapses before the execution completes
+			name:        "Timeout",
+			status:      armappcontainers.JobExecutionRunningStateRunning,
+			expectedErr: "did not complete within 0s, status 'Running'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			_ = mockazsdk.MockContainerAppJobExecutionGet(
+				mockContext,
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"JOB_NAME",
+				&armappcontainers.JobExecution{
+					Name: to.Ptr("JOB_NAME-abc123"),
+					Properties: &armappcontainers.JobExecutionProperties{
+						Status: to.Ptr(tt.status),
+					},
+				},
+			)
+
+			cjs := NewContainerAppJobService(
+				mockContext.SubscriptionCredentialProvider,
+				clock.NewMock(),
+				mockContext.ArmClientOptions,
+			)
+			execution, err := cjs.WaitForExecution(
+				*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "JOB_NAME", "JOB_NAME-abc123", 0)
+			if tt.expectedErr != "" {
+				require.ErrorIs(t, err, ErrJobExecutionFailed)
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "Succeeded", execution.Status)
+		})
+	}
+}
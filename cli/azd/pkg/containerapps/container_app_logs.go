@@ -0,0 +1,170 @@
+package containerapps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/internal"
+)
+
+// The maximum number of recent log entries returned by the Container Apps log stream
+const maxLogStreamTailLines = 300
+
+// ContainerAppLogSource is a container of a container app replica whose console logs can be streamed
+type ContainerAppLogSource struct {
+	Replica   string
+	Container string
+	endpoint  string
+	token     string
+}
+
+// ContainerAppLogsOptions are the options for streaming the console logs of a container app
+type ContainerAppLogsOptions struct {
+	// Streams new log entries until the context is cancelled
+	Follow bool
+	// The number of recent log entries to return, at most 300
+	Tail int
+}
+
+// Lists the containers of the replicas of the latest ready revision of the container app
+// When the container name is not empty, only the containers with the specified name are returned
+func (cas *containerAppService) ListLogSources(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	containerName string,
+) ([]*ContainerAppLogSource, error) {
+	appClient, err := cas.createContainerAppsClient(ctx, subscriptionId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	containerApp, err := appClient.Get(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app: %w", err)
+	}
+
+	if containerApp.Properties == nil || containerApp.Properties.LatestReadyRevisionName == nil {
+		return nil, fmt.Errorf("container app '%s' does not have a ready revision", appName)
+	}
+
+	authToken, err := appClient.GetAuthToken(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app auth token: %w", err)
+	}
+
+	if authToken.Properties == nil || authToken.Properties.Token == nil {
+		return nil, fmt.Errorf("container app '%s' did not return an auth token", appName)
+	}
+
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	replicasClient, err := armappcontainers.NewContainerAppsRevisionReplicasClient(
+		subscriptionId, credential, cas.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerApps replicas client: %w", err)
+	}
+
+	revisionName := *containerApp.Properties.LatestReadyRevisionName
+	replicas, err := replicasClient.ListReplicas(ctx, resourceGroupName, appName, revisionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing replicas of revision '%s': %w", revisionName, err)
+	}
+
+	sources := []*ContainerAppLogSource{}
+	for _, replica := range replicas.Value {
+		if replica.Name == nil || replica.Properties == nil {
+			continue
+		}
+
+		for _, container := range replica.Properties.Containers {
+			if container.Name == nil || container.LogStreamEndpoint == nil {
+				continue
+			}
+
+			if containerName != "" && *container.Name != containerName {
+				continue
+			}
+
+			sources = append(sources, &ContainerAppLogSource{
+				Replica:   *replica.Name,
+				Container: *container.Name,
+				endpoint:  *container.LogStreamEndpoint,
+				token:     *authToken.Properties.Token,
+			})
+		}
+	}
+
+	return sources, nil
+}
+
+// Streams the console logs of the container to the writer
+// When following the logs, blocks until the context is cancelled or the log stream is closed
+func (cas *containerAppService) StreamLogs(
+	ctx context.Context,
+	source *ContainerAppLogSource,
+	options *ContainerAppLogsOptions,
+	writer io.Writer,
+) error {
+	if options == nil {
+		options = &ContainerAppLogsOptions{}
+	}
+
+	endpoint, err := url.Parse(source.endpoint)
+	if err != nil {
+		return fmt.Errorf("parsing log stream endpoint: %w", err)
+	}
+
+	query := endpoint.Query()
+	query.Set("follow", strconv.FormatBool(options.Follow))
+	query.Set("output", "text")
+	if options.Tail > 0 {
+		query.Set("tailLines", strconv.Itoa(min(options.Tail, maxLogStreamTailLines)))
+	}
+	endpoint.RawQuery = query.Encode()
+
+	// The log stream is authenticated with the container app auth token instead of an ARM token
+	var clientOptions policy.ClientOptions
+	if cas.armClientOptions != nil {
+		clientOptions = cas.armClientOptions.ClientOptions
+	}
+	pipeline := runtime.NewPipeline("azd-containerapps-logs", internal.Version, runtime.PipelineOptions{}, &clientOptions)
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint.String())
+	if err != nil {
+		return fmt.Errorf("creating log stream request: %w", err)
+	}
+	req.Raw().Header.Set("Authorization", fmt.Sprintf("Bearer %s", source.token))
+	runtime.SkipBodyDownload(req)
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return fmt.Errorf("streaming logs of container '%s': %w", source.Container, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return runtime.NewResponseError(response)
+	}
+
+	if _, err := io.Copy(writer, response.Body); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("reading logs of container '%s': %w", source.Container, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package containerapps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerApp_StreamLogs(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	logStreamEndpoint := "https://eastus2.azurecontainerapps.dev/subscriptions/SUBSCRIPTION_ID/resourceGroups/" +
+		"RESOURCE_GROUP/containerApps/APP_NAME/revisions/APP_NAME--rev2/replicas/APP_NAME--rev2-abc/containers/%s/logstream"
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, &armappcontainers.ContainerApp{
+		Name: &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			LatestRevisionName:      to.Ptr("APP_NAME--rev3"),
+			LatestReadyRevisionName: to.Ptr("APP_NAME--rev2"),
+		},
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/getAuthtoken")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappcontainers.ContainerAppAuthToken{
+			Properties: &armappcontainers.ContainerAppAuthTokenProperties{Token: to.Ptr("TOKEN")},
+		})
+	})
+
+	var replicasPath string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/replicas")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		replicasPath = request.URL.Path
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappcontainers.ReplicaCollection{
+			Value: []*armappcontainers.Replica{
+				{
+					Name: to.Ptr("APP_NAME--rev2-abc"),
+					Properties: &armappcontainers.ReplicaProperties{
+						Containers: []*armappcontainers.ReplicaContainer{
+							{
+								Name:              to.Ptr("api"),
+								LogStreamEndpoint: to.Ptr(fmt.Sprintf(logStreamEndpoint, "api")),
+							},
+							{
+								Name:              to.Ptr("sidecar"),
+								LogStreamEndpoint: to.Ptr(fmt.Sprintf(logStreamEndpoint, "sidecar")),
+							},
+						},
+					},
+				},
+			},
+		})
+	})
+
+	var logStreamRequest *http.Request
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/logstream")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		logStreamRequest = request
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Request:    request,
+			Body:       io.NopCloser(bytes.NewBufferString("listening on port 3000\n")),
+		}, nil
+	})
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+
+	sources, err := cas.ListLogSources(*mockContext.Context, subscriptionId, resourceGroup, appName, "")
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	require.Contains(t, replicasPath, "/revisions/APP_NAME--rev2/replicas")
+	require.Equal(t, "APP_NAME--rev2-abc", sources[0].Replica)
+	require.Equal(t, "api", sources[0].Container)
+
+	sources, err = cas.ListLogSources(*mockContext.Context, subscriptionId, resourceGroup, appName, "sidecar")
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	require.Equal(t, "sidecar", sources[0].Container)
+
+	output := &strings.Builder{}
+	err = cas.StreamLogs(*mockContext.Context, sources[0], &ContainerAppLogsOptions{Follow: true, Tail: 500}, output)
+	require.NoError(t, err)
+	require.Equal(t, "listening on port 3000\n", output.String())
+	require.Equal(t, "Bearer TOKEN", logStreamRequest.Header.Get("Authorization"))
+	require.Contains(t, logStreamRequest.URL.Path, "/containers/sidecar/logstream")
+	require.Equal(t, "true", logStreamRequest.URL.Query().Get("follow"))
+	require.Equal(t, "300", logStreamRequest.URL.Query().Get("tailLines"))
+}
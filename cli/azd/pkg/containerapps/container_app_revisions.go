@@ -0,0 +1,263 @@
+package containerapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+// The interval between the checks of the health of a revision
+const revisionPollInterval = 5 * time.Second
+
+// ErrRevisionUnhealthy is returned when a revision failed to provision, its health probes fail or it did not become
+// healthy in time
+var ErrRevisionUnhealthy = errors.New("revision is unhealthy")
+
+// ContainerAppRevision is a revision of a container app along with the percentage of the ingress traffic routed to it
+type ContainerAppRevision struct {
+	Name   string
+	Active bool
+	// The percentage of the ingress traffic routed to the revision, ex) 10
+	TrafficWeight int
+	Replicas      int
+	// The health of the revision as reported by its health probes, ex) Healthy, Unhealthy or None
+	HealthState string
+	// ex) Provisioning, Provisioned or Failed
+	ProvisioningState string
+	// ex) Running, Stopped or Failed
+	RunningState string
+	// The image of the first container of the revision
+	Image       string
+	CreatedTime time.Time
+}
+
+// healthy returns true when the revision is provisioned and its health probes succeed. Revisions without health
+// probes are healthy once they are running.
+func (r *ContainerAppRevision) healthy() bool {
+	if r.ProvisioningState != string(armappcontainers.RevisionProvisioningStateProvisioned) {
+		return false
+	}
+
+	return r.HealthState == string(armappcontainers.RevisionHealthStateHealthy) ||
+		(r.HealthState == string(armappcontainers.RevisionHealthStateNone) &&
+			r.RunningState == string(armappcontainers.RevisionRunningStateRunning))
+}
+
+// failed returns true when the revision failed to provision or to run, or when its health probes fail once it is
+// provisioned
+func (r *ContainerAppRevision) failed() bool {
+	if r.ProvisioningState == string(armappcontainers.RevisionProvisioningStateFailed) ||
+		r.RunningState == string(armappcontainers.RevisionRunningStateFailed) {
+		return true
+	}
+
+	return r.ProvisioningState == string(armappcontainers.RevisionProvisioningStateProvisioned) &&
+		r.HealthState == string(armappcontainers.RevisionHealthStateUnhealthy)
+}
+
+func newContainerAppRevision(revision *armappcontainers.Revision) *ContainerAppRevision {
+	result := &ContainerAppRevision{}
+	if revision.Name != nil {
+		result.Name = *revision.Name
+	}
+
+	properties := revision.Properties
+	if properties == nil {
+		return result
+	}
+
+	if properties.Active != nil {
+		result.Active = *properties.Active
+	}
+	if properties.TrafficWeight != nil {
+		result.TrafficWeight = int(*properties.TrafficWeight)
+	}
+	if properties.Replicas != nil {
+		result.Replicas = int(*properties.Replicas)
+	}
+	if properties.HealthState != nil {
+		result.HealthState = string(*properties.HealthState)
+	}
+	if properties.ProvisioningState != nil {
+		result.ProvisioningState = string(*properties.ProvisioningState)
+	}
+	if properties.RunningState != nil {
+		result.RunningState = string(*properties.RunningState)
+	}
+	if properties.CreatedTime != nil {
+		result.CreatedTime = *properties.CreatedTime
+	}
+	if properties.Template != nil && len(properties.Template.Containers) > 0 &&
+		properties.Template.Containers[0].Image != nil {
+		result.Image = *properties.Template.Containers[0].Image
+	}
+
+	return result
+}
+
+// Lists the revisions of the specified container app, the most recently created revision first
+func (cas *containerAppService) ListRevisions(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	options *ContainerAppOptions,
+) ([]*ContainerAppRevision, error) {
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId, createApiVersionPolicy(options))
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []*ContainerAppRevision{}
+	pager := revisionsClient.NewListRevisionsPager(resourceGroupName, appName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing revisions: %w", err)
+		}
+
+		for _, revision := range page.Value {
+			revisions = append(revisions, newContainerAppRevision(revision))
+		}
+	}
+
+	slices.SortStableFunc(revisions, func(a, b *ContainerAppRevision) int {
+		return b.CreatedTime.Compare(a.CreatedTime)
+	})
+
+	return revisions, nil
+}
+
+// Routes the ingress traffic of the specified container app to the revisions by their percentage of the traffic
+// The container app is switched to the multiple revisions mode so that the revisions remain active.
+func (cas *containerAppService) SetTraffic(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	weights map[string]int,
+	options *ContainerAppOptions,
+) error {
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	if total != 100 {
+		return fmt.Errorf("the traffic weights of the revisions add up to %d%%, expected 100%%", total)
+	}
+
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, options)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	if _, has := containerApp.Get(pathConfigurationIngress); !has {
+		return fmt.Errorf("routing the traffic of container app '%s' requires ingress", appName)
+	}
+
+	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
+	if err != nil {
+		return fmt.Errorf("syncing secrets: %w", err)
+	}
+
+	if err := setMultipleRevisionsMode(containerApp); err != nil {
+		return err
+	}
+
+	return cas.setTrafficWeights(ctx, subscriptionId, resourceGroupName, appName, containerApp, weights, options)
+}
+
+// Waits until the revision is provisioned and healthy, returns ErrRevisionUnhealthy when the revision failed
+// to provision, its health probes fail or it did not become healthy within the timeout
+func (cas *containerAppService) WaitForRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revisionName string,
+	timeout time.Duration,
+	options *ContainerAppOptions,
+) (*ContainerAppRevision, error) {
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId, createApiVersionPolicy(options))
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := cas.clock.Now().Add(timeout)
+	for {
+		response, err := revisionsClient.GetRevision(ctx, resourceGroupName, appName, revisionName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting revision '%s': %w", revisionName, err)
+		}
+
+		revision := newContainerAppRevision(&response.Revision)
+		if revision.failed() {
+			return revision, fmt.Errorf(
+				"%w: revision '%s' is %s with health state '%s' and running state '%s'",
+				ErrRevisionUnhealthy,
+				revisionName,
+				revision.ProvisioningState,
+				revision.HealthState,
+				revision.RunningState,
+			)
+		}
+
+		if revision.healthy() {
+			return revision, nil
+		}
+
+		if !cas.clock.Now().Before(deadline) {
+			return revision, fmt.Errorf(
+				"%w: revision '%s' did not become healthy within %s, health state '%s'",
+				ErrRevisionUnhealthy,
+				revisionName,
+				timeout,
+				revision.HealthState,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-cas.clock.After(revisionPollInterval):
+		}
+	}
+}
+
+// Deactivates the revision of the specified container app
+func (cas *containerAppService) DeactivateRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revisionName string,
+	options *ContainerAppOptions,
+) error {
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId, createApiVersionPolicy(options))
+	if err != nil {
+		return err
+	}
+
+	if _, err := revisionsClient.DeactivateRevision(ctx, resourceGroupName, appName, revisionName, nil); err != nil {
+		return fmt.Errorf("deactivating revision '%s': %w", revisionName, err)
+	}
+
+	return nil
+}
+
+// setMultipleRevisionsMode switches the container app to the multiple revisions mode, where the previous revisions
+// remain active when a new revision is added
+func setMultipleRevisionsMode(containerApp config.Config) error {
+	err := containerApp.Set(pathConfigurationActiveRevisionsMode, string(armappcontainers.ActiveRevisionsModeMultiple))
+	if err != nil {
+		return fmt.Errorf("setting active revisions mode: %w", err)
+	}
+
+	return nil
+}
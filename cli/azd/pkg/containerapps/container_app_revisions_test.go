@@ -0,0 +1,270 @@
+package containerapps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerApp_AddRevision_TrafficWeight(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	revisionName := "APP_NAME--stable"
+
+	containerApp := &armappcontainers.ContainerApp{
+		Name: &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			LatestRevisionName: &revisionName,
+			Configuration: &armappcontainers.Configuration{
+				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeSingle),
+				Ingress: &armappcontainers.Ingress{
+					Traffic: []*armappcontainers.TrafficWeight{
+						{LatestRevision: to.Ptr(true), Weight: to.Ptr[int32](100)},
+					},
+				},
+			},
+		},
+	}
+
+	revision := &armappcontainers.Revision{
+		Properties: &armappcontainers.RevisionProperties{
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{Image: to.Ptr("contoso.azurecr.io/api:stable")},
+				},
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	_ = mockazsdk.MockContainerAppRevisionGet(mockContext, subscriptionId, resourceGroup, appName, revisionName, revision)
+	updateRequest := mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	newRevisionName, err := cas.AddRevision(
+		*mockContext.Context,
+		subscriptionId,
+		resourceGroup,
+		appName,
+		"contoso.azurecr.io/api:canary",
+		&ContainerAppOptions{TrafficWeight: to.Ptr(10)},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "APP_NAME--azd-0", newRevisionName)
+
+	// The last update routes the traffic to the new revision and the previous latest revision
+	var updatedContainerApp *armappcontainers.ContainerApp
+	require.NoError(t, json.NewDecoder(updateRequest.Body).Decode(&updatedContainerApp))
+
+	configuration := updatedContainerApp.Properties.Configuration
+	require.Equal(t, armappcontainers.ActiveRevisionsModeMultiple, *configuration.ActiveRevisionsMode)
+	require.Len(t, configuration.Ingress.Traffic, 2)
+	require.Equal(t, "APP_NAME--azd-0", *configuration.Ingress.Traffic[0].RevisionName)
+	require.Equal(t, int32(10), *configuration.Ingress.Traffic[0].Weight)
+	require.Equal(t, revisionName, *configuration.Ingress.Traffic[1].RevisionName)
+	require.Equal(t, int32(90), *configuration.Ingress.Traffic[1].Weight)
+}
+
+func Test_SplitTrafficWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		traffic  []any
+		weight   int
+		expected map[string]int
+	}{
+		{
+			name:     "LatestRevision",
+			traffic:  []any{map[string]any{"latestRevision": true, "weight": float64(100)}},
+			weight:   20,
+			expected: map[string]int{"app--new": 20, "app--latest": 80},
+		},
+		{
+			// The stable revision of an ongoing canary deployment keeps the rest of the traffic
+			name: "StableRevision",
+			traffic: []any{
+				map[string]any{"revisionName": "app--stable", "weight": float64(70)},
+				map[string]any{"revisionName": "app--canary", "weight": float64(30)},
+			},
+			weight:   10,
+			expected: map[string]int{"app--new": 10, "app--stable": 90},
+		},
+		{
+			name:     "NoTraffic",
+			weight:   0,
+			expected: map[string]int{"app--new": 0, "app--latest": 100},
+		},
+		{
+			name:     "Promote",
+			traffic:  []any{map[string]any{"revisionName": "app--stable", "weight": float64(100)}},
+			weight:   100,
+			expected: map[string]int{"app--new": 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, splitTrafficWeights(tt.traffic, "app--latest", "app--new", tt.weight))
+		})
+	}
+}
+
+func Test_ContainerApp_WaitForRevision(t *testing.T) {
+	tests := []struct {
+		name              string
+		provisioningState armappcontainers.RevisionProvisioningState
+		healthState       armappcontainers.RevisionHealthState
+		runningState      armappcontainers.RevisionRunningState
+		expectedErr       string
+	}{
+		{
+			name:              "Healthy",
+			provisioningState: armappcontainers.RevisionProvisioningStateProvisioned,
+			healthState:       armappcontainers.RevisionHealthStateHealthy,
+			runningState:      armappcontainers.RevisionRunningStateRunning,
+		},
+		{
+			name:              "Unhealthy",
+			provisioningState: armappcontainers.RevisionProvisioningStateProvisioned,
+			healthState:       armappcontainers.RevisionHealthStateUnhealthy,
+			runningState:      armappcontainers.RevisionRunningStateDegraded,
+			expectedErr:       "revision 'APP_NAME--azd-1' is Provisioned with health state 'Unhealthy'",
+		},
+		{
+			name:              "Failed",
+			provisioningState: armappcontainers.RevisionProvisioningStateFailed,
+			healthState:       armappcontainers.RevisionHealthStateNone,
+			runningState:      armappcontainers.RevisionRunningStateFailed,
+			expectedErr:       "revision 'APP_NAME--azd-1' is Failed",
+		},
+		{
+			// The timeout elapses before the revision is provisioned
+			name:              "Timeout",
+			provisioningState: armappcontainers.RevisionProvisioningStateProvisioning,
+			healthState:       armappcontainers.RevisionHealthStateNone,
+			runningState:      armappcontainers.RevisionRunningStateProcessing,
+			expectedErr:       "did not become healthy within 0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			_ = mockazsdk.MockContainerAppRevisionGet(
+				mockContext,
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"APP_NAME",
+				"APP_NAME--azd-1",
+				&armappcontainers.Revision{
+					Name: to.Ptr("APP_NAME--azd-1"),
+					Properties: &armappcontainers.RevisionProperties{
+						ProvisioningState: to.Ptr(tt.provisioningState),
+						HealthState:       to.Ptr(tt.healthState),
+						RunningState:      to.Ptr(tt.runningState),
+					},
+				},
+			)
+
+			cas := NewContainerAppService(
+				mockContext.SubscriptionCredentialProvider,
+				clock.NewMock(),
+				mockContext.ArmClientOptions,
+				mockContext.AlphaFeaturesManager,
+			)
+			revision, err := cas.WaitForRevision(
+				*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "APP_NAME", "APP_NAME--azd-1", 0, nil)
+			if tt.expectedErr != "" {
+				require.ErrorIs(t, err, ErrRevisionUnhealthy)
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "Healthy", revision.HealthState)
+		})
+	}
+}
+
+func Test_ContainerApp_ListRevisions(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	_ = mockazsdk.MockContainerAppRevisionsList(
+		mockContext,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"APP_NAME",
+		[]*armappcontainers.Revision{
+			{
+				Name: to.Ptr("APP_NAME--stable"),
+				Properties: &armappcontainers.RevisionProperties{
+					Active:        to.Ptr(true),
+					TrafficWeight: to.Ptr[int32](90),
+					CreatedTime:   to.Ptr(created),
+				},
+			},
+			{
+				Name: to.Ptr("APP_NAME--canary"),
+				Properties: &armappcontainers.RevisionProperties{
+					Active:        to.Ptr(true),
+					TrafficWeight: to.Ptr[int32](10),
+					CreatedTime:   to.Ptr(created.Add(time.Hour)),
+					Template: &armappcontainers.Template{
+						Containers: []*armappcontainers.Container{{Image: to.Ptr("contoso.azurecr.io/api:canary")}},
+					},
+				},
+			},
+		},
+	)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	revisions, err := cas.ListRevisions(*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "APP_NAME", nil)
+	require.NoError(t, err)
+
+	// The most recently created revision is listed first
+	require.Len(t, revisions, 2)
+	require.Equal(t, "APP_NAME--canary", revisions[0].Name)
+	require.Equal(t, 10, revisions[0].TrafficWeight)
+	require.Equal(t, "contoso.azurecr.io/api:canary", revisions[0].Image)
+	require.Equal(t, "APP_NAME--stable", revisions[1].Name)
+	require.Equal(t, 90, revisions[1].TrafficWeight)
+}
+
+func Test_ContainerApp_SetTraffic_Total(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+
+	err := cas.SetTraffic(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"APP_NAME",
+		map[string]int{"APP_NAME--stable": 50, "APP_NAME--canary": 40},
+		nil,
+	)
+	require.ErrorContains(t, err, "add up to 90%")
+}
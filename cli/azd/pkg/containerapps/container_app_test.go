@@ -138,7 +138,7 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 		mockContext.ArmClientOptions,
 		mockContext.AlphaFeaturesManager,
 	)
-	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil)
+	_, err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil)
 	require.NoError(t, err)
 
 	// Verify lastest revision is read
@@ -161,6 +161,217 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	require.Equal(t, "azd-0", *updatedContainerApp.Properties.Template.RevisionSuffix)
 }
 
+func Test_ContainerApp_AddRevision_Registry(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	location := "eastus2"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	revisionName := "ORIGINAL_REVISION_NAME"
+	imageName := "ghcr.io/contoso/api:azd-deploy-0"
+
+	containerApp := &armappcontainers.ContainerApp{
+		Location: &location,
+		Name:     &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			LatestRevisionName: &revisionName,
+			Configuration: &armappcontainers.Configuration{
+				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeSingle),
+				Registries: []*armappcontainers.RegistryCredentials{
+					{
+						Server:   to.Ptr("contoso.azurecr.io"),
+						Identity: to.Ptr("system"),
+					},
+					{
+						Server:            to.Ptr("ghcr.io"),
+						Username:          to.Ptr("old-user"),
+						PasswordSecretRef: to.Ptr("registry-ghcr-io"),
+					},
+				},
+				Secrets: []*armappcontainers.Secret{
+					{
+						Name: to.Ptr("registry-ghcr-io"),
+					},
+				},
+			},
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Image: to.Ptr("ghcr.io/contoso/api:original"),
+					},
+				},
+			},
+		},
+	}
+
+	revision := &armappcontainers.Revision{
+		Properties: &armappcontainers.RevisionProperties{
+			Template: containerApp.Properties.Template,
+		},
+	}
+
+	secrets := &armappcontainers.SecretsCollection{
+		Value: []*armappcontainers.ContainerAppSecret{
+			{
+				Name:  to.Ptr("registry-ghcr-io"),
+				Value: to.Ptr("OLD_TOKEN"),
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	_ = mockazsdk.MockContainerAppRevisionGet(
+		mockContext,
+		subscriptionId,
+		resourceGroup,
+		appName,
+		revisionName,
+		revision,
+	)
+	_ = mockazsdk.MockContainerAppSecretsList(mockContext, subscriptionId, resourceGroup, appName, secrets)
+	updateContainerAppRequest := mockazsdk.MockContainerAppUpdate(
+		mockContext,
+		subscriptionId,
+		resourceGroup,
+		appName,
+		containerApp,
+	)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	options := &ContainerAppOptions{
+		Registry: &ContainerAppRegistry{
+			Server:   "ghcr.io",
+			Username: "octocat",
+			Password: "GHCR_TOKEN",
+		},
+	}
+	_, err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, imageName, options)
+	require.NoError(t, err)
+
+	var updatedContainerApp *armappcontainers.ContainerApp
+	jsonDecoder := json.NewDecoder(updateContainerAppRequest.Body)
+	err = jsonDecoder.Decode(&updatedContainerApp)
+	require.NoError(t, err)
+
+	// The existing registry credentials are replaced, other registries are preserved
+	registries := updatedContainerApp.Properties.Configuration.Registries
+	require.Len(t, registries, 2)
+	require.Equal(t, "contoso.azurecr.io", *registries[0].Server)
+	require.Equal(t, "ghcr.io", *registries[1].Server)
+	require.Equal(t, "octocat", *registries[1].Username)
+	require.Equal(t, "registry-ghcr-io", *registries[1].PasswordSecretRef)
+
+	secretValues := updatedContainerApp.Properties.Configuration.Secrets
+	require.Len(t, secretValues, 1)
+	require.Equal(t, "registry-ghcr-io", *secretValues[0].Name)
+	require.Equal(t, "GHCR_TOKEN", *secretValues[0].Value)
+}
+
+func Test_RegistrySecretName(t *testing.T) {
+	require.Equal(t, "registry-ghcr-io", registrySecretName("ghcr.io"))
+	require.Equal(t, "registry-contoso-jfrog-io-5000", registrySecretName("Contoso.JFrog.io:5000"))
+}
+
+func Test_ContainerApp_AddRevision_EnvAndTargetPort(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	location := "eastus2"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	revisionName := "ORIGINAL_REVISION_NAME"
+
+	containerApp := &armappcontainers.ContainerApp{
+		Location: &location,
+		Name:     &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			LatestRevisionName: &revisionName,
+			Configuration: &armappcontainers.Configuration{
+				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeSingle),
+				Ingress: &armappcontainers.Ingress{
+					External:   to.Ptr(true),
+					TargetPort: to.Ptr[int32](8080),
+				},
+			},
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Image: to.Ptr("contoso.azurecr.io/api:original"),
+						Env: []*armappcontainers.EnvironmentVar{
+							{Name: to.Ptr("LOG_LEVEL"), Value: to.Ptr("debug")},
+							{Name: to.Ptr("REDIS_HOST"), SecretRef: to.Ptr("redis-host")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	revision := &armappcontainers.Revision{
+		Properties: &armappcontainers.RevisionProperties{
+			Template: containerApp.Properties.Template,
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	_ = mockazsdk.MockContainerAppRevisionGet(
+		mockContext,
+		subscriptionId,
+		resourceGroup,
+		appName,
+		revisionName,
+		revision,
+	)
+	_ = mockazsdk.MockContainerAppSecretsList(
+		mockContext, subscriptionId, resourceGroup, appName, &armappcontainers.SecretsCollection{})
+	updateContainerAppRequest := mockazsdk.MockContainerAppUpdate(
+		mockContext,
+		subscriptionId,
+		resourceGroup,
+		appName,
+		containerApp,
+	)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	options := &ContainerAppOptions{
+		Env: map[string]string{
+			"REDIS_HOST": "cache",
+			"PORT":       "80",
+		},
+		TargetPort: 80,
+	}
+	_, err := cas.AddRevision(
+		*mockContext.Context, subscriptionId, resourceGroup, appName, "contoso.azurecr.io/api:azd-deploy-0", options)
+	require.NoError(t, err)
+
+	var updatedContainerApp *armappcontainers.ContainerApp
+	jsonDecoder := json.NewDecoder(updateContainerAppRequest.Body)
+	err = jsonDecoder.Decode(&updatedContainerApp)
+	require.NoError(t, err)
+
+	require.Equal(t, int32(80), *updatedContainerApp.Properties.Configuration.Ingress.TargetPort)
+
+	// Variables with the same name are replaced, other variables are preserved
+	env := updatedContainerApp.Properties.Template.Containers[0].Env
+	require.Len(t, env, 3)
+	require.Equal(t, "LOG_LEVEL", *env[0].Name)
+	require.Equal(t, "debug", *env[0].Value)
+	require.Equal(t, "PORT", *env[1].Name)
+	require.Equal(t, "80", *env[1].Value)
+	require.Equal(t, "REDIS_HOST", *env[2].Name)
+	require.Equal(t, "cache", *env[2].Value)
+	require.Nil(t, env[2].SecretRef)
+}
+
 func Test_ContainerApp_DeployYaml(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 
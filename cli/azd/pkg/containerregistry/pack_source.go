@@ -31,24 +31,9 @@ import (
 //
 // Any folders named `.git` is excluded from the produced archive.
 func PackRemoteBuildSource(ctx context.Context, root string, dockerfile string) (string, string, error) {
-	var ignores []string
-
-	// Like docker, we allow the use of a .dockerignore file to control what is included in the build context.
-	candidates := []string{dockerfile + ".dockerignore", filepath.Join(root, ".dockerignore")}
-
-	for _, candidate := range candidates {
-		f, err := os.Open(candidate)
-		if err == nil {
-			defer f.Close()
-			i, err := ignorefile.ReadAll(f)
-			if err != nil {
-				return "", "", err
-			}
-			ignores = i
-			break
-		} else if !errors.Is(err, os.ErrNotExist) {
-			return "", "", err
-		}
+	ignores, err := ReadBuildContextIgnores(root, dockerfile)
+	if err != nil {
+		return "", "", err
 	}
 
 	contextArchive, err := os.CreateTemp("", "azd-docker-context*.tar.gz")
@@ -164,3 +149,23 @@ func PackRemoteBuildSource(ctx context.Context, root string, dockerfile string)
 
 	return contextArchive.Name(), dockerfileArchivePath, err
 }
+
+// ReadBuildContextIgnores returns the patterns of the files excluded from the build context, read from the
+// `<dockerfile>.dockerignore` file or else the `.dockerignore` file in the root of the context. Returns no patterns when
+// neither exists.
+func ReadBuildContextIgnores(root string, dockerfile string) ([]string, error) {
+	// Like docker, we allow the use of a .dockerignore file to control what is included in the build context.
+	candidates := []string{dockerfile + ".dockerignore", filepath.Join(root, ".dockerignore")}
+
+	for _, candidate := range candidates {
+		f, err := os.Open(candidate)
+		if err == nil {
+			defer f.Close()
+			return ignorefile.ReadAll(f)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
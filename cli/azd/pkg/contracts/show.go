@@ -2,6 +2,8 @@
 // Licensed under the MIT License.
 package contracts
 
+import "time"
+
 // ShowType are the values for the language property of a ShowServiceProject
 type ShowType string
 
@@ -23,10 +25,16 @@ type ShowResult struct {
 type ShowService struct {
 	// Project contains information about the project that backs this service.
 	Project ShowServiceProject `json:"project"`
+	// Host is the kind of Azure resource hosting the service, such as containerapp, appservice or aks.
+	Host string `json:"host,omitempty"`
 	// Target contains information about the resource that the service is deployed
 	// to.
-	Target    *ShowTargetArm `json:"target,omitempty"`
-	IngresUrl string         `json:"-"`
+	Target *ShowTargetArm `json:"target,omitempty"`
+	// Endpoints contains the endpoints of the deployed service.
+	Endpoints []ShowEndpoint `json:"endpoints,omitempty"`
+	// Deployment contains information about the last deployment of the service and its live status.
+	Deployment *ShowDeployment `json:"deployment,omitempty"`
+	IngresUrl  string          `json:"-"`
 }
 
 // ShowServiceProject is the contract for a service's project as returned by `azd show`
@@ -42,4 +50,41 @@ type ShowServiceProject struct {
 // is deployed to.
 type ShowTargetArm struct {
 	ResourceIds []string `json:"resourceIds"`
+	// ResourceId is the id of the resource the service is deployed to.
+	ResourceId string `json:"resourceId,omitempty"`
+}
+
+// ShowEndpoint is the contract for an endpoint of a service returned by `azd show`
+type ShowEndpoint struct {
+	Url string `json:"url"`
+	// The kind of resource exposing the endpoint, such as host, slot, service, ingress or route.
+	Kind     string `json:"kind"`
+	Protocol string `json:"protocol"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Path     string `json:"path,omitempty"`
+	// Whether the endpoint is only reachable from a private network.
+	Internal bool `json:"internal"`
+}
+
+// ShowDeployment is the contract for the last deployment of a service returned by `azd show`
+type ShowDeployment struct {
+	// The container image last deployed from the environment.
+	Image       string `json:"image,omitempty"`
+	ImageDigest string `json:"imageDigest,omitempty"`
+	// The time of the last successful deployment from the environment.
+	DeployedAt *time.Time `json:"deployedAt,omitempty"`
+	// The live status of the service as reported by the resource hosting it, when supported.
+	Status *ShowServiceStatus `json:"status,omitempty"`
+}
+
+// ShowServiceStatus is the contract for the live status of a service returned by `azd show`
+type ShowServiceStatus struct {
+	// The revision serving the service, such as a container app revision or a k8s deployment revision.
+	Revision string `json:"revision,omitempty"`
+	// The status of the rollout of the revision, such as Running, Progressing or Available.
+	Status  string `json:"status"`
+	Details string `json:"details,omitempty"`
+	// The image running, when reported by the resource hosting the service.
+	Image string `json:"image,omitempty"`
 }
@@ -313,7 +313,8 @@ func newEnvironmentStoreForTest(
 
 	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
-	dataStore := environment.NewLocalFileDataStore(azdContext, fileConfigManager)
+	dataStore := environment.NewLocalFileDataStore(
+		azdContext, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 
 	return NewEnvironmentStore(devCenterConfig, devCenterClient, prompter, manager, dataStore)
 }
@@ -0,0 +1,169 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+// DotEnvEncryptionConfigPath is the user config setting that enables the encryption of the .env files of environments
+// at rest, ex) azd config set env.encryption true
+const DotEnvEncryptionConfigPath = "env.encryption"
+
+// The first line of an encrypted .env file. The rest of the file is the base64 encoded nonce and ciphertext.
+const encryptedDotEnvHeader = "# azd-encrypted: aes-256-gcm"
+
+// The size in bytes of the AES-256 key .env files are encrypted with
+const dotEnvKeySize = 32
+
+// The service and account of the key .env files are encrypted with in the credential store of the platform
+const (
+	dotEnvKeyService = "azd"
+	dotEnvKeyAccount = "env-encryption-key"
+)
+
+// ErrDotEnvKeyStoreUnavailable is returned when saving an environment with encryption enabled on a machine without a
+// credential store to protect the key with. The key is never stored unprotected.
+var ErrDotEnvKeyStoreUnavailable = errors.New(
+	"no credential store is available to protect the .env encryption key, disable the encryption with " +
+		"'azd config unset " + DotEnvEncryptionConfigPath + "'")
+
+// dotEnvEncryptionEnabled returns whether the .env files of environments are encrypted when saved
+func dotEnvEncryptionEnabled(userConfigManager config.UserConfigManager) (bool, error) {
+	if userConfigManager == nil {
+		return false, nil
+	}
+
+	userConfig, err := userConfigManager.Load()
+	if err != nil {
+		return false, err
+	}
+
+	value, ok := userConfig.GetString(DotEnvEncryptionConfigPath)
+	if !ok || value == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value '%s' of user config '%s', expected true or false", value,
+			DotEnvEncryptionConfigPath)
+	}
+
+	return enabled, nil
+}
+
+// isEncryptedDotEnv returns whether the contents of a .env file were written by encryptDotEnv
+func isEncryptedDotEnv(contents []byte) bool {
+	return bytes.HasPrefix(contents, []byte(encryptedDotEnvHeader))
+}
+
+// encryptDotEnv encrypts the contents of a .env file with the key of the current user, creating the key the first time
+func encryptDotEnv(keyStore dotEnvKeyStore, plaintext []byte) ([]byte, error) {
+	key, err := loadDotEnvKey(keyStore, true)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newDotEnvCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return []byte(encryptedDotEnvHeader + "\n" + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// decryptDotEnv decrypts the contents of a .env file written by encryptDotEnv
+func decryptDotEnv(keyStore dotEnvKeyStore, contents []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(
+		string(bytes.TrimSpace(bytes.TrimPrefix(contents, []byte(encryptedDotEnvHeader)))))
+	if err != nil {
+		return nil, fmt.Errorf("decoding encrypted .env: %w", err)
+	}
+
+	key, err := loadDotEnvKey(keyStore, false)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New(
+			"the key the .env file is encrypted with does not exist, encrypted environments can only be read by " +
+				"the user that saved them on the same machine")
+	} else if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newDotEnvCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("decrypting .env: the encrypted contents are truncated")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting .env, the file was modified or encrypted with another key: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func newDotEnvCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// dotEnvKeyStore stores the key .env files are encrypted with, protected by the credential store of the platform:
+// DPAPI on Windows, the Keychain on macOS and the Secret Service on Linux
+type dotEnvKeyStore interface {
+	// Load returns the stored key, an error wrapping os.ErrNotExist when no key is stored
+	Load() ([]byte, error)
+	// Save stores the key
+	Save(key []byte) error
+}
+
+// loadDotEnvKey loads the key .env files are encrypted with from the key store, generating and storing a new key when
+// create is set and no key is stored yet
+func loadDotEnvKey(keyStore dotEnvKeyStore, create bool) ([]byte, error) {
+	key, err := keyStore.Load()
+	if errors.Is(err, os.ErrNotExist) && create {
+		key := make([]byte, dotEnvKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generating .env encryption key: %w", err)
+		}
+
+		if err := keyStore.Save(key); err != nil {
+			return nil, fmt.Errorf("saving .env encryption key: %w", err)
+		}
+
+		return key, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("loading .env encryption key: %w", err)
+	}
+
+	if len(key) != dotEnvKeySize {
+		return nil, errors.New("invalid .env encryption key")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build darwin
+// +build darwin
+
+package environment
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"strings"
+)
+
+// The exit code of `security find-generic-password` when the Keychain has no matching item
+const keychainItemNotFoundExitCode = 44
+
+// keychainKeyStore stores the key in the login Keychain of the current user with the `security` command
+type keychainKeyStore struct{}
+
+func newDotEnvKeyStore() dotEnvKeyStore {
+	return &keychainKeyStore{}
+}
+
+func (s *keychainKeyStore) Load() ([]byte, error) {
+	out, err := osexec.Command(
+		"security", "find-generic-password", "-s", dotEnvKeyService, "-a", dotEnvKeyAccount, "-w").Output()
+
+	var exitErr *osexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == keychainItemNotFoundExitCode {
+		return nil, fmt.Errorf("the Keychain has no .env encryption key: %w", os.ErrNotExist)
+	} else if err != nil {
+		return nil, keychainError(err)
+	}
+
+	return hex.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (s *keychainKeyStore) Save(key []byte) error {
+	// The key is passed through stdin rather than as an argument, so it isn't visible in the list of processes
+	cmd := osexec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf(
+		"add-generic-password -U -s %s -a %s -w %s\n", dotEnvKeyService, dotEnvKeyAccount, hex.EncodeToString(key)))
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return keychainError(fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out))))
+	}
+
+	return nil
+}
+
+func keychainError(err error) error {
+	if errors.Is(err, osexec.ErrNotFound) {
+		return fmt.Errorf("%w: the 'security' command of macOS is not available", ErrDotEnvKeyStoreUnavailable)
+	}
+
+	return fmt.Errorf("accessing the Keychain: %w", err)
+}
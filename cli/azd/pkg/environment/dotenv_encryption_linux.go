@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build linux
+// +build linux
+
+package environment
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"strings"
+)
+
+// The label of the item holding the key in the Secret Service, ex) as shown by GNOME Keyring
+const secretServiceLabel = "azd environment encryption key"
+
+// secretServiceKeyStore stores the key in the Secret Service of the current user, ex) GNOME Keyring or KWallet, with
+// the `secret-tool` command of libsecret
+type secretServiceKeyStore struct{}
+
+func newDotEnvKeyStore() dotEnvKeyStore {
+	return &secretServiceKeyStore{}
+}
+
+func (s *secretServiceKeyStore) Load() ([]byte, error) {
+	out, err := osexec.Command(
+		"secret-tool", "lookup", "service", dotEnvKeyService, "account", dotEnvKeyAccount).Output()
+
+	// secret-tool exits with an error without any output when no item matches
+	var exitErr *osexec.ExitError
+	if errors.As(err, &exitErr) && len(out) == 0 && len(exitErr.Stderr) == 0 {
+		return nil, fmt.Errorf("the Secret Service has no .env encryption key: %w", os.ErrNotExist)
+	} else if errors.As(err, &exitErr) {
+		return nil, secretServiceError(fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr))))
+	} else if err != nil {
+		return nil, secretServiceError(err)
+	}
+
+	return hex.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (s *secretServiceKeyStore) Save(key []byte) error {
+	// The key is passed through stdin rather than as an argument, so it isn't visible in the list of processes
+	cmd := osexec.Command(
+		"secret-tool", "store", "--label", secretServiceLabel, "service", dotEnvKeyService, "account", dotEnvKeyAccount)
+	cmd.Stdin = strings.NewReader(hex.EncodeToString(key))
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return secretServiceError(fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out))))
+	}
+
+	return nil
+}
+
+func secretServiceError(err error) error {
+	if errors.Is(err, osexec.ErrNotFound) {
+		return fmt.Errorf(
+			"%w: install the 'secret-tool' command of libsecret and a Secret Service provider, ex) GNOME Keyring",
+			ErrDotEnvKeyStoreUnavailable,
+		)
+	}
+
+	return fmt.Errorf(
+		"%w: accessing the Secret Service, ensure a Secret Service provider is running and unlocked: %w",
+		ErrDotEnvKeyStoreUnavailable,
+		err,
+	)
+}
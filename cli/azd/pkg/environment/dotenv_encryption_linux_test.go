@@ -0,0 +1,25 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build linux
+// +build linux
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SecretServiceKeyStore_Unavailable(t *testing.T) {
+	// secret-tool cannot be found in an empty PATH
+	t.Setenv("PATH", t.TempDir())
+
+	keyStore := newDotEnvKeyStore()
+	require.ErrorIs(t, keyStore.Save(make([]byte, dotEnvKeySize)), ErrDotEnvKeyStoreUnavailable)
+
+	_, err := keyStore.Load()
+	require.ErrorIs(t, err, ErrDotEnvKeyStoreUnavailable)
+	require.ErrorContains(t, err, "secret-tool")
+}
@@ -0,0 +1,28 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build unix && !darwin && !linux
+// +build unix,!darwin,!linux
+
+package environment
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// unavailableKeyStore is the key store of the platforms without a supported credential store. The .env files are not
+// encrypted, rather than storing the key unprotected.
+type unavailableKeyStore struct{}
+
+func newDotEnvKeyStore() dotEnvKeyStore {
+	return &unavailableKeyStore{}
+}
+
+func (s *unavailableKeyStore) Load() ([]byte, error) {
+	return nil, fmt.Errorf("%w on %s", ErrDotEnvKeyStoreUnavailable, runtime.GOOS)
+}
+
+func (s *unavailableKeyStore) Save(key []byte) error {
+	return fmt.Errorf("%w on %s", ErrDotEnvKeyStoreUnavailable, runtime.GOOS)
+}
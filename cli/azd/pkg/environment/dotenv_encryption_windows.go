@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build windows
+// +build windows
+
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"golang.org/x/sys/windows"
+)
+
+// The name of the file in the user config directory holding the DPAPI encrypted key .env files are encrypted with
+const dotEnvKeyFileName = "env.key"
+
+// dpapiKeyStore stores the key in the user config directory, encrypted with DPAPI so only the current user can decrypt it
+type dpapiKeyStore struct{}
+
+func newDotEnvKeyStore() dotEnvKeyStore {
+	return &dpapiKeyStore{}
+}
+
+func (s *dpapiKeyStore) Load() ([]byte, error) {
+	keyPath, err := dotEnvKeyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	protected, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return unprotectDotEnvKey(protected)
+}
+
+func (s *dpapiKeyStore) Save(key []byte) error {
+	keyPath, err := dotEnvKeyPath()
+	if err != nil {
+		return err
+	}
+
+	protected, err := protectDotEnvKey(key)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(keyPath, protected, osutil.PermissionFileOwnerOnly)
+}
+
+func dotEnvKeyPath() (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, dotEnvKeyFileName), nil
+}
+
+// protectDotEnvKey encrypts the key with CryptProtectData, so only the current user can decrypt it.
+// See https://learn.microsoft.com/windows/win32/api/dpapi/nf-dpapi-cryptprotectdata
+func protectDotEnvKey(key []byte) ([]byte, error) {
+	plaintext := windows.DataBlob{
+		Size: uint32(len(key)),
+		Data: &key[0],
+	}
+	var encrypted windows.DataBlob
+
+	if err := windows.CryptProtectData(&plaintext, nil, nil, uintptr(0), nil, 0, &encrypted); err != nil {
+		return nil, fmt.Errorf("failed to encrypt .env encryption key: %w", err)
+	}
+
+	return copyDataBlob(&encrypted)
+}
+
+// unprotectDotEnvKey decrypts a key encrypted by protectDotEnvKey with CryptUnprotectData.
+func unprotectDotEnvKey(protected []byte) ([]byte, error) {
+	if len(protected) == 0 {
+		return nil, fmt.Errorf("the .env encryption key is empty")
+	}
+
+	encrypted := windows.DataBlob{
+		Size: uint32(len(protected)),
+		Data: &protected[0],
+	}
+	var plaintext windows.DataBlob
+
+	if err := windows.CryptUnprotectData(&encrypted, nil, nil, uintptr(0), nil, 0, &plaintext); err != nil {
+		return nil, fmt.Errorf("failed to decrypt .env encryption key: %w", err)
+	}
+
+	return copyDataBlob(&plaintext)
+}
+
+// copyDataBlob copies the data of a blob allocated by the crypt APIs, and frees the blob.
+func copyDataBlob(blob *windows.DataBlob) ([]byte, error) {
+	cs := make([]byte, blob.Size)
+	copy(cs, unsafe.Slice(blob.Data, blob.Size))
+
+	if _, err := windows.LocalFree(windows.Handle(unsafe.Pointer(blob.Data))); err != nil {
+		return nil, fmt.Errorf("failed to free data: %w", err)
+	}
+
+	return cs, nil
+}
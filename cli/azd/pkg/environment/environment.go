@@ -11,6 +11,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"maps"
 
@@ -37,6 +38,18 @@ const TenantIdEnvVarName = "AZURE_TENANT_ID"
 // to.
 const ContainerRegistryEndpointEnvVarName = "AZURE_CONTAINER_REGISTRY_ENDPOINT"
 
+// ConnectedRegistryEndpointEnvVarName is the name of the key used to store the endpoint of the ACR connected registry to
+// push to, ex) of an Arc enabled AKS cluster on the edge.
+const ConnectedRegistryEndpointEnvVarName = "AZURE_CONNECTED_REGISTRY_ENDPOINT"
+
+// ConnectedRegistryTokenEnvVarName is the name of the key used to store the name of the client token of the connected
+// registry.
+const ConnectedRegistryTokenEnvVarName = "AZURE_CONNECTED_REGISTRY_TOKEN"
+
+// ConnectedRegistryTokenPasswordEnvVarName is the name of the key used to store the password of the client token of the
+// connected registry.
+const ConnectedRegistryTokenPasswordEnvVarName = "AZURE_CONNECTED_REGISTRY_TOKEN_PASSWORD"
+
 // AksClusterEnvVarName is the name of they key used to store the endpoint of the AKS cluster to push to.
 const AksClusterEnvVarName = "AZURE_AKS_CLUSTER_NAME"
 
@@ -60,6 +73,17 @@ type Environment struct {
 
 	// Config is environment specific config
 	Config config.Config
+
+	// remoteETags are the ETags of the files of the environment in the remote state, keyed by their path, as of when the
+	// environment was loaded from or saved to the remote state. Saves to the remote state are conditioned on them.
+	remoteETags map[string]string
+
+	// remoteDeletedKeys are the keys deleted from the `.env` in the remote state, so the local copies of the
+	// environment on other machines delete them too when they are updated from the remote state
+	remoteDeletedKeys map[string]struct{}
+
+	// dotenvMu guards the dotenv and deletedKeys, which are updated concurrently when services are deployed in parallel
+	dotenvMu sync.RWMutex
 }
 
 const AzdInitialEnvironmentConfigName = "AZD_INITIAL_ENVIRONMENT_CONFIG"
@@ -146,7 +170,11 @@ func CleanName(name string) string {
 // Getenv behaves like os.Getenv, except that any keys in the `.env` file associated with this environment are considered
 // first.
 func (e *Environment) Getenv(key string) string {
-	if v, has := e.dotenv[key]; has {
+	e.dotenvMu.RLock()
+	v, has := e.dotenv[key]
+	e.dotenvMu.RUnlock()
+
+	if has {
 		return v
 	}
 
@@ -156,7 +184,11 @@ func (e *Environment) Getenv(key string) string {
 // LookupEnv behaves like os.LookupEnv, except that any keys in the `.env` file associated with this environment are
 // considered first.
 func (e *Environment) LookupEnv(key string) (string, bool) {
-	if v, has := e.dotenv[key]; has {
+	e.dotenvMu.RLock()
+	v, has := e.dotenv[key]
+	e.dotenvMu.RUnlock()
+
+	if has {
 		return v, true
 	}
 
@@ -166,18 +198,27 @@ func (e *Environment) LookupEnv(key string) (string, bool) {
 // DotenvDelete removes the given key from the .env file in the environment, it is a no-op if the key
 // does not exist. [Save] should be called to ensure this change is persisted.
 func (e *Environment) DotenvDelete(key string) {
+	e.dotenvMu.Lock()
+	defer e.dotenvMu.Unlock()
+
 	delete(e.dotenv, key)
 	e.deletedKeys[key] = struct{}{}
 }
 
 // Dotenv returns a copy of the key value pairs from the .env file in the environment.
 func (e *Environment) Dotenv() map[string]string {
+	e.dotenvMu.RLock()
+	defer e.dotenvMu.RUnlock()
+
 	return maps.Clone(e.dotenv)
 }
 
 // DotenvSet sets the value of [key] to [value] in the .env file associated with the environment. [Save] should be
 // called to ensure this change is persisted.
 func (e *Environment) DotenvSet(key string, value string) {
+	e.dotenvMu.Lock()
+	defer e.dotenvMu.Unlock()
+
 	e.dotenv[key] = value
 	delete(e.deletedKeys, key)
 }
@@ -231,9 +272,17 @@ func (e *Environment) SetServiceProperty(serviceName string, propertyName string
 	e.DotenvSet(fmt.Sprintf("SERVICE_%s_%s", normalize(serviceName), propertyName), value)
 }
 
+// Removes a service-namespaced property from the environment.
+func (e *Environment) DeleteServiceProperty(serviceName string, propertyName string) {
+	e.DotenvDelete(fmt.Sprintf("SERVICE_%s_%s", normalize(serviceName), propertyName))
+}
+
 // Creates a slice of key value pairs, based on the entries in the `.env` file like `KEY=VALUE` that
 // can be used to pass into command runner or similar constructs.
 func (e *Environment) Environ() []string {
+	e.dotenvMu.RLock()
+	defer e.dotenvMu.RUnlock()
+
 	envVars := []string{}
 	for k, v := range e.dotenv {
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
@@ -281,6 +330,9 @@ func fixupUnquotedDotenv(values map[string]string, dotenv string) string {
 // Instead of calling `godotenv.Write` directly, we need to save the file ourselves, so we can fixup any numeric values
 // that were incorrectly unquoted.
 func marshallDotEnv(env *Environment) (string, error) {
+	env.dotenvMu.RLock()
+	defer env.dotenvMu.RUnlock()
+
 	marshalled, err := godotenv.Marshal(env.dotenv)
 	if err != nil {
 		return "", fmt.Errorf("marshalling .env: %w", err)
@@ -288,3 +340,43 @@ func marshallDotEnv(env *Environment) (string, error) {
 
 	return fixupUnquotedDotenv(env.dotenv, marshalled), nil
 }
+
+// setDotenv replaces the values of the .env file of the environment with the values loaded from a data store
+func (e *Environment) setDotenv(values map[string]string) {
+	e.dotenvMu.Lock()
+	defer e.dotenvMu.Unlock()
+
+	e.dotenv = values
+	e.deletedKeys = make(map[string]struct{})
+}
+
+// dotenvState returns a copy of the values and the deleted keys of the .env file of the environment
+func (e *Environment) dotenvState() (map[string]string, map[string]struct{}) {
+	e.dotenvMu.RLock()
+	defer e.dotenvMu.RUnlock()
+
+	return maps.Clone(e.dotenv), maps.Clone(e.deletedKeys)
+}
+
+// mergeDotenv adds the values of the .env file loaded from a data store that are neither set nor deleted in the
+// environment. The deleted keys are forgotten, the merged values no longer contain them.
+// The values are merged into the values of the environment rather than replacing them, so the values set while the
+// .env file is loaded aren't lost.
+func (e *Environment) mergeDotenv(values map[string]string) {
+	e.dotenvMu.Lock()
+	defer e.dotenvMu.Unlock()
+
+	for key, value := range values {
+		if _, has := e.dotenv[key]; has {
+			continue
+		}
+
+		if _, deleted := e.deletedKeys[key]; deleted {
+			continue
+		}
+
+		e.dotenv[key] = value
+	}
+
+	e.deletedKeys = make(map[string]struct{})
+}
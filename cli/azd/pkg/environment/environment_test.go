@@ -6,8 +6,10 @@ package environment
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
@@ -179,6 +181,28 @@ func Test_SaveAndReload(t *testing.T) {
 	require.Equal(t, "http://api.example.com/updated", value)
 }
 
+func Test_ConcurrentDotenv(t *testing.T) {
+	t.Parallel()
+
+	env := New("test")
+
+	wg := sync.WaitGroup{}
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			key := fmt.Sprintf("SERVICE_%d_IMAGE_NAME", i)
+			env.DotenvSet(key, "image")
+			require.Equal(t, "image", env.Getenv(key))
+			require.NotEmpty(t, env.Environ())
+		}()
+	}
+
+	wg.Wait()
+	require.Len(t, env.Dotenv(), 11)
+}
+
 func TestCleanName(t *testing.T) {
 	require.Equal(t, "already-clean-name", CleanName("already-clean-name"))
 	require.Equal(t, "was-CLEANED-with--bad--things-(123)", CleanName("was CLEANED with *bad* things (123)"))
@@ -269,7 +293,7 @@ func Test_fixupUnquotedDotenv(t *testing.T) {
 func createEnvManager(mockContext *mocks.MockContext, root string) (Manager, *azdcontext.AzdContext) {
 	azdCtx := azdcontext.NewAzdContextWithDirectory(root)
 	configManager := config.NewFileConfigManager(config.NewManager())
-	localDataStore := NewLocalFileDataStore(azdCtx, configManager)
+	localDataStore := NewLocalFileDataStore(azdCtx, configManager, config.NewUserConfigManager(configManager))
 
 	return newManagerForTest(azdCtx, mockContext.Console, localDataStore, nil), azdCtx
 }
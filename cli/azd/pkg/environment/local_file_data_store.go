@@ -2,8 +2,10 @@ package environment
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -14,21 +16,43 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 )
 
+// The file of a local environment recording the state of the environment in the remote state as of when it was last
+// synced, ex) to only update the local environment when the remote state changed
+const remoteStateFileName = "remote-state.json"
+
+// remoteState is the state of an environment in the remote state as of when the local environment was last synced with it
+type remoteState struct {
+	// The ETags of the files of the environment in the remote state, keyed by their path
+	ETags map[string]string `json:"etags,omitempty"`
+	// The keys deleted from the .env in the remote state
+	DeletedKeys []string `json:"deletedKeys,omitempty"`
+}
+
 // LocalFileDataStore is a DataStore implementation that stores environment data in the local file system.
 type LocalFileDataStore struct {
-	azdContext    *azdcontext.AzdContext
-	configManager config.FileConfigManager
+	azdContext        *azdcontext.AzdContext
+	configManager     config.FileConfigManager
+	userConfigManager config.UserConfigManager
+	keyStore          dotEnvKeyStore
 }
 
-// NewLocalFileDataStore creates a new LocalFileDataStore instance
-func NewLocalFileDataStore(azdContext *azdcontext.AzdContext, configManager config.FileConfigManager) LocalDataStore {
+// NewLocalFileDataStore creates a new LocalFileDataStore instance. The .env files are encrypted at rest when enabled
+// by the `env.encryption` user config setting.
+func NewLocalFileDataStore(
+	azdContext *azdcontext.AzdContext,
+	configManager config.FileConfigManager,
+	userConfigManager config.UserConfigManager,
+) LocalDataStore {
 	return &LocalFileDataStore{
-		azdContext:    azdContext,
-		configManager: configManager,
+		azdContext:        azdContext,
+		configManager:     configManager,
+		userConfigManager: userConfigManager,
+		keyStore:          newDotEnvKeyStore(),
 	}
 }
 
@@ -42,6 +66,11 @@ func (fs *LocalFileDataStore) ConfigPath(env *Environment) string {
 	return filepath.Join(fs.azdContext.EnvironmentRoot(env.name), ConfigFileName)
 }
 
+// remoteStatePath returns the path to the file recording the remote state of the given environment
+func (fs *LocalFileDataStore) remoteStatePath(env *Environment) string {
+	return filepath.Join(fs.azdContext.EnvironmentRoot(env.name), remoteStateFileName)
+}
+
 // List returns a list of all environments within the data store
 func (fs *LocalFileDataStore) List(ctx context.Context) ([]*contracts.EnvListEnvironment, error) {
 	defaultEnv, err := fs.azdContext.GetDefaultEnvironmentName()
@@ -100,14 +129,12 @@ func (fs *LocalFileDataStore) Get(ctx context.Context, name string) (*Environmen
 // Reload reloads the environment from the persistent data store
 func (fs *LocalFileDataStore) Reload(ctx context.Context, env *Environment) error {
 	// Reload env values
-	if envMap, err := godotenv.Read(fs.EnvPath(env)); errors.Is(err, os.ErrNotExist) {
-		env.dotenv = make(map[string]string)
-		env.deletedKeys = make(map[string]struct{})
+	if envMap, err := fs.readDotEnv(env); errors.Is(err, os.ErrNotExist) {
+		env.setDotenv(make(map[string]string))
 	} else if err != nil {
 		return fmt.Errorf("loading .env: %w", err)
 	} else {
-		env.dotenv = envMap
-		env.deletedKeys = make(map[string]struct{})
+		env.setDotenv(envMap)
 	}
 
 	// Reload env config
@@ -119,6 +146,11 @@ func (fs *LocalFileDataStore) Reload(ctx context.Context, env *Environment) erro
 		env.Config = cfg
 	}
 
+	// Reload the remote state the environment was last synced with
+	if err := fs.readRemoteState(env); err != nil {
+		return fmt.Errorf("loading remote state: %w", err)
+	}
+
 	if env.Name() != "" {
 		tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
 	}
@@ -139,21 +171,16 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment, option
 		return fmt.Errorf("saving config: %w", err)
 	}
 
-	// Cache current values & reload to get any new env vars
-	currentValues := env.dotenv
-	deletedValues := env.deletedKeys
-	if err := fs.Reload(ctx, env); err != nil {
+	// Merge any new env vars of the .env file, the current values and deletions take precedence
+	envMap, err := fs.readDotEnv(env)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("failed reloading env vars, %w", err)
 	}
 
-	// Overlay current values before saving
-	for key, value := range currentValues {
-		env.dotenv[key] = value
-	}
+	env.mergeDotenv(envMap)
 
-	// Replay deletion
-	for key := range deletedValues {
-		delete(env.dotenv, key)
+	if err := fs.writeRemoteState(env); err != nil {
+		return fmt.Errorf("saving remote state: %w", err)
 	}
 
 	marshalled, err := marshallDotEnv(env)
@@ -161,14 +188,29 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment, option
 		return fmt.Errorf("marshalling .env: %w", err)
 	}
 
+	// The contents have a trailing newline, as godotenv.Write would have written.
+	contents := []byte(marshalled + "\n")
+
+	// Saving without encryption enabled decrypts a previously encrypted .env file
+	encrypt, err := dotEnvEncryptionEnabled(fs.userConfigManager)
+	if err != nil {
+		return fmt.Errorf("saving .env: %w", err)
+	}
+
+	if encrypt {
+		if contents, err = encryptDotEnv(fs.keyStore, contents); err != nil {
+			return fmt.Errorf("encrypting .env: %w", err)
+		}
+	}
+
 	envFile, err := os.Create(fs.EnvPath(env))
 	if err != nil {
 		return fmt.Errorf("saving .env: %w", err)
 	}
 	defer envFile.Close()
 
-	// Write the contents (with a trailing newline), and sync the file, as godotenv.Write would have.
-	if _, err := envFile.WriteString(marshalled + "\n"); err != nil {
+	// Write the contents, and sync the file, as godotenv.Write would have.
+	if _, err := envFile.Write(contents); err != nil {
 		return fmt.Errorf("saving .env: %w", err)
 	}
 
@@ -180,6 +222,68 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment, option
 	return nil
 }
 
+// readDotEnv reads the values of the .env file of the environment, decrypting the file when encrypted
+func (fs *LocalFileDataStore) readDotEnv(env *Environment) (map[string]string, error) {
+	contents, err := os.ReadFile(fs.EnvPath(env))
+	if err != nil {
+		return nil, err
+	}
+
+	if isEncryptedDotEnv(contents) {
+		if contents, err = decryptDotEnv(fs.keyStore, contents); err != nil {
+			return nil, err
+		}
+	}
+
+	return godotenv.Unmarshal(string(contents))
+}
+
+// readRemoteState reads the remote state the environment was last synced with, none when never synced
+func (fs *LocalFileDataStore) readRemoteState(env *Environment) error {
+	env.remoteETags, env.remoteDeletedKeys = nil, nil
+
+	contents, err := os.ReadFile(fs.remoteStatePath(env))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var state remoteState
+	if err := json.Unmarshal(contents, &state); err != nil {
+		return err
+	}
+
+	env.remoteETags = state.ETags
+	if len(state.DeletedKeys) > 0 {
+		env.remoteDeletedKeys = map[string]struct{}{}
+		for _, key := range state.DeletedKeys {
+			env.remoteDeletedKeys[key] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// writeRemoteState records the remote state the environment was synced with, when synced with a remote state
+func (fs *LocalFileDataStore) writeRemoteState(env *Environment) error {
+	if len(env.remoteETags) == 0 {
+		return nil
+	}
+
+	state := remoteState{
+		ETags:       env.remoteETags,
+		DeletedKeys: slices.Sorted(maps.Keys(env.remoteDeletedKeys)),
+	}
+
+	contents, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(fs.remoteStatePath(env), contents, osutil.PermissionFile)
+}
+
 func (fs *LocalFileDataStore) Delete(ctx context.Context, name string) error {
 	envRoot := fs.azdContext.EnvironmentRoot(name)
 	_, err := os.Stat(envRoot)
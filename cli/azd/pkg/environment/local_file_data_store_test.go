@@ -2,6 +2,8 @@ package environment
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -15,7 +17,7 @@ func Test_LocalFileDataStore_List(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
-	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 
 	t.Run("List", func(t *testing.T) {
 		env1 := New("env1")
@@ -43,7 +45,7 @@ func Test_LocalFileDataStore_SaveAndGet(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
-	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 
 	t.Run("Success", func(t *testing.T) {
 		env1 := New("env1")
@@ -60,10 +62,180 @@ func Test_LocalFileDataStore_SaveAndGet(t *testing.T) {
 	})
 }
 
+func Test_LocalFileDataStore_SaveWhileSetting(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
+
+	env := New("env1")
+	env.DotenvSet("SAVED", "value")
+	require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+
+	// The values set by services deployed in parallel while the environment is saved are kept
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 500 {
+			env.DotenvSet(fmt.Sprintf("SERVICE_%d_ENDPOINT_URL", i), "http://localhost")
+		}
+	}()
+
+	saving := true
+	for saving {
+		select {
+		case <-done:
+			saving = false
+		default:
+		}
+
+		require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+	}
+
+	for i := range 500 {
+		require.Equal(t, "http://localhost", env.Getenv(fmt.Sprintf("SERVICE_%d_ENDPOINT_URL", i)))
+	}
+
+	require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+	saved, err := dataStore.Get(*mockContext.Context, "env1")
+	require.NoError(t, err)
+	require.Equal(t, env.Dotenv(), saved.Dotenv())
+}
+
+func Test_LocalFileDataStore_RemoteState(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
+
+	// Environments never synced with a remote state have no remote state
+	env := New("env1")
+	require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+	require.NoFileExists(t, filepath.Join(azdContext.EnvironmentRoot("env1"), remoteStateFileName))
+
+	// The remote state the environment was synced with is kept when the environment is saved and loaded
+	env.remoteETags = map[string]string{"env1/.env": "ETAG"}
+	env.remoteDeletedKeys = map[string]struct{}{"old": {}}
+	require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+	require.Equal(t, map[string]string{"env1/.env": "ETAG"}, env.remoteETags)
+
+	actual, err := dataStore.Get(*mockContext.Context, "env1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"env1/.env": "ETAG"}, actual.remoteETags)
+	require.Equal(t, map[string]struct{}{"old": {}}, actual.remoteDeletedKeys)
+}
+
+func Test_LocalFileDataStore_Encryption(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	userConfigManager := config.NewUserConfigManager(fileConfigManager)
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager, userConfigManager)
+	keyStore := &memoryKeyStore{}
+	dataStore.(*LocalFileDataStore).keyStore = keyStore
+
+	setEncryption := func(value string) {
+		userConfig, err := userConfigManager.Load()
+		require.NoError(t, err)
+		require.NoError(t, userConfig.Set(DotEnvEncryptionConfigPath, value))
+		require.NoError(t, userConfigManager.Save(userConfig))
+	}
+
+	env := New("env1")
+	env.DotenvSet("CONNECTION_STRING", "Server=contoso;Password=secret")
+
+	t.Run("Enabled", func(t *testing.T) {
+		setEncryption("true")
+		require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+
+		contents, err := os.ReadFile(dataStore.(*LocalFileDataStore).EnvPath(env))
+		require.NoError(t, err)
+		require.True(t, isEncryptedDotEnv(contents))
+		require.NotContains(t, string(contents), "secret")
+		require.Len(t, keyStore.key, dotEnvKeySize)
+
+		actual, err := dataStore.Get(*mockContext.Context, "env1")
+		require.NoError(t, err)
+		require.Equal(t, "Server=contoso;Password=secret", actual.Getenv("CONNECTION_STRING"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		// Saving with encryption disabled decrypts the previously encrypted file
+		setEncryption("false")
+		require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+
+		contents, err := os.ReadFile(dataStore.(*LocalFileDataStore).EnvPath(env))
+		require.NoError(t, err)
+		require.False(t, isEncryptedDotEnv(contents))
+		require.Contains(t, string(contents), "CONNECTION_STRING=\"Server=contoso;Password=secret\"\n")
+	})
+
+	t.Run("InvalidSetting", func(t *testing.T) {
+		setEncryption("sometimes")
+		err := dataStore.Save(*mockContext.Context, env, nil)
+		require.ErrorContains(t, err, "invalid value 'sometimes' of user config 'env.encryption'")
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		setEncryption("true")
+		require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+
+		keyStore.key = nil
+		_, err := dataStore.Get(*mockContext.Context, "env1")
+		require.ErrorContains(t, err, "the key the .env file is encrypted with does not exist")
+	})
+
+	// The .env file is never encrypted with a key that can't be protected by a credential store
+	t.Run("KeyStoreUnavailable", func(t *testing.T) {
+		setEncryption("false")
+		require.NoError(t, os.Remove(dataStore.(*LocalFileDataStore).EnvPath(env)))
+		require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+
+		setEncryption("true")
+		dataStore.(*LocalFileDataStore).keyStore = &memoryKeyStore{err: ErrDotEnvKeyStoreUnavailable}
+		err := dataStore.Save(*mockContext.Context, env, nil)
+		require.ErrorIs(t, err, ErrDotEnvKeyStoreUnavailable)
+		require.ErrorContains(t, err, "azd config unset env.encryption")
+
+		contents, err := os.ReadFile(dataStore.(*LocalFileDataStore).EnvPath(env))
+		require.NoError(t, err)
+		require.False(t, isEncryptedDotEnv(contents))
+	})
+}
+
+// memoryKeyStore is a dotEnvKeyStore keeping the key in memory, failing with err when set
+type memoryKeyStore struct {
+	key []byte
+	err error
+}
+
+func (s *memoryKeyStore) Load() ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.key == nil {
+		return nil, os.ErrNotExist
+	}
+
+	return s.key, nil
+}
+
+func (s *memoryKeyStore) Save(key []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.key = key
+	return nil
+}
+
 func Test_LocalFileDataStore_Path(t *testing.T) {
 	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
-	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 
 	env := New("env1")
 	expected := filepath.Join(azdContext.EnvironmentRoot("env1"), DotEnvFileName)
@@ -75,7 +247,7 @@ func Test_LocalFileDataStore_Path(t *testing.T) {
 func Test_LocalFileDataStore_ConfigPath(t *testing.T) {
 	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
-	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 
 	env := New("env1")
 	expected := filepath.Join(azdContext.EnvironmentRoot("env1"), ConfigFileName)
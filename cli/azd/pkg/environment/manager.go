@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
@@ -82,6 +84,9 @@ type manager struct {
 	remote     DataStore
 	azdContext *azdcontext.AzdContext
 	console    input.Console
+
+	// saveMu serializes the saves of environments, ex) by services deployed in parallel
+	saveMu sync.Mutex
 }
 
 // NewManager creates a new Manager instance
@@ -350,21 +355,24 @@ func (m *manager) Get(ctx context.Context, name string) (*Environment, error) {
 	}
 
 	localEnv, err := m.local.Get(ctx, name)
-	if err != nil {
-		if m.remote == nil {
-			return nil, err
-		}
 
-		remoteEnv, err := m.remote.Get(ctx, name)
-		if err != nil {
-			return nil, err
-		}
-
-		if err := m.local.Save(ctx, remoteEnv, nil); err != nil {
-			return nil, err
+	// The remote state is the source of truth of the environments shared by a team or CI pipelines, the local
+	// environment is updated from it. An environment not yet saved to the remote state is loaded from the local one.
+	// Local environments that can't be read are never overwritten.
+	if m.remote != nil && (err == nil || errors.Is(err, ErrNotFound)) {
+		remoteEnv, remoteErr := m.remote.Get(ctx, name)
+		switch {
+		case remoteErr == nil:
+			if localEnv, err = m.pullRemote(ctx, localEnv, remoteEnv); err != nil {
+				return nil, err
+			}
+		case !errors.Is(remoteErr, ErrNotFound):
+			return nil, fmt.Errorf("loading remote environment, %w", remoteErr)
 		}
+	}
 
-		localEnv = remoteEnv
+	if err != nil {
+		return nil, err
 	}
 
 	// Ensures local environment variable name is synced with the environment name
@@ -379,6 +387,36 @@ func (m *manager) Get(ctx context.Context, name string) (*Environment, error) {
 	return localEnv, nil
 }
 
+// pullRemote returns the local environment updated with the environment loaded from the remote state. The local
+// environment is only updated when the remote state changed since they were last synced, remote data stores that don't
+// report the ETags of the environments only create missing local environments. The values only set in the local
+// environment are kept, values are only removed when they were deleted from the remote state.
+func (m *manager) pullRemote(ctx context.Context, localEnv *Environment, remoteEnv *Environment) (*Environment, error) {
+	if localEnv != nil && (len(remoteEnv.remoteETags) == 0 || maps.Equal(localEnv.remoteETags, remoteEnv.remoteETags)) {
+		return localEnv, nil
+	}
+
+	if localEnv != nil {
+		remoteValues := remoteEnv.Dotenv()
+		for key := range localEnv.Dotenv() {
+			if _, has := remoteValues[key]; has {
+				continue
+			}
+
+			// The local values missing from the remote environment are kept when it is saved locally
+			if _, deleted := remoteEnv.remoteDeletedKeys[key]; deleted {
+				remoteEnv.DotenvDelete(key)
+			}
+		}
+	}
+
+	if err := m.local.Save(ctx, remoteEnv, nil); err != nil {
+		return nil, fmt.Errorf("saving remote environment locally, %w", err)
+	}
+
+	return remoteEnv, nil
+}
+
 // Save saves the environment to the persistent data store
 func (m *manager) Save(ctx context.Context, env *Environment) error {
 	return m.SaveWithOptions(ctx, env, nil)
@@ -390,16 +428,19 @@ func (m *manager) SaveWithOptions(ctx context.Context, env *Environment, options
 		options = &SaveOptions{}
 	}
 
-	if err := m.local.Save(ctx, env, options); err != nil {
-		return fmt.Errorf("saving local environment, %w", err)
-	}
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
 
-	if m.remote == nil {
-		return nil
+	// The environment is saved to the remote state first, so the local environment is left unchanged when the remote
+	// environment can't be saved, ex) when it was modified since it was loaded
+	if m.remote != nil {
+		if err := m.remote.Save(ctx, env, options); err != nil {
+			return fmt.Errorf("saving remote environment, %w", err)
+		}
 	}
 
-	if err := m.remote.Save(ctx, env, options); err != nil {
-		return fmt.Errorf("saving remote environment, %w", err)
+	if err := m.local.Save(ctx, env, options); err != nil {
+		return fmt.Errorf("saving local environment, %w", err)
 	}
 
 	return nil
@@ -407,6 +448,9 @@ func (m *manager) SaveWithOptions(ctx context.Context, env *Environment, options
 
 // Reload reloads the environment from the persistent data store
 func (m *manager) Reload(ctx context.Context, env *Environment) error {
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+
 	return m.local.Reload(ctx, env)
 }
 
@@ -114,7 +114,9 @@ func Test_EnvManager_PromptEnvironmentName(t *testing.T) {
 
 func createEnvManagerForManagerTest(t *testing.T, mockContext *mocks.MockContext) Manager {
 	azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
-	localDataStore := NewLocalFileDataStore(azdCtx, config.NewFileConfigManager(config.NewManager()))
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	localDataStore := NewLocalFileDataStore(
+		azdCtx, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 
 	return newManagerForTest(azdCtx, mockContext.Console, localDataStore, nil)
 }
@@ -207,6 +209,7 @@ func Test_EnvManager_Get(t *testing.T) {
 		remoteDataStore := &MockDataStore{}
 
 		localDataStore.On("Get", *mockContext.Context, "env1").Return(getEnv, nil)
+		remoteDataStore.On("Get", *mockContext.Context, "env1").Return(nil, ErrNotFound)
 
 		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
 		env, err := manager.Get(*mockContext.Context, "env1")
@@ -219,6 +222,99 @@ func Test_EnvManager_Get(t *testing.T) {
 		localDataStore.AssertNotCalled(t, "Save")
 	})
 
+	// The remote state is the source of truth of shared environments, the local environment is updated from it
+	t.Run("ExistsLocallyAndRemotely", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localEnv := NewWithValues("env1", map[string]string{
+			"key1":            "stale",
+			"removed":         "value",
+			"localOnly":       "value",
+			EnvNameEnvVarName: "env1",
+		})
+		localEnv.remoteETags = map[string]string{"env1/.env": "etag1"}
+		remoteEnv := NewWithValues("env1", map[string]string{
+			"key1":            "value1",
+			EnvNameEnvVarName: "env1",
+		})
+		remoteEnv.remoteETags = map[string]string{"env1/.env": "etag2"}
+		remoteEnv.remoteDeletedKeys = map[string]struct{}{"removed": {}}
+
+		localDataStore.On("Get", *mockContext.Context, "env1").Return(localEnv, nil)
+		remoteDataStore.On("Get", *mockContext.Context, "env1").Return(remoteEnv, nil)
+		localDataStore.On("Save", *mockContext.Context, remoteEnv, mock.Anything).Return(nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		env, err := manager.Get(*mockContext.Context, "env1")
+		require.NoError(t, err)
+		require.Same(t, remoteEnv, env)
+		require.Equal(t, "value1", env.Getenv("key1"))
+
+		// Only the values deleted from the remote state are removed from the local environment when it is saved,
+		// the values only set locally are kept
+		_, deletedKeys := env.dotenvState()
+		require.Contains(t, deletedKeys, "removed")
+		require.NotContains(t, deletedKeys, "localOnly")
+		localDataStore.AssertCalled(t, "Save", *mockContext.Context, remoteEnv, mock.Anything)
+	})
+
+	// The local environment is not updated when the remote state is unchanged since they were last synced
+	t.Run("RemoteUnchanged", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localEnv := NewWithValues("env1", map[string]string{
+			"localOnly":       "value",
+			EnvNameEnvVarName: "env1",
+		})
+		localEnv.remoteETags = map[string]string{"env1/.env": "etag1"}
+		remoteEnv := NewWithValues("env1", map[string]string{
+			EnvNameEnvVarName: "env1",
+		})
+		remoteEnv.remoteETags = map[string]string{"env1/.env": "etag1"}
+
+		localDataStore.On("Get", *mockContext.Context, "env1").Return(localEnv, nil)
+		remoteDataStore.On("Get", *mockContext.Context, "env1").Return(remoteEnv, nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		env, err := manager.Get(*mockContext.Context, "env1")
+		require.NoError(t, err)
+		require.Same(t, localEnv, env)
+		require.Equal(t, "value", env.Getenv("localOnly"))
+
+		localDataStore.AssertNotCalled(t, "Save")
+	})
+
+	// Local environments that can't be read are never overwritten by the remote state
+	t.Run("LocalError", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("Get", *mockContext.Context, "env1").Return(nil, errors.New("decrypting .env"))
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		env, err := manager.Get(*mockContext.Context, "env1")
+		require.ErrorContains(t, err, "decrypting .env")
+		require.Nil(t, env)
+
+		remoteDataStore.AssertNotCalled(t, "Get")
+		localDataStore.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("RemoteError", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("Get", *mockContext.Context, "env1").Return(getEnv, nil)
+		remoteDataStore.On("Get", *mockContext.Context, "env1").Return(nil, ErrAccessDenied)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		env, err := manager.Get(*mockContext.Context, "env1")
+		require.ErrorIs(t, err, ErrAccessDenied)
+		require.Nil(t, env)
+	})
+
 	t.Run("ExistsRemotely", func(t *testing.T) {
 		localDataStore := &MockDataStore{}
 		remoteDataStore := &MockDataStore{}
@@ -294,6 +390,7 @@ func Test_EnvManager_Save(t *testing.T) {
 		remoteDataStore.AssertCalled(t, "Save", *mockContext.Context, env, mock.Anything)
 	})
 
+	// The local environment is left unchanged when the remote environment can't be saved
 	t.Run("Error", func(t *testing.T) {
 		localDataStore := &MockDataStore{}
 		remoteDataStore := &MockDataStore{}
@@ -302,14 +399,14 @@ func Test_EnvManager_Save(t *testing.T) {
 			"key1": "value1",
 		})
 
-		localDataStore.On("Save", *mockContext.Context, env, mock.Anything).Return(errors.New("error"))
+		remoteDataStore.On("Save", *mockContext.Context, env, mock.Anything).Return(ErrRemoteEnvironmentModified)
 
 		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
 		err := manager.Save(*mockContext.Context, env)
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrRemoteEnvironmentModified)
 
-		localDataStore.AssertCalled(t, "Save", *mockContext.Context, env, mock.Anything)
-		remoteDataStore.AssertNotCalled(t, "Save", *mockContext.Context, env, mock.Anything)
+		remoteDataStore.AssertCalled(t, "Save", *mockContext.Context, env, mock.Anything)
+		localDataStore.AssertNotCalled(t, "Save", *mockContext.Context, env, mock.Anything)
 	})
 }
 
@@ -370,6 +467,7 @@ func registerContainerComponents(t *testing.T, mockContext *mocks.MockContext) {
 	})
 	mockContext.Container.MustRegisterSingleton(storage.NewBlobSdkClient)
 	mockContext.Container.MustRegisterSingleton(config.NewManager)
+	mockContext.Container.MustRegisterSingleton(config.NewUserConfigManager)
 	mockContext.Container.MustRegisterSingleton(storage.NewBlobClient)
 
 	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
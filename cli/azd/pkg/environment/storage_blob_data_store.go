@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -23,8 +25,14 @@ import (
 var (
 	ErrAccessDenied     = errors.New("access denied connecting Azure Blob Storage container.")
 	ErrInvalidContainer = errors.New("storage container name is invalid.")
+	// ErrRemoteEnvironmentModified is returned when saving an environment that was modified in the remote state since
+	// it was loaded, ex) by a teammate or a CI pipeline
+	ErrRemoteEnvironmentModified = errors.New("the environment was modified in the remote state since it was loaded")
 )
 
+// The comment of the remote .env listing the keys deleted from the environment, ex) # azd-deleted-keys: KEY1,KEY2
+const deletedKeysComment = "# azd-deleted-keys: "
+
 type StorageBlobDataStore struct {
 	configManager config.Manager
 	blobClient    storage.BlobClient
@@ -118,57 +126,131 @@ func (sbd *StorageBlobDataStore) Get(ctx context.Context, name string) (*Environ
 }
 
 func (sbd *StorageBlobDataStore) Save(ctx context.Context, env *Environment, options *SaveOptions) error {
-	// Update configuration
 	cfgWriter := new(bytes.Buffer)
 
 	if err := sbd.configManager.Save(env.Config, cfgWriter); err != nil {
 		return fmt.Errorf("saving config: %w", err)
 	}
 
-	if err := sbd.blobClient.Upload(ctx, sbd.ConfigPath(env), cfgWriter); err != nil {
-		return fmt.Errorf("uploading config: %w", describeError(err))
-	}
-
 	marshalled, err := marshallDotEnv(env)
 	if err != nil {
 		return fmt.Errorf("marshalling .env: %w", err)
 	}
 
+	// The deleted keys are recorded, so the local copies of the environment on other machines delete them too
+	deletedKeys := remoteDeletedKeys(env)
+	if len(deletedKeys) > 0 {
+		marshalled += "\n" + deletedKeysComment + strings.Join(slices.Sorted(maps.Keys(deletedKeys)), ",")
+	}
+
 	buffer := bytes.NewBuffer([]byte(marshalled))
 
-	if err := sbd.blobClient.Upload(ctx, sbd.EnvPath(env), buffer); err != nil {
-		return fmt.Errorf("uploading .env: %w", describeError(err))
+	// The .env file is uploaded first, so nothing is saved when the environment was modified in the remote state
+	if err := sbd.upload(ctx, env, sbd.EnvPath(env), buffer); err != nil {
+		return fmt.Errorf("uploading .env: %w", err)
+	}
+
+	env.remoteDeletedKeys = deletedKeys
+
+	// Update configuration
+	if err := sbd.upload(ctx, env, sbd.ConfigPath(env), cfgWriter); err != nil {
+		return fmt.Errorf("uploading config, the .env file of the environment was already saved: %w", err)
 	}
 
 	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
 	return nil
 }
 
+// upload uploads the file of the environment when it is unchanged in the remote state since the environment was loaded,
+// so that concurrent changes are never overwritten. A file the environment was not loaded from is only created.
+func (sbd *StorageBlobDataStore) upload(ctx context.Context, env *Environment, blobPath string, reader io.Reader) error {
+	options := &storage.UploadOptions{
+		IfMatch:     env.remoteETags[blobPath],
+		IfNotExists: env.remoteETags[blobPath] == "",
+	}
+
+	etag, err := sbd.blobClient.Upload(ctx, blobPath, reader, options)
+	if errors.Is(err, storage.ErrBlobModified) {
+		return fmt.Errorf(
+			"%w, run the command again to load the latest state of environment '%s': %w",
+			ErrRemoteEnvironmentModified,
+			env.Name(),
+			err,
+		)
+	} else if err != nil {
+		return describeError(err)
+	}
+
+	sbd.setETag(env, blobPath, etag)
+	return nil
+}
+
+// remoteDeletedKeys returns the keys deleted from the environment in the remote state, including the keys deleted since
+// the environment was loaded. Keys set again are no longer deleted.
+func remoteDeletedKeys(env *Environment) map[string]struct{} {
+	values, deletedKeys := env.dotenvState()
+	for key := range env.remoteDeletedKeys {
+		deletedKeys[key] = struct{}{}
+	}
+
+	for key := range deletedKeys {
+		if _, has := values[key]; has {
+			delete(deletedKeys, key)
+		}
+	}
+
+	return deletedKeys
+}
+
+// setETag records the ETag of the file of the environment in the remote state
+func (sbd *StorageBlobDataStore) setETag(env *Environment, blobPath string, etag string) {
+	if env.remoteETags == nil {
+		env.remoteETags = map[string]string{}
+	}
+
+	env.remoteETags[blobPath] = etag
+}
+
 func (sbd *StorageBlobDataStore) Reload(ctx context.Context, env *Environment) error {
 	// Reload .env file
-	dotEnvBuffer, err := sbd.blobClient.Download(ctx, sbd.EnvPath(env))
+	dotEnvBuffer, etag, err := sbd.blobClient.Download(ctx, sbd.EnvPath(env))
 	if err != nil {
 		return describeError(err)
 	}
 
 	defer dotEnvBuffer.Close()
+	sbd.setETag(env, sbd.EnvPath(env), etag)
+
+	dotEnv, err := io.ReadAll(dotEnvBuffer)
+	if err != nil {
+		return describeError(err)
+	}
 
-	envMap, err := godotenv.Parse(dotEnvBuffer)
+	envMap, err := godotenv.Unmarshal(string(dotEnv))
 	if err != nil {
-		env.dotenv = make(map[string]string)
-		env.deletedKeys = make(map[string]struct{})
+		env.setDotenv(make(map[string]string))
 	} else {
-		env.dotenv = envMap
-		env.deletedKeys = make(map[string]struct{})
+		env.setDotenv(envMap)
+	}
+
+	env.remoteDeletedKeys = nil
+	for _, line := range strings.Split(string(dotEnv), "\n") {
+		if keys, has := strings.CutPrefix(strings.TrimSpace(line), deletedKeysComment); has {
+			env.remoteDeletedKeys = map[string]struct{}{}
+			for _, key := range strings.Split(keys, ",") {
+				env.remoteDeletedKeys[key] = struct{}{}
+			}
+		}
 	}
 
 	// Reload config file
-	configBuffer, err := sbd.blobClient.Download(ctx, sbd.ConfigPath(env))
+	configBuffer, etag, err := sbd.blobClient.Download(ctx, sbd.ConfigPath(env))
 	if err != nil {
 		return describeError(err)
 	}
 
 	defer configBuffer.Close()
+	sbd.setETag(env, sbd.ConfigPath(env), etag)
 
 	if cfg, err := sbd.configManager.Load(configBuffer); errors.Is(err, os.ErrNotExist) {
 		env.Config = config.NewEmptyConfig()
@@ -72,9 +72,11 @@ func Test_StorageBlobDataStore_SaveAndGet(t *testing.T) {
 		envReader := io.NopCloser(bytes.NewReader([]byte("key1=value1")))
 		configReader := io.NopCloser(bytes.NewReader([]byte("{}")))
 		blobClient.On("Items", *mockContext.Context).Return(validBlobItems, nil)
-		blobClient.On("Download", *mockContext.Context, "env1/.env").Return(envReader, nil)
-		blobClient.On("Download", *mockContext.Context, "env1/config.json").Return(configReader, nil)
-		blobClient.On("Upload", *mockContext.Context, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+		blobClient.On("Download", *mockContext.Context, "env1/.env").Return(envReader, "ENV_ETAG", nil)
+		blobClient.On("Download", *mockContext.Context, "env1/config.json").Return(configReader, "CONFIG_ETAG", nil)
+		blobClient.
+			On("Upload", *mockContext.Context, mock.AnythingOfType("string"), mock.Anything, mock.Anything).
+			Return("ETAG", nil)
 
 		env1 := New("env1")
 		env1.DotenvSet("key1", "value1")
@@ -90,6 +92,131 @@ func Test_StorageBlobDataStore_SaveAndGet(t *testing.T) {
 	})
 }
 
+func Test_StorageBlobDataStore_Save_Concurrency(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	configManager := config.NewManager()
+
+	t.Run("UnchangedSinceLoaded", func(t *testing.T) {
+		blobClient := &MockBlobClient{}
+		dataStore := NewStorageBlobDataStore(configManager, blobClient)
+
+		blobClient.On("Items", *mockContext.Context).Return(validBlobItems, nil)
+		blobClient.On("Download", *mockContext.Context, "env1/.env").
+			Return(io.NopCloser(bytes.NewReader([]byte("key1=value1"))), "ENV_ETAG", nil)
+		blobClient.On("Download", *mockContext.Context, "env1/config.json").
+			Return(io.NopCloser(bytes.NewReader([]byte("{}"))), "CONFIG_ETAG", nil)
+		blobClient.On("Upload", *mockContext.Context, "env1/config.json", mock.Anything, mock.Anything).
+			Return("NEW_CONFIG_ETAG", nil)
+		blobClient.On("Upload", *mockContext.Context, "env1/.env", mock.Anything, mock.Anything).
+			Return("NEW_ENV_ETAG", nil)
+
+		env, err := dataStore.Get(*mockContext.Context, "env1")
+		require.NoError(t, err)
+
+		// The files are only uploaded when they are unchanged since they were loaded
+		require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+		blobClient.AssertCalled(t, "Upload", *mockContext.Context, "env1/.env", mock.Anything,
+			&storage.UploadOptions{IfMatch: "ENV_ETAG"})
+		blobClient.AssertCalled(t, "Upload", *mockContext.Context, "env1/config.json", mock.Anything,
+			&storage.UploadOptions{IfMatch: "CONFIG_ETAG"})
+
+		// The following saves are conditioned on the ETags of the uploaded files
+		require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+		blobClient.AssertCalled(t, "Upload", *mockContext.Context, "env1/.env", mock.Anything,
+			&storage.UploadOptions{IfMatch: "NEW_ENV_ETAG"})
+	})
+
+	t.Run("NotLoaded", func(t *testing.T) {
+		blobClient := &MockBlobClient{}
+		dataStore := NewStorageBlobDataStore(configManager, blobClient)
+
+		blobClient.On("Upload", *mockContext.Context, mock.AnythingOfType("string"), mock.Anything, mock.Anything).
+			Return("ETAG", nil)
+
+		// An environment not loaded from the remote state never overwrites an environment of the remote state
+		require.NoError(t, dataStore.Save(*mockContext.Context, New("env1"), &SaveOptions{IsNew: true}))
+		blobClient.AssertCalled(t, "Upload", *mockContext.Context, "env1/.env", mock.Anything,
+			&storage.UploadOptions{IfNotExists: true})
+	})
+
+	t.Run("ModifiedRemotely", func(t *testing.T) {
+		blobClient := &MockBlobClient{}
+		dataStore := NewStorageBlobDataStore(configManager, blobClient)
+
+		blobClient.On("Upload", *mockContext.Context, mock.AnythingOfType("string"), mock.Anything, mock.Anything).
+			Return("", fmt.Errorf("failed to upload blob 'env1/config.json', %w", storage.ErrBlobModified))
+
+		err := dataStore.Save(*mockContext.Context, New("env1"), nil)
+		require.ErrorIs(t, err, ErrRemoteEnvironmentModified)
+		require.ErrorContains(t, err, "run the command again to load the latest state of environment 'env1'")
+	})
+
+	t.Run("DotEnvModifiedRemotely", func(t *testing.T) {
+		blobClient := &MockBlobClient{}
+		dataStore := NewStorageBlobDataStore(configManager, blobClient)
+
+		blobClient.On("Upload", *mockContext.Context, "env1/.env", mock.Anything, mock.Anything).
+			Return("", fmt.Errorf("failed to upload blob 'env1/.env', %w", storage.ErrBlobModified))
+		blobClient.On("Upload", *mockContext.Context, "env1/config.json", mock.Anything, mock.Anything).
+			Return("ETAG", nil)
+
+		// Nothing is saved when the .env file was modified in the remote state
+		err := dataStore.Save(*mockContext.Context, New("env1"), nil)
+		require.ErrorIs(t, err, ErrRemoteEnvironmentModified)
+		require.ErrorContains(t, err, "uploading .env")
+		blobClient.AssertNotCalled(t, "Upload", *mockContext.Context, "env1/config.json", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ConfigModifiedRemotely", func(t *testing.T) {
+		blobClient := &MockBlobClient{}
+		dataStore := NewStorageBlobDataStore(configManager, blobClient)
+
+		blobClient.On("Upload", *mockContext.Context, "env1/.env", mock.Anything, mock.Anything).
+			Return("ETAG", nil)
+		blobClient.On("Upload", *mockContext.Context, "env1/config.json", mock.Anything, mock.Anything).
+			Return("", fmt.Errorf("failed to upload blob 'env1/config.json', %w", storage.ErrBlobModified))
+
+		// The .env file saved before the config is reported
+		err := dataStore.Save(*mockContext.Context, New("env1"), nil)
+		require.ErrorIs(t, err, ErrRemoteEnvironmentModified)
+		require.ErrorContains(t, err, "the .env file of the environment was already saved")
+	})
+}
+
+func Test_StorageBlobDataStore_DeletedKeys(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	blobClient := &MockBlobClient{}
+	dataStore := NewStorageBlobDataStore(config.NewManager(), blobClient)
+
+	blobClient.On("Items", *mockContext.Context).Return(validBlobItems, nil)
+	blobClient.On("Download", *mockContext.Context, "env1/.env").
+		Return(io.NopCloser(bytes.NewReader([]byte("key1=value1\nkey2=value2\n# azd-deleted-keys: old\n"))), "ETAG", nil)
+	blobClient.On("Download", *mockContext.Context, "env1/config.json").
+		Return(io.NopCloser(bytes.NewReader([]byte("{}"))), "ETAG", nil)
+	blobClient.On("Upload", *mockContext.Context, "env1/config.json", mock.Anything, mock.Anything).Return("ETAG", nil)
+
+	var uploaded string
+	blobClient.On("Upload", *mockContext.Context, "env1/.env", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			contents, err := io.ReadAll(args.Get(2).(io.Reader))
+			require.NoError(t, err)
+			uploaded = string(contents)
+		}).
+		Return("ETAG", nil)
+
+	env, err := dataStore.Get(*mockContext.Context, "env1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key1": "value1", "key2": "value2"}, env.Dotenv())
+	require.Equal(t, map[string]struct{}{"old": {}}, env.remoteDeletedKeys)
+
+	// The keys deleted from the environment are recorded, keys set again are no longer deleted
+	env.DotenvDelete("key2")
+	env.DotenvSet("old", "value")
+	require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+	require.Equal(t, "key1=\"value1\"\nold=\"value\"\n# azd-deleted-keys: key2", uploaded)
+	require.Equal(t, map[string]struct{}{"key2": {}}, env.remoteDeletedKeys)
+}
+
 func Test_StorageBlobDataStore_Path(t *testing.T) {
 	configManager := config.NewManager()
 	blobClient := &MockBlobClient{}
@@ -118,14 +245,19 @@ type MockBlobClient struct {
 	mock.Mock
 }
 
-func (m *MockBlobClient) Download(ctx context.Context, blobPath string) (io.ReadCloser, error) {
+func (m *MockBlobClient) Download(ctx context.Context, blobPath string) (io.ReadCloser, string, error) {
 	args := m.Called(ctx, blobPath)
-	return args.Get(0).(io.ReadCloser), args.Error(1)
+	return args.Get(0).(io.ReadCloser), args.String(1), args.Error(2)
 }
 
-func (m *MockBlobClient) Upload(ctx context.Context, blobPath string, reader io.Reader) error {
-	args := m.Called(ctx, blobPath, reader)
-	return args.Error(0)
+func (m *MockBlobClient) Upload(
+	ctx context.Context,
+	blobPath string,
+	reader io.Reader,
+	options *storage.UploadOptions,
+) (string, error) {
+	args := m.Called(ctx, blobPath, reader, options)
+	return args.String(0), args.Error(1)
 }
 
 func (m *MockBlobClient) Delete(ctx context.Context, blobPath string) error {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -13,6 +14,8 @@ import (
 
 type Cli struct {
 	commandRunner exec.CommandRunner
+	// Optional kube config file passed to the release commands with the --kubeconfig flag
+	kubeConfigPath string
 }
 
 func NewCli(commandRunner exec.CommandRunner) *Cli {
@@ -49,6 +52,12 @@ func (cli *Cli) CheckInstalled(ctx context.Context) error {
 	return nil
 }
 
+// Sets the kube config file passed explicitly with the --kubeconfig flag to the release commands
+// An empty path restores the default kube config resolution
+func (c *Cli) SetKubeConfigPath(kubeConfigPath string) {
+	c.kubeConfigPath = kubeConfigPath
+}
+
 // AddRepo adds a helm repo with the specified name and url
 func (c *Cli) AddRepo(ctx context.Context, repo *Repository) error {
 	runArgs := exec.NewRunArgs("helm", "repo", "add", repo.Name, repo.Url)
@@ -78,7 +87,7 @@ func (c *Cli) Install(ctx context.Context, release *Release) error {
 		runArgs = runArgs.AppendParams("--values", release.Values)
 	}
 
-	_, err := c.commandRunner.Run(ctx, runArgs)
+	_, err := c.commandRunner.Run(ctx, c.withKubeConfig(runArgs))
 	if err != nil {
 		return fmt.Errorf("failed to install helm chart %s: %w", release.Chart, err)
 	}
@@ -98,6 +107,10 @@ func (c *Cli) Upgrade(ctx context.Context, release *Release) error {
 		runArgs = runArgs.AppendParams("--values", release.Values)
 	}
 
+	for _, valuesFile := range release.ValuesFiles {
+		runArgs = runArgs.AppendParams("--values", valuesFile)
+	}
+
 	if release.Namespace != "" {
 		runArgs = runArgs.AppendParams(
 			"--namespace", release.Namespace,
@@ -105,7 +118,7 @@ func (c *Cli) Upgrade(ctx context.Context, release *Release) error {
 		)
 	}
 
-	_, err := c.commandRunner.Run(ctx, runArgs)
+	_, err := c.commandRunner.Run(ctx, c.withKubeConfig(runArgs))
 	if err != nil {
 		return fmt.Errorf("failed to install helm chart %s: %w", release.Chart, err)
 	}
@@ -113,6 +126,82 @@ func (c *Cli) Upgrade(ctx context.Context, release *Release) error {
 	return nil
 }
 
+// RegistryLogin logs into the OCI registry the charts are pushed to and pulled from, ex) contoso.azurecr.io
+func (c *Cli) RegistryLogin(ctx context.Context, server string, username string, password string) error {
+	runArgs := exec.NewRunArgs(
+		"helm", "registry", "login", server,
+		"--username", username,
+		"--password-stdin",
+	).WithStdIn(strings.NewReader(password))
+
+	_, err := c.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed logging into helm registry %s: %w", server, err)
+	}
+
+	return nil
+}
+
+// Package packages the chart directory into a chart archive within the destination directory and returns the path
+// of the archive, ex) api-0.1.0.tgz. The version of the Chart.yaml is used when the version is empty.
+func (c *Cli) Package(ctx context.Context, chartPath string, version string, destination string) (string, error) {
+	runArgs := exec.NewRunArgs("helm", "package", chartPath, "--destination", destination)
+	if version != "" {
+		runArgs = runArgs.AppendParams("--version", version)
+	}
+
+	runResult, err := c.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf("failed packaging helm chart %s: %w", chartPath, err)
+	}
+
+	// ex) Successfully packaged chart and saved it to: /tmp/charts/api-0.1.0.tgz
+	_, packagePath, has := strings.Cut(strings.TrimSpace(runResult.Stdout), "saved it to: ")
+	if !has {
+		return "", fmt.Errorf("failed packaging helm chart %s: unexpected output '%s'", chartPath, runResult.Stdout)
+	}
+
+	return strings.TrimSpace(packagePath), nil
+}
+
+// Push pushes the chart archive to the OCI registry, ex) oci://contoso.azurecr.io/helm
+func (c *Cli) Push(ctx context.Context, packagePath string, remote string) (*PushResult, error) {
+	runArgs := exec.NewRunArgs("helm", "push", packagePath, remote)
+	runResult, err := c.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed pushing helm chart %s: %w", packagePath, err)
+	}
+
+	// ex) Pushed: contoso.azurecr.io/helm/api:0.1.0
+	//     Digest: sha256:ec5f08ee7be8b557cd1fc5ae1a0ac985e8538da7c93f51a51eff4b277509a723
+	result := &PushResult{}
+	for _, line := range strings.Split(runResult.Stdout+"\n"+runResult.Stderr, "\n") {
+		key, value, has := strings.Cut(strings.TrimSpace(line), ": ")
+		if !has {
+			continue
+		}
+
+		switch key {
+		case "Pushed":
+			index := strings.LastIndex(value, ":")
+			if index < 0 {
+				return nil, fmt.Errorf("failed pushing helm chart %s: unexpected reference '%s'", packagePath, value)
+			}
+
+			result.Reference = fmt.Sprintf("oci://%s", value[:index])
+			result.Version = value[index+1:]
+		case "Digest":
+			result.Digest = value
+		}
+	}
+
+	if result.Reference == "" {
+		return nil, fmt.Errorf("failed pushing helm chart %s: the pushed reference was not found", packagePath)
+	}
+
+	return result, nil
+}
+
 // Status returns the status of a helm release
 func (c *Cli) Status(ctx context.Context, release *Release) (*StatusResult, error) {
 	runArgs := exec.NewRunArgs("helm", "status", release.Name, "--output", "json")
@@ -120,7 +209,7 @@ func (c *Cli) Status(ctx context.Context, release *Release) (*StatusResult, erro
 		runArgs = runArgs.AppendParams("--namespace", release.Namespace)
 	}
 
-	runResult, err := c.commandRunner.Run(ctx, runArgs)
+	runResult, err := c.commandRunner.Run(ctx, c.withKubeConfig(runArgs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query status for helm chart %s: %w", release.Chart, err)
 	}
@@ -133,6 +222,15 @@ func (c *Cli) Status(ctx context.Context, release *Release) (*StatusResult, erro
 	return result, nil
 }
 
+// withKubeConfig appends the --kubeconfig flag when a kube config file has been set
+func (c *Cli) withKubeConfig(runArgs exec.RunArgs) exec.RunArgs {
+	if c.kubeConfigPath == "" {
+		return runArgs
+	}
+
+	return runArgs.AppendParams("--kubeconfig", c.kubeConfigPath)
+}
+
 func (cli *Cli) getClientVersion(ctx context.Context) (string, error) {
 	runArgs := exec.NewRunArgs("helm", "version", "--template", "{{.Version}}")
 	versionResult, err := cli.commandRunner.Run(ctx, runArgs)
@@ -143,6 +241,16 @@ func (cli *Cli) getClientVersion(ctx context.Context) (string, error) {
 	return versionResult.Stdout[1:], nil
 }
 
+// PushResult is the chart pushed to an OCI registry with a helm push command
+type PushResult struct {
+	// The reference the chart is installed from, ex) oci://contoso.azurecr.io/helm/api
+	Reference string
+	// The version of the chart, ex) 0.1.0
+	Version string
+	// The digest of the chart manifest, ex) sha256:ec5f...
+	Digest string
+}
+
 // StatusResult is the result of a helm status command
 type StatusResult struct {
 	Name      string     `json:"name"`
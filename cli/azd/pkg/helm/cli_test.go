@@ -280,6 +280,78 @@ func Test_Cli_Upgrade(t *testing.T) {
 		}, runArgs.Args)
 	})
 
+	t.Run("WithKubeConfig", func(t *testing.T) {
+		ran := false
+		var runArgs exec.RunArgs
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				ran = true
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		cli.SetKubeConfigPath("/azure/dev/.kube/config")
+		err := cli.Upgrade(*mockContext.Context, release)
+		require.True(t, ran)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"upgrade",
+			"test",
+			"test/chart",
+			"--install",
+			"--wait",
+			"--kubeconfig",
+			"/azure/dev/.kube/config",
+		}, runArgs.Args)
+	})
+
+	t.Run("WithValuesFiles", func(t *testing.T) {
+		ran := false
+		var runArgs exec.RunArgs
+
+		releaseWithValues := *release
+		releaseWithValues.Values = "values.yaml"
+		releaseWithValues.ValuesFiles = []string{"values-dev.yaml", "values-local.yaml"}
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				ran = true
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Upgrade(*mockContext.Context, &releaseWithValues)
+		require.True(t, ran)
+		require.NoError(t, err)
+
+		require.Equal(t, "helm", runArgs.Cmd)
+		require.Equal(t, []string{
+			"upgrade",
+			"test",
+			"test/chart",
+			"--install",
+			"--wait",
+			"--values",
+			"values.yaml",
+			"--values",
+			"values-dev.yaml",
+			"--values",
+			"values-local.yaml",
+		}, runArgs.Args)
+	})
+
 	t.Run("WithVersion", func(t *testing.T) {
 		ran := false
 		var runArgs exec.RunArgs
@@ -472,3 +544,94 @@ func Test_Cli_Status(t *testing.T) {
 		require.ErrorContains(t, err, "failed to get status")
 	})
 }
+
+func Test_Cli_RegistryLogin(t *testing.T) {
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "helm registry login")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "Login Succeeded", ""), nil
+		})
+
+	cli := NewCli(mockContext.CommandRunner)
+	err := cli.RegistryLogin(*mockContext.Context, "contoso.azurecr.io", "USERNAME", "PASSWORD")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"registry", "login", "contoso.azurecr.io",
+		"--username", "USERNAME",
+		"--password-stdin",
+	}, runArgs.Args)
+	require.NotContains(t, runArgs.Args, "PASSWORD")
+}
+
+func Test_Cli_Package(t *testing.T) {
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "helm package")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(
+				0, "Successfully packaged chart and saved it to: /tmp/charts/api-1.0.2.tgz\n", ""), nil
+		})
+
+	cli := NewCli(mockContext.CommandRunner)
+	packagePath, err := cli.Package(*mockContext.Context, "./charts/api", "1.0.2", "/tmp/charts")
+	require.NoError(t, err)
+	require.Equal(t, "/tmp/charts/api-1.0.2.tgz", packagePath)
+	require.Equal(t, []string{
+		"package", "./charts/api",
+		"--destination", "/tmp/charts",
+		"--version", "1.0.2",
+	}, runArgs.Args)
+}
+
+func Test_Cli_Push(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm push")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				return exec.NewRunResult(
+					0, "", "Pushed: contoso.azurecr.io/helm/api:1.0.2\nDigest: sha256:ec5f08ee\n"), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		result, err := cli.Push(*mockContext.Context, "/tmp/charts/api-1.0.2.tgz", "oci://contoso.azurecr.io/helm")
+		require.NoError(t, err)
+		require.Equal(t, []string{"push", "/tmp/charts/api-1.0.2.tgz", "oci://contoso.azurecr.io/helm"}, runArgs.Args)
+		require.Equal(t, &PushResult{
+			Reference: "oci://contoso.azurecr.io/helm/api",
+			Version:   "1.0.2",
+			Digest:    "sha256:ec5f08ee",
+		}, result)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm push")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(1, "", ""), errors.New("unauthorized")
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		_, err := cli.Push(*mockContext.Context, "/tmp/charts/api-1.0.2.tgz", "oci://contoso.azurecr.io/helm")
+		require.ErrorContains(t, err, "failed pushing helm chart")
+	})
+}
@@ -1,8 +1,12 @@
 package helm
 
+import "strings"
+
 type Config struct {
 	Repositories []*Repository `yaml:"repositories"`
 	Releases     []*Release    `yaml:"releases"`
+	// The local charts packaged and pushed to the container registry when the service is packaged
+	Charts []*Chart `yaml:"charts"`
 }
 
 type Repository struct {
@@ -16,4 +20,24 @@ type Release struct {
 	Version   string `yaml:"version"`
 	Namespace string `yaml:"namespace"`
 	Values    string `yaml:"values"`
+	// Additional values files applied in order after Values.
+	// Supports environment variable substitution, ex) values-${AZURE_ENV_NAME}.yaml
+	ValuesFiles []string `yaml:"valuesFiles"`
+}
+
+// Chart is a local chart packaged and pushed to an OCI registry, ex) the container registry of the environment.
+// Releases of the chart path are installed from the pushed chart.
+type Chart struct {
+	// The path of the chart directory, relative to the service path, ex) ./charts/api
+	Path string `yaml:"path"`
+	// The repository the chart is pushed to, ex) helm. Defaults to helm
+	Repository string `yaml:"repository"`
+	// The version of the packaged chart, ex) 1.0.${GITHUB_RUN_NUMBER}. Defaults to the version of the Chart.yaml
+	// Supports environment variable substitution
+	Version string `yaml:"version"`
+}
+
+// IsOci returns true when the release chart references a chart stored within an OCI registry
+func (r *Release) IsOci() bool {
+	return strings.HasPrefix(r.Chart, "oci://")
 }
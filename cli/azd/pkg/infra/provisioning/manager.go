@@ -103,7 +103,7 @@ func (m *Manager) Deploy(ctx context.Context) (*DeployResult, error) {
 	if !filepath.IsAbs(infraRoot) {
 		infraRoot = filepath.Join(m.projectPath, m.options.Path)
 	}
-	bindMountOperations, err := azdFileShareUploadOperations(infraRoot, *m.env)
+	bindMountOperations, err := azdFileShareUploadOperations(infraRoot, m.env)
 	azdOperationsEnabled := m.alphaFeatureManager.IsEnabled(AzdOperationsFeatureKey)
 	if !azdOperationsEnabled && len(bindMountOperations) > 0 {
 		m.console.Message(ctx, ErrBindMountOperationDisabled.Error())
@@ -113,7 +113,7 @@ func (m *Manager) Deploy(ctx context.Context) (*DeployResult, error) {
 			return nil, fmt.Errorf("looking for azd fileShare upload operations: %w", err)
 		}
 		if err := doBindMountOperation(
-			ctx, bindMountOperations, *m.env, m.console, m.fileShareService, m.cloud.StorageEndpointSuffix); err != nil {
+			ctx, bindMountOperations, m.env, m.console, m.fileShareService, m.cloud.StorageEndpointSuffix); err != nil {
 			return nil, fmt.Errorf("error running bind mount operation: %w", err)
 		}
 	}
@@ -146,7 +146,7 @@ type azdOperationsModel struct {
 	Operations []azdOperation
 }
 
-func azdOperations(infraPath string, env environment.Environment) (azdOperationsModel, error) {
+func azdOperations(infraPath string, env *environment.Environment) (azdOperationsModel, error) {
 	path := filepath.Join(infraPath, azdOperationsFileName)
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -175,7 +175,7 @@ func azdOperations(infraPath string, env environment.Environment) (azdOperations
 	return operations, nil
 }
 
-func azdFileShareUploadOperations(infraPath string, env environment.Environment) ([]azdOperationFileShareUpload, error) {
+func azdFileShareUploadOperations(infraPath string, env *environment.Environment) ([]azdOperationFileShareUpload, error) {
 	model, err := azdOperations(infraPath, env)
 	if err != nil {
 		return nil, err
@@ -214,7 +214,7 @@ var ErrBindMountOperationDisabled = fmt.Errorf(
 func doBindMountOperation(
 	ctx context.Context,
 	fileShareUploadOperations []azdOperationFileShareUpload,
-	env environment.Environment,
+	env *environment.Environment,
 	console input.Console,
 	fileShareService storage.FileShareService,
 	cloudStorageEndpointSuffix string,
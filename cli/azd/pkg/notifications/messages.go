@@ -0,0 +1,134 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// message returns the body of the request notifying a target of the kind of the deploy result
+func message(kind Kind, result *DeployResult) ([]byte, error) {
+	var body any
+	switch kind {
+	case KindTeams:
+		body = teamsMessage(result)
+	case KindSlack:
+		body = slackMessage(result)
+	default:
+		body = result
+	}
+
+	jsonBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling notification: %w", err)
+	}
+
+	return jsonBytes, nil
+}
+
+// summary returns a one line summary of the deploy result, ex) Deployed todo to dev in 1m5s
+func summary(result *DeployResult) string {
+	duration := (time.Duration(result.Duration * float64(time.Second))).Round(time.Second)
+	if result.Success {
+		return fmt.Sprintf("Deployed %s to %s in %s", result.Project, result.Environment, duration)
+	}
+
+	return fmt.Sprintf("Failed deploying %s to %s after %s", result.Project, result.Environment, duration)
+}
+
+// serviceLines returns a line describing the result of each service
+func serviceLines(result *DeployResult, link func(url string) string) []string {
+	lines := []string{}
+	for _, service := range result.Services {
+		status := "succeeded"
+		if !service.Success {
+			status = "failed"
+		}
+
+		parts := []string{fmt.Sprintf("%s %s", service.Name, status)}
+		if service.Image != "" {
+			parts = append(parts, service.Image)
+		}
+
+		if service.ImageDigest != "" && !strings.Contains(service.Image, service.ImageDigest) {
+			parts = append(parts, service.ImageDigest)
+		}
+
+		for _, endpoint := range service.Endpoints {
+			parts = append(parts, link(endpoint))
+		}
+
+		if service.Error != "" {
+			parts = append(parts, service.Error)
+		}
+
+		lines = append(lines, strings.Join(parts, " - "))
+	}
+
+	if !result.Success && result.Error != "" && len(result.Services) == 0 {
+		lines = append(lines, result.Error)
+	}
+
+	return lines
+}
+
+// slackMessage returns the Slack message of the deploy result, ex) posted to a Slack incoming webhook
+func slackMessage(result *DeployResult) map[string]any {
+	lines := serviceLines(result, func(url string) string {
+		return fmt.Sprintf("<%s>", url)
+	})
+
+	text := fmt.Sprintf("*%s*", summary(result))
+	for _, line := range lines {
+		text += "\n• " + line
+	}
+
+	return map[string]any{
+		"text": text,
+	}
+}
+
+// teamsMessage returns the adaptive card of the deploy result, ex) posted to a Teams incoming webhook
+func teamsMessage(result *DeployResult) map[string]any {
+	color := "Good"
+	if !result.Success {
+		color = "Attention"
+	}
+
+	body := []any{
+		map[string]any{
+			"type":   "TextBlock",
+			"text":   summary(result),
+			"weight": "Bolder",
+			"size":   "Medium",
+			"color":  color,
+			"wrap":   true,
+		},
+	}
+
+	for _, line := range serviceLines(result, func(url string) string {
+		return fmt.Sprintf("[%s](%s)", url, url)
+	}) {
+		body = append(body, map[string]any{
+			"type": "TextBlock",
+			"text": "- " + line,
+			"wrap": true,
+		})
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []any{
+			map[string]any{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    body,
+				},
+			},
+		},
+	}
+}
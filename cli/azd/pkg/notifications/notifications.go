@@ -0,0 +1,235 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// Kind is the kind of destination notifications are sent to
+type Kind string
+
+const (
+	// A webhook receiving the deploy result as json
+	KindWebhook Kind = "webhook"
+	// A Microsoft Teams incoming webhook or workflow receiving an adaptive card
+	KindTeams Kind = "teams"
+	// A Slack incoming webhook
+	KindSlack Kind = "slack"
+)
+
+// When configures which deploy results a target is notified of
+type When string
+
+const (
+	WhenAlways  When = "always"
+	WhenSuccess When = "success"
+	WhenFailure When = "failure"
+)
+
+// The user config path of the notification targets, ex) azd config set notifications.team.url <url>
+const userConfigPath = "notifications"
+
+// The timeout of each notification request
+const requestTimeout = 30 * time.Second
+
+// Target is a destination the deploy results are sent to
+type Target struct {
+	// The kind of the target, webhook, teams or slack. Defaults to the name of the target when it names a kind,
+	// otherwise to webhook
+	Kind Kind `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// The url the results are posted to, ex) ${SLACK_WEBHOOK_URL}. Environment variables are substituted from the
+	// environment deployed to
+	Url string `json:"url"            yaml:"url"`
+	// Which results the target is notified of, always, success or failure. Defaults to always
+	On When `json:"on,omitempty"   yaml:"on,omitempty"`
+}
+
+// kind returns the kind of the target named name
+func (t *Target) kind(name string) Kind {
+	if t.Kind != "" {
+		return t.Kind
+	}
+
+	if kind := Kind(name); kind == KindTeams || kind == KindSlack {
+		return kind
+	}
+
+	return KindWebhook
+}
+
+// notifies returns true when the target is notified of the result
+func (t *Target) notifies(result *DeployResult) bool {
+	switch t.On {
+	case WhenSuccess:
+		return result.Success
+	case WhenFailure:
+		return !result.Success
+	default:
+		return true
+	}
+}
+
+// Validate returns an error when the targets are invalid
+func Validate(targets map[string]*Target) error {
+	for name, target := range targets {
+		if target == nil || target.Url == "" {
+			return fmt.Errorf("notification target '%s' is missing its url", name)
+		}
+
+		switch target.kind(name) {
+		case KindWebhook, KindTeams, KindSlack:
+		default:
+			return fmt.Errorf(
+				"unsupported kind '%s' of notification target '%s', supported values are webhook, teams and slack",
+				target.Kind, name)
+		}
+
+		switch target.On {
+		case "", WhenAlways, WhenSuccess, WhenFailure:
+		default:
+			return fmt.Errorf(
+				"unsupported value '%s' of 'on' of notification target '%s', supported values are always, success and "+
+					"failure", target.On, name)
+		}
+	}
+
+	return nil
+}
+
+// Targets returns the notification targets of the project, along with the targets of the user configuration not
+// configured by the project
+func Targets(projectTargets map[string]*Target, userConfig config.Config) (map[string]*Target, error) {
+	targets := map[string]*Target{}
+	if userConfig != nil {
+		if _, err := userConfig.GetSection(userConfigPath, &targets); err != nil {
+			return nil, fmt.Errorf("getting notifications config: %w", err)
+		}
+	}
+
+	maps.Copy(targets, projectTargets)
+	if err := Validate(targets); err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// DeployResult is the result of a deployment sent to the notification targets
+type DeployResult struct {
+	Project     string    `json:"project"`
+	Environment string    `json:"environment"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	// The duration of the deployment, in seconds
+	Duration float64          `json:"duration"`
+	Services []*ServiceResult `json:"services"`
+}
+
+// ServiceResult is the result of the deployment of a service
+type ServiceResult struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// The container image deployed, ex) contoso.azurecr.io/todo/api:azd-deploy-1700000000
+	Image string `json:"image,omitempty"`
+	// The digest of the container image deployed when known, ex) sha256:4f2d...
+	ImageDigest string   `json:"imageDigest,omitempty"`
+	Endpoints   []string `json:"endpoints,omitempty"`
+	// The duration of the deployment of the service, in seconds
+	Duration float64 `json:"duration"`
+}
+
+// Notifier sends the results of deployments to the notification targets
+type Notifier struct {
+	transporter policy.Transporter
+}
+
+// NewNotifier creates a new Notifier
+func NewNotifier(transporter policy.Transporter) *Notifier {
+	return &Notifier{
+		transporter: transporter,
+	}
+}
+
+// NotifyDeploy posts the deploy result to the targets notified of it. A failed notification does not prevent the other
+// targets from being notified; the errors of the failed notifications are returned.
+func (n *Notifier) NotifyDeploy(
+	ctx context.Context,
+	targets map[string]*Target,
+	getenv func(string) string,
+	result *DeployResult,
+) error {
+	errs := []error{}
+
+	// Targets are notified in a stable order
+	for _, name := range slices.Sorted(maps.Keys(targets)) {
+		target := targets[name]
+		if !target.notifies(result) {
+			continue
+		}
+
+		if err := n.notify(ctx, name, target, getenv, result); err != nil {
+			errs = append(errs, fmt.Errorf("notifying '%s': %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// notify posts the deploy result to the target, formatted for the kind of the target
+func (n *Notifier) notify(
+	ctx context.Context,
+	name string,
+	target *Target,
+	getenv func(string) string,
+	result *DeployResult,
+) error {
+	targetUrl, err := osutil.NewExpandableString(target.Url).Envsubst(getenv)
+	if err != nil {
+		return fmt.Errorf("substituting environment variables in url: %w", err)
+	}
+
+	if targetUrl == "" {
+		return errors.New("the url is empty in the environment")
+	}
+
+	body, err := message(target.kind(name), result)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, targetUrl, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	response, err := n.transporter.Do(request)
+	if err != nil {
+		// The url of a webhook is a secret, the error of the request may include it
+		return errors.New("sending request failed")
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("the request returned status code %d", response.StatusCode)
+	}
+
+	return nil
+}
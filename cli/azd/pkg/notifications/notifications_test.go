@@ -0,0 +1,115 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Targets(t *testing.T) {
+	userConfig := config.NewConfig(map[string]any{
+		"notifications": map[string]any{
+			"slack": map[string]any{"url": "https://hooks.slack.com/services/USER"},
+			"ops":   map[string]any{"url": "https://user.contoso.com", "on": "failure"},
+		},
+	})
+
+	// The targets of the project take precedence over the targets of the user configuration with the same name
+	targets, err := Targets(map[string]*Target{
+		"ops": {Kind: KindTeams, Url: "${TEAMS_WEBHOOK_URL}"},
+	}, userConfig)
+	require.NoError(t, err)
+	require.Equal(t, map[string]*Target{
+		"slack": {Url: "https://hooks.slack.com/services/USER"},
+		"ops":   {Kind: KindTeams, Url: "${TEAMS_WEBHOOK_URL}"},
+	}, targets)
+
+	_, err = Targets(map[string]*Target{"ops": {Kind: "email", Url: "https://contoso.com"}}, nil)
+	require.ErrorContains(t, err, "unsupported kind 'email' of notification target 'ops'")
+
+	_, err = Targets(map[string]*Target{"ops": {Url: "https://contoso.com", On: "never"}}, nil)
+	require.ErrorContains(t, err, "unsupported value 'never' of 'on' of notification target 'ops'")
+
+	_, err = Targets(map[string]*Target{"ops": {}}, nil)
+	require.ErrorContains(t, err, "notification target 'ops' is missing its url")
+}
+
+func Test_Notifier_NotifyDeploy(t *testing.T) {
+	result := &DeployResult{
+		Project:     "todo",
+		Environment: "dev",
+		Success:     true,
+		Timestamp:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Duration:    65,
+		Services: []*ServiceResult{
+			{
+				Name:        "api",
+				Success:     true,
+				Image:       "contoso.azurecr.io/todo/api@sha256:4f2d",
+				ImageDigest: "sha256:4f2d",
+				Endpoints:   []string{"https://api.contoso.com/"},
+				Duration:    60,
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	bodies := map[string]map[string]any{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		var message map[string]any
+		if err := json.Unmarshal(body, &message); err != nil {
+			return nil, err
+		}
+
+		bodies[request.URL.Host] = message
+		if request.URL.Host == "broken.contoso.com" {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusInternalServerError)
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	notifier := NewNotifier(mockContext.HttpClient)
+	err := notifier.NotifyDeploy(*mockContext.Context, map[string]*Target{
+		"webhook":  {Url: "https://${WEBHOOK_HOST}/deployments"},
+		"slack":    {Url: "https://slack.contoso.com"},
+		"channel":  {Kind: KindTeams, Url: "https://teams.contoso.com"},
+		"failures": {Url: "https://failures.contoso.com", On: WhenFailure},
+		"broken":   {Url: "https://broken.contoso.com"},
+	}, func(name string) string {
+		return map[string]string{"WEBHOOK_HOST": "webhook.contoso.com"}[name]
+	}, result)
+
+	// A failed notification does not prevent the other targets from being notified
+	require.ErrorContains(t, err, "notifying 'broken': the request returned status code 500")
+	require.NotContains(t, bodies, "failures.contoso.com")
+
+	require.Equal(t, "todo", bodies["webhook.contoso.com"]["project"])
+	require.Equal(t, true, bodies["webhook.contoso.com"]["success"])
+	services := bodies["webhook.contoso.com"]["services"].([]any)
+	require.Equal(t, "sha256:4f2d", services[0].(map[string]any)["imageDigest"])
+
+	require.Equal(t,
+		"*Deployed todo to dev in 1m5s*\n• api succeeded - contoso.azurecr.io/todo/api@sha256:4f2d - "+
+			"<https://api.contoso.com/>",
+		bodies["slack.contoso.com"]["text"])
+
+	attachments := bodies["teams.contoso.com"]["attachments"].([]any)
+	card := attachments[0].(map[string]any)["content"].(map[string]any)
+	require.Equal(t, "AdaptiveCard", card["type"])
+	require.Equal(t, "Deployed todo to dev in 1m5s", card["body"].([]any)[0].(map[string]any)["text"])
+}
@@ -14,6 +14,9 @@ import (
 type ShowService struct {
 	Name      string
 	IngresUrl string
+	// The live status of the service and the revision serving it, when reported by its host
+	Status   string
+	Revision string
 }
 
 type ShowEnvironment struct {
@@ -75,10 +78,26 @@ func services(services []*ShowService) string {
 			color.HiBlueString(service.Name),
 			output.WithLinkFormat(service.IngresUrl),
 		)
+		if status := serviceStatus(service); status != "" {
+			lines[index] += "  " + output.WithGrayFormat("%s", status)
+		}
 	}
 	return strings.Join(lines, "\n")
 }
 
+// serviceStatus returns the status of the service, ex) (Available, revision 3)
+func serviceStatus(service *ShowService) string {
+	if service.Status == "" {
+		return ""
+	}
+
+	if service.Revision == "" {
+		return fmt.Sprintf("(%s)", service.Status)
+	}
+
+	return fmt.Sprintf("(%s, revision %s)", service.Status, service.Revision)
+}
+
 func environments(environments []*ShowEnvironment) string {
 	environmentsCount := len(environments)
 	if environmentsCount == 0 {
@@ -104,3 +104,27 @@ func TestShowRemoteAndCurrent(t *testing.T) {
 	output := pp.ToString("")
 	snapshot.SnapshotT(t, output)
 }
+
+func TestShowServiceStatus(t *testing.T) {
+	pp := &Show{
+		AppName: "Foo",
+		Services: []*ShowService{
+			{
+				Name:      "xx",
+				IngresUrl: "bar",
+				Status:    "Available",
+				Revision:  "3",
+			},
+			{
+				Name:      "yy",
+				IngresUrl: "baz",
+				Status:    "Running",
+			},
+		},
+		Environments:    []*ShowEnvironment{},
+		AzurePortalLink: "foo.com",
+	}
+
+	output := pp.ToString("")
+	snapshot.SnapshotT(t, output)
+}
@@ -773,7 +773,7 @@ func createPipelineManager(
 		mockContext.Console,
 		args,
 		mockContext.Container,
-		project.NewImportManager(nil),
+		project.NewImportManager(nil, nil),
 		&mockUserConfigManager{},
 	)
 }
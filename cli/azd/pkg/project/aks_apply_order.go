@@ -0,0 +1,185 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The annotation used to apply a k8s resource in an explicit wave, ex) azd.azure.com/wave: "1"
+// Waves are applied in ascending order. Resources without the annotation are applied in wave 0.
+const aksWaveAnnotation = "azd.azure.com/wave"
+
+// The kinds applied before the remaining resources of a wave when the kinds are not configured
+var defaultApplyOrderKinds = []string{"CustomResourceDefinition", "Namespace"}
+
+// The readiness conditions of the resources waited on before the next wave is applied
+// Resources of other kinds are applied without waiting for them to be ready
+var waveReadinessConditions = map[string]string{
+	"CustomResourceDefinition": "condition=Established",
+	"Namespace":                "jsonpath={.status.phase}=Active",
+	"Deployment":               "condition=Available",
+	"Job":                      "condition=Complete",
+}
+
+// The AKS apply order options of the deployment manifests
+type AksApplyOrderOptions struct {
+	// The kinds applied in order before the remaining resources of the same wave.
+	// Defaults to CustomResourceDefinition and Namespace
+	Kinds []string `yaml:"kinds"`
+	// When disabled, the resources of a wave are not waited on to be ready before the next wave is applied.
+	// Defaults to true
+	Wait *bool `yaml:"wait"`
+}
+
+// applyWave is a set of k8s objects applied together
+type applyWave struct {
+	// The wave of the azd.azure.com/wave annotation
+	wave int
+	// The position of the kind of the objects within the ordered kinds
+	rank    int
+	objects []k8sObject
+}
+
+// applyWaves groups the k8s objects into waves, ordered by the wave annotation and then by the ordered kinds.
+// Objects of the same wave keep the order they are defined in within the manifests.
+func applyWaves(options *AksApplyOrderOptions, objects []k8sObject) ([]*applyWave, error) {
+	kinds := options.Kinds
+	if len(kinds) == 0 {
+		kinds = defaultApplyOrderKinds
+	}
+
+	waves := []*applyWave{}
+	for _, object := range objects {
+		wave := 0
+		if value, has := object.nested("metadata", "annotations")[aksWaveAnnotation]; has {
+			parsed, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(value)))
+			if err != nil {
+				return nil, fmt.Errorf(
+					"invalid '%s' annotation '%v' of %s '%s', the wave must be an integer",
+					aksWaveAnnotation, value, object.kind(), object.name(),
+				)
+			}
+
+			wave = parsed
+		}
+
+		rank := slices.IndexFunc(kinds, func(kind string) bool {
+			return strings.EqualFold(kind, object.kind())
+		})
+		if rank == -1 {
+			rank = len(kinds)
+		}
+
+		index := slices.IndexFunc(waves, func(w *applyWave) bool {
+			return w.wave == wave && w.rank == rank
+		})
+		if index == -1 {
+			waves = append(waves, &applyWave{wave: wave, rank: rank})
+			index = len(waves) - 1
+		}
+
+		waves[index].objects = append(waves[index].objects, object)
+	}
+
+	slices.SortStableFunc(waves, func(a, b *applyWave) int {
+		if a.wave != b.wave {
+			return a.wave - b.wave
+		}
+
+		return a.rank - b.rank
+	})
+
+	return waves, nil
+}
+
+// applyManifestsInWaves applies the manifests within the deployment path in waves, ex) CRDs and namespaces before
+// the resources that depend on them, waiting for the resources of each wave to be ready before the next wave
+func (t *aksTarget) applyManifestsInWaves(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentPath string,
+	task *async.Progress[ServiceProgress],
+) error {
+	manifests, err := t.kubectl.ReadManifests(deploymentPath, serviceConfig.K8s.Envsubst)
+	if err != nil {
+		return err
+	}
+
+	objects, err := parseK8sObjects(manifests)
+	if err != nil {
+		return err
+	}
+
+	if err := t.applyScheduling(serviceConfig, objects); err != nil {
+		return err
+	}
+
+	options := serviceConfig.K8s.ApplyOrder
+	waves, err := applyWaves(options, objects)
+	if err != nil {
+		return err
+	}
+
+	wait := convert.ToValueWithDefault(options.Wait, true)
+	for i, wave := range waves {
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Applying k8s manifests (wave %d of %d)", i+1, len(waves))))
+
+		manifest, err := marshalK8sObjects(wave.objects)
+		if err != nil {
+			return err
+		}
+
+		if _, err := t.kubectl.ApplyWithStdIn(ctx, manifest, nil); err != nil {
+			return err
+		}
+
+		// The resources of the last wave are verified along with the deployments of the service
+		if !wait || i == len(waves)-1 {
+			continue
+		}
+
+		if err := t.waitForWave(ctx, serviceConfig, wave, task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitForWave waits for the resources of the wave with a known readiness condition to be ready
+func (t *aksTarget) waitForWave(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	wave *applyWave,
+	task *async.Progress[ServiceProgress],
+) error {
+	timeout := serviceConfig.K8s.Wait.Timeout
+	if timeout == 0 {
+		timeout = kubectl.DefaultWaitTimeout
+	}
+
+	for _, object := range wave.objects {
+		condition, has := waveReadinessConditions[object.kind()]
+		if !has {
+			continue
+		}
+
+		resource := fmt.Sprintf("%s/%s", strings.ToLower(object.kind()), object.name())
+		namespace, _ := object.nested("metadata")["namespace"].(string)
+
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Waiting for %s", resource)))
+		_, err := t.kubectl.Wait(ctx, resource, condition, timeout, &kubectl.KubeCliFlags{Namespace: namespace})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package project
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_ApplyOrder(t *testing.T) {
+	manifests := map[string]string{
+		"app.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api-deployment\n  namespace: apps\n" +
+			"---\napiVersion: v1\nkind: Service\nmetadata:\n  name: api-service\n  namespace: apps\n",
+		"crd.yaml": "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\n" +
+			"metadata:\n  name: widgets.contoso.com\n",
+		"migrate.yaml": "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: migrate\n  namespace: apps\n" +
+			"  annotations:\n    azd.azure.com/wave: \"-1\"\n",
+		"namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n",
+		"widget.yaml": "apiVersion: contoso.com/v1\nkind: Widget\nmetadata:\n  name: widget\n  namespace: apps\n" +
+			"  annotations:\n    azd.azure.com/wave: \"1\"\n",
+	}
+
+	tests := map[string]struct {
+		applyOrder    *AksApplyOrderOptions
+		prune         bool
+		expectedWaves [][]string
+		expectedWaits [][]string
+		expectedError string
+	}{
+		"Default": {
+			applyOrder: &AksApplyOrderOptions{},
+			expectedWaves: [][]string{
+				{"Job/migrate"},
+				{"CustomResourceDefinition/widgets.contoso.com"},
+				{"Namespace/apps"},
+				{"Deployment/api-deployment", "Service/api-service"},
+				{"Widget/widget"},
+			},
+			expectedWaits: [][]string{
+				withIsolatedKubeConfig("wait", "job/migrate", "--for=condition=Complete", "--timeout=10ms", "-n", "apps"),
+				withIsolatedKubeConfig(
+					"wait", "customresourcedefinition/widgets.contoso.com", "--for=condition=Established",
+					"--timeout=10ms",
+				),
+				withIsolatedKubeConfig("wait", "namespace/apps", "--for=jsonpath={.status.phase}=Active", "--timeout=10ms"),
+				withIsolatedKubeConfig(
+					"wait", "deployment/api-deployment", "--for=condition=Available", "--timeout=10ms", "-n", "apps",
+				),
+			},
+		},
+		"KindsWithoutWait": {
+			applyOrder: &AksApplyOrderOptions{Kinds: []string{"namespace", "service"}, Wait: to.Ptr(false)},
+			expectedWaves: [][]string{
+				{"Job/migrate"},
+				{"Namespace/apps"},
+				{"Service/api-service"},
+				{"Deployment/api-deployment", "CustomResourceDefinition/widgets.contoso.com"},
+				{"Widget/widget"},
+			},
+			expectedWaits: [][]string{},
+		},
+		"Prune": {
+			applyOrder:    &AksApplyOrderOptions{},
+			prune:         true,
+			expectedError: "apply order is not supported when pruning is enabled",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, manifests)
+			serviceConfig.K8s.ApplyOrder = test.applyOrder
+			serviceConfig.K8s.Prune = test.prune
+			setupJobMocks(mockContext, []kubectl.JobCondition{{Type: "Complete", Status: "True"}})
+
+			waves := [][]string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply -f -")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				input, err := io.ReadAll(args.StdIn)
+				require.NoError(t, err)
+
+				objects, err := parseK8sObjects([]string{string(input)})
+				require.NoError(t, err)
+
+				wave := []string{}
+				for _, object := range objects {
+					wave = append(wave, object.kind()+"/"+object.name())
+				}
+
+				waves = append(waves, wave)
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			waits := [][]string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl wait")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				waits = append(waits, args.Args)
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			_, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedWaves, waves)
+			require.Equal(t, test.expectedWaits, waits)
+		})
+	}
+}
+
+func Test_ApplyWaves_InvalidAnnotation(t *testing.T) {
+	objects, err := parseK8sObjects([]string{
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  annotations:\n    azd.azure.com/wave: first\n",
+	})
+	require.NoError(t, err)
+
+	_, err = applyWaves(&AksApplyOrderOptions{}, objects)
+	require.ErrorContains(t, err, "invalid 'azd.azure.com/wave' annotation 'first' of ConfigMap 'settings'")
+}
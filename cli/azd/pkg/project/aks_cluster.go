@@ -0,0 +1,230 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The AKS cluster options
+// When configured, the service targets the specified cluster instead of the cluster resolved from the azd environment
+type AksClusterOptions struct {
+	AksClusterTarget `yaml:",inline"`
+	// The cluster overrides of specific azd environments keyed by the environment name, ex) dev, prod
+	Environments map[string]AksClusterTarget `yaml:"environments"`
+}
+
+// The kind of Kubernetes cluster targeted by the service
+type K8sClusterKind string
+
+const (
+	// An Azure Kubernetes Service managed cluster
+	K8sClusterKindAks K8sClusterKind = "aks"
+	// An Azure Red Hat OpenShift cluster
+	K8sClusterKindOpenShift K8sClusterKind = "aro"
+	// An Azure Arc-enabled Kubernetes cluster, ex) a cluster running on-premises connected to Azure Arc
+	K8sClusterKindArc K8sClusterKind = "arc"
+)
+
+// The AKS cluster targeted by the service
+type AksClusterTarget struct {
+	// The kind of the cluster, ex) aro or arc so the same manifests are deployed on-premises for some of the azd
+	// environments. Defaults to aks
+	Kind K8sClusterKind `yaml:"kind"`
+	// The name of the AKS cluster
+	Name osutil.ExpandableString `yaml:"name"`
+	// The resource group of the AKS cluster. Defaults to the resource group of the service
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup"`
+	// The subscription of the AKS cluster. Defaults to the subscription of the service
+	SubscriptionId osutil.ExpandableString `yaml:"subscriptionId"`
+}
+
+// targetCluster returns the AKS cluster targeted by the service
+// The cluster configuration of the current azd environment takes precedence over the cluster configuration of the
+// service, any values that are not configured are resolved from resolveClusterName and the target resource
+func (t *aksTarget) targetCluster(
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (aksCluster, error) {
+	cluster := newAksCluster(targetResource.SubscriptionId(), targetResource.ResourceGroupName(), "")
+
+	if options := serviceConfig.K8s.Cluster; options != nil {
+		targets := []AksClusterTarget{options.AksClusterTarget}
+		if override, has := options.Environments[t.env.Name()]; has {
+			targets = append(targets, override)
+		}
+
+		for _, target := range targets {
+			if err := t.applyClusterTarget(&cluster, target); err != nil {
+				return aksCluster{}, err
+			}
+		}
+	}
+
+	if cluster.name == "" {
+		clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
+		if err != nil {
+			return aksCluster{}, err
+		}
+
+		cluster.name = clusterName
+	}
+
+	return cluster, nil
+}
+
+// applyClusterTarget overrides the cluster values that are configured on the cluster target
+func (t *aksTarget) applyClusterTarget(cluster *aksCluster, target AksClusterTarget) error {
+	name, err := target.Name.Envsubst(t.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst cluster name: %w", err)
+	}
+
+	resourceGroupName, err := target.ResourceGroup.Envsubst(t.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst cluster resource group: %w", err)
+	}
+
+	subscriptionId, err := target.SubscriptionId.Envsubst(t.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst cluster subscription id: %w", err)
+	}
+
+	if name != "" {
+		cluster.name = name
+	}
+
+	if resourceGroupName != "" {
+		cluster.resourceGroupName = resourceGroupName
+	}
+
+	if subscriptionId != "" {
+		cluster.subscriptionId = subscriptionId
+	}
+
+	switch target.Kind {
+	case "":
+	case K8sClusterKindAks, K8sClusterKindOpenShift, K8sClusterKindArc:
+		cluster.kind = target.Kind
+	default:
+		return fmt.Errorf("unsupported cluster kind '%s', use one of aks, aro or arc", target.Kind)
+	}
+
+	return nil
+}
+
+// resourceId returns the ARM resource id of the cluster
+func (c aksCluster) resourceId() string {
+	resourceType := azapi.AzureResourceTypeManagedCluster
+	switch c.kind {
+	case K8sClusterKindOpenShift:
+		resourceType = azapi.AzureResourceTypeOpenShiftCluster
+	case K8sClusterKindArc:
+		resourceType = azapi.AzureResourceTypeConnectedCluster
+	}
+
+	return fmt.Sprintf(
+		"%s/providers/%s/%s",
+		azure.ResourceGroupRID(c.subscriptionId, c.resourceGroupName),
+		resourceType,
+		c.name,
+	)
+}
+
+// validateClusterKind returns an error when the service uses features that depend on the AKS resource provider and
+// targets an OpenShift or Arc-enabled cluster
+func validateClusterKind(serviceConfig *ServiceConfig, cluster aksCluster) error {
+	if cluster.kind != K8sClusterKindOpenShift && cluster.kind != K8sClusterKindArc {
+		return nil
+	}
+
+	features := []string{}
+	if convert.ToValueWithDefault(serviceConfig.K8s.RunCommand, false) {
+		features = append(features, "runCommand")
+	}
+
+	if serviceConfig.K8s.WorkloadIdentity != nil {
+		features = append(features, "workloadIdentity")
+	}
+
+	if serviceConfig.K8s.KeyVault != nil {
+		features = append(features, "keyVault")
+	}
+
+	if len(features) > 0 {
+		return fmt.Errorf(
+			"'k8s.%s' of service '%s' is only supported on AKS clusters, cluster '%s' is of kind '%s'",
+			strings.Join(features, "', 'k8s."),
+			serviceConfig.Name,
+			cluster.name,
+			cluster.kind,
+		)
+	}
+
+	return nil
+}
+
+// connectKubernetesCluster acquires the credentials of the OpenShift or Arc-enabled cluster and writes them to the
+// kube config of the azd environment.
+// OpenShift clusters are accessed with their admin kube config. Arc-enabled clusters are accessed through the cluster
+// connect endpoint of Azure Arc with AAD credentials converted with kubelogin, so that clusters running on-premises
+// don't need to expose their API server.
+func (t *aksTarget) connectKubernetesCluster(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	cluster aksCluster,
+	defaultNamespace string,
+) (string, error) {
+	if err := validateClusterKind(serviceConfig, cluster); err != nil {
+		return "", err
+	}
+
+	var rawKubeConfig []byte
+	var err error
+	aadEnabled := false
+
+	switch cluster.kind {
+	case K8sClusterKindOpenShift:
+		log.Printf("getting OpenShift admin credentials for cluster '%s'\n", cluster.name)
+		rawKubeConfig, err = t.managedClustersService.GetOpenShiftAdminKubeConfig(
+			ctx, cluster.subscriptionId, cluster.resourceGroupName, cluster.name)
+	default:
+		log.Printf("getting Azure Arc cluster connect credentials for cluster '%s'\n", cluster.name)
+		rawKubeConfig, err = t.managedClustersService.GetConnectedClusterUserKubeConfig(
+			ctx, cluster.subscriptionId, cluster.resourceGroupName, cluster.name)
+		aadEnabled = true
+	}
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed retrieving cluster credentials. Ensure the current principal has been granted rights to the cluster, %w",
+			err,
+		)
+	}
+
+	kubeConfig, err := kubectl.ParseKubeConfig(ctx, rawKubeConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed parsing kube config. Ensure your configuration is valid yaml. %w", err)
+	}
+
+	if len(kubeConfig.Contexts) == 0 {
+		return "", fmt.Errorf("the kube config of cluster '%s' has no context", cluster.name)
+	}
+
+	return t.useKubeConfig(
+		ctx,
+		serviceConfig,
+		cluster.name,
+		kubeConfig,
+		defaultNamespace,
+		aadEnabled,
+		t.env.Getenv(environment.TenantIdEnvVarName),
+	)
+}
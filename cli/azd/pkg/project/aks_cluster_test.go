@@ -0,0 +1,166 @@
+package project
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_TargetCluster(t *testing.T) {
+	tests := map[string]struct {
+		options  *AksClusterOptions
+		expected aksCluster
+	}{
+		"Default": {
+			expected: newAksCluster("SUB_ID", "RG_ID", "AKS_CLUSTER"),
+		},
+		"Service": {
+			options: &AksClusterOptions{
+				AksClusterTarget: AksClusterTarget{
+					Name:          osutil.NewExpandableString("${CLUSTER_NAME}"),
+					ResourceGroup: osutil.NewExpandableString("rg-shared"),
+				},
+			},
+			expected: newAksCluster("SUB_ID", "rg-shared", "aks-shared"),
+		},
+		"Environment": {
+			options: &AksClusterOptions{
+				AksClusterTarget: AksClusterTarget{
+					Name:          osutil.NewExpandableString("aks-dev"),
+					ResourceGroup: osutil.NewExpandableString("rg-dev"),
+				},
+				Environments: map[string]AksClusterTarget{
+					"test": {
+						Name:           osutil.NewExpandableString("aks-test"),
+						SubscriptionId: osutil.NewExpandableString("SUB_TEST"),
+					},
+					"prod": {
+						Name: osutil.NewExpandableString("aks-prod"),
+					},
+				},
+			},
+			expected: newAksCluster("SUB_TEST", "rg-dev", "aks-test"),
+		},
+		"Kind": {
+			options: &AksClusterOptions{
+				Environments: map[string]AksClusterTarget{
+					"test": {
+						Kind: K8sClusterKindArc,
+						Name: osutil.NewExpandableString("arc-onprem"),
+					},
+				},
+			},
+			expected: aksCluster{
+				subscriptionId:    "SUB_ID",
+				resourceGroupName: "RG_ID",
+				name:              "arc-onprem",
+				kind:              K8sClusterKindArc,
+			},
+		},
+		"OtherEnvironment": {
+			options: &AksClusterOptions{
+				Environments: map[string]AksClusterTarget{
+					"prod": {
+						Name:          osutil.NewExpandableString("aks-prod"),
+						ResourceGroup: osutil.NewExpandableString("rg-prod"),
+					},
+				},
+			},
+			expected: newAksCluster("SUB_ID", "RG_ID", "AKS_CLUSTER"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Cluster = test.options
+
+			env := createEnv()
+			env.DotenvSet("CLUSTER_NAME", "aks-shared")
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil).(*aksTarget)
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+
+			cluster, err := serviceTarget.targetCluster(serviceConfig, scope)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cluster)
+		})
+	}
+}
+
+func Test_TargetCluster_UnsupportedKind(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Cluster = &AksClusterOptions{
+		AksClusterTarget: AksClusterTarget{Kind: "gke"},
+	}
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil).(*aksTarget)
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+
+	_, err := serviceTarget.targetCluster(serviceConfig, scope)
+	require.ErrorContains(t, err, "unsupported cluster kind 'gke'")
+}
+
+func Test_ValidateClusterKind(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.WorkloadIdentity = &AksWorkloadIdentityOptions{}
+
+	require.NoError(t, validateClusterKind(serviceConfig, newAksCluster("SUB_ID", "RG_ID", "aks")))
+
+	arcCluster := aksCluster{subscriptionId: "SUB_ID", resourceGroupName: "RG_ID", name: "arc", kind: K8sClusterKindArc}
+	require.ErrorContains(t,
+		validateClusterKind(serviceConfig, arcCluster),
+		"'k8s.workloadIdentity' of service 'api' is only supported on AKS clusters, cluster 'arc' is of kind 'arc'",
+	)
+
+	serviceConfig.K8s.WorkloadIdentity = nil
+	require.NoError(t, validateClusterKind(serviceConfig, arcCluster))
+}
+
+func Test_ConnectKubernetesCluster_OpenShift(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	kubeConfigBytes, err := yaml.Marshal(createTestCluster("aro", "kubeadmin"))
+	require.NoError(t, err)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(
+			request.URL.Path, "/Microsoft.RedHatOpenShift/openShiftClusters/aro/listAdminCredentials")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"kubeconfig": kubeConfigBytes})
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil).(*aksTarget)
+	cluster := aksCluster{subscriptionId: "SUB_ID", resourceGroupName: "RG_ID", name: "aro", kind: K8sClusterKindOpenShift}
+
+	kubeConfigPath, err := serviceTarget.connectKubernetesCluster(*mockContext.Context, serviceConfig, cluster, "api")
+	require.NoError(t, err)
+	require.Equal(t, serviceTarget.isolatedKubeConfigPath(), kubeConfigPath)
+
+	raw, err := os.ReadFile(kubeConfigPath)
+	require.NoError(t, err)
+
+	var kubeConfig map[string]any
+	require.NoError(t, yaml.Unmarshal(raw, &kubeConfig))
+	require.Equal(t, "aro", kubeConfig["current-context"])
+
+	require.Equal(t,
+		"/subscriptions/SUB_ID/resourceGroups/RG_ID/providers/Microsoft.RedHatOpenShift/openShiftClusters/aro",
+		cluster.resourceId(),
+	)
+}
@@ -0,0 +1,124 @@
+package project
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"gopkg.in/yaml.v3"
+)
+
+// writeComposeManifests writes the k8s manifests of a service imported from a compose file to a temporary directory,
+// ex) when the service does not declare manifests of its own. The caller should ensure the directory is removed.
+// The Deployment runs the image of the service with the environment variables of the compose service and a Service
+// with the name of the compose service exposes its ports, so that the services reach each other like they do in compose.
+func (t *aksTarget) writeComposeManifests(serviceConfig *ServiceConfig) (string, error) {
+	manifests, err := t.composeManifests(serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	deploymentPath, err := os.MkdirTemp("", "azd-compose-manifests")
+	if err != nil {
+		return "", fmt.Errorf("failed creating compose manifests directory, %w", err)
+	}
+
+	manifestPath := filepath.Join(deploymentPath, fmt.Sprintf("%s.yaml", serviceConfig.Name))
+	if err := os.WriteFile(manifestPath, []byte(manifests), osutil.PermissionFileOwnerOnly); err != nil {
+		os.RemoveAll(deploymentPath)
+		return "", fmt.Errorf("failed writing compose manifests, %w", err)
+	}
+
+	return deploymentPath, nil
+}
+
+// composeManifests returns the Deployment and Service of a service imported from a compose file
+func (t *aksTarget) composeManifests(serviceConfig *ServiceConfig) (string, error) {
+	image := t.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+	if image == "" {
+		return "", fmt.Errorf("the image of compose service '%s' has not been pushed", serviceConfig.Name)
+	}
+
+	env, err := serviceConfig.Compose.resolveEnvironment(t.env)
+	if err != nil {
+		return "", err
+	}
+
+	labels := map[string]any{"app": serviceConfig.Name}
+
+	container := map[string]any{
+		"name":  serviceConfig.Name,
+		"image": image,
+	}
+
+	containerEnv := []any{}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		containerEnv = append(containerEnv, map[string]any{"name": name, "value": env[name]})
+	}
+	if len(containerEnv) > 0 {
+		container["env"] = containerEnv
+	}
+
+	containerPorts := []any{}
+	servicePorts := []any{}
+	for _, port := range serviceConfig.Compose.Ports {
+		name := fmt.Sprintf("port-%d", port)
+		containerPorts = append(containerPorts, map[string]any{"name": name, "containerPort": port})
+		servicePorts = append(servicePorts, map[string]any{"name": name, "port": port, "targetPort": port})
+	}
+	if len(containerPorts) > 0 {
+		container["ports"] = containerPorts
+	}
+
+	objects := []map[string]any{
+		{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":   t.getDeploymentName(serviceConfig),
+				"labels": labels,
+			},
+			"spec": map[string]any{
+				"replicas": 1,
+				"selector": map[string]any{"matchLabels": labels},
+				"template": map[string]any{
+					"metadata": map[string]any{"labels": labels},
+					"spec": map[string]any{
+						"containers": []any{container},
+					},
+				},
+			},
+		},
+	}
+
+	if len(servicePorts) > 0 {
+		objects = append(objects, map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]any{
+				"name":   serviceConfig.Name,
+				"labels": labels,
+			},
+			"spec": map[string]any{
+				"selector": labels,
+				"ports":    servicePorts,
+			},
+		})
+	}
+
+	documents := make([]string, 0, len(objects))
+	for _, object := range objects {
+		document, err := yaml.Marshal(object)
+		if err != nil {
+			return "", fmt.Errorf("failed marshalling compose manifest, %w", err)
+		}
+
+		documents = append(documents, string(document))
+	}
+
+	return strings.Join(documents, "---\n"), nil
+}
@@ -0,0 +1,92 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AksTarget_ComposeManifests(t *testing.T) {
+	env := environment.NewWithValues("dev", map[string]string{
+		"SERVICE_API_IMAGE_NAME": "contoso.azurecr.io/todo/api:azd-deploy-0",
+		"DATABASE_URL":           "postgres://db:5432/todo",
+	})
+	target := &aksTarget{env: env}
+
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageDocker)
+	serviceConfig.Compose = &ComposeServiceOptions{
+		Ports: []int{80},
+		Environment: map[string]osutil.ExpandableString{
+			"DATABASE_URL": osutil.NewExpandableString("${DATABASE_URL}"),
+			"LOG_LEVEL":    osutil.NewExpandableString("info"),
+		},
+	}
+
+	manifests, err := target.composeManifests(serviceConfig)
+	require.NoError(t, err)
+	require.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+    labels:
+        app: api
+    name: api
+spec:
+    replicas: 1
+    selector:
+        matchLabels:
+            app: api
+    template:
+        metadata:
+            labels:
+                app: api
+        spec:
+            containers:
+                - env:
+                    - name: DATABASE_URL
+                      value: postgres://db:5432/todo
+                    - name: LOG_LEVEL
+                      value: info
+                  image: contoso.azurecr.io/todo/api:azd-deploy-0
+                  name: api
+                  ports:
+                    - containerPort: 80
+                      name: port-80
+---
+apiVersion: v1
+kind: Service
+metadata:
+    labels:
+        app: api
+    name: api
+spec:
+    ports:
+        - name: port-80
+          port: 80
+          targetPort: 80
+    selector:
+        app: api
+`, manifests)
+
+	deploymentPath, err := target.writeComposeManifests(serviceConfig)
+	require.NoError(t, err)
+	defer os.RemoveAll(deploymentPath)
+
+	contents, err := os.ReadFile(filepath.Join(deploymentPath, "api.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, manifests, string(contents))
+
+	// Without ports, only the Deployment is generated
+	serviceConfig.Compose.Ports = nil
+	manifests, err = target.composeManifests(serviceConfig)
+	require.NoError(t, err)
+	require.NotContains(t, manifests, "kind: Service")
+
+	// The image is pushed before the manifests are generated
+	env.DotenvDelete("SERVICE_API_IMAGE_NAME")
+	_, err = target.composeManifests(serviceConfig)
+	require.ErrorContains(t, err, "the image of compose service 'api' has not been pushed")
+}
@@ -0,0 +1,79 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// applyDaprComponents applies the Dapr components of the service to the namespace of the service
+// Components with a namespace in their metadata are applied to that namespace instead
+func (t *aksTarget) applyDaprComponents(ctx context.Context, serviceConfig *ServiceConfig) error {
+	components, err := loadDaprComponents(serviceConfig, t.env.Getenv)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, daprComponentsManifest(components), nil); err != nil {
+		return fmt.Errorf(
+			"failed applying Dapr components, verify the Dapr extension is installed on the cluster: %w", err)
+	}
+
+	return nil
+}
+
+// waitForDaprSidecars waits until all pods of the deployment are running with a ready Dapr sidecar
+// The sidecar is only ready once it loaded the Dapr components of the service
+func (t *aksTarget) waitForDaprSidecars(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentName string,
+	task *async.Progress[ServiceProgress],
+) error {
+	dapr := serviceConfig.Dapr
+	if dapr == nil || !dapr.waitForSidecar() {
+		return nil
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying Dapr sidecars: %s", deploymentName)))
+
+	pending, timeout, err := t.waitForSidecarContainer(ctx, serviceConfig, deploymentName, daprSidecarName)
+	if errors.Is(err, kubectl.ErrResourceNotReady) {
+		return fmt.Errorf(
+			//nolint:lll
+			"%s sidecar of deployment '%s' is not ready after %s (%s). Verify the Dapr extension is installed on the cluster, the pod template sets the 'dapr.io/enabled' and 'dapr.io/app-id' annotations and the Dapr components of the service are valid",
+			daprSidecarName,
+			deploymentName,
+			timeout.Round(time.Second),
+			strings.Join(pending, ", "),
+		)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The app id is only verified when configured since the pod template sets the app id of the sidecar
+	if dapr.AppId == "" {
+		return nil
+	}
+
+	for _, pod := range t.getWorkloadPods(ctx, deploymentName) {
+		if appId, has := pod.Metadata.Annotations["dapr.io/app-id"]; has && appId != dapr.AppId {
+			return fmt.Errorf(
+				"pod '%s' of deployment '%s' runs with Dapr app id '%v' instead of '%s'",
+				pod.Metadata.Name,
+				deploymentName,
+				appId,
+				dapr.AppId,
+			)
+		}
+	}
+
+	return nil
+}
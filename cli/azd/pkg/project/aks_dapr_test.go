@@ -0,0 +1,118 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_Dapr_Components(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	applied := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		input, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		applied = append(applied, string(input))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.Dapr = &DaprOptions{Components: "components", WaitForSidecar: new(bool)}
+	writeDaprComponent(t, filepath.Join(serviceConfig.Path(), "components"), "pubsub.yaml")
+
+	env := createEnv()
+	env.DotenvSet("SERVICEBUS_NAMESPACE", "sb-01")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+	})
+	require.NoError(t, err)
+
+	// The components are applied before the manifests of the service
+	require.NotEmpty(t, applied)
+	require.Contains(t, applied[0], "kind: Component")
+	require.Contains(t, applied[0], "value: sb-01.servicebus.windows.net")
+}
+
+func Test_WaitForDaprSidecars(t *testing.T) {
+	tests := map[string]struct {
+		pod           kubectl.Pod
+		appId         string
+		expectedError string
+	}{
+		"Ready": {
+			pod: createSidecarPod("api-5d8f7b-new", daprSidecarName, true),
+		},
+		"NotInjected": {
+			pod:           createPod("api-5d8f7b-new", "Running", kubectl.ContainerState{}, true),
+			expectedError: "daprd sidecar of deployment 'api' is not ready",
+		},
+		"AppIdMismatch": {
+			pod:           createSidecarPod("api-5d8f7b-new", daprSidecarName, true),
+			appId:         "orders",
+			expectedError: "pod 'api-5d8f7b-new' of deployment 'api' runs with Dapr app id 'api' instead of 'orders'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+
+			pod := test.pod
+			pod.Metadata.Annotations = map[string]any{"dapr.io/enabled": "true", "dapr.io/app-id": "api"}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get pods")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				jsonBytes, _ := json.Marshal(kubectl.List[kubectl.Pod]{Items: []kubectl.Pod{pod}})
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.Dapr = &DaprOptions{AppId: test.appId}
+			serviceConfig.K8s.Wait.Timeout = 50 * time.Millisecond
+			serviceConfig.K8s.Wait.PollInterval = time.Millisecond
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil).(*aksTarget)
+			err := async.RunWithProgressE(func(progress ServiceProgress) {}, func(p *async.Progress[ServiceProgress]) error {
+				return serviceTarget.waitForDaprSidecars(*mockContext.Context, serviceConfig, "api", p)
+			})
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// Previews the changes deploying the service would apply to the AKS cluster using kubectl diff
+// Container images are not pushed during a preview, so manifests referencing the service image resolve to the
+// image of the previous deployment.
+func (t *aksTarget) Preview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePreviewResult, error) {
+	if err := t.validateTargetResource(targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	// Unlike a deployment, the namespace is not created since a preview must not modify the cluster
+	t.kubectl.SetEnv(t.env.Dotenv())
+	t.kubectl.SetServerSideApply(serverSideApplyOptions(serviceConfig))
+	if kubeConfigPath := t.env.Getenv(kubectl.KubeConfigEnvVarName); kubeConfigPath != "" {
+		t.kubectl.SetKubeConfig(kubeConfigPath)
+	}
+
+	progress.SetProgress(NewServiceProgress("Connecting to AKS cluster"))
+	_, err := t.ensureClusterContext(ctx, serviceConfig, targetResource, t.getK8sNamespace(serviceConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ServicePreviewResult{
+		TargetResourceId: azure.KubernetesServiceRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind: AksTarget,
+	}
+
+	if serviceConfig.K8s.Fleet != nil {
+		result.Warnings = append(result.Warnings, "Fleet deployments are only previewed against the first cluster")
+	}
+
+	previewed := false
+
+	if serviceConfig.K8s.Environment != nil {
+		manifest, err := t.renderEnvironmentObjects(serviceConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		if manifest != "" {
+			diff, _, err := t.kubectl.DiffWithStdIn(ctx, manifest, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed comparing environment objects: %w", err)
+			}
+
+			result.Changes += diff
+		}
+	}
+
+	if serviceConfig.K8s.Helm != nil {
+		result.Warnings = append(result.Warnings, "Helm releases are not included in the preview")
+		previewed = true
+	}
+
+	if serviceConfig.K8s.Kustomize != nil {
+		progress.SetProgress(NewServiceProgress("Comparing k8s manifests with Kustomize"))
+		diff, err := t.previewKustomize(ctx, serviceConfig, result)
+		if err != nil {
+			return nil, fmt.Errorf("kustomize preview failed: %w", err)
+		}
+
+		result.Changes += diff
+		previewed = true
+	}
+
+	deploymentPath := t.getDeploymentPath(serviceConfig)
+	if _, err := os.Stat(deploymentPath); err == nil {
+		progress.SetProgress(NewServiceProgress("Comparing k8s manifests"))
+		diff, err := t.previewManifests(ctx, serviceConfig, deploymentPath, result)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Changes += diff
+		previewed = true
+	}
+
+	if !previewed {
+		return nil, errors.New("no deployment manifests found")
+	}
+
+	result.HasChanges = result.Changes != ""
+
+	return result, nil
+}
+
+// previewKustomize returns the differences between the kustomize manifests and the live state of the cluster
+// Kustomize edits modify the kustomization.yaml in place and are therefore not applied during a preview
+func (t *aksTarget) previewKustomize(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	result *ServicePreviewResult,
+) (string, error) {
+	if !t.featureManager.IsEnabled(featureKustomize) {
+		return "", fmt.Errorf(
+			"Kustomize support is not enabled. Run '%s' to enable it.",
+			alpha.GetEnableCommand(featureKustomize),
+		)
+	}
+
+	kustomizeDir, err := t.resolveKustomizeDir(serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	envFilePath, err := t.writeKustomizeEnv(serviceConfig, kustomizeDir)
+	if err != nil {
+		return "", err
+	}
+
+	if envFilePath != "" {
+		defer os.Remove(envFilePath)
+	}
+
+	if len(serviceConfig.K8s.Kustomize.Edits) > 0 {
+		result.Warnings = append(result.Warnings, "Kustomize edits are not applied in the preview")
+	}
+
+	diff, _, err := t.kubectl.DiffWithKustomize(ctx, kustomizeDir, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return diff, nil
+}
+
+// previewManifests returns the differences between the raw or templated yaml manifests and the live state
+// of the cluster
+func (t *aksTarget) previewManifests(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentPath string,
+	result *ServicePreviewResult,
+) (string, error) {
+	manifests, err := t.kubectl.ReadManifests(deploymentPath, serviceConfig.K8s.Envsubst)
+	if err != nil {
+		return "", err
+	}
+
+	if len(manifests) == 0 {
+		return "", nil
+	}
+
+	strategyType := serviceConfig.K8s.Deployment.Strategy.Type
+	if strategyType != "" && strategyType != AksDeploymentStrategyRolling {
+		result.Warnings = append(
+			result.Warnings,
+			fmt.Sprintf("Manifests are compared as defined, without the changes of the '%s' strategy", strategyType),
+		)
+	}
+
+	manifest := strings.Join(manifests, "\n---\n")
+
+	// Compare the manifests with the azd service label that is added when pruning is enabled
+	if serviceConfig.K8s.Prune {
+		objects, err := labelManifests(serviceConfig, manifests)
+		if err != nil {
+			return "", err
+		}
+
+		if manifest, err = marshalK8sObjects(objects); err != nil {
+			return "", err
+		}
+
+		result.Warnings = append(result.Warnings, "Resources that would be pruned are not included in the preview")
+	}
+
+	diff, _, err := t.kubectl.DiffWithStdIn(ctx, manifest, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed comparing kube manifests: %w", err)
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,168 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Preview_Manifests(t *testing.T) {
+	tests := map[string]struct {
+		exitCode        int
+		diff            string
+		strategy        AksDeploymentStrategyType
+		expectChanges   bool
+		expectedWarning string
+	}{
+		"Changes": {
+			exitCode:      1,
+			diff:          "-  replicas: 1\n+  replicas: 3\n",
+			expectChanges: true,
+		},
+		"NoChanges": {
+			exitCode: 0,
+		},
+		"Strategy": {
+			exitCode:        1,
+			diff:            "+  image: api:v2\n",
+			strategy:        AksDeploymentStrategyBlueGreen,
+			expectChanges:   true,
+			expectedWarning: "without the changes of the 'blueGreen' strategy",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Envsubst = true
+			serviceConfig.K8s.Deployment.Strategy.Type = test.strategy
+			env := createEnv()
+			env.DotenvSet("SERVICE_API_IMAGE_NAME", "api:v2")
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+			err = serviceTarget.Initialize(*mockContext.Context, serviceConfig)
+			require.NoError(t, err)
+
+			manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
+			require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+			err = os.WriteFile(
+				filepath.Join(manifestsDir, "deployment.yaml"),
+				[]byte("kind: Deployment\nimage: ${SERVICE_API_IMAGE_NAME}\n"),
+				osutil.PermissionFile,
+			)
+			require.NoError(t, err)
+
+			var diffInput string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl diff -f -")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				input, err := io.ReadAll(args.StdIn)
+				require.NoError(t, err)
+				diffInput = string(input)
+
+				result := exec.NewRunResult(test.exitCode, test.diff, "")
+				if test.exitCode != 0 {
+					return result, errors.New("exit code: 1")
+				}
+
+				return result, nil
+			})
+
+			// A preview must not modify the cluster
+			modified := []string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply") || strings.Contains(command, "kubectl create")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				modified = append(modified, strings.Join(args.Args, " "))
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			previewResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServicePreviewResult, error) {
+					return serviceTarget.(ServiceTargetPreviewer).Preview(
+						*mockContext.Context,
+						serviceConfig,
+						&ServicePackageResult{},
+						scope,
+						progress,
+					)
+				},
+			)
+
+			require.NoError(t, err)
+			require.Empty(t, modified)
+			require.Contains(t, diffInput, "image: api:v2")
+			require.Equal(t, AksTarget, previewResult.Kind)
+			require.Equal(t, test.expectChanges, previewResult.HasChanges)
+			require.Equal(t, test.diff, previewResult.Changes)
+
+			if test.expectedWarning == "" {
+				require.Empty(t, previewResult.Warnings)
+			} else {
+				require.Len(t, previewResult.Warnings, 1)
+				require.Contains(t, previewResult.Warnings[0], test.expectedWarning)
+			}
+		})
+	}
+}
+
+func Test_Preview_No_Manifests(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServicePreviewResult, error) {
+			return serviceTarget.(ServiceTargetPreviewer).Preview(
+				*mockContext.Context,
+				serviceConfig,
+				&ServicePackageResult{},
+				scope,
+				progress,
+			)
+		},
+	)
+
+	require.ErrorContains(t, err, "no deployment manifests found")
+}
+
+func Test_ServicePreviewResult_ToString(t *testing.T) {
+	noChanges := &ServicePreviewResult{Kind: AksTarget}
+	require.Equal(t, "  - No changes\n", noChanges.ToString("  "))
+
+	changes := &ServicePreviewResult{
+		Kind:       AksTarget,
+		HasChanges: true,
+		Changes:    "-  replicas: 1\n+  replicas: 3\n",
+	}
+	require.Equal(t, "    -  replicas: 1\n    +  replicas: 3\n", changes.ToString("  "))
+}
@@ -0,0 +1,391 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
+)
+
+// The rollout strategy used to deploy AKS services
+type AksDeploymentStrategyType string
+
+const (
+	// Updates the deployment in place using the k8s rolling update strategy (default)
+	AksDeploymentStrategyRolling AksDeploymentStrategyType = "rolling"
+	// Deploys the new version side-by-side with the active version and switches the service traffic
+	// once the new version is available
+	AksDeploymentStrategyBlueGreen AksDeploymentStrategyType = "blueGreen"
+	// Deploys the new version next to the stable version and routes a percentage of the traffic to it
+	AksDeploymentStrategyCanary AksDeploymentStrategyType = "canary"
+)
+
+const (
+	// The label used to identify the blue/green slot of a deployment
+	aksSlotLabel = "azd.azure.com/slot"
+	// The label used to identify the canary track of a deployment
+	aksTrackLabel = "azd.azure.com/track"
+
+	aksSlotBlue    = "blue"
+	aksSlotGreen   = "green"
+	aksTrackCanary = "canary"
+)
+
+// The AKS deployment strategy options
+type AksDeploymentStrategy struct {
+	// The rollout strategy type. Defaults to 'rolling'
+	Type AksDeploymentStrategyType `yaml:"type"`
+	// The percentage of traffic (1-100) routed to the canary deployment.
+	// A value of 100 promotes the canary to the stable deployment
+	TrafficPercentage int `yaml:"trafficPercentage"`
+}
+
+// k8sObject is a generic k8s object parsed from a manifest
+type k8sObject map[string]any
+
+func (o k8sObject) kind() string {
+	kind, _ := o["kind"].(string)
+	return kind
+}
+
+func (o k8sObject) name() string {
+	name, _ := o.nested("metadata")["name"].(string)
+	return name
+}
+
+func (o k8sObject) setName(name string) {
+	o.nested("metadata")["name"] = name
+}
+
+// nested returns the nested map at the specified path, creating any missing maps
+func (o k8sObject) nested(keys ...string) map[string]any {
+	current := map[string]any(o)
+	for _, key := range keys {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[key] = next
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+// parseK8sObjects parses all yaml documents within the manifests into generic k8s objects
+func parseK8sObjects(manifests []string) ([]k8sObject, error) {
+	objects := []k8sObject{}
+
+	for _, manifest := range manifests {
+		decoder := yaml.NewDecoder(strings.NewReader(manifest))
+		for {
+			var object map[string]any
+			err := decoder.Decode(&object)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing k8s manifest, %w", err)
+			}
+
+			if len(object) == 0 {
+				continue
+			}
+
+			objects = append(objects, k8sObject(object))
+		}
+	}
+
+	return objects, nil
+}
+
+// marshalK8sObjects marshals the k8s objects into a multi-document yaml manifest
+func marshalK8sObjects(objects []k8sObject) (string, error) {
+	documents := make([]string, 0, len(objects))
+	for _, object := range objects {
+		document, err := yaml.Marshal(object)
+		if err != nil {
+			return "", fmt.Errorf("failed marshalling k8s manifest, %w", err)
+		}
+
+		documents = append(documents, string(document))
+	}
+
+	return strings.Join(documents, "---\n"), nil
+}
+
+// findK8sObject finds the k8s object with the specified kind and name
+func findK8sObject(objects []k8sObject, kind string, name string) (k8sObject, int) {
+	for index, object := range objects {
+		if object.kind() == kind && object.name() == name {
+			return object, index
+		}
+	}
+
+	return nil, -1
+}
+
+// deployManifestsWithStrategy deploys the k8s manifests with the configured blue/green or canary strategy
+func (t *aksTarget) deployManifestsWithStrategy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentPath string,
+	task *async.Progress[ServiceProgress],
+) (*kubectl.Deployment, error) {
+	manifests, err := t.kubectl.ReadManifests(deploymentPath, serviceConfig.K8s.Envsubst)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading kube manifests: %w", err)
+	}
+
+	objects, err := parseK8sObjects(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.applyScheduling(serviceConfig, objects); err != nil {
+		return nil, err
+	}
+
+	deploymentName := t.getDeploymentName(serviceConfig)
+	deploymentObject, _ := findK8sObject(objects, "Deployment", deploymentName)
+	if deploymentObject == nil {
+		return nil, fmt.Errorf(
+			"the '%s' strategy requires a Deployment named '%s' within the k8s manifests",
+			serviceConfig.K8s.Deployment.Strategy.Type,
+			deploymentName,
+		)
+	}
+
+	switch serviceConfig.K8s.Deployment.Strategy.Type {
+	case AksDeploymentStrategyBlueGreen:
+		return t.deployBlueGreen(ctx, serviceConfig, objects, task)
+	case AksDeploymentStrategyCanary:
+		return t.deployCanary(ctx, serviceConfig, objects, task)
+	default:
+		return nil, fmt.Errorf("unsupported deployment strategy '%s'", serviceConfig.K8s.Deployment.Strategy.Type)
+	}
+}
+
+// deployBlueGreen deploys the new version into the inactive slot, waits until the new deployment is available,
+// switches the service traffic to the new slot and finally removes the previously active deployment
+func (t *aksTarget) deployBlueGreen(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	objects []k8sObject,
+	task *async.Progress[ServiceProgress],
+) (*kubectl.Deployment, error) {
+	deploymentName := t.getDeploymentName(serviceConfig)
+	serviceName := t.getServiceName(serviceConfig)
+
+	serviceObject, serviceIndex := findK8sObject(objects, "Service", serviceName)
+	if serviceObject == nil {
+		return nil, fmt.Errorf(
+			"the '%s' strategy requires a Service named '%s' within the k8s manifests",
+			AksDeploymentStrategyBlueGreen,
+			serviceName,
+		)
+	}
+
+	activeSlot, err := t.getActiveSlot(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	newSlot := aksSlotBlue
+	if activeSlot == aksSlotBlue {
+		newSlot = aksSlotGreen
+	}
+
+	slotDeploymentName := fmt.Sprintf("%s-%s", deploymentName, newSlot)
+	deploymentObject, _ := findK8sObject(objects, "Deployment", deploymentName)
+	deploymentObject.setName(slotDeploymentName)
+	deploymentObject.nested("spec", "selector", "matchLabels")[aksSlotLabel] = newSlot
+	deploymentObject.nested("spec", "template", "metadata", "labels")[aksSlotLabel] = newSlot
+
+	// The service is applied last to switch the traffic once the new slot is available
+	serviceObject.nested("spec", "selector")[aksSlotLabel] = newSlot
+	resources := append([]k8sObject{}, objects[:serviceIndex]...)
+	resources = append(resources, objects[serviceIndex+1:]...)
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Applying k8s manifests to '%s' slot", newSlot)))
+	if err := t.applyK8sObjects(ctx, resources); err != nil {
+		return nil, err
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying deployment in '%s' slot", newSlot)))
+	deployment, err := t.waitForDeployment(ctx, serviceConfig, slotDeploymentName, task)
+	if err != nil {
+		return nil, err
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Switching traffic to '%s' slot", newSlot)))
+	if err := t.applyK8sObjects(ctx, []k8sObject{serviceObject}); err != nil {
+		return nil, err
+	}
+
+	// The first blue/green deployment replaces the deployment created without a slot
+	previousDeploymentName := deploymentName
+	if activeSlot != "" {
+		previousDeploymentName = fmt.Sprintf("%s-%s", deploymentName, activeSlot)
+	}
+
+	task.SetProgress(NewServiceProgress("Removing previous deployment"))
+	if _, err := t.kubectl.Delete(ctx, kubectl.ResourceTypeDeployment, previousDeploymentName, nil); err != nil {
+		return nil, fmt.Errorf("failed removing previous deployment '%s': %w", previousDeploymentName, err)
+	}
+
+	return deployment, nil
+}
+
+// deployCanary deploys the new version as a canary deployment next to the stable deployment.
+// Traffic is split between the stable and canary pods through the shared service selector, with the canary
+// replicas scaled relative to the stable replicas to match the configured traffic percentage.
+// When no stable deployment exists yet or the traffic percentage is 100 the canary is promoted to stable.
+func (t *aksTarget) deployCanary(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	objects []k8sObject,
+	task *async.Progress[ServiceProgress],
+) (*kubectl.Deployment, error) {
+	trafficPercentage := serviceConfig.K8s.Deployment.Strategy.TrafficPercentage
+	if trafficPercentage < 1 || trafficPercentage > 100 {
+		return nil, fmt.Errorf(
+			"the canary traffic percentage must be between 1 and 100, found '%d'",
+			trafficPercentage,
+		)
+	}
+
+	deploymentName := t.getDeploymentName(serviceConfig)
+	canaryDeploymentName := fmt.Sprintf("%s-%s", deploymentName, aksTrackCanary)
+
+	stableDeployment, err := t.getDeployment(ctx, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+
+	if stableDeployment == nil || trafficPercentage == 100 {
+		task.SetProgress(NewServiceProgress("Promoting k8s manifests to stable deployment"))
+		if err := t.applyK8sObjects(ctx, objects); err != nil {
+			return nil, err
+		}
+
+		task.SetProgress(NewServiceProgress("Verifying deployment"))
+		deployment, err := t.waitForDeployment(ctx, serviceConfig, deploymentName, task)
+		if err != nil {
+			return nil, err
+		}
+
+		task.SetProgress(NewServiceProgress("Removing canary deployment"))
+		if _, err := t.kubectl.Delete(ctx, kubectl.ResourceTypeDeployment, canaryDeploymentName, nil); err != nil {
+			return nil, fmt.Errorf("failed removing canary deployment '%s': %w", canaryDeploymentName, err)
+		}
+
+		return deployment, nil
+	}
+
+	stableReplicas := stableDeployment.Spec.Replicas
+	canaryReplicas := int(math.Round(float64(stableReplicas*trafficPercentage) / float64(100-trafficPercentage)))
+	if canaryReplicas < 1 {
+		canaryReplicas = 1
+	}
+
+	deploymentObject, deploymentIndex := findK8sObject(objects, "Deployment", deploymentName)
+	deploymentObject.setName(canaryDeploymentName)
+	deploymentObject.nested("spec")["replicas"] = canaryReplicas
+	deploymentObject.nested("spec", "selector", "matchLabels")[aksTrackLabel] = aksTrackCanary
+	deploymentObject.nested("spec", "template", "metadata", "labels")[aksTrackLabel] = aksTrackCanary
+
+	// The stable deployment is left untouched while the canary is running
+	resources := append([]k8sObject{}, objects[:deploymentIndex]...)
+	resources = append(resources, objects[deploymentIndex+1:]...)
+	resources = append(resources, deploymentObject)
+
+	task.SetProgress(NewServiceProgress(
+		fmt.Sprintf("Applying k8s manifests to canary deployment (%d%% traffic)", trafficPercentage),
+	))
+	if err := t.applyK8sObjects(ctx, resources); err != nil {
+		return nil, err
+	}
+
+	task.SetProgress(NewServiceProgress("Verifying canary deployment"))
+	return t.waitForDeployment(ctx, serviceConfig, canaryDeploymentName, task)
+}
+
+// applyK8sObjects applies the k8s objects to the cluster
+func (t *aksTarget) applyK8sObjects(ctx context.Context, objects []k8sObject) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	manifest, err := marshalK8sObjects(objects)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, manifest, nil); err != nil {
+		return withKubectlErrorSuggestion(fmt.Errorf("failed applying kube manifests: %w", err))
+	}
+
+	return nil
+}
+
+// getActiveSlot returns the blue/green slot currently receiving the service traffic
+// An empty slot is returned when the service does not exist or has not been deployed with a slot
+func (t *aksTarget) getActiveSlot(ctx context.Context, serviceName string) (string, error) {
+	res, err := t.kubectl.Exec(
+		ctx,
+		&kubectl.KubeCliFlags{Output: kubectl.OutputTypeJson},
+		"get", string(kubectl.ResourceTypeService), serviceName, "--ignore-not-found",
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed getting service '%s': %w", serviceName, err)
+	}
+
+	if strings.TrimSpace(res.Stdout) == "" {
+		return "", nil
+	}
+
+	var service struct {
+		Spec struct {
+			Selector map[string]string `json:"selector"`
+		} `json:"spec"`
+	}
+
+	if err := json.Unmarshal([]byte(res.Stdout), &service); err != nil {
+		return "", fmt.Errorf("failed unmarshalling service '%s', %w", serviceName, err)
+	}
+
+	return service.Spec.Selector[aksSlotLabel], nil
+}
+
+// getDeployment returns the deployment with the specified name or nil when the deployment does not exist
+func (t *aksTarget) getDeployment(ctx context.Context, deploymentName string) (*kubectl.Deployment, error) {
+	res, err := t.kubectl.Exec(
+		ctx,
+		&kubectl.KubeCliFlags{Output: kubectl.OutputTypeJson},
+		"get", string(kubectl.ResourceTypeDeployment), deploymentName, "--ignore-not-found",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting deployment '%s': %w", deploymentName, err)
+	}
+
+	if strings.TrimSpace(res.Stdout) == "" {
+		return nil, nil
+	}
+
+	var deployment kubectl.Deployment
+	if err := json.Unmarshal([]byte(res.Stdout), &deployment); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling deployment '%s', %w", deploymentName, err)
+	}
+
+	return &deployment, nil
+}
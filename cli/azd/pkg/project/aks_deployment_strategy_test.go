@@ -0,0 +1,315 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const strategyTestManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: api
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      containers:
+        - name: api
+          image: REGISTRY.azurecr.io/api:azd-deploy-1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+spec:
+  type: ClusterIP
+  selector:
+    app: api
+  ports:
+    - port: 80
+      targetPort: 3000
+`
+
+func Test_Deploy_BlueGreen(t *testing.T) {
+	tests := map[string]struct {
+		activeSlot       string
+		expectedSlot     string
+		expectedDeletion string
+	}{
+		"FirstDeployment": {activeSlot: "", expectedSlot: "blue", expectedDeletion: "api"},
+		"BlueToGreen":     {activeSlot: "blue", expectedSlot: "green", expectedDeletion: "api-blue"},
+		"GreenToBlue":     {activeSlot: "green", expectedSlot: "blue", expectedDeletion: "api-green"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext, serviceConfig, serviceTarget := setupStrategyTest(t, AksDeploymentStrategy{
+				Type: AksDeploymentStrategyBlueGreen,
+			})
+
+			selector := map[string]string{"app": "api"}
+			if test.activeSlot != "" {
+				selector[aksSlotLabel] = test.activeSlot
+			}
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get svc api --ignore-not-found")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				service := map[string]any{"spec": map[string]any{"selector": selector}}
+				jsonBytes, _ := json.Marshal(service)
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			setupStrategyDeploymentMock(mockContext, "api-"+test.expectedSlot, 3)
+			applied := captureStrategyApplies(mockContext)
+			deleted := captureStrategyDeletes(mockContext)
+
+			deployResult, err := deployStrategyTest(t, mockContext, serviceConfig, serviceTarget)
+			require.NoError(t, err)
+			require.NotNil(t, deployResult)
+
+			// The new slot deployment is applied before the traffic is switched by the service
+			require.Len(t, *applied, 2)
+			require.Len(t, (*applied)[0], 1)
+			deployment := (*applied)[0][0]
+			require.Equal(t, "Deployment", deployment.kind())
+			require.Equal(t, "api-"+test.expectedSlot, deployment.name())
+			require.Equal(t, test.expectedSlot, deployment.nested("spec", "selector", "matchLabels")[aksSlotLabel])
+			require.Equal(t, test.expectedSlot, deployment.nested("spec", "template", "metadata", "labels")[aksSlotLabel])
+
+			require.Len(t, (*applied)[1], 1)
+			service := (*applied)[1][0]
+			require.Equal(t, "Service", service.kind())
+			require.Equal(t, test.expectedSlot, service.nested("spec", "selector")[aksSlotLabel])
+
+			require.Equal(t, []string{test.expectedDeletion}, *deleted)
+		})
+	}
+}
+
+func Test_Deploy_Canary(t *testing.T) {
+	t.Run("SplitTraffic", func(t *testing.T) {
+		mockContext, serviceConfig, serviceTarget := setupStrategyTest(t, AksDeploymentStrategy{
+			Type:              AksDeploymentStrategyCanary,
+			TrafficPercentage: 25,
+		})
+
+		setupStrategyStableDeploymentMock(mockContext, 3)
+		setupStrategyDeploymentMock(mockContext, "api-canary", 1)
+		applied := captureStrategyApplies(mockContext)
+		deleted := captureStrategyDeletes(mockContext)
+
+		deployResult, err := deployStrategyTest(t, mockContext, serviceConfig, serviceTarget)
+		require.NoError(t, err)
+		require.NotNil(t, deployResult)
+
+		// The stable deployment is untouched and the canary runs 1 replica next to 3 stable replicas
+		require.Len(t, *applied, 1)
+		require.Len(t, (*applied)[0], 2)
+		require.Equal(t, "Service", (*applied)[0][0].kind())
+
+		canary := (*applied)[0][1]
+		require.Equal(t, "api-canary", canary.name())
+		require.Equal(t, 1, canary.nested("spec")["replicas"])
+		require.Equal(t, aksTrackCanary, canary.nested("spec", "selector", "matchLabels")[aksTrackLabel])
+		require.Equal(t, "api", canary.nested("spec", "template", "metadata", "labels")["app"])
+		require.Equal(t, aksTrackCanary, canary.nested("spec", "template", "metadata", "labels")[aksTrackLabel])
+
+		require.Empty(t, *deleted)
+	})
+
+	t.Run("Promote", func(t *testing.T) {
+		mockContext, serviceConfig, serviceTarget := setupStrategyTest(t, AksDeploymentStrategy{
+			Type:              AksDeploymentStrategyCanary,
+			TrafficPercentage: 100,
+		})
+
+		setupStrategyStableDeploymentMock(mockContext, 3)
+		setupStrategyDeploymentMock(mockContext, "api", 3)
+		applied := captureStrategyApplies(mockContext)
+		deleted := captureStrategyDeletes(mockContext)
+
+		_, err := deployStrategyTest(t, mockContext, serviceConfig, serviceTarget)
+		require.NoError(t, err)
+
+		require.Len(t, *applied, 1)
+		require.Len(t, (*applied)[0], 2)
+		require.Equal(t, "api", (*applied)[0][0].name())
+		require.Equal(t, []string{"api-canary"}, *deleted)
+	})
+
+	t.Run("InvalidTrafficPercentage", func(t *testing.T) {
+		mockContext, serviceConfig, serviceTarget := setupStrategyTest(t, AksDeploymentStrategy{
+			Type: AksDeploymentStrategyCanary,
+		})
+
+		_, err := deployStrategyTest(t, mockContext, serviceConfig, serviceTarget)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "traffic percentage must be between 1 and 100")
+	})
+}
+
+func Test_Deploy_Strategy_Missing_Deployment(t *testing.T) {
+	mockContext, serviceConfig, serviceTarget := setupStrategyTest(t, AksDeploymentStrategy{
+		Type: AksDeploymentStrategyBlueGreen,
+	})
+	serviceConfig.K8s.Deployment.Name = "web"
+
+	_, err := deployStrategyTest(t, mockContext, serviceConfig, serviceTarget)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "requires a Deployment named 'web'")
+}
+
+func setupStrategyTest(
+	t *testing.T,
+	strategy AksDeploymentStrategy,
+) (*mocks.MockContext, *ServiceConfig, ServiceTarget) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Deployment.Strategy = strategy
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
+	require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+	err = os.WriteFile(filepath.Join(manifestsDir, "app.yaml"), []byte(strategyTestManifest), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	return mockContext, serviceConfig, serviceTarget
+}
+
+func deployStrategyTest(
+	t *testing.T,
+	mockContext *mocks.MockContext,
+	serviceConfig *ServiceConfig,
+	serviceTarget ServiceTarget,
+) (*ServiceDeployResult, error) {
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	return logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+	})
+}
+
+// setupStrategyDeploymentMock mocks the deployment list used to wait for the specified deployment
+func setupStrategyDeploymentMock(mockContext *mocks.MockContext, name string, replicas int) {
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get deployment -o json")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		deploymentList := createK8sResourceList(createStrategyDeployment(name, replicas))
+		jsonBytes, _ := json.Marshal(deploymentList)
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+}
+
+// setupStrategyStableDeploymentMock mocks the existing stable deployment
+func setupStrategyStableDeploymentMock(mockContext *mocks.MockContext, replicas int) {
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get deployment api --ignore-not-found")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		jsonBytes, _ := json.Marshal(createStrategyDeployment("api", replicas))
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+}
+
+func createStrategyDeployment(name string, replicas int) *kubectl.Deployment {
+	return &kubectl.Deployment{
+		Resource: kubectl.Resource{
+			ApiVersion: "apps/v1",
+			Kind:       "Deployment",
+			Metadata: kubectl.ResourceMetadata{
+				Name: name,
+			},
+		},
+		Spec: kubectl.DeploymentSpec{
+			Replicas: replicas,
+		},
+		Status: kubectl.DeploymentStatus{
+			AvailableReplicas: replicas,
+			ReadyReplicas:     replicas,
+			Replicas:          replicas,
+			UpdatedReplicas:   replicas,
+		},
+	}
+}
+
+// captureStrategyApplies captures the k8s objects of each kubectl apply
+func captureStrategyApplies(mockContext *mocks.MockContext) *[][]k8sObject {
+	applied := [][]k8sObject{}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -") && !strings.Contains(command, "--dry-run")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		content, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		objects := []k8sObject{}
+		decoder := yaml.NewDecoder(strings.NewReader(string(content)))
+		for {
+			var object map[string]any
+			if err := decoder.Decode(&object); err != nil {
+				break
+			}
+
+			// Ignore the namespace applied before the manifests
+			if k8sObject(object).kind() != "Namespace" && len(object) > 0 {
+				objects = append(objects, k8sObject(object))
+			}
+		}
+
+		if len(objects) > 0 {
+			applied = append(applied, objects)
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	return &applied
+}
+
+// captureStrategyDeletes captures the names of all deleted deployments
+func captureStrategyDeletes(mockContext *mocks.MockContext) *[]string {
+	deleted := []string{}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl delete deployment")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		deleted = append(deleted, args.Args[2])
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	return &deleted
+}
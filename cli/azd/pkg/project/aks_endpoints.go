@@ -0,0 +1,82 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// saveEndpointDetails stores all endpoints of the service in the environment, both as a json array in
+// SERVICE_<NAME>_ENDPOINTS and as SERVICE_<NAME>_ENDPOINT_<INDEX>_<URL|PROTOCOL|HOST|PORT|PATH|SOURCE> values,
+// and removes the values of the endpoints that are no longer discovered.
+func (t *aksTarget) saveEndpointDetails(serviceConfig *ServiceConfig, details []ServiceEndpoint) error {
+	detailsJson, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed marshalling endpoints, %w", err)
+	}
+
+	t.env.SetServiceProperty(serviceConfig.Name, "ENDPOINTS", string(detailsJson))
+	for index, detail := range details {
+		for property, value := range endpointProperties(detail) {
+			t.env.SetServiceProperty(serviceConfig.Name, fmt.Sprintf("ENDPOINT_%d_%s", index, property), value)
+		}
+	}
+
+	for index := len(details); ; index++ {
+		if t.env.GetServiceProperty(serviceConfig.Name, fmt.Sprintf("ENDPOINT_%d_URL", index)) == "" {
+			break
+		}
+
+		for property := range endpointProperties(ServiceEndpoint{}) {
+			t.env.DeleteServiceProperty(serviceConfig.Name, fmt.Sprintf("ENDPOINT_%d_%s", index, property))
+		}
+	}
+
+	return nil
+}
+
+// endpointProperties returns the environment values of the endpoint by property name
+func endpointProperties(detail ServiceEndpoint) map[string]string {
+	return map[string]string{
+		"URL":      detail.Url,
+		"PROTOCOL": detail.Protocol,
+		"HOST":     detail.Host,
+		"PORT":     strconv.Itoa(detail.Port),
+		"PATH":     detail.Path,
+		"SOURCE":   detail.Source,
+	}
+}
+
+// ingressPaths returns the paths of the ingress rule that endpoints are reported for
+// The configured relative path takes precedence. Otherwise each path of the rule is reported, with the root path
+// reported last as the most publicly exposed endpoint. Regular expression paths, ex) /api(/|$)(.*), are skipped
+// since they do not identify a single url.
+func ingressPaths(serviceConfig *ServiceConfig, ingress *kubectl.Ingress, ruleIndex int) []string {
+	if serviceConfig.K8s.Ingress.RelativePath != "" {
+		return []string{serviceConfig.K8s.Ingress.RelativePath}
+	}
+
+	paths := []string{}
+	hasRoot := false
+	if ruleIndex < len(ingress.Spec.Rules) {
+		for _, path := range ingress.Spec.Rules[ruleIndex].Http.Paths {
+			switch {
+			case path.Path == "" || path.Path == "/":
+				hasRoot = true
+			case strings.ContainsAny(path.Path, "()[]*$^|"):
+				continue
+			default:
+				paths = appendUnique(paths, path.Path)
+			}
+		}
+	}
+
+	if hasRoot || len(paths) == 0 {
+		paths = append(paths, "")
+	}
+
+	return paths
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_EndpointDetails(t *testing.T) {
+	mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, map[string]string{
+		"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n",
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get ing")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		host := "api.contoso.com"
+		ingress := &kubectl.Ingress{
+			Resource: kubectl.Resource{
+				Metadata: kubectl.ResourceMetadata{Name: "api-ingress", Namespace: "api-namespace"},
+			},
+			Spec: kubectl.IngressSpec{
+				Tls: []kubectl.IngressTls{{Hosts: []string{host}}},
+				Rules: []kubectl.IngressRule{
+					{
+						Host: &host,
+						Http: kubectl.IngressRuleHttp{
+							Paths: []kubectl.IngressPath{
+								{Path: "/", PathType: "Prefix"},
+								{Path: "/api", PathType: "Prefix"},
+								{Path: "/admin(/|$)(.*)", PathType: "ImplementationSpecific"},
+								{Path: "/admin", PathType: "Exact"},
+							},
+						},
+					},
+				},
+			},
+			Status: kubectl.IngressStatus{
+				LoadBalancer: kubectl.LoadBalancer{Ingress: []kubectl.LoadBalancerIngress{{Ip: "1.1.1.1"}}},
+			},
+		}
+		jsonBytes, _ := json.Marshal(createK8sResourceList(ingress))
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	aksTarget := serviceTarget.(*aksTarget)
+	// The values of endpoints that are no longer discovered are removed
+	aksTarget.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_4_URL", "http://stale.contoso.com")
+	aksTarget.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_4_PORT", "80")
+
+	deployResult, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"http://10.10.10.10:80 (Service: api-service, Type: ClusterIP)",
+		"https://api.contoso.com/api (Ingress, Type: LoadBalancer)",
+		"https://api.contoso.com/admin (Ingress, Type: LoadBalancer)",
+		"https://api.contoso.com (Ingress, Type: LoadBalancer)",
+	}, deployResult.Endpoints)
+
+	require.Len(t, deployResult.EndpointDetails, 4)
+	require.Equal(t, ServiceEndpoint{
+		Url:      "http://10.10.10.10:80",
+		Kind:     ServiceEndpointKindService,
+		Protocol: "http",
+		Host:     "10.10.10.10",
+		Port:     80,
+		Source:   "Service",
+		Name:     "api-service",
+		Type:     "ClusterIP",
+		Internal: true,
+	}, deployResult.EndpointDetails[0])
+	require.Equal(t, ServiceEndpoint{
+		Url:      "https://api.contoso.com/api",
+		Kind:     ServiceEndpointKindIngress,
+		Protocol: "https",
+		Host:     "api.contoso.com",
+		Port:     443,
+		Path:     "/api",
+		Source:   "Ingress",
+		Type:     "LoadBalancer",
+	}, deployResult.EndpointDetails[1])
+
+	env := aksTarget.env
+	require.Equal(t, "https://api.contoso.com", env.GetServiceProperty(serviceConfig.Name, "ENDPOINT_URL"))
+	require.Equal(t, "https://api.contoso.com/admin", env.GetServiceProperty(serviceConfig.Name, "ENDPOINT_2_URL"))
+	require.Equal(t, "/admin", env.GetServiceProperty(serviceConfig.Name, "ENDPOINT_2_PATH"))
+	require.Equal(t, "443", env.GetServiceProperty(serviceConfig.Name, "ENDPOINT_2_PORT"))
+	require.Equal(t, "Ingress", env.GetServiceProperty(serviceConfig.Name, "ENDPOINT_2_SOURCE"))
+	require.Empty(t, env.GetServiceProperty(serviceConfig.Name, "ENDPOINT_4_URL"))
+	require.Empty(t, env.GetServiceProperty(serviceConfig.Name, "ENDPOINT_4_PORT"))
+
+	endpoints := []ServiceEndpoint{}
+	require.NoError(t, json.Unmarshal([]byte(env.GetServiceProperty(serviceConfig.Name, "ENDPOINTS")), &endpoints))
+	require.Equal(t, deployResult.EndpointDetails, endpoints)
+}
@@ -0,0 +1,116 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
+)
+
+// The AKS environment options
+// When configured, the selected azd environment values are written to a generated ConfigMap and Secret in the
+// namespace before the k8s resources of the service are deployed, ex) infrastructure outputs
+type AksEnvironmentOptions struct {
+	// The azd environment values written to the generated ConfigMap
+	ConfigMap *AksEnvironmentObjectOptions `yaml:"configMap"`
+	// The azd environment values written to the generated Secret
+	Secret *AksEnvironmentObjectOptions `yaml:"secret"`
+}
+
+// The options of a k8s object generated from the azd environment
+type AksEnvironmentObjectOptions struct {
+	// The name of the k8s object. Defaults to '<service>-config' for the ConfigMap and '<service>-secrets' for the Secret
+	Name string `yaml:"name"`
+	// The azd environment keys to include, ex) AZURE_STORAGE_ENDPOINT
+	Keys []string `yaml:"keys"`
+}
+
+// deployEnvironmentObjects applies the ConfigMap and Secret generated from the azd environment so that they are
+// available to the workloads deployed from the helm charts, kustomize or manifests
+func (t *aksTarget) deployEnvironmentObjects(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	progress *async.Progress[ServiceProgress],
+) error {
+	manifest, err := t.renderEnvironmentObjects(serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	if manifest == "" {
+		return nil
+	}
+
+	progress.SetProgress(NewServiceProgress("Applying environment ConfigMap and Secret"))
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, manifest, nil); err != nil {
+		return fmt.Errorf("failed applying environment objects, %w", err)
+	}
+
+	return nil
+}
+
+// renderEnvironmentObjects returns the yaml manifest of the ConfigMap and Secret generated from the azd environment
+func (t *aksTarget) renderEnvironmentObjects(serviceConfig *ServiceConfig) (string, error) {
+	options := serviceConfig.K8s.Environment
+	namespace := t.getK8sNamespace(serviceConfig)
+	objects := []any{}
+
+	if options.ConfigMap != nil && len(options.ConfigMap.Keys) > 0 {
+		values, err := t.environmentValues(options.ConfigMap.Keys)
+		if err != nil {
+			return "", err
+		}
+
+		name := options.ConfigMap.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-config", serviceConfig.Name)
+		}
+
+		objects = append(objects, kubectl.NewConfigMap(name, namespace, values))
+	}
+
+	if options.Secret != nil && len(options.Secret.Keys) > 0 {
+		values, err := t.environmentValues(options.Secret.Keys)
+		if err != nil {
+			return "", err
+		}
+
+		name := options.Secret.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-secrets", serviceConfig.Name)
+		}
+
+		objects = append(objects, kubectl.NewOpaqueSecret(name, namespace, values))
+	}
+
+	documents := make([]string, 0, len(objects))
+	for _, object := range objects {
+		document, err := yaml.Marshal(object)
+		if err != nil {
+			return "", fmt.Errorf("failed marshalling environment object, %w", err)
+		}
+
+		documents = append(documents, string(document))
+	}
+
+	return strings.Join(documents, "---\n"), nil
+}
+
+// environmentValues returns the values of the specified azd environment keys
+// Missing keys fail the deployment instead of silently generating empty values
+func (t *aksTarget) environmentValues(keys []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, key := range keys {
+		value, has := t.env.LookupEnv(key)
+		if !has {
+			return nil, fmt.Errorf("environment key '%s' is not set", key)
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,119 @@
+package project
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Deploy_Environment_Objects(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	applied := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		input, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		applied = append(applied, string(input))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Environment = &AksEnvironmentOptions{
+		ConfigMap: &AksEnvironmentObjectOptions{
+			Keys: []string{"AZURE_STORAGE_ENDPOINT"},
+		},
+		Secret: &AksEnvironmentObjectOptions{
+			Name: "api-db",
+			Keys: []string{"DB_CONNECTION_STRING"},
+		},
+	}
+	env := createEnv()
+	env.DotenvSet("AZURE_STORAGE_ENDPOINT", "https://storage.blob.core.windows.net/")
+	env.DotenvSet("DB_CONNECTION_STRING", "Server=db;Password=secret")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+	})
+	require.NoError(t, err)
+
+	// The generated objects are applied before the manifests of the service
+	require.NotEmpty(t, applied)
+	documents := strings.Split(applied[0], "---\n")
+	require.Len(t, documents, 2)
+
+	var configMap kubectl.ConfigMap
+	err = yaml.Unmarshal([]byte(documents[0]), &configMap)
+	require.NoError(t, err)
+	require.Equal(t, "ConfigMap", configMap.Kind)
+	require.Equal(t, "api-config", configMap.Metadata.Name)
+	require.Equal(t, "Test-App", configMap.Metadata.Namespace)
+	require.Equal(t, map[string]string{
+		"AZURE_STORAGE_ENDPOINT": "https://storage.blob.core.windows.net/",
+	}, configMap.Data)
+
+	var secret kubectl.Secret
+	err = yaml.Unmarshal([]byte(documents[1]), &secret)
+	require.NoError(t, err)
+	require.Equal(t, "Secret", secret.Kind)
+	require.Equal(t, "api-db", secret.Metadata.Name)
+	require.Equal(t, kubectl.SecretTypeOpaque, secret.Type)
+
+	value, err := base64.StdEncoding.DecodeString(secret.Data["DB_CONNECTION_STRING"])
+	require.NoError(t, err)
+	require.Equal(t, "Server=db;Password=secret", string(value))
+}
+
+func Test_Deploy_Environment_Objects_Missing_Key(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Environment = &AksEnvironmentOptions{
+		ConfigMap: &AksEnvironmentObjectOptions{
+			Keys: []string{"MISSING_KEY"},
+		},
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+	})
+
+	require.ErrorContains(t, err, "environment key 'MISSING_KEY' is not set")
+}
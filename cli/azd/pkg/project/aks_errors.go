@@ -0,0 +1,50 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// withKubectlErrorSuggestion adds a suggestion for resolving the classified kubectl failure to the error
+// ex) a forbidden error suggests the roles required for the cluster instead of only reporting the kubectl output
+func withKubectlErrorSuggestion(err error) error {
+	var suggestionErr *internal.ErrorWithSuggestion
+	if err == nil || errors.As(err, &suggestionErr) {
+		return err
+	}
+
+	var suggestion string
+	switch {
+	case errors.Is(err, kubectl.ErrApplyConflict):
+		suggestion = fmt.Sprintf(
+			"The conflicting fields are managed by another field manager. Remove the fields from the manifests, "+
+				"or set 'k8s.serverSideApply.forceConflicts' to true in azure.yaml to transfer their ownership to '%s'",
+			kubectl.DefaultFieldManager,
+		)
+	case errors.Is(err, kubectl.ErrForbidden):
+		suggestion = "Ensure the current principal has been granted rights to the AKS cluster, such as the " +
+			"'Azure Kubernetes Service RBAC Writer' role for the namespace of the service. " +
+			"Run 'azd auth login' to sign in with another account"
+	case errors.Is(err, kubectl.ErrInvalidResource):
+		suggestion = "Fix the k8s manifests reported in the error. Set 'k8s.validation' to 'server' in azure.yaml " +
+			"to validate the manifests against the cluster before any of them is applied"
+	case errors.Is(err, kubectl.ErrResourceNotFound):
+		suggestion = "Ensure the resources and kinds referenced by the manifests exist in the cluster, such as the " +
+			"custom resource definitions installed by an operator"
+	case errors.Is(err, kubectl.ErrServerBusy):
+		suggestion = "The AKS API server kept failing with transient errors after the command was retried. " +
+			"Increase 'k8s.retry.maxRetries' in azure.yaml, or consider the Standard pricing tier of the cluster " +
+			"for a higher API server capacity"
+	case errors.Is(err, kubectl.ErrClusterUnreachable):
+		suggestion = "Ensure the AKS cluster is running and its API server can be reached from this machine. " +
+			"For private clusters, set 'k8s.runCommand' to true in azure.yaml to run the commands through " +
+			"the AKS run command API"
+	default:
+		return err
+	}
+
+	return &internal.ErrorWithSuggestion{Err: err, Suggestion: suggestion}
+}
@@ -0,0 +1,78 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_KubectlErrors(t *testing.T) {
+	tests := map[string]struct {
+		stderr             string
+		expectedErr        error
+		expectedSuggestion string
+	}{
+		"Forbidden": {
+			stderr: `Error from server (Forbidden): error when retrieving current configuration of: ` +
+				`deployments.apps "api" is forbidden: User "dev@contoso.com" cannot get resource "deployments"`,
+			expectedErr:        kubectl.ErrForbidden,
+			expectedSuggestion: "Azure Kubernetes Service RBAC Writer",
+		},
+		"Invalid": {
+			stderr: `The Deployment "api" is invalid: spec.template.metadata.labels: Invalid value` + "\n" +
+				`error: error validating "deployment.yaml": error validating data: unknown field "replica"`,
+			expectedErr:        kubectl.ErrInvalidResource,
+			expectedSuggestion: "k8s.validation",
+		},
+		"NotFound": {
+			stderr: `error: resource mapping not found for name: "api" namespace: "" from "deployment.yaml": ` +
+				`no matches for kind "Rollout" in version "argoproj.io/v1alpha1"`,
+			expectedErr:        kubectl.ErrResourceNotFound,
+			expectedSuggestion: "custom resource definitions",
+		},
+		"Unreachable": {
+			stderr: `Unable to connect to the server: dial tcp: lookup cluster1-dns.hcp.eastus.azmk8s.io: ` +
+				`no such host`,
+			expectedErr:        kubectl.ErrClusterUnreachable,
+			expectedSuggestion: "k8s.runCommand",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil)
+			err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+			require.NoError(t, err)
+
+			manifestPath := writeTestDeploymentManifest(t, serviceConfig)
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply -f "+manifestPath)
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(1, "", test.stderr), errors.New("exit code: 1")
+			})
+
+			_, err = deployTestService(t, mockContext, serviceTarget, serviceConfig)
+			require.ErrorIs(t, err, test.expectedErr)
+
+			var errWithSuggestion *internal.ErrorWithSuggestion
+			require.True(t, errors.As(err, &errWithSuggestion))
+			require.Contains(t, errWithSuggestion.Suggestion, test.expectedSuggestion)
+		})
+	}
+}
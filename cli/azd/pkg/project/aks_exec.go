@@ -0,0 +1,87 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The command executed when no command is specified, available in most container images
+var aksDefaultExecCommand = []string{"/bin/sh"}
+
+// Executes the command in a pod of the service deployment, equivalent to 'kubectl exec -it <pod> -- <command>'
+// A ready pod of the deployment is selected unless the pod is specified as the instance.
+func (t *aksTarget) Exec(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options ServiceExecOptions,
+) error {
+	namespace, err := t.useServiceCluster(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return err
+	}
+
+	pods, err := t.getServiceDeploymentPods(ctx, serviceConfig, namespace)
+	if err != nil {
+		return err
+	}
+
+	pod, err := selectExecPod(pods, options.Instance)
+	if err != nil {
+		return err
+	}
+
+	command := options.Command
+	if len(command) == 0 {
+		command = aksDefaultExecCommand
+	}
+
+	log.Printf("executing '%s' in pod '%s'", strings.Join(command, " "), pod.Metadata.Name)
+
+	return t.kubectl.ExecInPod(ctx, pod.Metadata.Name, command, &kubectl.ExecInPodOptions{
+		Container: options.Container,
+		Tty:       options.Tty,
+	}, nil)
+}
+
+// selectExecPod returns the pod with the specified name, or the first ready pod when the name is empty
+// Running pods that are not ready, ex) failing their readiness probe, are selected when no pod is ready.
+func selectExecPod(pods []kubectl.Pod, name string) (*kubectl.Pod, error) {
+	if name != "" {
+		for _, pod := range pods {
+			if pod.Metadata.Name == name {
+				return &pod, nil
+			}
+		}
+
+		return nil, fmt.Errorf("pod '%s' not found, available pods: %s", name, podNames(pods))
+	}
+
+	for _, pod := range pods {
+		if pod.IsReady() {
+			return &pod, nil
+		}
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Phase == "Running" {
+			return &pod, nil
+		}
+	}
+
+	return nil, fmt.Errorf("none of the pods %s are running", podNames(pods))
+}
+
+func podNames(pods []kubectl.Pod) string {
+	names := []string{}
+	for _, pod := range pods {
+		names = append(names, fmt.Sprintf("'%s'", pod.Metadata.Name))
+	}
+
+	return strings.Join(names, ", ")
+}
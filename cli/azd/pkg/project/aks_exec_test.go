@@ -0,0 +1,113 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Exec_Aks(t *testing.T) {
+	tests := map[string]struct {
+		options       ServiceExecOptions
+		expectedArgs  []string
+		expectedError string
+	}{
+		"DefaultShell": {
+			options: ServiceExecOptions{Tty: true},
+			expectedArgs: []string{
+				"exec", "-i", "-t", "api-5d8f7b-fghij", "--kubeconfig", filepath.Join(".azure", "test", "kube", "config"),
+				"--", "/bin/sh",
+			},
+		},
+		"Command": {
+			options: ServiceExecOptions{
+				Command:   []string{"env"},
+				Instance:  "api-5d8f7b-abcde",
+				Container: "app",
+			},
+			expectedArgs: []string{
+				"exec", "-i", "api-5d8f7b-abcde", "-c", "app",
+				"--kubeconfig", filepath.Join(".azure", "test", "kube", "config"), "--", "env",
+			},
+		},
+		"PodNotFound": {
+			options:       ServiceExecOptions{Instance: "worker-7c9d6e-klmno"},
+			expectedError: "pod 'worker-7c9d6e-klmno' not found, available pods: 'api-5d8f7b-abcde', 'api-5d8f7b-fghij'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get deployment")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				deployments := kubectl.List[kubectl.Deployment]{
+					Items: []kubectl.Deployment{
+						{Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "api"}}},
+					},
+				}
+				jsonBytes, _ := json.Marshal(deployments)
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get pods")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				pods := kubectl.List[kubectl.Pod]{
+					Items: []kubectl.Pod{
+						// The first pod is failing its readiness probe
+						createPod("api-5d8f7b-abcde", "Running", kubectl.ContainerState{}, false),
+						createPod("api-5d8f7b-fghij", "Running", kubectl.ContainerState{}, true),
+						createPod("worker-7c9d6e-klmno", "Running", kubectl.ContainerState{}, true),
+					},
+				}
+				jsonBytes, _ := json.Marshal(pods)
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			var execArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl exec")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				execArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil)
+			err = serviceTarget.Initialize(*mockContext.Context, serviceConfig)
+			require.NoError(t, err)
+
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			err = serviceTarget.(ServiceTargetExecutor).Exec(*mockContext.Context, serviceConfig, scope, test.options)
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, execArgs.Interactive)
+			require.Equal(t, test.expectedArgs, execArgs.Args)
+		})
+	}
+}
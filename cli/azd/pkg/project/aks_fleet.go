@@ -0,0 +1,350 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The AKS fleet options used to deploy a service to multiple AKS clusters
+type AksFleetOptions struct {
+	// The AKS clusters the service is deployed to
+	Clusters []AksFleetCluster `yaml:"clusters"`
+	// The Azure Kubernetes Fleet Manager resource whose member clusters the service is deployed to
+	Manager *AksFleetManagerOptions `yaml:"manager"`
+}
+
+// An AKS cluster of the fleet
+type AksFleetCluster struct {
+	// The name of the AKS cluster
+	Name osutil.ExpandableString `yaml:"name"`
+	// The resource group of the AKS cluster. Defaults to the resource group of the service
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup"`
+	// The subscription of the AKS cluster. Defaults to the subscription of the environment
+	SubscriptionId osutil.ExpandableString `yaml:"subscriptionId"`
+}
+
+// The Azure Kubernetes Fleet Manager options
+type AksFleetManagerOptions struct {
+	// The name of the Fleet Manager resource
+	Name osutil.ExpandableString `yaml:"name"`
+	// The resource group of the Fleet Manager resource. Defaults to the resource group of the service
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup"`
+	// When set, only the member clusters of the update group are deployed to
+	Group string `yaml:"group"`
+}
+
+// AksFleetDeployResult is the deployment result of each AKS cluster of the fleet
+type AksFleetDeployResult struct {
+	Clusters []*AksFleetClusterDeployResult `json:"clusters"`
+}
+
+// AksFleetClusterDeployResult is the deployment result of a single AKS cluster of the fleet
+type AksFleetClusterDeployResult struct {
+	TargetResourceId string              `json:"targetResourceId"`
+	Deployment       *kubectl.Deployment `json:"deployment,omitempty"`
+	Endpoints        []string            `json:"endpoints"`
+}
+
+// aksCluster identifies the AKS cluster a k8s deployment is performed against
+type aksCluster struct {
+	subscriptionId    string
+	resourceGroupName string
+	name              string
+	// The kind of the cluster, an empty kind is an AKS cluster
+	kind K8sClusterKind
+}
+
+func newAksCluster(subscriptionId string, resourceGroupName string, name string) aksCluster {
+	return aksCluster{
+		subscriptionId:    subscriptionId,
+		resourceGroupName: resourceGroupName,
+		name:              name,
+	}
+}
+
+// resolveClusters resolves the AKS clusters the service is deployed to
+// Services without a fleet configuration are deployed to the single cluster resolved by targetCluster
+func (t *aksTarget) resolveClusters(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]aksCluster, error) {
+	fleet := serviceConfig.K8s.Fleet
+	if fleet == nil {
+		cluster, err := t.targetCluster(serviceConfig, targetResource)
+		if err != nil {
+			return nil, err
+		}
+
+		return []aksCluster{cluster}, nil
+	}
+
+	clusters := []aksCluster{}
+	for index, clusterConfig := range fleet.Clusters {
+		clusterName, err := clusterConfig.Name.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst fleet cluster name: %w", err)
+		}
+
+		if clusterName == "" {
+			return nil, fmt.Errorf("missing 'name' for fleet cluster at index %d", index)
+		}
+
+		resourceGroupName, err := clusterConfig.ResourceGroup.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst fleet cluster resource group: %w", err)
+		}
+
+		if resourceGroupName == "" {
+			resourceGroupName = targetResource.ResourceGroupName()
+		}
+
+		subscriptionId, err := clusterConfig.SubscriptionId.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst fleet cluster subscription: %w", err)
+		}
+
+		if subscriptionId == "" {
+			subscriptionId = targetResource.SubscriptionId()
+		}
+
+		clusters = appendUniqueCluster(clusters, newAksCluster(subscriptionId, resourceGroupName, clusterName))
+	}
+
+	if fleet.Manager != nil {
+		members, err := t.resolveFleetMembers(ctx, fleet.Manager, targetResource)
+		if err != nil {
+			return nil, err
+		}
+
+		clusters = appendUniqueCluster(clusters, members...)
+	}
+
+	if len(clusters) == 0 {
+		return nil, errors.New("no AKS clusters found for the fleet deployment")
+	}
+
+	return clusters, nil
+}
+
+// resolveFleetMembers resolves the AKS clusters joined to the Azure Kubernetes Fleet Manager resource
+func (t *aksTarget) resolveFleetMembers(
+	ctx context.Context,
+	options *AksFleetManagerOptions,
+	targetResource *environment.TargetResource,
+) ([]aksCluster, error) {
+	fleetName, err := options.Name.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst fleet manager name: %w", err)
+	}
+
+	if fleetName == "" {
+		return nil, errors.New("missing 'name' for the fleet manager configuration")
+	}
+
+	resourceGroupName, err := options.ResourceGroup.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst fleet manager resource group: %w", err)
+	}
+
+	if resourceGroupName == "" {
+		resourceGroupName = targetResource.ResourceGroupName()
+	}
+
+	members, err := t.managedClustersService.ListFleetMembers(
+		ctx,
+		targetResource.SubscriptionId(),
+		resourceGroupName,
+		fleetName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving members of fleet '%s', %w", fleetName, err)
+	}
+
+	clusters := []aksCluster{}
+	for _, member := range members {
+		if options.Group != "" && !strings.EqualFold(member.Properties.Group, options.Group) {
+			continue
+		}
+
+		clusterId, err := arm.ParseResourceID(member.Properties.ClusterResourceId)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing cluster resource id of fleet member '%s', %w", member.Name, err)
+		}
+
+		clusters = append(
+			clusters,
+			newAksCluster(clusterId.SubscriptionID, clusterId.ResourceGroupName, clusterId.Name),
+		)
+	}
+
+	return clusters, nil
+}
+
+// appendUniqueCluster appends the clusters that are not already contained in the slice
+func appendUniqueCluster(clusters []aksCluster, newClusters ...aksCluster) []aksCluster {
+	for _, cluster := range newClusters {
+		if !slices.Contains(clusters, cluster) {
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	return clusters
+}
+
+// deployFleet deploys the k8s resources of the service to each AKS cluster of the fleet
+// Clusters are deployed sequentially and the deployment stops at the first cluster that fails
+func (t *aksTarget) deployFleet(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	// Each cluster is connected through the kube config managed by azd, which a custom KUBECONFIG would bypass
+	if kubeConfigPath := t.env.Getenv(kubectl.KubeConfigEnvVarName); kubeConfigPath != "" {
+		return nil, fmt.Errorf("fleet deployments do not support a custom KUBECONFIG (%s)", kubeConfigPath)
+	}
+
+	clusters, err := t.resolveClusters(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &AksFleetDeployResult{}
+	endpoints := []string{}
+
+	for index, cluster := range clusters {
+		progress.SetProgress(NewServiceProgress(
+			fmt.Sprintf("Deploying to AKS cluster '%s' (%d/%d)", cluster.name, index+1, len(clusters)),
+		))
+
+		result, err := async.RunWithProgress(
+			func(clusterProgress ServiceProgress) {
+				progress.SetProgress(NewServiceProgress(fmt.Sprintf("%s: %s", cluster.name, clusterProgress.Message)))
+			},
+			func(clusterProgress *async.Progress[ServiceProgress]) (*AksFleetClusterDeployResult, error) {
+				return t.deployCluster(ctx, serviceConfig, packageOutput, targetResource, cluster, clusterProgress)
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed deploying to AKS cluster '%s': %w", cluster.name, err)
+		}
+
+		details.Clusters = append(details.Clusters, result)
+		endpoints = appendUnique(endpoints, result.Endpoints...)
+	}
+
+	if err := t.saveEndpointUrl(ctx, serviceConfig, endpoints); err != nil {
+		return nil, err
+	}
+
+	return &ServiceDeployResult{
+		Package:          packageOutput,
+		TargetResourceId: details.Clusters[0].TargetResourceId,
+		Kind:             AksTarget,
+		Details:          details,
+		Endpoints:        endpoints,
+		EndpointDetails:  ParseServiceEndpoints(endpoints),
+		healthChecked:    serviceConfig.HealthCheck != nil,
+	}, nil
+}
+
+// deployCluster connects to a single AKS cluster of the fleet, then deploys and verifies the k8s resources
+func (t *aksTarget) deployCluster(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	cluster aksCluster,
+	progress *async.Progress[ServiceProgress],
+) (*AksFleetClusterDeployResult, error) {
+	namespace := t.getK8sNamespace(serviceConfig)
+
+	progress.SetProgress(NewServiceProgress("Connecting to AKS cluster"))
+	if _, err := t.connectCluster(ctx, serviceConfig, cluster, namespace); err != nil {
+		return nil, err
+	}
+
+	if err := t.ensureServiceNamespace(ctx, serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if serviceConfig.K8s.WorkloadIdentity != nil {
+		progress.SetProgress(NewServiceProgress("Configuring workload identity"))
+		if err := t.ensureWorkloadIdentity(ctx, serviceConfig, targetResource, cluster); err != nil {
+			return nil, fmt.Errorf("failed configuring workload identity: %w", err)
+		}
+	}
+
+	if serviceConfig.K8s.ImagePullSecret != nil {
+		progress.SetProgress(NewServiceProgress("Configuring image pull secret"))
+		if err := t.ensureImagePullSecret(ctx, serviceConfig, targetResource); err != nil {
+			return nil, fmt.Errorf("failed configuring image pull secret: %w", err)
+		}
+	}
+
+	if serviceConfig.K8s.KeyVault != nil {
+		progress.SetProgress(NewServiceProgress("Configuring Key Vault secrets"))
+		if err := t.ensureSecretProviderClass(ctx, serviceConfig, cluster); err != nil {
+			return nil, fmt.Errorf("failed configuring Key Vault secrets: %w", err)
+		}
+	}
+
+	// The Dapr sidecar loads the components when the pods start, the components are applied before the workloads
+	if serviceConfig.Dapr != nil && serviceConfig.Dapr.Components != "" {
+		progress.SetProgress(NewServiceProgress("Applying Dapr components"))
+		if err := t.applyDaprComponents(ctx, serviceConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	deployment, err := t.deployResources(ctx, serviceConfig, packageOutput, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
+	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	// The health of the service is verified on each cluster instead of once for the endpoints of all the clusters
+	if serviceConfig.HealthCheck != nil {
+		progress.SetProgress(NewServiceProgress("Checking health of AKS service"))
+		err := checkHealth(ctx, t.transporter, t.env.Getenv, serviceConfig, serviceConfig.HealthCheck, endpoints)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for index, endpoint := range endpoints {
+		endpoints[index] = clusterEndpoint(endpoint, cluster.name)
+	}
+
+	return &AksFleetClusterDeployResult{
+		TargetResourceId: azure.KubernetesServiceRID(cluster.subscriptionId, cluster.resourceGroupName, cluster.name),
+		Deployment:       deployment,
+		Endpoints:        endpoints,
+	}, nil
+}
+
+// clusterEndpoint adds the name of the AKS cluster to the identifying information of the endpoint
+func clusterEndpoint(endpoint string, clusterName string) string {
+	if strings.HasSuffix(endpoint, ")") {
+		return fmt.Sprintf("%s, Cluster: %s)", strings.TrimSuffix(endpoint, ")"), clusterName)
+	}
+
+	return fmt.Sprintf("%s (Cluster: %s)", endpoint, clusterName)
+}
@@ -0,0 +1,205 @@
+package project
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Deploy_Fleet(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	connectedClusters := setupFleetClusterMocks(mockContext)
+	setupFleetMembersMock(mockContext, []*azcli.FleetMember{
+		fleetMember("aks-east", "RG_ID", "prod"),
+		fleetMember("aks-central", "rg-central", "prod"),
+		fleetMember("aks-canary", "rg-canary", "canary"),
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Fleet = &AksFleetOptions{
+		Clusters: []AksFleetCluster{
+			{Name: osutil.NewExpandableString("aks-east")},
+			{Name: osutil.NewExpandableString("aks-west"), ResourceGroup: osutil.NewExpandableString("rg-west")},
+		},
+		Manager: &AksFleetManagerOptions{
+			Name:  osutil.NewExpandableString("fleet"),
+			Group: "prod",
+		},
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	messages := []string{}
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := async.RunWithProgress(
+		func(progress ServiceProgress) { messages = append(messages, progress.Message) },
+		func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+
+	// Duplicate clusters and members of other update groups are not deployed to
+	require.Equal(t, []string{
+		"/subscriptions/SUB_ID/resourceGroups/RG_ID/aks-east",
+		"/subscriptions/SUB_ID/resourceGroups/rg-west/aks-west",
+		"/subscriptions/SUB_ID/resourceGroups/rg-central/aks-central",
+	}, *connectedClusters)
+
+	require.IsType(t, new(AksFleetDeployResult), deployResult.Details)
+	details := deployResult.Details.(*AksFleetDeployResult)
+	require.Len(t, details.Clusters, 3)
+	require.Equal(t, details.Clusters[0].TargetResourceId, deployResult.TargetResourceId)
+	require.Contains(t, details.Clusters[1].TargetResourceId, "/resourceGroups/rg-west/")
+
+	require.Contains(
+		t,
+		deployResult.Endpoints,
+		"http://10.10.10.10:80 (Service: api-service, Type: ClusterIP, Cluster: aks-west)",
+	)
+	require.Contains(t, messages, "Deploying to AKS cluster 'aks-central' (3/3)")
+	require.Contains(t, messages, "aks-central: Fetching endpoints for AKS service")
+}
+
+func Test_Deploy_Fleet_Custom_KubeConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Fleet = &AksFleetOptions{
+		Clusters: []AksFleetCluster{{Name: osutil.NewExpandableString("aks-east")}},
+	}
+	env := createEnv()
+	env.DotenvSet(kubectl.KubeConfigEnvVarName, "/tmp/kubeconfig")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+	})
+
+	require.ErrorContains(t, err, "fleet deployments do not support a custom KUBECONFIG")
+}
+
+func Test_ClusterEndpoint(t *testing.T) {
+	require.Equal(
+		t,
+		"http://1.1.1.1 (Ingress, Type: LoadBalancer, Cluster: aks-east)",
+		clusterEndpoint("http://1.1.1.1 (Ingress, Type: LoadBalancer)", "aks-east"),
+	)
+	require.Equal(t, "http://1.1.1.1 (Cluster: aks-east)", clusterEndpoint("http://1.1.1.1", "aks-east"))
+}
+
+// setupFleetClusterMocks mocks any managed cluster and records the clusters credentials were acquired for
+func setupFleetClusterMocks(mockContext *mocks.MockContext) *[]string {
+	connectedClusters := []string{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(
+			request.URL.Path,
+			"Microsoft.ContainerService/managedClusters/",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		managedCluster := armcontainerservice.ManagedCluster{
+			ID:       to.Ptr(request.URL.Path),
+			Location: to.Ptr("eastus2"),
+			Type:     to.Ptr("Microsoft.ContainerService/managedClusters"),
+			Properties: &armcontainerservice.ManagedClusterProperties{
+				EnableRBAC:           to.Ptr(true),
+				DisableLocalAccounts: to.Ptr(false),
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, managedCluster)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "listClusterUserCredential")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		clusterPath := strings.Split(request.URL.Path, "/providers/Microsoft.ContainerService/managedClusters")
+		clusterName := strings.Split(strings.Trim(clusterPath[1], "/"), "/")[0]
+		connectedClusters = append(connectedClusters, clusterPath[0]+"/"+clusterName)
+
+		kubeConfigBytes, err := yaml.Marshal(createTestCluster(clusterName, "user1"))
+		if err != nil {
+			return nil, err
+		}
+
+		creds := armcontainerservice.CredentialResults{
+			Kubeconfigs: []*armcontainerservice.CredentialResult{
+				{
+					Name:  to.Ptr("context"),
+					Value: kubeConfigBytes,
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, creds)
+	})
+
+	return &connectedClusters
+}
+
+// setupFleetMembersMock mocks the fleet members across two pages
+func setupFleetMembersMock(mockContext *mocks.MockContext, members []*azcli.FleetMember) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "fleets/fleet/members")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if request.URL.Query().Get("$skipToken") == "" {
+			nextLink := *request.URL
+			query := nextLink.Query()
+			query.Set("$skipToken", "1")
+			nextLink.RawQuery = query.Encode()
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value":    members[:1],
+				"nextLink": nextLink.String(),
+			})
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": members[1:],
+		})
+	})
+}
+
+func fleetMember(clusterName string, resourceGroupName string, group string) *azcli.FleetMember {
+	return &azcli.FleetMember{
+		Name: clusterName,
+		Properties: azcli.FleetMemberProperties{
+			ClusterResourceId: azure.KubernetesServiceRID("SUB_ID", resourceGroupName, clusterName),
+			Group:             group,
+		},
+	}
+}
@@ -0,0 +1,172 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The AKS Gateway API options
+// When configured, endpoints are also discovered from the Gateway API HTTPRoute resources of the service
+// and the Gateways they are attached to, ex) Application Gateway for Containers
+type AksGatewayOptions struct {
+	// The name of the HTTPRoute used to report endpoints. Defaults to the service name
+	Name string `yaml:"name"`
+	// Additional HTTPRoute resources used to report endpoints
+	Names []string `yaml:"names"`
+	// The label selector used to find additional HTTPRoute resources, ex) app=api
+	Selector     string `yaml:"selector"`
+	RelativePath string `yaml:"relativePath"`
+}
+
+// getHttpRouteName returns the name of the k8s HTTPRoute for the service. Defaults to the service name
+func (t *aksTarget) getHttpRouteName(serviceConfig *ServiceConfig) string {
+	routeName := serviceConfig.K8s.Gateway.Name
+	if routeName == "" {
+		routeName = serviceConfig.Name
+	}
+
+	return routeName
+}
+
+// Retrieve the endpoints exposed through the HTTPRoute with the specified name
+// The endpoint host is resolved from the route hostnames, the Gateway listener hostname or the Gateway address
+func (t *aksTarget) getHttpRouteEndpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	routeName string,
+) ([]string, error) {
+	route, err := t.waitForHttpRoute(ctx, serviceConfig, routeName)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []string{}
+	for _, parentRef := range route.Spec.ParentRefs {
+		if parentRef.Kind != "" && parentRef.Kind != "Gateway" {
+			continue
+		}
+
+		namespace := parentRef.Namespace
+		if namespace == "" {
+			namespace = route.Metadata.Namespace
+		}
+
+		gateway, err := t.waitForGateway(ctx, serviceConfig, parentRef.Name, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, listener := range gateway.Spec.Listeners {
+			if parentRef.SectionName != "" && listener.Name != parentRef.SectionName {
+				continue
+			}
+
+			if parentRef.Port != nil && listener.Port != *parentRef.Port {
+				continue
+			}
+
+			listenerEndpoints, err := gatewayListenerEndpoints(serviceConfig, route, gateway, listener)
+			if err != nil {
+				return nil, err
+			}
+
+			endpoints = appendUnique(endpoints, listenerEndpoints...)
+		}
+	}
+
+	return endpoints, nil
+}
+
+// gatewayListenerEndpoints returns the endpoints of the route exposed through the Gateway listener
+func gatewayListenerEndpoints(
+	serviceConfig *ServiceConfig,
+	route *kubectl.HttpRoute,
+	gateway *kubectl.Gateway,
+	listener kubectl.GatewayListener,
+) ([]string, error) {
+	var protocol string
+	switch listener.Protocol {
+	case "HTTP":
+		protocol = "http"
+	case "HTTPS":
+		protocol = "https"
+	default:
+		// Routes are only attached to HTTP and HTTPS listeners
+		return nil, nil
+	}
+
+	hosts := route.Spec.Hostnames
+	if len(hosts) == 0 && listener.Hostname != nil {
+		hosts = []string{*listener.Hostname}
+	}
+
+	if len(hosts) == 0 {
+		for _, address := range gateway.Status.Addresses {
+			hosts = append(hosts, address.Value)
+		}
+	}
+
+	endpoints := []string{}
+	for _, host := range hosts {
+		// Wildcard hostnames cannot be used to build an endpoint url
+		if strings.HasPrefix(host, "*") {
+			continue
+		}
+
+		baseUrl := endpointBaseUrl(protocol, host, listener.Port)
+		endpointUrl, err := url.JoinPath(baseUrl, serviceConfig.K8s.Gateway.RelativePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed constructing service endpoints, %w", err)
+		}
+
+		endpoints = append(endpoints, fmt.Sprintf("%s (HTTPRoute, Gateway: %s)", endpointUrl, gateway.Metadata.Name))
+	}
+
+	return endpoints, nil
+}
+
+// Finds the HTTPRoute with the specified name
+// Waits until the route has been accepted by at least one of its parent Gateways
+func (t *aksTarget) waitForHttpRoute(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	routeName string,
+) (*kubectl.HttpRoute, error) {
+	return kubectl.WaitForResource(
+		ctx, t.kubectl, kubectl.ResourceTypeHttpRoute,
+		func(route *kubectl.HttpRoute) bool {
+			return route.Metadata.Name == routeName
+		},
+		func(route *kubectl.HttpRoute) bool {
+			return route.IsAccepted()
+		},
+		t.getWaitOptions(serviceConfig),
+	)
+}
+
+// Finds the Gateway with the specified name within the namespace
+// Waits until an address has been assigned to the Gateway
+func (t *aksTarget) waitForGateway(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	gatewayName string,
+	namespace string,
+) (*kubectl.Gateway, error) {
+	waitOptions := t.getWaitOptions(serviceConfig)
+	waitOptions.Namespace = namespace
+
+	return kubectl.WaitForResource(
+		ctx, t.kubectl, kubectl.ResourceTypeGateway,
+		func(gateway *kubectl.Gateway) bool {
+			return gateway.Metadata.Name == gatewayName
+		},
+		func(gateway *kubectl.Gateway) bool {
+			return len(gateway.Status.Addresses) > 0
+		},
+		waitOptions,
+	)
+}
@@ -0,0 +1,150 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Endpoints_Gateway(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Gateway = &AksGatewayOptions{
+		RelativePath: "/api",
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get httproutes.gateway.networking.k8s.io")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		route := &kubectl.HttpRoute{
+			Resource: kubectl.Resource{
+				Metadata: kubectl.ResourceMetadata{Name: "api", Namespace: "api-namespace"},
+			},
+			Spec: kubectl.HttpRouteSpec{
+				ParentRefs: []kubectl.ParentReference{
+					{Name: "shared-gateway", Namespace: "gateway-infra", SectionName: "https"},
+				},
+				Hostnames: []string{"api.contoso.com"},
+			},
+			Status: kubectl.HttpRouteStatus{
+				Parents: []kubectl.RouteParentStatus{
+					{Conditions: []kubectl.Condition{{Type: "Accepted", Status: "True"}}},
+				},
+			},
+		}
+		jsonBytes, _ := json.Marshal(createK8sResourceList(route))
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	var gatewayArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get gateways.gateway.networking.k8s.io")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		gatewayArgs = args.Args
+		gateway := &kubectl.Gateway{
+			Resource: kubectl.Resource{
+				Metadata: kubectl.ResourceMetadata{Name: "shared-gateway", Namespace: "gateway-infra"},
+			},
+			Spec: kubectl.GatewaySpec{
+				Listeners: []kubectl.GatewayListener{
+					{Name: "http", Port: 80, Protocol: "HTTP"},
+					{Name: "https", Port: 443, Protocol: "HTTPS"},
+				},
+			},
+			Status: kubectl.GatewayStatus{
+				Addresses: []kubectl.GatewayAddress{{Type: "Hostname", Value: "abc.fz12.alb.azure.com"}},
+			},
+		}
+		jsonBytes, _ := json.Marshal(createK8sResourceList(gateway))
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	endpoints, err := serviceTarget.Endpoints(*mockContext.Context, serviceConfig, scope)
+	require.NoError(t, err)
+	require.NotEmpty(t, endpoints)
+
+	// Gateway endpoints are the most publicly exposed endpoints and reported last
+	require.Equal(t, "https://api.contoso.com/api (HTTPRoute, Gateway: shared-gateway)", endpoints[len(endpoints)-1])
+	require.Contains(t, strings.Join(gatewayArgs, " "), "-n gateway-infra")
+}
+
+func Test_GatewayListenerEndpoints(t *testing.T) {
+	gateway := &kubectl.Gateway{
+		Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "gateway"}},
+		Status: kubectl.GatewayStatus{
+			Addresses: []kubectl.GatewayAddress{{Type: "IPAddress", Value: "10.0.0.1"}},
+		},
+	}
+
+	tests := map[string]struct {
+		hostnames []string
+		listener  kubectl.GatewayListener
+		expected  []string
+	}{
+		"GatewayAddress": {
+			listener: kubectl.GatewayListener{Name: "http", Port: 80, Protocol: "HTTP"},
+			expected: []string{"http://10.0.0.1 (HTTPRoute, Gateway: gateway)"},
+		},
+		"ListenerHostname": {
+			listener: kubectl.GatewayListener{
+				Name: "https", Port: 443, Protocol: "HTTPS", Hostname: to.Ptr("web.contoso.com"),
+			},
+			expected: []string{"https://web.contoso.com (HTTPRoute, Gateway: gateway)"},
+		},
+		"NonDefaultPort": {
+			hostnames: []string{"api.contoso.com"},
+			listener:  kubectl.GatewayListener{Name: "http", Port: 8080, Protocol: "HTTP"},
+			expected:  []string{"http://api.contoso.com:8080 (HTTPRoute, Gateway: gateway)"},
+		},
+		"WildcardHostname": {
+			hostnames: []string{"*.contoso.com", "api.contoso.com"},
+			listener:  kubectl.GatewayListener{Name: "http", Port: 80, Protocol: "HTTP"},
+			expected:  []string{"http://api.contoso.com (HTTPRoute, Gateway: gateway)"},
+		},
+		"TcpListener": {
+			listener: kubectl.GatewayListener{Name: "tcp", Port: 5432, Protocol: "TCP"},
+			expected: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Gateway = &AksGatewayOptions{}
+			route := &kubectl.HttpRoute{
+				Spec: kubectl.HttpRouteSpec{Hostnames: test.hostnames},
+			}
+
+			endpoints, err := gatewayListenerEndpoints(serviceConfig, route, gateway, test.listener)
+			require.NoError(t, err)
+
+			if test.expected == nil {
+				require.Empty(t, endpoints)
+			} else {
+				require.Equal(t, test.expected, endpoints)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package project
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// The annotation of the deployments recording the azd revision they were deployed with
+	aksRevisionAnnotation = "azd.azure.com/revision"
+	// The environment config path of the deployment history of the AKS services of the environment
+	aksHistoryConfigPath = "aks.history"
+	// The key of the history ConfigMap containing the recorded revisions
+	aksHistoryRevisionsKey = "revisions.json"
+	// The number of revisions kept when the history limit is not configured
+	defaultAksHistoryLimit = 10
+)
+
+// ErrNoRevision is returned when rolling back to a revision that is not recorded within the deployment history
+var ErrNoRevision = errors.New("revision not found in the deployment history")
+
+// The AKS deployment history options
+type AksHistoryOptions struct {
+	// The maximum number of revisions kept within the deployment history. Defaults to 10
+	Limit int `yaml:"limit"`
+}
+
+// AksDeploymentRevision is a deployment of an AKS service recorded within the deployment history
+type AksDeploymentRevision struct {
+	// The revision number, incremented for every deployment of the service
+	Revision int `json:"revision"`
+	// The time the revision was deployed
+	Timestamp time.Time `json:"timestamp"`
+	// The container image of the service, ex) contoso.azurecr.io/todo/api:azd-deploy-1700000000
+	Image string `json:"image,omitempty"`
+	// The digest of the container image running in the cluster, empty when it could not be determined
+	ImageDigest string `json:"imageDigest,omitempty"`
+	// The sha256 checksum of the applied manifests
+	ManifestChecksum string `json:"manifestChecksum"`
+	// The revision the service was rolled back to, 0 when the revision is a regular deployment
+	RollbackOf int `json:"rollbackOf,omitempty"`
+}
+
+// DeploymentHistory returns the revisions of the service recorded within the environment, ordered by revision
+func DeploymentHistory(env *environment.Environment, serviceName string) ([]AksDeploymentRevision, error) {
+	revisions := []AksDeploymentRevision{}
+	if _, err := env.Config.GetSection(historyConfigPath(serviceName), &revisions); err != nil {
+		return nil, fmt.Errorf("failed reading deployment history of service '%s', %w", serviceName, err)
+	}
+
+	return revisions, nil
+}
+
+func historyConfigPath(serviceName string) string {
+	return fmt.Sprintf("%s.%s", aksHistoryConfigPath, serviceName)
+}
+
+// getHistoryConfigMapName returns the name of the ConfigMap containing the deployment history of the service
+func (t *aksTarget) getHistoryConfigMapName(serviceConfig *ServiceConfig) string {
+	return fmt.Sprintf("%s-azd-history", serviceConfig.Name)
+}
+
+// revisionManifestKey returns the key of the history ConfigMap containing the manifests of the revision
+func revisionManifestKey(revision int) string {
+	return fmt.Sprintf("revision-%d.yaml", revision)
+}
+
+// renderManifests returns the manifests applied from the deployment path, including the labels and node pool
+// scheduling added by azd, so that the revision can be reapplied as is
+func (t *aksTarget) renderManifests(serviceConfig *ServiceConfig) (string, error) {
+	manifests, err := t.kubectl.ReadManifests(t.getDeploymentPath(serviceConfig), serviceConfig.K8s.Envsubst)
+	if err != nil {
+		return "", fmt.Errorf("failed reading kube manifests: %w", err)
+	}
+
+	parse := parseK8sObjects
+	if serviceConfig.K8s.Prune {
+		parse = func(manifests []string) ([]k8sObject, error) {
+			return labelManifests(serviceConfig, manifests)
+		}
+	}
+
+	objects, err := parse(manifests)
+	if err != nil {
+		return "", err
+	}
+
+	if err := t.applyScheduling(serviceConfig, objects); err != nil {
+		return "", err
+	}
+
+	return marshalK8sObjects(objects)
+}
+
+// getHistory returns the deployment history recorded within the cluster along with the ConfigMap containing it
+// A nil ConfigMap is returned when no revision has been recorded yet
+func (t *aksTarget) getHistory(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) (*kubectl.ConfigMap, []AksDeploymentRevision, error) {
+	configMaps, err := kubectl.GetResources[*kubectl.ConfigMap](ctx, t.kubectl, kubectl.ResourceTypeConfigMap, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed retrieving config maps, %w", err)
+	}
+
+	configMapName := t.getHistoryConfigMapName(serviceConfig)
+	for _, configMap := range configMaps.Items {
+		if configMap.Metadata.Name != configMapName {
+			continue
+		}
+
+		revisions := []AksDeploymentRevision{}
+		if err := json.Unmarshal([]byte(configMap.Data[aksHistoryRevisionsKey]), &revisions); err != nil {
+			return nil, nil, fmt.Errorf("failed parsing deployment history '%s', %w", configMapName, err)
+		}
+
+		return configMap, revisions, nil
+	}
+
+	return nil, []AksDeploymentRevision{}, nil
+}
+
+// recordRevision records the deployed manifests as a new revision within the history ConfigMap of the cluster
+// and the environment, and annotates the deployments with the revision.
+// The oldest revisions are removed once the history exceeds its limit.
+func (t *aksTarget) recordRevision(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	manifest string,
+	rollbackOf int,
+) (*AksDeploymentRevision, error) {
+	configMap, revisions, err := t.getHistory(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if configMap == nil {
+		configMap = kubectl.NewConfigMap(
+			t.getHistoryConfigMapName(serviceConfig),
+			t.getK8sNamespace(serviceConfig),
+			map[string]string{},
+		)
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	checksum := sha256.Sum256([]byte(manifest))
+	image := t.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+	revision := AksDeploymentRevision{
+		Revision:         1,
+		Timestamp:        time.Now().UTC(),
+		Image:            image,
+		ImageDigest:      t.getImageDigest(ctx, image),
+		ManifestChecksum: hex.EncodeToString(checksum[:]),
+		RollbackOf:       rollbackOf,
+	}
+
+	if len(revisions) > 0 {
+		revision.Revision = revisions[len(revisions)-1].Revision + 1
+	}
+
+	revisions = append(revisions, revision)
+	configMap.Data[revisionManifestKey(revision.Revision)] = manifest
+
+	limit := defaultAksHistoryLimit
+	if serviceConfig.K8s.History != nil && serviceConfig.K8s.History.Limit > 0 {
+		limit = serviceConfig.K8s.History.Limit
+	}
+
+	if len(revisions) > limit {
+		for _, removed := range revisions[:len(revisions)-limit] {
+			delete(configMap.Data, revisionManifestKey(removed.Revision))
+		}
+
+		revisions = revisions[len(revisions)-limit:]
+	}
+
+	revisionsJson, err := json.Marshal(revisions)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshalling deployment history, %w", err)
+	}
+
+	// The history is not labelled with the service label so that it is not pruned with the deployed resources
+	configMap.Data[aksHistoryRevisionsKey] = string(revisionsJson)
+	configMapYaml, err := yaml.Marshal(configMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshalling deployment history, %w", err)
+	}
+
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, string(configMapYaml), nil); err != nil {
+		return nil, fmt.Errorf("failed applying deployment history '%s', %w", configMap.Metadata.Name, err)
+	}
+
+	if err := t.annotateRevision(ctx, manifest, revision.Revision); err != nil {
+		return nil, err
+	}
+
+	if err := t.env.Config.Set(historyConfigPath(serviceConfig.Name), revisions); err != nil {
+		return nil, err
+	}
+
+	if err := t.envManager.Save(ctx, t.env); err != nil {
+		return nil, fmt.Errorf("failed saving environment, %w", err)
+	}
+
+	return &revision, nil
+}
+
+// annotateRevision annotates the deployments defined within the manifest with the revision
+func (t *aksTarget) annotateRevision(ctx context.Context, manifest string, revision int) error {
+	objects, err := parseK8sObjects([]string{manifest})
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{aksRevisionAnnotation: fmt.Sprint(revision)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed marshalling deployment patch, %w", err)
+	}
+
+	for _, object := range objects {
+		if object.kind() != "Deployment" {
+			continue
+		}
+
+		namespace, _ := object.nested("metadata")["namespace"].(string)
+		_, err := t.kubectl.Patch(
+			ctx, kubectl.ResourceTypeDeployment, object.name(), string(patch), &kubectl.KubeCliFlags{Namespace: namespace},
+		)
+		if err != nil {
+			return fmt.Errorf("failed annotating deployment '%s' with revision %d, %w", object.name(), revision, err)
+		}
+	}
+
+	return nil
+}
+
+// getImageDigest returns the digest of the container image, ex) sha256:4f2d..., from the image reference or from
+// the image of the containers running in the cluster.
+// Errors are ignored since the digest is only recorded for informational purposes.
+func (t *aksTarget) getImageDigest(ctx context.Context, image string) string {
+	if image == "" {
+		return ""
+	}
+
+	if _, digest, has := strings.Cut(image, "@"); has {
+		return digest
+	}
+
+	pods, err := kubectl.GetResources[kubectl.Pod](ctx, t.kubectl, kubectl.ResourceTypePod, nil)
+	if err != nil {
+		log.Printf("failed getting pods for the digest of image '%s': %v\n", image, err)
+		return ""
+	}
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Image != image {
+				continue
+			}
+
+			// The image id is the resolved image reference, ex) contoso.azurecr.io/todo/api@sha256:4f2d...
+			if _, digest, has := strings.Cut(container.ImageID, "@"); has {
+				return digest
+			}
+		}
+	}
+
+	return ""
+}
+
+// Rollback reapplies the manifests of a revision recorded within the deployment history of the service
+// The previous revision is reapplied when the revision is 0. The rollback is recorded as a new revision.
+func (t *aksTarget) Rollback(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	revision int,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if _, err := t.useServiceCluster(ctx, serviceConfig, targetResource); err != nil {
+		return nil, err
+	}
+
+	t.kubectl.SetServerSideApply(serverSideApplyOptions(serviceConfig))
+	t.kubectl.SetRetry(retryOptions(serviceConfig))
+
+	progress.SetProgress(NewServiceProgress("Reading deployment history"))
+	configMap, revisions, err := t.getHistory(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if configMap == nil || len(revisions) == 0 {
+		return nil, fmt.Errorf("no deployment history recorded for service '%s'", serviceConfig.Name)
+	}
+
+	target, err := findRevision(revisions, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, has := configMap.Data[revisionManifestKey(target.Revision)]
+	if !has {
+		return nil, fmt.Errorf("the manifests of revision %d are missing from the deployment history", target.Revision)
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Reapplying revision %d", target.Revision)))
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, manifest, nil); err != nil {
+		return nil, withKubectlErrorSuggestion(fmt.Errorf("failed applying revision %d: %w", target.Revision, err))
+	}
+
+	objects, err := parseK8sObjects([]string{manifest})
+	if err != nil {
+		return nil, err
+	}
+
+	var deployment *kubectl.Deployment
+	for _, object := range objects {
+		if object.kind() != "Deployment" {
+			continue
+		}
+
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying deployment: %s", object.name())))
+		verified, err := t.waitForDeployment(ctx, serviceConfig, object.name(), progress)
+		if err != nil {
+			return nil, err
+		}
+
+		if deployment == nil {
+			deployment = verified
+		}
+	}
+
+	if target.Image != "" {
+		t.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", target.Image)
+	}
+
+	progress.SetProgress(NewServiceProgress("Recording deployment revision"))
+	if _, err := t.recordRevision(ctx, serviceConfig, manifest, target.Revision); err != nil {
+		return nil, fmt.Errorf("failed recording deployment revision: %w", err)
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
+	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.saveEndpointUrl(ctx, serviceConfig, endpoints); err != nil {
+		return nil, err
+	}
+
+	return &ServiceDeployResult{
+		TargetResourceId: targetResource.ResourceName(),
+		Kind:             AksTarget,
+		Details:          deployment,
+		Endpoints:        endpoints,
+		EndpointDetails:  ParseServiceEndpoints(endpoints),
+	}, nil
+}
+
+// findRevision returns the revision with the specified number, or the revision before the latest one when 0
+func findRevision(revisions []AksDeploymentRevision, revision int) (*AksDeploymentRevision, error) {
+	if revision == 0 {
+		if len(revisions) < 2 {
+			return nil, fmt.Errorf("%w, there is no revision before revision %d", ErrNoRevision, revisions[0].Revision)
+		}
+
+		return &revisions[len(revisions)-2], nil
+	}
+
+	available := []string{}
+	for index := range revisions {
+		if revisions[index].Revision == revision {
+			return &revisions[index], nil
+		}
+
+		available = append(available, fmt.Sprint(revisions[index].Revision))
+	}
+
+	return nil, fmt.Errorf("%w, revision %d is not one of the recorded revisions %s",
+		ErrNoRevision, revision, strings.Join(available, ", "))
+}
+
+// recordDeployment records the manifests deployed from the deployment path within the deployment history
+// Services without manifests, ex) deployed only with helm or kustomize, are not recorded.
+func (t *aksTarget) recordDeployment(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if _, err := os.Stat(t.getDeploymentPath(serviceConfig)); os.IsNotExist(err) {
+		log.Printf("no k8s manifests found for service '%s', skipping deployment history\n", serviceConfig.Name)
+		return nil
+	}
+
+	manifest, err := t.renderManifests(serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.recordRevision(ctx, serviceConfig, manifest, 0)
+	return err
+}
@@ -0,0 +1,204 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const historyTestManifest = "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api-deployment\n" +
+	"  namespace: api-namespace\nspec:\n  replicas: %d\n"
+
+func Test_Deploy_History(t *testing.T) {
+	mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, nil)
+	serviceConfig.K8s.History = &AksHistoryOptions{Limit: 2}
+	history, patches := setupHistoryMocks(t, mockContext)
+
+	writeHistoryTestManifest(t, serviceConfig, 1)
+	aksTarget := serviceTarget.(*aksTarget)
+	aksTarget.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", "contoso.azurecr.io/api:v1")
+
+	for range 3 {
+		_, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+		require.NoError(t, err)
+	}
+
+	// The oldest revision is removed once the history exceeds its limit
+	revisions := []AksDeploymentRevision{}
+	require.NoError(t, json.Unmarshal([]byte(history.Data[aksHistoryRevisionsKey]), &revisions))
+	require.Len(t, revisions, 2)
+	require.Equal(t, 2, revisions[0].Revision)
+	require.Equal(t, 3, revisions[1].Revision)
+	require.Equal(t, "contoso.azurecr.io/api:v1", revisions[1].Image)
+	require.Equal(t, "sha256:4f2d", revisions[1].ImageDigest)
+	require.Len(t, revisions[1].ManifestChecksum, 64)
+	require.NotContains(t, history.Data, revisionManifestKey(1))
+	require.Contains(t, history.Data[revisionManifestKey(3)], "name: api-deployment")
+
+	envRevisions, err := DeploymentHistory(aksTarget.env, serviceConfig.Name)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3}, []int{envRevisions[0].Revision, envRevisions[1].Revision})
+
+	require.Len(t, *patches, 3)
+	require.Equal(t, withIsolatedKubeConfig(
+		"patch", "deployment", "api-deployment", "--type", "merge",
+		"-p", `{"metadata":{"annotations":{"azd.azure.com/revision":"3"}}}`, "-n", "api-namespace",
+	), (*patches)[2])
+}
+
+func Test_Rollback(t *testing.T) {
+	tests := map[string]struct {
+		revision         int
+		expectedReplicas string
+		expectedImage    string
+		expectedError    string
+	}{
+		"Previous": {
+			revision:         0,
+			expectedReplicas: "replicas: 2",
+			expectedImage:    "contoso.azurecr.io/api:v2",
+		},
+		"Revision": {
+			revision:         1,
+			expectedReplicas: "replicas: 1",
+			expectedImage:    "contoso.azurecr.io/api:v1",
+		},
+		"NotFound": {
+			revision:      5,
+			expectedError: "revision 5 is not one of the recorded revisions 1, 2, 3",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, nil)
+			serviceConfig.K8s.History = &AksHistoryOptions{}
+			history, _ := setupHistoryMocks(t, mockContext)
+			aksTarget := serviceTarget.(*aksTarget)
+
+			for revision := 1; revision <= 3; revision++ {
+				writeHistoryTestManifest(t, serviceConfig, revision)
+				image := fmt.Sprintf("contoso.azurecr.io/api:v%d", revision)
+				aksTarget.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", image)
+
+				_, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+				require.NoError(t, err)
+			}
+
+			applied := ""
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply -f -")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				input, err := io.ReadAll(args.StdIn)
+				require.NoError(t, err)
+
+				if strings.Contains(string(input), "kind: ConfigMap") {
+					history.Data = map[string]string{}
+					require.NoError(t, yaml.Unmarshal(input, history))
+				} else {
+					applied = string(input)
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return aksTarget.Rollback(*mockContext.Context, serviceConfig, scope, test.revision, progress)
+				},
+			)
+
+			if test.expectedError != "" {
+				require.ErrorIs(t, err, ErrNoRevision)
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, deployResult)
+			require.Contains(t, applied, test.expectedReplicas)
+			require.Equal(t, test.expectedImage, aksTarget.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME"))
+
+			// The rollback is recorded as a new revision
+			revisions := []AksDeploymentRevision{}
+			require.NoError(t, json.Unmarshal([]byte(history.Data[aksHistoryRevisionsKey]), &revisions))
+			require.Len(t, revisions, 4)
+			require.Equal(t, 4, revisions[3].Revision)
+			require.Equal(t, test.expectedImage, revisions[3].Image)
+			require.NotZero(t, revisions[3].RollbackOf)
+		})
+	}
+}
+
+// setupHistoryMocks mocks the history ConfigMap of the cluster along with the pods and deployment patches
+func setupHistoryMocks(t *testing.T, mockContext *mocks.MockContext) (*kubectl.ConfigMap, *[][]string) {
+	history := &kubectl.ConfigMap{}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		input, err := io.ReadAll(args.StdIn)
+		require.NoError(t, err)
+
+		if strings.Contains(string(input), "kind: ConfigMap") {
+			history.Data = map[string]string{}
+			require.NoError(t, yaml.Unmarshal(input, history))
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get configmap")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		configMaps := kubectl.List[*kubectl.ConfigMap]{Items: []*kubectl.ConfigMap{}}
+		if history.Metadata.Name != "" {
+			configMaps.Items = append(configMaps.Items, history)
+		}
+
+		jsonBytes, _ := json.Marshal(configMaps)
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get pods")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		pod := createPod("api-7d9f", "Running", kubectl.ContainerState{}, true)
+		pod.Status.ContainerStatuses[0].Image = "contoso.azurecr.io/api:v1"
+		pod.Status.ContainerStatuses[0].ImageID = "contoso.azurecr.io/api@sha256:4f2d"
+
+		jsonBytes, _ := json.Marshal(kubectl.List[kubectl.Pod]{Items: []kubectl.Pod{pod}})
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	patches := [][]string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl patch")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		patches = append(patches, args.Args)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	return history, &patches
+}
+
+func writeHistoryTestManifest(t *testing.T, serviceConfig *ServiceConfig, replicas int) {
+	manifest := fmt.Sprintf(historyTestManifest, replicas)
+	path := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath, "deployment.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(manifest), osutil.PermissionFile))
+}
@@ -0,0 +1,208 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
+)
+
+// The AKS image pull secret options used when the container registry is not attached to the AKS cluster
+type AksImagePullSecretOptions struct {
+	// The name of the docker-registry secret. Defaults to '<service>-registry'
+	Name string `yaml:"name"`
+	// The container registry server. Defaults to the registry of the service container image
+	Server osutil.ExpandableString `yaml:"server"`
+	// The registry username. Azure Container Registry credentials are acquired automatically when not set
+	Username osutil.ExpandableString `yaml:"username"`
+	// The registry password or access token
+	Password osutil.ExpandableString `yaml:"password"`
+	// The service account that references the secret. Defaults to the workload identity service account or 'default'
+	ServiceAccount string `yaml:"serviceAccount"`
+}
+
+// ensureImagePullSecret creates or refreshes the docker-registry secret within the namespace and references it from
+// the service account so pods can pull images from registries that are not attached to the cluster, ex) GHCR
+func (t *aksTarget) ensureImagePullSecret(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	options := serviceConfig.K8s.ImagePullSecret
+
+	server, err := t.resolveImagePullSecretServer(ctx, serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	username, err := options.Username.Envsubst(t.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst image pull secret username: %w", err)
+	}
+
+	password, err := options.Password.Envsubst(t.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst image pull secret password: %w", err)
+	}
+
+	if username == "" || password == "" {
+		// ACR credentials are exchanged from the current principal and refreshed on every deployment, other registries
+		// use docker.credentials or the existing login of the container runtime
+		credentials, err := t.containerHelper.resolveRegistryCredentials(
+			ctx, serviceConfig, targetResource.SubscriptionId(), server)
+		if errors.Is(err, docker.ErrCredentialsNotFound) {
+			return fmt.Errorf("missing 'username' or 'password' for the image pull secret of registry '%s'", server)
+		} else if err != nil {
+			return fmt.Errorf("failed retrieving credentials for registry '%s', %w", server, err)
+		}
+
+		username = credentials.Username
+		password = credentials.Password
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	secretName := t.getImagePullSecretName(serviceConfig)
+	secret, err := kubectl.NewDockerRegistrySecret(secretName, namespace, server, username, password)
+	if err != nil {
+		return err
+	}
+
+	secretYaml, err := yaml.Marshal(secret)
+	if err != nil {
+		return fmt.Errorf("failed marshalling image pull secret, %w", err)
+	}
+
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, string(secretYaml), nil); err != nil {
+		return fmt.Errorf("failed applying image pull secret '%s', %w", secretName, err)
+	}
+
+	return t.ensureServiceAccountPullSecret(ctx, t.getImagePullServiceAccountName(serviceConfig), secretName)
+}
+
+// ensureServiceAccountPullSecret references the image pull secret from the service account
+// The service account is created when it does not exist yet. Existing image pull secrets are preserved.
+func (t *aksTarget) ensureServiceAccountPullSecret(
+	ctx context.Context,
+	serviceAccountName string,
+	secretName string,
+) error {
+	serviceAccounts, err := kubectl.GetResources[*kubectl.ServiceAccount](
+		ctx,
+		t.kubectl,
+		kubectl.ResourceTypeServiceAccount,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed retrieving service accounts, %w", err)
+	}
+
+	var serviceAccount *kubectl.ServiceAccount
+	for _, item := range serviceAccounts.Items {
+		if item.Metadata.Name == serviceAccountName {
+			serviceAccount = item
+			break
+		}
+	}
+
+	// The service account is created rather than applied so that service accounts later applied from the
+	// deployment manifests do not remove the image pull secret reference
+	if serviceAccount == nil {
+		if _, err := t.kubectl.CreateServiceAccount(ctx, serviceAccountName, nil); err != nil {
+			return fmt.Errorf("failed creating service account '%s', %w", serviceAccountName, err)
+		}
+
+		serviceAccount = &kubectl.ServiceAccount{}
+	}
+
+	if serviceAccount.HasImagePullSecret(secretName) {
+		return nil
+	}
+
+	imagePullSecrets := append(serviceAccount.ImagePullSecrets, kubectl.LocalObjectReference{Name: secretName})
+	patch, err := json.Marshal(map[string]any{
+		"imagePullSecrets": imagePullSecrets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed marshalling service account patch, %w", err)
+	}
+
+	_, err = t.kubectl.Patch(ctx, kubectl.ResourceTypeServiceAccount, serviceAccountName, string(patch), nil)
+	if err != nil {
+		return fmt.Errorf("failed referencing image pull secret from service account '%s', %w", serviceAccountName, err)
+	}
+
+	return nil
+}
+
+// resolveImagePullSecretServer resolves the container registry server from the following sources:
+// 1. The 'server' property of the image pull secret options
+// 2. The registry of the prebuilt container image
+// 3. The container registry of the service
+func (t *aksTarget) resolveImagePullSecretServer(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
+	server, err := serviceConfig.K8s.ImagePullSecret.Server.Envsubst(t.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed to envsubst image pull secret server: %w", err)
+	}
+
+	if server == "" && !serviceConfig.K8s.Image.Empty() {
+		image, err := serviceConfig.K8s.Image.Envsubst(t.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed to envsubst k8s image: %w", err)
+		}
+
+		containerImage, err := docker.ParseContainerImage(image)
+		if err != nil {
+			return "", fmt.Errorf("failed parsing k8s image, %w", err)
+		}
+
+		server = containerImage.Registry
+	}
+
+	if server == "" {
+		registryName, err := t.containerHelper.RegistryName(ctx, serviceConfig)
+		if err != nil {
+			return "", err
+		}
+
+		server = registryName
+	}
+
+	if server == "" {
+		return "", fmt.Errorf("could not determine the container registry server for the image pull secret")
+	}
+
+	return server, nil
+}
+
+func (t *aksTarget) getImagePullSecretName(serviceConfig *ServiceConfig) string {
+	secretName := serviceConfig.K8s.ImagePullSecret.Name
+	if secretName == "" {
+		secretName = fmt.Sprintf("%s-registry", serviceConfig.Name)
+	}
+
+	return secretName
+}
+
+func (t *aksTarget) getImagePullServiceAccountName(serviceConfig *ServiceConfig) string {
+	serviceAccountName := serviceConfig.K8s.ImagePullSecret.ServiceAccount
+	if serviceAccountName != "" {
+		return serviceAccountName
+	}
+
+	// Pods using workload identity run as the workload identity service account
+	if serviceConfig.K8s.WorkloadIdentity != nil {
+		if serviceConfig.K8s.WorkloadIdentity.ServiceAccount != "" {
+			return serviceConfig.K8s.WorkloadIdentity.ServiceAccount
+		}
+
+		return serviceConfig.Name
+	}
+
+	return "default"
+}
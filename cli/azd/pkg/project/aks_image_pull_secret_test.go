@@ -0,0 +1,285 @@
+package project
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Deploy_ImagePullSecret(t *testing.T) {
+	tests := map[string]struct {
+		options            *AksImagePullSecretOptions
+		credentials        *RegistryCredentialsOptions
+		dockerConfig       string
+		image              string
+		serviceAccounts    []*kubectl.ServiceAccount
+		expectedServer     string
+		expectedUsername   string
+		expectedPassword   string
+		expectedCreate     bool
+		expectedPatch      string
+		expectedPatchedSa  string
+		expectedSecretName string
+	}{
+		"AzureContainerRegistry": {
+			options:            &AksImagePullSecretOptions{},
+			serviceAccounts:    []*kubectl.ServiceAccount{createServiceAccount("default")},
+			expectedServer:     "REGISTRY.azurecr.io",
+			expectedUsername:   "00000000-0000-0000-0000-000000000000",
+			expectedPassword:   "REFRESH_TOKEN",
+			expectedPatch:      `{"imagePullSecrets":[{"name":"api-registry"}]}`,
+			expectedPatchedSa:  "default",
+			expectedSecretName: "api-registry",
+		},
+		"PrebuiltImageCredentials": {
+			options: &AksImagePullSecretOptions{
+				Name:     "ghcr",
+				Username: osutil.NewExpandableString("octocat"),
+				Password: osutil.NewExpandableString("${GHCR_TOKEN}"),
+			},
+			image:              "ghcr.io/contoso/api:1.0.0",
+			serviceAccounts:    []*kubectl.ServiceAccount{createServiceAccount("default", "other")},
+			expectedServer:     "ghcr.io",
+			expectedUsername:   "octocat",
+			expectedPassword:   "GHCR_TOKEN_VALUE",
+			expectedPatch:      `{"imagePullSecrets":[{"name":"other"},{"name":"ghcr"}]}`,
+			expectedPatchedSa:  "default",
+			expectedSecretName: "ghcr",
+		},
+		"DockerConfigCredentials": {
+			options: &AksImagePullSecretOptions{},
+			// base64 of octocat:LOGIN_TOKEN
+			dockerConfig:       `{"auths":{"ghcr.io":{"auth":"b2N0b2NhdDpMT0dJTl9UT0tFTg=="}}}`,
+			image:              "ghcr.io/contoso/api:1.0.0",
+			serviceAccounts:    []*kubectl.ServiceAccount{createServiceAccount("default")},
+			expectedServer:     "ghcr.io",
+			expectedUsername:   "octocat",
+			expectedPassword:   "LOGIN_TOKEN",
+			expectedPatch:      `{"imagePullSecrets":[{"name":"api-registry"}]}`,
+			expectedPatchedSa:  "default",
+			expectedSecretName: "api-registry",
+		},
+		"DockerCredentials": {
+			options: &AksImagePullSecretOptions{},
+			credentials: &RegistryCredentialsOptions{
+				Username: osutil.NewExpandableString("octocat"),
+				Password: osutil.NewExpandableString("${GHCR_TOKEN}"),
+			},
+			image:              "ghcr.io/contoso/api:1.0.0",
+			serviceAccounts:    []*kubectl.ServiceAccount{createServiceAccount("default")},
+			expectedServer:     "ghcr.io",
+			expectedUsername:   "octocat",
+			expectedPassword:   "GHCR_TOKEN_VALUE",
+			expectedPatch:      `{"imagePullSecrets":[{"name":"api-registry"}]}`,
+			expectedPatchedSa:  "default",
+			expectedSecretName: "api-registry",
+		},
+		"MissingServiceAccount": {
+			options: &AksImagePullSecretOptions{
+				ServiceAccount: "api-sa",
+			},
+			serviceAccounts:    []*kubectl.ServiceAccount{createServiceAccount("default")},
+			expectedServer:     "REGISTRY.azurecr.io",
+			expectedUsername:   "00000000-0000-0000-0000-000000000000",
+			expectedPassword:   "REFRESH_TOKEN",
+			expectedCreate:     true,
+			expectedPatch:      `{"imagePullSecrets":[{"name":"api-registry"}]}`,
+			expectedPatchedSa:  "api-sa",
+			expectedSecretName: "api-registry",
+		},
+		"AlreadyReferenced": {
+			options:            &AksImagePullSecretOptions{},
+			serviceAccounts:    []*kubectl.ServiceAccount{createServiceAccount("default", "api-registry")},
+			expectedServer:     "REGISTRY.azurecr.io",
+			expectedUsername:   "00000000-0000-0000-0000-000000000000",
+			expectedPassword:   "REFRESH_TOKEN",
+			expectedSecretName: "api-registry",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+			setupDockerConfig(t, test.dockerConfig)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			recorder := setupImagePullSecretMocks(mockContext, test.serviceAccounts)
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.ImagePullSecret = test.options
+			serviceConfig.Docker.Credentials = test.credentials
+			if test.image != "" {
+				serviceConfig.K8s.Image = osutil.NewExpandableString(test.image)
+			}
+
+			env := createEnv()
+			env.DotenvSet("GHCR_TOKEN", "GHCR_TOKEN_VALUE")
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+			err = setupK8sManifests(t, serviceConfig)
+			require.NoError(t, err)
+
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+				return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+			})
+			require.NoError(t, err)
+
+			require.NotNil(t, recorder.secret)
+			require.Equal(t, test.expectedSecretName, recorder.secret.Metadata.Name)
+			require.Equal(t, kubectl.SecretTypeDockerConfigJson, recorder.secret.Type)
+
+			dockerConfig := struct {
+				Auths map[string]struct {
+					Username string `json:"username"`
+					Password string `json:"password"`
+				} `json:"auths"`
+			}{}
+			dockerConfigBytes, err := base64.StdEncoding.DecodeString(recorder.secret.Data[".dockerconfigjson"])
+			require.NoError(t, err)
+			err = json.Unmarshal(dockerConfigBytes, &dockerConfig)
+			require.NoError(t, err)
+			require.Contains(t, dockerConfig.Auths, test.expectedServer)
+			require.Equal(t, test.expectedUsername, dockerConfig.Auths[test.expectedServer].Username)
+			require.Equal(t, test.expectedPassword, dockerConfig.Auths[test.expectedServer].Password)
+
+			require.Equal(t, test.expectedCreate, recorder.createdServiceAccount != "")
+			require.Equal(t, test.expectedPatch, recorder.patch)
+			require.Equal(t, test.expectedPatchedSa, recorder.patchedServiceAccount)
+		})
+	}
+}
+
+func Test_Deploy_ImagePullSecret_Missing_Credentials(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+	setupDockerConfig(t, "")
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Image = osutil.NewExpandableString("ghcr.io/contoso/api:1.0.0")
+	serviceConfig.K8s.ImagePullSecret = &AksImagePullSecretOptions{}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+	})
+
+	require.ErrorContains(t, err, "missing 'username' or 'password' for the image pull secret of registry 'ghcr.io'")
+}
+
+// setupDockerConfig points the container runtime to a docker config file with the specified content
+func setupDockerConfig(t *testing.T, content string) {
+	configDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", configDir)
+	t.Setenv(docker.ContainerRuntimeEnvVarName, string(docker.RuntimeDocker))
+
+	if content != "" {
+		err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(content), osutil.PermissionFile)
+		require.NoError(t, err)
+	}
+}
+
+type imagePullSecretRecorder struct {
+	secret                *kubectl.Secret
+	createdServiceAccount string
+	patchedServiceAccount string
+	patch                 string
+}
+
+func setupImagePullSecretMocks(
+	mockContext *mocks.MockContext,
+	serviceAccounts []*kubectl.ServiceAccount,
+) *imagePullSecretRecorder {
+	recorder := &imagePullSecretRecorder{}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		input, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		if strings.Contains(string(input), "kind: Secret") {
+			recorder.secret = &kubectl.Secret{}
+			if err := yaml.Unmarshal(input, recorder.secret); err != nil {
+				return exec.NewRunResult(1, "", ""), err
+			}
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get sa")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		list := &kubectl.List[*kubectl.ServiceAccount]{Items: serviceAccounts}
+		jsonBytes, _ := json.Marshal(list)
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl create serviceaccount")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		recorder.createdServiceAccount = args.Args[2]
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl patch sa")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		recorder.patchedServiceAccount = args.Args[2]
+		recorder.patch = args.Args[len(args.Args)-1]
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	return recorder
+}
+
+func createServiceAccount(name string, imagePullSecrets ...string) *kubectl.ServiceAccount {
+	serviceAccount := &kubectl.ServiceAccount{
+		Resource: kubectl.Resource{
+			ApiVersion: "v1",
+			Kind:       "ServiceAccount",
+			Metadata:   kubectl.ResourceMetadata{Name: name},
+		},
+	}
+
+	for _, secretName := range imagePullSecrets {
+		serviceAccount.ImagePullSecrets = append(
+			serviceAccount.ImagePullSecrets,
+			kubectl.LocalObjectReference{Name: secretName},
+		)
+	}
+
+	return serviceAccount
+}
@@ -0,0 +1,200 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// The name of the AKS add-on profile of the Azure Key Vault provider for the Secrets Store CSI driver
+	keyVaultSecretsProviderAddon  = "azureKeyvaultSecretsProvider"
+	secretProviderClassApiVersion = "secrets-store.csi.x-k8s.io/v1"
+)
+
+// The AKS Key Vault options
+// When configured, a SecretProviderClass referencing the Key Vault secrets is applied before the k8s resources
+// of the service are deployed so that workloads can mount the secrets with the Secrets Store CSI driver.
+// The secret values are never written to the azd environment.
+type AksKeyVaultOptions struct {
+	// The name of the SecretProviderClass. Defaults to '<service>-keyvault'
+	Name string `yaml:"name"`
+	// The name of the Key Vault
+	VaultName osutil.ExpandableString `yaml:"vaultName"`
+	// The tenant of the Key Vault. Defaults to the azd environment tenant
+	TenantId osutil.ExpandableString `yaml:"tenantId"`
+	// The client id of the identity used to access the Key Vault. Defaults to the workload identity client id
+	ClientId osutil.ExpandableString `yaml:"clientId"`
+	// The Key Vault secrets mounted by the workloads
+	Secrets []AksKeyVaultSecret `yaml:"secrets"`
+	// When set, the secrets are also synced to a k8s Secret with the specified name while mounted by a pod
+	SecretName string `yaml:"secretName"`
+}
+
+// A secret stored within the Key Vault
+type AksKeyVaultSecret struct {
+	// The name of the Key Vault secret
+	Name string `yaml:"name"`
+	// The file name of the mounted secret and key of the synced k8s Secret. Defaults to the secret name
+	Key string `yaml:"key"`
+}
+
+// ensureSecretProviderClass verifies the Key Vault secrets provider add-on is enabled on the AKS cluster and applies
+// the SecretProviderClass of the service
+func (t *aksTarget) ensureSecretProviderClass(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	cluster aksCluster,
+) error {
+	managedCluster, err := t.managedClustersService.Get(ctx, cluster.subscriptionId, cluster.resourceGroupName, cluster.name)
+	if err != nil {
+		return fmt.Errorf("failed retrieving managed cluster, %w", err)
+	}
+
+	enabled := false
+	if managedCluster.Properties != nil {
+		if addon, has := managedCluster.Properties.AddonProfiles[keyVaultSecretsProviderAddon]; has && addon != nil {
+			enabled = convert.ToValueWithDefault(addon.Enabled, false)
+		}
+	}
+
+	if !enabled {
+		return fmt.Errorf(
+			"the Azure Key Vault secrets provider add-on is not enabled on AKS cluster '%s'. Enable the add-on with "+
+				"'az aks enable-addons --addons azure-keyvault-secrets-provider' to use Key Vault secrets",
+			cluster.name,
+		)
+	}
+
+	secretProviderClass, err := t.createSecretProviderClass(serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := yaml.Marshal(secretProviderClass)
+	if err != nil {
+		return fmt.Errorf("failed marshalling SecretProviderClass, %w", err)
+	}
+
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, string(manifest), nil); err != nil {
+		return fmt.Errorf("failed applying SecretProviderClass '%s', %w", secretProviderClass.Metadata.Name, err)
+	}
+
+	return nil
+}
+
+// createSecretProviderClass creates the SecretProviderClass for the Key Vault secrets of the service
+func (t *aksTarget) createSecretProviderClass(serviceConfig *ServiceConfig) (*kubectl.SecretProviderClass, error) {
+	options := serviceConfig.K8s.KeyVault
+
+	vaultName, err := options.VaultName.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst Key Vault name: %w", err)
+	}
+
+	if vaultName == "" {
+		return nil, errors.New("missing 'vaultName' for the Key Vault configuration")
+	}
+
+	if len(options.Secrets) == 0 {
+		return nil, errors.New("missing 'secrets' for the Key Vault configuration")
+	}
+
+	tenantId, err := options.TenantId.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst Key Vault tenant id: %w", err)
+	}
+
+	if tenantId == "" {
+		tenantId = t.env.GetTenantId()
+	}
+
+	clientId, err := options.ClientId.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst Key Vault client id: %w", err)
+	}
+
+	// The workload identity client id is stored on the service once the workload identity is configured
+	if clientId == "" {
+		clientId = t.env.GetServiceProperty(serviceConfig.Name, "IDENTITY_CLIENT_ID")
+	}
+
+	if clientId == "" {
+		return nil, errors.New(
+			"missing 'clientId' for the Key Vault configuration. Set the client id or configure a workload identity",
+		)
+	}
+
+	objects := make([]string, 0, len(options.Secrets))
+	secretObject := kubectl.SecretObject{
+		SecretName: options.SecretName,
+		Type:       kubectl.SecretTypeOpaque,
+	}
+
+	for index, secret := range options.Secrets {
+		if secret.Name == "" {
+			return nil, fmt.Errorf("missing 'name' for Key Vault secret at index %d", index)
+		}
+
+		key := secret.Key
+		if key == "" {
+			key = secret.Name
+		}
+
+		object, err := yaml.Marshal(map[string]string{
+			"objectName":  secret.Name,
+			"objectType":  "secret",
+			"objectAlias": key,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed marshalling Key Vault object, %w", err)
+		}
+
+		objects = append(objects, string(object))
+		secretObject.Data = append(secretObject.Data, kubectl.SecretObjectData{ObjectName: key, Key: key})
+	}
+
+	// The provider expects the objects as a yaml document with an array of yaml strings
+	objectsYaml, err := yaml.Marshal(map[string][]string{"array": objects})
+	if err != nil {
+		return nil, fmt.Errorf("failed marshalling Key Vault objects, %w", err)
+	}
+
+	name := options.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-keyvault", serviceConfig.Name)
+	}
+
+	secretProviderClass := &kubectl.SecretProviderClass{
+		Resource: kubectl.Resource{
+			ApiVersion: secretProviderClassApiVersion,
+			Kind:       "SecretProviderClass",
+			Metadata: kubectl.ResourceMetadata{
+				Name:      name,
+				Namespace: t.getK8sNamespace(serviceConfig),
+			},
+		},
+		Spec: kubectl.SecretProviderClassSpec{
+			Provider: "azure",
+			Parameters: map[string]string{
+				"usePodIdentity": "false",
+				"clientID":       clientId,
+				"keyvaultName":   vaultName,
+				"tenantId":       tenantId,
+				"objects":        strings.TrimSpace(string(objectsYaml)),
+			},
+		},
+	}
+
+	if options.SecretName != "" {
+		secretProviderClass.Spec.SecretObjects = []kubectl.SecretObject{secretObject}
+	}
+
+	return secretProviderClass, nil
+}
@@ -0,0 +1,178 @@
+package project
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Deploy_KeyVault(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	setupKeyVaultClusterMock(mockContext, true)
+
+	var applied *kubectl.SecretProviderClass
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		input, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		if strings.Contains(string(input), "kind: SecretProviderClass") {
+			applied = &kubectl.SecretProviderClass{}
+			if err := yaml.Unmarshal(input, applied); err != nil {
+				return exec.NewRunResult(1, "", ""), err
+			}
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.KeyVault = &AksKeyVaultOptions{
+		VaultName: osutil.NewExpandableString("${AZURE_KEY_VAULT_NAME}"),
+		ClientId:  osutil.NewExpandableString("CLIENT_ID"),
+		Secrets: []AksKeyVaultSecret{
+			{Name: "db-password"},
+			{Name: "api-key", Key: "API_KEY"},
+		},
+		SecretName: "api-secrets",
+	}
+	env := createEnv()
+	env.DotenvSet("AZURE_KEY_VAULT_NAME", "kv-test")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, applied)
+	require.Equal(t, "api-keyvault", applied.Metadata.Name)
+	require.Equal(t, "azure", applied.Spec.Provider)
+	require.Equal(t, "kv-test", applied.Spec.Parameters["keyvaultName"])
+	require.Equal(t, "TENANT_ID", applied.Spec.Parameters["tenantId"])
+	require.Equal(t, "CLIENT_ID", applied.Spec.Parameters["clientID"])
+
+	objects := struct {
+		Array []string `yaml:"array"`
+	}{}
+	err = yaml.Unmarshal([]byte(applied.Spec.Parameters["objects"]), &objects)
+	require.NoError(t, err)
+	require.Len(t, objects.Array, 2)
+
+	object := map[string]string{}
+	err = yaml.Unmarshal([]byte(objects.Array[1]), &object)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"objectName":  "api-key",
+		"objectType":  "secret",
+		"objectAlias": "API_KEY",
+	}, object)
+
+	require.Len(t, applied.Spec.SecretObjects, 1)
+	require.Equal(t, "api-secrets", applied.Spec.SecretObjects[0].SecretName)
+	require.Equal(t, []kubectl.SecretObjectData{
+		{ObjectName: "db-password", Key: "db-password"},
+		{ObjectName: "API_KEY", Key: "API_KEY"},
+	}, applied.Spec.SecretObjects[0].Data)
+}
+
+func Test_Deploy_KeyVault_Addon_Disabled(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	setupKeyVaultClusterMock(mockContext, false)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.KeyVault = &AksKeyVaultOptions{
+		VaultName: osutil.NewExpandableString("kv-test"),
+		ClientId:  osutil.NewExpandableString("CLIENT_ID"),
+		Secrets:   []AksKeyVaultSecret{{Name: "db-password"}},
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+	})
+
+	require.ErrorContains(t, err, "the Azure Key Vault secrets provider add-on is not enabled on AKS cluster 'AKS_CLUSTER'")
+}
+
+func Test_CreateSecretProviderClass_Missing_ClientId(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.KeyVault = &AksKeyVaultOptions{
+		VaultName: osutil.NewExpandableString("kv-test"),
+		Secrets:   []AksKeyVaultSecret{{Name: "db-password"}},
+	}
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil).(*aksTarget)
+
+	_, err := serviceTarget.createSecretProviderClass(serviceConfig)
+	require.ErrorContains(t, err, "missing 'clientId' for the Key Vault configuration")
+
+	// The client id of the workload identity is used by default
+	serviceTarget.env.SetServiceProperty(serviceConfig.Name, "IDENTITY_CLIENT_ID", "WORKLOAD_CLIENT_ID")
+	secretProviderClass, err := serviceTarget.createSecretProviderClass(serviceConfig)
+	require.NoError(t, err)
+	require.Equal(t, "WORKLOAD_CLIENT_ID", secretProviderClass.Spec.Parameters["clientID"])
+	require.Empty(t, secretProviderClass.Spec.SecretObjects)
+}
+
+func setupKeyVaultClusterMock(mockContext *mocks.MockContext, addonEnabled bool) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(
+			request.URL.Path,
+			"Microsoft.ContainerService/managedClusters/AKS_CLUSTER",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		managedCluster := armcontainerservice.ManagedCluster{
+			ID:       to.Ptr("cluster1"),
+			Location: to.Ptr("eastus2"),
+			Type:     to.Ptr("Microsoft.ContainerService/managedClusters"),
+			Properties: &armcontainerservice.ManagedClusterProperties{
+				EnableRBAC:           to.Ptr(true),
+				DisableLocalAccounts: to.Ptr(false),
+				AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+					keyVaultSecretsProviderAddon: {Enabled: to.Ptr(addonEnabled)},
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, managedCluster)
+	})
+}
@@ -0,0 +1,149 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
+)
+
+// The environment config path of the kube contexts azd created for the AKS clusters of the environment
+const aksKubeContextsConfigPath = "aks.kubeContexts"
+
+// AksKubeContext is a kube context azd merged into the default kube config when connecting to an AKS cluster
+type AksKubeContext struct {
+	// The name of the kube config file saved for the cluster within the .kube directory
+	ConfigName string `json:"configName"`
+	// The name of the context
+	Name string `json:"name"`
+	// The name of the cluster referenced by the context
+	Cluster string `json:"cluster"`
+	// The name of the user referenced by the context
+	User string `json:"user"`
+}
+
+// KubeContexts returns the kube contexts azd created for the AKS clusters of the environment
+func KubeContexts(env *environment.Environment) ([]AksKubeContext, error) {
+	kubeContexts := []AksKubeContext{}
+	if _, err := env.Config.GetSection(aksKubeContextsConfigPath, &kubeContexts); err != nil {
+		return nil, fmt.Errorf("failed reading kube contexts of environment '%s', %w", env.Name(), err)
+	}
+
+	return kubeContexts, nil
+}
+
+// RemoveKubeContexts removes the kube contexts azd created for the environment from the default kube config
+// and returns the names of the removed contexts
+func RemoveKubeContexts(
+	ctx context.Context,
+	env *environment.Environment,
+	envManager environment.Manager,
+	kubectlCli *kubectl.Cli,
+) ([]string, error) {
+	kubeContexts, err := KubeContexts(env)
+	if err != nil || len(kubeContexts) == 0 {
+		return nil, err
+	}
+
+	kubeConfigManager, err := kubectl.NewKubeConfigManager(kubectlCli)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	for _, kubeContext := range kubeContexts {
+		err := kubeConfigManager.RemoveContext(ctx, kubeContext.ConfigName, &kubectl.KubeContext{
+			Name: kubeContext.Name,
+			Context: kubectl.KubeContextData{
+				Cluster: kubeContext.Cluster,
+				User:    kubeContext.User,
+			},
+		})
+		if err != nil {
+			return removed, fmt.Errorf("failed removing kube context '%s', %w", kubeContext.Name, err)
+		}
+
+		removed = append(removed, kubeContext.Name)
+	}
+
+	if err := env.Config.Unset(aksKubeContextsConfigPath); err != nil {
+		return removed, err
+	}
+
+	if err := envManager.Save(ctx, env); err != nil {
+		return removed, fmt.Errorf("failed saving environment, %w", err)
+	}
+
+	return removed, nil
+}
+
+// trackKubeContext records the kube context merged into the default kube config within the environment config
+// so that it can be removed once the environment or the cluster is deleted
+func (t *aksTarget) trackKubeContext(ctx context.Context, configName string, kubeConfig *kubectl.KubeConfig) error {
+	if len(kubeConfig.Contexts) == 0 {
+		return nil
+	}
+
+	kubeContexts, err := KubeContexts(t.env)
+	if err != nil {
+		return err
+	}
+
+	kubeContext := AksKubeContext{
+		ConfigName: configName,
+		Name:       kubeConfig.Contexts[0].Name,
+		Cluster:    kubeConfig.Contexts[0].Context.Cluster,
+		User:       kubeConfig.Contexts[0].Context.User,
+	}
+
+	if slices.Contains(kubeContexts, kubeContext) {
+		return nil
+	}
+
+	if err := t.env.Config.Set(aksKubeContextsConfigPath, append(kubeContexts, kubeContext)); err != nil {
+		return err
+	}
+
+	if err := t.envManager.Save(ctx, t.env); err != nil {
+		return fmt.Errorf("failed saving environment, %w", err)
+	}
+
+	return nil
+}
+
+// isolatedKubeConfigPath returns the path of the kube config dedicated to the azd environment,
+// ex) .azure/<environment>/kube/config
+func (t *aksTarget) isolatedKubeConfigPath() string {
+	return filepath.Join(filepath.Dir(t.envManager.EnvPath(t.env)), "kube", "config")
+}
+
+// saveIsolatedKubeConfig writes the cluster kube config to the azd environment directory with the cluster context
+// as the current context. The default kube config of the user is not read or modified.
+func (t *aksTarget) saveIsolatedKubeConfig(kubeConfig *kubectl.KubeConfig) (string, error) {
+	if len(kubeConfig.Contexts) > 0 {
+		kubeConfig.CurrentContext = kubeConfig.Contexts[0].Name
+	}
+
+	kubeConfigRaw, err := yaml.Marshal(kubeConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed marshalling kube config to yaml: %w", err)
+	}
+
+	kubeConfigPath := t.isolatedKubeConfigPath()
+	if err := os.MkdirAll(filepath.Dir(kubeConfigPath), osutil.PermissionDirectoryOwnerOnly); err != nil {
+		return "", fmt.Errorf("failed creating kube config directory, %w", err)
+	}
+
+	// The kube config contains the cluster credentials and is only readable by the current user
+	if err := os.WriteFile(kubeConfigPath, kubeConfigRaw, osutil.PermissionFileOwnerOnly); err != nil {
+		return "", fmt.Errorf("failed writing kube config file: %w", err)
+	}
+
+	return kubeConfigPath, nil
+}
@@ -0,0 +1,114 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_Isolated_KubeConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	configCommands := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl config")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		configCommands = append(configCommands, strings.Join(args.Args, " "))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	var applyArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		applyArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	// The default kube config and its current context are left untouched
+	require.Empty(t, configCommands)
+	require.NoDirExists(t, filepath.Join(homeDir, ".kube"))
+
+	kubeConfigPath := filepath.Join(".azure", "test", "kube", "config")
+	require.Equal(t, []string{"--kubeconfig", kubeConfigPath}, applyArgs[len(applyArgs)-2:])
+
+	kubeConfigRaw, err := os.ReadFile(kubeConfigPath)
+	require.NoError(t, err)
+
+	kubeConfig, err := kubectl.ParseKubeConfig(*mockContext.Context, kubeConfigRaw)
+	require.NoError(t, err)
+	require.Equal(t, "cluster1", kubeConfig.CurrentContext)
+	require.Equal(t, "Test-App", kubeConfig.Contexts[0].Context.Namespace)
+}
+
+func Test_Deploy_Remove_KubeContexts(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.IsolatedKubeConfig = to.Ptr(false)
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	kubeContexts, err := KubeContexts(env)
+	require.NoError(t, err)
+	require.Equal(t, []AksKubeContext{
+		{ConfigName: "AKS_CLUSTER", Name: "cluster1", Cluster: "cluster1", User: "cluster1_user1"},
+	}, kubeContexts)
+	require.FileExists(t, filepath.Join(homeDir, ".kube", "AKS_CLUSTER"))
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	removed, err := RemoveKubeContexts(*mockContext.Context, env, envManager, kubectl.NewCli(mockContext.CommandRunner))
+	require.NoError(t, err)
+	require.Equal(t, []string{"cluster1"}, removed)
+	require.NoFileExists(t, filepath.Join(homeDir, ".kube", "AKS_CLUSTER"))
+	envManager.AssertCalled(t, "Save", *mockContext.Context, env)
+
+	kubeContexts, err = KubeContexts(env)
+	require.NoError(t, err)
+	require.Empty(t, kubeContexts)
+}
+
+// withIsolatedKubeConfig appends the kube config of the test azd environment passed to kubectl and helm commands
+func withIsolatedKubeConfig(args ...string) []string {
+	return append(args, "--kubeconfig", filepath.Join(".azure", "test", "kube", "config"))
+}
@@ -0,0 +1,28 @@
+package project
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// Runs kubectl with the arguments as is, equivalent to 'kubectl <args>'
+// The cluster context of the service is configured first, so the commands run against the cluster and
+// namespace of the service unless the arguments specify otherwise, ex) with --namespace.
+func (t *aksTarget) RunKubectl(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	args []string,
+) error {
+	namespace, err := t.useServiceCluster(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("running 'kubectl %s' in namespace '%s'", strings.Join(args, " "), namespace)
+
+	return t.kubectl.Passthrough(ctx, args)
+}
@@ -0,0 +1,55 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunKubectl_Aks(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	var kubectlArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl rollout restart")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		kubectlArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil)
+	err = serviceTarget.Initialize(*mockContext.Context, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	err = serviceTarget.(ServiceTargetKubectlRunner).RunKubectl(
+		*mockContext.Context, serviceConfig, scope, []string{"rollout", "restart", "deployment/api"},
+	)
+	require.NoError(t, err)
+	require.True(t, kubectlArgs.Interactive)
+	require.Equal(t, withIsolatedKubeConfig("rollout", "restart", "deployment/api"), kubectlArgs.Args)
+
+	// The namespace of the service is the default namespace of the kube config passed to kubectl
+	kubeConfigRaw, err := os.ReadFile(filepath.Join(".azure", "test", "kube", "config"))
+	require.NoError(t, err)
+
+	kubeConfig, err := kubectl.ParseKubeConfig(*mockContext.Context, kubeConfigRaw)
+	require.NoError(t, err)
+	require.Equal(t, "Test-App", kubeConfig.Contexts[0].Context.Namespace)
+}
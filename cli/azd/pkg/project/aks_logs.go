@@ -0,0 +1,65 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// Streams the logs of the pods of the service deployment, equivalent to 'kubectl logs' for each pod
+// The deployment is resolved the same way it is when waiting for a deployment, and the logs of each of its pods and
+// containers are multiplexed with a '[<pod>/<container>]' prefix.
+func (t *aksTarget) StreamLogs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options ServiceLogsOptions,
+) error {
+	namespace, err := t.useServiceCluster(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return err
+	}
+
+	pods, err := t.getServiceDeploymentPods(ctx, serviceConfig, namespace)
+	if err != nil {
+		return err
+	}
+
+	sources := []serviceLogSource{}
+	for _, pod := range pods {
+		containers := []string{options.Container}
+		if options.Container == "" && len(pod.Status.ContainerStatuses) > 0 {
+			containers = []string{}
+			for _, status := range pod.Status.ContainerStatuses {
+				containers = append(containers, status.Name)
+			}
+		}
+
+		for _, container := range containers {
+			name := pod.Metadata.Name
+			if container != "" {
+				name = fmt.Sprintf("%s/%s", pod.Metadata.Name, container)
+			}
+
+			logsOptions := &kubectl.LogsOptions{
+				Container: container,
+				Follow:    options.Follow,
+				Since:     options.Since,
+				Tail:      options.Tail,
+			}
+			resource := fmt.Sprintf("pod/%s", pod.Metadata.Name)
+
+			sources = append(sources, serviceLogSource{
+				name: name,
+				stream: func(ctx context.Context, writer io.Writer) error {
+					return t.kubectl.StreamLogs(ctx, resource, logsOptions, nil, writer)
+				},
+			})
+		}
+	}
+
+	return streamServiceLogs(ctx, sources, options.Writer)
+}
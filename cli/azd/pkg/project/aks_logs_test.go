@@ -0,0 +1,131 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StreamLogs_Aks(t *testing.T) {
+	tests := map[string]struct {
+		container     string
+		expectedLines []string
+		expectedArgs  []string
+	}{
+		"AllContainers": {
+			expectedLines: []string{
+				"[api-5d8f7b-abcde/app] logs of api-5d8f7b-abcde/app",
+				"[api-5d8f7b-abcde/istio-proxy] logs of api-5d8f7b-abcde/istio-proxy",
+				"[api-5d8f7b-fghij/app] logs of api-5d8f7b-fghij/app",
+			},
+			expectedArgs: []string{"logs", "pod/api-5d8f7b-abcde", "-c", "app", "--follow", "--since=10m0s"},
+		},
+		"Container": {
+			container: "app",
+			expectedLines: []string{
+				"[api-5d8f7b-abcde/app] logs of api-5d8f7b-abcde/app",
+				"[api-5d8f7b-fghij/app] logs of api-5d8f7b-fghij/app",
+			},
+			expectedArgs: []string{"logs", "pod/api-5d8f7b-abcde", "-c", "app", "--follow", "--since=10m0s"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get deployment")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				deployments := kubectl.List[kubectl.Deployment]{
+					Items: []kubectl.Deployment{
+						{Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "worker"}}},
+						{Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "api"}}},
+					},
+				}
+				jsonBytes, _ := json.Marshal(deployments)
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get pods")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				sidecarPod := createSidecarPod("api-5d8f7b-abcde", "istio-proxy", true)
+				pods := kubectl.List[kubectl.Pod]{
+					Items: []kubectl.Pod{
+						sidecarPod,
+						createPod("api-5d8f7b-fghij", "Running", kubectl.ContainerState{}, true),
+						createPod("worker-7c9d6e-klmno", "Running", kubectl.ContainerState{}, true),
+					},
+				}
+				jsonBytes, _ := json.Marshal(pods)
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			var mu sync.Mutex
+			logArgs := [][]string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl logs")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				mu.Lock()
+				logArgs = append(logArgs, args.Args)
+				mu.Unlock()
+
+				pod := strings.TrimPrefix(args.Args[1], "pod/")
+				_, err := args.StdOut.Write([]byte("logs of " + pod + "/" + args.Args[3] + "\n"))
+				require.NoError(t, err)
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil)
+			err = serviceTarget.Initialize(*mockContext.Context, serviceConfig)
+			require.NoError(t, err)
+
+			output := &strings.Builder{}
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			err = serviceTarget.(ServiceTargetLogStreamer).StreamLogs(
+				*mockContext.Context,
+				serviceConfig,
+				scope,
+				ServiceLogsOptions{
+					Container: test.container,
+					Follow:    true,
+					Since:     10 * time.Minute,
+					Writer:    output,
+				},
+			)
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+			sort.Strings(lines)
+			require.Equal(t, test.expectedLines, lines)
+
+			sort.Slice(logArgs, func(i, j int) bool {
+				return strings.Join(logArgs[i], " ") < strings.Join(logArgs[j], " ")
+			})
+			require.Len(t, logArgs, len(test.expectedLines))
+			require.Equal(t, withIsolatedKubeConfig(test.expectedArgs...), logArgs[0])
+		})
+	}
+}
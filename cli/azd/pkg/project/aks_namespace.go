@@ -0,0 +1,180 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The AKS namespace options applied to the namespace created for the service
+type AksNamespaceOptions struct {
+	// The labels of the namespace, ex) istio-injection: enabled
+	Labels map[string]string `yaml:"labels"`
+	// The annotations of the namespace
+	Annotations map[string]string `yaml:"annotations"`
+	// The hard limits of the ResourceQuota created within the namespace, ex) requests.cpu: "4"
+	ResourceQuota map[string]string `yaml:"resourceQuota"`
+	// The container limits of the LimitRange created within the namespace
+	LimitRange *AksLimitRangeOptions `yaml:"limitRange"`
+}
+
+// The container limits of the AKS namespace LimitRange
+type AksLimitRangeOptions struct {
+	Default        map[string]string `yaml:"default"`
+	DefaultRequest map[string]string `yaml:"defaultRequest"`
+	Max            map[string]string `yaml:"max"`
+	Min            map[string]string `yaml:"min"`
+}
+
+// ensureServiceNamespace creates the namespace of the service
+// When namespace options are configured, the labels, annotations, ResourceQuota and LimitRange are applied as well
+// and updated on every deployment to match the service configuration, along with the service mesh injection labels
+func (t *aksTarget) ensureServiceNamespace(ctx context.Context, serviceConfig *ServiceConfig) error {
+	namespace := t.getK8sNamespace(serviceConfig)
+	if serviceConfig.K8s.NamespaceConfig == nil && serviceConfig.K8s.ServiceMesh == nil {
+		return t.ensureNamespace(ctx, namespace)
+	}
+
+	options, err := withServiceMesh(serviceConfig.K8s.NamespaceConfig, serviceConfig.K8s.ServiceMesh)
+	if err != nil {
+		return err
+	}
+
+	objects := []k8sObject{namespaceObject(namespace, options)}
+
+	if len(options.ResourceQuota) > 0 {
+		objects = append(objects, k8sObject{
+			"apiVersion": "v1",
+			"kind":       "ResourceQuota",
+			"metadata": map[string]any{
+				"name":      fmt.Sprintf("%s-quota", namespace),
+				"namespace": namespace,
+			},
+			"spec": map[string]any{
+				"hard": options.ResourceQuota,
+			},
+		})
+	}
+
+	if options.LimitRange != nil {
+		limit := map[string]any{"type": "Container"}
+		for key, values := range map[string]map[string]string{
+			"default":        options.LimitRange.Default,
+			"defaultRequest": options.LimitRange.DefaultRequest,
+			"max":            options.LimitRange.Max,
+			"min":            options.LimitRange.Min,
+		} {
+			if len(values) > 0 {
+				limit[key] = values
+			}
+		}
+
+		objects = append(objects, k8sObject{
+			"apiVersion": "v1",
+			"kind":       "LimitRange",
+			"metadata": map[string]any{
+				"name":      fmt.Sprintf("%s-limits", namespace),
+				"namespace": namespace,
+			},
+			"spec": map[string]any{
+				"limits": []any{limit},
+			},
+		})
+	}
+
+	manifest, err := marshalK8sObjects(objects)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, manifest, nil); err != nil {
+		return fmt.Errorf("failed applying kube namespace: %w", err)
+	}
+
+	return nil
+}
+
+// namespaceObject returns the k8s namespace with the configured labels and annotations
+func namespaceObject(namespace string, options *AksNamespaceOptions) k8sObject {
+	metadata := map[string]any{
+		"name": namespace,
+	}
+
+	if len(options.Labels) > 0 {
+		metadata["labels"] = options.Labels
+	}
+
+	if len(options.Annotations) > 0 {
+		metadata["annotations"] = options.Annotations
+	}
+
+	return k8sObject{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   metadata,
+	}
+}
+
+// The placeholders of the namespace template, ex) {envName} or {PR_NUMBER}
+var namespaceTemplateRegex = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// The characters that are not allowed within a k8s namespace name
+var invalidNamespaceCharsRegex = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// The maximum length of a k8s namespace name
+const maxNamespaceLength = 63
+
+// resolveNamespaceTemplate computes the namespace of the service from the namespace template, ex) {projectName}-{envName}
+// The {projectName}, {envName} and {serviceName} placeholders are replaced with the names of the project, azd environment
+// and service. Any other placeholder is replaced with the value of the environment variable, ex) {PR_NUMBER}.
+// The result is converted to a valid namespace name. An empty value is returned when the template is not used.
+func (t *aksTarget) resolveNamespaceTemplate(serviceConfig *ServiceConfig) (string, error) {
+	template := serviceConfig.K8s.NamespaceTemplate
+	if template == "" || serviceConfig.K8s.Namespace != "" {
+		return "", nil
+	}
+
+	var missing []string
+	resolved := namespaceTemplateRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := strings.Trim(placeholder, "{}")
+		var value string
+		switch name {
+		case "projectName":
+			value = serviceConfig.Project.Name
+		case "envName":
+			value = t.env.Name()
+		case "serviceName":
+			value = serviceConfig.Name
+		default:
+			value = t.env.Getenv(name)
+		}
+
+		if value == "" {
+			missing = append(missing, name)
+		}
+
+		return value
+	})
+
+	// An unset value would silently share the namespace with other environments, ex) a review environment without a PR
+	if len(missing) > 0 {
+		return "", fmt.Errorf(
+			"failed resolving namespace template '%s', the value of %s is not set",
+			template,
+			strings.Join(missing, ", "),
+		)
+	}
+
+	namespace := invalidNamespaceCharsRegex.ReplaceAllString(strings.ToLower(resolved), "-")
+	if len(namespace) > maxNamespaceLength {
+		namespace = namespace[:maxNamespaceLength]
+	}
+
+	namespace = strings.Trim(namespace, "-")
+	if namespace == "" {
+		return "", fmt.Errorf("namespace template '%s' resolved to an empty namespace", template)
+	}
+
+	return namespace, nil
+}
@@ -0,0 +1,147 @@
+package project
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_EnsureServiceNamespace(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	applied := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		input, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		applied = append(applied, string(input))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.NamespaceConfig = &AksNamespaceOptions{
+		Labels:        map[string]string{"istio-injection": "enabled"},
+		Annotations:   map[string]string{"contoso.com/owner": "team-a"},
+		ResourceQuota: map[string]string{"requests.cpu": "4", "limits.memory": "8Gi"},
+		LimitRange: &AksLimitRangeOptions{
+			Default: map[string]string{"cpu": "500m"},
+		},
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	require.Len(t, applied, 1)
+	objects, err := parseK8sObjects(strings.Split(applied[0], "---\n"))
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+
+	namespace, _ := findK8sObject(objects, "Namespace", "Test-App")
+	require.NotNil(t, namespace)
+	require.Equal(t, "enabled", namespace.nested("metadata", "labels")["istio-injection"])
+	require.Equal(t, "team-a", namespace.nested("metadata", "annotations")["contoso.com/owner"])
+
+	quota, _ := findK8sObject(objects, "ResourceQuota", "Test-App-quota")
+	require.NotNil(t, quota)
+	require.Equal(t, "8Gi", quota.nested("spec", "hard")["limits.memory"])
+
+	limitRange, _ := findK8sObject(objects, "LimitRange", "Test-App-limits")
+	require.NotNil(t, limitRange)
+
+	var spec struct {
+		Limits []map[string]any `yaml:"limits"`
+	}
+	specBytes, err := yaml.Marshal(limitRange["spec"])
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(specBytes, &spec))
+	require.Equal(t, []map[string]any{
+		{
+			"type":    "Container",
+			"default": map[string]any{"cpu": "500m"},
+		},
+	}, spec.Limits)
+}
+
+func Test_NamespaceTemplate(t *testing.T) {
+	tests := map[string]struct {
+		namespace         string
+		namespaceTemplate string
+		env               map[string]string
+		expected          string
+		expectedError     string
+	}{
+		"Default": {
+			expected: "Test-App",
+		},
+		"Environment": {
+			namespaceTemplate: "{projectName}-{envName}",
+			expected:          "test-app-test",
+		},
+		"PullRequest": {
+			namespaceTemplate: "{projectName}-pr{PR_NUMBER}",
+			env:               map[string]string{"PR_NUMBER": "42"},
+			expected:          "test-app-pr42",
+		},
+		"Sanitized": {
+			namespaceTemplate: "{serviceName}_{BRANCH}",
+			env:               map[string]string{"BRANCH": "Feature/Login." + strings.Repeat("x", 60)},
+			expected:          "api-feature-login-" + strings.Repeat("x", 45),
+		},
+		"ExplicitNamespace": {
+			namespace:         "shared",
+			namespaceTemplate: "{projectName}-{envName}",
+			expected:          "shared",
+		},
+		"MissingValue": {
+			namespaceTemplate: "{projectName}-pr{PR_NUMBER}",
+			expectedError:     "the value of PR_NUMBER is not set",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Namespace = test.namespace
+			serviceConfig.K8s.NamespaceTemplate = test.namespaceTemplate
+			env := createEnv()
+			for key, value := range test.env {
+				env.DotenvSet(key, value)
+			}
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+			err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, serviceTarget.(*aksTarget).getK8sNamespace(serviceConfig))
+		})
+	}
+}
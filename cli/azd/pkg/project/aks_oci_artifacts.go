@@ -0,0 +1,285 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+// The repository of the container registry helm charts are pushed to by default
+const defaultHelmChartRepository = "helm"
+
+type OciArtifactKind string
+
+const (
+	// A helm chart packaged from a local chart directory and pushed with 'helm push'
+	OciArtifactKindHelmChart OciArtifactKind = "helmChart"
+	// A file pushed with 'oras push'
+	OciArtifactKindFile OciArtifactKind = "file"
+)
+
+func (k OciArtifactKind) displayName() string {
+	if k == OciArtifactKindHelmChart {
+		return "Helm Chart"
+	}
+
+	return "Artifact"
+}
+
+// OciArtifact is an artifact pushed to an OCI registry when the service is packaged, ex) a helm chart
+type OciArtifact struct {
+	Kind OciArtifactKind `json:"kind"`
+	// The local path the artifact was pushed from, ex) the chart directory
+	Source string `json:"source"`
+	// The reference of the artifact without its version, ex) oci://contoso.azurecr.io/helm/api for helm charts
+	// or contoso.azurecr.io/policies/api for files
+	Reference string `json:"reference"`
+	// The version of the chart or the tag of the artifact, ex) 0.1.0
+	Version string `json:"version"`
+	// The digest of the artifact manifest, ex) sha256:4f2d...
+	Digest string `json:"digest,omitempty"`
+}
+
+// The AKS OCI artifact options
+// The file is pushed to the container registry of the service with oras when the service is packaged
+type AksOciArtifactOptions struct {
+	// The path of the file, relative to the service path, ex) ./policies/api.rego
+	Path string `yaml:"path"`
+	// The repository the artifact is pushed to, ex) policies/api
+	Repository string `yaml:"repository"`
+	// The tag of the artifact, ex) ${AZURE_ENV_NAME}. Defaults to the default image tag
+	Tag osutil.ExpandableString `yaml:"tag"`
+	// The artifact type, ex) application/vnd.cncf.openpolicyagent.policy.layer.v1+rego
+	ArtifactType string `yaml:"artifactType"`
+}
+
+// validateOciArtifacts returns an error when the OCI artifacts are configured with unsupported options
+func validateOciArtifacts(serviceConfig *ServiceConfig) error {
+	if serviceConfig.K8s.Helm != nil {
+		for _, chart := range serviceConfig.K8s.Helm.Charts {
+			if chart.Path == "" {
+				return errors.New("the helm charts pushed to the container registry require a 'path'")
+			}
+		}
+	}
+
+	for _, artifact := range serviceConfig.K8s.Artifacts {
+		if artifact.Path == "" || artifact.Repository == "" || artifact.ArtifactType == "" {
+			return errors.New("the OCI artifacts require a 'path', 'repository' and 'artifactType'")
+		}
+	}
+
+	return nil
+}
+
+// pushOciArtifacts packages and pushes the helm charts and pushes the files of the service as OCI artifacts to the
+// container registry of the service, returning the pushed artifacts
+func (t *aksTarget) pushOciArtifacts(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	progress *async.Progress[ServiceProgress],
+) ([]*OciArtifact, error) {
+	var charts []*helm.Chart
+	if serviceConfig.K8s.Helm != nil {
+		charts = serviceConfig.K8s.Helm.Charts
+	}
+
+	if len(charts) == 0 && len(serviceConfig.K8s.Artifacts) == 0 {
+		return nil, nil
+	}
+
+	if err := validateOciArtifacts(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if len(charts) > 0 && !t.featureManager.IsEnabled(featureHelm) {
+		return nil, fmt.Errorf("Helm support is not enabled. Run '%s' to enable it.", alpha.GetEnableCommand(featureHelm))
+	}
+
+	registryName, err := t.containerHelper.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if registryName == "" {
+		return nil, errors.New("pushing OCI artifacts requires a container registry, set 'docker.registry'")
+	}
+
+	artifacts := []*OciArtifact{}
+
+	if len(charts) > 0 {
+		if err := t.helmRegistryLogin(ctx, serviceConfig, registryName); err != nil {
+			return nil, err
+		}
+
+		packageDir, err := os.MkdirTemp("", "azd-helm-charts")
+		if err != nil {
+			return nil, fmt.Errorf("failed creating helm chart package directory, %w", err)
+		}
+		defer os.RemoveAll(packageDir)
+
+		for _, chart := range charts {
+			artifact, err := t.pushHelmChart(ctx, serviceConfig, registryName, chart, packageDir, progress)
+			if err != nil {
+				return nil, err
+			}
+
+			artifacts = append(artifacts, artifact)
+		}
+	}
+
+	if len(serviceConfig.K8s.Artifacts) > 0 {
+		// oras reads the credentials of the docker login
+		if _, err := t.containerHelper.Login(ctx, serviceConfig); err != nil {
+			return nil, fmt.Errorf("logging in to registry: %w", err)
+		}
+
+		for _, artifactConfig := range serviceConfig.K8s.Artifacts {
+			artifact, err := t.pushFileArtifact(ctx, serviceConfig, registryName, artifactConfig, progress)
+			if err != nil {
+				return nil, err
+			}
+
+			artifacts = append(artifacts, artifact)
+		}
+	}
+
+	return artifacts, nil
+}
+
+// pushHelmChart packages the local chart and pushes it to the repository of the chart in the container registry
+func (t *aksTarget) pushHelmChart(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	registryName string,
+	chart *helm.Chart,
+	packageDir string,
+	progress *async.Progress[ServiceProgress],
+) (*OciArtifact, error) {
+	chartPath := chart.Path
+	if !filepath.IsAbs(chartPath) {
+		chartPath = filepath.Join(serviceConfig.Path(), chartPath)
+	}
+
+	version, err := osutil.NewExpandableString(chart.Version).Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst helm chart version: %w", err)
+	}
+
+	repository := chart.Repository
+	if repository == "" {
+		repository = defaultHelmChartRepository
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Packaging helm chart: %s", chart.Path)))
+	packagePath, err := t.helmCli.Package(ctx, chartPath, version, packageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Pushing helm chart: %s", filepath.Base(packagePath))))
+	pushed, err := t.helmCli.Push(ctx, packagePath, fmt.Sprintf("oci://%s/%s", registryName, repository))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("pushed helm chart %s to %s:%s", chartPath, pushed.Reference, pushed.Version)
+
+	return &OciArtifact{
+		Kind:      OciArtifactKindHelmChart,
+		Source:    filepath.Clean(chartPath),
+		Reference: pushed.Reference,
+		Version:   pushed.Version,
+		Digest:    pushed.Digest,
+	}, nil
+}
+
+// pushFileArtifact pushes the file to the repository of the artifact in the container registry
+func (t *aksTarget) pushFileArtifact(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	registryName string,
+	artifactConfig AksOciArtifactOptions,
+	progress *async.Progress[ServiceProgress],
+) (*OciArtifact, error) {
+	filePath := artifactConfig.Path
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(serviceConfig.Path(), filePath)
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		return nil, fmt.Errorf("the OCI artifact '%s' does not exist: %w", artifactConfig.Path, err)
+	}
+
+	tag, err := artifactConfig.Tag.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst OCI artifact tag: %w", err)
+	}
+
+	if tag == "" {
+		tag = t.containerHelper.DefaultImageTag()
+	}
+
+	reference := fmt.Sprintf("%s/%s", registryName, artifactConfig.Repository)
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Pushing OCI artifact: %s", artifactConfig.Path)))
+	digest, err := t.containerHelper.oras.Push(
+		ctx, fmt.Sprintf("%s:%s", reference, tag), artifactConfig.ArtifactType, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OciArtifact{
+		Kind:      OciArtifactKindFile,
+		Source:    filepath.Clean(filePath),
+		Reference: reference,
+		Version:   tag,
+		Digest:    digest,
+	}, nil
+}
+
+// helmRegistryLogin logs helm into the registry with the credentials of the registry of the service. The existing
+// login of helm is used when the credentials are read from the docker config and docker is not logged into the registry.
+func (t *aksTarget) helmRegistryLogin(ctx context.Context, serviceConfig *ServiceConfig, registryName string) error {
+	credentials, err := t.containerHelper.resolveRegistryCredentials(
+		ctx, serviceConfig, t.env.GetSubscriptionId(), registryName)
+	if errors.Is(err, docker.ErrCredentialsNotFound) {
+		log.Printf("using the existing helm login for registry '%s'", registryName)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return t.helmCli.RegistryLogin(ctx, registryName, credentials.Username, credentials.Password)
+}
+
+// packagedHelmChart returns the helm chart pushed from the local chart directory when the service was packaged
+func packagedHelmChart(packageOutput *ServicePackageResult, chartPath string) *OciArtifact {
+	if packageOutput == nil {
+		return nil
+	}
+
+	for _, artifact := range packageOutput.Artifacts {
+		if artifact.Kind == OciArtifactKindHelmChart && artifact.Source == filepath.Clean(chartPath) {
+			return artifact
+		}
+	}
+
+	return nil
+}
+
+// ociRegistry returns the registry of the OCI reference, ex) contoso.azurecr.io of oci://contoso.azurecr.io/helm/api
+func ociRegistry(reference string) string {
+	registry, _, _ := strings.Cut(strings.TrimPrefix(reference, "oci://"), "/")
+	return registry
+}
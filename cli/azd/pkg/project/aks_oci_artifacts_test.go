@@ -0,0 +1,187 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMocksForHelmPush(mockContext *mocks.MockContext) map[string]exec.RunArgs {
+	result := map[string]exec.RunArgs{}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "helm registry login")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		result["helm-registry-login"] = args
+		return exec.NewRunResult(0, "Login Succeeded", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "helm package")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		result["helm-package"] = args
+		packagePath := filepath.Join(args.Args[3], "api-0.1.0.tgz")
+		return exec.NewRunResult(0, "Successfully packaged chart and saved it to: "+packagePath, ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "helm push")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		result["helm-push"] = args
+		return exec.NewRunResult(0, "", "Pushed: REGISTRY.azurecr.io/helm/api:0.1.0\nDigest: sha256:ec5f\n"), nil
+	})
+
+	return result
+}
+
+func Test_Package_Helm_OciPush(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	_, err = setupMocksForHelm(mockContext)
+	require.NoError(t, err)
+	mockResults := setupMocksForHelmPush(mockContext)
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.RelativePath = ""
+	serviceConfig.K8s.Helm = &helm.Config{
+		Charts: []*helm.Chart{
+			{Path: "./charts/api"},
+		},
+		Releases: []*helm.Release{
+			{Name: "api", Chart: "./charts/api"},
+		},
+	}
+
+	chartDir := filepath.Join(serviceConfig.Path(), "charts", "api")
+	require.NoError(t, os.MkdirAll(chartDir, osutil.PermissionDirectory))
+
+	env := createEnv()
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.helm", "on")
+
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	require.NoError(t, err)
+
+	packageResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+		return serviceTarget.Package(*mockContext.Context, &serviceConfig, &ServicePackageResult{}, progress)
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*OciArtifact{
+		{
+			Kind:      OciArtifactKindHelmChart,
+			Source:    chartDir,
+			Reference: "oci://REGISTRY.azurecr.io/helm/api",
+			Version:   "0.1.0",
+			Digest:    "sha256:ec5f",
+		},
+	}, packageResult.Artifacts)
+	require.Equal(t, "registry login REGISTRY.azurecr.io", strings.Join(mockResults["helm-registry-login"].Args[:3], " "))
+	require.Equal(t, chartDir, mockResults["helm-package"].Args[1])
+	require.Equal(t, "oci://REGISTRY.azurecr.io/helm", mockResults["helm-push"].Args[2])
+
+	helmUpgrades := []exec.RunArgs{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm upgrade")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		helmUpgrades = append(helmUpgrades, args)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	// The release of the local chart is installed from the pushed chart
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return serviceTarget.Deploy(*mockContext.Context, &serviceConfig, packageResult, scope, progress)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, helmUpgrades, 1)
+	require.Contains(
+		t,
+		strings.Join(helmUpgrades[0].Args, " "),
+		"upgrade api oci://REGISTRY.azurecr.io/helm/api --install --wait --version 0.1.0",
+	)
+}
+
+func Test_Package_OciArtifacts(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	var pushArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "oras push")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		pushArgs = args
+		return exec.NewRunResult(0, `{"digest":"sha256:9b3e"}`, ""), nil
+	})
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.RelativePath = ""
+	serviceConfig.K8s.Artifacts = []AksOciArtifactOptions{
+		{
+			Path:         "./policies/api.rego",
+			Repository:   "policies/api",
+			Tag:          osutil.NewExpandableString("${AZURE_ENV_NAME}"),
+			ArtifactType: "application/vnd.cncf.openpolicyagent.policy.layer.v1+rego",
+		},
+	}
+
+	policyDir := filepath.Join(serviceConfig.Path(), "policies")
+	require.NoError(t, os.MkdirAll(policyDir, osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(policyDir, "api.rego"), []byte(""), osutil.PermissionFile))
+
+	env := createEnv()
+	env.DotenvSet(environment.EnvNameEnvVarName, "dev")
+
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, nil)
+	serviceTarget.(*aksTarget).containerHelper.oras = oras.NewCli(mockContext.CommandRunner)
+
+	packageResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+		return serviceTarget.Package(*mockContext.Context, &serviceConfig, nil, progress)
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*OciArtifact{
+		{
+			Kind:      OciArtifactKindFile,
+			Source:    filepath.Join(policyDir, "api.rego"),
+			Reference: "REGISTRY.azurecr.io/policies/api",
+			Version:   "dev",
+			Digest:    "sha256:9b3e",
+		},
+	}, packageResult.Artifacts)
+	require.Equal(t, "REGISTRY.azurecr.io/policies/api:dev", pushArgs.Args[1])
+	require.Equal(t, policyDir, pushArgs.Cwd)
+}
+
+func Test_Package_OciArtifacts_Validation(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Artifacts = []AksOciArtifactOptions{{Path: "./policies/api.rego"}}
+
+	err := validateOciArtifacts(serviceConfig)
+	require.ErrorContains(t, err, "require a 'path', 'repository' and 'artifactType'")
+}
@@ -0,0 +1,104 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The delay before a closed port forward is reconnected, ex) after the forwarded pod was restarted
+var aksPortForwardReconnectDelay = 2 * time.Second
+
+// The number of consecutive attempts that fail to establish the port forward before giving up
+const aksPortForwardMaxAttempts = 5
+
+// Forwards local ports to the k8s service of the azd service, equivalent to 'kubectl port-forward svc/<name>'
+// The port forward is reconnected when it is closed, ex) when the pod serving the connections was replaced by a
+// deployment, until the context is cancelled.
+func (t *aksTarget) PortForward(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options PortForwardOptions,
+) error {
+	namespace, err := t.useServiceCluster(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return err
+	}
+
+	if !t.kubectl.CanPortForward() {
+		return fmt.Errorf("port forwarding is not supported for clusters accessed using 'az aks command invoke'")
+	}
+
+	serviceName := t.getServiceName(serviceConfig)
+	ports := options.Ports
+	if len(ports) == 0 {
+		service, err := kubectl.GetResource[kubectl.Service](
+			ctx, t.kubectl, kubectl.ResourceTypeService, serviceName, &kubectl.KubeCliFlags{Namespace: namespace},
+		)
+		if err != nil {
+			return fmt.Errorf("failed getting k8s service '%s': %w", serviceName, err)
+		}
+
+		for _, port := range service.Spec.Ports {
+			ports = append(ports, strconv.Itoa(port.Port))
+		}
+
+		if len(ports) == 0 {
+			return fmt.Errorf("k8s service '%s' does not expose any ports", serviceName)
+		}
+	}
+
+	writer := options.Writer
+	if writer == nil {
+		writer = io.Discard
+	}
+
+	resource := fmt.Sprintf("svc/%s", serviceName)
+	attempts := 0
+	for {
+		// kubectl only writes to stdout once the port forward was established
+		output := &portForwardWriter{writer: writer}
+		err := t.kubectl.PortForward(ctx, resource, ports, &kubectl.KubeCliFlags{Namespace: namespace}, output)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if output.established {
+			attempts = 0
+		}
+
+		attempts++
+		if attempts >= aksPortForwardMaxAttempts {
+			if err == nil {
+				err = fmt.Errorf("port forward to k8s service '%s' could not be established", serviceName)
+			}
+
+			return err
+		}
+
+		fmt.Fprintf(writer, "Port forward to k8s service '%s' closed, reconnecting...\n", serviceName)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(aksPortForwardReconnectDelay):
+		}
+	}
+}
+
+// portForwardWriter tracks whether the port forward was established
+type portForwardWriter struct {
+	writer      io.Writer
+	established bool
+}
+
+func (w *portForwardWriter) Write(p []byte) (int, error) {
+	w.established = true
+	return w.writer.Write(p)
+}
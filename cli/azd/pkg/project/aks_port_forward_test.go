@@ -0,0 +1,121 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PortForward(t *testing.T) {
+	reconnectDelay := aksPortForwardReconnectDelay
+	aksPortForwardReconnectDelay = time.Millisecond
+	defer func() { aksPortForwardReconnectDelay = reconnectDelay }()
+
+	tests := map[string]struct {
+		ports           []string
+		establishAfter  int
+		expectedArgs    []string
+		expectedError   string
+		expectedForward int
+	}{
+		"DefaultPorts": {
+			establishAfter:  1,
+			expectedArgs:    []string{"port-forward", "svc/api-service", "80", "443", "-n", "api-namespace"},
+			expectedForward: 2,
+		},
+		"Ports": {
+			ports:           []string{"8080:80"},
+			expectedArgs:    []string{"port-forward", "svc/api-service", "8080:80", "-n", "api-namespace"},
+			expectedForward: 1,
+		},
+		"NotEstablished": {
+			ports:           []string{"8080:80"},
+			establishAfter:  aksPortForwardMaxAttempts,
+			expectedArgs:    []string{"port-forward", "svc/api-service", "8080:80", "-n", "api-namespace"},
+			expectedError:   "failed port forwarding to 'svc/api-service'",
+			expectedForward: aksPortForwardMaxAttempts,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			mockContext := mocks.NewMockContext(ctx)
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get svc api-service")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				jsonBytes, _ := json.Marshal(kubectl.Service{
+					Spec: kubectl.ServiceSpec{
+						Type:  kubectl.ServiceTypeClusterIp,
+						Ports: []kubectl.Port{{Port: 80, TargetPort: 3000}, {Port: 443, TargetPort: 3001}},
+					},
+				})
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			forwards := [][]string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl port-forward")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				forwards = append(forwards, args.Args)
+				if len(forwards) <= test.establishAfter {
+					return exec.NewRunResult(1, "", "error: unable to forward port"), errors.New("exit code: 1")
+				}
+
+				_, err := args.StdOut.Write([]byte("Forwarding from 127.0.0.1:8080 -> 3000\n"))
+				require.NoError(t, err)
+
+				// Stop forwarding once the port forward was established
+				cancel()
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Namespace = "api-namespace"
+			serviceConfig.K8s.Service.Name = "api-service"
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil)
+			err = serviceTarget.Initialize(ctx, serviceConfig)
+			require.NoError(t, err)
+
+			output := &strings.Builder{}
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			err = serviceTarget.(ServiceTargetPortForwarder).PortForward(ctx, serviceConfig, scope, PortForwardOptions{
+				Ports:  test.ports,
+				Writer: output,
+			})
+
+			require.Len(t, forwards, test.expectedForward)
+			require.Equal(t, withIsolatedKubeConfig(test.expectedArgs...), forwards[0])
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Contains(t, output.String(), "Forwarding from 127.0.0.1:8080")
+			require.Equal(t, test.establishAfter, strings.Count(output.String(), "reconnecting"))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The label used to identify the k8s resources deployed by azd for a service.
+// When pruning is enabled, resources with the label that are no longer defined within the manifests are deleted.
+const aksServiceLabel = "azd.azure.com/service"
+
+// aksServiceSelector returns the label selector matching the k8s resources deployed by azd for the service
+func aksServiceSelector(serviceConfig *ServiceConfig) string {
+	return fmt.Sprintf("%s=%s", aksServiceLabel, serviceConfig.Name)
+}
+
+// labelManifests adds the azd service label to all k8s objects within the manifests
+func labelManifests(serviceConfig *ServiceConfig, manifests []string) ([]k8sObject, error) {
+	objects, err := parseK8sObjects(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, object := range objects {
+		object.nested("metadata", "labels")[aksServiceLabel] = serviceConfig.Name
+	}
+
+	return objects, nil
+}
+
+// applyManifestsWithPrune applies all manifests within the deployment path in a single operation and deletes
+// the resources previously deployed for the service that are no longer defined within the manifests
+func (t *aksTarget) applyManifestsWithPrune(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentPath string,
+) error {
+	// Pruning compares the live resources against the applied set of objects, so all manifests
+	// must be applied together instead of file by file.
+	manifests, err := t.kubectl.ReadManifests(deploymentPath, serviceConfig.K8s.Envsubst)
+	if err != nil {
+		return err
+	}
+
+	objects, err := labelManifests(serviceConfig, manifests)
+	if err != nil {
+		return err
+	}
+
+	if err := t.applyScheduling(serviceConfig, objects); err != nil {
+		return err
+	}
+
+	// kubectl does not support applying an empty set of objects
+	if len(objects) == 0 {
+		log.Printf("no k8s objects found in '%s', skipping apply\n", deploymentPath)
+		return nil
+	}
+
+	manifest, err := marshalK8sObjects(objects)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.kubectl.ApplyWithStdIn(ctx, manifest, &kubectl.KubeCliFlags{
+		Prune:         true,
+		LabelSelector: aksServiceSelector(serviceConfig),
+	})
+
+	return err
+}
@@ -0,0 +1,144 @@
+package project
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Deploy_Prune(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Prune = true
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
+	require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+	manifests := map[string]string{
+		"deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api-deployment\n",
+		"service.yaml":    "apiVersion: v1\nkind: Service\nmetadata:\n  name: api-service\n  labels:\n    app: api\n",
+	}
+	for filename, content := range manifests {
+		err = os.WriteFile(filepath.Join(manifestsDir, filename), []byte(content), osutil.PermissionFile)
+		require.NoError(t, err)
+	}
+
+	applies := []exec.RunArgs{}
+	applied := []k8sObject{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		applies = append(applies, args)
+
+		input, err := io.ReadAll(args.StdIn)
+		require.NoError(t, err)
+
+		objects, err := parseK8sObjects([]string{string(input)})
+		require.NoError(t, err)
+		applied = append(applied, objects...)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+
+	// All manifests are applied together with the service label selector
+	require.Len(t, applies, 1)
+	require.Equal(
+		t, withIsolatedKubeConfig("apply", "-f", "-", "--prune", "-l", "azd.azure.com/service=api"), applies[0].Args,
+	)
+
+	require.Len(t, applied, 2)
+	for _, object := range applied {
+		require.Equal(t, "api", object.nested("metadata", "labels")[aksServiceLabel])
+	}
+
+	service, _ := findK8sObject(applied, "Service", "api-service")
+	require.NotNil(t, service)
+	require.Equal(t, "api", service.nested("metadata", "labels")["app"])
+}
+
+func Test_Deploy_Prune_With_Strategy(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Prune = true
+	serviceConfig.K8s.Deployment.Strategy.Type = AksDeploymentStrategyCanary
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.ErrorContains(t, err, "pruning is not supported with the 'canary' deployment strategy")
+}
+
+func Test_LabelManifests(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+
+	objects, err := labelManifests(serviceConfig, []string{
+		"kind: ConfigMap\nmetadata:\n  name: config\n---\nkind: Secret\nmetadata:\n  name: secret\n",
+		"",
+	})
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	manifest, err := marshalK8sObjects(objects)
+	require.NoError(t, err)
+
+	var configMap map[string]any
+	require.NoError(t, yaml.NewDecoder(strings.NewReader(manifest)).Decode(&configMap))
+	require.Equal(t, map[string]any{
+		"kind": "ConfigMap",
+		"metadata": map[string]any{
+			"name":   "config",
+			"labels": map[string]any{aksServiceLabel: "api"},
+		},
+	}, configMap)
+}
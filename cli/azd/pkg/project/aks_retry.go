@@ -0,0 +1,39 @@
+package project
+
+import (
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The AKS retry options
+// kubectl commands that fail with transient API server errors, ex) throttled requests during large applies,
+// are retried with exponential backoff instead of failing the deployment
+type AksRetryOptions struct {
+	// When enabled, failed kubectl commands are not retried
+	Disabled bool `yaml:"disabled"`
+	// The maximum number of retries. Defaults to 3
+	MaxRetries int `yaml:"maxRetries"`
+	// The delay before the first retry, doubled for every following retry, ex) 5s. Defaults to 2 seconds
+	InitialDelay time.Duration `yaml:"initialDelay"`
+	// The maximum delay between retries, ex) 1m. Defaults to 30 seconds
+	MaxDelay time.Duration `yaml:"maxDelay"`
+}
+
+// retryOptions returns the kubectl retry options, or nil when retries are disabled
+func retryOptions(serviceConfig *ServiceConfig) *kubectl.RetryOptions {
+	options := serviceConfig.K8s.Retry
+	if options == nil {
+		return &kubectl.RetryOptions{}
+	}
+
+	if options.Disabled {
+		return nil
+	}
+
+	return &kubectl.RetryOptions{
+		MaxRetries:   options.MaxRetries,
+		InitialDelay: options.InitialDelay,
+		MaxDelay:     options.MaxDelay,
+	}
+}
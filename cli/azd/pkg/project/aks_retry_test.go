@@ -0,0 +1,73 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_Retry(t *testing.T) {
+	tests := map[string]struct {
+		retry            *AksRetryOptions
+		expectedAttempts int
+		expectedErr      error
+	}{
+		"Retried": {
+			retry:            &AksRetryOptions{InitialDelay: time.Millisecond},
+			expectedAttempts: 2,
+		},
+		"Disabled": {
+			retry:            &AksRetryOptions{Disabled: true},
+			expectedAttempts: 1,
+			expectedErr:      kubectl.ErrServerBusy,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Retry = test.retry
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil)
+			err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+			require.NoError(t, err)
+
+			manifestPath := writeTestDeploymentManifest(t, serviceConfig)
+			attempts := 0
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply -f "+manifestPath)
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				attempts++
+				if attempts == 1 {
+					stderr := "Error from server (TooManyRequests): the server has received too many requests"
+					return exec.NewRunResult(1, "", stderr), errors.New("exit code: 1")
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			_, err = deployTestService(t, mockContext, serviceTarget, serviceConfig)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, test.expectedAttempts, attempts)
+		})
+	}
+}
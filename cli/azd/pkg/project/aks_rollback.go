@@ -0,0 +1,89 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// AksRollbackError is returned when a deployment failed to roll out and was rolled back
+// The error reports both the rollout failure and the result of the rollback
+type AksRollbackError struct {
+	DeploymentName string
+	// The error of the failed rollout
+	Err error
+	// The error of the rollback, nil when the deployment was rolled back to the previous revision
+	RollbackErr error
+}
+
+func (e *AksRollbackError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf(
+			"deployment '%s' failed to roll out and could not be rolled back: %s\nrollout failure: %s",
+			e.DeploymentName,
+			e.RollbackErr,
+			e.Err,
+		)
+	}
+
+	return fmt.Sprintf(
+		"deployment '%s' failed to roll out and was rolled back to the previous revision\nrollout failure: %s",
+		e.DeploymentName,
+		e.Err,
+	)
+}
+
+func (e *AksRollbackError) Unwrap() error {
+	return e.Err
+}
+
+// rollbackDeployment rolls back the deployment matching the deployment name filter to its previous revision and
+// waits for the rollback to complete. Deployments without a previous revision cannot be rolled back.
+func (t *aksTarget) rollbackDeployment(
+	ctx context.Context,
+	deploymentNameFilter string,
+	rolloutErr error,
+	task *async.Progress[ServiceProgress],
+) error {
+	rollbackErr := &AksRollbackError{
+		DeploymentName: deploymentNameFilter,
+		Err:            rolloutErr,
+	}
+
+	deployments, err := kubectl.GetResources[kubectl.Resource](ctx, t.kubectl, kubectl.ResourceTypeDeployment, nil)
+	if err != nil {
+		rollbackErr.RollbackErr = fmt.Errorf("failed retrieving deployments, %w", err)
+		return rollbackErr
+	}
+
+	deploymentName := ""
+	for _, deployment := range deployments.Items {
+		if strings.Contains(deployment.Metadata.Name, deploymentNameFilter) {
+			deploymentName = deployment.Metadata.Name
+			break
+		}
+	}
+
+	if deploymentName == "" {
+		rollbackErr.RollbackErr = fmt.Errorf("deployment '%s' %w", deploymentNameFilter, kubectl.ErrResourceNotFound)
+		return rollbackErr
+	}
+
+	rollbackErr.DeploymentName = deploymentName
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Rolling back deployment: %s", deploymentName)))
+	if _, err := t.kubectl.RolloutUndo(ctx, deploymentName, nil); err != nil {
+		rollbackErr.RollbackErr = err
+		return rollbackErr
+	}
+
+	if _, err := t.kubectl.RolloutStatus(ctx, deploymentName, nil); err != nil {
+		rollbackErr.RollbackErr = err
+		return rollbackErr
+	}
+
+	return rollbackErr
+}
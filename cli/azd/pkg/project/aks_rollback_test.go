@@ -0,0 +1,92 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_Rollback(t *testing.T) {
+	tests := map[string]struct {
+		undoErr       error
+		expectedError string
+	}{
+		"RolledBack": {
+			expectedError: "deployment 'api-deployment' failed to roll out and was rolled back to the previous revision",
+		},
+		"RollbackFailed": {
+			undoErr:       errors.New("no rollout history found for deployment \"api-deployment\""),
+			expectedError: "deployment 'api-deployment' failed to roll out and could not be rolled back",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			rolloutStatusCalls := 0
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl rollout status")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				rolloutStatusCalls++
+				if rolloutStatusCalls == 1 {
+					return exec.NewRunResult(1, "", "progress deadline exceeded"), errors.New("progress deadline exceeded")
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			var undoArgs []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl rollout undo")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				undoArgs = args.Args
+				if test.undoErr != nil {
+					return exec.NewRunResult(1, "", test.undoErr.Error()), test.undoErr
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Deployment.Rollback = true
+			env := createEnv()
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+			err = setupK8sManifests(t, serviceConfig)
+			require.NoError(t, err)
+
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+				return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+			})
+
+			var rollbackErr *AksRollbackError
+			require.ErrorAs(t, err, &rollbackErr)
+			require.ErrorContains(t, err, test.expectedError)
+			require.ErrorContains(t, err, "progress deadline exceeded")
+			require.Equal(t, []string{"rollout", "undo", "deployment/api-deployment"}, undoArgs)
+
+			if test.undoErr != nil {
+				require.Error(t, rollbackErr.RollbackErr)
+			} else {
+				require.NoError(t, rollbackErr.RollbackErr)
+				require.Equal(t, 2, rolloutStatusCalls)
+			}
+		})
+	}
+}
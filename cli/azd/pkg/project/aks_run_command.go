@@ -0,0 +1,269 @@
+package project
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// aksRunCommandRunner is an exec.CommandRunner that executes commands on an AKS cluster through the
+// managed cluster run command API instead of the local machine.
+// This enables deployments to private clusters where the API server is not reachable from the developer machine.
+// Any local files or standard input referenced by the command are uploaded as part of the command context.
+type aksRunCommandRunner struct {
+	managedClustersService azcli.ManagedClustersService
+	subscriptionId         string
+	resourceGroupName      string
+	clusterName            string
+	// AAD enabled clusters require a token for the AKS AAD server application
+	aadEnabled bool
+}
+
+// newAksRunCommandRunner creates a new command runner for the specified AKS cluster
+func newAksRunCommandRunner(
+	managedClustersService azcli.ManagedClustersService,
+	subscriptionId string,
+	resourceGroupName string,
+	clusterName string,
+	aadEnabled bool,
+) exec.CommandRunner {
+	return &aksRunCommandRunner{
+		managedClustersService: managedClustersService,
+		subscriptionId:         subscriptionId,
+		resourceGroupName:      resourceGroupName,
+		clusterName:            clusterName,
+		aadEnabled:             aadEnabled,
+	}
+}
+
+// Run executes the command on the AKS cluster and returns the command logs as stdout
+func (r *aksRunCommandRunner) Run(ctx context.Context, args exec.RunArgs) (exec.RunResult, error) {
+	commandContext := newRunCommandContext()
+	commandArgs := make([]string, len(args.Args))
+	copy(commandArgs, args.Args)
+
+	for i := 0; i < len(commandArgs); i++ {
+		switch commandArgs[i] {
+		case "-f", "--filename", "-k", "--kustomize":
+			if i+1 >= len(commandArgs) {
+				continue
+			}
+
+			i++
+			localPath := commandArgs[i]
+			if localPath != "-" && !filepath.IsAbs(localPath) && args.Cwd != "" {
+				localPath = filepath.Join(args.Cwd, localPath)
+			}
+
+			contextPath, err := commandContext.add(localPath, args.StdIn)
+			if err != nil {
+				return exec.RunResult{}, err
+			}
+
+			commandArgs[i] = contextPath
+		}
+	}
+
+	command := strings.Join(append([]string{args.Cmd}, quoteArgs(commandArgs)...), " ")
+	request := armcontainerservice.RunCommandRequest{
+		Command: to.Ptr(command),
+	}
+
+	if r.aadEnabled {
+		clusterToken, err := r.managedClustersService.GetClusterToken(ctx, r.subscriptionId)
+		if err != nil {
+			return exec.RunResult{}, err
+		}
+
+		request.ClusterToken = to.Ptr(clusterToken)
+	}
+
+	if commandContext.len() > 0 {
+		encodedContext, err := commandContext.encode()
+		if err != nil {
+			return exec.RunResult{}, err
+		}
+
+		request.Context = to.Ptr(encodedContext)
+	}
+
+	log.Printf("Run command on AKS cluster '%s': '%s'\n", r.clusterName, command)
+	result, err := r.managedClustersService.RunCommand(
+		ctx,
+		r.subscriptionId,
+		r.resourceGroupName,
+		r.clusterName,
+		request,
+	)
+	if err != nil {
+		return exec.RunResult{}, fmt.Errorf(
+			"failed running command '%s' on AKS cluster '%s': %w",
+			command,
+			r.clusterName,
+			err,
+		)
+	}
+
+	var exitCode int32
+	var logs string
+	var reason string
+
+	if result.Properties != nil {
+		exitCode = convert.ToValueWithDefault(result.Properties.ExitCode, 0)
+		logs = convert.ToValueWithDefault(result.Properties.Logs, "")
+		reason = convert.ToValueWithDefault(result.Properties.Reason, "")
+	}
+
+	runResult := exec.NewRunResult(int(exitCode), logs, reason)
+	if exitCode != 0 {
+		return runResult, fmt.Errorf(
+			"command '%s' on AKS cluster '%s' exited with code %d: %s",
+			command,
+			r.clusterName,
+			exitCode,
+			strings.TrimSpace(logs+" "+reason),
+		)
+	}
+
+	return runResult, nil
+}
+
+// RunList is not supported by the AKS run command runner
+func (r *aksRunCommandRunner) RunList(ctx context.Context, commands []string, args exec.RunArgs) (exec.RunResult, error) {
+	return exec.RunResult{}, errors.New("running a list of commands is not supported through the AKS run command API")
+}
+
+// runCommandContext builds the zip archive of files uploaded with the run command
+type runCommandContext struct {
+	files map[string][]byte
+	// The number of local paths added to the context
+	paths int
+}
+
+func newRunCommandContext() *runCommandContext {
+	return &runCommandContext{
+		files: map[string][]byte{},
+	}
+}
+
+func (c *runCommandContext) len() int {
+	return len(c.files)
+}
+
+// add adds the referenced file or directory to the context and returns the path relative to the command context
+// A '-' path references the standard input of the command
+func (c *runCommandContext) add(localPath string, stdIn io.Reader) (string, error) {
+	// Each referenced path is stored in a unique folder to avoid name collisions between files
+	contextDir := fmt.Sprintf("%d", c.paths)
+	c.paths++
+
+	if localPath == "-" {
+		if stdIn == nil {
+			return "", errors.New("command references standard input but no input was provided")
+		}
+
+		content, err := io.ReadAll(stdIn)
+		if err != nil {
+			return "", fmt.Errorf("failed reading standard input: %w", err)
+		}
+
+		contextPath := path.Join(contextDir, "stdin.yaml")
+		c.files[contextPath] = content
+
+		return contextPath, nil
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed reading '%s': %w", localPath, err)
+	}
+
+	if !info.IsDir() {
+		content, err := os.ReadFile(localPath)
+		if err != nil {
+			return "", fmt.Errorf("failed reading '%s': %w", localPath, err)
+		}
+
+		contextPath := path.Join(contextDir, filepath.Base(localPath))
+		c.files[contextPath] = content
+
+		return contextPath, nil
+	}
+
+	err = filepath.WalkDir(localPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(localPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		c.files[path.Join(contextDir, filepath.ToSlash(relativePath))] = content
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed reading directory '%s': %w", localPath, err)
+	}
+
+	return contextDir, nil
+}
+
+// encode returns the base64 encoded zip archive of all files within the context
+func (c *runCommandContext) encode() (string, error) {
+	buffer := bytes.Buffer{}
+	zipWriter := zip.NewWriter(&buffer)
+
+	for name, content := range c.files {
+		fileWriter, err := zipWriter.Create(name)
+		if err != nil {
+			return "", fmt.Errorf("failed creating run command context: %w", err)
+		}
+
+		if _, err := fileWriter.Write(content); err != nil {
+			return "", fmt.Errorf("failed creating run command context: %w", err)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return "", fmt.Errorf("failed creating run command context: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// quoteArgs quotes any arguments that contain characters interpreted by the remote shell
+func quoteArgs(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"$`\\|&;<>()*?[]{}~#!") {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		} else {
+			quoted[i] = arg
+		}
+	}
+
+	return quoted
+}
@@ -0,0 +1,206 @@
+package project
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AksRunCommandRunner_Run(t *testing.T) {
+	t.Run("WithStdInAndFiles", func(t *testing.T) {
+		tempDir := t.TempDir()
+		manifestPath := filepath.Join(tempDir, "deployment.yaml")
+		require.NoError(t, os.WriteFile(manifestPath, []byte("kind: Deployment"), osutil.PermissionFile))
+
+		mockContext := mocks.NewMockContext(context.Background())
+		requests := setupRunCommandMock(mockContext, 0, "applied")
+		runner := createAksRunCommandRunner(mockContext)
+
+		runArgs := exec.
+			NewRunArgs("kubectl", "apply", "-f", "-", "-f", manifestPath, "-n", "my namespace").
+			WithStdIn(strings.NewReader("kind: Namespace"))
+
+		result, err := runner.Run(*mockContext.Context, runArgs)
+		require.NoError(t, err)
+		require.Equal(t, "applied", result.Stdout)
+
+		require.Len(t, *requests, 1)
+		request := (*requests)[0]
+		require.Equal(t, "kubectl apply -f 0/stdin.yaml -f 1/deployment.yaml -n 'my namespace'", *request.Command)
+		require.Nil(t, request.ClusterToken)
+
+		files := readRunCommandContext(t, *request.Context)
+		require.Equal(t, map[string]string{
+			"0/stdin.yaml":      "kind: Namespace",
+			"1/deployment.yaml": "kind: Deployment",
+		}, files)
+	})
+
+	t.Run("WithDirectory", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "base"), osutil.PermissionDirectory))
+		err := os.WriteFile(filepath.Join(tempDir, "kustomization.yaml"), []byte("resources: [base]"), osutil.PermissionFile)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(tempDir, "base", "service.yaml"), []byte("kind: Service"), osutil.PermissionFile)
+		require.NoError(t, err)
+
+		mockContext := mocks.NewMockContext(context.Background())
+		requests := setupRunCommandMock(mockContext, 0, "")
+		runner := createAksRunCommandRunner(mockContext)
+
+		_, err = runner.Run(*mockContext.Context, exec.NewRunArgs("kubectl", "apply", "-k", tempDir))
+		require.NoError(t, err)
+
+		request := (*requests)[0]
+		require.Equal(t, "kubectl apply -k 0", *request.Command)
+
+		files := readRunCommandContext(t, *request.Context)
+		require.Equal(t, map[string]string{
+			"0/kustomization.yaml": "resources: [base]",
+			"0/base/service.yaml":  "kind: Service",
+		}, files)
+	})
+
+	t.Run("NoContext", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		requests := setupRunCommandMock(mockContext, 0, "{}")
+		runner := createAksRunCommandRunner(mockContext)
+
+		_, err := runner.Run(*mockContext.Context, exec.NewRunArgs("kubectl", "get", "deployment", "-o", "json"))
+		require.NoError(t, err)
+
+		request := (*requests)[0]
+		require.Equal(t, "kubectl get deployment -o json", *request.Command)
+		require.Nil(t, request.Context)
+	})
+
+	t.Run("NonZeroExitCode", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupRunCommandMock(mockContext, 1, "deployments.apps \"api\" not found")
+		runner := createAksRunCommandRunner(mockContext)
+
+		result, err := runner.Run(*mockContext.Context, exec.NewRunArgs("kubectl", "rollout", "status", "deployment/api"))
+		require.Error(t, err)
+		require.ErrorContains(t, err, "exited with code 1")
+		require.ErrorContains(t, err, "not found")
+		require.Equal(t, 1, result.ExitCode)
+	})
+}
+
+func Test_Aks_Use_Run_Command(t *testing.T) {
+	privateCluster := &armcontainerservice.ManagedCluster{
+		Properties: &armcontainerservice.ManagedClusterProperties{
+			APIServerAccessProfile: &armcontainerservice.ManagedClusterAPIServerAccessProfile{
+				EnablePrivateCluster: to.Ptr(true),
+			},
+		},
+	}
+
+	publicCluster := &armcontainerservice.ManagedCluster{
+		Properties: &armcontainerservice.ManagedClusterProperties{},
+	}
+
+	tests := map[string]struct {
+		runCommand *bool
+		cluster    *armcontainerservice.ManagedCluster
+		expected   bool
+	}{
+		"PublicCluster":          {runCommand: nil, cluster: publicCluster, expected: false},
+		"PrivateCluster":         {runCommand: nil, cluster: privateCluster, expected: true},
+		"PrivateClusterDisabled": {runCommand: to.Ptr(false), cluster: privateCluster, expected: false},
+		"PublicClusterEnabled":   {runCommand: to.Ptr(true), cluster: publicCluster, expected: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.RunCommand = test.runCommand
+
+			target := &aksTarget{}
+			require.Equal(t, test.expected, target.useRunCommand(serviceConfig, test.cluster))
+		})
+	}
+}
+
+func createAksRunCommandRunner(mockContext *mocks.MockContext) exec.CommandRunner {
+	credentialProvider := mockaccount.SubscriptionCredentialProviderFunc(
+		func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		})
+
+	managedClustersService := azcli.NewManagedClustersService(credentialProvider, mockContext.ArmClientOptions)
+	return newAksRunCommandRunner(managedClustersService, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "AKS_CLUSTER", false)
+}
+
+func setupRunCommandMock(
+	mockContext *mocks.MockContext,
+	exitCode int32,
+	logs string,
+) *[]armcontainerservice.RunCommandRequest {
+	requests := []armcontainerservice.RunCommandRequest{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(
+			request.URL.Path,
+			"Microsoft.ContainerService/managedClusters/AKS_CLUSTER/runCommand",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		var runCommandRequest armcontainerservice.RunCommandRequest
+		if err := json.NewDecoder(request.Body).Decode(&runCommandRequest); err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, runCommandRequest)
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerservice.RunCommandResult{
+			ID: to.Ptr("COMMAND_ID"),
+			Properties: &armcontainerservice.CommandResultProperties{
+				ExitCode:          to.Ptr(exitCode),
+				Logs:              to.Ptr(logs),
+				ProvisioningState: to.Ptr("Succeeded"),
+			},
+		})
+	})
+
+	return &requests
+}
+
+func readRunCommandContext(t *testing.T, encodedContext string) map[string]string {
+	zipBytes, err := base64.StdEncoding.DecodeString(encodedContext)
+	require.NoError(t, err)
+
+	zipReader, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range zipReader.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+
+		files[file.Name] = string(content)
+	}
+
+	return files
+}
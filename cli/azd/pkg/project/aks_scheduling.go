@@ -0,0 +1,291 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// How the node pool scheduling of the AKS service is applied to the deployments within the manifests
+type AksSchedulingMode string
+
+const (
+	// The node selector, tolerations and runtime class are injected into the pod template of the deployments (default)
+	AksSchedulingModeInject AksSchedulingMode = "inject"
+	// The deployments are verified to already declare the node selector, tolerations and runtime class
+	AksSchedulingModeValidate AksSchedulingMode = "validate"
+)
+
+// The AKS toleration of the pods for a taint of the node pool, ex) sku=gpu:NoSchedule
+type AksToleration struct {
+	// The taint key the toleration applies to, ex) sku
+	Key string `yaml:"key"`
+	// The operator of the toleration, ex) Equal or Exists. Defaults to Equal
+	Operator string `yaml:"operator"`
+	// The taint value the toleration matches, ex) gpu
+	Value osutil.ExpandableString `yaml:"value"`
+	// The taint effect the toleration matches, ex) NoSchedule. Matches all effects when empty
+	Effect string `yaml:"effect"`
+	// The duration in seconds the pods stay bound to a node tainted with the NoExecute effect
+	TolerationSeconds *int64 `yaml:"tolerationSeconds"`
+}
+
+// hasScheduling returns true when any node pool scheduling is configured for the service
+func (o *AksOptions) hasScheduling() bool {
+	return len(o.NodeSelector) > 0 || len(o.Tolerations) > 0 || !o.RuntimeClass.Empty()
+}
+
+// aksPodScheduling is the node pool scheduling of the pods with the environment values substituted
+type aksPodScheduling struct {
+	nodeSelector map[string]string
+	tolerations  []map[string]any
+	runtimeClass string
+}
+
+// resolveScheduling substitutes the environment values within the node pool scheduling of the service
+func (t *aksTarget) resolveScheduling(serviceConfig *ServiceConfig) (*aksPodScheduling, error) {
+	scheduling := &aksPodScheduling{nodeSelector: map[string]string{}}
+
+	for key, value := range serviceConfig.K8s.NodeSelector {
+		resolved, err := value.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst node selector '%s': %w", key, err)
+		}
+
+		scheduling.nodeSelector[key] = resolved
+	}
+
+	for index, toleration := range serviceConfig.K8s.Tolerations {
+		value, err := toleration.Value.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst value of toleration at index %d: %w", index, err)
+		}
+
+		if toleration.Operator == "Exists" && value != "" {
+			return nil, fmt.Errorf(
+				"invalid toleration at index %d, the value must be empty with the 'Exists' operator", index,
+			)
+		}
+
+		scheduling.tolerations = append(scheduling.tolerations, toleration.k8sToleration(value))
+	}
+
+	runtimeClass, err := serviceConfig.K8s.RuntimeClass.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst runtime class: %w", err)
+	}
+
+	scheduling.runtimeClass = runtimeClass
+
+	return scheduling, nil
+}
+
+// k8sToleration returns the toleration in the format of the k8s pod spec
+func (o AksToleration) k8sToleration(value string) map[string]any {
+	toleration := map[string]any{}
+	if o.Key != "" {
+		toleration["key"] = o.Key
+	}
+
+	if o.Operator != "" {
+		toleration["operator"] = o.Operator
+	}
+
+	if value != "" {
+		toleration["value"] = value
+	}
+
+	if o.Effect != "" {
+		toleration["effect"] = o.Effect
+	}
+
+	if o.TolerationSeconds != nil {
+		toleration["tolerationSeconds"] = *o.TolerationSeconds
+	}
+
+	return toleration
+}
+
+// applyScheduling injects or validates the node pool scheduling of the service within the deployments
+// of the k8s objects, so that the same manifests can target different node pools per environment
+func (t *aksTarget) applyScheduling(serviceConfig *ServiceConfig, objects []k8sObject) error {
+	if !serviceConfig.K8s.hasScheduling() {
+		return nil
+	}
+
+	scheduling, err := t.resolveScheduling(serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	mode := serviceConfig.K8s.SchedulingMode
+	switch mode {
+	case "", AksSchedulingModeInject, AksSchedulingModeValidate:
+	default:
+		return fmt.Errorf(
+			"unsupported scheduling mode '%s', supported values are 'inject' and 'validate'", mode,
+		)
+	}
+
+	violations := []string{}
+	for _, object := range objects {
+		if object.kind() != "Deployment" {
+			continue
+		}
+
+		podSpec := object.nested("spec", "template", "spec")
+		if mode == AksSchedulingModeValidate {
+			for _, violation := range scheduling.violations(podSpec) {
+				violations = append(violations, fmt.Sprintf("deployment '%s' %s", object.name(), violation))
+			}
+
+			continue
+		}
+
+		scheduling.inject(podSpec)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf(
+			"the deployments do not match the scheduling of service '%s':\n  %s",
+			serviceConfig.Name,
+			strings.Join(violations, "\n  "),
+		)
+	}
+
+	return nil
+}
+
+// inject merges the node selector and tolerations into the pod spec and sets the runtime class.
+// The values of the service take precedence over the values declared within the manifests.
+func (s *aksPodScheduling) inject(podSpec map[string]any) {
+	if len(s.nodeSelector) > 0 {
+		nodeSelector, _ := podSpec["nodeSelector"].(map[string]any)
+		if nodeSelector == nil {
+			nodeSelector = map[string]any{}
+		}
+
+		for key, value := range s.nodeSelector {
+			nodeSelector[key] = value
+		}
+
+		podSpec["nodeSelector"] = nodeSelector
+	}
+
+	if len(s.tolerations) > 0 {
+		tolerations, _ := podSpec["tolerations"].([]any)
+		for _, toleration := range s.tolerations {
+			if !hasToleration(tolerations, toleration) {
+				tolerations = append(tolerations, toleration)
+			}
+		}
+
+		podSpec["tolerations"] = tolerations
+	}
+
+	if s.runtimeClass != "" {
+		podSpec["runtimeClassName"] = s.runtimeClass
+	}
+}
+
+// violations returns the node selector, tolerations and runtime class missing from the pod spec
+func (s *aksPodScheduling) violations(podSpec map[string]any) []string {
+	violations := []string{}
+
+	nodeSelector, _ := podSpec["nodeSelector"].(map[string]any)
+	for _, key := range slices.Sorted(maps.Keys(s.nodeSelector)) {
+		if value, _ := nodeSelector[key].(string); value != s.nodeSelector[key] {
+			violations = append(violations, fmt.Sprintf("is missing node selector '%s=%s'", key, s.nodeSelector[key]))
+		}
+	}
+
+	tolerations, _ := podSpec["tolerations"].([]any)
+	for _, toleration := range s.tolerations {
+		if !hasToleration(tolerations, toleration) {
+			violations = append(violations, fmt.Sprintf("is missing toleration %s", formatToleration(toleration)))
+		}
+	}
+
+	if runtimeClass, _ := podSpec["runtimeClassName"].(string); s.runtimeClass != "" && runtimeClass != s.runtimeClass {
+		violations = append(violations, fmt.Sprintf("is missing runtime class '%s'", s.runtimeClass))
+	}
+
+	return violations
+}
+
+// hasToleration returns true when the tolerations contain a toleration with the same fields
+func hasToleration(tolerations []any, toleration map[string]any) bool {
+	for _, item := range tolerations {
+		existing, ok := item.(map[string]any)
+		if !ok || len(existing) != len(toleration) {
+			continue
+		}
+
+		matches := true
+		for key, value := range toleration {
+			if fmt.Sprint(existing[key]) != fmt.Sprint(value) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatToleration formats the toleration like the taints of a node, ex) sku=gpu:NoSchedule
+func formatToleration(toleration map[string]any) string {
+	formatted := fmt.Sprint(toleration["key"])
+	if value, has := toleration["value"]; has {
+		formatted = fmt.Sprintf("%s=%v", formatted, value)
+	}
+
+	if effect, has := toleration["effect"]; has {
+		formatted = fmt.Sprintf("%s:%v", formatted, effect)
+	}
+
+	return fmt.Sprintf("'%s'", formatted)
+}
+
+// applyManifestsWithScheduling applies all manifests within the deployment path with the node pool scheduling
+// of the service applied to the deployments
+func (t *aksTarget) applyManifestsWithScheduling(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentPath string,
+) error {
+	manifests, err := t.kubectl.ReadManifests(deploymentPath, serviceConfig.K8s.Envsubst)
+	if err != nil {
+		return err
+	}
+
+	objects, err := parseK8sObjects(manifests)
+	if err != nil {
+		return err
+	}
+
+	if err := t.applyScheduling(serviceConfig, objects); err != nil {
+		return err
+	}
+
+	// kubectl does not support applying an empty set of objects
+	if len(objects) == 0 {
+		return nil
+	}
+
+	manifest, err := marshalK8sObjects(objects)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.kubectl.ApplyWithStdIn(ctx, manifest, nil)
+	return err
+}
@@ -0,0 +1,150 @@
+package project
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_Scheduling(t *testing.T) {
+	deploymentManifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api-deployment
+spec:
+  template:
+    spec:
+      nodeSelector:
+        team: api
+      tolerations:
+        - key: sku
+          operator: Equal
+          value: gpu
+          effect: NoSchedule
+      containers:
+        - name: api
+          image: api:latest
+`
+	manifests := map[string]string{
+		"deployment.yaml": deploymentManifest,
+		"service.yaml":    "apiVersion: v1\nkind: Service\nmetadata:\n  name: api-service\n",
+	}
+
+	gpuToleration := AksToleration{
+		Key:      "sku",
+		Operator: "Equal",
+		Value:    osutil.NewExpandableString("${GPU_SKU}"),
+		Effect:   "NoSchedule",
+	}
+
+	tests := map[string]struct {
+		nodeSelector map[string]osutil.ExpandableString
+		tolerations  []AksToleration
+		runtimeClass string
+		mode         AksSchedulingMode
+		// The expected pod spec of the applied deployment
+		expectedPodSpec map[string]any
+		expectedError   string
+	}{
+		"Inject": {
+			nodeSelector: map[string]osutil.ExpandableString{
+				"kubernetes.io/os": osutil.NewExpandableString("windows"),
+			},
+			tolerations: []AksToleration{
+				gpuToleration,
+				{Key: "os", Operator: "Exists", Effect: "NoExecute"},
+			},
+			runtimeClass: "nvidia",
+			expectedPodSpec: map[string]any{
+				"nodeSelector": map[string]any{"team": "api", "kubernetes.io/os": "windows"},
+				"tolerations": []any{
+					map[string]any{"key": "sku", "operator": "Equal", "value": "gpu", "effect": "NoSchedule"},
+					map[string]any{"key": "os", "operator": "Exists", "effect": "NoExecute"},
+				},
+				"runtimeClassName": "nvidia",
+			},
+		},
+		"Validate": {
+			nodeSelector: map[string]osutil.ExpandableString{"team": osutil.NewExpandableString("api")},
+			tolerations:  []AksToleration{gpuToleration},
+			mode:         AksSchedulingModeValidate,
+			expectedPodSpec: map[string]any{
+				"nodeSelector": map[string]any{"team": "api"},
+				"tolerations": []any{
+					map[string]any{"key": "sku", "operator": "Equal", "value": "gpu", "effect": "NoSchedule"},
+				},
+			},
+		},
+		"ValidateMismatch": {
+			nodeSelector: map[string]osutil.ExpandableString{
+				"kubernetes.io/os": osutil.NewExpandableString("windows"),
+			},
+			tolerations:  []AksToleration{{Key: "os", Operator: "Exists", Effect: "NoExecute"}},
+			runtimeClass: "nvidia",
+			mode:         AksSchedulingModeValidate,
+			expectedError: "deployment 'api-deployment' is missing node selector 'kubernetes.io/os=windows'\n" +
+				"  deployment 'api-deployment' is missing toleration 'os:NoExecute'\n" +
+				"  deployment 'api-deployment' is missing runtime class 'nvidia'",
+		},
+		"InvalidMode": {
+			runtimeClass:  "nvidia",
+			mode:          "ignore",
+			expectedError: "unsupported scheduling mode 'ignore'",
+		},
+		"InvalidToleration": {
+			tolerations:   []AksToleration{{Key: "sku", Operator: "Exists", Value: osutil.NewExpandableString("gpu")}},
+			expectedError: "the value must be empty with the 'Exists' operator",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, manifests)
+			serviceConfig.K8s.NodeSelector = test.nodeSelector
+			serviceConfig.K8s.Tolerations = test.tolerations
+			serviceConfig.K8s.RuntimeClass = osutil.NewExpandableString(test.runtimeClass)
+			serviceConfig.K8s.SchedulingMode = test.mode
+			serviceTarget.(*aksTarget).env.DotenvSet("GPU_SKU", "gpu")
+
+			applied := []k8sObject{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply -f -")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				input, err := io.ReadAll(args.StdIn)
+				require.NoError(t, err)
+
+				objects, err := parseK8sObjects([]string{string(input)})
+				require.NoError(t, err)
+				applied = append(applied, objects...)
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			deployResult, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				require.Empty(t, applied)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, deployResult)
+			require.Len(t, applied, 2)
+
+			deployment, _ := findK8sObject(applied, "Deployment", "api-deployment")
+			require.NotNil(t, deployment)
+
+			podSpec := deployment.nested("spec", "template", "spec")
+			delete(podSpec, "containers")
+			require.Equal(t, test.expectedPodSpec, podSpec)
+
+			// Only the pod template of the deployments is updated
+			service, _ := findK8sObject(applied, "Service", "api-service")
+			require.NotContains(t, service, "spec")
+		})
+	}
+}
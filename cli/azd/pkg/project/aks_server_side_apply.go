@@ -0,0 +1,29 @@
+package project
+
+import (
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The AKS server-side apply options
+// When configured, the manifests are applied with server-side apply so that the ownership of each field is tracked
+// by its field manager instead of the last-applied-configuration annotation
+type AksServerSideApplyOptions struct {
+	// The field manager recorded for the fields applied by azd. Defaults to 'azd'
+	FieldManager string `yaml:"fieldManager"`
+	// When enabled, fields owned by other field managers, ex) a GitOps controller, are taken over by azd
+	// instead of failing the deployment with a conflict
+	ForceConflicts bool `yaml:"forceConflicts"`
+}
+
+// serverSideApplyOptions returns the kubectl server-side apply options, or nil for client-side apply
+func serverSideApplyOptions(serviceConfig *ServiceConfig) *kubectl.ServerSideApplyOptions {
+	options := serviceConfig.K8s.ServerSideApply
+	if options == nil {
+		return nil
+	}
+
+	return &kubectl.ServerSideApplyOptions{
+		FieldManager:   options.FieldManager,
+		ForceConflicts: options.ForceConflicts,
+	}
+}
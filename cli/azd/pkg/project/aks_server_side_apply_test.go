@@ -0,0 +1,130 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_ServerSideApply(t *testing.T) {
+	tests := map[string]struct {
+		options      *AksServerSideApplyOptions
+		expectedArgs []string
+	}{
+		"Default": {
+			options:      &AksServerSideApplyOptions{},
+			expectedArgs: []string{"--server-side", "--field-manager=azd"},
+		},
+		"ForceConflicts": {
+			options:      &AksServerSideApplyOptions{FieldManager: "contoso", ForceConflicts: true},
+			expectedArgs: []string{"--server-side", "--field-manager=contoso", "--force-conflicts"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.ServerSideApply = test.options
+			env := createEnv()
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+			err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+			require.NoError(t, err)
+
+			manifestPath := writeTestDeploymentManifest(t, serviceConfig)
+
+			var applyArgs []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply -f "+manifestPath)
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				applyArgs = args.Args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			_, err = deployTestService(t, mockContext, serviceTarget, serviceConfig)
+			require.NoError(t, err)
+			expectedArgs := append([]string{"apply", "-f", manifestPath}, test.expectedArgs...)
+			require.Equal(t, withIsolatedKubeConfig(expectedArgs...), applyArgs)
+		})
+	}
+}
+
+func Test_Deploy_ServerSideApply_Conflict(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.ServerSideApply = &AksServerSideApplyOptions{}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	manifestPath := writeTestDeploymentManifest(t, serviceConfig)
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f "+manifestPath)
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		//nolint:lll
+		stderr := `error: Apply failed with 1 conflict: conflict with "flux-client-side-apply" using apps/v1: .spec.replicas`
+		return exec.NewRunResult(1, "", stderr), errors.New("exit code: 1")
+	})
+
+	_, err = deployTestService(t, mockContext, serviceTarget, serviceConfig)
+	var errWithSuggestion *internal.ErrorWithSuggestion
+	require.True(t, errors.As(err, &errWithSuggestion))
+	require.Contains(t, errWithSuggestion.Suggestion, "k8s.serverSideApply.forceConflicts")
+}
+
+func writeTestDeploymentManifest(t *testing.T, serviceConfig *ServiceConfig) string {
+	manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
+	require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+
+	manifestPath := filepath.Join(manifestsDir, "deployment.yaml")
+	err := os.WriteFile(
+		manifestPath,
+		[]byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api-deployment\n"),
+		osutil.PermissionFile,
+	)
+	require.NoError(t, err)
+
+	return manifestPath
+}
+
+func deployTestService(
+	t *testing.T,
+	mockContext *mocks.MockContext,
+	serviceTarget ServiceTarget,
+	serviceConfig *ServiceConfig,
+) (*ServiceDeployResult, error) {
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	return logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+}
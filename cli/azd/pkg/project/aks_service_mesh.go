@@ -0,0 +1,267 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/sethvargo/go-retry"
+)
+
+type AksServiceMeshType string
+
+const (
+	AksServiceMeshTypeIstio   AksServiceMeshType = "istio"
+	AksServiceMeshTypeLinkerd AksServiceMeshType = "linkerd"
+	AksServiceMeshTypeOsm     AksServiceMeshType = "osm"
+)
+
+// The AKS service mesh options
+// When configured, the namespace is labeled and annotated for sidecar injection and the deployment pods are
+// verified to be running with a ready sidecar
+type AksServiceMeshOptions struct {
+	// The service mesh installed on the cluster, istio, linkerd or osm
+	Type AksServiceMeshType `yaml:"type"`
+	// The Istio control plane revision used for sidecar injection, ex) asm-1-22. Required by the AKS Istio add-on.
+	// Without a revision the namespace is labeled with 'istio-injection: enabled'
+	Revision string `yaml:"revision"`
+	// Whether sidecar injection is enabled for the namespace. Defaults to true
+	Injection *bool `yaml:"injection"`
+	// Whether to wait for the sidecars of the deployment pods to be ready. Defaults to true when injection is enabled
+	WaitForSidecar *bool `yaml:"waitForSidecar"`
+	// The mesh ingress gateway service used to resolve the service endpoints, in '<namespace>/<name>' format,
+	// ex) aks-istio-ingress/aks-istio-ingressgateway-external
+	IngressGateway string `yaml:"ingressGateway"`
+}
+
+// injectionEnabled returns whether sidecar injection is enabled for the namespace
+func (o *AksServiceMeshOptions) injectionEnabled() bool {
+	return o.Injection == nil || *o.Injection
+}
+
+// sidecarName returns the name of the sidecar container injected by the service mesh
+func (o *AksServiceMeshOptions) sidecarName() string {
+	switch o.Type {
+	case AksServiceMeshTypeLinkerd:
+		return "linkerd-proxy"
+	case AksServiceMeshTypeOsm:
+		return "envoy"
+	default:
+		return "istio-proxy"
+	}
+}
+
+// namespaceMetadata returns the labels and annotations that enable or disable sidecar injection for the namespace
+func (o *AksServiceMeshOptions) namespaceMetadata() (map[string]string, map[string]string, error) {
+	injection := "enabled"
+	if !o.injectionEnabled() {
+		injection = "disabled"
+	}
+
+	switch o.Type {
+	case AksServiceMeshTypeIstio:
+		if o.Revision != "" && o.injectionEnabled() {
+			return map[string]string{"istio.io/rev": o.Revision}, nil, nil
+		}
+
+		return map[string]string{"istio-injection": injection}, nil, nil
+	case AksServiceMeshTypeLinkerd:
+		return nil, map[string]string{"linkerd.io/inject": injection}, nil
+	case AksServiceMeshTypeOsm:
+		return map[string]string{"openservicemesh.io/monitored-by": "osm"},
+			map[string]string{"openservicemesh.io/sidecar-injection": injection},
+			nil
+	default:
+		return nil, nil, fmt.Errorf(
+			"unsupported service mesh type '%s', supported values are 'istio', 'linkerd' and 'osm'", o.Type,
+		)
+	}
+}
+
+// withServiceMesh returns the namespace options including the labels and annotations of the service mesh
+// Labels and annotations configured on the namespace take precedence over the service mesh defaults
+func withServiceMesh(options *AksNamespaceOptions, mesh *AksServiceMeshOptions) (*AksNamespaceOptions, error) {
+	if options == nil {
+		options = &AksNamespaceOptions{}
+	}
+
+	if mesh == nil {
+		return options, nil
+	}
+
+	labels, annotations, err := mesh.namespaceMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	merged := *options
+	merged.Labels = mergeStringMaps(labels, options.Labels)
+	merged.Annotations = mergeStringMaps(annotations, options.Annotations)
+
+	return &merged, nil
+}
+
+// mergeStringMaps returns a new map with the values of all maps, later maps take precedence
+func mergeStringMaps(values ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, value := range values {
+		maps.Copy(merged, value)
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	return merged
+}
+
+// waitForSidecars waits until all pods of the deployment are running with a ready service mesh sidecar
+// Pods created before sidecar injection was enabled for the namespace are reported until they are replaced
+func (t *aksTarget) waitForSidecars(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentName string,
+	task *async.Progress[ServiceProgress],
+) error {
+	mesh := serviceConfig.K8s.ServiceMesh
+	if mesh == nil || !mesh.injectionEnabled() || (mesh.WaitForSidecar != nil && !*mesh.WaitForSidecar) {
+		return nil
+	}
+
+	sidecarName := mesh.sidecarName()
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying %s sidecars: %s", mesh.Type, deploymentName)))
+
+	pending, timeout, err := t.waitForSidecarContainer(ctx, serviceConfig, deploymentName, sidecarName)
+	if errors.Is(err, kubectl.ErrResourceNotReady) {
+		return fmt.Errorf(
+			//nolint:lll
+			"%s sidecar of deployment '%s' is not ready after %s (%s). Pods created before sidecar injection was enabled can be replaced with 'kubectl rollout restart deployment/%s'",
+			sidecarName,
+			deploymentName,
+			timeout.Round(time.Second),
+			strings.Join(pending, ", "),
+			deploymentName,
+		)
+	}
+
+	return err
+}
+
+// waitForSidecarContainer waits until all pods of the deployment are running with a ready sidecar container
+// Returns the pods whose sidecar is not injected or not ready along with kubectl.ErrResourceNotReady when the
+// sidecars are not ready within the wait timeout of the service
+func (t *aksTarget) waitForSidecarContainer(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentName string,
+	sidecarName string,
+) ([]string, time.Duration, error) {
+	waitOptions := t.getWaitOptions(serviceConfig)
+	timeout := kubectl.DefaultWaitTimeout
+	if waitOptions.Timeout > 0 {
+		timeout = waitOptions.Timeout
+	}
+
+	pollInterval := kubectl.DefaultWaitPollInterval
+	if waitOptions.PollInterval > 0 {
+		pollInterval = waitOptions.PollInterval
+	}
+
+	var pending []string
+	err := retry.Do(
+		ctx,
+		retry.WithMaxDuration(timeout, retry.NewConstant(pollInterval)),
+		func(ctx context.Context) error {
+			pending = []string{}
+			for _, pod := range t.getWorkloadPods(ctx, deploymentName) {
+				sidecar := pod.ContainerStatus(sidecarName)
+				switch {
+				case sidecar == nil:
+					pending = append(pending, fmt.Sprintf("%s: not injected", pod.Metadata.Name))
+				case !sidecar.Ready:
+					pending = append(pending, fmt.Sprintf("%s: not ready", pod.Metadata.Name))
+				}
+			}
+
+			if len(pending) > 0 {
+				return retry.RetryableError(kubectl.ErrResourceNotReady)
+			}
+
+			return nil
+		},
+	)
+
+	return pending, timeout, err
+}
+
+// getMeshGatewayEndpoints returns the endpoints of the service mesh ingress gateway
+// The gateway is waited on until the load balancer has assigned an address
+func (t *aksTarget) getMeshGatewayEndpoints(ctx context.Context, serviceConfig *ServiceConfig) ([]string, error) {
+	gateway := serviceConfig.K8s.ServiceMesh.IngressGateway
+	namespace, name, has := strings.Cut(gateway, "/")
+	if !has || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid service mesh ingress gateway '%s', expected '<namespace>/<name>'", gateway)
+	}
+
+	waitOptions := t.getWaitOptions(serviceConfig)
+	waitOptions.Namespace = namespace
+
+	service, err := kubectl.WaitForResource(
+		ctx, t.kubectl, kubectl.ResourceTypeService,
+		func(service *kubectl.Service) bool {
+			return service.Metadata.Name == name
+		},
+		func(service *kubectl.Service) bool {
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				if ingress.Address() != "" {
+					return true
+				}
+			}
+
+			return false
+		},
+		waitOptions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the http and https ports are reported, ex) the Istio gateway also exposes its status port
+	ports := []int{}
+	for _, port := range service.Spec.Ports {
+		if port.Port == 80 || port.Port == 443 {
+			ports = append(ports, port.Port)
+		}
+	}
+
+	if len(ports) == 0 {
+		ports = append(ports, 0)
+	}
+
+	var endpoints []string
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		address := ingress.Address()
+		if address == "" {
+			continue
+		}
+
+		for _, port := range ports {
+			protocol := "http"
+			if port == 443 {
+				protocol = "https"
+			}
+
+			endpoints = append(
+				endpoints,
+				fmt.Sprintf("%s (Mesh gateway: %s, Type: LoadBalancer)", endpointBaseUrl(protocol, address, port), gateway),
+			)
+		}
+	}
+
+	return endpoints, nil
+}
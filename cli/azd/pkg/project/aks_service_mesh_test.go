@@ -0,0 +1,209 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithServiceMesh(t *testing.T) {
+	tests := map[string]struct {
+		namespace           *AksNamespaceOptions
+		mesh                *AksServiceMeshOptions
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+		expectedError       string
+	}{
+		"IstioRevision": {
+			mesh:           &AksServiceMeshOptions{Type: AksServiceMeshTypeIstio, Revision: "asm-1-22"},
+			expectedLabels: map[string]string{"istio.io/rev": "asm-1-22"},
+		},
+		"Istio": {
+			mesh:           &AksServiceMeshOptions{Type: AksServiceMeshTypeIstio},
+			expectedLabels: map[string]string{"istio-injection": "enabled"},
+		},
+		"IstioInjectionDisabled": {
+			mesh: &AksServiceMeshOptions{
+				Type:      AksServiceMeshTypeIstio,
+				Revision:  "asm-1-22",
+				Injection: to.Ptr(false),
+			},
+			expectedLabels: map[string]string{"istio-injection": "disabled"},
+		},
+		"Linkerd": {
+			mesh:                &AksServiceMeshOptions{Type: AksServiceMeshTypeLinkerd},
+			expectedAnnotations: map[string]string{"linkerd.io/inject": "enabled"},
+		},
+		"Osm": {
+			mesh:                &AksServiceMeshOptions{Type: AksServiceMeshTypeOsm},
+			expectedLabels:      map[string]string{"openservicemesh.io/monitored-by": "osm"},
+			expectedAnnotations: map[string]string{"openservicemesh.io/sidecar-injection": "enabled"},
+		},
+		"NamespaceOverrides": {
+			namespace: &AksNamespaceOptions{
+				Labels:      map[string]string{"istio-injection": "disabled", "team": "a"},
+				Annotations: map[string]string{"contoso.com/owner": "team-a"},
+			},
+			mesh:                &AksServiceMeshOptions{Type: AksServiceMeshTypeIstio},
+			expectedLabels:      map[string]string{"istio-injection": "disabled", "team": "a"},
+			expectedAnnotations: map[string]string{"contoso.com/owner": "team-a"},
+		},
+		"Unsupported": {
+			mesh:          &AksServiceMeshOptions{Type: "consul"},
+			expectedError: "unsupported service mesh type 'consul'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			options, err := withServiceMesh(test.namespace, test.mesh)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedLabels, options.Labels)
+			require.Equal(t, test.expectedAnnotations, options.Annotations)
+		})
+	}
+}
+
+func Test_WaitForSidecars(t *testing.T) {
+	tests := map[string]struct {
+		responses     [][]kubectl.Pod
+		expectedError string
+	}{
+		"Ready": {
+			responses: [][]kubectl.Pod{
+				// The pod created before sidecar injection was enabled is still terminating
+				{
+					createPod("api-5d8f7b-old", "Running", kubectl.ContainerState{}, true),
+					createSidecarPod("api-5d8f7b-new", "istio-proxy", true),
+				},
+				{
+					createSidecarPod("api-5d8f7b-new", "istio-proxy", true),
+				},
+			},
+		},
+		"NotInjected": {
+			responses: [][]kubectl.Pod{
+				{
+					createPod("api-5d8f7b-old", "Running", kubectl.ContainerState{}, true),
+					createSidecarPod("api-5d8f7b-new", "istio-proxy", false),
+				},
+			},
+			expectedError: "istio-proxy sidecar of deployment 'api' is not ready",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+
+			calls := 0
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get pods")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				pods := test.responses[min(calls, len(test.responses)-1)]
+				calls++
+				jsonBytes, _ := json.Marshal(kubectl.List[kubectl.Pod]{Items: pods})
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.ServiceMesh = &AksServiceMeshOptions{Type: AksServiceMeshTypeIstio}
+			serviceConfig.K8s.Wait.Timeout = 50 * time.Millisecond
+			serviceConfig.K8s.Wait.PollInterval = time.Millisecond
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil).(*aksTarget)
+			err := async.RunWithProgressE(func(progress ServiceProgress) {}, func(p *async.Progress[ServiceProgress]) error {
+				return serviceTarget.waitForSidecars(*mockContext.Context, serviceConfig, "api", p)
+			})
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				require.ErrorContains(t, err, "api-5d8f7b-old: not injected")
+				require.ErrorContains(t, err, "api-5d8f7b-new: not ready")
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, 2, calls)
+		})
+	}
+}
+
+func Test_MeshGatewayEndpoints(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var getArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get svc")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		getArgs = args.Args
+		service := &kubectl.Service{
+			Resource: kubectl.Resource{
+				Metadata: kubectl.ResourceMetadata{
+					Name:      "aks-istio-ingressgateway-external",
+					Namespace: "aks-istio-ingress",
+				},
+			},
+			Spec: kubectl.ServiceSpec{
+				Type: kubectl.ServiceTypeLoadBalancer,
+				Ports: []kubectl.Port{
+					{Port: 15021, TargetPort: 15021},
+					{Port: 80, TargetPort: 8080},
+					{Port: 443, TargetPort: 8443},
+				},
+			},
+			Status: kubectl.ServiceStatus{
+				LoadBalancer: kubectl.LoadBalancer{
+					Ingress: []kubectl.LoadBalancerIngress{{Ip: "20.10.10.10"}},
+				},
+			},
+		}
+		jsonBytes, _ := json.Marshal(createK8sResourceList(service))
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.ServiceMesh = &AksServiceMeshOptions{
+		Type:           AksServiceMeshTypeIstio,
+		IngressGateway: "aks-istio-ingress/aks-istio-ingressgateway-external",
+	}
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil).(*aksTarget)
+	endpoints, err := serviceTarget.getMeshGatewayEndpoints(*mockContext.Context, serviceConfig)
+	require.NoError(t, err)
+	require.Contains(t, getArgs, "aks-istio-ingress")
+	require.Equal(t, []string{
+		"http://20.10.10.10 (Mesh gateway: aks-istio-ingress/aks-istio-ingressgateway-external, Type: LoadBalancer)",
+		"https://20.10.10.10 (Mesh gateway: aks-istio-ingress/aks-istio-ingressgateway-external, Type: LoadBalancer)",
+	}, endpoints)
+
+	serviceConfig.K8s.ServiceMesh.IngressGateway = "aks-istio-ingressgateway-external"
+	_, err = serviceTarget.getMeshGatewayEndpoints(*mockContext.Context, serviceConfig)
+	require.ErrorContains(t, err, "expected '<namespace>/<name>'")
+}
+
+func createSidecarPod(name string, sidecarName string, sidecarReady bool) kubectl.Pod {
+	pod := createPod(name, "Running", kubectl.ContainerState{}, sidecarReady)
+	pod.Status.ContainerStatuses = append(
+		pod.Status.ContainerStatuses,
+		kubectl.ContainerStatus{Name: sidecarName, Ready: sidecarReady},
+	)
+
+	return pod
+}
@@ -0,0 +1,81 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The annotation k8s records the revision of a deployment with
+const k8sDeploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// The rollout statuses of a k8s deployment
+const (
+	aksStatusAvailable   = "Available"
+	aksStatusProgressing = "Progressing"
+)
+
+// Status returns the live rollout status of the primary k8s deployment of the service, resolved the same way it is
+// when verifying a deployment
+func (t *aksTarget) Status(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (*ServiceStatus, error) {
+	namespace, err := t.useServiceCluster(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentNames, err := t.resolveResourceNames(
+		ctx,
+		kubectl.ResourceTypeDeployment,
+		t.getDeploymentName(serviceConfig),
+		serviceConfig.K8s.Deployment.Names,
+		serviceConfig.K8s.Deployment.Selector,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := kubectl.GetResources[kubectl.Deployment](ctx, t.kubectl, kubectl.ResourceTypeDeployment, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting deployments: %w", err)
+	}
+
+	for _, deployment := range deployments.Items {
+		if strings.Contains(deployment.Metadata.Name, deploymentNames[0]) {
+			return deploymentStatus(&deployment), nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"k8s deployment matching '%s' %w in namespace '%s'", deploymentNames[0], kubectl.ErrResourceNotFound, namespace)
+}
+
+// deploymentStatus returns the rollout status of the deployment. The revision is the azd revision the deployment was
+// deployed with, ex) the revision to pass to `azd rollback`, or the k8s revision when not deployed by azd.
+func deploymentStatus(deployment *kubectl.Deployment) *ServiceStatus {
+	status := &ServiceStatus{
+		Status: aksStatusAvailable,
+		Details: fmt.Sprintf(
+			"%d/%d replicas available", deployment.Status.AvailableReplicas, deployment.Spec.Replicas),
+	}
+
+	if deployment.Status.UpdatedReplicas < deployment.Spec.Replicas ||
+		deployment.Status.AvailableReplicas < deployment.Spec.Replicas {
+		status.Status = aksStatusProgressing
+	}
+
+	for _, annotation := range []string{aksRevisionAnnotation, k8sDeploymentRevisionAnnotation} {
+		if revision, ok := deployment.Metadata.Annotations[annotation]; ok {
+			status.Revision = fmt.Sprint(revision)
+			break
+		}
+	}
+
+	return status
+}
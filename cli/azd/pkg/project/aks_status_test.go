@@ -0,0 +1,39 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DeploymentStatus(t *testing.T) {
+	deployment := func(annotations map[string]any, status kubectl.DeploymentStatus) *kubectl.Deployment {
+		return &kubectl.Deployment{
+			Resource: kubectl.Resource{
+				Metadata: kubectl.ResourceMetadata{Name: "api", Annotations: annotations},
+			},
+			Spec:   kubectl.DeploymentSpec{Replicas: 3},
+			Status: status,
+		}
+	}
+
+	t.Run("Available", func(t *testing.T) {
+		status := deploymentStatus(deployment(
+			map[string]any{aksRevisionAnnotation: "4", k8sDeploymentRevisionAnnotation: "7"},
+			kubectl.DeploymentStatus{AvailableReplicas: 3, UpdatedReplicas: 3, Replicas: 3},
+		))
+
+		// The azd revision is reported, ex) to roll back with `azd rollback`
+		require.Equal(t, &ServiceStatus{Revision: "4", Status: "Available", Details: "3/3 replicas available"}, status)
+	})
+
+	t.Run("Progressing", func(t *testing.T) {
+		status := deploymentStatus(deployment(
+			map[string]any{k8sDeploymentRevisionAnnotation: "7"},
+			kubectl.DeploymentStatus{AvailableReplicas: 3, UpdatedReplicas: 1, Replicas: 4},
+		))
+
+		require.Equal(t, &ServiceStatus{Revision: "7", Status: "Progressing", Details: "3/3 replicas available"}, status)
+	})
+}
@@ -0,0 +1,67 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// waitForConditions waits for the user-defined conditions in the order they are defined
+// The conditions are evaluated after all helm charts, kustomize overlays and manifests have been deployed.
+func (t *aksTarget) waitForConditions(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	task *async.Progress[ServiceProgress],
+) error {
+	for i, waitFor := range serviceConfig.K8s.WaitFor {
+		resource, err := waitFor.resource()
+		if err != nil {
+			return fmt.Errorf("invalid wait condition at index %d, %w", i, err)
+		}
+
+		timeout := waitFor.Timeout
+		if timeout == 0 {
+			timeout = serviceConfig.K8s.Wait.Timeout
+		}
+
+		if timeout == 0 {
+			timeout = kubectl.DefaultWaitTimeout
+		}
+
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Waiting for %s: %s", resource, waitFor.Condition)))
+		_, err = t.kubectl.Wait(ctx, resource, waitFor.Condition, timeout, &kubectl.KubeCliFlags{
+			Namespace:     waitFor.Namespace,
+			LabelSelector: waitFor.Selector,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resource returns the resource argument of the kubectl wait command, ex) deployment/api
+func (c *AksWaitCondition) resource() (string, error) {
+	if c.Kind == "" {
+		return "", errors.New("the kind of the resource is required")
+	}
+
+	if c.Condition == "" {
+		return "", errors.New("the condition is required")
+	}
+
+	switch {
+	case c.Name != "" && c.Selector != "":
+		return "", errors.New("either the name or the selector of the resource can be specified, not both")
+	case c.Name != "":
+		return fmt.Sprintf("%s/%s", c.Kind, c.Name), nil
+	case c.Selector != "":
+		return c.Kind, nil
+	default:
+		return "", errors.New("either the name or the selector of the resource is required")
+	}
+}
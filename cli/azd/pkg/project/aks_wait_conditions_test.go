@@ -0,0 +1,84 @@
+package project
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_WaitFor(t *testing.T) {
+	manifests := map[string]string{
+		"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n",
+	}
+
+	tests := map[string]struct {
+		waitFor       []AksWaitCondition
+		waitErr       error
+		expectedArgs  [][]string
+		expectedError string
+	}{
+		"NameAndSelector": {
+			waitFor: []AksWaitCondition{
+				{Kind: "deployment", Name: "api", Condition: "condition=Available"},
+				{
+					Kind:      "pods",
+					Selector:  "app=api",
+					Namespace: "jobs",
+					Condition: "jsonpath={.status.phase}=Running",
+					Timeout:   2 * time.Minute,
+				},
+			},
+			expectedArgs: [][]string{
+				withIsolatedKubeConfig("wait", "deployment/api", "--for=condition=Available", "--timeout=10ms"),
+				withIsolatedKubeConfig(
+					"wait", "pods", "--for=jsonpath={.status.phase}=Running", "--timeout=2m0s", "-n", "jobs",
+					"-l", "app=api",
+				),
+			},
+		},
+		"Failed": {
+			waitFor: []AksWaitCondition{
+				{Kind: "certificate.cert-manager.io", Name: "api-tls", Condition: "condition=Ready"},
+			},
+			waitErr:       errors.New("timed out waiting for the condition"),
+			expectedError: "failed waiting for 'certificate.cert-manager.io/api-tls' to meet condition 'condition=Ready'",
+		},
+		"MissingCondition": {
+			waitFor:       []AksWaitCondition{{Kind: "deployment", Name: "api"}},
+			expectedError: "invalid wait condition at index 0, the condition is required",
+		},
+		"NameAndSelectorConflict": {
+			waitFor:       []AksWaitCondition{{Kind: "pods", Name: "api", Selector: "app=api", Condition: "delete"}},
+			expectedError: "either the name or the selector of the resource can be specified, not both",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, manifests)
+			serviceConfig.K8s.WaitFor = test.waitFor
+
+			waitArgs := [][]string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl wait")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				waitArgs = append(waitArgs, args.Args)
+				return exec.NewRunResult(0, "", ""), test.waitErr
+			})
+
+			deployResult, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, deployResult)
+			require.Equal(t, test.expectedArgs, waitArgs)
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The number of log lines of the job containers included when a job fails
+const aksJobLogTail = 50
+
+// waitForWorkloads verifies the jobs, cron jobs, stateful sets and daemon sets defined within the manifests of the
+// deployment path in the order they are defined. Jobs are waited on until they complete, stateful sets and daemon
+// sets until they are rolled out, while cron jobs are only verified to exist since they run on a schedule.
+// Custom resources matching a readiness rule are waited on until their status conditions report them ready
+func (t *aksTarget) waitForWorkloads(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentPath string,
+	task *async.Progress[ServiceProgress],
+) error {
+	manifests, err := t.kubectl.ReadManifests(deploymentPath, serviceConfig.K8s.Envsubst)
+	if err != nil {
+		return err
+	}
+
+	objects, err := parseK8sObjects(manifests)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		kind := object.kind()
+		rule := findReadinessRule(serviceConfig.K8s.Wait.Resources, object)
+		if kind != "Job" && kind != "CronJob" && kind != "StatefulSet" && kind != "DaemonSet" && rule == nil {
+			continue
+		}
+
+		// Workloads created with a generated name cannot be matched to the live resource
+		name := object.name()
+		if name == "" {
+			log.Printf("skipping verification of %s without a name\n", kind)
+			continue
+		}
+
+		switch {
+		case rule != nil:
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying %s: %s", kind, name)))
+			err = t.waitForCustomResource(ctx, serviceConfig, object, rule)
+		case kind == "Job":
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying job: %s", name)))
+			err = t.waitForJob(ctx, serviceConfig, name)
+		case kind == "CronJob":
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying cron job: %s", name)))
+			err = t.verifyCronJob(ctx, name)
+		case kind == "StatefulSet":
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying stateful set: %s", name)))
+			err = t.waitForStatefulSet(ctx, serviceConfig, name)
+		case kind == "DaemonSet":
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying daemon set: %s", name)))
+			err = t.waitForDaemonSet(ctx, serviceConfig, name)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findReadinessRule returns the readiness rule matching the kind and API group of the k8s object, otherwise nil
+func findReadinessRule(rules []AksReadinessRule, object k8sObject) *AksReadinessRule {
+	apiVersion, _ := object["apiVersion"].(string)
+	group := ""
+	if before, _, found := strings.Cut(apiVersion, "/"); found {
+		group = before
+	}
+
+	for i, rule := range rules {
+		if strings.EqualFold(rule.Kind, object.kind()) && (rule.Group == "" || strings.EqualFold(rule.Group, group)) {
+			return &rules[i]
+		}
+	}
+
+	return nil
+}
+
+// waitForCustomResource waits until the status conditions of the custom resource report it ready
+func (t *aksTarget) waitForCustomResource(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	object k8sObject,
+	rule *AksReadinessRule,
+) error {
+	// The resource type is qualified with the API group, ex) certificate.cert-manager.io, to avoid ambiguous kinds
+	resourceType := strings.ToLower(object.kind())
+	if apiVersion, _ := object["apiVersion"].(string); strings.Contains(apiVersion, "/") {
+		group, _, _ := strings.Cut(apiVersion, "/")
+		resourceType = fmt.Sprintf("%s.%s", resourceType, group)
+	}
+
+	_, err := kubectl.WaitForReadiness(
+		ctx, t.kubectl, kubectl.ResourceType(resourceType), object.name(),
+		&kubectl.ReadinessRule{
+			ReadyConditions:   rule.Conditions,
+			FailureConditions: rule.FailureConditions,
+		},
+		t.getWaitOptions(serviceConfig),
+	)
+
+	return err
+}
+
+// waitForStatefulSet waits until the latest revision of the stateful set has been rolled out
+func (t *aksTarget) waitForStatefulSet(ctx context.Context, serviceConfig *ServiceConfig, name string) error {
+	_, err := kubectl.WaitForResource(
+		ctx, t.kubectl, kubectl.ResourceTypeStatefulSet,
+		func(statefulSet *kubectl.StatefulSet) bool {
+			return statefulSet.Metadata.Name == name
+		},
+		func(statefulSet *kubectl.StatefulSet) bool {
+			return statefulSet.IsRolledOut()
+		},
+		t.getWaitOptions(serviceConfig),
+	)
+
+	return t.workloadTimeoutError(ctx, name, err)
+}
+
+// waitForDaemonSet waits until the latest revision of the daemon set has been rolled out to all nodes
+func (t *aksTarget) waitForDaemonSet(ctx context.Context, serviceConfig *ServiceConfig, name string) error {
+	_, err := kubectl.WaitForResource(
+		ctx, t.kubectl, kubectl.ResourceTypeDaemonSet,
+		func(daemonSet *kubectl.DaemonSet) bool {
+			return daemonSet.Metadata.Name == name
+		},
+		func(daemonSet *kubectl.DaemonSet) bool {
+			return daemonSet.IsRolledOut()
+		},
+		t.getWaitOptions(serviceConfig),
+	)
+
+	return t.workloadTimeoutError(ctx, name, err)
+}
+
+// workloadTimeoutError includes the last observed state of the workload pods when waiting for the workload timed out
+func (t *aksTarget) workloadTimeoutError(ctx context.Context, name string, err error) error {
+	var timeoutErr *kubectl.ResourceTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return &kubectl.ResourceTimeoutError{
+			ResourceType: timeoutErr.ResourceType,
+			ResourceName: name,
+			Timeout:      timeoutErr.Timeout,
+			Pods:         t.getWorkloadPods(ctx, name),
+			Err:          timeoutErr.Err,
+		}
+	}
+
+	return err
+}
+
+// waitForJob waits until the job has completed
+// When the job fails, the logs of the job containers are included to help diagnose the failure
+func (t *aksTarget) waitForJob(ctx context.Context, serviceConfig *ServiceConfig, jobName string) error {
+	_, err := kubectl.WaitForJob(ctx, t.kubectl, jobName, t.getWaitOptions(serviceConfig))
+
+	var failedErr *kubectl.JobFailedError
+	if errors.As(err, &failedErr) {
+		return &kubectl.JobFailedError{
+			JobName: failedErr.JobName,
+			Reason:  failedErr.Reason,
+			Message: failedErr.Message,
+			Logs:    t.getJobLogs(ctx, jobName),
+		}
+	}
+
+	var timeoutErr *kubectl.ResourceTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return &kubectl.ResourceTimeoutError{
+			ResourceType: timeoutErr.ResourceType,
+			ResourceName: jobName,
+			Timeout:      timeoutErr.Timeout,
+			Pods:         t.getJobPods(ctx, jobName),
+			Err:          timeoutErr.Err,
+		}
+	}
+
+	return err
+}
+
+// verifyCronJob verifies the cron job exists within the cluster
+func (t *aksTarget) verifyCronJob(ctx context.Context, cronJobName string) error {
+	cronJob, err := kubectl.GetResource[kubectl.CronJob](ctx, t.kubectl, kubectl.ResourceTypeCronJob, cronJobName, nil)
+	if err != nil {
+		return fmt.Errorf("failed verifying cron job '%s': %w", cronJobName, err)
+	}
+
+	if cronJob.Spec.Suspend {
+		log.Printf("cron job '%s' is suspended and will not be scheduled\n", cronJobName)
+	}
+
+	return nil
+}
+
+// getJobLogs returns the last logs of the job containers
+// Errors are ignored since the logs are only used to provide additional diagnostics
+func (t *aksTarget) getJobLogs(ctx context.Context, jobName string) string {
+	logs, err := t.kubectl.Logs(ctx, fmt.Sprintf("job/%s", jobName), aksJobLogTail, nil)
+	if err != nil {
+		log.Printf("failed getting logs for job '%s': %v\n", jobName, err)
+		return ""
+	}
+
+	return logs
+}
+
+// getJobPods returns the pods created for the job
+// Errors are ignored since the pods are only used to provide additional diagnostics
+func (t *aksTarget) getJobPods(ctx context.Context, jobName string) []kubectl.Pod {
+	pods, err := kubectl.GetResources[kubectl.Pod](ctx, t.kubectl, kubectl.ResourceTypePod, &kubectl.KubeCliFlags{
+		LabelSelector: fmt.Sprintf("job-name=%s", jobName),
+	})
+	if err != nil {
+		log.Printf("failed getting pods for job '%s': %v\n", jobName, err)
+		return nil
+	}
+
+	return pods.Items
+}
+
+// The maximum number of pods included in the rollout progress
+const aksRolloutProgressPods = 3
+
+// reportRolloutProgress reports the number of available replicas and the status of the pods that are not ready yet
+// while waiting for the workload rollout, ex) Verifying deployment: api (1/3 ready, api-7d9f-x2: CrashLoopBackOff)
+func (t *aksTarget) reportRolloutProgress(
+	ctx context.Context,
+	task *async.Progress[ServiceProgress],
+	workloadName string,
+	available int,
+	desired int,
+) {
+	details := []string{fmt.Sprintf("%d/%d ready", available, desired)}
+
+	pending := []string{}
+	for _, pod := range t.getWorkloadPods(ctx, workloadName) {
+		if !pod.IsReady() {
+			pending = append(pending, fmt.Sprintf("%s: %s", pod.Metadata.Name, pod.StatusReason()))
+		}
+	}
+
+	if len(pending) > aksRolloutProgressPods {
+		remaining := len(pending) - aksRolloutProgressPods
+		pending = append(pending[:aksRolloutProgressPods], fmt.Sprintf("%d more", remaining))
+	}
+
+	details = append(details, pending...)
+	task.SetProgress(NewServiceProgress(
+		fmt.Sprintf("Verifying deployment: %s (%s)", workloadName, strings.Join(details, ", ")),
+	))
+}
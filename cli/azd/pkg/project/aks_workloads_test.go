@@ -0,0 +1,390 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_Jobs(t *testing.T) {
+	t.Run("Complete", func(t *testing.T) {
+		mockContext, serviceTarget, serviceConfig := setupAksJobTest(t)
+		setupJobMocks(mockContext, []kubectl.JobCondition{{Type: "Complete", Status: "True"}})
+
+		cronJobVerified := false
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl get cronjob cleanup")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			cronJobVerified = true
+			return exec.NewRunResult(0, `{"metadata":{"name":"cleanup"}}`, ""), nil
+		})
+
+		deployResult, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+		require.NoError(t, err)
+		require.NotNil(t, deployResult)
+		require.True(t, cronJobVerified)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockContext, serviceTarget, serviceConfig := setupAksJobTest(t)
+		setupJobMocks(mockContext, []kubectl.JobCondition{
+			{Type: "Failed", Status: "True", Reason: "BackoffLimitExceeded", Message: "Job has reached the backoff limit"},
+		})
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl logs job/migrate")
+		}).Respond(exec.NewRunResult(0, "error: relation \"users\" already exists", ""))
+
+		_, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+		require.Error(t, err)
+
+		var failedErr *kubectl.JobFailedError
+		require.True(t, errors.As(err, &failedErr))
+		require.Equal(t, "migrate", failedErr.JobName)
+		require.Equal(t, "BackoffLimitExceeded", failedErr.Reason)
+		require.ErrorContains(t, err, "relation \"users\" already exists")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		mockContext, serviceTarget, serviceConfig := setupAksJobTest(t)
+		setupJobMocks(mockContext, nil)
+
+		podSelector := ""
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl get pods")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			podSelector = args.Args[slices.Index(args.Args, "-l")+1]
+			pod := kubectl.Pod{
+				Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "migrate-x2x4z"}},
+				Status:   kubectl.PodStatus{Phase: "Pending"},
+			}
+			jsonBytes, _ := json.Marshal(createK8sResourceList(pod))
+
+			return exec.NewRunResult(0, string(jsonBytes), ""), nil
+		})
+
+		_, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+		require.Error(t, err)
+
+		var timeoutErr *kubectl.ResourceTimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.Equal(t, kubectl.ResourceTypeJob, timeoutErr.ResourceType)
+		require.Equal(t, "migrate", timeoutErr.ResourceName)
+		require.Len(t, timeoutErr.Pods, 1)
+		require.Equal(t, "job-name=migrate", podSelector)
+	})
+}
+
+func Test_Deploy_StatefulSet_DaemonSet(t *testing.T) {
+	manifests := map[string]string{
+		"statefulset.yaml": "apiVersion: apps/v1\nkind: StatefulSet\nmetadata:\n  name: db\n",
+		"daemonset.yaml":   "apiVersion: apps/v1\nkind: DaemonSet\nmetadata:\n  name: agent\n",
+	}
+
+	t.Run("RolledOut", func(t *testing.T) {
+		mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, manifests)
+
+		// The stateful set is rolled out on the second attempt, the watch of the stateful set is closed without events
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl get statefulset --watch")
+		}).Respond(exec.NewRunResult(0, "", ""))
+
+		statefulSetAttempts := 0
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl get statefulset") && !strings.Contains(command, "--watch")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			statefulSetAttempts++
+			currentRevision := "db-1"
+			if statefulSetAttempts > 1 {
+				currentRevision = "db-2"
+			}
+
+			statefulSet := &kubectl.StatefulSet{
+				Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "db"}},
+				Status: kubectl.StatefulSetStatus{
+					ReadyReplicas:   1,
+					CurrentRevision: currentRevision,
+					UpdateRevision:  "db-2",
+				},
+			}
+			jsonBytes, _ := json.Marshal(createK8sResourceList(statefulSet))
+
+			return exec.NewRunResult(0, string(jsonBytes), ""), nil
+		})
+
+		daemonSetVerified := false
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl get daemonset")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			daemonSetVerified = true
+			daemonSet := &kubectl.DaemonSet{
+				Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "agent"}},
+				Status: kubectl.DaemonSetStatus{
+					DesiredNumberScheduled: 2,
+					UpdatedNumberScheduled: 2,
+					NumberAvailable:        2,
+				},
+			}
+			jsonBytes, _ := json.Marshal(createK8sResourceList(daemonSet))
+
+			return exec.NewRunResult(0, string(jsonBytes), ""), nil
+		})
+
+		serviceConfig.K8s.Wait.Timeout = time.Minute
+		deployResult, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+		require.NoError(t, err)
+		require.NotNil(t, deployResult)
+		require.Equal(t, 2, statefulSetAttempts)
+		require.True(t, daemonSetVerified)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, map[string]string{
+			"statefulset.yaml": manifests["statefulset.yaml"],
+		})
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl get statefulset")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			statefulSet := &kubectl.StatefulSet{
+				Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "db"}},
+			}
+			jsonBytes, _ := json.Marshal(createK8sResourceList(statefulSet))
+
+			return exec.NewRunResult(0, string(jsonBytes), ""), nil
+		})
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl get pods")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			pods := &kubectl.List[kubectl.Pod]{
+				Items: []kubectl.Pod{
+					{Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "db-0"}}},
+					{Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "api-7d9f8b6c5-x2x4z"}}},
+				},
+			}
+			jsonBytes, _ := json.Marshal(pods)
+
+			return exec.NewRunResult(0, string(jsonBytes), ""), nil
+		})
+
+		_, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+		require.Error(t, err)
+
+		var timeoutErr *kubectl.ResourceTimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.Equal(t, kubectl.ResourceTypeStatefulSet, timeoutErr.ResourceType)
+		require.Equal(t, "db", timeoutErr.ResourceName)
+		require.Len(t, timeoutErr.Pods, 1)
+	})
+}
+
+func Test_Deploy_CustomResources(t *testing.T) {
+	manifests := map[string]string{
+		"certificate.yaml":  "apiVersion: cert-manager.io/v1\nkind: Certificate\nmetadata:\n  name: api-tls\n",
+		"scaledobject.yaml": "apiVersion: keda.sh/v1alpha1\nkind: ScaledObject\nmetadata:\n  name: api\n",
+	}
+
+	tests := map[string]struct {
+		conditions    []kubectl.Condition
+		expectedError string
+	}{
+		"Ready": {
+			conditions: []kubectl.Condition{{Type: "Ready", Status: "True"}},
+		},
+		"Stalled": {
+			conditions: []kubectl.Condition{
+				{Type: "Ready", Status: "False"},
+				{Type: "Stalled", Status: "True", Reason: "IssuerNotFound", Message: "issuer 'letsencrypt' not found"},
+			},
+			expectedError: "certificate.cert-manager.io 'api-tls' failed with condition 'Stalled', IssuerNotFound",
+		},
+		"NotReady": {
+			conditions:    []kubectl.Condition{{Type: "Ready", Status: "False", Reason: "Issuing"}},
+			expectedError: "condition 'Ready' is False (Issuing)",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, manifests)
+			// The scaled object does not have a readiness rule and is not waited on
+			serviceConfig.K8s.Wait.Resources = []AksReadinessRule{
+				{Kind: "Certificate", Group: "cert-manager.io"},
+			}
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get certificate.cert-manager.io")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				certificate := &kubectl.CustomResource{
+					Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "api-tls"}},
+					Status:   kubectl.CustomResourceStatus{Conditions: test.conditions},
+				}
+				jsonBytes, _ := json.Marshal(createK8sResourceList(certificate))
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			deployResult, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, deployResult)
+		})
+	}
+}
+
+func setupAksJobTest(t *testing.T) (*mocks.MockContext, ServiceTarget, *ServiceConfig) {
+	return setupAksWorkloadTest(t, map[string]string{
+		"job.yaml":     "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: migrate\n",
+		"cronjob.yaml": "apiVersion: batch/v1\nkind: CronJob\nmetadata:\n  name: cleanup\n",
+	})
+}
+
+func setupAksWorkloadTest(
+	t *testing.T,
+	manifests map[string]string,
+) (*mocks.MockContext, ServiceTarget, *ServiceConfig) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Wait = AksWaitOptions{
+		Timeout:      10 * time.Millisecond,
+		PollInterval: time.Millisecond,
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
+	require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+	for filename, content := range manifests {
+		err = os.WriteFile(filepath.Join(manifestsDir, filename), []byte(content), osutil.PermissionFile)
+		require.NoError(t, err)
+	}
+
+	return mockContext, serviceTarget, serviceConfig
+}
+
+func setupJobMocks(mockContext *mocks.MockContext, conditions []kubectl.JobCondition) {
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get job")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		job := &kubectl.Job{
+			Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "migrate"}},
+			Status:   kubectl.JobStatus{Conditions: conditions},
+		}
+		jsonBytes, _ := json.Marshal(createK8sResourceList(job))
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get cronjob")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		cronJob := kubectl.CronJob{
+			Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: "cleanup"}},
+			Spec:     kubectl.CronJobSpec{Schedule: "0 * * * *"},
+		}
+		jsonBytes, _ := json.Marshal(cronJob)
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+}
+
+func deployAksWorkloadTest(
+	t *testing.T,
+	mockContext *mocks.MockContext,
+	serviceTarget ServiceTarget,
+	serviceConfig *ServiceConfig,
+) (*ServiceDeployResult, error) {
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	return logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+}
+
+func Test_ReportRolloutProgress(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get pods")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		pods := &kubectl.List[kubectl.Pod]{
+			Items: []kubectl.Pod{
+				createPod("api-7d9f-ready", "Running", kubectl.ContainerState{}, true),
+				createPod("api-7d9f-crash", "Running", kubectl.ContainerState{
+					Waiting: &kubectl.ContainerStateDetail{Reason: "CrashLoopBackOff"},
+				}, false),
+				createPod("api-7d9f-new", "Pending", kubectl.ContainerState{
+					Waiting: &kubectl.ContainerStateDetail{Reason: "ContainerCreating"},
+				}, false),
+				createPod("api-7d9f-pending", "Pending", kubectl.ContainerState{}, false),
+				createPod("api-7d9f-other", "Pending", kubectl.ContainerState{}, false),
+				createPod("web-5c4b-crash", "Running", kubectl.ContainerState{
+					Waiting: &kubectl.ContainerStateDetail{Reason: "CrashLoopBackOff"},
+				}, false),
+			},
+		}
+		jsonBytes, _ := json.Marshal(pods)
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil).(*aksTarget)
+
+	messages := []string{}
+	_, err := async.RunWithProgress(
+		func(progress ServiceProgress) { messages = append(messages, progress.Message) },
+		func(progress *async.Progress[ServiceProgress]) (any, error) {
+			serviceTarget.reportRolloutProgress(*mockContext.Context, progress, "api-7d9f", 1, 5)
+			return nil, nil
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"Verifying deployment: api-7d9f (1/5 ready, api-7d9f-crash: CrashLoopBackOff, " +
+			"api-7d9f-new: ContainerCreating, api-7d9f-pending: Pending, 1 more)",
+	}, messages)
+}
+
+func createPod(name string, phase string, state kubectl.ContainerState, ready bool) kubectl.Pod {
+	return kubectl.Pod{
+		Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: name}},
+		Status: kubectl.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []kubectl.ContainerStatus{
+				{Name: "app", Ready: ready, State: state},
+			},
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+type BuildCacheMode string
+
+const (
+	// Exports the layers of all build stages, so multi-stage builds reuse the layers of their build stages
+	BuildCacheModeMax BuildCacheMode = "max"
+	// Exports only the layers of the final image
+	BuildCacheModeMin BuildCacheMode = "min"
+)
+
+// The tag of the build cache when not configured
+const defaultBuildCacheTag = "buildcache"
+
+// BuildCacheOptions configures the registry-backed cache of the image build
+// The layers of the build are imported from and exported to a cache repository in the container registry of the
+// service, so builds on other machines, ex) CI runs, reuse the layers of previous builds.
+type BuildCacheOptions struct {
+	// The repository of the cache in the container registry, ex) todo/api-cache.
+	// Defaults to the image repository with the -cache suffix
+	Repository osutil.ExpandableString `yaml:"repository,omitempty" json:"repository,omitempty"`
+	// The tag of the cache, ex) main. Defaults to buildcache
+	Tag osutil.ExpandableString `yaml:"tag,omitempty"        json:"tag,omitempty"`
+	// The layers exported to the cache, ex) max or min. Defaults to max
+	Mode BuildCacheMode `yaml:"mode,omitempty"       json:"mode,omitempty"`
+	// When enabled, the layers are imported from the cache but the cache is not updated, ex) for pull request builds
+	ReadOnly bool `yaml:"readOnly,omitempty"   json:"readOnly,omitempty"`
+}
+
+// validateBuildCache returns an error when the build cache is configured with unsupported options
+func validateBuildCache(serviceConfig *ServiceConfig) error {
+	cache := serviceConfig.Docker.Cache
+	if cache == nil {
+		return nil
+	}
+
+	if cache.Mode != "" && cache.Mode != BuildCacheModeMax && cache.Mode != BuildCacheModeMin {
+		return fmt.Errorf(
+			"unsupported build cache mode '%s', supported modes are '%s' and '%s'",
+			cache.Mode, BuildCacheModeMax, BuildCacheModeMin,
+		)
+	}
+
+	// Remote builds run on ACR tasks and buildpacks cache the layers in their own cache image
+	if serviceConfig.Docker.RemoteBuild || serviceConfig.Docker.Buildpack {
+		return fmt.Errorf("the build cache is not supported with remote builds or buildpacks")
+	}
+
+	return nil
+}
+
+// buildCache returns the build cache in the container registry of the service
+func (ch *ContainerHelper) buildCache(ctx context.Context, serviceConfig *ServiceConfig) (*docker.BuildCache, error) {
+	cacheOptions := serviceConfig.Docker.Cache
+
+	registryName, err := ch.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := cacheOptions.Repository.Envsubst(ch.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst cache repository: %w", err)
+	}
+
+	if repository == "" {
+		image, err := ch.GeneratedImage(ctx, serviceConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		// ex) todo/api-dev-cache
+		repository = fmt.Sprintf("%s-cache", image.Repository)
+	}
+
+	tag, err := cacheOptions.Tag.Envsubst(ch.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst cache tag: %w", err)
+	}
+
+	if tag == "" {
+		tag = defaultBuildCacheTag
+	}
+
+	mode := cacheOptions.Mode
+	if mode == "" {
+		mode = BuildCacheModeMax
+	}
+
+	return &docker.BuildCache{
+		Builder:    docker.MultiPlatformBuilder,
+		Repository: fmt.Sprintf("%s/%s", registryName, repository),
+		Tag:        tag,
+		Mode:       string(mode),
+		ReadOnly:   cacheOptions.ReadOnly,
+	}, nil
+}
+
+// prepareBuildCache logs into the container registry of the build cache and creates the buildx builder the cache is
+// exported with. Returns nil when the build cache is not configured, or when the container registry is not available
+// yet, ex) when packaging before provisioning, so the image is built without the cache.
+func (ch *ContainerHelper) prepareBuildCache(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	progress *async.Progress[ServiceProgress],
+) (*docker.BuildCache, error) {
+	if serviceConfig.Docker.Cache == nil {
+		return nil, nil
+	}
+
+	cache, err := ch.buildCache(ctx, serviceConfig)
+	if err != nil {
+		log.Printf("building image for service %s without the build cache: %v", serviceConfig.Name, err)
+		return nil, nil
+	}
+
+	progress.SetProgress(NewServiceProgress("Logging into build cache registry"))
+	if _, err := ch.Login(ctx, serviceConfig); err != nil {
+		log.Printf("building image for service %s without the build cache: %v", serviceConfig.Name, err)
+		return nil, nil
+	}
+
+	if err := ch.docker.EnsureBuilder(ctx, cache.Builder); err != nil {
+		return nil, err
+	}
+
+	log.Printf("using build cache '%s' for service %s", cache.Ref(), serviceConfig.Name)
+	return cache, nil
+}
+
+// buildCacheProgress reports the cache hit statistics of the build, ex) Built Docker image, 4 of 6 steps cached
+func buildCacheProgress(
+	serviceConfig *ServiceConfig,
+	stats *docker.BuildCacheStats,
+	progress *async.Progress[ServiceProgress],
+) {
+	steps, cached := stats.Stats()
+	log.Printf("build cache of service %s: %d of %d steps cached", serviceConfig.Name, cached, steps)
+
+	if steps > 0 {
+		progress.SetProgress(NewServiceProgress(
+			fmt.Sprintf("Built Docker image, %d of %d steps cached (%d%%)", cached, steps, cached*100/steps)))
+	}
+}
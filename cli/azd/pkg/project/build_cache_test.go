@@ -0,0 +1,144 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DockerProject_Build_Cache(t *testing.T) {
+	tests := map[string]struct {
+		registry         string
+		cache            BuildCacheOptions
+		expectedArgs     []string
+		expectedProgress string
+	}{
+		"Default": {
+			registry: "registry.contoso.com",
+			expectedArgs: []string{
+				"buildx", "build", "--builder", "azd-multiplatform", "--load", "--progress", "plain",
+				"-f", "./Dockerfile", "--platform", docker.DefaultPlatform, "-t", "test-app-api",
+				"--cache-from", "type=registry,ref=registry.contoso.com/test-app/api-test-cache:buildcache",
+				"--cache-to", "type=registry,ref=registry.contoso.com/test-app/api-test-cache:buildcache," +
+					"mode=max,image-manifest=true,oci-mediatypes=true",
+				".",
+			},
+			expectedProgress: "Built Docker image, 1 of 2 steps cached (50%)",
+		},
+		"Configured": {
+			registry: "registry.contoso.com",
+			cache: BuildCacheOptions{
+				Repository: osutil.NewExpandableString("todo/cache"),
+				Tag:        osutil.NewExpandableString("${BRANCH}"),
+				ReadOnly:   true,
+			},
+			expectedArgs: []string{
+				"buildx", "build", "--builder", "azd-multiplatform", "--load", "--progress", "plain",
+				"-f", "./Dockerfile", "--platform", docker.DefaultPlatform, "-t", "test-app-api",
+				"--cache-from", "type=registry,ref=registry.contoso.com/todo/cache:main",
+				".",
+			},
+			expectedProgress: "Built Docker image, 1 of 2 steps cached (50%)",
+		},
+		"NoRegistry": {
+			// The registry is not available before provisioning, so the image is built without the cache
+			expectedArgs: []string{
+				"build", "-f", "./Dockerfile", "--platform", docker.DefaultPlatform, "-t", "test-app-api", ".",
+			},
+			// Without the cache, the last step of the build is reported
+			expectedProgress: "Building Docker image, step 2/2: COPY . .",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			env := environment.NewWithValues("test", map[string]string{
+				"BRANCH": "main",
+			})
+			if test.registry != "" {
+				env.DotenvSet(environment.ContainerRegistryEndpointEnvVarName, test.registry)
+			}
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker buildx inspect")
+			}).Respond(exec.NewRunResult(0, "", ""))
+
+			var buildArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.HasPrefix(command, "docker build ") || strings.HasPrefix(command, "docker buildx build")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				buildArgs = args
+				_, err := fmt.Fprint(args.StdOut, "#5 [1/2] FROM docker.io/library/node:20\n#5 CACHED\n"+
+					"#6 [2/2] COPY . .\n#6 DONE 0.1s\n")
+				require.NoError(t, err)
+
+				// "--iidfile" and path args are expected always at the end
+				err = os.WriteFile(args.Args[len(args.Args)-1], []byte("IMAGE_ID"), 0600)
+				require.NoError(t, err)
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			dockerCli := docker.NewCli(mockContext.CommandRunner)
+			dockerProject := NewDockerProject(
+				env,
+				dockerCli,
+				NewContainerHelper(
+					env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, nil, dockerCli, mockContext.Console,
+					cloud.AzurePublic(), nil, nil, nil, nil, nil,
+				),
+				mockinput.NewMockConsole(),
+				mockContext.AlphaFeaturesManager,
+				mockContext.CommandRunner)
+
+			temp := t.TempDir()
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Project.Path = temp
+			serviceConfig.Docker.Cache = &test.cache
+			require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+			err := os.WriteFile(filepath.Join(serviceConfig.Path(), "Dockerfile"), []byte("FROM node:20"), 0600)
+			require.NoError(t, err)
+
+			status := ""
+			result, err := async.RunWithProgress(
+				func(value ServiceProgress) {
+					status = value.Message
+				}, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+					return dockerProject.Build(*mockContext.Context, serviceConfig, nil, progress)
+				},
+			)
+
+			require.NoError(t, err)
+			require.Equal(t, "IMAGE_ID", result.BuildOutputPath)
+			require.Equal(t, test.expectedArgs, buildArgs.Args[:len(buildArgs.Args)-2])
+			require.Equal(t, test.expectedProgress, status)
+		})
+	}
+
+	t.Run("UnsupportedMode", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		dockerProject := newTestDockerProject(mockContext)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Cache = &BuildCacheOptions{Mode: "all"}
+
+		_, err := dockerProject.Build(
+			*mockContext.Context, serviceConfig, nil, async.NewProgress[ServiceProgress]())
+		require.ErrorContains(t, err, "unsupported build cache mode 'all'")
+	})
+}
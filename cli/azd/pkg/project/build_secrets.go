@@ -0,0 +1,139 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// DockerBuildSecret is a BuildKit secret mounted into the build, ex) RUN --mount=type=secret,id=npm_token
+// The value is passed to docker in the environment of the build, so it is neither recorded in the layers nor in the
+// history of the image like the value of a build arg is.
+type DockerBuildSecret struct {
+	// The id the secret is mounted with, ex) npm_token
+	Id string `yaml:"id"                 json:"id"`
+	// The value of the secret, ex) ${NPM_TOKEN} from the azd environment
+	Value osutil.ExpandableString `yaml:"value,omitempty"    json:"value,omitempty"`
+	// The Key Vault secret the value is read from, instead of the value
+	KeyVault *DockerBuildSecretKeyVault `yaml:"keyVault,omitempty" json:"keyVault,omitempty"`
+}
+
+// DockerBuildSecretKeyVault references the Key Vault secret the value of a build secret is read from
+type DockerBuildSecretKeyVault struct {
+	// The name or URL of the vault, ex) ${AZURE_KEY_VAULT_NAME}
+	VaultName osutil.ExpandableString `yaml:"vaultName"  json:"vaultName"`
+	// The name of the secret in the vault, ex) nuget-token
+	SecretName osutil.ExpandableString `yaml:"secretName" json:"secretName"`
+}
+
+// validateBuildSecrets returns an error when the build secrets are configured with unsupported options
+func validateBuildSecrets(serviceConfig *ServiceConfig) error {
+	secrets := serviceConfig.Docker.Secrets
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	if serviceConfig.Docker.RemoteBuild {
+		return errors.New("remote build does not support build secrets, build the image locally")
+	}
+
+	if serviceConfig.Docker.SourceBuilder != "" || serviceConfig.Docker.Buildpack {
+		return errors.New("build secrets are only supported when the image is built from a Dockerfile")
+	}
+
+	ids := []string{}
+	for _, secret := range secrets {
+		if secret.Id == "" || strings.ContainsAny(secret.Id, "=, ") {
+			return fmt.Errorf("invalid build secret id '%s', expected an id like npm_token", secret.Id)
+		}
+
+		if slices.Contains(ids, secret.Id) {
+			return fmt.Errorf("the build secret '%s' is declared more than once", secret.Id)
+		}
+		ids = append(ids, secret.Id)
+
+		if secret.Value.Empty() == (secret.KeyVault == nil) {
+			return fmt.Errorf("the build secret '%s' requires either a value or a keyVault secret", secret.Id)
+		}
+
+		if secret.KeyVault != nil && (secret.KeyVault.VaultName.Empty() || secret.KeyVault.SecretName.Empty()) {
+			return fmt.Errorf("the keyVault of build secret '%s' requires a vaultName and secretName", secret.Id)
+		}
+	}
+
+	return nil
+}
+
+// buildSecretsAndEnv returns the docker build secrets of the service, ex) id=npm_token, and the environment variables
+// docker reads their values from, ex) npm_token=<value>. Build args without a value, ex) NODE_ENV, take their value
+// from the azd environment the same way.
+func (ch *ContainerHelper) buildSecretsAndEnv(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildArgs []string,
+) ([]string, []string, error) {
+	buildSecrets := []string{}
+	buildEnv := []string{}
+
+	for _, buildArg := range buildArgs {
+		if strings.Contains(buildArg, "=") {
+			continue
+		}
+
+		if value, has := ch.env.LookupEnv(buildArg); has {
+			buildEnv = append(buildEnv, fmt.Sprintf("%s=%s", buildArg, value))
+		}
+	}
+
+	for _, secret := range serviceConfig.Docker.Secrets {
+		value, err := ch.buildSecretValue(ctx, secret)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		buildSecrets = append(buildSecrets, fmt.Sprintf("id=%s", secret.Id))
+		buildEnv = append(buildEnv, fmt.Sprintf("%s=%s", secret.Id, value))
+	}
+
+	return buildSecrets, buildEnv, nil
+}
+
+// buildSecretValue returns the value of the build secret from the azd environment or Key Vault
+func (ch *ContainerHelper) buildSecretValue(ctx context.Context, secret DockerBuildSecret) (string, error) {
+	if secret.KeyVault == nil {
+		value, err := secret.Value.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed to envsubst build secret '%s': %w", secret.Id, err)
+		}
+
+		if value == "" {
+			return "", fmt.Errorf("the value of build secret '%s' is empty, set it in the azd environment", secret.Id)
+		}
+
+		return value, nil
+	}
+
+	vaultName, err := secret.KeyVault.VaultName.Envsubst(ch.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed to envsubst key vault name: %w", err)
+	}
+
+	secretName, err := secret.KeyVault.SecretName.Envsubst(ch.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed to envsubst key vault secret name: %w", err)
+	}
+
+	log.Printf("reading build secret '%s' from key vault '%s'", secret.Id, vaultName)
+
+	kvSecret, err := ch.keyvaultService.GetKeyVaultSecret(ctx, ch.env.GetSubscriptionId(), vaultName, secretName)
+	if err != nil {
+		return "", fmt.Errorf("failed reading secret '%s' of build secret '%s', %w", secretName, secret.Id, err)
+	}
+
+	return kvSecret.Value, nil
+}
@@ -0,0 +1,210 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockKeyVaultService returns the secrets of a single vault by name
+type mockKeyVaultService struct {
+	keyvault.KeyVaultService
+	vaultName string
+	secrets   map[string]string
+}
+
+func (m *mockKeyVaultService) GetKeyVaultSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+) (*keyvault.Secret, error) {
+	value, has := m.secrets[secretName]
+	if vaultName != m.vaultName || !has {
+		return nil, errors.New("secret not found")
+	}
+
+	return &keyvault.Secret{Name: secretName, Value: value}, nil
+}
+
+func Test_ContainerHelper_BuildSecretsAndEnv(t *testing.T) {
+	env := environment.NewWithValues("dev", map[string]string{
+		"NPM_TOKEN":             "NPM_TOKEN_VALUE",
+		"NODE_ENV":              "production",
+		"AZURE_KEY_VAULT_NAME":  "kv-contoso",
+		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+	})
+	keyvaultService := &mockKeyVaultService{
+		vaultName: "kv-contoso",
+		secrets:   map[string]string{"nuget-token": "NUGET_TOKEN_VALUE"},
+	}
+	containerHelper := NewContainerHelper(
+		env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, keyvaultService,
+	)
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Secrets = []DockerBuildSecret{
+		{Id: "npm_token", Value: osutil.NewExpandableString("${NPM_TOKEN}")},
+		{Id: "nuget_token", KeyVault: &DockerBuildSecretKeyVault{
+			VaultName:  osutil.NewExpandableString("${AZURE_KEY_VAULT_NAME}"),
+			SecretName: osutil.NewExpandableString("nuget-token"),
+		}},
+	}
+
+	t.Run("Resolved", func(t *testing.T) {
+		buildSecrets, buildEnv, err := containerHelper.buildSecretsAndEnv(
+			context.Background(), serviceConfig, []string{"NODE_ENV", "VERSION=1.0", "UNDEFINED_ARG"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"id=npm_token", "id=nuget_token"}, buildSecrets)
+		require.Equal(t, []string{
+			"NODE_ENV=production",
+			"npm_token=NPM_TOKEN_VALUE",
+			"nuget_token=NUGET_TOKEN_VALUE",
+		}, buildEnv)
+	})
+
+	t.Run("EmptyValue", func(t *testing.T) {
+		serviceConfig.Docker.Secrets = []DockerBuildSecret{
+			{Id: "npm_token", Value: osutil.NewExpandableString("${MISSING_TOKEN}")},
+		}
+
+		_, _, err := containerHelper.buildSecretsAndEnv(context.Background(), serviceConfig, nil)
+		require.ErrorContains(t, err, "the value of build secret 'npm_token' is empty")
+	})
+
+	t.Run("KeyVaultSecretNotFound", func(t *testing.T) {
+		serviceConfig.Docker.Secrets = []DockerBuildSecret{
+			{Id: "nuget_token", KeyVault: &DockerBuildSecretKeyVault{
+				VaultName:  osutil.NewExpandableString("kv-contoso"),
+				SecretName: osutil.NewExpandableString("missing"),
+			}},
+		}
+
+		_, _, err := containerHelper.buildSecretsAndEnv(context.Background(), serviceConfig, nil)
+		require.ErrorContains(t, err, "failed reading secret 'missing' of build secret 'nuget_token'")
+	})
+}
+
+func Test_ValidateBuildSecrets(t *testing.T) {
+	value := osutil.NewExpandableString("${NPM_TOKEN}")
+	tests := map[string]struct {
+		secrets       []DockerBuildSecret
+		remoteBuild   bool
+		expectedError string
+	}{
+		"Valid": {
+			secrets: []DockerBuildSecret{{Id: "npm_token", Value: value}},
+		},
+		"InvalidId": {
+			secrets:       []DockerBuildSecret{{Id: "npm_token,src=/etc/passwd", Value: value}},
+			expectedError: "invalid build secret id 'npm_token,src=/etc/passwd'",
+		},
+		"DuplicateId": {
+			secrets:       []DockerBuildSecret{{Id: "npm_token", Value: value}, {Id: "npm_token", Value: value}},
+			expectedError: "the build secret 'npm_token' is declared more than once",
+		},
+		"NoValue": {
+			secrets:       []DockerBuildSecret{{Id: "npm_token"}},
+			expectedError: "requires either a value or a keyVault secret",
+		},
+		"IncompleteKeyVault": {
+			secrets: []DockerBuildSecret{{Id: "npm_token", KeyVault: &DockerBuildSecretKeyVault{
+				VaultName: osutil.NewExpandableString("kv-contoso"),
+			}}},
+			expectedError: "requires a vaultName and secretName",
+		},
+		"RemoteBuild": {
+			secrets:       []DockerBuildSecret{{Id: "npm_token", Value: value}},
+			remoteBuild:   true,
+			expectedError: "remote build does not support build secrets",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Secrets = test.secrets
+			serviceConfig.Docker.RemoteBuild = test.remoteBuild
+
+			err := validateBuildSecrets(serviceConfig)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func Test_DockerProject_Build_Secrets(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("test", map[string]string{
+		"NPM_TOKEN": "NPM_TOKEN_VALUE",
+		"NODE_ENV":  "production",
+	})
+
+	var buildArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "docker build ")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		buildArgs = args
+
+		// "--iidfile" and path args are expected always at the end
+		err := os.WriteFile(args.Args[len(args.Args)-1], []byte("IMAGE_ID"), 0600)
+		require.NoError(t, err)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	dockerCli := docker.NewCli(mockContext.CommandRunner)
+	dockerProject := NewDockerProject(
+		env,
+		dockerCli,
+		NewContainerHelper(
+			env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, nil, dockerCli, mockContext.Console,
+			cloud.AzurePublic(), nil, nil, nil, nil, nil,
+		),
+		mockinput.NewMockConsole(),
+		mockContext.AlphaFeaturesManager,
+		mockContext.CommandRunner)
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = t.TempDir()
+	serviceConfig.Docker.BuildArgs = []string{"NODE_ENV"}
+	serviceConfig.Docker.Secrets = []DockerBuildSecret{
+		{Id: "npm_token", Value: osutil.NewExpandableString("${NPM_TOKEN}")},
+	}
+	require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+	err := os.WriteFile(filepath.Join(serviceConfig.Path(), "Dockerfile"), []byte("FROM node:20"), 0600)
+	require.NoError(t, err)
+
+	result, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+		return dockerProject.Build(*mockContext.Context, serviceConfig, nil, progress)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "IMAGE_ID", result.BuildOutputPath)
+
+	// The values are passed in the environment of the build instead of its arguments
+	require.Equal(t, []string{
+		"build", "-f", "./Dockerfile", "--platform", docker.DefaultPlatform, "-t", "test-app-api",
+		"--build-arg", "NODE_ENV", "--secret", "id=npm_token", ".",
+	}, buildArgs.Args[:len(buildArgs.Args)-2])
+	require.Equal(t, []string{"NODE_ENV=production", "npm_token=NPM_TOKEN_VALUE"}, buildArgs.Env)
+	require.NotContains(t, strings.Join(buildArgs.Args, " "), "NPM_TOKEN_VALUE")
+}
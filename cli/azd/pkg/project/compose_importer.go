@@ -0,0 +1,300 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"gopkg.in/yaml.v3"
+)
+
+// The file names of docker-compose files, ex) the project of a service set to ./docker-compose.yaml
+var composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// ComposeServiceOptions are the options of a service imported from a docker-compose file. These are set by the
+// importer and can not be controlled via the project file.
+type ComposeServiceOptions struct {
+	// The ports the container listens on, ex) 80 of '8080:80'. The first port is the target port of the ingress
+	Ports []int
+	// The environment variables of the container, ex) ${DATABASE_URL} from the azd environment
+	Environment map[string]osutil.ExpandableString
+	// The services of the compose file the service depends on, which are deployed before it
+	DependsOn []string
+}
+
+// ComposeImporter is an importer that is able to import the services of a docker-compose file, so that an existing
+// compose based app is deployed to Container Apps or AKS without declaring each of its services in azure.yaml.
+type ComposeImporter struct {
+}
+
+func NewComposeImporter() *ComposeImporter {
+	return &ComposeImporter{}
+}
+
+// CanImport returns true when the project of the service is a docker-compose file, ex) ./docker-compose.yaml
+func (ci *ComposeImporter) CanImport(svcConfig *ServiceConfig) bool {
+	return svcConfig.Language == ServiceLanguageDocker &&
+		slices.Contains(composeFileNames, strings.ToLower(filepath.Base(svcConfig.RelativePath)))
+}
+
+// Services returns the services of the docker-compose file of the service, by name. The imported services target the
+// host of the service and inherit its docker and k8s options.
+func (ci *ComposeImporter) Services(
+	_ context.Context,
+	p *ProjectConfig,
+	svcConfig *ServiceConfig,
+) (map[string]*ServiceConfig, error) {
+	if svcConfig.Host != ContainerAppTarget && svcConfig.Host != AksTarget {
+		return nil, fmt.Errorf(
+			"services of the compose file '%s' must target '%s' or '%s'",
+			svcConfig.RelativePath, ContainerAppTarget, AksTarget,
+		)
+	}
+
+	composePath := svcConfig.Path()
+	contents, err := os.ReadFile(composePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading compose file: %w", err)
+	}
+
+	var compose composeFile
+	if err := yaml.Unmarshal(contents, &compose); err != nil {
+		return nil, fmt.Errorf("parsing compose file '%s': %w", composePath, err)
+	}
+
+	if len(compose.Services) == 0 {
+		return nil, fmt.Errorf("the compose file '%s' does not contain any services", composePath)
+	}
+
+	services := map[string]*ServiceConfig{}
+	for name, composeService := range compose.Services {
+		for _, dependency := range composeService.DependsOn {
+			if _, has := compose.Services[dependency]; !has {
+				return nil, fmt.Errorf("the compose service '%s' depends on unknown service '%s'", name, dependency)
+			}
+		}
+
+		svc, err := composeServiceConfig(p, svcConfig, name, composeService)
+		if err != nil {
+			return nil, fmt.Errorf("importing compose service '%s': %w", name, err)
+		}
+
+		services[name] = svc
+	}
+
+	return services, nil
+}
+
+// composeServiceConfig maps the compose service to an azd service, which is built from the build context of the
+// compose service or deployed from its image
+func composeServiceConfig(
+	p *ProjectConfig,
+	svcConfig *ServiceConfig,
+	name string,
+	composeService composeService,
+) (*ServiceConfig, error) {
+	svc := &ServiceConfig{
+		Name:    name,
+		Project: p,
+		Host:    svcConfig.Host,
+		Docker:  svcConfig.Docker,
+		K8s:     svcConfig.K8s,
+		Compose: &ComposeServiceOptions{
+			Environment: map[string]osutil.ExpandableString{},
+			DependsOn:   composeService.DependsOn,
+		},
+	}
+	svc.EventDispatcher = ext.NewEventDispatcher[ServiceLifecycleEventArgs]()
+
+	// A single image name can not be shared by the services, their default image names are derived from their names
+	svc.Docker.Image = osutil.NewExpandableString("")
+
+	switch {
+	case composeService.Build != nil:
+		buildContext := composeService.Build.Context
+		if buildContext == "" {
+			buildContext = "."
+		}
+
+		if !filepath.IsAbs(buildContext) {
+			buildContext = filepath.Join(filepath.Dir(svcConfig.Path()), buildContext)
+		}
+
+		relPath, err := filepath.Rel(p.Path, buildContext)
+		if err != nil {
+			return nil, err
+		}
+
+		// The dockerfile of the compose service is relative to its build context, which is the path of the service
+		svc.RelativePath = relPath
+		svc.Language = ServiceLanguageDocker
+		svc.Docker.Path = composeService.Build.Dockerfile
+		svc.Docker.Context = ""
+		svc.Docker.Target = composeService.Build.Target
+		svc.Docker.BuildArgs = nil
+		for _, key := range slices.Sorted(maps.Keys(composeService.Build.Args)) {
+			svc.Docker.BuildArgs = append(
+				svc.Docker.BuildArgs, fmt.Sprintf("%s=%s", key, composeService.Build.Args[key]))
+		}
+	case composeService.Image != "":
+		svc.Image = osutil.NewExpandableString(composeService.Image)
+	default:
+		return nil, fmt.Errorf("either 'build' or 'image' is required")
+	}
+
+	for _, port := range composeService.Ports {
+		svc.Compose.Ports = append(svc.Compose.Ports, port.Target)
+	}
+
+	for key, value := range composeService.Environment {
+		svc.Compose.Environment[key] = osutil.NewExpandableString(value)
+	}
+
+	return svc, nil
+}
+
+// resolveEnvironment returns the environment variables of the compose service with the references to the azd
+// environment substituted, ex) ${DATABASE_URL}
+func (o *ComposeServiceOptions) resolveEnvironment(env *environment.Environment) (map[string]string, error) {
+	resolved := map[string]string{}
+	for key, value := range o.Environment {
+		resolvedValue, err := value.Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst compose environment variable '%s': %w", key, err)
+		}
+
+		resolved[key] = resolvedValue
+	}
+
+	return resolved, nil
+}
+
+// composeFile is the subset of the compose specification the services are imported from
+// See: https://github.com/compose-spec/compose-spec/blob/main/spec.md
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Build       *composeBuild    `yaml:"build"`
+	Image       string           `yaml:"image"`
+	Ports       []composePort    `yaml:"ports"`
+	Environment composeMapping   `yaml:"environment"`
+	DependsOn   composeDependsOn `yaml:"depends_on"`
+}
+
+// composeBuild is the build of a compose service, either its build context, ex) ./api, or its build options
+type composeBuild struct {
+	Context    string         `yaml:"context"`
+	Dockerfile string         `yaml:"dockerfile"`
+	Target     string         `yaml:"target"`
+	Args       composeMapping `yaml:"args"`
+}
+
+func (b *composeBuild) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		b.Context = node.Value
+		return nil
+	}
+
+	type plain composeBuild
+	return node.Decode((*plain)(b))
+}
+
+// composeMapping is a map of environment variables or build args, either as a map or as a list of KEY=VALUE entries.
+// Entries without a value, ex) DATABASE_URL, take their value from the azd environment.
+type composeMapping map[string]string
+
+func (m *composeMapping) UnmarshalYAML(node *yaml.Node) error {
+	*m = composeMapping{}
+
+	if node.Kind == yaml.MappingNode {
+		values := map[string]*string{}
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+
+		for key, value := range values {
+			if value == nil {
+				(*m)[key] = fmt.Sprintf("${%s}", key)
+			} else {
+				(*m)[key] = *value
+			}
+		}
+
+		return nil
+	}
+
+	var entries []string
+	if err := node.Decode(&entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		key, value, has := strings.Cut(entry, "=")
+		if !has {
+			value = fmt.Sprintf("${%s}", key)
+		}
+
+		(*m)[key] = value
+	}
+
+	return nil
+}
+
+// composeDependsOn are the services a compose service depends on, either as a list or as a map of their conditions
+type composeDependsOn []string
+
+func (d *composeDependsOn) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		conditions := map[string]any{}
+		if err := node.Decode(&conditions); err != nil {
+			return err
+		}
+
+		*d = slices.Sorted(maps.Keys(conditions))
+		return nil
+	}
+
+	var services []string
+	if err := node.Decode(&services); err != nil {
+		return err
+	}
+
+	*d = services
+	return nil
+}
+
+// composePort is a port of a compose service, either in the short syntax, ex) 8080:80/tcp, or the long syntax
+type composePort struct {
+	Target int `yaml:"target"`
+}
+
+func (p *composePort) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		type plain composePort
+		return node.Decode((*plain)(p))
+	}
+
+	// The container port is the last part of [HOST:]CONTAINER[/PROTOCOL], ex) 127.0.0.1:8080:80
+	value, _, _ := strings.Cut(node.Value, "/")
+	if index := strings.LastIndex(value, ":"); index >= 0 {
+		value = value[index+1:]
+	}
+
+	port, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("unsupported port '%s', expected a single container port, ex) 8080:80", node.Value)
+	}
+
+	p.Target = port
+	return nil
+}
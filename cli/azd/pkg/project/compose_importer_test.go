@@ -0,0 +1,174 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testComposeFile = `
+services:
+  web:
+    build: ./web
+    ports:
+      - "8080:80"
+    environment:
+      - API_URL=http://api:3000
+    depends_on:
+      - api
+  api:
+    build:
+      context: ./api
+      dockerfile: Dockerfile.prod
+      target: runtime
+      args:
+        NODE_VERSION: "20"
+    ports:
+      - target: 3000
+        published: 3000
+    environment:
+      DATABASE_URL:
+      LOG_LEVEL: info
+    depends_on:
+      cache:
+        condition: service_started
+  cache:
+    image: redis:7
+    ports:
+      - "127.0.0.1:6379:6379/tcp"
+`
+
+func createComposeProject(t *testing.T, compose string, host ServiceTargetKind) *ProjectConfig {
+	projectPath := t.TempDir()
+	err := os.WriteFile(filepath.Join(projectPath, "docker-compose.yaml"), []byte(compose), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	projectConfig := &ProjectConfig{
+		Name:            "todo",
+		Path:            projectPath,
+		EventDispatcher: ext.NewEventDispatcher[ProjectLifecycleEventArgs](),
+	}
+	projectConfig.Services = map[string]*ServiceConfig{
+		"app": {
+			Name:            "app",
+			Project:         projectConfig,
+			RelativePath:    "./docker-compose.yaml",
+			Host:            host,
+			Language:        ServiceLanguageDocker,
+			Docker:          DockerProjectOptions{Registry: osutil.NewExpandableString("contoso.azurecr.io")},
+			EventDispatcher: ext.NewEventDispatcher[ServiceLifecycleEventArgs](),
+		},
+	}
+
+	return projectConfig
+}
+
+func Test_ComposeImporter_Services(t *testing.T) {
+	projectConfig := createComposeProject(t, testComposeFile, ContainerAppTarget)
+	importer := NewComposeImporter()
+	require.True(t, importer.CanImport(projectConfig.Services["app"]))
+
+	services, err := importer.Services(context.Background(), projectConfig, projectConfig.Services["app"])
+	require.NoError(t, err)
+	require.Len(t, services, 3)
+
+	web := services["web"]
+	require.Equal(t, "web", web.Name)
+	require.Equal(t, "web", web.RelativePath)
+	require.Equal(t, ContainerAppTarget, web.Host)
+	require.Equal(t, ServiceLanguageDocker, web.Language)
+	require.Equal(t, "contoso.azurecr.io", web.Docker.Registry.MustEnvsubst(nil))
+	require.Equal(t, []int{80}, web.Compose.Ports)
+	require.Equal(t, "http://api:3000", web.Compose.Environment["API_URL"].MustEnvsubst(nil))
+	require.Equal(t, []string{"api"}, web.Compose.DependsOn)
+
+	api := services["api"]
+	require.Equal(t, "api", api.RelativePath)
+	require.Equal(t, "Dockerfile.prod", api.Docker.Path)
+	require.Equal(t, "runtime", api.Docker.Target)
+	require.Equal(t, []string{"NODE_VERSION=20"}, api.Docker.BuildArgs)
+	require.Equal(t, []int{3000}, api.Compose.Ports)
+	// Variables without a value take their value from the azd environment
+	require.Equal(t, "postgres://db", api.Compose.Environment["DATABASE_URL"].MustEnvsubst(func(name string) string {
+		return map[string]string{"DATABASE_URL": "postgres://db"}[name]
+	}))
+	require.Equal(t, []string{"cache"}, api.Compose.DependsOn)
+
+	cache := services["cache"]
+	require.Equal(t, "", cache.RelativePath)
+	require.Equal(t, ServiceLanguageNone, cache.Language)
+	require.Equal(t, "redis:7", cache.Image.MustEnvsubst(nil))
+	require.Equal(t, []int{6379}, cache.Compose.Ports)
+}
+
+func Test_ComposeImporter_Services_Errors(t *testing.T) {
+	tests := map[string]struct {
+		compose       string
+		host          ServiceTargetKind
+		expectedError string
+	}{
+		"UnsupportedHost": {
+			compose:       testComposeFile,
+			host:          AppServiceTarget,
+			expectedError: "must target 'containerapp' or 'aks'",
+		},
+		"NoServices": {
+			compose:       "name: todo\n",
+			host:          AksTarget,
+			expectedError: "does not contain any services",
+		},
+		"NoBuildOrImage": {
+			compose:       "services:\n  api:\n    ports: ['80']\n",
+			host:          AksTarget,
+			expectedError: "importing compose service 'api': either 'build' or 'image' is required",
+		},
+		"UnknownDependency": {
+			compose:       "services:\n  api:\n    image: nginx\n    depends_on: [db]\n",
+			host:          AksTarget,
+			expectedError: "the compose service 'api' depends on unknown service 'db'",
+		},
+		"PortRange": {
+			compose:       "services:\n  api:\n    image: nginx\n    ports: ['3000-3005']\n",
+			host:          AksTarget,
+			expectedError: "unsupported port '3000-3005'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			projectConfig := createComposeProject(t, test.compose, test.host)
+			_, err := NewComposeImporter().Services(
+				context.Background(), projectConfig, projectConfig.Services["app"])
+			require.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
+func Test_ImportManager_ServiceStable_Compose(t *testing.T) {
+	projectConfig := createComposeProject(t, testComposeFile, AksTarget)
+	manager := NewImportManager(nil, NewComposeImporter())
+
+	services, err := manager.ServiceStable(context.Background(), projectConfig)
+	require.NoError(t, err)
+
+	// The services are deployed after the services they depend on
+	names := []string{}
+	for _, svc := range services {
+		names = append(names, svc.Name)
+	}
+	require.Equal(t, []string{"cache", "api", "web"}, names)
+
+	t.Run("CircularDependency", func(t *testing.T) {
+		compose := "services:\n  api:\n    image: nginx\n    depends_on: [web]\n" +
+			"  web:\n    image: nginx\n    depends_on: [api]\n"
+		projectConfig := createComposeProject(t, compose, AksTarget)
+
+		_, err := manager.ServiceStable(context.Background(), projectConfig)
+		require.ErrorContains(t, err, "circular dependency")
+	})
+}
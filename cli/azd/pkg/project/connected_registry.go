@@ -0,0 +1,130 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// ConnectedRegistryOptions configures the ACR connected registry the image is pushed to and pulled from, ex) by a
+// cluster that is disconnected or semi-connected to Azure. The connected registry is accessed with a client token of its
+// parent registry instead of the signed in Azure principal.
+type ConnectedRegistryOptions struct {
+	// The login server of the connected registry, ex) 192.168.0.10:8080. Defaults to AZURE_CONNECTED_REGISTRY_ENDPOINT
+	Endpoint osutil.ExpandableString `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	// The name of the client token of the connected registry in its parent registry. Defaults to
+	// AZURE_CONNECTED_REGISTRY_TOKEN
+	Token osutil.ExpandableString `yaml:"token,omitempty"    json:"token,omitempty"`
+	// The password of the client token. Defaults to AZURE_CONNECTED_REGISTRY_TOKEN_PASSWORD
+	Password osutil.ExpandableString `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+// connectedRegistry is the connected registry resolved from the options of the service and the environment
+type connectedRegistry struct {
+	endpoint string
+	token    string
+	password string
+}
+
+// connectedRegistry returns the connected registry the image of the service is pushed to, or nil when the image is
+// pushed to the container registry. The endpoint, token and password are read from the service configuration, and
+// otherwise from the environment, ex) to push to a connected registry from the environment of an edge site only.
+func (ch *ContainerHelper) connectedRegistry(serviceConfig *ServiceConfig) (*connectedRegistry, error) {
+	options := serviceConfig.Docker.ConnectedRegistry
+	if options == nil {
+		options = &ConnectedRegistryOptions{}
+	}
+
+	resolve := func(name string, value osutil.ExpandableString, envVarName string) (string, error) {
+		resolved, err := value.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed parsing 'connectedRegistry.%s' from docker configuration, %w", name, err)
+		}
+
+		if resolved == "" {
+			resolved = ch.env.Getenv(envVarName)
+		}
+
+		return resolved, nil
+	}
+
+	endpoint, err := resolve("endpoint", options.Endpoint, environment.ConnectedRegistryEndpointEnvVarName)
+	if err != nil || endpoint == "" {
+		return nil, err
+	}
+
+	token, err := resolve("token", options.Token, environment.ConnectedRegistryTokenEnvVarName)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf(
+			"the connected registry '%s' requires a client token, set 'connectedRegistry.token' in the docker options "+
+				"or the '%s' environment variable",
+			endpoint, environment.ConnectedRegistryTokenEnvVarName,
+		)
+	}
+
+	password, err := resolve("password", options.Password, environment.ConnectedRegistryTokenPasswordEnvVarName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connectedRegistry{endpoint: endpoint, token: token, password: password}, nil
+}
+
+// connectedRegistryCredentials returns the credentials of the client token of the connected registry. Without a
+// configured password, a password of the token is generated in the parent registry and stored in the environment, so
+// that later deployments, ex) from the edge site without access to Azure, reuse it.
+func (ch *ContainerHelper) connectedRegistryCredentials(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	subscriptionId string,
+	registry *connectedRegistry,
+) (*azcli.DockerCredentials, error) {
+	if registry.password != "" {
+		return &azcli.DockerCredentials{
+			Username:    registry.token,
+			Password:    registry.password,
+			LoginServer: registry.endpoint,
+		}, nil
+	}
+
+	// The parent registry is only required to generate the password
+	parentRegistry, err := ch.cloudRegistryName(ctx, serviceConfig)
+	if err != nil || parentRegistry == "" || !ch.isAzureContainerRegistry(parentRegistry) {
+		return nil, fmt.Errorf(
+			"the password of the token '%s' of the connected registry '%s' is generated in its parent registry, set "+
+				"'registry' in the docker options or the '%s' environment variable to the parent registry, or set the "+
+				"'%s' environment variable",
+			registry.token,
+			registry.endpoint,
+			environment.ContainerRegistryEndpointEnvVarName,
+			environment.ConnectedRegistryTokenPasswordEnvVarName,
+		)
+	}
+
+	log.Printf("generating password of token '%s' in registry '%s'", registry.token, parentRegistry)
+	credentials, err := ch.containerRegistryService.GenerateTokenCredentials(
+		ctx, subscriptionId, parentRegistry, registry.token)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating the password of the connected registry token, %w", err)
+	}
+
+	ch.env.DotenvSet(environment.ConnectedRegistryTokenPasswordEnvVarName, credentials.Password)
+	if err := ch.envManager.Save(ctx, ch.env); err != nil {
+		return nil, fmt.Errorf("failed saving the password of the connected registry token, %w", err)
+	}
+
+	registry.password = credentials.Password
+	return &azcli.DockerCredentials{
+		Username:    credentials.Username,
+		Password:    credentials.Password,
+		LoginServer: registry.endpoint,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHelper_ConnectedRegistry(t *testing.T) {
+	t.Run("FromEnvironment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockResults := setupDockerMocks(mockContext)
+
+		env := environment.NewWithValues("edge", map[string]string{
+			environment.ContainerRegistryEndpointEnvVarName:      "contoso.azurecr.io",
+			environment.ConnectedRegistryEndpointEnvVarName:      "192.168.0.10:8080",
+			environment.ConnectedRegistryTokenEnvVarName:         "azd-push",
+			environment.ConnectedRegistryTokenPasswordEnvVarName: "password",
+		})
+		containerHelper := NewContainerHelper(
+			env, &mockenv.MockEnvManager{}, clock.NewMock(), &mockContainerRegistryService{}, nil,
+			docker.NewCli(mockContext.CommandRunner), mockContext.Console, cloud.AzurePublic(), nil, nil, nil, nil, nil)
+
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+
+		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, "192.168.0.10:8080", registryName)
+
+		registryName, err = containerHelper.Login(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, "192.168.0.10:8080", registryName)
+		require.Equal(t,
+			[]string{"login", "--username", "azd-push", "--password-stdin", "192.168.0.10:8080"},
+			mockResults["docker-login"].Args)
+
+		// The image pull secret of the cluster is created with the client token
+		credentials, err := containerHelper.Credentials(
+			*mockContext.Context,
+			serviceConfig,
+			environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "CLUSTER", "Microsoft.ContainerService"),
+		)
+		require.NoError(t, err)
+		require.Equal(t, &azcli.DockerCredentials{
+			Username:    "azd-push",
+			Password:    "password",
+			LoginServer: "192.168.0.10:8080",
+		}, credentials)
+	})
+
+	t.Run("GeneratesPassword", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockResults := setupDockerMocks(mockContext)
+
+		env := environment.NewWithValues("edge", map[string]string{
+			environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		})
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+		mockContainerRegistryService := &mockContainerRegistryService{}
+		mockContainerRegistryService.
+			On("GenerateTokenCredentials", *mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", "azd-push").
+			Return(&azcli.DockerCredentials{
+				Username:    "azd-push",
+				Password:    "generated",
+				LoginServer: "contoso.azurecr.io",
+			}, nil)
+
+		containerHelper := NewContainerHelper(
+			env, envManager, clock.NewMock(), mockContainerRegistryService, nil,
+			docker.NewCli(mockContext.CommandRunner), mockContext.Console, cloud.AzurePublic(), nil, nil, nil, nil, nil)
+
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+		serviceConfig.Docker.ConnectedRegistry = &ConnectedRegistryOptions{
+			Endpoint: osutil.NewExpandableString("registry.contoso.local"),
+			Token:    osutil.NewExpandableString("azd-push"),
+		}
+
+		registryName, err := containerHelper.Login(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, "registry.contoso.local", registryName)
+		require.Equal(t,
+			[]string{"login", "--username", "azd-push", "--password-stdin", "registry.contoso.local"},
+			mockResults["docker-login"].Args)
+
+		// The generated password is stored in the environment for the later deployments
+		require.Equal(t, "generated", env.Getenv(environment.ConnectedRegistryTokenPasswordEnvVarName))
+		envManager.AssertCalled(t, "Save", *mockContext.Context, env)
+		mockContainerRegistryService.AssertNotCalled(t, "Login", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		env := environment.NewWithValues("edge", map[string]string{
+			environment.ConnectedRegistryEndpointEnvVarName: "192.168.0.10:8080",
+		})
+		containerHelper := NewContainerHelper(
+			env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil,
+			nil)
+
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+		_, err := containerHelper.RegistryName(context.Background(), serviceConfig)
+		require.ErrorContains(t, err, "requires a client token")
+	})
+
+	t.Run("MissingParentRegistry", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.NewWithValues("edge", map[string]string{
+			environment.ConnectedRegistryEndpointEnvVarName: "192.168.0.10:8080",
+			environment.ConnectedRegistryTokenEnvVarName:    "azd-push",
+		})
+		containerHelper := NewContainerHelper(
+			env, &mockenv.MockEnvManager{}, clock.NewMock(), &mockContainerRegistryService{}, nil,
+			docker.NewCli(mockContext.CommandRunner), mockContext.Console, cloud.AzurePublic(), nil, nil, nil, nil, nil)
+
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+		_, err := containerHelper.Login(*mockContext.Context, serviceConfig)
+		require.ErrorContains(t, err, "is generated in its parent registry")
+	})
+}
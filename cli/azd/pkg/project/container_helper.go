@@ -19,9 +19,14 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cosign"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/notation"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
 	"github.com/benbjohnson/clock"
 	"github.com/sethvargo/go-retry"
 )
@@ -35,6 +40,11 @@ type ContainerHelper struct {
 	clock                    clock.Clock
 	console                  input.Console
 	cloud                    *cloud.Cloud
+	notation                 *notation.Cli
+	cosign                   *cosign.Cli
+	oras                     *oras.Cli
+	git                      *git.Cli
+	keyvaultService          keyvault.KeyVaultService
 }
 
 func NewContainerHelper(
@@ -46,6 +56,11 @@ func NewContainerHelper(
 	docker *docker.Cli,
 	console input.Console,
 	cloud *cloud.Cloud,
+	notation *notation.Cli,
+	cosign *cosign.Cli,
+	oras *oras.Cli,
+	git *git.Cli,
+	keyvaultService keyvault.KeyVaultService,
 ) *ContainerHelper {
 	return &ContainerHelper{
 		env:                      env,
@@ -56,6 +71,11 @@ func NewContainerHelper(
 		clock:                    clock,
 		console:                  console,
 		cloud:                    cloud,
+		notation:                 notation,
+		cosign:                   cosign,
+		oras:                     oras,
+		git:                      git,
+		keyvaultService:          keyvaultService,
 	}
 }
 
@@ -73,9 +93,25 @@ func (ch *ContainerHelper) DefaultImageTag() string {
 }
 
 // RegistryName returns the name of the destination container registry to use for the current environment from the following:
-// 1. AZURE_CONTAINER_REGISTRY_ENDPOINT environment variable
-// 2. docker.registry from the service configuration
+// 1. docker.connectedRegistry.endpoint or AZURE_CONNECTED_REGISTRY_ENDPOINT, when pushing to a connected registry
+// 2. AZURE_CONTAINER_REGISTRY_ENDPOINT environment variable
+// 3. docker.registry from the service configuration
 func (ch *ContainerHelper) RegistryName(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
+	connectedRegistry, err := ch.connectedRegistry(serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	if connectedRegistry != nil {
+		return connectedRegistry.endpoint, nil
+	}
+
+	return ch.cloudRegistryName(ctx, serviceConfig)
+}
+
+// cloudRegistryName returns the name of the container registry configured by AZURE_CONTAINER_REGISTRY_ENDPOINT or
+// docker.registry, ex) the parent registry of a connected registry
+func (ch *ContainerHelper) cloudRegistryName(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
 	registryName, found := ch.env.LookupEnv(environment.ContainerRegistryEndpointEnvVarName)
 	if !found {
 		log.Printf(
@@ -108,8 +144,8 @@ func (ch *ContainerHelper) RegistryName(ctx context.Context, serviceConfig *Serv
 	return registryName, nil
 }
 
-// GeneratedImage returns the configured image from the service configuration
-// or a default image name generated from the service name and environment name.
+// GeneratedImage returns the configured image from the service configuration, the image rendered from the image
+// template of the service or project, or a default image name generated from the service name and environment name.
 func (ch *ContainerHelper) GeneratedImage(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -120,9 +156,21 @@ func (ch *ContainerHelper) GeneratedImage(
 		return nil, fmt.Errorf("failed parsing 'image' from docker configuration, %w", err)
 	}
 
-	// Set default image name if not configured
+	// Render the image template when configured, otherwise set default image name
 	if configuredImage == "" {
-		configuredImage = ch.DefaultImageName(serviceConfig)
+		imageTemplate, err := ch.imageTemplate(serviceConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		if imageTemplate != "" {
+			configuredImage, err = ch.renderImageTemplate(ctx, serviceConfig, imageTemplate)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			configuredImage = ch.DefaultImageName(serviceConfig)
+		}
 	}
 
 	parsedImage, err := docker.ParseContainerImage(configuredImage)
@@ -190,32 +238,75 @@ func (ch *ContainerHelper) LocalImageTag(ctx context.Context, serviceConfig *Ser
 }
 
 func (ch *ContainerHelper) RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
-	if serviceConfig.Docker.RemoteBuild {
-		return []tools.ExternalTool{}
+	requiredTools := []tools.ExternalTool{}
+	if !serviceConfig.Docker.RemoteBuild {
+		requiredTools = append(requiredTools, ch.docker)
 	}
 
-	return []tools.ExternalTool{ch.docker}
+	if signingTool := ch.signingTool(serviceConfig); signingTool != nil {
+		requiredTools = append(requiredTools, signingTool)
+	}
+
+	if serviceConfig.Docker.Sbom != nil || serviceConfig.Docker.Provenance {
+		requiredTools = append(requiredTools, ch.oras)
+	}
+
+	return requiredTools
 }
 
 // Login logs into the container registry specified by AZURE_CONTAINER_REGISTRY_ENDPOINT in the environment. On success,
-// it returns the name of the container registry that was logged into.
+// it returns the name of the container registry that was logged into. The registry is logged into with the credentials
+// of docker.credentials, ex) from the environment, or relies on an existing login of the container runtime.
 func (ch *ContainerHelper) Login(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 ) (string, error) {
+	if err := validateRegistryCredentials(serviceConfig); err != nil {
+		return "", err
+	}
+
 	registryName, err := ch.RegistryName(ctx, serviceConfig)
 	if err != nil {
 		return "", err
 	}
 
-	// Only perform automatic login for ACR
-	// Other registries require manual login via external 'docker login' command
-	hostParts := strings.Split(registryName, ".")
-	if len(hostParts) == 1 || strings.HasSuffix(registryName, ch.cloud.ContainerRegistryEndpointSuffix) {
+	connectedRegistry, err := ch.connectedRegistry(serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	// The connected registry is logged into with its client token, ex) from a site on the edge without access to Azure
+	if connectedRegistry != nil {
+		credentials, err := ch.connectedRegistryCredentials(
+			ctx, serviceConfig, ch.env.GetSubscriptionId(), connectedRegistry)
+		if err != nil {
+			return "", err
+		}
+
+		return registryName, ch.docker.Login(ctx, registryName, credentials.Username, credentials.Password)
+	}
+
+	switch ch.registryCredentialSource(serviceConfig, registryName) {
+	case RegistryCredentialSourceAzure:
 		return registryName, ch.containerRegistryService.Login(ctx, ch.env.GetSubscriptionId(), registryName)
+	case RegistryCredentialSourceEnv:
+		credentials, err := ch.resolveRegistryCredentials(ctx, serviceConfig, ch.env.GetSubscriptionId(), registryName)
+		if err != nil {
+			return "", err
+		}
+
+		return registryName, ch.docker.Login(ctx, registryName, credentials.Username, credentials.Password)
+	default:
+		// Pushes use the existing login of the container runtime, ex) 'docker login ghcr.io'
+		log.Printf("using the existing login of the container runtime for registry '%s'", registryName)
+		return registryName, nil
 	}
+}
 
-	return registryName, nil
+// isAzureContainerRegistry returns whether the login server is an Azure Container Registry
+func (ch *ContainerHelper) isAzureContainerRegistry(loginServer string) bool {
+	hostParts := strings.Split(loginServer, ".")
+	return len(hostParts) == 1 || strings.HasSuffix(loginServer, ch.cloud.ContainerRegistryEndpointSuffix)
 }
 
 var defaultCredentialsRetryDelay = 20 * time.Second
@@ -230,6 +321,15 @@ func (ch *ContainerHelper) Credentials(
 		return nil, err
 	}
 
+	return ch.resolveRegistryCredentials(ctx, serviceConfig, targetResource.SubscriptionId(), loginServer)
+}
+
+// registryCredentials gets the credentials of the specified Azure Container Registry login server
+func (ch *ContainerHelper) registryCredentials(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+) (*azcli.DockerCredentials, error) {
 	var credential *azcli.DockerCredentials
 	credentialsError := retry.Do(
 		ctx,
@@ -237,7 +337,7 @@ func (ch *ContainerHelper) Credentials(
 		// https://learn.microsoft.com/en-us/azure/dns/dns-faq#how-long-does-it-take-for-dns-changes-to-take-effect-
 		retry.WithMaxRetries(3, retry.NewConstant(defaultCredentialsRetryDelay)),
 		func(ctx context.Context) error {
-			cred, err := ch.containerRegistryService.Credentials(ctx, targetResource.SubscriptionId(), loginServer)
+			cred, err := ch.containerRegistryService.Credentials(ctx, subscriptionId, loginServer)
 			if err != nil {
 				var httpErr *azcore.ResponseError
 				if errors.As(err, &httpErr) {
@@ -265,32 +365,185 @@ func (ch *ContainerHelper) Deploy(
 	writeImageToEnv bool,
 	progress *async.Progress[ServiceProgress],
 ) (*ServiceDeployResult, error) {
+	if err := validateSigning(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validatePinDigest(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateImageRetention(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateDockerProgress(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	var sourceHash string
+	if packageOutput != nil {
+		if packageDetails, ok := packageOutput.Details.(*dockerPackageResult); ok && packageDetails != nil {
+			sourceHash = packageDetails.SourceHash
+
+			// The image pushed by the last deployment, or promoted from another environment, is deployed again along
+			// with the image values in the environment
+			if packageDetails.ReusedImage != "" {
+				message := "Skipping push, the source is unchanged since the last deployment"
+				if packageDetails.PromotedFrom != "" {
+					message = fmt.Sprintf("Skipping push, promoting the image of '%s'", packageDetails.PromotedFrom)
+				}
+
+				log.Printf("reusing image %s: %s", packageDetails.ReusedImage, message)
+				progress.SetProgress(NewServiceProgress(message))
+
+				return &ServiceDeployResult{
+					Package: packageOutput,
+					Details: &dockerDeployResult{
+						RemoteImageTag: packageDetails.ReusedImage,
+					},
+				}, nil
+			}
+		}
+	}
+
 	var remoteImage string
+	var digest string
 	var err error
 
-	if serviceConfig.Docker.RemoteBuild {
+	switch {
+	case serviceConfig.Docker.RemoteBuild:
 		remoteImage, err = ch.runRemoteBuild(ctx, serviceConfig, targetResource, progress)
-	} else {
+	case serviceConfig.Docker.IsMultiPlatform():
+		remoteImage, digest, err = ch.runMultiPlatformBuild(ctx, serviceConfig, progress)
+	default:
 		remoteImage, err = ch.runLocalBuild(ctx, serviceConfig, packageOutput, progress)
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	var sbomPath string
+	if packageOutput != nil {
+		sbomPath = packageOutput.SbomPath
+	}
+
+	// The provenance is only attested for the images built by azd
+	provenance := serviceConfig.Docker.Provenance && builtImage(serviceConfig, packageOutput)
+	if serviceConfig.Docker.Provenance && !provenance {
+		log.Printf("skipping provenance of service '%s', the image is not built by azd", serviceConfig.Name)
+	}
+
+	if digest == "" &&
+		(serviceConfig.Docker.Signing != nil || sbomPath != "" || provenance || serviceConfig.Docker.PinDigest) {
+		digest, err = ch.imageDigest(ctx, remoteImage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var sbom string
+	if sbomPath != "" {
+		sbom, err = ch.attachSbom(ctx, serviceConfig, remoteImage, digest, sbomPath, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Printf("attached sbom %s to image %s@%s", sbom, remoteImage, digest)
+	}
+
+	var provenanceReference string
+	if provenance {
+		provenanceReference, err = ch.attachProvenance(ctx, serviceConfig, remoteImage, digest, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Printf("attached provenance %s to image %s@%s", provenanceReference, remoteImage, digest)
+	}
+
+	var signature string
+	if serviceConfig.Docker.Signing != nil {
+		signature, err = ch.signImage(ctx, serviceConfig, remoteImage, digest, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Printf("signed image %s@%s, signature: %s", remoteImage, digest, signature)
+	}
+
+	var pinnedImage string
+	if serviceConfig.Docker.PinDigest {
+		pinnedImage = pinImageDigest(remoteImage, digest)
+		log.Printf("pinned image %s to %s", remoteImage, pinnedImage)
+	}
+
 	if writeImageToEnv {
 		// Save the name of the image we pushed into the environment with a well known key.
+		// Manifests and container app revisions reference the image by its digest when pinned
 		log.Printf("writing image name to environment")
-		ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", remoteImage)
+		if pinnedImage != "" {
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", pinnedImage)
+		} else {
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", remoteImage)
+		}
+
+		// The repository and tag of the image, ex) rendered from the image template, for manifests and hooks
+		if image, err := docker.ParseContainerImage(remoteImage); err == nil {
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_REPOSITORY", image.Repository)
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_TAG", image.Tag)
+		}
+
+		if signature != "" || sbom != "" || provenanceReference != "" || pinnedImage != "" {
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_DIGEST", digest)
+		}
+
+		if signature != "" {
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_SIGNATURE", signature)
+		}
+
+		if sbom != "" {
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_SBOM", sbom)
+		}
+
+		if provenanceReference != "" {
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_PROVENANCE", provenanceReference)
+		}
+
+		// The next deployment reuses the image while the source is unchanged, images pushed without a source hash
+		// are never reused
+		if sourceHash != "" {
+			ch.env.SetServiceProperty(serviceConfig.Name, sourceHashServiceProperty, sourceHash)
+		} else {
+			ch.env.DeleteServiceProperty(serviceConfig.Name, sourceHashServiceProperty)
+		}
 
 		if err := ch.envManager.Save(ctx, ch.env); err != nil {
 			return nil, fmt.Errorf("saving image name to environment: %w", err)
 		}
 	}
 
+	// The images of earlier deployments are deleted past the retention of the service, a failed cleanup does not fail
+	// the deployment since the image has been pushed
+	if serviceConfig.Docker.Retention != nil {
+		progress.SetProgress(NewServiceProgress("Cleaning up old images"))
+		result, err := ch.CleanImages(ctx, serviceConfig, serviceConfig.Docker.Retention.Keep, false)
+		if err != nil {
+			log.Printf("failed cleaning up old images of service '%s': %v", serviceConfig.Name, err)
+		} else {
+			log.Printf("deleted %d old images from %s/%s", len(result.Deleted), result.Registry, result.Repository)
+		}
+	}
+
 	return &ServiceDeployResult{
 		Package: packageOutput,
 		Details: &dockerDeployResult{
 			RemoteImageTag: remoteImage,
+			Digest:         digest,
+			PinnedImage:    pinnedImage,
+			Signature:      signature,
+			Sbom:           sbom,
+			Provenance:     provenanceReference,
 		},
 	}, nil
 }
@@ -317,6 +570,14 @@ func (ch *ContainerHelper) runLocalBuild(
 		targetImage = packageDetails.TargetImage
 	}
 
+	// The package is an image archive, ex) created by 'docker save' and deployed with '--from-package'
+	if !ok && isImageArchive(targetImage) {
+		targetImage, err = ch.loadImageArchive(ctx, serviceConfig, targetImage, progress)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// Default to the local image tag
 	remoteImage := targetImage
 
@@ -367,11 +628,18 @@ func (ch *ContainerHelper) runLocalBuild(
 			// Push image.
 			log.Printf("pushing %s to registry", remoteImage)
 			progress.SetProgress(NewServiceProgress("Pushing container image"))
-			if err := ch.docker.Push(ctx, serviceConfig.Path(), remoteImage); err != nil {
+			previewerWriter := showDockerOutput(ctx, ch.console, serviceConfig, "Docker Output", true)
+			err = ch.docker.PushWithProgress(ctx, serviceConfig.Path(), remoteImage,
+				docker.NewPushProgress(previewerWriter, reportDockerProgress("Pushing container image", progress)))
+			stopDockerOutput(ctx, ch.console, previewerWriter)
+			if err != nil {
 				errSuggestion := &internal.ErrorWithSuggestion{
 					Err: err,
-					//nolint:lll
-					Suggestion: "When pushing to an external registry, ensure you have successfully authenticated by calling 'docker login' and run 'azd deploy' again",
+					Suggestion: fmt.Sprintf(
+						"When pushing to an external registry, ensure you have successfully authenticated by calling "+
+							"'%s login' and run 'azd deploy' again",
+						ch.docker.Runtime(),
+					),
 				}
 
 				return "", errSuggestion
@@ -382,14 +650,129 @@ func (ch *ContainerHelper) runLocalBuild(
 	return remoteImage, nil
 }
 
-// runLocalBuild builds the image using a remote azure container registry and tags it. It returns the full remote image name.
+// runMultiPlatformBuild builds the image for each of the platforms with buildx and pushes the manifest list
+// to the remote registry. It returns the full remote image name along with the digest of the manifest list.
+func (ch *ContainerHelper) runMultiPlatformBuild(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	progress *async.Progress[ServiceProgress],
+) (string, string, error) {
+	if err := validateDockerfile(serviceConfig); err != nil {
+		return "", "", err
+	}
+
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	removeDockerfile, err := writeInlineDockerfile(&dockerOptions)
+	if err != nil {
+		return "", "", err
+	}
+	defer removeDockerfile()
+
+	registryName, err := ch.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Multi-platform images are pushed as part of the build
+	if registryName == "" {
+		return "", "", errors.New("a container registry is required to build multi-platform images, " +
+			"set docker.registry or AZURE_CONTAINER_REGISTRY_ENDPOINT")
+	}
+
+	buildArgs, err := resolveDockerParameters(ch.env, dockerOptions.BuildArgs)
+	if err != nil {
+		return "", "", err
+	}
+
+	buildEnv, err := resolveDockerParameters(ch.env, dockerOptions.BuildEnv)
+	if err != nil {
+		return "", "", err
+	}
+
+	buildSecrets, secretsEnv, err := ch.buildSecretsAndEnv(ctx, serviceConfig, buildArgs)
+	if err != nil {
+		return "", "", err
+	}
+
+	localImageTag, err := ch.LocalImageTag(ctx, serviceConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	remoteImage, err := ch.RemoteImageTag(ctx, serviceConfig, localImageTag)
+	if err != nil {
+		return "", "", fmt.Errorf("getting remote image tag: %w", err)
+	}
+
+	log.Printf("logging into container registry '%s'\n", registryName)
+	progress.SetProgress(NewServiceProgress("Logging into container registry"))
+	if _, err := ch.Login(ctx, serviceConfig); err != nil {
+		return "", "", err
+	}
+
+	progress.SetProgress(NewServiceProgress("Preparing multi-platform builder"))
+	if err := ch.docker.EnsureBuilder(ctx, docker.MultiPlatformBuilder); err != nil {
+		return "", "", err
+	}
+
+	var cache *docker.BuildCache
+	if serviceConfig.Docker.Cache != nil {
+		cache, err = ch.buildCache(ctx, serviceConfig)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	platforms := strings.Join(dockerOptions.Platforms, ", ")
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Building and pushing container image (%s)", platforms)))
+	previewerWriter := showDockerOutput(ctx, ch.console, serviceConfig, "Docker Output", false)
+	cacheStats := docker.NewBuildCacheStats(previewerWriter)
+	buildProgress := docker.NewBuildProgress(
+		cacheStats, reportDockerProgress(fmt.Sprintf("Building and pushing container image (%s)", platforms), progress))
+	digest, err := ch.docker.BuildMultiPlatform(ctx, serviceConfig.Path(), docker.MultiPlatformBuildOptions{
+		Builder:        docker.MultiPlatformBuilder,
+		DockerFilePath: dockerOptions.Path,
+		Platforms:      dockerOptions.Platforms,
+		Target:         dockerOptions.Target,
+		BuildContext:   dockerOptions.Context,
+		Tags:           []string{remoteImage},
+		BuildArgs:      buildArgs,
+		BuildSecrets:   append(dockerOptions.BuildSecrets, buildSecrets...),
+		BuildEnv:       append(buildEnv, secretsEnv...),
+		Cache:          cache,
+	}, buildProgress)
+	stopDockerOutput(ctx, ch.console, previewerWriter)
+	if err != nil {
+		return "", "", err
+	}
+
+	if cache != nil {
+		buildCacheProgress(serviceConfig, cacheStats, progress)
+	}
+
+	log.Printf("pushed multi-platform image %s (%s) to registry, digest: %s", remoteImage, platforms, digest)
+
+	return remoteImage, digest, nil
+}
+
+// runRemoteBuild builds the image using a remote azure container registry and tags it.
+// It returns the full remote image name.
 func (ch *ContainerHelper) runRemoteBuild(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 	target *environment.TargetResource,
 	progress *async.Progress[ServiceProgress],
 ) (string, error) {
+	if err := validateDockerfile(serviceConfig); err != nil {
+		return "", err
+	}
+
 	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	removeDockerfile, err := writeInlineDockerfile(&dockerOptions)
+	if err != nil {
+		return "", err
+	}
+	defer removeDockerfile()
 
 	if !filepath.IsAbs(dockerOptions.Path) {
 		dockerOptions.Path = filepath.Join(serviceConfig.Path(), dockerOptions.Path)
@@ -399,8 +782,23 @@ func (ch *ContainerHelper) runRemoteBuild(
 		dockerOptions.Context = filepath.Join(serviceConfig.Path(), dockerOptions.Context)
 	}
 
-	if dockerOptions.Platform != "linux/amd64" {
-		return "", fmt.Errorf("remote build only supports the linux/amd64 platform")
+	buildArgs, err := resolveDockerParameters(ch.env, dockerOptions.BuildArgs)
+	if err != nil {
+		return "", err
+	}
+
+	// Build args without a value take their value from the azd environment, as they do for local builds
+	for i, buildArg := range buildArgs {
+		if value, has := ch.env.LookupEnv(buildArg); !strings.Contains(buildArg, "=") && has {
+			buildArgs[i] = fmt.Sprintf("%s=%s", buildArg, value)
+		}
+	}
+
+	dockerOptions.BuildArgs = buildArgs
+
+	// The platform and build args are validated before the build context is packed and uploaded
+	if _, err := remoteBuildRequest(dockerOptions, nil, "", ""); err != nil {
+		return "", err
 	}
 
 	progress.SetProgress(NewServiceProgress("Packing remote build context"))
@@ -446,15 +844,9 @@ func (ch *ContainerHelper) runRemoteBuild(
 
 	progress.SetProgress(NewServiceProgress("Running remote build"))
 
-	buildRequest := &armcontainerregistry.DockerBuildRequest{
-		SourceLocation: source.RelativePath,
-		DockerFilePath: to.Ptr(dockerPath),
-		IsPushEnabled:  to.Ptr(true),
-		ImageNames:     []*string{to.Ptr(imageName)},
-		Platform: &armcontainerregistry.PlatformProperties{
-			OS:           to.Ptr(armcontainerregistry.OSLinux),
-			Architecture: to.Ptr(armcontainerregistry.ArchitectureAmd64),
-		},
+	buildRequest, err := remoteBuildRequest(dockerOptions, source.RelativePath, dockerPath, imageName)
+	if err != nil {
+		return "", err
 	}
 
 	previewerWriter := ch.console.ShowPreviewer(ctx,
@@ -473,6 +865,88 @@ func (ch *ContainerHelper) runRemoteBuild(
 	return imageName, nil
 }
 
+// The architectures of the linux platforms supported by ACR Tasks, by docker platform
+var remoteBuildArchitectures = map[string]armcontainerregistry.Architecture{
+	"linux/amd64": armcontainerregistry.ArchitectureAmd64,
+	"linux/arm64": armcontainerregistry.ArchitectureArm64,
+}
+
+// remoteBuildRequest returns the ACR Tasks build request of the uploaded build context, equivalent to 'az acr build'
+// The build args and target of the docker options are passed to the build, ex) KEY=VALUE build args.
+// Build args without a value take their value from the environment variables like 'docker build' does.
+func remoteBuildRequest(
+	dockerOptions DockerProjectOptions,
+	sourceLocation *string,
+	dockerPath string,
+	imageName string,
+) (*armcontainerregistry.DockerBuildRequest, error) {
+	architecture, has := remoteBuildArchitectures[dockerOptions.Platform]
+	if !has {
+		return nil, fmt.Errorf(
+			"remote build does not support the '%s' platform, only linux/amd64 and linux/arm64 are supported",
+			dockerOptions.Platform,
+		)
+	}
+
+	if len(dockerOptions.BuildSecrets) > 0 {
+		return nil, errors.New("remote build does not support build secrets")
+	}
+
+	if dockerOptions.Buildpack {
+		return nil, errors.New("remote build does not support buildpacks, build the image locally or author a Dockerfile")
+	}
+
+	if dockerOptions.IsMultiPlatform() {
+		return nil, errors.New("remote build does not support multi-platform images, specify a single platform")
+	}
+
+	arguments := []*armcontainerregistry.Argument{}
+	for _, buildArg := range dockerOptions.BuildArgs {
+		name, value, has := strings.Cut(buildArg, "=")
+		if name == "" {
+			return nil, fmt.Errorf("invalid build arg '%s', expected the format KEY=VALUE", buildArg)
+		}
+
+		if !has {
+			value = os.Getenv(name)
+		}
+
+		arguments = append(arguments, &armcontainerregistry.Argument{
+			Name:     to.Ptr(name),
+			Value:    to.Ptr(value),
+			IsSecret: to.Ptr(false),
+		})
+	}
+
+	buildRequest := &armcontainerregistry.DockerBuildRequest{
+		SourceLocation: sourceLocation,
+		DockerFilePath: to.Ptr(dockerPath),
+		IsPushEnabled:  to.Ptr(true),
+		ImageNames:     []*string{to.Ptr(imageName)},
+		Arguments:      arguments,
+		Platform: &armcontainerregistry.PlatformProperties{
+			OS:           to.Ptr(armcontainerregistry.OSLinux),
+			Architecture: to.Ptr(architecture),
+		},
+	}
+
+	if dockerOptions.Target != "" {
+		buildRequest.Target = to.Ptr(dockerOptions.Target)
+	}
+
+	return buildRequest, nil
+}
+
 type dockerDeployResult struct {
 	RemoteImageTag string
+	// The digest of the pushed image, set when the image is signed, pinned or built for multiple platforms
+	Digest string
+	// The image referenced by its digest, ex) contoso.azurecr.io/todo/api@sha256:4f2d..., set when the image is pinned
+	PinnedImage string
+	// The reference of the image signature, ex) contoso.azurecr.io/todo/api:sha256-4f2d....sig
+	Signature string
+	// The reference of the SBOM attached to the image, ex) contoso.azurecr.io/todo/api@sha256:7c1a...
+	Sbom string
+	// The reference of the provenance attestation attached to the image, ex) contoso.azurecr.io/todo/api@sha256:9e3b...
+	Provenance string
 }
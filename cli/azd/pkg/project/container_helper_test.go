@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
@@ -17,7 +19,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cosign"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/notation"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/benbjohnson/clock"
@@ -62,7 +66,9 @@ func Test_ContainerHelper_LocalImageTag(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			env := environment.NewWithValues("dev", map[string]string{})
-			containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic())
+			containerHelper := NewContainerHelper(
+				env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, nil,
+			)
 			serviceConfig.Docker = tt.dockerConfig
 
 			tag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
@@ -111,7 +117,8 @@ func Test_ContainerHelper_RemoteImageTag(t *testing.T) {
 
 	mockContext := mocks.NewMockContext(context.Background())
 	env := environment.NewWithValues("dev", map[string]string{})
-	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic())
+	containerHelper := NewContainerHelper(
+		env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, nil)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -138,7 +145,9 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 			environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
 		})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(
+			env, envManager, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, nil,
+		)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
 
@@ -150,7 +159,9 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		env := environment.NewWithValues("dev", map[string]string{})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(
+			env, envManager, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, nil,
+		)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
@@ -164,7 +175,9 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		env := environment.NewWithValues("dev", map[string]string{})
 		env.DotenvSet("MY_CUSTOM_REGISTRY", "custom.azurecr.io")
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(
+			env, envManager, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, nil,
+		)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		serviceConfig.Docker.Registry = osutil.NewExpandableString("${MY_CUSTOM_REGISTRY}")
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
@@ -177,7 +190,9 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		env := environment.NewWithValues("dev", map[string]string{})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(
+			env, envManager, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, nil,
+		)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
 
@@ -349,6 +364,9 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 				dockerCli,
 				mockContext.Console,
 				cloud.AzurePublic(),
+				nil,
+				nil,
+				nil, nil, nil,
 			)
 			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 
@@ -411,7 +429,8 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 func Test_ContainerHelper_ConfiguredImage(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	env := environment.NewWithValues("dev", map[string]string{})
-	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic())
+	containerHelper := NewContainerHelper(
+		env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, nil)
 
 	tests := []struct {
 		name                 string
@@ -584,6 +603,59 @@ func (m *mockContainerRegistryServiceForRetry) GetContainerRegistries(
 	return args.Get(0).([]*armcontainerregistry.Registry), args.Error(1)
 }
 
+func (m *mockContainerRegistryServiceForRetry) GetRepositoryTags(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+) ([]*azcli.RepositoryTag, error) {
+	args := m.Called(ctx, subscriptionId, loginServer, repository)
+	return args.Get(0).([]*azcli.RepositoryTag), args.Error(1)
+}
+
+func (m *mockContainerRegistryServiceForRetry) DeleteRepositoryTag(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	tag string,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, repository, tag)
+	return args.Error(0)
+}
+
+func (m *mockContainerRegistryServiceForRetry) DeleteRepositoryManifest(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	digest string,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, repository, digest)
+	return args.Error(0)
+}
+
+func (m *mockContainerRegistryServiceForRetry) ImportImage(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	source azcli.ImageImportSource,
+	targetTags []string,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, source, targetTags)
+	return args.Error(0)
+}
+
+func (m *mockContainerRegistryServiceForRetry) GenerateTokenCredentials(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	tokenName string,
+) (*azcli.DockerCredentials, error) {
+	args := m.Called(ctx, subscriptionId, loginServer, tokenName)
+	return args.Get(0).(*azcli.DockerCredentials), args.Error(1)
+}
+
 func Test_ContainerHelper_Credential_Retry(t *testing.T) {
 	t.Run("Retry on 404 on time", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
@@ -597,7 +669,8 @@ func Test_ContainerHelper_Credential_Retry(t *testing.T) {
 		defaultCredentialsRetryDelay = 1 * time.Millisecond
 
 		containerHelper := NewContainerHelper(
-			env, envManager, clock.NewMock(), mockContainerService, nil, nil, nil, cloud.AzurePublic())
+			env, envManager, clock.NewMock(), mockContainerService, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil,
+			nil)
 
 		serviceConfig := createTestServiceConfig("path", ContainerAppTarget, ServiceLanguageDotNet)
 		serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
@@ -693,3 +766,294 @@ func (m *mockContainerRegistryService) GetContainerRegistries(
 	args := m.Called(ctx, subscriptionId)
 	return args.Get(0).([]*armcontainerregistry.Registry), args.Error(1)
 }
+
+func (m *mockContainerRegistryService) GetRepositoryTags(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+) ([]*azcli.RepositoryTag, error) {
+	args := m.Called(ctx, subscriptionId, loginServer, repository)
+	return args.Get(0).([]*azcli.RepositoryTag), args.Error(1)
+}
+
+func (m *mockContainerRegistryService) DeleteRepositoryTag(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	tag string,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, repository, tag)
+	return args.Error(0)
+}
+
+func (m *mockContainerRegistryService) DeleteRepositoryManifest(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	digest string,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, repository, digest)
+	return args.Error(0)
+}
+
+func (m *mockContainerRegistryService) ImportImage(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	source azcli.ImageImportSource,
+	targetTags []string,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, source, targetTags)
+	return args.Error(0)
+}
+
+func (m *mockContainerRegistryService) GenerateTokenCredentials(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	tokenName string,
+) (*azcli.DockerCredentials, error) {
+	args := m.Called(ctx, subscriptionId, loginServer, tokenName)
+	return args.Get(0).(*azcli.DockerCredentials), args.Error(1)
+}
+
+func Test_ContainerHelper_RemoteBuildRequest(t *testing.T) {
+	t.Setenv("NPM_TOKEN", "token")
+
+	t.Run("BuildArgsAndTarget", func(t *testing.T) {
+		buildRequest, err := remoteBuildRequest(DockerProjectOptions{
+			Platform:  "linux/arm64",
+			Target:    "runtime",
+			BuildArgs: []string{"VERSION=1.2.3", "NPM_TOKEN"},
+		}, to.Ptr("source/upload.tar.gz"), "Dockerfile", "contoso.azurecr.io/todo/api:azd-deploy-1")
+		require.NoError(t, err)
+
+		require.Equal(t, "source/upload.tar.gz", *buildRequest.SourceLocation)
+		require.Equal(t, "runtime", *buildRequest.Target)
+		require.Equal(t, armcontainerregistry.ArchitectureArm64, *buildRequest.Platform.Architecture)
+		require.Equal(t, "contoso.azurecr.io/todo/api:azd-deploy-1", *buildRequest.ImageNames[0])
+		require.Len(t, buildRequest.Arguments, 2)
+		require.Equal(t, "VERSION", *buildRequest.Arguments[0].Name)
+		require.Equal(t, "1.2.3", *buildRequest.Arguments[0].Value)
+		require.Equal(t, "NPM_TOKEN", *buildRequest.Arguments[1].Name)
+		require.Equal(t, "token", *buildRequest.Arguments[1].Value)
+	})
+
+	t.Run("UnsupportedPlatform", func(t *testing.T) {
+		_, err := remoteBuildRequest(DockerProjectOptions{Platform: "windows/amd64"}, nil, "", "")
+		require.ErrorContains(t, err, "remote build does not support the 'windows/amd64' platform")
+	})
+
+	t.Run("Buildpack", func(t *testing.T) {
+		_, err := remoteBuildRequest(DockerProjectOptions{Platform: "linux/amd64", Buildpack: true}, nil, "", "")
+		require.ErrorContains(t, err, "remote build does not support buildpacks")
+	})
+
+	t.Run("MultiPlatform", func(t *testing.T) {
+		dockerOptions := DockerProjectOptions{Platform: "linux/amd64", Platforms: []string{"linux/amd64", "linux/arm64"}}
+		_, err := remoteBuildRequest(dockerOptions, nil, "", "")
+		require.ErrorContains(t, err, "remote build does not support multi-platform images")
+	})
+
+	t.Run("InvalidBuildArg", func(t *testing.T) {
+		dockerOptions := DockerProjectOptions{Platform: "linux/amd64", BuildArgs: []string{"=value"}}
+		_, err := remoteBuildRequest(dockerOptions, nil, "", "")
+		require.ErrorContains(t, err, "invalid build arg '=value'")
+	})
+}
+
+func Test_ContainerHelper_Deploy_MultiPlatform(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockResults := setupDockerMocks(mockContext)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx inspect")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["docker-buildx-inspect"] = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["docker-buildx-build"] = args
+
+		metadataFile := args.Args[len(args.Args)-1]
+		err := os.WriteFile(metadataFile, []byte(`{"containerimage.digest":"sha256:4f2d"}`), osutil.PermissionFile)
+		return exec.NewRunResult(0, "", ""), err
+	})
+
+	env := environment.NewWithValues("dev", map[string]string{})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		mockContainerRegistryService,
+		nil,
+		docker.NewCli(mockContext.CommandRunner),
+		mockContext.Console,
+		cloud.AzurePublic(),
+		nil,
+		nil,
+		nil, nil, nil,
+	)
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+	serviceConfig.Docker.Platforms = []string{"linux/amd64", "linux/arm64"}
+	targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return containerHelper.Deploy(
+				*mockContext.Context, serviceConfig, &ServicePackageResult{}, targetResource, true, progress)
+		},
+	)
+	require.NoError(t, err)
+
+	remoteImage := deployResult.Details.(*dockerDeployResult).RemoteImageTag
+	require.True(t, strings.HasPrefix(remoteImage, "contoso.azurecr.io/"))
+	require.Equal(t, remoteImage, env.GetServiceProperty("api", "IMAGE_NAME"))
+
+	// The images are pushed by buildx as part of the build instead of being tagged and pushed locally
+	require.Contains(t, mockResults, "docker-buildx-inspect")
+	require.NotContains(t, mockResults, "docker-tag")
+	require.NotContains(t, mockResults, "docker-push")
+
+	buildArgs := mockResults["docker-buildx-build"].Args
+	require.Contains(t, buildArgs, "linux/amd64,linux/arm64")
+	require.Contains(t, buildArgs, remoteImage)
+	require.Contains(t, buildArgs, "--push")
+	mockContainerRegistryService.AssertCalled(
+		t, "Login", *mockContext.Context, env.GetSubscriptionId(), "contoso.azurecr.io")
+}
+
+func Test_ContainerHelper_Deploy_Signing(t *testing.T) {
+	tests := map[string]struct {
+		signing           *ImageSigningOptions
+		remoteBuild       bool
+		expectedSignature string
+		expectedError     string
+	}{
+		"Cosign": {
+			signing:           &ImageSigningOptions{Provider: ImageSigningProviderCosign},
+			expectedSignature: "contoso.azurecr.io/my-project/my-service:sha256-4f2d.sig",
+		},
+		"Notation": {
+			signing: &ImageSigningOptions{
+				Provider: ImageSigningProviderNotation,
+				KeyId:    osutil.NewExpandableString("https://contoso.vault.azure.net/keys/signing/0b1c"),
+			},
+			expectedSignature: "contoso.azurecr.io/my-project/my-service@sha256:9a7e",
+		},
+		"NotationWithoutKey": {
+			signing:       &ImageSigningOptions{Provider: ImageSigningProviderNotation},
+			expectedError: "image signing with notation requires docker.signing.keyId",
+		},
+		"UnsupportedProvider": {
+			signing:       &ImageSigningOptions{Provider: "gpg"},
+			expectedError: "unsupported image signing provider 'gpg'",
+		},
+		"RemoteBuild": {
+			signing:       &ImageSigningOptions{Provider: ImageSigningProviderCosign},
+			remoteBuild:   true,
+			expectedError: "image signing is not supported with remote builds",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockResults := setupDockerMocks(mockContext)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker image inspect")
+			}).Respond(exec.NewRunResult(0, `["contoso.azurecr.io/my-project/my-service@sha256:4f2d"]`, ""))
+
+			signedReferences := []string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "cosign sign")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				signedReferences = append(signedReferences, args.Args[2])
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "notation sign")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				signedReferences = append(signedReferences, args.Args[1])
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "cosign triangulate")
+			}).Respond(exec.NewRunResult(0, "contoso.azurecr.io/my-project/my-service:sha256-4f2d.sig\n", ""))
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "notation inspect")
+			}).Respond(exec.NewRunResult(0, `{"signatures":[{"digest":"sha256:9a7e"}]}`, ""))
+
+			env := environment.NewWithValues("dev", map[string]string{})
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+			mockContainerRegistryService := &mockContainerRegistryService{}
+			setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+			containerHelper := NewContainerHelper(
+				env,
+				envManager,
+				clock.NewMock(),
+				mockContainerRegistryService,
+				nil,
+				docker.NewCli(mockContext.CommandRunner),
+				mockContext.Console,
+				cloud.AzurePublic(),
+				notation.NewCli(mockContext.CommandRunner),
+				cosign.NewCli(mockContext.CommandRunner),
+				nil, nil, nil,
+			)
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+			serviceConfig.Docker.Signing = test.signing
+			serviceConfig.Docker.RemoteBuild = test.remoteBuild
+
+			packageOutput := &ServicePackageResult{
+				Details: &dockerPackageResult{TargetImage: "my-project/my-service:azd-deploy-0"},
+			}
+			targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return containerHelper.Deploy(
+						*mockContext.Context, serviceConfig, packageOutput, targetResource, true, progress)
+				},
+			)
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				require.NotContains(t, mockResults, "docker-push")
+				return
+			}
+
+			require.NoError(t, err)
+
+			// The digest of the pushed image is signed instead of the mutable tag
+			require.Equal(t, []string{"contoso.azurecr.io/my-project/my-service@sha256:4f2d"}, signedReferences)
+
+			details := deployResult.Details.(*dockerDeployResult)
+			require.Equal(t, "sha256:4f2d", details.Digest)
+			require.Equal(t, test.expectedSignature, details.Signature)
+			require.Equal(t, "sha256:4f2d", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+			require.Equal(t, test.expectedSignature, env.GetServiceProperty("api", "IMAGE_SIGNATURE"))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// validateDaprOptions returns an error when the Dapr options of the service are invalid
+func validateDaprOptions(serviceConfig *ServiceConfig) error {
+	dapr := serviceConfig.Dapr
+	if dapr.AppPort < 0 {
+		return fmt.Errorf("the Dapr app port of service '%s' must not be negative", serviceConfig.Name)
+	}
+
+	if dapr.AppProtocol != "" && dapr.AppProtocol != "http" && dapr.AppProtocol != "grpc" {
+		return fmt.Errorf(
+			"unsupported Dapr app protocol '%s' of service '%s', supported values are 'http' and 'grpc'",
+			dapr.AppProtocol,
+			serviceConfig.Name,
+		)
+	}
+
+	return nil
+}
+
+// applyDaprComponents applies the Dapr components of the service to the managed environment of the container app
+func (at *containerAppTarget) applyDaprComponents(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	components, err := loadDaprComponents(serviceConfig, at.env.Getenv)
+	if err != nil {
+		return err
+	}
+
+	containerAppComponents, err := containerAppDaprComponents(components)
+	if err != nil {
+		return err
+	}
+
+	if err := at.containerAppService.ApplyDaprComponents(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		containerAppComponents,
+	); err != nil {
+		return fmt.Errorf("failed applying Dapr components: %w", err)
+	}
+
+	return nil
+}
+
+// waitForDaprRevision waits for the new revision to become healthy, the revision is unhealthy when its Dapr
+// sidecar fails to start, ex) when a Dapr component of the service can't be initialized
+func (at *containerAppTarget) waitForDaprRevision(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	revisionName string,
+	options *containerapps.ContainerAppOptions,
+	progress *async.Progress[ServiceProgress],
+) error {
+	timeout := serviceConfig.ContainerApp.HealthTimeout
+	if timeout == 0 {
+		timeout = defaultRevisionHealthTimeout
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying Dapr sidecar: %s", revisionName)))
+	_, err := at.containerAppService.WaitForRevision(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		revisionName,
+		timeout,
+		options,
+	)
+	if errors.Is(err, containerapps.ErrRevisionUnhealthy) {
+		return fmt.Errorf(
+			"revision '%s' with the Dapr sidecar is unhealthy, verify the Dapr components of service '%s' are valid: %w",
+			revisionName,
+			serviceConfig.Name,
+			err,
+		)
+	}
+
+	return err
+}
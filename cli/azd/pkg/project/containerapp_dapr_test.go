@@ -0,0 +1,147 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerApp_Deploy_Dapr(t *testing.T) {
+	tests := map[string]struct {
+		healthState   armappcontainers.RevisionHealthState
+		expectedError string
+	}{
+		"Healthy": {
+			healthState: armappcontainers.RevisionHealthStateHealthy,
+		},
+		"Unhealthy": {
+			healthState:   armappcontainers.RevisionHealthStateUnhealthy,
+			expectedError: "revision 'CONTAINER_APP--azd-0' with the Dapr sidecar is unhealthy",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			setupMocksForDocker(mockContext)
+			setupMocksForAcr(mockContext)
+			updateRequest := setupMocksForContainerApps(mockContext)
+			componentRequest := mockazsdk.MockContainerAppDaprComponentCreateOrUpdate(
+				mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "ENVIRONMENT", "pubsub")
+			mockazsdk.MockContainerAppRevisionGet(
+				mockContext,
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"CONTAINER_APP",
+				"CONTAINER_APP--azd-0",
+				&armappcontainers.Revision{
+					Name: to.Ptr("CONTAINER_APP--azd-0"),
+					Properties: &armappcontainers.RevisionProperties{
+						ProvisioningState: to.Ptr(armappcontainers.RevisionProvisioningStateProvisioned),
+						HealthState:       to.Ptr(test.healthState),
+						RunningState:      to.Ptr(armappcontainers.RevisionRunningStateRunning),
+					},
+				},
+			)
+
+			serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Dapr = &DaprOptions{AppPort: 3000, Components: "components"}
+			writeDaprComponent(t, filepath.Join(serviceConfig.Path(), "components"), "pubsub.yaml")
+
+			env := createEnv()
+			env.DotenvSet("SERVICEBUS_NAMESPACE", "sb-01")
+
+			serviceTarget := createContainerAppServiceTarget(mockContext, env)
+			scope := environment.NewTargetResource(
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"CONTAINER_APP",
+				string(azapi.AzureResourceTypeContainerApp),
+			)
+			packageResult := &ServicePackageResult{
+				PackagePath: "test-app/api-test:azd-deploy-0",
+				Details: &dockerPackageResult{
+					ImageHash:   "IMAGE_HASH",
+					TargetImage: "test-app/api-test:azd-deploy-0",
+				},
+			}
+
+			_, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+				},
+			)
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+
+			// The component is applied to the managed environment with the environment values substituted
+			var component *armappcontainers.DaprComponent
+			require.NoError(t, json.NewDecoder(componentRequest.Body).Decode(&component))
+			require.Equal(t, "pubsub.azure.servicebus.topics", *component.Properties.ComponentType)
+			require.Equal(t, "sb-01.servicebus.windows.net", *component.Properties.Metadata[0].Value)
+
+			// The Dapr sidecar is enabled with the revision
+			var updatedContainerApp *armappcontainers.ContainerApp
+			require.NoError(t, json.NewDecoder(updateRequest.Body).Decode(&updatedContainerApp))
+
+			dapr := updatedContainerApp.Properties.Configuration.Dapr
+			require.True(t, *dapr.Enabled)
+			require.Equal(t, "api", *dapr.AppID)
+			require.Equal(t, int32(3000), *dapr.AppPort)
+		})
+	}
+}
+
+func Test_ValidateDaprOptions(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+	serviceConfig.Dapr = &DaprOptions{AppPort: 8080, AppProtocol: "grpc"}
+	require.NoError(t, validateDaprOptions(serviceConfig))
+
+	serviceConfig.Dapr = &DaprOptions{AppPort: -1}
+	require.ErrorContains(t, validateDaprOptions(serviceConfig), "Dapr app port of service 'api' must not be negative")
+
+	serviceConfig.Dapr = &DaprOptions{AppProtocol: "https"}
+	require.ErrorContains(t, validateDaprOptions(serviceConfig), "unsupported Dapr app protocol 'https'")
+}
+
+// writeDaprComponent writes a Service Bus pub/sub component referencing the namespace of the azd environment
+func writeDaprComponent(t *testing.T, dir string, name string) {
+	component := `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: pubsub
+spec:
+  type: pubsub.azure.servicebus.topics
+  version: v1
+  metadata:
+    - name: namespaceName
+      value: ${SERVICEBUS_NAMESPACE}.servicebus.windows.net
+scopes:
+  - api
+`
+
+	require.NoError(t, os.MkdirAll(dir, osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(component), osutil.PermissionFile))
+}
@@ -0,0 +1,123 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// A custom domain of a container app, bound with a managed certificate of the managed environment when the service is
+// deployed
+type ContainerAppCustomDomainOptions struct {
+	// The host name of the domain, ex) api.contoso.com or ${API_DOMAIN}. Domains whose name is empty in the environment
+	// are skipped
+	Name osutil.ExpandableString `yaml:"name"`
+	// The environments the domain is bound in, ex) prod. The domain is bound in all environments when empty
+	Environments []string `yaml:"environments,omitempty"`
+	// How the managed certificate validates the ownership of the domain, CNAME, HTTP or TXT. Defaults to CNAME
+	Validation string `yaml:"validation,omitempty"`
+}
+
+// validateCustomDomains returns an error when the custom domains of the service are invalid
+func validateCustomDomains(serviceConfig *ServiceConfig) error {
+	for _, domain := range serviceConfig.ContainerApp.CustomDomains {
+		if domain.Name.Empty() {
+			return fmt.Errorf("a custom domain of service '%s' is missing its name", serviceConfig.Name)
+		}
+
+		switch strings.ToUpper(domain.Validation) {
+		case "", "CNAME", "HTTP", "TXT":
+		default:
+			return fmt.Errorf(
+				"unsupported validation '%s' of a custom domain of service '%s', supported values are CNAME, HTTP and TXT",
+				domain.Validation,
+				serviceConfig.Name,
+			)
+		}
+	}
+
+	return nil
+}
+
+// customDomains returns the custom domains of the service bound in the environment
+func customDomains(
+	serviceConfig *ServiceConfig,
+	env *environment.Environment,
+) ([]*containerapps.ContainerAppCustomDomain, error) {
+	domains := []*containerapps.ContainerAppCustomDomain{}
+	for _, domain := range serviceConfig.ContainerApp.CustomDomains {
+		hostName, err := customDomainHostName(serviceConfig, domain.Name, domain.Environments, env)
+		if err != nil {
+			return nil, err
+		}
+
+		if hostName == "" {
+			continue
+		}
+
+		domains = append(domains, &containerapps.ContainerAppCustomDomain{
+			HostName:   hostName,
+			Validation: domain.Validation,
+		})
+	}
+
+	return domains, nil
+}
+
+// customDomainHostName returns the host name of a custom domain of the service in the environment, empty when the
+// domain is not bound in the environment
+func customDomainHostName(
+	serviceConfig *ServiceConfig,
+	name osutil.ExpandableString,
+	environments []string,
+	env *environment.Environment,
+) (string, error) {
+	if len(environments) > 0 && !slices.Contains(environments, env.Name()) {
+		return "", nil
+	}
+
+	hostName, err := name.Envsubst(env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("substituting environment variables in custom domain: %w", err)
+	}
+
+	hostName = strings.ToLower(strings.TrimSpace(hostName))
+	if hostName == "" {
+		log.Printf("skipping a custom domain of service '%s', its name is empty in environment '%s'",
+			serviceConfig.Name, env.Name())
+	}
+
+	return hostName, nil
+}
+
+// bindCustomDomains binds the custom domains of the service in the environment to the container app
+func (at *containerAppTarget) bindCustomDomains(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *containerapps.ContainerAppOptions,
+) error {
+	domains, err := customDomains(serviceConfig, at.env)
+	if err != nil || len(domains) == 0 {
+		return err
+	}
+
+	if err := at.containerAppService.BindCustomDomains(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		domains,
+		options,
+	); err != nil {
+		return fmt.Errorf("failed binding custom domains: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CustomDomains(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.ContainerApp.CustomDomains = []ContainerAppCustomDomainOptions{
+		{Name: osutil.NewExpandableString("${API_DOMAIN}"), Validation: "TXT"},
+		{Name: osutil.NewExpandableString("www.contoso.com"), Environments: []string{"prod"}},
+	}
+
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.EnvNameEnvVarName: "dev",
+		"API_DOMAIN":                  "API.dev.contoso.com",
+	})
+	domains, err := customDomains(serviceConfig, env)
+	require.NoError(t, err)
+	require.Equal(t, []*containerapps.ContainerAppCustomDomain{
+		{HostName: "api.dev.contoso.com", Validation: "TXT"},
+	}, domains)
+
+	// Domains whose name is not set in the environment are skipped
+	env = environment.NewWithValues("prod", nil)
+	domains, err = customDomains(serviceConfig, env)
+	require.NoError(t, err)
+	require.Equal(t, []*containerapps.ContainerAppCustomDomain{
+		{HostName: "www.contoso.com"},
+	}, domains)
+}
+
+func Test_ValidateCustomDomains(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.ContainerApp.CustomDomains = []ContainerAppCustomDomainOptions{
+		{Name: osutil.NewExpandableString("api.contoso.com"), Validation: "http"},
+	}
+	require.NoError(t, validateCustomDomains(serviceConfig))
+
+	serviceConfig.ContainerApp.CustomDomains[0].Validation = "EMAIL"
+	require.ErrorContains(t, validateCustomDomains(serviceConfig), "unsupported validation 'EMAIL'")
+
+	serviceConfig.ContainerApp.CustomDomains = []ContainerAppCustomDomainOptions{{}}
+	require.ErrorContains(t, validateCustomDomains(serviceConfig), "a custom domain of service 'api' is missing its name")
+}
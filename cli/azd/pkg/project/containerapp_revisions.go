@@ -0,0 +1,344 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// The time the deployment waits for a new revision to become healthy when not configured
+const defaultRevisionHealthTimeout = 5 * time.Minute
+
+// The Azure Container Apps options of a service
+type ContainerAppOptions struct {
+	// The percentage of the ingress traffic routed to the new revision, ex) 10 for a canary revision. The revision
+	// serving most of the traffic keeps the rest until the traffic is shifted with 'azd traffic'.
+	// All the traffic is routed to the new revision when not set.
+	Traffic *int `yaml:"traffic,omitempty"`
+	// When enabled, the deployment waits for the new revision to become healthy. A revision that fails to provision or
+	// whose health probes fail is deactivated and the traffic is routed back to the previous revisions.
+	Rollback bool `yaml:"rollback,omitempty"`
+	// How long the deployment waits for the new revision to become healthy, ex) 10m
+	HealthTimeout time.Duration `yaml:"healthTimeout,omitempty"`
+	// The custom domains bound to the container app with managed certificates, ex) api.contoso.com in a 'prod'
+	// environment. The DNS records proving the ownership of a domain are reported when missing.
+	CustomDomains []ContainerAppCustomDomainOptions `yaml:"customDomains,omitempty"`
+}
+
+// ContainerAppRollbackError is returned when a new revision was unhealthy and was rolled back
+// The error reports both the health failure and the result of the rollback
+type ContainerAppRollbackError struct {
+	RevisionName string
+	// The error of the unhealthy revision
+	Err error
+	// The error of the rollback, nil when the traffic was routed back to the previous revisions
+	RollbackErr error
+}
+
+func (e *ContainerAppRollbackError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf(
+			"revision '%s' is unhealthy and could not be rolled back: %s\nhealth failure: %s",
+			e.RevisionName,
+			e.RollbackErr,
+			e.Err,
+		)
+	}
+
+	return fmt.Sprintf(
+		"revision '%s' is unhealthy and was rolled back to the previous revisions\nhealth failure: %s",
+		e.RevisionName,
+		e.Err,
+	)
+}
+
+func (e *ContainerAppRollbackError) Unwrap() error {
+	return e.Err
+}
+
+// validateContainerAppOptions returns an error when the revision options of the service are invalid
+func validateContainerAppOptions(serviceConfig *ServiceConfig) error {
+	options := serviceConfig.ContainerApp
+	if options.Traffic != nil && (*options.Traffic < 0 || *options.Traffic > 100) {
+		return fmt.Errorf(
+			"the traffic of service '%s' must be a percentage between 0 and 100, got %d",
+			serviceConfig.Name,
+			*options.Traffic,
+		)
+	}
+
+	if options.HealthTimeout < 0 {
+		return fmt.Errorf("the health timeout of service '%s' must not be negative", serviceConfig.Name)
+	}
+
+	return validateCustomDomains(serviceConfig)
+}
+
+// trafficWeights returns the percentage of the traffic routed to each revision of the container app serving traffic
+func (at *containerAppTarget) trafficWeights(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	options *containerapps.ContainerAppOptions,
+) (map[string]int, error) {
+	revisions, err := at.containerAppService.ListRevisions(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		options,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	weights := map[string]int{}
+	for _, revision := range revisions {
+		if revision.Active && revision.TrafficWeight > 0 {
+			weights[revision.Name] = revision.TrafficWeight
+		}
+	}
+
+	return weights, nil
+}
+
+// verifyRevision waits for the new revision to become healthy. An unhealthy revision is rolled back by routing the
+// traffic back to the previous revisions and deactivating the new revision.
+func (at *containerAppTarget) verifyRevision(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	revisionName string,
+	previousTraffic map[string]int,
+	options *containerapps.ContainerAppOptions,
+	progress *async.Progress[ServiceProgress],
+) error {
+	timeout := serviceConfig.ContainerApp.HealthTimeout
+	if timeout == 0 {
+		timeout = defaultRevisionHealthTimeout
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying revision: %s", revisionName)))
+	_, err := at.containerAppService.WaitForRevision(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		revisionName,
+		timeout,
+		options,
+	)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, containerapps.ErrRevisionUnhealthy) {
+		return err
+	}
+
+	rollbackErr := &ContainerAppRollbackError{
+		RevisionName: revisionName,
+		Err:          err,
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Rolling back revision: %s", revisionName)))
+	if len(previousTraffic) > 0 {
+		if err := at.containerAppService.SetTraffic(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			previousTraffic,
+			options,
+		); err != nil {
+			rollbackErr.RollbackErr = err
+			return rollbackErr
+		}
+	}
+
+	if err := at.containerAppService.DeactivateRevision(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		revisionName,
+		options,
+	); err != nil {
+		rollbackErr.RollbackErr = err
+		return rollbackErr
+	}
+
+	return rollbackErr
+}
+
+// Lists the revisions of the container app, the most recent revision first
+func (at *containerAppTarget) Revisions(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]*ServiceRevision, error) {
+	if err := at.validateTargetResource(targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	revisions, err := at.containerAppService.ListRevisions(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		&containerapps.ContainerAppOptions{ApiVersion: serviceConfig.ApiVersion},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceRevisions := make([]*ServiceRevision, len(revisions))
+	for idx, revision := range revisions {
+		serviceRevisions[idx] = &ServiceRevision{
+			Name:      revision.Name,
+			Active:    revision.Active,
+			Traffic:   revision.TrafficWeight,
+			Health:    revision.HealthState,
+			Image:     revision.Image,
+			CreatedOn: revision.CreatedTime,
+		}
+	}
+
+	return serviceRevisions, nil
+}
+
+// Status returns the live status of the latest active revision of the container app
+func (at *containerAppTarget) Status(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (*ServiceStatus, error) {
+	if err := at.validateTargetResource(targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	revisions, err := at.containerAppService.ListRevisions(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		&containerapps.ContainerAppOptions{ApiVersion: serviceConfig.ApiVersion},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range revisions {
+		if !revision.Active {
+			continue
+		}
+
+		return &ServiceStatus{
+			Revision: revision.Name,
+			Status:   revision.RunningState,
+			Details: fmt.Sprintf(
+				"%d replicas, %d%% of the traffic, health %s",
+				revision.Replicas,
+				revision.TrafficWeight,
+				revision.HealthState,
+			),
+			Image: revision.Image,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("container app '%s' has no active revision", targetResource.ResourceName())
+}
+
+// Routes the percentage of the ingress traffic of the container app to the revision, or to the latest revision when
+// empty, and the rest of the traffic to the other revision serving most of the traffic
+func (at *containerAppTarget) ShiftTraffic(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	revision string,
+	percent int,
+	progress *async.Progress[ServiceProgress],
+) ([]*ServiceRevision, error) {
+	revisions, err := at.Revisions(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	weights, err := shiftTrafficWeights(revisions, revision, percent)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Updating container app traffic"))
+	if err := at.containerAppService.SetTraffic(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		weights,
+		&containerapps.ContainerAppOptions{ApiVersion: serviceConfig.ApiVersion},
+	); err != nil {
+		return nil, err
+	}
+
+	for _, serviceRevision := range revisions {
+		serviceRevision.Traffic = weights[serviceRevision.Name]
+	}
+
+	return revisions, nil
+}
+
+// shiftTrafficWeights routes the percentage of the traffic to the revision, or to the latest revision when empty, and
+// the rest of the traffic to the other active revision serving most of the traffic
+func shiftTrafficWeights(revisions []*ServiceRevision, revisionName string, percent int) (map[string]int, error) {
+	var target *ServiceRevision
+	for _, revision := range revisions {
+		if revisionName == "" || revision.Name == revisionName {
+			target = revision
+			break
+		}
+	}
+
+	if target == nil && revisionName == "" {
+		return nil, errors.New("the container app does not have any revisions")
+	}
+
+	if target == nil {
+		return nil, fmt.Errorf("revision '%s' does not exist", revisionName)
+	}
+
+	if !target.Active {
+		return nil, fmt.Errorf("revision '%s' is not active", target.Name)
+	}
+
+	if percent == 100 {
+		return map[string]int{target.Name: 100}, nil
+	}
+
+	var stable *ServiceRevision
+	for _, revision := range revisions {
+		if revision == target || !revision.Active {
+			continue
+		}
+
+		if stable == nil || revision.Traffic > stable.Traffic {
+			stable = revision
+		}
+	}
+
+	if stable == nil {
+		return nil, fmt.Errorf(
+			"revision '%s' is the only active revision, the rest of the traffic can't be routed to another revision",
+			target.Name,
+		)
+	}
+
+	return map[string]int{
+		target.Name: percent,
+		stable.Name: 100 - percent,
+	}, nil
+}
@@ -0,0 +1,232 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerApp_Deploy_Rollback(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForDocker(mockContext)
+	setupMocksForAcr(mockContext)
+	updateRequest := setupMocksForContainerApps(mockContext)
+
+	mockazsdk.MockContainerAppRevisionsList(
+		mockContext,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		[]*armappcontainers.Revision{
+			{
+				Name: to.Ptr("ORIGINAL_REVISION_NAME"),
+				Properties: &armappcontainers.RevisionProperties{
+					Active:        to.Ptr(true),
+					TrafficWeight: to.Ptr[int32](100),
+				},
+			},
+		},
+	)
+	mockazsdk.MockContainerAppRevisionGet(
+		mockContext,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		"CONTAINER_APP--azd-0",
+		&armappcontainers.Revision{
+			Name: to.Ptr("CONTAINER_APP--azd-0"),
+			Properties: &armappcontainers.RevisionProperties{
+				ProvisioningState: to.Ptr(armappcontainers.RevisionProvisioningStateProvisioned),
+				HealthState:       to.Ptr(armappcontainers.RevisionHealthStateUnhealthy),
+				RunningState:      to.Ptr(armappcontainers.RevisionRunningStateDegraded),
+			},
+		},
+	)
+	deactivateRequest := mockazsdk.MockContainerAppRevisionDeactivate(
+		mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP", "CONTAINER_APP--azd-0")
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.ContainerApp.Rollback = true
+	serviceTarget := createContainerAppServiceTarget(mockContext, createEnv())
+
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(azapi.AzureResourceTypeContainerApp),
+	)
+	packageResult := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash:   "IMAGE_HASH",
+			TargetImage: "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	_, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+		},
+	)
+
+	var rollbackErr *ContainerAppRollbackError
+	require.ErrorAs(t, err, &rollbackErr)
+	require.Equal(t, "CONTAINER_APP--azd-0", rollbackErr.RevisionName)
+	require.NoError(t, rollbackErr.RollbackErr)
+	require.ErrorContains(t, err, "was rolled back to the previous revisions")
+
+	// The traffic is routed back to the previous revision and the new revision is deactivated
+	var updatedContainerApp *armappcontainers.ContainerApp
+	require.NoError(t, json.NewDecoder(updateRequest.Body).Decode(&updatedContainerApp))
+
+	traffic := updatedContainerApp.Properties.Configuration.Ingress.Traffic
+	require.Len(t, traffic, 1)
+	require.Equal(t, "ORIGINAL_REVISION_NAME", *traffic[0].RevisionName)
+	require.Equal(t, int32(100), *traffic[0].Weight)
+	require.Contains(t, deactivateRequest.URL.Path, "/revisions/CONTAINER_APP--azd-0/deactivate")
+}
+
+func Test_ShiftTrafficWeights(t *testing.T) {
+	revisions := []*ServiceRevision{
+		{Name: "api--canary", Active: true, Traffic: 10},
+		{Name: "api--stable", Active: true, Traffic: 90},
+		{Name: "api--old", Active: false},
+	}
+
+	tests := []struct {
+		name        string
+		revision    string
+		percent     int
+		expected    map[string]int
+		expectedErr string
+	}{
+		{
+			name:     "Latest",
+			percent:  50,
+			expected: map[string]int{"api--canary": 50, "api--stable": 50},
+		},
+		{
+			name:     "Promote",
+			percent:  100,
+			expected: map[string]int{"api--canary": 100},
+		},
+		{
+			name:     "Revision",
+			revision: "api--stable",
+			percent:  100,
+			expected: map[string]int{"api--stable": 100},
+		},
+		{
+			name:        "Inactive",
+			revision:    "api--old",
+			percent:     100,
+			expectedErr: "revision 'api--old' is not active",
+		},
+		{
+			name:        "Missing",
+			revision:    "api--missing",
+			percent:     100,
+			expectedErr: "revision 'api--missing' does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights, err := shiftTrafficWeights(revisions, tt.revision, tt.percent)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, weights)
+		})
+	}
+
+	t.Run("OnlyRevision", func(t *testing.T) {
+		_, err := shiftTrafficWeights(revisions[:1], "", 50)
+		require.ErrorContains(t, err, "is the only active revision")
+	})
+}
+
+func Test_ValidateContainerAppOptions(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	require.NoError(t, validateContainerAppOptions(serviceConfig))
+
+	serviceConfig.ContainerApp.Traffic = to.Ptr(0)
+	require.NoError(t, validateContainerAppOptions(serviceConfig))
+
+	serviceConfig.ContainerApp.Traffic = to.Ptr(120)
+	require.ErrorContains(t, validateContainerAppOptions(serviceConfig), "between 0 and 100, got 120")
+}
+
+func Test_ContainerApp_Status(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockazsdk.MockContainerAppRevisionsList(
+		mockContext,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		[]*armappcontainers.Revision{
+			{
+				Name: to.Ptr("CONTAINER_APP--inactive"),
+				Properties: &armappcontainers.RevisionProperties{
+					Active:      to.Ptr(false),
+					CreatedTime: to.Ptr(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+				},
+			},
+			{
+				Name: to.Ptr("CONTAINER_APP--azd-1"),
+				Properties: &armappcontainers.RevisionProperties{
+					Active:        to.Ptr(true),
+					TrafficWeight: to.Ptr[int32](100),
+					Replicas:      to.Ptr[int32](2),
+					HealthState:   to.Ptr(armappcontainers.RevisionHealthStateHealthy),
+					RunningState:  to.Ptr(armappcontainers.RevisionRunningStateRunning),
+					CreatedTime:   to.Ptr(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+					Template: &armappcontainers.Template{
+						Containers: []*armappcontainers.Container{
+							{Image: to.Ptr("contoso.azurecr.io/todo/api:azd-deploy-1")},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceTarget := createContainerAppServiceTarget(mockContext, createEnv())
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(azapi.AzureResourceTypeContainerApp),
+	)
+
+	// The most recent revision is inactive, the status is the status of the latest active revision
+	status, err := serviceTarget.(ServiceTargetStatusReporter).Status(*mockContext.Context, serviceConfig, scope)
+	require.NoError(t, err)
+	require.Equal(t, &ServiceStatus{
+		Revision: "CONTAINER_APP--azd-1",
+		Status:   "Running",
+		Details:  "2 replicas, 100% of the traffic, health Healthy",
+		Image:    "contoso.azurecr.io/todo/api:azd-deploy-1",
+	}, status)
+}
@@ -0,0 +1,245 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"gopkg.in/yaml.v3"
+)
+
+// The name of the sidecar container injected by Dapr into the pods of a k8s deployment
+const daprSidecarName = "daprd"
+
+// The Dapr options of an AKS or Container Apps service
+// When configured, the Dapr components of the service are applied before the service is deployed and the Dapr
+// sidecar of the service is verified to be healthy once it is deployed
+type DaprOptions struct {
+	// The Dapr app id of the service. Defaults to the service name. On AKS the app id is set with the
+	// 'dapr.io/app-id' annotation of the pod template
+	AppId string `yaml:"appId,omitempty"`
+	// The port the application listens on for Dapr, ex) 8080. Only used by Container Apps, on AKS the port is set
+	// with the 'dapr.io/app-port' annotation of the pod template
+	AppPort int `yaml:"appPort,omitempty"`
+	// The protocol the application uses, http or grpc. Only used by Container Apps
+	AppProtocol string `yaml:"appProtocol,omitempty"`
+	// The path of a Dapr component file or of a directory of component files relative to the service path,
+	// ex) ./components. Environment variable references are substituted with the values of the azd environment,
+	// ex) the outputs of the infrastructure
+	Components string `yaml:"components,omitempty"`
+	// Whether to wait for the Dapr sidecar of the service to be healthy. Defaults to true
+	WaitForSidecar *bool `yaml:"waitForSidecar,omitempty"`
+}
+
+// appId returns the Dapr app id of the service
+func (o *DaprOptions) appId(serviceConfig *ServiceConfig) string {
+	if o.AppId != "" {
+		return o.AppId
+	}
+
+	return serviceConfig.Name
+}
+
+// waitForSidecar returns whether to wait for the Dapr sidecar of the service to be healthy
+func (o *DaprOptions) waitForSidecar() bool {
+	return o.WaitForSidecar == nil || *o.WaitForSidecar
+}
+
+// daprComponent is a Dapr component definition, ex) a pub/sub or state store component
+// See https://docs.dapr.io/reference/resource-specs/component-schema/
+type daprComponent struct {
+	ApiVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Type         string                  `yaml:"type"`
+		Version      string                  `yaml:"version"`
+		IgnoreErrors bool                    `yaml:"ignoreErrors"`
+		InitTimeout  string                  `yaml:"initTimeout"`
+		Metadata     []daprComponentMetadata `yaml:"metadata"`
+	} `yaml:"spec"`
+	Auth struct {
+		SecretStore string `yaml:"secretStore"`
+	} `yaml:"auth"`
+	Scopes []string `yaml:"scopes"`
+
+	// The component definition with the environment variable references substituted
+	manifest string
+}
+
+type daprComponentMetadata struct {
+	Name         string            `yaml:"name"`
+	Value        string            `yaml:"value"`
+	SecretKeyRef *daprSecretKeyRef `yaml:"secretKeyRef"`
+}
+
+// daprSecretKeyRef references the key of a secret of the secret store of a Dapr component
+type daprSecretKeyRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+// loadDaprComponents reads the Dapr components of the service with the environment variable references substituted
+// Documents of the component files that are not Dapr components, ex) Dapr subscriptions, are skipped
+func loadDaprComponents(serviceConfig *ServiceConfig, getenv func(string) string) ([]*daprComponent, error) {
+	componentsPath := serviceConfig.Dapr.Components
+	if !filepath.IsAbs(componentsPath) {
+		componentsPath = filepath.Join(serviceConfig.Path(), componentsPath)
+	}
+
+	info, err := os.Stat(componentsPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading Dapr components of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	files := []string{componentsPath}
+	if info.IsDir() {
+		entries, err := os.ReadDir(componentsPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading Dapr components of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		files = []string{}
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, filepath.Join(componentsPath, entry.Name()))
+			}
+		}
+	}
+
+	components := []*daprComponent{}
+	for _, file := range files {
+		fileComponents, err := parseDaprComponents(file, getenv)
+		if err != nil {
+			return nil, err
+		}
+
+		components = append(components, fileComponents...)
+	}
+
+	if len(components) == 0 {
+		return nil, fmt.Errorf("no Dapr components found in '%s'", componentsPath)
+	}
+
+	return components, nil
+}
+
+// parseDaprComponents parses the Dapr components of all yaml documents of the file
+func parseDaprComponents(file string, getenv func(string) string) ([]*daprComponent, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading Dapr component file '%s': %w", file, err)
+	}
+
+	substituted, err := osutil.NewExpandableString(string(contents)).Envsubst(getenv)
+	if err != nil {
+		return nil, fmt.Errorf("substituting environment variables in '%s': %w", file, err)
+	}
+
+	components := []*daprComponent{}
+	decoder := yaml.NewDecoder(strings.NewReader(substituted))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("parsing Dapr component file '%s': %w", file, err)
+		}
+
+		var component daprComponent
+		if err := document.Decode(&component); err != nil {
+			return nil, fmt.Errorf("parsing Dapr component file '%s': %w", file, err)
+		}
+
+		if component.Kind != "Component" || !strings.HasPrefix(component.ApiVersion, "dapr.io/") {
+			log.Printf("skipping '%s' of kind '%s' in Dapr component file '%s'",
+				component.Metadata.Name, component.Kind, file)
+			continue
+		}
+
+		if component.Metadata.Name == "" || component.Spec.Type == "" {
+			return nil, fmt.Errorf("component in '%s' must set 'metadata.name' and 'spec.type'", file)
+		}
+
+		manifest, err := yaml.Marshal(&document)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling Dapr component '%s': %w", component.Metadata.Name, err)
+		}
+
+		component.manifest = string(manifest)
+		components = append(components, &component)
+	}
+
+	return components, nil
+}
+
+// daprComponentsManifest returns the multi-document yaml manifest of the Dapr components applied to a k8s cluster
+func daprComponentsManifest(components []*daprComponent) string {
+	manifests := make([]string, 0, len(components))
+	for _, component := range components {
+		manifests = append(manifests, component.manifest)
+	}
+
+	return strings.Join(manifests, "---\n")
+}
+
+// containerAppDaprComponents converts the Dapr components to the Dapr components of a Container Apps environment
+// Secrets of the components are referenced from the secret store component of the environment since the components
+// of an environment don't reference k8s secrets
+func containerAppDaprComponents(components []*daprComponent) ([]*containerapps.ContainerAppDaprComponent, error) {
+	converted := make([]*containerapps.ContainerAppDaprComponent, 0, len(components))
+	for _, component := range components {
+		version := component.Spec.Version
+		if version == "" {
+			version = "v1"
+		}
+
+		containerAppComponent := &containerapps.ContainerAppDaprComponent{
+			Name:                 component.Metadata.Name,
+			ComponentType:        component.Spec.Type,
+			Version:              version,
+			IgnoreErrors:         component.Spec.IgnoreErrors,
+			InitTimeout:          component.Spec.InitTimeout,
+			SecretStoreComponent: component.Auth.SecretStore,
+			Scopes:               component.Scopes,
+		}
+
+		for _, metadata := range component.Spec.Metadata {
+			item := containerapps.ContainerAppDaprMetadata{Name: metadata.Name, Value: metadata.Value}
+			if metadata.SecretKeyRef != nil {
+				if component.Auth.SecretStore == "" {
+					return nil, fmt.Errorf(
+						"component '%s' references secret '%s' without setting 'auth.secretStore', "+
+							"which Container Apps requires to resolve secret references",
+						component.Metadata.Name,
+						metadata.SecretKeyRef.Name,
+					)
+				}
+
+				item.Value = ""
+				item.SecretRef = metadata.SecretKeyRef.Name
+			}
+
+			containerAppComponent.Metadata = append(containerAppComponent.Metadata, item)
+		}
+
+		converted = append(converted, containerAppComponent)
+	}
+
+	return converted, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadDaprComponents(t *testing.T) {
+	t.Run("Directory", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Dapr = &DaprOptions{Components: "components"}
+
+		componentsDir := filepath.Join(serviceConfig.Path(), "components")
+		writeDaprComponent(t, componentsDir, "pubsub.yaml")
+		statestore := `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.azure.cosmosdb
+  metadata:
+    - name: url
+      value: ${COSMOS_ENDPOINT}
+    - name: masterKey
+      secretKeyRef:
+        name: cosmos-key
+        key: cosmos-key
+auth:
+  secretStore: secretstore
+---
+apiVersion: dapr.io/v2alpha1
+kind: Subscription
+metadata:
+  name: orders
+`
+		require.NoError(t, os.WriteFile(
+			filepath.Join(componentsDir, "statestore.yml"), []byte(statestore), osutil.PermissionFile))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(componentsDir, "README.md"), []byte("# Components"), osutil.PermissionFile))
+
+		env := map[string]string{"SERVICEBUS_NAMESPACE": "sb-01", "COSMOS_ENDPOINT": "https://cosmos-01.documents.azure.com"}
+		components, err := loadDaprComponents(serviceConfig, func(name string) string { return env[name] })
+		require.NoError(t, err)
+		require.Len(t, components, 2)
+
+		require.Equal(t, "pubsub", components[0].Metadata.Name)
+		require.Equal(t, []string{"api"}, components[0].Scopes)
+		require.Contains(t, components[0].manifest, "value: sb-01.servicebus.windows.net")
+
+		require.Equal(t, "statestore", components[1].Metadata.Name)
+		require.Equal(t, "https://cosmos-01.documents.azure.com", components[1].Spec.Metadata[0].Value)
+		require.Equal(t, "cosmos-key", components[1].Spec.Metadata[1].SecretKeyRef.Name)
+		require.Equal(t, "secretstore", components[1].Auth.SecretStore)
+		require.NotContains(t, components[1].manifest, "Subscription")
+
+		require.Equal(t,
+			components[0].manifest+"---\n"+components[1].manifest, daprComponentsManifest(components))
+	})
+
+	t.Run("File", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Dapr = &DaprOptions{Components: "dapr/pubsub.yaml"}
+		writeDaprComponent(t, filepath.Join(serviceConfig.Path(), "dapr"), "pubsub.yaml")
+
+		components, err := loadDaprComponents(serviceConfig, func(string) string { return "" })
+		require.NoError(t, err)
+		require.Len(t, components, 1)
+	})
+
+	t.Run("MissingType", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Dapr = &DaprOptions{Components: "components"}
+
+		componentsDir := filepath.Join(serviceConfig.Path(), "components")
+		require.NoError(t, os.MkdirAll(componentsDir, osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(componentsDir, "pubsub.yaml"),
+			[]byte("apiVersion: dapr.io/v1alpha1\nkind: Component\nmetadata:\n  name: pubsub\n"),
+			osutil.PermissionFile,
+		))
+
+		_, err := loadDaprComponents(serviceConfig, func(string) string { return "" })
+		require.ErrorContains(t, err, "must set 'metadata.name' and 'spec.type'")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Dapr = &DaprOptions{Components: "components"}
+		require.NoError(t, os.MkdirAll(filepath.Join(serviceConfig.Path(), "components"), osutil.PermissionDirectory))
+
+		_, err := loadDaprComponents(serviceConfig, func(string) string { return "" })
+		require.ErrorContains(t, err, "no Dapr components found")
+	})
+}
+
+func Test_ContainerAppDaprComponents(t *testing.T) {
+	component := &daprComponent{}
+	component.Metadata.Name = "statestore"
+	component.Spec.Type = "state.azure.cosmosdb"
+	component.Spec.Metadata = []daprComponentMetadata{
+		{Name: "url", Value: "https://cosmos-01.documents.azure.com"},
+		{Name: "masterKey", SecretKeyRef: &daprSecretKeyRef{Name: "cosmos-key", Key: "cosmos-key"}},
+	}
+
+	_, err := containerAppDaprComponents([]*daprComponent{component})
+	require.ErrorContains(t, err, "component 'statestore' references secret 'cosmos-key' without setting 'auth.secretStore'")
+
+	component.Auth.SecretStore = "secretstore"
+	converted, err := containerAppDaprComponents([]*daprComponent{component})
+	require.NoError(t, err)
+	require.Equal(t, []*containerapps.ContainerAppDaprComponent{
+		{
+			Name:          "statestore",
+			ComponentType: "state.azure.cosmosdb",
+			Version:       "v1",
+			Metadata: []containerapps.ContainerAppDaprMetadata{
+				{Name: "url", Value: "https://cosmos-01.documents.azure.com"},
+				{Name: "masterKey", SecretRef: "cosmos-key"},
+			},
+			SecretStoreComponent: "secretstore",
+		},
+	}, converted)
+}
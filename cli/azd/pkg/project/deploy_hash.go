@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+	"gopkg.in/yaml.v3"
+)
+
+// The service property holding the hash of the source of the last successful deployment of the service,
+// ex) SERVICE_API_DEPLOY_HASH
+const deployHashServiceProperty = "DEPLOY_HASH"
+
+// ServiceDeployHash returns the hash of what a deployment of the service is made from: the configuration of the
+// service, the files of the service and, for services deployed as containers, the build context and the Dockerfile
+// of the image. Files ignored by the .gitignore or .dockerignore file of the service are excluded, as are the
+// dependencies and build outputs of the service, ex) node_modules
+// Inputs outside of the service, ex) the values of the environment the service reads at runtime, are not detected.
+func ServiceDeployHash(serviceConfig *ServiceConfig, env *environment.Environment) (string, error) {
+	h := sha256.New()
+
+	config, err := yaml.Marshal(serviceConfig)
+	if err != nil {
+		return "", fmt.Errorf("marshalling service '%s': %w", serviceConfig.Name, err)
+	}
+
+	image, err := serviceConfig.Image.Envsubst(env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("substituting environment variables in image: %w", err)
+	}
+
+	writeHashEntry(h, string(config))
+	writeHashEntry(h, string(serviceConfig.Host))
+	writeHashEntry(h, image)
+
+	// The build context of an image can be outside of the service, ex) the root of a monorepo
+	if serviceConfig.Host.RequiresContainer() && image == "" {
+		sourceHash, err := buildSourceHash(serviceConfig, getDockerOptionsWithDefaults(serviceConfig.Docker), "")
+		if err != nil {
+			return "", err
+		}
+
+		writeHashEntry(h, sourceHash)
+	}
+
+	if err := hashServiceFiles(h, serviceConfig); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// IsServiceUnchanged returns whether the hash is the hash recorded by the last successful deployment of the service
+func IsServiceUnchanged(serviceConfig *ServiceConfig, env *environment.Environment, deployHash string) bool {
+	return deployHash != "" && env.GetServiceProperty(serviceConfig.Name, deployHashProperty(serviceConfig)) == deployHash
+}
+
+// SetServiceDeployHash records the hash of the source of a successful deployment of the service in the environment
+func SetServiceDeployHash(serviceConfig *ServiceConfig, env *environment.Environment, deployHash string) {
+	env.SetServiceProperty(serviceConfig.Name, deployHashProperty(serviceConfig), deployHash)
+}
+
+// deployHashProperty returns the service property holding the deploy hash of the service, qualified by the host of
+// the service when the service is deployed to more than one host, ex) DEPLOY_HASH_CONTAINERAPP
+func deployHashProperty(serviceConfig *ServiceConfig) string {
+	return hostServiceProperty(serviceConfig, deployHashServiceProperty)
+}
+
+// hostServiceProperty returns the service property qualified by the host of the service when the service is deployed
+// to more than one host, so that each host records its own value
+func hostServiceProperty(serviceConfig *ServiceConfig, property string) string {
+	if serviceConfig.targetName == "" {
+		return property
+	}
+
+	host := strings.ToUpper(strings.ReplaceAll(string(serviceConfig.Host), ".", "_"))
+	return fmt.Sprintf("%s_%s", property, host)
+}
+
+// hashServiceFiles writes the paths and content of the files of the service to the hash in lexical order
+func hashServiceFiles(h hash.Hash, serviceConfig *ServiceConfig) error {
+	root := serviceConfig.Path()
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+
+	// The project of a service can be a file, ex) a compose file
+	if !info.IsDir() {
+		return hashFile(h, root)
+	}
+
+	ignores := []string{}
+	for _, ignoreFile := range []string{".gitignore", ".dockerignore"} {
+		patterns, err := readIgnoreFile(filepath.Join(root, ignoreFile))
+		if err != nil {
+			return err
+		}
+
+		ignores = append(ignores, patterns...)
+	}
+
+	matcher, err := patternmatcher.New(ignores)
+	if err != nil {
+		return fmt.Errorf("parsing the ignore files of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	excludedDirs := []string{".git", ".azure", "node_modules", "__pycache__"}
+	switch serviceConfig.Language {
+	case ServiceLanguageDotNet, ServiceLanguageCsharp, ServiceLanguageFsharp:
+		excludedDirs = append(excludedDirs, "bin", "obj")
+	case ServiceLanguageJava:
+		excludedDirs = append(excludedDirs, "target")
+	}
+
+	outputPath := ""
+	if serviceConfig.OutputPath != "" && !filepath.IsAbs(serviceConfig.OutputPath) {
+		outputPath = filepath.ToSlash(filepath.Clean(serviceConfig.OutputPath))
+	}
+
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil || relativePath == "." {
+			return err
+		}
+
+		relativePath = filepath.ToSlash(relativePath)
+		ignore, err := matcher.MatchesOrParentMatches(relativePath)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if ignore || relativePath == outputPath || slices.Contains(excludedDirs, d.Name()) || isPythonVirtualEnv(path) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if ignore {
+			return nil
+		}
+
+		writeHashEntry(h, relativePath)
+		return hashFile(h, path)
+	})
+}
+
+// readIgnoreFile returns the patterns of the ignore file, none when the file doesn't exist
+func readIgnoreFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ignorefile.ReadAll(f)
+}
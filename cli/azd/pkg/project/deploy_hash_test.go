@@ -0,0 +1,95 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceDeployHash(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = t.TempDir()
+	writeFile := func(name string, content string) {
+		path := filepath.Join(serviceConfig.Path(), name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+	}
+
+	writeFile(".gitignore", "dist\n*.log")
+	writeFile("src/index.js", "console.log('hello')")
+	writeFile("package.json", "{}")
+
+	env := environment.NewWithValues("test", nil)
+	deployHash := func() string {
+		hash, err := ServiceDeployHash(serviceConfig, env)
+		require.NoError(t, err)
+		return hash
+	}
+
+	hash := deployHash()
+	require.Len(t, hash, 64)
+
+	// Dependencies, build outputs and ignored files do not change the hash
+	writeFile("node_modules/express/index.js", "module.exports = {}")
+	writeFile("dist/index.js", "console.log('hello')")
+	writeFile("debug.log", "debug")
+	require.Equal(t, hash, deployHash())
+
+	// Changes of the source and of the configuration of the service change the hash
+	writeFile("src/index.js", "console.log('hello world')")
+	changedHash := deployHash()
+	require.NotEqual(t, hash, changedHash)
+
+	serviceConfig.OutputPath = "build"
+	require.NotEqual(t, changedHash, deployHash())
+}
+
+func Test_ServiceDeployHash_Container(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = t.TempDir()
+	serviceConfig.Docker.Context = ".."
+
+	// The build context of the image is the parent of the service
+	require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(serviceConfig.Path(), "Dockerfile"), []byte("FROM node:20"),
+		osutil.PermissionFile))
+	sharedFile := filepath.Join(serviceConfig.Path(), "..", "shared.js")
+	require.NoError(t, os.WriteFile(sharedFile, []byte("module.exports = {}"), osutil.PermissionFile))
+
+	env := environment.NewWithValues("test", nil)
+	hash, err := ServiceDeployHash(serviceConfig, env)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(sharedFile, []byte("module.exports = { version: 2 }"), osutil.PermissionFile))
+	changedHash, err := ServiceDeployHash(serviceConfig, env)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, changedHash)
+}
+
+func Test_IsServiceUnchanged(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	env := environment.NewWithValues("test", nil)
+
+	require.False(t, IsServiceUnchanged(serviceConfig, env, "HASH"))
+
+	SetServiceDeployHash(serviceConfig, env, "HASH")
+	require.Equal(t, "HASH", env.Getenv("SERVICE_API_DEPLOY_HASH"))
+	require.True(t, IsServiceUnchanged(serviceConfig, env, "HASH"))
+	require.False(t, IsServiceUnchanged(serviceConfig, env, "OTHER_HASH"))
+	require.False(t, IsServiceUnchanged(serviceConfig, env, ""))
+
+	// Services deployed to more than one host record a hash per host
+	serviceConfig.Host = ContainerAppJobTarget
+	serviceConfig.targetName = "api (containerapp.job)"
+	require.False(t, IsServiceUnchanged(serviceConfig, env, "HASH"))
+
+	SetServiceDeployHash(serviceConfig, env, "HASH")
+	require.Equal(t, "HASH", env.Getenv("SERVICE_API_DEPLOY_HASH_CONTAINERAPP_JOB"))
+}
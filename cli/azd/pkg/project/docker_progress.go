@@ -0,0 +1,94 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+)
+
+type DockerProgressMode string
+
+const (
+	// Reports the step of the build and the layers pushed, along with a preview of the output of the build
+	DockerProgressAuto DockerProgressMode = "auto"
+	// Reports the step of the build and the layers pushed without displaying the output of docker
+	DockerProgressQuiet DockerProgressMode = "quiet"
+	// Displays a longer preview of the output of the build and of the push, ex) to troubleshoot a slow build
+	DockerProgressVerbose DockerProgressMode = "verbose"
+)
+
+// The number of lines of the docker output previewed by default and in the verbose mode
+const (
+	dockerOutputLineCount        = 8
+	dockerVerboseOutputLineCount = 30
+)
+
+// ParseDockerProgressMode returns the progress mode of the value, ex) of the '--build-progress' flag
+func ParseDockerProgressMode(value string) (DockerProgressMode, error) {
+	switch mode := DockerProgressMode(value); mode {
+	case DockerProgressAuto, DockerProgressQuiet, DockerProgressVerbose:
+		return mode, nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported docker progress '%s', supported values are '%s', '%s' and '%s'",
+			value, DockerProgressAuto, DockerProgressQuiet, DockerProgressVerbose,
+		)
+	}
+}
+
+// validateDockerProgress returns an error when the progress of the service is configured with an unsupported mode
+func validateDockerProgress(serviceConfig *ServiceConfig) error {
+	if serviceConfig.Docker.Progress == "" {
+		return nil
+	}
+
+	_, err := ParseDockerProgressMode(string(serviceConfig.Docker.Progress))
+	return err
+}
+
+// showDockerOutput displays a preview of the output of docker as configured by the progress of the service. The output
+// of pushes is only previewed in the verbose mode. Returns nil when the output is not displayed.
+func showDockerOutput(
+	ctx context.Context,
+	console input.Console,
+	serviceConfig *ServiceConfig,
+	title string,
+	push bool,
+) io.Writer {
+	lineCount := dockerOutputLineCount
+	switch serviceConfig.Docker.Progress {
+	case DockerProgressQuiet:
+		return nil
+	case DockerProgressVerbose:
+		lineCount = dockerVerboseOutputLineCount
+	default:
+		if push {
+			return nil
+		}
+	}
+
+	return console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: lineCount,
+			Title:        title,
+		})
+}
+
+// stopDockerOutput stops the preview of the docker output displayed by showDockerOutput
+func stopDockerOutput(ctx context.Context, console input.Console, previewer io.Writer) {
+	if previewer != nil {
+		console.StopPreviewer(ctx, false)
+	}
+}
+
+// reportDockerProgress returns a function reporting the progress of docker along with the message of the current
+// operation, ex) Building Docker image, build 2/5: RUN npm ci
+func reportDockerProgress(message string, progress *async.Progress[ServiceProgress]) func(string) {
+	return func(detail string) {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("%s, %s", message, detail)))
+	}
+}
@@ -0,0 +1,46 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShowDockerOutput(t *testing.T) {
+	tests := []struct {
+		progress    DockerProgressMode
+		showsBuild  bool
+		showsPushes bool
+	}{
+		{progress: "", showsBuild: true},
+		{progress: DockerProgressAuto, showsBuild: true},
+		{progress: DockerProgressQuiet},
+		{progress: DockerProgressVerbose, showsBuild: true, showsPushes: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.progress), func(t *testing.T) {
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Progress = tt.progress
+			console := mockinput.NewMockConsole()
+
+			build := showDockerOutput(context.Background(), console, serviceConfig, "Docker Output", false)
+			push := showDockerOutput(context.Background(), console, serviceConfig, "Docker Output", true)
+			require.Equal(t, tt.showsBuild, build != nil)
+			require.Equal(t, tt.showsPushes, push != nil)
+		})
+	}
+}
+
+func Test_ValidateDockerProgress(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	require.NoError(t, validateDockerProgress(serviceConfig))
+
+	serviceConfig.Docker.Progress = DockerProgressVerbose
+	require.NoError(t, validateDockerProgress(serviceConfig))
+
+	serviceConfig.Docker.Progress = "plain"
+	require.ErrorContains(t, validateDockerProgress(serviceConfig), "unsupported docker progress 'plain'")
+}
@@ -0,0 +1,132 @@
+package project
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// validateDockerfile returns an error when the Dockerfile, the build context or the target stage of the service are
+// misconfigured, ex) a target stage that is not defined in the Dockerfile, before the image is built
+func validateDockerfile(serviceConfig *ServiceConfig) error {
+	options := serviceConfig.Docker
+	if options.Dockerfile != "" {
+		if options.Path != "" {
+			return errors.New("docker.dockerfile and docker.path cannot be set together, the image is built from " +
+				"either the inline Dockerfile or the Dockerfile at the path")
+		}
+
+		if options.Buildpack || options.SourceBuilder != "" {
+			return errors.New("docker.dockerfile is not supported with buildpacks or docker.sourceBuilder")
+		}
+	}
+
+	// Services without source, ex) of an external image, and services built from source without a Dockerfile have
+	// nothing to validate
+	if serviceConfig.RelativePath == "" || options.Buildpack || options.SourceBuilder != "" {
+		return nil
+	}
+
+	dockerOptions := getDockerOptionsWithDefaults(options)
+	content := options.Dockerfile
+	if content == "" {
+		path := dockerOptions.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(serviceConfig.Path(), path)
+		}
+
+		dockerfile, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) && options.Path == "" {
+			// Services without a Dockerfile are built from source with buildpacks
+			return nil
+		} else if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("the Dockerfile '%s' of service '%s' does not exist", path, serviceConfig.Name)
+		} else if err != nil {
+			return fmt.Errorf("failed reading the Dockerfile of service '%s', %w", serviceConfig.Name, err)
+		}
+
+		content = string(dockerfile)
+	}
+
+	contextPath := dockerOptions.Context
+	if !filepath.IsAbs(contextPath) {
+		contextPath = filepath.Join(serviceConfig.Path(), contextPath)
+	}
+
+	info, err := os.Stat(contextPath)
+	if err != nil {
+		return fmt.Errorf("the docker context '%s' of service '%s' does not exist: %w", contextPath, serviceConfig.Name, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("the docker context '%s' of service '%s' is not a directory", contextPath, serviceConfig.Name)
+	}
+
+	if options.Target == "" {
+		return nil
+	}
+
+	stages := dockerfileStages(content)
+	if !slices.Contains(stages, strings.ToLower(options.Target)) {
+		if len(stages) == 0 {
+			return fmt.Errorf(
+				"the target stage '%s' is not defined in the Dockerfile of service '%s', which has no named stages",
+				options.Target, serviceConfig.Name,
+			)
+		}
+
+		return fmt.Errorf(
+			"the target stage '%s' is not defined in the Dockerfile of service '%s', the stages are '%s'",
+			options.Target, serviceConfig.Name, strings.Join(stages, "', '"),
+		)
+	}
+
+	return nil
+}
+
+// dockerfileStages returns the lowercase names of the stages of the Dockerfile, ex) build for 'FROM node:20 AS build'
+func dockerfileStages(content string) []string {
+	stages := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		// FROM [--platform=<platform>] <image> [AS <name>]
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+
+		if strings.EqualFold(fields[len(fields)-2], "AS") {
+			stages = append(stages, strings.ToLower(fields[len(fields)-1]))
+		}
+	}
+
+	return stages
+}
+
+// writeInlineDockerfile writes the inline Dockerfile of the docker options to a temporary file the image is built
+// from, and sets the path of the options to it. The returned function removes the file.
+func writeInlineDockerfile(dockerOptions *DockerProjectOptions) (func(), error) {
+	if dockerOptions.Dockerfile == "" {
+		return func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "azd-dockerfile")
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the inline Dockerfile, %w", err)
+	}
+
+	path := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(path, []byte(dockerOptions.Dockerfile), osutil.PermissionFile); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed creating the inline Dockerfile, %w", err)
+	}
+
+	dockerOptions.Path = path
+	return func() { os.RemoveAll(dir) }, nil
+}
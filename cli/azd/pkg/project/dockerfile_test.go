@@ -0,0 +1,144 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateDockerfile(t *testing.T) {
+	tests := []struct {
+		name          string
+		dockerOptions DockerProjectOptions
+		expectedError string
+	}{
+		{
+			name:          "Defaults",
+			dockerOptions: DockerProjectOptions{},
+		},
+		{
+			name:          "Target",
+			dockerOptions: DockerProjectOptions{Target: "Runtime"},
+		},
+		{
+			name:          "MissingTarget",
+			dockerOptions: DockerProjectOptions{Target: "test"},
+			expectedError: "the target stage 'test' is not defined in the Dockerfile of service 'api', " +
+				"the stages are 'build', 'runtime'",
+		},
+		{
+			name:          "MissingDockerfile",
+			dockerOptions: DockerProjectOptions{Path: "./Dockerfile.prod"},
+			expectedError: "Dockerfile.prod' of service 'api' does not exist",
+		},
+		{
+			name:          "MissingContext",
+			dockerOptions: DockerProjectOptions{Context: "../web"},
+			expectedError: "the docker context",
+		},
+		{
+			name:          "ContextNotDirectory",
+			dockerOptions: DockerProjectOptions{Context: "./Dockerfile"},
+			expectedError: "is not a directory",
+		},
+		{
+			name:          "InlineDockerfile",
+			dockerOptions: DockerProjectOptions{Dockerfile: "FROM nginx AS web\n", Target: "web"},
+		},
+		{
+			name:          "InlineDockerfileWithoutStages",
+			dockerOptions: DockerProjectOptions{Dockerfile: "FROM nginx\n", Target: "web"},
+			expectedError: "which has no named stages",
+		},
+		{
+			name:          "InlineDockerfileAndPath",
+			dockerOptions: DockerProjectOptions{Dockerfile: "FROM nginx\n", Path: "./Dockerfile"},
+			expectedError: "docker.dockerfile and docker.path cannot be set together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageDocker)
+			serviceConfig.Project.Path = t.TempDir()
+			serviceConfig.Docker = tt.dockerOptions
+
+			require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+			err := os.WriteFile(
+				filepath.Join(serviceConfig.Path(), "Dockerfile"),
+				[]byte("FROM node:20 AS build\nRUN npm ci\n\nfrom --platform=$BUILDPLATFORM nginx as runtime\n"),
+				osutil.PermissionFile,
+			)
+			require.NoError(t, err)
+
+			err = validateDockerfile(serviceConfig)
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func Test_DockerProject_Build_InlineDockerfile(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var dockerfilePath, dockerfile string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		dockerfilePath = args.Args[2]
+		content, err := os.ReadFile(dockerfilePath)
+		require.NoError(t, err)
+		dockerfile = string(content)
+
+		err = os.WriteFile(args.Args[len(args.Args)-1], []byte("IMAGE_ID"), osutil.PermissionFile)
+		require.NoError(t, err)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	env := environment.New("test")
+	dockerCli := docker.NewCli(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageDocker)
+	serviceConfig.Project.Path = t.TempDir()
+	serviceConfig.Docker = DockerProjectOptions{
+		Dockerfile: "FROM nginx\nCOPY . /usr/share/nginx/html\n",
+	}
+	require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+
+	dockerProject := NewDockerProject(
+		env,
+		dockerCli,
+		NewContainerHelper(
+			env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, nil, dockerCli, mockContext.Console,
+			cloud.AzurePublic(), nil, nil, nil, nil, nil,
+		),
+		mockinput.NewMockConsole(),
+		mockContext.AlphaFeaturesManager,
+		mockContext.CommandRunner)
+
+	result, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+		return dockerProject.Build(*mockContext.Context, serviceConfig, nil, progress)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "IMAGE_ID", result.BuildOutputPath)
+
+	// The image is built from the inline Dockerfile, which is removed after the build
+	require.Equal(t, serviceConfig.Docker.Dockerfile, dockerfile)
+	require.NoFileExists(t, dockerfilePath)
+}
@@ -34,15 +34,59 @@ import (
 )
 
 type DockerProjectOptions struct {
-	Path        string                  `yaml:"path,omitempty"        json:"path,omitempty"`
-	Context     string                  `yaml:"context,omitempty"     json:"context,omitempty"`
-	Platform    string                  `yaml:"platform,omitempty"    json:"platform,omitempty"`
+	Path     string `yaml:"path,omitempty"        json:"path,omitempty"`
+	Context  string `yaml:"context,omitempty"     json:"context,omitempty"`
+	Platform string `yaml:"platform,omitempty"    json:"platform,omitempty"`
+	// The platforms of a multi-platform image, ex) linux/amd64 and linux/arm64, built with buildx and pushed
+	// as a manifest list on deploy. Takes precedence over the platform
+	Platforms   []string                `yaml:"platforms,omitempty" json:"platforms,omitempty"`
 	Target      string                  `yaml:"target,omitempty"      json:"target,omitempty"`
 	Registry    osutil.ExpandableString `yaml:"registry,omitempty"    json:"registry,omitempty"`
 	Image       osutil.ExpandableString `yaml:"image,omitempty"       json:"image,omitempty"`
 	Tag         osutil.ExpandableString `yaml:"tag,omitempty"         json:"tag,omitempty"`
 	RemoteBuild bool                    `yaml:"remoteBuild,omitempty" json:"remoteBuild,omitempty"`
 	BuildArgs   []string                `yaml:"buildArgs,omitempty"   json:"buildArgs,omitempty"`
+	// The content of the Dockerfile the image is built from, ex) a short Dockerfile written inline in azure.yaml
+	// Cannot be set along with the path
+	Dockerfile string `yaml:"dockerfile,omitempty" json:"dockerfile,omitempty"`
+	// When enabled, the image is built from source with Cloud Native Buildpacks even when a Dockerfile exists
+	Buildpack bool `yaml:"buildpack,omitempty" json:"buildpack,omitempty"`
+	// The buildpacks builder image, ex) paketobuildpacks/builder-jammy-base. Defaults to the Oryx builder image
+	Builder osutil.ExpandableString `yaml:"builder,omitempty" json:"builder,omitempty"`
+	// The template the image name and tag are rendered from, ex) {project}/{service}:{env}-{gitsha}. Takes precedence
+	// over the image template of the project, while the image and tag take precedence over the template
+	ImageTemplate osutil.ExpandableString `yaml:"imageTemplate,omitempty" json:"imageTemplate,omitempty"`
+	// Signs the digest of the pushed image, ex) with notation and an Azure Key Vault key
+	Signing *ImageSigningOptions `yaml:"signing,omitempty" json:"signing,omitempty"`
+	// Generates the software bill of materials of the image when it is packaged, ex) in the spdx-json format
+	Sbom *SbomOptions `yaml:"sbom,omitempty" json:"sbom,omitempty"`
+	// Attaches a SLSA provenance attestation of the build, ex) the git commit and build parameters, to the pushed image
+	Provenance bool `yaml:"provenance,omitempty" json:"provenance,omitempty"`
+	// Scans the image for vulnerabilities when it is packaged, failing on those at or above the severity threshold
+	Scan *ImageScanOptions `yaml:"scan,omitempty" json:"scan,omitempty"`
+	// Imports and exports the layers of the build from a cache repository in the container registry, ex) todo/api-cache
+	Cache *BuildCacheOptions `yaml:"cache,omitempty" json:"cache,omitempty"`
+	// The credentials the image is pushed to and pulled from the container registry with, ex) from a 'docker login'
+	Credentials *RegistryCredentialsOptions `yaml:"credentials,omitempty" json:"credentials,omitempty"`
+	// Deploys the image by the digest it was pushed with, ex) contoso.azurecr.io/todo/api@sha256:4f2d..., instead of
+	// its mutable tag
+	PinDigest bool `yaml:"pinDigest,omitempty" json:"pinDigest,omitempty"`
+	// Builds the image from source without a Dockerfile, ex) with ko for Go or jib for Java services
+	SourceBuilder SourceBuilderKind `yaml:"sourceBuilder,omitempty" json:"sourceBuilder,omitempty"`
+	// Reuses the image pushed by the last deployment when the build context is unchanged since, instead of rebuilding
+	// and pushing the image
+	SkipUnchanged bool `yaml:"skipUnchanged,omitempty" json:"skipUnchanged,omitempty"`
+	// The BuildKit secrets mounted into the build, ex) a token of a private package feed, with values from the azd
+	// environment or Key Vault
+	Secrets []DockerBuildSecret `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	// Deletes the images of earlier deployments from the repository of the service after each deployment, ex) keeping
+	// the images of the last 10 deployments
+	Retention *ImageRetentionOptions `yaml:"retention,omitempty" json:"retention,omitempty"`
+	// The progress of the build and push reported while deploying, ex) quiet or verbose. Defaults to auto
+	Progress DockerProgressMode `yaml:"progress,omitempty" json:"progress,omitempty"`
+	// Pushes the image to an ACR connected registry, ex) of an Arc enabled AKS cluster on the edge, instead of its
+	// parent registry in Azure
+	ConnectedRegistry *ConnectedRegistryOptions `yaml:"connectedRegistry,omitempty" json:"connectedRegistry,omitempty"`
 	// not supported from azure.yaml directly yet. Adding it for Aspire to use it, initially.
 	// Aspire would pass the secret keys, which are env vars that azd will set just to run docker build.
 	BuildSecrets []string `yaml:"-"                     json:"-"`
@@ -52,9 +96,17 @@ type DockerProjectOptions struct {
 type dockerBuildResult struct {
 	ImageId   string `json:"imageId"`
 	ImageName string `json:"imageName"`
+	// The hash of the source the image is built from, set when unchanged builds are skipped
+	SourceHash string `json:"sourceHash,omitempty"`
+	// The image pushed by the last deployment from the same source, set when the build is skipped
+	ReusedImage string `json:"reusedImage,omitempty"`
 }
 
 func (dbr *dockerBuildResult) ToString(currentIndentation string) string {
+	if dbr.ReusedImage != "" {
+		return fmt.Sprintf("%s- Reused Image: %s", currentIndentation, output.WithLinkFormat(dbr.ReusedImage))
+	}
+
 	lines := []string{
 		fmt.Sprintf("%s- Image ID: %s", currentIndentation, output.WithLinkFormat(dbr.ImageId)),
 		fmt.Sprintf("%s- Image Name: %s", currentIndentation, output.WithLinkFormat(dbr.ImageName)),
@@ -74,10 +126,25 @@ type dockerPackageResult struct {
 	SourceImage string `json:"sourceImage"`
 	// The target image with tag that is used for publishing and deployment when targeting a container registry
 	TargetImage string `json:"targetImage"`
+	// The hash of the source the image is built from, set when unchanged builds are skipped
+	SourceHash string `json:"sourceHash,omitempty"`
+	// The image pushed by the last deployment from the same source, deployed instead of pushing a new image
+	ReusedImage string `json:"reusedImage,omitempty"`
+	// The environment the reused image was promoted from, ex) staging
+	PromotedFrom string `json:"promotedFrom,omitempty"`
 }
 
 func (dpr *dockerPackageResult) ToString(currentIndentation string) string {
 	builder := strings.Builder{}
+	if dpr.ReusedImage != "" {
+		builder.WriteString(
+			fmt.Sprintf("%s- Reused Image: %s\n", currentIndentation, output.WithLinkFormat(dpr.ReusedImage)))
+	}
+
+	if dpr.PromotedFrom != "" {
+		builder.WriteString(fmt.Sprintf("%s- Promoted From: %s\n", currentIndentation, dpr.PromotedFrom))
+	}
+
 	if dpr.ImageHash != "" {
 		builder.WriteString(fmt.Sprintf("%s- Image Hash: %s\n", currentIndentation, output.WithLinkFormat(dpr.ImageHash)))
 	}
@@ -152,6 +219,27 @@ func NewDockerProjectAsFrameworkService(
 	return NewDockerProject(env, docker, containerHelper, console, alphaFeatureManager, commandRunner)
 }
 
+// resolveDockerParameters evaluates the parameter references of the docker build args, ex) {infra.parameters.name},
+// against the configuration of the environment
+func resolveDockerParameters(env *environment.Environment, source []string) ([]string, error) {
+	result := make([]string, len(source))
+	for i, arg := range source {
+		evaluatedString, err := apphost.EvalString(arg, func(match string) (string, error) {
+			path := match
+			value, has := env.Config.GetString(path)
+			if !has {
+				return "", fmt.Errorf("parameter %s not found", path)
+			}
+			return value, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		result[i] = evaluatedString
+	}
+	return result, nil
+}
+
 func (p *dockerProject) Requirements() FrameworkRequirements {
 	return FrameworkRequirements{
 		Package: FrameworkPackageRequirements{
@@ -168,7 +256,20 @@ func (p *dockerProject) RequiredExternalTools(_ context.Context, sc *ServiceConf
 		return []tools.ExternalTool{}
 	}
 
-	return []tools.ExternalTool{p.docker}
+	requiredTools := []tools.ExternalTool{p.docker}
+	if scanTool := p.scanTool(sc); scanTool != nil {
+		requiredTools = append(requiredTools, scanTool)
+	}
+
+	if sbomTool := p.sbomTool(sc); sbomTool != nil {
+		requiredTools = append(requiredTools, sbomTool)
+	}
+
+	if sourceBuilderTool := p.sourceBuilderTool(sc); sourceBuilderTool != nil {
+		requiredTools = append(requiredTools, sourceBuilderTool)
+	}
+
+	return requiredTools
 }
 
 // Initializes the docker project
@@ -199,29 +300,51 @@ func (p *dockerProject) Build(
 	restoreOutput *ServiceRestoreResult,
 	progress *async.Progress[ServiceProgress],
 ) (*ServiceBuildResult, error) {
+	if err := validateBuildCache(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateSourceBuilder(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateSkipUnchanged(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateBuildSecrets(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateDockerProgress(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateDockerfile(serviceConfig); err != nil {
+		return nil, err
+	}
+
 	if serviceConfig.Docker.RemoteBuild {
 		return &ServiceBuildResult{Restore: restoreOutput}, nil
 	}
 
+	// Images for multiple platforms cannot be loaded into the local image store,
+	// so they are built and pushed to the registry in a single step on deploy
+	if serviceConfig.Docker.IsMultiPlatform() {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf(
+			"Deferring multi-platform build (%s) to deploy", strings.Join(serviceConfig.Docker.Platforms, ", "))))
+		return &ServiceBuildResult{Restore: restoreOutput}, nil
+	}
+
 	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	removeDockerfile, err := writeInlineDockerfile(&dockerOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer removeDockerfile()
 
 	resolveParameters := func(source []string) ([]string, error) {
-		result := make([]string, len(source))
-		for i, arg := range source {
-			evaluatedString, err := apphost.EvalString(arg, func(match string) (string, error) {
-				path := match
-				value, has := p.env.Config.GetString(path)
-				if !has {
-					return "", fmt.Errorf("parameter %s not found", path)
-				}
-				return value, nil
-			})
-			if err != nil {
-				return nil, err
-			}
-			result[i] = evaluatedString
-		}
-		return result, nil
+		return resolveDockerParameters(p.env, source)
 	}
 	// resolve parameters for build args and secrets
 	resolvedBuildArgs, err := resolveParameters(dockerOptions.BuildArgs)
@@ -271,11 +394,45 @@ func (p *dockerProject) Build(
 		path = filepath.Join(serviceConfig.Path(), path)
 	}
 
+	var sourceHash string
+	if dockerOptions.SkipUnchanged {
+		var reusedImage string
+		sourceHash, reusedImage, err = p.unchangedImage(ctx, serviceConfig, dockerOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		if reusedImage != "" {
+			log.Printf("skipping build of %s, the source is unchanged since image %s was pushed", serviceConfig.Name,
+				reusedImage)
+			progress.SetProgress(NewServiceProgress("Skipping build, the source is unchanged since the last deployment"))
+			return &ServiceBuildResult{
+				Restore: restoreOutput,
+				Details: &dockerBuildResult{
+					ImageName:   imageName,
+					SourceHash:  sourceHash,
+					ReusedImage: reusedImage,
+				},
+			}, nil
+		}
+	}
+
+	if dockerOptions.SourceBuilder != "" {
+		res, err := p.sourceBuild(ctx, serviceConfig, dockerOptions, imageName, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Restore = restoreOutput
+		res.Details.(*dockerBuildResult).SourceHash = sourceHash
+		return res, nil
+	}
+
 	_, err = os.Stat(path)
-	if errors.Is(err, os.ErrNotExist) && serviceConfig.Docker.Path == "" {
+	if dockerOptions.Buildpack || (errors.Is(err, os.ErrNotExist) && serviceConfig.Docker.Path == "") {
 		// Build the container from source when:
-		// 1. No Dockerfile path is specified, and
-		// 2. <service directory>/Dockerfile doesn't exist
+		// 1. Buildpacks are enabled, or
+		// 2. No Dockerfile path is specified, and <service directory>/Dockerfile doesn't exist
 		progress.SetProgress(NewServiceProgress("Building Docker image from source"))
 		res, err := p.packBuild(ctx, serviceConfig, dockerOptions, imageName)
 		if err != nil {
@@ -283,17 +440,28 @@ func (p *dockerProject) Build(
 		}
 
 		res.Restore = restoreOutput
+		res.Details.(*dockerBuildResult).SourceHash = sourceHash
 		return res, nil
 	}
 
+	// the values of the secrets are read only when the image is built and are not evaluated as parameters
+	buildSecrets, secretsEnv, err := p.containerHelper.buildSecretsAndEnv(ctx, serviceConfig, dockerOptions.BuildArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerOptions.BuildSecrets = append(dockerOptions.BuildSecrets, buildSecrets...)
+	dockerOptions.BuildEnv = append(dockerOptions.BuildEnv, secretsEnv...)
+
+	cache, err := p.containerHelper.prepareBuildCache(ctx, serviceConfig, progress)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build the container
 	progress.SetProgress(NewServiceProgress("Building Docker image"))
-	previewerWriter := p.console.ShowPreviewer(ctx,
-		&input.ShowPreviewerOptions{
-			Prefix:       "  ",
-			MaxLineCount: 8,
-			Title:        "Docker Output",
-		})
+	previewerWriter := showDockerOutput(ctx, p.console, serviceConfig, "Docker Output", false)
+	cacheStats := docker.NewBuildCacheStats(previewerWriter)
 	imageId, err := p.docker.Build(
 		ctx,
 		serviceConfig.Path(),
@@ -305,20 +473,26 @@ func (p *dockerProject) Build(
 		dockerOptions.BuildArgs,
 		dockerOptions.BuildSecrets,
 		dockerOptions.BuildEnv,
-		previewerWriter,
+		cache,
+		docker.NewBuildProgress(cacheStats, reportDockerProgress("Building Docker image", progress)),
 	)
-	p.console.StopPreviewer(ctx, false)
+	stopDockerOutput(ctx, p.console, previewerWriter)
 	if err != nil {
 		return nil, fmt.Errorf("building container: %s at %s: %w", serviceConfig.Name, dockerOptions.Context, err)
 	}
 
+	if cache != nil {
+		buildCacheProgress(serviceConfig, cacheStats, progress)
+	}
+
 	log.Printf("built image %s for %s", imageId, serviceConfig.Name)
 	return &ServiceBuildResult{
 		Restore:         restoreOutput,
 		BuildOutputPath: imageId,
 		Details: &dockerBuildResult{
-			ImageId:   imageId,
-			ImageName: imageName,
+			ImageId:    imageId,
+			ImageName:  imageName,
+			SourceHash: sourceHash,
 		},
 	}, nil
 }
@@ -329,18 +503,44 @@ func (p *dockerProject) Package(
 	buildOutput *ServiceBuildResult,
 	progress *async.Progress[ServiceProgress],
 ) (*ServicePackageResult, error) {
-	if serviceConfig.Docker.RemoteBuild {
+	if err := validateScan(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateSbom(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	if serviceConfig.Docker.RemoteBuild || serviceConfig.Docker.IsMultiPlatform() {
 		return &ServicePackageResult{Build: buildOutput}, nil
 	}
 
 	var imageId string
+	var sourceHash string
 
 	if buildOutput != nil {
 		imageId = buildOutput.BuildOutputPath
+
+		if buildDetails, ok := buildOutput.Details.(*dockerBuildResult); ok && buildDetails != nil {
+			sourceHash = buildDetails.SourceHash
+
+			// The image of the last deployment is reused, so there is no image to tag, scan or attach an SBOM to
+			if buildDetails.ReusedImage != "" {
+				progress.SetProgress(NewServiceProgress("Reusing container image of the last deployment"))
+				return &ServicePackageResult{
+					Build: buildOutput,
+					Details: &dockerPackageResult{
+						SourceHash:  sourceHash,
+						ReusedImage: buildDetails.ReusedImage,
+					},
+				}, nil
+			}
+		}
 	}
 
 	packageDetails := &dockerPackageResult{
-		ImageHash: imageId,
+		ImageHash:  imageId,
+		SourceHash: sourceHash,
 	}
 
 	// If we don't have an image ID from a docker build then an external source image is being used
@@ -381,16 +581,51 @@ func (p *dockerProject) Package(
 
 	packageDetails.TargetImage = imageWithTag
 
+	// The image is scanned before it is pushed, so images with known vulnerabilities are not shipped
+	if serviceConfig.Docker.Scan != nil {
+		if err := p.scanImage(ctx, serviceConfig, imageWithTag, progress); err != nil {
+			return nil, err
+		}
+	}
+
+	var sbomPath string
+	if serviceConfig.Docker.Sbom != nil {
+		sbomPath, err = p.generateSbom(ctx, serviceConfig, imageWithTag, progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &ServicePackageResult{
 		Build:       buildOutput,
 		PackagePath: packageDetails.SourceImage,
 		Details:     packageDetails,
+		SbomPath:    sbomPath,
 	}, nil
 }
 
 // Default builder image to produce container images from source, needn't java jdk storage, use the standard bp
 const DefaultBuilderImage = "mcr.microsoft.com/oryx/builder:debian-bullseye-20240424.1"
 
+// packBuilderImage returns the buildpacks builder image configured for the service, or set by AZD_BUILDER_IMAGE,
+// along with whether the image is user defined instead of the default Oryx builder image
+func packBuilderImage(env *environment.Environment, dockerOptions DockerProjectOptions) (string, bool, error) {
+	builder, err := dockerOptions.Builder.Envsubst(env.Getenv)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to envsubst builder image: %w", err)
+	}
+
+	if builder == "" {
+		builder = os.Getenv("AZD_BUILDER_IMAGE")
+	}
+
+	if builder == "" {
+		return DefaultBuilderImage, false, nil
+	}
+
+	return builder, true, nil
+}
+
 func (p *dockerProject) packBuild(
 	ctx context.Context,
 	svc *ServiceConfig,
@@ -400,14 +635,12 @@ func (p *dockerProject) packBuild(
 	if err != nil {
 		return nil, err
 	}
-	builder := DefaultBuilderImage
+	builder, userDefinedImage, err := packBuilderImage(p.env, dockerOptions)
+	if err != nil {
+		return nil, err
+	}
 
 	environ := []string{}
-	userDefinedImage := false
-	if os.Getenv("AZD_BUILDER_IMAGE") != "" {
-		builder = os.Getenv("AZD_BUILDER_IMAGE")
-		userDefinedImage = true
-	}
 
 	if !userDefinedImage {
 		// Always default to port 80 for consistency across languages
@@ -446,12 +679,7 @@ func (p *dockerProject) packBuild(
 		}
 	}
 
-	previewer := p.console.ShowPreviewer(ctx,
-		&input.ShowPreviewerOptions{
-			Prefix:       "  ",
-			MaxLineCount: 8,
-			Title:        "Docker (pack) Output",
-		})
+	previewer := showDockerOutput(ctx, p.console, svc, "Docker (pack) Output", false)
 
 	ctx, span := tracing.Start(
 		ctx,
@@ -478,7 +706,7 @@ func (p *dockerProject) packBuild(
 		imageName,
 		environ,
 		previewer)
-	p.console.StopPreviewer(ctx, false)
+	stopDockerOutput(ctx, p.console, previewer)
 	if err != nil {
 		span.EndWithStatus(err)
 
@@ -548,7 +776,16 @@ func getEnvironForPython(ctx context.Context, svc *ServiceConfig) ([]string, err
 	return nil, nil
 }
 
+// IsMultiPlatform returns whether the image is built for multiple platforms
+func (o DockerProjectOptions) IsMultiPlatform() bool {
+	return len(o.Platforms) > 1
+}
+
 func getDockerOptionsWithDefaults(options DockerProjectOptions) DockerProjectOptions {
+	if len(options.Platforms) == 1 {
+		options.Platform = options.Platforms[0]
+	}
+
 	if options.Path == "" {
 		options.Path = "./Dockerfile"
 	}
@@ -107,7 +107,10 @@ services:
 	framework := NewDockerProject(
 		env,
 		docker,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, docker, mockContext.Console, cloud.AzurePublic()),
+		NewContainerHelper(
+			env, envManager, clock.NewMock(), nil, nil, docker, mockContext.Console, cloud.AzurePublic(), nil, nil, nil, nil,
+			nil,
+		),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -211,7 +214,10 @@ services:
 	framework := NewDockerProject(
 		env,
 		docker,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, docker, mockContext.Console, cloud.AzurePublic()),
+		NewContainerHelper(
+			env, envManager, clock.NewMock(), nil, nil, docker, mockContext.Console, cloud.AzurePublic(), nil, nil, nil, nil,
+			nil,
+		),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -403,7 +409,12 @@ func Test_DockerProject_Build(t *testing.T) {
 					dockerFilePath = serviceConfig.Docker.Path
 				}
 
-				err = os.WriteFile(filepath.Join(serviceConfig.Path(), dockerFilePath), []byte("FROM node:14"), 0600)
+				dockerFile := "FROM node:14"
+				if serviceConfig.Docker.Target != "" {
+					dockerFile = "FROM node:14 AS " + serviceConfig.Docker.Target
+				}
+
+				err = os.WriteFile(filepath.Join(serviceConfig.Path(), dockerFilePath), []byte(dockerFile), 0600)
 				require.NoError(t, err)
 			}
 
@@ -411,7 +422,9 @@ func Test_DockerProject_Build(t *testing.T) {
 				env,
 				dockerCli,
 				NewContainerHelper(
-					env, envManager, clock.NewMock(), nil, nil, dockerCli, mockContext.Console, cloud.AzurePublic()),
+					env, envManager, clock.NewMock(), nil, nil, dockerCli, mockContext.Console, cloud.AzurePublic(),
+					nil, nil, nil, nil, nil,
+				),
 				mockinput.NewMockConsole(),
 				mockContext.AlphaFeaturesManager,
 				mockContext.CommandRunner)
@@ -528,7 +541,9 @@ func Test_DockerProject_Package(t *testing.T) {
 				env,
 				dockerCli,
 				NewContainerHelper(
-					env, envManager, clock.NewMock(), nil, nil, dockerCli, mockContext.Console, cloud.AzurePublic()),
+					env, envManager, clock.NewMock(), nil, nil, dockerCli, mockContext.Console, cloud.AzurePublic(),
+					nil, nil, nil, nil, nil,
+				),
 				mockinput.NewMockConsole(),
 				mockContext.AlphaFeaturesManager,
 				mockContext.CommandRunner)
@@ -577,3 +592,34 @@ func Test_DockerProject_Package(t *testing.T) {
 		})
 	}
 }
+
+func Test_PackBuilderImage(t *testing.T) {
+	env := environment.NewWithValues("test", map[string]string{"BUILDER_TAG": "base"})
+
+	t.Run("Default", func(t *testing.T) {
+		t.Setenv("AZD_BUILDER_IMAGE", "")
+		builder, userDefined, err := packBuilderImage(env, DockerProjectOptions{Buildpack: true})
+		require.NoError(t, err)
+		require.Equal(t, DefaultBuilderImage, builder)
+		require.False(t, userDefined)
+	})
+
+	t.Run("EnvironmentVariable", func(t *testing.T) {
+		t.Setenv("AZD_BUILDER_IMAGE", "paketobuildpacks/builder-jammy-full")
+		builder, userDefined, err := packBuilderImage(env, DockerProjectOptions{Buildpack: true})
+		require.NoError(t, err)
+		require.Equal(t, "paketobuildpacks/builder-jammy-full", builder)
+		require.True(t, userDefined)
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		t.Setenv("AZD_BUILDER_IMAGE", "paketobuildpacks/builder-jammy-full")
+		builder, userDefined, err := packBuilderImage(env, DockerProjectOptions{
+			Buildpack: true,
+			Builder:   osutil.NewExpandableString("paketobuildpacks/builder-jammy-${BUILDER_TAG}"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "paketobuildpacks/builder-jammy-base", builder)
+		require.True(t, userDefined)
+	})
+}
@@ -0,0 +1,64 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+)
+
+// The extensions of the image archives created by 'docker save', ex) api.tar or api.tar.gz
+var imageArchiveExtensions = []string{".tar", ".tar.gz", ".tgz"}
+
+// isImageArchive returns true when the package is an image archive created by 'docker save', ex) handed over by a CI
+// system without access to the container registry
+func isImageArchive(packagePath string) bool {
+	for _, ext := range imageArchiveExtensions {
+		if strings.HasSuffix(strings.ToLower(packagePath), ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadImageArchive loads the image archive into the container runtime and returns the local image that is tagged and
+// pushed to the container registry. An image saved without a name is tagged with the local image tag of the service.
+func (ch *ContainerHelper) loadImageArchive(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	archivePath string,
+	progress *async.Progress[ServiceProgress],
+) (string, error) {
+	if _, err := os.Stat(archivePath); err != nil {
+		return "", fmt.Errorf("the image archive '%s' does not exist: %w", archivePath, err)
+	}
+
+	progress.SetProgress(NewServiceProgress("Loading container image archive"))
+	images, err := ch.docker.Load(ctx, archivePath)
+	if err != nil {
+		return "", err
+	}
+
+	// An image saved with several tags is loaded with each of them, the first one is pushed
+	image := images[0]
+	log.Printf("loaded image %s from archive %s", image, archivePath)
+
+	if strings.HasPrefix(image, "sha256:") {
+		localImage, err := ch.LocalImageTag(ctx, serviceConfig)
+		if err != nil {
+			return "", err
+		}
+
+		if err := ch.docker.Tag(ctx, serviceConfig.Path(), image, localImage); err != nil {
+			return "", err
+		}
+
+		image = localImage
+	}
+
+	return image, nil
+}
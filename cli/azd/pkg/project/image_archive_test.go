@@ -0,0 +1,124 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHelper_Deploy_ImageArchive(t *testing.T) {
+	tests := []struct {
+		name                string
+		loaded              string
+		expectedTag         []string
+		expectedRemoteImage string
+	}{
+		{
+			name:                "Named",
+			loaded:              "Loaded image: todo/api:ci-42\n",
+			expectedTag:         []string{"tag", "todo/api:ci-42", "contoso.azurecr.io/todo/api:ci-42"},
+			expectedRemoteImage: "contoso.azurecr.io/todo/api:ci-42",
+		},
+		{
+			// The image saved without a name is tagged with the local image tag of the service
+			name:   "Unnamed",
+			loaded: "Loaded image ID: sha256:4f2d\n",
+			expectedTag: []string{
+				"tag", "test-app/api-dev:azd-deploy-0", "contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+			},
+			expectedRemoteImage: "contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+		},
+	}
+
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP", "Microsoft.App/containerApps")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockResults := setupDockerMocks(mockContext)
+
+			var loadArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker load")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				loadArgs = args
+				return exec.NewRunResult(0, tt.loaded, ""), nil
+			})
+
+			env := environment.NewWithValues("dev", map[string]string{})
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+			mockContainerRegistryService := &mockContainerRegistryService{}
+			setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+			containerHelper := NewContainerHelper(
+				env, envManager, clock.NewMock(), mockContainerRegistryService, nil,
+				docker.NewCli(mockContext.CommandRunner), mockContext.Console, cloud.AzurePublic(), nil, nil, nil, nil,
+				nil)
+
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+
+			archivePath := filepath.Join(t.TempDir(), "api.tar")
+			require.NoError(t, os.WriteFile(archivePath, []byte{}, osutil.PermissionFile))
+
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return containerHelper.Deploy(
+						*mockContext.Context,
+						serviceConfig,
+						&ServicePackageResult{PackagePath: archivePath},
+						targetResource,
+						true,
+						progress,
+					)
+				},
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, []string{"load", "--input", archivePath}, loadArgs.Args)
+			require.Equal(t, tt.expectedTag, mockResults["docker-tag"].Args)
+			require.Equal(t, []string{"push", tt.expectedRemoteImage}, mockResults["docker-push"].Args)
+			require.Equal(t, tt.expectedRemoteImage, deployResult.Details.(*dockerDeployResult).RemoteImageTag)
+			require.Equal(t, tt.expectedRemoteImage, env.GetServiceProperty("api", "IMAGE_NAME"))
+		})
+	}
+
+	t.Run("MissingArchive", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.NewWithValues("dev", map[string]string{})
+		containerHelper := NewContainerHelper(
+			env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, nil, docker.NewCli(mockContext.CommandRunner),
+			mockContext.Console, cloud.AzurePublic(), nil, nil, nil, nil, nil)
+
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+
+		_, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return containerHelper.Deploy(
+				*mockContext.Context,
+				serviceConfig,
+				&ServicePackageResult{PackagePath: filepath.Join(t.TempDir(), "api.tar.gz")},
+				targetResource,
+				true,
+				progress,
+			)
+		})
+		require.ErrorContains(t, err, "the image archive")
+	})
+}
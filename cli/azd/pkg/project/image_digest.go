@@ -0,0 +1,23 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+// validatePinDigest returns an error when the image is pinned by digest with unsupported options
+func validatePinDigest(serviceConfig *ServiceConfig) error {
+	if serviceConfig.Docker.PinDigest && serviceConfig.Docker.RemoteBuild {
+		return fmt.Errorf("pinning the image digest is not supported with remote builds, build the image locally to pin it")
+	}
+
+	return nil
+}
+
+// pinImageDigest returns the image referenced by its digest instead of its tag,
+// ex) contoso.azurecr.io/todo/api:azd-deploy-0 and sha256:4f2d... returns contoso.azurecr.io/todo/api@sha256:4f2d...
+func pinImageDigest(remoteImage string, digest string) string {
+	repository, _ := docker.SplitDockerImage(remoteImage)
+	return fmt.Sprintf("%s@%s", repository, digest)
+}
@@ -0,0 +1,108 @@
+package project
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHelper_Deploy_PinDigest(t *testing.T) {
+	tests := map[string]struct {
+		pinDigest     bool
+		remoteBuild   bool
+		expectedImage string
+		expectedError string
+	}{
+		"Pinned": {
+			pinDigest:     true,
+			expectedImage: "contoso.azurecr.io/my-project/my-service@sha256:4f2d",
+		},
+		"NotPinned": {
+			expectedImage: "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
+		},
+		"RemoteBuild": {
+			pinDigest:     true,
+			remoteBuild:   true,
+			expectedError: "pinning the image digest is not supported with remote builds",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockResults := setupDockerMocks(mockContext)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker image inspect")
+			}).Respond(exec.NewRunResult(0, `["contoso.azurecr.io/my-project/my-service@sha256:4f2d"]`, ""))
+
+			env := environment.NewWithValues("dev", map[string]string{})
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+			mockContainerRegistryService := &mockContainerRegistryService{}
+			setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+			containerHelper := NewContainerHelper(
+				env, envManager, clock.NewMock(), mockContainerRegistryService, nil,
+				docker.NewCli(mockContext.CommandRunner), mockContext.Console, cloud.AzurePublic(), nil, nil, nil, nil, nil,
+			)
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+			serviceConfig.Docker.PinDigest = test.pinDigest
+			serviceConfig.Docker.RemoteBuild = test.remoteBuild
+
+			packageOutput := &ServicePackageResult{
+				Details: &dockerPackageResult{TargetImage: "my-project/my-service:azd-deploy-0"},
+			}
+			targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return containerHelper.Deploy(
+						*mockContext.Context, serviceConfig, packageOutput, targetResource, true, progress)
+				},
+			)
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				require.NotContains(t, mockResults, "docker-push")
+				return
+			}
+
+			require.NoError(t, err)
+
+			// Manifests and revisions reference the image by its digest, while the tag stays traceable
+			require.Equal(t, test.expectedImage, env.GetServiceProperty("api", "IMAGE_NAME"))
+			require.Equal(t, "azd-deploy-0", env.GetServiceProperty("api", "IMAGE_TAG"))
+
+			details := deployResult.Details.(*dockerDeployResult)
+			require.Equal(t, "contoso.azurecr.io/my-project/my-service:azd-deploy-0", details.RemoteImageTag)
+			if test.pinDigest {
+				require.Equal(t, test.expectedImage, details.PinnedImage)
+				require.Equal(t, "sha256:4f2d", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+			} else {
+				require.Empty(t, details.PinnedImage)
+				require.Empty(t, env.GetServiceProperty("api", "IMAGE_DIGEST"))
+			}
+		})
+	}
+}
+
+func Test_PinImageDigest(t *testing.T) {
+	require.Equal(t,
+		"contoso.azurecr.io/todo/api@sha256:4f2d", pinImageDigest("contoso.azurecr.io/todo/api:azd-deploy-0", "sha256:4f2d"))
+	require.Equal(t,
+		"localhost:5000/todo/api@sha256:4f2d", pinImageDigest("localhost:5000/todo/api:v1", "sha256:4f2d"))
+}
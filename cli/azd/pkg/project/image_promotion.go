@@ -0,0 +1,141 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// PromoteImage records the image deployed to the service in the source environment as the image of the service in the
+// current environment, so the deployment of the service deploys the exact image, referenced by its digest, without
+// building or pushing it. The image is imported into the container registry of the current environment when the
+// environments push to different registries.
+func (ch *ContainerHelper) PromoteImage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	sourceEnv *environment.Environment,
+) (*ServicePackageResult, error) {
+	sourceImage := sourceEnv.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+	repository := sourceEnv.GetServiceProperty(serviceConfig.Name, "IMAGE_REPOSITORY")
+	tag := sourceEnv.GetServiceProperty(serviceConfig.Name, "IMAGE_TAG")
+	if sourceImage == "" || repository == "" {
+		return nil, fmt.Errorf(
+			"the service '%s' has no image recorded in environment '%s', deploy the service to '%s' first",
+			serviceConfig.Name, sourceEnv.Name(), sourceEnv.Name())
+	}
+
+	// The registry of the image, ex) contoso.azurecr.io of contoso.azurecr.io/todo/api:azd-deploy-1
+	sourceRegistry, _, _ := strings.Cut(sourceImage, "/")
+
+	digest := sourceEnv.GetServiceProperty(serviceConfig.Name, "IMAGE_DIGEST")
+	if digest == "" {
+		resolved, err := ch.promotedImageDigest(ctx, serviceConfig, sourceEnv, sourceRegistry, repository, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		digest = resolved
+	}
+
+	registryName, err := ch.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := registryName != "" && !strings.EqualFold(registryName, sourceRegistry)
+	if imported {
+		if ch.registryCredentialSource(serviceConfig, registryName) != RegistryCredentialSourceAzure ||
+			!ch.isAzureContainerRegistry(sourceRegistry) {
+			return nil, fmt.Errorf(
+				"promoting images between registries is only supported for Azure Container Registry, "+
+					"cannot promote the image of '%s' to '%s'", sourceRegistry, registryName)
+		}
+
+		log.Printf("importing image %s/%s@%s into %s", sourceRegistry, repository, digest, registryName)
+		err := ch.containerRegistryService.ImportImage(
+			ctx,
+			ch.env.GetSubscriptionId(),
+			registryName,
+			azcli.ImageImportSource{
+				SubscriptionId: sourceEnv.GetSubscriptionId(),
+				LoginServer:    sourceRegistry,
+				Image:          fmt.Sprintf("%s@%s", repository, digest),
+			},
+			[]string{fmt.Sprintf("%s:%s", repository, tag)},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed importing image into registry '%s', %w", registryName, err)
+		}
+	} else {
+		registryName = sourceRegistry
+	}
+
+	image := fmt.Sprintf("%s/%s@%s", registryName, repository, digest)
+	log.Printf("promoting image %s of environment '%s'", image, sourceEnv.Name())
+
+	ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", image)
+	ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_REPOSITORY", repository)
+	ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_TAG", tag)
+	ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_DIGEST", digest)
+
+	// The signature, SBOM and provenance are attached to the image in the source registry, they are not imported along
+	// with it
+	for _, property := range []string{"IMAGE_SIGNATURE", "IMAGE_SBOM", "IMAGE_PROVENANCE", sourceHashServiceProperty} {
+		value := sourceEnv.GetServiceProperty(serviceConfig.Name, property)
+		if value == "" || (imported && property != sourceHashServiceProperty) {
+			ch.env.DeleteServiceProperty(serviceConfig.Name, property)
+			continue
+		}
+
+		ch.env.SetServiceProperty(serviceConfig.Name, property, value)
+	}
+
+	if err := ch.envManager.Save(ctx, ch.env); err != nil {
+		return nil, fmt.Errorf("saving image name to environment: %w", err)
+	}
+
+	return &ServicePackageResult{
+		Details: &dockerPackageResult{
+			ReusedImage:  image,
+			PromotedFrom: sourceEnv.Name(),
+		},
+	}, nil
+}
+
+// promotedImageDigest returns the digest of the image recorded in the source environment that was deployed without
+// recording its digest, ex) since the image was not signed or pinned to its digest
+func (ch *ContainerHelper) promotedImageDigest(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	sourceEnv *environment.Environment,
+	sourceRegistry string,
+	repository string,
+	tag string,
+) (string, error) {
+	if ch.registryCredentialSource(serviceConfig, sourceRegistry) != RegistryCredentialSourceAzure {
+		return "", fmt.Errorf(
+			"the digest of the image of service '%s' is not recorded in environment '%s', "+
+				"enable 'docker.pinDigest' and deploy the service to '%s' again",
+			serviceConfig.Name, sourceEnv.Name(), sourceEnv.Name())
+	}
+
+	tags, err := ch.containerRegistryService.GetRepositoryTags(
+		ctx, sourceEnv.GetSubscriptionId(), sourceRegistry, repository)
+	if err != nil {
+		return "", fmt.Errorf("failed listing tags of repository '%s', %w", repository, err)
+	}
+
+	index := slices.IndexFunc(tags, func(t *azcli.RepositoryTag) bool {
+		return t.Name == tag
+	})
+	if index == -1 {
+		return "", fmt.Errorf("the image '%s/%s:%s' no longer exists in the registry", sourceRegistry, repository, tag)
+	}
+
+	return tags[index].Digest, nil
+}
@@ -0,0 +1,126 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHelper_PromoteImage(t *testing.T) {
+	createSourceEnv := func() *environment.Environment {
+		env := environment.NewWithValues("staging", map[string]string{
+			"AZURE_SUBSCRIPTION_ID": "STAGING_SUBSCRIPTION_ID",
+		})
+		env.SetServiceProperty("api", "IMAGE_NAME", "contosostaging.azurecr.io/todo/api:azd-deploy-1")
+		env.SetServiceProperty("api", "IMAGE_REPOSITORY", "todo/api")
+		env.SetServiceProperty("api", "IMAGE_TAG", "azd-deploy-1")
+		env.SetServiceProperty("api", "IMAGE_SIGNATURE", "sha256:5e1d")
+
+		return env
+	}
+
+	setup := func(
+		t *testing.T, registry string,
+	) (*ContainerHelper, *mockContainerRegistryService, *environment.Environment) {
+		env := environment.NewWithValues("prod", map[string]string{
+			"AZURE_SUBSCRIPTION_ID":                         "PROD_SUBSCRIPTION_ID",
+			environment.ContainerRegistryEndpointEnvVarName: registry,
+		})
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Save", mock.Anything, env).Return(nil)
+
+		containerRegistryService := &mockContainerRegistryService{}
+		containerRegistryService.On(
+			"GetRepositoryTags", mock.Anything, "STAGING_SUBSCRIPTION_ID", "contosostaging.azurecr.io", "todo/api").
+			Return([]*azcli.RepositoryTag{
+				{Name: "azd-deploy-2", Digest: "sha256:2"},
+				{Name: "azd-deploy-1", Digest: "sha256:1"},
+			}, nil)
+		containerRegistryService.On("ImportImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything).Return(nil)
+
+		containerHelper := NewContainerHelper(
+			env, envManager, clock.NewMock(), containerRegistryService, nil, nil, nil, cloud.AzurePublic(), nil, nil,
+			nil, nil, nil)
+
+		return containerHelper, containerRegistryService, env
+	}
+
+	t.Run("SameRegistry", func(t *testing.T) {
+		containerHelper, containerRegistryService, env := setup(t, "contosostaging.azurecr.io")
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+		sourceEnv := createSourceEnv()
+		sourceEnv.SetServiceProperty("api", "IMAGE_DIGEST", "sha256:1")
+
+		packageResult, err := containerHelper.PromoteImage(context.Background(), serviceConfig, sourceEnv)
+		require.NoError(t, err)
+
+		details, ok := packageResult.Details.(*dockerPackageResult)
+		require.True(t, ok)
+		require.Equal(t, "contosostaging.azurecr.io/todo/api@sha256:1", details.ReusedImage)
+		require.Equal(t, "staging", details.PromotedFrom)
+
+		require.Equal(t, "contosostaging.azurecr.io/todo/api@sha256:1", env.GetServiceProperty("api", "IMAGE_NAME"))
+		require.Equal(t, "azd-deploy-1", env.GetServiceProperty("api", "IMAGE_TAG"))
+		require.Equal(t, "sha256:1", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+		require.Equal(t, "sha256:5e1d", env.GetServiceProperty("api", "IMAGE_SIGNATURE"))
+
+		containerRegistryService.AssertNotCalled(
+			t, "GetRepositoryTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		containerRegistryService.AssertNotCalled(
+			t, "ImportImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ImportIntoRegistry", func(t *testing.T) {
+		containerHelper, containerRegistryService, env := setup(t, "contosoprod.azurecr.io")
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+		_, err := containerHelper.PromoteImage(context.Background(), serviceConfig, createSourceEnv())
+		require.NoError(t, err)
+
+		// The digest is resolved from the tag of the image in the source registry
+		require.Equal(t, "contosoprod.azurecr.io/todo/api@sha256:1", env.GetServiceProperty("api", "IMAGE_NAME"))
+		require.Equal(t, "sha256:1", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+		require.Empty(t, env.GetServiceProperty("api", "IMAGE_SIGNATURE"))
+
+		containerRegistryService.AssertCalled(t, "ImportImage",
+			mock.Anything,
+			"PROD_SUBSCRIPTION_ID",
+			"contosoprod.azurecr.io",
+			azcli.ImageImportSource{
+				SubscriptionId: "STAGING_SUBSCRIPTION_ID",
+				LoginServer:    "contosostaging.azurecr.io",
+				Image:          "todo/api@sha256:1",
+			},
+			[]string{"todo/api:azd-deploy-1"},
+		)
+	})
+
+	t.Run("NotDeployed", func(t *testing.T) {
+		containerHelper, _, _ := setup(t, "contosoprod.azurecr.io")
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+		_, err := containerHelper.PromoteImage(
+			context.Background(), serviceConfig, environment.NewWithValues("staging", nil))
+		require.ErrorContains(t, err, "has no image recorded in environment 'staging'")
+	})
+
+	t.Run("NotAzureContainerRegistry", func(t *testing.T) {
+		containerHelper, _, _ := setup(t, "ghcr.io")
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+		sourceEnv := createSourceEnv()
+		sourceEnv.SetServiceProperty("api", "IMAGE_DIGEST", "sha256:1")
+
+		_, err := containerHelper.PromoteImage(context.Background(), serviceConfig, sourceEnv)
+		require.ErrorContains(t, err, "only supported for Azure Container Registry")
+	})
+}
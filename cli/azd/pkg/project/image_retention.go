@@ -0,0 +1,264 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// DefaultImageRetentionKeep is the number of images kept by 'azd env clean-images' when the service does not declare
+// a retention policy
+const DefaultImageRetentionKeep = 10
+
+// ImageRetentionOptions is the retention policy of the images pushed to the repository of the service, applied after
+// each deployment, ex) to keep the images of the last 10 deployments
+type ImageRetentionOptions struct {
+	// The number of the tags most recently pushed by azd kept in the repository, ex) 10
+	Keep int `yaml:"keep" json:"keep"`
+}
+
+// ImageCleanupResult are the tags deleted from and kept in the repository of a service
+type ImageCleanupResult struct {
+	Registry   string
+	Repository string
+	// The tags deleted from the repository, or deleted when running a dry run
+	Deleted []string
+	// The tags kept in the repository, ex) the most recent tags and the tag of the deployed image
+	Kept []string
+}
+
+// validateImageRetention returns an error when the retention policy is configured with unsupported options
+func validateImageRetention(serviceConfig *ServiceConfig) error {
+	retention := serviceConfig.Docker.Retention
+	if retention == nil {
+		return nil
+	}
+
+	if retention.Keep < 1 {
+		return fmt.Errorf("the retention of service '%s' must keep at least 1 image", serviceConfig.Name)
+	}
+
+	return nil
+}
+
+// RetentionKeep returns the number of images kept in the repository of the service, ex) from its retention policy
+func (ch *ContainerHelper) RetentionKeep(serviceConfig *ServiceConfig) int {
+	if retention := serviceConfig.Docker.Retention; retention != nil && retention.Keep > 0 {
+		return retention.Keep
+	}
+
+	return DefaultImageRetentionKeep
+}
+
+// The tags of the artifacts attached to images by their digest, ex) the cosign signature sha256-4f2d....sig
+var imageArtifactTagSuffixes = []string{".sig", ".att", ".sbom"}
+
+// The default tag of the images pushed by azd, see ContainerHelper.DefaultImageTag
+var defaultImageTagRegexp = regexp.MustCompile(`^azd-deploy-[0-9]+$`)
+
+// The delimiter of the variables and placeholders of the tag of the service when matching the tags pushed by azd
+const imageTagDelimiter = "\x00"
+
+// imageTagRegexp returns the pattern of the tags pushed by azd for the service, from the tag of docker.image, the
+// image template or docker.tag, as resolved by ContainerHelper.GeneratedImage, otherwise the default tag. Variables,
+// ex) ${GIT_SHA}, and the {gitsha} and {timestamp} placeholders match the values of earlier deployments too.
+func (ch *ContainerHelper) imageTagRegexp(ctx context.Context, serviceConfig *ServiceConfig) (*regexp.Regexp, error) {
+	// The variables are substituted for as placeholders without a name
+	anyValue := func(string) string { return imageTagDelimiter + imageTagDelimiter }
+
+	image, err := serviceConfig.Docker.Image.Envsubst(anyValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing 'image' from docker configuration, %w", err)
+	}
+
+	tag := imageReferenceTag(image)
+	if image == "" {
+		imageTemplate := serviceConfig.Docker.ImageTemplate
+		if imageTemplate.Empty() && serviceConfig.Project != nil {
+			imageTemplate = serviceConfig.Project.ImageTemplate
+		}
+
+		template, err := imageTemplate.Envsubst(anyValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst imageTemplate: %w", err)
+		}
+
+		tag = imageTemplatePlaceholderRegexp.ReplaceAllStringFunc(
+			imageReferenceTag(template), func(match string) string {
+				return imageTagDelimiter + strings.Trim(match, "{}") + imageTagDelimiter
+			})
+	}
+
+	if tag == "" {
+		if tag, err = serviceConfig.Docker.Tag.Envsubst(anyValue); err != nil {
+			return nil, fmt.Errorf("failed parsing 'tag' from docker configuration, %w", err)
+		}
+	}
+
+	if tag == "" {
+		return defaultImageTagRegexp, nil
+	}
+
+	// The parts of the tag alternate between literals and the variables and placeholders substituted for, empty for
+	// variables and the name of the placeholder for placeholders
+	pattern := strings.Builder{}
+	pattern.WriteString("^")
+	for i, part := range strings.Split(tag, imageTagDelimiter) {
+		switch {
+		case i%2 == 0:
+			pattern.WriteString(regexp.QuoteMeta(part))
+		case part == imageTemplateGitSha:
+			pattern.WriteString("[0-9a-f]+")
+		case part == imageTemplateTimestamp:
+			pattern.WriteString("[0-9]+")
+		case part == imageTemplateProject, part == imageTemplateService, part == imageTemplateEnv:
+			value, err := ch.imageTemplateValue(ctx, serviceConfig, part)
+			if err != nil {
+				return nil, err
+			}
+
+			pattern.WriteString(regexp.QuoteMeta(value))
+		default:
+			pattern.WriteString(".+")
+		}
+	}
+	pattern.WriteString("$")
+
+	return regexp.Compile(pattern.String())
+}
+
+// imageReferenceTag returns the tag of the image reference, ex) v1 of contoso.azurecr.io/todo/api:v1, empty when the
+// reference has no tag
+func imageReferenceTag(reference string) string {
+	reference, _, _ = strings.Cut(reference, "@")
+	name := reference[strings.LastIndex(reference, "/")+1:]
+
+	_, tag, _ := strings.Cut(name, ":")
+	return tag
+}
+
+// isImageArtifactTag returns whether the tag is the tag of an artifact attached to an image, ex) a signature
+func isImageArtifactTag(tag string) bool {
+	return slices.ContainsFunc(imageArtifactTagSuffixes, func(suffix string) bool {
+		return strings.HasSuffix(tag, suffix)
+	})
+}
+
+// CleanImages deletes the tags pushed by azd to the repository of the service except for the most recently pushed
+// tags and the tag of the deployed image. Manifests no longer referenced by a kept tag are deleted along with their
+// tags, so that the storage of the images is released. Tags not pushed by azd for the service, ex) release tags pushed
+// by pipelines, and the tags of the artifacts attached to images, ex) signatures, are never deleted, nor are the
+// manifests they reference. When dryRun is set, the tags are listed without deleting them.
+func (ch *ContainerHelper) CleanImages(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	keep int,
+	dryRun bool,
+) (*ImageCleanupResult, error) {
+	if keep < 1 {
+		return nil, errors.New("at least 1 image must be kept")
+	}
+
+	registryName, err := ch.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if registryName == "" {
+		return nil, fmt.Errorf("the service '%s' does not push images to a container registry", serviceConfig.Name)
+	}
+
+	if ch.registryCredentialSource(serviceConfig, registryName) != RegistryCredentialSourceAzure {
+		return nil, fmt.Errorf(
+			"cleaning images is only supported for Azure Container Registry, '%s' is not supported", registryName)
+	}
+
+	// The repository the last deployment pushed to, otherwise the repository of the image of the service
+	repository := ch.env.GetServiceProperty(serviceConfig.Name, "IMAGE_REPOSITORY")
+	if repository == "" {
+		image, err := ch.GeneratedImage(ctx, serviceConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		repository = image.Repository
+	}
+
+	subscriptionId := ch.env.GetSubscriptionId()
+	tags, err := ch.containerRegistryService.GetRepositoryTags(ctx, subscriptionId, registryName, repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing tags of repository '%s', %w", repository, err)
+	}
+
+	tagRegexp, err := ch.imageTagRegexp(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(tags, func(a, b *azcli.RepositoryTag) int {
+		return b.LastUpdateTime.Compare(a.LastUpdateTime)
+	})
+
+	result := &ImageCleanupResult{
+		Registry:   registryName,
+		Repository: repository,
+		Deleted:    []string{},
+		Kept:       []string{},
+	}
+
+	// The deployed image is kept even when more recent images have been pushed since, ex) by another environment
+	deployedTag := ch.env.GetServiceProperty(serviceConfig.Name, "IMAGE_TAG")
+	deployedDigest := ch.env.GetServiceProperty(serviceConfig.Name, "IMAGE_DIGEST")
+
+	keptDigests := map[string]bool{}
+	deleted := []*azcli.RepositoryTag{}
+	for _, tag := range tags {
+		// The manifests of the tags not pushed by azd are kept along with the tags
+		if isImageArtifactTag(tag.Name) || !tagRegexp.MatchString(tag.Name) {
+			keptDigests[tag.Digest] = true
+			continue
+		}
+
+		if len(result.Kept) < keep || tag.Name == deployedTag || (deployedDigest != "" && tag.Digest == deployedDigest) {
+			result.Kept = append(result.Kept, tag.Name)
+			keptDigests[tag.Digest] = true
+			continue
+		}
+
+		deleted = append(deleted, tag)
+	}
+
+	deletedDigests := map[string]bool{}
+	for _, tag := range deleted {
+		result.Deleted = append(result.Deleted, tag.Name)
+		if dryRun {
+			continue
+		}
+
+		switch {
+		case keptDigests[tag.Digest]:
+			// The manifest is referenced by a kept tag, only the tag is deleted
+			log.Printf("deleting tag %s/%s:%s", registryName, repository, tag.Name)
+			err = ch.containerRegistryService.DeleteRepositoryTag(ctx, subscriptionId, registryName, repository, tag.Name)
+		case deletedDigests[tag.Digest]:
+			// The tag has been deleted along with the manifest of another tag
+			continue
+		default:
+			log.Printf("deleting manifest %s/%s@%s", registryName, repository, tag.Digest)
+			deletedDigests[tag.Digest] = true
+			err = ch.containerRegistryService.DeleteRepositoryManifest(
+				ctx, subscriptionId, registryName, repository, tag.Digest)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed deleting tag '%s' of repository '%s', %w", tag.Name, repository, err)
+		}
+	}
+
+	return result, nil
+}
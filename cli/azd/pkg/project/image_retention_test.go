@@ -0,0 +1,178 @@
+package project
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHelper_CleanImages(t *testing.T) {
+	pushed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tags := func() []*azcli.RepositoryTag {
+		return []*azcli.RepositoryTag{
+			{Name: "azd-deploy-1", Digest: "sha256:1", LastUpdateTime: pushed},
+			{Name: "azd-deploy-3", Digest: "sha256:3", LastUpdateTime: pushed.Add(2 * time.Hour)},
+			{Name: "v1", Digest: "sha256:2", LastUpdateTime: pushed.Add(time.Hour)},
+			{Name: "azd-deploy-2", Digest: "sha256:2", LastUpdateTime: pushed.Add(time.Hour)},
+			{Name: "azd-deploy-4", Digest: "sha256:4", LastUpdateTime: pushed.Add(3 * time.Hour)},
+			{Name: "release", Digest: "sha256:5", LastUpdateTime: pushed.Add(-time.Hour)},
+			{Name: "sha256-3.sig", Digest: "sha256:6", LastUpdateTime: pushed.Add(2 * time.Hour)},
+			{Name: "azd-deploy-0", Digest: "sha256:0", LastUpdateTime: pushed.Add(-time.Hour)},
+		}
+	}
+
+	setup := func(t *testing.T) (*ContainerHelper, *mockContainerRegistryService, *ServiceConfig) {
+		env := environment.NewWithValues("dev", map[string]string{"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID"})
+		env.SetServiceProperty("api", "IMAGE_REPOSITORY", "todo/api-dev")
+		env.SetServiceProperty("api", "IMAGE_TAG", "azd-deploy-1")
+
+		containerRegistryService := &mockContainerRegistryService{}
+		containerRegistryService.On(
+			"GetRepositoryTags", mock.Anything, "SUBSCRIPTION_ID", "contoso.azurecr.io", "todo/api-dev").
+			Return(tags(), nil)
+		containerRegistryService.On("DeleteRepositoryTag", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything).Return(nil)
+		containerRegistryService.On("DeleteRepositoryManifest", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Return(nil)
+
+		containerHelper := NewContainerHelper(
+			env, nil, clock.NewMock(), containerRegistryService, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil,
+			nil)
+
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+
+		return containerHelper, containerRegistryService, serviceConfig
+	}
+
+	t.Run("KeepRecentAndDeployed", func(t *testing.T) {
+		containerHelper, containerRegistryService, serviceConfig := setup(t)
+
+		result, err := containerHelper.CleanImages(context.Background(), serviceConfig, 2, false)
+		require.NoError(t, err)
+		require.Equal(t, "todo/api-dev", result.Repository)
+		// The deployed image is kept along with the 2 most recent images, the tags not pushed by azd are ignored
+		require.Equal(t, []string{"azd-deploy-4", "azd-deploy-3", "azd-deploy-1"}, result.Kept)
+		require.Equal(t, []string{"azd-deploy-2", "azd-deploy-0"}, result.Deleted)
+
+		// The manifest of azd-deploy-2 is referenced by the v1 tag, only the tag is deleted
+		containerRegistryService.AssertCalled(t, "DeleteRepositoryTag",
+			mock.Anything, "SUBSCRIPTION_ID", "contoso.azurecr.io", "todo/api-dev", "azd-deploy-2")
+		containerRegistryService.AssertCalled(t, "DeleteRepositoryManifest",
+			mock.Anything, "SUBSCRIPTION_ID", "contoso.azurecr.io", "todo/api-dev", "sha256:0")
+		containerRegistryService.AssertNumberOfCalls(t, "DeleteRepositoryTag", 1)
+		containerRegistryService.AssertNumberOfCalls(t, "DeleteRepositoryManifest", 1)
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		containerHelper, containerRegistryService, serviceConfig := setup(t)
+
+		result, err := containerHelper.CleanImages(context.Background(), serviceConfig, 1, true)
+		require.NoError(t, err)
+		require.Equal(t, []string{"azd-deploy-4", "azd-deploy-1"}, result.Kept)
+		require.Equal(t, []string{"azd-deploy-3", "azd-deploy-2", "azd-deploy-0"}, result.Deleted)
+		containerRegistryService.AssertNotCalled(
+			t, "DeleteRepositoryTag", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		containerRegistryService.AssertNotCalled(
+			t, "DeleteRepositoryManifest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("NotAzureContainerRegistry", func(t *testing.T) {
+		containerHelper, _, serviceConfig := setup(t)
+		serviceConfig.Docker.Registry = osutil.NewExpandableString("ghcr.io")
+
+		_, err := containerHelper.CleanImages(context.Background(), serviceConfig, 1, false)
+		require.ErrorContains(t, err, "only supported for Azure Container Registry")
+	})
+}
+
+func Test_ContainerHelper_ImageTagRegexp(t *testing.T) {
+	tests := []struct {
+		name       string
+		configure  func(serviceConfig *ServiceConfig)
+		matches    []string
+		notMatches []string
+	}{
+		{
+			name:       "Default",
+			configure:  func(serviceConfig *ServiceConfig) {},
+			matches:    []string{"azd-deploy-1700000000"},
+			notMatches: []string{"latest", "azd-deploy-1700000000.sig", "v1"},
+		},
+		{
+			name: "Tag",
+			configure: func(serviceConfig *ServiceConfig) {
+				serviceConfig.Docker.Tag = osutil.NewExpandableString("${GIT_SHA}-prod")
+			},
+			matches:    []string{"4f2d8a1-prod"},
+			notMatches: []string{"azd-deploy-1700000000", "4f2d8a1-dev"},
+		},
+		{
+			name: "Image",
+			configure: func(serviceConfig *ServiceConfig) {
+				serviceConfig.Docker.Image = osutil.NewExpandableString("todo/api:v${VERSION}")
+				serviceConfig.Docker.Tag = osutil.NewExpandableString("ignored")
+			},
+			matches:    []string{"v1.2.3"},
+			notMatches: []string{"ignored", "release"},
+		},
+		{
+			name: "ImageTemplate",
+			configure: func(serviceConfig *ServiceConfig) {
+				serviceConfig.Docker.ImageTemplate = osutil.NewExpandableString(
+					"{registry}/{project}/{service}:{env}-{gitsha}-{timestamp}")
+			},
+			matches:    []string{"dev-4f2d8a1-1700000000"},
+			notMatches: []string{"prod-4f2d8a1-1700000000", "dev-main-1700000000", "azd-deploy-1700000000"},
+		},
+		{
+			name: "ImageTemplateWithoutTag",
+			configure: func(serviceConfig *ServiceConfig) {
+				serviceConfig.Docker.ImageTemplate = osutil.NewExpandableString("{project}/{service}")
+			},
+			matches:    []string{"azd-deploy-1700000000"},
+			notMatches: []string{"latest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := environment.NewWithValues("dev", nil)
+			containerHelper := NewContainerHelper(
+				env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil, nil)
+
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			tt.configure(serviceConfig)
+
+			tagRegexp, err := containerHelper.imageTagRegexp(context.Background(), serviceConfig)
+			require.NoError(t, err)
+
+			for _, tag := range tt.matches {
+				require.True(t, tagRegexp.MatchString(tag), "expected '%s' to match %s", tag, tagRegexp)
+			}
+
+			for _, tag := range tt.notMatches {
+				require.False(t, tagRegexp.MatchString(tag), "expected '%s' not to match %s", tag, tagRegexp)
+			}
+		})
+	}
+}
+
+func Test_ValidateImageRetention(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	require.NoError(t, validateImageRetention(serviceConfig))
+
+	serviceConfig.Docker.Retention = &ImageRetentionOptions{Keep: 5}
+	require.NoError(t, validateImageRetention(serviceConfig))
+
+	serviceConfig.Docker.Retention = &ImageRetentionOptions{Keep: 0}
+	require.ErrorContains(t, validateImageRetention(serviceConfig), "must keep at least 1 image")
+}
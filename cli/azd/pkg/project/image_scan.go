@@ -0,0 +1,218 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/grype"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/trivy"
+)
+
+var ErrImageVulnerabilities = errors.New("the container image has vulnerabilities at or above the severity threshold")
+
+type ImageScanner string
+
+const (
+	ImageScannerTrivy ImageScanner = "trivy"
+	ImageScannerGrype ImageScanner = "grype"
+)
+
+// The severities of vulnerabilities, from the least to the most severe
+var vulnerabilitySeverities = []string{"unknown", "low", "medium", "high", "critical"}
+
+// The maximum number of vulnerabilities listed in the scan report of a failed scan
+const maxReportedVulnerabilities = 20
+
+// ImageScanOptions configures the vulnerability scan of the container image
+// The image is scanned when it is packaged, before it is pushed, and fails the package when it has vulnerabilities
+// at or above the severity threshold.
+type ImageScanOptions struct {
+	// The tool the image is scanned with, ex) trivy or grype. Defaults to trivy
+	Scanner ImageScanner `yaml:"scanner,omitempty"       json:"scanner,omitempty"`
+	// The lowest severity that fails the scan, ex) critical, high, medium or low. Defaults to critical
+	Severity string `yaml:"severity,omitempty"      json:"severity,omitempty"`
+	// When enabled, vulnerabilities without a fixed version don't fail the scan
+	IgnoreUnfixed bool `yaml:"ignoreUnfixed,omitempty" json:"ignoreUnfixed,omitempty"`
+}
+
+// imageVulnerability is a known vulnerability of a package installed in the image, reported by either scanner
+type imageVulnerability struct {
+	Id               string
+	Package          string
+	InstalledVersion string
+	FixedVersion     string
+	Severity         string
+}
+
+// getScanOptionsWithDefaults returns the scan options with the default scanner and severity applied
+func getScanOptionsWithDefaults(options ImageScanOptions) ImageScanOptions {
+	if options.Scanner == "" {
+		options.Scanner = ImageScannerTrivy
+	}
+
+	if options.Severity == "" {
+		options.Severity = "critical"
+	}
+
+	options.Severity = strings.ToLower(options.Severity)
+
+	return options
+}
+
+// validateScan returns an error when the scan is configured with unsupported options
+func validateScan(serviceConfig *ServiceConfig) error {
+	if serviceConfig.Docker.Scan == nil {
+		return nil
+	}
+
+	scanOptions := getScanOptionsWithDefaults(*serviceConfig.Docker.Scan)
+	if scanOptions.Scanner != ImageScannerTrivy && scanOptions.Scanner != ImageScannerGrype {
+		return fmt.Errorf(
+			"unsupported image scanner '%s', supported scanners are '%s' and '%s'",
+			scanOptions.Scanner, ImageScannerTrivy, ImageScannerGrype,
+		)
+	}
+
+	if !slices.Contains(vulnerabilitySeverities[1:], scanOptions.Severity) {
+		return fmt.Errorf(
+			"unsupported severity threshold '%s', supported severities are %s",
+			scanOptions.Severity, strings.Join(vulnerabilitySeverities[1:], ", "),
+		)
+	}
+
+	// The scan runs against the local image, which remote and multi-platform builds do not produce
+	if serviceConfig.Docker.RemoteBuild || serviceConfig.Docker.IsMultiPlatform() {
+		return fmt.Errorf("image scanning is not supported with remote or multi-platform builds")
+	}
+
+	return nil
+}
+
+// scanTool returns the external tool the image is scanned with, nil when scanning is not configured
+func (p *dockerProject) scanTool(serviceConfig *ServiceConfig) tools.ExternalTool {
+	if serviceConfig.Docker.Scan == nil {
+		return nil
+	}
+
+	if getScanOptionsWithDefaults(*serviceConfig.Docker.Scan).Scanner == ImageScannerGrype {
+		return grype.NewCli(p.commandRunner)
+	}
+
+	return trivy.NewCli(p.commandRunner)
+}
+
+// scanImage scans the local image for vulnerabilities and returns an error with the scan report when the image has
+// vulnerabilities at or above the severity threshold
+func (p *dockerProject) scanImage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	imageName string,
+	progress *async.Progress[ServiceProgress],
+) error {
+	scanOptions := getScanOptionsWithDefaults(*serviceConfig.Docker.Scan)
+
+	progress.SetProgress(
+		NewServiceProgress(fmt.Sprintf("Scanning container image for vulnerabilities (%s)", scanOptions.Scanner)))
+
+	vulnerabilities := []imageVulnerability{}
+	switch scanOptions.Scanner {
+	case ImageScannerGrype:
+		results, err := grype.NewCli(p.commandRunner).Scan(ctx, imageName, scanOptions.IgnoreUnfixed)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			vulnerabilities = append(vulnerabilities, imageVulnerability(result))
+		}
+	default:
+		results, err := trivy.NewCli(p.commandRunner).Scan(ctx, imageName, scanOptions.IgnoreUnfixed)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			vulnerabilities = append(vulnerabilities, imageVulnerability(result))
+		}
+	}
+
+	threshold := slices.Index(vulnerabilitySeverities, scanOptions.Severity)
+	failed := []imageVulnerability{}
+	for _, vulnerability := range vulnerabilities {
+		vulnerability.Severity = strings.ToLower(vulnerability.Severity)
+		if slices.Index(vulnerabilitySeverities, vulnerability.Severity) >= threshold {
+			failed = append(failed, vulnerability)
+		}
+	}
+
+	log.Printf(
+		"scanned image '%s', %d vulnerabilities found, %d at or above the '%s' severity threshold",
+		imageName, len(vulnerabilities), len(failed), scanOptions.Severity,
+	)
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return &internal.ErrorWithSuggestion{
+		Err: fmt.Errorf("%w, image '%s':\n%s", ErrImageVulnerabilities, imageName, scanReport(failed)),
+		Suggestion: fmt.Sprintf(
+			"Update the base image or the vulnerable packages and run the command again. To accept the "+
+				"vulnerabilities, raise 'docker.scan.severity' above '%s' in azure.yaml.",
+			scanOptions.Severity,
+		),
+	}
+}
+
+// scanReport returns the report of the vulnerabilities, the most severe first
+// ex) CVE-2023-5363 (critical) libssl3 3.0.9-1, fixed in 3.0.11-1
+func scanReport(vulnerabilities []imageVulnerability) string {
+	slices.SortStableFunc(vulnerabilities, func(a, b imageVulnerability) int {
+		severity := slices.Index(vulnerabilitySeverities, b.Severity) - slices.Index(vulnerabilitySeverities, a.Severity)
+		if severity != 0 {
+			return severity
+		}
+
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	counts := []string{}
+	for i := len(vulnerabilitySeverities) - 1; i >= 0; i-- {
+		severity := vulnerabilitySeverities[i]
+		count := 0
+		for _, vulnerability := range vulnerabilities {
+			if vulnerability.Severity == severity {
+				count++
+			}
+		}
+
+		if count > 0 {
+			counts = append(counts, fmt.Sprintf("%s: %d", severity, count))
+		}
+	}
+
+	lines := []string{fmt.Sprintf("  %d vulnerabilities (%s)", len(vulnerabilities), strings.Join(counts, ", "))}
+	for i, vulnerability := range vulnerabilities {
+		if i == maxReportedVulnerabilities {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(vulnerabilities)-maxReportedVulnerabilities))
+			break
+		}
+
+		line := fmt.Sprintf("  - %s (%s) %s %s",
+			vulnerability.Id, vulnerability.Severity, vulnerability.Package, vulnerability.InstalledVersion)
+		if vulnerability.FixedVersion != "" {
+			line += fmt.Sprintf(", fixed in %s", vulnerability.FixedVersion)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
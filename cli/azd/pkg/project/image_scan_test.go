@@ -0,0 +1,144 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const trivyTestReport = `{"Results":[{"Vulnerabilities":[` +
+	`{"VulnerabilityID":"CVE-2023-5363","PkgName":"libssl3","InstalledVersion":"3.0.9-1",` +
+	`"FixedVersion":"3.0.11-1","Severity":"HIGH"},` +
+	`{"VulnerabilityID":"CVE-2023-4911","PkgName":"libc6","InstalledVersion":"2.36-9","Severity":"CRITICAL"},` +
+	`{"VulnerabilityID":"CVE-2023-2975","PkgName":"libssl3","InstalledVersion":"3.0.9-1","Severity":"MEDIUM"}]}]}`
+
+func Test_DockerProject_Package_Scan(t *testing.T) {
+	tests := map[string]struct {
+		scan           ImageScanOptions
+		expectedReport []string
+	}{
+		"Default": {
+			expectedReport: []string{
+				"1 vulnerabilities (critical: 1)",
+				"- CVE-2023-4911 (critical) libc6 2.36-9",
+			},
+		},
+		"HighSeverity": {
+			scan: ImageScanOptions{Severity: "HIGH"},
+			expectedReport: []string{
+				"2 vulnerabilities (critical: 1, high: 1)",
+				"- CVE-2023-4911 (critical) libc6 2.36-9\n  - CVE-2023-5363 (high) libssl3 3.0.9-1, fixed in 3.0.11-1",
+			},
+		},
+		"BelowThreshold": {
+			scan: ImageScanOptions{Severity: "critical", IgnoreUnfixed: true},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockResults := setupDockerMocks(mockContext)
+
+			var scanArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "trivy image")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				scanArgs = args
+				report := trivyTestReport
+				if test.scan.IgnoreUnfixed {
+					report = strings.ReplaceAll(report, `"Severity":"CRITICAL"`, `"Severity":"LOW"`)
+				}
+
+				return exec.NewRunResult(0, report, ""), nil
+			})
+
+			dockerProject := newTestDockerProject(mockContext)
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Scan = &test.scan
+
+			result, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+					return dockerProject.Package(
+						*mockContext.Context, serviceConfig, &ServiceBuildResult{BuildOutputPath: "IMAGE_ID"}, progress)
+				},
+			)
+
+			// The tagged local image is scanned
+			require.Contains(t, mockResults, "docker-tag")
+			require.Equal(t, "test-app/api-test:azd-deploy-0", scanArgs.Args[len(scanArgs.Args)-1])
+
+			if len(test.expectedReport) == 0 {
+				require.NoError(t, err)
+				require.NotNil(t, result)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrImageVulnerabilities)
+			for _, expected := range test.expectedReport {
+				require.ErrorContains(t, err, expected)
+			}
+
+			var suggestionErr *internal.ErrorWithSuggestion
+			require.ErrorAs(t, err, &suggestionErr)
+			require.Contains(t, suggestionErr.Suggestion, "docker.scan.severity")
+		})
+	}
+
+	t.Run("Grype", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		setupDockerMocks(mockContext)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "grype docker:")
+		}).Respond(exec.NewRunResult(0, `{"matches":[{"vulnerability":{"id":"CVE-2023-4911","severity":"Critical"},`+
+			`"artifact":{"name":"libc6","version":"2.36-9"}}]}`, ""))
+
+		dockerProject := newTestDockerProject(mockContext)
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Scan = &ImageScanOptions{Scanner: ImageScannerGrype}
+
+		_, err := logProgress(
+			t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+				return dockerProject.Package(
+					*mockContext.Context, serviceConfig, &ServiceBuildResult{BuildOutputPath: "IMAGE_ID"}, progress)
+			},
+		)
+		require.ErrorIs(t, err, ErrImageVulnerabilities)
+		require.ErrorContains(t, err, "- CVE-2023-4911 (critical) libc6 2.36-9")
+	})
+
+	t.Run("UnsupportedSeverity", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		dockerProject := newTestDockerProject(mockContext)
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Scan = &ImageScanOptions{Severity: "severe"}
+
+		_, err := dockerProject.Package(*mockContext.Context, serviceConfig, nil, async.NewProgress[ServiceProgress]())
+		require.ErrorContains(t, err, "unsupported severity threshold 'severe'")
+	})
+}
+
+func Test_ScanReport(t *testing.T) {
+	vulnerabilities := []imageVulnerability{}
+	for i := range maxReportedVulnerabilities + 5 {
+		vulnerabilities = append(vulnerabilities, imageVulnerability{
+			Id:       fmt.Sprintf("CVE-2023-%04d", i),
+			Package:  "libssl3",
+			Severity: "high",
+		})
+	}
+
+	report := scanReport(vulnerabilities)
+	require.Contains(t, report, "25 vulnerabilities (high: 25)")
+	require.Contains(t, report, "- CVE-2023-0019 (high) libssl3")
+	require.NotContains(t, report, "CVE-2023-0020")
+	require.True(t, strings.HasSuffix(report, "  ... and 5 more"))
+}
@@ -0,0 +1,140 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/notation"
+)
+
+type ImageSigningProvider string
+
+const (
+	// Signs the image with Notation and a key stored in Azure Key Vault
+	ImageSigningProviderNotation ImageSigningProvider = "notation"
+	// Signs the image keyless with Cosign and the OIDC identity of the current user or workload
+	ImageSigningProviderCosign ImageSigningProvider = "cosign"
+)
+
+// ImageSigningOptions configures the signing of the pushed container image
+// The pushed digest is signed so clusters that enforce image integrity policies accept the deployed image.
+type ImageSigningOptions struct {
+	// The tool the image is signed with, ex) notation or cosign
+	Provider ImageSigningProvider `yaml:"provider,omitempty"          json:"provider,omitempty"`
+	// The id of the Key Vault signing key used by notation, ex) https://contoso.vault.azure.net/keys/signing/0b1c
+	KeyId osutil.ExpandableString `yaml:"keyId,omitempty"             json:"keyId,omitempty"`
+	// The path of the certificate chain of the notation signing certificate, empty for self-signed certificates
+	CertificateBundle string `yaml:"certificateBundle,omitempty" json:"certificateBundle,omitempty"`
+}
+
+// signingTool returns the external tool the image is signed with, nil when signing is not configured
+func (ch *ContainerHelper) signingTool(serviceConfig *ServiceConfig) tools.ExternalTool {
+	if serviceConfig.Docker.Signing == nil {
+		return nil
+	}
+
+	switch serviceConfig.Docker.Signing.Provider {
+	case ImageSigningProviderNotation:
+		return ch.notation
+	case ImageSigningProviderCosign:
+		return ch.cosign
+	default:
+		return nil
+	}
+}
+
+// validateSigning returns an error when signing is configured with unsupported options
+// The options are validated before the image is built so misconfigurations fail fast.
+func validateSigning(serviceConfig *ServiceConfig) error {
+	signing := serviceConfig.Docker.Signing
+	if signing == nil {
+		return nil
+	}
+
+	switch signing.Provider {
+	case ImageSigningProviderNotation:
+		if signing.KeyId.Empty() {
+			return fmt.Errorf("image signing with notation requires docker.signing.keyId")
+		}
+	case ImageSigningProviderCosign:
+	default:
+		return fmt.Errorf(
+			"unsupported image signing provider '%s', supported providers are '%s' and '%s'",
+			signing.Provider, ImageSigningProviderNotation, ImageSigningProviderCosign,
+		)
+	}
+
+	if serviceConfig.Docker.RemoteBuild {
+		return fmt.Errorf("image signing is not supported with remote builds, build the image locally to sign it")
+	}
+
+	return nil
+}
+
+// imageDigest returns the digest of the pushed image, ex) sha256:4f2d...
+func (ch *ContainerHelper) imageDigest(ctx context.Context, remoteImage string) (string, error) {
+	repoDigestsJson, err := ch.docker.Inspect(ctx, remoteImage, "{{json .RepoDigests}}")
+	if err != nil {
+		return "", fmt.Errorf("failed inspecting image digest, %w", err)
+	}
+
+	repoDigests := []string{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(repoDigestsJson)), &repoDigests); err != nil {
+		return "", fmt.Errorf("failed reading image digest, %w", err)
+	}
+
+	// The image holds a digest per repository it was pushed to, ex) contoso.azurecr.io/todo/api@sha256:4f2d...
+	repository, _ := docker.SplitDockerImage(remoteImage)
+	for _, repoDigest := range repoDigests {
+		if name, digest, ok := strings.Cut(repoDigest, "@"); ok && name == repository {
+			return digest, nil
+		}
+	}
+
+	return "", fmt.Errorf("no digest found for image '%s', ensure the image was pushed to the registry", remoteImage)
+}
+
+// signImage signs the digest of the pushed image and returns the reference of the signature
+func (ch *ContainerHelper) signImage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	remoteImage string,
+	digest string,
+	progress *async.Progress[ServiceProgress],
+) (string, error) {
+	// The digest is signed instead of the tag, since tags are mutable
+	repository, _ := docker.SplitDockerImage(remoteImage)
+	reference := fmt.Sprintf("%s@%s", repository, digest)
+	signing := serviceConfig.Docker.Signing
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Signing container image with %s", signing.Provider)))
+
+	switch signing.Provider {
+	case ImageSigningProviderNotation:
+		keyId, err := signing.KeyId.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed to envsubst keyId: %w", err)
+		}
+
+		certificateBundle := signing.CertificateBundle
+		if certificateBundle != "" && !filepath.IsAbs(certificateBundle) {
+			certificateBundle = filepath.Join(serviceConfig.Path(), certificateBundle)
+		}
+
+		return ch.notation.Sign(ctx, reference, notation.SignOptions{
+			KeyId:             keyId,
+			CertificateBundle: certificateBundle,
+		})
+	case ImageSigningProviderCosign:
+		return ch.cosign.Sign(ctx, reference)
+	default:
+		return "", validateSigning(serviceConfig)
+	}
+}
@@ -0,0 +1,117 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The placeholders supported by the image template
+const (
+	// The container registry endpoint, ex) contoso.azurecr.io. Empty while the registry is not available yet
+	imageTemplateRegistry = "registry"
+	// The name of the project, ex) todo
+	imageTemplateProject = "project"
+	// The name of the service, ex) api
+	imageTemplateService = "service"
+	// The name of the environment, ex) dev
+	imageTemplateEnv = "env"
+	// The abbreviated sha of the commit checked out in the service directory, ex) 4f2d8a1
+	imageTemplateGitSha = "gitsha"
+	// The current time in seconds since the unix epoch, ex) 1700000000
+	imageTemplateTimestamp = "timestamp"
+)
+
+var imageTemplatePlaceholders = []string{
+	imageTemplateRegistry,
+	imageTemplateProject,
+	imageTemplateService,
+	imageTemplateEnv,
+	imageTemplateGitSha,
+	imageTemplateTimestamp,
+}
+
+// The placeholders of the image template, ex) {service}
+var imageTemplatePlaceholderRegexp = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// imageTemplate returns the image template of the service, which takes precedence over the image template of the
+// project. Returns an empty template when neither is configured.
+func (ch *ContainerHelper) imageTemplate(serviceConfig *ServiceConfig) (string, error) {
+	imageTemplate := serviceConfig.Docker.ImageTemplate
+	if imageTemplate.Empty() && serviceConfig.Project != nil {
+		imageTemplate = serviceConfig.Project.ImageTemplate
+	}
+
+	template, err := imageTemplate.Envsubst(ch.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed to envsubst imageTemplate: %w", err)
+	}
+
+	return template, nil
+}
+
+// renderImageTemplate renders the placeholders of the image template for the service,
+// ex) {registry}/{project}/{service}:{env}-{gitsha}-{timestamp} renders contoso.azurecr.io/todo/api:dev-4f2d8a1-1700000000
+func (ch *ContainerHelper) renderImageTemplate(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	template string,
+) (string, error) {
+	var renderErr error
+	rendered := imageTemplatePlaceholderRegexp.ReplaceAllStringFunc(template, func(match string) string {
+		if renderErr != nil {
+			return ""
+		}
+
+		var value string
+		value, renderErr = ch.imageTemplateValue(ctx, serviceConfig, strings.Trim(match, "{}"))
+		return value
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+
+	// The registry is added to the image when it is pushed, so an unavailable registry is left out of the image
+	return strings.TrimPrefix(rendered, "/"), nil
+}
+
+// imageTemplateValue returns the value of the placeholder of the image template
+func (ch *ContainerHelper) imageTemplateValue(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	placeholder string,
+) (string, error) {
+	switch placeholder {
+	case imageTemplateRegistry:
+		// This can fail if called before provisioning the registry
+		registryName, err := ch.RegistryName(ctx, serviceConfig)
+		if err != nil {
+			return "", nil
+		}
+
+		return registryName, nil
+	// Repositories are lowercase, like the default image name
+	case imageTemplateProject:
+		return strings.ToLower(serviceConfig.Project.Name), nil
+	case imageTemplateService:
+		return strings.ToLower(serviceConfig.Name), nil
+	case imageTemplateEnv:
+		return strings.ToLower(ch.env.Name()), nil
+	case imageTemplateGitSha:
+		commit, err := ch.git.GetCurrentCommit(ctx, serviceConfig.Path())
+		if err != nil {
+			return "", fmt.Errorf("failed resolving {%s} of the image template, %w", imageTemplateGitSha, err)
+		}
+
+		return commit, nil
+	case imageTemplateTimestamp:
+		return strconv.FormatInt(ch.clock.Now().Unix(), 10), nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported placeholder '{%s}' in the image template, supported placeholders are {%s}",
+			placeholder, strings.Join(imageTemplatePlaceholders, "}, {"),
+		)
+	}
+}
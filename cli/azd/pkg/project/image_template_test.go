@@ -0,0 +1,117 @@
+package project
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHelper_ImageTemplate(t *testing.T) {
+	tests := map[string]struct {
+		env                  map[string]string
+		projectTemplate      string
+		serviceTemplate      string
+		image                string
+		tag                  string
+		expectedImage        docker.ContainerImage
+		expectedErrorMessage string
+	}{
+		"ProjectTemplate": {
+			env: map[string]string{
+				environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+			},
+			projectTemplate: "{registry}/{project}/{service}:{env}-{gitsha}-{timestamp}",
+			expectedImage: docker.ContainerImage{
+				Registry:   "contoso.azurecr.io",
+				Repository: "test-app/api",
+				Tag:        "dev-4f2d8a1-0",
+			},
+		},
+		"NoRegistry": {
+			// The registry is not available before provisioning
+			projectTemplate: "{registry}/{project}/{service}:{env}-{gitsha}",
+			expectedImage: docker.ContainerImage{
+				Repository: "test-app/api",
+				Tag:        "dev-4f2d8a1",
+			},
+		},
+		"ServiceTemplate": {
+			env: map[string]string{
+				"RUN_NUMBER": "42",
+			},
+			projectTemplate: "{project}/{service}:{gitsha}",
+			serviceTemplate: "apps/{service}:{env}-${RUN_NUMBER}",
+			expectedImage: docker.ContainerImage{
+				Repository: "apps/api",
+				Tag:        "dev-42",
+			},
+		},
+		"DefaultTag": {
+			projectTemplate: "{project}/{service}",
+			expectedImage: docker.ContainerImage{
+				Repository: "test-app/api",
+				Tag:        "azd-deploy-0",
+			},
+		},
+		"ConfiguredTag": {
+			projectTemplate: "{project}/{service}",
+			tag:             "v1",
+			expectedImage: docker.ContainerImage{
+				Repository: "test-app/api",
+				Tag:        "v1",
+			},
+		},
+		"ConfiguredImage": {
+			projectTemplate: "{project}/{service}:{gitsha}",
+			image:           "custom-image",
+			expectedImage: docker.ContainerImage{
+				Repository: "custom-image",
+				Tag:        "azd-deploy-0",
+			},
+		},
+		"UnsupportedPlaceholder": {
+			projectTemplate:      "{project}/{service}:{branch}",
+			expectedErrorMessage: "unsupported placeholder '{branch}' in the image template",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "git") && strings.Contains(command, "rev-parse --short HEAD")
+			}).Respond(exec.NewRunResult(0, "4f2d8a1\n", ""))
+
+			env := environment.NewWithValues("dev", test.env)
+			containerHelper := NewContainerHelper(
+				env, nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil,
+				git.NewCli(mockContext.CommandRunner), nil,
+			)
+
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Project.ImageTemplate = osutil.NewExpandableString(test.projectTemplate)
+			serviceConfig.Docker.ImageTemplate = osutil.NewExpandableString(test.serviceTemplate)
+			serviceConfig.Docker.Image = osutil.NewExpandableString(test.image)
+			serviceConfig.Docker.Tag = osutil.NewExpandableString(test.tag)
+
+			image, err := containerHelper.GeneratedImage(*mockContext.Context, serviceConfig)
+			if test.expectedErrorMessage != "" {
+				require.ErrorContains(t, err, test.expectedErrorMessage)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedImage, *image)
+		})
+	}
+}
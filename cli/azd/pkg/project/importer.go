@@ -17,12 +17,14 @@ import (
 )
 
 type ImportManager struct {
-	dotNetImporter *DotNetImporter
+	dotNetImporter  *DotNetImporter
+	composeImporter *ComposeImporter
 }
 
-func NewImportManager(dotNetImporter *DotNetImporter) *ImportManager {
+func NewImportManager(dotNetImporter *DotNetImporter, composeImporter *ComposeImporter) *ImportManager {
 	return &ImportManager{
-		dotNetImporter: dotNetImporter,
+		dotNetImporter:  dotNetImporter,
+		composeImporter: composeImporter,
 	}
 }
 
@@ -83,6 +85,24 @@ func (im *ImportManager) ServiceStable(ctx context.Context, projectConfig *Proje
 			}
 		}
 
+		if im.composeImporter.CanImport(svcConfig) {
+			services, err := im.composeImporter.Services(ctx, projectConfig, svcConfig)
+			if err != nil {
+				return nil, fmt.Errorf("importing services: %w", err)
+			}
+
+			for name, svcConfig := range services {
+				if _, has := projectConfig.Services[name]; has {
+					return nil, fmt.Errorf(
+						"the compose service '%s' has the same name as a service of the project", name)
+				}
+
+				allServices[name] = svcConfig
+			}
+
+			continue
+		}
+
 		allServices[name] = svcConfig
 	}
 
@@ -96,7 +116,14 @@ func (im *ImportManager) ServiceStable(ctx context.Context, projectConfig *Proje
 		return strings.Compare(x.Name, y.Name)
 	})
 
-	return allServicesSlice, nil
+	// Services are deployed after the services they depend on
+	sorted, err := sortByDependencies(allServicesSlice)
+	if err != nil {
+		return nil, err
+	}
+
+	// Services deployed to more than one host are deployed to each of their hosts in turn
+	return withServiceHostTargets(sorted), nil
 }
 
 // HasAppHost returns true when there is one AppHost (Aspire) in the project.
@@ -41,7 +41,7 @@ func TestImportManagerHasService(t *testing.T) {
 		lazyEnvManager: lazy.NewLazy(func() (environment.Manager, error) {
 			return mockEnv, nil
 		}),
-	})
+	}, NewComposeImporter())
 
 	// has service
 	r, e := manager.HasService(*mockContext.Context, &ProjectConfig{
@@ -83,7 +83,7 @@ func TestImportManagerHasServiceErrorNoMultipleServicesWithAppHost(t *testing.T)
 			return mockEnv, nil
 		}),
 		hostCheck: make(map[string]hostCheckResult),
-	})
+	}, NewComposeImporter())
 
 	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
 		return strings.Contains(command, "dotnet") &&
@@ -136,7 +136,7 @@ func TestImportManagerHasServiceErrorAppHostMustTargetContainerApp(t *testing.T)
 			return mockEnv, nil
 		}),
 		hostCheck: make(map[string]hostCheckResult),
-	})
+	}, NewComposeImporter())
 
 	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
 		return strings.Contains(command, "dotnet") &&
@@ -182,7 +182,7 @@ func TestImportManagerProjectInfrastructureDefaults(t *testing.T) {
 			return mockEnv, nil
 		}),
 		hostCheck: make(map[string]hostCheckResult),
-	})
+	}, NewComposeImporter())
 
 	// Get defaults and error b/c no infra found and no Aspire project
 	r, e := manager.ProjectInfrastructure(*mockContext.Context, &ProjectConfig{})
@@ -231,7 +231,7 @@ func TestImportManagerProjectInfrastructure(t *testing.T) {
 			return mockEnv, nil
 		}),
 		hostCheck: make(map[string]hostCheckResult),
-	})
+	}, NewComposeImporter())
 
 	// Do not use defaults
 	expectedDefaultFolder := "customFolder"
@@ -313,7 +313,7 @@ func TestImportManagerProjectInfrastructureAspire(t *testing.T) {
 		hostCheck:           make(map[string]hostCheckResult),
 		cache:               make(map[manifestCacheKey]*apphost.Manifest),
 		alphaFeatureManager: alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
-	})
+	}, NewComposeImporter())
 
 	// adding infra folder to test defaults
 	err := os.Mkdir(DefaultPath, os.ModePerm)
@@ -99,6 +99,12 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
 		}
 
+		if len(svc.Hosts) > 0 {
+			if err := parseServiceHosts(svc); err != nil {
+				return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
+			}
+		}
+
 		svc.Host, err = parseServiceHost(svc.Host)
 		if err != nil {
 			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
@@ -115,10 +121,16 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 
 		svc.Infra.Path = filepath.FromSlash(svc.Infra.Path)
 
+		// k8s.healthCheck is deprecated in favor of the healthCheck of the service, which applies to any host
+		if svc.K8s.HealthCheck != nil && svc.HealthCheck == nil {
+			svc.HealthCheck = svc.K8s.HealthCheck
+		}
+
 		// TODO: Move parsing/validation requirements for service targets into their respective components.
 		// When working within container based applications users may be using external/pre-built images instead of source
 		// In this case it is valid to have not specified a language but would be required to specify a source image
-		if svc.Host == ContainerAppTarget && svc.Language == ServiceLanguageNone && svc.Image.Empty() {
+		isContainerApp := svc.Host == ContainerAppTarget || svc.Host == ContainerAppJobTarget
+		if isContainerApp && svc.Language == ServiceLanguageNone && svc.Image.Empty() {
 			return nil, fmt.Errorf("parsing service %s: must specify language or image", svc.Name)
 		}
 
@@ -7,6 +7,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/notifications"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/platform"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
@@ -30,6 +31,11 @@ type ProjectConfig struct {
 	Platform          *platform.Config          `yaml:"platform,omitempty"`
 	Workflows         workflow.WorkflowMap      `yaml:"workflows,omitempty"`
 	Cloud             *cloud.Config             `yaml:"cloud,omitempty"`
+	// The targets the results of deployments are posted to, by name, ex) a webhook, a Teams channel or a Slack channel
+	Notifications map[string]*notifications.Target `yaml:"notifications,omitempty"`
+	// The template the image names and tags of the container services are rendered from,
+	// ex) {registry}/{project}/{service}:{env}-{gitsha}-{timestamp}
+	ImageTemplate osutil.ExpandableString `yaml:"imageTemplate,omitempty"`
 
 	*ext.EventDispatcher[ProjectLifecycleEventArgs] `yaml:"-"`
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -65,6 +66,7 @@ type ServiceFilterPredicate func(svc *ServiceConfig) bool
 
 type projectManager struct {
 	azdContext     *azdcontext.AzdContext
+	env            *environment.Environment
 	serviceManager ServiceManager
 	importManager  *ImportManager
 }
@@ -72,11 +74,13 @@ type projectManager struct {
 // NewProjectManager creates a new instance of the ProjectManager
 func NewProjectManager(
 	azdContext *azdcontext.AzdContext,
+	env *environment.Environment,
 	serviceManager ServiceManager,
 	importManager *ImportManager,
 ) ProjectManager {
 	return &projectManager{
 		azdContext:     azdContext,
+		env:            env,
 		serviceManager: serviceManager,
 		importManager:  importManager,
 	}
@@ -86,6 +90,10 @@ func NewProjectManager(
 func (pm *projectManager) Initialize(ctx context.Context, projectConfig *ProjectConfig) error {
 	var projectTools []tools.ExternalTool
 
+	if err := selectServiceHosts(projectConfig, pm.env); err != nil {
+		return err
+	}
+
 	servicesStable, err := pm.importManager.ServiceStable(ctx, projectConfig)
 	if err != nil {
 		return err
@@ -342,3 +342,33 @@ services:
 	assert.Equal(t, filepath.FromSlash("src/api"), projectConfig.Services["api"].RelativePath)
 	assert.Equal(t, filepath.FromSlash("bin/api"), projectConfig.Services["api"].OutputPath)
 }
+
+// Test_K8sHealthCheckFromYaml ensures the deprecated k8s.healthCheck is used as the healthCheck of the service, so the
+// health of AKS services is verified once per deployment.
+func Test_K8sHealthCheckFromYaml(t *testing.T) {
+	const testProj = `
+name: test-proj
+services:
+  api:
+    host: aks
+    language: js
+    k8s:
+      healthCheck:
+        path: /health
+  web:
+    host: aks
+    language: js
+    healthCheck:
+      path: /ready
+    k8s:
+      healthCheck:
+        path: /health
+`
+
+	projectConfig, err := Parse(context.Background(), testProj)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/health", projectConfig.Services["api"].HealthCheck.Path)
+	// The healthCheck of the service takes precedence over the deprecated k8s.healthCheck
+	assert.Equal(t, "/ready", projectConfig.Services["web"].HealthCheck.Path)
+}
@@ -4,7 +4,10 @@
 package project
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -39,6 +42,88 @@ func createDeployableZip(projectName string, appName string, path string) (strin
 	return zipFile.Name(), nil
 }
 
+// createDeployableTarball creates a gzipped tarball of a folder, recursively, preserving the file modes so
+// executables stay executable once extracted. Returns the path to the created tarball or an error if it fails.
+func createDeployableTarball(projectName string, appName string, path string) (string, error) {
+	filePath := filepath.Join(
+		os.TempDir(), fmt.Sprintf("%s-%s-azddeploy-%d.tar.gz", projectName, appName, time.Now().Unix()))
+	tarFile, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed when creating tarball to deploy %s: %w", appName, err)
+	}
+
+	if err := writeTarball(path, tarFile); err != nil {
+		// if we fail here just do our best to close things out and cleanup
+		tarFile.Close()
+		os.Remove(tarFile.Name())
+		return "", err
+	}
+
+	if err := tarFile.Close(); err != nil {
+		os.Remove(tarFile.Name())
+		return "", err
+	}
+
+	return tarFile.Name(), nil
+}
+
+// writeTarball writes the files of the source folder to a gzipped tarball, relative to the source folder
+func writeTarball(source string, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == source || !(info.IsDir() || info.Mode().IsRegular()) {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = filepath.ToSlash(relativePath)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
 // excludeDirEntryCondition resolves when a file or directory should be considered or not as part of build, when build is a
 // copy-paste source strategy. Return true to exclude the directory entry.
 type excludeDirEntryCondition func(path string, file os.FileInfo) bool
@@ -0,0 +1,249 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+// The artifact type the provenance attestation is attached to the image with, an in-toto statement
+const provenanceArtifactType = "application/vnd.in-toto+json"
+
+const (
+	inTotoStatementType = "https://in-toto.io/Statement/v1"
+	slsaProvenanceType  = "https://slsa.dev/provenance/v1"
+	// The build type of the images built by azd, which defines the parameters of the build definition
+	azdBuildType = "https://github.com/Azure/azure-dev/container-image/v1"
+	azdBuilderId = "https://github.com/Azure/azure-dev"
+)
+
+// provenanceStatement is an in-toto statement with a SLSA provenance predicate describing how the image was built
+type provenanceStatement struct {
+	Type          string              `json:"_type"`
+	Subject       []provenanceSubject `json:"subject"`
+	PredicateType string              `json:"predicateType"`
+	Predicate     slsaProvenance      `json:"predicate"`
+}
+
+type provenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type slsaProvenance struct {
+	BuildDefinition slsaBuildDefinition `json:"buildDefinition"`
+	RunDetails      slsaRunDetails      `json:"runDetails"`
+}
+
+type slsaBuildDefinition struct {
+	BuildType            string                   `json:"buildType"`
+	ExternalParameters   provenanceParameters     `json:"externalParameters"`
+	InternalParameters   map[string]string        `json:"internalParameters"`
+	ResolvedDependencies []slsaResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// provenanceParameters are the build parameters of the service, the values of the build args are omitted since they
+// can hold secrets
+type provenanceParameters struct {
+	Service     string   `json:"service"`
+	Environment string   `json:"environment"`
+	Dockerfile  string   `json:"dockerfile,omitempty"`
+	Context     string   `json:"context,omitempty"`
+	Target      string   `json:"target,omitempty"`
+	Platforms   []string `json:"platforms,omitempty"`
+	BuildArgs   []string `json:"buildArgs,omitempty"`
+}
+
+type slsaResourceDescriptor struct {
+	Uri    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+type slsaRunDetails struct {
+	Builder  slsaBuilder     `json:"builder"`
+	Metadata slsaRunMetadata `json:"metadata"`
+}
+
+type slsaBuilder struct {
+	Id      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
+
+type slsaRunMetadata struct {
+	FinishedOn string `json:"finishedOn"`
+}
+
+// builtImage returns whether the image of the package was built by azd, ex) not an external image or an image archive
+// loaded from 'docker save', which are deployed without a provenance attestation
+func builtImage(serviceConfig *ServiceConfig, packageOutput *ServicePackageResult) bool {
+	if serviceConfig.Docker.RemoteBuild || serviceConfig.Docker.IsMultiPlatform() {
+		return true
+	}
+
+	if packageOutput == nil {
+		return false
+	}
+
+	packageDetails, ok := packageOutput.Details.(*dockerPackageResult)
+	return ok && packageDetails != nil && packageDetails.ImageHash != "" && packageDetails.SourceImage == ""
+}
+
+// imageBuilder returns the tool the image of the service is built with, ex) docker, pack or acr
+func (ch *ContainerHelper) imageBuilder(serviceConfig *ServiceConfig) string {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	switch {
+	case dockerOptions.RemoteBuild:
+		return "acr"
+	case dockerOptions.IsMultiPlatform():
+		return "buildx"
+	case dockerOptions.SourceBuilder != "":
+		return string(dockerOptions.SourceBuilder)
+	case dockerOptions.Buildpack:
+		return "pack"
+	}
+
+	// Services without a Dockerfile are built from source with buildpacks
+	if dockerOptions.Dockerfile == "" && serviceConfig.Docker.Path == "" {
+		if _, err := os.Stat(filepath.Join(serviceConfig.Path(), dockerOptions.Path)); errors.Is(err, os.ErrNotExist) {
+			return "pack"
+		}
+	}
+
+	return string(ch.docker.Runtime())
+}
+
+// provenance returns the provenance statement of the image pushed with the digest
+func (ch *ContainerHelper) provenance(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	remoteImage string,
+	digest string,
+) provenanceStatement {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	repository, _ := docker.SplitDockerImage(remoteImage)
+	algorithm, value, _ := strings.Cut(digest, ":")
+
+	parameters := provenanceParameters{
+		Service:     serviceConfig.Name,
+		Environment: ch.env.Name(),
+		Target:      dockerOptions.Target,
+		Platforms:   dockerOptions.Platforms,
+	}
+
+	if dockerOptions.SourceBuilder == "" && !dockerOptions.Buildpack {
+		parameters.Context = filepath.ToSlash(dockerOptions.Context)
+		parameters.Dockerfile = filepath.ToSlash(dockerOptions.Path)
+		if dockerOptions.Dockerfile != "" {
+			parameters.Dockerfile = "inline"
+		}
+	}
+
+	if len(parameters.Platforms) == 0 {
+		parameters.Platforms = []string{dockerOptions.Platform}
+	}
+
+	for _, buildArg := range dockerOptions.BuildArgs {
+		name, _, _ := strings.Cut(buildArg, "=")
+		parameters.BuildArgs = append(parameters.BuildArgs, name)
+	}
+
+	return provenanceStatement{
+		Type: inTotoStatementType,
+		Subject: []provenanceSubject{
+			{Name: repository, Digest: map[string]string{algorithm: value}},
+		},
+		PredicateType: slsaProvenanceType,
+		Predicate: slsaProvenance{
+			BuildDefinition: slsaBuildDefinition{
+				BuildType:            azdBuildType,
+				ExternalParameters:   parameters,
+				InternalParameters:   map[string]string{"builder": ch.imageBuilder(serviceConfig)},
+				ResolvedDependencies: ch.sourceDependencies(ctx, serviceConfig),
+			},
+			RunDetails: slsaRunDetails{
+				Builder: slsaBuilder{
+					Id:      azdBuilderId,
+					Version: map[string]string{"azd": internal.VersionInfo().Version.String()},
+				},
+				Metadata: slsaRunMetadata{
+					FinishedOn: ch.clock.Now().UTC().Format(time.RFC3339),
+				},
+			},
+		},
+	}
+}
+
+// sourceDependencies returns the git commit the service is built from, or nil when the service is not in a git
+// repository
+func (ch *ContainerHelper) sourceDependencies(ctx context.Context, serviceConfig *ServiceConfig) []slsaResourceDescriptor {
+	commit, err := ch.git.GetHeadCommit(ctx, serviceConfig.Path())
+	if err != nil {
+		log.Printf("no git commit for the provenance of service '%s': %v", serviceConfig.Name, err)
+		return nil
+	}
+
+	uri := "git+file://" + filepath.ToSlash(serviceConfig.Path())
+	if remoteUrl, err := ch.git.GetRemoteUrl(ctx, serviceConfig.Path(), "origin"); err == nil {
+		uri = "git+" + redactRemoteUrl(remoteUrl)
+	}
+
+	return []slsaResourceDescriptor{
+		{Uri: uri, Digest: map[string]string{"gitCommit": commit}},
+	}
+}
+
+// redactRemoteUrl removes the credentials from the remote url, ex) a token in https://token@github.com/contoso/todo
+func redactRemoteUrl(remoteUrl string) string {
+	parsed, err := url.Parse(remoteUrl)
+	if err != nil || parsed.User == nil {
+		return remoteUrl
+	}
+
+	parsed.User = nil
+	return parsed.String()
+}
+
+// attachProvenance attaches the provenance attestation to the pushed image as an OCI referrer and returns the reference
+// of the attestation artifact
+func (ch *ContainerHelper) attachProvenance(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	remoteImage string,
+	digest string,
+	progress *async.Progress[ServiceProgress],
+) (string, error) {
+	statement, err := json.MarshalIndent(ch.provenance(ctx, serviceConfig, remoteImage, digest), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed creating provenance attestation, %w", err)
+	}
+
+	provenanceDir, err := os.MkdirTemp("", "azd-provenance")
+	if err != nil {
+		return "", fmt.Errorf("failed creating provenance directory, %w", err)
+	}
+	defer os.RemoveAll(provenanceDir)
+
+	// ex) api-provenance.intoto.json
+	provenancePath := filepath.Join(provenanceDir, fmt.Sprintf("%s-provenance.intoto.json", serviceConfig.Name))
+	if err := os.WriteFile(provenancePath, statement, osutil.PermissionFile); err != nil {
+		return "", fmt.Errorf("failed writing provenance attestation, %w", err)
+	}
+
+	repository, _ := docker.SplitDockerImage(remoteImage)
+	reference := fmt.Sprintf("%s@%s", repository, digest)
+
+	progress.SetProgress(NewServiceProgress("Attaching provenance to container image"))
+	return ch.oras.Attach(ctx, reference, provenanceArtifactType, provenancePath)
+}
@@ -0,0 +1,164 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHelper_Deploy_Provenance(t *testing.T) {
+	tests := []struct {
+		name               string
+		packageDetails     *dockerPackageResult
+		expectedProvenance bool
+	}{
+		{
+			name: "BuiltImage",
+			packageDetails: &dockerPackageResult{
+				ImageHash:   "IMAGE_ID",
+				TargetImage: "my-project/my-service:azd-deploy-0",
+			},
+			expectedProvenance: true,
+		},
+		{
+			// External images are not built by azd, so there is no provenance to attest
+			name: "ExternalImage",
+			packageDetails: &dockerPackageResult{
+				ImageHash:   "IMAGE_ID",
+				SourceImage: "nginx:latest",
+				TargetImage: "my-project/my-service:azd-deploy-0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			setupDockerMocks(mockContext)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker image inspect")
+			}).Respond(exec.NewRunResult(0, `["contoso.azurecr.io/my-project/my-service@sha256:4f2d"]`, ""))
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "git") && strings.Contains(command, "rev-parse HEAD")
+			}).Respond(exec.NewRunResult(0, "8a49ae5ae9ab13beeade35f91ad4b4611c2f5574\n", ""))
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "git") && strings.Contains(command, "remote get-url origin")
+			}).Respond(exec.NewRunResult(0, "https://token@github.com/contoso/todo.git\n", ""))
+
+			var attachArgs exec.RunArgs
+			var statement provenanceStatement
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "oras attach")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				attachArgs = args
+
+				file, _, _ := strings.Cut(args.Args[2], ":")
+				content, err := os.ReadFile(filepath.Join(args.Cwd, file))
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(content, &statement))
+
+				return exec.NewRunResult(0, `{"reference":"contoso.azurecr.io/my-project/my-service@sha256:9e3b"}`, ""), nil
+			})
+
+			env := environment.NewWithValues("dev", map[string]string{})
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+			mockContainerRegistryService := &mockContainerRegistryService{}
+			setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+			mockClock := clock.NewMock()
+			mockClock.Set(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+			containerHelper := NewContainerHelper(
+				env, envManager, mockClock, mockContainerRegistryService, nil, docker.NewCli(mockContext.CommandRunner),
+				mockContext.Console, cloud.AzurePublic(), nil, nil, oras.NewCli(mockContext.CommandRunner),
+				git.NewCli(mockContext.CommandRunner), nil,
+			)
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+			serviceConfig.Docker.Provenance = true
+			serviceConfig.Docker.Target = "runtime"
+			serviceConfig.Docker.BuildArgs = []string{"NPM_TOKEN=secret", "NODE_ENV"}
+
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return containerHelper.Deploy(
+						*mockContext.Context,
+						serviceConfig,
+						&ServicePackageResult{Details: tt.packageDetails},
+						environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", ""),
+						true,
+						progress,
+					)
+				},
+			)
+			require.NoError(t, err)
+
+			details := deployResult.Details.(*dockerDeployResult)
+			if !tt.expectedProvenance {
+				require.Empty(t, attachArgs.Args)
+				require.Empty(t, details.Provenance)
+				require.Empty(t, env.GetServiceProperty("api", "IMAGE_PROVENANCE"))
+				return
+			}
+
+			// The provenance refers to the digest of the pushed image
+			require.Equal(t, []string{
+				"attach", "contoso.azurecr.io/my-project/my-service@sha256:4f2d",
+				"api-provenance.intoto.json:application/vnd.in-toto+json",
+				"--artifact-type", "application/vnd.in-toto+json",
+				"--format", "json",
+			}, attachArgs.Args)
+			require.Equal(t, "contoso.azurecr.io/my-project/my-service@sha256:9e3b", details.Provenance)
+			require.Equal(t, details.Provenance, env.GetServiceProperty("api", "IMAGE_PROVENANCE"))
+			require.Equal(t, "sha256:4f2d", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+
+			require.Equal(t, "https://in-toto.io/Statement/v1", statement.Type)
+			require.Equal(t, "https://slsa.dev/provenance/v1", statement.PredicateType)
+			require.Equal(t, []provenanceSubject{
+				{Name: "contoso.azurecr.io/my-project/my-service", Digest: map[string]string{"sha256": "4f2d"}},
+			}, statement.Subject)
+
+			buildDefinition := statement.Predicate.BuildDefinition
+			require.Equal(t, provenanceParameters{
+				Service:     "api",
+				Environment: "dev",
+				Dockerfile:  "./Dockerfile",
+				Context:     ".",
+				Target:      "runtime",
+				Platforms:   []string{docker.DefaultPlatform},
+				// The values of the build args are not attested
+				BuildArgs: []string{"NPM_TOKEN", "NODE_ENV"},
+			}, buildDefinition.ExternalParameters)
+			require.Equal(t, []slsaResourceDescriptor{
+				{
+					Uri:    "git+https://github.com/contoso/todo.git",
+					Digest: map[string]string{"gitCommit": "8a49ae5ae9ab13beeade35f91ad4b4611c2f5574"},
+				},
+			}, buildDefinition.ResolvedDependencies)
+			require.Equal(t, "2024-01-01T00:00:00Z", statement.Predicate.RunDetails.Metadata.FinishedOn)
+			require.Equal(t, "https://github.com/Azure/azure-dev", statement.Predicate.RunDetails.Builder.Id)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+// The registry of images without a registry, ex) nginx
+const dockerHubServer = "docker.io"
+
+type RegistryCredentialSource string
+
+const (
+	// The credentials are exchanged from the signed in Azure principal, only for Azure Container Registry
+	RegistryCredentialSourceAzure RegistryCredentialSource = "azure"
+	// The username and password are read from the configured values, ex) ${GHCR_USERNAME} and ${GHCR_TOKEN}
+	RegistryCredentialSourceEnv RegistryCredentialSource = "env"
+	// The credentials of an existing login, ex) 'docker login ghcr.io', are read from the docker config file or its
+	// credential helper
+	RegistryCredentialSourceDockerConfig RegistryCredentialSource = "dockerConfig"
+)
+
+// RegistryCredentialsOptions configures the credentials of the container registry the image is pushed to
+// The credentials are used to push the image and, for registries other than Azure Container Registry, to reference
+// the image from the container app or the image pull secret of the AKS cluster.
+type RegistryCredentialsOptions struct {
+	// The source of the credentials, ex) azure, env or dockerConfig. Defaults to env when a username is set, azure for
+	// Azure Container Registry and dockerConfig for other registries
+	Source RegistryCredentialSource `yaml:"source,omitempty"   json:"source,omitempty"`
+	// The registry username, ex) ${GHCR_USERNAME}
+	Username osutil.ExpandableString `yaml:"username,omitempty" json:"username,omitempty"`
+	// The registry password or access token, ex) ${GHCR_TOKEN}
+	Password osutil.ExpandableString `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+// validateRegistryCredentials returns an error when the registry credentials are configured with unsupported options
+func validateRegistryCredentials(serviceConfig *ServiceConfig) error {
+	credentials := serviceConfig.Docker.Credentials
+	if credentials == nil {
+		return nil
+	}
+
+	switch credentials.Source {
+	case "", RegistryCredentialSourceAzure, RegistryCredentialSourceDockerConfig:
+	case RegistryCredentialSourceEnv:
+		if credentials.Username.Empty() || credentials.Password.Empty() {
+			return fmt.Errorf("registry credentials from 'env' require docker.credentials.username and password")
+		}
+	default:
+		return fmt.Errorf(
+			"unsupported registry credential source '%s', supported sources are '%s', '%s' and '%s'",
+			credentials.Source,
+			RegistryCredentialSourceAzure, RegistryCredentialSourceEnv, RegistryCredentialSourceDockerConfig,
+		)
+	}
+
+	return nil
+}
+
+// registryCredentialSource returns the source of the credentials of the registry server the service image is pushed
+// to or pulled from
+func (ch *ContainerHelper) registryCredentialSource(serviceConfig *ServiceConfig, server string) RegistryCredentialSource {
+	if credentials := serviceConfig.Docker.Credentials; credentials != nil {
+		if credentials.Source != "" {
+			return credentials.Source
+		}
+
+		if !credentials.Username.Empty() {
+			return RegistryCredentialSourceEnv
+		}
+	}
+
+	if ch.isAzureContainerRegistry(server) {
+		return RegistryCredentialSourceAzure
+	}
+
+	return RegistryCredentialSourceDockerConfig
+}
+
+// resolveRegistryCredentials returns the credentials of the registry server from its credential source
+// Returns docker.ErrCredentialsNotFound when the credentials are read from the docker config and the container runtime
+// is not logged into the registry.
+func (ch *ContainerHelper) resolveRegistryCredentials(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	subscriptionId string,
+	server string,
+) (*azcli.DockerCredentials, error) {
+	connectedRegistry, err := ch.connectedRegistry(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if connectedRegistry != nil && connectedRegistry.endpoint == server {
+		return ch.connectedRegistryCredentials(ctx, serviceConfig, subscriptionId, connectedRegistry)
+	}
+
+	source := ch.registryCredentialSource(serviceConfig, server)
+	log.Printf("resolving credentials of registry '%s' from '%s'", server, source)
+
+	switch source {
+	case RegistryCredentialSourceAzure:
+		return ch.registryCredentials(ctx, subscriptionId, server)
+	case RegistryCredentialSourceEnv:
+		username, err := serviceConfig.Docker.Credentials.Username.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst registry username: %w", err)
+		}
+
+		password, err := serviceConfig.Docker.Credentials.Password.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst registry password: %w", err)
+		}
+
+		if username == "" || password == "" {
+			return nil, fmt.Errorf("missing 'username' or 'password' for the credentials of registry '%s'", server)
+		}
+
+		return &azcli.DockerCredentials{Username: username, Password: password, LoginServer: server}, nil
+	default:
+		username, password, err := ch.docker.Credentials(ctx, server)
+		if err != nil {
+			return nil, err
+		}
+
+		return &azcli.DockerCredentials{Username: username, Password: password, LoginServer: server}, nil
+	}
+}
+
+// externalRegistryCredentials returns the credentials the image is pulled from a registry other than Azure Container
+// Registry with, ex) ghcr.io. Returns nil when the image is pulled from Azure Container Registry, which is accessed with
+// the identity of the target resource, or when no credentials are found for the registry, ex) of a public image.
+func (ch *ContainerHelper) externalRegistryCredentials(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	subscriptionId string,
+	image string,
+) (*azcli.DockerCredentials, error) {
+	containerImage, err := docker.ParseContainerImage(image)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing image '%s', %w", image, err)
+	}
+
+	server := containerImage.Registry
+	if server == "" {
+		server = dockerHubServer
+	}
+
+	if ch.isAzureContainerRegistry(server) {
+		return nil, nil
+	}
+
+	credentials, err := ch.resolveRegistryCredentials(ctx, serviceConfig, subscriptionId, server)
+	if errors.Is(err, docker.ErrCredentialsNotFound) {
+		log.Printf("no credentials found for registry '%s', pulling image '%s' anonymously", server, image)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
@@ -0,0 +1,96 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHelper_RegistryCredentialSource(t *testing.T) {
+	tests := map[string]struct {
+		credentials    *RegistryCredentialsOptions
+		server         string
+		expectedSource RegistryCredentialSource
+	}{
+		"AzureContainerRegistry": {
+			server:         "contoso.azurecr.io",
+			expectedSource: RegistryCredentialSourceAzure,
+		},
+		"ExternalRegistry": {
+			server:         "ghcr.io",
+			expectedSource: RegistryCredentialSourceDockerConfig,
+		},
+		"Username": {
+			credentials: &RegistryCredentialsOptions{
+				Username: osutil.NewExpandableString("octocat"),
+				Password: osutil.NewExpandableString("${GHCR_TOKEN}"),
+			},
+			server:         "ghcr.io",
+			expectedSource: RegistryCredentialSourceEnv,
+		},
+		"ConfiguredSource": {
+			credentials: &RegistryCredentialsOptions{
+				Source: RegistryCredentialSourceDockerConfig,
+			},
+			server:         "contoso.azurecr.io",
+			expectedSource: RegistryCredentialSourceDockerConfig,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			containerHelper := NewContainerHelper(
+				environment.New("dev"), nil, clock.NewMock(), nil, nil, nil, nil, cloud.AzurePublic(), nil, nil, nil, nil,
+				nil,
+			)
+
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Credentials = test.credentials
+
+			require.Equal(t, test.expectedSource, containerHelper.registryCredentialSource(serviceConfig, test.server))
+		})
+	}
+}
+
+func Test_ContainerHelper_ExternalRegistryCredentials(t *testing.T) {
+	setupDockerConfig(t, "")
+
+	mockContext := mocks.NewMockContext(context.Background())
+	containerHelper := NewContainerHelper(
+		environment.New("dev"), nil, clock.NewMock(), nil, nil, docker.NewCli(mockContext.CommandRunner), nil,
+		cloud.AzurePublic(), nil, nil, nil, nil, nil,
+	)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+	// Images of ACR are pulled with the identity of the target resource
+	credentials, err := containerHelper.externalRegistryCredentials(
+		*mockContext.Context, serviceConfig, "SUBSCRIPTION_ID", "contoso.azurecr.io/todo/api:v1")
+	require.NoError(t, err)
+	require.Nil(t, credentials)
+
+	// Public images are pulled anonymously
+	credentials, err = containerHelper.externalRegistryCredentials(
+		*mockContext.Context, serviceConfig, "SUBSCRIPTION_ID", "nginx:latest")
+	require.NoError(t, err)
+	require.Nil(t, credentials)
+}
+
+func Test_ValidateRegistryCredentials(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+	serviceConfig.Docker.Credentials = &RegistryCredentialsOptions{Source: RegistryCredentialSourceEnv}
+	require.ErrorContains(t, validateRegistryCredentials(serviceConfig), "require docker.credentials.username and password")
+
+	serviceConfig.Docker.Credentials = &RegistryCredentialsOptions{Source: "vault"}
+	require.ErrorContains(t, validateRegistryCredentials(serviceConfig), "unsupported registry credential source 'vault'")
+
+	serviceConfig.Docker.Credentials = &RegistryCredentialsOptions{Source: RegistryCredentialSourceDockerConfig}
+	require.NoError(t, validateRegistryCredentials(serviceConfig))
+}
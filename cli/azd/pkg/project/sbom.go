@@ -0,0 +1,156 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/syft"
+)
+
+type SbomGenerator string
+
+const (
+	SbomGeneratorSyft   SbomGenerator = "syft"
+	SbomGeneratorDocker SbomGenerator = "docker"
+)
+
+type SbomFormat string
+
+const (
+	SbomFormatSpdx      SbomFormat = "spdx-json"
+	SbomFormatCycloneDx SbomFormat = "cyclonedx-json"
+)
+
+// The artifact types the SBOM is attached to the image with, by format
+var sbomArtifactTypes = map[SbomFormat]string{
+	SbomFormatSpdx:      "application/spdx+json",
+	SbomFormatCycloneDx: "application/vnd.cyclonedx+json",
+}
+
+// The file extensions of the generated SBOM, by format
+var sbomFileExtensions = map[SbomFormat]string{
+	SbomFormatSpdx:      ".spdx.json",
+	SbomFormatCycloneDx: ".cdx.json",
+}
+
+// SbomOptions configures the software bill of materials generated for the container image
+// The SBOM is generated when the image is packaged and attached to the image as an OCI referrer when it is pushed.
+type SbomOptions struct {
+	// The tool the SBOM is generated with, ex) syft or docker. Defaults to syft
+	Generator SbomGenerator `yaml:"generator,omitempty" json:"generator,omitempty"`
+	// The format of the SBOM, ex) spdx-json or cyclonedx-json. Defaults to spdx-json
+	Format SbomFormat `yaml:"format,omitempty"    json:"format,omitempty"`
+}
+
+// getSbomOptionsWithDefaults returns the SBOM options with the default generator and format applied
+func getSbomOptionsWithDefaults(options SbomOptions) SbomOptions {
+	if options.Generator == "" {
+		options.Generator = SbomGeneratorSyft
+	}
+
+	if options.Format == "" {
+		options.Format = SbomFormatSpdx
+	}
+
+	return options
+}
+
+// validateSbom returns an error when the SBOM is configured with unsupported options
+func validateSbom(serviceConfig *ServiceConfig) error {
+	if serviceConfig.Docker.Sbom == nil {
+		return nil
+	}
+
+	sbomOptions := getSbomOptionsWithDefaults(*serviceConfig.Docker.Sbom)
+	if sbomOptions.Generator != SbomGeneratorSyft && sbomOptions.Generator != SbomGeneratorDocker {
+		return fmt.Errorf(
+			"unsupported sbom generator '%s', supported generators are '%s' and '%s'",
+			sbomOptions.Generator, SbomGeneratorSyft, SbomGeneratorDocker,
+		)
+	}
+
+	if _, has := sbomArtifactTypes[sbomOptions.Format]; !has {
+		return fmt.Errorf(
+			"unsupported sbom format '%s', supported formats are '%s' and '%s'",
+			sbomOptions.Format, SbomFormatSpdx, SbomFormatCycloneDx,
+		)
+	}
+
+	// The SBOM is generated from the local image, which remote and multi-platform builds do not produce
+	if serviceConfig.Docker.RemoteBuild || serviceConfig.Docker.IsMultiPlatform() {
+		return fmt.Errorf("sbom generation is not supported with remote or multi-platform builds")
+	}
+
+	return nil
+}
+
+// sbomTool returns the external tool the SBOM is generated with, nil when it is generated by docker or not configured
+func (p *dockerProject) sbomTool(serviceConfig *ServiceConfig) tools.ExternalTool {
+	if serviceConfig.Docker.Sbom == nil {
+		return nil
+	}
+
+	if getSbomOptionsWithDefaults(*serviceConfig.Docker.Sbom).Generator == SbomGeneratorSyft {
+		return syft.NewCli(p.commandRunner)
+	}
+
+	return nil
+}
+
+// generateSbom generates the SBOM of the local image and returns the path of the generated file
+func (p *dockerProject) generateSbom(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	imageName string,
+	progress *async.Progress[ServiceProgress],
+) (string, error) {
+	sbomOptions := getSbomOptionsWithDefaults(*serviceConfig.Docker.Sbom)
+
+	sbomDir, err := os.MkdirTemp("", "azd-sbom")
+	if err != nil {
+		return "", fmt.Errorf("failed creating sbom directory, %w", err)
+	}
+
+	// ex) api-sbom.spdx.json
+	sbomPath := filepath.Join(sbomDir, fmt.Sprintf("%s-sbom%s", serviceConfig.Name, sbomFileExtensions[sbomOptions.Format]))
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Generating SBOM (%s)", sbomOptions.Format)))
+	switch sbomOptions.Generator {
+	case SbomGeneratorDocker:
+		err = p.docker.Sbom(ctx, imageName, string(sbomOptions.Format), sbomPath)
+	default:
+		err = syft.NewCli(p.commandRunner).GenerateSbom(ctx, imageName, string(sbomOptions.Format), sbomPath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return sbomPath, nil
+}
+
+// attachSbom attaches the SBOM to the pushed image as an OCI referrer and returns the reference of the SBOM artifact
+func (ch *ContainerHelper) attachSbom(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	remoteImage string,
+	digest string,
+	sbomPath string,
+	progress *async.Progress[ServiceProgress],
+) (string, error) {
+	sbomOptions := SbomOptions{}
+	if serviceConfig.Docker.Sbom != nil {
+		sbomOptions = *serviceConfig.Docker.Sbom
+	}
+	sbomOptions = getSbomOptionsWithDefaults(sbomOptions)
+
+	repository, _ := docker.SplitDockerImage(remoteImage)
+	reference := fmt.Sprintf("%s@%s", repository, digest)
+
+	progress.SetProgress(NewServiceProgress("Attaching SBOM to container image"))
+	return ch.oras.Attach(ctx, reference, sbomArtifactTypes[sbomOptions.Format], sbomPath)
+}
@@ -0,0 +1,188 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DockerProject_Package_Sbom(t *testing.T) {
+	tests := map[string]struct {
+		sbom            SbomOptions
+		expectedCommand string
+		expectedArgs    []string
+		expectedFile    string
+	}{
+		"Default": {
+			expectedCommand: "syft",
+			expectedArgs:    []string{"scan", "docker:test-app/api-test:azd-deploy-0", "--output"},
+			expectedFile:    "api-sbom.spdx.json",
+		},
+		"DockerCycloneDx": {
+			sbom:            SbomOptions{Generator: SbomGeneratorDocker, Format: SbomFormatCycloneDx},
+			expectedCommand: "docker",
+			expectedArgs:    []string{"sbom", "test-app/api-test:azd-deploy-0", "--format", "cyclonedx-json", "--output"},
+			expectedFile:    "api-sbom.cdx.json",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			setupDockerMocks(mockContext)
+
+			var sbomArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "syft scan") || strings.Contains(command, "docker sbom")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				sbomArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			dockerProject := newTestDockerProject(mockContext)
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Sbom = &test.sbom
+
+			result, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+					return dockerProject.Package(
+						*mockContext.Context, serviceConfig, &ServiceBuildResult{BuildOutputPath: "IMAGE_ID"}, progress)
+				},
+			)
+			require.NoError(t, err)
+			defer os.RemoveAll(filepath.Dir(result.SbomPath))
+
+			require.Equal(t, test.expectedFile, filepath.Base(result.SbomPath))
+			require.Equal(t, test.expectedCommand, sbomArgs.Cmd)
+			require.Equal(t, test.expectedArgs, sbomArgs.Args[:len(sbomArgs.Args)-1])
+			require.Contains(t, sbomArgs.Args[len(sbomArgs.Args)-1], result.SbomPath)
+			require.Contains(t, result.ToString(""), "- SBOM: ")
+		})
+	}
+
+	t.Run("MultiPlatform", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		dockerProject := newTestDockerProject(mockContext)
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Sbom = &SbomOptions{}
+		serviceConfig.Docker.Platforms = []string{"linux/amd64", "linux/arm64"}
+
+		_, err := dockerProject.Package(
+			*mockContext.Context, serviceConfig, nil, async.NewProgress[ServiceProgress]())
+		require.ErrorContains(t, err, "sbom generation is not supported with remote or multi-platform builds")
+	})
+
+	t.Run("UnsupportedFormat", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		dockerProject := newTestDockerProject(mockContext)
+		serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Sbom = &SbomOptions{Format: "spdx-tag-value"}
+
+		_, err := dockerProject.Package(
+			*mockContext.Context, serviceConfig, nil, async.NewProgress[ServiceProgress]())
+		require.ErrorContains(t, err, "unsupported sbom format 'spdx-tag-value'")
+	})
+}
+
+func Test_ContainerHelper_Deploy_Sbom(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockResults := setupDockerMocks(mockContext)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect")
+	}).Respond(exec.NewRunResult(0, `["contoso.azurecr.io/my-project/my-service@sha256:4f2d"]`, ""))
+
+	var attachArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "oras attach")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		attachArgs = args
+		return exec.NewRunResult(0, `{"reference":"contoso.azurecr.io/my-project/my-service@sha256:7c1a"}`, ""), nil
+	})
+
+	env := environment.NewWithValues("dev", map[string]string{})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		mockContainerRegistryService,
+		nil,
+		docker.NewCli(mockContext.CommandRunner),
+		mockContext.Console,
+		cloud.AzurePublic(),
+		nil,
+		nil,
+		oras.NewCli(mockContext.CommandRunner), nil, nil,
+	)
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+	serviceConfig.Docker.Sbom = &SbomOptions{Format: SbomFormatCycloneDx}
+
+	sbomPath := filepath.Join(t.TempDir(), "api-sbom.cdx.json")
+	packageOutput := &ServicePackageResult{
+		Details:  &dockerPackageResult{TargetImage: "my-project/my-service:azd-deploy-0"},
+		SbomPath: sbomPath,
+	}
+	targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return containerHelper.Deploy(
+				*mockContext.Context, serviceConfig, packageOutput, targetResource, true, progress)
+		},
+	)
+	require.NoError(t, err)
+	require.Contains(t, mockResults, "docker-push")
+
+	// The SBOM refers to the digest of the pushed image
+	require.Equal(t, filepath.Dir(sbomPath), attachArgs.Cwd)
+	require.Equal(t, []string{
+		"attach", "contoso.azurecr.io/my-project/my-service@sha256:4f2d",
+		"api-sbom.cdx.json:application/vnd.cyclonedx+json",
+		"--artifact-type", "application/vnd.cyclonedx+json",
+		"--format", "json",
+	}, attachArgs.Args)
+
+	details := deployResult.Details.(*dockerDeployResult)
+	require.Equal(t, "contoso.azurecr.io/my-project/my-service@sha256:7c1a", details.Sbom)
+	require.Equal(t, "sha256:4f2d", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+	require.Equal(t, details.Sbom, env.GetServiceProperty("api", "IMAGE_SBOM"))
+	require.Empty(t, env.GetServiceProperty("api", "IMAGE_SIGNATURE"))
+}
+
+func newTestDockerProject(mockContext *mocks.MockContext) CompositeFrameworkService {
+	env := environment.NewWithValues("test", map[string]string{})
+	dockerCli := docker.NewCli(mockContext.CommandRunner)
+
+	return NewDockerProject(
+		env,
+		dockerCli,
+		NewContainerHelper(
+			env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, nil, dockerCli, mockContext.Console,
+			cloud.AzurePublic(), nil, nil, nil, nil, nil,
+		),
+		mockinput.NewMockConsole(),
+		mockContext.AlphaFeaturesManager,
+		mockContext.CommandRunner)
+}
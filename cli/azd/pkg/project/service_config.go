@@ -24,6 +24,15 @@ type ServiceConfig struct {
 	RelativePath string `yaml:"project"`
 	// The azure hosting model to use, ex) appservice, function, containerapp
 	Host ServiceTargetKind `yaml:"host"`
+	// The hosts of a service deployed to more than one azure hosting model, selected per environment.
+	// When set, host defaults to the first of the hosts
+	Hosts []ServiceHostConfig `yaml:"hosts,omitempty"`
+	// The names of the services deployed before the service, ex) a backend whose endpoint the service is built with
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+	// The optional deploy options, ex) the timeout of the deployment
+	Deploy ServiceDeployOptions `yaml:"deploy,omitempty"`
+	// When configured, the service endpoint is probed after the deployment to verify the service is healthy
+	HealthCheck *HealthCheckOptions `yaml:"healthCheck,omitempty"`
 	// The programming language of the project
 	Language ServiceLanguageKind `yaml:"language"`
 	// The output path for build artifacts
@@ -34,8 +43,30 @@ type ServiceConfig struct {
 	Docker DockerProjectOptions `yaml:"docker,omitempty"`
 	// The optional K8S / AKS options
 	K8s AksOptions `yaml:"k8s,omitempty"`
+	// The optional Azure Container Apps options
+	ContainerApp ContainerAppOptions `yaml:"containerApp,omitempty"`
+	// The optional Azure Container Apps Jobs options
+	Job ContainerAppJobOptions `yaml:"job,omitempty"`
+	// The optional Azure App Service options
+	AppService AppServiceOptions `yaml:"appService,omitempty"`
+	// The optional Azure Functions options
+	FunctionApp FunctionAppOptions `yaml:"functionApp,omitempty"`
+	// The Azure Static Web Apps options, set by 'azd deploy --preview-env'
+	StaticWebApp StaticWebAppOptions `yaml:"-"`
 	// The optional Azure Spring Apps options
 	Spring SpringOptions `yaml:"spring,omitempty"`
+	// The optional virtual machine options
+	VirtualMachine VirtualMachineOptions `yaml:"vm,omitempty"`
+	// The optional Azure API Management options
+	Apim ApimOptions `yaml:"apim,omitempty"`
+	// The optional IoT Edge options
+	IotEdge IotEdgeOptions `yaml:"iotEdge,omitempty"`
+	// The optional Azure Storage static website options
+	StorageWebsite StorageWebsiteOptions `yaml:"storageWebsite,omitempty"`
+	// The optional Azure Batch options
+	Batch BatchOptions `yaml:"batch,omitempty"`
+	// The optional Dapr options of AKS and Container Apps services
+	Dapr *DaprOptions `yaml:"dapr,omitempty"`
 	// The infrastructure provisioning configuration
 	Infra provisioning.Options `yaml:"infra,omitempty"`
 	// Hook configuration for service
@@ -43,10 +74,20 @@ type ServiceConfig struct {
 	// Options specific to the DotNetContainerApp target. These are set by the importer and
 	// can not be controlled via the project file today.
 	DotNetContainerApp *DotNetContainerAppOptions `yaml:"-,omitempty"`
+	// Options of a service imported from a docker-compose file. These are set by the importer and
+	// can not be controlled via the project file.
+	Compose *ComposeServiceOptions `yaml:"-"`
 	// Custom configuration for the service target
 	Config map[string]any `yaml:"config,omitempty"`
 
 	*ext.EventDispatcher[ServiceLifecycleEventArgs] `yaml:"-"`
+
+	// The environment the hosts of the service were selected for
+	hostsEnv string
+	// The services deployed to the selected hosts of the service after the first
+	hostTargets []*ServiceConfig
+	// The name of the service qualified by its host when the service is deployed to more than one host
+	targetName string
 }
 
 type DotNetContainerAppOptions struct {
@@ -0,0 +1,144 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// dependencies returns the names of the services the service depends on, either listed in 'dependsOn' or by the
+// compose file the service is imported from
+func (sc *ServiceConfig) dependencies() []string {
+	dependencies := slices.Clone(sc.DependsOn)
+	if sc.Compose != nil {
+		dependencies = append(dependencies, sc.Compose.DependsOn...)
+	}
+
+	slices.Sort(dependencies)
+	return slices.Compact(dependencies)
+}
+
+// sortByDependencies orders the services so that services are deployed after the services they depend on.
+// Services are otherwise kept in their order, ex) by name.
+func sortByDependencies(services []*ServiceConfig) ([]*ServiceConfig, error) {
+	byName := map[string]*ServiceConfig{}
+	for _, svc := range services {
+		byName[svc.Name] = svc
+	}
+
+	sorted := make([]*ServiceConfig, 0, len(services))
+	visited := map[string]bool{}
+	visiting := map[string]bool{}
+
+	var visit func(svc *ServiceConfig) error
+	visit = func(svc *ServiceConfig) error {
+		if visited[svc.Name] {
+			return nil
+		}
+
+		if visiting[svc.Name] {
+			return fmt.Errorf("the service '%s' has a circular dependency", svc.Name)
+		}
+		visiting[svc.Name] = true
+
+		for _, dependency := range svc.dependencies() {
+			dependencySvc, has := byName[dependency]
+			if !has {
+				// The compose services a compose service depends on are not necessarily services of the project,
+				// ex) a database run only locally
+				if slices.Contains(svc.DependsOn, dependency) {
+					return fmt.Errorf("the service '%s' depends on the unknown service '%s'", svc.Name, dependency)
+				}
+
+				continue
+			}
+
+			if err := visit(dependencySvc); err != nil {
+				return err
+			}
+		}
+
+		visiting[svc.Name] = false
+		visited[svc.Name] = true
+		sorted = append(sorted, svc)
+		return nil
+	}
+
+	for _, svc := range services {
+		if err := visit(svc); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// RunInDependencyOrder runs the function for each of the services, ordered by ImportManager.ServiceStable, running up
+// to maxParallel of the services at a time. A service is run once all the services it depends on have completed,
+// dependencies not part of the services are ignored, ex) when a single service is deployed.
+// No service is started once a service fails, the error of the first failed service is returned once the running
+// services complete.
+func RunInDependencyOrder(
+	ctx context.Context,
+	services []*ServiceConfig,
+	maxParallel int,
+	fn func(ctx context.Context, svc *ServiceConfig) error,
+) error {
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	// The services deployed to more than one host are only complete once deployed to each of their hosts
+	remaining := map[string]int{}
+	for _, svc := range services {
+		remaining[svc.Name]++
+	}
+
+	ready := func(svc *ServiceConfig) bool {
+		return !slices.ContainsFunc(svc.dependencies(), func(dependency string) bool {
+			return remaining[dependency] > 0
+		})
+	}
+
+	type result struct {
+		svc *ServiceConfig
+		err error
+	}
+
+	results := make(chan result)
+	started := make([]bool, len(services))
+	running := 0
+
+	var firstErr error
+	for {
+		for i, svc := range services {
+			if firstErr != nil || running >= maxParallel {
+				break
+			}
+
+			if started[i] || !ready(svc) {
+				continue
+			}
+
+			started[i] = true
+			running++
+			go func() {
+				results <- result{svc: svc, err: fn(ctx, svc)}
+			}()
+		}
+
+		if running == 0 {
+			return firstErr
+		}
+
+		completed := <-results
+		running--
+		remaining[completed.svc.Name]--
+		if completed.err != nil && firstErr == nil {
+			firstErr = completed.err
+		}
+	}
+}
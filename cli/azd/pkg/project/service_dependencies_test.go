@@ -0,0 +1,127 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sortByDependencies(t *testing.T) {
+	t.Run("DependsOn", func(t *testing.T) {
+		services := []*ServiceConfig{
+			{Name: "api", DependsOn: []string{"db"}},
+			{Name: "db"},
+			{Name: "web", DependsOn: []string{"api"}},
+			{Name: "worker"},
+		}
+
+		sorted, err := sortByDependencies(services)
+		require.NoError(t, err)
+		require.Equal(t, []string{"db", "api", "web", "worker"}, serviceNames(sorted))
+	})
+
+	t.Run("UnknownService", func(t *testing.T) {
+		_, err := sortByDependencies([]*ServiceConfig{{Name: "web", DependsOn: []string{"api"}}})
+		require.ErrorContains(t, err, "the service 'web' depends on the unknown service 'api'")
+	})
+
+	t.Run("UnknownComposeService", func(t *testing.T) {
+		sorted, err := sortByDependencies([]*ServiceConfig{
+			{Name: "web", Compose: &ComposeServiceOptions{DependsOn: []string{"redis"}}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"web"}, serviceNames(sorted))
+	})
+
+	t.Run("Circular", func(t *testing.T) {
+		_, err := sortByDependencies([]*ServiceConfig{
+			{Name: "api", DependsOn: []string{"web"}},
+			{Name: "web", DependsOn: []string{"api"}},
+		})
+		require.ErrorContains(t, err, "the service 'api' has a circular dependency")
+	})
+}
+
+func Test_RunInDependencyOrder(t *testing.T) {
+	api := &ServiceConfig{Name: "api"}
+	worker := &ServiceConfig{Name: "worker"}
+	web := &ServiceConfig{Name: "web", DependsOn: []string{"api", "worker"}}
+	services := []*ServiceConfig{api, worker, web}
+
+	t.Run("Parallel", func(t *testing.T) {
+		// api and worker only complete once both are running, web is started once both are complete
+		var mu sync.Mutex
+		completed := []string{}
+		started := sync.WaitGroup{}
+		started.Add(2)
+
+		err := RunInDependencyOrder(context.Background(), services, 3, func(ctx context.Context, svc *ServiceConfig) error {
+			if svc != web {
+				started.Done()
+				started.Wait()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if svc == web {
+				require.ElementsMatch(t, []string{"api", "worker"}, completed)
+			}
+
+			completed = append(completed, svc.Name)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "web", completed[2])
+	})
+
+	t.Run("Sequential", func(t *testing.T) {
+		completed := []string{}
+		err := RunInDependencyOrder(context.Background(), services, 1, func(ctx context.Context, svc *ServiceConfig) error {
+			completed = append(completed, svc.Name)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"api", "worker", "web"}, completed)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		completed := []string{}
+		err := RunInDependencyOrder(context.Background(), services, 1, func(ctx context.Context, svc *ServiceConfig) error {
+			if svc == worker {
+				return errors.New("deploying worker")
+			}
+
+			completed = append(completed, svc.Name)
+			return nil
+		})
+		require.EqualError(t, err, "deploying worker")
+		require.Equal(t, []string{"api"}, completed)
+	})
+
+	t.Run("DependencyNotDeployed", func(t *testing.T) {
+		completed := []string{}
+		err := RunInDependencyOrder(context.Background(), []*ServiceConfig{web}, 2,
+			func(ctx context.Context, svc *ServiceConfig) error {
+				completed = append(completed, svc.Name)
+				return nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, []string{"web"}, completed)
+	})
+}
+
+func serviceNames(services []*ServiceConfig) []string {
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		names = append(names, svc.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The deploy options of a service
+type ServiceDeployOptions struct {
+	// The maximum duration of the deployment of the service, including the deploy hooks (ex: 15m).
+	// The deployment is canceled and fails once the timeout expires. Defaults to no timeout
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// withDeployTimeout returns the context the service is deployed with, canceled once the deploy timeout of the service
+// expires
+func withDeployTimeout(ctx context.Context, serviceConfig *ServiceConfig) (context.Context, context.CancelFunc) {
+	if serviceConfig.Deploy.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, serviceConfig.Deploy.Timeout)
+}
+
+// deployTimeoutError reports the deployment of the service as timed out when the deploy timeout of the service expired,
+// rather than the deployment being canceled by the caller
+func deployTimeoutError(ctx context.Context, deployCtx context.Context, serviceConfig *ServiceConfig, err error) error {
+	if ctx.Err() == nil && errors.Is(deployCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf(
+			"deploying service '%s' timed out after %s, increase 'deploy.timeout' of the service: %w",
+			serviceConfig.Name,
+			serviceConfig.Deploy.Timeout,
+			err,
+		)
+	}
+
+	return err
+}
@@ -0,0 +1,161 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ServiceEndpointKind is the kind of resource exposing an endpoint, normalized across the service targets
+type ServiceEndpointKind string
+
+const (
+	// The host name of the Azure resource hosting the service, ex) an app service or a container app
+	ServiceEndpointKindHost ServiceEndpointKind = "host"
+	// The host name of a deployment slot of an app service or a function app
+	ServiceEndpointKindSlot ServiceEndpointKind = "slot"
+	// A port of a k8s service
+	ServiceEndpointKindService ServiceEndpointKind = "service"
+	// A path of a k8s ingress
+	ServiceEndpointKindIngress ServiceEndpointKind = "ingress"
+	// A route of a k8s gateway
+	ServiceEndpointKindRoute ServiceEndpointKind = "route"
+)
+
+var (
+	// The url and identifying information of an endpoint, ex) http://1.1.1.1:8080 (Service: api, Type: LoadBalancer)
+	describedEndpointRegex = regexp.MustCompile(`^(\S+)\s*(?:\((.*)\))?$`)
+	// The label and url of an endpoint, ex) Slot staging: https://app-staging.azurewebsites.net/
+	labeledEndpointRegex = regexp.MustCompile(`^(.+?):\s+(\S+)$`)
+)
+
+// ParseServiceEndpoints returns the structured details of the endpoints reported by a service target, so IDEs and
+// scripts don't have to parse their descriptions. Endpoints without a url, ex) the node ports of a NodePort service
+// without known node addresses, are skipped.
+func ParseServiceEndpoints(endpoints []string) []ServiceEndpoint {
+	details := []ServiceEndpoint{}
+	for _, endpoint := range endpoints {
+		if detail, ok := parseServiceEndpoint(strings.TrimSpace(endpoint)); ok {
+			details = append(details, detail)
+		}
+	}
+
+	return details
+}
+
+// parseServiceEndpoint returns the structured details of the endpoint, false when the endpoint has no url
+func parseServiceEndpoint(endpoint string) (ServiceEndpoint, bool) {
+	matches := describedEndpointRegex.FindStringSubmatch(endpoint)
+	if len(matches) < 2 {
+		return parseLabeledEndpoint(endpoint)
+	}
+
+	detail, ok := newServiceEndpoint(matches[1])
+	if !ok {
+		return parseLabeledEndpoint(endpoint)
+	}
+
+	// The identifying information is a list of either the source or key value pairs,
+	// ex) Ingress, Type: LoadBalancer, Cluster: aks-east
+	for _, part := range strings.Split(matches[2], ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), ":")
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "Service":
+			detail.Source = key
+			detail.Name = value
+		case "Type":
+			detail.Type = value
+		case "Gateway":
+			detail.Gateway = value
+		case "Cluster":
+			detail.Cluster = value
+		default:
+			if key != "" {
+				detail.Source = key
+			}
+		}
+	}
+
+	switch detail.Source {
+	case "Service":
+		detail.Kind = ServiceEndpointKindService
+	case "Ingress":
+		detail.Kind = ServiceEndpointKindIngress
+	case "HTTPRoute":
+		detail.Kind = ServiceEndpointKindRoute
+	}
+
+	// Cluster IPs are only reachable from within the cluster
+	if detail.Type == "ClusterIP" {
+		detail.Internal = true
+	}
+
+	return detail, true
+}
+
+// parseLabeledEndpoint returns the structured details of an endpoint prefixed with a label,
+// ex) Slot staging: https://app-staging.azurewebsites.net/
+func parseLabeledEndpoint(endpoint string) (ServiceEndpoint, bool) {
+	matches := labeledEndpointRegex.FindStringSubmatch(endpoint)
+	if len(matches) < 3 {
+		return ServiceEndpoint{}, false
+	}
+
+	detail, ok := newServiceEndpoint(matches[2])
+	if !ok {
+		return ServiceEndpoint{}, false
+	}
+
+	detail.Name = matches[1]
+	if slot, has := strings.CutPrefix(matches[1], "Slot "); has {
+		detail.Kind = ServiceEndpointKindSlot
+		detail.Name = slot
+	}
+
+	return detail, true
+}
+
+// newServiceEndpoint returns the details of the endpoint url, false when the url is not absolute
+func newServiceEndpoint(endpointUrl string) (ServiceEndpoint, bool) {
+	parsed, err := url.Parse(endpointUrl)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return ServiceEndpoint{}, false
+	}
+
+	detail := ServiceEndpoint{
+		Url:      endpointUrl,
+		Kind:     ServiceEndpointKindHost,
+		Protocol: parsed.Scheme,
+		Host:     parsed.Hostname(),
+		Path:     parsed.Path,
+		Internal: isInternalHost(parsed.Hostname()),
+	}
+
+	if port, err := strconv.Atoi(parsed.Port()); err == nil {
+		detail.Port = port
+	} else if detail.Protocol == "https" {
+		detail.Port = 443
+	} else {
+		detail.Port = 80
+	}
+
+	return detail, true
+}
+
+// isInternalHost returns true when the host is only reachable from a private network, ex) a private IP address or the
+// fqdn of a container app of an internal managed environment
+func isInternalHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsPrivate() || ip.IsLoopback()
+	}
+
+	host = strings.ToLower(host)
+	return host == "localhost" || strings.Contains(host, ".internal.")
+}
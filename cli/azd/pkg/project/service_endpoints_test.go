@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseServiceEndpoints(t *testing.T) {
+	details := ParseServiceEndpoints([]string{
+		"http://1.1.1.1:8080 (HTTPRoute, Gateway: public, Cluster: aks-east)",
+		"Node port 30080 (Service: api, Type: NodePort)",
+		"Slot staging: https://app-staging.azurewebsites.net/",
+		"https://app.internal.happyhill-01.eastus2.azurecontainerapps.io/",
+		"https://api.contoso.com/",
+	})
+
+	require.Equal(t, []ServiceEndpoint{
+		{
+			Url:      "http://1.1.1.1:8080",
+			Kind:     ServiceEndpointKindRoute,
+			Protocol: "http",
+			Host:     "1.1.1.1",
+			Port:     8080,
+			Source:   "HTTPRoute",
+			Gateway:  "public",
+			Cluster:  "aks-east",
+		},
+		{
+			Url:      "https://app-staging.azurewebsites.net/",
+			Kind:     ServiceEndpointKindSlot,
+			Protocol: "https",
+			Host:     "app-staging.azurewebsites.net",
+			Port:     443,
+			Path:     "/",
+			Name:     "staging",
+		},
+		{
+			Url:      "https://app.internal.happyhill-01.eastus2.azurecontainerapps.io/",
+			Kind:     ServiceEndpointKindHost,
+			Protocol: "https",
+			Host:     "app.internal.happyhill-01.eastus2.azurecontainerapps.io",
+			Port:     443,
+			Path:     "/",
+			Internal: true,
+		},
+		{
+			Url:      "https://api.contoso.com/",
+			Kind:     ServiceEndpointKindHost,
+			Protocol: "https",
+			Host:     "api.contoso.com",
+			Port:     443,
+			Path:     "/",
+		},
+	}, details)
+}
@@ -0,0 +1,140 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/sethvargo/go-retry"
+)
+
+const (
+	defaultHealthCheckRetries  = 5
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckTimeout  = 30 * time.Second
+)
+
+// The health check options
+// When configured, the service endpoint is probed with an HTTP GET request after the deployment and the deployment
+// fails when the endpoint does not respond with the expected status code
+type HealthCheckOptions struct {
+	// The url of the probed endpoint. Defaults to the last http endpoint of the service, the most publicly exposed
+	// endpoint of AKS services
+	Url osutil.ExpandableString `yaml:"url,omitempty"`
+	// The path of the probed endpoint, ex) /health. Defaults to the endpoint path
+	Path string `yaml:"path,omitempty"`
+	// The expected response status code. Defaults to any 2xx status code
+	ExpectedStatus int `yaml:"expectedStatus,omitempty"`
+	// The number of retries before the deployment fails. Defaults to 5
+	Retries *int `yaml:"retries,omitempty"`
+	// The interval between retries (ex: 5s). Defaults to 10 seconds
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// The timeout of each request (ex: 10s). Defaults to 30 seconds
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// checkHealth probes the service endpoint until it responds with the expected status code or the retries are exhausted
+func checkHealth(
+	ctx context.Context,
+	transporter policy.Transporter,
+	getenv func(string) string,
+	serviceConfig *ServiceConfig,
+	options *HealthCheckOptions,
+	endpoints []string,
+) error {
+	endpointUrl, err := options.Url.Envsubst(getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst health check url: %w", err)
+	}
+
+	if endpointUrl == "" {
+		endpointUrl = healthCheckEndpoint(endpoints)
+	}
+
+	if endpointUrl == "" {
+		return fmt.Errorf("no endpoint found to check the health of service '%s'", serviceConfig.Name)
+	}
+
+	if options.Path != "" {
+		if endpointUrl, err = url.JoinPath(endpointUrl, options.Path); err != nil {
+			return fmt.Errorf("failed constructing health check url, %w", err)
+		}
+	}
+
+	retries := defaultHealthCheckRetries
+	if options.Retries != nil {
+		retries = *options.Retries
+	}
+
+	interval := options.Interval
+	if interval == 0 {
+		interval = defaultHealthCheckInterval
+	}
+
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+
+	return retry.Do(
+		ctx,
+		retry.WithMaxRetries(uint64(retries), retry.NewConstant(interval)),
+		func(ctx context.Context) error {
+			return retry.RetryableError(probeEndpoint(ctx, transporter, endpointUrl, options.ExpectedStatus, timeout))
+		},
+	)
+}
+
+// healthCheckEndpoint returns the url of the last http endpoint, without the description of the endpoint,
+// ex) http://10.10.10.10:80 (Service: api-service, Type: ClusterIP)
+func healthCheckEndpoint(endpoints []string) string {
+	for i := len(endpoints) - 1; i >= 0; i-- {
+		matches := endpointRegex.FindStringSubmatch(endpoints[i])
+		if len(matches) > 1 && (strings.HasPrefix(matches[1], "http://") || strings.HasPrefix(matches[1], "https://")) {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
+
+// probeEndpoint sends a single HTTP GET request to the endpoint and verifies the response status code
+func probeEndpoint(
+	ctx context.Context,
+	transporter policy.Transporter,
+	endpointUrl string,
+	expectedStatus int,
+	timeout time.Duration,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpointUrl, nil)
+	if err != nil {
+		return fmt.Errorf("failed creating health check request, %w", err)
+	}
+
+	response, err := transporter.Do(request)
+	if err != nil {
+		return fmt.Errorf("health check of '%s' failed, %w", endpointUrl, err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	healthy := response.StatusCode >= 200 && response.StatusCode < 300
+	if expectedStatus != 0 {
+		healthy = response.StatusCode == expectedStatus
+	}
+
+	if !healthy {
+		return fmt.Errorf("health check of '%s' returned status code %d", endpointUrl, response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package project
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckHealth(t *testing.T) {
+	endpoints := []string{
+		"http://10.10.10.10:80 (Service: api-service, Type: ClusterIP)",
+		"http://api.contoso.com (Ingress, Type: LoadBalancer)",
+	}
+
+	tests := map[string]struct {
+		options       *HealthCheckOptions
+		statusCodes   []int
+		expectedUrl   string
+		expectedCalls int
+		expectedError string
+	}{
+		"Healthy": {
+			options:       &HealthCheckOptions{Path: "/health"},
+			statusCodes:   []int{http.StatusOK},
+			expectedUrl:   "http://api.contoso.com/health",
+			expectedCalls: 1,
+		},
+		"RecoversAfterRetry": {
+			options:       &HealthCheckOptions{},
+			statusCodes:   []int{http.StatusServiceUnavailable, http.StatusNoContent},
+			expectedUrl:   "http://api.contoso.com",
+			expectedCalls: 2,
+		},
+		"ExpectedStatus": {
+			options:       &HealthCheckOptions{ExpectedStatus: http.StatusUnauthorized},
+			statusCodes:   []int{http.StatusUnauthorized},
+			expectedUrl:   "http://api.contoso.com",
+			expectedCalls: 1,
+		},
+		"Url": {
+			options: &HealthCheckOptions{
+				Url:  osutil.NewExpandableString("https://${API_HOST}"),
+				Path: "/ready",
+			},
+			statusCodes:   []int{http.StatusOK},
+			expectedUrl:   "https://api.test.contoso.com/ready",
+			expectedCalls: 1,
+		},
+		"Unhealthy": {
+			options:       &HealthCheckOptions{Retries: to.Ptr(2)},
+			statusCodes:   []int{http.StatusInternalServerError},
+			expectedUrl:   "http://api.contoso.com",
+			expectedCalls: 3,
+			expectedError: "health check of 'http://api.contoso.com' returned status code 500",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+
+			requestUrls := []string{}
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodGet
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				requestUrls = append(requestUrls, request.URL.String())
+				statusCode := test.statusCodes[min(len(requestUrls), len(test.statusCodes))-1]
+
+				return mocks.CreateEmptyHttpResponse(request, statusCode)
+			})
+
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			test.options.Interval = time.Millisecond
+
+			env := createEnv()
+			env.DotenvSet("API_HOST", "api.test.contoso.com")
+
+			err := checkHealth(
+				*mockContext.Context, mockContext.HttpClient, env.Getenv, serviceConfig, test.options, endpoints)
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, requestUrls, test.expectedCalls)
+			require.Equal(t, test.expectedUrl, requestUrls[0])
+		})
+	}
+}
+
+func Test_CheckHealth_No_Endpoints(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+
+	// Endpoints other than http endpoints are not probed
+	endpoints := []string{"grpc://api.contoso.com:50051"}
+	err := checkHealth(
+		*mockContext.Context, mockContext.HttpClient, createEnv().Getenv, serviceConfig, &HealthCheckOptions{}, endpoints)
+
+	require.ErrorContains(t, err, "no endpoint found to check the health of service 'api'")
+}
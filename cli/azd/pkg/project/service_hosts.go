@@ -0,0 +1,159 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The service property overriding the hosts a service is deployed to, ex) SERVICE_API_HOST=containerapp,function
+const serviceHostProperty = "HOST"
+
+// ServiceHostConfig is one of the hosts of a service deployed to more than one azure hosting model
+type ServiceHostConfig struct {
+	// The azure hosting model, ex) containerapp, aks, function
+	Host ServiceTargetKind `yaml:"host"`
+	// The name of the resource the service is deployed to on the host. Defaults to the resource name of the service.
+	// Required when the resources of more than one host are tagged with the name of the service in the resource group
+	ResourceName osutil.ExpandableString `yaml:"resourceName,omitempty"`
+	// The environments the service is deployed to the host in, ex) [prod]. All environments when empty
+	Environments []string `yaml:"environments,omitempty"`
+}
+
+// TargetName returns the name of the service, qualified by its host when the service is deployed to more than one
+// host in the environment, ex) api (aks)
+func (sc *ServiceConfig) TargetName() string {
+	if sc.targetName != "" {
+		return sc.targetName
+	}
+
+	return sc.Name
+}
+
+// parseServiceHosts validates the hosts of the service and defaults the host of the service to the first of them
+func parseServiceHosts(svc *ServiceConfig) error {
+	for i := range svc.Hosts {
+		host, err := parseServiceHost(svc.Hosts[i].Host)
+		if err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(svc.Hosts[:i], func(h ServiceHostConfig) bool { return h.Host == host }) {
+			return fmt.Errorf("host '%s' is listed more than once in 'hosts'", host)
+		}
+
+		svc.Hosts[i].Host = host
+	}
+
+	if svc.Host == "" {
+		svc.Host = svc.Hosts[0].Host
+	} else if !slices.ContainsFunc(svc.Hosts, func(h ServiceHostConfig) bool { return h.Host == svc.Host }) {
+		return fmt.Errorf("host '%s' is not one of the hosts listed in 'hosts'", svc.Host)
+	}
+
+	return nil
+}
+
+// selectServiceHosts selects the hosts the services listing more than one host are deployed to in the environment.
+// The first selected host becomes the host of the service, the other selected hosts are deployed by copies of the
+// service returned after the service by ImportManager.ServiceStable
+func selectServiceHosts(projectConfig *ProjectConfig, env *environment.Environment) error {
+	for _, svc := range projectConfig.Services {
+		if len(svc.Hosts) == 0 || svc.hostsEnv == env.Name() {
+			continue
+		}
+
+		selected, err := selectedServiceHosts(svc, env)
+		if err != nil {
+			return err
+		}
+
+		svc.Host = selected[0].Host
+		if !selected[0].ResourceName.Empty() {
+			svc.ResourceName = selected[0].ResourceName
+		}
+
+		svc.targetName = ""
+		svc.hostTargets = nil
+		if len(selected) > 1 {
+			svc.targetName = fmt.Sprintf("%s (%s)", svc.Name, svc.Host)
+		}
+
+		for _, host := range selected[1:] {
+			// The copy shares the event dispatcher of the service, the hooks of the service run for every host
+			target := *svc
+			target.Host = host.Host
+			if !host.ResourceName.Empty() {
+				target.ResourceName = host.ResourceName
+			}
+
+			target.targetName = fmt.Sprintf("%s (%s)", svc.Name, host.Host)
+			target.hostTargets = nil
+			svc.hostTargets = append(svc.hostTargets, &target)
+		}
+
+		svc.hostsEnv = env.Name()
+	}
+
+	return nil
+}
+
+// selectedServiceHosts returns the hosts of the service selected by the host property of the service in the
+// environment, or else the hosts that list the environment or that don't list any environment
+func selectedServiceHosts(svc *ServiceConfig, env *environment.Environment) ([]ServiceHostConfig, error) {
+	selected := []ServiceHostConfig{}
+
+	if hosts := env.GetServiceProperty(svc.Name, serviceHostProperty); hosts != "" {
+		for _, name := range strings.Split(hosts, ",") {
+			name = strings.TrimSpace(name)
+			index := slices.IndexFunc(svc.Hosts, func(h ServiceHostConfig) bool { return string(h.Host) == name })
+			if index < 0 {
+				return nil, fmt.Errorf(
+					"service '%s' is set to deploy to host '%s' which is not one of the hosts listed in 'hosts'",
+					svc.Name,
+					name,
+				)
+			}
+
+			selected = append(selected, svc.Hosts[index])
+		}
+
+		return selected, nil
+	}
+
+	for _, host := range svc.Hosts {
+		if len(host.Environments) == 0 || slices.Contains(host.Environments, env.Name()) {
+			selected = append(selected, host)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf(
+			"service '%s' has no host for environment '%s', add the environment to the environments of one of "+
+				"its hosts or set SERVICE_%s_%s",
+			svc.Name,
+			env.Name(),
+			strings.ReplaceAll(strings.ToUpper(svc.Name), "-", "_"),
+			serviceHostProperty,
+		)
+	}
+
+	return selected, nil
+}
+
+// withServiceHostTargets returns the services followed by the copies deploying them to their other selected hosts
+func withServiceHostTargets(services []*ServiceConfig) []*ServiceConfig {
+	expanded := make([]*ServiceConfig, 0, len(services))
+	for _, svc := range services {
+		expanded = append(expanded, svc)
+		expanded = append(expanded, svc.hostTargets...)
+	}
+
+	return expanded
+}
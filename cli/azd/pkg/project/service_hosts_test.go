@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const multiHostProject = `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    hosts:
+      - host: containerapp
+        environments: [prod]
+      - host: aks
+        environments: [internal]
+      - host: function
+        resourceName: func-${AZURE_ENV_NAME}
+        environments: [prod, internal]
+`
+
+func Test_ServiceHosts(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	t.Run("Parse", func(t *testing.T) {
+		projectConfig, err := Parse(*mockContext.Context, multiHostProject)
+		require.NoError(t, err)
+
+		api := projectConfig.Services["api"]
+		require.Equal(t, ContainerAppTarget, api.Host)
+		require.Equal(t, []ServiceTargetKind{ContainerAppTarget, AksTarget, AzureFunctionTarget}, []ServiceTargetKind{
+			api.Hosts[0].Host, api.Hosts[1].Host, api.Hosts[2].Host,
+		})
+	})
+
+	t.Run("SelectByEnvironment", func(t *testing.T) {
+		projectConfig, err := Parse(*mockContext.Context, multiHostProject)
+		require.NoError(t, err)
+
+		env := environment.NewWithValues("internal", nil)
+		require.NoError(t, selectServiceHosts(projectConfig, env))
+
+		api := projectConfig.Services["api"]
+		require.Equal(t, AksTarget, api.Host)
+		require.Equal(t, "api (aks)", api.TargetName())
+		require.Len(t, api.hostTargets, 1)
+
+		function := api.hostTargets[0]
+		require.Equal(t, "api", function.Name)
+		require.Equal(t, AzureFunctionTarget, function.Host)
+		require.Equal(t, "api (function)", function.TargetName())
+		require.Equal(t, "func-internal", function.ResourceName.MustEnvsubst(env.Getenv))
+		require.Same(t, api.EventDispatcher, function.EventDispatcher)
+
+		require.Equal(t, []*ServiceConfig{api, function}, withServiceHostTargets([]*ServiceConfig{api}))
+	})
+
+	t.Run("SelectByHostProperty", func(t *testing.T) {
+		projectConfig, err := Parse(*mockContext.Context, multiHostProject)
+		require.NoError(t, err)
+
+		env := environment.NewWithValues("prod", map[string]string{"SERVICE_API_HOST": "function"})
+		require.NoError(t, selectServiceHosts(projectConfig, env))
+
+		api := projectConfig.Services["api"]
+		require.Equal(t, AzureFunctionTarget, api.Host)
+		require.Equal(t, "api", api.TargetName())
+		require.Empty(t, api.hostTargets)
+	})
+
+	t.Run("NoHostForEnvironment", func(t *testing.T) {
+		projectConfig, err := Parse(*mockContext.Context, multiHostProject)
+		require.NoError(t, err)
+
+		env := environment.NewWithValues("dev", nil)
+		err = selectServiceHosts(projectConfig, env)
+		require.ErrorContains(t, err, "service 'api' has no host for environment 'dev'")
+		require.ErrorContains(t, err, "SERVICE_API_HOST")
+	})
+
+	t.Run("UnlistedHostProperty", func(t *testing.T) {
+		projectConfig, err := Parse(*mockContext.Context, multiHostProject)
+		require.NoError(t, err)
+
+		env := environment.NewWithValues("prod", map[string]string{"SERVICE_API_HOST": "appservice"})
+		err = selectServiceHosts(projectConfig, env)
+		require.ErrorContains(t, err, "host 'appservice' which is not one of the hosts listed in 'hosts'")
+	})
+
+	t.Run("InvalidHosts", func(t *testing.T) {
+		_, err := Parse(*mockContext.Context, `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    host: appservice
+    hosts:
+      - host: containerapp
+`)
+		require.ErrorContains(t, err, "host 'appservice' is not one of the hosts listed in 'hosts'")
+
+		_, err = Parse(*mockContext.Context, `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    hosts:
+      - host: containerapp
+      - host: containerapp
+`)
+		require.ErrorContains(t, err, "host 'containerapp' is listed more than once in 'hosts'")
+	})
+}
@@ -0,0 +1,87 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// serviceLogSource streams the logs of a single source, ex) a container of a pod, to the writer
+type serviceLogSource struct {
+	name   string
+	stream func(ctx context.Context, writer io.Writer) error
+}
+
+// streamServiceLogs streams the logs of the sources concurrently to the writer
+// When there is more than one source, each line is prefixed with the name of the source it was written by.
+// The errors of the sources are returned once all sources completed.
+func streamServiceLogs(ctx context.Context, sources []serviceLogSource, writer io.Writer) error {
+	if len(sources) == 1 {
+		return sources[0].stream(ctx, writer)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(sources))
+
+	for i, source := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			lineWriter := &prefixLineWriter{prefix: fmt.Sprintf("[%s] ", source.name), writer: writer, mu: &mu}
+			if err := source.stream(ctx, lineWriter); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", source.name, err)
+			}
+			lineWriter.Flush()
+		}()
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// prefixLineWriter prefixes each complete line with the prefix before writing it to the shared writer
+// Lines are written while holding the mutex so the lines of concurrent writers are not interleaved.
+type prefixLineWriter struct {
+	prefix string
+	writer io.Writer
+	mu     *sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (w *prefixLineWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+
+	for {
+		index := bytes.IndexByte(w.buffer.Bytes(), '\n')
+		if index < 0 {
+			break
+		}
+
+		if err := w.writeLine(w.buffer.Next(index + 1)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the remaining incomplete line, if any
+func (w *prefixLineWriter) Flush() {
+	if w.buffer.Len() > 0 {
+		_ = w.writeLine(append(w.buffer.Bytes(), '\n'))
+		w.buffer.Reset()
+	}
+}
+
+func (w *prefixLineWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.writer.Write(append([]byte(w.prefix), line...))
+	return err
+}
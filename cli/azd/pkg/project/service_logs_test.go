@@ -0,0 +1,68 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StreamServiceLogs(t *testing.T) {
+	t.Run("SingleSource", func(t *testing.T) {
+		output := &strings.Builder{}
+		err := streamServiceLogs(context.Background(), []serviceLogSource{
+			{name: "api", stream: writeLogs("listening on port 3000\n")},
+		}, output)
+
+		require.NoError(t, err)
+		require.Equal(t, "listening on port 3000\n", output.String())
+	})
+
+	t.Run("MultipleSources", func(t *testing.T) {
+		output := &strings.Builder{}
+		err := streamServiceLogs(context.Background(), []serviceLogSource{
+			{name: "api-1", stream: writeLogs("starting\n", "listening ", "on port 3000\n")},
+			{name: "api-2", stream: writeLogs("starting\nno trailing newline")},
+		}, output)
+
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+		sort.Strings(lines)
+		require.Equal(t, []string{
+			"[api-1] listening on port 3000",
+			"[api-1] starting",
+			"[api-2] no trailing newline",
+			"[api-2] starting",
+		}, lines)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		output := &strings.Builder{}
+		err := streamServiceLogs(context.Background(), []serviceLogSource{
+			{name: "api-1", stream: writeLogs("starting\n")},
+			{name: "api-2", stream: func(ctx context.Context, writer io.Writer) error {
+				return errors.New("pod not found")
+			}},
+		}, output)
+
+		require.ErrorContains(t, err, "api-2: pod not found")
+		require.Equal(t, "[api-1] starting\n", output.String())
+	})
+}
+
+func writeLogs(chunks ...string) func(ctx context.Context, writer io.Writer) error {
+	return func(ctx context.Context, writer io.Writer) error {
+		for _, chunk := range chunks {
+			if _, err := writer.Write([]byte(chunk)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
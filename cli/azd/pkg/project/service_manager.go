@@ -10,11 +10,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -93,6 +96,55 @@ type ServiceManager interface {
 		progress *async.Progress[ServiceProgress],
 	) (*ServiceDeployResult, error)
 
+	// Previews the changes that deploying the generated artifacts would apply to the Azure resource
+	// that hosts the service application, without applying them.
+	// Returns ErrPreviewNotSupported when the service target does not support previews.
+	Preview(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		packageOutput *ServicePackageResult,
+		progress *async.Progress[ServiceProgress],
+	) (*ServicePreviewResult, error)
+
+	// Forwards local ports to the service deployed to the Azure resource until the context is cancelled
+	// Returns ErrPortForwardNotSupported when the service target does not support port forwarding.
+	PortForward(ctx context.Context, serviceConfig *ServiceConfig, options PortForwardOptions) error
+
+	// Streams the logs of the service deployed to the Azure resource
+	// Returns ErrLogsNotSupported when the service target does not support streaming logs.
+	StreamLogs(ctx context.Context, serviceConfig *ServiceConfig, options ServiceLogsOptions) error
+
+	// Executes a command in a running instance of the service deployed to the Azure resource
+	// Returns ErrExecNotSupported when the service target does not support executing commands.
+	Exec(ctx context.Context, serviceConfig *ServiceConfig, options ServiceExecOptions) error
+
+	// Runs kubectl against the cluster and namespace of the service deployed to the Azure resource
+	// Returns ErrKubectlNotSupported when the service target is not a k8s cluster.
+	RunKubectl(ctx context.Context, serviceConfig *ServiceConfig, args []string) error
+
+	// Reapplies a revision recorded within the deployment history of the service, or the previous revision when 0
+	// Returns ErrRollbackNotSupported when the service target does not record a deployment history.
+	Rollback(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		revision int,
+		progress *async.Progress[ServiceProgress],
+	) (*ServiceDeployResult, error)
+
+	// Lists the revisions of the service deployed to the Azure resource along with the traffic routed to them
+	// Returns ErrTrafficNotSupported when the service target does not deploy revisions.
+	Revisions(ctx context.Context, serviceConfig *ServiceConfig) ([]*ServiceRevision, error)
+
+	// Routes the percentage of the traffic of the service to the revision, or to the latest revision when empty
+	// Returns ErrTrafficNotSupported when the service target does not deploy revisions.
+	ShiftTraffic(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		revision string,
+		percent int,
+		progress *async.Progress[ServiceProgress],
+	) ([]*ServiceRevision, error)
+
 	// Gets the framework service for the specified service config
 	// The framework service performs the restoration and building of the service app code
 	GetFrameworkService(ctx context.Context, serviceConfig *ServiceConfig) (FrameworkService, error)
@@ -108,6 +160,9 @@ type ServiceManager interface {
 // The ServiceOperationCache is used as a singleton cache for all service manager instances
 type ServiceOperationCache map[string]any
 
+// operationCacheMu guards the operation cache shared by the service managers, services can be deployed in parallel
+var operationCacheMu sync.Mutex
+
 type serviceManager struct {
 	env                 *environment.Environment
 	resourceManager     ResourceManager
@@ -280,6 +335,14 @@ func (sm *serviceManager) Package(
 		return cachedResult.(*ServicePackageResult), nil
 	}
 
+	// Services deploying a prebuilt container image to AKS have nothing to build or package
+	if serviceConfig.Host == AksTarget && !serviceConfig.K8s.Image.Empty() {
+		packageResult := &ServicePackageResult{}
+		sm.setOperationResult(serviceConfig, string(ServiceEventPackage), packageResult)
+
+		return packageResult, nil
+	}
+
 	if buildOutput == nil {
 		cachedResult, ok := sm.getOperationResult(serviceConfig, string(ServiceEventBuild))
 		if ok && cachedResult != nil {
@@ -396,6 +459,26 @@ func (sm *serviceManager) Package(
 		packageResult.PackagePath = destFilePath
 	}
 
+	// The SBOM of container images is moved next to the package output for pipelines to publish
+	if packageResult.SbomPath != "" && options.OutputPath != "" {
+		destDirectory := options.OutputPath
+		if filepath.Ext(options.OutputPath) != "" {
+			destDirectory = filepath.Dir(options.OutputPath)
+		}
+
+		if err := os.MkdirAll(destDirectory, osutil.PermissionDirectory); err != nil {
+			return nil, fmt.Errorf("failed creating output directory '%s': %w", destDirectory, err)
+		}
+
+		destFilePath := filepath.Join(destDirectory, filepath.Base(packageResult.SbomPath))
+		if err := moveFile(packageResult.SbomPath, destFilePath); err != nil {
+			return nil, fmt.Errorf(
+				"failed moving sbom file '%s' to '%s': %w", packageResult.SbomPath, destFilePath, err)
+		}
+
+		packageResult.SbomPath = destFilePath
+	}
+
 	return packageResult, nil
 }
 
@@ -425,8 +508,287 @@ func (sm *serviceManager) Deploy(
 		return nil, fmt.Errorf("getting service target: %w", err)
 	}
 
-	var targetResource *environment.TargetResource
+	targetResource, err := sm.getTargetResource(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// The lock is held from the predeploy to the postdeploy hooks, which also use the CLIs configured for the service
+	unlock := serviceConfig.Host.lockDeploy()
+	defer unlock()
+
+	deployCtx, cancel := withDeployTimeout(ctx, serviceConfig)
+	defer cancel()
+
+	deployResult, err := runCommand(
+		deployCtx,
+		ServiceEventDeploy,
+		serviceConfig,
+		func() (*ServiceDeployResult, error) {
+			deployResult, err := serviceTarget.Deploy(deployCtx, serviceConfig, packageResult, targetResource, progress)
+			if err != nil {
+				return nil, err
+			}
+
+			sm.applyEndpoints(ctx, serviceConfig, deployResult)
+
+			// The health of the service is verified before the postdeploy hooks run
+			if serviceConfig.HealthCheck != nil && !deployResult.healthChecked {
+				progress.SetProgress(NewServiceProgress("Checking health of service"))
+				if err := sm.checkHealth(deployCtx, serviceConfig, deployResult.Endpoints); err != nil {
+					return nil, err
+				}
+			}
+
+			return deployResult, nil
+		},
+	)
+
+	if err != nil {
+		err = deployTimeoutError(ctx, deployCtx, serviceConfig, err)
+		return nil, fmt.Errorf("failed deploying service '%s': %w", serviceConfig.Name, err)
+	}
+
+	sm.setOperationResult(serviceConfig, string(ServiceEventDeploy), deployResult)
+	return deployResult, nil
+}
+
+// Previews the changes that deploying the generated artifacts would apply to the Azure resource
+// that hosts the service application, without applying them.
+func (sm *serviceManager) Preview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageResult *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePreviewResult, error) {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting service target: %w", err)
+	}
+
+	previewer, ok := serviceTarget.(ServiceTargetPreviewer)
+	if !ok {
+		return nil, fmt.Errorf("%w for service host '%s'", ErrPreviewNotSupported, serviceConfig.Host)
+	}
+
+	targetResource, err := sm.getTargetResource(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	previewResult, err := previewer.Preview(ctx, serviceConfig, packageResult, targetResource, progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed previewing deployment of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	return previewResult, nil
+}
 
+// Forwards local ports to the service deployed to the Azure resource until the context is cancelled
+func (sm *serviceManager) PortForward(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	options PortForwardOptions,
+) error {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return fmt.Errorf("getting service target: %w", err)
+	}
+
+	portForwarder, ok := serviceTarget.(ServiceTargetPortForwarder)
+	if !ok {
+		return fmt.Errorf("%w for service host '%s'", ErrPortForwardNotSupported, serviceConfig.Host)
+	}
+
+	targetResource, err := sm.getTargetResource(ctx, serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := portForwarder.PortForward(ctx, serviceConfig, targetResource, options); err != nil {
+		return fmt.Errorf("failed port forwarding to service '%s': %w", serviceConfig.Name, err)
+	}
+
+	return nil
+}
+
+// Streams the logs of the service deployed to the Azure resource
+func (sm *serviceManager) StreamLogs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	options ServiceLogsOptions,
+) error {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return fmt.Errorf("getting service target: %w", err)
+	}
+
+	logStreamer, ok := serviceTarget.(ServiceTargetLogStreamer)
+	if !ok {
+		return fmt.Errorf("%w for service host '%s'", ErrLogsNotSupported, serviceConfig.Host)
+	}
+
+	targetResource, err := sm.getTargetResource(ctx, serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := logStreamer.StreamLogs(ctx, serviceConfig, targetResource, options); err != nil {
+		return fmt.Errorf("failed streaming logs of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	return nil
+}
+
+// Executes a command in a running instance of the service deployed to the Azure resource
+func (sm *serviceManager) Exec(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	options ServiceExecOptions,
+) error {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return fmt.Errorf("getting service target: %w", err)
+	}
+
+	executor, ok := serviceTarget.(ServiceTargetExecutor)
+	if !ok {
+		return fmt.Errorf("%w for service host '%s'", ErrExecNotSupported, serviceConfig.Host)
+	}
+
+	targetResource, err := sm.getTargetResource(ctx, serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := executor.Exec(ctx, serviceConfig, targetResource, options); err != nil {
+		return fmt.Errorf("failed executing command in service '%s': %w", serviceConfig.Name, err)
+	}
+
+	return nil
+}
+
+// Runs kubectl against the cluster and namespace of the service deployed to the Azure resource
+func (sm *serviceManager) RunKubectl(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	args []string,
+) error {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return fmt.Errorf("getting service target: %w", err)
+	}
+
+	runner, ok := serviceTarget.(ServiceTargetKubectlRunner)
+	if !ok {
+		return fmt.Errorf("%w for service host '%s'", ErrKubectlNotSupported, serviceConfig.Host)
+	}
+
+	targetResource, err := sm.getTargetResource(ctx, serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	return runner.RunKubectl(ctx, serviceConfig, targetResource, args)
+}
+
+// Reapplies a revision recorded within the deployment history of the service to the Azure resource
+// that hosts the service application.
+func (sm *serviceManager) Rollback(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	revision int,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting service target: %w", err)
+	}
+
+	rollbacker, ok := serviceTarget.(ServiceTargetRollbacker)
+	if !ok {
+		return nil, fmt.Errorf("%w for service host '%s'", ErrRollbackNotSupported, serviceConfig.Host)
+	}
+
+	targetResource, err := sm.getTargetResource(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	deployResult, err := rollbacker.Rollback(ctx, serviceConfig, targetResource, revision, progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed rolling back service '%s': %w", serviceConfig.Name, err)
+	}
+
+	sm.applyEndpoints(ctx, serviceConfig, deployResult)
+
+	return deployResult, nil
+}
+
+// Lists the revisions of the service deployed to the Azure resource that hosts the service application
+func (sm *serviceManager) Revisions(ctx context.Context, serviceConfig *ServiceConfig) ([]*ServiceRevision, error) {
+	trafficManager, targetResource, err := sm.trafficManager(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return trafficManager.Revisions(ctx, serviceConfig, targetResource)
+}
+
+// Routes the percentage of the traffic of the service to the revision of the Azure resource that hosts the service
+// application
+func (sm *serviceManager) ShiftTraffic(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	revision string,
+	percent int,
+	progress *async.Progress[ServiceProgress],
+) ([]*ServiceRevision, error) {
+	if percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("the percentage of the traffic must be between 0 and 100, got %d", percent)
+	}
+
+	trafficManager, targetResource, err := sm.trafficManager(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := trafficManager.ShiftTraffic(ctx, serviceConfig, targetResource, revision, percent, progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed shifting traffic of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	return revisions, nil
+}
+
+// trafficManager returns the service target of the service when it deploys revisions, along with its target resource
+func (sm *serviceManager) trafficManager(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) (ServiceTargetTrafficManager, *environment.TargetResource, error) {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting service target: %w", err)
+	}
+
+	trafficManager, ok := serviceTarget.(ServiceTargetTrafficManager)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w for service host '%s'", ErrTrafficNotSupported, serviceConfig.Host)
+	}
+
+	targetResource, err := sm.getTargetResource(ctx, serviceConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return trafficManager, targetResource, nil
+}
+
+// getTargetResource resolves the Azure resource that hosts the service application
+func (sm *serviceManager) getTargetResource(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) (*environment.TargetResource, error) {
 	if serviceConfig.Host == DotNetContainerAppTarget {
 		containerEnvName := sm.env.GetServiceProperty(serviceConfig.Name, "CONTAINER_ENVIRONMENT_NAME")
 		if containerEnvName == "" {
@@ -460,41 +822,34 @@ func (sm *serviceManager) Deploy(
 			return nil, fmt.Errorf("getting resource group name: %w", err)
 		}
 
-		targetResource = environment.NewTargetResource(
+		return environment.NewTargetResource(
 			sm.env.GetSubscriptionId(),
 			resourceGroupName,
 			containerEnvName,
 			string(azapi.AzureResourceTypeContainerAppEnvironment),
-		)
-	} else {
-		targetResource, err = sm.resourceManager.GetTargetResource(ctx, sm.env.GetSubscriptionId(), serviceConfig)
-		if err != nil {
-			return nil, fmt.Errorf("getting target resource: %w", err)
-		}
+		), nil
 	}
 
-	deployResult, err := runCommand(
-		ctx,
-		ServiceEventDeploy,
-		serviceConfig,
-		func() (*ServiceDeployResult, error) {
-			return serviceTarget.Deploy(ctx, serviceConfig, packageResult, targetResource, progress)
-		},
-	)
+	if serviceConfig.Host.IsExtensionTarget() {
+		return getExtensionTargetResource(serviceConfig, sm.env)
+	}
 
+	targetResource, err := sm.resourceManager.GetTargetResource(ctx, sm.env.GetSubscriptionId(), serviceConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed deploying service '%s': %w", serviceConfig.Name, err)
+		return nil, fmt.Errorf("getting target resource: %w", err)
 	}
 
-	// Allow users to specify their own endpoints, in cases where they've configured their own front-end load balancers,
-	// reverse proxies or DNS host names outside of the service target (and prefer that to be used instead).
-	overriddenEndpoints := OverriddenEndpoints(ctx, serviceConfig, sm.env)
-	if len(overriddenEndpoints) > 0 {
-		deployResult.Endpoints = overriddenEndpoints
+	return targetResource, nil
+}
+
+// checkHealth probes the endpoint of the deployed service with the health check of the service
+func (sm *serviceManager) checkHealth(ctx context.Context, serviceConfig *ServiceConfig, endpoints []string) error {
+	var transporter policy.Transporter
+	if err := sm.serviceLocator.Resolve(&transporter); err != nil {
+		return fmt.Errorf("resolving http transporter: %w", err)
 	}
 
-	sm.setOperationResult(serviceConfig, string(ServiceEventDeploy), deployResult)
-	return deployResult, nil
+	return checkHealth(ctx, transporter, sm.env.Getenv, serviceConfig, serviceConfig.HealthCheck, endpoints)
 }
 
 // GetServiceTarget constructs a ServiceTarget from the underlying service configuration
@@ -513,6 +868,16 @@ func (sm *serviceManager) GetServiceTarget(ctx context.Context, serviceConfig *S
 		}
 	}
 
+	// Extension hosts are not registered, they are implemented by the executable of the extension
+	if serviceConfig.Host.IsExtensionTarget() {
+		var commandRunner exec.CommandRunner
+		if err := sm.serviceLocator.Resolve(&commandRunner); err != nil {
+			return nil, fmt.Errorf("resolving command runner for service host '%s': %w", serviceConfig.Host, err)
+		}
+
+		return NewExtensionTarget(serviceConfig.Host, sm.env, commandRunner), nil
+	}
+
 	if err := sm.serviceLocator.ResolveNamed(host, &target); err != nil {
 		return nil, fmt.Errorf(
 			"failed to resolve service host '%s' for service '%s', %w",
@@ -581,6 +946,27 @@ func (sm *serviceManager) GetFrameworkService(ctx context.Context, serviceConfig
 	return frameworkService, nil
 }
 
+// applyEndpoints sets the endpoints of the deploy result, along with their structured details
+func (sm *serviceManager) applyEndpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deployResult *ServiceDeployResult,
+) {
+	// Allow users to specify their own endpoints, in cases where they've configured their own front-end load
+	// balancers, reverse proxies or DNS host names outside of the service target (and prefer that to be used
+	// instead).
+	overriddenEndpoints := OverriddenEndpoints(ctx, serviceConfig, sm.env)
+	if len(overriddenEndpoints) > 0 {
+		deployResult.Endpoints = overriddenEndpoints
+		deployResult.EndpointDetails = nil
+	}
+
+	// The details of the endpoints of service targets that don't report them are parsed from the endpoints
+	if deployResult.EndpointDetails == nil {
+		deployResult.EndpointDetails = ParseServiceEndpoints(deployResult.Endpoints)
+	}
+}
+
 func OverriddenEndpoints(ctx context.Context, serviceConfig *ServiceConfig, env *environment.Environment) []string {
 	overriddenEndpoints := env.GetServiceProperty(serviceConfig.Name, "ENDPOINTS")
 	if overriddenEndpoints != "" {
@@ -603,7 +989,10 @@ func OverriddenEndpoints(ctx context.Context, serviceConfig *ServiceConfig, env
 
 // Attempts to retrieve the result of a previous operation from the cache
 func (sm *serviceManager) getOperationResult(serviceConfig *ServiceConfig, operationName string) (any, bool) {
-	key := fmt.Sprintf("%s:%s:%s", sm.env.Name(), serviceConfig.Name, operationName)
+	key := fmt.Sprintf("%s:%s:%s", sm.env.Name(), serviceConfig.TargetName(), operationName)
+
+	operationCacheMu.Lock()
+	defer operationCacheMu.Unlock()
 	value, ok := sm.operationCache[key]
 
 	return value, ok
@@ -611,7 +1000,10 @@ func (sm *serviceManager) getOperationResult(serviceConfig *ServiceConfig, opera
 
 // Sets the result of an operation in the cache
 func (sm *serviceManager) setOperationResult(serviceConfig *ServiceConfig, operationName string, result any) {
-	key := fmt.Sprintf("%s:%s:%s", sm.env.Name(), serviceConfig.Name, operationName)
+	key := fmt.Sprintf("%s:%s:%s", sm.env.Name(), serviceConfig.TargetName(), operationName)
+
+	operationCacheMu.Lock()
+	defer operationCacheMu.Unlock()
 	sm.operationCache[key] = result
 }
 
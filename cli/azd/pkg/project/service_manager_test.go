@@ -3,8 +3,10 @@ package project
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
@@ -185,6 +187,26 @@ func Test_ServiceManager_Package(t *testing.T) {
 	require.True(t, raisedPostPackageEvent)
 }
 
+func Test_ServiceManager_Package_Prebuilt_Image(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForServiceManager(mockContext)
+	env := environment.New("test")
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageFake)
+	serviceConfig.K8s.Image = osutil.NewExpandableString("contoso.azurecr.io/api:1.0.0")
+
+	fakeFrameworkPackageCalled := to.Ptr(false)
+	ctx := context.WithValue(*mockContext.Context, frameworkPackageCalled, fakeFrameworkPackageCalled)
+
+	result, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+		return sm.Package(ctx, serviceConfig, nil, progress, nil)
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, &ServicePackageResult{}, result)
+	require.False(t, *fakeFrameworkPackageCalled)
+}
+
 func Test_ServiceManager_Deploy(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
@@ -221,6 +243,127 @@ func Test_ServiceManager_Deploy(t *testing.T) {
 	require.True(t, raisedPostDeployEvent)
 }
 
+func Test_ServiceManager_Deploy_Timeout(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForServiceManager(mockContext)
+	env := environment.NewWithValues("test", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+	})
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+	serviceConfig.Deploy.Timeout = 10 * time.Millisecond
+
+	// The deploy hooks are canceled with the deployment
+	_ = serviceConfig.AddHandler("predeploy", func(ctx context.Context, args ServiceLifecycleEventArgs) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	_, err := logProgress(t, func(progess *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return sm.Deploy(*mockContext.Context, serviceConfig, nil, progess)
+	})
+
+	require.ErrorContains(t, err, "deploying service 'api' timed out after 10ms")
+
+	// Deployments canceled by the caller are not reported as timed out
+	ctx, cancel := context.WithCancel(*mockContext.Context)
+	cancel()
+	_, err = logProgress(t, func(progess *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return sm.Deploy(ctx, serviceConfig, nil, progess)
+	})
+
+	require.ErrorContains(t, err, "context canceled")
+	require.NotContains(t, err.Error(), "timed out")
+}
+
+func Test_ServiceManager_Deploy_EndpointDetails(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForServiceManager(mockContext)
+	env := environment.NewWithValues("test", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"SERVICE_API_ENDPOINTS":              `["https://api.contoso.com/"]`,
+	})
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+
+	result, err := logProgress(t, func(progess *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return sm.Deploy(*mockContext.Context, serviceConfig, nil, progess)
+	})
+
+	// The details of the endpoints are reported for all service targets
+	require.NoError(t, err)
+	require.Equal(t, []ServiceEndpoint{
+		{
+			Url:      "https://api.contoso.com/",
+			Kind:     ServiceEndpointKindHost,
+			Protocol: "https",
+			Host:     "api.contoso.com",
+			Port:     443,
+			Path:     "/",
+		},
+	}, result.EndpointDetails)
+}
+
+func Test_ServiceManager_Deploy_HealthCheck(t *testing.T) {
+	tests := map[string]struct {
+		statusCode    int
+		expectedError string
+	}{
+		"Healthy": {
+			statusCode: http.StatusOK,
+		},
+		"Unhealthy": {
+			statusCode:    http.StatusServiceUnavailable,
+			expectedError: "health check of 'https://api.contoso.com/health' returned status code 503",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			setupMocksForServiceManager(mockContext)
+			env := environment.NewWithValues("test", map[string]string{
+				environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+			})
+			sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+			serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+			serviceConfig.HealthCheck = &HealthCheckOptions{
+				Url:     osutil.NewExpandableString("https://api.contoso.com"),
+				Path:    "/health",
+				Retries: to.Ptr(0),
+			}
+
+			requestUrls := []string{}
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodGet && request.URL.Host == "api.contoso.com"
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				requestUrls = append(requestUrls, request.URL.String())
+				return mocks.CreateEmptyHttpResponse(request, test.statusCode)
+			})
+
+			// The postdeploy hooks only run once the service is healthy
+			raisedPostDeployEvent := false
+			_ = serviceConfig.AddHandler("postdeploy", func(ctx context.Context, args ServiceLifecycleEventArgs) error {
+				raisedPostDeployEvent = true
+				return nil
+			})
+
+			_, err := logProgress(t, func(progess *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+				return sm.Deploy(*mockContext.Context, serviceConfig, nil, progess)
+			})
+
+			require.Equal(t, []string{"https://api.contoso.com/health"}, requestUrls)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				require.False(t, raisedPostDeployEvent)
+			} else {
+				require.NoError(t, err)
+				require.True(t, raisedPostDeployEvent)
+			}
+		})
+	}
+}
+
 func Test_ServiceManager_GetFrameworkService(t *testing.T) {
 	t.Run("Standard", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
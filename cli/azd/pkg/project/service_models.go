@@ -73,20 +73,42 @@ type ServicePackageResult struct {
 	Build       *ServiceBuildResult `json:"build"`
 	PackagePath string              `json:"packagePath"`
 	Details     interface{}         `json:"details"`
+	// The path of the software bill of materials generated for the container image, ex) api-sbom.spdx.json
+	SbomPath string `json:"sbomPath,omitempty"`
+	// The artifacts pushed to an OCI registry when the service was packaged, ex) helm charts
+	Artifacts []*OciArtifact `json:"artifacts,omitempty"`
 }
 
 // Supports rendering messages for UX items
 func (spr *ServicePackageResult) ToString(currentIndentation string) string {
-	uxItem, ok := spr.Details.(ux.UxItem)
-	if ok {
-		return uxItem.ToString(currentIndentation)
+	var result string
+	if uxItem, ok := spr.Details.(ux.UxItem); ok {
+		result = uxItem.ToString(currentIndentation)
+	} else if spr.PackagePath != "" {
+		result = fmt.Sprintf("%s- Package Output: %s", currentIndentation, output.WithLinkFormat(spr.PackagePath))
+	}
+
+	if spr.SbomPath != "" {
+		sbom := fmt.Sprintf("%s- SBOM: %s", currentIndentation, output.WithLinkFormat(spr.SbomPath))
+		if result != "" && !strings.HasSuffix(result, "\n") {
+			result += "\n"
+		}
+
+		result += sbom
 	}
 
-	if spr.PackagePath != "" {
-		return fmt.Sprintf("%s- Package Output: %s", currentIndentation, output.WithLinkFormat(spr.PackagePath))
+	for _, artifact := range spr.Artifacts {
+		line := fmt.Sprintf(
+			"%s- %s: %s", currentIndentation, artifact.Kind.displayName(),
+			output.WithHighLightFormat("%s:%s", artifact.Reference, artifact.Version))
+		if result != "" && !strings.HasSuffix(result, "\n") {
+			result += "\n"
+		}
+
+		result += line
 	}
 
-	return ""
+	return result
 }
 
 func (spr *ServicePackageResult) MarshalJSON() ([]byte, error) {
@@ -100,7 +122,35 @@ type ServiceDeployResult struct {
 	TargetResourceId string            `json:"targetResourceId"`
 	Kind             ServiceTargetKind `json:"kind"`
 	Endpoints        []string          `json:"endpoints"`
-	Details          interface{}       `json:"details"`
+	// The structured details of the endpoints, when reported by the service target
+	EndpointDetails []ServiceEndpoint `json:"endpointDetails,omitempty"`
+	Details         interface{}       `json:"details"`
+	// Whether the service target already verified the health of the service, ex) on each cluster of an AKS fleet
+	healthChecked bool
+}
+
+// ServiceEndpoint is an endpoint of a deployed service, ex) a path of an ingress or a port of a load balancer
+type ServiceEndpoint struct {
+	Url string `json:"url"`
+	// The kind of resource exposing the endpoint, normalized across the service targets, ex) host, slot or ingress
+	Kind     ServiceEndpointKind `json:"kind"`
+	Protocol string              `json:"protocol"`
+	Host     string              `json:"host"`
+	// The port of the endpoint, the default port of the protocol when the url does not specify one
+	Port int    `json:"port"`
+	Path string `json:"path,omitempty"`
+	// The kind of resource exposing the endpoint, ex) Service, Ingress or HTTPRoute
+	Source string `json:"source,omitempty"`
+	// The name of the resource exposing the endpoint when known, ex) the name of the k8s service
+	Name string `json:"name,omitempty"`
+	// The type of the resource exposing the endpoint, ex) LoadBalancer, ClusterIP or NodePort
+	Type string `json:"type,omitempty"`
+	// The gateway the route is attached to, only reported for HTTPRoute endpoints
+	Gateway string `json:"gateway,omitempty"`
+	// The cluster the endpoint is deployed to, only reported for fleet deployments
+	Cluster string `json:"cluster,omitempty"`
+	// Whether the endpoint is only reachable from a private network, ex) a ClusterIP service or a private IP address
+	Internal bool `json:"internal"`
 }
 
 // Supports rendering messages for UX items
@@ -136,3 +186,39 @@ func (spr *ServiceDeployResult) ToString(currentIndentation string) string {
 func (spr *ServiceDeployResult) MarshalJSON() ([]byte, error) {
 	return json.Marshal(*spr)
 }
+
+// ServicePreviewResult is the result of a successful Preview operation
+type ServicePreviewResult struct {
+	// Related Azure resource ID
+	TargetResourceId string            `json:"targetResourceId"`
+	Kind             ServiceTargetKind `json:"kind"`
+	HasChanges       bool              `json:"hasChanges"`
+	// The changes a deployment would apply, in the native format of the service target (ex: kubectl diff)
+	Changes string `json:"changes"`
+	// Parts of the deployment that are not included within the preview
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Supports rendering messages for UX items
+func (spr *ServicePreviewResult) ToString(currentIndentation string) string {
+	builder := strings.Builder{}
+
+	for _, warning := range spr.Warnings {
+		builder.WriteString(output.WithWarningFormat("%s- %s\n", currentIndentation, warning))
+	}
+
+	if !spr.HasChanges {
+		builder.WriteString(fmt.Sprintf("%s- No changes\n", currentIndentation))
+		return builder.String()
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(spr.Changes, "\n"), "\n") {
+		builder.WriteString(fmt.Sprintf("%s  %s\n", currentIndentation, line))
+	}
+
+	return builder.String()
+}
+
+func (spr *ServicePreviewResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*spr)
+}
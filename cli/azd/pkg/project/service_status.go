@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"log"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// The service property holding the time of the last successful deployment of the service,
+// ex) SERVICE_API_DEPLOYED_AT=2024-01-01T00:00:00Z
+const deployedAtServiceProperty = "DEPLOYED_AT"
+
+// ServiceDeployedAt returns the time of the last successful deployment of the service recorded in the environment,
+// the zero time when the service has not been deployed from the environment
+func ServiceDeployedAt(serviceConfig *ServiceConfig, env *environment.Environment) time.Time {
+	value := env.GetServiceProperty(serviceConfig.Name, hostServiceProperty(serviceConfig, deployedAtServiceProperty))
+	if value == "" {
+		return time.Time{}
+	}
+
+	deployedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Printf("ignoring invalid deploy time '%s' of service '%s': %v", value, serviceConfig.Name, err)
+		return time.Time{}
+	}
+
+	return deployedAt
+}
+
+// SetServiceDeployedAt records the time of a successful deployment of the service in the environment
+func SetServiceDeployedAt(serviceConfig *ServiceConfig, env *environment.Environment, deployedAt time.Time) {
+	env.SetServiceProperty(
+		serviceConfig.Name,
+		hostServiceProperty(serviceConfig, deployedAtServiceProperty),
+		deployedAt.UTC().Format(time.RFC3339),
+	)
+}
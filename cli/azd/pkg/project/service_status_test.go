@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceDeployedAt(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	env := environment.NewWithValues("test", nil)
+
+	require.True(t, ServiceDeployedAt(serviceConfig, env).IsZero())
+
+	deployedAt := time.Date(2024, 1, 1, 12, 30, 0, 0, time.FixedZone("PST", -8*60*60))
+	SetServiceDeployedAt(serviceConfig, env, deployedAt)
+	require.Equal(t, "2024-01-01T20:30:00Z", env.Getenv("SERVICE_API_DEPLOYED_AT"))
+	require.True(t, deployedAt.Equal(ServiceDeployedAt(serviceConfig, env)))
+
+	// Services deployed to more than one host record a time per host
+	serviceConfig.Host = ContainerAppJobTarget
+	serviceConfig.targetName = "api (containerapp.job)"
+	require.True(t, ServiceDeployedAt(serviceConfig, env).IsZero())
+
+	env.SetServiceProperty("api", "DEPLOYED_AT_CONTAINERAPP_JOB", "not a time")
+	require.True(t, ServiceDeployedAt(serviceConfig, env).IsZero())
+}
@@ -6,8 +6,12 @@ package project
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
@@ -21,19 +25,27 @@ const (
 	NonSpecifiedTarget       ServiceTargetKind = ""
 	AppServiceTarget         ServiceTargetKind = "appservice"
 	ContainerAppTarget       ServiceTargetKind = "containerapp"
+	ContainerAppJobTarget    ServiceTargetKind = "containerapp.job"
 	AzureFunctionTarget      ServiceTargetKind = "function"
 	StaticWebAppTarget       ServiceTargetKind = "staticwebapp"
 	SpringAppTarget          ServiceTargetKind = "springapp"
 	AksTarget                ServiceTargetKind = "aks"
 	DotNetContainerAppTarget ServiceTargetKind = "containerapp-dotnet"
 	AiEndpointTarget         ServiceTargetKind = "ai.endpoint"
+	VirtualMachineTarget     ServiceTargetKind = "vm"
+	ApimTarget               ServiceTargetKind = "apim"
+	IotEdgeTarget            ServiceTargetKind = "iotedge"
+	StorageWebsiteTarget     ServiceTargetKind = "storage.website"
+	BatchTarget              ServiceTargetKind = "batch"
 )
 
 // RequiresContainer returns true if the service target runs a container image.
 func (stk ServiceTargetKind) RequiresContainer() bool {
 	switch stk {
 	case ContainerAppTarget,
-		AksTarget:
+		ContainerAppJobTarget,
+		AksTarget,
+		IotEdgeTarget:
 		return true
 	}
 
@@ -48,15 +60,25 @@ func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
 	// presently it's the only service target that is tied to a language.
 	case AppServiceTarget,
 		ContainerAppTarget,
+		ContainerAppJobTarget,
 		AzureFunctionTarget,
 		StaticWebAppTarget,
 		SpringAppTarget,
 		AksTarget,
-		AiEndpointTarget:
+		AiEndpointTarget,
+		VirtualMachineTarget,
+		ApimTarget,
+		IotEdgeTarget,
+		StorageWebsiteTarget,
+		BatchTarget:
 
 		return kind, nil
 	}
 
+	if kind.IsExtensionTarget() {
+		return parseExtensionTarget(kind)
+	}
+
 	return ServiceTargetKind(""), fmt.Errorf("unsupported host '%s'", kind)
 }
 
@@ -94,6 +116,194 @@ type ServiceTarget interface {
 	) ([]string, error)
 }
 
+// ErrPreviewNotSupported is returned when previewing the deployment of a service whose target does not support it
+var ErrPreviewNotSupported = errors.New("deployment preview is not supported")
+
+// ServiceTargetPreviewer is implemented by service targets that can preview the changes a deployment would apply
+// to the target resource without applying them.
+type ServiceTargetPreviewer interface {
+	// Preview returns the changes that deploying the given deployment artifact would apply to the target resource
+	Preview(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		servicePackage *ServicePackageResult,
+		targetResource *environment.TargetResource,
+		progress *async.Progress[ServiceProgress],
+	) (*ServicePreviewResult, error)
+}
+
+// ErrPortForwardNotSupported is returned when forwarding local ports to a service whose target does not support it
+var ErrPortForwardNotSupported = errors.New("port forwarding is not supported")
+
+// PortForwardOptions are the options for forwarding local ports to a deployed service
+type PortForwardOptions struct {
+	// The ports to forward in the form [LOCAL_PORT:]REMOTE_PORT, defaults to all ports exposed by the service
+	Ports []string
+	// Receives the status of the port forward, ex) the forwarded local addresses
+	Writer io.Writer
+}
+
+// ServiceTargetPortForwarder is implemented by service targets that can forward local ports to the deployed service,
+// ex) to reach services that are not exposed outside of the target resource.
+type ServiceTargetPortForwarder interface {
+	// PortForward forwards the local ports to the service until the context is cancelled
+	PortForward(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		options PortForwardOptions,
+	) error
+}
+
+// ErrLogsNotSupported is returned when streaming the logs of a service whose target does not support it
+var ErrLogsNotSupported = errors.New("streaming logs is not supported")
+
+// ServiceLogsOptions are the options for streaming the logs of a deployed service
+type ServiceLogsOptions struct {
+	// The container to stream the logs of, defaults to all containers
+	Container string
+	// Streams new log entries until the context is cancelled
+	Follow bool
+	// Only returns the log entries newer than the duration
+	Since time.Duration
+	// The number of recent log entries to return for each container
+	Tail int
+	// Receives the log entries
+	Writer io.Writer
+}
+
+// ServiceTargetLogStreamer is implemented by service targets that can stream the logs of the deployed service
+type ServiceTargetLogStreamer interface {
+	// StreamLogs writes the logs of the service to the writer of the options
+	StreamLogs(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		options ServiceLogsOptions,
+	) error
+}
+
+// ErrExecNotSupported is returned when executing a command in a service whose target does not support it
+var ErrExecNotSupported = errors.New("executing commands is not supported")
+
+// ServiceExecOptions are the options for executing a command in a running instance of a deployed service
+type ServiceExecOptions struct {
+	// The command to execute, defaults to a shell
+	Command []string
+	// The instance to execute the command in, ex) the name of a pod, defaults to a running instance
+	Instance string
+	// The container to execute the command in, defaults to the default container of the instance
+	Container string
+	// Allocates a TTY for the command, ex) for an interactive shell
+	Tty bool
+}
+
+// ServiceTargetExecutor is implemented by service targets that can execute commands in a running instance of the
+// deployed service, ex) to open an interactive shell for debugging.
+type ServiceTargetExecutor interface {
+	// Exec executes the command with the stdin, stdout and stderr attached to the console
+	Exec(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		options ServiceExecOptions,
+	) error
+}
+
+// ErrKubectlNotSupported is returned when running kubectl for a service whose target is not a k8s cluster
+var ErrKubectlNotSupported = errors.New("running kubectl is not supported")
+
+// ServiceTargetKubectlRunner is implemented by service targets deployed to a k8s cluster that can run kubectl
+// against the cluster and namespace of the service, ex) for ad-hoc operations on the deployed resources.
+type ServiceTargetKubectlRunner interface {
+	// RunKubectl runs kubectl with the arguments as is, with the stdin, stdout and stderr attached to the console
+	RunKubectl(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		args []string,
+	) error
+}
+
+// ErrRollbackNotSupported is returned when rolling back a service whose target does not record a deployment history
+var ErrRollbackNotSupported = errors.New("rolling back is not supported")
+
+// ServiceTargetRollbacker is implemented by service targets that record the history of their deployments
+// and can reapply a previously deployed revision.
+type ServiceTargetRollbacker interface {
+	// Rollback reapplies the recorded revision, ex) 3, or the previous revision when the revision is 0
+	Rollback(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		revision int,
+		progress *async.Progress[ServiceProgress],
+	) (*ServiceDeployResult, error)
+}
+
+// ErrTrafficNotSupported is returned when managing the traffic of a service whose target does not deploy revisions
+var ErrTrafficNotSupported = errors.New("managing traffic is not supported")
+
+// ServiceRevision is a deployed revision of a service along with the percentage of the traffic routed to it
+type ServiceRevision struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+	// The percentage of the traffic routed to the revision, ex) 10
+	Traffic int `json:"traffic"`
+	// The health of the revision as reported by its health probes, ex) Healthy
+	Health    string    `json:"health"`
+	Image     string    `json:"image"`
+	CreatedOn time.Time `json:"createdOn"`
+}
+
+// ServiceTargetTrafficManager is implemented by service targets that deploy the service as revisions and can split
+// the traffic between them, ex) to gradually shift the traffic to a canary revision.
+type ServiceTargetTrafficManager interface {
+	// Revisions lists the revisions of the service, the most recent revision first
+	Revisions(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+	) ([]*ServiceRevision, error)
+	// ShiftTraffic routes the percentage of the traffic to the revision, or to the latest revision when empty, and the
+	// rest of the traffic to the other revision serving most of the traffic. 100 promotes the revision.
+	ShiftTraffic(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		revision string,
+		percent int,
+		progress *async.Progress[ServiceProgress],
+	) ([]*ServiceRevision, error)
+}
+
+// ErrStatusNotSupported is returned when getting the live status of a service whose target does not report it
+var ErrStatusNotSupported = errors.New("reporting the status is not supported")
+
+// ServiceStatus is the live status of the deployed service as reported by its target
+type ServiceStatus struct {
+	// The revision serving the service, ex) the latest active revision of a container app or the revision of a k8s
+	// deployment
+	Revision string `json:"revision,omitempty"`
+	// The status of the rollout of the revision, ex) Running, Progressing or Available
+	Status string `json:"status"`
+	// Details of the status, ex) 2/3 replicas available
+	Details string `json:"details,omitempty"`
+	// The image running, when reported by the target
+	Image string `json:"image,omitempty"`
+}
+
+// ServiceTargetStatusReporter is implemented by service targets that can report the live status of the deployed
+// service, ex) for `azd show`
+type ServiceTargetStatusReporter interface {
+	// Status returns the live status of the service deployed to the target resource
+	Status(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+	) (*ServiceStatus, error)
+}
+
 // NewServiceDeployResult is a helper function to create a new ServiceDeployResult
 func NewServiceDeployResult(
 	relatedResourceId string,
@@ -142,6 +352,25 @@ func (st ServiceTargetKind) SupportsDelayedProvisioning() bool {
 	return st == AksTarget
 }
 
+// The locks of the targets that deploy one service at a time, even when services are deployed in parallel. The
+// services of these targets share CLIs configured for the service being deployed, ex) the kube config, namespace and
+// environment of the kubectl and helm CLIs of AKS services
+var serialDeployLocks = map[ServiceTargetKind]*sync.Mutex{
+	AksTarget: {},
+}
+
+// lockDeploy waits for the services of the target being deployed to complete when the target deploys one service at
+// a time, and returns the function releasing the lock once the service is deployed
+func (st ServiceTargetKind) lockDeploy() func() {
+	mu, has := serialDeployLocks[st]
+	if !has {
+		return func() {}
+	}
+
+	mu.Lock()
+	return mu.Unlock
+}
+
 func checkResourceType(resource *environment.TargetResource, expectedResourceType azapi.AzureResourceType) error {
 	if !strings.EqualFold(resource.ResourceType(), string(expectedResourceType)) {
 		return resourceTypeMismatchError(
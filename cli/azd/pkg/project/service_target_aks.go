@@ -9,9 +9,12 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
@@ -28,6 +31,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/sethvargo/go-retry"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -37,44 +41,202 @@ const (
 var (
 	featureHelm      alpha.FeatureId = alpha.MustFeatureKey("aks.helm")
 	featureKustomize alpha.FeatureId = alpha.MustFeatureKey("aks.kustomize")
+	featureApiClient alpha.FeatureId = alpha.MustFeatureKey("aks.apiClient")
 
 	// Finds URLS in the endpoints that contain additional metadata
 	// Example: http://10.0.101.18:80 (Service: todo-api, Type: ClusterIP)
 	endpointRegex = regexp.MustCompile(`^(.*?)\s*(?:\(.*?\))?$`)
+
+	// Matches characters that are not valid within a federated identity credential name
+	federatedCredentialNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
 )
 
 // The AKS configuration options
 type AksOptions struct {
 	// The namespace used for deploying k8s resources. Defaults to the project name
 	Namespace string `yaml:"namespace"`
+	// The template the namespace is computed from when the namespace is not set, ex) {projectName}-{envName}
+	// or {projectName}-pr{PR_NUMBER}, so that each azd environment is isolated within a shared cluster
+	NamespaceTemplate string `yaml:"namespaceTemplate"`
+	// The labels, annotations, ResourceQuota and LimitRange applied to the namespace
+	NamespaceConfig *AksNamespaceOptions `yaml:"namespaceConfig"`
+	// The AKS cluster the service is deployed to. Defaults to the cluster resolved from the azd environment
+	Cluster *AksClusterOptions `yaml:"cluster"`
+	// When enabled, the cluster credentials are written to a kube config within the azd environment directory that is
+	// passed explicitly to kubectl and helm. The default kube config and its current context are left untouched.
+	// Defaults to true, so that azd environments targeting different clusters can be used in parallel
+	IsolatedKubeConfig *bool `yaml:"isolatedKubeConfig"`
+	// The kubelogin login mode the credentials of AAD enabled clusters are converted to, ex) azurecli or
+	// workloadidentity for CI pipelines. Supports environment variables, ex) ${AKS_KUBELOGIN}. Defaults to 'azd'
+	KubeLogin osutil.ExpandableString `yaml:"kubeLogin"`
+	// When configured, the manifests are applied with server-side apply using a dedicated field manager
+	ServerSideApply *AksServerSideApplyOptions `yaml:"serverSideApply"`
+	// The retries of kubectl commands that fail with transient API server errors. Enabled by default
+	Retry *AksRetryOptions `yaml:"retry"`
+	// When configured, the namespace is prepared for sidecar injection of the service mesh, ex) Istio
+	ServiceMesh *AksServiceMeshOptions `yaml:"serviceMesh"`
+	// Deprecated: use the healthCheck of the service instead, which is used when the service does not configure one
+	HealthCheck *HealthCheckOptions `yaml:"healthCheck"`
+	// The node labels the pods of the deployments are scheduled on, ex) kubernetes.io/os: windows
+	NodeSelector map[string]osutil.ExpandableString `yaml:"nodeSelector"`
+	// The tolerations of the pods of the deployments for the taints of the node pool, ex) sku=gpu:NoSchedule
+	Tolerations []AksToleration `yaml:"tolerations"`
+	// The runtime class of the pods of the deployments, ex) a sandboxed or GPU container runtime
+	RuntimeClass osutil.ExpandableString `yaml:"runtimeClass"`
+	// Whether the node selector, tolerations and runtime class are injected into the deployments or validated
+	// to be declared by the deployments. Defaults to 'inject'
+	SchedulingMode AksSchedulingMode `yaml:"schedulingMode"`
 	// The relative folder path from the service that contains the k8s deployment manifests. Defaults to 'manifests'
 	DeploymentPath string `yaml:"deploymentPath"`
+	// The prebuilt container image deployed to the cluster, ex) an image built by another pipeline
+	// When set, the container image is not built or pushed to the container registry
+	Image osutil.ExpandableString `yaml:"image"`
+	// When enabled, environment variable references (ex: ${SERVICE_API_IMAGE_NAME}) within all k8s deployment
+	// manifests are substituted with values from the azd environment before being applied
+	Envsubst bool `yaml:"envsubst"`
+	// How the deployment manifests are validated before any of them is applied. Defaults to local
+	Validation AksManifestValidation `yaml:"validation"`
 	// The services ingress configuration options
 	Ingress AksIngressOptions `yaml:"ingress"`
 	// The services deployment configuration options
 	Deployment AksDeploymentOptions `yaml:"deployment"`
 	// The services service configuration options
 	Service AksServiceOptions `yaml:"service"`
+	// The services Gateway API configuration options
+	Gateway *AksGatewayOptions `yaml:"gateway"`
 	// The helm configuration options
 	Helm *helm.Config `yaml:"helm"`
+	// The files pushed to the container registry as OCI artifacts when the service is packaged, ex) policy bundles
+	Artifacts []AksOciArtifactOptions `yaml:"artifacts"`
 	// The kustomize configuration options
 	Kustomize *kustomize.Config `yaml:"kustomize"`
+	// Whether k8s commands are executed through the AKS run command API instead of the local kubectl.
+	// Defaults to true when the AKS cluster is a private cluster with an API server that is not publicly accessible
+	RunCommand *bool `yaml:"runCommand"`
+	// The workload identity configuration options
+	WorkloadIdentity *AksWorkloadIdentityOptions `yaml:"workloadIdentity"`
+	// The options used when waiting for the deployed k8s resources to be ready
+	Wait AksWaitOptions `yaml:"wait"`
+	// The user-defined conditions waited on after the k8s resources have been deployed
+	WaitFor []AksWaitCondition `yaml:"waitFor"`
+	// When configured, the deployment manifests are applied in waves, ex) CRDs and namespaces before the resources
+	// that depend on them, waiting for the resources of each wave to be ready before the next wave
+	ApplyOrder *AksApplyOrderOptions `yaml:"applyOrder"`
+	// When enabled, resources previously deployed from the deployment manifests that are no longer defined
+	// within the manifests are deleted from the cluster
+	Prune bool `yaml:"prune"`
+	// When configured, each deployment of the manifests is recorded as a revision that can be reapplied
+	// with 'azd deploy <service> --rollback [revision]'
+	History *AksHistoryOptions `yaml:"history"`
+	// When configured, the service is deployed to each AKS cluster of the fleet
+	Fleet *AksFleetOptions `yaml:"fleet"`
+	// When configured, a docker-registry secret is created in the namespace and referenced from the service account
+	// so that images can be pulled from registries that are not attached to the AKS cluster
+	ImagePullSecret *AksImagePullSecretOptions `yaml:"imagePullSecret"`
+	// When configured, a ConfigMap and Secret are generated from the selected azd environment values
+	Environment *AksEnvironmentOptions `yaml:"environment"`
+	// When configured, a SecretProviderClass is generated to mount Key Vault secrets with the Secrets Store CSI driver
+	KeyVault *AksKeyVaultOptions `yaml:"keyVault"`
+}
+
+// The validation of the AKS deployment manifests before they are applied
+type AksManifestValidation string
+
+const (
+	// The manifests are parsed and verified to define the apiVersion, kind and name of each resource (default)
+	AksManifestValidationLocal AksManifestValidation = "local"
+	// The manifests are additionally validated against the OpenAPI schema of the cluster with a server-side dry run
+	AksManifestValidationServer AksManifestValidation = "server"
+	// The manifests are applied without validation
+	AksManifestValidationNone AksManifestValidation = "none"
+)
+
+// The AKS wait options
+type AksWaitOptions struct {
+	// When disabled, azd does not wait for the deployment rollout to complete and only checks the
+	// services and ingresses once for endpoints. Blue/green and canary strategies always wait for the new
+	// deployment before shifting traffic.
+	Disabled bool `yaml:"disabled"`
+	// The maximum duration to wait for each k8s resource to be ready (ex: 5m). Defaults to 10 minutes
+	Timeout time.Duration `yaml:"timeout"`
+	// The interval between polling the k8s resource status (ex: 5s). Defaults to 10 seconds
+	PollInterval time.Duration `yaml:"pollInterval"`
+	// The readiness rules of the custom resources defined within the deployment manifests, ex) cert-manager
+	// Certificates. Custom resources without a matching rule are not waited on
+	Resources []AksReadinessRule `yaml:"resources"`
+}
+
+// The AKS wait condition of k8s resources, evaluated with 'kubectl wait'
+type AksWaitCondition struct {
+	// The kind of the resources, ex) deployment or certificate.cert-manager.io
+	Kind string `yaml:"kind"`
+	// The name of the resource. Either the name or the selector is required
+	Name string `yaml:"name"`
+	// The label selector used to find the resources, ex) app=api
+	Selector string `yaml:"selector"`
+	// The namespace of the resources. Defaults to the namespace of the service
+	Namespace string `yaml:"namespace"`
+	// The condition in the format of the kubectl wait command,
+	// ex) condition=Available or jsonpath={.status.phase}=Running
+	Condition string `yaml:"condition"`
+	// The maximum duration to wait for the condition (ex: 5m). Defaults to the wait timeout
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// The AKS readiness rule of custom resources
+// A custom resource is ready once its controller observed the latest generation and all ready conditions are True
+type AksReadinessRule struct {
+	// The kind of the custom resources the rule applies to, ex) Certificate
+	Kind string `yaml:"kind"`
+	// The API group of the custom resources, ex) cert-manager.io. Defaults to matching any API group
+	Group string `yaml:"group"`
+	// The status conditions that must be True for the resource to be ready. Defaults to Ready
+	Conditions []string `yaml:"conditions"`
+	// The status conditions that fail the deployment as soon as they are True. Defaults to Stalled
+	FailureConditions []string `yaml:"failureConditions"`
+}
+
+// The AKS workload identity options
+// When configured, a k8s service account is created and federated with a user-assigned managed identity
+type AksWorkloadIdentityOptions struct {
+	// The name of the k8s service account to create. Defaults to the service name
+	ServiceAccount string `yaml:"serviceAccount"`
+	// The name of the user-assigned managed identity to federate with the service account
+	IdentityName osutil.ExpandableString `yaml:"identityName"`
+	// The resource group of the user-assigned managed identity. Defaults to the AKS cluster resource group
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup"`
 }
 
 // The AKS ingress options
 type AksIngressOptions struct {
-	Name         string `yaml:"name"`
+	Name string `yaml:"name"`
+	// Additional ingress resources used to report endpoints
+	Names []string `yaml:"names"`
+	// The label selector used to find additional ingress resources, ex) app=api
+	Selector     string `yaml:"selector"`
 	RelativePath string `yaml:"relativePath"`
 }
 
 // The AKS deployment options
 type AksDeploymentOptions struct {
 	Name string `yaml:"name"`
+	// Additional deployments verified after the k8s manifests have been applied
+	Names []string `yaml:"names"`
+	// The label selector used to find additional deployments, ex) app=api
+	Selector string `yaml:"selector"`
+	// The rollout strategy used to deploy the k8s manifests
+	Strategy AksDeploymentStrategy `yaml:"strategy"`
+	// When enabled, a deployment that fails to roll out is rolled back to its previous revision
+	Rollback bool `yaml:"rollback"`
 }
 
 // The AKS service configuration options
 type AksServiceOptions struct {
 	Name string `yaml:"name"`
+	// Additional services used to report endpoints
+	Names []string `yaml:"names"`
+	// The label selector used to find additional services, ex) app=api
+	Selector string `yaml:"selector"`
 }
 
 type aksTarget struct {
@@ -82,6 +244,7 @@ type aksTarget struct {
 	envManager             environment.Manager
 	console                input.Console
 	managedClustersService azcli.ManagedClustersService
+	identityService        azcli.UserAssignedIdentityService
 	resourceManager        ResourceManager
 	kubectl                *kubectl.Cli
 	kubeLoginCli           *kubelogin.Cli
@@ -89,6 +252,7 @@ type aksTarget struct {
 	kustomizeCli           *kustomize.Cli
 	containerHelper        *ContainerHelper
 	featureManager         *alpha.FeatureManager
+	transporter            policy.Transporter
 }
 
 // Creates a new instance of the AKS service target
@@ -97,6 +261,7 @@ func NewAksTarget(
 	envManager environment.Manager,
 	console input.Console,
 	managedClustersService azcli.ManagedClustersService,
+	identityService azcli.UserAssignedIdentityService,
 	resourceManager ResourceManager,
 	kubectlCli *kubectl.Cli,
 	kubeLoginCli *kubelogin.Cli,
@@ -104,12 +269,16 @@ func NewAksTarget(
 	kustomizeCli *kustomize.Cli,
 	containerHelper *ContainerHelper,
 	featureManager *alpha.FeatureManager,
+	transporter policy.Transporter,
 ) ServiceTarget {
+	kubectlCli.EnableApiClient(featureManager.IsEnabled(featureApiClient))
+
 	return &aksTarget{
 		env:                    env,
 		envManager:             envManager,
 		console:                console,
 		managedClustersService: managedClustersService,
+		identityService:        identityService,
 		resourceManager:        resourceManager,
 		kubectl:                kubectlCli,
 		kubeLoginCli:           kubeLoginCli,
@@ -117,14 +286,23 @@ func NewAksTarget(
 		kustomizeCli:           kustomizeCli,
 		containerHelper:        containerHelper,
 		featureManager:         featureManager,
+		transporter:            transporter,
 	}
 }
 
 // Gets the required external tools to support the AKS service
 func (t *aksTarget) RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
 	allTools := []tools.ExternalTool{}
-	allTools = append(allTools, t.containerHelper.RequiredExternalTools(ctx, serviceConfig)...)
-	allTools = append(allTools, t.kubectl)
+
+	// Prebuilt container images are not built or pushed so the container tooling is not required
+	if serviceConfig.K8s.Image.Empty() {
+		allTools = append(allTools, t.containerHelper.RequiredExternalTools(ctx, serviceConfig)...)
+	}
+
+	// kubectl is still used to apply kustomizations when the k8s API is used directly
+	if !t.featureManager.IsEnabled(featureApiClient) || t.featureManager.IsEnabled(featureKustomize) {
+		allTools = append(allTools, t.kubectl)
+	}
 
 	if t.featureManager.IsEnabled(featureHelm) {
 		allTools = append(allTools, t.helmCli)
@@ -134,6 +312,10 @@ func (t *aksTarget) RequiredExternalTools(ctx context.Context, serviceConfig *Se
 		allTools = append(allTools, t.kustomizeCli)
 	}
 
+	if len(serviceConfig.K8s.Artifacts) > 0 {
+		allTools = append(allTools, t.containerHelper.oras)
+	}
+
 	return allTools
 }
 
@@ -161,6 +343,11 @@ func (t *aksTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig
 	// Ensure that the k8s context has been configured by the time a deploy operation is performed.
 	// We attach to "predeploy" so that any predeploy hooks can take advantage of the configuration
 	err = serviceConfig.AddHandler("predeploy", func(ctx context.Context, args ServiceLifecycleEventArgs) error {
+		// The handlers of a service deployed to more than one host are shared by all of its hosts
+		if args.Service != nil && args.Service.Host != AksTarget {
+			return nil
+		}
+
 		return t.setK8sContext(ctx, serviceConfig, "predeploy")
 	})
 
@@ -178,7 +365,23 @@ func (t *aksTarget) Package(
 	packageOutput *ServicePackageResult,
 	progress *async.Progress[ServiceProgress],
 ) (*ServicePackageResult, error) {
-	return packageOutput, nil
+	// The helm charts and artifacts pushed to the container registry are referenced by the deploy phase and pipelines
+	artifacts, err := t.pushOciArtifacts(ctx, serviceConfig, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(artifacts) == 0 {
+		return packageOutput, nil
+	}
+
+	result := &ServicePackageResult{}
+	if packageOutput != nil {
+		*result = *packageOutput
+	}
+	result.Artifacts = append(result.Artifacts, artifacts...)
+
+	return result, nil
 }
 
 // Deploys service container images to ACR and AKS resources to the AKS cluster
@@ -197,11 +400,25 @@ func (t *aksTarget) Deploy(
 		return nil, errors.New("missing package output")
 	}
 
+	prebuiltImage, err := serviceConfig.K8s.Image.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst k8s image: %w", err)
+	}
+
 	// Only deploy the container image if a package output has been defined
 	// Empty package details is a valid scenario for any AKS deployment that does not build any containers
 	// Ex) Helm charts, or other manifests that reference external images
-	if packageOutput.Details != nil || packageOutput.PackagePath != "" {
-		// Login, tag & push container image to ACR
+	if prebuiltImage != "" {
+		// Prebuilt images are referenced as-is by the manifests without logging into or pushing to ACR
+		progress.SetProgress(NewServiceProgress("Using prebuilt container image"))
+		t.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", prebuiltImage)
+		if err := t.envManager.Save(ctx, t.env); err != nil {
+			return nil, fmt.Errorf("failed updating environment with image name, %w", err)
+		}
+	} else if serviceConfig.Docker.RemoteBuild || serviceConfig.Docker.IsMultiPlatform() ||
+		packageOutput.Details != nil || packageOutput.PackagePath != "" {
+		// Login, tag & push container image to ACR. Remote and multi-platform builds are not packaged locally,
+		// the image is built and pushed in a single step instead
 		_, err := t.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true, progress)
 		if err != nil {
 			return nil, err
@@ -210,7 +427,134 @@ func (t *aksTarget) Deploy(
 
 	// Sync environment
 	t.kubectl.SetEnv(t.env.Dotenv())
+	t.kubectl.SetServerSideApply(serverSideApplyOptions(serviceConfig))
+	t.kubectl.SetRetry(retryOptions(serviceConfig))
+
+	if serviceConfig.K8s.HealthCheck != nil {
+		t.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: 'k8s.healthCheck' of service '%s' is deprecated, configure the 'healthCheck' of the service.\n",
+			serviceConfig.Name,
+		))
+	}
+
+	// Fleet deployments repeat the k8s deployment against each cluster of the fleet
+	if serviceConfig.K8s.Fleet != nil {
+		return t.deployFleet(ctx, serviceConfig, packageOutput, targetResource, progress)
+	}
+
+	cluster, err := t.targetCluster(serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateClusterKind(serviceConfig, cluster); err != nil {
+		return nil, err
+	}
+
+	// The workload identity service account must exist before any workloads referencing it are deployed
+	if serviceConfig.K8s.WorkloadIdentity != nil {
+		progress.SetProgress(NewServiceProgress("Configuring workload identity"))
+		if err := t.ensureWorkloadIdentity(ctx, serviceConfig, targetResource, cluster); err != nil {
+			return nil, fmt.Errorf("failed configuring workload identity: %w", err)
+		}
+	}
+
+	if serviceConfig.K8s.ImagePullSecret != nil {
+		progress.SetProgress(NewServiceProgress("Configuring image pull secret"))
+		if err := t.ensureImagePullSecret(ctx, serviceConfig, targetResource); err != nil {
+			return nil, fmt.Errorf("failed configuring image pull secret: %w", err)
+		}
+	}
+
+	if serviceConfig.K8s.KeyVault != nil {
+		progress.SetProgress(NewServiceProgress("Configuring Key Vault secrets"))
+		if err := t.ensureSecretProviderClass(ctx, serviceConfig, cluster); err != nil {
+			return nil, fmt.Errorf("failed configuring Key Vault secrets: %w", err)
+		}
+	}
+
+	// The Dapr sidecar loads the components when the pods start, the components are applied before the workloads
+	if serviceConfig.Dapr != nil && serviceConfig.Dapr.Components != "" {
+		progress.SetProgress(NewServiceProgress("Applying Dapr components"))
+		if err := t.applyDaprComponents(ctx, serviceConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	deployment, err := t.deployResources(ctx, serviceConfig, packageOutput, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if serviceConfig.K8s.History != nil {
+		progress.SetProgress(NewServiceProgress("Recording deployment revision"))
+		if err := t.recordDeployment(ctx, serviceConfig); err != nil {
+			return nil, fmt.Errorf("failed recording deployment revision: %w", err)
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
+	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.saveEndpointUrl(ctx, serviceConfig, endpoints); err != nil {
+		return nil, err
+	}
+
+	targetResourceId := azure.KubernetesServiceRID(
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if cluster.kind == K8sClusterKindOpenShift || cluster.kind == K8sClusterKindArc {
+		targetResourceId = cluster.resourceId()
+	}
+
+	return &ServiceDeployResult{
+		Package:          packageOutput,
+		TargetResourceId: targetResourceId,
+		Kind:             AksTarget,
+		Details:          deployment,
+		Endpoints:        endpoints,
+		EndpointDetails:  ParseServiceEndpoints(endpoints),
+	}, nil
+}
+
+// saveEndpointUrl stores the most publicly exposed endpoint url of the service in the environment
+// along with the details of all endpoints of the service
+func (t *aksTarget) saveEndpointUrl(ctx context.Context, serviceConfig *ServiceConfig, endpoints []string) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	// The AKS endpoints contain some additional identifying information
+	// Regex is used to pull the URL ignoring the additional metadata
+	// The last endpoint in the array will be the most publicly exposed
+	matches := endpointRegex.FindStringSubmatch(endpoints[len(endpoints)-1])
+	if len(matches) > 1 {
+		t.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_URL", matches[1])
+	}
+
+	if err := t.saveEndpointDetails(serviceConfig, ParseServiceEndpoints(endpoints)); err != nil {
+		return err
+	}
+
+	if err := t.envManager.Save(ctx, t.env); err != nil {
+		return fmt.Errorf("failed updating environment with endpoint url, %w", err)
+	}
 
+	return nil
+}
+
+// deployResources deploys the helm charts, kustomize overlays and manifests of the service to the current k8s context
+func (t *aksTarget) deployResources(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*kubectl.Deployment, error) {
 	// Deploy k8s resources in the following order:
 	// 1. Helm
 	// 2. Kustomize
@@ -220,10 +564,17 @@ func (t *aksTarget) Deploy(
 	// custom manifests depend on.
 	// Users are more likely to either deploy with kustomize or vanilla manifests but they could do both.
 
+	// The objects generated from the azd environment are applied first since the workloads reference them
+	if serviceConfig.K8s.Environment != nil {
+		if err := t.deployEnvironmentObjects(ctx, serviceConfig, progress); err != nil {
+			return nil, err
+		}
+	}
+
 	deployed := false
 
 	// Helm Support
-	helmDeployed, err := t.deployHelmCharts(ctx, serviceConfig, progress)
+	helmDeployed, err := t.deployHelmCharts(ctx, serviceConfig, packageOutput, progress)
 	if err != nil {
 		return nil, fmt.Errorf("helm deployment failed: %w", err)
 	}
@@ -250,36 +601,11 @@ func (t *aksTarget) Deploy(
 		return nil, errors.New("no deployment manifests found")
 	}
 
-	progress.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
-	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
-	if err != nil {
+	if err := t.waitForConditions(ctx, serviceConfig, progress); err != nil {
 		return nil, err
 	}
 
-	if len(endpoints) > 0 {
-		// The AKS endpoints contain some additional identifying information
-		// Regex is used to pull the URL ignoring the additional metadata
-		// The last endpoint in the array will be the most publicly exposed
-		matches := endpointRegex.FindStringSubmatch(endpoints[len(endpoints)-1])
-		if len(matches) > 1 {
-			t.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_URL", matches[1])
-			if err := t.envManager.Save(ctx, t.env); err != nil {
-				return nil, fmt.Errorf("failed updating environment with endpoint url, %w", err)
-			}
-		}
-	}
-
-	return &ServiceDeployResult{
-		Package: packageOutput,
-		TargetResourceId: azure.KubernetesServiceRID(
-			targetResource.SubscriptionId(),
-			targetResource.ResourceGroupName(),
-			targetResource.ResourceName(),
-		),
-		Kind:      AksTarget,
-		Details:   deployment,
-		Endpoints: endpoints,
-	}, nil
+	return deployment, nil
 }
 
 // deployManifests deploys raw or templated yaml manifests to the k8s cluster
@@ -288,46 +614,151 @@ func (t *aksTarget) deployManifests(
 	serviceConfig *ServiceConfig,
 	task *async.Progress[ServiceProgress],
 ) (bool, *kubectl.Deployment, error) {
-	deploymentPath := serviceConfig.K8s.DeploymentPath
-	if deploymentPath == "" {
-		deploymentPath = defaultDeploymentPath
-	}
-
-	deploymentPath = filepath.Join(serviceConfig.Path(), deploymentPath)
+	deploymentPath := t.getDeploymentPath(serviceConfig)
 
 	// Manifests are optional so we will continue if the directory does not exist
 	if _, err := os.Stat(deploymentPath); os.IsNotExist(err) {
+		// Services imported from a compose file are deployed with the manifests generated from the compose service
+		if serviceConfig.Compose == nil {
+			return false, nil, err
+		}
+
+		deploymentPath, err = t.writeComposeManifests(serviceConfig)
+		if err != nil {
+			return false, nil, err
+		}
+		defer os.RemoveAll(deploymentPath)
+	}
+
+	// The manifests are validated up front so that an invalid manifest does not result in a partially applied deployment
+	if err := t.validateManifests(ctx, serviceConfig, deploymentPath, task); err != nil {
 		return false, nil, err
 	}
 
+	// Blue/green and canary strategies orchestrate additional k8s objects on top of the manifests
+	strategyType := serviceConfig.K8s.Deployment.Strategy.Type
+	if strategyType != "" && strategyType != AksDeploymentStrategyRolling {
+		// The strategies apply the manifests in multiple steps, which would prune the objects of the other steps
+		if serviceConfig.K8s.Prune {
+			return false, nil, fmt.Errorf("pruning is not supported with the '%s' deployment strategy", strategyType)
+		}
+
+		if serviceConfig.K8s.ApplyOrder != nil {
+			return false, nil, fmt.Errorf("apply order is not supported with the '%s' deployment strategy", strategyType)
+		}
+
+		// A recorded revision is reapplied as a whole, which would bypass the steps of the strategy
+		if serviceConfig.K8s.History != nil {
+			return false, nil, fmt.Errorf(
+				"deployment history is not supported with the '%s' deployment strategy", strategyType)
+		}
+
+		deployment, err := t.deployManifestsWithStrategy(ctx, serviceConfig, deploymentPath, task)
+		if err != nil {
+			return false, nil, err
+		}
+
+		return true, deployment, nil
+	}
+
+	// Pruning compares the live resources against a single apply of all manifests
+	if serviceConfig.K8s.Prune && serviceConfig.K8s.ApplyOrder != nil {
+		return false, nil, errors.New("apply order is not supported when pruning is enabled")
+	}
+
 	task.SetProgress(NewServiceProgress("Applying k8s manifests"))
-	err := t.kubectl.Apply(
-		ctx,
-		deploymentPath,
-		nil,
-	)
+	var err error
+	switch {
+	case serviceConfig.K8s.Prune:
+		err = t.applyManifestsWithPrune(ctx, serviceConfig, deploymentPath)
+	case serviceConfig.K8s.ApplyOrder != nil:
+		err = t.applyManifestsInWaves(ctx, serviceConfig, deploymentPath, task)
+	case serviceConfig.K8s.hasScheduling():
+		err = t.applyManifestsWithScheduling(ctx, serviceConfig, deploymentPath)
+	case serviceConfig.K8s.Envsubst:
+		err = t.kubectl.ApplyWithEnvsubst(ctx, deploymentPath, nil)
+	default:
+		err = t.kubectl.Apply(ctx, deploymentPath, nil)
+	}
+
 	if err != nil {
-		return false, nil, fmt.Errorf("failed applying kube manifests: %w", err)
+		return false, nil, withKubectlErrorSuggestion(fmt.Errorf("failed applying kube manifests: %w", err))
+	}
+
+	if serviceConfig.K8s.Wait.Disabled {
+		return true, nil, nil
 	}
 
-	deploymentName := serviceConfig.K8s.Deployment.Name
-	if deploymentName == "" {
-		deploymentName = serviceConfig.Name
+	// Jobs such as database migrations and stateful workloads are verified before the deployments
+	// that may depend on them
+	if err := t.waitForWorkloads(ctx, serviceConfig, deploymentPath, task); err != nil {
+		return true, nil, err
+	}
+
+	deploymentNames, err := t.resolveResourceNames(
+		ctx,
+		kubectl.ResourceTypeDeployment,
+		t.getDeploymentName(serviceConfig),
+		serviceConfig.K8s.Deployment.Names,
+		serviceConfig.K8s.Deployment.Selector,
+	)
+	if err != nil {
+		return true, nil, err
 	}
 
 	// It is not a requirement for a AZD deploy to contain a deployment object
 	// If we don't find any deployment within the namespace we will continue
-	task.SetProgress(NewServiceProgress("Verifying deployment"))
-	deployment, err := t.waitForDeployment(ctx, deploymentName)
-	if err != nil && !errors.Is(err, kubectl.ErrResourceNotFound) {
-		// We continue to return a true value here since at this point we have successfully applied the manifests
-		// even through the deployment may not have been found
-		return true, nil, err
+	// The first deployment is returned as the primary deployment of the service
+	var deployment *kubectl.Deployment
+	for _, deploymentName := range deploymentNames {
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Verifying deployment: %s", deploymentName)))
+		verified, err := t.waitForDeployment(ctx, serviceConfig, deploymentName, task)
+		if err != nil && !errors.Is(err, kubectl.ErrResourceNotFound) {
+			if serviceConfig.K8s.Deployment.Rollback {
+				err = t.rollbackDeployment(ctx, deploymentName, err, task)
+			}
+
+			// We continue to return a true value here since at this point we have successfully applied the manifests
+			// even through the deployment may not have been found
+			return true, nil, err
+		}
+
+		if deployment == nil {
+			deployment = verified
+		}
 	}
 
 	return true, deployment, nil
 }
 
+// validateManifests validates the manifests of the deployment path with the configured validation
+func (t *aksTarget) validateManifests(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deploymentPath string,
+	task *async.Progress[ServiceProgress],
+) error {
+	validation := serviceConfig.K8s.Validation
+	switch validation {
+	case "", AksManifestValidationLocal, AksManifestValidationServer:
+	case AksManifestValidationNone:
+		return nil
+	default:
+		return fmt.Errorf("manifest validation '%s' is not supported", validation)
+	}
+
+	task.SetProgress(NewServiceProgress("Validating k8s manifests"))
+	err := t.kubectl.ValidateManifests(ctx, deploymentPath, &kubectl.ValidateManifestsOptions{
+		Envsubst: serviceConfig.K8s.Envsubst,
+		Server:   validation == AksManifestValidationServer,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed validating kube manifests: %w", err)
+	}
+
+	return nil
+}
+
 // deployKustomize deploys kustomize manifests to the k8s cluster
 func (t *aksTarget) deployKustomize(
 	ctx context.Context,
@@ -346,40 +777,17 @@ func (t *aksTarget) deployKustomize(
 	}
 
 	task.SetProgress(NewServiceProgress("Applying k8s manifests with Kustomize"))
-	overlayPath, err := serviceConfig.K8s.Kustomize.Directory.Envsubst(t.env.Getenv)
+	kustomizeDir, err := t.resolveKustomizeDir(serviceConfig)
 	if err != nil {
-		return false, fmt.Errorf("failed to envsubst kustomize directory: %w", err)
+		return false, err
 	}
 
-	// When deploying with kustomize we need to specify the full path to the kustomize directory.
-	// This can either be a base or overlay directory but must contain a kustomization.yaml file
-	kustomizeDir := filepath.Join(serviceConfig.Project.Path, serviceConfig.RelativePath, overlayPath)
-	if _, err := os.Stat(kustomizeDir); os.IsNotExist(err) {
-		return false, fmt.Errorf("kustomize directory '%s' does not exist: %w", kustomizeDir, err)
+	envFilePath, err := t.writeKustomizeEnv(serviceConfig, kustomizeDir)
+	if err != nil {
+		return false, err
 	}
 
-	// Kustomize does not have a built in way to specify environment variables
-	// A common well-known solution is to use the kustomize configMapGenerator within your kustomization.yaml
-	// and then generate a .env file that can be used to generate config maps
-	// azd can help here to create an .env file from the map specified within azure.yaml kustomize config section
-	if len(serviceConfig.K8s.Kustomize.Env) > 0 {
-		builder := strings.Builder{}
-		for key, exp := range serviceConfig.K8s.Kustomize.Env {
-			value, err := exp.Envsubst(t.env.Getenv)
-			if err != nil {
-				return false, fmt.Errorf("failed to envsubst kustomize env: %w", err)
-			}
-
-			builder.WriteString(fmt.Sprintf("%s=%s\n", key, value))
-		}
-
-		// We are manually writing the .env file since k8s config maps expect unquoted values
-		// The godotenv library will quote values when writing the file without an option to disable
-		envFilePath := filepath.Join(kustomizeDir, ".env")
-		if err := os.WriteFile(envFilePath, []byte(builder.String()), osutil.PermissionFile); err != nil {
-			return false, fmt.Errorf("failed to write kustomize .env: %w", err)
-		}
-
+	if envFilePath != "" {
 		defer os.Remove(envFilePath)
 	}
 
@@ -401,15 +809,66 @@ func (t *aksTarget) deployKustomize(
 
 	// Finally apply manifests with kustomize using the -k flag
 	if err := t.kubectl.ApplyWithKustomize(ctx, kustomizeDir, nil); err != nil {
-		return false, err
+		return false, withKubectlErrorSuggestion(err)
 	}
 
 	return true, nil
 }
 
+// resolveKustomizeDir returns the full path to the configured kustomize directory
+func (t *aksTarget) resolveKustomizeDir(serviceConfig *ServiceConfig) (string, error) {
+	overlayPath, err := serviceConfig.K8s.Kustomize.Directory.Envsubst(t.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed to envsubst kustomize directory: %w", err)
+	}
+
+	// When deploying with kustomize we need to specify the full path to the kustomize directory.
+	// This can either be a base or overlay directory but must contain a kustomization.yaml file
+	kustomizeDir := filepath.Join(serviceConfig.Project.Path, serviceConfig.RelativePath, overlayPath)
+	if _, err := os.Stat(kustomizeDir); os.IsNotExist(err) {
+		return "", fmt.Errorf("kustomize directory '%s' does not exist: %w", kustomizeDir, err)
+	}
+
+	return kustomizeDir, nil
+}
+
+// writeKustomizeEnv writes the .env file for the kustomize env config within the kustomize directory
+// Returns the path of the written file, or an empty string when no env values are configured
+func (t *aksTarget) writeKustomizeEnv(serviceConfig *ServiceConfig, kustomizeDir string) (string, error) {
+	// Kustomize does not have a built in way to specify environment variables
+	// A common well-known solution is to use the kustomize configMapGenerator within your kustomization.yaml
+	// and then generate a .env file that can be used to generate config maps
+	// azd can help here to create an .env file from the map specified within azure.yaml kustomize config section
+	if len(serviceConfig.K8s.Kustomize.Env) == 0 {
+		return "", nil
+	}
+
+	builder := strings.Builder{}
+	for key, exp := range serviceConfig.K8s.Kustomize.Env {
+		value, err := exp.Envsubst(t.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed to envsubst kustomize env: %w", err)
+		}
+
+		builder.WriteString(fmt.Sprintf("%s=%s\n", key, value))
+	}
+
+	// We are manually writing the .env file since k8s config maps expect unquoted values
+	// The godotenv library will quote values when writing the file without an option to disable
+	envFilePath := filepath.Join(kustomizeDir, ".env")
+	if err := os.WriteFile(envFilePath, []byte(builder.String()), osutil.PermissionFile); err != nil {
+		return "", fmt.Errorf("failed to write kustomize .env: %w", err)
+	}
+
+	return envFilePath, nil
+}
+
 // deployHelmCharts deploys helm charts to the k8s cluster
+// Releases of local charts pushed to the container registry when the service was packaged are installed from the
+// pushed charts
 func (t *aksTarget) deployHelmCharts(
 	ctx context.Context, serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
 	task *async.Progress[ServiceProgress],
 ) (bool, error) {
 	if serviceConfig.K8s.Helm == nil {
@@ -431,7 +890,26 @@ func (t *aksTarget) deployHelmCharts(
 		}
 	}
 
-	for _, release := range serviceConfig.K8s.Helm.Releases {
+	loggedIn := []string{}
+	for _, releaseConfig := range serviceConfig.K8s.Helm.Releases {
+		release, err := t.resolveHelmRelease(serviceConfig, releaseConfig)
+		if err != nil {
+			return false, err
+		}
+
+		if chart := packagedHelmChart(packageOutput, release.Chart); chart != nil {
+			release.Chart = chart.Reference
+			release.Version = chart.Version
+
+			if registryName := ociRegistry(chart.Reference); !slices.Contains(loggedIn, registryName) {
+				if err := t.helmRegistryLogin(ctx, serviceConfig, registryName); err != nil {
+					return false, err
+				}
+
+				loggedIn = append(loggedIn, registryName)
+			}
+		}
+
 		if release.Namespace == "" {
 			release.Namespace = t.getK8sNamespace(serviceConfig)
 		}
@@ -446,7 +924,7 @@ func (t *aksTarget) deployHelmCharts(
 		}
 
 		task.SetProgress(NewServiceProgress(fmt.Sprintf("Checking helm release status: %s", release.Name)))
-		err := retry.Do(
+		err = retry.Do(
 			ctx,
 			retry.WithMaxDuration(10*time.Minute, retry.NewConstant(5*time.Second)),
 			func(ctx context.Context) error {
@@ -474,73 +952,361 @@ func (t *aksTarget) deployHelmCharts(
 	return true, nil
 }
 
-// Gets the service endpoints for the AKS service target
-func (t *aksTarget) Endpoints(
-	ctx context.Context,
-	serviceConfig *ServiceConfig,
-	targetResource *environment.TargetResource,
-) ([]string, error) {
-	serviceName := serviceConfig.K8s.Service.Name
-	if serviceName == "" {
-		serviceName = serviceConfig.Name
-	}
+// resolveHelmRelease creates a copy of the configured helm release with environment variables substituted
+// and any local chart / values file paths resolved relative to the service path.
+// Charts referenced from a helm repository (ex: repo/chart) or an OCI registry (ex: oci://) are used as-is.
+func (t *aksTarget) resolveHelmRelease(serviceConfig *ServiceConfig, releaseConfig *helm.Release) (*helm.Release, error) {
+	release := *releaseConfig
 
-	ingressName := serviceConfig.K8s.Service.Name
-	if ingressName == "" {
-		ingressName = serviceConfig.Name
+	chart, err := osutil.NewExpandableString(release.Chart).Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst helm chart for release '%s': %w", release.Name, err)
 	}
 
-	// Find endpoints for any matching services
-	// These endpoints would typically be internal cluster accessible endpoints
-	serviceEndpoints, err := t.getServiceEndpoints(ctx, serviceName)
-	if err != nil && !errors.Is(err, kubectl.ErrResourceNotFound) {
-		return nil, fmt.Errorf("failed retrieving service endpoints, %w", err)
+	release.Chart = chart
+	if !release.IsOci() && !filepath.IsAbs(release.Chart) {
+		chartPath := filepath.Join(serviceConfig.Path(), release.Chart)
+		if _, err := os.Stat(chartPath); err == nil {
+			release.Chart = chartPath
+		}
 	}
 
-	// Find endpoints for any matching ingress controllers
-	// These endpoints would typically be publicly accessible endpoints
-	ingressEndpoints, err := t.getIngressEndpoints(ctx, serviceConfig, ingressName)
-	if err != nil && !errors.Is(err, kubectl.ErrResourceNotFound) {
-		return nil, fmt.Errorf("failed retrieving ingress endpoints, %w", err)
-	}
+	resolveValuesPath := func(valuesFile string) (string, error) {
+		valuesPath, err := osutil.NewExpandableString(valuesFile).Envsubst(t.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed to envsubst helm values for release '%s': %w", release.Name, err)
+		}
 
-	endpoints := append(serviceEndpoints, ingressEndpoints...)
+		if !filepath.IsAbs(valuesPath) {
+			valuesPath = filepath.Join(serviceConfig.Path(), valuesPath)
+		}
 
-	return endpoints, nil
-}
+		if _, err := os.Stat(valuesPath); err != nil {
+			return "", fmt.Errorf("helm values file '%s' for release '%s' does not exist: %w", valuesPath, release.Name, err)
+		}
 
-func (t *aksTarget) validateTargetResource(
-	targetResource *environment.TargetResource,
-) error {
-	if targetResource.ResourceGroupName() == "" {
-		return fmt.Errorf("missing resource group name: %s", targetResource.ResourceGroupName())
+		return valuesPath, nil
 	}
 
-	return nil
-}
-
+	if release.Values != "" {
+		valuesPath, err := resolveValuesPath(release.Values)
+		if err != nil {
+			return nil, err
+		}
+
+		release.Values = valuesPath
+	}
+
+	release.ValuesFiles = make([]string, 0, len(releaseConfig.ValuesFiles))
+	for _, valuesFile := range releaseConfig.ValuesFiles {
+		valuesPath, err := resolveValuesPath(valuesFile)
+		if err != nil {
+			return nil, err
+		}
+
+		release.ValuesFiles = append(release.ValuesFiles, valuesPath)
+	}
+
+	return &release, nil
+}
+
+// Gets the service endpoints for the AKS service target
+func (t *aksTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	serviceNames, err := t.resolveResourceNames(
+		ctx,
+		kubectl.ResourceTypeService,
+		t.getServiceName(serviceConfig),
+		serviceConfig.K8s.Service.Names,
+		serviceConfig.K8s.Service.Selector,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ingressNames, err := t.resolveResourceNames(
+		ctx,
+		kubectl.ResourceTypeIngress,
+		t.getIngressName(serviceConfig),
+		serviceConfig.K8s.Ingress.Names,
+		serviceConfig.K8s.Ingress.Selector,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []string{}
+
+	// Find endpoints for any matching services
+	// These endpoints would typically be internal cluster accessible endpoints
+	for _, serviceName := range serviceNames {
+		serviceEndpoints, err := t.getServiceEndpoints(ctx, serviceConfig, serviceName)
+		if err != nil && !t.isIgnorableEndpointError(serviceConfig, err) {
+			return nil, fmt.Errorf("failed retrieving service endpoints, %w", err)
+		}
+
+		endpoints = appendUnique(endpoints, serviceEndpoints...)
+	}
+
+	// Find endpoints for any matching ingress controllers
+	// These endpoints would typically be publicly accessible endpoints
+	for _, ingressName := range ingressNames {
+		ingressEndpoints, err := t.getIngressEndpoints(ctx, serviceConfig, ingressName)
+		if err != nil && !t.isIgnorableEndpointError(serviceConfig, err) {
+			return nil, fmt.Errorf("failed retrieving ingress endpoints, %w", err)
+		}
+
+		endpoints = appendUnique(endpoints, ingressEndpoints...)
+	}
+
+	// Find endpoints for any matching Gateway API routes
+	// These endpoints would typically be publicly accessible endpoints
+	if serviceConfig.K8s.Gateway != nil {
+		routeNames, err := t.resolveResourceNames(
+			ctx,
+			kubectl.ResourceTypeHttpRoute,
+			t.getHttpRouteName(serviceConfig),
+			serviceConfig.K8s.Gateway.Names,
+			serviceConfig.K8s.Gateway.Selector,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, routeName := range routeNames {
+			routeEndpoints, err := t.getHttpRouteEndpoints(ctx, serviceConfig, routeName)
+			if err != nil && !t.isIgnorableEndpointError(serviceConfig, err) {
+				return nil, fmt.Errorf("failed retrieving gateway endpoints, %w", err)
+			}
+
+			endpoints = appendUnique(endpoints, routeEndpoints...)
+		}
+	}
+
+	// Find endpoints of the service mesh ingress gateway
+	// These endpoints would typically be publicly accessible endpoints
+	if serviceConfig.K8s.ServiceMesh != nil && serviceConfig.K8s.ServiceMesh.IngressGateway != "" {
+		gatewayEndpoints, err := t.getMeshGatewayEndpoints(ctx, serviceConfig)
+		if err != nil && !t.isIgnorableEndpointError(serviceConfig, err) {
+			return nil, fmt.Errorf("failed retrieving service mesh gateway endpoints, %w", err)
+		}
+
+		endpoints = appendUnique(endpoints, gatewayEndpoints...)
+	}
+
+	return endpoints, nil
+}
+
+// appendUnique appends the values that are not already contained in the slice
+func appendUnique(values []string, newValues ...string) []string {
+	for _, value := range newValues {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// resolveResourceNames returns the names of the k8s resources of the specified type for the service.
+// The configured name (or default name) is combined with any additional names and the names of all resources
+// matching the label selector.
+func (t *aksTarget) resolveResourceNames(
+	ctx context.Context,
+	resourceType kubectl.ResourceType,
+	name string,
+	names []string,
+	selector string,
+) ([]string, error) {
+	resourceNames := appendUnique([]string{name}, names...)
+
+	if selector == "" {
+		return resourceNames, nil
+	}
+
+	resources, err := kubectl.GetResources[kubectl.Resource](
+		ctx,
+		t.kubectl,
+		resourceType,
+		&kubectl.KubeCliFlags{LabelSelector: selector},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed finding '%s' resources with selector '%s', %w", resourceType, selector, err)
+	}
+
+	for _, resource := range resources.Items {
+		resourceNames = appendUnique(resourceNames, resource.Metadata.Name)
+	}
+
+	return resourceNames, nil
+}
+
+// isIgnorableEndpointError returns whether the error retrieving endpoints can be ignored
+// Services and ingresses are optional, and when waiting is disabled they may not be ready yet
+func (t *aksTarget) isIgnorableEndpointError(serviceConfig *ServiceConfig, err error) bool {
+	if errors.Is(err, kubectl.ErrResourceNotFound) {
+		return true
+	}
+
+	return serviceConfig.K8s.Wait.Disabled && errors.Is(err, kubectl.ErrResourceNotReady)
+}
+
+// getWaitOptions returns the options used when waiting for k8s resources of the service
+// When waiting is disabled resources are only checked once
+func (t *aksTarget) getWaitOptions(serviceConfig *ServiceConfig) *kubectl.WaitOptions {
+	if serviceConfig.K8s.Wait.Disabled {
+		return &kubectl.WaitOptions{
+			// The smallest possible timeout results in a single attempt
+			Timeout: time.Nanosecond,
+		}
+	}
+
+	return &kubectl.WaitOptions{
+		Timeout:      serviceConfig.K8s.Wait.Timeout,
+		PollInterval: serviceConfig.K8s.Wait.PollInterval,
+	}
+}
+
+// getDeploymentPath returns the full path to the directory containing the k8s manifests of the service
+func (t *aksTarget) getDeploymentPath(serviceConfig *ServiceConfig) string {
+	deploymentPath := serviceConfig.K8s.DeploymentPath
+	if deploymentPath == "" {
+		deploymentPath = defaultDeploymentPath
+	}
+
+	return filepath.Join(serviceConfig.Path(), deploymentPath)
+}
+
+// getDeploymentName returns the name of the k8s deployment for the service. Defaults to the service name
+func (t *aksTarget) getDeploymentName(serviceConfig *ServiceConfig) string {
+	if serviceConfig.K8s.Deployment.Name != "" {
+		return serviceConfig.K8s.Deployment.Name
+	}
+
+	return serviceConfig.Name
+}
+
+// getIngressName returns the name of the k8s ingress for the service. Defaults to the service name
+func (t *aksTarget) getIngressName(serviceConfig *ServiceConfig) string {
+	if serviceConfig.K8s.Ingress.Name != "" {
+		return serviceConfig.K8s.Ingress.Name
+	}
+
+	return serviceConfig.Name
+}
+
+// getServiceName returns the name of the k8s service for the service. Defaults to the service name
+func (t *aksTarget) getServiceName(serviceConfig *ServiceConfig) string {
+	if serviceConfig.K8s.Service.Name != "" {
+		return serviceConfig.K8s.Service.Name
+	}
+
+	return serviceConfig.Name
+}
+
+func (t *aksTarget) validateTargetResource(
+	targetResource *environment.TargetResource,
+) error {
+	if targetResource.ResourceGroupName() == "" {
+		return fmt.Errorf("missing resource group name: %s", targetResource.ResourceGroupName())
+	}
+
+	return nil
+}
+
 func (t *aksTarget) ensureClusterContext(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 	targetResource *environment.TargetResource,
 	defaultNamespace string,
 ) (string, error) {
+	t.kubectl.SetRemoteRunner(nil)
+
 	kubeConfigPath := t.env.Getenv(kubectl.KubeConfigEnvVarName)
 	if kubeConfigPath != "" {
 		return kubeConfigPath, nil
 	}
 
-	// Login to AKS cluster
-	clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
+	// Fleet deployments default to the first cluster of the fleet, ex) for hooks and previews
+	clusters, err := t.resolveClusters(ctx, serviceConfig, targetResource)
 	if err != nil {
 		return "", err
 	}
 
+	return t.connectCluster(ctx, serviceConfig, clusters[0], defaultNamespace)
+}
+
+// useServiceCluster connects to the cluster of the service outside of a deployment, ex) to port forward or stream logs
+// Returns the namespace of the service
+func (t *aksTarget) useServiceCluster(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (string, error) {
+	if err := t.validateTargetResource(targetResource); err != nil {
+		return "", fmt.Errorf("validating target resource: %w", err)
+	}
+
+	t.kubectl.SetEnv(t.env.Dotenv())
+	if kubeConfigPath := t.env.Getenv(kubectl.KubeConfigEnvVarName); kubeConfigPath != "" {
+		t.kubectl.SetKubeConfig(kubeConfigPath)
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	if _, err := t.ensureClusterContext(ctx, serviceConfig, targetResource, namespace); err != nil {
+		return "", err
+	}
+
+	return namespace, nil
+}
+
+// connectCluster acquires the credentials for the AKS cluster and writes them to the kube config of the azd environment
+// When the isolated kube config is disabled, the credentials are merged into the default kube config instead
+// and the cluster is set as the current kube context
+func (t *aksTarget) connectCluster(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	cluster aksCluster,
+	defaultNamespace string,
+) (string, error) {
+	t.kubectl.SetRemoteRunner(nil)
+	t.kubectl.SetKubeConfigPath("")
+	t.helmCli.SetKubeConfigPath("")
+	clusterName := cluster.name
+
+	// OpenShift and Arc-enabled clusters are not managed clusters and have credentials of their own
+	if cluster.kind == K8sClusterKindOpenShift || cluster.kind == K8sClusterKindArc {
+		return t.connectKubernetesCluster(ctx, serviceConfig, cluster, defaultNamespace)
+	}
+
+	// Get the provisioned cluster properties to inspect configuration
+	managedCluster, err := t.managedClustersService.Get(
+		ctx,
+		cluster.subscriptionId,
+		cluster.resourceGroupName,
+		clusterName,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving managed cluster, %w", err)
+	}
+
+	// kubectl only supports k8s versions within one minor version of its own version
+	if managedCluster.Properties != nil && managedCluster.Properties.CurrentKubernetesVersion != nil {
+		if err := t.kubectl.EnsureCompatibleVersion(ctx, *managedCluster.Properties.CurrentKubernetesVersion); err != nil {
+			return "", fmt.Errorf("failed selecting kubectl version for cluster '%s', %w", clusterName, err)
+		}
+	}
+
+	aadEnabled, tenantId := t.isAadEnabled(managedCluster)
+
 	log.Printf("getting AKS credentials for cluster '%s'\n", clusterName)
 	clusterCreds, err := t.managedClustersService.GetUserCredentials(
 		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
+		cluster.subscriptionId,
+		cluster.resourceGroupName,
 		clusterName,
 	)
 	if err != nil {
@@ -568,6 +1334,39 @@ func (t *aksTarget) ensureClusterContext(
 		)
 	}
 
+	kubeConfigPath, err := t.useKubeConfig(
+		ctx, serviceConfig, clusterName, kubeConfig, defaultNamespace, aadEnabled, tenantId)
+	if err != nil {
+		return "", err
+	}
+
+	// Private clusters cannot be reached from the local machine so any commands that communicate with
+	// the k8s API server are routed through the AKS run command API
+	if t.useRunCommand(serviceConfig, managedCluster) {
+		log.Printf("using AKS run command API for commands on cluster '%s'\n", clusterName)
+		t.kubectl.SetRemoteRunner(newAksRunCommandRunner(
+			t.managedClustersService,
+			cluster.subscriptionId,
+			cluster.resourceGroupName,
+			clusterName,
+			aadEnabled,
+		))
+	}
+
+	return kubeConfigPath, nil
+}
+
+// useKubeConfig writes the kube config of the cluster to the kube config of the azd environment, or merges it into the
+// default kube config, and points kubectl and helm to it
+func (t *aksTarget) useKubeConfig(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	clusterName string,
+	kubeConfig *kubectl.KubeConfig,
+	defaultNamespace string,
+	aadEnabled bool,
+	tenantId string,
+) (string, error) {
 	// Set default namespace for the context
 	// This avoids having to specify the namespace for every kubectl command
 	kubeConfig.Contexts[0].Context.Namespace = defaultNamespace
@@ -576,59 +1375,241 @@ func (t *aksTarget) ensureClusterContext(
 		return "", err
 	}
 
-	// Create or update the kube config/context for the AKS cluster
-	kubeConfigPath, err = kubeConfigManager.AddOrUpdateContext(ctx, clusterName, kubeConfig)
+	// If we're connecting to an AAD enabled cluster (ex: Azure RBAC or local accounts disabled)
+	// then we need to convert the kube config to use the exec auth module of kubelogin
+	if aadEnabled {
+		if err := tools.EnsureInstalled(ctx, t.kubeLoginCli); err != nil {
+			return "", err
+		}
+
+		login, err := serviceConfig.K8s.KubeLogin.Envsubst(t.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed to envsubst kubelogin login mode: %w", err)
+		}
+
+		kubeConfigManager.SetKubeLogin(&kubectl.KubeLoginOptions{
+			Cli:      t.kubeLoginCli,
+			Login:    login,
+			TenantId: tenantId,
+		})
+	}
+
+	var kubeConfigPath string
+	isolatedKubeConfig := convert.ToValueWithDefault(serviceConfig.K8s.IsolatedKubeConfig, true)
+	if isolatedKubeConfig {
+		kubeConfigPath, err = t.saveIsolatedKubeConfig(kubeConfig)
+		if err != nil {
+			return "", err
+		}
+
+		if err := kubeConfigManager.ConvertKubeConfig(ctx, kubeConfigPath); err != nil {
+			return "", err
+		}
+	} else {
+		// Create or update the kube config/context for the AKS cluster
+		kubeConfigPath, err = kubeConfigManager.AddOrUpdateContext(ctx, clusterName, kubeConfig)
+		if err != nil {
+			return "", fmt.Errorf("failed adding/updating kube context, %w", err)
+		}
+	}
+
+	if isolatedKubeConfig {
+		t.kubectl.SetKubeConfigPath(kubeConfigPath)
+		t.helmCli.SetKubeConfigPath(kubeConfigPath)
+	} else {
+		// Merge the cluster config/context into the default kube config
+		kubeConfigPath, err = kubeConfigManager.MergeConfigs(ctx, "config", clusterName)
+		if err != nil {
+			return "", err
+		}
+
+		// Setup the default kube context to use the AKS cluster context
+		if _, err := t.kubectl.ConfigUseContext(ctx, clusterName, nil); err != nil {
+			return "", fmt.Errorf(
+				"failed setting kube context '%s'. Ensure the specified context exists. %w", clusterName,
+				err,
+			)
+		}
+
+		if err := t.trackKubeContext(ctx, clusterName, kubeConfig); err != nil {
+			return "", err
+		}
+	}
+
+	return kubeConfigPath, nil
+}
+
+// useRunCommand returns whether k8s commands should be executed through the AKS run command API
+// When not explicitly configured, the run command API is used for private clusters
+func (t *aksTarget) useRunCommand(
+	serviceConfig *ServiceConfig,
+	managedCluster *armcontainerservice.ManagedCluster,
+) bool {
+	if serviceConfig.K8s.RunCommand != nil {
+		return *serviceConfig.K8s.RunCommand
+	}
+
+	if managedCluster.Properties == nil || managedCluster.Properties.APIServerAccessProfile == nil {
+		return false
+	}
+
+	return convert.ToValueWithDefault(managedCluster.Properties.APIServerAccessProfile.EnablePrivateCluster, false)
+}
+
+// isAadEnabled returns whether the managed cluster requires AAD based authentication along with the AAD tenant
+// Clusters with AAD integration, Azure RBAC or local accounts disabled cannot be accessed with the default
+// kube config credentials and require token based authentication through kubelogin.
+func (t *aksTarget) isAadEnabled(managedCluster *armcontainerservice.ManagedCluster) (bool, string) {
+	if managedCluster.Properties == nil {
+		return false, ""
+	}
+
+	aadProfile := managedCluster.Properties.AADProfile
+	localAccountsDisabled := convert.ToValueWithDefault(managedCluster.Properties.DisableLocalAccounts, false)
+
+	tenantId := ""
+	if aadProfile != nil {
+		tenantId = convert.ToValueWithDefault(aadProfile.TenantID, "")
+	}
+
+	if tenantId == "" {
+		tenantId = t.env.Getenv(environment.TenantIdEnvVarName)
+	}
+
+	return aadProfile != nil || localAccountsDisabled, tenantId
+}
+
+// ensureWorkloadIdentity creates a federated identity credential between the user-assigned managed identity
+// and the k8s service account then creates / updates the service account with the workload identity annotations
+func (t *aksTarget) ensureWorkloadIdentity(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	cluster aksCluster,
+) error {
+	options := serviceConfig.K8s.WorkloadIdentity
+
+	identityName, err := options.IdentityName.Envsubst(t.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to envsubst workload identity name: %w", err)
+	}
+
+	if identityName == "" {
+		return errors.New("missing 'identityName' for the workload identity configuration")
+	}
+
+	resourceGroupName, err := options.ResourceGroup.Envsubst(t.env.Getenv)
 	if err != nil {
-		return "", fmt.Errorf("failed adding/updating kube context, %w", err)
+		return fmt.Errorf("failed to envsubst workload identity resource group: %w", err)
 	}
 
-	// Get the provisioned cluster properties to inspect configuration
+	if resourceGroupName == "" {
+		resourceGroupName = targetResource.ResourceGroupName()
+	}
+
+	serviceAccountName := options.ServiceAccount
+	if serviceAccountName == "" {
+		serviceAccountName = serviceConfig.Name
+	}
+
+	clusterName := cluster.name
 	managedCluster, err := t.managedClustersService.Get(
 		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
+		cluster.subscriptionId,
+		cluster.resourceGroupName,
 		clusterName,
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed retrieving managed cluster, %w", err)
+		return fmt.Errorf("failed retrieving managed cluster, %w", err)
 	}
 
-	azureRbacEnabled := managedCluster.Properties.AADProfile != nil &&
-		convert.ToValueWithDefault(managedCluster.Properties.AADProfile.EnableAzureRBAC, false)
-	localAccountsDisabled := convert.ToValueWithDefault(managedCluster.Properties.DisableLocalAccounts, false)
+	var issuerUrl string
+	if managedCluster.Properties != nil && managedCluster.Properties.OidcIssuerProfile != nil {
+		issuerUrl = convert.ToValueWithDefault(managedCluster.Properties.OidcIssuerProfile.IssuerURL, "")
+	}
 
-	// If we're connecting to a cluster with RBAC enabled and local accounts disabled
-	// then we need to convert the kube config to use the exec auth module with azd auth
-	if azureRbacEnabled || localAccountsDisabled {
-		convertOptions := &kubelogin.ConvertOptions{
-			Login:      "azd",
-			KubeConfig: kubeConfigPath,
-		}
+	if issuerUrl == "" {
+		return fmt.Errorf(
+			"the OIDC issuer is not enabled on AKS cluster '%s'. Enable the OIDC issuer and workload identity "+
+				"on the cluster to use workload identity",
+			clusterName,
+		)
+	}
 
-		if err := tools.EnsureInstalled(ctx, t.kubeLoginCli); err != nil {
-			return "", err
-		}
+	identity, err := t.identityService.Get(ctx, targetResource.SubscriptionId(), resourceGroupName, identityName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving user-assigned identity '%s', %w", identityName, err)
+	}
 
-		if err := t.kubeLoginCli.ConvertKubeConfig(ctx, convertOptions); err != nil {
-			return "", err
-		}
+	var clientId string
+	if identity.Properties != nil {
+		clientId = convert.ToValueWithDefault(identity.Properties.ClientID, "")
+	}
+
+	if clientId == "" {
+		return fmt.Errorf("user-assigned identity '%s' is missing a client id", identityName)
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	_, err = t.identityService.CreateOrUpdateFederatedCredential(
+		ctx,
+		targetResource.SubscriptionId(),
+		resourceGroupName,
+		identityName,
+		federatedCredentialName(clusterName, namespace, serviceAccountName),
+		issuerUrl,
+		fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccountName),
+	)
+	if err != nil {
+		return fmt.Errorf("failed creating federated identity credential for '%s', %w", identityName, err)
+	}
+
+	serviceAccount := kubectl.Resource{
+		ApiVersion: "v1",
+		Kind:       "ServiceAccount",
+		Metadata: kubectl.ResourceMetadata{
+			Name:      serviceAccountName,
+			Namespace: namespace,
+			Annotations: map[string]any{
+				"azure.workload.identity/client-id": clientId,
+			},
+			Labels: map[string]string{
+				"azure.workload.identity/use": "true",
+			},
+		},
 	}
 
-	// Merge the cluster config/context into the default kube config
-	kubeConfigPath, err = kubeConfigManager.MergeConfigs(ctx, "config", clusterName)
+	serviceAccountYaml, err := yaml.Marshal(serviceAccount)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("failed marshalling service account, %w", err)
 	}
 
-	// Setup the default kube context to use the AKS cluster context
-	if _, err := t.kubectl.ConfigUseContext(ctx, clusterName, nil); err != nil {
-		return "", fmt.Errorf(
-			"failed setting kube context '%s'. Ensure the specified context exists. %w", clusterName,
-			err,
-		)
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, string(serviceAccountYaml), nil); err != nil {
+		return fmt.Errorf("failed applying service account '%s', %w", serviceAccountName, err)
 	}
 
-	return kubeConfigPath, nil
+	// Expose the service account & identity to k8s manifest templates
+	t.env.SetServiceProperty(serviceConfig.Name, "SERVICE_ACCOUNT_NAME", serviceAccountName)
+	t.env.SetServiceProperty(serviceConfig.Name, "IDENTITY_CLIENT_ID", clientId)
+	if err := t.envManager.Save(ctx, t.env); err != nil {
+		return fmt.Errorf("failed updating environment with workload identity, %w", err)
+	}
+
+	t.kubectl.SetEnv(t.env.Dotenv())
+
+	return nil
+}
+
+// federatedCredentialName builds a unique federated credential name for the cluster service account
+// Federated credential names must be 3-120 characters and only contain alphanumeric, dash or underscore characters
+func federatedCredentialName(clusterName string, namespace string, serviceAccountName string) string {
+	name := fmt.Sprintf("%s-%s-%s", clusterName, namespace, serviceAccountName)
+	name = federatedCredentialNameRegex.ReplaceAllString(name, "-")
+	if len(name) > 120 {
+		name = name[:120]
+	}
+
+	return name
 }
 
 // Ensures the k8s namespace exists otherwise creates it
@@ -658,7 +1639,9 @@ func (t *aksTarget) ensureNamespace(ctx context.Context, namespace string) error
 // Additionally confirms rollout is complete by checking the rollout status
 func (t *aksTarget) waitForDeployment(
 	ctx context.Context,
+	serviceConfig *ServiceConfig,
 	deploymentNameFilter string,
+	task *async.Progress[ServiceProgress],
 ) (*kubectl.Deployment, error) {
 	// The deployment can appear like it has succeeded when a previous deployment
 	// was already in place.
@@ -668,10 +1651,35 @@ func (t *aksTarget) waitForDeployment(
 			return strings.Contains(deployment.Metadata.Name, deploymentNameFilter)
 		},
 		func(deployment *kubectl.Deployment) bool {
-			return deployment.Status.AvailableReplicas == deployment.Spec.Replicas
+			ready := deployment.Status.AvailableReplicas == deployment.Spec.Replicas
+			if !ready {
+				t.reportRolloutProgress(
+					ctx,
+					task,
+					deployment.Metadata.Name,
+					deployment.Status.AvailableReplicas,
+					deployment.Spec.Replicas,
+				)
+			}
+
+			return ready
 		},
+		t.getWaitOptions(serviceConfig),
 	)
 
+	// Include the last observed state of the deployment pods to help diagnose the failure
+	// Ex) ImagePullBackOff, CrashLoopBackOff or unschedulable pods
+	var timeoutErr *kubectl.ResourceTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return nil, &kubectl.ResourceTimeoutError{
+			ResourceType: timeoutErr.ResourceType,
+			ResourceName: deploymentNameFilter,
+			Timeout:      timeoutErr.Timeout,
+			Pods:         t.getWorkloadPods(ctx, deploymentNameFilter),
+			Err:          timeoutErr.Err,
+		}
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -683,13 +1691,77 @@ func (t *aksTarget) waitForDeployment(
 		return nil, err
 	}
 
+	if err := t.waitForSidecars(ctx, serviceConfig, deployment.Metadata.Name, task); err != nil {
+		return nil, err
+	}
+
+	if err := t.waitForDaprSidecars(ctx, serviceConfig, deployment.Metadata.Name, task); err != nil {
+		return nil, err
+	}
+
 	return deployment, nil
 }
 
+// getWorkloadPods returns the pods created for the deployment, stateful set or daemon set
+// Errors are ignored since the pods are only used to provide additional diagnostics
+func (t *aksTarget) getWorkloadPods(ctx context.Context, workloadName string) []kubectl.Pod {
+	pods, err := kubectl.GetResources[kubectl.Pod](ctx, t.kubectl, kubectl.ResourceTypePod, nil)
+	if err != nil {
+		log.Printf("failed getting pods for workload '%s': %v\n", workloadName, err)
+		return nil
+	}
+
+	// Pods created by a deployment are named '<deployment>-<replica set hash>-<pod hash>',
+	// by a stateful set '<stateful set>-<ordinal>' and by a daemon set '<daemon set>-<pod hash>'
+	workloadPods := []kubectl.Pod{}
+	for _, pod := range pods.Items {
+		if strings.HasPrefix(pod.Metadata.Name, workloadName+"-") {
+			workloadPods = append(workloadPods, pod)
+		}
+	}
+
+	return workloadPods
+}
+
+// getServiceDeploymentPods returns the pods of the deployment of the service
+// The deployment is resolved the same way it is when waiting for the deployment during a deployment
+func (t *aksTarget) getServiceDeploymentPods(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	namespace string,
+) ([]kubectl.Pod, error) {
+	deploymentName := t.getDeploymentName(serviceConfig)
+	// The namespace of the service is the default namespace of the cluster context
+	deployments, err := kubectl.GetResources[kubectl.Deployment](ctx, t.kubectl, kubectl.ResourceTypeDeployment, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting deployments: %w", err)
+	}
+
+	var deployment *kubectl.Deployment
+	for _, item := range deployments.Items {
+		if strings.Contains(item.Metadata.Name, deploymentName) {
+			deployment = &item
+			break
+		}
+	}
+
+	if deployment == nil {
+		return nil, fmt.Errorf("k8s deployment matching '%s' not found in namespace '%s'", deploymentName, namespace)
+	}
+
+	pods := t.getWorkloadPods(ctx, deployment.Metadata.Name)
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("k8s deployment '%s' does not have any pods", deployment.Metadata.Name)
+	}
+
+	return pods, nil
+}
+
 // Finds an ingress using the specified ingressNameFilter string
 // Waits until the ingress LoadBalancer has assigned a valid IP address
 func (t *aksTarget) waitForIngress(
 	ctx context.Context,
+	serviceConfig *ServiceConfig,
 	ingressNameFilter string,
 ) (*kubectl.Ingress, error) {
 	return kubectl.WaitForResource(
@@ -699,13 +1771,14 @@ func (t *aksTarget) waitForIngress(
 		},
 		func(ingress *kubectl.Ingress) bool {
 			for _, config := range ingress.Status.LoadBalancer.Ingress {
-				if config.Ip != "" {
+				if config.Address() != "" {
 					return true
 				}
 			}
 
 			return false
 		},
+		t.getWaitOptions(serviceConfig),
 	)
 }
 
@@ -713,6 +1786,7 @@ func (t *aksTarget) waitForIngress(
 // Waits until the service is available
 func (t *aksTarget) waitForService(
 	ctx context.Context,
+	serviceConfig *ServiceConfig,
 	serviceNameFilter string,
 ) (*kubectl.Service, error) {
 	return kubectl.WaitForResource(
@@ -727,26 +1801,26 @@ func (t *aksTarget) waitForService(
 			}
 
 			// Load balancer can take some time to be provision by AKS
-			var ipAddress string
 			for _, config := range service.Status.LoadBalancer.Ingress {
-				if config.Ip != "" {
-					ipAddress = config.Ip
-					break
+				if config.Address() != "" {
+					return true
 				}
 			}
 
-			return ipAddress != ""
+			return false
 		},
+		t.getWaitOptions(serviceConfig),
 	)
 }
 
 // Retrieve any service endpoints for the specified serviceNameFilter
-// Supports service types for LoadBalancer and ClusterIP
+// Supports service types for LoadBalancer, ClusterIP and NodePort
 func (t *aksTarget) getServiceEndpoints(
 	ctx context.Context,
+	serviceConfig *ServiceConfig,
 	serviceNameFilter string,
 ) ([]string, error) {
-	service, err := t.waitForService(ctx, serviceNameFilter)
+	service, err := t.waitForService(ctx, serviceConfig, serviceNameFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -754,10 +1828,36 @@ func (t *aksTarget) getServiceEndpoints(
 	var endpoints []string
 	if service.Spec.Type == kubectl.ServiceTypeLoadBalancer {
 		for _, resource := range service.Status.LoadBalancer.Ingress {
-			endpoints = append(
-				endpoints,
-				fmt.Sprintf("http://%s (Service: %s, Type: LoadBalancer)", resource.Ip, service.Metadata.Name),
-			)
+			// Hostnames, ex) assigned by external-dns, are preferred over the load balancer IP address
+			address := resource.Address()
+			if address == "" {
+				continue
+			}
+
+			ports := []int{}
+			for _, port := range service.Spec.Ports {
+				ports = append(ports, port.Port)
+			}
+
+			if len(ports) == 0 {
+				ports = append(ports, 0)
+			}
+
+			for _, port := range ports {
+				protocol := "http"
+				if port == 443 {
+					protocol = "https"
+				}
+
+				endpoints = append(
+					endpoints,
+					fmt.Sprintf(
+						"%s (Service: %s, Type: LoadBalancer)",
+						endpointBaseUrl(protocol, address, port),
+						service.Metadata.Name,
+					),
+				)
+			}
 		}
 	} else if service.Spec.Type == kubectl.ServiceTypeClusterIp {
 		for index, ip := range service.Spec.ClusterIps {
@@ -770,11 +1870,64 @@ func (t *aksTarget) getServiceEndpoints(
 				),
 			)
 		}
+	} else if service.Spec.Type == kubectl.ServiceTypeNodePort {
+		endpoints = t.getNodePortEndpoints(ctx, service)
 	}
 
 	return endpoints, nil
 }
 
+// getNodePortEndpoints returns the node address and node port pairs of the NodePort service
+// When the node addresses cannot be retrieved only the node ports are reported
+func (t *aksTarget) getNodePortEndpoints(ctx context.Context, service *kubectl.Service) []string {
+	addresses := []string{}
+	nodes, err := kubectl.GetResources[kubectl.Node](ctx, t.kubectl, kubectl.ResourceTypeNode, nil)
+	if err != nil {
+		log.Printf("failed retrieving nodes for NodePort service '%s': %v", service.Metadata.Name, err)
+	} else {
+		for _, node := range nodes.Items {
+			if address := node.Address(); address != "" {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	var endpoints []string
+	for _, port := range service.Spec.Ports {
+		if port.NodePort == 0 {
+			continue
+		}
+
+		if len(addresses) == 0 {
+			endpoints = append(
+				endpoints,
+				fmt.Sprintf("Node port %d (Service: %s, Type: NodePort)", port.NodePort, service.Metadata.Name),
+			)
+			continue
+		}
+
+		protocol := "http"
+		if port.Port == 443 {
+			protocol = "https"
+		}
+
+		for _, address := range addresses {
+			endpoints = append(
+				endpoints,
+				fmt.Sprintf(
+					"%s://%s:%d (Service: %s, Type: NodePort)",
+					protocol,
+					address,
+					port.NodePort,
+					service.Metadata.Name,
+				),
+			)
+		}
+	}
+
+	return endpoints
+}
+
 // Retrieve any ingress endpoints for the specified serviceNameFilter
 // Supports service types for LoadBalancer, supports Hosts and/or IP address
 func (t *aksTarget) getIngressEndpoints(
@@ -782,7 +1935,7 @@ func (t *aksTarget) getIngressEndpoints(
 	serviceConfig *ServiceConfig,
 	resourceFilter string,
 ) ([]string, error) {
-	ingress, err := t.waitForIngress(ctx, resourceFilter)
+	ingress, err := t.waitForIngress(ctx, serviceConfig, resourceFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -796,26 +1949,58 @@ func (t *aksTarget) getIngressEndpoints(
 	}
 
 	for index, resource := range ingress.Status.LoadBalancer.Ingress {
-		var baseUrl string
-		if ingress.Spec.Rules[index].Host == nil {
-			baseUrl = fmt.Sprintf("%s://%s", protocol, resource.Ip)
-		} else {
-			baseUrl = fmt.Sprintf("%s://%s", protocol, *ingress.Spec.Rules[index].Host)
+		// The rule host is preferred, followed by the load balancer hostname, ex) assigned by external-dns,
+		// and finally the load balancer IP address
+		host := resource.Address()
+		if index < len(ingress.Spec.Rules) && ingress.Spec.Rules[index].Host != nil {
+			host = *ingress.Spec.Rules[index].Host
 		}
 
-		endpointUrl, err := url.JoinPath(baseUrl, serviceConfig.K8s.Ingress.RelativePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed constructing service endpoints, %w", err)
+		if host == "" {
+			continue
+		}
+
+		// Ingress controllers listen on the default ports unless the load balancer reports otherwise
+		ports := []int{0}
+		if len(resource.Ports) > 0 {
+			ports = []int{}
+			for _, port := range resource.Ports {
+				ports = append(ports, port.Port)
+			}
 		}
 
-		endpoints = append(endpoints, fmt.Sprintf("%s (Ingress, Type: LoadBalancer)", endpointUrl))
+		for _, port := range ports {
+			for _, path := range ingressPaths(serviceConfig, ingress, index) {
+				endpointUrl, err := url.JoinPath(endpointBaseUrl(protocol, host, port), path)
+				if err != nil {
+					return nil, fmt.Errorf("failed constructing service endpoints, %w", err)
+				}
+
+				endpoints = append(endpoints, fmt.Sprintf("%s (Ingress, Type: LoadBalancer)", endpointUrl))
+			}
+		}
 	}
 
 	return endpoints, nil
 }
 
+// endpointBaseUrl returns the base url of the endpoint for the specified protocol, host and port
+// The port is omitted when it is unknown or the default port of the protocol
+func endpointBaseUrl(protocol string, host string, port int) string {
+	if port == 0 || (protocol == "http" && port == 80) || (protocol == "https" && port == 443) {
+		return fmt.Sprintf("%s://%s", protocol, host)
+	}
+
+	return fmt.Sprintf("%s://%s:%d", protocol, host, port)
+}
+
 func (t *aksTarget) getK8sNamespace(serviceConfig *ServiceConfig) string {
 	namespace := serviceConfig.K8s.Namespace
+	if namespace == "" {
+		// The namespace template is validated when the k8s context of the service is set
+		namespace, _ = t.resolveNamespaceTemplate(serviceConfig)
+	}
+
 	if namespace == "" {
 		namespace = serviceConfig.Project.Name
 	}
@@ -839,15 +2024,20 @@ func (t *aksTarget) setK8sContext(ctx context.Context, serviceConfig *ServiceCon
 		return err
 	}
 
+	if _, err := t.resolveNamespaceTemplate(serviceConfig); err != nil {
+		return err
+	}
+
 	defaultNamespace := t.getK8sNamespace(serviceConfig)
 	_, err = t.ensureClusterContext(ctx, serviceConfig, targetResource, defaultNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = t.ensureNamespace(ctx, defaultNamespace)
+	// The namespace is the first resource created in the cluster, ex) failing when the API server cannot be reached
+	err = t.ensureServiceNamespace(ctx, serviceConfig)
 	if err != nil {
-		return err
+		return withKubectlErrorSuggestion(err)
 	}
 
 	// Display message to the user when we detect they are using a non-default KUBECONFIG configuration
@@ -3,17 +3,22 @@ package project
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
@@ -31,6 +36,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockarmresources"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/azure/azure-dev/cli/azd/test/ostest"
@@ -98,6 +104,35 @@ func Test_Required_Tools_WithAlpha(t *testing.T) {
 	require.IsType(t, &kustomize.Cli{}, requiredTools[3])
 }
 
+func Test_Required_Tools_WithApiClient(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Image = osutil.NewExpandableString("contoso.azurecr.io/api:1.0.0")
+	env := createEnv()
+
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.apiClient", "on")
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, userConfig)
+
+	// kubectl is not required when the k8s API server is called directly
+	requiredTools := serviceTarget.RequiredExternalTools(*mockContext.Context, serviceConfig)
+	require.Empty(t, requiredTools)
+
+	_ = userConfig.Set("alpha.aks.kustomize", "on")
+	serviceTarget = createAksServiceTarget(mockContext, serviceConfig, env, userConfig)
+
+	requiredTools = serviceTarget.RequiredExternalTools(*mockContext.Context, serviceConfig)
+	require.Len(t, requiredTools, 2)
+	require.IsType(t, &kubectl.Cli{}, requiredTools[0])
+	require.IsType(t, &kustomize.Cli{}, requiredTools[1])
+}
+
 func Test_Package_Deploy_HappyPath(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -151,6 +186,214 @@ func Test_Package_Deploy_HappyPath(t *testing.T) {
 	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
 }
 
+func Test_Deploy_Parallel(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockarmresources.AddResourceGroupListMock(mockContext.HttpClient, "SUBSCRIPTION_ID", []*armresources.ResourceGroup{
+		{
+			ID:       to.Ptr("ID"),
+			Name:     to.Ptr("RESOURCE_GROUP"),
+			Location: to.Ptr("eastus2"),
+			Type:     to.Ptr(string(azapi.AzureResourceTypeResourceGroup)),
+		},
+	})
+	mockarmresources.AddAzResourceListMock(mockContext.HttpClient, to.Ptr("RESOURCE_GROUP"), nil)
+
+	env := createEnv()
+	services := []*ServiceConfig{}
+	for _, name := range []string{"api", "web"} {
+		serviceConfig := createTestServiceConfig(filepath.Join(tempDir, name), AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.Name = name
+		serviceConfig.K8s.Namespace = name
+
+		err = setupK8sManifests(t, serviceConfig)
+		require.NoError(t, err)
+
+		services = append(services, serviceConfig)
+	}
+
+	// The services share the kubectl and helm CLIs of the AKS target, configured for the service being deployed
+	serviceTarget := createAksServiceTarget(mockContext, services[0], env, nil)
+	serviceTarget.(*aksTarget).resourceManager.(*MockResourceManager).
+		On("GetTargetResource", mock.Anything, "SUBSCRIPTION_ID", mock.Anything).
+		Return(environment.NewTargetResource(
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP",
+			"",
+			string(azapi.AzureResourceTypeManagedCluster),
+		), nil)
+	mockContext.Container.MustRegisterNamedSingleton(string(AksTarget), func() ServiceTarget {
+		return serviceTarget
+	})
+
+	for _, serviceConfig := range services {
+		err = serviceTarget.Initialize(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+	}
+
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	err = RunInDependencyOrder(*mockContext.Context, services, len(services),
+		func(ctx context.Context, serviceConfig *ServiceConfig) error {
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return sm.Deploy(ctx, serviceConfig, &ServicePackageResult{
+						PackagePath: fmt.Sprintf("test-app/%s-test:azd-deploy-0", serviceConfig.Name),
+						Details: &dockerPackageResult{
+							ImageHash:   "IMAGE_HASH",
+							TargetImage: fmt.Sprintf("test-app/%s-test:azd-deploy-0", serviceConfig.Name),
+						},
+					}, progress)
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			require.Equal(t, AksTarget, deployResult.Kind)
+			return nil
+		},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
+	require.Equal(t, "REGISTRY.azurecr.io/test-app/web-test:azd-deploy-0", env.Dotenv()["SERVICE_WEB_IMAGE_NAME"])
+}
+
+func Test_Deploy_Manifests_Envsubst(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Envsubst = true
+	env := createEnv()
+	env.DotenvSet("SERVICE_API_IMAGE_NAME", "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
+	require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+	err = os.WriteFile(
+		filepath.Join(manifestsDir, "deployment.yaml"),
+		[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: api\nimage: ${SERVICE_API_IMAGE_NAME}"),
+		osutil.PermissionFile,
+	)
+	require.NoError(t, err)
+
+	applied := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		builder := strings.Builder{}
+		if _, err := io.Copy(&builder, args.StdIn); err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		applied = append(applied, builder.String())
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+	require.Equal(t, []string{
+		"apiVersion: v1\nkind: Pod\nmetadata:\n  name: api\nimage: REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0",
+	}, applied)
+}
+
+func Test_Deploy_Prebuilt_Image(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Envsubst = true
+	serviceConfig.K8s.Image = osutil.NewExpandableString("${IMAGE_REGISTRY}/api:1.0.0")
+	env := createEnv()
+	env.DotenvSet("IMAGE_REGISTRY", "contoso.azurecr.io")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	// The container tooling is not required when the image is not built or pushed
+	requiredTools := serviceTarget.RequiredExternalTools(*mockContext.Context, serviceConfig)
+	require.Len(t, requiredTools, 1)
+	require.IsType(t, &kubectl.Cli{}, requiredTools[0])
+
+	manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
+	require.NoError(t, os.MkdirAll(manifestsDir, osutil.PermissionDirectory))
+	err = os.WriteFile(
+		filepath.Join(manifestsDir, "deployment.yaml"),
+		[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: api\nimage: ${SERVICE_API_IMAGE_NAME}"),
+		osutil.PermissionFile,
+	)
+	require.NoError(t, err)
+
+	dockerCommands := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "docker")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		dockerCommands = append(dockerCommands, strings.Join(args.Args, " "))
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	applied := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		builder := strings.Builder{}
+		if _, err := io.Copy(&builder, args.StdIn); err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		applied = append(applied, builder.String())
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	packageOutput := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash:   "IMAGE_HASH",
+			TargetImage: "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+	require.Empty(t, dockerCommands)
+	require.Equal(t, "contoso.azurecr.io/api:1.0.0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
+	require.Equal(t, []string{
+		"apiVersion: v1\nkind: Pod\nmetadata:\n  name: api\nimage: contoso.azurecr.io/api:1.0.0",
+	}, applied)
+}
+
 func Test_Resolve_Cluster_Name(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -249,6 +492,106 @@ func Test_Deploy_No_Credentials(t *testing.T) {
 	require.ErrorContains(t, err, "failed retrieving cluster user credentials")
 }
 
+func Test_Deploy_User_Credentials_Exec_Format(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	var credentialsRequest *http.Request
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "listClusterUserCredential")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		credentialsRequest = request
+		kubeConfigBytes, err := yaml.Marshal(createTestCluster("cluster1", "user1"))
+		if err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerservice.CredentialResults{
+			Kubeconfigs: []*armcontainerservice.CredentialResult{
+				{
+					Name:  to.Ptr("context"),
+					Value: kubeConfigBytes,
+				},
+			},
+		})
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	require.NotNil(t, credentialsRequest)
+	require.Equal(t, string(armcontainerservice.FormatExec), credentialsRequest.URL.Query().Get("format"))
+}
+
+func Test_Aks_Is_Aad_Enabled(t *testing.T) {
+	tests := map[string]struct {
+		properties       *armcontainerservice.ManagedClusterProperties
+		expectedEnabled  bool
+		expectedTenantId string
+	}{
+		"NoProperties": {
+			properties:       nil,
+			expectedEnabled:  false,
+			expectedTenantId: "",
+		},
+		"LocalAccounts": {
+			properties: &armcontainerservice.ManagedClusterProperties{
+				EnableRBAC:           to.Ptr(true),
+				DisableLocalAccounts: to.Ptr(false),
+			},
+			expectedEnabled:  false,
+			expectedTenantId: "TENANT_ID",
+		},
+		"LocalAccountsDisabled": {
+			properties: &armcontainerservice.ManagedClusterProperties{
+				DisableLocalAccounts: to.Ptr(true),
+			},
+			expectedEnabled:  true,
+			expectedTenantId: "TENANT_ID",
+		},
+		"AadWithKubernetesRbac": {
+			properties: &armcontainerservice.ManagedClusterProperties{
+				AADProfile: &armcontainerservice.ManagedClusterAADProfile{
+					Managed:  to.Ptr(true),
+					TenantID: to.Ptr("AAD_TENANT_ID"),
+				},
+			},
+			expectedEnabled:  true,
+			expectedTenantId: "AAD_TENANT_ID",
+		},
+		"AadWithAzureRbac": {
+			properties: &armcontainerservice.ManagedClusterProperties{
+				AADProfile: &armcontainerservice.ManagedClusterAADProfile{
+					Managed:         to.Ptr(true),
+					EnableAzureRBAC: to.Ptr(true),
+				},
+			},
+			expectedEnabled:  true,
+			expectedTenantId: "TENANT_ID",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			target := &aksTarget{env: createEnv()}
+			enabled, tenantId := target.isAadEnabled(&armcontainerservice.ManagedCluster{
+				Properties: test.properties,
+			})
+
+			require.Equal(t, test.expectedEnabled, enabled)
+			require.Equal(t, test.expectedTenantId, tenantId)
+		})
+	}
+}
+
 func Test_Deploy_Helm(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -282,39 +625,258 @@ func Test_Deploy_Helm(t *testing.T) {
 	userConfig := config.NewConfig(nil)
 	_ = userConfig.Set("alpha.aks.helm", "on")
 
-	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
-	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	require.NoError(t, err)
+
+	packageResult := &ServicePackageResult{
+		PackagePath: "",
+	}
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, &serviceConfig, packageResult, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+
+	repoAdd, repoAddCalled := mockResults["helm-repo-add"]
+	require.True(t, repoAddCalled)
+	require.Equal(t, []string{"repo", "add", "argo", "https://argoproj.github.io/argo-helm"}, repoAdd.Args)
+
+	repoUpdate, repoUpdateCalled := mockResults["helm-repo-update"]
+	require.True(t, repoUpdateCalled)
+	require.Equal(t, []string{"repo", "update", "argo"}, repoUpdate.Args)
+
+	helmUpgrade, helmUpgradeCalled := mockResults["helm-upgrade"]
+	require.True(t, helmUpgradeCalled)
+	require.Contains(t, strings.Join(helmUpgrade.Args, " "), "upgrade argocd argo/argo-cd")
+
+	helmStatus, helmStatusCalled := mockResults["helm-status"]
+	require.True(t, helmStatusCalled)
+	require.Contains(t, strings.Join(helmStatus.Args, " "), "status argocd")
+}
+
+func Test_Deploy_Helm_LocalChart(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockResults, err := setupMocksForHelm(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.RelativePath = ""
+	serviceConfig.K8s.Helm = &helm.Config{
+		Releases: []*helm.Release{
+			{
+				Name:        "api",
+				Chart:       "./charts/api",
+				Values:      "./charts/api/values.yaml",
+				ValuesFiles: []string{"./charts/api/values-${AZURE_ENV_NAME}.yaml"},
+			},
+			{
+				Name:    "redis",
+				Chart:   "oci://registry-1.docker.io/bitnamicharts/redis",
+				Version: "18.0.0",
+			},
+		},
+	}
+
+	chartDir := filepath.Join(serviceConfig.Path(), "charts", "api")
+	require.NoError(t, os.MkdirAll(chartDir, osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte(""), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "values-dev.yaml"), []byte(""), osutil.PermissionFile))
+
+	env := createEnv()
+	env.DotenvSet(environment.EnvNameEnvVarName, "dev")
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.helm", "on")
+
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	require.NoError(t, err)
+
+	helmUpgrades := []exec.RunArgs{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm upgrade")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		helmUpgrades = append(helmUpgrades, args)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, &serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+
+	_, repoAddCalled := mockResults["helm-repo-add"]
+	require.False(t, repoAddCalled)
+
+	require.Len(t, helmUpgrades, 2)
+	require.Equal(t, withIsolatedKubeConfig(
+		"upgrade", "api", chartDir, "--install", "--wait",
+		"--values", filepath.Join(chartDir, "values.yaml"),
+		"--values", filepath.Join(chartDir, "values-dev.yaml"),
+		"--namespace", serviceConfig.Project.Name, "--create-namespace",
+	), helmUpgrades[0].Args)
+	require.Contains(
+		t,
+		strings.Join(helmUpgrades[1].Args, " "),
+		"upgrade redis oci://registry-1.docker.io/bitnamicharts/redis --install --wait --version 18.0.0",
+	)
+}
+
+func Test_Deploy_WorkloadIdentity(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(
+			request.URL.Path,
+			"Microsoft.ContainerService/managedClusters/AKS_CLUSTER",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerservice.ManagedCluster{
+			ID:   to.Ptr("cluster1"),
+			Type: to.Ptr("Microsoft.ContainerService/managedClusters"),
+			Properties: &armcontainerservice.ManagedClusterProperties{
+				OidcIssuerProfile: &armcontainerservice.ManagedClusterOIDCIssuerProfile{
+					Enabled:   to.Ptr(true),
+					IssuerURL: to.Ptr("https://eastus2.oic.prod-aks.azure.com/TENANT_ID/ISSUER_ID/"),
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path,
+			"Microsoft.ManagedIdentity/userAssignedIdentities/api-identity",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armmsi.Identity{
+			Name: to.Ptr("api-identity"),
+			Properties: &armmsi.UserAssignedIdentityProperties{
+				ClientID: to.Ptr("IDENTITY_CLIENT_ID"),
+			},
+		})
+	})
+
+	var federatedCredential armmsi.FederatedIdentityCredential
+	var federatedCredentialPath string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "federatedIdentityCredentials")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		federatedCredentialPath = request.URL.Path
+		if err := json.NewDecoder(request.Body).Decode(&federatedCredential); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, federatedCredential)
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.WorkloadIdentity = &AksWorkloadIdentityOptions{
+		IdentityName: osutil.NewExpandableString("${AZURE_API_IDENTITY_NAME}"),
+	}
+
+	env := createEnv()
+	env.DotenvSet("AZURE_API_IDENTITY_NAME", "api-identity")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	applied := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		builder := strings.Builder{}
+		if _, err := io.Copy(&builder, args.StdIn); err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		applied = append(applied, builder.String())
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+
+	require.True(t, strings.HasSuffix(federatedCredentialPath, "federatedIdentityCredentials/AKS_CLUSTER-Test-App-api"))
+	require.Equal(
+		t,
+		"https://eastus2.oic.prod-aks.azure.com/TENANT_ID/ISSUER_ID/",
+		*federatedCredential.Properties.Issuer,
+	)
+	require.Equal(t, "system:serviceaccount:Test-App:api", *federatedCredential.Properties.Subject)
+	require.Equal(t, azcli.FederatedCredentialAudience, *federatedCredential.Properties.Audiences[0])
+
+	require.Len(t, applied, 1)
+	var serviceAccount kubectl.Resource
+	require.NoError(t, yaml.Unmarshal([]byte(applied[0]), &serviceAccount))
+	require.Equal(t, "ServiceAccount", serviceAccount.Kind)
+	require.Equal(t, "api", serviceAccount.Metadata.Name)
+	require.Equal(t, "IDENTITY_CLIENT_ID", serviceAccount.Metadata.Annotations["azure.workload.identity/client-id"])
+	require.Equal(t, "true", serviceAccount.Metadata.Labels["azure.workload.identity/use"])
+
+	require.Equal(t, "api", env.Dotenv()["SERVICE_API_SERVICE_ACCOUNT_NAME"])
+	require.Equal(t, "IDENTITY_CLIENT_ID", env.Dotenv()["SERVICE_API_IDENTITY_CLIENT_ID"])
+}
+
+func Test_Deploy_WorkloadIdentity_No_Oidc_Issuer(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
 	require.NoError(t, err)
 
-	packageResult := &ServicePackageResult{
-		PackagePath: "",
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.WorkloadIdentity = &AksWorkloadIdentityOptions{
+		IdentityName: osutil.NewExpandableString("api-identity"),
 	}
 
+	env := createEnv()
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
 	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
-	deployResult, err := logProgress(
+	_, err = logProgress(
 		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
-			return serviceTarget.Deploy(*mockContext.Context, &serviceConfig, packageResult, scope, progress)
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
 		},
 	)
 
-	require.NoError(t, err)
-	require.NotNil(t, deployResult)
-
-	repoAdd, repoAddCalled := mockResults["helm-repo-add"]
-	require.True(t, repoAddCalled)
-	require.Equal(t, []string{"repo", "add", "argo", "https://argoproj.github.io/argo-helm"}, repoAdd.Args)
-
-	repoUpdate, repoUpdateCalled := mockResults["helm-repo-update"]
-	require.True(t, repoUpdateCalled)
-	require.Equal(t, []string{"repo", "update", "argo"}, repoUpdate.Args)
-
-	helmUpgrade, helmUpgradeCalled := mockResults["helm-upgrade"]
-	require.True(t, helmUpgradeCalled)
-	require.Contains(t, strings.Join(helmUpgrade.Args, " "), "upgrade argocd argo/argo-cd")
-
-	helmStatus, helmStatusCalled := mockResults["helm-status"]
-	require.True(t, helmStatusCalled)
-	require.Contains(t, strings.Join(helmStatus.Args, " "), "status argocd")
+	require.Error(t, err)
+	require.ErrorContains(t, err, "OIDC issuer is not enabled")
 }
 
 func Test_Deploy_Kustomize(t *testing.T) {
@@ -375,7 +937,433 @@ func Test_Deploy_Kustomize(t *testing.T) {
 
 	kubectlApplyKustomize, kubectlApplyKustomizeCalled := mockResults["kubectl-apply-kustomize"]
 	require.True(t, kubectlApplyKustomizeCalled)
-	require.Equal(t, []string{"apply", "-k", filepath.FromSlash("kustomize/overlays/dev")}, kubectlApplyKustomize.Args)
+	require.Equal(
+		t, withIsolatedKubeConfig("apply", "-k", filepath.FromSlash("kustomize/overlays/dev")), kubectlApplyKustomize.Args,
+	)
+}
+
+func Test_Deploy_Wait_Timeout(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Wait = AksWaitOptions{
+		Timeout:      10 * time.Millisecond,
+		PollInterval: time.Millisecond,
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	setupNotReadyDeploymentMocks(mockContext)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err = logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.Error(t, err)
+
+	var timeoutErr *kubectl.ResourceTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.Equal(t, "api", timeoutErr.ResourceName)
+	require.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	// Only the pods of the deployment are reported
+	require.Len(t, timeoutErr.Pods, 1)
+	require.ErrorContains(t, err, "container 'api' waiting: ImagePullBackOff")
+}
+
+func Test_Deploy_Wait_Disabled(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Wait = AksWaitOptions{
+		Disabled: true,
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	setupNotReadyDeploymentMocks(mockContext)
+
+	rolloutStatus := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl rollout status")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		rolloutStatus = true
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	// The load balancer has not been assigned an IP address yet
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get ing")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ingress := &kubectl.Ingress{
+			Resource: kubectl.Resource{
+				Metadata: kubectl.ResourceMetadata{
+					Name: "api-ingress",
+				},
+			},
+		}
+		jsonBytes, _ := json.Marshal(createK8sResourceList(ingress))
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+	require.Nil(t, deployResult.Details)
+	require.False(t, rolloutStatus)
+	// Only the cluster IP service endpoint is available
+	require.Len(t, deployResult.Endpoints, 1)
+}
+
+func Test_Deploy_Multiple_Resources(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Deployment.Names = []string{"worker"}
+	serviceConfig.K8s.Service.Selector = "app=api"
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get deployment")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		deployments := kubectl.List[*kubectl.Deployment]{}
+		for _, name := range []string{"api", "worker"} {
+			deployments.Items = append(deployments.Items, &kubectl.Deployment{
+				Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: name}},
+				Spec:     kubectl.DeploymentSpec{Replicas: 1},
+				Status:   kubectl.DeploymentStatus{AvailableReplicas: 1},
+			})
+		}
+		jsonBytes, _ := json.Marshal(deployments)
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	rollouts := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl rollout status")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		rollouts = append(rollouts, args.Args[2])
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	selectors := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get svc")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		if strings.Contains(strings.Join(args.Args, " "), "-l app=api") {
+			selectors = append(selectors, "app=api")
+		}
+
+		services := kubectl.List[*kubectl.Service]{}
+		for index, name := range []string{"api", "api-admin"} {
+			services.Items = append(services.Items, &kubectl.Service{
+				Resource: kubectl.Resource{Metadata: kubectl.ResourceMetadata{Name: name}},
+				Spec: kubectl.ServiceSpec{
+					Type:       kubectl.ServiceTypeClusterIp,
+					ClusterIps: []string{fmt.Sprintf("10.0.0.%d", index+1)},
+					Ports:      []kubectl.Port{{Port: 80, TargetPort: 3000}},
+				},
+			})
+		}
+		jsonBytes, _ := json.Marshal(services)
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"deployment/api", "deployment/worker"}, rollouts)
+	require.Equal(t, "api", deployResult.Details.(*kubectl.Deployment).Metadata.Name)
+	require.NotEmpty(t, selectors)
+	require.Equal(t, []string{
+		"http://10.0.0.1:80 (Service: api, Type: ClusterIP)",
+		"http://10.0.0.2:80 (Service: api-admin, Type: ClusterIP)",
+		"http://1.1.1.1 (Ingress, Type: LoadBalancer)",
+	}, deployResult.Endpoints)
+}
+
+func Test_Endpoints_LoadBalancer_Hostname(t *testing.T) {
+	tests := map[string]struct {
+		serviceType kubectl.ServiceType
+		ports       []kubectl.Port
+		ingress     kubectl.LoadBalancerIngress
+		expected    []string
+	}{
+		"Hostname": {
+			serviceType: kubectl.ServiceTypeLoadBalancer,
+			ports:       []kubectl.Port{{Port: 80, TargetPort: 3000}},
+			ingress:     kubectl.LoadBalancerIngress{Ip: "1.1.1.1", Hostname: "api.contoso.com"},
+			expected: []string{
+				"http://api.contoso.com (Service: api-service, Type: LoadBalancer)",
+				"http://api.contoso.com (Ingress, Type: LoadBalancer)",
+			},
+		},
+		"HostnameOnly": {
+			serviceType: kubectl.ServiceTypeLoadBalancer,
+			ports:       []kubectl.Port{{Port: 443, TargetPort: 3000}},
+			ingress:     kubectl.LoadBalancerIngress{Hostname: "api.contoso.com"},
+			expected: []string{
+				"https://api.contoso.com (Service: api-service, Type: LoadBalancer)",
+				"http://api.contoso.com (Ingress, Type: LoadBalancer)",
+			},
+		},
+		"NonDefaultPort": {
+			serviceType: kubectl.ServiceTypeLoadBalancer,
+			ports:       []kubectl.Port{{Port: 8080, TargetPort: 3000}},
+			ingress: kubectl.LoadBalancerIngress{
+				Ip:    "1.1.1.1",
+				Ports: []kubectl.PortStatus{{Port: 8080, Protocol: "TCP"}},
+			},
+			expected: []string{
+				"http://1.1.1.1:8080 (Service: api-service, Type: LoadBalancer)",
+				"http://1.1.1.1:8080 (Ingress, Type: LoadBalancer)",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			loadBalancer := kubectl.LoadBalancer{Ingress: []kubectl.LoadBalancerIngress{test.ingress}}
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get svc")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				service := &kubectl.Service{
+					Resource: kubectl.Resource{
+						Metadata: kubectl.ResourceMetadata{Name: "api-service", Namespace: "api-namespace"},
+					},
+					Spec:   kubectl.ServiceSpec{Type: test.serviceType, Ports: test.ports},
+					Status: kubectl.ServiceStatus{LoadBalancer: loadBalancer},
+				}
+				jsonBytes, _ := json.Marshal(createK8sResourceList(service))
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get ing")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				ingress := &kubectl.Ingress{
+					Resource: kubectl.Resource{
+						Metadata: kubectl.ResourceMetadata{Name: "api-ingress", Namespace: "api-namespace"},
+					},
+					Status: kubectl.IngressStatus{LoadBalancer: loadBalancer},
+				}
+				jsonBytes, _ := json.Marshal(createK8sResourceList(ingress))
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			env := createEnv()
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			endpoints, err := serviceTarget.Endpoints(*mockContext.Context, serviceConfig, scope)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, endpoints)
+		})
+	}
+}
+
+func Test_Endpoints_NodePort(t *testing.T) {
+	tests := map[string]struct {
+		nodes    []kubectl.Node
+		nodesErr error
+		expected []string
+	}{
+		"NodeAddresses": {
+			nodes: []kubectl.Node{
+				createNode(kubectl.NodeAddress{Type: kubectl.NodeAddressTypeInternalIp, Address: "10.224.0.4"}),
+				createNode(
+					kubectl.NodeAddress{Type: kubectl.NodeAddressTypeInternalIp, Address: "10.224.0.5"},
+					kubectl.NodeAddress{Type: kubectl.NodeAddressTypeExternalIp, Address: "20.10.10.10"},
+				),
+			},
+			expected: []string{
+				"http://10.224.0.4:30080 (Service: api-service, Type: NodePort)",
+				"http://20.10.10.10:30080 (Service: api-service, Type: NodePort)",
+				"https://10.224.0.4:30443 (Service: api-service, Type: NodePort)",
+				"https://20.10.10.10:30443 (Service: api-service, Type: NodePort)",
+			},
+		},
+		"NodesUnavailable": {
+			nodesErr: errors.New("nodes is forbidden"),
+			expected: []string{
+				"Node port 30080 (Service: api-service, Type: NodePort)",
+				"Node port 30443 (Service: api-service, Type: NodePort)",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get svc")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				service := &kubectl.Service{
+					Resource: kubectl.Resource{
+						Metadata: kubectl.ResourceMetadata{Name: "api-service", Namespace: "api-namespace"},
+					},
+					Spec: kubectl.ServiceSpec{
+						Type: kubectl.ServiceTypeNodePort,
+						Ports: []kubectl.Port{
+							{Port: 80, TargetPort: 3000, NodePort: 30080},
+							{Port: 443, TargetPort: 3443, NodePort: 30443},
+						},
+					},
+				}
+				jsonBytes, _ := json.Marshal(createK8sResourceList(service))
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get nodes")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				if test.nodesErr != nil {
+					return exec.NewRunResult(1, "", test.nodesErr.Error()), test.nodesErr
+				}
+
+				jsonBytes, _ := json.Marshal(kubectl.List[kubectl.Node]{Items: test.nodes})
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil).(*aksTarget)
+
+			endpoints, err := serviceTarget.getServiceEndpoints(*mockContext.Context, serviceConfig, "api-service")
+			require.NoError(t, err)
+			require.Equal(t, test.expected, endpoints)
+		})
+	}
+}
+
+func createNode(addresses ...kubectl.NodeAddress) kubectl.Node {
+	return kubectl.Node{
+		Resource: kubectl.Resource{ApiVersion: "v1", Kind: "Node"},
+		Status:   kubectl.NodeStatus{Addresses: addresses},
+	}
+}
+
+// setupNotReadyDeploymentMocks mocks a deployment whose pods are stuck pulling the container image
+func setupNotReadyDeploymentMocks(mockContext *mocks.MockContext) {
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get deployment")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		deployment := &kubectl.Deployment{
+			Resource: kubectl.Resource{
+				Metadata: kubectl.ResourceMetadata{
+					Name: "api",
+				},
+			},
+			Spec: kubectl.DeploymentSpec{
+				Replicas: 1,
+			},
+		}
+		jsonBytes, _ := json.Marshal(createK8sResourceList(deployment))
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get pods")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		pods := kubectl.List[kubectl.Pod]{
+			Items: []kubectl.Pod{
+				{
+					Resource: kubectl.Resource{
+						Metadata: kubectl.ResourceMetadata{
+							Name: "api-7d9f8b6c5-x2x4z",
+						},
+					},
+					Status: kubectl.PodStatus{
+						Phase: "Pending",
+						ContainerStatuses: []kubectl.ContainerStatus{
+							{
+								Name: "api",
+								State: kubectl.ContainerState{
+									Waiting: &kubectl.ContainerStateDetail{
+										Reason: "ImagePullBackOff",
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Resource: kubectl.Resource{
+						Metadata: kubectl.ResourceMetadata{
+							Name: "worker-5c7b9d8f4-a1b2c",
+						},
+					},
+				},
+			},
+		}
+		jsonBytes, _ := json.Marshal(pods)
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
 }
 
 func setupK8sManifests(t *testing.T, serviceConfig *ServiceConfig) error {
@@ -818,7 +1806,8 @@ func createAksServiceTarget(
 		})
 
 	envManager := &mockenv.MockEnvManager{}
-	envManager.On("Save", *mockContext.Context, env).Return(nil)
+	envManager.On("Save", mock.Anything, env).Return(nil)
+	envManager.On("EnvPath", env).Return(filepath.Join(".azure", env.Name(), ".env"))
 
 	resourceManager := &MockResourceManager{}
 	targetResource := environment.NewTargetResource(
@@ -828,10 +1817,11 @@ func createAksServiceTarget(
 		string(azapi.AzureResourceTypeManagedCluster),
 	)
 	resourceManager.
-		On("GetTargetResource", *mockContext.Context, "SUBSCRIPTION_ID", serviceConfig).
+		On("GetTargetResource", mock.Anything, "SUBSCRIPTION_ID", serviceConfig).
 		Return(targetResource, nil)
 
 	managedClustersService := azcli.NewManagedClustersService(credentialProvider, mockContext.ArmClientOptions)
+	identityService := azcli.NewUserAssignedIdentityService(credentialProvider, mockContext.ArmClientOptions)
 	containerRegistryService := azcli.NewContainerRegistryService(
 		credentialProvider,
 		dockerCli,
@@ -851,6 +1841,9 @@ func createAksServiceTarget(
 		dockerCli,
 		mockContext.Console,
 		cloud.AzurePublic(),
+		nil,
+		nil,
+		nil, nil, nil,
 	)
 
 	if userConfig == nil {
@@ -865,6 +1858,7 @@ func createAksServiceTarget(
 		envManager,
 		mockContext.Console,
 		managedClustersService,
+		identityService,
 		resourceManager,
 		kubeCtl,
 		kubeLoginCli,
@@ -872,6 +1866,7 @@ func createAksServiceTarget(
 		kustomizeCli,
 		containerHelper,
 		alpha.NewFeaturesManagerWithConfig(userConfig),
+		mockContext.HttpClient,
 	)
 }
 
@@ -988,3 +1983,52 @@ func (m *mockUserConfigManager) Save(config config.Config) error {
 	args := m.Called(config)
 	return args.Error(0)
 }
+
+func Test_Deploy_ValidateManifests(t *testing.T) {
+	manifests := map[string]string{
+		"deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n",
+		"service.yaml":    "apiVersion: v1\nkind: Service\nmetadata:\n  labels:\n    app: api\n",
+	}
+
+	tests := map[string]struct {
+		validation    AksManifestValidation
+		expectedError string
+	}{
+		"Local": {
+			expectedError: "service.yaml:1: missing required field(s) metadata.name",
+		},
+		"None": {
+			validation: AksManifestValidationNone,
+		},
+		"NotSupported": {
+			validation:    AksManifestValidation("offline"),
+			expectedError: "manifest validation 'offline' is not supported",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext, serviceTarget, serviceConfig := setupAksWorkloadTest(t, manifests)
+			serviceConfig.K8s.Validation = test.validation
+
+			applied := false
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				applied = true
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			_, err := deployAksWorkloadTest(t, mockContext, serviceTarget, serviceConfig)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				// None of the manifests is applied when any of them is invalid
+				require.False(t, applied)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, applied)
+		})
+	}
+}
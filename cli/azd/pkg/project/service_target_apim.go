@@ -0,0 +1,287 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"gopkg.in/yaml.v3"
+)
+
+// The definition files looked up in the service directory, in order, when the spec is not configured
+var defaultApimSpecFiles = []string{"openapi.yaml", "openapi.yml", "openapi.json", "swagger.json"}
+
+// The Azure API Management options of a service
+// The API of the service is imported from its OpenAPI definition on every deploy, so the API facade stays in sync
+// with the backend it fronts.
+type ApimOptions struct {
+	// The id of the API in the API Management service. Defaults to the name of the service
+	ApiId string `yaml:"apiId,omitempty"`
+	// The display name of the API. Defaults to the title of the definition
+	DisplayName string `yaml:"displayName,omitempty"`
+	// The URL suffix of the API, relative to the gateway URL. Defaults to the id of the API
+	Path string `yaml:"path,omitempty"`
+	// The path of the OpenAPI definition, relative to the service directory, ex) openapi.yaml
+	Spec string `yaml:"spec,omitempty"`
+	// The URL of the backend the API forwards the requests to, ex) ${SERVICE_API_URI} from the outputs of the
+	// infrastructure. Defaults to the servers of the definition.
+	ServiceUrl osutil.ExpandableString `yaml:"serviceUrl,omitempty"`
+	// Whether the API can only be called with a subscription key. Defaults to true
+	SubscriptionRequired *bool `yaml:"subscriptionRequired,omitempty"`
+}
+
+type apimTarget struct {
+	env *environment.Environment
+	cli azcli.AzCli
+}
+
+// NewApimTarget creates a new instance of the API Management service target
+func NewApimTarget(
+	env *environment.Environment,
+	azCli azcli.AzCli,
+) ServiceTarget {
+	return &apimTarget{
+		env: env,
+		cli: azCli,
+	}
+}
+
+// Gets the required external tools
+func (t *apimTarget) RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the API Management target
+func (t *apimTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Resolves the OpenAPI definition of the service, which is deployed as is
+func (t *apimTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	specPath, err := apimSpecPath(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServicePackageResult{
+		Build:       packageOutput.Build,
+		PackagePath: specPath,
+	}, nil
+}
+
+// Imports the OpenAPI definition to the API of the API Management service
+func (t *apimTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := t.validateTargetResource(targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	options := serviceConfig.Apim
+	spec, err := os.ReadFile(packageOutput.PackagePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading api definition: %w", err)
+	}
+
+	format, err := apimSpecFormat(packageOutput.PackagePath, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceUrl, err := options.ServiceUrl.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the service url of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	apiId := apimApiId(serviceConfig)
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Importing api %s", apiId)))
+	err = t.cli.ImportApimApi(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		apiId,
+		azcli.AzCliApimApi{
+			DisplayName:          options.DisplayName,
+			Path:                 apimApiPath(serviceConfig),
+			Format:               format,
+			Value:                string(spec),
+			ServiceUrl:           serviceUrl,
+			SubscriptionRequired: options.SubscriptionRequired,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err)
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching endpoints for api management"))
+	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	sdr := NewServiceDeployResult(
+		fmt.Sprintf(
+			"%s/providers/%s/%s/apis/%s",
+			azure.ResourceGroupRID(targetResource.SubscriptionId(), targetResource.ResourceGroupName()),
+			azapi.AzureResourceTypeApim,
+			targetResource.ResourceName(),
+			apiId,
+		),
+		ApimTarget,
+		apiId,
+		endpoints,
+	)
+	sdr.Package = packageOutput
+
+	return sdr, nil
+}
+
+// Gets the gateway URL of the API
+func (t *apimTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	apim, err := t.cli.GetApim(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching service properties: %w", err)
+	}
+
+	if apim.GatewayUrl == "" {
+		return []string{}, nil
+	}
+
+	return []string{
+		fmt.Sprintf("%s/%s", strings.TrimSuffix(apim.GatewayUrl, "/"), apimApiPath(serviceConfig)),
+	}, nil
+}
+
+func (t *apimTarget) validateTargetResource(targetResource *environment.TargetResource) error {
+	if !strings.EqualFold(targetResource.ResourceType(), string(azapi.AzureResourceTypeApim)) {
+		return resourceTypeMismatchError(
+			targetResource.ResourceName(),
+			targetResource.ResourceType(),
+			azapi.AzureResourceTypeApim,
+		)
+	}
+
+	return nil
+}
+
+// apimApiId returns the id of the API of the service in the API Management service
+func apimApiId(serviceConfig *ServiceConfig) string {
+	if serviceConfig.Apim.ApiId != "" {
+		return serviceConfig.Apim.ApiId
+	}
+
+	return serviceConfig.Name
+}
+
+// apimApiPath returns the URL suffix of the API of the service
+func apimApiPath(serviceConfig *ServiceConfig) string {
+	if serviceConfig.Apim.Path != "" {
+		return strings.Trim(serviceConfig.Apim.Path, "/")
+	}
+
+	return apimApiId(serviceConfig)
+}
+
+// apimSpecPath returns the path of the OpenAPI definition of the service, looking up the well known definition files
+// in the service directory when it is not configured
+func apimSpecPath(serviceConfig *ServiceConfig) (string, error) {
+	if serviceConfig.Apim.Spec != "" {
+		specPath := serviceConfig.Apim.Spec
+		if !filepath.IsAbs(specPath) {
+			specPath = filepath.Join(serviceConfig.Path(), specPath)
+		}
+
+		if _, err := os.Stat(specPath); err != nil {
+			return "", fmt.Errorf("api definition of service '%s' not found: %w", serviceConfig.Name, err)
+		}
+
+		return specPath, nil
+	}
+
+	for _, file := range defaultApimSpecFiles {
+		specPath := filepath.Join(serviceConfig.Path(), file)
+		if _, err := os.Stat(specPath); err == nil {
+			return specPath, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf(
+		"api definition of service '%s' not found, add one of %s to '%s' or set 'apim.spec'",
+		serviceConfig.Name,
+		strings.Join(defaultApimSpecFiles, ", "),
+		serviceConfig.Path(),
+	)
+}
+
+// apimSpecFormat returns the API Management content format of the definition. JSON definitions are OpenAPI 3 or
+// Swagger 2 documents, YAML definitions must be OpenAPI 3 documents as API Management can't import Swagger 2 YAML.
+func apimSpecFormat(specPath string, spec []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(specPath)) {
+	case ".json":
+		var document struct {
+			Swagger string `json:"swagger"`
+		}
+
+		if err := json.Unmarshal(spec, &document); err != nil {
+			return "", fmt.Errorf("parsing api definition '%s': %w", specPath, err)
+		}
+
+		if document.Swagger != "" {
+			return "swagger-json", nil
+		}
+
+		return "openapi+json", nil
+	case ".yaml", ".yml":
+		var document struct {
+			Swagger string `yaml:"swagger"`
+		}
+
+		if err := yaml.Unmarshal(spec, &document); err != nil {
+			return "", fmt.Errorf("parsing api definition '%s': %w", specPath, err)
+		}
+
+		if document.Swagger != "" {
+			return "", fmt.Errorf(
+				"api definition '%s' is a Swagger 2 YAML document, convert it to JSON or to OpenAPI 3", specPath)
+		}
+
+		return "openapi", nil
+	}
+
+	return "", fmt.Errorf("unsupported api definition '%s', use an OpenAPI definition in JSON or YAML", specPath)
+}
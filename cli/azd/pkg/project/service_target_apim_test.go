@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Apim_Deploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	servicePath := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+		"Microsoft.ApiManagement/service/APIM_NAME"
+
+	var imported armapimanagement.APICreateOrUpdateParameter
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, servicePath+"/apis/orders")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(body, &imported); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armapimanagement.APIContract{
+			Name: to.Ptr("orders"),
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, servicePath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armapimanagement.ServiceResource{
+			ID:       to.Ptr(servicePath),
+			Name:     to.Ptr("APIM_NAME"),
+			Location: to.Ptr("eastus2"),
+			Properties: &armapimanagement.ServiceProperties{
+				GatewayURL: to.Ptr("https://APIM_NAME.azure-api.net"),
+			},
+		})
+	})
+
+	spec := "openapi: 3.0.1\ninfo:\n  title: Orders\n  version: '1.0'\npaths: {}\n"
+	servicePathDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(servicePathDir, "openapi.yaml"), []byte(spec), osutil.PermissionFile))
+
+	serviceConfig := createTestServiceConfig(servicePathDir, ApimTarget, ServiceLanguageNone)
+	serviceConfig.Apim = ApimOptions{
+		ApiId:      "orders",
+		Path:       "/api/orders/",
+		ServiceUrl: osutil.NewExpandableString("${SERVICE_API_URI}"),
+	}
+
+	env := environment.NewWithValues("test", map[string]string{"SERVICE_API_URI": "https://api.contoso.com"})
+	azCli := azcli.NewAzCli(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+	serviceTarget := NewApimTarget(env, azCli)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"APIM_NAME",
+		string(azapi.AzureResourceTypeApim),
+	)
+
+	packageResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+			return serviceTarget.Package(*mockContext.Context, serviceConfig, &ServicePackageResult{}, progress)
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(servicePathDir, "openapi.yaml"), packageResult.PackagePath)
+
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "api/orders", *imported.Properties.Path)
+	require.Equal(t, armapimanagement.ContentFormatOpenapi, *imported.Properties.Format)
+	require.Equal(t, spec, *imported.Properties.Value)
+	require.Equal(t, "https://api.contoso.com", *imported.Properties.ServiceURL)
+	require.Nil(t, imported.Properties.SubscriptionRequired)
+
+	require.Equal(t, "orders", deployResult.Details)
+	require.Equal(t, []string{"https://APIM_NAME.azure-api.net/api/orders"}, deployResult.Endpoints)
+}
+
+func Test_Apim_Package_SpecNotFound(t *testing.T) {
+	serviceConfig := createTestServiceConfig(t.TempDir(), ApimTarget, ServiceLanguageNone)
+	serviceTarget := NewApimTarget(environment.New("test"), nil)
+
+	_, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+			return serviceTarget.Package(context.Background(), serviceConfig, &ServicePackageResult{}, progress)
+		},
+	)
+	require.ErrorContains(t, err, "api definition of service 'api' not found")
+	require.ErrorContains(t, err, "set 'apim.spec'")
+}
+
+func Test_apimSpecFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		spec        string
+		expected    string
+		expectedErr string
+	}{
+		{name: "OpenApiYaml", path: "openapi.yaml", spec: "openapi: 3.0.1", expected: "openapi"},
+		{name: "OpenApiJson", path: "openapi.json", spec: `{"openapi": "3.0.1"}`, expected: "openapi+json"},
+		{name: "SwaggerJson", path: "swagger.json", spec: `{"swagger": "2.0"}`, expected: "swagger-json"},
+		{
+			name:        "SwaggerYaml",
+			path:        "swagger.yaml",
+			spec:        "swagger: '2.0'",
+			expectedErr: "is a Swagger 2 YAML document",
+		},
+		{name: "Unsupported", path: "api.wsdl", spec: "<definitions/>", expectedErr: "unsupported api definition"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := apimSpecFormat(tt.path, []byte(tt.spec))
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, format)
+		})
+	}
+}
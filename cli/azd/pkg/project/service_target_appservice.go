@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
@@ -17,19 +18,30 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 )
 
+// The Azure App Service options of a service
+type AppServiceOptions struct {
+	// The deployment slot the app service is deployed to before it is swapped with the production slot
+	SlotOptions `yaml:",inline"`
+	// The custom domains bound to the app service with App Service managed certificates
+	CustomDomains []AppServiceCustomDomainOptions `yaml:"customDomains,omitempty"`
+}
+
 type appServiceTarget struct {
-	env *environment.Environment
-	cli azcli.AzCli
+	env         *environment.Environment
+	cli         azcli.AzCli
+	transporter policy.Transporter
 }
 
 // NewAppServiceTarget creates a new instance of the AppServiceTarget
 func NewAppServiceTarget(
 	env *environment.Environment,
 	azCli azcli.AzCli,
+	transporter policy.Transporter,
 ) ServiceTarget {
 	return &appServiceTarget{
-		env: env,
-		cli: azCli,
+		env:         env,
+		cli:         azCli,
+		transporter: transporter,
 	}
 }
 
@@ -78,6 +90,10 @@ func (st *appServiceTarget) Deploy(
 		return nil, fmt.Errorf("validating target resource: %w", err)
 	}
 
+	if err := validateAppServiceCustomDomains(serviceConfig); err != nil {
+		return nil, err
+	}
+
 	zipFile, err := os.Open(packageOutput.PackagePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed reading deployment zip file: %w", err)
@@ -86,23 +102,65 @@ func (st *appServiceTarget) Deploy(
 	defer os.Remove(packageOutput.PackagePath)
 	defer zipFile.Close()
 
+	slot, err := resolveSlot(serviceConfig, serviceConfig.AppService.SlotOptions, st.env)
+	if err != nil {
+		return nil, err
+	}
+
 	progress.SetProgress(NewServiceProgress("Uploading deployment package"))
-	res, err := st.cli.DeployAppServiceZip(
-		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
-		targetResource.ResourceName(),
-		zipFile,
-		func(logProgress string) { progress.SetProgress(NewServiceProgress(logProgress)) },
-	)
+	logProgress := func(logProgress string) { progress.SetProgress(NewServiceProgress(logProgress)) }
+	var res *string
+	if slot != "" {
+		res, err = st.cli.DeployAppServiceSlotZip(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			slot,
+			zipFile,
+			logProgress,
+		)
+	} else {
+		res, err = st.cli.DeployAppServiceZip(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			zipFile,
+			logProgress,
+		)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err)
 	}
 
-	progress.SetProgress(NewServiceProgress("Fetching endpoints for app service"))
-	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
-	if err != nil {
-		return nil, err
+	var endpoints []string
+	if slot != "" {
+		slotEndpoints, err := deploySlot(
+			ctx, st.transporter, st.webAppSlot(targetResource, slot), serviceConfig.AppService.SlotOptions, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		// The new version is only reachable through the slot until it is swapped manually
+		if serviceConfig.AppService.NoSwap {
+			endpoints = slotEndpoints
+		}
+	}
+
+	if len(serviceConfig.AppService.CustomDomains) > 0 {
+		progress.SetProgress(NewServiceProgress("Binding custom domains"))
+		if err := st.bindCustomDomains(ctx, serviceConfig, targetResource); err != nil {
+			return nil, err
+		}
+	}
+
+	if endpoints == nil {
+		progress.SetProgress(NewServiceProgress("Fetching endpoints for app service"))
+		endpoints, err = st.Endpoints(ctx, serviceConfig, targetResource)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	sdr := NewServiceDeployResult(
@@ -144,6 +202,36 @@ func (st *appServiceTarget) Endpoints(
 	return endpoints, nil
 }
 
+// webAppSlot returns the deployment slot of the app service
+func (st *appServiceTarget) webAppSlot(targetResource *environment.TargetResource, slot string) webAppSlot {
+	return webAppSlot{
+		name: slot,
+		hostNames: func(ctx context.Context) ([]string, error) {
+			props, err := st.cli.GetAppServiceSlotProperties(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				slot,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return props.HostNames, nil
+		},
+		swap: func(ctx context.Context) error {
+			return st.cli.SwapAppServiceSlot(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				slot,
+			)
+		},
+	}
+}
+
 func (st *appServiceTarget) validateTargetResource(
 	targetResource *environment.TargetResource,
 ) error {
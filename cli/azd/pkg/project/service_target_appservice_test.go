@@ -4,11 +4,23 @@
 package project
 
 import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,3 +65,168 @@ func TestNewAppServiceTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_AppService_Deploy_Slot(t *testing.T) {
+	tests := []struct {
+		name              string
+		noSwap            bool
+		smokeTestStatus   int
+		expectedEndpoints []string
+		expectedSwap      bool
+		expectedErr       string
+	}{
+		{
+			name:              "Swap",
+			smokeTestStatus:   http.StatusOK,
+			expectedEndpoints: []string{"https://WEB_APP_NAME.azurewebsites.net/"},
+			expectedSwap:      true,
+		},
+		{
+			name:              "NoSwap",
+			noSwap:            true,
+			smokeTestStatus:   http.StatusOK,
+			expectedEndpoints: []string{"Slot staging: https://WEB_APP_NAME-staging.azurewebsites.net/"},
+		},
+		{
+			name:            "SmokeTestFailed",
+			smokeTestStatus: http.StatusInternalServerError,
+			expectedErr:     "smoke test of slot 'staging' failed, the slot was not swapped with production",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			swapped := setupMocksForAppServiceSlot(mockContext, tt.smokeTestStatus)
+
+			zipFilePath := filepath.Join(t.TempDir(), "package.zip")
+			require.NoError(t, os.WriteFile(zipFilePath, []byte{}, osutil.PermissionFile))
+
+			// The slot is resolved from the environment, so each environment can deploy to its own slot
+			env := environment.NewWithValues("test", map[string]string{"AZURE_APP_SLOT": "staging"})
+			serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
+			serviceConfig.AppService = AppServiceOptions{
+				SlotOptions: SlotOptions{
+					Slot:               osutil.NewExpandableString("${AZURE_APP_SLOT}"),
+					HealthCheckPath:    "/health",
+					HealthCheckTimeout: time.Millisecond,
+					SmokeTest: &SlotSmokeTestOptions{
+						Path:           "/api/orders",
+						ExpectedStatus: http.StatusOK,
+					},
+					NoSwap: tt.noSwap,
+				},
+			}
+
+			azCli := azcli.NewAzCli(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+			serviceTarget := NewAppServiceTarget(env, azCli, mockContext.HttpClient)
+			scope := environment.NewTargetResource(
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"WEB_APP_NAME",
+				string(azapi.AzureResourceTypeWebSite),
+			)
+
+			packageResult := &ServicePackageResult{PackagePath: zipFilePath}
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+				},
+			)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				require.False(t, *swapped)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedEndpoints, deployResult.Endpoints)
+			require.Equal(t, tt.expectedSwap, *swapped)
+		})
+	}
+}
+
+func setupMocksForAppServiceSlot(mockContext *mocks.MockContext, smokeTestStatus int) *bool {
+	sitePath := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/WEB_APP_NAME"
+	site := func(hostName string, repositoryHost string) armappservice.Site {
+		return armappservice.Site{
+			Kind: to.Ptr("app,linux"),
+			Properties: &armappservice.SiteProperties{
+				DefaultHostName: to.Ptr(hostName),
+				SiteConfig: &armappservice.SiteConfig{
+					LinuxFxVersion: to.Ptr("Python"),
+				},
+				HostNameSSLStates: []*armappservice.HostNameSSLState{
+					{
+						HostType: to.Ptr(armappservice.HostTypeRepository),
+						Name:     to.Ptr(repositoryHost),
+					},
+				},
+			},
+		}
+	}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, sitePath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WebAppsClientGetResponse{
+			Site: site("WEB_APP_NAME.azurewebsites.net", "WEB_APP_NAME.scm.azurewebsites.net"),
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, sitePath+"/slots/staging")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WebAppsClientGetSlotResponse{
+			Site: site("WEB_APP_NAME-staging.azurewebsites.net", "WEB_APP_NAME-staging.scm.azurewebsites.net"),
+		})
+	})
+
+	// The package is only deployed to the slot
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			request.URL.Host == "WEB_APP_NAME-staging.scm.azurewebsites.net" &&
+			strings.Contains(request.URL.Path, "/api/zipdeploy")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response, _ := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+		response.Header.Set("Location", "https://WEB_APP_NAME-staging.scm.azurewebsites.net/deployments/latest")
+
+		return response, nil
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/deployments/latest")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DeployStatusResponse{
+			DeployStatus: azsdk.DeployStatus{
+				Status:     http.StatusOK,
+				StatusText: "OK",
+				Complete:   true,
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Host == "WEB_APP_NAME-staging.azurewebsites.net" &&
+			request.URL.Path == "/health"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Host == "WEB_APP_NAME-staging.azurewebsites.net" &&
+			request.URL.Path == "/api/orders"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, smokeTestStatus)
+	})
+
+	swapped := false
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, sitePath+"/slotsswap")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		swapped = true
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	return &swapped
+}
@@ -0,0 +1,484 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/sethvargo/go-retry"
+)
+
+const (
+	// The time the deployment waits for the task to complete when not configured
+	defaultBatchTimeout = 30 * time.Minute
+	// The placeholder of the command line of the task replaced with the directory the application package is
+	// extracted to on the compute node
+	batchAppDirPlaceholder = "BATCH_APP_DIR"
+)
+
+// The interval the state of the task is polled at while waiting for the task to complete
+var batchTaskPollInterval = 10 * time.Second
+
+// The Azure Batch options of a service
+// The build output of the service is uploaded as a new version of a Batch application package and a task running
+// the version is added to the job of the service, which is created on the pool when it doesn't exist.
+type BatchOptions struct {
+	// The pool the job runs on, ex) ${AZURE_BATCH_POOL_ID}
+	Pool osutil.ExpandableString `yaml:"pool"`
+	// The job the tasks are added to. Defaults to the name of the service
+	Job osutil.ExpandableString `yaml:"job,omitempty"`
+	// The id of the application package. Defaults to the name of the service
+	Application string `yaml:"application,omitempty"`
+	// The priority of the job, from -1000 to 1000
+	Priority int `yaml:"priority,omitempty"`
+	// The template of the task added on every deploy
+	Task BatchTaskOptions `yaml:"task"`
+	// When enabled, the deployment doesn't wait for the task to complete
+	NoWait bool `yaml:"noWait,omitempty"`
+	// How long the deployment waits for the task to complete, ex) 1h. Defaults to 30m
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// The template of the task of a Batch service
+type BatchTaskOptions struct {
+	// The command line of the task, ex) /bin/sh -c '${BATCH_APP_DIR}/run.sh'.
+	// ${BATCH_APP_DIR} is replaced with the directory the application package is extracted to on the node
+	CommandLine osutil.ExpandableString `yaml:"commandLine"`
+	// The environment variables of the task
+	Env map[string]osutil.ExpandableString `yaml:"env,omitempty"`
+	// How long the task may run, ex) 2h
+	MaxWallClockTime time.Duration `yaml:"maxWallClockTime,omitempty"`
+	// The number of times the task is retried when it fails
+	MaxRetries int `yaml:"maxRetries,omitempty"`
+}
+
+type batchTarget struct {
+	env          *environment.Environment
+	batchService azcli.BatchService
+}
+
+// NewBatchTarget creates the service target for compute intensive workloads running as Azure Batch tasks
+func NewBatchTarget(
+	env *environment.Environment,
+	batchService azcli.BatchService,
+) ServiceTarget {
+	return &batchTarget{
+		env:          env,
+		batchService: batchService,
+	}
+}
+
+// Gets the required external tools
+func (t *batchTarget) RequiredExternalTools(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the batch target
+func (t *batchTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Prepares a zip archive of the build output, the format of Batch application packages
+func (t *batchTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	progress.SetProgress(NewServiceProgress("Compressing deployment artifacts"))
+	zipFilePath, err := createDeployableZip(serviceConfig.Project.Name, serviceConfig.Name, packageOutput.PackagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServicePackageResult{
+		Build:       packageOutput.Build,
+		PackagePath: zipFilePath,
+	}, nil
+}
+
+// Uploads the application package and submits its task to the job of the service, waiting for the task to complete
+func (t *batchTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := checkResourceType(targetResource, azapi.AzureResourceTypeBatchAccount); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	defer os.Remove(packageOutput.PackagePath)
+
+	options := serviceConfig.Batch
+	poolId, err := options.Pool.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the pool of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if poolId == "" {
+		return nil, fmt.Errorf("the pool of the job is not set, set 'batch.pool' of service '%s'", serviceConfig.Name)
+	}
+
+	jobId, err := options.Job.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the job of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if jobId == "" {
+		jobId = serviceConfig.Name
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Validating pool %s", poolId)))
+	pool, err := t.batchService.GetBatchPool(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		poolId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateBatchPool(serviceConfig, pool); err != nil {
+		return nil, err
+	}
+
+	applicationId := options.Application
+	if applicationId == "" {
+		applicationId = serviceConfig.Name
+	}
+
+	release := time.Now().UTC().Format("20060102-150405")
+	task, err := t.batchTask(serviceConfig, pool, applicationId, release)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Uploading application package %s %s", applicationId, release)))
+	err = t.batchService.UploadBatchApplicationPackage(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		applicationId,
+		release,
+		packageOutput.PackagePath,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("uploading application package of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	endpoint, err := t.batchService.GetBatchAccountEndpoint(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Submitting job %s", jobId)))
+	if err := t.submitJob(ctx, targetResource, endpoint, jobId, poolId, options.Priority); err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Adding task %s", task.Id)))
+	if err := t.batchService.AddBatchTask(ctx, targetResource.SubscriptionId(), endpoint, jobId, task); err != nil {
+		return nil, err
+	}
+
+	deployResult := &batchDeployResult{
+		Job:   jobId,
+		Task:  task.Id,
+		State: "active",
+	}
+
+	if !options.NoWait {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Waiting for task %s", task.Id)))
+		completed, err := t.waitForTask(ctx, serviceConfig, targetResource, endpoint, jobId, task.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		deployResult.State = completed.State
+		if completed.ExecutionInfo != nil {
+			deployResult.ExitCode = completed.ExecutionInfo.ExitCode
+		}
+	}
+
+	return &ServiceDeployResult{
+		Package: packageOutput,
+		TargetResourceId: fmt.Sprintf(
+			"%s/providers/%s/%s",
+			azure.ResourceGroupRID(targetResource.SubscriptionId(), targetResource.ResourceGroupName()),
+			azapi.AzureResourceTypeBatchAccount,
+			targetResource.ResourceName(),
+		),
+		Kind:      BatchTarget,
+		Endpoints: []string{},
+		Details:   deployResult,
+	}, nil
+}
+
+// Batch services have no endpoints
+func (t *batchTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	return []string{}, nil
+}
+
+// submitJob adds the job on the pool when it doesn't exist, otherwise updates its priority
+func (t *batchTarget) submitJob(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	endpoint string,
+	jobId string,
+	poolId string,
+	priority int,
+) error {
+	job, err := t.batchService.GetBatchJob(ctx, targetResource.SubscriptionId(), endpoint, jobId)
+	if errors.Is(err, azcli.ErrBatchJobNotFound) {
+		return t.batchService.AddBatchJob(ctx, targetResource.SubscriptionId(), endpoint, &azcli.BatchJob{
+			Id:       jobId,
+			Priority: priority,
+			PoolInfo: &azcli.BatchJobPoolInfo{PoolId: poolId},
+		})
+	} else if err != nil {
+		return err
+	}
+
+	if job.State != "active" && job.State != "disabled" {
+		return fmt.Errorf("tasks can't be added to job '%s' as it is %s, delete the job to recreate it", jobId, job.State)
+	}
+
+	if job.PoolInfo != nil && job.PoolInfo.PoolId != "" && !strings.EqualFold(job.PoolInfo.PoolId, poolId) {
+		return fmt.Errorf(
+			"job '%s' runs on pool '%s' instead of pool '%s', delete the job to recreate it on pool '%s'",
+			jobId,
+			job.PoolInfo.PoolId,
+			poolId,
+			poolId,
+		)
+	}
+
+	job.Priority = priority
+	return t.batchService.UpdateBatchJob(ctx, targetResource.SubscriptionId(), endpoint, job)
+}
+
+// batchTask creates the task running the version of the application package from the task template of the service
+func (t *batchTarget) batchTask(
+	serviceConfig *ServiceConfig,
+	pool *azcli.BatchPool,
+	applicationId string,
+	version string,
+) (*azcli.BatchTask, error) {
+	options := serviceConfig.Batch.Task
+	appDir := batchAppPackageDir(applicationId, version, isWindowsBatchPool(pool))
+	commandLine, err := options.CommandLine.Envsubst(func(name string) string {
+		if name == batchAppDirPlaceholder {
+			return appDir
+		}
+
+		return t.env.Getenv(name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the command line of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if strings.TrimSpace(commandLine) == "" {
+		return nil, fmt.Errorf(
+			"the command line of the task is not set, set 'batch.task.commandLine' of service '%s'", serviceConfig.Name)
+	}
+
+	task := &azcli.BatchTask{
+		Id:          fmt.Sprintf("%s-%s", serviceConfig.Name, version),
+		DisplayName: fmt.Sprintf("%s %s", serviceConfig.Name, version),
+		CommandLine: commandLine,
+		ApplicationPackageReferences: []*azcli.BatchApplicationPackageReference{
+			{ApplicationId: applicationId, Version: version},
+		},
+		EnvironmentSettings: []*azcli.BatchEnvironmentSetting{},
+		Constraints: &azcli.BatchTaskConstraints{
+			MaxTaskRetryCount: options.MaxRetries,
+		},
+	}
+
+	if options.MaxWallClockTime > 0 {
+		task.Constraints.MaxWallClockTime = fmt.Sprintf("PT%dS", int(options.MaxWallClockTime.Seconds()))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(options.Env)) {
+		value, err := options.Env[name].Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to envsubst the environment variable '%s' of service '%s': %w", name, serviceConfig.Name, err)
+		}
+
+		task.EnvironmentSettings = append(task.EnvironmentSettings, &azcli.BatchEnvironmentSetting{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	return task, nil
+}
+
+// waitForTask polls the task until it completes and returns an error when it failed
+func (t *batchTarget) waitForTask(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	endpoint string,
+	jobId string,
+	taskId string,
+) (*azcli.BatchTask, error) {
+	timeout := serviceConfig.Batch.Timeout
+	if timeout == 0 {
+		timeout = defaultBatchTimeout
+	}
+
+	var task *azcli.BatchTask
+	err := retry.Do(
+		ctx,
+		retry.WithMaxDuration(timeout, retry.NewConstant(batchTaskPollInterval)),
+		func(ctx context.Context) error {
+			var err error
+			task, err = t.batchService.GetBatchTask(ctx, targetResource.SubscriptionId(), endpoint, jobId, taskId)
+			if err != nil {
+				return err
+			}
+
+			if task.State != "completed" {
+				return retry.RetryableError(fmt.Errorf("task is %s", task.State))
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"task '%s' of service '%s' was submitted but did not complete within %s: %w",
+			taskId,
+			serviceConfig.Name,
+			timeout,
+			err,
+		)
+	}
+
+	if info := task.ExecutionInfo; info != nil && info.Result == "failure" {
+		message := ""
+		if info.FailureInfo != nil {
+			message = fmt.Sprintf(": %s", info.FailureInfo.Message)
+		}
+
+		exitCode := "none"
+		if info.ExitCode != nil {
+			exitCode = fmt.Sprint(*info.ExitCode)
+		}
+
+		return nil, fmt.Errorf(
+			"task '%s' of service '%s' failed (exit code: %s, retries: %d)%s",
+			taskId,
+			serviceConfig.Name,
+			exitCode,
+			info.RetryCount,
+			message,
+		)
+	}
+
+	return task, nil
+}
+
+// validateBatchPool verifies the task of the service can run on the pool
+func validateBatchPool(serviceConfig *ServiceConfig, pool *azcli.BatchPool) error {
+	if !strings.EqualFold(pool.ProvisioningState, "Succeeded") {
+		return fmt.Errorf("pool '%s' is %s, the pool must be provisioned", pool.Name, pool.ProvisioningState)
+	}
+
+	// A task waits in the queue of the job until the pool has nodes, which would never happen for a pool without
+	// nodes that is not resizing or autoscaling
+	if !serviceConfig.Batch.NoWait &&
+		pool.DedicatedNodes+pool.LowPriorityNodes == 0 &&
+		!pool.AutoScale &&
+		!strings.EqualFold(pool.AllocationState, "resizing") {
+		return fmt.Errorf(
+			"pool '%s' has no nodes and doesn't autoscale so the task of service '%s' would never run, "+
+				"resize the pool or set 'batch.noWait'",
+			pool.Name,
+			serviceConfig.Name,
+		)
+	}
+
+	return nil
+}
+
+// isWindowsBatchPool returns whether the nodes of the pool run Windows
+func isWindowsBatchPool(pool *azcli.BatchPool) bool {
+	return strings.Contains(strings.ToLower(pool.NodeAgentSkuId), "windows")
+}
+
+// batchAppPackageDir returns the reference to the environment variable Batch sets to the directory the version of
+// the application package is extracted to. On Linux nodes the periods, hyphens and number signs of the name are
+// replaced with underscores.
+func batchAppPackageDir(applicationId string, version string, windows bool) string {
+	if windows {
+		return fmt.Sprintf("%%AZ_BATCH_APP_PACKAGE_%s#%s%%", strings.ToUpper(applicationId), version)
+	}
+
+	replacer := strings.NewReplacer(".", "_", "-", "_", "#", "_")
+	return fmt.Sprintf("$AZ_BATCH_APP_PACKAGE_%s_%s", replacer.Replace(applicationId), replacer.Replace(version))
+}
+
+// batchDeployResult reports the task submitted to the job of the service and its final state
+type batchDeployResult struct {
+	Job   string `json:"job"`
+	Task  string `json:"task"`
+	State string `json:"state"`
+	// The exit code of the completed task
+	ExitCode *int `json:"exitCode,omitempty"`
+}
+
+func (r *batchDeployResult) ToString(currentIndentation string) string {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("%s- Job: %s\n", currentIndentation, output.WithHighLightFormat(r.Job)))
+
+	state := r.State
+	if r.ExitCode != nil {
+		state = fmt.Sprintf("%s, exit code %d", state, *r.ExitCode)
+	}
+
+	builder.WriteString(fmt.Sprintf(
+		"%s- Task: %s (%s)\n", currentIndentation, output.WithHighLightFormat(r.Task), state))
+
+	return builder.String()
+}
+
+func (r *batchDeployResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*r)
+}
@@ -0,0 +1,314 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Batch_Deploy(t *testing.T) {
+	t.Run("NewJob", func(t *testing.T) {
+		batchService := &fakeBatchService{
+			pool: &azcli.BatchPool{
+				Name:              "pool-01",
+				ProvisioningState: "Succeeded",
+				NodeAgentSkuId:    "batch.node.ubuntu 22.04",
+				DedicatedNodes:    2,
+			},
+			taskState: &azcli.BatchTask{
+				State:         "completed",
+				ExecutionInfo: &azcli.BatchTaskExecutionInfo{ExitCode: to.Ptr(0), Result: "success"},
+			},
+		}
+		serviceConfig := createBatchServiceConfig(t)
+		serviceConfig.Batch.Task.Env = map[string]osutil.ExpandableString{
+			"LOCATION": osutil.NewExpandableString("${AZURE_LOCATION}"),
+		}
+		serviceConfig.Batch.Task.MaxWallClockTime = time.Hour
+
+		deployResult, err := deployBatch(t, serviceConfig, batchService)
+		require.NoError(t, err)
+
+		require.Equal(t, "api", batchService.application)
+		require.Equal(t, &azcli.BatchJob{
+			Id:       "api",
+			PoolInfo: &azcli.BatchJobPoolInfo{PoolId: "pool-01"},
+		}, batchService.added)
+		require.Nil(t, batchService.updated)
+
+		task := batchService.task
+		version := batchService.version
+		require.Equal(t, "api-"+version, task.Id)
+		require.Equal(t,
+			"/bin/sh -c '$AZ_BATCH_APP_PACKAGE_api_"+strings.ReplaceAll(version, "-", "_")+"/run.sh'", task.CommandLine)
+		require.Equal(t, []*azcli.BatchApplicationPackageReference{
+			{ApplicationId: "api", Version: version},
+		}, task.ApplicationPackageReferences)
+		require.Equal(t, []*azcli.BatchEnvironmentSetting{{Name: "LOCATION", Value: "eastus2"}}, task.EnvironmentSettings)
+		require.Equal(t, &azcli.BatchTaskConstraints{MaxWallClockTime: "PT3600S"}, task.Constraints)
+
+		require.Equal(t, BatchTarget, deployResult.Kind)
+		require.Empty(t, deployResult.Endpoints)
+		require.Equal(t, &batchDeployResult{
+			Job:      "api",
+			Task:     task.Id,
+			State:    "completed",
+			ExitCode: to.Ptr(0),
+		}, deployResult.Details)
+	})
+
+	t.Run("ExistingJob", func(t *testing.T) {
+		batchService := &fakeBatchService{
+			pool: &azcli.BatchPool{Name: "pool-01", ProvisioningState: "Succeeded", AutoScale: true},
+			job: &azcli.BatchJob{
+				Id:       "api",
+				State:    "active",
+				PoolInfo: &azcli.BatchJobPoolInfo{PoolId: "pool-01"},
+			},
+		}
+		serviceConfig := createBatchServiceConfig(t)
+		serviceConfig.Batch.Priority = 100
+		serviceConfig.Batch.NoWait = true
+
+		deployResult, err := deployBatch(t, serviceConfig, batchService)
+		require.NoError(t, err)
+
+		require.Nil(t, batchService.added)
+		require.Equal(t, 100, batchService.updated.Priority)
+		require.Equal(t, "active", deployResult.Details.(*batchDeployResult).State)
+	})
+
+	t.Run("ExistingJobOnOtherPool", func(t *testing.T) {
+		batchService := &fakeBatchService{
+			pool: &azcli.BatchPool{Name: "pool-01", ProvisioningState: "Succeeded", AutoScale: true},
+			job: &azcli.BatchJob{
+				Id:       "api",
+				State:    "active",
+				PoolInfo: &azcli.BatchJobPoolInfo{PoolId: "pool-00"},
+			},
+		}
+
+		_, err := deployBatch(t, createBatchServiceConfig(t), batchService)
+		require.ErrorContains(t, err, "job 'api' runs on pool 'pool-00' instead of pool 'pool-01'")
+		require.Nil(t, batchService.task)
+	})
+
+	t.Run("TaskFailed", func(t *testing.T) {
+		batchService := &fakeBatchService{
+			pool: &azcli.BatchPool{Name: "pool-01", ProvisioningState: "Succeeded", DedicatedNodes: 1},
+			taskState: &azcli.BatchTask{
+				State: "completed",
+				ExecutionInfo: &azcli.BatchTaskExecutionInfo{
+					ExitCode:    to.Ptr(2),
+					Result:      "failure",
+					FailureInfo: &azcli.BatchTaskFailureInfo{Message: "The task exited with an exit code"},
+				},
+			},
+		}
+
+		_, err := deployBatch(t, createBatchServiceConfig(t), batchService)
+		require.ErrorContains(t, err, "failed (exit code: 2, retries: 0): The task exited with an exit code")
+	})
+
+	t.Run("TaskNotCompleted", func(t *testing.T) {
+		batchService := &fakeBatchService{
+			pool:      &azcli.BatchPool{Name: "pool-01", ProvisioningState: "Succeeded", DedicatedNodes: 1},
+			taskState: &azcli.BatchTask{State: "running"},
+		}
+		serviceConfig := createBatchServiceConfig(t)
+		serviceConfig.Batch.Timeout = time.Millisecond
+
+		_, err := deployBatch(t, serviceConfig, batchService)
+		require.ErrorContains(t, err, "was submitted but did not complete within 1ms: task is running")
+	})
+}
+
+func Test_validateBatchPool(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", BatchTarget, ServiceLanguagePython)
+
+	err := validateBatchPool(serviceConfig, &azcli.BatchPool{Name: "pool-01", ProvisioningState: "Deleting"})
+	require.ErrorContains(t, err, "pool 'pool-01' is Deleting")
+
+	empty := &azcli.BatchPool{Name: "pool-01", ProvisioningState: "Succeeded", AllocationState: "steady"}
+	require.ErrorContains(t, validateBatchPool(serviceConfig, empty), "pool 'pool-01' has no nodes and doesn't autoscale")
+
+	empty.AllocationState = "resizing"
+	require.NoError(t, validateBatchPool(serviceConfig, empty))
+
+	empty.AllocationState = "steady"
+	serviceConfig.Batch.NoWait = true
+	require.NoError(t, validateBatchPool(serviceConfig, empty))
+}
+
+func Test_batchAppPackageDir(t *testing.T) {
+	require.Equal(t, "$AZ_BATCH_APP_PACKAGE_my_app_20240101_000000", batchAppPackageDir("my-app", "20240101-000000", false))
+	require.Equal(t, "%AZ_BATCH_APP_PACKAGE_MY-APP#20240101-000000%", batchAppPackageDir("my-app", "20240101-000000", true))
+}
+
+func Test_BatchDeployResult_ToString(t *testing.T) {
+	result := &batchDeployResult{
+		Job:      "api",
+		Task:     "api-20240101-000000",
+		State:    "completed",
+		ExitCode: to.Ptr(0),
+	}
+
+	require.Equal(t, "  - Job: api\n  - Task: api-20240101-000000 (completed, exit code 0)\n", result.ToString("  "))
+}
+
+func createBatchServiceConfig(t *testing.T) *ServiceConfig {
+	serviceConfig := createTestServiceConfig(t.TempDir(), BatchTarget, ServiceLanguagePython)
+	serviceConfig.Batch = BatchOptions{
+		Pool: osutil.NewExpandableString("${AZURE_BATCH_POOL_ID}"),
+		Task: BatchTaskOptions{
+			CommandLine: osutil.NewExpandableString("/bin/sh -c '${BATCH_APP_DIR}/run.sh'"),
+		},
+	}
+
+	return serviceConfig
+}
+
+func deployBatch(
+	t *testing.T,
+	serviceConfig *ServiceConfig,
+	batchService azcli.BatchService,
+) (*ServiceDeployResult, error) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	packagePath := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, os.WriteFile(packagePath, []byte("zip"), osutil.PermissionFile))
+
+	env := createEnv()
+	env.DotenvSet("AZURE_BATCH_POOL_ID", "pool-01")
+	env.DotenvSet("AZURE_LOCATION", "eastus2")
+
+	serviceTarget := NewBatchTarget(env, batchService)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"BATCH_ACCOUNT",
+		string(azapi.AzureResourceTypeBatchAccount),
+	)
+	packageResult := &ServicePackageResult{PackagePath: packagePath}
+
+	return logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+		},
+	)
+}
+
+// fakeBatchService records the application package uploaded and the job and task submitted to the Batch account
+type fakeBatchService struct {
+	pool      *azcli.BatchPool
+	job       *azcli.BatchJob
+	taskState *azcli.BatchTask
+
+	application string
+	version     string
+	added       *azcli.BatchJob
+	updated     *azcli.BatchJob
+	task        *azcli.BatchTask
+}
+
+func (f *fakeBatchService) GetBatchAccountEndpoint(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+) (string, error) {
+	return "batch-account.eastus2.batch.azure.com", nil
+}
+
+func (f *fakeBatchService) GetBatchPool(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+	poolName string,
+) (*azcli.BatchPool, error) {
+	return f.pool, nil
+}
+
+func (f *fakeBatchService) UploadBatchApplicationPackage(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+	applicationId string,
+	version string,
+	packagePath string,
+) error {
+	f.application = applicationId
+	f.version = version
+	return nil
+}
+
+func (f *fakeBatchService) GetBatchJob(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	jobId string,
+) (*azcli.BatchJob, error) {
+	if f.job == nil {
+		return nil, azcli.ErrBatchJobNotFound
+	}
+
+	return f.job, nil
+}
+
+func (f *fakeBatchService) AddBatchJob(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	job *azcli.BatchJob,
+) error {
+	f.added = job
+	return nil
+}
+
+func (f *fakeBatchService) UpdateBatchJob(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	job *azcli.BatchJob,
+) error {
+	f.updated = job
+	return nil
+}
+
+func (f *fakeBatchService) AddBatchTask(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	jobId string,
+	task *azcli.BatchTask,
+) error {
+	f.task = task
+	return nil
+}
+
+func (f *fakeBatchService) GetBatchTask(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	jobId string,
+	taskId string,
+) (*azcli.BatchTask, error) {
+	return f.taskState, nil
+}
@@ -5,7 +5,9 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -80,19 +82,84 @@ func (at *containerAppTarget) Deploy(
 		return nil, fmt.Errorf("validating target resource: %w", err)
 	}
 
+	if err := validateContainerAppOptions(serviceConfig); err != nil {
+		return nil, err
+	}
+
 	// Login, tag & push container image to ACR
 	_, err := at.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true, progress)
 	if err != nil {
 		return nil, err
 	}
 
+	// The previous revisions remain active to split the traffic with the new revision or to roll back to them
 	containerAppOptions := containerapps.ContainerAppOptions{
-		ApiVersion: serviceConfig.ApiVersion,
+		ApiVersion:        serviceConfig.ApiVersion,
+		TrafficWeight:     serviceConfig.ContainerApp.Traffic,
+		MultipleRevisions: serviceConfig.ContainerApp.Rollback,
 	}
 
 	imageName := at.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+
+	// Images of registries other than ACR are pulled with the registry credentials stored in the container app
+	registryCredentials, err := at.containerHelper.externalRegistryCredentials(
+		ctx, serviceConfig, targetResource.SubscriptionId(), imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	if registryCredentials != nil {
+		containerAppOptions.Registry = &containerapps.ContainerAppRegistry{
+			Server:   registryCredentials.LoginServer,
+			Username: registryCredentials.Username,
+			Password: registryCredentials.Password,
+		}
+	}
+
+	// Services imported from a compose file carry the environment variables and ports of the compose service
+	if serviceConfig.Compose != nil {
+		env, err := serviceConfig.Compose.resolveEnvironment(at.env)
+		if err != nil {
+			return nil, err
+		}
+
+		containerAppOptions.Env = env
+		if len(serviceConfig.Compose.Ports) > 0 {
+			containerAppOptions.TargetPort = serviceConfig.Compose.Ports[0]
+		}
+	}
+
+	// The components are applied before the revision is added since the Dapr sidecar loads them when it starts
+	if serviceConfig.Dapr != nil {
+		if err := validateDaprOptions(serviceConfig); err != nil {
+			return nil, err
+		}
+
+		if serviceConfig.Dapr.Components != "" {
+			progress.SetProgress(NewServiceProgress("Applying Dapr components"))
+			if err := at.applyDaprComponents(ctx, serviceConfig, targetResource); err != nil {
+				return nil, err
+			}
+		}
+
+		containerAppOptions.Dapr = &containerapps.ContainerAppDapr{
+			AppId:       serviceConfig.Dapr.appId(serviceConfig),
+			AppPort:     serviceConfig.Dapr.AppPort,
+			AppProtocol: serviceConfig.Dapr.AppProtocol,
+		}
+	}
+
+	// The traffic of the previous revisions is restored when the new revision is rolled back
+	var previousTraffic map[string]int
+	if serviceConfig.ContainerApp.Rollback {
+		previousTraffic, err = at.trafficWeights(ctx, targetResource, &containerAppOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	progress.SetProgress(NewServiceProgress("Updating container app revision"))
-	err = at.containerAppService.AddRevision(
+	revisionName, err := at.containerAppService.AddRevision(
 		ctx,
 		targetResource.SubscriptionId(),
 		targetResource.ResourceGroupName(),
@@ -104,6 +171,26 @@ func (at *containerAppTarget) Deploy(
 		return nil, fmt.Errorf("updating container app service: %w", err)
 	}
 
+	if serviceConfig.ContainerApp.Rollback {
+		err := at.verifyRevision(
+			ctx, serviceConfig, targetResource, revisionName, previousTraffic, &containerAppOptions, progress)
+		if err != nil {
+			return nil, err
+		}
+	} else if serviceConfig.Dapr != nil && serviceConfig.Dapr.waitForSidecar() {
+		err := at.waitForDaprRevision(ctx, serviceConfig, targetResource, revisionName, &containerAppOptions, progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(serviceConfig.ContainerApp.CustomDomains) > 0 {
+		progress.SetProgress(NewServiceProgress("Binding custom domains"))
+		if err := at.bindCustomDomains(ctx, serviceConfig, targetResource, &containerAppOptions); err != nil {
+			return nil, err
+		}
+	}
+
 	progress.SetProgress(NewServiceProgress("Fetching endpoints for container app service"))
 	endpoints, err := at.Endpoints(ctx, serviceConfig, targetResource)
 	if err != nil {
@@ -150,6 +237,60 @@ func (at *containerAppTarget) Endpoints(
 	}
 }
 
+// Streams the console logs of the replicas of the latest ready revision of the container app
+// The logs of each replica and container are multiplexed with a '[<replica>/<container>]' prefix.
+func (at *containerAppTarget) StreamLogs(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options ServiceLogsOptions,
+) error {
+	if err := at.validateTargetResource(targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	if options.Since > 0 {
+		return errors.New("filtering logs by time is not supported for Container Apps, use the number of recent lines")
+	}
+
+	logSources, err := at.containerAppService.ListLogSources(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		options.Container,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(logSources) == 0 && options.Container != "" {
+		return fmt.Errorf("container app '%s' does not have a container named '%s'",
+			targetResource.ResourceName(), options.Container)
+	}
+
+	if len(logSources) == 0 {
+		return fmt.Errorf("container app '%s' does not have any running replicas", targetResource.ResourceName())
+	}
+
+	logsOptions := &containerapps.ContainerAppLogsOptions{
+		Follow: options.Follow,
+		Tail:   options.Tail,
+	}
+
+	sources := []serviceLogSource{}
+	for _, logSource := range logSources {
+		sources = append(sources, serviceLogSource{
+			name: fmt.Sprintf("%s/%s", logSource.Replica, logSource.Container),
+			stream: func(ctx context.Context, writer io.Writer) error {
+				return at.containerAppService.StreamLogs(ctx, logSource, logsOptions, writer)
+			},
+		})
+	}
+
+	return streamServiceLogs(ctx, sources, options.Writer)
+}
+
 func (at *containerAppTarget) validateTargetResource(
 	targetResource *environment.TargetResource,
 ) error {
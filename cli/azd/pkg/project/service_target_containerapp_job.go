@@ -0,0 +1,295 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The time the deployment waits for the execution of a job to complete when not configured
+const defaultJobExecutionTimeout = 10 * time.Minute
+
+// The Azure Container Apps Jobs options of a service
+type ContainerAppJobOptions struct {
+	// When enabled, an execution of the job is started once the job is updated with the new image,
+	// ex) to run a database migration as part of the deployment
+	Execute bool `yaml:"execute,omitempty"`
+	// When enabled, the deployment waits for the started execution to complete and fails when the execution fails
+	Wait bool `yaml:"wait,omitempty"`
+	// How long the deployment waits for the execution to complete, ex) 30m
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// validateContainerAppJobOptions returns an error when the job options of the service are invalid
+func validateContainerAppJobOptions(serviceConfig *ServiceConfig) error {
+	options := serviceConfig.Job
+	if options.Wait && !options.Execute {
+		return fmt.Errorf("service '%s' waits for the job execution but does not execute the job", serviceConfig.Name)
+	}
+
+	if options.Timeout < 0 {
+		return fmt.Errorf("the job timeout of service '%s' must not be negative", serviceConfig.Name)
+	}
+
+	return nil
+}
+
+type containerAppJobTarget struct {
+	env                    *environment.Environment
+	containerHelper        *ContainerHelper
+	containerAppJobService containerapps.ContainerAppJobService
+}
+
+// NewContainerAppJobTarget creates the service target for Azure Container Apps Jobs.
+//
+// Jobs run to completion instead of serving traffic, so they don't expose any endpoints. Deploying the service updates
+// the image of the job, and optionally starts an execution of the job.
+func NewContainerAppJobTarget(
+	env *environment.Environment,
+	containerHelper *ContainerHelper,
+	containerAppJobService containerapps.ContainerAppJobService,
+) ServiceTarget {
+	return &containerAppJobTarget{
+		env:                    env,
+		containerHelper:        containerHelper,
+		containerAppJobService: containerAppJobService,
+	}
+}
+
+// Gets the required external tools
+func (jt *containerAppJobTarget) RequiredExternalTools(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) []tools.ExternalTool {
+	return jt.containerHelper.RequiredExternalTools(ctx, serviceConfig)
+}
+
+// Initializes the Container Apps Job target
+func (jt *containerAppJobTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Prepares and tags the container image from the build output based on the specified service configuration
+func (jt *containerAppJobTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	return packageOutput, nil
+}
+
+// Deploys the service container image to ACR, updates the job with the image and optionally starts an execution
+func (jt *containerAppJobTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := jt.validateTargetResource(targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	if err := validateContainerAppJobOptions(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	// Login, tag & push container image to ACR
+	_, err := jt.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	containerAppOptions := containerapps.ContainerAppOptions{
+		ApiVersion: serviceConfig.ApiVersion,
+	}
+
+	imageName := jt.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+
+	// Images of registries other than ACR are pulled with the registry credentials stored in the job
+	registryCredentials, err := jt.containerHelper.externalRegistryCredentials(
+		ctx, serviceConfig, targetResource.SubscriptionId(), imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	if registryCredentials != nil {
+		containerAppOptions.Registry = &containerapps.ContainerAppRegistry{
+			Server:   registryCredentials.LoginServer,
+			Username: registryCredentials.Username,
+			Password: registryCredentials.Password,
+		}
+	}
+
+	// Services imported from a compose file carry the environment variables of the compose service
+	if serviceConfig.Compose != nil {
+		env, err := serviceConfig.Compose.resolveEnvironment(jt.env)
+		if err != nil {
+			return nil, err
+		}
+
+		containerAppOptions.Env = env
+	}
+
+	progress.SetProgress(NewServiceProgress("Updating container app job"))
+	job, err := jt.containerAppJobService.UpdateImage(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		imageName,
+		&containerAppOptions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("updating container app job: %w", err)
+	}
+
+	deployResult := &containerAppJobDeployResult{
+		TriggerType:    job.TriggerType,
+		CronExpression: job.CronExpression,
+	}
+
+	if serviceConfig.Job.Execute {
+		progress.SetProgress(NewServiceProgress("Starting container app job execution"))
+		executionName, err := jt.containerAppJobService.StartExecution(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			&containerAppOptions,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		deployResult.Execution = executionName
+		if serviceConfig.Job.Wait {
+			execution, err := jt.waitForExecution(ctx, serviceConfig, targetResource, executionName, progress)
+			if err != nil {
+				return nil, err
+			}
+
+			deployResult.Status = execution.Status
+		}
+	}
+
+	return &ServiceDeployResult{
+		Package: packageOutput,
+		TargetResourceId: azure.ContainerAppJobRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:      ContainerAppJobTarget,
+		Endpoints: []string{},
+		Details:   deployResult,
+	}, nil
+}
+
+// waitForExecution waits for the execution of the job to complete within the configured timeout
+func (jt *containerAppJobTarget) waitForExecution(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	executionName string,
+	progress *async.Progress[ServiceProgress],
+) (*containerapps.ContainerAppJobExecution, error) {
+	timeout := serviceConfig.Job.Timeout
+	if timeout == 0 {
+		timeout = defaultJobExecutionTimeout
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Waiting for execution: %s", executionName)))
+	execution, err := jt.containerAppJobService.WaitForExecution(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		executionName,
+		timeout,
+	)
+	if errors.Is(err, containerapps.ErrJobExecutionFailed) {
+		return nil, fmt.Errorf(
+			"the job of service '%s' was updated but its execution did not succeed: %w", serviceConfig.Name, err)
+	}
+
+	return execution, err
+}
+
+// Jobs don't expose any endpoints
+func (jt *containerAppJobTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	return []string{}, nil
+}
+
+func (jt *containerAppJobTarget) validateTargetResource(
+	targetResource *environment.TargetResource,
+) error {
+	if targetResource.ResourceGroupName() == "" {
+		return fmt.Errorf("missing resource group name: %s", targetResource.ResourceGroupName())
+	}
+
+	if targetResource.ResourceType() != "" {
+		if err := checkResourceType(targetResource, azapi.AzureResourceTypeContainerAppJob); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// containerAppJobDeployResult reports how the executions of a deployed job are triggered and the execution started
+// by the deployment, if any
+type containerAppJobDeployResult struct {
+	// ex) Manual, Schedule or Event
+	TriggerType    string `json:"triggerType"`
+	CronExpression string `json:"cronExpression,omitempty"`
+	// The name of the execution started by the deployment
+	Execution string `json:"execution,omitempty"`
+	// The status of the execution when the deployment waited for it to complete, ex) Succeeded
+	Status string `json:"status,omitempty"`
+}
+
+func (jdr *containerAppJobDeployResult) ToString(currentIndentation string) string {
+	builder := strings.Builder{}
+
+	trigger := jdr.TriggerType
+	if jdr.CronExpression != "" {
+		trigger = fmt.Sprintf("%s (%s)", trigger, jdr.CronExpression)
+	}
+
+	builder.WriteString(fmt.Sprintf("%s- Trigger: %s\n", currentIndentation, output.WithHighLightFormat(trigger)))
+
+	if jdr.Execution != "" {
+		execution := jdr.Execution
+		if jdr.Status != "" {
+			execution = fmt.Sprintf("%s (%s)", execution, jdr.Status)
+		}
+
+		builder.WriteString(fmt.Sprintf("%s- Execution: %s\n", currentIndentation, output.WithHighLightFormat(execution)))
+	}
+
+	return builder.String()
+}
+
+func (jdr *containerAppJobDeployResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*jdr)
+}
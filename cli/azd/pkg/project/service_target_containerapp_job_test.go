@@ -0,0 +1,234 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerAppJob_Deploy(t *testing.T) {
+	tests := []struct {
+		name            string
+		options         ContainerAppJobOptions
+		status          armappcontainers.JobExecutionRunningState
+		expectedDetails *containerAppJobDeployResult
+		expectedErr     string
+	}{
+		{
+			name: "UpdateImage",
+			expectedDetails: &containerAppJobDeployResult{
+				TriggerType: "Manual",
+			},
+		},
+		{
+			name:    "Execute",
+			options: ContainerAppJobOptions{Execute: true},
+			expectedDetails: &containerAppJobDeployResult{
+				TriggerType: "Manual",
+				Execution:   "JOB_NAME-abc123",
+			},
+		},
+		{
+			name:    "Wait",
+			options: ContainerAppJobOptions{Execute: true, Wait: true},
+			status:  armappcontainers.JobExecutionRunningStateSucceeded,
+			expectedDetails: &containerAppJobDeployResult{
+				TriggerType: "Manual",
+				Execution:   "JOB_NAME-abc123",
+				Status:      "Succeeded",
+			},
+		},
+		{
+			name:        "WaitFailed",
+			options:     ContainerAppJobOptions{Execute: true, Wait: true},
+			status:      armappcontainers.JobExecutionRunningStateFailed,
+			expectedErr: "was updated but its execution did not succeed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			setupMocksForDocker(mockContext)
+			setupMocksForAcr(mockContext)
+			updateRequest := setupMocksForContainerAppJob(mockContext, tt.status)
+
+			serviceConfig := createTestServiceConfig(tempDir, ContainerAppJobTarget, ServiceLanguageTypeScript)
+			serviceConfig.Job = tt.options
+			serviceTarget := createContainerAppJobServiceTarget(mockContext, createEnv())
+
+			scope := environment.NewTargetResource(
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"JOB_NAME",
+				string(azapi.AzureResourceTypeContainerAppJob),
+			)
+			packageResult := &ServicePackageResult{
+				PackagePath: "test-app/api-test:azd-deploy-0",
+				Details: &dockerPackageResult{
+					ImageHash:   "IMAGE_HASH",
+					TargetImage: "test-app/api-test:azd-deploy-0",
+				},
+			}
+
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+				},
+			)
+			if tt.expectedErr != "" {
+				require.ErrorIs(t, err, containerapps.ErrJobExecutionFailed)
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, ContainerAppJobTarget, deployResult.Kind)
+			require.Empty(t, deployResult.Endpoints)
+			require.Equal(t, tt.expectedDetails, deployResult.Details)
+
+			var patch *armappcontainers.JobPatchProperties
+			require.NoError(t, json.NewDecoder(updateRequest.Body).Decode(&patch))
+			require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0",
+				*patch.Properties.Template.Containers[0].Image)
+		})
+	}
+}
+
+func Test_ValidateContainerAppJobOptions(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppJobTarget, ServiceLanguageTypeScript)
+	require.NoError(t, validateContainerAppJobOptions(serviceConfig))
+
+	serviceConfig.Job.Wait = true
+	require.ErrorContains(t, validateContainerAppJobOptions(serviceConfig), "does not execute the job")
+
+	serviceConfig.Job.Execute = true
+	require.NoError(t, validateContainerAppJobOptions(serviceConfig))
+}
+
+func Test_ContainerAppJobDeployResult_ToString(t *testing.T) {
+	result := &containerAppJobDeployResult{
+		TriggerType:    "Schedule",
+		CronExpression: "0 * * * *",
+		Execution:      "JOB_NAME-abc123",
+		Status:         "Succeeded",
+	}
+
+	value := result.ToString("  ")
+	require.Contains(t, value, "- Trigger: ")
+	require.Contains(t, value, "Schedule (0 * * * *)")
+	require.Contains(t, value, "JOB_NAME-abc123 (Succeeded)")
+}
+
+func createContainerAppJobServiceTarget(
+	mockContext *mocks.MockContext,
+	env *environment.Environment,
+) ServiceTarget {
+	dockerCli := docker.NewCli(mockContext.CommandRunner)
+	credentialProvider := mockaccount.SubscriptionCredentialProviderFunc(
+		func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		})
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	containerAppJobService := containerapps.NewContainerAppJobService(
+		credentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+	)
+	containerRegistryService := azcli.NewContainerRegistryService(
+		credentialProvider,
+		dockerCli,
+		mockContext.ArmClientOptions,
+		mockContext.CoreClientOptions,
+	)
+	remoteBuildManager := containerregistry.NewRemoteBuildManager(
+		credentialProvider,
+		mockContext.ArmClientOptions,
+	)
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		containerRegistryService,
+		remoteBuildManager,
+		dockerCli,
+		mockContext.Console,
+		cloud.AzurePublic(),
+		nil,
+		nil,
+		nil, nil, nil,
+	)
+
+	return NewContainerAppJobTarget(env, containerHelper, containerAppJobService)
+}
+
+func setupMocksForContainerAppJob(
+	mockContext *mocks.MockContext,
+	status armappcontainers.JobExecutionRunningState,
+) *http.Request {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	jobName := "JOB_NAME"
+
+	job := &armappcontainers.Job{
+		Name: &jobName,
+		Properties: &armappcontainers.JobProperties{
+			Configuration: &armappcontainers.JobConfiguration{
+				TriggerType: to.Ptr(armappcontainers.TriggerTypeManual),
+			},
+			Template: &armappcontainers.JobTemplate{
+				Containers: []*armappcontainers.Container{
+					{Image: to.Ptr("ORIGINAL_IMAGE_NAME")},
+				},
+			},
+		},
+	}
+
+	mockazsdk.MockContainerAppJobGet(mockContext, subscriptionId, resourceGroup, jobName, job)
+	updateRequest := mockazsdk.MockContainerAppJobUpdate(mockContext, subscriptionId, resourceGroup, jobName)
+	mockazsdk.MockContainerAppJobStart(mockContext, subscriptionId, resourceGroup, jobName, "JOB_NAME-abc123")
+	mockazsdk.MockContainerAppJobExecutionGet(
+		mockContext,
+		subscriptionId,
+		resourceGroup,
+		jobName,
+		&armappcontainers.JobExecution{
+			Name: to.Ptr("JOB_NAME-abc123"),
+			Properties: &armappcontainers.JobExecutionProperties{
+				Status: to.Ptr(status),
+			},
+		},
+	)
+	mockazsdk.MockContainerRegistryTokenExchange(mockContext, subscriptionId, subscriptionId, "REFRESH_TOKEN")
+
+	return updateRequest
+}
@@ -5,9 +5,12 @@ package project
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
@@ -18,7 +21,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
 	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -125,6 +130,94 @@ func Test_ContainerApp_Deploy(t *testing.T) {
 	require.Greater(t, len(deployResult.Endpoints), 0)
 	// New env variable is created
 	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
+	require.Equal(t, "test-app/api-test", env.Dotenv()["SERVICE_API_IMAGE_REPOSITORY"])
+	require.Equal(t, "azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_TAG"])
+}
+
+func Test_ContainerApp_Deploy_ExternalRegistry(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+	setupDockerConfig(t, "")
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForDocker(mockContext)
+	updateRequest := setupMocksForContainerApps(mockContext)
+
+	var loginArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker login")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		loginArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Credentials = &RegistryCredentialsOptions{
+		Username: osutil.NewExpandableString("octocat"),
+		Password: osutil.NewExpandableString("${GHCR_TOKEN}"),
+	}
+	env := createEnv()
+	env.DotenvSet(environment.ContainerRegistryEndpointEnvVarName, "ghcr.io")
+	env.DotenvSet("GHCR_TOKEN", "GHCR_TOKEN_VALUE")
+
+	serviceTarget := createContainerAppServiceTarget(mockContext, env)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(azapi.AzureResourceTypeContainerApp),
+	)
+	packageResult := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash:   "IMAGE_HASH",
+			TargetImage: "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	_, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "ghcr.io/test-app/api-test:azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
+
+	// The image is pushed with the configured credentials
+	require.Equal(t, []string{"login", "--username", "octocat", "--password-stdin", "ghcr.io"}, loginArgs.Args)
+
+	// The container app pulls the image with the configured credentials
+	var updatedContainerApp *armappcontainers.ContainerApp
+	err = json.NewDecoder(updateRequest.Body).Decode(&updatedContainerApp)
+	require.NoError(t, err)
+
+	registries := updatedContainerApp.Properties.Configuration.Registries
+	require.Len(t, registries, 1)
+	require.Equal(t, "ghcr.io", *registries[0].Server)
+	require.Equal(t, "octocat", *registries[0].Username)
+	require.Equal(t, "registry-ghcr-io", *registries[0].PasswordSecretRef)
+}
+
+func Test_ContainerApp_StreamLogs_Since(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceConfig := createTestServiceConfig(t.TempDir(), ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceTarget := createContainerAppServiceTarget(mockContext, createEnv())
+
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(azapi.AzureResourceTypeContainerApp),
+	)
+
+	// The Container Apps log stream only supports returning a number of recent lines
+	err := serviceTarget.(ServiceTargetLogStreamer).StreamLogs(
+		*mockContext.Context,
+		serviceConfig,
+		scope,
+		ServiceLogsOptions{Since: time.Hour},
+	)
+	require.ErrorContains(t, err, "filtering logs by time is not supported")
 }
 
 func createContainerAppServiceTarget(
@@ -165,6 +258,9 @@ func createContainerAppServiceTarget(
 		dockerCli,
 		mockContext.Console,
 		cloud.AzurePublic(),
+		nil,
+		nil,
+		nil, nil, nil,
 	)
 	deploymentService := mockazcli.NewStandardDeploymentsFromMockContext(mockContext)
 	resourceService := azapi.NewResourceService(credentialProvider, mockContext.ArmClientOptions)
@@ -186,7 +282,7 @@ func setupMocksForContainerAppTarget(mockContext *mocks.MockContext) {
 	setupMocksForContainerApps(mockContext)
 }
 
-func setupMocksForContainerApps(mockContext *mocks.MockContext) {
+func setupMocksForContainerApps(mockContext *mocks.MockContext) *http.Request {
 	subscriptionId := "SUBSCRIPTION_ID"
 	location := "eastus2"
 	resourceGroup := "RESOURCE_GROUP"
@@ -201,6 +297,11 @@ func setupMocksForContainerApps(mockContext *mocks.MockContext) {
 		Name:     &appName,
 		Properties: &armappcontainers.ContainerAppProperties{
 			LatestRevisionName: &originalRevisionName,
+			ManagedEnvironmentID: to.Ptr(fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/managedEnvironments/ENVIRONMENT",
+				subscriptionId,
+				resourceGroup,
+			)),
 			Configuration: &armappcontainers.Configuration{
 				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeSingle),
 				Secrets: []*armappcontainers.Secret{
@@ -249,6 +350,8 @@ func setupMocksForContainerApps(mockContext *mocks.MockContext) {
 		revision,
 	)
 	mockazsdk.MockContainerAppSecretsList(mockContext, subscriptionId, resourceGroup, appName, secrets)
-	mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	updateRequest := mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, containerApp)
 	mockazsdk.MockContainerRegistryTokenExchange(mockContext, subscriptionId, subscriptionId, "REFRESH_TOKEN")
+
+	return updateRequest
 }
@@ -139,7 +139,11 @@ func (at *dotnetContainerAppTarget) Deploy(
 			return nil, err
 		}
 
-		remoteImageName = res.Details.(*dockerDeployResult).RemoteImageTag
+		deployResult := res.Details.(*dockerDeployResult)
+		remoteImageName = deployResult.RemoteImageTag
+		if deployResult.PinnedImage != "" {
+			remoteImageName = deployResult.PinnedImage
+		}
 	} else if serviceConfig.DotNetContainerApp.ContainerImage != "" {
 		remoteImageName = serviceConfig.DotNetContainerApp.ContainerImage
 	} else {
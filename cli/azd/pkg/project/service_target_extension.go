@@ -0,0 +1,398 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	azdexec "github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The prefix of the hosts implemented by extensions, ex) ext.internal-paas
+const ExtensionTargetPrefix = "ext."
+
+// The prefix of the executables implementing extension hosts, ex) azd-target-internal-paas
+const extensionTargetExecutablePrefix = "azd-target-"
+
+var extensionTargetNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// IsExtensionTarget returns true when the host is implemented by an extension rather than by azd
+func (stk ServiceTargetKind) IsExtensionTarget() bool {
+	return strings.HasPrefix(string(stk), ExtensionTargetPrefix)
+}
+
+// parseExtensionTarget validates the name of a host implemented by an extension, ex) ext.internal-paas
+func parseExtensionTarget(kind ServiceTargetKind) (ServiceTargetKind, error) {
+	name := strings.TrimPrefix(string(kind), ExtensionTargetPrefix)
+	if !extensionTargetNameRegexp.MatchString(name) {
+		return ServiceTargetKind(""), fmt.Errorf(
+			"unsupported host '%s', the name of an extension host can only contain lowercase letters, digits and dashes",
+			kind,
+		)
+	}
+
+	return kind, nil
+}
+
+// extensionTarget deploys services to a host implemented by an executable, so in-house hosts can be used like
+// the hosts of azd. For a host named ext.<name>, azd runs the executable azd-target-<name> found in the 'targets'
+// directory of the azd configuration directory, ex) ~/.azd/targets, or else on the PATH. The executable is run with
+// the operation as its argument: package, deploy or endpoints.
+//
+// The request is written as JSON to the standard input of the executable, see extensionTargetRequest, and the
+// response is read as JSON from its standard output, see extensionTargetResponse. The lines written to the standard
+// error are reported as the progress of the operation. A non-zero exit code fails the operation.
+// The protocol is described in [../../docs/extension-hosts.md].
+type extensionTarget struct {
+	host          ServiceTargetKind
+	env           *environment.Environment
+	commandRunner azdexec.CommandRunner
+}
+
+// NewExtensionTarget creates a service target for a host implemented by an extension
+func NewExtensionTarget(
+	host ServiceTargetKind,
+	env *environment.Environment,
+	commandRunner azdexec.CommandRunner,
+) ServiceTarget {
+	return &extensionTarget{
+		host:          host,
+		env:           env,
+		commandRunner: commandRunner,
+	}
+}
+
+// extensionTargetRequest is the request written to the standard input of the executable of an extension host
+type extensionTargetRequest struct {
+	Service     extensionTargetService     `json:"service"`
+	Environment extensionTargetEnvironment `json:"environment"`
+	// The package of the service, set for the deploy operation and for the package operation when the language of
+	// the service produced a package, ex) the path of a zip archive or the name of a container image
+	Package *extensionTargetPackage `json:"package,omitempty"`
+	// The resource the service is deployed to, set for the deploy and endpoints operations
+	Target *extensionTargetResource `json:"target,omitempty"`
+}
+
+type extensionTargetService struct {
+	Name     string `json:"name"`
+	Host     string `json:"host"`
+	Language string `json:"language"`
+	// The absolute path of the service
+	Path string `json:"path"`
+	// The custom configuration of the service, the 'config' of the service in azure.yaml
+	Config map[string]any `json:"config,omitempty"`
+}
+
+type extensionTargetEnvironment struct {
+	Name string `json:"name"`
+	// The values of the environment, ex) the outputs of the infrastructure
+	Values map[string]string `json:"values"`
+}
+
+type extensionTargetPackage struct {
+	Path string `json:"path"`
+}
+
+// extensionTargetResource is the resource a service is deployed to. The resource is not looked up in Azure, the
+// names are the 'resourceGroup' and 'resourceName' of the service with environment variables substituted
+type extensionTargetResource struct {
+	SubscriptionId string `json:"subscriptionId"`
+	ResourceGroup  string `json:"resourceGroup"`
+	ResourceName   string `json:"resourceName"`
+}
+
+// extensionTargetResponse is the response read from the standard output of the executable of an extension host
+type extensionTargetResponse struct {
+	// The package of the service, returned by the package operation. The package of the language of the service is
+	// deployed when empty
+	PackagePath string `json:"packagePath,omitempty"`
+	// The endpoints of the service, returned by the deploy and endpoints operations
+	Endpoints []string `json:"endpoints,omitempty"`
+	// The values set in the environment, returned by the deploy operation, ex) SERVICE_API_URL
+	Environment map[string]string `json:"environment,omitempty"`
+	// The details of the deployment shown to the user, returned by the deploy operation
+	Details any `json:"details,omitempty"`
+}
+
+// Initialize is a no-op, extension hosts don't participate in the lifecycle events of the service
+func (t *extensionTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+func (t *extensionTarget) RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
+	return []tools.ExternalTool{&extensionTargetTool{host: t.host}}
+}
+
+// Package runs the package operation of the extension, ex) to build an archive in the format of the host
+func (t *extensionTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	request := t.request(serviceConfig)
+	if packageOutput != nil && packageOutput.PackagePath != "" {
+		request.Package = &extensionTargetPackage{Path: packageOutput.PackagePath}
+	}
+
+	response, err := t.run(ctx, "package", request, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.PackagePath == "" {
+		return packageOutput, nil
+	}
+
+	packageResult := &ServicePackageResult{PackagePath: response.PackagePath}
+	if packageOutput != nil {
+		packageResult.Build = packageOutput.Build
+	}
+
+	return packageResult, nil
+}
+
+// Deploy runs the deploy operation of the extension and sets the values it returns in the environment
+func (t *extensionTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	request := t.request(serviceConfig)
+	request.Package = &extensionTargetPackage{Path: packageOutput.PackagePath}
+	request.Target = extensionResource(targetResource)
+
+	response, err := t.run(ctx, "deploy", request, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range response.Environment {
+		t.env.DotenvSet(key, value)
+	}
+
+	return &ServiceDeployResult{
+		Package:   packageOutput,
+		Kind:      t.host,
+		Endpoints: response.Endpoints,
+		Details:   response.Details,
+	}, nil
+}
+
+// Endpoints runs the endpoints operation of the extension
+func (t *extensionTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	request := t.request(serviceConfig)
+	request.Target = extensionResource(targetResource)
+
+	response, err := t.run(ctx, "endpoints", request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Endpoints, nil
+}
+
+// request returns the request of an operation of the service, with the values of the environment
+func (t *extensionTarget) request(serviceConfig *ServiceConfig) *extensionTargetRequest {
+	return &extensionTargetRequest{
+		Service: extensionTargetService{
+			Name:     serviceConfig.Name,
+			Host:     string(serviceConfig.Host),
+			Language: string(serviceConfig.Language),
+			Path:     serviceConfig.Path(),
+			Config:   serviceConfig.Config,
+		},
+		Environment: extensionTargetEnvironment{
+			Name:   t.env.Name(),
+			Values: t.env.Dotenv(),
+		},
+	}
+}
+
+// run runs the operation of the extension, reporting the lines written to the standard error as progress
+func (t *extensionTarget) run(
+	ctx context.Context,
+	operation string,
+	request *extensionTargetRequest,
+	progress *async.Progress[ServiceProgress],
+) (*extensionTargetResponse, error) {
+	executable, err := findExtensionTarget(t.host)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling %s request: %w", operation, err)
+	}
+
+	stderr := &progressLineWriter{progress: progress}
+	runArgs := azdexec.NewRunArgs(executable, operation).
+		WithCwd(request.Service.Path).
+		WithStdIn(bytes.NewReader(input)).
+		WithStdErr(stderr)
+
+	result, err := t.commandRunner.Run(ctx, runArgs)
+	stderr.Flush()
+	if err != nil {
+		return nil, fmt.Errorf("running %s of host '%s': %w", operation, t.host, err)
+	}
+
+	response := &extensionTargetResponse{}
+	if strings.TrimSpace(result.Stdout) == "" {
+		return response, nil
+	}
+
+	if err := json.Unmarshal([]byte(result.Stdout), response); err != nil {
+		return nil, fmt.Errorf("parsing the %s response of host '%s': %w", operation, t.host, err)
+	}
+
+	return response, nil
+}
+
+// extensionResource returns the resource the service is deployed to as sent to the extension
+func extensionResource(targetResource *environment.TargetResource) *extensionTargetResource {
+	if targetResource == nil {
+		return nil
+	}
+
+	return &extensionTargetResource{
+		SubscriptionId: targetResource.SubscriptionId(),
+		ResourceGroup:  targetResource.ResourceGroupName(),
+		ResourceName:   targetResource.ResourceName(),
+	}
+}
+
+// getExtensionTargetResource returns the resource a service deployed to an extension host is deployed to, the
+// resource is resolved by the extension rather than looked up in Azure
+func getExtensionTargetResource(
+	serviceConfig *ServiceConfig,
+	env *environment.Environment,
+) (*environment.TargetResource, error) {
+	resourceGroupTemplate := serviceConfig.ResourceGroupName
+	if resourceGroupTemplate.Empty() {
+		resourceGroupTemplate = serviceConfig.Project.ResourceGroupName
+	}
+
+	resourceGroupName, err := resourceGroupTemplate.Envsubst(env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("substituting environment variables in resourceGroup: %w", err)
+	}
+
+	resourceName, err := serviceConfig.ResourceName.Envsubst(env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("substituting environment variables in resourceName: %w", err)
+	}
+
+	if resourceName == "" {
+		resourceName = serviceConfig.Name
+	}
+
+	return environment.NewTargetResource(env.GetSubscriptionId(), resourceGroupName, resourceName, ""), nil
+}
+
+// findExtensionTarget returns the path of the executable implementing the extension host, in the 'targets'
+// directory of the azd configuration directory or else on the PATH
+func findExtensionTarget(host ServiceTargetKind) (string, error) {
+	executable := extensionTargetExecutablePrefix + strings.TrimPrefix(string(host), ExtensionTargetPrefix)
+	if runtime.GOOS == "windows" {
+		executable += ".exe"
+	}
+
+	if configDir, err := config.GetUserConfigDir(); err == nil {
+		path := filepath.Join(configDir, "targets", executable)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	path, err := exec.LookPath(executable)
+	if err != nil {
+		return "", fmt.Errorf(
+			"host '%s' is implemented by '%s', which was not found in the 'targets' directory of the azd "+
+				"configuration directory or on the PATH: %w",
+			host,
+			executable,
+			errExtensionTargetNotFound,
+		)
+	}
+
+	return path, nil
+}
+
+var errExtensionTargetNotFound = errors.New("extension host not installed")
+
+// extensionTargetTool reports the executable of an extension host as a tool required to deploy the service
+type extensionTargetTool struct {
+	host ServiceTargetKind
+}
+
+func (t *extensionTargetTool) CheckInstalled(ctx context.Context) error {
+	_, err := findExtensionTarget(t.host)
+	return err
+}
+
+func (t *extensionTargetTool) InstallUrl() string {
+	return "https://github.com/Azure/azure-dev/blob/main/cli/azd/docs/extension-hosts.md"
+}
+
+func (t *extensionTargetTool) Name() string {
+	return extensionTargetExecutablePrefix + strings.TrimPrefix(string(t.host), ExtensionTargetPrefix)
+}
+
+// progressLineWriter reports each complete line written to it as the progress of a service operation
+type progressLineWriter struct {
+	progress *async.Progress[ServiceProgress]
+	buffer   bytes.Buffer
+}
+
+func (w *progressLineWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+
+	for {
+		index := bytes.IndexByte(w.buffer.Bytes(), '\n')
+		if index < 0 {
+			break
+		}
+
+		w.report(string(w.buffer.Next(index + 1)))
+	}
+
+	return len(p), nil
+}
+
+// Flush reports the remaining incomplete line, if any
+func (w *progressLineWriter) Flush() {
+	if w.buffer.Len() > 0 {
+		w.report(w.buffer.String())
+		w.buffer.Reset()
+	}
+}
+
+func (w *progressLineWriter) report(line string) {
+	line = strings.TrimSpace(line)
+	if line != "" && w.progress != nil {
+		w.progress.SetProgress(NewServiceProgress(line))
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseServiceHost_Extension(t *testing.T) {
+	host, err := parseServiceHost("ext.internal-paas")
+	require.NoError(t, err)
+	require.True(t, host.IsExtensionTarget())
+	require.False(t, AppServiceTarget.IsExtensionTarget())
+
+	_, err = parseServiceHost("ext.Internal_PaaS")
+	require.ErrorContains(t, err, "the name of an extension host can only contain")
+
+	_, err = parseServiceHost("ext.")
+	require.Error(t, err)
+}
+
+func Test_ExtensionTarget_Deploy(t *testing.T) {
+	executable := installExtensionTarget(t, "internal-paas")
+
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.EnvNameEnvVarName: "dev",
+		"AZURE_SUBSCRIPTION_ID":       "SUBSCRIPTION_ID",
+		"PLATFORM_URL":                "https://paas.contoso.com",
+	})
+
+	var request extensionTargetRequest
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == executable && args.Args[0] == "deploy"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		input, err := io.ReadAll(args.StdIn)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(input, &request))
+
+		_, err = args.Stderr.Write([]byte("Uploading package\nStarting api"))
+		require.NoError(t, err)
+
+		return exec.NewRunResult(0, `{
+			"endpoints": ["https://api.paas.contoso.com"],
+			"environment": {"SERVICE_API_URL": "https://api.paas.contoso.com"}
+		}`, ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetKind("ext.internal-paas"), ServiceLanguageJavaScript)
+	serviceConfig.Config = map[string]any{"replicas": 2}
+	serviceTarget := NewExtensionTarget(serviceConfig.Host, env, mockContext.CommandRunner)
+
+	targetResource, err := getExtensionTargetResource(serviceConfig, env)
+	require.NoError(t, err)
+
+	progressMessages := []string{}
+	deployResult, err := async.RunWithProgress(
+		func(progress ServiceProgress) {
+			progressMessages = append(progressMessages, progress.Message)
+		},
+		func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(
+				*mockContext.Context,
+				serviceConfig,
+				&ServicePackageResult{PackagePath: "api.zip"},
+				targetResource,
+				progress,
+			)
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"https://api.paas.contoso.com"}, deployResult.Endpoints)
+	require.Equal(t, "https://api.paas.contoso.com", env.Getenv("SERVICE_API_URL"))
+	require.Equal(t, []string{"Uploading package", "Starting api"}, progressMessages)
+
+	require.Equal(t, "api", request.Service.Name)
+	require.Equal(t, "ext.internal-paas", request.Service.Host)
+	require.Equal(t, serviceConfig.Path(), request.Service.Path)
+	require.Equal(t, map[string]any{"replicas": float64(2)}, request.Service.Config)
+	require.Equal(t, "dev", request.Environment.Name)
+	require.Equal(t, "https://paas.contoso.com", request.Environment.Values["PLATFORM_URL"])
+	require.Equal(t, &extensionTargetPackage{Path: "api.zip"}, request.Package)
+	require.Equal(t, &extensionTargetResource{
+		SubscriptionId: "SUBSCRIPTION_ID",
+		ResourceName:   "api",
+	}, request.Target)
+}
+
+func Test_ExtensionTarget_Package(t *testing.T) {
+	executable := installExtensionTarget(t, "internal-paas")
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", nil)
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetKind("ext.internal-paas"), ServiceLanguageJavaScript)
+	serviceTarget := NewExtensionTarget(serviceConfig.Host, env, mockContext.CommandRunner)
+
+	packageOutput := &ServicePackageResult{PackagePath: "dist"}
+	packagePath := ""
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == executable && args.Args[0] == "package"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		return exec.NewRunResult(0, packagePath, ""), nil
+	})
+
+	// The package of the language of the service is deployed when the extension doesn't return a package
+	packageResult, err := serviceTarget.Package(*mockContext.Context, serviceConfig, packageOutput, nil)
+	require.NoError(t, err)
+	require.Same(t, packageOutput, packageResult)
+
+	packagePath = `{"packagePath": "api.tar.gz"}`
+	packageResult, err = serviceTarget.Package(*mockContext.Context, serviceConfig, packageOutput, nil)
+	require.NoError(t, err)
+	require.Equal(t, "api.tar.gz", packageResult.PackagePath)
+}
+
+func Test_ExtensionTarget_Failed(t *testing.T) {
+	executable := installExtensionTarget(t, "internal-paas")
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", nil)
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetKind("ext.internal-paas"), ServiceLanguageJavaScript)
+	serviceTarget := NewExtensionTarget(serviceConfig.Host, env, mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == executable
+	}).SetError(errors.New("exit code: 1"))
+
+	_, err := serviceTarget.Endpoints(*mockContext.Context, serviceConfig, nil)
+	require.EqualError(t, err, "running endpoints of host 'ext.internal-paas': exit code: 1")
+
+	// The executable of an unknown host is reported as a missing tool
+	serviceTarget = NewExtensionTarget(ServiceTargetKind("ext.unknown"), env, mockContext.CommandRunner)
+	tools := serviceTarget.RequiredExternalTools(*mockContext.Context, serviceConfig)
+	require.Len(t, tools, 1)
+	require.Equal(t, "azd-target-unknown", tools[0].Name())
+	require.ErrorIs(t, tools[0].CheckInstalled(*mockContext.Context), errExtensionTargetNotFound)
+}
+
+// installExtensionTarget installs an executable for the extension host in the 'targets' directory of a temporary
+// azd configuration directory, returning the path of the executable
+func installExtensionTarget(t *testing.T, name string) string {
+	configDir := t.TempDir()
+	t.Setenv("AZD_CONFIG_DIR", configDir)
+	t.Setenv("PATH", "")
+
+	executable := extensionTargetExecutablePrefix + name
+	if runtime.GOOS == "windows" {
+		executable += ".exe"
+	}
+
+	path := filepath.Join(configDir, "targets", executable)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh"), osutil.PermissionExecutableFile))
+
+	return path
+}
@@ -5,10 +5,14 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
@@ -17,21 +21,45 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 )
 
+// The Azure Functions options of a service
+type FunctionAppOptions struct {
+	// The deployment slot the function app is deployed to before it is swapped with the production slot
+	SlotOptions `yaml:",inline"`
+	// The scale settings applied to a function app on a Flex Consumption plan before it is deployed
+	Flex *FunctionAppFlexOptions `yaml:"flex,omitempty"`
+}
+
+// The scale settings of a function app on a Flex Consumption plan
+type FunctionAppFlexOptions struct {
+	// The number of instances always ready per function group or function, ex) http: 2 or function:orders: 1
+	AlwaysReady map[string]int `yaml:"alwaysReady,omitempty"`
+	// The maximum number of instances the app scales out to
+	MaximumInstanceCount int `yaml:"maximumInstanceCount,omitempty"`
+	// The memory of each instance in MB, ex) 2048
+	InstanceMemoryMB int `yaml:"instanceMemoryMB,omitempty"`
+}
+
+// The function groups of the always ready instances of Flex Consumption apps, functions are set as function:<name>
+var flexAlwaysReadyGroups = []string{"http", "blob", "durable"}
+
 // functionAppTarget specifies an Azure Function to deploy to.
 // Implements `project.ServiceTarget`
 type functionAppTarget struct {
-	env *environment.Environment
-	cli azcli.AzCli
+	env         *environment.Environment
+	cli         azcli.AzCli
+	transporter policy.Transporter
 }
 
 // NewFunctionAppTarget creates a new instance of the Function App target
 func NewFunctionAppTarget(
 	env *environment.Environment,
 	azCli azcli.AzCli,
+	transporter policy.Transporter,
 ) ServiceTarget {
 	return &functionAppTarget{
-		env: env,
-		cli: azCli,
+		env:         env,
+		cli:         azCli,
+		transporter: transporter,
 	}
 }
 
@@ -88,28 +116,88 @@ func (f *functionAppTarget) Deploy(
 	defer os.Remove(packageOutput.PackagePath)
 	defer zipFile.Close()
 
-	progress.SetProgress(NewServiceProgress("Uploading deployment package"))
 	remoteBuild := serviceConfig.Language == ServiceLanguageJavaScript ||
 		serviceConfig.Language == ServiceLanguageTypeScript ||
 		serviceConfig.Language == ServiceLanguagePython
-	res, err := f.cli.DeployFunctionAppUsingZipFile(
-		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
-		targetResource.ResourceName(),
-		zipFile,
-		remoteBuild,
-	)
+	slot, err := resolveSlot(serviceConfig, serviceConfig.FunctionApp.SlotOptions, f.env)
 	if err != nil {
 		return nil, err
 	}
 
-	progress.SetProgress(NewServiceProgress("Fetching endpoints for function app"))
-	endpoints, err := f.Endpoints(ctx, serviceConfig, targetResource)
+	if flex := serviceConfig.FunctionApp.Flex; flex != nil {
+		if slot != "" {
+			return nil, fmt.Errorf(
+				"deployment slots are not supported by the Flex Consumption plan, remove the slot of service '%s'",
+				serviceConfig.Name,
+			)
+		}
+
+		scale, err := flexScale(flex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flex settings of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		progress.SetProgress(NewServiceProgress("Updating Flex Consumption scale settings"))
+		err = f.cli.UpdateFunctionAppFlexScale(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			scale,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress("Uploading deployment package"))
+	var res *string
+	if slot != "" {
+		res, err = f.cli.DeployFunctionAppSlotUsingZipFile(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			slot,
+			zipFile,
+			remoteBuild,
+		)
+	} else {
+		res, err = f.cli.DeployFunctionAppUsingZipFile(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			zipFile,
+			remoteBuild,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	var endpoints []string
+	if slot != "" {
+		slotEndpoints, err := deploySlot(
+			ctx, f.transporter, f.webAppSlot(targetResource, slot), serviceConfig.FunctionApp.SlotOptions, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		// The new version is only reachable through the slot until it is swapped manually
+		if serviceConfig.FunctionApp.NoSwap {
+			endpoints = slotEndpoints
+		}
+	}
+
+	if endpoints == nil {
+		progress.SetProgress(NewServiceProgress("Fetching endpoints for function app"))
+		endpoints, err = f.Endpoints(ctx, serviceConfig, targetResource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	sdr := NewServiceDeployResult(
 		azure.WebsiteRID(
 			targetResource.SubscriptionId(),
@@ -150,6 +238,36 @@ func (f *functionAppTarget) Endpoints(
 	}
 }
 
+// webAppSlot returns the deployment slot of the function app
+func (f *functionAppTarget) webAppSlot(targetResource *environment.TargetResource, slot string) webAppSlot {
+	return webAppSlot{
+		name: slot,
+		hostNames: func(ctx context.Context) ([]string, error) {
+			props, err := f.cli.GetFunctionAppSlotProperties(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				slot,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return props.HostNames, nil
+		},
+		swap: func(ctx context.Context) error {
+			return f.cli.SwapFunctionAppSlot(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				slot,
+			)
+		},
+	}
+}
+
 func (f *functionAppTarget) validateTargetResource(
 	targetResource *environment.TargetResource,
 ) error {
@@ -163,3 +281,38 @@ func (f *functionAppTarget) validateTargetResource(
 
 	return nil
 }
+
+// flexScale validates the flex settings of the service and converts them to the scale of the function app
+func flexScale(options *FunctionAppFlexOptions) (*azcli.FunctionAppFlexScale, error) {
+	scale := &azcli.FunctionAppFlexScale{
+		AlwaysReady:          []*azcli.FunctionAppAlwaysReady{},
+		MaximumInstanceCount: options.MaximumInstanceCount,
+		InstanceMemoryMB:     options.InstanceMemoryMB,
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(options.AlwaysReady)) {
+		if !slices.Contains(flexAlwaysReadyGroups, name) &&
+			(!strings.HasPrefix(name, "function:") || name == "function:") {
+			return nil, fmt.Errorf(
+				"always ready '%s' is not a function group (%s) or a function:<name>",
+				name,
+				strings.Join(flexAlwaysReadyGroups, ", "),
+			)
+		}
+
+		if options.AlwaysReady[name] < 0 {
+			return nil, fmt.Errorf("always ready '%s' has a negative instance count", name)
+		}
+
+		scale.AlwaysReady = append(scale.AlwaysReady, &azcli.FunctionAppAlwaysReady{
+			Name:          name,
+			InstanceCount: options.AlwaysReady[name],
+		})
+	}
+
+	if options.MaximumInstanceCount < 0 || options.InstanceMemoryMB < 0 {
+		return nil, errors.New("the maximum instance count and instance memory must not be negative")
+	}
+
+	return scale, nil
+}
@@ -4,11 +4,24 @@
 package project
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,3 +58,324 @@ func TestNewFunctionAppTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_FunctionApp_Deploy_Slot(t *testing.T) {
+	tests := []struct {
+		name              string
+		noSwap            bool
+		healthStatus      int
+		expectedEndpoints []string
+		expectedSwap      bool
+		expectedErr       string
+	}{
+		{
+			name:              "Swap",
+			healthStatus:      http.StatusOK,
+			expectedEndpoints: []string{"https://FUNC_APP_NAME.azurewebsites.net/"},
+			expectedSwap:      true,
+		},
+		{
+			name:              "NoSwap",
+			noSwap:            true,
+			healthStatus:      http.StatusOK,
+			expectedEndpoints: []string{"Slot staging: https://FUNC_APP_NAME-staging.azurewebsites.net/"},
+		},
+		{
+			name:         "Unhealthy",
+			healthStatus: http.StatusServiceUnavailable,
+			expectedErr:  "slot 'staging' is not healthy, the slot was not swapped with production",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			swapped := setupMocksForFunctionAppSlot(mockContext, tt.healthStatus)
+
+			zipFilePath := filepath.Join(t.TempDir(), "package.zip")
+			require.NoError(t, os.WriteFile(zipFilePath, []byte{}, osutil.PermissionFile))
+
+			serviceConfig := createTestServiceConfig("./src/api", AzureFunctionTarget, ServiceLanguageJava)
+			serviceConfig.FunctionApp = FunctionAppOptions{
+				SlotOptions: SlotOptions{
+					Slot:               osutil.NewExpandableString("staging"),
+					HealthCheckPath:    "/api/health",
+					HealthCheckTimeout: time.Millisecond,
+					NoSwap:             tt.noSwap,
+				},
+			}
+
+			azCli := azcli.NewAzCli(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+			serviceTarget := NewFunctionAppTarget(environment.New("test"), azCli, mockContext.HttpClient)
+			scope := environment.NewTargetResource(
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"FUNC_APP_NAME",
+				string(azapi.AzureResourceTypeWebSite),
+			)
+
+			packageResult := &ServicePackageResult{PackagePath: zipFilePath}
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+				},
+			)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				require.False(t, *swapped)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedEndpoints, deployResult.Endpoints)
+			require.Equal(t, tt.expectedSwap, *swapped)
+		})
+	}
+}
+
+func setupMocksForFunctionAppSlot(mockContext *mocks.MockContext, healthStatus int) *bool {
+	sitePath := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/FUNC_APP_NAME"
+	site := func(hostName string, repositoryHost string) armappservice.Site {
+		return armappservice.Site{
+			Properties: &armappservice.SiteProperties{
+				DefaultHostName: to.Ptr(hostName),
+				HostNameSSLStates: []*armappservice.HostNameSSLState{
+					{
+						HostType: to.Ptr(armappservice.HostTypeRepository),
+						Name:     to.Ptr(repositoryHost),
+					},
+				},
+				ServerFarmID: to.Ptr(
+					"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/serverfarms/PLAN"),
+			},
+		}
+	}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, sitePath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WebAppsClientGetResponse{
+			Site: site("FUNC_APP_NAME.azurewebsites.net", "FUNC_APP_NAME.scm.azurewebsites.net"),
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, sitePath+"/slots/staging")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WebAppsClientGetSlotResponse{
+			Site: site("FUNC_APP_NAME-staging.azurewebsites.net", "FUNC_APP_NAME-staging.scm.azurewebsites.net"),
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/serverfarms/PLAN")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.PlansClientGetResponse{
+			Plan: armappservice.Plan{
+				SKU: &armappservice.SKUDescription{Tier: to.Ptr("ElasticPremium")},
+			},
+		})
+	})
+
+	// The package is only deployed to the slot
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			request.URL.Host == "FUNC_APP_NAME-staging.scm.azurewebsites.net" &&
+			strings.Contains(request.URL.Path, "/api/zipdeploy")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response, _ := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+		response.Header.Set("Location", "https://FUNC_APP_NAME-staging.scm.azurewebsites.net/deployments/latest")
+
+		return response, nil
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/deployments/latest")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DeployStatusResponse{
+			DeployStatus: azsdk.DeployStatus{
+				Status:     http.StatusOK,
+				StatusText: "OK",
+				Complete:   true,
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Host == "FUNC_APP_NAME-staging.azurewebsites.net" &&
+			request.URL.Path == "/api/health"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, healthStatus)
+	})
+
+	swapped := false
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, sitePath+"/slotsswap")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		swapped = true
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	return &swapped
+}
+
+func Test_FunctionApp_Deploy_Flex(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		patched := setupMocksForFunctionAppFlex(mockContext)
+
+		serviceConfig := createTestServiceConfig("./src/api", AzureFunctionTarget, ServiceLanguagePython)
+		serviceConfig.FunctionApp.Flex = &FunctionAppFlexOptions{
+			AlwaysReady:          map[string]int{"http": 2, "function:orders": 1},
+			MaximumInstanceCount: 100,
+		}
+
+		deployResult, err := deployFunctionApp(t, mockContext, serviceConfig)
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://FUNC_APP_NAME.azurewebsites.net/"}, deployResult.Endpoints)
+
+		scale := (*patched)["properties"].(map[string]any)["functionAppConfig"].(map[string]any)["scaleAndConcurrency"]
+		require.Equal(t, map[string]any{
+			"alwaysReady": []any{
+				map[string]any{"name": "function:orders", "instanceCount": float64(1)},
+				map[string]any{"name": "http", "instanceCount": float64(2)},
+			},
+			"maximumInstanceCount": float64(100),
+			"instanceMemoryMB":     float64(2048),
+		}, scale)
+	})
+
+	t.Run("Slot", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		patched := setupMocksForFunctionAppFlex(mockContext)
+
+		serviceConfig := createTestServiceConfig("./src/api", AzureFunctionTarget, ServiceLanguagePython)
+		serviceConfig.FunctionApp = FunctionAppOptions{
+			SlotOptions: SlotOptions{Slot: osutil.NewExpandableString("staging")},
+			Flex:        &FunctionAppFlexOptions{MaximumInstanceCount: 100},
+		}
+
+		_, err := deployFunctionApp(t, mockContext, serviceConfig)
+		require.ErrorContains(t, err, "deployment slots are not supported by the Flex Consumption plan")
+		require.Nil(t, *patched)
+	})
+}
+
+func Test_flexScale(t *testing.T) {
+	scale, err := flexScale(&FunctionAppFlexOptions{
+		AlwaysReady:      map[string]int{"http": 2, "durable": 1},
+		InstanceMemoryMB: 4096,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &azcli.FunctionAppFlexScale{
+		AlwaysReady: []*azcli.FunctionAppAlwaysReady{
+			{Name: "durable", InstanceCount: 1},
+			{Name: "http", InstanceCount: 2},
+		},
+		InstanceMemoryMB: 4096,
+	}, scale)
+
+	_, err = flexScale(&FunctionAppFlexOptions{AlwaysReady: map[string]int{"queue": 1}})
+	require.ErrorContains(t, err, "always ready 'queue' is not a function group (http, blob, durable) or a function:<name>")
+
+	_, err = flexScale(&FunctionAppFlexOptions{AlwaysReady: map[string]int{"function:": 1}})
+	require.ErrorContains(t, err, "always ready 'function:' is not a function group")
+
+	_, err = flexScale(&FunctionAppFlexOptions{AlwaysReady: map[string]int{"http": -1}})
+	require.ErrorContains(t, err, "always ready 'http' has a negative instance count")
+}
+
+func deployFunctionApp(
+	t *testing.T,
+	mockContext *mocks.MockContext,
+	serviceConfig *ServiceConfig,
+) (*ServiceDeployResult, error) {
+	zipFilePath := filepath.Join(t.TempDir(), "package.zip")
+	require.NoError(t, os.WriteFile(zipFilePath, []byte{}, osutil.PermissionFile))
+
+	azCli := azcli.NewAzCli(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+	serviceTarget := NewFunctionAppTarget(environment.New("test"), azCli, mockContext.HttpClient)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"FUNC_APP_NAME",
+		string(azapi.AzureResourceTypeWebSite),
+	)
+
+	packageResult := &ServicePackageResult{PackagePath: zipFilePath}
+	return logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+		},
+	)
+}
+
+// setupMocksForFunctionAppFlex mocks a function app on a Flex Consumption plan and returns the body of the update of
+// its function app configuration
+func setupMocksForFunctionAppFlex(mockContext *mocks.MockContext) *map[string]any {
+	sitePath := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/FUNC_APP_NAME"
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == sitePath
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"properties": map[string]any{
+				"defaultHostName": "FUNC_APP_NAME.azurewebsites.net",
+				"hostNameSslStates": []map[string]any{
+					{"hostType": "Repository", "name": "FUNC_APP_NAME.scm.azurewebsites.net"},
+				},
+				"serverFarmId": "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+					"Microsoft.Web/serverfarms/PLAN",
+				"functionAppConfig": map[string]any{
+					"scaleAndConcurrency": map[string]any{
+						"maximumInstanceCount": 40,
+						"instanceMemoryMB":     2048,
+					},
+				},
+			},
+		})
+	})
+
+	var patched map[string]any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPatch && request.URL.Path == sitePath
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(request.Body).Decode(&patched); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/serverfarms/PLAN")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.PlansClientGetResponse{
+			Plan: armappservice.Plan{
+				SKU: &armappservice.SKUDescription{Name: to.Ptr("FC1"), Tier: to.Ptr("FlexConsumption")},
+			},
+		})
+	})
+
+	// Flex Consumption apps are deployed through the one deploy api
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			request.URL.Host == "FUNC_APP_NAME.scm.azurewebsites.net" &&
+			request.URL.Path == "/api/publish"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, "DEPLOYMENT_ID")
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == "/api/deployments/DEPLOYMENT_ID"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.PublishResponse{
+			Id:         "DEPLOYMENT_ID",
+			Status:     azsdk.PublishStatusSuccess,
+			StatusText: "Succeeded",
+			Complete:   true,
+		})
+	})
+
+	return &patched
+}
@@ -0,0 +1,524 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/sethvargo/go-retry"
+)
+
+const (
+	// The deployment manifest template looked up in the service directory when the template is not configured
+	defaultIotEdgeTemplate = "deployment.template.json"
+	// The priority of the at-scale deployments when not configured
+	defaultIotEdgeDeploymentPriority = 10
+	// The time the deployment waits for the modules of the device to run when not configured
+	defaultIotEdgeTimeout = 5 * time.Minute
+	// The label identifying the at-scale deployments created for a service
+	iotEdgeServiceLabel = "azd-service"
+	// The number of at-scale deployments of a service kept, including the deployment just created
+	iotEdgeDeploymentsKept = 2
+)
+
+// The interval the status of the modules of the device is polled at while waiting for the modules to run
+var iotEdgeStatusPollInterval = 10 * time.Second
+
+// Matches the ${NAME} placeholders of a deployment manifest template. Unlike envsubst, the bare $NAME form is not
+// matched, as the manifest uses it for the $edgeAgent and $edgeHub modules.
+var iotEdgePlaceholderRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.]*)\}`)
+
+// The IoT Edge options of a service
+// The deployment manifest is applied to a single device when deviceId is set, or as an at-scale deployment to all the
+// devices matching the target condition of the deployment.
+type IotEdgeOptions struct {
+	// The path of the deployment manifest template, relative to the service directory.
+	// Defaults to deployment.template.json
+	Template string `yaml:"template,omitempty"`
+	// The id of the IoT Edge device the deployment manifest is applied to
+	DeviceId osutil.ExpandableString `yaml:"deviceId,omitempty"`
+	// The at-scale deployment the deployment manifest is applied with
+	Deployment *IotEdgeDeploymentOptions `yaml:"deployment,omitempty"`
+	// How long the deployment waits for the modules of the device to run, ex) 10m
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// The at-scale deployment options of an IoT Edge service
+type IotEdgeDeploymentOptions struct {
+	// The prefix of the ids of the deployments created on every deploy. Defaults to the name of the service
+	Name string `yaml:"name,omitempty"`
+	// The devices the deployment applies to, ex) tags.environment='dev'
+	TargetCondition osutil.ExpandableString `yaml:"targetCondition,omitempty"`
+	// The priority of the deployment. Defaults to 10
+	Priority int `yaml:"priority,omitempty"`
+}
+
+// validateIotEdgeOptions returns an error when the IoT Edge options of the service are invalid
+func validateIotEdgeOptions(serviceConfig *ServiceConfig) error {
+	options := serviceConfig.IotEdge
+	if options.DeviceId.Empty() == (options.Deployment == nil) {
+		return fmt.Errorf(
+			"set exactly one of 'iotEdge.deviceId' and 'iotEdge.deployment' of service '%s'", serviceConfig.Name)
+	}
+
+	if options.Deployment != nil && options.Deployment.TargetCondition.Empty() {
+		return fmt.Errorf("set 'iotEdge.deployment.targetCondition' of service '%s'", serviceConfig.Name)
+	}
+
+	if options.Timeout < 0 {
+		return fmt.Errorf("the iot edge timeout of service '%s' must not be negative", serviceConfig.Name)
+	}
+
+	return nil
+}
+
+type iotEdgeTarget struct {
+	env             *environment.Environment
+	containerHelper *ContainerHelper
+	iotHubService   azcli.IotHubService
+}
+
+// NewIotEdgeTarget creates the service target for IoT Edge modules.
+//
+// Deploying the service pushes the image of the module and applies the deployment manifest rendered from the template
+// of the service to the IoT Edge devices through the IoT hub.
+func NewIotEdgeTarget(
+	env *environment.Environment,
+	containerHelper *ContainerHelper,
+	iotHubService azcli.IotHubService,
+) ServiceTarget {
+	return &iotEdgeTarget{
+		env:             env,
+		containerHelper: containerHelper,
+		iotHubService:   iotHubService,
+	}
+}
+
+// Gets the required external tools
+func (t *iotEdgeTarget) RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
+	return t.containerHelper.RequiredExternalTools(ctx, serviceConfig)
+}
+
+// Initializes the IoT Edge target
+func (t *iotEdgeTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Prepares and tags the container image of the module from the build output
+func (t *iotEdgeTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	return packageOutput, nil
+}
+
+// Pushes the image of the module and applies the deployment manifest to the device or as an at-scale deployment
+func (t *iotEdgeTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := checkResourceType(targetResource, azapi.AzureResourceTypeIotHub); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	if err := validateIotEdgeOptions(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	// Login, tag & push container image to ACR
+	_, err := t.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	imageName := t.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+
+	progress.SetProgress(NewServiceProgress("Rendering deployment manifest"))
+	modulesContent, err := t.modulesContent(serviceConfig, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	hostName, err := t.iotHubService.GetIotHubHostName(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var deployResult *iotEdgeDeployResult
+	if serviceConfig.IotEdge.Deployment != nil {
+		deployResult, err = t.deployAtScale(ctx, serviceConfig, targetResource, hostName, modulesContent, progress)
+	} else {
+		deployResult, err = t.deployToDevice(ctx, serviceConfig, targetResource, hostName, modulesContent, progress)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceDeployResult{
+		Package: packageOutput,
+		TargetResourceId: fmt.Sprintf(
+			"%s/providers/%s/%s",
+			azure.ResourceGroupRID(targetResource.SubscriptionId(), targetResource.ResourceGroupName()),
+			azapi.AzureResourceTypeIotHub,
+			targetResource.ResourceName(),
+		),
+		Kind:      IotEdgeTarget,
+		Endpoints: []string{},
+		Details:   deployResult,
+	}, nil
+}
+
+// IoT Edge modules don't expose any endpoints through the IoT hub
+func (t *iotEdgeTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	return []string{}, nil
+}
+
+// modulesContent renders the deployment manifest template of the service and returns its modules content
+func (t *iotEdgeTarget) modulesContent(serviceConfig *ServiceConfig, imageName string) (map[string]any, error) {
+	templatePath := serviceConfig.IotEdge.Template
+	if templatePath == "" {
+		templatePath = defaultIotEdgeTemplate
+	}
+
+	if !filepath.IsAbs(templatePath) {
+		templatePath = filepath.Join(serviceConfig.Path(), templatePath)
+	}
+
+	template, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading deployment manifest template of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	// The image pushed by the deployment is referenced as ${MODULES.<service name>} in the template
+	moduleImageKey := fmt.Sprintf("MODULES.%s", serviceConfig.Name)
+	manifest, err := renderIotEdgeTemplate(template, func(name string) (string, bool) {
+		if name == moduleImageKey {
+			return imageName, true
+		}
+
+		return t.env.LookupEnv(name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rendering deployment manifest '%s': %w", templatePath, err)
+	}
+
+	var deployment struct {
+		ModulesContent map[string]any `json:"modulesContent"`
+	}
+
+	if err := json.Unmarshal(manifest, &deployment); err != nil {
+		return nil, fmt.Errorf("parsing deployment manifest '%s': %w", templatePath, err)
+	}
+
+	if _, has := deployment.ModulesContent["$edgeAgent"]; !has {
+		return nil, fmt.Errorf("deployment manifest '%s' has no modules content for $edgeAgent", templatePath)
+	}
+
+	return deployment.ModulesContent, nil
+}
+
+// deployToDevice applies the modules content to the device and waits for the modules of the device to run the
+// images of the deployment manifest
+func (t *iotEdgeTarget) deployToDevice(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	hostName string,
+	modulesContent map[string]any,
+	progress *async.Progress[ServiceProgress],
+) (*iotEdgeDeployResult, error) {
+	deviceId, err := serviceConfig.IotEdge.DeviceId.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the device id of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Applying deployment manifest to device %s", deviceId)))
+	err = t.iotHubService.ApplyIotEdgeDeviceContent(
+		ctx, targetResource.SubscriptionId(), hostName, deviceId, modulesContent)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := serviceConfig.IotEdge.Timeout
+	if timeout == 0 {
+		timeout = defaultIotEdgeTimeout
+	}
+
+	desired := desiredIotEdgeModuleImages(modulesContent)
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Waiting for modules of device %s", deviceId)))
+
+	var statuses []*azcli.IotEdgeModuleStatus
+	err = retry.Do(
+		ctx,
+		retry.WithMaxDuration(timeout, retry.NewConstant(iotEdgeStatusPollInterval)),
+		func(ctx context.Context) error {
+			statuses, err = t.iotHubService.GetIotEdgeModuleStatus(
+				ctx, targetResource.SubscriptionId(), hostName, deviceId)
+			if err != nil {
+				return err
+			}
+
+			if pending := pendingIotEdgeModules(desired, statuses); len(pending) > 0 {
+				return retry.RetryableError(fmt.Errorf("modules not running: %s", strings.Join(pending, ", ")))
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"the deployment manifest of service '%s' was applied to device '%s' but its modules did not run within %s: %w",
+			serviceConfig.Name,
+			deviceId,
+			timeout,
+			err,
+		)
+	}
+
+	modules := map[string]string{}
+	for _, status := range statuses {
+		modules[status.Name] = status.RuntimeStatus
+	}
+
+	return &iotEdgeDeployResult{
+		DeviceId: deviceId,
+		Modules:  modules,
+	}, nil
+}
+
+// deployAtScale creates a new at-scale deployment with the modules content, as the content of a deployment can't be
+// updated, and deletes the older deployments of the service
+func (t *iotEdgeTarget) deployAtScale(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	hostName string,
+	modulesContent map[string]any,
+	progress *async.Progress[ServiceProgress],
+) (*iotEdgeDeployResult, error) {
+	options := serviceConfig.IotEdge.Deployment
+	targetCondition, err := options.TargetCondition.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the target condition of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	name := options.Name
+	if name == "" {
+		name = serviceConfig.Name
+	}
+
+	priority := options.Priority
+	if priority == 0 {
+		priority = defaultIotEdgeDeploymentPriority
+	}
+
+	// Deployment ids are lower case, and the deployment created last wins over the older ones of the same priority
+	deploymentId := fmt.Sprintf("%s-%s", strings.ToLower(name), time.Now().UTC().Format("20060102-150405"))
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Creating deployment %s", deploymentId)))
+	_, err = t.iotHubService.CreateIotEdgeDeployment(ctx, targetResource.SubscriptionId(), hostName,
+		&azcli.IotEdgeDeployment{
+			Id:              deploymentId,
+			Labels:          map[string]string{iotEdgeServiceLabel: serviceConfig.Name},
+			Content:         &azcli.IotEdgeDeploymentContent{ModulesContent: modulesContent},
+			TargetCondition: targetCondition,
+			Priority:        priority,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Removing previous deployments"))
+	if err := t.pruneDeployments(ctx, serviceConfig, targetResource, hostName, deploymentId); err != nil {
+		log.Printf("failed removing previous deployments of service '%s': %v", serviceConfig.Name, err)
+	}
+
+	return &iotEdgeDeployResult{
+		Deployment:      deploymentId,
+		TargetCondition: targetCondition,
+	}, nil
+}
+
+// pruneDeployments deletes the at-scale deployments of the service but the most recent ones
+func (t *iotEdgeTarget) pruneDeployments(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	hostName string,
+	deploymentId string,
+) error {
+	deployments, err := t.iotHubService.ListIotEdgeDeployments(ctx, targetResource.SubscriptionId(), hostName)
+	if err != nil {
+		return err
+	}
+
+	previous := []*azcli.IotEdgeDeployment{}
+	for _, deployment := range deployments {
+		if deployment.Id != deploymentId && deployment.Labels[iotEdgeServiceLabel] == serviceConfig.Name {
+			previous = append(previous, deployment)
+		}
+	}
+
+	// The creation times are ISO 8601 timestamps, which sort lexically
+	slices.SortFunc(previous, func(a, b *azcli.IotEdgeDeployment) int {
+		return strings.Compare(b.CreatedTimeUtc, a.CreatedTimeUtc)
+	})
+
+	var errs []error
+	for i, deployment := range previous {
+		if i < iotEdgeDeploymentsKept-1 {
+			continue
+		}
+
+		err := t.iotHubService.DeleteIotEdgeDeployment(ctx, targetResource.SubscriptionId(), hostName, deployment.Id)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// renderIotEdgeTemplate replaces the ${NAME} placeholders of the deployment manifest template with their values.
+// The values are escaped as the placeholders are in JSON strings, and placeholders without a value are an error.
+func renderIotEdgeTemplate(template []byte, lookup func(name string) (string, bool)) ([]byte, error) {
+	missing := []string{}
+	rendered := iotEdgePlaceholderRegex.ReplaceAllFunc(template, func(placeholder []byte) []byte {
+		name := string(iotEdgePlaceholderRegex.FindSubmatch(placeholder)[1])
+		value, has := lookup(name)
+		if !has {
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+
+			return placeholder
+		}
+
+		escaped, _ := json.Marshal(value)
+		return escaped[1 : len(escaped)-1]
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no value for %s, set them in the environment", strings.Join(missing, ", "))
+	}
+
+	return rendered, nil
+}
+
+// desiredIotEdgeModuleImages returns the images of the modules of the modules content, by module name
+func desiredIotEdgeModuleImages(modulesContent map[string]any) map[string]string {
+	images := map[string]string{}
+
+	edgeAgent, _ := modulesContent["$edgeAgent"].(map[string]any)
+	desired, _ := edgeAgent["properties.desired"].(map[string]any)
+	modules, _ := desired["modules"].(map[string]any)
+	for name, module := range modules {
+		moduleContent, _ := module.(map[string]any)
+		settings, _ := moduleContent["settings"].(map[string]any)
+		image, _ := settings["image"].(string)
+		images[name] = image
+	}
+
+	return images
+}
+
+// pendingIotEdgeModules returns the modules that don't run the desired image yet, with their runtime status
+func pendingIotEdgeModules(desired map[string]string, statuses []*azcli.IotEdgeModuleStatus) []string {
+	pending := []string{}
+	for name, image := range desired {
+		idx := slices.IndexFunc(statuses, func(status *azcli.IotEdgeModuleStatus) bool {
+			return status.Name == name
+		})
+
+		switch {
+		case idx < 0:
+			pending = append(pending, fmt.Sprintf("%s (not reported)", name))
+		case image != "" && statuses[idx].Image != image:
+			pending = append(pending, fmt.Sprintf("%s (previous image)", name))
+		case statuses[idx].RuntimeStatus != "running":
+			pending = append(pending, fmt.Sprintf("%s (%s)", name, statuses[idx].RuntimeStatus))
+		}
+	}
+
+	slices.Sort(pending)
+	return pending
+}
+
+// iotEdgeDeployResult reports the device the deployment manifest was applied to with the runtime status of its
+// modules, or the at-scale deployment created
+type iotEdgeDeployResult struct {
+	DeviceId string `json:"deviceId,omitempty"`
+	// The runtime status of the modules of the device by module name, ex) running
+	Modules map[string]string `json:"modules,omitempty"`
+	// The id of the at-scale deployment created
+	Deployment      string `json:"deployment,omitempty"`
+	TargetCondition string `json:"targetCondition,omitempty"`
+}
+
+func (r *iotEdgeDeployResult) ToString(currentIndentation string) string {
+	builder := strings.Builder{}
+
+	if r.DeviceId != "" {
+		builder.WriteString(fmt.Sprintf("%s- Device: %s\n", currentIndentation, output.WithHighLightFormat(r.DeviceId)))
+
+		names := make([]string, 0, len(r.Modules))
+		for name := range r.Modules {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			builder.WriteString(fmt.Sprintf(
+				"%s  - %s: %s\n", currentIndentation, name, output.WithHighLightFormat(r.Modules[name])))
+		}
+	}
+
+	if r.Deployment != "" {
+		builder.WriteString(fmt.Sprintf(
+			"%s- Deployment: %s (%s)\n",
+			currentIndentation,
+			output.WithHighLightFormat(r.Deployment),
+			r.TargetCondition,
+		))
+	}
+
+	return builder.String()
+}
+
+func (r *iotEdgeDeployResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*r)
+}
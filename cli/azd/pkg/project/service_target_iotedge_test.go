@@ -0,0 +1,321 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+const testIotEdgeTemplate = `{
+  "modulesContent": {
+    "$edgeAgent": {
+      "properties.desired": {
+        "runtime": {
+          "settings": {
+            "registryCredentials": {
+              "acr": {"address": "${AZURE_CONTAINER_REGISTRY_ENDPOINT}"}
+            }
+          }
+        },
+        "modules": {
+          "api": {
+            "type": "docker",
+            "status": "running",
+            "settings": {"image": "${MODULES.api}"}
+          }
+        }
+      }
+    },
+    "$edgeHub": {
+      "properties.desired": {"routes": {}}
+    }
+  }
+}`
+
+func Test_IotEdge_Deploy(t *testing.T) {
+	t.Run("Device", func(t *testing.T) {
+		iotHubService := &fakeIotHubService{
+			statuses: []*azcli.IotEdgeModuleStatus{
+				{Name: "api", Image: "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", RuntimeStatus: "running"},
+				{Name: "edgeAgent", RuntimeStatus: "running", System: true},
+			},
+		}
+		serviceConfig := createIotEdgeServiceConfig(t)
+		serviceConfig.IotEdge.DeviceId = osutil.NewExpandableString("${IOT_EDGE_DEVICE_ID}")
+
+		deployResult, err := deployIotEdge(t, serviceConfig, iotHubService)
+		require.NoError(t, err)
+
+		require.Equal(t, "edge-01", iotHubService.appliedTo)
+		edgeAgent := iotHubService.applied["$edgeAgent"].(map[string]any)["properties.desired"].(map[string]any)
+		module := edgeAgent["modules"].(map[string]any)["api"].(map[string]any)
+		require.Equal(t,
+			"REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", module["settings"].(map[string]any)["image"])
+		require.Contains(t, iotHubService.applied, "$edgeHub")
+
+		require.Equal(t, IotEdgeTarget, deployResult.Kind)
+		require.Empty(t, deployResult.Endpoints)
+		require.Equal(t, &iotEdgeDeployResult{
+			DeviceId: "edge-01",
+			Modules:  map[string]string{"api": "running", "edgeAgent": "running"},
+		}, deployResult.Details)
+	})
+
+	t.Run("DeviceModulesNotRunning", func(t *testing.T) {
+		iotHubService := &fakeIotHubService{
+			statuses: []*azcli.IotEdgeModuleStatus{
+				{Name: "api", Image: "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", RuntimeStatus: "backoff"},
+			},
+		}
+		serviceConfig := createIotEdgeServiceConfig(t)
+		serviceConfig.IotEdge.DeviceId = osutil.NewExpandableString("edge-01")
+		serviceConfig.IotEdge.Timeout = time.Millisecond
+
+		_, err := deployIotEdge(t, serviceConfig, iotHubService)
+		require.ErrorContains(t, err, "was applied to device 'edge-01' but its modules did not run")
+		require.ErrorContains(t, err, "api (backoff)")
+	})
+
+	t.Run("AtScale", func(t *testing.T) {
+		iotHubService := &fakeIotHubService{
+			deployments: []*azcli.IotEdgeDeployment{
+				{
+					Id:             "api-20240101-000000",
+					Labels:         map[string]string{iotEdgeServiceLabel: "api"},
+					CreatedTimeUtc: "2024-01-01T00:00:00Z",
+				},
+				{
+					Id:             "api-20240201-000000",
+					Labels:         map[string]string{iotEdgeServiceLabel: "api"},
+					CreatedTimeUtc: "2024-02-01T00:00:00Z",
+				},
+				{
+					Id:             "other",
+					Labels:         map[string]string{iotEdgeServiceLabel: "web"},
+					CreatedTimeUtc: "2023-01-01T00:00:00Z",
+				},
+			},
+		}
+		serviceConfig := createIotEdgeServiceConfig(t)
+		serviceConfig.IotEdge.Deployment = &IotEdgeDeploymentOptions{
+			TargetCondition: osutil.NewExpandableString("tags.environment='${AZURE_ENV_NAME}'"),
+		}
+
+		deployResult, err := deployIotEdge(t, serviceConfig, iotHubService)
+		require.NoError(t, err)
+
+		created := iotHubService.created
+		require.True(t, strings.HasPrefix(created.Id, "api-"))
+		require.Equal(t, "tags.environment='test'", created.TargetCondition)
+		require.Equal(t, defaultIotEdgeDeploymentPriority, created.Priority)
+		require.Equal(t, "api", created.Labels[iotEdgeServiceLabel])
+		require.Contains(t, created.Content.ModulesContent, "$edgeAgent")
+
+		// The newest previous deployment is kept along with the one just created
+		require.Equal(t, []string{"api-20240101-000000"}, iotHubService.deleted)
+
+		require.Equal(t, &iotEdgeDeployResult{
+			Deployment:      created.Id,
+			TargetCondition: "tags.environment='test'",
+		}, deployResult.Details)
+	})
+
+	t.Run("DeviceAndDeployment", func(t *testing.T) {
+		serviceConfig := createIotEdgeServiceConfig(t)
+		serviceConfig.IotEdge.DeviceId = osutil.NewExpandableString("edge-01")
+		serviceConfig.IotEdge.Deployment = &IotEdgeDeploymentOptions{
+			TargetCondition: osutil.NewExpandableString("*"),
+		}
+
+		_, err := deployIotEdge(t, serviceConfig, &fakeIotHubService{})
+		require.ErrorContains(t, err, "set exactly one of 'iotEdge.deviceId' and 'iotEdge.deployment'")
+	})
+}
+
+func Test_renderIotEdgeTemplate(t *testing.T) {
+	values := map[string]string{
+		"MODULES.api": "contoso.azurecr.io/api:1",
+		"GREETING":    `say "hi"`,
+	}
+	lookup := func(name string) (string, bool) {
+		value, has := values[name]
+		return value, has
+	}
+
+	rendered, err := renderIotEdgeTemplate(
+		[]byte(`{"$edgeAgent": {"image": "${MODULES.api}", "env": "${GREETING}", "cost": "$5"}}`), lookup)
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"$edgeAgent": {"image": "contoso.azurecr.io/api:1", "env": "say \"hi\"", "cost": "$5"}}`, string(rendered))
+
+	_, err = renderIotEdgeTemplate([]byte(`{"image": "${MODULES.web}", "tag": "${TAG}${TAG}"}`), lookup)
+	require.ErrorContains(t, err, "no value for MODULES.web, TAG")
+}
+
+func Test_IotEdgeDeployResult_ToString(t *testing.T) {
+	result := &iotEdgeDeployResult{
+		DeviceId: "edge-01",
+		Modules:  map[string]string{"edgeAgent": "running", "api": "running"},
+	}
+
+	require.Equal(t,
+		"  - Device: edge-01\n    - api: running\n    - edgeAgent: running\n",
+		result.ToString("  "),
+	)
+}
+
+func createIotEdgeServiceConfig(t *testing.T) *ServiceConfig {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	require.NoError(t, os.WriteFile(
+		filepath.Join(tempDir, defaultIotEdgeTemplate), []byte(testIotEdgeTemplate), osutil.PermissionFile))
+
+	return createTestServiceConfig(tempDir, IotEdgeTarget, ServiceLanguageTypeScript)
+}
+
+func deployIotEdge(
+	t *testing.T,
+	serviceConfig *ServiceConfig,
+	iotHubService azcli.IotHubService,
+) (*ServiceDeployResult, error) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForDocker(mockContext)
+	setupMocksForAcr(mockContext)
+
+	env := createEnv()
+	env.DotenvSet(environment.EnvNameEnvVarName, "test")
+	env.DotenvSet("IOT_EDGE_DEVICE_ID", "edge-01")
+	env.DotenvSet("AZURE_CONTAINER_REGISTRY_ENDPOINT", "REGISTRY.azurecr.io")
+
+	dockerCli := docker.NewCli(mockContext.CommandRunner)
+	credentialProvider := mockaccount.SubscriptionCredentialProviderFunc(
+		func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		})
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		azcli.NewContainerRegistryService(
+			credentialProvider,
+			dockerCli,
+			mockContext.ArmClientOptions,
+			mockContext.CoreClientOptions,
+		),
+		containerregistry.NewRemoteBuildManager(credentialProvider, mockContext.ArmClientOptions),
+		dockerCli,
+		mockContext.Console,
+		cloud.AzurePublic(),
+		nil,
+		nil,
+		nil, nil, nil,
+	)
+
+	serviceTarget := NewIotEdgeTarget(env, containerHelper, iotHubService)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"IOT_HUB",
+		string(azapi.AzureResourceTypeIotHub),
+	)
+	packageResult := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash:   "IMAGE_HASH",
+			TargetImage: "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	return logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+		},
+	)
+}
+
+// fakeIotHubService records the deployment manifests applied to the IoT hub
+type fakeIotHubService struct {
+	statuses    []*azcli.IotEdgeModuleStatus
+	deployments []*azcli.IotEdgeDeployment
+
+	appliedTo string
+	applied   map[string]any
+	created   *azcli.IotEdgeDeployment
+	deleted   []string
+}
+
+func (f *fakeIotHubService) GetIotHubHostName(
+	ctx context.Context,
+	subscriptionId, resourceGroupName, hubName string,
+) (string, error) {
+	return hubName + ".azure-devices.net", nil
+}
+
+func (f *fakeIotHubService) ApplyIotEdgeDeviceContent(
+	ctx context.Context,
+	subscriptionId, hostName, deviceId string,
+	modulesContent map[string]any,
+) error {
+	f.appliedTo = deviceId
+	f.applied = modulesContent
+	return nil
+}
+
+func (f *fakeIotHubService) CreateIotEdgeDeployment(
+	ctx context.Context,
+	subscriptionId, hostName string,
+	deployment *azcli.IotEdgeDeployment,
+) (*azcli.IotEdgeDeployment, error) {
+	f.created = deployment
+	f.deployments = append(f.deployments, deployment)
+	return deployment, nil
+}
+
+func (f *fakeIotHubService) ListIotEdgeDeployments(
+	ctx context.Context,
+	subscriptionId, hostName string,
+) ([]*azcli.IotEdgeDeployment, error) {
+	return f.deployments, nil
+}
+
+func (f *fakeIotHubService) DeleteIotEdgeDeployment(
+	ctx context.Context,
+	subscriptionId, hostName, deploymentId string,
+) error {
+	f.deleted = append(f.deleted, deploymentId)
+	return nil
+}
+
+func (f *fakeIotHubService) GetIotEdgeModuleStatus(
+	ctx context.Context,
+	subscriptionId, hostName, deviceId string,
+) ([]*azcli.IotEdgeModuleStatus, error) {
+	return f.statuses, nil
+}
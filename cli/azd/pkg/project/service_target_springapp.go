@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
@@ -16,16 +18,52 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/sethvargo/go-retry"
 )
 
 const (
 	defaultDeploymentName = "default"
+	// The time the deployment waits for the instances of the staging deployment to run when not configured
+	defaultSpringStagingTimeout = 5 * time.Minute
+	// The interval between the checks of the instances of the staging deployment
+	springStagingPollInterval = 10 * time.Second
 )
 
+// The deployments that take turns serving the production traffic when not configured
+var defaultBlueGreenDeployments = []string{"blue", "green"}
+
 // The Azure Spring Apps configuration options
 type SpringOptions struct {
 	// The deployment name of ASA app
 	DeploymentName string `yaml:"deploymentName"`
+	// When set, the new version is deployed to the staging deployment of the app, validated and then set as the active
+	// deployment, for zero-downtime rollouts. The deployment name is not used.
+	BlueGreen *SpringBlueGreenOptions `yaml:"blueGreen,omitempty"`
+}
+
+// The blue/green deployment options of a Spring app
+type SpringBlueGreenOptions struct {
+	// The names of the two deployments that take turns serving the production traffic, ex) [blue, green].
+	// The new version is deployed to the deployment that is not active.
+	Deployments []string `yaml:"deployments,omitempty"`
+	// How long the deployment waits for the instances of the staging deployment to run, ex) 10m
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Keeps the new version in the staging deployment without setting it active, set by 'azd deploy --no-swap'
+	// to set it active manually
+	NoSwap bool `yaml:"-"`
+}
+
+// deployments returns the names of the two deployments that take turns serving the production traffic
+func (o *SpringBlueGreenOptions) deployments() ([]string, error) {
+	if len(o.Deployments) == 0 {
+		return defaultBlueGreenDeployments, nil
+	}
+
+	if len(o.Deployments) != 2 || o.Deployments[0] == o.Deployments[1] {
+		return nil, fmt.Errorf("blue/green deployments require two distinct deployment names, got: %v", o.Deployments)
+	}
+
+	return o.Deployments, nil
 }
 
 type springAppTarget struct {
@@ -85,17 +123,21 @@ func (st *springAppTarget) Deploy(
 		deploymentName = defaultDeploymentName
 	}
 
-	_, err := st.springService.GetSpringAppDeployment(
-		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
-		targetResource.ResourceName(),
-		serviceConfig.Name,
-		deploymentName,
-	)
+	// The staging deployment of blue/green deployments is created when it doesn't exist yet
+	if serviceConfig.Spring.BlueGreen == nil {
+		_, err := st.springService.GetSpringAppDeployment(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			serviceConfig.Name,
+			deploymentName,
+		)
 
-	if err != nil {
-		return nil, fmt.Errorf("get deployment '%s' of Spring App '%s' failed: %w", serviceConfig.Name, deploymentName, err)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"get deployment '%s' of Spring App '%s' failed: %w", serviceConfig.Name, deploymentName, err)
+		}
 	}
 
 	// TODO: Consider support container image and buildpacks deployment in the future
@@ -103,7 +145,7 @@ func (st *springAppTarget) Deploy(
 	ext := ".jar"
 	artifactPath := filepath.Join(packageOutput.PackagePath, AppServiceJavaPackageName+ext)
 
-	_, err = os.Stat(artifactPath)
+	_, err := os.Stat(artifactPath)
 	if errors.Is(err, os.ErrNotExist) {
 		return nil, fmt.Errorf("artifact %s does not exist: %w", artifactPath, err)
 	}
@@ -126,17 +168,21 @@ func (st *springAppTarget) Deploy(
 		return nil, fmt.Errorf("failed to upload spring artifact: %w", err)
 	}
 
-	progress.SetProgress(NewServiceProgress("Deploying spring artifact"))
-
-	res, err := st.springService.DeploySpringAppArtifact(
-		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
-		targetResource.ResourceName(),
-		serviceConfig.Name,
-		*relativePath,
-		deploymentName,
-	)
+	var res *string
+	if serviceConfig.Spring.BlueGreen != nil {
+		res, err = st.deployBlueGreen(ctx, serviceConfig, targetResource, *relativePath, progress)
+	} else {
+		progress.SetProgress(NewServiceProgress("Deploying spring artifact"))
+		res, err = st.springService.DeploySpringAppArtifact(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			serviceConfig.Name,
+			*relativePath,
+			deploymentName,
+		)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err)
 	}
@@ -169,6 +215,264 @@ func (st *springAppTarget) Deploy(
 	return sdr, nil
 }
 
+// deployBlueGreen deploys the artifact to the deployment of the app that is not active, waits for its instances to
+// run and sets it as the active deployment, unless the swap is skipped. The staging deployment is returned.
+func (st *springAppTarget) deployBlueGreen(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	relativePath string,
+	progress *async.Progress[ServiceProgress],
+) (*string, error) {
+	options := serviceConfig.Spring.BlueGreen
+	names, err := options.deployments()
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := st.springService.ListSpringAppDeployments(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	active := activeSpringDeployment(deployments)
+	if active == nil {
+		return nil, fmt.Errorf("spring app '%s' has no active deployment to stage the new version against",
+			serviceConfig.Name)
+	}
+
+	// The first blue/green rollout replaces a deployment outside of the pair, ex) the default deployment
+	staging := names[0]
+	if active.Name == names[0] {
+		staging = names[1]
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Deploying spring artifact to staging deployment: %s", staging)))
+	if _, err := st.springService.DeploySpringAppStagingArtifact(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+		relativePath,
+		staging,
+		active.Name,
+	); err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Validating staging deployment: %s", staging)))
+	if err := st.waitForDeployment(ctx, serviceConfig, targetResource, staging); err != nil {
+		return nil, err
+	}
+
+	if options.NoSwap {
+		return &staging, nil
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Setting deployment %s active", staging)))
+	if err := st.springService.SetSpringAppActiveDeployment(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+		staging,
+	); err != nil {
+		return nil, err
+	}
+
+	return &staging, nil
+}
+
+// waitForDeployment waits for all the instances of the deployment to run within the configured timeout, so the
+// deployment only serves the production traffic once the new version is started
+func (st *springAppTarget) waitForDeployment(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	deploymentName string,
+) error {
+	timeout := serviceConfig.Spring.BlueGreen.Timeout
+	if timeout == 0 {
+		timeout = defaultSpringStagingTimeout
+	}
+
+	err := retry.Do(
+		ctx,
+		retry.WithMaxDuration(timeout, retry.NewConstant(springStagingPollInterval)),
+		func(ctx context.Context) error {
+			deployments, err := st.springService.ListSpringAppDeployments(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				serviceConfig.Name,
+			)
+			if err != nil {
+				return err
+			}
+
+			idx := slices.IndexFunc(deployments, func(d *azcli.SpringAppDeployment) bool {
+				return d.Name == deploymentName
+			})
+			if idx < 0 {
+				return fmt.Errorf("deployment '%s' not found", deploymentName)
+			}
+
+			return retry.RetryableError(springDeploymentRunning(deployments[idx]))
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"staging deployment '%s' is not running, the deployment was not set active: %w", deploymentName, err)
+	}
+
+	return nil
+}
+
+// springDeploymentRunning returns an error when the deployment or any of its instances is not running
+func springDeploymentRunning(deployment *azcli.SpringAppDeployment) error {
+	if deployment.Status != "Running" {
+		return fmt.Errorf("deployment status is '%s'", deployment.Status)
+	}
+
+	if len(deployment.Instances) == 0 {
+		return errors.New("deployment has no instances")
+	}
+
+	for _, instance := range deployment.Instances {
+		if instance.Status != "Running" {
+			if instance.Reason != "" {
+				return fmt.Errorf("instance '%s' is '%s': %s", instance.Name, instance.Status, instance.Reason)
+			}
+
+			return fmt.Errorf("instance '%s' is '%s'", instance.Name, instance.Status)
+		}
+	}
+
+	return nil
+}
+
+// activeSpringDeployment returns the deployment serving the production traffic, nil when no deployment is active
+func activeSpringDeployment(deployments []*azcli.SpringAppDeployment) *azcli.SpringAppDeployment {
+	for _, deployment := range deployments {
+		if deployment.Active {
+			return deployment
+		}
+	}
+
+	return nil
+}
+
+// Rollback sets the deployment that served the production traffic before the latest blue/green rollout active again.
+// Spring apps don't record a deployment history, so only the previous deployment can be restored.
+func (st *springAppTarget) Rollback(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	revision int,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if serviceConfig.Spring.BlueGreen == nil {
+		return nil, fmt.Errorf("%w for service '%s' without blue/green deployments, configure 'spring.blueGreen'",
+			ErrRollbackNotSupported, serviceConfig.Name)
+	}
+
+	if revision != 0 {
+		return nil, errors.New("spring apps can only roll back to the previous deployment, omit the revision")
+	}
+
+	if err := st.validateTargetResource(targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	names, err := serviceConfig.Spring.BlueGreen.deployments()
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching deployments of spring app"))
+	deployments, err := st.springService.ListSpringAppDeployments(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := previousSpringDeployment(deployments, names)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Setting deployment %s active", previous)))
+	if err := st.springService.SetSpringAppActiveDeployment(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		serviceConfig.Name,
+		previous,
+	); err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching endpoints for spring app service"))
+	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewServiceDeployResult(
+		azure.SpringAppRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		SpringAppTarget,
+		previous,
+		endpoints,
+	), nil
+}
+
+// previousSpringDeployment returns the deployment the production traffic is rolled back to: the other deployment of
+// the blue/green pair or, after the first blue/green rollout, the only other deployment of the app
+func previousSpringDeployment(deployments []*azcli.SpringAppDeployment, names []string) (string, error) {
+	active := activeSpringDeployment(deployments)
+	if active == nil {
+		return "", errors.New("the spring app has no active deployment")
+	}
+
+	others := []string{}
+	for _, deployment := range deployments {
+		if deployment.Name != active.Name {
+			others = append(others, deployment.Name)
+		}
+	}
+
+	for _, name := range names {
+		if name != active.Name && slices.Contains(others, name) {
+			return name, nil
+		}
+	}
+
+	if len(others) == 1 {
+		return others[0], nil
+	}
+
+	return "", fmt.Errorf("no previous deployment to roll back to from the active deployment '%s'", active.Name)
+}
+
 // Gets the exposed endpoints for the Spring Apps Service
 func (st *springAppTarget) Endpoints(
 	ctx context.Context,
@@ -4,11 +4,21 @@
 package project
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,3 +63,227 @@ func TestNewSpringAppTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_SpringApp_Deploy_BlueGreen(t *testing.T) {
+	tests := []struct {
+		name           string
+		active         string
+		noSwap         bool
+		stagingStatus  string
+		expectedStaged string
+		expectedActive string
+		expectedErr    string
+	}{
+		{
+			name:           "FirstRollout",
+			active:         "default",
+			stagingStatus:  "Running",
+			expectedStaged: "blue",
+			expectedActive: "blue",
+		},
+		{
+			name:           "Swap",
+			active:         "blue",
+			stagingStatus:  "Running",
+			expectedStaged: "green",
+			expectedActive: "green",
+		},
+		{
+			name:           "NoSwap",
+			active:         "blue",
+			noSwap:         true,
+			stagingStatus:  "Running",
+			expectedStaged: "green",
+			expectedActive: "blue",
+		},
+		{
+			name:          "NotRunning",
+			active:        "blue",
+			stagingStatus: "Failed",
+			expectedErr:   "staging deployment 'green' is not running, the deployment was not set active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			springService := &fakeSpringService{
+				deployments: []*azcli.SpringAppDeployment{
+					{Name: tt.active, Active: true, Status: "Running"},
+				},
+				stagingStatus: tt.stagingStatus,
+			}
+
+			packagePath := t.TempDir()
+			artifactPath := filepath.Join(packagePath, AppServiceJavaPackageName+".jar")
+			require.NoError(t, os.WriteFile(artifactPath, []byte{}, osutil.PermissionFile))
+
+			serviceConfig := createTestServiceConfig("./src/api", SpringAppTarget, ServiceLanguageJava)
+			serviceConfig.Spring.BlueGreen = &SpringBlueGreenOptions{
+				Timeout: time.Millisecond,
+				NoSwap:  tt.noSwap,
+			}
+
+			env := environment.New("test")
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", mock.Anything, env).Return(nil)
+
+			serviceTarget := NewSpringAppTarget(env, envManager, springService)
+			scope := environment.NewTargetResource(
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"SPRING_APPS_NAME",
+				string(azapi.AzureResourceTypeSpringApp),
+			)
+
+			packageResult := &ServicePackageResult{PackagePath: packagePath}
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+				},
+			)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				require.Equal(t, tt.active, springService.active())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedStaged, springService.staged)
+			require.Equal(t, tt.expectedActive, springService.active())
+			require.Equal(t, tt.expectedStaged, deployResult.Details)
+			require.Equal(t, []string{"https://SPRING_APPS_NAME-api.azuremicroservices.io"}, deployResult.Endpoints)
+		})
+	}
+}
+
+func Test_SpringApp_Rollback(t *testing.T) {
+	tests := []struct {
+		name           string
+		deployments    []*azcli.SpringAppDeployment
+		expectedActive string
+		expectedErr    string
+	}{
+		{
+			name: "BlueGreen",
+			deployments: []*azcli.SpringAppDeployment{
+				{Name: "default"},
+				{Name: "blue"},
+				{Name: "green", Active: true},
+			},
+			expectedActive: "blue",
+		},
+		{
+			name: "FirstRollout",
+			deployments: []*azcli.SpringAppDeployment{
+				{Name: "default"},
+				{Name: "blue", Active: true},
+			},
+			expectedActive: "default",
+		},
+		{
+			name: "NoPreviousDeployment",
+			deployments: []*azcli.SpringAppDeployment{
+				{Name: "blue", Active: true},
+			},
+			expectedErr: "no previous deployment to roll back to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			springService := &fakeSpringService{deployments: tt.deployments}
+
+			serviceConfig := createTestServiceConfig("./src/api", SpringAppTarget, ServiceLanguageJava)
+			serviceConfig.Spring.BlueGreen = &SpringBlueGreenOptions{}
+
+			serviceTarget := NewSpringAppTarget(environment.New("test"), &mockenv.MockEnvManager{}, springService)
+			scope := environment.NewTargetResource(
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"SPRING_APPS_NAME",
+				string(azapi.AzureResourceTypeSpringApp),
+			)
+
+			rollbacker, ok := serviceTarget.(ServiceTargetRollbacker)
+			require.True(t, ok)
+
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return rollbacker.Rollback(*mockContext.Context, serviceConfig, scope, 0, progress)
+				},
+			)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedActive, springService.active())
+			require.Equal(t, tt.expectedActive, deployResult.Details)
+		})
+	}
+}
+
+// fakeSpringService keeps the deployments of a single spring app in memory
+type fakeSpringService struct {
+	azcli.SpringService
+	deployments []*azcli.SpringAppDeployment
+	// The status of the deployments the artifact is staged to
+	stagingStatus string
+	staged        string
+}
+
+func (f *fakeSpringService) active() string {
+	if deployment := activeSpringDeployment(f.deployments); deployment != nil {
+		return deployment.Name
+	}
+
+	return ""
+}
+
+func (f *fakeSpringService) GetSpringAppProperties(
+	ctx context.Context, subscriptionId, resourceGroupName, instanceName, appName string,
+) (*azcli.SpringAppProperties, error) {
+	return &azcli.SpringAppProperties{
+		Url: []string{"https://" + instanceName + "-" + appName + ".azuremicroservices.io"},
+	}, nil
+}
+
+func (f *fakeSpringService) UploadSpringArtifact(
+	ctx context.Context, subscriptionId, resourceGroup, instanceName, appName, artifactPath string,
+) (*string, error) {
+	relativePath := "resources/" + filepath.Base(artifactPath)
+	return &relativePath, nil
+}
+
+func (f *fakeSpringService) ListSpringAppDeployments(
+	ctx context.Context, subscriptionId, resourceGroupName, instanceName, appName string,
+) ([]*azcli.SpringAppDeployment, error) {
+	return f.deployments, nil
+}
+
+func (f *fakeSpringService) DeploySpringAppStagingArtifact(
+	ctx context.Context,
+	subscriptionId, resourceGroup, instanceName, appName, relativePath, deploymentName, sourceDeploymentName string,
+) (*string, error) {
+	f.staged = deploymentName
+	f.deployments = append(f.deployments, &azcli.SpringAppDeployment{
+		Name:      deploymentName,
+		Status:    f.stagingStatus,
+		Instances: []azcli.SpringAppDeploymentInstance{{Name: deploymentName + "-0", Status: f.stagingStatus}},
+	})
+
+	return &deploymentName, nil
+}
+
+func (f *fakeSpringService) SetSpringAppActiveDeployment(
+	ctx context.Context, subscriptionId, resourceGroup, instanceName, appName, deploymentName string,
+) error {
+	for _, deployment := range f.deployments {
+		deployment.Active = deployment.Name == deploymentName
+	}
+
+	return nil
+}
@@ -19,8 +19,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
 )
 
-// TODO: Enhance for multi-environment support
-// https://github.com/Azure/azure-dev/issues/1152
+// The name of the production environment of a static web app, services are deployed to named preview environments
+// with 'azd deploy --preview-env'
 const DefaultStaticWebAppEnvironmentName = "default"
 
 type staticWebAppTarget struct {
@@ -128,7 +128,7 @@ func (at *staticWebAppTarget) Deploy(
 		targetResource.SubscriptionId(),
 		targetResource.ResourceGroupName(),
 		targetResource.ResourceName(),
-		DefaultStaticWebAppEnvironmentName,
+		staticWebAppEnvironmentName(serviceConfig),
 		*deploymentToken,
 		dOptions)
 
@@ -139,7 +139,7 @@ func (at *staticWebAppTarget) Deploy(
 	}
 
 	progress.SetProgress(NewServiceProgress("Verifying deployment"))
-	if err := at.verifyDeployment(ctx, targetResource); err != nil {
+	if err := at.verifyDeployment(ctx, serviceConfig, targetResource); err != nil {
 		return nil, err
 	}
 
@@ -170,21 +170,32 @@ func (at *staticWebAppTarget) Endpoints(
 	serviceConfig *ServiceConfig,
 	targetResource *environment.TargetResource,
 ) ([]string, error) {
-	// TODO: Enhance for multi-environment support
-	// https://github.com/Azure/azure-dev/issues/1152
 	if envProps, err := at.cli.GetStaticWebAppEnvironmentProperties(
 		ctx,
 		targetResource.SubscriptionId(),
 		targetResource.ResourceGroupName(),
 		targetResource.ResourceName(),
-		DefaultStaticWebAppEnvironmentName,
+		staticWebAppEnvironmentName(serviceConfig),
 	); err != nil {
 		return nil, fmt.Errorf("fetching service properties: %w", err)
+	} else if preview := serviceConfig.StaticWebApp.Preview; preview != "" {
+		// The preview environment has its own host name, distinct from the production one
+		return []string{fmt.Sprintf("Preview %s: https://%s/", preview, envProps.Hostname)}, nil
 	} else {
 		return []string{fmt.Sprintf("https://%s/", envProps.Hostname)}, nil
 	}
 }
 
+// staticWebAppEnvironmentName returns the static web app environment the service is deployed to, the preview
+// environment when one is set
+func staticWebAppEnvironmentName(serviceConfig *ServiceConfig) string {
+	if serviceConfig.StaticWebApp.Preview != "" {
+		return serviceConfig.StaticWebApp.Preview
+	}
+
+	return DefaultStaticWebAppEnvironmentName
+}
+
 func (at *staticWebAppTarget) validateTargetResource(
 	targetResource *environment.TargetResource,
 ) error {
@@ -199,7 +210,11 @@ func (at *staticWebAppTarget) validateTargetResource(
 	return nil
 }
 
-func (at *staticWebAppTarget) verifyDeployment(ctx context.Context, targetResource *environment.TargetResource) error {
+func (at *staticWebAppTarget) verifyDeployment(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
 	retries := 0
 	const maxRetries = 10
 
@@ -209,7 +224,7 @@ func (at *staticWebAppTarget) verifyDeployment(ctx context.Context, targetResour
 			targetResource.SubscriptionId(),
 			targetResource.ResourceGroupName(),
 			targetResource.ResourceName(),
-			DefaultStaticWebAppEnvironmentName,
+			staticWebAppEnvironmentName(serviceConfig),
 		)
 		if err != nil {
 			return fmt.Errorf("failed verifying static web app deployment: %w", err)
@@ -225,7 +240,11 @@ func (at *staticWebAppTarget) verifyDeployment(ctx context.Context, targetResour
 			return fmt.Errorf("failed verifying static web app deployment. Still in %s state", envProps.Status)
 		}
 
-		time.Sleep(5 * time.Second)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
 	}
 
 	return nil
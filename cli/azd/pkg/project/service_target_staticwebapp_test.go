@@ -4,11 +4,18 @@
 package project
 
 import (
+	"context"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,3 +60,69 @@ func TestNewStaticWebAppTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_StaticWebApp_Endpoints_Preview(t *testing.T) {
+	tests := []struct {
+		name             string
+		preview          string
+		expectedBuild    string
+		expectedEndpoint string
+	}{
+		{
+			name:             "Production",
+			expectedBuild:    DefaultStaticWebAppEnvironmentName,
+			expectedEndpoint: "https://APP_NAME.azurestaticapps.net/",
+		},
+		{
+			name:             "Preview",
+			preview:          "42",
+			expectedBuild:    "42",
+			expectedEndpoint: "Preview 42: https://APP_NAME-42.azurestaticapps.net/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodGet &&
+					strings.Contains(request.URL.Path, "/providers/Microsoft.Web/staticSites/APP_NAME/builds/")
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				hostName := "APP_NAME.azurestaticapps.net"
+				if !strings.HasSuffix(request.URL.Path, "/builds/"+DefaultStaticWebAppEnvironmentName) {
+					hostName = "APP_NAME-42.azurestaticapps.net"
+				}
+
+				require.True(t, strings.HasSuffix(request.URL.Path, "/builds/"+tt.expectedBuild))
+				return mocks.CreateHttpResponseWithBody(
+					request,
+					http.StatusOK,
+					armappservice.StaticSitesClientGetStaticSiteBuildResponse{
+						StaticSiteBuildARMResource: armappservice.StaticSiteBuildARMResource{
+							Properties: &armappservice.StaticSiteBuildARMResourceProperties{
+								Hostname: to.Ptr(hostName),
+								Status:   to.Ptr(armappservice.BuildStatusReady),
+							},
+						},
+					},
+				)
+			})
+
+			serviceConfig := createTestServiceConfig("./src/web", StaticWebAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.StaticWebApp.Preview = tt.preview
+
+			azCli := azcli.NewAzCli(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+			serviceTarget := NewStaticWebAppTarget(environment.New("test"), azCli, swa.NewCli(mockContext.CommandRunner))
+			scope := environment.NewTargetResource(
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"APP_NAME",
+				string(azapi.AzureResourceTypeStaticWebSite),
+			)
+
+			endpoints, err := serviceTarget.Endpoints(*mockContext.Context, serviceConfig, scope)
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.expectedEndpoint}, endpoints)
+		})
+	}
+}
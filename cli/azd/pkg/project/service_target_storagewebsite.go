@@ -0,0 +1,464 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+const (
+	// The container served by the static website of a storage account
+	staticWebsiteContainer = "$web"
+	// The index document of the static website when not configured
+	defaultStaticWebsiteIndexDocument = "index.html"
+	// The cache control of the HTML documents that don't match any cache control rule, so that a deployment is
+	// visible as soon as the cache of the CDN is purged
+	defaultStaticWebsiteHtmlCacheControl = "no-cache"
+)
+
+// The Azure Storage static website options of a service
+// The build output of the service is uploaded to the $web container served by the static website of the storage
+// account, or to another container, ex) the origin of an Azure Front Door profile.
+type StorageWebsiteOptions struct {
+	// The blob container the site is uploaded to. Defaults to $web, whose static website is enabled on deploy
+	Container string `yaml:"container,omitempty"`
+	// The index document of the static website. Defaults to index.html
+	IndexDocument string `yaml:"indexDocument,omitempty"`
+	// The document served when a path is not found, ex) index.html for single page applications routing on the client
+	ErrorDocument string `yaml:"errorDocument,omitempty"`
+	// The cache control of the uploaded files, the first rule matching a file applies.
+	// HTML files matched by no rule are not cached
+	CacheControl []StorageWebsiteCacheRule `yaml:"cacheControl,omitempty"`
+	// When enabled, the blobs of the container that are not part of the site are deleted
+	Prune bool `yaml:"prune,omitempty"`
+	// The CDN or Front Door endpoint purged after the site is uploaded
+	Cdn *StorageWebsiteCdnOptions `yaml:"cdn,omitempty"`
+	// The endpoints reported for the service, ex) ${FRONT_DOOR_ENDPOINT} from the outputs of the infrastructure.
+	// Defaults to the URL of the static website
+	Endpoints []osutil.ExpandableString `yaml:"endpoints,omitempty"`
+}
+
+// A cache control rule of the files of a static website
+type StorageWebsiteCacheRule struct {
+	// The glob pattern of the files the rule applies to, relative to the site root, ex) assets/**
+	Pattern string `yaml:"pattern"`
+	// The Cache-Control header of the files, ex) public, max-age=31536000, immutable
+	Value string `yaml:"value"`
+}
+
+// The CDN or Front Door endpoint of a static website
+type StorageWebsiteCdnOptions struct {
+	// The name of the CDN or Front Door profile
+	Profile osutil.ExpandableString `yaml:"profile"`
+	// The name of the endpoint of the profile
+	Endpoint osutil.ExpandableString `yaml:"endpoint"`
+	// The resource group of the profile. Defaults to the resource group of the service
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup,omitempty"`
+	// Whether the profile is an Azure Front Door Standard/Premium profile
+	FrontDoor bool `yaml:"frontDoor,omitempty"`
+	// The content paths purged. Defaults to /*
+	Paths []string `yaml:"paths,omitempty"`
+}
+
+type storageWebsiteTarget struct {
+	env            *environment.Environment
+	websiteService azcli.StorageWebsiteService
+}
+
+// NewStorageWebsiteTarget creates the service target for static sites hosted on Azure Storage.
+//
+// It is a cheaper alternative to Azure Static Web Apps for sites that don't need its APIs, authentication or preview
+// environments.
+func NewStorageWebsiteTarget(
+	env *environment.Environment,
+	websiteService azcli.StorageWebsiteService,
+) ServiceTarget {
+	return &storageWebsiteTarget{
+		env:            env,
+		websiteService: websiteService,
+	}
+}
+
+// Gets the required external tools
+func (t *storageWebsiteTarget) RequiredExternalTools(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the storage website target
+func (t *storageWebsiteTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Resolves the directory of the built site, the 'dist' of the service or the output of the language framework
+func (t *storageWebsiteTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	sitePath := serviceConfig.OutputPath
+	if strings.TrimSpace(sitePath) == "" {
+		sitePath = packageOutput.PackagePath
+	}
+
+	if sitePath == "" {
+		return nil, fmt.Errorf("the build output of service '%s' is unknown, set 'dist' of the service", serviceConfig.Name)
+	}
+
+	if !filepath.IsAbs(sitePath) {
+		sitePath = filepath.Join(serviceConfig.Path(), sitePath)
+	}
+
+	if info, err := os.Stat(sitePath); err != nil {
+		return nil, fmt.Errorf("build output of service '%s' not found: %w", serviceConfig.Name, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("build output '%s' of service '%s' is not a directory", sitePath, serviceConfig.Name)
+	}
+
+	return &ServicePackageResult{
+		Build:       packageOutput.Build,
+		PackagePath: sitePath,
+	}, nil
+}
+
+// Uploads the site to the blob container and purges the CDN endpoint
+func (t *storageWebsiteTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := checkResourceType(targetResource, azapi.AzureResourceTypeStorageAccount); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	options := serviceConfig.StorageWebsite
+	files, err := storageWebsiteFiles(packageOutput.PackagePath, options)
+	if err != nil {
+		return nil, err
+	}
+
+	container := storageWebsiteContainer(serviceConfig)
+	if container == staticWebsiteContainer {
+		indexDocument := options.IndexDocument
+		if indexDocument == "" {
+			indexDocument = defaultStaticWebsiteIndexDocument
+		}
+
+		progress.SetProgress(NewServiceProgress("Enabling static website"))
+		err := t.websiteService.EnableStaticWebsite(
+			ctx, targetResource.SubscriptionId(), targetResource.ResourceName(), indexDocument, options.ErrorDocument)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Uploading %d files", len(files))))
+	err = t.websiteService.UploadStorageWebsiteFiles(
+		ctx, targetResource.SubscriptionId(), targetResource.ResourceName(), container, files)
+	if err != nil {
+		return nil, fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err)
+	}
+
+	deployResult := &storageWebsiteDeployResult{
+		Container: container,
+		Uploaded:  len(files),
+	}
+
+	if options.Prune {
+		progress.SetProgress(NewServiceProgress("Deleting stale files"))
+		deleted, err := t.prune(ctx, targetResource, container, files)
+		if err != nil {
+			return nil, err
+		}
+
+		deployResult.Deleted = deleted
+	}
+
+	if options.Cdn != nil {
+		endpoint, err := t.cdnEndpoint(serviceConfig, targetResource)
+		if err != nil {
+			return nil, err
+		}
+
+		paths := options.Cdn.Paths
+		if len(paths) == 0 {
+			paths = []string{"/*"}
+		}
+
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Purging endpoint %s", endpoint.EndpointName)))
+		err = t.websiteService.PurgeCdnEndpoint(ctx, targetResource.SubscriptionId(), endpoint, paths)
+		if err != nil {
+			return nil, err
+		}
+
+		deployResult.Purged = endpoint.EndpointName
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching endpoints for static website"))
+	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceDeployResult{
+		Package: packageOutput,
+		TargetResourceId: fmt.Sprintf(
+			"%s/providers/%s/%s",
+			azure.ResourceGroupRID(targetResource.SubscriptionId(), targetResource.ResourceGroupName()),
+			azapi.AzureResourceTypeStorageAccount,
+			targetResource.ResourceName(),
+		),
+		Kind:      StorageWebsiteTarget,
+		Endpoints: endpoints,
+		Details:   deployResult,
+	}, nil
+}
+
+// Gets the configured endpoints of the service, or the URL of the static website
+func (t *storageWebsiteTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	if len(serviceConfig.StorageWebsite.Endpoints) > 0 {
+		endpoints := []string{}
+		for _, endpoint := range serviceConfig.StorageWebsite.Endpoints {
+			value, err := endpoint.Envsubst(t.env.Getenv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to envsubst the endpoints of service '%s': %w", serviceConfig.Name, err)
+			}
+
+			if value != "" {
+				endpoints = append(endpoints, value)
+			}
+		}
+
+		return endpoints, nil
+	}
+
+	if storageWebsiteContainer(serviceConfig) != staticWebsiteContainer {
+		return []string{}, nil
+	}
+
+	siteUrl, err := t.websiteService.GetStaticWebsiteUrl(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching static website url: %w", err)
+	}
+
+	if siteUrl == "" {
+		return []string{}, nil
+	}
+
+	return []string{siteUrl}, nil
+}
+
+// prune deletes the blobs of the container that are not files of the site and returns the number of deleted blobs
+func (t *storageWebsiteTarget) prune(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	container string,
+	files []*azcli.StorageWebsiteFile,
+) (int, error) {
+	names, err := t.websiteService.ListStorageWebsiteFiles(
+		ctx, targetResource.SubscriptionId(), targetResource.ResourceName(), container)
+	if err != nil {
+		return 0, err
+	}
+
+	stale := []string{}
+	for _, name := range names {
+		if !slices.ContainsFunc(files, func(file *azcli.StorageWebsiteFile) bool { return file.Name == name }) {
+			stale = append(stale, name)
+		}
+	}
+
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	err = t.websiteService.DeleteStorageWebsiteFiles(
+		ctx, targetResource.SubscriptionId(), targetResource.ResourceName(), container, stale)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(stale), nil
+}
+
+// cdnEndpoint resolves the CDN or Front Door endpoint of the service
+func (t *storageWebsiteTarget) cdnEndpoint(
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (*azcli.CdnEndpoint, error) {
+	options := serviceConfig.StorageWebsite.Cdn
+	profile, err := options.Profile.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the cdn profile of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	endpoint, err := options.Endpoint.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the cdn endpoint of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if profile == "" || endpoint == "" {
+		return nil, fmt.Errorf(
+			"set 'storageWebsite.cdn.profile' and 'storageWebsite.cdn.endpoint' of service '%s'", serviceConfig.Name)
+	}
+
+	resourceGroupName, err := options.ResourceGroup.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the cdn resource group of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if resourceGroupName == "" {
+		resourceGroupName = targetResource.ResourceGroupName()
+	}
+
+	return &azcli.CdnEndpoint{
+		ResourceGroupName: resourceGroupName,
+		ProfileName:       profile,
+		EndpointName:      endpoint,
+		FrontDoor:         options.FrontDoor,
+	}, nil
+}
+
+// storageWebsiteContainer returns the blob container the site of the service is uploaded to
+func storageWebsiteContainer(serviceConfig *ServiceConfig) string {
+	if serviceConfig.StorageWebsite.Container != "" {
+		return serviceConfig.StorageWebsite.Container
+	}
+
+	return staticWebsiteContainer
+}
+
+// storageWebsiteFiles returns the files of the site with their content type and cache control
+func storageWebsiteFiles(sitePath string, options StorageWebsiteOptions) ([]*azcli.StorageWebsiteFile, error) {
+	files := []*azcli.StorageWebsiteFile{}
+	err := filepath.WalkDir(sitePath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(sitePath, filePath)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(relativePath)
+		cacheControl, err := storageWebsiteCacheControl(name, options.CacheControl)
+		if err != nil {
+			return err
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		files = append(files, &azcli.StorageWebsiteFile{
+			Path:         filePath,
+			Name:         name,
+			ContentType:  contentType,
+			CacheControl: cacheControl,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading build output '%s': %w", sitePath, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("build output '%s' has no files", sitePath)
+	}
+
+	return files, nil
+}
+
+// storageWebsiteCacheControl returns the cache control of the first rule matching the file
+func storageWebsiteCacheControl(name string, rules []StorageWebsiteCacheRule) (string, error) {
+	for _, rule := range rules {
+		matched, err := doublestar.Match(strings.TrimPrefix(rule.Pattern, "/"), name)
+		if err != nil {
+			return "", fmt.Errorf("invalid cache control pattern '%s': %w", rule.Pattern, err)
+		}
+
+		if matched {
+			return rule.Value, nil
+		}
+	}
+
+	if strings.EqualFold(path.Ext(name), ".html") {
+		return defaultStaticWebsiteHtmlCacheControl, nil
+	}
+
+	return "", nil
+}
+
+// storageWebsiteDeployResult reports the container the site was uploaded to and the endpoint purged, if any
+type storageWebsiteDeployResult struct {
+	Container string `json:"container"`
+	// The number of files uploaded
+	Uploaded int `json:"uploaded"`
+	// The number of stale blobs deleted
+	Deleted int `json:"deleted,omitempty"`
+	// The name of the CDN or Front Door endpoint purged
+	Purged string `json:"purged,omitempty"`
+}
+
+func (r *storageWebsiteDeployResult) ToString(currentIndentation string) string {
+	builder := strings.Builder{}
+
+	files := fmt.Sprintf("%d uploaded", r.Uploaded)
+	if r.Deleted > 0 {
+		files = fmt.Sprintf("%s, %d deleted", files, r.Deleted)
+	}
+
+	builder.WriteString(fmt.Sprintf(
+		"%s- Files: %s (%s)\n", currentIndentation, output.WithHighLightFormat(r.Container), files))
+
+	if r.Purged != "" {
+		builder.WriteString(fmt.Sprintf("%s- Purged: %s\n", currentIndentation, output.WithHighLightFormat(r.Purged)))
+	}
+
+	return builder.String()
+}
+
+func (r *storageWebsiteDeployResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*r)
+}
@@ -0,0 +1,283 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StorageWebsite_Package(t *testing.T) {
+	t.Run("Dist", func(t *testing.T) {
+		serviceConfig := createStorageWebsiteServiceConfig(t)
+		serviceConfig.OutputPath = "dist"
+		require.NoError(t, os.Mkdir(filepath.Join(serviceConfig.Path(), "dist"), osutil.PermissionDirectory))
+
+		packageResult, err := packageStorageWebsite(t, serviceConfig, &ServicePackageResult{PackagePath: "build"})
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(serviceConfig.Path(), "dist"), packageResult.PackagePath)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		serviceConfig := createStorageWebsiteServiceConfig(t)
+
+		_, err := packageStorageWebsite(t, serviceConfig, &ServicePackageResult{PackagePath: "build"})
+		require.ErrorContains(t, err, "build output of service 'api' not found")
+	})
+}
+
+func Test_StorageWebsite_Deploy(t *testing.T) {
+	t.Run("StaticWebsite", func(t *testing.T) {
+		websiteService := &fakeStorageWebsiteService{
+			siteUrl: "https://contoso.z13.web.core.windows.net/",
+			blobs:   []string{"index.html", "assets/old.js"},
+		}
+		serviceConfig := createStorageWebsiteServiceConfig(t)
+		serviceConfig.StorageWebsite = StorageWebsiteOptions{
+			ErrorDocument: "index.html",
+			Prune:         true,
+			CacheControl: []StorageWebsiteCacheRule{
+				{Pattern: "assets/**", Value: "public, max-age=31536000, immutable"},
+			},
+			Cdn: &StorageWebsiteCdnOptions{
+				Profile:   osutil.NewExpandableString("${CDN_PROFILE}"),
+				Endpoint:  osutil.NewExpandableString("site"),
+				FrontDoor: true,
+			},
+		}
+
+		deployResult, err := deployStorageWebsite(t, serviceConfig, websiteService)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"index.html", "index.html"}, websiteService.documents)
+		require.Equal(t, staticWebsiteContainer, websiteService.container)
+		require.Len(t, websiteService.uploaded, 2)
+		require.Equal(t, &azcli.StorageWebsiteFile{
+			Path:         filepath.Join(serviceConfig.Path(), "dist", "assets", "app.js"),
+			Name:         "assets/app.js",
+			ContentType:  "text/javascript; charset=utf-8",
+			CacheControl: "public, max-age=31536000, immutable",
+		}, websiteService.uploaded[0])
+		require.Equal(t, "index.html", websiteService.uploaded[1].Name)
+		require.Equal(t, "no-cache", websiteService.uploaded[1].CacheControl)
+		require.Equal(t, []string{"assets/old.js"}, websiteService.deleted)
+		require.Equal(t, &azcli.CdnEndpoint{
+			ResourceGroupName: "RESOURCE_GROUP",
+			ProfileName:       "contoso-cdn",
+			EndpointName:      "site",
+			FrontDoor:         true,
+		}, websiteService.purged)
+		require.Equal(t, []string{"/*"}, websiteService.purgedPaths)
+
+		require.Equal(t, StorageWebsiteTarget, deployResult.Kind)
+		require.Equal(t, []string{"https://contoso.z13.web.core.windows.net/"}, deployResult.Endpoints)
+		require.Equal(t, &storageWebsiteDeployResult{
+			Container: staticWebsiteContainer,
+			Uploaded:  2,
+			Deleted:   1,
+			Purged:    "site",
+		}, deployResult.Details)
+	})
+
+	t.Run("Container", func(t *testing.T) {
+		websiteService := &fakeStorageWebsiteService{}
+		serviceConfig := createStorageWebsiteServiceConfig(t)
+		serviceConfig.StorageWebsite = StorageWebsiteOptions{
+			Container: "origin",
+			Endpoints: []osutil.ExpandableString{osutil.NewExpandableString("https://${CDN_PROFILE}.azurefd.net")},
+		}
+
+		deployResult, err := deployStorageWebsite(t, serviceConfig, websiteService)
+		require.NoError(t, err)
+
+		require.Nil(t, websiteService.documents)
+		require.Equal(t, "origin", websiteService.container)
+		require.Nil(t, websiteService.purged)
+		require.Equal(t, []string{"https://contoso-cdn.azurefd.net"}, deployResult.Endpoints)
+	})
+
+	t.Run("CdnEndpointMissing", func(t *testing.T) {
+		serviceConfig := createStorageWebsiteServiceConfig(t)
+		serviceConfig.StorageWebsite.Cdn = &StorageWebsiteCdnOptions{
+			Profile: osutil.NewExpandableString("contoso-cdn"),
+		}
+
+		_, err := deployStorageWebsite(t, serviceConfig, &fakeStorageWebsiteService{})
+		require.ErrorContains(t, err, "set 'storageWebsite.cdn.profile' and 'storageWebsite.cdn.endpoint'")
+	})
+}
+
+func Test_storageWebsiteCacheControl(t *testing.T) {
+	rules := []StorageWebsiteCacheRule{
+		{Pattern: "/assets/**", Value: "immutable"},
+		{Pattern: "**/*.json", Value: "max-age=60"},
+	}
+
+	tests := map[string]string{
+		"assets/js/app.js":  "immutable",
+		"data/config.json":  "max-age=60",
+		"index.html":        "no-cache",
+		"docs/INDEX.HTML":   "no-cache",
+		"images/banner.png": "",
+	}
+
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			cacheControl, err := storageWebsiteCacheControl(name, rules)
+			require.NoError(t, err)
+			require.Equal(t, expected, cacheControl)
+		})
+	}
+
+	_, err := storageWebsiteCacheControl("index.html", []StorageWebsiteCacheRule{{Pattern: "[", Value: "x"}})
+	require.ErrorContains(t, err, "invalid cache control pattern '['")
+}
+
+func Test_StorageWebsiteDeployResult_ToString(t *testing.T) {
+	result := &storageWebsiteDeployResult{
+		Container: "$web",
+		Uploaded:  12,
+		Deleted:   2,
+		Purged:    "site",
+	}
+
+	require.Equal(t, "  - Files: $web (12 uploaded, 2 deleted)\n  - Purged: site\n", result.ToString("  "))
+}
+
+func createStorageWebsiteServiceConfig(t *testing.T) *ServiceConfig {
+	return createTestServiceConfig(t.TempDir(), StorageWebsiteTarget, ServiceLanguageJavaScript)
+}
+
+func packageStorageWebsite(
+	t *testing.T,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) (*ServicePackageResult, error) {
+	serviceTarget := NewStorageWebsiteTarget(createEnv(), &fakeStorageWebsiteService{})
+
+	return logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+			return serviceTarget.Package(context.Background(), serviceConfig, packageOutput, progress)
+		},
+	)
+}
+
+func deployStorageWebsite(
+	t *testing.T,
+	serviceConfig *ServiceConfig,
+	websiteService azcli.StorageWebsiteService,
+) (*ServiceDeployResult, error) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	sitePath := filepath.Join(serviceConfig.Path(), "dist")
+	require.NoError(t, os.MkdirAll(filepath.Join(sitePath, "assets"), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(sitePath, "index.html"), []byte("<html></html>"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(sitePath, "assets", "app.js"), []byte("console.log('hi')"), osutil.PermissionFile))
+
+	env := createEnv()
+	env.DotenvSet("CDN_PROFILE", "contoso-cdn")
+
+	serviceTarget := NewStorageWebsiteTarget(env, websiteService)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"STORAGE_ACCOUNT",
+		string(azapi.AzureResourceTypeStorageAccount),
+	)
+	packageResult := &ServicePackageResult{PackagePath: sitePath}
+
+	return logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope, progress)
+		},
+	)
+}
+
+// fakeStorageWebsiteService records the files uploaded to the storage account and the endpoint purged
+type fakeStorageWebsiteService struct {
+	siteUrl string
+	blobs   []string
+
+	documents   []string
+	container   string
+	uploaded    []*azcli.StorageWebsiteFile
+	deleted     []string
+	purged      *azcli.CdnEndpoint
+	purgedPaths []string
+}
+
+func (f *fakeStorageWebsiteService) EnableStaticWebsite(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	indexDocument string,
+	errorDocument string,
+) error {
+	f.documents = []string{indexDocument, errorDocument}
+	return nil
+}
+
+func (f *fakeStorageWebsiteService) GetStaticWebsiteUrl(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	storageAccountName string,
+) (string, error) {
+	return f.siteUrl, nil
+}
+
+func (f *fakeStorageWebsiteService) UploadStorageWebsiteFiles(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	containerName string,
+	files []*azcli.StorageWebsiteFile,
+) error {
+	f.container = containerName
+	f.uploaded = files
+	return nil
+}
+
+func (f *fakeStorageWebsiteService) ListStorageWebsiteFiles(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	containerName string,
+) ([]string, error) {
+	return f.blobs, nil
+}
+
+func (f *fakeStorageWebsiteService) DeleteStorageWebsiteFiles(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	containerName string,
+	names []string,
+) error {
+	f.deleted = names
+	return nil
+}
+
+func (f *fakeStorageWebsiteService) PurgeCdnEndpoint(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint *azcli.CdnEndpoint,
+	contentPaths []string,
+) error {
+	f.purged = endpoint
+	f.purgedPaths = contentPaths
+	return nil
+}
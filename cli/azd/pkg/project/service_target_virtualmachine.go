@@ -0,0 +1,445 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+const (
+	// The blob container the deployment packages are uploaded to when not configured
+	defaultVirtualMachineContainer = "azd-deployments"
+	// How long the deployment script may run on each virtual machine when not configured
+	defaultVirtualMachineTimeout = 15 * time.Minute
+	// How long the deployment script waits for the health check to pass when not configured
+	defaultVirtualMachineHealthCheckTimeout = 5 * time.Minute
+	// How long the virtual machines can download the deployment package after it is uploaded
+	virtualMachinePackageExpiry = 2 * time.Hour
+)
+
+// VirtualMachinePackageFormat is the archive format of the deployment package of a virtual machine service
+type VirtualMachinePackageFormat string
+
+const (
+	// A gzipped tarball, extracted with tar which is available on every Linux distribution
+	VirtualMachinePackageTar VirtualMachinePackageFormat = "tar"
+	// A zip archive, extracted with unzip which must be installed on the virtual machine
+	VirtualMachinePackageZip VirtualMachinePackageFormat = "zip"
+)
+
+// The virtual machine options of a service
+// The deployment package is uploaded to a blob container and the virtual machine, or each instance of the virtual
+// machine scale set in turn, downloads and extracts it with a run command, then restarts the app and verifies it is
+// healthy. Only Linux virtual machines are supported.
+type VirtualMachineOptions struct {
+	// The storage account the deployment package is uploaded to, ex) ${AZURE_STORAGE_ACCOUNT_NAME}
+	StorageAccount osutil.ExpandableString `yaml:"storageAccount,omitempty"`
+	// The blob container the deployment package is uploaded to. Defaults to azd-deployments
+	Container string `yaml:"container,omitempty"`
+	// The archive format of the deployment package. Defaults to tar
+	Package VirtualMachinePackageFormat `yaml:"package,omitempty"`
+	// The directory of the virtual machine the app is installed to. Defaults to /opt/<service name>
+	Path string `yaml:"path,omitempty"`
+	// The shell command that starts or restarts the app once it is extracted, run in the install directory,
+	// ex) systemctl restart api
+	Command string `yaml:"command,omitempty"`
+	// How long the deployment may run on each virtual machine, ex) 30m
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// The request sent from the virtual machine to the app once it is started
+	HealthCheck *VirtualMachineHealthCheckOptions `yaml:"healthCheck,omitempty"`
+	// The endpoints the app is reachable at, ex) ${SERVICE_API_URL} from the outputs of the infrastructure
+	Endpoints []osutil.ExpandableString `yaml:"endpoints,omitempty"`
+}
+
+// The health check the virtual machine runs against the app it deployed
+type VirtualMachineHealthCheckOptions struct {
+	// The local port the app listens on, ex) 8080
+	Port int `yaml:"port"`
+	// The path that is requested, ex) /health. Defaults to /
+	Path string `yaml:"path,omitempty"`
+	// How long the app has to respond with a 2xx status code, ex) 2m
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// virtualMachineDeployScript downloads and extracts the deployment package to a new release directory, points the
+// current symlink at it, restarts the app and waits for its health check. The previous releases are kept, except for
+// the oldest ones, so a bad release can be reverted by hand on the machine.
+const virtualMachineDeployScript = `#!/bin/bash
+set -euo pipefail
+
+work_dir="$(mktemp -d)"
+trap 'rm -rf "$work_dir"' EXIT
+
+echo "Downloading deployment package"
+curl -fsSL --retry 5 --retry-delay 2 -o "$work_dir/package" "$AZD_PACKAGE_URL"
+
+release_dir="$AZD_APP_PATH/releases/$AZD_RELEASE"
+mkdir -p "$release_dir"
+if [ "$AZD_PACKAGE_FORMAT" = "zip" ]; then
+  unzip -q -o "$work_dir/package" -d "$release_dir"
+else
+  tar -xzf "$work_dir/package" -C "$release_dir"
+fi
+
+ln -sfn "$release_dir" "$AZD_APP_PATH/current"
+ls -1dt "$AZD_APP_PATH"/releases/* | tail -n +6 | xargs -r rm -rf
+cd "$AZD_APP_PATH/current"
+
+if [ -n "${AZD_COMMAND:-}" ]; then
+  echo "Running: $AZD_COMMAND"
+  bash -c "$AZD_COMMAND"
+fi
+
+if [ -n "${AZD_HEALTH_CHECK_URL:-}" ]; then
+  echo "Waiting for $AZD_HEALTH_CHECK_URL"
+  deadline=$((SECONDS + AZD_HEALTH_CHECK_TIMEOUT))
+  until curl -fsS -o /dev/null --max-time 10 "$AZD_HEALTH_CHECK_URL"; do
+    if [ "$SECONDS" -ge "$deadline" ]; then
+      echo "health check $AZD_HEALTH_CHECK_URL did not succeed within $AZD_HEALTH_CHECK_TIMEOUT seconds" >&2
+      exit 1
+    fi
+    sleep 5
+  done
+fi
+
+echo "Deployed release $AZD_RELEASE"
+`
+
+type virtualMachineTarget struct {
+	env       *environment.Environment
+	vmService azcli.VirtualMachineService
+}
+
+// NewVirtualMachineTarget creates a new instance of the virtual machine service target
+func NewVirtualMachineTarget(
+	env *environment.Environment,
+	vmService azcli.VirtualMachineService,
+) ServiceTarget {
+	return &virtualMachineTarget{
+		env:       env,
+		vmService: vmService,
+	}
+}
+
+// Gets the required external tools
+func (t *virtualMachineTarget) RequiredExternalTools(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the virtual machine target
+func (t *virtualMachineTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Prepares an archive of the build output in the configured package format
+func (t *virtualMachineTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	var packagePath string
+	var err error
+
+	progress.SetProgress(NewServiceProgress("Compressing deployment artifacts"))
+	switch serviceConfig.VirtualMachine.Package {
+	case "", VirtualMachinePackageTar:
+		packagePath, err = createDeployableTarball(
+			serviceConfig.Project.Name, serviceConfig.Name, packageOutput.PackagePath)
+	case VirtualMachinePackageZip:
+		packagePath, err = createDeployableZip(serviceConfig.Project.Name, serviceConfig.Name, packageOutput.PackagePath)
+	default:
+		return nil, fmt.Errorf(
+			"unsupported package format '%s' of service '%s', use '%s' or '%s'",
+			serviceConfig.VirtualMachine.Package,
+			serviceConfig.Name,
+			VirtualMachinePackageTar,
+			VirtualMachinePackageZip,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServicePackageResult{
+		Build:       packageOutput.Build,
+		PackagePath: packagePath,
+	}, nil
+}
+
+// Uploads the deployment package and deploys it to the virtual machine, or to the instances of the virtual machine
+// scale set one at a time, stopping at the first instance that fails its health check
+func (t *virtualMachineTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := t.validateTargetResource(targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	defer os.Remove(packageOutput.PackagePath)
+
+	options := serviceConfig.VirtualMachine
+	storageAccount, err := options.StorageAccount.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to envsubst the storage account of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if storageAccount == "" {
+		return nil, fmt.Errorf(
+			"the storage account the deployment package is uploaded to is not set, set 'vm.storageAccount' of service '%s'",
+			serviceConfig.Name,
+		)
+	}
+
+	container := options.Container
+	if container == "" {
+		container = defaultVirtualMachineContainer
+	}
+
+	release := time.Now().UTC().Format("20060102-150405")
+	blobName := path.Join(serviceConfig.Name, release+packageExtension(packageOutput.PackagePath))
+
+	progress.SetProgress(NewServiceProgress("Uploading deployment package"))
+	packageUrl, err := t.vmService.UploadVirtualMachineArtifact(
+		ctx,
+		targetResource.SubscriptionId(),
+		storageAccount,
+		container,
+		blobName,
+		packageOutput.PackagePath,
+		virtualMachinePackageExpiry,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("uploading deployment package of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	command := virtualMachineDeployCommand(serviceConfig, release, packageUrl)
+
+	if strings.EqualFold(targetResource.ResourceType(), string(azapi.AzureResourceTypeVirtualMachineScaleSet)) {
+		err = t.deployScaleSet(ctx, targetResource, command, progress)
+	} else {
+		progress.SetProgress(
+			NewServiceProgress(fmt.Sprintf("Deploying to virtual machine %s", targetResource.ResourceName())))
+		var result *azcli.VirtualMachineRunCommandResult
+		result, err = t.vmService.RunVirtualMachineCommand(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			command,
+		)
+		if err == nil {
+			err = runCommandError(targetResource.ResourceName(), result)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceId := fmt.Sprintf(
+		"%s/providers/%s/%s",
+		azure.ResourceGroupRID(targetResource.SubscriptionId(), targetResource.ResourceGroupName()),
+		targetResource.ResourceType(),
+		targetResource.ResourceName(),
+	)
+
+	sdr := NewServiceDeployResult(
+		resourceId,
+		VirtualMachineTarget,
+		release,
+		endpoints,
+	)
+	sdr.Package = packageOutput
+
+	return sdr, nil
+}
+
+// deployScaleSet runs the deployment on the instances of the virtual machine scale set one at a time, so the other
+// instances keep serving while an instance is updated
+func (t *virtualMachineTarget) deployScaleSet(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	command azcli.VirtualMachineRunCommand,
+	progress *async.Progress[ServiceProgress],
+) error {
+	instanceIds, err := t.vmService.ListVirtualMachineScaleSetInstances(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(instanceIds) == 0 {
+		return fmt.Errorf("virtual machine scale set '%s' has no instances to deploy to", targetResource.ResourceName())
+	}
+
+	for idx, instanceId := range instanceIds {
+		progress.SetProgress(NewServiceProgress(
+			fmt.Sprintf("Deploying to instance %s (%d/%d)", instanceId, idx+1, len(instanceIds))))
+
+		result, err := t.vmService.RunVirtualMachineScaleSetCommand(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			instanceId,
+			command,
+		)
+		if err != nil {
+			return fmt.Errorf("deploying to instance '%s': %w", instanceId, err)
+		}
+
+		name := fmt.Sprintf("%s_%s", targetResource.ResourceName(), instanceId)
+		if err := runCommandError(name, result); err != nil {
+			return fmt.Errorf("%w, the remaining %d instances were not updated", err, len(instanceIds)-idx-1)
+		}
+	}
+
+	return nil
+}
+
+// Gets the configured endpoints of the virtual machine service
+func (t *virtualMachineTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	endpoints := []string{}
+	for _, endpoint := range serviceConfig.VirtualMachine.Endpoints {
+		value, err := endpoint.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst the endpoints of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		if value != "" {
+			endpoints = append(endpoints, value)
+		}
+	}
+
+	return endpoints, nil
+}
+
+func (t *virtualMachineTarget) validateTargetResource(targetResource *environment.TargetResource) error {
+	if !strings.EqualFold(targetResource.ResourceType(), string(azapi.AzureResourceTypeVirtualMachine)) &&
+		!strings.EqualFold(targetResource.ResourceType(), string(azapi.AzureResourceTypeVirtualMachineScaleSet)) {
+		return resourceTypeMismatchError(
+			targetResource.ResourceName(),
+			targetResource.ResourceType(),
+			azapi.AzureResourceTypeVirtualMachine,
+		)
+	}
+
+	return nil
+}
+
+// virtualMachineDeployCommand creates the run command that deploys the release of the service. The package URL
+// contains a SAS token and is passed as a protected parameter.
+func virtualMachineDeployCommand(
+	serviceConfig *ServiceConfig,
+	release string,
+	packageUrl string,
+) azcli.VirtualMachineRunCommand {
+	options := serviceConfig.VirtualMachine
+
+	appPath := options.Path
+	if appPath == "" {
+		appPath = path.Join("/opt", serviceConfig.Name)
+	}
+
+	packageFormat := options.Package
+	if packageFormat == "" {
+		packageFormat = VirtualMachinePackageTar
+	}
+
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = defaultVirtualMachineTimeout
+	}
+
+	healthCheckUrl := ""
+	healthCheckTimeout := defaultVirtualMachineHealthCheckTimeout
+	if options.HealthCheck != nil {
+		healthCheckUrl = fmt.Sprintf(
+			"http://localhost:%d/%s", options.HealthCheck.Port, strings.TrimPrefix(options.HealthCheck.Path, "/"))
+		if options.HealthCheck.Timeout != 0 {
+			healthCheckTimeout = options.HealthCheck.Timeout
+		}
+	}
+
+	return azcli.VirtualMachineRunCommand{
+		Name:   fmt.Sprintf("azd-deploy-%s", serviceConfig.Name),
+		Script: virtualMachineDeployScript,
+		Parameters: map[string]string{
+			"AZD_APP_PATH":             appPath,
+			"AZD_RELEASE":              release,
+			"AZD_PACKAGE_FORMAT":       string(packageFormat),
+			"AZD_COMMAND":              options.Command,
+			"AZD_HEALTH_CHECK_URL":     healthCheckUrl,
+			"AZD_HEALTH_CHECK_TIMEOUT": strconv.Itoa(int(healthCheckTimeout.Seconds())),
+		},
+		ProtectedParameters: map[string]string{
+			"AZD_PACKAGE_URL": packageUrl,
+		},
+		Timeout: timeout,
+	}
+}
+
+// runCommandError returns the error of a deployment script that did not succeed, including its standard error
+func runCommandError(vmName string, result *azcli.VirtualMachineRunCommandResult) error {
+	if result.Succeeded() {
+		return nil
+	}
+
+	message := strings.TrimSpace(result.Error)
+	if message == "" {
+		message = strings.TrimSpace(result.Output)
+	}
+
+	return fmt.Errorf(
+		"deploying to virtual machine '%s' failed (state: %s, exit code: %d): %s",
+		vmName,
+		result.State,
+		result.ExitCode,
+		message,
+	)
+}
+
+// packageExtension returns the extension of the deployment package, including the .tar.gz double extension
+func packageExtension(packagePath string) string {
+	if strings.HasSuffix(packagePath, ".tar.gz") {
+		return ".tar.gz"
+	}
+
+	return filepath.Ext(packagePath)
+}
@@ -0,0 +1,228 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_VirtualMachine_Package_Tarball(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	buildOutput := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(buildOutput, "bin"), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(buildOutput, "bin", "start.sh"), []byte("#!/bin/sh"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(buildOutput, "app.py"), []byte("print()"), osutil.PermissionFile))
+
+	serviceConfig := createTestServiceConfig("./src/api", VirtualMachineTarget, ServiceLanguagePython)
+	serviceTarget := NewVirtualMachineTarget(environment.New("test"), &fakeVirtualMachineService{})
+
+	packageResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+			return serviceTarget.Package(
+				*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: buildOutput}, progress)
+		},
+	)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(packageResult.PackagePath, ".tar.gz"))
+	defer os.Remove(packageResult.PackagePath)
+
+	file, err := os.Open(packageResult.PackagePath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	modes := map[string]os.FileMode{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		modes[hdr.Name] = hdr.FileInfo().Mode()
+	}
+
+	require.Len(t, modes, 3)
+	require.True(t, modes["bin/"].IsDir())
+	require.Equal(t, os.FileMode(0755), modes["bin/start.sh"].Perm())
+	require.Contains(t, modes, "app.py")
+}
+
+func Test_VirtualMachine_Deploy(t *testing.T) {
+	t.Run("VirtualMachine", func(t *testing.T) {
+		vmService := &fakeVirtualMachineService{}
+		serviceConfig := createTestServiceConfig("./src/api", VirtualMachineTarget, ServiceLanguagePython)
+		serviceConfig.VirtualMachine = VirtualMachineOptions{
+			StorageAccount: osutil.NewExpandableString("${AZURE_STORAGE_ACCOUNT_NAME}"),
+			Command:        "systemctl restart api",
+			HealthCheck:    &VirtualMachineHealthCheckOptions{Port: 8080, Path: "/health", Timeout: time.Minute},
+			Endpoints:      []osutil.ExpandableString{osutil.NewExpandableString("${SERVICE_API_URL}")},
+		}
+
+		deployResult, err := deployVirtualMachine(t, vmService, serviceConfig, azapi.AzureResourceTypeVirtualMachine)
+		require.NoError(t, err)
+
+		require.Equal(t, "stdata", vmService.uploadedAccount)
+		require.Equal(t, defaultVirtualMachineContainer, vmService.uploadedContainer)
+		require.True(t, strings.HasPrefix(vmService.uploadedBlob, "api/"))
+		require.True(t, strings.HasSuffix(vmService.uploadedBlob, ".tar.gz"))
+
+		require.Equal(t, []string{"VM_NAME"}, vmService.deployedTo)
+		command := vmService.commands[0]
+		require.Equal(t, "azd-deploy-api", command.Name)
+		require.Equal(t, "/opt/api", command.Parameters["AZD_APP_PATH"])
+		require.Equal(t, "tar", command.Parameters["AZD_PACKAGE_FORMAT"])
+		require.Equal(t, "systemctl restart api", command.Parameters["AZD_COMMAND"])
+		require.Equal(t, "http://localhost:8080/health", command.Parameters["AZD_HEALTH_CHECK_URL"])
+		require.Equal(t, "60", command.Parameters["AZD_HEALTH_CHECK_TIMEOUT"])
+		require.Equal(t, deployResult.Details, command.Parameters["AZD_RELEASE"])
+		require.Equal(t, "https://stdata/api?sas", command.ProtectedParameters["AZD_PACKAGE_URL"])
+		require.NotContains(t, command.Parameters, "AZD_PACKAGE_URL")
+		require.Equal(t, defaultVirtualMachineTimeout, command.Timeout)
+
+		require.Equal(t, []string{"https://api.contoso.com"}, deployResult.Endpoints)
+	})
+
+	t.Run("ScaleSetStopsAtUnhealthyInstance", func(t *testing.T) {
+		vmService := &fakeVirtualMachineService{
+			instances: []string{"0", "1", "2"},
+			failing:   "VMSS_NAME_1",
+		}
+		serviceConfig := createTestServiceConfig("./src/api", VirtualMachineTarget, ServiceLanguagePython)
+		serviceConfig.VirtualMachine.StorageAccount = osutil.NewExpandableString("stdata")
+
+		_, err := deployVirtualMachine(t, vmService, serviceConfig, azapi.AzureResourceTypeVirtualMachineScaleSet)
+		require.ErrorContains(t, err, "deploying to virtual machine 'VMSS_NAME_1' failed (state: Failed, exit code: 1)")
+		require.ErrorContains(t, err, "health check failed")
+		require.ErrorContains(t, err, "the remaining 1 instances were not updated")
+		require.Equal(t, []string{"VMSS_NAME_0", "VMSS_NAME_1"}, vmService.deployedTo)
+	})
+
+	t.Run("NoStorageAccount", func(t *testing.T) {
+		vmService := &fakeVirtualMachineService{}
+		serviceConfig := createTestServiceConfig("./src/api", VirtualMachineTarget, ServiceLanguagePython)
+
+		_, err := deployVirtualMachine(t, vmService, serviceConfig, azapi.AzureResourceTypeVirtualMachine)
+		require.ErrorContains(t, err, "set 'vm.storageAccount' of service 'api'")
+		require.Empty(t, vmService.deployedTo)
+	})
+
+	t.Run("WrongResourceType", func(t *testing.T) {
+		vmService := &fakeVirtualMachineService{}
+		serviceConfig := createTestServiceConfig("./src/api", VirtualMachineTarget, ServiceLanguagePython)
+
+		_, err := deployVirtualMachine(t, vmService, serviceConfig, azapi.AzureResourceTypeWebSite)
+		require.ErrorContains(t, err, "validating target resource")
+	})
+}
+
+func deployVirtualMachine(
+	t *testing.T,
+	vmService *fakeVirtualMachineService,
+	serviceConfig *ServiceConfig,
+	resourceType azapi.AzureResourceType,
+) (*ServiceDeployResult, error) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("test", map[string]string{
+		"AZURE_STORAGE_ACCOUNT_NAME": "stdata",
+		"SERVICE_API_URL":            "https://api.contoso.com",
+	})
+
+	packagePath := filepath.Join(t.TempDir(), "api.tar.gz")
+	require.NoError(t, os.WriteFile(packagePath, []byte{}, osutil.PermissionFile))
+
+	resourceName := "VM_NAME"
+	if resourceType == azapi.AzureResourceTypeVirtualMachineScaleSet {
+		resourceName = "VMSS_NAME"
+	}
+
+	serviceTarget := NewVirtualMachineTarget(env, vmService)
+	scope := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", resourceName, string(resourceType))
+
+	return logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(
+				*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: packagePath}, scope, progress)
+		},
+	)
+}
+
+// fakeVirtualMachineService records the deployments to the virtual machines instead of running them
+type fakeVirtualMachineService struct {
+	instances []string
+	// The name of the virtual machine whose deployment fails its health check
+	failing string
+
+	uploadedAccount   string
+	uploadedContainer string
+	uploadedBlob      string
+	deployedTo        []string
+	commands          []azcli.VirtualMachineRunCommand
+}
+
+func (f *fakeVirtualMachineService) UploadVirtualMachineArtifact(
+	ctx context.Context,
+	subscriptionId, storageAccountName, containerName, blobName, artifactPath string,
+	expiry time.Duration,
+) (string, error) {
+	f.uploadedAccount = storageAccountName
+	f.uploadedContainer = containerName
+	f.uploadedBlob = blobName
+	return "https://" + storageAccountName + "/api?sas", nil
+}
+
+func (f *fakeVirtualMachineService) RunVirtualMachineCommand(
+	ctx context.Context,
+	subscriptionId, resourceGroupName, vmName string,
+	command azcli.VirtualMachineRunCommand,
+) (*azcli.VirtualMachineRunCommandResult, error) {
+	return f.run(vmName, command), nil
+}
+
+func (f *fakeVirtualMachineService) ListVirtualMachineScaleSetInstances(
+	ctx context.Context,
+	subscriptionId, resourceGroupName, vmScaleSetName string,
+) ([]string, error) {
+	return f.instances, nil
+}
+
+func (f *fakeVirtualMachineService) RunVirtualMachineScaleSetCommand(
+	ctx context.Context,
+	subscriptionId, resourceGroupName, vmScaleSetName, instanceId string,
+	command azcli.VirtualMachineRunCommand,
+) (*azcli.VirtualMachineRunCommandResult, error) {
+	return f.run(vmScaleSetName+"_"+instanceId, command), nil
+}
+
+func (f *fakeVirtualMachineService) run(
+	name string,
+	command azcli.VirtualMachineRunCommand,
+) *azcli.VirtualMachineRunCommandResult {
+	f.deployedTo = append(f.deployedTo, name)
+	f.commands = append(f.commands, command)
+
+	if name == f.failing {
+		return &azcli.VirtualMachineRunCommandResult{State: "Failed", ExitCode: 1, Error: "health check failed\n"}
+	}
+
+	return &azcli.VirtualMachineRunCommandResult{State: "Succeeded", Output: "Deployed release"}
+}
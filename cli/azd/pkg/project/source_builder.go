@@ -0,0 +1,109 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/ko"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
+)
+
+type SourceBuilderKind string
+
+const (
+	// Builds the image of a Go main package with ko, ex) ./cmd/api set as the context
+	SourceBuilderKo SourceBuilderKind = "ko"
+	// Builds the image of a Maven project with the jib maven plugin
+	SourceBuilderJib SourceBuilderKind = "jib"
+)
+
+// validateSourceBuilder returns an error when the image is built from source with unsupported options
+func validateSourceBuilder(serviceConfig *ServiceConfig) error {
+	dockerOptions := serviceConfig.Docker
+	switch dockerOptions.SourceBuilder {
+	case "":
+		return nil
+	case SourceBuilderKo:
+	case SourceBuilderJib:
+		if serviceConfig.Language != ServiceLanguageJava {
+			return fmt.Errorf("building images with jib requires a java service, the service language is '%s'",
+				serviceConfig.Language)
+		}
+	default:
+		return fmt.Errorf(
+			"unsupported source builder '%s', supported builders are '%s' and '%s'",
+			dockerOptions.SourceBuilder, SourceBuilderKo, SourceBuilderJib,
+		)
+	}
+
+	if dockerOptions.RemoteBuild || dockerOptions.IsMultiPlatform() || dockerOptions.Buildpack ||
+		dockerOptions.Cache != nil {
+		return fmt.Errorf(
+			"building images with %s is not supported with remote builds, multi-platform builds, buildpacks or "+
+				"the build cache",
+			dockerOptions.SourceBuilder,
+		)
+	}
+
+	return nil
+}
+
+// sourceBuilderTool returns the tool the image is built from source with, nil when the image is built with docker
+func (p *dockerProject) sourceBuilderTool(serviceConfig *ServiceConfig) tools.ExternalTool {
+	switch serviceConfig.Docker.SourceBuilder {
+	case SourceBuilderKo:
+		return ko.NewCli(p.commandRunner)
+	case SourceBuilderJib:
+		mavenCli := maven.NewCli(p.commandRunner)
+		mavenCli.SetPath(serviceConfig.Path(), serviceConfig.Project.Path)
+		return mavenCli
+	default:
+		return nil
+	}
+}
+
+// sourceBuild builds the image from source without a Dockerfile with the source builder of the service and loads it
+// into the local docker daemon
+func (p *dockerProject) sourceBuild(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	dockerOptions DockerProjectOptions,
+	imageName string,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceBuildResult, error) {
+	progress.SetProgress(NewServiceProgress(
+		fmt.Sprintf("Building Docker image from source with %s", dockerOptions.SourceBuilder)))
+	previewer := p.console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: 8,
+			Title:        fmt.Sprintf("Docker (%s) Output", dockerOptions.SourceBuilder),
+		})
+
+	imageId := imageName
+	var err error
+	switch builder := p.sourceBuilderTool(serviceConfig).(type) {
+	case *ko.Cli:
+		// The context is the import path of the main package, ex) ./cmd/api
+		imageId, err = builder.Build(ctx, serviceConfig.Path(), dockerOptions.Context, dockerOptions.Platform, previewer)
+	case *maven.Cli:
+		err = builder.JibDockerBuild(ctx, serviceConfig.Path(), imageName, dockerOptions.Platform, previewer)
+	}
+	p.console.StopPreviewer(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("building container: %s with %s: %w", serviceConfig.Name, dockerOptions.SourceBuilder, err)
+	}
+
+	log.Printf("built image %s for %s with %s", imageId, serviceConfig.Name, dockerOptions.SourceBuilder)
+	return &ServiceBuildResult{
+		BuildOutputPath: imageId,
+		Details: &dockerBuildResult{
+			ImageId:   imageId,
+			ImageName: imageName,
+		},
+	}, nil
+}
@@ -0,0 +1,117 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DockerProject_Build_SourceBuilder(t *testing.T) {
+	t.Run("Ko", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		var buildArgs exec.RunArgs
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "ko build")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			buildArgs = args
+			return exec.NewRunResult(0, "ko.local/api-4f2d8a1b:latest\n", ""), nil
+		})
+
+		dockerProject := newTestDockerProject(mockContext)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageDocker)
+		serviceConfig.Project.Path = t.TempDir()
+		serviceConfig.Docker.SourceBuilder = SourceBuilderKo
+		serviceConfig.Docker.Context = "./cmd/api"
+
+		result, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+			return dockerProject.Build(*mockContext.Context, serviceConfig, nil, progress)
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "ko.local/api-4f2d8a1b:latest", result.BuildOutputPath)
+		require.Equal(t, serviceConfig.Path(), buildArgs.Cwd)
+		require.Equal(t, []string{"build", "./cmd/api", "--local", "--platform", docker.DefaultPlatform}, buildArgs.Args)
+	})
+
+	t.Run("Jib", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		var buildArgs exec.RunArgs
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "jib-maven-plugin")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			buildArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		dockerProject := newTestDockerProject(mockContext)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageJava)
+		serviceConfig.Project.Path = t.TempDir()
+		serviceConfig.Docker.SourceBuilder = SourceBuilderJib
+
+		// The maven wrapper of the project runs the build
+		mvnw := "mvnw"
+		if runtime.GOOS == "windows" {
+			mvnw = "mvnw.cmd"
+		}
+		require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+		err := os.WriteFile(filepath.Join(serviceConfig.Path(), mvnw), []byte{}, osutil.PermissionExecutableFile)
+		require.NoError(t, err)
+
+		result, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+			return dockerProject.Build(*mockContext.Context, serviceConfig, nil, progress)
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "test-app-api", result.BuildOutputPath)
+		require.Equal(t, filepath.Join(serviceConfig.Path(), mvnw), buildArgs.Cmd)
+		require.Contains(t, buildArgs.Args, "-Dimage=test-app-api")
+	})
+
+	tests := map[string]struct {
+		language      ServiceLanguageKind
+		builder       SourceBuilderKind
+		remoteBuild   bool
+		expectedError string
+	}{
+		"JibWithoutJava": {
+			language:      ServiceLanguageTypeScript,
+			builder:       SourceBuilderJib,
+			expectedError: "building images with jib requires a java service",
+		},
+		"UnsupportedBuilder": {
+			language:      ServiceLanguageDocker,
+			builder:       "bazel",
+			expectedError: "unsupported source builder 'bazel'",
+		},
+		"RemoteBuild": {
+			language:      ServiceLanguageDocker,
+			builder:       SourceBuilderKo,
+			remoteBuild:   true,
+			expectedError: "building images with ko is not supported with remote builds",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			dockerProject := newTestDockerProject(mockContext)
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, test.language)
+			serviceConfig.Docker.SourceBuilder = test.builder
+			serviceConfig.Docker.RemoteBuild = test.remoteBuild
+
+			_, err := dockerProject.Build(
+				*mockContext.Context, serviceConfig, nil, async.NewProgress[ServiceProgress]())
+			require.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package project
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/moby/patternmatcher"
+)
+
+// The service property holding the hash of the source of the last pushed image, ex) SERVICE_API_IMAGE_SOURCE_HASH
+const sourceHashServiceProperty = "IMAGE_SOURCE_HASH"
+
+// validateSkipUnchanged returns an error when unchanged builds are skipped with unsupported options
+func validateSkipUnchanged(serviceConfig *ServiceConfig) error {
+	if serviceConfig.Docker.SkipUnchanged && (serviceConfig.Docker.RemoteBuild || serviceConfig.Docker.IsMultiPlatform()) {
+		return fmt.Errorf("skipping unchanged builds is not supported with remote or multi-platform builds")
+	}
+
+	return nil
+}
+
+// unchangedImage returns the hash of the source of the service, along with the image pushed from the same source by
+// the last deployment when the source is unchanged since, ex) contoso.azurecr.io/todo/api:azd-deploy-1700000000
+func (p *dockerProject) unchangedImage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	dockerOptions DockerProjectOptions,
+) (string, string, error) {
+	// The image is pushed to the registry of the environment, so a changed registry requires a new push
+	registryName, _ := p.containerHelper.RegistryName(ctx, serviceConfig)
+
+	sourceHash, err := buildSourceHash(serviceConfig, dockerOptions, registryName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed hashing the source of service '%s', %w", serviceConfig.Name, err)
+	}
+
+	previousImage := p.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+	if previousImage == "" || p.env.GetServiceProperty(serviceConfig.Name, sourceHashServiceProperty) != sourceHash {
+		return sourceHash, "", nil
+	}
+
+	return sourceHash, previousImage, nil
+}
+
+// buildSourceHash returns the hash of the build context of the service, excluding the files ignored by .dockerignore,
+// and of the build options the image is built with
+// Inputs outside of the build context, ex) updates of the base image, are not detected.
+func buildSourceHash(serviceConfig *ServiceConfig, dockerOptions DockerProjectOptions, registryName string) (string, error) {
+	dockerfile := dockerOptions.Path
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(serviceConfig.Path(), dockerfile)
+	}
+
+	// Builds from source, ex) with buildpacks or ko, build the service directory rather than the build context
+	root := serviceConfig.Path()
+	if _, err := os.Stat(dockerfile); err == nil && !dockerOptions.Buildpack && dockerOptions.SourceBuilder == "" {
+		root = dockerOptions.Context
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(serviceConfig.Path(), root)
+		}
+	}
+
+	h := sha256.New()
+	for _, option := range []string{
+		registryName,
+		dockerOptions.Platform,
+		dockerOptions.Target,
+		string(dockerOptions.SourceBuilder),
+		strings.Join(dockerOptions.BuildArgs, "\n"),
+	} {
+		writeHashEntry(h, option)
+	}
+
+	// The Dockerfile can be outside of the build context, builds from source have no Dockerfile
+	if err := hashFile(h, dockerfile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	if err := hashBuildContext(h, root, dockerfile); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashBuildContext writes the paths and content of the files of the build context to the hash in lexical order
+func hashBuildContext(h hash.Hash, root string, dockerfile string) error {
+	ignores, err := containerregistry.ReadBuildContextIgnores(root, dockerfile)
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if filepath.Base(path) == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		contextPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		contextPath = filepath.ToSlash(contextPath)
+		ignore, err := patternmatcher.MatchesOrParentMatches(contextPath, ignores)
+		if err != nil || ignore {
+			return err
+		}
+
+		writeHashEntry(h, contextPath)
+		return hashFile(h, path)
+	})
+}
+
+// hashFile writes the content of the file to the hash
+func hashFile(h hash.Hash, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	writeHashEntry(h, "")
+	return nil
+}
+
+// writeHashEntry writes the value to the hash, separated from the next entry
+func writeHashEntry(h hash.Hash, value string) {
+	// Writes to a hash never fail
+	_, _ = io.WriteString(h, value+"\x00")
+}
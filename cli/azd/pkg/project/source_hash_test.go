@@ -0,0 +1,147 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildSourceHash(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = t.TempDir()
+	writeFile := func(name string, content string) {
+		path := filepath.Join(serviceConfig.Path(), name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+	}
+
+	writeFile("Dockerfile", "FROM node:20")
+	writeFile(".dockerignore", "node_modules\n*.log")
+	writeFile("src/index.js", "console.log('hello')")
+	writeFile("node_modules/express/index.js", "module.exports = {}")
+
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	sourceHash := func(dockerOptions DockerProjectOptions) string {
+		hash, err := buildSourceHash(serviceConfig, dockerOptions, "contoso.azurecr.io")
+		require.NoError(t, err)
+		return hash
+	}
+
+	hash := sourceHash(dockerOptions)
+	require.Len(t, hash, 64)
+
+	// Files ignored by .dockerignore do not change the hash
+	writeFile("node_modules/express/index.js", "module.exports = { version: 2 }")
+	writeFile("debug.log", "debug")
+	require.Equal(t, hash, sourceHash(dockerOptions))
+
+	// The registry and build options change the hash
+	otherRegistryHash, err := buildSourceHash(serviceConfig, dockerOptions, "fabrikam.azurecr.io")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, otherRegistryHash)
+
+	withBuildArgs := dockerOptions
+	withBuildArgs.BuildArgs = []string{"NODE_ENV=production"}
+	require.NotEqual(t, hash, sourceHash(withBuildArgs))
+
+	// Changes of the build context and the Dockerfile change the hash
+	writeFile("src/index.js", "console.log('hello world')")
+	changedHash := sourceHash(dockerOptions)
+	require.NotEqual(t, hash, changedHash)
+
+	writeFile("Dockerfile", "FROM node:22")
+	require.NotEqual(t, changedHash, sourceHash(dockerOptions))
+}
+
+func Test_DockerProject_SkipUnchanged(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockResults := setupDockerMocks(mockContext)
+
+	env := environment.NewWithValues("test", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+	})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+	dockerCli := docker.NewCli(mockContext.CommandRunner)
+	containerHelper := NewContainerHelper(
+		env, envManager, clock.NewMock(), mockContainerRegistryService, nil, dockerCli, mockContext.Console,
+		cloud.AzurePublic(), nil, nil, nil, nil, nil,
+	)
+	dockerProject := NewDockerProject(
+		env, dockerCli, containerHelper, mockinput.NewMockConsole(), mockContext.AlphaFeaturesManager,
+		mockContext.CommandRunner)
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = t.TempDir()
+	serviceConfig.Docker.SkipUnchanged = true
+	require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+	err := os.WriteFile(filepath.Join(serviceConfig.Path(), "Dockerfile"), []byte("FROM node:20"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	sourceHash, err := buildSourceHash(
+		serviceConfig, getDockerOptionsWithDefaults(serviceConfig.Docker), "contoso.azurecr.io")
+	require.NoError(t, err)
+
+	t.Run("Unchanged", func(t *testing.T) {
+		previousImage := "contoso.azurecr.io/test-app/api-test:azd-deploy-1"
+		env.SetServiceProperty("api", "IMAGE_NAME", previousImage)
+		env.SetServiceProperty("api", sourceHashServiceProperty, sourceHash)
+
+		// The image is neither built, tagged nor pushed
+		buildResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+			return dockerProject.Build(*mockContext.Context, serviceConfig, nil, progress)
+		})
+		require.NoError(t, err)
+
+		packageResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+			return dockerProject.Package(*mockContext.Context, serviceConfig, buildResult, progress)
+		})
+		require.NoError(t, err)
+
+		targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+		deployResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return containerHelper.Deploy(*mockContext.Context, serviceConfig, packageResult, targetResource, true, progress)
+		})
+		require.NoError(t, err)
+
+		require.Empty(t, mockResults)
+		require.Equal(t, previousImage, deployResult.Details.(*dockerDeployResult).RemoteImageTag)
+		require.Equal(t, previousImage, env.GetServiceProperty("api", "IMAGE_NAME"))
+	})
+
+	t.Run("Pushed", func(t *testing.T) {
+		packageResult := &ServicePackageResult{
+			Details: &dockerPackageResult{
+				TargetImage: "test-app/api-test:azd-deploy-2",
+				SourceHash:  "SOURCE_HASH",
+			},
+		}
+
+		targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+		_, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return containerHelper.Deploy(*mockContext.Context, serviceConfig, packageResult, targetResource, true, progress)
+		})
+		require.NoError(t, err)
+
+		// The source hash is stored along with the pushed image for the next deployment
+		require.Contains(t, mockResults, "docker-push")
+		require.Equal(t, "contoso.azurecr.io/test-app/api-test:azd-deploy-2", env.GetServiceProperty("api", "IMAGE_NAME"))
+		require.Equal(t, "SOURCE_HASH", env.GetServiceProperty("api", sourceHashServiceProperty))
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+)
+
+// The Azure Static Web Apps options of a service
+type StaticWebAppOptions struct {
+	// The preview environment the service is deployed to instead of the production environment, ex) 42 for a pull
+	// request or feature-login for a branch. Set by 'azd deploy --preview-env'.
+	Preview string `yaml:"-"`
+}
+
+// The preview environment name that derives the preview environment from the pull request or the branch
+const StaticWebAppPreviewAuto = "auto"
+
+var (
+	// The ref of the GitHub Actions workflows triggered by a pull request, ex) refs/pull/42/merge
+	githubPullRequestRefRegex = regexp.MustCompile(`^refs/pull/(\d+)/merge$`)
+	// The characters that are not allowed in the name of a static web app environment
+	invalidPreviewNameCharsRegex = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// StaticWebAppPreviewName returns the name of the static web app preview environment for the specified pull request
+// number or branch, ex) 42 or feature-login for the branch feature/login. When the value is 'auto', the preview
+// environment is derived from the pull request the CI pipeline runs for or, outside of pull requests,
+// from the branch checked out in the repository.
+func StaticWebAppPreviewName(
+	ctx context.Context,
+	gitCli *git.Cli,
+	repositoryPath string,
+	value string,
+	getenv func(string) string,
+) (string, error) {
+	if value != StaticWebAppPreviewAuto {
+		return parseStaticWebAppPreviewName(value)
+	}
+
+	source := previewSourceFromCI(getenv)
+	if source == "" {
+		branch, err := gitCli.GetCurrentBranch(ctx, repositoryPath)
+		if errors.Is(err, git.ErrNotRepository) {
+			return "", errors.New(
+				"the preview environment can't be derived outside of a git repository, specify the preview environment name")
+		} else if err != nil {
+			return "", err
+		}
+
+		if branch == "" {
+			return "", errors.New(
+				"the preview environment can't be derived from a detached HEAD, specify the preview environment name")
+		}
+
+		source = branch
+	}
+
+	return parseStaticWebAppPreviewName(source)
+}
+
+// previewSourceFromCI returns the pull request number or the branch the CI pipeline runs for, empty when not running
+// in GitHub Actions or Azure Pipelines
+func previewSourceFromCI(getenv func(string) string) string {
+	// GitHub Actions
+	if match := githubPullRequestRefRegex.FindStringSubmatch(getenv("GITHUB_REF")); match != nil {
+		return match[1]
+	}
+
+	if branch := getenv("GITHUB_REF_NAME"); branch != "" && getenv("GITHUB_REF_TYPE") == "branch" {
+		return branch
+	}
+
+	// Azure Pipelines
+	if number := getenv("SYSTEM_PULLREQUEST_PULLREQUESTNUMBER"); number != "" {
+		return number
+	}
+
+	if branch, has := strings.CutPrefix(getenv("BUILD_SOURCEBRANCH"), "refs/heads/"); has {
+		return branch
+	}
+
+	return ""
+}
+
+// parseStaticWebAppPreviewName converts a pull request number or a branch name to the name of a static web app preview
+// environment, ex) feature/Login becomes feature-login
+func parseStaticWebAppPreviewName(value string) (string, error) {
+	name := strings.Trim(invalidPreviewNameCharsRegex.ReplaceAllString(strings.ToLower(value), "-"), "-")
+	if name == "" {
+		return "", fmt.Errorf("'%s' is not a valid preview environment name", value)
+	}
+
+	if name == DefaultStaticWebAppEnvironmentName {
+		return "", fmt.Errorf("the preview environment name '%s' is reserved for the production environment", name)
+	}
+
+	return name, nil
+}
+
+// DeleteStaticWebAppPreviews deletes the named preview environment of the services hosted on Azure Static Web Apps,
+// leaving the production environment and the other resources of the application in place. The names of the services
+// the preview environment was deleted for are returned.
+func DeleteStaticWebAppPreviews(
+	ctx context.Context,
+	projectConfig *ProjectConfig,
+	env *environment.Environment,
+	resourceManager ResourceManager,
+	azCli azcli.AzCli,
+	previewName string,
+) ([]string, error) {
+	deleted := []string{}
+	for _, serviceConfig := range projectConfig.Services {
+		if serviceConfig.Host != StaticWebAppTarget {
+			continue
+		}
+
+		targetResource, err := resourceManager.GetTargetResource(ctx, env.GetSubscriptionId(), serviceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("getting target resource of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		if err := azCli.DeleteStaticWebAppEnvironment(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			previewName,
+		); err != nil {
+			return nil, err
+		}
+
+		deleted = append(deleted, serviceConfig.Name)
+	}
+
+	if len(deleted) == 0 {
+		return nil, fmt.Errorf("the project has no service hosted on '%s' with preview environments", StaticWebAppTarget)
+	}
+
+	return deleted, nil
+}
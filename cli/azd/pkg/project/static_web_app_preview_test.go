@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StaticWebAppPreviewName(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        string
+		env          map[string]string
+		branch       string
+		expectedName string
+		expectedErr  string
+	}{
+		{
+			name:         "PullRequestNumber",
+			value:        "42",
+			expectedName: "42",
+		},
+		{
+			name:         "Branch",
+			value:        "feature/Add_Login",
+			expectedName: "feature-add-login",
+		},
+		{
+			name:        "Reserved",
+			value:       "default",
+			expectedErr: "is reserved for the production environment",
+		},
+		{
+			name:        "Invalid",
+			value:       "//",
+			expectedErr: "is not a valid preview environment name",
+		},
+		{
+			name:         "AutoGitHubPullRequest",
+			value:        StaticWebAppPreviewAuto,
+			env:          map[string]string{"GITHUB_REF": "refs/pull/42/merge", "GITHUB_REF_NAME": "42/merge"},
+			expectedName: "42",
+		},
+		{
+			name:         "AutoGitHubBranch",
+			value:        StaticWebAppPreviewAuto,
+			env:          map[string]string{"GITHUB_REF_NAME": "feature/login", "GITHUB_REF_TYPE": "branch"},
+			expectedName: "feature-login",
+		},
+		{
+			name:         "AutoAzurePipelinesPullRequest",
+			value:        StaticWebAppPreviewAuto,
+			env:          map[string]string{"SYSTEM_PULLREQUEST_PULLREQUESTNUMBER": "7"},
+			expectedName: "7",
+		},
+		{
+			name:         "AutoAzurePipelinesBranch",
+			value:        StaticWebAppPreviewAuto,
+			env:          map[string]string{"BUILD_SOURCEBRANCH": "refs/heads/feature/login"},
+			expectedName: "feature-login",
+		},
+		{
+			name:         "AutoGitBranch",
+			value:        StaticWebAppPreviewAuto,
+			branch:       "bugfix/Header",
+			expectedName: "bugfix-header",
+		},
+		{
+			name:        "AutoDetachedHead",
+			value:       StaticWebAppPreviewAuto,
+			expectedErr: "can't be derived from a detached HEAD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "branch --show-current")
+			}).Respond(exec.NewRunResult(0, tt.branch+"\n", ""))
+
+			getenv := func(key string) string { return tt.env[key] }
+			name, err := StaticWebAppPreviewName(
+				*mockContext.Context, git.NewCli(mockContext.CommandRunner), t.TempDir(), tt.value, getenv)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedName, name)
+		})
+	}
+}
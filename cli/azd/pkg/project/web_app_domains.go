@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// A custom domain of an app service, bound with an App Service managed certificate when the service is deployed
+type AppServiceCustomDomainOptions struct {
+	// The host name of the domain, ex) api.contoso.com or ${API_DOMAIN}. Domains whose name is empty in the environment
+	// are skipped
+	Name osutil.ExpandableString `yaml:"name"`
+	// The environments the domain is bound in, ex) prod. The domain is bound in all environments when empty
+	Environments []string `yaml:"environments,omitempty"`
+	// The type of the DNS record mapping the domain to the app service, CNAME or A for apex domains. Defaults to CNAME
+	DnsRecordType string `yaml:"dnsRecordType,omitempty"`
+}
+
+// validateAppServiceCustomDomains returns an error when the custom domains of the service are invalid
+func validateAppServiceCustomDomains(serviceConfig *ServiceConfig) error {
+	for _, domain := range serviceConfig.AppService.CustomDomains {
+		if domain.Name.Empty() {
+			return fmt.Errorf("a custom domain of service '%s' is missing its name", serviceConfig.Name)
+		}
+
+		switch strings.ToUpper(domain.DnsRecordType) {
+		case "", "CNAME", "A":
+		default:
+			return fmt.Errorf(
+				"unsupported DNS record type '%s' of a custom domain of service '%s', supported values are CNAME and A",
+				domain.DnsRecordType,
+				serviceConfig.Name,
+			)
+		}
+	}
+
+	return nil
+}
+
+// appServiceCustomDomains returns the custom domains of the service bound in the environment
+func appServiceCustomDomains(
+	serviceConfig *ServiceConfig,
+	env *environment.Environment,
+) ([]*azcli.AzCliAppServiceCustomDomain, error) {
+	domains := []*azcli.AzCliAppServiceCustomDomain{}
+	for _, domain := range serviceConfig.AppService.CustomDomains {
+		hostName, err := customDomainHostName(serviceConfig, domain.Name, domain.Environments, env)
+		if err != nil {
+			return nil, err
+		}
+
+		if hostName == "" {
+			continue
+		}
+
+		domains = append(domains, &azcli.AzCliAppServiceCustomDomain{
+			HostName:      hostName,
+			DnsRecordType: domain.DnsRecordType,
+		})
+	}
+
+	return domains, nil
+}
+
+// bindCustomDomains binds the custom domains of the service in the environment to the app service. The domains are
+// bound to the production slot, which serves them once a deployment slot is swapped.
+func (st *appServiceTarget) bindCustomDomains(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	domains, err := appServiceCustomDomains(serviceConfig, st.env)
+	if err != nil || len(domains) == 0 {
+		return err
+	}
+
+	if err := st.cli.BindAppServiceCustomDomains(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		domains,
+	); err != nil {
+		return fmt.Errorf("failed binding custom domains: %w", err)
+	}
+
+	return nil
+}
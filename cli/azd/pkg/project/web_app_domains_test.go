@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AppServiceCustomDomains(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+	serviceConfig.AppService.CustomDomains = []AppServiceCustomDomainOptions{
+		{Name: osutil.NewExpandableString("${WEB_DOMAIN}"), DnsRecordType: "A"},
+		{Name: osutil.NewExpandableString("www.contoso.com"), Environments: []string{"prod"}},
+	}
+
+	env := environment.NewWithValues("dev", map[string]string{
+		"WEB_DOMAIN": "Contoso.com",
+	})
+	domains, err := appServiceCustomDomains(serviceConfig, env)
+	require.NoError(t, err)
+	require.Equal(t, []*azcli.AzCliAppServiceCustomDomain{
+		{HostName: "contoso.com", DnsRecordType: "A"},
+	}, domains)
+
+	// Domains whose name is not set in the environment are skipped
+	env = environment.NewWithValues("prod", nil)
+	domains, err = appServiceCustomDomains(serviceConfig, env)
+	require.NoError(t, err)
+	require.Equal(t, []*azcli.AzCliAppServiceCustomDomain{
+		{HostName: "www.contoso.com"},
+	}, domains)
+}
+
+func Test_ValidateAppServiceCustomDomains(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+	serviceConfig.AppService.CustomDomains = []AppServiceCustomDomainOptions{
+		{Name: osutil.NewExpandableString("api.contoso.com"), DnsRecordType: "cname"},
+	}
+	require.NoError(t, validateAppServiceCustomDomains(serviceConfig))
+
+	serviceConfig.AppService.CustomDomains[0].DnsRecordType = "AAAA"
+	require.ErrorContains(t, validateAppServiceCustomDomains(serviceConfig), "unsupported DNS record type 'AAAA'")
+
+	serviceConfig.AppService.CustomDomains = []AppServiceCustomDomainOptions{{}}
+	require.ErrorContains(t, validateAppServiceCustomDomains(serviceConfig), "is missing its name")
+}
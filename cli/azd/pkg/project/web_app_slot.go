@@ -0,0 +1,175 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/sethvargo/go-retry"
+)
+
+const (
+	// The time the deployment waits for the deployment slot to respond before the swap when not configured
+	defaultSlotWarmupTimeout = 5 * time.Minute
+	// The interval between the warmup requests sent to the deployment slot
+	slotWarmupInterval = 10 * time.Second
+	// The timeout of each warmup request, cold starts of a web app can take a while
+	slotWarmupRequestTimeout = time.Minute
+)
+
+// The deployment slot options of the services hosted on App Service or Azure Functions
+// When a slot is configured, the service is deployed to the slot, the slot is warmed up and then swapped with the
+// production slot, so the new version only receives the production traffic once it is started.
+type SlotOptions struct {
+	// The deployment slot the service is deployed to before the slot is swapped with the production slot,
+	// ex) staging or ${AZURE_APP_SLOT} to use a different slot for each environment.
+	// The service is deployed to the production slot when not set or empty.
+	Slot osutil.ExpandableString `yaml:"slot,omitempty"`
+	// The path of the deployment slot that is probed before the swap, ex) /api/health. The swap only happens once
+	// the path responds with a 2xx status code. Defaults to the root of the slot.
+	HealthCheckPath string `yaml:"healthCheckPath,omitempty"`
+	// How long the deployment waits for the deployment slot to respond before the swap, ex) 10m
+	HealthCheckTimeout time.Duration `yaml:"healthCheckTimeout,omitempty"`
+	// The request sent to the deployment slot once it is warmed up, the slot is not swapped when the request fails
+	SmokeTest *SlotSmokeTestOptions `yaml:"smokeTest,omitempty"`
+	// Keeps the new version in the deployment slot without swapping it with the production slot, set by
+	// 'azd deploy --no-swap' to promote the new version manually
+	NoSwap bool `yaml:"-"`
+}
+
+// The smoke test of a deployment slot
+type SlotSmokeTestOptions struct {
+	// The path of the deployment slot that is requested, ex) /api/orders
+	Path string `yaml:"path"`
+	// The expected response status code. Defaults to any 2xx status code
+	ExpectedStatus int `yaml:"expectedStatus,omitempty"`
+}
+
+// webAppSlot is a deployment slot of a web app, ex) an app service or a function app
+type webAppSlot struct {
+	name string
+	// Gets the host names of the slot
+	hostNames func(ctx context.Context) ([]string, error)
+	// Swaps the slot with the production slot
+	swap func(ctx context.Context) error
+}
+
+// resolveSlot returns the name of the deployment slot of the service in the environment, empty when the service is
+// deployed to the production slot
+func resolveSlot(serviceConfig *ServiceConfig, options SlotOptions, env *environment.Environment) (string, error) {
+	slot, err := options.Slot.Envsubst(env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed to envsubst the slot of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	return slot, nil
+}
+
+// deploySlot warms up the deployment slot the service was deployed to, runs the smoke test and swaps the slot with the
+// production slot, unless the swap is skipped. The endpoints of the slot are returned.
+func deploySlot(
+	ctx context.Context,
+	transporter policy.Transporter,
+	slot webAppSlot,
+	options SlotOptions,
+	progress *async.Progress[ServiceProgress],
+) ([]string, error) {
+	hostNames, err := slot.hostNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching slot properties: %w", err)
+	}
+
+	endpoints := make([]string, len(hostNames))
+	for idx, hostName := range hostNames {
+		endpoints[idx] = fmt.Sprintf("Slot %s: https://%s/", slot.name, hostName)
+	}
+
+	if len(hostNames) > 0 {
+		slotUrl := fmt.Sprintf("https://%s/", hostNames[0])
+
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Warming up slot: %s", slot.name)))
+		if err := warmupSlot(ctx, transporter, slot.name, slotUrl, options); err != nil {
+			return nil, err
+		}
+
+		if options.SmokeTest != nil {
+			progress.SetProgress(NewServiceProgress(fmt.Sprintf("Running smoke test of slot: %s", slot.name)))
+			if err := smokeTestSlot(ctx, transporter, slot.name, slotUrl, options.SmokeTest); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if options.NoSwap {
+		return endpoints, nil
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Swapping slot %s with production", slot.name)))
+	if err := slot.swap(ctx); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// warmupSlot sends requests to the health check path of the deployment slot until it responds with a 2xx status code,
+// so the new version is started and healthy before it receives the production traffic
+func warmupSlot(
+	ctx context.Context,
+	transporter policy.Transporter,
+	slotName string,
+	slotUrl string,
+	options SlotOptions,
+) error {
+	endpointUrl, err := url.JoinPath(slotUrl, options.HealthCheckPath)
+	if err != nil {
+		return fmt.Errorf("failed constructing slot health check url, %w", err)
+	}
+
+	timeout := options.HealthCheckTimeout
+	if timeout == 0 {
+		timeout = defaultSlotWarmupTimeout
+	}
+
+	err = retry.Do(
+		ctx,
+		retry.WithMaxDuration(timeout, retry.NewConstant(slotWarmupInterval)),
+		func(ctx context.Context) error {
+			return retry.RetryableError(probeEndpoint(ctx, transporter, endpointUrl, 0, slotWarmupRequestTimeout))
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("slot '%s' is not healthy, the slot was not swapped with production: %w", slotName, err)
+	}
+
+	return nil
+}
+
+// smokeTestSlot sends a single request to the smoke test path of the warmed up deployment slot
+func smokeTestSlot(
+	ctx context.Context,
+	transporter policy.Transporter,
+	slotName string,
+	slotUrl string,
+	smokeTest *SlotSmokeTestOptions,
+) error {
+	endpointUrl, err := url.JoinPath(slotUrl, smokeTest.Path)
+	if err != nil {
+		return fmt.Errorf("failed constructing slot smoke test url, %w", err)
+	}
+
+	err = probeEndpoint(ctx, transporter, endpointUrl, smokeTest.ExpectedStatus, slotWarmupRequestTimeout)
+	if err != nil {
+		return fmt.Errorf("smoke test of slot '%s' failed, the slot was not swapped with production: %w", slotName, err)
+	}
+
+	return nil
+}
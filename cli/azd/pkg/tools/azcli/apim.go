@@ -4,13 +4,32 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 )
 
 type AzCliApim struct {
-	Id       string `json:"id"`
-	Name     string `json:"name"`
-	Location string `json:"location"`
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	GatewayUrl string `json:"gatewayUrl"`
+}
+
+// AzCliApimApi is the definition of an API imported to an API Management service
+type AzCliApimApi struct {
+	// The display name of the API
+	DisplayName string
+	// The URL suffix of the API, relative to the gateway URL of the API Management service
+	Path string
+	// The format of the definition, ex) openapi, openapi+json or swagger-json
+	Format string
+	// The content of the definition
+	Value string
+	// The URL of the backend the API forwards the requests to, the servers of the definition are used when empty
+	ServiceUrl string
+	// Whether the API can only be called with a subscription key, the API Management default is used when nil
+	SubscriptionRequired *bool
 }
 
 func (cli *azCli) GetApim(
@@ -29,13 +48,74 @@ func (cli *azCli) GetApim(
 		return nil, fmt.Errorf("getting api management service: %w", err)
 	}
 
+	gatewayUrl := ""
+	if apim.Properties != nil {
+		gatewayUrl = convert.ToValueWithDefault(apim.Properties.GatewayURL, "")
+	}
+
 	return &AzCliApim{
-		Id:       *apim.ID,
-		Name:     *apim.Name,
-		Location: *apim.Location,
+		Id:         *apim.ID,
+		Name:       *apim.Name,
+		Location:   *apim.Location,
+		GatewayUrl: gatewayUrl,
 	}, nil
 }
 
+// ImportApimApi creates or updates the API of the API Management service from the definition, replacing the
+// operations of the API with the operations of the definition
+func (cli *azCli) ImportApimApi(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	apimName string,
+	apiId string,
+	api AzCliApimApi,
+) error {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := armapimanagement.NewAPIClient(subscriptionId, credential, cli.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating api client: %w", err)
+	}
+
+	properties := &armapimanagement.APICreateOrUpdateProperties{
+		Path:                 to.Ptr(api.Path),
+		Format:               to.Ptr(armapimanagement.ContentFormat(api.Format)),
+		Value:                to.Ptr(api.Value),
+		Protocols:            []*armapimanagement.Protocol{to.Ptr(armapimanagement.ProtocolHTTPS)},
+		SubscriptionRequired: api.SubscriptionRequired,
+	}
+
+	if api.DisplayName != "" {
+		properties.DisplayName = to.Ptr(api.DisplayName)
+	}
+
+	if api.ServiceUrl != "" {
+		properties.ServiceURL = to.Ptr(api.ServiceUrl)
+	}
+
+	poller, err := apiClient.BeginCreateOrUpdate(
+		ctx,
+		resourceGroupName,
+		apimName,
+		apiId,
+		armapimanagement.APICreateOrUpdateParameter{Properties: properties},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("starting import of api '%s': %w", apiId, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("importing api '%s': %w", apiId, err)
+	}
+
+	return nil
+}
+
 func (cli *azCli) PurgeApim(ctx context.Context, subscriptionId string, apimName string, location string) error {
 	apimClient, err := cli.createApimDeletedClient(ctx, subscriptionId)
 
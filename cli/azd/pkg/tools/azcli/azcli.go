@@ -39,6 +39,14 @@ type AzCli interface {
 	PurgeCognitiveAccount(ctx context.Context, subscriptionId, location, resourceGroup, accountName string) error
 	GetApim(
 		ctx context.Context, subscriptionId string, resourceGroupName string, apimName string) (*AzCliApim, error)
+	ImportApimApi(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		apimName string,
+		apiId string,
+		api AzCliApimApi,
+	) error
 	DeployAppServiceZip(
 		ctx context.Context,
 		subscriptionId string,
@@ -47,6 +55,29 @@ type AzCli interface {
 		deployZipFile io.ReadSeeker,
 		logProgress func(string),
 	) (*string, error)
+	DeployAppServiceSlotZip(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		slotName string,
+		deployZipFile io.ReadSeeker,
+		logProgress func(string),
+	) (*string, error)
+	SwapAppServiceSlot(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		slotName string,
+	) error
+	BindAppServiceCustomDomains(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		domains []*AzCliAppServiceCustomDomain,
+	) error
 	DeployFunctionAppUsingZipFile(
 		ctx context.Context,
 		subscriptionID string,
@@ -61,6 +92,38 @@ type AzCli interface {
 		resourceGroup string,
 		funcName string,
 	) (*AzCliFunctionAppProperties, error)
+	DeployFunctionAppSlotUsingZipFile(
+		ctx context.Context,
+		subscriptionID string,
+		resourceGroup string,
+		funcName string,
+		slotName string,
+		deployZipFile io.ReadSeeker,
+		remoteBuild bool,
+	) (*string, error)
+	GetFunctionAppSlotProperties(
+		ctx context.Context,
+		subscriptionID string,
+		resourceGroup string,
+		funcName string,
+		slotName string,
+	) (*AzCliFunctionAppProperties, error)
+	SwapFunctionAppSlot(
+		ctx context.Context,
+		subscriptionID string,
+		resourceGroup string,
+		funcName string,
+		slotName string,
+	) error
+	// UpdateFunctionAppFlexScale updates the scale and concurrency of a function app on a Flex Consumption plan,
+	// such as its always ready instances
+	UpdateFunctionAppFlexScale(
+		ctx context.Context,
+		subscriptionID string,
+		resourceGroup string,
+		funcName string,
+		scale *FunctionAppFlexScale,
+	) error
 	// CreateOrUpdateServicePrincipal creates a service principal using a given name and returns a JSON object which
 	// may be used by tools which understand the `AZURE_CREDENTIALS` format (i.e. the `sdk-auth` format). The service
 	// principal is assigned a given role. If an existing principal exists with the given name,
@@ -71,6 +134,13 @@ type AzCli interface {
 		resourceGroupName string,
 		applicationName string,
 	) (*AzCliAppServiceProperties, error)
+	GetAppServiceSlotProperties(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		slotName string,
+	) (*AzCliAppServiceProperties, error)
 	GetStaticWebAppProperties(
 		ctx context.Context,
 		subscriptionID string,
@@ -85,6 +155,13 @@ type AzCli interface {
 		appName string,
 		environmentName string,
 	) (*AzCliStaticWebAppEnvironmentProperties, error)
+	DeleteStaticWebAppEnvironment(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		environmentName string,
+	) error
 }
 
 func NewAzCli(
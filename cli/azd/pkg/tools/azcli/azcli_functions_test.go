@@ -6,6 +6,7 @@ package azcli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
 	"testing"
@@ -132,6 +133,104 @@ func Test_DeployFunctionAppUsingZipFile(t *testing.T) {
 	})
 }
 
+func Test_DeployFunctionAppSlotUsingZipFile(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+
+		registerInfoMocks(mockContext, &ran)
+		registerSlotInfoMocks(mockContext)
+		registerPollingMocks(mockContext, &ran)
+
+		// The zip file is deployed to the repository host of the slot
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && request.URL.Host == "FUNC_APP_NAME_SCM_HOST" &&
+				strings.Contains(request.URL.Path, "/api/zipdeploy")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.Fail(t, "the zip file must not be deployed to the production slot")
+			return nil, nil
+		})
+
+		res, err := azCli.DeployFunctionAppSlotUsingZipFile(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"FUNC_APP_NAME",
+			"staging",
+			bytes.NewReader([]byte{}),
+			false,
+		)
+
+		require.NoError(t, err)
+		require.True(t, ran)
+		require.NotNil(t, res)
+	})
+}
+
+func Test_SwapFunctionAppSlot(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := newAzCliFromMockContext(mockContext)
+
+	var slotEntity *armappservice.CsmSlotEntity
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/sites/FUNC_APP_NAME/slotsswap")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.NoError(t, json.NewDecoder(request.Body).Decode(&slotEntity))
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	err := azCli.SwapFunctionAppSlot(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP_ID",
+		"FUNC_APP_NAME",
+		"staging",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "staging", *slotEntity.TargetSlot)
+}
+
+func registerSlotInfoMocks(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/sites/FUNC_APP_NAME/slots/staging")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(
+			request,
+			http.StatusOK,
+			armappservice.WebAppsClientGetSlotResponse{
+				Site: armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						HostNameSSLStates: []*armappservice.HostNameSSLState{
+							{
+								HostType: to.Ptr(armappservice.HostTypeRepository),
+								Name:     to.Ptr("FUNC_APP_NAME_STAGING_SCM_HOST"),
+							},
+						},
+						//nolint:lll
+						ServerFarmID: to.Ptr(
+							"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP_ID/providers/Microsoft.Web/serverfarms/FUNC_APP_PLAN_NAME",
+						),
+					},
+				},
+			},
+		)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			request.URL.Host == "FUNC_APP_NAME_STAGING_SCM_HOST" &&
+			strings.Contains(request.URL.Path, "/api/zipdeploy")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response, _ := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+		response.Header.Set("Location", "https://FUNC_APP_NAME_STAGING_SCM_HOST/deployments/latest")
+
+		return response, nil
+	})
+}
+
 func registerInfoMocks(mockContext *mocks.MockContext, ran *bool) {
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		//nolint:lll
@@ -243,3 +342,98 @@ func registerPollingMocks(mockContext *mocks.MockContext, ran *bool) {
 		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, completeStatus)
 	})
 }
+
+func Test_UpdateFunctionAppFlexScale(t *testing.T) {
+	sitePath := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP_ID/providers/Microsoft.Web/sites/FUNC_APP_NAME"
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && request.URL.Path == sitePath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"properties": map[string]any{
+					"functionAppConfig": map[string]any{
+						"deployment": map[string]any{"storage": map[string]any{"type": "blobContainer"}},
+						"runtime":    map[string]any{"name": "python", "version": "3.11"},
+						"scaleAndConcurrency": map[string]any{
+							"maximumInstanceCount": 100,
+							"instanceMemoryMB":     2048,
+						},
+					},
+				},
+			})
+		})
+
+		var body map[string]any
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPatch && request.URL.Path == sitePath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+
+			return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+		})
+
+		err := azCli.UpdateFunctionAppFlexScale(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"FUNC_APP_NAME",
+			&FunctionAppFlexScale{
+				AlwaysReady:          []*FunctionAppAlwaysReady{{Name: "http", InstanceCount: 2}},
+				MaximumInstanceCount: 40,
+			},
+		)
+		require.NoError(t, err)
+
+		// The deployment storage, the runtime and the settings that are not set are kept
+		require.Equal(t, map[string]any{
+			"properties": map[string]any{
+				"functionAppConfig": map[string]any{
+					"deployment": map[string]any{"storage": map[string]any{"type": "blobContainer"}},
+					"runtime":    map[string]any{"name": "python", "version": "3.11"},
+					"scaleAndConcurrency": map[string]any{
+						"alwaysReady":          []any{map[string]any{"name": "http", "instanceCount": float64(2)}},
+						"maximumInstanceCount": float64(40),
+						"instanceMemoryMB":     float64(2048),
+					},
+				},
+			},
+		}, body)
+	})
+
+	t.Run("NotFlexConsumption", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && request.URL.Path == sitePath
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"properties": map[string]any{}})
+		})
+
+		err := azCli.UpdateFunctionAppFlexScale(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"FUNC_APP_NAME",
+			&FunctionAppFlexScale{MaximumInstanceCount: 40},
+		)
+		require.ErrorContains(t, err, "function app 'FUNC_APP_NAME' is not hosted on a Flex Consumption plan")
+	})
+}
+
+func Test_isFlexConsumptionPlan(t *testing.T) {
+	require.True(t, isFlexConsumptionPlan(&armappservice.Plan{
+		SKU: &armappservice.SKUDescription{Tier: to.Ptr("FlexConsumption")},
+	}))
+	require.True(t, isFlexConsumptionPlan(&armappservice.Plan{SKU: &armappservice.SKUDescription{Name: to.Ptr("FC1")}}))
+	require.False(t, isFlexConsumptionPlan(&armappservice.Plan{
+		SKU: &armappservice.SKUDescription{Name: to.Ptr("Y1"), Tier: to.Ptr("Dynamic")},
+	}))
+	require.False(t, isFlexConsumptionPlan(&armappservice.Plan{}))
+}
@@ -95,6 +95,84 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 	})
 }
 
+func Test_DeployAppServiceSlotZip(t *testing.T) {
+	ran := false
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := newAzCliFromMockContext(mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/sites/LINUX_WEB_APP_NAME/slots/staging")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WebAppsClientGetSlotResponse{
+			Site: armappservice.Site{
+				Kind: to.Ptr("app,linux"),
+				Properties: &armappservice.SiteProperties{
+					DefaultHostName: to.Ptr("LINUX_WEB_APP_NAME-staging.azurewebsites.net"),
+					SiteConfig: &armappservice.SiteConfig{
+						LinuxFxVersion: to.Ptr("Python"),
+					},
+					HostNameSSLStates: []*armappservice.HostNameSSLState{
+						{
+							HostType: to.Ptr(armappservice.HostTypeRepository),
+							Name:     to.Ptr("LINUX_WEB_APP_NAME_STAGING_SCM_HOST"),
+						},
+					},
+				},
+			},
+		})
+	})
+
+	// The deployment to the slot is polled instead of being tracked with the deployment status api
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			request.URL.Host == "LINUX_WEB_APP_NAME_STAGING_SCM_HOST" &&
+			strings.Contains(request.URL.Path, "/api/zipdeploy")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response, _ := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+		response.Header.Set("Location", "https://LINUX_WEB_APP_NAME_STAGING_SCM_HOST/deployments/latest")
+
+		return response, nil
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/deployments/latest")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		ran = true
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DeployStatusResponse{
+			DeployStatus: azsdk.DeployStatus{
+				Status:     http.StatusOK,
+				StatusText: "OK",
+				Complete:   true,
+			},
+		})
+	})
+
+	res, err := azCli.DeployAppServiceSlotZip(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP_ID",
+		"LINUX_WEB_APP_NAME",
+		"staging",
+		bytes.NewReader([]byte{}),
+		func(s string) {},
+	)
+
+	require.NoError(t, err)
+	require.True(t, ran)
+	require.Equal(t, "OK", *res)
+
+	props, err := azCli.GetAppServiceSlotProperties(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP_ID",
+		"LINUX_WEB_APP_NAME",
+		"staging",
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"LINUX_WEB_APP_NAME-staging.azurewebsites.net"}, props.HostNames)
+}
+
 func registerIsLinuxWebAppMocks(mockContext *mocks.MockContext, ran *bool) {
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		return request.Method == http.MethodGet &&
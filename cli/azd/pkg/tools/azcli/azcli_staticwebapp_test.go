@@ -173,3 +173,27 @@ func Test_GetStaticWebAppApiKey(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func Test_DeleteStaticWebAppEnvironment(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := newAzCliFromMockContext(mockContext)
+	ran := false
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/staticSites/appName/builds/feature-login")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		ran = true
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	err := azCli.DeleteStaticWebAppEnvironment(
+		*mockContext.Context,
+		"subID",
+		"resourceGroupID",
+		"appName",
+		"feature-login",
+	)
+	require.NoError(t, err)
+	require.True(t, ran)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azcli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const webAppDomainsSitePath = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/" +
+	"sites/WEB_APP_NAME"
+
+func Test_BindAppServiceCustomDomains(t *testing.T) {
+	domains := []*AzCliAppServiceCustomDomain{
+		{HostName: "api.contoso.com"},
+	}
+
+	t.Run("Bind", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerWebAppDomainsSiteMock(mockContext)
+		registerWebAppDomainsAnalysisMock(mockContext, armappservice.DNSVerificationTestResultPassed)
+		bindings := registerWebAppDomainsBindingMock(mockContext)
+
+		var issued *armappservice.AppCertificate
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.HasSuffix(request.URL.Path, "/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/certificates")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.AppCertificateCollection{
+				Value: []*armappservice.AppCertificate{},
+			})
+		})
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut &&
+				strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/certificates/mc-api-contoso-com")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(request.Body).Decode(&issued); err != nil {
+				return nil, err
+			}
+
+			issued.Properties.Thumbprint = to.Ptr("THUMBPRINT")
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, issued)
+		})
+
+		azCli := newAzCliFromMockContext(mockContext)
+		err := azCli.BindAppServiceCustomDomains(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "WEB_APP_NAME", domains)
+		require.NoError(t, err)
+
+		require.NotNil(t, issued)
+		require.Equal(t, "api.contoso.com", *issued.Properties.CanonicalName)
+		require.Equal(t, "SERVER_FARM_ID", *issued.Properties.ServerFarmID)
+
+		// The host name is added before the certificate is issued, then bound with the certificate
+		require.Len(t, *bindings, 2)
+		require.Equal(t, armappservice.SSLStateDisabled, *(*bindings)[0].Properties.SSLState)
+		require.Equal(t, armappservice.SSLStateSniEnabled, *(*bindings)[1].Properties.SSLState)
+		require.Equal(t, "THUMBPRINT", *(*bindings)[1].Properties.Thumbprint)
+	})
+
+	t.Run("AlreadyBound", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerWebAppDomainsSiteMock(mockContext, &armappservice.HostNameSSLState{
+			HostType: to.Ptr(armappservice.HostTypeStandard),
+			Name:     to.Ptr("api.contoso.com"),
+			SSLState: to.Ptr(armappservice.SSLStateSniEnabled),
+		})
+		bindings := registerWebAppDomainsBindingMock(mockContext)
+
+		azCli := newAzCliFromMockContext(mockContext)
+		err := azCli.BindAppServiceCustomDomains(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "WEB_APP_NAME", domains)
+		require.NoError(t, err)
+		require.Empty(t, *bindings)
+	})
+
+	t.Run("NotVerified", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerWebAppDomainsSiteMock(mockContext)
+		registerWebAppDomainsAnalysisMock(mockContext, armappservice.DNSVerificationTestResultFailed)
+		bindings := registerWebAppDomainsBindingMock(mockContext)
+
+		azCli := newAzCliFromMockContext(mockContext)
+		err := azCli.BindAppServiceCustomDomains(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "WEB_APP_NAME", domains)
+		require.ErrorIs(t, err, azure.ErrCustomDomainNotVerified)
+		require.ErrorContains(t, err, "CNAME api.contoso.com -> WEB_APP_NAME.azurewebsites.net")
+		require.ErrorContains(t, err, "TXT asuid.api.contoso.com -> VERIFICATION_ID")
+		require.Empty(t, *bindings)
+	})
+}
+
+func Test_GetAppServiceProperties_CustomDomains(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	registerWebAppDomainsSiteMock(mockContext,
+		&armappservice.HostNameSSLState{
+			HostType: to.Ptr(armappservice.HostTypeStandard),
+			Name:     to.Ptr("api.contoso.com"),
+			SSLState: to.Ptr(armappservice.SSLStateSniEnabled),
+		},
+		// Domains not bound with a certificate are not served over https
+		&armappservice.HostNameSSLState{
+			HostType: to.Ptr(armappservice.HostTypeStandard),
+			Name:     to.Ptr("www.contoso.com"),
+			SSLState: to.Ptr(armappservice.SSLStateDisabled),
+		},
+	)
+
+	azCli := newAzCliFromMockContext(mockContext)
+	props, err := azCli.GetAppServiceProperties(*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "WEB_APP_NAME")
+	require.NoError(t, err)
+	require.Equal(t, []string{"WEB_APP_NAME.azurewebsites.net", "api.contoso.com"}, props.HostNames)
+}
+
+func registerWebAppDomainsSiteMock(mockContext *mocks.MockContext, states ...*armappservice.HostNameSSLState) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, webAppDomainsSitePath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WebAppsClientGetResponse{
+			Site: armappservice.Site{
+				Location: to.Ptr("eastus2"),
+				Properties: &armappservice.SiteProperties{
+					DefaultHostName:            to.Ptr("WEB_APP_NAME.azurewebsites.net"),
+					CustomDomainVerificationID: to.Ptr("VERIFICATION_ID"),
+					ServerFarmID:               to.Ptr("SERVER_FARM_ID"),
+					HostNameSSLStates:          states,
+				},
+			},
+		})
+	})
+}
+
+func registerWebAppDomainsAnalysisMock(mockContext *mocks.MockContext, result armappservice.DNSVerificationTestResult) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, webAppDomainsSitePath+"/analyzeCustomHostname")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.CustomHostnameAnalysisResult{
+			Properties: &armappservice.CustomHostnameAnalysisResultProperties{
+				CustomDomainVerificationTest: to.Ptr(result),
+			},
+		})
+	})
+}
+
+func registerWebAppDomainsBindingMock(mockContext *mocks.MockContext) *[]*armappservice.HostNameBinding {
+	bindings := []*armappservice.HostNameBinding{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut &&
+			strings.HasSuffix(request.URL.Path, webAppDomainsSitePath+"/hostNameBindings/api.contoso.com")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		var binding *armappservice.HostNameBinding
+		if err := json.NewDecoder(request.Body).Decode(&binding); err != nil {
+			return nil, err
+		}
+
+		bindings = append(bindings, binding)
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, binding)
+	})
+
+	return &bindings
+}
@@ -0,0 +1,508 @@
+package azcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+const (
+	// The ARM api version of the Batch resource provider
+	batchArmApiVersion = "2024-02-01"
+	// The api version of the Batch service api
+	batchApiVersion = "2024-02-01.19.0"
+	// The scope of the tokens of the Batch service api
+	batchScope = "https://batch.core.windows.net/.default"
+)
+
+// ErrBatchJobNotFound is returned when the job doesn't exist in the Batch account
+var ErrBatchJobNotFound = errors.New("batch job not found")
+
+// BatchService uploads application packages to Batch accounts and submits the jobs and tasks that run them
+type BatchService interface {
+	// Get the endpoint of the Batch service api of the account, ex) contoso.eastus.batch.azure.com
+	GetBatchAccountEndpoint(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		accountName string,
+	) (string, error)
+	// Get the pool of the Batch account
+	GetBatchPool(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		accountName string,
+		poolName string,
+	) (*BatchPool, error)
+	// Upload the zip package as a new version of the application, creating the application when it doesn't exist,
+	// and activate it. The account must have a linked storage account.
+	UploadBatchApplicationPackage(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		accountName string,
+		applicationId string,
+		version string,
+		packagePath string,
+	) error
+	// Get the job of the Batch account, ErrBatchJobNotFound is returned when the job doesn't exist
+	GetBatchJob(ctx context.Context, subscriptionId string, endpoint string, jobId string) (*BatchJob, error)
+	// Add the job to the Batch account
+	AddBatchJob(ctx context.Context, subscriptionId string, endpoint string, job *BatchJob) error
+	// Update the priority and the constraints of the job
+	UpdateBatchJob(ctx context.Context, subscriptionId string, endpoint string, job *BatchJob) error
+	// Add the task to the job
+	AddBatchTask(ctx context.Context, subscriptionId string, endpoint string, jobId string, task *BatchTask) error
+	// Get the task of the job
+	GetBatchTask(
+		ctx context.Context,
+		subscriptionId string,
+		endpoint string,
+		jobId string,
+		taskId string,
+	) (*BatchTask, error)
+}
+
+// BatchPool is a pool of compute nodes of a Batch account
+type BatchPool struct {
+	Name              string
+	ProvisioningState string
+	AllocationState   string
+	// The SKU of the Batch node agent, ex) batch.node.ubuntu 22.04
+	NodeAgentSkuId   string
+	DedicatedNodes   int
+	LowPriorityNodes int
+	AutoScale        bool
+}
+
+// BatchJob is a job of a Batch account, the tasks of a job run on its pool
+type BatchJob struct {
+	Id          string               `json:"id"`
+	DisplayName string               `json:"displayName,omitempty"`
+	Priority    int                  `json:"priority"`
+	PoolInfo    *BatchJobPoolInfo    `json:"poolInfo,omitempty"`
+	Constraints *BatchJobConstraints `json:"constraints,omitempty"`
+	// The state of the job, ex) active, disabled, terminating, completed
+	State string `json:"state,omitempty"`
+}
+
+type BatchJobPoolInfo struct {
+	PoolId string `json:"poolId"`
+}
+
+type BatchJobConstraints struct {
+	// The ISO 8601 duration the job may run, ex) PT1H
+	MaxWallClockTime  string `json:"maxWallClockTime,omitempty"`
+	MaxTaskRetryCount int    `json:"maxTaskRetryCount"`
+}
+
+// BatchTask is a task of a Batch job
+type BatchTask struct {
+	Id                           string                              `json:"id"`
+	DisplayName                  string                              `json:"displayName,omitempty"`
+	CommandLine                  string                              `json:"commandLine"`
+	EnvironmentSettings          []*BatchEnvironmentSetting          `json:"environmentSettings,omitempty"`
+	ApplicationPackageReferences []*BatchApplicationPackageReference `json:"applicationPackageReferences,omitempty"`
+	Constraints                  *BatchTaskConstraints               `json:"constraints,omitempty"`
+	// The state of the task, ex) active, preparing, running, completed
+	State         string                  `json:"state,omitempty"`
+	ExecutionInfo *BatchTaskExecutionInfo `json:"executionInfo,omitempty"`
+}
+
+type BatchEnvironmentSetting struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type BatchApplicationPackageReference struct {
+	ApplicationId string `json:"applicationId"`
+	Version       string `json:"version,omitempty"`
+}
+
+type BatchTaskConstraints struct {
+	// The ISO 8601 duration the task may run, ex) PT30M
+	MaxWallClockTime  string `json:"maxWallClockTime,omitempty"`
+	MaxTaskRetryCount int    `json:"maxTaskRetryCount"`
+}
+
+type BatchTaskExecutionInfo struct {
+	ExitCode   *int `json:"exitCode,omitempty"`
+	RetryCount int  `json:"retryCount"`
+	// The result of the completed task, success or failure
+	Result      string                `json:"result,omitempty"`
+	FailureInfo *BatchTaskFailureInfo `json:"failureInfo,omitempty"`
+}
+
+type BatchTaskFailureInfo struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type batchService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// Creates a new instance of the BatchService
+func NewBatchService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) BatchService {
+	return &batchService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+func (bs *batchService) GetBatchAccountEndpoint(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+) (string, error) {
+	var batchAccount struct {
+		Properties struct {
+			AccountEndpoint string `json:"accountEndpoint"`
+		} `json:"properties"`
+	}
+
+	path := bs.accountPath(subscriptionId, resourceGroupName, accountName)
+	if err := bs.armDo(ctx, subscriptionId, http.MethodGet, path, nil, &batchAccount); err != nil {
+		return "", fmt.Errorf("getting batch account: %w", err)
+	}
+
+	if batchAccount.Properties.AccountEndpoint == "" {
+		return "", fmt.Errorf("batch account '%s' has no account endpoint", accountName)
+	}
+
+	return batchAccount.Properties.AccountEndpoint, nil
+}
+
+func (bs *batchService) GetBatchPool(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+	poolName string,
+) (*BatchPool, error) {
+	var pool struct {
+		Name       string `json:"name"`
+		Properties struct {
+			ProvisioningState       string `json:"provisioningState"`
+			AllocationState         string `json:"allocationState"`
+			CurrentDedicatedNodes   int    `json:"currentDedicatedNodes"`
+			CurrentLowPriorityNodes int    `json:"currentLowPriorityNodes"`
+			DeploymentConfiguration struct {
+				VirtualMachineConfiguration struct {
+					NodeAgentSkuId string `json:"nodeAgentSkuId"`
+				} `json:"virtualMachineConfiguration"`
+			} `json:"deploymentConfiguration"`
+			ScaleSettings struct {
+				AutoScale *struct{} `json:"autoScale"`
+			} `json:"scaleSettings"`
+		} `json:"properties"`
+	}
+
+	path, err := url.JoinPath(bs.accountPath(subscriptionId, resourceGroupName, accountName), "pools", poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bs.armDo(ctx, subscriptionId, http.MethodGet, path, nil, &pool); err != nil {
+		return nil, fmt.Errorf("getting pool '%s': %w", poolName, err)
+	}
+
+	return &BatchPool{
+		Name:              pool.Name,
+		ProvisioningState: pool.Properties.ProvisioningState,
+		AllocationState:   pool.Properties.AllocationState,
+		NodeAgentSkuId:    pool.Properties.DeploymentConfiguration.VirtualMachineConfiguration.NodeAgentSkuId,
+		DedicatedNodes:    pool.Properties.CurrentDedicatedNodes,
+		LowPriorityNodes:  pool.Properties.CurrentLowPriorityNodes,
+		AutoScale:         pool.Properties.ScaleSettings.AutoScale != nil,
+	}, nil
+}
+
+func (bs *batchService) UploadBatchApplicationPackage(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	accountName string,
+	applicationId string,
+	version string,
+	packagePath string,
+) error {
+	applicationPath, err := url.JoinPath(
+		bs.accountPath(subscriptionId, resourceGroupName, accountName), "applications", applicationId)
+	if err != nil {
+		return err
+	}
+
+	// Creating the application is idempotent, the versions of an existing application are kept
+	application := map[string]any{"properties": map[string]any{"allowUpdates": true}}
+	if err := bs.armDo(ctx, subscriptionId, http.MethodPut, applicationPath, application, nil); err != nil {
+		return fmt.Errorf("creating application '%s': %w", applicationId, err)
+	}
+
+	versionPath, err := url.JoinPath(applicationPath, "versions", version)
+	if err != nil {
+		return err
+	}
+
+	var applicationPackage struct {
+		Properties struct {
+			StorageUrl string `json:"storageUrl"`
+		} `json:"properties"`
+	}
+
+	err = bs.armDo(ctx, subscriptionId, http.MethodPut, versionPath, map[string]any{}, &applicationPackage)
+	if err != nil {
+		return fmt.Errorf("creating version '%s' of application '%s': %w", version, applicationId, err)
+	}
+
+	// The storage url of the package is a SAS url of a blob of the storage account linked to the Batch account
+	client, err := blockblob.NewClientWithNoCredential(applicationPackage.Properties.StorageUrl, nil)
+	if err != nil {
+		return fmt.Errorf("creating blob client: %w", err)
+	}
+
+	file, err := os.Open(packagePath)
+	if err != nil {
+		return fmt.Errorf("opening package: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := client.UploadFile(ctx, file, nil); err != nil {
+		return fmt.Errorf("uploading version '%s' of application '%s': %w", version, applicationId, err)
+	}
+
+	activatePath, err := url.JoinPath(versionPath, "activate")
+	if err != nil {
+		return err
+	}
+
+	err = bs.armDo(ctx, subscriptionId, http.MethodPost, activatePath, map[string]any{"format": "zip"}, nil)
+	if err != nil {
+		return fmt.Errorf("activating version '%s' of application '%s': %w", version, applicationId, err)
+	}
+
+	return nil
+}
+
+func (bs *batchService) GetBatchJob(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	jobId string,
+) (*BatchJob, error) {
+	var job BatchJob
+	path := fmt.Sprintf("jobs/%s", url.PathEscape(jobId))
+	if err := bs.do(ctx, subscriptionId, endpoint, http.MethodGet, path, nil, &job); err != nil {
+		var responseErr *azcore.ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound {
+			return nil, ErrBatchJobNotFound
+		}
+
+		return nil, fmt.Errorf("getting job '%s': %w", jobId, err)
+	}
+
+	return &job, nil
+}
+
+func (bs *batchService) AddBatchJob(ctx context.Context, subscriptionId string, endpoint string, job *BatchJob) error {
+	if err := bs.do(ctx, subscriptionId, endpoint, http.MethodPost, "jobs", job, nil); err != nil {
+		return fmt.Errorf("adding job '%s': %w", job.Id, err)
+	}
+
+	return nil
+}
+
+func (bs *batchService) UpdateBatchJob(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	job *BatchJob,
+) error {
+	// The pool of a job can only change while the job is disabled, so only the priority and the constraints are patched
+	body := map[string]any{"priority": job.Priority}
+	if job.Constraints != nil {
+		body["constraints"] = job.Constraints
+	}
+
+	path := fmt.Sprintf("jobs/%s", url.PathEscape(job.Id))
+	if err := bs.do(ctx, subscriptionId, endpoint, http.MethodPatch, path, body, nil); err != nil {
+		return fmt.Errorf("updating job '%s': %w", job.Id, err)
+	}
+
+	return nil
+}
+
+func (bs *batchService) AddBatchTask(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	jobId string,
+	task *BatchTask,
+) error {
+	path := fmt.Sprintf("jobs/%s/tasks", url.PathEscape(jobId))
+	if err := bs.do(ctx, subscriptionId, endpoint, http.MethodPost, path, task, nil); err != nil {
+		return fmt.Errorf("adding task '%s' to job '%s': %w", task.Id, jobId, err)
+	}
+
+	return nil
+}
+
+func (bs *batchService) GetBatchTask(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	jobId string,
+	taskId string,
+) (*BatchTask, error) {
+	var task BatchTask
+	path := fmt.Sprintf("jobs/%s/tasks/%s", url.PathEscape(jobId), url.PathEscape(taskId))
+	if err := bs.do(ctx, subscriptionId, endpoint, http.MethodGet, path, nil, &task); err != nil {
+		return nil, fmt.Errorf("getting task '%s' of job '%s': %w", taskId, jobId, err)
+	}
+
+	return &task, nil
+}
+
+func (bs *batchService) accountPath(subscriptionId string, resourceGroupName string, accountName string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.Batch/batchAccounts/%s",
+		azure.ResourceGroupRID(subscriptionId, resourceGroupName),
+		accountName,
+	)
+}
+
+// armDo sends the request to the Batch resource provider through the ARM REST API, as the Batch SDK is not
+// referenced by azd
+func (bs *batchService) armDo(
+	ctx context.Context,
+	subscriptionId string,
+	method string,
+	path string,
+	body any,
+	result any,
+) error {
+	credential, err := bs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := armruntime.NewPipeline(
+		"azd-batch", internal.Version, credential, runtime.PipelineOptions{}, bs.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if bs.armClientOptions != nil {
+		if service, has := bs.armClientOptions.Cloud.Services[cloud.ResourceManager]; has && service.Endpoint != "" {
+			endpoint = service.Endpoint
+		}
+	}
+
+	requestUrl, err := url.JoinPath(endpoint, path)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(ctx, method, fmt.Sprintf("%s?api-version=%s", requestUrl, batchArmApiVersion))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if body != nil {
+		if err := runtime.MarshalAsJSON(req, body); err != nil {
+			return err
+		}
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated, http.StatusNoContent) {
+		return runtime.NewResponseError(response)
+	}
+
+	if result != nil {
+		return runtime.UnmarshalAsJSON(response, result)
+	}
+
+	return nil
+}
+
+// do sends the request to the Batch service api of the account, authenticated with the token of the subscription
+// credential
+func (bs *batchService) do(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	method string,
+	path string,
+	body any,
+	result any,
+) error {
+	credential, err := bs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	var clientOptions policy.ClientOptions
+	if bs.armClientOptions != nil {
+		clientOptions = bs.armClientOptions.ClientOptions
+	}
+
+	pipeline := runtime.NewPipeline("azd-batch", internal.Version, runtime.PipelineOptions{
+		PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(credential, []string{batchScope}, nil)},
+	}, &clientOptions)
+
+	requestUrl := fmt.Sprintf("https://%s/%s?api-version=%s", endpoint, path, batchApiVersion)
+	req, err := runtime.NewRequest(ctx, method, requestUrl)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if body != nil {
+		if err := runtime.MarshalAsJSON(req, body); err != nil {
+			return err
+		}
+
+		// The Batch service api requires the odata flavor of the json content type
+		req.Raw().Header.Set("Content-Type", "application/json; odata=minimalmetadata")
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated, http.StatusNoContent) {
+		return runtime.NewResponseError(response)
+	}
+
+	if result != nil {
+		return runtime.UnmarshalAsJSON(response, result)
+	}
+
+	return nil
+}
@@ -10,15 +10,19 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
@@ -47,6 +51,26 @@ type ContainerRegistryService interface {
 	Credentials(ctx context.Context, subscriptionId string, loginServer string) (*DockerCredentials, error)
 	// Gets a list of container registries for the specified subscription
 	GetContainerRegistries(ctx context.Context, subscriptionId string) ([]*armcontainerregistry.Registry, error)
+	// Gets the tags of the specified repository, newest first
+	GetRepositoryTags(
+		ctx context.Context, subscriptionId string, loginServer string, repository string,
+	) ([]*RepositoryTag, error)
+	// Deletes the tag from the specified repository
+	DeleteRepositoryTag(
+		ctx context.Context, subscriptionId string, loginServer string, repository string, tag string,
+	) error
+	// Deletes the manifest with the digest from the specified repository, along with its tags
+	DeleteRepositoryManifest(
+		ctx context.Context, subscriptionId string, loginServer string, repository string, digest string,
+	) error
+	// Imports the image of the source registry into the specified container registry
+	ImportImage(
+		ctx context.Context, subscriptionId string, loginServer string, source ImageImportSource, targetTags []string,
+	) error
+	// Generates a password of the token of the specified container registry, ex) the client token of a connected registry
+	GenerateTokenCredentials(
+		ctx context.Context, subscriptionId string, loginServer string, tokenName string,
+	) (*DockerCredentials, error)
 }
 
 type containerRegistryService struct {
@@ -54,8 +78,28 @@ type containerRegistryService struct {
 	docker             *docker.Cli
 	armClientOptions   *arm.ClientOptions
 	coreClientOptions  *azcore.ClientOptions
+
+	// The ACR refresh tokens and docker logins are cached for the duration of an azd invocation, ex) for the services
+	// of a project pushing to the same registry.
+	cacheMu sync.Mutex
+	// The ACR refresh tokens by subscription and login server
+	tokenCache map[string]cachedAcrToken
+	// The password the container runtime was last logged into each login server with
+	dockerLogins map[string]string
+}
+
+// cachedAcrToken is an ACR refresh token along with the time it is refreshed at, ahead of its expiry
+type cachedAcrToken struct {
+	token     *acrToken
+	refreshOn time.Time
 }
 
+// The lifetime of ACR refresh tokens without an expiry claim, and the time ahead of the expiry they are refreshed at
+const (
+	defaultAcrTokenLifetime = time.Hour
+	acrTokenRefreshMargin   = 5 * time.Minute
+)
+
 // Creates a new instance of the ContainerRegistryService
 func NewContainerRegistryService(
 	credentialProvider account.SubscriptionCredentialProvider,
@@ -68,6 +112,8 @@ func NewContainerRegistryService(
 		docker:             docker,
 		armClientOptions:   armClientOptions,
 		coreClientOptions:  coreClientOptions,
+		tokenCache:         map[string]cachedAcrToken{},
+		dockerLogins:       map[string]string{},
 	}
 }
 
@@ -102,6 +148,16 @@ func (crs *containerRegistryService) Login(ctx context.Context, subscriptionId s
 		return err
 	}
 
+	// The container runtime is already logged in when the token is unchanged since the last login, ex) by the
+	// deployment of another service
+	crs.cacheMu.Lock()
+	loggedIn := crs.dockerLogins[dockerCreds.LoginServer] == dockerCreds.Password
+	crs.cacheMu.Unlock()
+	if loggedIn {
+		log.Printf("reusing the login of the container runtime to registry '%s'", loginServer)
+		return nil
+	}
+
 	err = crs.docker.Login(ctx, dockerCreds.LoginServer, dockerCreds.Username, dockerCreds.Password)
 	if err != nil {
 		return fmt.Errorf(
@@ -110,6 +166,10 @@ func (crs *containerRegistryService) Login(ctx context.Context, subscriptionId s
 			err)
 	}
 
+	crs.cacheMu.Lock()
+	crs.dockerLogins[dockerCreds.LoginServer] = dockerCreds.Password
+	crs.cacheMu.Unlock()
+
 	return nil
 }
 
@@ -204,6 +264,53 @@ func (crs *containerRegistryService) getAdminUserCredentials(
 	}, nil
 }
 
+// GenerateTokenCredentials generates the second password of the token of the container registry, ex) for the client
+// token of a connected registry. The first password is left unchanged for the other clients of the token.
+func (crs *containerRegistryService) GenerateTokenCredentials(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	tokenName string,
+) (*DockerCredentials, error) {
+	registryName := strings.Split(loginServer, ".")[0]
+	registry, resourceGroup, err := crs.findContainerRegistryByName(ctx, subscriptionId, registryName)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := crs.createRegistriesClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginGenerateCredentials(ctx, resourceGroup, registryName,
+		armcontainerregistry.GenerateCredentialsParameters{
+			TokenID: to.Ptr(fmt.Sprintf("%s/tokens/%s", *registry.ID, tokenName)),
+			Name:    to.Ptr(armcontainerregistry.TokenPasswordNamePassword2),
+		}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("generating password of token '%s': %w", tokenName, err)
+	}
+
+	response, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("generating password of token '%s': %w", tokenName, err)
+	}
+
+	for _, password := range response.Passwords {
+		if password.Name != nil && *password.Name == armcontainerregistry.TokenPasswordNamePassword2 &&
+			password.Value != nil {
+			return &DockerCredentials{
+				Username:    *response.Username,
+				Password:    *password.Value,
+				LoginServer: loginServer,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no password generated for token '%s'", tokenName)
+}
+
 func (crs *containerRegistryService) findContainerRegistryByName(
 	ctx context.Context,
 	subscriptionId string,
@@ -252,11 +359,48 @@ func (crs *containerRegistryService) createRegistriesClient(
 	return client, nil
 }
 
-// Exchanges an AAD token for an ACR refresh token
+// getAcrToken returns the ACR refresh token of the login server, exchanged once for the duration of an azd invocation
+// and refreshed ahead of its expiry
 func (crs *containerRegistryService) getAcrToken(
 	ctx context.Context,
 	subscriptionId string,
 	loginServer string,
+) (*acrToken, error) {
+	// The lock is held during the exchange, so that concurrent requests for the same registry exchange a single token
+	crs.cacheMu.Lock()
+	defer crs.cacheMu.Unlock()
+
+	key := fmt.Sprintf("%s/%s", subscriptionId, loginServer)
+	if cached, has := crs.tokenCache[key]; has && time.Now().Before(cached.refreshOn) {
+		log.Printf("using cached ACR token for registry '%s'", loginServer)
+		return cached.token, nil
+	}
+
+	token, err := crs.exchangeAcrToken(ctx, subscriptionId, loginServer)
+	if err != nil {
+		return nil, err
+	}
+
+	crs.tokenCache[key] = cachedAcrToken{token: token, refreshOn: acrTokenRefreshOn(token.RefreshToken)}
+	return token, nil
+}
+
+// acrTokenRefreshOn returns the time the refresh token is refreshed at, ahead of the expiry of its exp claim. Tokens
+// without the claim are refreshed after a default lifetime.
+func acrTokenRefreshOn(refreshToken string) time.Time {
+	claims, err := auth.GetClaimsFromAccessToken(refreshToken)
+	if err != nil || claims.ExpirationTime == 0 {
+		return time.Now().Add(defaultAcrTokenLifetime - acrTokenRefreshMargin)
+	}
+
+	return time.Unix(claims.ExpirationTime, 0).Add(-acrTokenRefreshMargin)
+}
+
+// Exchanges an AAD token for an ACR refresh token
+func (crs *containerRegistryService) exchangeAcrToken(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
 ) (*acrToken, error) {
 	creds, err := crs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
@@ -0,0 +1,255 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// RepositoryTag is a tag of a repository in an Azure Container Registry
+type RepositoryTag struct {
+	Name           string    `json:"name"`
+	Digest         string    `json:"digest"`
+	CreatedTime    time.Time `json:"createdTime"`
+	LastUpdateTime time.Time `json:"lastUpdateTime"`
+}
+
+type repositoryTagList struct {
+	Tags []*RepositoryTag `json:"tags"`
+}
+
+// ImageImportSource is the image of a container registry imported into another container registry
+type ImageImportSource struct {
+	SubscriptionId string
+	// The login server of the source registry, ex) contoso.azurecr.io
+	LoginServer string
+	// The repository of the image with its tag or digest, ex) todo/api@sha256:4f2d...
+	Image string
+}
+
+type acrAccessToken struct {
+	AccessToken string `json:"access_token"`
+}
+
+// GetRepositoryTags gets the tags of the repository, ex) todo/api, newest first
+func (crs *containerRegistryService) GetRepositoryTags(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+) ([]*RepositoryTag, error) {
+	accessToken, err := crs.getAcrAccessToken(ctx, subscriptionId, loginServer, repository, "metadata_read")
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, crs.coreClientOptions)
+
+	tags := []*RepositoryTag{}
+	nextUrl := fmt.Sprintf("/acr/v1/%s/_tags?orderby=timedesc&n=100", repository)
+
+	for nextUrl != "" {
+		req, err := azruntime.NewRequest(ctx, http.MethodGet, fmt.Sprintf("https://%s%s", loginServer, nextUrl))
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.Raw().Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+		response, err := pipeline.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !azruntime.HasStatusCode(response, http.StatusOK) {
+			return nil, azruntime.NewResponseError(response)
+		}
+
+		nextUrl = nextLink(response.Header.Get("Link"))
+
+		tagList, err := httputil.ReadRawResponse[repositoryTagList](response)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tagList.Tags...)
+	}
+
+	return tags, nil
+}
+
+// DeleteRepositoryTag deletes the tag from the repository, the manifest the tag references is kept
+func (crs *containerRegistryService) DeleteRepositoryTag(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	tag string,
+) error {
+	return crs.deleteRepositoryResource(
+		ctx, subscriptionId, loginServer, repository, fmt.Sprintf("/acr/v1/%s/_tags/%s", repository, tag))
+}
+
+// DeleteRepositoryManifest deletes the manifest with the digest from the repository, along with all of its tags
+func (crs *containerRegistryService) DeleteRepositoryManifest(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	digest string,
+) error {
+	return crs.deleteRepositoryResource(
+		ctx, subscriptionId, loginServer, repository, fmt.Sprintf("/v2/%s/manifests/%s", repository, digest))
+}
+
+// ImportImage imports the image of the source registry into the container registry, ex) to promote the image deployed
+// to an environment to the registry of another environment. The image keeps its digest in the container registry.
+func (crs *containerRegistryService) ImportImage(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	source ImageImportSource,
+	targetTags []string,
+) error {
+	registryName := strings.Split(loginServer, ".")[0]
+	_, resourceGroup, err := crs.findContainerRegistryByName(ctx, subscriptionId, registryName)
+	if err != nil {
+		return err
+	}
+
+	sourceRegistry, _, err := crs.findContainerRegistryByName(
+		ctx, source.SubscriptionId, strings.Split(source.LoginServer, ".")[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := crs.createRegistriesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]*string, len(targetTags))
+	for i := range targetTags {
+		tags[i] = &targetTags[i]
+	}
+
+	poller, err := client.BeginImportImage(ctx, resourceGroup, registryName, armcontainerregistry.ImportImageParameters{
+		Source: &armcontainerregistry.ImportSource{
+			ResourceID:  sourceRegistry.ID,
+			SourceImage: &source.Image,
+		},
+		TargetTags: tags,
+		Mode:       to.Ptr(armcontainerregistry.ImportModeForce),
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("importing image '%s' from '%s': %w", source.Image, source.LoginServer, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("importing image '%s' from '%s': %w", source.Image, source.LoginServer, err)
+	}
+
+	return nil
+}
+
+func (crs *containerRegistryService) deleteRepositoryResource(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	path string,
+) error {
+	accessToken, err := crs.getAcrAccessToken(ctx, subscriptionId, loginServer, repository, "delete")
+	if err != nil {
+		return err
+	}
+
+	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, crs.coreClientOptions)
+
+	req, err := azruntime.NewRequest(ctx, http.MethodDelete, fmt.Sprintf("https://%s%s", loginServer, path))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Raw().Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if !azruntime.HasStatusCode(response, http.StatusOK, http.StatusAccepted) {
+		return azruntime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+// Exchanges an ACR refresh token for an access token scoped to the actions on the repository, ex) delete
+func (crs *containerRegistryService) getAcrAccessToken(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	actions string,
+) (string, error) {
+	refreshToken, err := crs.getAcrToken(ctx, subscriptionId, loginServer)
+	if err != nil {
+		return "", fmt.Errorf("failed getting ACR token: %w", err)
+	}
+
+	// Implementation based on docs @ https://azure.github.io/acr/AAD-OAuth.html
+	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, crs.coreClientOptions)
+
+	formData := url.Values{}
+	formData.Set("grant_type", "refresh_token")
+	formData.Set("service", loginServer)
+	formData.Set("scope", fmt.Sprintf("repository:%s:%s", repository, actions))
+	formData.Set("refresh_token", refreshToken.RefreshToken)
+
+	tokenUrl := fmt.Sprintf("https://%s/oauth2/token", loginServer)
+	req, err := azruntime.NewRequest(ctx, http.MethodPost, tokenUrl)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	setHttpRequestBody(req, formData)
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	if !azruntime.HasStatusCode(response, http.StatusOK) {
+		return "", azruntime.NewResponseError(response)
+	}
+
+	accessToken, err := httputil.ReadRawResponse[acrAccessToken](response)
+	if err != nil {
+		return "", err
+	}
+
+	return accessToken.AccessToken, nil
+}
+
+// nextLink returns the url of the next page of a Link header, ex) </acr/v1/todo/_tags?last=v1&n=100>; rel="next"
+func nextLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		target, params, has := strings.Cut(link, ";")
+		if !has || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+
+		return strings.Trim(strings.TrimSpace(target), "<>")
+	}
+
+	return ""
+}
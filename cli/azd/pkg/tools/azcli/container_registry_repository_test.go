@@ -0,0 +1,201 @@
+package azcli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
+	"github.com/stretchr/testify/require"
+)
+
+func newContainerRegistryServiceFromMockContext(mockContext *mocks.MockContext) ContainerRegistryService {
+	return NewContainerRegistryService(
+		mockaccount.SubscriptionCredentialProviderFunc(func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		}),
+		nil,
+		mockContext.ArmClientOptions,
+		mockContext.CoreClientOptions,
+	)
+}
+
+func Test_ContainerRegistryService_GetRepositoryTags(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockazsdk.MockContainerRegistryTokenExchange(mockContext, "SUBSCRIPTION_ID", "contoso.azurecr.io", "REFRESH_TOKEN")
+
+	var scope string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "oauth2/token")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.NoError(t, request.ParseForm())
+		require.Equal(t, "REFRESH_TOKEN", request.PostForm.Get("refresh_token"))
+		scope = request.PostForm.Get("scope")
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, acrAccessToken{AccessToken: "ACCESS_TOKEN"})
+	})
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == "/acr/v1/todo/api/_tags"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.Equal(t, "Bearer ACCESS_TOKEN", request.Header.Get("Authorization"))
+
+		if request.URL.Query().Get("last") == "" {
+			response, err := mocks.CreateHttpResponseWithBody(request, http.StatusOK, repositoryTagList{
+				Tags: []*RepositoryTag{{Name: "azd-deploy-2", Digest: "sha256:2", LastUpdateTime: created}},
+			})
+			response.Header.Set("Link", `</acr/v1/todo/api/_tags?last=azd-deploy-2&n=100&orderby=timedesc>; rel="next"`)
+			return response, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, repositoryTagList{
+			Tags: []*RepositoryTag{{Name: "azd-deploy-1", Digest: "sha256:1", LastUpdateTime: created}},
+		})
+	})
+
+	containerRegistryService := newContainerRegistryServiceFromMockContext(mockContext)
+	tags, err := containerRegistryService.GetRepositoryTags(
+		*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", "todo/api")
+	require.NoError(t, err)
+	require.Equal(t, "repository:todo/api:metadata_read", scope)
+	require.Len(t, tags, 2)
+	require.Equal(t, "azd-deploy-2", tags[0].Name)
+	require.Equal(t, "sha256:2", tags[0].Digest)
+	require.Equal(t, "azd-deploy-1", tags[1].Name)
+}
+
+func Test_ContainerRegistryService_Delete(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockazsdk.MockContainerRegistryTokenExchange(mockContext, "SUBSCRIPTION_ID", "contoso.azurecr.io", "REFRESH_TOKEN")
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "oauth2/token")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.NoError(t, request.ParseForm())
+		require.Equal(t, "repository:todo/api:delete", request.PostForm.Get("scope"))
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, acrAccessToken{AccessToken: "ACCESS_TOKEN"})
+	})
+
+	deleted := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = append(deleted, request.URL.Path)
+		return mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+	})
+
+	containerRegistryService := newContainerRegistryServiceFromMockContext(mockContext)
+	err := containerRegistryService.DeleteRepositoryTag(
+		*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", "todo/api", "azd-deploy-1")
+	require.NoError(t, err)
+
+	err = containerRegistryService.DeleteRepositoryManifest(
+		*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", "todo/api", "sha256:1")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/acr/v1/todo/api/_tags/azd-deploy-1", "/v2/todo/api/manifests/sha256:1"}, deleted)
+}
+
+func Test_ContainerRegistryService_ImportImage(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockazsdk.MockContainerRegistryList(mockContext, []*armcontainerregistry.Registry{
+		{
+			Name: to.Ptr("contosodev"),
+			ID: to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-dev/providers/" +
+				"Microsoft.ContainerRegistry/registries/contosodev"),
+		},
+		{
+			Name: to.Ptr("contosoprod"),
+			ID: to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-prod/providers/" +
+				"Microsoft.ContainerRegistry/registries/contosoprod"),
+		},
+	})
+
+	var importRequest *http.Request
+	var importParameters armcontainerregistry.ImportImageParameters
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/importImage")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		importRequest = request
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &importParameters))
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	containerRegistryService := newContainerRegistryServiceFromMockContext(mockContext)
+	err := containerRegistryService.ImportImage(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"contosoprod.azurecr.io",
+		ImageImportSource{
+			SubscriptionId: "SUBSCRIPTION_ID",
+			LoginServer:    "contosodev.azurecr.io",
+			Image:          "todo/api@sha256:1",
+		},
+		[]string{"todo/api:azd-deploy-1"},
+	)
+	require.NoError(t, err)
+
+	require.NotNil(t, importRequest)
+	require.Contains(t, importRequest.URL.Path, "/resourceGroups/rg-prod/")
+	require.Contains(t, importRequest.URL.Path, "/registries/contosoprod/")
+	require.Equal(t, "todo/api@sha256:1", *importParameters.Source.SourceImage)
+	require.Contains(t, *importParameters.Source.ResourceID, "/registries/contosodev")
+	require.Equal(t, []*string{to.Ptr("todo/api:azd-deploy-1")}, importParameters.TargetTags)
+}
+
+func Test_ContainerRegistryService_GenerateTokenCredentials(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockazsdk.MockContainerRegistryList(mockContext, []*armcontainerregistry.Registry{
+		{
+			Name: to.Ptr("contoso"),
+			ID: to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-edge/providers/" +
+				"Microsoft.ContainerRegistry/registries/contoso"),
+		},
+	})
+
+	var parameters armcontainerregistry.GenerateCredentialsParameters
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/generateCredentials")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &parameters))
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.GenerateCredentialsResult{
+			Username: to.Ptr("azd-push"),
+			Passwords: []*armcontainerregistry.TokenPassword{
+				{Name: to.Ptr(armcontainerregistry.TokenPasswordNamePassword1), Value: to.Ptr("password1")},
+				{Name: to.Ptr(armcontainerregistry.TokenPasswordNamePassword2), Value: to.Ptr("password2")},
+			},
+		})
+	})
+
+	containerRegistryService := newContainerRegistryServiceFromMockContext(mockContext)
+	credentials, err := containerRegistryService.GenerateTokenCredentials(
+		*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", "azd-push")
+	require.NoError(t, err)
+
+	require.Equal(t, &DockerCredentials{
+		Username:    "azd-push",
+		Password:    "password2",
+		LoginServer: "contoso.azurecr.io",
+	}, credentials)
+	require.Equal(t, armcontainerregistry.TokenPasswordNamePassword2, *parameters.Name)
+	require.Equal(t,
+		"/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-edge/providers/Microsoft.ContainerRegistry/registries/contoso/"+
+			"tokens/azd-push",
+		*parameters.TokenID)
+}
@@ -0,0 +1,102 @@
+package azcli
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerRegistryService_Login_CachesToken(t *testing.T) {
+	tests := []struct {
+		name              string
+		expiresOn         time.Time
+		expectedExchanges int
+		expectedLogins    int
+	}{
+		{
+			name:              "Valid",
+			expiresOn:         time.Now().Add(3 * time.Hour),
+			expectedExchanges: 1,
+			expectedLogins:    1,
+		},
+		{
+			// The token expiring within the refresh margin is exchanged again for each login
+			name:              "Expiring",
+			expiresOn:         time.Now().Add(time.Minute),
+			expectedExchanges: 2,
+			expectedLogins:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			refreshToken := acrRefreshToken(tt.expiresOn)
+
+			exchanges := 0
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "oauth2/exchange")
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				exchanges++
+				return mocks.CreateHttpResponseWithBody(request, http.StatusOK, acrToken{RefreshToken: refreshToken})
+			})
+
+			logins := 0
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker login")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				logins++
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			containerRegistryService := NewContainerRegistryService(
+				mockaccount.SubscriptionCredentialProviderFunc(
+					func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+						return mockContext.Credentials, nil
+					}),
+				docker.NewCli(mockContext.CommandRunner),
+				mockContext.ArmClientOptions,
+				mockContext.CoreClientOptions,
+			)
+
+			// The services of a project pushing to the same registry
+			for range 2 {
+				err := containerRegistryService.Login(*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io")
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.expectedExchanges, exchanges)
+			require.Equal(t, tt.expectedLogins, logins)
+		})
+	}
+}
+
+func Test_AcrTokenRefreshOn(t *testing.T) {
+	expiresOn := time.Unix(time.Now().Add(3*time.Hour).Unix(), 0)
+	require.Equal(t, expiresOn.Add(-acrTokenRefreshMargin), acrTokenRefreshOn(acrRefreshToken(expiresOn)))
+
+	// Tokens that are not a JWT are refreshed after the default lifetime
+	refreshOn := acrTokenRefreshOn("REFRESH_TOKEN")
+	require.WithinDuration(t, time.Now().Add(defaultAcrTokenLifetime-acrTokenRefreshMargin), refreshOn, time.Minute)
+}
+
+// acrRefreshToken returns an unsigned ACR refresh token expiring at the specified time
+func acrRefreshToken(expiresOn time.Time) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return fmt.Sprintf("%s.%s.%s",
+		encode([]byte(`{"alg":"RS256","typ":"JWT"}`)),
+		encode([]byte(fmt.Sprintf(`{"exp":%d}`, expiresOn.Unix()))),
+		encode([]byte("signature")),
+	)
+}
@@ -4,18 +4,46 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 )
 
+// The ARM api version used to read and update the function app configuration of Flex Consumption apps, which is
+// not part of the app service SDK referenced by azd
+const flexConsumptionApiVersion = "2023-12-01"
+
 type AzCliFunctionAppProperties struct {
 	HostNames []string
 }
 
+// FunctionAppFlexScale is the scale and concurrency of a function app on a Flex Consumption plan
+type FunctionAppFlexScale struct {
+	// The instances kept ready per function group or function. Replaces the always ready instances of the app when
+	// not empty
+	AlwaysReady []*FunctionAppAlwaysReady `json:"alwaysReady,omitempty"`
+	// The maximum number of instances the app scales out to, unchanged when 0
+	MaximumInstanceCount int `json:"maximumInstanceCount,omitempty"`
+	// The memory of each instance in MB, unchanged when 0
+	InstanceMemoryMB int `json:"instanceMemoryMB,omitempty"`
+}
+
+// FunctionAppAlwaysReady is the number of instances of a Flex Consumption app that are always ready to run a
+// function group or function, ex) http, blob, durable or function:<function name>
+type FunctionAppAlwaysReady struct {
+	Name          string `json:"name"`
+	InstanceCount int    `json:"instanceCount"`
+}
+
 func (cli *azCli) GetFunctionAppProperties(
 	ctx context.Context,
 	subscriptionId string,
@@ -32,6 +60,24 @@ func (cli *azCli) GetFunctionAppProperties(
 	}, nil
 }
 
+// GetFunctionAppSlotProperties returns the properties of the deployment slot of the function app
+func (cli *azCli) GetFunctionAppSlotProperties(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) (*AzCliFunctionAppProperties, error) {
+	slot, err := cli.appServiceSlot(ctx, subscriptionId, resourceGroup, appName, slotName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzCliFunctionAppProperties{
+		HostNames: []string{*slot.Properties.DefaultHostName},
+	}, nil
+}
+
 func (cli *azCli) DeployFunctionAppUsingZipFile(
 	ctx context.Context,
 	subscriptionId string,
@@ -45,6 +91,48 @@ func (cli *azCli) DeployFunctionAppUsingZipFile(
 		return nil, err
 	}
 
+	return cli.deployFunctionAppZip(ctx, subscriptionId, app, appName, "", deployZipFile, remoteBuild)
+}
+
+// DeployFunctionAppSlotUsingZipFile deploys the zip file to the deployment slot of the function app, ex) staging
+func (cli *azCli) DeployFunctionAppSlotUsingZipFile(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+	deployZipFile io.ReadSeeker,
+	remoteBuild bool,
+) (*string, error) {
+	slot, err := cli.appServiceSlot(ctx, subscriptionId, resourceGroup, appName, slotName)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.deployFunctionAppZip(ctx, subscriptionId, slot, appName, slotName, deployZipFile, remoteBuild)
+}
+
+// SwapFunctionAppSlot swaps the deployment slot of the function app with the production slot
+func (cli *azCli) SwapFunctionAppSlot(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) error {
+	return cli.swapSlotWithProduction(ctx, subscriptionId, resourceGroup, appName, slotName)
+}
+
+// deployFunctionAppZip deploys the zip file to the function app, or to its deployment slot when the slot is not empty
+func (cli *azCli) deployFunctionAppZip(
+	ctx context.Context,
+	subscriptionId string,
+	app *armappservice.WebAppsClientGetResponse,
+	appName string,
+	slotName string,
+	deployZipFile io.ReadSeeker,
+	remoteBuild bool,
+) (*string, error) {
 	hostName, err := appServiceRepositoryHost(app, appName)
 	if err != nil {
 		return nil, err
@@ -70,7 +158,11 @@ func (cli *azCli) DeployFunctionAppUsingZipFile(
 		return nil, err
 	}
 
-	if strings.ToLower(*plan.SKU.Tier) == "flexconsumption" {
+	if isFlexConsumptionPlan(&plan.Plan) {
+		if slotName != "" {
+			return nil, fmt.Errorf("deployment slots are not supported by the Flex Consumption plan of '%s'", appName)
+		}
+
 		cred, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 		if err != nil {
 			return nil, err
@@ -100,3 +192,136 @@ func (cli *azCli) DeployFunctionAppUsingZipFile(
 
 	return to.Ptr(response.StatusText), nil
 }
+
+// UpdateFunctionAppFlexScale updates the scale and concurrency of the function app configuration of a Flex
+// Consumption app. The configuration is read first so that its deployment storage and runtime are kept, as the
+// configuration is replaced as a whole by the update.
+func (cli *azCli) UpdateFunctionAppFlexScale(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	scale *FunctionAppFlexScale,
+) error {
+	sitePath := fmt.Sprintf(
+		"subscriptions/%s/resourceGroups/%s/providers/Microsoft.Web/sites/%s",
+		url.PathEscape(subscriptionId),
+		url.PathEscape(resourceGroup),
+		url.PathEscape(appName),
+	)
+
+	var site struct {
+		Properties struct {
+			FunctionAppConfig map[string]any `json:"functionAppConfig"`
+		} `json:"properties"`
+	}
+
+	if err := cli.siteRequest(ctx, subscriptionId, http.MethodGet, sitePath, nil, &site); err != nil {
+		return fmt.Errorf("getting function app '%s': %w", appName, err)
+	}
+
+	functionAppConfig := site.Properties.FunctionAppConfig
+	if functionAppConfig == nil {
+		return fmt.Errorf("function app '%s' is not hosted on a Flex Consumption plan", appName)
+	}
+
+	scaleAndConcurrency, _ := functionAppConfig["scaleAndConcurrency"].(map[string]any)
+	if scaleAndConcurrency == nil {
+		scaleAndConcurrency = map[string]any{}
+	}
+
+	if len(scale.AlwaysReady) > 0 {
+		scaleAndConcurrency["alwaysReady"] = scale.AlwaysReady
+	}
+
+	if scale.MaximumInstanceCount > 0 {
+		scaleAndConcurrency["maximumInstanceCount"] = scale.MaximumInstanceCount
+	}
+
+	if scale.InstanceMemoryMB > 0 {
+		scaleAndConcurrency["instanceMemoryMB"] = scale.InstanceMemoryMB
+	}
+
+	functionAppConfig["scaleAndConcurrency"] = scaleAndConcurrency
+	body := map[string]any{
+		"properties": map[string]any{
+			"functionAppConfig": functionAppConfig,
+		},
+	}
+
+	if err := cli.siteRequest(ctx, subscriptionId, http.MethodPatch, sitePath, body, nil); err != nil {
+		return fmt.Errorf("updating scale of function app '%s': %w", appName, err)
+	}
+
+	return nil
+}
+
+// siteRequest sends the request to the sites of the app service resource provider through the ARM REST API
+func (cli *azCli) siteRequest(
+	ctx context.Context,
+	subscriptionId string,
+	method string,
+	path string,
+	body any,
+	result any,
+) error {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := armruntime.NewPipeline(
+		"azd-functions", internal.Version, credential, runtime.PipelineOptions{}, cli.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if cli.armClientOptions != nil {
+		if service, has := cli.armClientOptions.Cloud.Services[cloud.ResourceManager]; has && service.Endpoint != "" {
+			endpoint = service.Endpoint
+		}
+	}
+
+	requestUrl, err := url.JoinPath(endpoint, path)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(ctx, method, fmt.Sprintf("%s?api-version=%s", requestUrl, flexConsumptionApiVersion))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if body != nil {
+		if err := runtime.MarshalAsJSON(req, body); err != nil {
+			return err
+		}
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	if result != nil {
+		return runtime.UnmarshalAsJSON(response, result)
+	}
+
+	return nil
+}
+
+// isFlexConsumptionPlan returns whether the app service plan is a Flex Consumption plan, which only supports
+// deployments through the one deploy api of the app
+func isFlexConsumptionPlan(plan *armappservice.Plan) bool {
+	if plan.SKU == nil {
+		return false
+	}
+
+	return (plan.SKU.Tier != nil && strings.EqualFold(*plan.SKU.Tier, "FlexConsumption")) ||
+		(plan.SKU.Name != nil && strings.EqualFold(*plan.SKU.Name, "FC1"))
+}
@@ -0,0 +1,307 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+const (
+	// The ARM api version used to read the host name of the IoT hub
+	iotHubArmApiVersion = "2023-06-30"
+	// The api version of the IoT Hub service api
+	iotHubApiVersion = "2021-04-12"
+	// The scope of the tokens of the IoT Hub service api
+	iotHubScope = "https://iothubs.azure.net/.default"
+)
+
+// IotHubService applies IoT Edge deployment manifests to the devices of an IoT hub and reports the status of the
+// modules running on the devices
+type IotHubService interface {
+	// Get the host name of the IoT hub, ex) contoso.azure-devices.net
+	GetIotHubHostName(ctx context.Context, subscriptionId string, resourceGroupName string, hubName string) (string, error)
+	// Apply the modules content of a deployment manifest to the IoT Edge device
+	ApplyIotEdgeDeviceContent(
+		ctx context.Context,
+		subscriptionId string,
+		hostName string,
+		deviceId string,
+		modulesContent map[string]any,
+	) error
+	// Create the IoT Edge deployment that applies the modules content to the devices matching its target condition.
+	// The content of a deployment can't change once it is created.
+	CreateIotEdgeDeployment(
+		ctx context.Context,
+		subscriptionId string,
+		hostName string,
+		deployment *IotEdgeDeployment,
+	) (*IotEdgeDeployment, error)
+	// List the IoT Edge deployments of the IoT hub
+	ListIotEdgeDeployments(ctx context.Context, subscriptionId string, hostName string) ([]*IotEdgeDeployment, error)
+	// Delete the IoT Edge deployment
+	DeleteIotEdgeDeployment(ctx context.Context, subscriptionId string, hostName string, deploymentId string) error
+	// Get the status of the modules reported by the edge agent of the IoT Edge device
+	GetIotEdgeModuleStatus(
+		ctx context.Context,
+		subscriptionId string,
+		hostName string,
+		deviceId string,
+	) ([]*IotEdgeModuleStatus, error)
+}
+
+// IotEdgeDeployment is an at-scale deployment of IoT Edge modules, a configuration of the IoT hub
+type IotEdgeDeployment struct {
+	Id              string                    `json:"id"`
+	Labels          map[string]string         `json:"labels,omitempty"`
+	Content         *IotEdgeDeploymentContent `json:"content,omitempty"`
+	TargetCondition string                    `json:"targetCondition"`
+	Priority        int                       `json:"priority"`
+	CreatedTimeUtc  string                    `json:"createdTimeUtc,omitempty"`
+	SystemMetrics   *IotEdgeDeploymentMetrics `json:"systemMetrics,omitempty"`
+	Etag            string                    `json:"etag,omitempty"`
+}
+
+// IotEdgeDeploymentContent is the modules content of a deployment manifest
+type IotEdgeDeploymentContent struct {
+	ModulesContent map[string]any `json:"modulesContent"`
+}
+
+// IotEdgeDeploymentMetrics are the metrics the IoT hub computes for a deployment
+type IotEdgeDeploymentMetrics struct {
+	// ex) targetedCount and appliedCount
+	Results map[string]int64 `json:"results,omitempty"`
+}
+
+// IotEdgeModuleStatus is the status of a module reported by the edge agent of a device
+type IotEdgeModuleStatus struct {
+	Name string
+	// The image the module runs
+	Image string
+	// ex) running, backoff or failed
+	RuntimeStatus string
+	// Whether the module is one of the edgeAgent and edgeHub system modules
+	System bool
+}
+
+type iotHubService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// Creates a new instance of the IotHubService
+func NewIotHubService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) IotHubService {
+	return &iotHubService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+func (is *iotHubService) GetIotHubHostName(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	hubName string,
+) (string, error) {
+	credential, err := is.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := armresources.NewClient(subscriptionId, credential, is.armClientOptions)
+	if err != nil {
+		return "", fmt.Errorf("creating resources client: %w", err)
+	}
+
+	resourceId := fmt.Sprintf(
+		"%s/providers/Microsoft.Devices/IotHubs/%s", azure.ResourceGroupRID(subscriptionId, resourceGroupName), hubName)
+	res, err := client.GetByID(ctx, resourceId, iotHubArmApiVersion, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting iot hub: %w", err)
+	}
+
+	if properties, ok := res.Properties.(map[string]any); ok {
+		if hostName, ok := properties["hostName"].(string); ok && hostName != "" {
+			return hostName, nil
+		}
+	}
+
+	return "", fmt.Errorf("iot hub '%s' has no host name", hubName)
+}
+
+func (is *iotHubService) ApplyIotEdgeDeviceContent(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+	deviceId string,
+	modulesContent map[string]any,
+) error {
+	body := IotEdgeDeploymentContent{ModulesContent: modulesContent}
+	path := fmt.Sprintf("devices/%s/applyConfigurationContent", url.PathEscape(deviceId))
+	if err := is.do(ctx, subscriptionId, hostName, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("applying deployment manifest to device '%s': %w", deviceId, err)
+	}
+
+	return nil
+}
+
+func (is *iotHubService) CreateIotEdgeDeployment(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+	deployment *IotEdgeDeployment,
+) (*IotEdgeDeployment, error) {
+	var created IotEdgeDeployment
+	path := fmt.Sprintf("configurations/%s", url.PathEscape(deployment.Id))
+	if err := is.do(ctx, subscriptionId, hostName, http.MethodPut, path, deployment, &created); err != nil {
+		return nil, fmt.Errorf("creating deployment '%s': %w", deployment.Id, err)
+	}
+
+	return &created, nil
+}
+
+func (is *iotHubService) ListIotEdgeDeployments(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+) ([]*IotEdgeDeployment, error) {
+	var deployments []*IotEdgeDeployment
+	if err := is.do(ctx, subscriptionId, hostName, http.MethodGet, "configurations", nil, &deployments); err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+
+	return deployments, nil
+}
+
+func (is *iotHubService) DeleteIotEdgeDeployment(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+	deploymentId string,
+) error {
+	path := fmt.Sprintf("configurations/%s", url.PathEscape(deploymentId))
+	if err := is.do(ctx, subscriptionId, hostName, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("deleting deployment '%s': %w", deploymentId, err)
+	}
+
+	return nil
+}
+
+func (is *iotHubService) GetIotEdgeModuleStatus(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+	deviceId string,
+) ([]*IotEdgeModuleStatus, error) {
+	type reportedModule struct {
+		RuntimeStatus string `json:"runtimeStatus"`
+		Settings      struct {
+			Image string `json:"image"`
+		} `json:"settings"`
+	}
+
+	var twin struct {
+		Properties struct {
+			Reported struct {
+				SystemModules map[string]reportedModule `json:"systemModules"`
+				Modules       map[string]reportedModule `json:"modules"`
+			} `json:"reported"`
+		} `json:"properties"`
+	}
+
+	path := fmt.Sprintf("twins/%s/modules/$edgeAgent", url.PathEscape(deviceId))
+	if err := is.do(ctx, subscriptionId, hostName, http.MethodGet, path, nil, &twin); err != nil {
+		return nil, fmt.Errorf("getting module status of device '%s': %w", deviceId, err)
+	}
+
+	statuses := []*IotEdgeModuleStatus{}
+	for system, modules := range map[bool]map[string]reportedModule{
+		true:  twin.Properties.Reported.SystemModules,
+		false: twin.Properties.Reported.Modules,
+	} {
+		for name, module := range modules {
+			statuses = append(statuses, &IotEdgeModuleStatus{
+				Name:          name,
+				Image:         module.Settings.Image,
+				RuntimeStatus: module.RuntimeStatus,
+				System:        system,
+			})
+		}
+	}
+
+	slices.SortFunc(statuses, func(a, b *IotEdgeModuleStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return statuses, nil
+}
+
+// do sends the request to the IoT Hub service api, authenticated with the token of the subscription credential
+func (is *iotHubService) do(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+	method string,
+	path string,
+	body any,
+	result any,
+) error {
+	credential, err := is.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	var clientOptions policy.ClientOptions
+	if is.armClientOptions != nil {
+		clientOptions = is.armClientOptions.ClientOptions
+	}
+
+	pipeline := runtime.NewPipeline("azd-iothub", internal.Version, runtime.PipelineOptions{
+		PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(credential, []string{iotHubScope}, nil)},
+	}, &clientOptions)
+
+	endpoint := fmt.Sprintf("https://%s/%s?api-version=%s", hostName, path, iotHubApiVersion)
+	req, err := runtime.NewRequest(ctx, method, endpoint)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if body != nil {
+		if err := runtime.MarshalAsJSON(req, body); err != nil {
+			return err
+		}
+	}
+
+	if method == http.MethodDelete {
+		// Deletes the deployment regardless of its version
+		req.Raw().Header.Set("If-Match", "*")
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated, http.StatusNoContent) {
+		return runtime.NewResponseError(response)
+	}
+
+	if result != nil {
+		return runtime.UnmarshalAsJSON(response, result)
+	}
+
+	return nil
+}
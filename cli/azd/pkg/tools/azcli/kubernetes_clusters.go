@@ -0,0 +1,161 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/internal"
+)
+
+const (
+	// The API version used to request the credentials of Azure Red Hat OpenShift clusters
+	openShiftApiVersion = "2023-11-22"
+	// The API version used to request the credentials of Azure Arc-enabled Kubernetes clusters
+	connectedClusterApiVersion = "2024-01-01"
+)
+
+// Gets the admin kube config of an Azure Red Hat OpenShift cluster
+func (cs *managedClustersService) GetOpenShiftAdminKubeConfig(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	resourceName string,
+) ([]byte, error) {
+	var result struct {
+		Kubeconfig []byte `json:"kubeconfig"`
+	}
+
+	err := cs.postClusterAction(
+		ctx,
+		subscriptionId,
+		resourceGroupName,
+		"Microsoft.RedHatOpenShift/openShiftClusters",
+		resourceName,
+		"listAdminCredentials",
+		openShiftApiVersion,
+		nil,
+		&result,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing admin credentials of OpenShift cluster '%s': %w", resourceName, err)
+	}
+
+	if len(result.Kubeconfig) == 0 {
+		return nil, fmt.Errorf("OpenShift cluster '%s' returned no admin kube config", resourceName)
+	}
+
+	return result.Kubeconfig, nil
+}
+
+// Gets the user kube config of an Azure Arc-enabled Kubernetes cluster
+// The kube config reaches the API server of the cluster through the cluster connect endpoint of Azure Arc and
+// authenticates with AAD, so it must be converted with kubelogin.
+func (cs *managedClustersService) GetConnectedClusterUserKubeConfig(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	resourceName string,
+) ([]byte, error) {
+	body := map[string]any{
+		"authenticationMethod": "AAD",
+		"clientProxy":          false,
+	}
+
+	var result struct {
+		Kubeconfigs []struct {
+			Name  string `json:"name"`
+			Value []byte `json:"value"`
+		} `json:"kubeconfigs"`
+	}
+
+	err := cs.postClusterAction(
+		ctx,
+		subscriptionId,
+		resourceGroupName,
+		"Microsoft.Kubernetes/connectedClusters",
+		resourceName,
+		"listClusterUserCredential",
+		connectedClusterApiVersion,
+		body,
+		&result,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing user credentials of connected cluster '%s': %w", resourceName, err)
+	}
+
+	if len(result.Kubeconfigs) == 0 || len(result.Kubeconfigs[0].Value) == 0 {
+		return nil, fmt.Errorf("connected cluster '%s' returned no user kube config", resourceName)
+	}
+
+	return result.Kubeconfigs[0].Value, nil
+}
+
+// postClusterAction invokes the action of the cluster resource through the ARM REST API, as the SDKs of these
+// resource providers are not referenced by azd
+func (cs *managedClustersService) postClusterAction(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	resourceType string,
+	resourceName string,
+	action string,
+	apiVersion string,
+	body any,
+	result any,
+) error {
+	credential, err := cs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := armruntime.NewPipeline(
+		"azd-k8s", internal.Version, credential, runtime.PipelineOptions{}, cs.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if cs.armClientOptions != nil {
+		if service, has := cs.armClientOptions.Cloud.Services[cloud.ResourceManager]; has && service.Endpoint != "" {
+			endpoint = service.Endpoint
+		}
+	}
+
+	actionUrl, err := url.JoinPath(
+		endpoint,
+		"subscriptions", subscriptionId,
+		"resourceGroups", resourceGroupName,
+		"providers", resourceType, resourceName,
+		action,
+	)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, fmt.Sprintf("%s?api-version=%s", actionUrl, apiVersion))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if body != nil {
+		if err := runtime.MarshalAsJSON(req, body); err != nil {
+			return err
+		}
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return runtime.NewResponseError(response)
+	}
+
+	return runtime.UnmarshalAsJSON(response, result)
+}
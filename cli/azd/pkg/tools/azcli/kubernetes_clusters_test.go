@@ -0,0 +1,81 @@
+package azcli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetOpenShiftAdminKubeConfig(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	service := NewManagedClustersService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(
+			request.URL.Path, "/Microsoft.RedHatOpenShift/openShiftClusters/ARO_CLUSTER/listAdminCredentials")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"kubeconfig": []byte("apiVersion: v1"),
+		})
+	})
+
+	kubeConfig, err := service.GetOpenShiftAdminKubeConfig(
+		*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "ARO_CLUSTER")
+	require.NoError(t, err)
+	require.Equal(t, "apiVersion: v1", string(kubeConfig))
+}
+
+func Test_GetConnectedClusterUserKubeConfig(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		service := NewManagedClustersService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		var body map[string]any
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(
+				request.URL.Path, "/Microsoft.Kubernetes/connectedClusters/ARC_CLUSTER/listClusterUserCredential")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			raw, err := io.ReadAll(request.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, err
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"kubeconfigs": []map[string]any{
+					{"name": "credentialName1", "value": []byte("apiVersion: v1")},
+				},
+			})
+		})
+
+		kubeConfig, err := service.GetConnectedClusterUserKubeConfig(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "ARC_CLUSTER")
+		require.NoError(t, err)
+		require.Equal(t, "apiVersion: v1", string(kubeConfig))
+		require.Equal(t, map[string]any{"authenticationMethod": "AAD", "clientProxy": false}, body)
+	})
+
+	t.Run("NoKubeConfig", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		service := NewManagedClustersService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "listClusterUserCredential")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"kubeconfigs": []any{}})
+		})
+
+		_, err := service.GetConnectedClusterUserKubeConfig(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "ARC_CLUSTER")
+		require.ErrorContains(t, err, "connected cluster 'ARC_CLUSTER' returned no user kube config")
+	})
+}
@@ -3,9 +3,17 @@ package azcli
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 )
 
@@ -25,6 +33,62 @@ type ManagedClustersService interface {
 		resourceGroupName string,
 		resourceName string,
 	) (*armcontainerservice.CredentialResults, error)
+	// Runs a command on the managed cluster through the AKS run command API
+	// This supports running commands on private clusters where the API server is not publicly accessible
+	RunCommand(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		resourceName string,
+		request armcontainerservice.RunCommandRequest,
+	) (*armcontainerservice.RunCommandResult, error)
+	// Gets an access token for the AKS AAD server application used to authenticate with AAD enabled clusters
+	GetClusterToken(ctx context.Context, subscriptionId string) (string, error)
+	// Lists the member clusters of an Azure Kubernetes Fleet Manager resource
+	ListFleetMembers(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		fleetName string,
+	) ([]*FleetMember, error)
+	// Gets the admin kube config of an Azure Red Hat OpenShift cluster
+	GetOpenShiftAdminKubeConfig(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		resourceName string,
+	) ([]byte, error)
+	// Gets the user kube config of an Azure Arc-enabled Kubernetes cluster, connected through cluster connect
+	GetConnectedClusterUserKubeConfig(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		resourceName string,
+	) ([]byte, error)
+}
+
+// The well-known scope of the AKS AAD server application
+const aksAadServerScope = "6dae42f8-4368-4678-94ff-3960e28e3630/.default"
+
+// The API version used to query Azure Kubernetes Fleet Manager resources
+const fleetApiVersion = "2023-10-15"
+
+// FleetMember is a member cluster of an Azure Kubernetes Fleet Manager resource
+type FleetMember struct {
+	Id         string                `json:"id"`
+	Name       string                `json:"name"`
+	Properties FleetMemberProperties `json:"properties"`
+}
+
+type FleetMemberProperties struct {
+	// The ARM resource id of the AKS cluster joined to the fleet
+	ClusterResourceId string `json:"clusterResourceId"`
+	Group             string `json:"group"`
+}
+
+type fleetMemberListResult struct {
+	Value    []*FleetMember `json:"value"`
+	NextLink string         `json:"nextLink"`
 }
 
 type managedClustersService struct {
@@ -75,7 +139,13 @@ func (cs *managedClustersService) GetUserCredentials(
 		return nil, err
 	}
 
-	credResult, err := client.ListClusterUserCredentials(ctx, resourceGroupName, resourceName, nil)
+	// The exec format only applies to AAD enabled clusters and is required to convert the kube config
+	// with kubelogin. Non AAD enabled clusters will continue to return the default credential format.
+	options := &armcontainerservice.ManagedClustersClientListClusterUserCredentialsOptions{
+		Format: to.Ptr(armcontainerservice.FormatExec),
+	}
+
+	credResult, err := client.ListClusterUserCredentials(ctx, resourceGroupName, resourceName, options)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +153,116 @@ func (cs *managedClustersService) GetUserCredentials(
 	return &credResult.CredentialResults, nil
 }
 
+// Runs a command on the managed cluster through the AKS run command API
+func (cs *managedClustersService) RunCommand(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	resourceName string,
+	request armcontainerservice.RunCommandRequest,
+) (*armcontainerservice.RunCommandResult, error) {
+	client, err := cs.createManagedClusterClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginRunCommand(ctx, resourceGroupName, resourceName, request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.RunCommandResult, nil
+}
+
+// Gets an access token for the AKS AAD server application used to authenticate with AAD enabled clusters
+func (cs *managedClustersService) GetClusterToken(ctx context.Context, subscriptionId string) (string, error) {
+	credential, err := cs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{aksAadServerScope},
+	})
+	if err != nil {
+		return "", fmt.Errorf("getting AKS cluster token, %w", err)
+	}
+
+	return token.Token, nil
+}
+
+// Lists the member clusters of an Azure Kubernetes Fleet Manager resource
+func (cs *managedClustersService) ListFleetMembers(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	fleetName string,
+) ([]*FleetMember, error) {
+	credential, err := cs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The fleet SDK is not referenced by azd so the members are listed directly through the ARM REST API
+	pipeline, err := armruntime.NewPipeline(
+		"azd-fleet", internal.Version, credential, runtime.PipelineOptions{}, cs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if cs.armClientOptions != nil {
+		if service, has := cs.armClientOptions.Cloud.Services[cloud.ResourceManager]; has && service.Endpoint != "" {
+			endpoint = service.Endpoint
+		}
+	}
+
+	nextLink, err := url.JoinPath(
+		endpoint,
+		"subscriptions", subscriptionId,
+		"resourceGroups", resourceGroupName,
+		"providers/Microsoft.ContainerService/fleets", fleetName,
+		"members",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	nextLink = fmt.Sprintf("%s?api-version=%s", nextLink, fleetApiVersion)
+
+	members := []*FleetMember{}
+	for nextLink != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, fmt.Errorf("creating fleet members request: %w", err)
+		}
+
+		response, err := pipeline.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("listing members of fleet '%s': %w", fleetName, err)
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return nil, runtime.NewResponseError(response)
+		}
+
+		var result fleetMemberListResult
+		if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+			return nil, fmt.Errorf("unmarshalling fleet members: %w", err)
+		}
+
+		members = append(members, result.Value...)
+		nextLink = result.NextLink
+	}
+
+	return members, nil
+}
+
 func (cs *managedClustersService) createManagedClusterClient(
 	ctx context.Context,
 	subscriptionId string,
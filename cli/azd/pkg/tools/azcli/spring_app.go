@@ -12,6 +12,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appplatform/armappplatform/v2"
 	"github.com/Azure/azure-storage-file-go/azfile"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 )
 
 // SpringService provides artifacts upload/deploy and query to Azure Spring Apps (ASA)
@@ -52,6 +53,53 @@ type SpringService interface {
 		appName string,
 		deploymentName string,
 	) (*string, error)
+	// List the deployments of the Spring app
+	ListSpringAppDeployments(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		instanceName string,
+		appName string,
+	) ([]*SpringAppDeployment, error)
+	// Deploy jar artifact to the staging deployment of the ASA app, without making it the active deployment. The
+	// staging deployment is created with the SKU and the settings of the source deployment when it doesn't exist.
+	DeploySpringAppStagingArtifact(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		instanceName string,
+		appName string,
+		relativePath string,
+		deploymentName string,
+		sourceDeploymentName string,
+	) (*string, error)
+	// Set the deployment that receives the production traffic of the Spring app
+	SetSpringAppActiveDeployment(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		instanceName string,
+		appName string,
+		deploymentName string,
+	) error
+}
+
+// SpringAppDeployment is a deployment of a Spring app, ex) the blue or the green deployment of a blue/green rollout
+type SpringAppDeployment struct {
+	Name   string
+	Active bool
+	// ex) Running or Stopped
+	Status    string
+	Instances []SpringAppDeploymentInstance
+}
+
+// SpringAppDeploymentInstance is an instance of a Spring app deployment
+type SpringAppDeploymentInstance struct {
+	Name string
+	// ex) Running, Pending or Failed
+	Status string
+	// The reason the instance failed, if any
+	Reason string
 }
 
 type springService struct {
@@ -200,6 +248,122 @@ func (ss *springService) GetSpringAppDeployment(
 	return resp.Name, nil
 }
 
+func (ss *springService) ListSpringAppDeployments(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	instanceName string,
+	appName string,
+) ([]*SpringAppDeployment, error) {
+	client, err := ss.createSpringAppDeploymentClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := []*SpringAppDeployment{}
+	pager := client.NewListPager(resourceGroupName, instanceName, appName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed listing deployments of spring app '%s': %w", appName, err)
+		}
+
+		for _, resource := range page.Value {
+			deployment := &SpringAppDeployment{
+				Name: *resource.Name,
+			}
+
+			if props := resource.Properties; props != nil {
+				deployment.Active = props.Active != nil && *props.Active
+				if props.Status != nil {
+					deployment.Status = string(*props.Status)
+				}
+
+				for _, instance := range props.Instances {
+					deployment.Instances = append(deployment.Instances, SpringAppDeploymentInstance{
+						Name:   convert.ToValueWithDefault(instance.Name, ""),
+						Status: convert.ToValueWithDefault(instance.Status, ""),
+						Reason: convert.ToValueWithDefault(instance.Reason, ""),
+					})
+				}
+			}
+
+			deployments = append(deployments, deployment)
+		}
+	}
+
+	return deployments, nil
+}
+
+func (ss *springService) DeploySpringAppStagingArtifact(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	instanceName string,
+	appName string,
+	relativePath string,
+	deploymentName string,
+	sourceDeploymentName string,
+) (*string, error) {
+	deploymentClient, err := ss.createSpringAppDeploymentClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The staging deployment runs with the same SKU and settings as the deployment it replaces
+	source, err := deploymentClient.Get(ctx, resourceGroup, instanceName, appName, sourceDeploymentName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving deployment '%s' of spring app '%s': %w",
+			sourceDeploymentName, appName, err)
+	}
+
+	deployment := armappplatform.DeploymentResource{
+		SKU: source.SKU,
+		Properties: &armappplatform.DeploymentResourceProperties{
+			Source: &armappplatform.JarUploadedUserSourceInfo{
+				Type:         to.Ptr("Jar"),
+				RelativePath: to.Ptr(relativePath),
+			},
+		},
+	}
+	if source.Properties != nil {
+		deployment.Properties.DeploymentSettings = source.Properties.DeploymentSettings
+	}
+
+	poller, err := deploymentClient.BeginCreateOrUpdate(
+		ctx, resourceGroup, instanceName, appName, deploymentName, deployment, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed deploying staging deployment '%s': %w", deploymentName, err)
+	}
+
+	res, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed deploying staging deployment '%s': %w", deploymentName, err)
+	}
+
+	return res.Name, nil
+}
+
+func (ss *springService) SetSpringAppActiveDeployment(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	instanceName string,
+	appName string,
+	deploymentName string,
+) error {
+	springClient, err := ss.createSpringAppClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ss.activeDeployment(springClient, ctx, resourceGroup, instanceName, appName, deploymentName); err != nil {
+		return fmt.Errorf("failed setting active deployment '%s' of spring app '%s': %w", deploymentName, appName, err)
+	}
+
+	return nil
+}
+
 func (ss *springService) createSpringAppClient(
 	ctx context.Context,
 	subscriptionId string,
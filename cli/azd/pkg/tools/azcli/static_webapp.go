@@ -61,6 +61,31 @@ func (cli *azCli) GetStaticWebAppEnvironmentProperties(
 	}, nil
 }
 
+// DeleteStaticWebAppEnvironment deletes the named environment of the static web app, ex) a preview environment
+func (cli *azCli) DeleteStaticWebAppEnvironment(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	environmentName string,
+) error {
+	client, err := cli.createStaticSitesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginDeleteStaticSiteBuild(ctx, resourceGroup, appName, environmentName, nil)
+	if err != nil {
+		return fmt.Errorf("deleting static site environment '%s': %w", environmentName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("deleting static site environment '%s': %w", environmentName, err)
+	}
+
+	return nil
+}
+
 func (cli *azCli) GetStaticWebAppApiKey(
 	ctx context.Context,
 	subscriptionId string,
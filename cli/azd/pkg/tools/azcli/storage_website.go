@@ -0,0 +1,374 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	azcloud "github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+)
+
+const (
+	// The ARM api version used to read the endpoints of the storage account
+	storageAccountApiVersion = "2023-01-01"
+	// The ARM api version used to purge the endpoints of CDN and Front Door profiles
+	cdnApiVersion = "2023-05-01"
+)
+
+// StorageWebsiteService uploads static sites to the blob containers of storage accounts and purges the CDN and Front
+// Door endpoints serving them
+type StorageWebsiteService interface {
+	// Enable the static website of the storage account, which serves the $web container
+	EnableStaticWebsite(
+		ctx context.Context,
+		subscriptionId string,
+		storageAccountName string,
+		indexDocument string,
+		errorDocument string,
+	) error
+	// Get the URL of the static website of the storage account, ex) https://contoso.z13.web.core.windows.net/
+	GetStaticWebsiteUrl(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		storageAccountName string,
+	) (string, error)
+	// Upload the files to the blob container, creating the container when it doesn't exist
+	UploadStorageWebsiteFiles(
+		ctx context.Context,
+		subscriptionId string,
+		storageAccountName string,
+		containerName string,
+		files []*StorageWebsiteFile,
+	) error
+	// List the names of the blobs of the container
+	ListStorageWebsiteFiles(
+		ctx context.Context,
+		subscriptionId string,
+		storageAccountName string,
+		containerName string,
+	) ([]string, error)
+	// Delete the blobs of the container
+	DeleteStorageWebsiteFiles(
+		ctx context.Context,
+		subscriptionId string,
+		storageAccountName string,
+		containerName string,
+		names []string,
+	) error
+	// Purge the content paths from the cache of the CDN or Front Door endpoint and wait for the purge to complete
+	PurgeCdnEndpoint(ctx context.Context, subscriptionId string, endpoint *CdnEndpoint, contentPaths []string) error
+}
+
+// StorageWebsiteFile is a file of a static site uploaded to a blob
+type StorageWebsiteFile struct {
+	// The local path of the file
+	Path string
+	// The name of the blob, ex) assets/app.js
+	Name         string
+	ContentType  string
+	CacheControl string
+}
+
+// CdnEndpoint is an endpoint of an Azure CDN or Azure Front Door profile
+type CdnEndpoint struct {
+	ResourceGroupName string
+	ProfileName       string
+	EndpointName      string
+	// Whether the profile is an Azure Front Door Standard/Premium profile, whose endpoints are AFD endpoints
+	FrontDoor bool
+}
+
+type storageWebsiteService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+	cloud              *cloud.Cloud
+}
+
+// Creates a new instance of the StorageWebsiteService
+func NewStorageWebsiteService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+	cloud *cloud.Cloud,
+) StorageWebsiteService {
+	return &storageWebsiteService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+		cloud:              cloud,
+	}
+}
+
+func (ss *storageWebsiteService) EnableStaticWebsite(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	indexDocument string,
+	errorDocument string,
+) error {
+	client, err := ss.createBlobClient(ctx, subscriptionId, storageAccountName)
+	if err != nil {
+		return err
+	}
+
+	website := &service.StaticWebsite{
+		Enabled:       to.Ptr(true),
+		IndexDocument: to.Ptr(indexDocument),
+	}
+	if errorDocument != "" {
+		website.ErrorDocument404Path = to.Ptr(errorDocument)
+	}
+
+	if _, err := client.ServiceClient().SetProperties(ctx, &service.SetPropertiesOptions{
+		StaticWebsite: website,
+	}); err != nil {
+		return fmt.Errorf("enabling static website of storage account '%s': %w", storageAccountName, err)
+	}
+
+	return nil
+}
+
+func (ss *storageWebsiteService) GetStaticWebsiteUrl(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	storageAccountName string,
+) (string, error) {
+	credential, err := ss.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := armresources.NewClient(subscriptionId, credential, ss.armClientOptions)
+	if err != nil {
+		return "", fmt.Errorf("creating resources client: %w", err)
+	}
+
+	resourceId := fmt.Sprintf(
+		"%s/providers/Microsoft.Storage/storageAccounts/%s",
+		azure.ResourceGroupRID(subscriptionId, resourceGroupName),
+		storageAccountName,
+	)
+	res, err := client.GetByID(ctx, resourceId, storageAccountApiVersion, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting storage account: %w", err)
+	}
+
+	if properties, ok := res.Properties.(map[string]any); ok {
+		if endpoints, ok := properties["primaryEndpoints"].(map[string]any); ok {
+			if web, ok := endpoints["web"].(string); ok {
+				return web, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func (ss *storageWebsiteService) UploadStorageWebsiteFiles(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	containerName string,
+	files []*StorageWebsiteFile,
+) error {
+	client, err := ss.createBlobClient(ctx, subscriptionId, storageAccountName)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CreateContainer(ctx, containerName, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return fmt.Errorf("creating container '%s': %w", containerName, err)
+	}
+
+	for _, file := range files {
+		if err := uploadStorageWebsiteFile(ctx, client, containerName, file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func uploadStorageWebsiteFile(
+	ctx context.Context,
+	client *azblob.Client,
+	containerName string,
+	file *StorageWebsiteFile,
+) error {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	headers := &blob.HTTPHeaders{BlobContentType: to.Ptr(file.ContentType)}
+	if file.CacheControl != "" {
+		headers.BlobCacheControl = to.Ptr(file.CacheControl)
+	}
+
+	if _, err := client.UploadFile(ctx, containerName, file.Name, f, &azblob.UploadFileOptions{
+		HTTPHeaders: headers,
+	}); err != nil {
+		return fmt.Errorf("uploading file to blob '%s': %w", file.Name, err)
+	}
+
+	return nil
+}
+
+func (ss *storageWebsiteService) ListStorageWebsiteFiles(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	containerName string,
+) ([]string, error) {
+	client, err := ss.createBlobClient(ctx, subscriptionId, storageAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	pager := client.NewListBlobsFlatPager(containerName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing blobs of container '%s': %w", containerName, err)
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			names = append(names, *item.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (ss *storageWebsiteService) DeleteStorageWebsiteFiles(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	containerName string,
+	names []string,
+) error {
+	client, err := ss.createBlobClient(ctx, subscriptionId, storageAccountName)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		_, err := client.DeleteBlob(ctx, containerName, name, nil)
+		if err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return fmt.Errorf("deleting blob '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (ss *storageWebsiteService) PurgeCdnEndpoint(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint *CdnEndpoint,
+	contentPaths []string,
+) error {
+	credential, err := ss.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	// The CDN SDK is not referenced by azd so the endpoint is purged directly through the ARM REST API
+	pipeline, err := armruntime.NewPipeline(
+		"azd-cdn", internal.Version, credential, runtime.PipelineOptions{}, ss.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	armEndpoint := azcloud.AzurePublic.Services[azcloud.ResourceManager].Endpoint
+	if ss.armClientOptions != nil {
+		if service, has := ss.armClientOptions.Cloud.Services[azcloud.ResourceManager]; has && service.Endpoint != "" {
+			armEndpoint = service.Endpoint
+		}
+	}
+
+	endpointsPath := "endpoints"
+	if endpoint.FrontDoor {
+		endpointsPath = "afdEndpoints"
+	}
+
+	purgeUrl, err := url.JoinPath(
+		armEndpoint,
+		"subscriptions", subscriptionId,
+		"resourceGroups", endpoint.ResourceGroupName,
+		"providers/Microsoft.Cdn/profiles", endpoint.ProfileName,
+		endpointsPath, endpoint.EndpointName,
+		"purge",
+	)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, fmt.Sprintf("%s?api-version=%s", purgeUrl, cdnApiVersion))
+	if err != nil {
+		return fmt.Errorf("creating purge request: %w", err)
+	}
+
+	if err := runtime.MarshalAsJSON(req, map[string]any{"contentPaths": contentPaths}); err != nil {
+		return err
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return fmt.Errorf("purging endpoint '%s': %w", endpoint.EndpointName, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[struct{}](response, pipeline, nil)
+	if err != nil {
+		return fmt.Errorf("polling purge of endpoint '%s': %w", endpoint.EndpointName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("purging endpoint '%s': %w", endpoint.EndpointName, err)
+	}
+
+	return nil
+}
+
+func (ss *storageWebsiteService) createBlobClient(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+) (*azblob.Client, error) {
+	credential, err := ss.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceUrl := fmt.Sprintf("https://%s.blob.%s/", storageAccountName, ss.cloud.StorageEndpointSuffix)
+	client, err := azblob.NewClient(serviceUrl, credential, &azblob.ClientOptions{
+		ClientOptions: ss.armClientOptions.ClientOptions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating blob client: %w", err)
+	}
+
+	return client, nil
+}
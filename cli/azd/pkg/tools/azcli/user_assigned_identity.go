@@ -0,0 +1,143 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// The audience used by Microsoft Entra ID for workload identity federation token exchange
+const FederatedCredentialAudience = "api://AzureADTokenExchange"
+
+// UserAssignedIdentityService provides actions on top of Azure user-assigned managed identities
+type UserAssignedIdentityService interface {
+	// Gets the user-assigned managed identity by name
+	Get(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		identityName string,
+	) (*armmsi.Identity, error)
+	// Creates or updates a federated identity credential for the user-assigned managed identity
+	CreateOrUpdateFederatedCredential(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		identityName string,
+		credentialName string,
+		issuer string,
+		subject string,
+	) (*armmsi.FederatedIdentityCredential, error)
+}
+
+type userAssignedIdentityService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// Creates a new instance of the UserAssignedIdentityService
+func NewUserAssignedIdentityService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) UserAssignedIdentityService {
+	return &userAssignedIdentityService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+// Gets the user-assigned managed identity by name
+func (s *userAssignedIdentityService) Get(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	identityName string,
+) (*armmsi.Identity, error) {
+	client, err := s.createIdentitiesClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := client.Get(ctx, resourceGroupName, identityName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity.Identity, nil
+}
+
+// Creates or updates a federated identity credential for the user-assigned managed identity
+func (s *userAssignedIdentityService) CreateOrUpdateFederatedCredential(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	identityName string,
+	credentialName string,
+	issuer string,
+	subject string,
+) (*armmsi.FederatedIdentityCredential, error) {
+	client, err := s.createFederatedCredentialsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	federatedCredential := armmsi.FederatedIdentityCredential{
+		Properties: &armmsi.FederatedIdentityCredentialProperties{
+			Issuer:    to.Ptr(issuer),
+			Subject:   to.Ptr(subject),
+			Audiences: []*string{to.Ptr(FederatedCredentialAudience)},
+		},
+	}
+
+	response, err := client.CreateOrUpdate(
+		ctx,
+		resourceGroupName,
+		identityName,
+		credentialName,
+		federatedCredential,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.FederatedIdentityCredential, nil
+}
+
+func (s *userAssignedIdentityService) createIdentitiesClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armmsi.UserAssignedIdentitiesClient, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armmsi.NewUserAssignedIdentitiesClient(subscriptionId, credential, s.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating user assigned identities client, %w", err)
+	}
+
+	return client, nil
+}
+
+func (s *userAssignedIdentityService) createFederatedCredentialsClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armmsi.FederatedIdentityCredentialsClient, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armmsi.NewFederatedIdentityCredentialsClient(subscriptionId, credential, s.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating federated identity credentials client, %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,368 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// VirtualMachineService uploads deployment artifacts and runs scripts on Azure virtual machines and the instances of
+// virtual machine scale sets
+type VirtualMachineService interface {
+	// Upload the deployment artifact to the blob container of the storage account, creating the container when it
+	// doesn't exist. A read-only URL of the blob that expires after the specified duration is returned, so the virtual
+	// machines can download the artifact without access to the storage account.
+	UploadVirtualMachineArtifact(
+		ctx context.Context,
+		subscriptionId string,
+		storageAccountName string,
+		containerName string,
+		blobName string,
+		artifactPath string,
+		expiry time.Duration,
+	) (string, error)
+	// Run the script on the virtual machine and wait for it to complete
+	RunVirtualMachineCommand(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		vmName string,
+		command VirtualMachineRunCommand,
+	) (*VirtualMachineRunCommandResult, error)
+	// List the instance ids of the virtual machine scale set
+	ListVirtualMachineScaleSetInstances(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		vmScaleSetName string,
+	) ([]string, error)
+	// Run the script on the instance of the virtual machine scale set and wait for it to complete
+	RunVirtualMachineScaleSetCommand(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		vmScaleSetName string,
+		instanceId string,
+		command VirtualMachineRunCommand,
+	) (*VirtualMachineRunCommandResult, error)
+}
+
+// VirtualMachineRunCommand is a script run on a virtual machine with the managed run command of the virtual machine
+type VirtualMachineRunCommand struct {
+	// The name of the run command resource, running the same name again replaces the previous run
+	Name string
+	// The shell script that is run
+	Script string
+	// The parameters passed to the script as environment variables
+	Parameters map[string]string
+	// The parameters passed to the script as environment variables, which are not returned by the run command
+	// resource, ex) URLs that contain a SAS token
+	ProtectedParameters map[string]string
+	// How long the script may run before it is cancelled
+	Timeout time.Duration
+}
+
+// VirtualMachineRunCommandResult is the outcome of a script run on a virtual machine
+type VirtualMachineRunCommandResult struct {
+	// The execution state of the script, ex) Succeeded, Failed or TimedOut
+	State string
+	// The exit code of the script
+	ExitCode int
+	// The standard output of the script
+	Output string
+	// The standard error of the script
+	Error string
+}
+
+// Succeeded returns true when the script ran to completion with a zero exit code
+func (r *VirtualMachineRunCommandResult) Succeeded() bool {
+	return r.State == string(armcompute.ExecutionStateSucceeded) && r.ExitCode == 0
+}
+
+type virtualMachineService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+	cloud              *cloud.Cloud
+}
+
+// Creates a new instance of the VirtualMachineService
+func NewVirtualMachineService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+	cloud *cloud.Cloud,
+) VirtualMachineService {
+	return &virtualMachineService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+		cloud:              cloud,
+	}
+}
+
+func (vs *virtualMachineService) UploadVirtualMachineArtifact(
+	ctx context.Context,
+	subscriptionId string,
+	storageAccountName string,
+	containerName string,
+	blobName string,
+	artifactPath string,
+	expiry time.Duration,
+) (string, error) {
+	credential, err := vs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	serviceUrl := fmt.Sprintf("https://%s.blob.%s/", storageAccountName, vs.cloud.StorageEndpointSuffix)
+	client, err := azblob.NewClient(serviceUrl, credential, &azblob.ClientOptions{
+		ClientOptions: vs.armClientOptions.ClientOptions,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating blob client: %w", err)
+	}
+
+	_, err = client.CreateContainer(ctx, containerName, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return "", fmt.Errorf("creating container '%s': %w", containerName, err)
+	}
+
+	file, err := os.Open(artifactPath)
+	if err != nil {
+		return "", fmt.Errorf("opening artifact: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := client.UploadFile(ctx, containerName, blobName, file, nil); err != nil {
+		return "", fmt.Errorf("uploading artifact to blob '%s': %w", blobName, err)
+	}
+
+	// The blob is shared with a user delegation SAS, so the storage account doesn't need to allow shared key access
+	start := time.Now().UTC().Add(-5 * time.Minute)
+	end := time.Now().UTC().Add(expiry)
+	delegationCredential, err := client.ServiceClient().GetUserDelegationCredential(ctx, service.KeyInfo{
+		Start:  to.Ptr(start.Format(sas.TimeFormat)),
+		Expiry: to.Ptr(end.Format(sas.TimeFormat)),
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting user delegation key: %w", err)
+	}
+
+	queryParams, err := sas.BlobSignatureValues{
+		Protocol:      sas.ProtocolHTTPS,
+		StartTime:     start,
+		ExpiryTime:    end,
+		Permissions:   to.Ptr(sas.BlobPermissions{Read: true}).String(),
+		ContainerName: containerName,
+		BlobName:      blobName,
+	}.SignWithUserDelegation(delegationCredential)
+	if err != nil {
+		return "", fmt.Errorf("signing artifact url: %w", err)
+	}
+
+	blobUrl := client.ServiceClient().NewContainerClient(containerName).NewBlobClient(blobName).URL()
+	return blobUrl + "?" + queryParams.Encode(), nil
+}
+
+func (vs *virtualMachineService) RunVirtualMachineCommand(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	vmName string,
+	command VirtualMachineRunCommand,
+) (*VirtualMachineRunCommandResult, error) {
+	credential, err := vs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionId, credential, vs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating virtual machine client: %w", err)
+	}
+
+	vm, err := vmClient.Get(ctx, resourceGroupName, vmName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting virtual machine: %w", err)
+	}
+
+	client, err := armcompute.NewVirtualMachineRunCommandsClient(subscriptionId, credential, vs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating run command client: %w", err)
+	}
+
+	poller, err := client.BeginCreateOrUpdate(
+		ctx, resourceGroupName, vmName, command.Name, runCommandResource(*vm.Location, command), nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting run command: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return nil, fmt.Errorf("running command: %w", err)
+	}
+
+	res, err := client.GetByVirtualMachine(
+		ctx,
+		resourceGroupName,
+		vmName,
+		command.Name,
+		&armcompute.VirtualMachineRunCommandsClientGetByVirtualMachineOptions{Expand: to.Ptr("instanceView")},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting run command result: %w", err)
+	}
+
+	return runCommandResult(res.Properties), nil
+}
+
+func (vs *virtualMachineService) ListVirtualMachineScaleSetInstances(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	vmScaleSetName string,
+) ([]string, error) {
+	credential, err := vs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armcompute.NewVirtualMachineScaleSetVMsClient(subscriptionId, credential, vs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating scale set instances client: %w", err)
+	}
+
+	instanceIds := []string{}
+	pager := client.NewListPager(resourceGroupName, vmScaleSetName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing scale set instances: %w", err)
+		}
+
+		for _, instance := range page.Value {
+			instanceIds = append(instanceIds, convert.ToValueWithDefault(instance.InstanceID, ""))
+		}
+	}
+
+	slices.Sort(instanceIds)
+	return instanceIds, nil
+}
+
+func (vs *virtualMachineService) RunVirtualMachineScaleSetCommand(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	vmScaleSetName string,
+	instanceId string,
+	command VirtualMachineRunCommand,
+) (*VirtualMachineRunCommandResult, error) {
+	credential, err := vs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	vmClient, err := armcompute.NewVirtualMachineScaleSetVMsClient(subscriptionId, credential, vs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating scale set instances client: %w", err)
+	}
+
+	instance, err := vmClient.Get(ctx, resourceGroupName, vmScaleSetName, instanceId, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting scale set instance '%s': %w", instanceId, err)
+	}
+
+	client, err := armcompute.NewVirtualMachineScaleSetVMRunCommandsClient(
+		subscriptionId, credential, vs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating run command client: %w", err)
+	}
+
+	poller, err := client.BeginCreateOrUpdate(
+		ctx,
+		resourceGroupName,
+		vmScaleSetName,
+		instanceId,
+		command.Name,
+		runCommandResource(*instance.Location, command),
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("starting run command: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return nil, fmt.Errorf("running command: %w", err)
+	}
+
+	res, err := client.Get(
+		ctx,
+		resourceGroupName,
+		vmScaleSetName,
+		instanceId,
+		command.Name,
+		&armcompute.VirtualMachineScaleSetVMRunCommandsClientGetOptions{Expand: to.Ptr("instanceView")},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting run command result: %w", err)
+	}
+
+	return runCommandResult(res.Properties), nil
+}
+
+// runCommandResource creates the managed run command resource that runs the script synchronously
+func runCommandResource(location string, command VirtualMachineRunCommand) armcompute.VirtualMachineRunCommand {
+	return armcompute.VirtualMachineRunCommand{
+		Location: to.Ptr(location),
+		Properties: &armcompute.VirtualMachineRunCommandProperties{
+			AsyncExecution:      to.Ptr(false),
+			Source:              &armcompute.VirtualMachineRunCommandScriptSource{Script: to.Ptr(command.Script)},
+			Parameters:          runCommandParameters(command.Parameters),
+			ProtectedParameters: runCommandParameters(command.ProtectedParameters),
+			TimeoutInSeconds:    to.Ptr(int32(command.Timeout.Seconds())),
+		},
+	}
+}
+
+// runCommandParameters converts the parameters of the script, sorted by name so the run command is stable
+func runCommandParameters(parameters map[string]string) []*armcompute.RunCommandInputParameter {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	result := make([]*armcompute.RunCommandInputParameter, len(names))
+	for idx, name := range names {
+		result[idx] = &armcompute.RunCommandInputParameter{
+			Name:  to.Ptr(name),
+			Value: to.Ptr(parameters[name]),
+		}
+	}
+
+	return result
+}
+
+func runCommandResult(properties *armcompute.VirtualMachineRunCommandProperties) *VirtualMachineRunCommandResult {
+	if properties == nil || properties.InstanceView == nil {
+		return &VirtualMachineRunCommandResult{State: string(armcompute.ExecutionStateUnknown), ExitCode: -1}
+	}
+
+	view := properties.InstanceView
+	return &VirtualMachineRunCommandResult{
+		State:    string(convert.ToValueWithDefault(view.ExecutionState, armcompute.ExecutionStateUnknown)),
+		ExitCode: int(convert.ToValueWithDefault(view.ExitCode, int32(-1))),
+		Output:   convert.ToValueWithDefault(view.Output, ""),
+		Error:    convert.ToValueWithDefault(view.Error, ""),
+	}
+}
@@ -28,8 +28,9 @@ func (cli *azCli) GetAppServiceProperties(
 		return nil, err
 	}
 
+	// Custom domains bound with a certificate are reported after the default host name
 	return &AzCliAppServiceProperties{
-		HostNames: []string{*webApp.Properties.DefaultHostName},
+		HostNames: append([]string{*webApp.Properties.DefaultHostName}, customDomainHostNames(webApp)...),
 	}, nil
 }
 
@@ -52,6 +53,27 @@ func (cli *azCli) appService(
 	return &webApp, nil
 }
 
+func (cli *azCli) appServiceSlot(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) (*armappservice.WebAppsClientGetResponse, error) {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := client.GetSlot(ctx, resourceGroup, appName, slotName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving webapp slot '%s' properties: %w", slotName, err)
+	}
+
+	// A slot is a site of its own, with its own host names
+	return &armappservice.WebAppsClientGetResponse{Site: slot.Site}, nil
+}
+
 func isLinuxWebApp(response *armappservice.WebAppsClientGetResponse) bool {
 	if *response.Kind == "app,linux" && response.Properties != nil && response.Properties.SiteConfig != nil &&
 		response.Properties.SiteConfig.LinuxFxVersion != nil &&
@@ -115,6 +137,24 @@ func resumeDeployment(err error, progressLog func(msg string)) bool {
 	return false
 }
 
+// GetAppServiceSlotProperties returns the properties of the deployment slot of the app service
+func (cli *azCli) GetAppServiceSlotProperties(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) (*AzCliAppServiceProperties, error) {
+	slot, err := cli.appServiceSlot(ctx, subscriptionId, resourceGroup, appName, slotName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzCliAppServiceProperties{
+		HostNames: []string{*slot.Properties.DefaultHostName},
+	}, nil
+}
+
 func (cli *azCli) DeployAppServiceZip(
 	ctx context.Context,
 	subscriptionId string,
@@ -128,6 +168,49 @@ func (cli *azCli) DeployAppServiceZip(
 		return nil, err
 	}
 
+	return cli.deployAppServiceZip(ctx, subscriptionId, resourceGroup, app, appName, "", deployZipFile, progressLog)
+}
+
+// DeployAppServiceSlotZip deploys the zip file to the deployment slot of the app service, ex) staging
+func (cli *azCli) DeployAppServiceSlotZip(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+	deployZipFile io.ReadSeeker,
+	progressLog func(string),
+) (*string, error) {
+	slot, err := cli.appServiceSlot(ctx, subscriptionId, resourceGroup, appName, slotName)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.deployAppServiceZip(ctx, subscriptionId, resourceGroup, slot, appName, slotName, deployZipFile, progressLog)
+}
+
+// SwapAppServiceSlot swaps the deployment slot of the app service with the production slot
+func (cli *azCli) SwapAppServiceSlot(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) error {
+	return cli.swapSlotWithProduction(ctx, subscriptionId, resourceGroup, appName, slotName)
+}
+
+// deployAppServiceZip deploys the zip file to the app service, or to its deployment slot when the slot is not empty
+func (cli *azCli) deployAppServiceZip(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	app *armappservice.WebAppsClientGetResponse,
+	appName string,
+	slotName string,
+	deployZipFile io.ReadSeeker,
+	progressLog func(string),
+) (*string, error) {
 	hostName, err := appServiceRepositoryHost(app, appName)
 	if err != nil {
 		return nil, err
@@ -138,8 +221,8 @@ func (cli *azCli) DeployAppServiceZip(
 		return nil, err
 	}
 
-	// Deployment Status API only support linux web app for now
-	if isLinuxWebApp(app) {
+	// Deployment Status API only support linux web app for now, and only tracks the production slot
+	if slotName == "" && isLinuxWebApp(app) {
 		if err := client.DeployTrackStatus(
 			ctx, deployZipFile, subscriptionId, resourceGroup, appName, progressLog); err != nil {
 			if !resumeDeployment(err, progressLog) {
@@ -160,6 +243,34 @@ func (cli *azCli) DeployAppServiceZip(
 	return to.Ptr(response.StatusText), nil
 }
 
+// swapSlotWithProduction swaps the deployment slot of the web app with the production slot
+func (cli *azCli) swapSlotWithProduction(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginSwapSlotWithProduction(ctx, resourceGroup, appName, armappservice.CsmSlotEntity{
+		TargetSlot:   to.Ptr(slotName),
+		PreserveVnet: to.Ptr(true),
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed swapping slot '%s' with production: %w", slotName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("failed swapping slot '%s' with production: %w", slotName, err)
+	}
+
+	return nil
+}
+
 func (cli *azCli) createWebAppsClient(ctx context.Context, subscriptionId string) (*armappservice.WebAppsClient, error) {
 	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
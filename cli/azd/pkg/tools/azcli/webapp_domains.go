@@ -0,0 +1,329 @@
+package azcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/sethvargo/go-retry"
+)
+
+const (
+	// The interval between the checks of a managed certificate being issued
+	appServiceCertificatePollInterval = 10 * time.Second
+	// How long the deployment waits for a managed certificate to be issued
+	appServiceCertificateTimeout = 10 * time.Minute
+)
+
+// AzCliAppServiceCustomDomain is a custom domain of an app service, bound with an App Service managed certificate
+type AzCliAppServiceCustomDomain struct {
+	// ex) api.contoso.com
+	HostName string
+	// The type of the DNS record mapping the domain to the app service, CNAME or A. Defaults to CNAME.
+	// A records are used for apex domains, ex) contoso.com
+	DnsRecordType string
+}
+
+// isApex returns true when the domain is mapped to the app service with an A record
+func (domain *AzCliAppServiceCustomDomain) isApex() bool {
+	return strings.EqualFold(domain.DnsRecordType, "A")
+}
+
+// BindAppServiceCustomDomains adds the custom domains to the app service and binds them with App Service managed
+// certificates. Domains already bound with a certificate are left unchanged. Returns
+// azure.ErrCustomDomainNotVerified with the DNS records to create when the ownership of a domain can't be verified.
+func (cli *azCli) BindAppServiceCustomDomains(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	domains []*AzCliAppServiceCustomDomain,
+) error {
+	app, err := cli.appService(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return err
+	}
+
+	pending := []*AzCliAppServiceCustomDomain{}
+	for _, domain := range domains {
+		if !hasSniBinding(app, domain.HostName) {
+			pending = append(pending, domain)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	verificationErrs := []error{}
+	for _, domain := range pending {
+		if err := verifyAppServiceCustomDomain(ctx, client, resourceGroup, app, appName, domain); err != nil {
+			verificationErrs = append(verificationErrs, err)
+		}
+	}
+
+	if len(verificationErrs) > 0 {
+		return errors.Join(verificationErrs...)
+	}
+
+	for _, domain := range pending {
+		// The host name is added to the app service before the managed certificate is issued for it
+		if err := createHostNameBinding(ctx, client, resourceGroup, appName, domain, ""); err != nil {
+			return err
+		}
+
+		thumbprint, err := cli.ensureAppServiceCertificate(ctx, subscriptionId, resourceGroup, app, domain)
+		if err != nil {
+			return err
+		}
+
+		if err := createHostNameBinding(ctx, client, resourceGroup, appName, domain, thumbprint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasSniBinding returns true when the host name of the app service is bound with a certificate
+func hasSniBinding(app *armappservice.WebAppsClientGetResponse, hostName string) bool {
+	if app.Properties == nil {
+		return false
+	}
+
+	for _, state := range app.Properties.HostNameSSLStates {
+		if state.Name != nil && strings.EqualFold(*state.Name, hostName) &&
+			convert.ToValueWithDefault(state.SSLState, "") == armappservice.SSLStateSniEnabled {
+			return true
+		}
+	}
+
+	return false
+}
+
+// customDomainHostNames returns the custom host names of the app service bound with a certificate
+func customDomainHostNames(app *armappservice.WebAppsClientGetResponse) []string {
+	hostNames := []string{}
+	if app.Properties == nil {
+		return hostNames
+	}
+
+	for _, state := range app.Properties.HostNameSSLStates {
+		if state.Name != nil &&
+			convert.ToValueWithDefault(state.HostType, "") == armappservice.HostTypeStandard &&
+			convert.ToValueWithDefault(state.SSLState, "") == armappservice.SSLStateSniEnabled &&
+			!strings.EqualFold(*state.Name, convert.ToValueWithDefault(app.Properties.DefaultHostName, "")) {
+			hostNames = append(hostNames, *state.Name)
+		}
+	}
+
+	return hostNames
+}
+
+// verifyAppServiceCustomDomain verifies the DNS records proving the ownership of the custom domain by the app service
+func verifyAppServiceCustomDomain(
+	ctx context.Context,
+	client *armappservice.WebAppsClient,
+	resourceGroup string,
+	app *armappservice.WebAppsClientGetResponse,
+	appName string,
+	domain *AzCliAppServiceCustomDomain,
+) error {
+	analysis, err := client.AnalyzeCustomHostname(
+		ctx,
+		resourceGroup,
+		appName,
+		&armappservice.WebAppsClientAnalyzeCustomHostnameOptions{HostName: to.Ptr(domain.HostName)},
+	)
+	if err != nil {
+		return fmt.Errorf("analyzing custom domain '%s': %w", domain.HostName, err)
+	}
+
+	result := analysis.Properties
+	if result == nil {
+		result = &armappservice.CustomHostnameAnalysisResultProperties{}
+	}
+
+	if convert.ToValueWithDefault(result.HasConflictOnScaleUnit, false) ||
+		convert.ToValueWithDefault(result.HasConflictAcrossSubscription, false) {
+		return fmt.Errorf(
+			"custom domain '%s' is already used by app '%s'",
+			domain.HostName,
+			convert.ToValueWithDefault(result.ConflictingAppResourceID, ""),
+		)
+	}
+
+	if convert.ToValueWithDefault(result.IsHostnameAlreadyVerified, false) ||
+		convert.ToValueWithDefault(
+			result.CustomDomainVerificationTest, "") == armappservice.DNSVerificationTestResultPassed {
+		return nil
+	}
+
+	reason := ""
+	if result.CustomDomainVerificationFailureInfo != nil {
+		reason = convert.ToValueWithDefault(result.CustomDomainVerificationFailureInfo.Message, "")
+	}
+
+	defaultHostName := ""
+	verificationId := ""
+	if app.Properties != nil {
+		defaultHostName = convert.ToValueWithDefault(app.Properties.DefaultHostName, "")
+		verificationId = convert.ToValueWithDefault(app.Properties.CustomDomainVerificationID, "")
+	}
+
+	record := fmt.Sprintf("CNAME %s -> %s", domain.HostName, defaultHostName)
+	if domain.isApex() {
+		record = fmt.Sprintf("A %s -> the inbound IP address of app '%s'", domain.HostName, appName)
+	}
+
+	return azure.NewCustomDomainNotVerifiedError(domain.HostName, reason, record, verificationId)
+}
+
+// createHostNameBinding adds the host name to the app service, bound with the certificate when the thumbprint is set
+func createHostNameBinding(
+	ctx context.Context,
+	client *armappservice.WebAppsClient,
+	resourceGroup string,
+	appName string,
+	domain *AzCliAppServiceCustomDomain,
+	thumbprint string,
+) error {
+	recordType := armappservice.CustomHostNameDNSRecordTypeCName
+	if domain.isApex() {
+		recordType = armappservice.CustomHostNameDNSRecordTypeA
+	}
+
+	properties := &armappservice.HostNameBindingProperties{
+		SiteName:                    to.Ptr(appName),
+		HostNameType:                to.Ptr(armappservice.HostNameTypeVerified),
+		CustomHostNameDNSRecordType: to.Ptr(recordType),
+		SSLState:                    to.Ptr(armappservice.SSLStateDisabled),
+	}
+
+	if thumbprint != "" {
+		properties.SSLState = to.Ptr(armappservice.SSLStateSniEnabled)
+		properties.Thumbprint = to.Ptr(thumbprint)
+	}
+
+	_, err := client.CreateOrUpdateHostNameBinding(
+		ctx,
+		resourceGroup,
+		appName,
+		domain.HostName,
+		armappservice.HostNameBinding{Properties: properties},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("binding custom domain '%s': %w", domain.HostName, err)
+	}
+
+	return nil
+}
+
+// ensureAppServiceCertificate returns the thumbprint of the App Service managed certificate of the custom domain,
+// issuing the certificate when the resource group of the app service has none for the domain
+func (cli *azCli) ensureAppServiceCertificate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	app *armappservice.WebAppsClientGetResponse,
+	domain *AzCliAppServiceCustomDomain,
+) (string, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := armappservice.NewCertificatesClient(subscriptionId, credential, cli.armClientOptions)
+	if err != nil {
+		return "", fmt.Errorf("creating Certificates client: %w", err)
+	}
+
+	pager := client.NewListByResourceGroupPager(resourceGroup, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("listing certificates of resource group '%s': %w", resourceGroup, err)
+		}
+
+		for _, certificate := range page.Value {
+			if certificate.Properties != nil && certificate.Properties.Thumbprint != nil &&
+				strings.EqualFold(convert.ToValueWithDefault(certificate.Properties.CanonicalName, ""), domain.HostName) {
+				log.Printf("using certificate '%s' for custom domain '%s'",
+					convert.ToValueWithDefault(certificate.Name, ""), domain.HostName)
+				return *certificate.Properties.Thumbprint, nil
+			}
+		}
+	}
+
+	properties := &armappservice.AppCertificateProperties{
+		CanonicalName: to.Ptr(domain.HostName),
+		Password:      to.Ptr(""),
+	}
+
+	if app.Properties != nil {
+		properties.ServerFarmID = app.Properties.ServerFarmID
+	}
+
+	// Certificates of apex domains are validated with an HTTP token served by the app service
+	if domain.isApex() {
+		properties.DomainValidationMethod = to.Ptr("http-token")
+	}
+
+	name := azure.ManagedCertificateName(domain.HostName)
+	response, err := client.CreateOrUpdate(
+		ctx,
+		resourceGroup,
+		name,
+		armappservice.AppCertificate{Location: app.Location, Properties: properties},
+		nil,
+	)
+
+	// The certificate is issued asynchronously when the request is accepted
+	var httpErr *azcore.ResponseError
+	if err != nil && !(errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusAccepted) {
+		return "", fmt.Errorf("issuing managed certificate for custom domain '%s': %w", domain.HostName, err)
+	}
+
+	if err == nil && response.Properties != nil && response.Properties.Thumbprint != nil {
+		return *response.Properties.Thumbprint, nil
+	}
+
+	thumbprint := ""
+	err = retry.Do(
+		ctx,
+		retry.WithMaxDuration(appServiceCertificateTimeout, retry.NewConstant(appServiceCertificatePollInterval)),
+		func(ctx context.Context) error {
+			certificate, err := client.Get(ctx, resourceGroup, name, nil)
+			if err != nil {
+				return retry.RetryableError(err)
+			}
+
+			if certificate.Properties == nil || certificate.Properties.Thumbprint == nil {
+				return retry.RetryableError(fmt.Errorf("certificate '%s' has not been issued yet", name))
+			}
+
+			thumbprint = *certificate.Properties.Thumbprint
+			return nil
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("issuing managed certificate for custom domain '%s': %w", domain.HostName, err)
+	}
+
+	return thumbprint, nil
+}
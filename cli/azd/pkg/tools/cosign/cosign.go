@@ -0,0 +1,69 @@
+package cosign
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Cli signs container images with the cosign CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// Sign signs the image reference, ex) contoso.azurecr.io/todo/api@sha256:4f2d..., keyless with the OIDC identity of
+// the current user or workload and pushes the signature to the registry. Returns the reference of the pushed
+// signature, ex) contoso.azurecr.io/todo/api:sha256-4f2d....sig
+func (cli *Cli) Sign(ctx context.Context, reference string) (string, error) {
+	log.Printf("signing image '%s' keyless", reference)
+
+	// --yes skips the confirmation of uploading to the transparency log, which would block non-interactive sessions
+	if _, err := cli.executeCommand(ctx, "sign", "--yes", reference); err != nil {
+		return "", fmt.Errorf("signing image '%s': %w", reference, err)
+	}
+
+	res, err := cli.executeCommand(ctx, "triangulate", reference)
+	if err != nil {
+		return "", fmt.Errorf("resolving signature of image '%s': %w", reference, err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("cosign"); err != nil {
+		return err
+	}
+
+	version, err := tools.ExecuteCommand(ctx, cli.commandRunner, "cosign", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("cosign version: %s", version)
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://docs.sigstore.dev/cosign/system_config/installation/"
+}
+
+func (cli *Cli) Name() string {
+	return "Cosign"
+}
+
+func (cli *Cli) executeCommand(ctx context.Context, args ...string) (exec.RunResult, error) {
+	return cli.commandRunner.Run(ctx, exec.NewRunArgs("cosign", args...))
+}
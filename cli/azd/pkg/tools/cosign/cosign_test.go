@@ -0,0 +1,33 @@
+package cosign
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Sign(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewCli(mockContext.CommandRunner)
+
+	var signArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "cosign sign")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		signArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "cosign triangulate")
+	}).Respond(exec.NewRunResult(0, "contoso.azurecr.io/todo/api:sha256-4f2d.sig\n", ""))
+
+	signature, err := cli.Sign(*mockContext.Context, "contoso.azurecr.io/todo/api@sha256:4f2d")
+	require.NoError(t, err)
+	require.Equal(t, "contoso.azurecr.io/todo/api:sha256-4f2d.sig", signature)
+	require.Equal(t, []string{"sign", "--yes", "contoso.azurecr.io/todo/api@sha256:4f2d"}, signArgs.Args)
+}
@@ -0,0 +1,137 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// BuildCache configures the registry the layers of a build are imported from and exported to
+type BuildCache struct {
+	// The buildx builder used for the build, ex) azd-multiplatform
+	// The default docker driver cannot export the cache to a registry, so a docker-container builder is used.
+	Builder string
+	// The repository of the cache, ex) contoso.azurecr.io/todo/api-cache
+	Repository string
+	// The tag of the cache, ex) buildcache
+	Tag string
+	// Exports the layers of all build stages when max, only the layers of the final image when min
+	Mode string
+	// When enabled, the layers are imported from the cache but the cache is not updated
+	ReadOnly bool
+}
+
+// Ref returns the image reference of the cache, ex) contoso.azurecr.io/todo/api-cache:buildcache
+func (c *BuildCache) Ref() string {
+	return fmt.Sprintf("%s:%s", c.Repository, c.Tag)
+}
+
+// buildCacheArgs returns the args that import and export the layers of the build from the cache registry
+func buildCacheArgs(runtime ContainerRuntime, cache *BuildCache) []string {
+	if cache == nil {
+		return nil
+	}
+
+	// Podman stores each layer as a tag of the cache repository, so the cache is referenced by the repository
+	if runtime == RuntimePodman {
+		args := []string{"--layers", "--cache-from", cache.Repository}
+		if !cache.ReadOnly {
+			args = append(args, "--cache-to", cache.Repository)
+		}
+
+		return args
+	}
+
+	args := []string{"--cache-from", fmt.Sprintf("type=registry,ref=%s", cache.Ref())}
+	if !cache.ReadOnly {
+		// Azure Container Registry requires the cache to be exported as an OCI image manifest
+		args = append(args, "--cache-to", fmt.Sprintf(
+			"type=registry,ref=%s,mode=%s,image-manifest=true,oci-mediatypes=true", cache.Ref(), cache.Mode))
+	}
+
+	return args
+}
+
+// The steps of the Dockerfile reported by BuildKit, ex) #6 [build 2/5] RUN npm ci
+var buildKitStepRegexp = regexp.MustCompile(`^#(\d+) \[[^\]]*\d+/\d+\] `)
+
+// The steps of the Dockerfile whose layers are reused, reported by BuildKit, ex) #6 CACHED
+var buildKitCachedRegexp = regexp.MustCompile(`^#(\d+) CACHED`)
+
+// The steps of the Dockerfile reported by podman, ex) [2/2] STEP 2/5: RUN npm ci
+var podmanStepRegexp = regexp.MustCompile(`^(\[\d+/\d+\] )?STEP \d+/\d+: `)
+
+// The steps of the Dockerfile whose layers are reused, reported by podman, ex) --> Using cache 4f2d...
+var podmanCachedRegexp = regexp.MustCompile(`^--> Using cache `)
+
+// BuildCacheStats counts the steps of the build whose layers are reused, from the plain progress output of the build
+// The output is written through to the inner writer.
+type BuildCacheStats struct {
+	inner  io.Writer
+	mu     sync.Mutex
+	line   []byte
+	steps  map[string]bool
+	cached map[string]bool
+	// podman reports the steps without ids, so the steps are counted
+	podmanSteps  int
+	podmanCached int
+}
+
+// NewBuildCacheStats returns a writer that counts the cached steps of the build output written to the inner writer
+// The inner writer can be nil when the build output is not displayed.
+func NewBuildCacheStats(inner io.Writer) *BuildCacheStats {
+	return &BuildCacheStats{
+		inner:  inner,
+		steps:  map[string]bool{},
+		cached: map[string]bool{},
+	}
+}
+
+func (s *BuildCacheStats) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.line = append(s.line, p...)
+	for {
+		i := bytes.IndexByte(s.line, '\n')
+		if i < 0 {
+			break
+		}
+
+		s.parseLine(string(bytes.TrimRight(s.line[:i], "\r")))
+		s.line = s.line[i+1:]
+	}
+	s.mu.Unlock()
+
+	if s.inner == nil {
+		return len(p), nil
+	}
+
+	return s.inner.Write(p)
+}
+
+func (s *BuildCacheStats) parseLine(line string) {
+	if match := buildKitStepRegexp.FindStringSubmatch(line); match != nil {
+		s.steps[match[1]] = true
+	} else if match := buildKitCachedRegexp.FindStringSubmatch(line); match != nil {
+		s.cached[match[1]] = true
+	} else if podmanStepRegexp.MatchString(line) {
+		s.podmanSteps++
+	} else if podmanCachedRegexp.MatchString(line) {
+		s.podmanCached++
+	}
+}
+
+// Stats returns the number of steps of the build and the number of steps whose layers were reused from the cache
+func (s *BuildCacheStats) Stats() (steps int, cached int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.cached {
+		if s.steps[id] {
+			cached++
+		}
+	}
+
+	return len(s.steps) + s.podmanSteps, cached + s.podmanCached
+}
@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DockerBuildWithCache(t *testing.T) {
+	cache := &BuildCache{
+		Builder:    MultiPlatformBuilder,
+		Repository: "contoso.azurecr.io/todo/api-cache",
+		Tag:        "buildcache",
+		Mode:       "max",
+	}
+
+	tests := map[string]struct {
+		runtime      ContainerRuntime
+		readOnly     bool
+		expectedArgs []string
+	}{
+		"Docker": {
+			runtime: RuntimeDocker,
+			expectedArgs: []string{
+				"buildx", "build", "--builder", "azd-multiplatform", "--load", "--progress", "plain",
+				"-f", "./Dockerfile", "--platform", "linux/amd64", "-t", "todo-api",
+				"--cache-from", "type=registry,ref=contoso.azurecr.io/todo/api-cache:buildcache",
+				"--cache-to", "type=registry,ref=contoso.azurecr.io/todo/api-cache:buildcache," +
+					"mode=max,image-manifest=true,oci-mediatypes=true",
+				".",
+			},
+		},
+		"ReadOnly": {
+			runtime:  RuntimeDocker,
+			readOnly: true,
+			expectedArgs: []string{
+				"buildx", "build", "--builder", "azd-multiplatform", "--load", "--progress", "plain",
+				"-f", "./Dockerfile", "--platform", "linux/amd64", "-t", "todo-api",
+				"--cache-from", "type=registry,ref=contoso.azurecr.io/todo/api-cache:buildcache",
+				".",
+			},
+		},
+		"Podman": {
+			runtime: RuntimePodman,
+			expectedArgs: []string{
+				"build", "-f", "./Dockerfile", "--platform", "linux/amd64", "-t", "todo-api",
+				"--layers",
+				"--cache-from", "contoso.azurecr.io/todo/api-cache",
+				"--cache-to", "contoso.azurecr.io/todo/api-cache",
+				".",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(ContainerRuntimeEnvVarName, string(test.runtime))
+			mockContext := mocks.NewMockContext(context.Background())
+			docker := NewCli(mockContext.CommandRunner)
+
+			var buildArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.HasPrefix(command, string(test.runtime))
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				buildArgs = args
+
+				// "--iidfile" and path args are expected always at the end
+				err := os.WriteFile(args.Args[len(args.Args)-1], []byte("sha256:4f2d"), 0600)
+				require.NoError(t, err)
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			buildCache := *cache
+			buildCache.ReadOnly = test.readOnly
+
+			imageId, err := docker.Build(
+				*mockContext.Context, "./src/api", "./Dockerfile", "", "", ".", "todo-api", nil, nil, nil, &buildCache, nil)
+
+			require.NoError(t, err)
+			require.Equal(t, "sha256:4f2d", imageId)
+			require.Equal(t, test.expectedArgs, buildArgs.Args[:len(buildArgs.Args)-2])
+		})
+	}
+}
+
+func Test_BuildCacheStats(t *testing.T) {
+	t.Run("BuildKit", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		stats := NewBuildCacheStats(output)
+
+		lines := []string{
+			"#1 [internal] load build definition from Dockerfile",
+			"#5 importing cache manifest from contoso.azurecr.io/todo/api-cache:buildcache",
+			"#6 [build 1/4] FROM docker.io/library/node:20@sha256:4f2d",
+			"#6 CACHED",
+			"#7 [build 2/4] COPY package*.json ./",
+			"#7 CACHED",
+			"#8 [build 3/4] RUN npm ci",
+			"#8 CACHED",
+			"#9 [build 4/4] COPY . .",
+			"#9 DONE 0.1s",
+			"#10 exporting cache to registry",
+			"#10 CACHED",
+		}
+
+		// The output is written in chunks that do not align with the lines
+		content := strings.Join(lines, "\n") + "\n"
+		_, err := stats.Write([]byte(content[:50]))
+		require.NoError(t, err)
+		_, err = stats.Write([]byte(content[50:]))
+		require.NoError(t, err)
+
+		steps, cached := stats.Stats()
+		require.Equal(t, 4, steps)
+		require.Equal(t, 3, cached)
+		require.Equal(t, content, output.String())
+	})
+
+	t.Run("Podman", func(t *testing.T) {
+		stats := NewBuildCacheStats(nil)
+
+		_, err := stats.Write([]byte(strings.Join([]string{
+			"[1/2] STEP 1/3: FROM node:20 AS build",
+			"[1/2] STEP 2/3: RUN npm ci",
+			"--> Using cache 4f2d8a1b",
+			"[1/2] STEP 3/3: RUN npm run build",
+			"--> 9c3e5f7a",
+			"[2/2] STEP 1/1: FROM nginx",
+			"COMMIT todo-api",
+		}, "\r\n") + "\r\n"))
+		require.NoError(t, err)
+
+		steps, cached := stats.Stats()
+		require.Equal(t, 4, steps)
+		require.Equal(t, 1, cached)
+	})
+}
@@ -0,0 +1,236 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// The buildx builder created for multi-platform builds
+// The default docker driver cannot build images for multiple platforms, so a docker-container builder is used.
+const MultiPlatformBuilder = "azd-multiplatform"
+
+// MultiPlatformBuildOptions configures a multi-platform image build with buildx
+type MultiPlatformBuildOptions struct {
+	// The buildx builder used for the build, ex) azd-multiplatform
+	Builder        string
+	DockerFilePath string
+	// The platforms the image is built for, ex) linux/amd64 and linux/arm64
+	Platforms    []string
+	Target       string
+	BuildContext string
+	// The fully qualified tags the manifest list is pushed with, ex) contoso.azurecr.io/todo/api:azd-deploy-1700000000
+	Tags         []string
+	BuildArgs    []string
+	BuildSecrets []string
+	BuildEnv     []string
+	// The registry the layers of the build are imported from and exported to, nil to build without a cache
+	Cache *BuildCache
+}
+
+// EnsureBuilder creates the buildx builder with the docker-container driver when it does not exist yet
+// Podman and nerdctl build images for multiple platforms without a builder.
+func (d *Cli) EnsureBuilder(ctx context.Context, name string) error {
+	if d.Runtime() != RuntimeDocker {
+		return nil
+	}
+
+	if _, err := d.executeCommand(ctx, "", "buildx", "inspect", name); err == nil {
+		return nil
+	}
+
+	log.Printf("creating buildx builder '%s'", name)
+	if _, err := d.executeCommand(ctx, "", "buildx", "create", "--name", name, "--driver", "docker-container"); err != nil {
+		return fmt.Errorf("creating buildx builder '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// BuildMultiPlatform builds the image for each platform with buildx and pushes the manifest list referencing the
+// images of all platforms. Images for multiple platforms cannot be loaded into the local image store, so the images are
+// pushed as part of the build. Returns the digest of the pushed manifest list, ex) sha256:4f2d...
+// Podman builds the images into a local manifest list that is pushed afterwards, and nerdctl pushes the images of all
+// platforms of the tag. nerdctl does not report the digest of the pushed manifest list, so an empty digest is returned.
+func (d *Cli) BuildMultiPlatform(
+	ctx context.Context,
+	cwd string,
+	options MultiPlatformBuildOptions,
+	buildProgress io.Writer,
+) (string, error) {
+	if len(options.Platforms) == 0 {
+		return "", fmt.Errorf("building multi-platform image: no platforms specified")
+	}
+
+	if len(options.Tags) == 0 {
+		return "", fmt.Errorf("building multi-platform image: no tags specified")
+	}
+
+	switch d.Runtime() {
+	case RuntimePodman:
+		return d.buildPodmanManifest(ctx, cwd, options, buildProgress)
+	case RuntimeNerdctl:
+		return "", d.buildNerdctlMultiPlatform(ctx, cwd, options, buildProgress)
+	}
+
+	tmpFolder, err := os.MkdirTemp(os.TempDir(), "azd-docker-buildx")
+	defer func() {
+		// fail to remove tmp files is not so bad as the OS will delete it
+		// eventually
+		_ = os.RemoveAll(tmpFolder)
+	}()
+
+	if err != nil {
+		return "", fmt.Errorf("building multi-platform image: %w", err)
+	}
+	metadataFile := filepath.Join(tmpFolder, "metadata.json")
+
+	args := []string{"buildx", "build"}
+	if options.Builder != "" {
+		args = append(args, "--builder", options.Builder)
+	}
+
+	args = append(args, multiPlatformBuildArgs(options)...)
+	args = append(args, buildCacheArgs(RuntimeDocker, options.Cache)...)
+	for _, tag := range options.Tags {
+		args = append(args, "-t", tag)
+	}
+
+	args = append(args, "--push", options.BuildContext)
+
+	// create a file with the build metadata, including the digest of the manifest list
+	args = append(args, "--metadata-file", metadataFile)
+
+	runArgs := exec.NewRunArgs("docker", args...).WithCwd(cwd).WithEnv(options.BuildEnv)
+	if buildProgress != nil {
+		// buildx reports the progress of the build on stderr
+		runArgs = runArgs.WithStdOut(buildProgress).WithStdErr(buildProgress)
+	}
+
+	if _, err := d.commandRunner.Run(ctx, runArgs); err != nil {
+		return "", fmt.Errorf("building multi-platform image: %w", err)
+	}
+
+	metadataJson, err := os.ReadFile(metadataFile)
+	if err != nil {
+		return "", fmt.Errorf("building multi-platform image: %w", err)
+	}
+
+	var metadata struct {
+		Digest string `json:"containerimage.digest"`
+	}
+	if err := json.Unmarshal(metadataJson, &metadata); err != nil {
+		return "", fmt.Errorf("building multi-platform image: reading build metadata: %w", err)
+	}
+
+	return metadata.Digest, nil
+}
+
+// buildPodmanManifest builds the image for each platform into a local manifest list with podman and pushes the
+// manifest list along with the images of all platforms
+func (d *Cli) buildPodmanManifest(
+	ctx context.Context,
+	cwd string,
+	options MultiPlatformBuildOptions,
+	buildProgress io.Writer,
+) (string, error) {
+	tmpFolder, err := os.MkdirTemp(os.TempDir(), "azd-podman-manifest")
+	defer func() {
+		_ = os.RemoveAll(tmpFolder)
+	}()
+
+	if err != nil {
+		return "", fmt.Errorf("building multi-platform image: %w", err)
+	}
+	digestFile := filepath.Join(tmpFolder, "digest")
+
+	// The manifest list is named after the first tag, building the platforms adds their images to the list
+	manifest := options.Tags[0]
+	args := append([]string{"build", "--manifest", manifest}, multiPlatformBuildArgs(options)...)
+	args = append(args, buildCacheArgs(RuntimePodman, options.Cache)...)
+	args = append(args, options.BuildContext)
+
+	runArgs := exec.NewRunArgs(string(RuntimePodman), args...).WithCwd(cwd).WithEnv(options.BuildEnv)
+	if buildProgress != nil {
+		runArgs = runArgs.WithStdOut(buildProgress).WithStdErr(buildProgress)
+	}
+
+	if _, err := d.commandRunner.Run(ctx, runArgs); err != nil {
+		return "", fmt.Errorf("building multi-platform image: %w", err)
+	}
+
+	for _, tag := range options.Tags {
+		_, err := d.executeCommand(ctx, cwd, "manifest", "push", "--all", "--digestfile", digestFile, manifest, tag)
+		if err != nil {
+			return "", fmt.Errorf("pushing multi-platform image: %w", err)
+		}
+	}
+
+	digest, err := os.ReadFile(digestFile)
+	if err != nil {
+		return "", fmt.Errorf("pushing multi-platform image: %w", err)
+	}
+
+	return strings.TrimSpace(string(digest)), nil
+}
+
+// buildNerdctlMultiPlatform builds the image for each platform with nerdctl and pushes the images of all platforms
+func (d *Cli) buildNerdctlMultiPlatform(
+	ctx context.Context,
+	cwd string,
+	options MultiPlatformBuildOptions,
+	buildProgress io.Writer,
+) error {
+	args := append([]string{"build"}, multiPlatformBuildArgs(options)...)
+	args = append(args, buildCacheArgs(RuntimeNerdctl, options.Cache)...)
+	for _, tag := range options.Tags {
+		args = append(args, "-t", tag)
+	}
+	args = append(args, options.BuildContext)
+
+	runArgs := exec.NewRunArgs(string(RuntimeNerdctl), args...).WithCwd(cwd).WithEnv(options.BuildEnv)
+	if buildProgress != nil {
+		runArgs = runArgs.WithStdOut(buildProgress).WithStdErr(buildProgress)
+	}
+
+	if _, err := d.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("building multi-platform image: %w", err)
+	}
+
+	for _, tag := range options.Tags {
+		if _, err := d.executeCommand(ctx, cwd, "push", "--all-platforms", tag); err != nil {
+			return fmt.Errorf("pushing multi-platform image: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// multiPlatformBuildArgs returns the build args shared by the container runtimes, ex) the dockerfile and platforms
+func multiPlatformBuildArgs(options MultiPlatformBuildOptions) []string {
+	args := []string{
+		"-f", options.DockerFilePath,
+		"--platform", strings.Join(options.Platforms, ","),
+	}
+
+	if options.Target != "" {
+		args = append(args, "--target", options.Target)
+	}
+
+	for _, arg := range options.BuildArgs {
+		args = append(args, "--build-arg", arg)
+	}
+
+	for _, arg := range options.BuildSecrets {
+		args = append(args, "--secret", arg)
+	}
+
+	return args
+}
@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DockerBuildMultiPlatform(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewCli(mockContext.CommandRunner)
+
+	var buildArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		buildArgs = args
+
+		// "--metadata-file" and path args are expected always at the end
+		err := os.WriteFile(args.Args[len(args.Args)-1], []byte(`{"containerimage.digest":"sha256:4f2d"}`), 0600)
+		require.NoError(t, err)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	digest, err := docker.BuildMultiPlatform(*mockContext.Context, "./src/api", MultiPlatformBuildOptions{
+		Builder:        MultiPlatformBuilder,
+		DockerFilePath: "./Dockerfile",
+		Platforms:      []string{"linux/amd64", "linux/arm64"},
+		Target:         "runtime",
+		BuildContext:   ".",
+		Tags:           []string{"contoso.azurecr.io/todo/api:azd-deploy-1"},
+		BuildArgs:      []string{"VERSION=1.2.3"},
+	}, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "sha256:4f2d", digest)
+	require.Equal(t, "./src/api", buildArgs.Cwd)
+	require.Equal(t, []string{
+		"buildx", "build",
+		"--builder", "azd-multiplatform",
+		"-f", "./Dockerfile",
+		"--platform", "linux/amd64,linux/arm64",
+		"--target", "runtime",
+		"--build-arg", "VERSION=1.2.3",
+		"-t", "contoso.azurecr.io/todo/api:azd-deploy-1",
+		"--push", ".",
+	}, buildArgs.Args[:len(buildArgs.Args)-2])
+}
+
+func Test_DockerEnsureBuilder(t *testing.T) {
+	tests := map[string]struct {
+		exists          bool
+		expectedCreated bool
+	}{
+		"Exists":  {exists: true},
+		"Missing": {expectedCreated: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			docker := NewCli(mockContext.CommandRunner)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker buildx inspect azd-multiplatform")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				if test.exists {
+					return exec.NewRunResult(0, "", ""), nil
+				}
+
+				return exec.NewRunResult(1, "", "ERROR: no builder \"azd-multiplatform\" found"), errors.New("exit code: 1")
+			})
+
+			created := false
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker buildx create")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				created = true
+				require.Equal(t, []string{
+					"buildx", "create", "--name", "azd-multiplatform", "--driver", "docker-container",
+				}, args.Args)
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			require.NoError(t, docker.EnsureBuilder(*mockContext.Context, MultiPlatformBuilder))
+			require.Equal(t, test.expectedCreated, created)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+var ErrCredentialsNotFound = errors.New("no credentials found for the container registry")
+
+// The key of Docker Hub in the docker config file
+const dockerHubConfigKey = "https://index.docker.io/v1/"
+
+// dockerConfig is the subset of the docker config file holding the registry credentials, ex) ~/.docker/config.json
+// Podman stores the registry credentials in the same format, ex) ${XDG_RUNTIME_DIR}/containers/auth.json
+type dockerConfig struct {
+	Auths map[string]struct {
+		// The base64 encoded username:password
+		Auth string `json:"auth"`
+	} `json:"auths"`
+	// The credential helper storing the credentials of all registries, ex) desktop or osxkeychain
+	CredsStore string `json:"credsStore"`
+	// The credential helpers storing the credentials by registry, ex) ghcr.io: gh
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// Credentials returns the username and password the container runtime is logged into the registry server with,
+// ex) with 'docker login ghcr.io'. The credentials are read from the docker config file, or the credential helper the
+// config file refers to. Returns ErrCredentialsNotFound when the runtime is not logged into the registry.
+func (d *Cli) Credentials(ctx context.Context, server string) (string, string, error) {
+	config, err := d.readConfig()
+	if err != nil {
+		return "", "", err
+	}
+
+	key := server
+	if server == "docker.io" || server == "index.docker.io" || server == "registry-1.docker.io" {
+		key = dockerHubConfigKey
+	}
+
+	helper := config.CredsStore
+	if credHelper, has := config.CredHelpers[key]; has {
+		helper = credHelper
+	}
+
+	auth, has := config.Auths[key]
+	if !has {
+		// Older docker versions store the credentials by the url of the registry
+		auth, has = config.Auths["https://"+server]
+	}
+
+	if has && auth.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("reading credentials of registry '%s': %w", server, err)
+		}
+
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return "", "", fmt.Errorf("reading credentials of registry '%s': invalid auth format", server)
+		}
+
+		return username, password, nil
+	}
+
+	if helper == "" {
+		return "", "", fmt.Errorf("%w '%s'", ErrCredentialsNotFound, server)
+	}
+
+	return d.helperCredentials(ctx, helper, key)
+}
+
+// helperCredentials returns the credentials of the registry stored by the docker credential helper
+func (d *Cli) helperCredentials(ctx context.Context, helper string, server string) (string, string, error) {
+	runArgs := exec.NewRunArgs(fmt.Sprintf("docker-credential-%s", helper), "get").WithStdIn(strings.NewReader(server))
+	res, err := d.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		// Credential helpers report registries without credentials on stdout
+		if strings.Contains(res.Stdout, "credentials not found") {
+			return "", "", fmt.Errorf("%w '%s'", ErrCredentialsNotFound, server)
+		}
+
+		return "", "", fmt.Errorf(
+			"reading credentials of registry '%s' from credential helper '%s': %w", server, helper, err)
+	}
+
+	var credentials struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &credentials); err != nil {
+		return "", "", fmt.Errorf(
+			"reading credentials of registry '%s' from credential helper '%s': %w", server, helper, err)
+	}
+
+	return credentials.Username, credentials.Secret, nil
+}
+
+// readConfig reads the config file of the container runtime, an empty config when the file does not exist
+func (d *Cli) readConfig() (*dockerConfig, error) {
+	configPath, err := d.configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &dockerConfig{}
+	configJson, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	if err := json.Unmarshal(configJson, config); err != nil {
+		return nil, fmt.Errorf("reading config file '%s': %w", configPath, err)
+	}
+
+	return config, nil
+}
+
+// configPath returns the path of the config file holding the registry credentials of the container runtime
+func (d *Cli) configPath() (string, error) {
+	if d.Runtime() == RuntimePodman {
+		if authFile := os.Getenv("REGISTRY_AUTH_FILE"); authFile != "" {
+			return authFile, nil
+		}
+
+		if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+			return filepath.Join(runtimeDir, "containers", "auth.json"), nil
+		}
+	}
+
+	if configDir := os.Getenv("DOCKER_CONFIG"); configDir != "" {
+		return filepath.Join(configDir, "config.json"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("reading config file: %w", err)
+	}
+
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
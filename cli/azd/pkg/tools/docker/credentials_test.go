@@ -0,0 +1,116 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DockerCredentials(t *testing.T) {
+	tests := map[string]struct {
+		config           string
+		server           string
+		helperStdout     string
+		helperError      bool
+		expectedHelper   string
+		expectedUsername string
+		expectedPassword string
+		expectedNotFound bool
+	}{
+		"Auths": {
+			// base64 of octocat:GHCR_TOKEN
+			config:           `{"auths":{"ghcr.io":{"auth":"b2N0b2NhdDpHSENSX1RPS0VO"}}}`,
+			server:           "ghcr.io",
+			expectedUsername: "octocat",
+			expectedPassword: "GHCR_TOKEN",
+		},
+		"DockerHub": {
+			// base64 of contoso:HUB_TOKEN
+			config:           `{"auths":{"https://index.docker.io/v1/":{"auth":"Y29udG9zbzpIVUJfVE9LRU4="}}}`,
+			server:           "docker.io",
+			expectedUsername: "contoso",
+			expectedPassword: "HUB_TOKEN",
+		},
+		"CredsStore": {
+			config:           `{"auths":{"ghcr.io":{}},"credsStore":"desktop"}`,
+			server:           "ghcr.io",
+			helperStdout:     `{"ServerURL":"ghcr.io","Username":"octocat","Secret":"GHCR_TOKEN"}`,
+			expectedHelper:   "docker-credential-desktop",
+			expectedUsername: "octocat",
+			expectedPassword: "GHCR_TOKEN",
+		},
+		"CredHelpers": {
+			config:           `{"credsStore":"desktop","credHelpers":{"contoso.jfrog.io":"jfrog"}}`,
+			server:           "contoso.jfrog.io",
+			helperStdout:     `{"ServerURL":"contoso.jfrog.io","Username":"builder","Secret":"JFROG_TOKEN"}`,
+			expectedHelper:   "docker-credential-jfrog",
+			expectedUsername: "builder",
+			expectedPassword: "JFROG_TOKEN",
+		},
+		"HelperNotFound": {
+			config:           `{"credsStore":"desktop"}`,
+			server:           "ghcr.io",
+			helperStdout:     "credentials not found in native keychain",
+			helperError:      true,
+			expectedHelper:   "docker-credential-desktop",
+			expectedNotFound: true,
+		},
+		"NoConfig": {
+			server:           "ghcr.io",
+			expectedNotFound: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+			t.Setenv("DOCKER_CONFIG", configDir)
+			t.Setenv(ContainerRuntimeEnvVarName, string(RuntimeDocker))
+			if test.config != "" {
+				err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(test.config), 0600)
+				require.NoError(t, err)
+			}
+
+			mockContext := mocks.NewMockContext(context.Background())
+			var helperStdIn string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.HasPrefix(command, "docker-credential-")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				require.Equal(t, test.expectedHelper, args.Cmd)
+				require.Equal(t, []string{"get"}, args.Args)
+
+				stdIn, err := io.ReadAll(args.StdIn)
+				require.NoError(t, err)
+				helperStdIn = string(stdIn)
+
+				if test.helperError {
+					return exec.NewRunResult(1, test.helperStdout, ""), errors.New("exit code: 1")
+				}
+
+				return exec.NewRunResult(0, test.helperStdout, ""), nil
+			})
+
+			docker := NewCli(mockContext.CommandRunner)
+			username, password, err := docker.Credentials(*mockContext.Context, test.server)
+			if test.expectedNotFound {
+				require.ErrorIs(t, err, ErrCredentialsNotFound)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedUsername, username)
+			require.Equal(t, test.expectedPassword, password)
+			if test.expectedHelper != "" {
+				require.Equal(t, test.server, helperStdIn)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -26,13 +27,17 @@ func NewCli(commandRunner exec.CommandRunner) *Cli {
 	}
 }
 
+// Cli executes the docker CLI, or the podman or nerdctl CLI when it is the container runtime of the machine
 type Cli struct {
 	commandRunner exec.CommandRunner
+	runtime       ContainerRuntime
+	runtimeErr    error
+	runtimeOnce   sync.Once
 }
 
 func (d *Cli) Login(ctx context.Context, loginServer string, username string, password string) error {
 	runArgs := exec.NewRunArgs(
-		"docker", "login",
+		string(d.Runtime()), "login",
 		"--username", username,
 		"--password-stdin",
 		loginServer,
@@ -49,6 +54,8 @@ func (d *Cli) Login(ctx context.Context, loginServer string, username string, pa
 // Runs a Docker build for a given Dockerfile, writing the output of docker build to [stdOut] when it is
 // not nil. If the platform is not specified (empty) it defaults to amd64. If the build is successful,
 // the function returns the image id of the built image.
+// When the cache is not nil, the layers are imported from and exported to the cache registry. Docker builds with the
+// buildx builder of the cache and loads the image into the local image store.
 func (d *Cli) Build(
 	ctx context.Context,
 	cwd string,
@@ -60,6 +67,7 @@ func (d *Cli) Build(
 	buildArgs []string,
 	buildSecrets []string,
 	buildEnv []string,
+	cache *BuildCache,
 	buildProgress io.Writer,
 ) (string, error) {
 	if strings.TrimSpace(platform) == "" {
@@ -78,11 +86,15 @@ func (d *Cli) Build(
 	}
 	imgIdFile := filepath.Join(tmpFolder, "imgId")
 
-	args := []string{
-		"build",
+	args := []string{"build"}
+	if cache != nil && d.Runtime() == RuntimeDocker {
+		args = []string{"buildx", "build", "--builder", cache.Builder, "--load", "--progress", "plain"}
+	}
+
+	args = append(args,
 		"-f", dockerFilePath,
 		"--platform", platform,
-	}
+	)
 
 	if target != "" {
 		args = append(args, "--target", target)
@@ -99,13 +111,15 @@ func (d *Cli) Build(
 	for _, arg := range buildSecrets {
 		args = append(args, "--secret", arg)
 	}
+
+	args = append(args, buildCacheArgs(d.Runtime(), cache)...)
 	args = append(args, buildContext)
 
 	// create a file with the docker img id
 	args = append(args, "--iidfile", imgIdFile)
 
 	// Build and produce output
-	runArgs := exec.NewRunArgs("docker", args...).WithCwd(cwd).WithEnv(buildEnv)
+	runArgs := exec.NewRunArgs(string(d.Runtime()), args...).WithCwd(cwd).WithEnv(buildEnv)
 
 	if buildProgress != nil {
 		// setting stderr and stdout both, as it's been noticed
@@ -135,7 +149,17 @@ func (d *Cli) Tag(ctx context.Context, cwd string, imageName string, tag string)
 }
 
 func (d *Cli) Push(ctx context.Context, cwd string, tag string) error {
-	_, err := d.executeCommand(ctx, cwd, "push", tag)
+	return d.PushWithProgress(ctx, cwd, tag, nil)
+}
+
+// PushWithProgress pushes the image, writing the output of the push to pushProgress when set
+func (d *Cli) PushWithProgress(ctx context.Context, cwd string, tag string, pushProgress io.Writer) error {
+	runArgs := exec.NewRunArgs(string(d.Runtime()), "push", tag).WithCwd(cwd)
+	if pushProgress != nil {
+		runArgs = runArgs.WithStdOut(pushProgress).WithStdErr(pushProgress)
+	}
+
+	_, err := d.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("pushing image: %w", err)
 	}
@@ -143,6 +167,41 @@ func (d *Cli) Push(ctx context.Context, cwd string, tag string) error {
 	return nil
 }
 
+// Load loads the images of the archive created by 'docker save', ex) image.tar, returning the references of the loaded
+// images. Images saved without a name are returned by their id, ex) sha256:4f2d...
+func (d *Cli) Load(ctx context.Context, archivePath string) ([]string, error) {
+	res, err := d.executeCommand(ctx, "", "load", "--input", archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("loading image archive: %w", err)
+	}
+
+	images := []string{}
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		// docker reports 'Loaded image: ' or 'Loaded image ID: ', podman reports 'Loaded image(s): ' or 'Loaded image: '
+		_, loaded, has := strings.Cut(line, "Loaded image")
+		if !has {
+			continue
+		}
+
+		_, refs, has := strings.Cut(loaded, ": ")
+		if !has {
+			continue
+		}
+
+		for _, ref := range strings.Split(refs, ",") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				images = append(images, ref)
+			}
+		}
+	}
+
+	if len(images) == 0 {
+		return nil, fmt.Errorf("loading image archive: no image loaded from '%s'", archivePath)
+	}
+
+	return images, nil
+}
+
 func (d *Cli) Pull(ctx context.Context, imageName string) error {
 	_, err := d.executeCommand(ctx, "", "pull", imageName)
 	if err != nil {
@@ -161,6 +220,22 @@ func (d *Cli) Inspect(ctx context.Context, imageName string, format string) (str
 	return out.Stdout, nil
 }
 
+// Sbom generates the software bill of materials of the local image with 'docker sbom' and writes it to the output
+// path in the format, ex) spdx-json or cyclonedx-json
+func (d *Cli) Sbom(ctx context.Context, imageName string, format string, outputPath string) error {
+	if runtime := d.Runtime(); runtime != RuntimeDocker {
+		return fmt.Errorf("generating sbom: 'docker sbom' is not supported by %s, use the syft generator instead",
+			runtimeNames[runtime])
+	}
+
+	_, err := d.executeCommand(ctx, "", "sbom", imageName, "--format", format, "--output", outputPath)
+	if err != nil {
+		return fmt.Errorf("generating sbom: %w", err)
+	}
+
+	return nil
+}
+
 func (d *Cli) versionInfo() tools.VersionInfo {
 	return tools.VersionInfo{
 		MinimumVersion: semver.Version{
@@ -247,6 +322,15 @@ func isSupportedDockerVersion(cliOutput string) (bool, error) {
 	return false, fmt.Errorf("could not determine version from docker version string: %s", version)
 }
 func (d *Cli) CheckInstalled(ctx context.Context) error {
+	runtime := d.Runtime()
+	if d.runtimeErr != nil {
+		return d.runtimeErr
+	}
+
+	if runtime != RuntimeDocker {
+		return d.checkRuntimeInstalled(ctx, runtime)
+	}
+
 	err := tools.ToolInPath("docker")
 	if err != nil {
 		return err
@@ -266,16 +350,42 @@ func (d *Cli) CheckInstalled(ctx context.Context) error {
 	return nil
 }
 
+// checkRuntimeInstalled checks the docker alternative is installed with at least the minimum supported version
+func (d *Cli) checkRuntimeInstalled(ctx context.Context, runtime ContainerRuntime) error {
+	if err := tools.ToolInPath(string(runtime)); err != nil {
+		return err
+	}
+
+	// ex) podman version 4.9.3 or nerdctl version 1.7.6
+	versionRes, err := tools.ExecuteCommand(ctx, d.commandRunner, string(runtime), "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", d.Name(), err)
+	}
+	log.Printf("%s version: %s", runtime, versionRes)
+
+	version, err := tools.ExtractVersion(versionRes)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+
+	versionInfo := runtimeVersionInfo(runtime)
+	if version.LT(versionInfo.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: d.Name(), VersionInfo: versionInfo}
+	}
+
+	return nil
+}
+
 func (d *Cli) InstallUrl() string {
-	return "https://aka.ms/azure-dev/docker-install"
+	return runtimeInstallUrls[d.Runtime()]
 }
 
 func (d *Cli) Name() string {
-	return "Docker"
+	return runtimeNames[d.Runtime()]
 }
 
 func (d *Cli) executeCommand(ctx context.Context, cwd string, args ...string) (exec.RunResult, error) {
-	runArgs := exec.NewRunArgs("docker", args...).
+	runArgs := exec.NewRunArgs(string(d.Runtime()), args...).
 		WithCwd(cwd)
 
 	return d.commandRunner.Run(ctx, runArgs)
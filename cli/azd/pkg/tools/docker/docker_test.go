@@ -70,6 +70,7 @@ func Test_DockerBuild(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		require.Equal(t, true, ran)
@@ -129,6 +130,7 @@ func Test_DockerBuild(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		require.Equal(t, true, ran)
@@ -185,7 +187,7 @@ func Test_DockerBuildEmptyPlatform(t *testing.T) {
 	})
 
 	result, err := docker.Build(
-		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil, nil)
+		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -234,7 +236,7 @@ func Test_DockerBuildArgsEmpty(t *testing.T) {
 	})
 
 	result, err := docker.Build(
-		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil, nil)
+		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -285,7 +287,7 @@ func Test_DockerBuildArgsMultiple(t *testing.T) {
 	})
 
 	result, err := docker.Build(
-		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil, nil)
+		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil, nil, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -443,6 +445,61 @@ func Test_DockerPush(t *testing.T) {
 	})
 }
 
+func Test_DockerLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdout   string
+		expected []string
+	}{
+		{
+			name:     "Named",
+			stdout:   "Loaded image: contoso.azurecr.io/todo/api:v1\nLoaded image: todo/api:latest\n",
+			expected: []string{"contoso.azurecr.io/todo/api:v1", "todo/api:latest"},
+		},
+		{
+			name:     "Unnamed",
+			stdout:   "Loaded image ID: sha256:4f2d\n",
+			expected: []string{"sha256:4f2d"},
+		},
+		{
+			name:     "Podman",
+			stdout:   "Getting image source signatures\nLoaded image(s): localhost/todo/api:v1\n",
+			expected: []string{"localhost/todo/api:v1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			docker := NewCli(mockContext.CommandRunner)
+
+			var loadArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker load")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				loadArgs = args
+				return exec.NewRunResult(0, tt.stdout, ""), nil
+			})
+
+			images, err := docker.Load(context.Background(), "./api.tar")
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, images)
+			require.Equal(t, []string{"load", "--input", "./api.tar"}, loadArgs.Args)
+		})
+	}
+
+	t.Run("NoImage", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewCli(mockContext.CommandRunner)
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker load")
+		}).Respond(exec.NewRunResult(0, "", ""))
+
+		_, err := docker.Load(context.Background(), "./api.tar")
+		require.ErrorContains(t, err, "no image loaded")
+	})
+}
+
 func Test_DockerLogin(t *testing.T) {
 	t.Run("NoError", func(t *testing.T) {
 		ran := false
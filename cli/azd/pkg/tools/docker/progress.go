@@ -0,0 +1,187 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The step of a stage of the Dockerfile reported by BuildKit, ex) #6 [build 2/5] RUN npm ci
+var buildKitStageStepRegexp = regexp.MustCompile(`^#\d+ \[(?:(\S+) )?(\d+/\d+)\] (.+)$`)
+
+// The export of the image reported by BuildKit, ex) #12 exporting to image
+var buildKitExportRegexp = regexp.MustCompile(`^#\d+ (exporting .+|pushing layers.*)$`)
+
+// The step of a stage of the Dockerfile reported by podman, ex) [2/2] STEP 2/5: RUN npm ci
+var podmanStageStepRegexp = regexp.MustCompile(`^(?:\[(\d+/\d+)\] )?STEP (\d+/\d+): (.+)$`)
+
+// The status of a layer reported by docker push, ex) 5f70bf18a086: Pushing [==>    ]  1.2MB/45MB
+var pushLayerRegexp = regexp.MustCompile(
+	`^([0-9a-f]{12,}): (Preparing|Waiting|Pushing|Pushed|Layer already exists|Mounted from .+)` +
+		`(?:\s+\[[=> ]*\]\s+([\d.]+\s*[kMG]?B)/([\d.]+\s*[kMG]?B))?`)
+
+// The layers reported by podman push, ex) Copying blob 5f70bf18a086 done
+var podmanPushLayerRegexp = regexp.MustCompile(`^Copying blob (?:sha256:)?([0-9a-f]+)( done| skipped.*)?`)
+
+// The longest instruction of a build step reported, longer instructions are truncated
+const maxReportedInstructionLength = 60
+
+// lineReporter splits the output written to it into lines, parsing each line and reporting the message of the line
+// when it changes. The output is written through to the inner writer.
+type lineReporter struct {
+	inner  io.Writer
+	report func(message string)
+	parse  func(line string) string
+	mu     sync.Mutex
+	line   []byte
+	last   string
+}
+
+func (r *lineReporter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	r.line = append(r.line, p...)
+	for {
+		i := bytes.IndexByte(r.line, '\n')
+		if i < 0 {
+			break
+		}
+
+		line := strings.TrimSpace(string(bytes.TrimRight(r.line[:i], "\r")))
+		r.line = r.line[i+1:]
+
+		if message := r.parse(line); message != "" && message != r.last {
+			r.last = message
+			r.report(message)
+		}
+	}
+	r.mu.Unlock()
+
+	if r.inner == nil {
+		return len(p), nil
+	}
+
+	return r.inner.Write(p)
+}
+
+// NewBuildProgress returns a writer that reports the current step of the build from the plain progress output of
+// BuildKit or podman, ex) build 2/5: RUN npm ci. The output is written through to the inner writer, which can be nil
+// when the build output is not displayed.
+func NewBuildProgress(inner io.Writer, report func(message string)) io.Writer {
+	return &lineReporter{
+		inner:  inner,
+		report: report,
+		parse:  parseBuildStep,
+	}
+}
+
+func parseBuildStep(line string) string {
+	var stage, step, instruction string
+	if match := buildKitStageStepRegexp.FindStringSubmatch(line); match != nil {
+		stage, step, instruction = match[1], match[2], match[3]
+	} else if match := podmanStageStepRegexp.FindStringSubmatch(line); match != nil {
+		stage, step, instruction = match[1], match[2], match[3]
+	} else if match := buildKitExportRegexp.FindStringSubmatch(line); match != nil {
+		return match[1]
+	} else {
+		return ""
+	}
+
+	if len(instruction) > maxReportedInstructionLength {
+		instruction = instruction[:maxReportedInstructionLength] + "..."
+	}
+
+	if stage == "" {
+		return fmt.Sprintf("step %s: %s", step, instruction)
+	}
+
+	return fmt.Sprintf("%s %s: %s", stage, step, instruction)
+}
+
+// NewPushProgress returns a writer that reports the layers of the image pushed to the registry from the output of
+// docker push or podman push, ex) 3 of 7 layers pushed (52%). The output is written through to the inner writer,
+// which can be nil when the push output is not displayed.
+func NewPushProgress(inner io.Writer, report func(message string)) io.Writer {
+	layers := &pushLayers{progress: map[string]float64{}}
+
+	return &lineReporter{
+		inner:  inner,
+		report: report,
+		parse:  layers.parse,
+	}
+}
+
+// pushLayers tracks the pushed fraction of each layer of the image, between 0 and 1
+type pushLayers struct {
+	order    []string
+	progress map[string]float64
+}
+
+func (l *pushLayers) parse(line string) string {
+	var layer string
+	var pushed float64
+	if match := pushLayerRegexp.FindStringSubmatch(line); match != nil {
+		layer = match[1]
+		switch {
+		case match[2] == "Pushing" && match[3] != "":
+			current, total := parseSize(match[3]), parseSize(match[4])
+			if total > 0 {
+				pushed = min(current/total, 1)
+			}
+		case match[2] == "Pushed" || match[2] == "Layer already exists" || strings.HasPrefix(match[2], "Mounted from"):
+			pushed = 1
+		}
+	} else if match := podmanPushLayerRegexp.FindStringSubmatch(line); match != nil {
+		layer = match[1]
+		if match[2] != "" {
+			pushed = 1
+		}
+	} else {
+		return ""
+	}
+
+	if _, has := l.progress[layer]; !has {
+		l.order = append(l.order, layer)
+	}
+
+	// A layer never goes back, ex) when a retried push reports the layer as waiting
+	l.progress[layer] = max(l.progress[layer], pushed)
+
+	done := 0
+	total := 0.0
+	for _, layer := range l.order {
+		if l.progress[layer] == 1 {
+			done++
+		}
+
+		total += l.progress[layer]
+	}
+
+	return fmt.Sprintf(
+		"%d of %d layers pushed (%d%%)", done, len(l.order), int(total*100/float64(len(l.order))))
+}
+
+// parseSize returns the number of bytes of a size reported by docker push, ex) 1.2MB
+func parseSize(size string) float64 {
+	size = strings.TrimSpace(size)
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(size, "kB"):
+		multiplier = 1e3
+	case strings.HasSuffix(size, "MB"):
+		multiplier = 1e6
+	case strings.HasSuffix(size, "GB"):
+		multiplier = 1e9
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimRight(size, "kMGB")), 64)
+	if err != nil {
+		return 0
+	}
+
+	return value * multiplier
+}
@@ -0,0 +1,118 @@
+package docker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name: "BuildKit",
+			output: `#1 [internal] load build definition from Dockerfile
+#5 [build 1/3] FROM docker.io/library/node:20
+#6 [build 2/3] RUN npm ci
+#6 12.34 added 200 packages
+#6 DONE 13.1s
+#7 [2/2] COPY --from=build /app/dist /usr/share/nginx/html
+#8 exporting to image
+`,
+			expected: []string{
+				"build 1/3: FROM docker.io/library/node:20",
+				"build 2/3: RUN npm ci",
+				"step 2/2: COPY --from=build /app/dist /usr/share/nginx/html",
+				"exporting to image",
+			},
+		},
+		{
+			name: "Podman",
+			output: `[1/2] STEP 1/3: FROM node:20 AS build
+[1/2] STEP 2/3: RUN npm ci
+--> Using cache 4f2d
+STEP 1/1: FROM nginx
+`,
+			expected: []string{
+				"1/2 1/3: FROM node:20 AS build",
+				"1/2 2/3: RUN npm ci",
+				"step 1/1: FROM nginx",
+			},
+		},
+		{
+			name:   "TruncatesInstruction",
+			output: "#6 [2/3] RUN " + strings.Repeat("a", 80) + "\n",
+			expected: []string{
+				"step 2/3: RUN " + strings.Repeat("a", 56) + "...",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported := []string{}
+			inner := &bytes.Buffer{}
+			writer := NewBuildProgress(inner, func(message string) {
+				reported = append(reported, message)
+			})
+
+			// The output is written in chunks that split the lines
+			for _, chunk := range strings.SplitAfter(tt.output, " ") {
+				_, err := writer.Write([]byte(chunk))
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.expected, reported)
+			require.Equal(t, tt.output, inner.String())
+		})
+	}
+}
+
+func Test_PushProgress(t *testing.T) {
+	t.Run("Docker", func(t *testing.T) {
+		reported := []string{}
+		writer := NewPushProgress(nil, func(message string) {
+			reported = append(reported, message)
+		})
+
+		_, err := writer.Write([]byte(`The push refers to repository [contoso.azurecr.io/todo/api]
+5f70bf18a086: Preparing
+a3b2c1d0e9f8: Preparing
+5f70bf18a086: Layer already exists
+a3b2c1d0e9f8: Pushing [=====>              ]  15MB/60MB
+a3b2c1d0e9f8: Pushed
+azd-deploy-1: digest: sha256:4f2d size: 1570
+`))
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"0 of 1 layers pushed (0%)",
+			"0 of 2 layers pushed (0%)",
+			"1 of 2 layers pushed (50%)",
+			"1 of 2 layers pushed (62%)",
+			"2 of 2 layers pushed (100%)",
+		}, reported)
+	})
+
+	t.Run("Podman", func(t *testing.T) {
+		reported := []string{}
+		writer := NewPushProgress(nil, func(message string) {
+			reported = append(reported, message)
+		})
+
+		_, err := writer.Write([]byte(`Getting image source signatures
+Copying blob sha256:5f70bf18a086
+Copying blob 5f70bf18a086 done
+Copying config 4f2d done
+Writing manifest to image destination
+`))
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"0 of 1 layers pushed (0%)", "1 of 1 layers pushed (100%)"}, reported)
+	})
+}
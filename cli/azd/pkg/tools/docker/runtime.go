@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+// ContainerRuntime is the CLI container images are built, tagged and pushed with
+type ContainerRuntime string
+
+const (
+	RuntimeDocker  ContainerRuntime = "docker"
+	RuntimePodman  ContainerRuntime = "podman"
+	RuntimeNerdctl ContainerRuntime = "nerdctl"
+)
+
+// The environment variable that selects the container runtime, ex) AZD_CONTAINER_RUNTIME=podman
+// When unset, the first runtime found on the PATH is used, in the order of docker, podman and nerdctl.
+const ContainerRuntimeEnvVarName = "AZD_CONTAINER_RUNTIME"
+
+// The supported container runtimes, in the order they are detected
+var containerRuntimes = []ContainerRuntime{RuntimeDocker, RuntimePodman, RuntimeNerdctl}
+
+// The minimum versions of the docker alternatives, with support for multi-platform builds and build secrets
+var containerRuntimeVersions = map[ContainerRuntime]semver.Version{
+	RuntimePodman:  semver.MustParse("4.0.0"),
+	RuntimeNerdctl: semver.MustParse("1.0.0"),
+}
+
+// Runtime returns the container runtime the commands are executed with
+func (d *Cli) Runtime() ContainerRuntime {
+	d.runtimeOnce.Do(func() {
+		d.runtime, d.runtimeErr = resolveRuntime()
+		log.Printf("using container runtime '%s'", d.runtime)
+	})
+
+	return d.runtime
+}
+
+// resolveRuntime returns the container runtime set by AZD_CONTAINER_RUNTIME, or the first runtime found on the PATH
+// Docker is returned when no runtime is found, so the missing tool is reported as docker.
+func resolveRuntime() (ContainerRuntime, error) {
+	if value, has := os.LookupEnv(ContainerRuntimeEnvVarName); has && value != "" {
+		runtime := ContainerRuntime(strings.ToLower(value))
+		if !slices.Contains(containerRuntimes, runtime) {
+			return RuntimeDocker, fmt.Errorf(
+				"unsupported container runtime '%s' set by %s, supported runtimes are docker, podman and nerdctl",
+				value, ContainerRuntimeEnvVarName,
+			)
+		}
+
+		return runtime, nil
+	}
+
+	for _, runtime := range containerRuntimes {
+		if err := tools.ToolInPath(string(runtime)); err == nil {
+			return runtime, nil
+		}
+	}
+
+	return RuntimeDocker, nil
+}
+
+// runtimeVersionInfo returns the minimum version of the docker alternatives
+func runtimeVersionInfo(runtime ContainerRuntime) tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: containerRuntimeVersions[runtime],
+		UpdateCommand:  fmt.Sprintf("Visit %s to upgrade", runtimeInstallUrls[runtime]),
+	}
+}
+
+// The install urls of the container runtimes
+var runtimeInstallUrls = map[ContainerRuntime]string{
+	RuntimeDocker:  "https://aka.ms/azure-dev/docker-install",
+	RuntimePodman:  "https://podman.io/docs/installation",
+	RuntimeNerdctl: "https://github.com/containerd/nerdctl#install",
+}
+
+// The display names of the container runtimes
+var runtimeNames = map[ContainerRuntime]string{
+	RuntimeDocker:  "Docker",
+	RuntimePodman:  "Podman",
+	RuntimeNerdctl: "nerdctl",
+}
@@ -0,0 +1,135 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Runtime(t *testing.T) {
+	t.Run("EnvironmentVariable", func(t *testing.T) {
+		t.Setenv(ContainerRuntimeEnvVarName, "Podman")
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewCli(mockContext.CommandRunner)
+
+		require.Equal(t, RuntimePodman, docker.Runtime())
+		require.Equal(t, "Podman", docker.Name())
+		require.Equal(t, "https://podman.io/docs/installation", docker.InstallUrl())
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		t.Setenv(ContainerRuntimeEnvVarName, "rancher")
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewCli(mockContext.CommandRunner)
+
+		require.Equal(t, RuntimeDocker, docker.Runtime())
+		require.ErrorContains(t, docker.CheckInstalled(*mockContext.Context), "unsupported container runtime 'rancher'")
+	})
+}
+
+func Test_RuntimeCommands(t *testing.T) {
+	t.Setenv(ContainerRuntimeEnvVarName, string(RuntimeNerdctl))
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewCli(mockContext.CommandRunner)
+
+	commands := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "nerdctl")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		commands = append(commands, args.Cmd+" "+args.Args[0])
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	ctx := *mockContext.Context
+	require.NoError(t, docker.Login(ctx, "contoso.azurecr.io", "user", "password"))
+	require.NoError(t, docker.Tag(ctx, "", "todo/api", "contoso.azurecr.io/todo/api"))
+	require.NoError(t, docker.Push(ctx, "", "contoso.azurecr.io/todo/api"))
+	require.Equal(t, []string{"nerdctl login", "nerdctl tag", "nerdctl push"}, commands)
+
+	// The sbom plugin is specific to docker
+	err := docker.Sbom(ctx, "todo/api", "spdx-json", "api-sbom.spdx.json")
+	require.ErrorContains(t, err, "'docker sbom' is not supported by nerdctl")
+}
+
+func Test_PodmanBuildMultiPlatform(t *testing.T) {
+	t.Setenv(ContainerRuntimeEnvVarName, string(RuntimePodman))
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewCli(mockContext.CommandRunner)
+
+	var buildArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "podman build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		buildArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	var pushArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "podman manifest push")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		pushArgs = args
+		err := os.WriteFile(args.Args[4], []byte("sha256:4f2d\n"), 0600)
+		return exec.NewRunResult(0, "", ""), err
+	})
+
+	// Podman builds for multiple platforms without a builder
+	require.NoError(t, docker.EnsureBuilder(*mockContext.Context, MultiPlatformBuilder))
+
+	digest, err := docker.BuildMultiPlatform(*mockContext.Context, "./src/api", MultiPlatformBuildOptions{
+		Builder:        MultiPlatformBuilder,
+		DockerFilePath: "./Dockerfile",
+		Platforms:      []string{"linux/amd64", "linux/arm64"},
+		BuildContext:   ".",
+		Tags:           []string{"contoso.azurecr.io/todo/api:azd-deploy-1"},
+	}, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "sha256:4f2d", digest)
+	require.Equal(t, []string{
+		"build", "--manifest", "contoso.azurecr.io/todo/api:azd-deploy-1",
+		"-f", "./Dockerfile",
+		"--platform", "linux/amd64,linux/arm64",
+		".",
+	}, buildArgs.Args)
+	require.Equal(t, "manifest", pushArgs.Args[0])
+	require.Equal(t, []string{
+		"contoso.azurecr.io/todo/api:azd-deploy-1", "contoso.azurecr.io/todo/api:azd-deploy-1",
+	}, pushArgs.Args[5:])
+}
+
+func Test_NerdctlBuildMultiPlatform(t *testing.T) {
+	t.Setenv(ContainerRuntimeEnvVarName, string(RuntimeNerdctl))
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewCli(mockContext.CommandRunner)
+
+	commands := [][]string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "nerdctl")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		commands = append(commands, args.Args)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	digest, err := docker.BuildMultiPlatform(*mockContext.Context, "./src/api", MultiPlatformBuildOptions{
+		DockerFilePath: "./Dockerfile",
+		Platforms:      []string{"linux/amd64", "linux/arm64"},
+		BuildContext:   ".",
+		Tags:           []string{"contoso.azurecr.io/todo/api:azd-deploy-1"},
+	}, nil)
+
+	require.NoError(t, err)
+	require.Empty(t, digest)
+	require.Equal(t, [][]string{
+		{
+			"build", "-f", "./Dockerfile", "--platform", "linux/amd64,linux/arm64",
+			"-t", "contoso.azurecr.io/todo/api:azd-deploy-1", ".",
+		},
+		{"push", "--all-platforms", "contoso.azurecr.io/todo/api:azd-deploy-1"},
+	}, commands)
+}
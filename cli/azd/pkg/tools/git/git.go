@@ -123,6 +123,33 @@ func (cli *Cli) GetCurrentBranch(ctx context.Context, repositoryPath string) (st
 	return strings.TrimSpace(res.Stdout), nil
 }
 
+// GetCurrentCommit returns the abbreviated sha of the commit checked out in the repository, ex) 4f2d8a1
+func (cli *Cli) GetCurrentCommit(ctx context.Context, repositoryPath string) (string, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "rev-parse", "--short", "HEAD")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if notGitRepositoryRegex.MatchString(res.Stderr) {
+		return "", ErrNotRepository
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get current commit: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
+// GetHeadCommit returns the full sha of the commit checked out in the repository, unlike the short sha of
+// GetCurrentCommit
+func (cli *Cli) GetHeadCommit(ctx context.Context, repositoryPath string) (string, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "rev-parse", "HEAD")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if notGitRepositoryRegex.MatchString(res.Stderr) {
+		return "", ErrNotRepository
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
 func (cli *Cli) GetRepoRoot(ctx context.Context, repositoryPath string) (string, error) {
 	runArgs := newRunArgs("-C", repositoryPath, "rev-parse", "--show-toplevel")
 	res, err := cli.commandRunner.Run(ctx, runArgs)
@@ -0,0 +1,110 @@
+package grype
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Cli scans container images for vulnerabilities with the grype CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// Vulnerability is a known vulnerability of a package installed in the image
+type Vulnerability struct {
+	// ex) CVE-2023-5363
+	Id string
+	// ex) libssl3
+	Package          string
+	InstalledVersion string
+	// The version the vulnerability is fixed in, empty when there is no fix yet
+	FixedVersion string
+	// ex) CRITICAL, HIGH, MEDIUM, LOW or UNKNOWN
+	Severity string
+}
+
+// Scan scans the image in the local docker daemon and returns its vulnerabilities
+// Vulnerabilities without a fix are excluded when ignoreUnfixed is set.
+func (cli *Cli) Scan(ctx context.Context, imageName string, ignoreUnfixed bool) ([]Vulnerability, error) {
+	log.Printf("scanning image '%s' for vulnerabilities", imageName)
+
+	// The docker: scheme scans the local image instead of pulling it from a registry
+	args := []string{fmt.Sprintf("docker:%s", imageName), "--output", "json", "--quiet"}
+	if ignoreUnfixed {
+		args = append(args, "--only-fixed")
+	}
+
+	res, err := cli.commandRunner.Run(ctx, exec.NewRunArgs("grype", args...))
+	if err != nil {
+		return nil, fmt.Errorf("scanning image '%s': %w", imageName, err)
+	}
+
+	var report struct {
+		Matches []struct {
+			Vulnerability struct {
+				Id       string `json:"id"`
+				Severity string `json:"severity"`
+				Fix      struct {
+					Versions []string `json:"versions"`
+				} `json:"fix"`
+			} `json:"vulnerability"`
+			Artifact struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"artifact"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		return nil, fmt.Errorf("reading scan report of image '%s': %w", imageName, err)
+	}
+
+	vulnerabilities := []Vulnerability{}
+	for _, match := range report.Matches {
+		vulnerabilities = append(vulnerabilities, Vulnerability{
+			Id:               match.Vulnerability.Id,
+			Package:          match.Artifact.Name,
+			InstalledVersion: match.Artifact.Version,
+			FixedVersion:     strings.Join(match.Vulnerability.Fix.Versions, ", "),
+			// grype reports severities capitalized, ex) Critical
+			Severity: strings.ToUpper(match.Vulnerability.Severity),
+		})
+	}
+
+	return vulnerabilities, nil
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("grype"); err != nil {
+		return err
+	}
+
+	version, err := tools.ExecuteCommand(ctx, cli.commandRunner, "grype", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("grype version: %s", version)
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://github.com/anchore/grype#installation"
+}
+
+func (cli *Cli) Name() string {
+	return "Grype"
+}
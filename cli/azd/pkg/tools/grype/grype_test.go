@@ -0,0 +1,39 @@
+package grype
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Scan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewCli(mockContext.CommandRunner)
+
+	var scanArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "grype docker:")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		scanArgs = args
+		report := `{"matches":[{"vulnerability":{"id":"CVE-2023-5363","severity":"High",` +
+			`"fix":{"versions":["3.0.11-1"]}},"artifact":{"name":"libssl3","version":"3.0.9-1"}}]}`
+		return exec.NewRunResult(0, report, ""), nil
+	})
+
+	vulnerabilities, err := cli.Scan(*mockContext.Context, "todo/api:azd-deploy-1", false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"docker:todo/api:azd-deploy-1", "--output", "json", "--quiet"}, scanArgs.Args)
+	require.Equal(t, []Vulnerability{
+		{
+			Id:               "CVE-2023-5363",
+			Package:          "libssl3",
+			InstalledVersion: "3.0.9-1",
+			FixedVersion:     "3.0.11-1",
+			Severity:         "HIGH",
+		},
+	}, vulnerabilities)
+}
@@ -0,0 +1,83 @@
+package ko
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Cli builds container images of Go applications without a Dockerfile with the ko CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// Build builds the image of the main package at the import path, ex) ./cmd/api, relative to the working directory and
+// loads it into the local docker daemon. Returns the reference of the built image, ex) ko.local/api-4f2d...:latest
+// The platform, ex) linux/amd64, defaults to the platform of the base image when empty.
+func (cli *Cli) Build(
+	ctx context.Context,
+	cwd string,
+	importPath string,
+	platform string,
+	buildProgress io.Writer,
+) (string, error) {
+	args := []string{"build", importPath, "--local"}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+
+	runArgs := exec.NewRunArgs("ko", args...).WithCwd(cwd)
+	if buildProgress != nil {
+		// The image reference is written to stdout, the build logs to stderr
+		runArgs = runArgs.WithStdErr(buildProgress)
+	}
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf("building image of '%s': %w", importPath, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(res.Stdout), "\n")
+	image := strings.TrimSpace(lines[len(lines)-1])
+	if image == "" {
+		return "", fmt.Errorf("building image of '%s': no image reference in the output of ko", importPath)
+	}
+
+	log.Printf("built image '%s' of '%s'", image, importPath)
+	return image, nil
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("ko"); err != nil {
+		return err
+	}
+
+	version, err := tools.ExecuteCommand(ctx, cli.commandRunner, "ko", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("ko version: %s", version)
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://ko.build/install/"
+}
+
+func (cli *Cli) Name() string {
+	return "ko"
+}
@@ -0,0 +1,42 @@
+package ko
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Build(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewCli(mockContext.CommandRunner)
+
+	var buildArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "ko build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		buildArgs = args
+		return exec.NewRunResult(0, "ko.local/api-4f2d8a1b:latest\n", "2024/01/01 Building github.com/contoso/api"), nil
+	})
+
+	image, err := cli.Build(*mockContext.Context, "./src/api", "./cmd/api", "linux/amd64", nil)
+	require.NoError(t, err)
+	require.Equal(t, "ko.local/api-4f2d8a1b:latest", image)
+	require.Equal(t, "./src/api", buildArgs.Cwd)
+	require.Equal(t, []string{"build", "./cmd/api", "--local", "--platform", "linux/amd64"}, buildArgs.Args)
+}
+
+func Test_Build_NoImage(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewCli(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "ko build")
+	}).Respond(exec.NewRunResult(0, "", ""))
+
+	_, err := cli.Build(*mockContext.Context, "./src/api", ".", "", nil)
+	require.ErrorContains(t, err, "no image reference in the output of ko")
+}
@@ -0,0 +1,383 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// The interval between polling the deployment status while waiting for a rollout
+var apiClientRolloutPollInterval = 2 * time.Second
+
+// ApiClient communicates with the k8s API server directly instead of executing the kubectl binary
+// Errors returned by the API server are returned as *apierrors.StatusError and can be inspected with
+// the k8s.io/apimachinery/pkg/api/errors helpers, ex) apierrors.IsForbidden(err)
+type ApiClient struct {
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+	// The namespace of the current kube context, used when a command does not specify a namespace
+	namespace string
+}
+
+// Creates a new ApiClient for the current context of the kube config files.
+// Defaults to the default kube config resolution when no kube config files are specified
+func NewApiClient(kubeConfigPaths ...string) (*ApiClient, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if len(kubeConfigPaths) > 0 {
+		loadingRules.Precedence = kubeConfigPaths
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed loading kube config, %w", err)
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving namespace of the kube context, %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating k8s discovery client, %w", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating k8s client, %w", err)
+	}
+
+	// The shortcut expander resolves the short resource names used by kubectl, ex) svc or ing
+	cachedDiscovery := memory.NewMemCacheClient(discoveryClient)
+	mapper := restmapper.NewShortcutExpander(
+		restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+		cachedDiscovery,
+		nil,
+	)
+
+	return newApiClient(dynamicClient, mapper, namespace), nil
+}
+
+func newApiClient(dynamicClient dynamic.Interface, mapper meta.RESTMapper, namespace string) *ApiClient {
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	return &ApiClient{
+		dynamicClient: dynamicClient,
+		mapper:        mapper,
+		namespace:     namespace,
+	}
+}
+
+// List returns the JSON encoded list of the resources of the specified type, equivalent to 'kubectl get -o json'
+func (c *ApiClient) List(ctx context.Context, resourceType ResourceType, flags *KubeCliFlags) ([]byte, error) {
+	gvr, err := c.mapper.ResourceFor(schema.ParseGroupResource(string(resourceType)).WithVersion(""))
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving resource type '%s', %w", resourceType, err)
+	}
+
+	mapping, err := c.restMapping(gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	listOptions := metav1.ListOptions{}
+	if flags != nil {
+		listOptions.LabelSelector = flags.LabelSelector
+	}
+
+	list, err := c.resource(mapping, "", flags).List(ctx, listOptions)
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	return list.MarshalJSON()
+}
+
+// Apply applies the YAML or JSON manifests with server-side apply, equivalent to 'kubectl apply --server-side'
+// Without server-side apply options, conflicting fields are taken over to match the behavior of client-side apply
+// Returns the applied resources in the format reported by kubectl, ex) deployment.apps/api serverside-applied
+func (c *ApiClient) Apply(
+	ctx context.Context,
+	manifests string,
+	options *ServerSideApplyOptions,
+	flags *KubeCliFlags,
+) (string, error) {
+	applyOptions := metav1.ApplyOptions{
+		FieldManager: options.fieldManager(),
+		Force:        options == nil || options.ForceConflicts,
+		DryRun:       dryRun(flags),
+	}
+
+	var output strings.Builder
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(manifests)), 4096)
+	for {
+		var object map[string]any
+		if err := decoder.Decode(&object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return output.String(), fmt.Errorf("failed decoding manifest, %w", err)
+		}
+
+		if len(object) == 0 {
+			continue
+		}
+
+		resource := &unstructured.Unstructured{Object: object}
+		gvk := resource.GroupVersionKind()
+		mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return output.String(), fmt.Errorf("failed resolving resource kind '%s', %w", gvk.Kind, err)
+		}
+
+		_, err = c.resource(mapping, resource.GetNamespace(), flags).
+			Apply(ctx, resource.GetName(), resource, applyOptions)
+		if apierrors.IsConflict(err) {
+			return output.String(), fmt.Errorf("%w, %w", ErrApplyConflict, err)
+		}
+		if err != nil {
+			return output.String(), apiError(err)
+		}
+
+		fmt.Fprintf(&output, "%s/%s serverside-applied\n", qualifiedResource(mapping), resource.GetName())
+	}
+
+	return output.String(), nil
+}
+
+// Create creates the resource, equivalent to 'kubectl create'
+// Client dry-runs return the resource in the requested output format without communicating with the API server
+func (c *ApiClient) Create(ctx context.Context, resource *unstructured.Unstructured, flags *KubeCliFlags) (string, error) {
+	if flags != nil && flags.DryRun == DryRunTypeClient {
+		var manifest []byte
+		var err error
+		if flags.Output == OutputTypeJson {
+			manifest, err = resource.MarshalJSON()
+		} else {
+			manifest, err = yaml.Marshal(resource.Object)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed marshalling resource, %w", err)
+		}
+
+		return string(manifest), nil
+	}
+
+	gvk := resource.GroupVersionKind()
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return "", fmt.Errorf("failed resolving resource kind '%s', %w", gvk.Kind, err)
+	}
+
+	_, err = c.resource(mapping, resource.GetNamespace(), flags).
+		Create(ctx, resource, metav1.CreateOptions{FieldManager: DefaultFieldManager, DryRun: dryRun(flags)})
+	if err != nil {
+		return "", apiError(err)
+	}
+
+	return fmt.Sprintf("%s/%s created\n", qualifiedResource(mapping), resource.GetName()), nil
+}
+
+// Patch updates the resource with the specified type and name using a JSON merge patch
+func (c *ApiClient) Patch(
+	ctx context.Context,
+	resourceType ResourceType,
+	name string,
+	patch string,
+	flags *KubeCliFlags,
+) error {
+	mapping, err := c.resourceMapping(resourceType)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.resource(mapping, "", flags).
+		Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{
+			FieldManager: DefaultFieldManager,
+			DryRun:       dryRun(flags),
+		})
+
+	return apiError(err)
+}
+
+// Delete deletes the resource with the specified type and name. Resources that do not exist are ignored
+func (c *ApiClient) Delete(ctx context.Context, resourceType ResourceType, name string, flags *KubeCliFlags) error {
+	mapping, err := c.resourceMapping(resourceType)
+	if err != nil {
+		return err
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	err = c.resource(mapping, "", flags).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+		DryRun:            dryRun(flags),
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return apiError(err)
+}
+
+// RolloutStatus waits until the latest revision of the deployment has been rolled out, equivalent to
+// 'kubectl rollout status'. Fails when the deployment exceeded its progress deadline
+func (c *ApiClient) RolloutStatus(ctx context.Context, deploymentName string, flags *KubeCliFlags) error {
+	mapping, err := c.resourceMapping(ResourceTypeDeployment)
+	if err != nil {
+		return err
+	}
+
+	client := c.resource(mapping, "", flags)
+	for {
+		resource, err := client.Get(ctx, deploymentName, metav1.GetOptions{})
+		if err != nil {
+			return apiError(err)
+		}
+
+		done, err := deploymentRolledOut(resource)
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(apiClientRolloutPollInterval):
+		}
+	}
+}
+
+// deploymentRolledOut returns whether the deployment rollout has completed, using the same conditions as kubectl
+func deploymentRolledOut(resource *unstructured.Unstructured) (bool, error) {
+	generation := resource.GetGeneration()
+	observedGeneration, _, _ := unstructured.NestedInt64(resource.Object, "status", "observedGeneration")
+	if generation > observedGeneration {
+		return false, nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(resource.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]any)
+		if ok && conditionMap["type"] == "Progressing" && conditionMap["reason"] == "ProgressDeadlineExceeded" {
+			return false, fmt.Errorf("deployment %q exceeded its progress deadline", resource.GetName())
+		}
+	}
+
+	replicas, has, _ := unstructured.NestedInt64(resource.Object, "spec", "replicas")
+	if !has {
+		replicas = 1
+	}
+
+	updatedReplicas, _, _ := unstructured.NestedInt64(resource.Object, "status", "updatedReplicas")
+	statusReplicas, _, _ := unstructured.NestedInt64(resource.Object, "status", "replicas")
+	availableReplicas, _, _ := unstructured.NestedInt64(resource.Object, "status", "availableReplicas")
+
+	return updatedReplicas >= replicas && statusReplicas <= updatedReplicas && availableReplicas >= updatedReplicas, nil
+}
+
+// resourceMapping returns the REST mapping of the resource type, ex) svc or deployment
+func (c *ApiClient) resourceMapping(resourceType ResourceType) (*meta.RESTMapping, error) {
+	gvr, err := c.mapper.ResourceFor(schema.ParseGroupResource(string(resourceType)).WithVersion(""))
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving resource type '%s', %w", resourceType, err)
+	}
+
+	return c.restMapping(gvr)
+}
+
+func (c *ApiClient) restMapping(gvr schema.GroupVersionResource) (*meta.RESTMapping, error) {
+	gvk, err := c.mapper.KindFor(gvr)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving kind of resource '%s', %w", gvr.Resource, err)
+	}
+
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving resource kind '%s', %w", gvk.Kind, err)
+	}
+
+	return mapping, nil
+}
+
+// resource returns the client of the mapped resource, scoped to the namespace for namespaced resources
+// The namespace of the resource takes precedence over the namespace flag and the namespace of the kube context
+func (c *ApiClient) resource(mapping *meta.RESTMapping, namespace string, flags *KubeCliFlags) dynamic.ResourceInterface {
+	resource := c.dynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return resource
+	}
+
+	if namespace == "" && flags != nil {
+		namespace = flags.Namespace
+	}
+
+	if namespace == "" {
+		namespace = c.namespace
+	}
+
+	return resource.Namespace(namespace)
+}
+
+// qualifiedResource returns the resource name qualified with its group as reported by kubectl, ex) deployment.apps
+func qualifiedResource(mapping *meta.RESTMapping) string {
+	kind := strings.ToLower(mapping.GroupVersionKind.Kind)
+	if mapping.GroupVersionKind.Group == "" {
+		return kind
+	}
+
+	return fmt.Sprintf("%s.%s", kind, mapping.GroupVersionKind.Group)
+}
+
+// newUnstructured returns a resource of the specified kind with only its name set
+func newUnstructured(apiVersion string, kind string, name string) *unstructured.Unstructured {
+	resource := &unstructured.Unstructured{}
+	resource.SetAPIVersion(apiVersion)
+	resource.SetKind(kind)
+	resource.SetName(name)
+
+	return resource
+}
+
+// dryRun returns the server-side dry-run option of the command flags
+func dryRun(flags *KubeCliFlags) []string {
+	if flags != nil && flags.DryRun == DryRunTypeServer {
+		return []string{metav1.DryRunAll}
+	}
+
+	return nil
+}
+
+// apiError wraps resources that do not exist with ErrResourceNotFound while preserving the API status error
+func apiError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w, %w", ErrResourceNotFound, err)
+	}
+
+	return err
+}
@@ -0,0 +1,395 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/restmapper"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func Test_ApiClient_List(t *testing.T) {
+	client, _ := createTestApiClient(
+		createTestObject("v1", "Service", "test-namespace", "api", map[string]any{"app": "api"}),
+		createTestObject("v1", "Service", "test-namespace", "web", map[string]any{"app": "web"}),
+		createTestObject("v1", "Service", "other-namespace", "api", map[string]any{"app": "api"}),
+	)
+
+	jsonBytes, err := client.List(context.Background(), ResourceTypeService, &KubeCliFlags{
+		Namespace:     "test-namespace",
+		LabelSelector: "app=api",
+	})
+	require.NoError(t, err)
+
+	var list List[Service]
+	require.NoError(t, json.Unmarshal(jsonBytes, &list))
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "api", list.Items[0].Metadata.Name)
+	require.Equal(t, "test-namespace", list.Items[0].Metadata.Namespace)
+
+	_, err = client.List(context.Background(), "unknown", nil)
+	require.ErrorContains(t, err, "failed resolving resource type 'unknown'")
+}
+
+func Test_ApiClient_Apply(t *testing.T) {
+	client, dynamicClient := createTestApiClient()
+
+	// The fake object tracker does not support server-side apply of unstructured resources
+	applied := []*unstructured.Unstructured{}
+	dynamicClient.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(clienttesting.PatchAction)
+		require.Equal(t, types.ApplyPatchType, patchAction.GetPatchType())
+
+		resource := &unstructured.Unstructured{}
+		require.NoError(t, resource.UnmarshalJSON(patchAction.GetPatch()))
+		resource.SetNamespace(patchAction.GetNamespace())
+		applied = append(applied, resource)
+
+		return true, resource, nil
+	})
+
+	manifests := strings.Join([]string{
+		"apiVersion: apps/v1",
+		"kind: Deployment",
+		"metadata:",
+		"  name: api",
+		"spec:",
+		"  replicas: 2",
+		"---",
+		"apiVersion: v1",
+		"kind: Service",
+		"metadata:",
+		"  name: api",
+		"  namespace: other-namespace",
+		"---",
+	}, "\n")
+
+	output, err := client.Apply(context.Background(), manifests, nil, &KubeCliFlags{Namespace: "test-namespace"})
+	require.NoError(t, err)
+	require.Equal(t, "deployment.apps/api serverside-applied\nservice/api serverside-applied\n", output)
+
+	require.Len(t, applied, 2)
+	require.Equal(t, "Deployment", applied[0].GetKind())
+	require.Equal(t, "test-namespace", applied[0].GetNamespace())
+	replicas, _, _ := unstructured.NestedInt64(applied[0].Object, "spec", "replicas")
+	require.Equal(t, int64(2), replicas)
+	// The namespace of the manifest takes precedence over the namespace flag
+	require.Equal(t, "Service", applied[1].GetKind())
+	require.Equal(t, "other-namespace", applied[1].GetNamespace())
+
+	_, err = client.Apply(context.Background(), "apiVersion: v1\nkind: Unknown\nmetadata:\n  name: api\n", nil, nil)
+	require.ErrorContains(t, err, "failed resolving resource kind 'Unknown'")
+
+	t.Run("Conflict", func(t *testing.T) {
+		client, dynamicClient := createTestApiClient()
+		dynamicClient.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewApplyConflict(
+				[]metav1.StatusCause{{Type: metav1.CauseTypeFieldManagerConflict, Field: ".spec.replicas"}},
+				`Apply failed with 1 conflict: conflict with "kubectl-client-side-apply" using apps/v1: .spec.replicas`,
+			)
+		})
+
+		output, err := client.Apply(context.Background(), manifests, &ServerSideApplyOptions{}, nil)
+		require.ErrorIs(t, err, ErrApplyConflict)
+		require.ErrorContains(t, err, ".spec.replicas")
+		require.Empty(t, output)
+	})
+}
+
+func Test_ApiClient_Create(t *testing.T) {
+	client, dynamicClient := createTestApiClient()
+
+	t.Run("ClientDryRun", func(t *testing.T) {
+		output, err := client.Create(
+			context.Background(),
+			newUnstructured("v1", "Namespace", "test-namespace"),
+			&KubeCliFlags{DryRun: DryRunTypeClient, Output: OutputTypeYaml},
+		)
+		require.NoError(t, err)
+		require.Equal(t, "apiVersion: v1\nkind: Namespace\nmetadata:\n    name: test-namespace\n", output)
+		require.Empty(t, dynamicClient.Actions())
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		output, err := client.Create(
+			context.Background(),
+			newUnstructured("v1", "ServiceAccount", "api"),
+			&KubeCliFlags{Namespace: "test-namespace"},
+		)
+		require.NoError(t, err)
+		require.Equal(t, "serviceaccount/api created\n", output)
+
+		_, err = client.Create(context.Background(), newUnstructured("v1", "ServiceAccount", "api"), &KubeCliFlags{
+			Namespace: "test-namespace",
+		})
+		require.True(t, apierrors.IsAlreadyExists(err))
+	})
+}
+
+func Test_ApiClient_Patch_Delete(t *testing.T) {
+	client, dynamicClient := createTestApiClient(
+		createTestObject("apps/v1", "Deployment", "default", "api", nil),
+	)
+
+	err := client.Patch(context.Background(), ResourceTypeDeployment, "api", `{"spec":{"paused":true}}`, nil)
+	require.NoError(t, err)
+
+	err = client.Patch(context.Background(), ResourceTypeDeployment, "web", `{"spec":{"paused":true}}`, nil)
+	require.ErrorIs(t, err, ErrResourceNotFound)
+	var statusErr *apierrors.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	require.Equal(t, metav1.StatusReasonNotFound, statusErr.ErrStatus.Reason)
+
+	err = client.Delete(context.Background(), ResourceTypeDeployment, "api", nil)
+	require.NoError(t, err)
+
+	// Resources that do not exist are ignored
+	err = client.Delete(context.Background(), ResourceTypeDeployment, "api", nil)
+	require.NoError(t, err)
+
+	verbs := []string{}
+	for _, action := range dynamicClient.Actions() {
+		verbs = append(verbs, action.GetVerb())
+	}
+	require.Equal(t, []string{"patch", "patch", "delete", "delete"}, verbs)
+}
+
+func Test_ApiClient_Watch(t *testing.T) {
+	client, dynamicClient := createTestApiClient()
+	watcher := watch.NewFake()
+	dynamicClient.PrependWatchReactor("deployments", clienttesting.DefaultWatchReactor(watcher, nil))
+
+	go func() {
+		watcher.Add(createTestObject("apps/v1", "Deployment", "default", "api", nil))
+		watcher.Modify(createTestObject("apps/v1", "Deployment", "default", "web", nil))
+		watcher.Modify(createTestObject("apps/v1", "Deployment", "default", "api", map[string]any{"ready": "true"}))
+	}()
+
+	events := []WatchEventType{}
+	err := client.Watch(context.Background(), ResourceTypeDeployment, nil, func(event WatchEvent) (bool, error) {
+		events = append(events, event.Type)
+
+		var deployment Deployment
+		require.NoError(t, json.Unmarshal(event.Object, &deployment))
+
+		return deployment.Metadata.Labels["ready"] == "true", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []WatchEventType{WatchEventTypeAdded, WatchEventTypeModified, WatchEventTypeModified}, events)
+
+	// Watches closed by the API server return without an error so that callers can reconnect
+	watcher = watch.NewFake()
+	dynamicClient.PrependWatchReactor("deployments", clienttesting.DefaultWatchReactor(watcher, nil))
+	go watcher.Error(&apierrors.NewResourceExpired("too old resource version").ErrStatus)
+
+	err = client.Watch(context.Background(), ResourceTypeDeployment, nil, func(event WatchEvent) (bool, error) {
+		return false, errors.New("unexpected event")
+	})
+	require.NoError(t, err)
+}
+
+func Test_DeploymentRolledOut(t *testing.T) {
+	tests := map[string]struct {
+		status        map[string]any
+		expected      bool
+		expectedError string
+	}{
+		"Complete": {
+			status: map[string]any{
+				"observedGeneration": int64(2),
+				"replicas":           int64(2),
+				"updatedReplicas":    int64(2),
+				"availableReplicas":  int64(2),
+			},
+			expected: true,
+		},
+		"NotObserved": {
+			status: map[string]any{
+				"observedGeneration": int64(1),
+				"replicas":           int64(2),
+				"updatedReplicas":    int64(2),
+				"availableReplicas":  int64(2),
+			},
+		},
+		"OldReplicasPending": {
+			status: map[string]any{
+				"observedGeneration": int64(2),
+				"replicas":           int64(3),
+				"updatedReplicas":    int64(2),
+				"availableReplicas":  int64(2),
+			},
+		},
+		"ProgressDeadlineExceeded": {
+			status: map[string]any{
+				"observedGeneration": int64(2),
+				"conditions": []any{
+					map[string]any{"type": "Progressing", "reason": "ProgressDeadlineExceeded"},
+				},
+			},
+			expectedError: `deployment "api" exceeded its progress deadline`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			deployment := createTestObject("apps/v1", "Deployment", "default", "api", nil)
+			deployment.SetGeneration(2)
+			deployment.Object["spec"] = map[string]any{"replicas": int64(2)}
+			deployment.Object["status"] = test.status
+
+			done, err := deploymentRolledOut(deployment)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, done)
+		})
+	}
+}
+
+func Test_Cli_ApiClient(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	commands := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		commands = append(commands, args.Args[0])
+		return exec.NewRunResult(0, `{"items":[]}`, ""), nil
+	})
+
+	apiClient, _ := createTestApiClient(createTestObject("v1", "Service", "default", "api", nil))
+	kubeConfigs := [][]string{}
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.newApiClient = func(kubeConfigPaths ...string) (*ApiClient, error) {
+		kubeConfigs = append(kubeConfigs, kubeConfigPaths)
+		return apiClient, nil
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, err := GetResources[Service](*mockContext.Context, cli, ResourceTypeService, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"get"}, commands)
+		require.Empty(t, kubeConfigs)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		commands = []string{}
+		cli.EnableApiClient(true)
+		cli.SetKubeConfigPath("/tmp/.kube/config")
+
+		services, err := GetResources[Service](*mockContext.Context, cli, ResourceTypeService, nil)
+		require.NoError(t, err)
+		require.Len(t, services.Items, 1)
+
+		_, err = cli.CreateNamespace(*mockContext.Context, "test-namespace", &KubeCliFlags{
+			DryRun: DryRunTypeClient,
+			Output: OutputTypeYaml,
+		})
+		require.NoError(t, err)
+
+		// Pruning is only supported by kubectl
+		_, err = cli.ApplyWithStdIn(*mockContext.Context, "yaml", &KubeCliFlags{Prune: true, LabelSelector: "app=api"})
+		require.NoError(t, err)
+
+		_, err = cli.ConfigUseContext(*mockContext.Context, "context", nil)
+		require.NoError(t, err)
+
+		_, err = cli.Delete(*mockContext.Context, ResourceTypeService, "api", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"apply", "config"}, commands)
+		// The API client is recreated after the current context changed
+		require.Equal(t, [][]string{{"/tmp/.kube/config"}, {"/tmp/.kube/config"}}, kubeConfigs)
+	})
+
+	t.Run("FallbackOnError", func(t *testing.T) {
+		commands = []string{}
+		cli.SetKubeConfigPath("")
+		cli.newApiClient = func(kubeConfigPaths ...string) (*ApiClient, error) {
+			return nil, errors.New("invalid configuration")
+		}
+
+		_, err := GetResources[Service](*mockContext.Context, cli, ResourceTypeService, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"get"}, commands)
+	})
+}
+
+// createTestApiClient creates an API client on top of fake discovery and dynamic clients
+func createTestApiClient(objects ...runtime.Object) (*ApiClient, *fakedynamic.FakeDynamicClient) {
+	discoveryClient := &fakediscovery.FakeDiscovery{
+		Fake: &clienttesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "v1",
+					APIResources: []metav1.APIResource{
+						{Name: "namespaces", Kind: "Namespace", Namespaced: false, ShortNames: []string{"ns"}},
+						{Name: "services", Kind: "Service", Namespaced: true, ShortNames: []string{"svc"}},
+						{Name: "serviceaccounts", Kind: "ServiceAccount", Namespaced: true, ShortNames: []string{"sa"}},
+					},
+				},
+				{
+					GroupVersion: "apps/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "deployments", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}},
+					},
+				},
+			},
+		},
+	}
+
+	cachedDiscovery := memory.NewMemCacheClient(discoveryClient)
+	mapper := restmapper.NewShortcutExpander(
+		restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+		cachedDiscovery,
+		nil,
+	)
+
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Version: "v1", Resource: "services"}:                   "ServiceList",
+			{Version: "v1", Resource: "serviceaccounts"}:            "ServiceAccountList",
+			{Version: "v1", Resource: "namespaces"}:                 "NamespaceList",
+			{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+		},
+		objects...,
+	)
+
+	return newApiClient(dynamicClient, mapper, ""), dynamicClient
+}
+
+func createTestObject(
+	apiVersion string,
+	kind string,
+	namespace string,
+	name string,
+	labels map[string]any,
+) *unstructured.Unstructured {
+	resource := newUnstructured(apiVersion, kind, name)
+	resource.SetNamespace(namespace)
+	if labels != nil {
+		_ = unstructured.SetNestedMap(resource.Object, labels, "metadata", "labels")
+	}
+
+	return resource
+}
@@ -0,0 +1,139 @@
+package kubectl
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+var (
+	ErrForbidden          = errors.New("the current principal is not authorized to perform the operation")
+	ErrInvalidResource    = errors.New("the k8s resource is invalid")
+	ErrClusterUnreachable = errors.New("the k8s API server cannot be reached")
+	ErrServerBusy         = errors.New("the k8s API server is temporarily unable to handle the request")
+)
+
+// CommandError is a failed kubectl command, classified by the error kubectl reported
+// The error message is the error line reported by kubectl instead of the full output of the command.
+type CommandError struct {
+	// The classification of the failure, ex) ErrForbidden, nil when the failure is not classified
+	Reason error
+	// The error reported by kubectl, ex) Error from server (Forbidden): deployments.apps is forbidden: ...
+	Message string
+	// The error of the command execution
+	Err error
+}
+
+func (e *CommandError) Error() string {
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+
+	return e.Message
+}
+
+// Unwrap returns the classification of the failure along with the error of the command execution
+func (e *CommandError) Unwrap() []error {
+	if e.Reason == nil {
+		return []error{e.Err}
+	}
+
+	return []error{e.Reason, e.Err}
+}
+
+// The prefixes of the lines kubectl reports errors with
+var commandErrorPrefixes = []string{"Error from server", "error:", "Error:", "Unable to connect to the server"}
+
+// The classifications of the errors reported by kubectl, matched in order
+var commandErrorReasons = []struct {
+	reason   error
+	patterns []string
+}{
+	{
+		reason:   ErrApplyConflict,
+		patterns: []string{"Apply failed with"},
+	},
+	{
+		reason: ErrForbidden,
+		patterns: []string{
+			"Error from server (Forbidden)", "is forbidden:", "(Unauthorized)", "You must be logged in to the server",
+		},
+	},
+	{
+		reason: ErrInvalidResource,
+		patterns: []string{
+			"Error from server (Invalid)", "Error from server (BadRequest)", "error validating", "strict decoding error",
+			"error parsing",
+		},
+	},
+	{
+		reason: ErrResourceNotFound,
+		patterns: []string{
+			"Error from server (NotFound)", "the server doesn't have a resource type", "no matches for kind",
+		},
+	},
+	{
+		reason: ErrServerBusy,
+		patterns: []string{
+			"Error from server (TooManyRequests)", "Error from server (ServiceUnavailable)", "Error from server (Timeout)",
+			"the server is currently unable to handle the request", "etcdserver: request timed out",
+			"etcdserver: leader changed", "http2: client connection lost", "connection reset by peer",
+			"TLS handshake timeout",
+		},
+	},
+	{
+		reason: ErrClusterUnreachable,
+		patterns: []string{
+			"Unable to connect to the server", "connection refused", "no such host", "i/o timeout",
+			"context deadline exceeded",
+		},
+	},
+}
+
+// commandError classifies the error of a failed kubectl command from the errors reported in its output
+// Commands executed through a remote runner report their errors in the stdout of the command.
+func commandError(res exec.RunResult, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var commandErr *CommandError
+	if errors.As(err, &commandErr) {
+		return err
+	}
+
+	message := commandErrorMessage(res.Stderr)
+	if message == "" {
+		message = commandErrorMessage(res.Stdout)
+	}
+
+	// The error itself is classified too, ex) for a remote command that failed before kubectl was executed
+	classified := message + "\n" + err.Error()
+
+	for _, classification := range commandErrorReasons {
+		for _, pattern := range classification.patterns {
+			if strings.Contains(classified, pattern) {
+				return &CommandError{Reason: classification.reason, Message: message, Err: err}
+			}
+		}
+	}
+
+	return &CommandError{Message: message, Err: err}
+}
+
+// commandErrorMessage returns the error lines reported by kubectl in the output, or empty when there are none
+func commandErrorMessage(output string) string {
+	lines := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		for _, prefix := range commandErrorPrefixes {
+			if strings.HasPrefix(line, prefix) {
+				lines = append(lines, line)
+				break
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
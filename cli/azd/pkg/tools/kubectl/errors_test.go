@@ -0,0 +1,87 @@
+package kubectl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CommandError(t *testing.T) {
+	tests := map[string]struct {
+		res             exec.RunResult
+		err             error
+		expectedReason  error
+		expectedMessage string
+	}{
+		"Forbidden": {
+			res: exec.NewRunResult(
+				1, "", `Error from server (Forbidden): pods is forbidden: User "dev" cannot list pods`,
+			),
+			err:             errors.New("exit code: 1"),
+			expectedReason:  ErrForbidden,
+			expectedMessage: `Error from server (Forbidden): pods is forbidden: User "dev" cannot list pods`,
+		},
+		"Unauthorized": {
+			res:             exec.NewRunResult(1, "", "error: You must be logged in to the server (Unauthorized)"),
+			err:             errors.New("exit code: 1"),
+			expectedReason:  ErrForbidden,
+			expectedMessage: "error: You must be logged in to the server (Unauthorized)",
+		},
+		"Invalid": {
+			res: exec.NewRunResult(1, "", "Warning: resource is deprecated\n"+
+				`Error from server (Invalid): Service "api" is invalid: spec.ports[0].port: Invalid value: 0`),
+			err:             errors.New("exit code: 1"),
+			expectedReason:  ErrInvalidResource,
+			expectedMessage: `Error from server (Invalid): Service "api" is invalid: spec.ports[0].port: Invalid value: 0`,
+		},
+		"NotFound": {
+			res:             exec.NewRunResult(1, "", `Error from server (NotFound): deployments.apps "api" not found`),
+			err:             errors.New("exit code: 1"),
+			expectedReason:  ErrResourceNotFound,
+			expectedMessage: `Error from server (NotFound): deployments.apps "api" not found`,
+		},
+		"ConnectionRefused": {
+			res: exec.NewRunResult(1, "", "E1014 memcache.go:265] couldn't get current server API group list\n"+
+				"The connection to the server localhost:8080 was refused - did you specify the right host or port?\n"+
+				"Unable to connect to the server: dial tcp 127.0.0.1:8080: connect: connection refused"),
+			err:             errors.New("exit code: 1"),
+			expectedReason:  ErrClusterUnreachable,
+			expectedMessage: "Unable to connect to the server: dial tcp 127.0.0.1:8080: connect: connection refused",
+		},
+		"RemoteRunner": {
+			// Commands executed through the AKS run command API report their output as the logs of the command
+			res:             exec.NewRunResult(1, `error: the server doesn't have a resource type "rollouts"`, ""),
+			err:             errors.New("command exited with code 1"),
+			expectedReason:  ErrResourceNotFound,
+			expectedMessage: `error: the server doesn't have a resource type "rollouts"`,
+		},
+		"Unclassified": {
+			res:             exec.NewRunResult(1, "", "something unexpected happened"),
+			err:             errors.New("exit code: 1, stderr: something unexpected happened"),
+			expectedMessage: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := commandError(test.res, test.err)
+			require.ErrorIs(t, err, test.err)
+
+			var commandErr *CommandError
+			require.True(t, errors.As(err, &commandErr))
+			require.Equal(t, test.expectedReason, commandErr.Reason)
+			require.Equal(t, test.expectedMessage, commandErr.Message)
+
+			if test.expectedReason != nil {
+				require.ErrorIs(t, err, test.expectedReason)
+				require.EqualError(t, err, test.expectedMessage)
+			} else {
+				require.EqualError(t, err, test.err.Error())
+			}
+		})
+	}
+
+	require.NoError(t, commandError(exec.NewRunResult(0, "", ""), nil))
+}
@@ -0,0 +1,241 @@
+package kubectl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+// The version of kubectl downloaded by azd when kubectl is not installed
+var Version semver.Version = semver.MustParse("1.31.2")
+
+// kubectl is supported within one minor version (older or newer) of the k8s API server
+// https://kubernetes.io/releases/version-skew-policy/#kubectl
+const maxMinorVersionSkew = 1
+
+// ensureInstalled selects the kubectl binary used for the commands
+// The kubectl binary found in the PATH is preferred, otherwise the pinned version is downloaded into the azd bin directory.
+func (cli *Cli) ensureInstalled(ctx context.Context) error {
+	if override := os.Getenv("AZD_KUBECTL_TOOL_PATH"); override != "" {
+		log.Printf("using external kubectl tool: %s", override)
+		cli.path = override
+
+		return nil
+	}
+
+	if err := tools.ToolInPath("kubectl"); err == nil {
+		return nil
+	} else if !errors.Is(err, osexec.ErrNotFound) {
+		return err
+	}
+
+	log.Printf("kubectl not found in PATH, using kubectl %s managed by azd", Version)
+
+	return cli.useManagedVersion(ctx, Version)
+}
+
+// EnsureCompatibleVersion ensures the kubectl binary used for the commands supports the k8s version of the cluster
+// When the kubectl client version is outside of the supported version skew, the kubectl version matching the cluster
+// is downloaded into the azd bin directory and used for the subsequent commands.
+func (cli *Cli) EnsureCompatibleVersion(ctx context.Context, serverVersion string) error {
+	// The user explicitly selected the kubectl binary to use
+	if os.Getenv("AZD_KUBECTL_TOOL_PATH") != "" {
+		return nil
+	}
+
+	server, err := semver.ParseTolerant(serverVersion)
+	if err != nil {
+		return fmt.Errorf("parsing k8s server version '%s': %w", serverVersion, err)
+	}
+
+	clientVersion, err := cli.getClientVersion(ctx)
+	if err != nil {
+		log.Printf("error fetching kubectl version: %s", err)
+	} else if client, err := semver.ParseTolerant(clientVersion); err != nil {
+		log.Printf("error parsing kubectl version '%s': %s", clientVersion, err)
+	} else if isCompatibleVersion(client, server) {
+		return nil
+	} else {
+		log.Printf("kubectl version %s is not compatible with k8s server version %s", client, server)
+	}
+
+	return cli.useManagedVersion(ctx, semver.Version{Major: server.Major, Minor: server.Minor, Patch: server.Patch})
+}
+
+// isCompatibleVersion returns true when the kubectl client version is within the supported skew of the server version
+func isCompatibleVersion(client semver.Version, server semver.Version) bool {
+	if client.Major != server.Major {
+		return false
+	}
+
+	skew := int64(client.Minor) - int64(server.Minor)
+
+	return skew >= -maxMinorVersionSkew && skew <= maxMinorVersionSkew
+}
+
+// useManagedVersion uses the specified version of kubectl, downloading it into the azd bin directory when missing
+func (cli *Cli) useManagedVersion(ctx context.Context, version semver.Version) error {
+	kubectlPath, err := azdKubectlPath(version)
+	if err != nil {
+		return fmt.Errorf("finding kubectl: %w", err)
+	}
+
+	if _, err := os.Stat(kubectlPath); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(kubectlPath), osutil.PermissionDirectory); err != nil {
+			return fmt.Errorf("downloading kubectl: %w", err)
+		}
+
+		if err := downloadKubectl(ctx, cli.transporter, version, kubectlPath); err != nil {
+			return fmt.Errorf("downloading kubectl: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("finding kubectl: %w", err)
+	}
+
+	cli.path = kubectlPath
+
+	return nil
+}
+
+// azdKubectlPath returns the path of the specified kubectl version within the azd bin directory
+// Each version is stored in its own directory so that the binary keeps its 'kubectl' name.
+func azdKubectlPath(version semver.Version) (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	name := "kubectl"
+	if runtime.GOOS == "windows" {
+		name = "kubectl.exe"
+	}
+
+	return filepath.Join(configDir, "bin", "kubectl", fmt.Sprintf("v%s", version), name), nil
+}
+
+func downloadKubectl(ctx context.Context, transporter policy.Transporter, version semver.Version, name string) error {
+	switch runtime.GOARCH {
+	case "amd64", "arm64":
+	default:
+		return fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
+	}
+
+	releaseName := "kubectl"
+	switch runtime.GOOS {
+	case "windows":
+		releaseName = "kubectl.exe"
+	case "darwin", "linux":
+	default:
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+
+	kubectlReleaseUrl := fmt.Sprintf(
+		"https://dl.k8s.io/release/v%s/bin/%s/%s/%s", version, runtime.GOOS, runtime.GOARCH, releaseName,
+	)
+
+	// The checksum published alongside the release, verified before the binary is installed
+	expectedChecksum, err := downloadKubectlChecksum(ctx, transporter, kubectlReleaseUrl+".sha256")
+	if err != nil {
+		return fmt.Errorf("fetching kubectl checksum: %w", err)
+	}
+
+	log.Printf("downloading kubectl release %s -> %s", kubectlReleaseUrl, name)
+
+	resp, err := httpGet(ctx, transporter, kubectlReleaseUrl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	f, err := os.CreateTemp(filepath.Dir(name), fmt.Sprintf("%s.tmp*", filepath.Base(name)))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), resp.Body); err != nil {
+		return err
+	}
+
+	if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != expectedChecksum {
+		return fmt.Errorf(
+			"the checksum %s of the downloaded kubectl does not match the published checksum %s", checksum, expectedChecksum)
+	}
+
+	if err := f.Chmod(osutil.PermissionExecutableFile); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return osutil.Rename(ctx, f.Name(), name)
+}
+
+// downloadKubectlChecksum returns the hex encoded sha256 checksum published for a kubectl release
+// The checksum file contains the checksum only, ex) https://dl.k8s.io/release/v1.31.2/bin/linux/amd64/kubectl.sha256
+func downloadKubectlChecksum(ctx context.Context, transporter policy.Transporter, checksumUrl string) (string, error) {
+	resp, err := httpGet(ctx, transporter, checksumUrl)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	contents, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(contents))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("the checksum file %s is empty", checksumUrl)
+	}
+
+	checksum := strings.ToLower(fields[0])
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("the checksum file %s does not contain a sha256 checksum", checksumUrl)
+	}
+
+	return checksum, nil
+}
+
+// httpGet sends a GET request to the url, returning the response when successful
+func httpGet(ctx context.Context, transporter policy.Transporter, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := transporter.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("http error %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,173 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckInstalled_Download(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+	// kubectl cannot be found in an empty PATH
+	t.Setenv("PATH", t.TempDir())
+
+	mockContext := mocks.NewMockContext(context.Background())
+	downloadUrl := mockKubectlRelease(mockContext, "this is kubectl", checksum("this is kubectl"))
+
+	var versionArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "version --client=true")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		versionArgs = args
+		return exec.NewRunResult(0, clientVersionOutput(Version.String()), ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.transporter = mockContext.HttpClient
+
+	err := cli.CheckInstalled(*mockContext.Context)
+	require.NoError(t, err)
+	require.Contains(t, *downloadUrl, fmt.Sprintf("/release/v%s/bin/", Version))
+
+	kubectlPath, err := azdKubectlPath(Version)
+	require.NoError(t, err)
+	require.Equal(t, kubectlPath, versionArgs.Cmd)
+
+	contents, err := os.ReadFile(kubectlPath)
+	require.NoError(t, err)
+	require.Equal(t, []byte("this is kubectl"), contents)
+}
+
+func Test_CheckInstalled_ChecksumMismatch(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+	t.Setenv("PATH", t.TempDir())
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockKubectlRelease(mockContext, "this is not kubectl", checksum("this is kubectl"))
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.transporter = mockContext.HttpClient
+
+	err := cli.CheckInstalled(*mockContext.Context)
+	require.ErrorContains(t, err, "does not match the published checksum")
+
+	// The binary is not installed when its checksum does not match
+	kubectlPath, err := azdKubectlPath(Version)
+	require.NoError(t, err)
+	require.NoFileExists(t, kubectlPath)
+}
+
+func Test_EnsureCompatibleVersion(t *testing.T) {
+	tests := map[string]struct {
+		clientVersion   string
+		serverVersion   string
+		toolPath        string
+		expectedVersion string
+	}{
+		"Compatible": {
+			clientVersion: "v1.30.1",
+			serverVersion: "1.29.4",
+		},
+		"TooOld": {
+			clientVersion:   "v1.27.3",
+			serverVersion:   "1.29.4",
+			expectedVersion: "1.29.4",
+		},
+		"TooNew": {
+			clientVersion:   "v1.31.2",
+			serverVersion:   "1.29.4",
+			expectedVersion: "1.29.4",
+		},
+		"ToolPathOverride": {
+			clientVersion: "v1.27.3",
+			serverVersion: "1.29.4",
+			toolPath:      "/usr/local/bin/kubectl",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+			t.Setenv("AZD_KUBECTL_TOOL_PATH", test.toolPath)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			downloadUrl := mockKubectlRelease(mockContext, "this is kubectl", checksum("this is kubectl"))
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "version --client=true")
+			}).Respond(exec.NewRunResult(0, clientVersionOutput(test.clientVersion), ""))
+
+			var getArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "get pods")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				getArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			cli := NewCli(mockContext.CommandRunner)
+			cli.transporter = mockContext.HttpClient
+
+			err := cli.EnsureCompatibleVersion(*mockContext.Context, test.serverVersion)
+			require.NoError(t, err)
+
+			_, err = cli.Exec(*mockContext.Context, nil, "get", "pods")
+			require.NoError(t, err)
+
+			if test.expectedVersion == "" {
+				require.Empty(t, *downloadUrl)
+				require.Equal(t, "kubectl", getArgs.Cmd)
+				return
+			}
+
+			require.Contains(t, *downloadUrl, fmt.Sprintf("/release/v%s/bin/", test.expectedVersion))
+
+			kubectlPath, err := azdKubectlPath(semver.MustParse(test.expectedVersion))
+			require.NoError(t, err)
+			require.Equal(t, kubectlPath, getArgs.Cmd)
+		})
+	}
+}
+
+func clientVersionOutput(version string) string {
+	return fmt.Sprintf(`{"clientVersion": {"gitVersion": "%s"}}`, version)
+}
+
+// mockKubectlRelease responds to the kubectl release downloads with the contents and the checksum published for them,
+// returning the url the binary was downloaded from
+func mockKubectlRelease(mockContext *mocks.MockContext, contents string, checksum string) *string {
+	var downloadUrl string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Host == "dl.k8s.io"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body := checksum
+		if !strings.HasSuffix(request.URL.Path, ".sha256") {
+			downloadUrl = request.URL.String()
+			body = contents
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}, nil
+	})
+
+	return &downloadUrl
+}
+
+func checksum(contents string) string {
+	hash := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(hash[:])
+}
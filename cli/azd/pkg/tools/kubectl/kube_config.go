@@ -2,11 +2,13 @@ package kubectl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/kubelogin"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"gopkg.in/yaml.v3"
 )
@@ -15,6 +17,28 @@ import (
 type KubeConfigManager struct {
 	cli        *Cli
 	configPath string
+	kubeLogin  *KubeLoginOptions
+}
+
+// The kubelogin login modes the kube config credentials can be converted to
+const (
+	// Tokens are acquired from the azd login (default)
+	KubeLoginAzd = "azd"
+	// Tokens are acquired from the Azure CLI login
+	KubeLoginAzureCli = "azurecli"
+	// Tokens are exchanged from the federated token of the workload identity, ex) in CI pipelines
+	KubeLoginWorkloadIdentity = "workloadidentity"
+)
+
+// KubeLoginOptions configures the conversion of the kube configs written for AAD enabled clusters
+// to the exec auth module of kubelogin
+type KubeLoginOptions struct {
+	// The kubelogin CLI used for the conversion
+	Cli *kubelogin.Cli
+	// The kubelogin login mode, ex) azurecli. Defaults to azd
+	Login string
+	// The AAD tenant of the cluster
+	TenantId string
 }
 
 // Creates a new instance of the KubeConfigManager
@@ -30,6 +54,12 @@ func NewKubeConfigManager(cli *Cli) (*KubeConfigManager, error) {
 	}, nil
 }
 
+// Sets the kubelogin conversion applied to the kube configs added or updated by the manager
+// A nil value leaves the credentials of the kube configs unchanged
+func (kcm *KubeConfigManager) SetKubeLogin(options *KubeLoginOptions) {
+	kcm.kubeLogin = options
+}
+
 // Parses the raw bytes into a KubeConfig instance
 func ParseKubeConfig(ctx context.Context, raw []byte) (*KubeConfig, error) {
 	var existing KubeConfig
@@ -110,9 +140,110 @@ func (kcm *KubeConfigManager) AddOrUpdateContext(
 		return "", fmt.Errorf("failed write new kube context file: %w", err)
 	}
 
+	if err := kcm.ConvertKubeConfig(ctx, configPath); err != nil {
+		return "", err
+	}
+
 	return configPath, nil
 }
 
+// Converts the credentials of the kube config file to the exec auth module of kubelogin with the configured
+// login mode, so that tokens are acquired from azd, the Azure CLI or a workload identity.
+// The kube config is left unchanged when no kubelogin conversion is configured.
+func (kcm *KubeConfigManager) ConvertKubeConfig(ctx context.Context, kubeConfigPath string) error {
+	if kcm.kubeLogin == nil {
+		return nil
+	}
+
+	login := kcm.kubeLogin.Login
+	switch login {
+	case "":
+		login = KubeLoginAzd
+	case KubeLoginAzd, KubeLoginAzureCli, KubeLoginWorkloadIdentity:
+	default:
+		return fmt.Errorf(
+			"unsupported kubelogin login mode '%s', supported values are '%s', '%s' and '%s'",
+			login,
+			KubeLoginAzd,
+			KubeLoginAzureCli,
+			KubeLoginWorkloadIdentity,
+		)
+	}
+
+	return kcm.kubeLogin.Cli.ConvertKubeConfig(ctx, &kubelogin.ConvertOptions{
+		Login:      login,
+		KubeConfig: kubeConfigPath,
+		TenantId:   kcm.kubeLogin.TenantId,
+	})
+}
+
+// Removes the context along with its cluster and user from the default kube config and deletes the kube config
+// file with the specified name. Entries that no longer exist are ignored.
+func (kcm *KubeConfigManager) RemoveContext(ctx context.Context, configName string, kubeContext *KubeContext) error {
+	kubeConfigPath := filepath.Join(kcm.configPath, "config")
+	kubeConfigRaw, err := os.ReadFile(kubeConfigPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed reading kube config: %w", err)
+	}
+
+	if err == nil {
+		// The kube config is modified as a generic document to preserve any fields not modeled by KubeConfig
+		var kubeConfig map[string]any
+		if err := yaml.Unmarshal(kubeConfigRaw, &kubeConfig); err != nil {
+			return fmt.Errorf("failed unmarshalling Kube Config YAML: %w", err)
+		}
+
+		if kubeConfig == nil {
+			kubeConfig = map[string]any{}
+		}
+
+		removeNamedEntry(kubeConfig, "contexts", kubeContext.Name)
+		removeNamedEntry(kubeConfig, "clusters", kubeContext.Context.Cluster)
+		removeNamedEntry(kubeConfig, "users", kubeContext.Context.User)
+
+		if currentContext, _ := kubeConfig["current-context"].(string); currentContext == kubeContext.Name {
+			kubeConfig["current-context"] = ""
+		}
+
+		kubeConfigRaw, err = yaml.Marshal(kubeConfig)
+		if err != nil {
+			return fmt.Errorf("failed marshalling KubeConfig to yaml: %w", err)
+		}
+
+		if err := os.WriteFile(kubeConfigPath, kubeConfigRaw, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("failed writing kube config file: %w", err)
+		}
+	}
+
+	if configName != "" {
+		err := os.Remove(filepath.Join(kcm.configPath, configName))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed deleting kube config file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// removeNamedEntry removes the entries with the specified name from the kube config list, ex) contexts
+func removeNamedEntry(kubeConfig map[string]any, listName string, name string) {
+	entries, ok := kubeConfig[listName].([]any)
+	if !ok || name == "" {
+		return
+	}
+
+	kept := []any{}
+	for _, entry := range entries {
+		if entryMap, ok := entry.(map[string]any); ok && entryMap["name"] == name {
+			continue
+		}
+
+		kept = append(kept, entry)
+	}
+
+	kubeConfig[listName] = kept
+}
+
 func getKubeConfigDir() (string, error) {
 	userHomeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -3,10 +3,13 @@ package kubectl
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/kubelogin"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
@@ -52,6 +55,120 @@ func Test_MergeKubeConfig(t *testing.T) {
 	require.Contains(t, kubeConfigPath, filepath.Join(".kube", "config"))
 }
 
+func Test_RemoveContext(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	kubeConfigManager, err := NewKubeConfigManager(NewCli(mockContext.CommandRunner))
+	require.NoError(t, err)
+
+	config1 := createTestCluster("cluster1", "user1")
+	config2 := createTestCluster("cluster2", "user2")
+	kubeConfig := &KubeConfig{
+		ApiVersion:     "v1",
+		Kind:           "Config",
+		CurrentContext: "cluster1",
+		Clusters:       append(config1.Clusters, config2.Clusters...),
+		Contexts:       append(config1.Contexts, config2.Contexts...),
+		Users:          append(config1.Users, config2.Users...),
+	}
+
+	_, err = kubeConfigManager.SaveKubeConfig(*mockContext.Context, "config", kubeConfig)
+	require.NoError(t, err)
+	_, err = kubeConfigManager.SaveKubeConfig(*mockContext.Context, "cluster1", config1)
+	require.NoError(t, err)
+
+	err = kubeConfigManager.RemoveContext(*mockContext.Context, "cluster1", config1.Contexts[0])
+	require.NoError(t, err)
+	require.NoFileExists(t, filepath.Join(homeDir, ".kube", "cluster1"))
+
+	kubeConfigRaw, err := os.ReadFile(filepath.Join(homeDir, ".kube", "config"))
+	require.NoError(t, err)
+
+	updated, err := ParseKubeConfig(*mockContext.Context, kubeConfigRaw)
+	require.NoError(t, err)
+	require.Empty(t, updated.CurrentContext)
+	require.Equal(t, config2.Clusters, updated.Clusters)
+	require.Equal(t, config2.Contexts, updated.Contexts)
+	require.Len(t, updated.Users, 1)
+	require.Equal(t, config2.Users[0].Name, updated.Users[0].Name)
+
+	// Removing a context that no longer exists is a no-op
+	err = kubeConfigManager.RemoveContext(*mockContext.Context, "cluster1", config1.Contexts[0])
+	require.NoError(t, err)
+}
+
+func Test_AddOrUpdateContext_KubeLogin(t *testing.T) {
+	tests := map[string]struct {
+		kubeLogin     *KubeLoginOptions
+		expectedArgs  []string
+		expectedError string
+	}{
+		"NotConfigured": {},
+		"DefaultLogin": {
+			kubeLogin:    &KubeLoginOptions{TenantId: "TENANT_ID"},
+			expectedArgs: []string{"convert-kubeconfig", "--login", "azd", "--kubeconfig", "%s", "--tenant-id", "TENANT_ID"},
+		},
+		"WorkloadIdentity": {
+			kubeLogin:    &KubeLoginOptions{Login: KubeLoginWorkloadIdentity},
+			expectedArgs: []string{"convert-kubeconfig", "--login", "workloadidentity", "--kubeconfig", "%s"},
+		},
+		"Unsupported": {
+			kubeLogin:     &KubeLoginOptions{Login: "devicecode"},
+			expectedError: "unsupported kubelogin login mode 'devicecode'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			t.Setenv("HOME", homeDir)
+			t.Setenv("USERPROFILE", homeDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			var kubeLoginArgs []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return args.Cmd == "kubelogin"
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				kubeLoginArgs = args.Args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			kubeConfigManager, err := NewKubeConfigManager(NewCli(mockContext.CommandRunner))
+			require.NoError(t, err)
+
+			if test.kubeLogin != nil {
+				test.kubeLogin.Cli = kubelogin.NewCli(mockContext.CommandRunner)
+				kubeConfigManager.SetKubeLogin(test.kubeLogin)
+			}
+
+			configPath, err := kubeConfigManager.AddOrUpdateContext(
+				*mockContext.Context, "cluster1", createTestCluster("cluster1", "user1"),
+			)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				require.Nil(t, kubeLoginArgs)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(homeDir, ".kube", "cluster1"), configPath)
+			require.FileExists(t, configPath)
+
+			if test.expectedArgs == nil {
+				require.Nil(t, kubeLoginArgs)
+				return
+			}
+
+			expectedArgs := slices.Clone(test.expectedArgs)
+			expectedArgs[slices.Index(expectedArgs, "%s")] = configPath
+			require.Equal(t, expectedArgs, kubeLoginArgs)
+		})
+	}
+}
+
 func createTestCluster(clusterName, username string) *KubeConfig {
 	return &KubeConfig{
 		ApiVersion:     "v1",
@@ -3,14 +3,21 @@ package kubectl
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 )
 
@@ -33,6 +40,38 @@ const (
 	DryRunTypeServer DryRunType = "server"
 )
 
+// The field manager recorded for the fields of the resources applied by azd
+const DefaultFieldManager = "azd"
+
+// The server-side apply options
+// When configured, manifests are applied by the API server which tracks the ownership of each field by its field manager
+// instead of storing the last-applied-configuration annotation
+type ServerSideApplyOptions struct {
+	// The field manager recorded for the applied fields. Defaults to 'azd'
+	FieldManager string
+	// When enabled, fields owned by other field managers are taken over instead of failing with a conflict
+	ForceConflicts bool
+}
+
+// fieldManager returns the field manager of the applied fields
+func (o *ServerSideApplyOptions) fieldManager() string {
+	if o == nil || o.FieldManager == "" {
+		return DefaultFieldManager
+	}
+
+	return o.FieldManager
+}
+
+// args returns the kubectl apply and diff flags of the server-side apply options
+func (o *ServerSideApplyOptions) args() []string {
+	args := []string{"--server-side", fmt.Sprintf("--field-manager=%s", o.fieldManager())}
+	if o.ForceConflicts {
+		args = append(args, "--force-conflicts")
+	}
+
+	return args
+}
+
 // K8s CLI Fags
 type KubeCliFlags struct {
 	// The namespace to filter the command or create resources
@@ -41,6 +80,11 @@ type KubeCliFlags struct {
 	DryRun DryRunType
 	// The expected output, typically JSON or YAML
 	Output OutputType
+	// The label selector used to filter the resources, ex) app=api
+	LabelSelector string
+	// When enabled, applying manifests deletes the resources matching the label selector
+	// that are no longer defined within the applied manifests
+	Prune bool
 }
 
 // templateRoot is the structure of the template available within the test templates that can be used within k8s manifests.
@@ -52,21 +96,41 @@ type templateRoot struct {
 
 type Cli struct {
 	commandRunner exec.CommandRunner
-	env           map[string]string
-	cwd           string
+	// The kubectl binary used for local commands, defaults to the kubectl found in the PATH
+	path        string
+	transporter policy.Transporter
+	// Optional command runner used for commands that communicate with the k8s API server
+	remoteRunner exec.CommandRunner
+	env          map[string]string
+	cwd          string
+	// Optional kube config file passed to every local command with the --kubeconfig flag
+	kubeConfigPath string
+	// When configured, manifests are applied and diffed with server-side apply
+	serverSideApply *ServerSideApplyOptions
+	// When configured, commands that fail with transient API server errors are retried
+	retry *RetryOptions
+	// When enabled, supported commands communicate with the k8s API server directly instead of executing kubectl
+	useApiClient bool
+	apiClient    *ApiClient
+	// The kube config files the cached API client was created from
+	apiClientKubeConfig string
+	newApiClient        func(kubeConfigPaths ...string) (*ApiClient, error)
 }
 
 // Creates a new K8s CLI instance
 func NewCli(commandRunner exec.CommandRunner) *Cli {
 	return &Cli{
 		commandRunner: commandRunner,
+		transporter:   http.DefaultClient,
 		env:           map[string]string{},
+		newApiClient:  NewApiClient,
 	}
 }
 
 // Checks whether or not the K8s CLI is installed and available within the PATH
+// When kubectl is not installed, the version pinned by azd is downloaded instead.
 func (cli *Cli) CheckInstalled(ctx context.Context) error {
-	if err := tools.ToolInPath("kubectl"); err != nil {
+	if err := cli.ensureInstalled(ctx); err != nil {
 		return err
 	}
 
@@ -123,11 +187,39 @@ func (cli *Cli) SetKubeConfig(kubeConfig string) {
 	cli.env[KubeConfigEnvVarName] = kubeConfig
 }
 
+// Sets the kube config file passed explicitly with the --kubeconfig flag to all commands executed locally
+// An empty path restores the default kube config resolution
+func (cli *Cli) SetKubeConfigPath(kubeConfigPath string) {
+	cli.kubeConfigPath = kubeConfigPath
+}
+
+// Sets the server-side apply options used when applying or diffing manifests
+// A nil value restores client-side apply
+func (cli *Cli) SetServerSideApply(options *ServerSideApplyOptions) {
+	cli.serverSideApply = options
+}
+
+// Enables communicating with the k8s API server directly for the supported commands, ex) get, apply, patch,
+// delete and rollout status. Commands that are not supported, or that run through a remote runner, continue to
+// execute kubectl. kubectl is also used when the API client cannot be created from the kube config.
+func (cli *Cli) EnableApiClient(enabled bool) {
+	cli.useApiClient = enabled
+	cli.apiClient = nil
+}
+
 // Sets the current working directory
 func (cli *Cli) Cwd(cwd string) {
 	cli.cwd = cwd
 }
 
+// Sets the command runner used for commands that communicate with the k8s API server, ex) private clusters
+// that are only reachable through a remote execution environment.
+// Commands that only operate on the local kube config or client continue to run locally.
+// Setting a nil runner resets the CLI to run all commands locally.
+func (cli *Cli) SetRemoteRunner(runner exec.CommandRunner) {
+	cli.remoteRunner = runner
+}
+
 // Sets the k8s context to use for future CLI commands
 func (cli *Cli) ConfigUseContext(ctx context.Context, name string, flags *KubeCliFlags) (*exec.RunResult, error) {
 	res, err := cli.Exec(ctx, flags, "config", "use-context", name)
@@ -135,6 +227,9 @@ func (cli *Cli) ConfigUseContext(ctx context.Context, name string, flags *KubeCl
 		return nil, fmt.Errorf("failed setting kubectl context: %w", err)
 	}
 
+	// The API client is recreated for the new current context
+	cli.apiClient = nil
+
 	return &res, nil
 }
 
@@ -170,9 +265,21 @@ func (cli *Cli) ConfigView(
 }
 
 func (cli *Cli) ApplyWithStdIn(ctx context.Context, input string, flags *KubeCliFlags) (*exec.RunResult, error) {
-	runArgs := exec.
+	// Applied resources are only reported in the kubectl output format
+	apiClient := cli.getApiClient()
+	if apiClient != nil && apiClientSupported(flags) && (flags == nil || flags.Output == "") {
+		output, err := apiClient.Apply(ctx, input, cli.serverSideApply, flags)
+		if err != nil {
+			return nil, fmt.Errorf("failed applying manifests: %w", err)
+		}
+
+		res := exec.NewRunResult(0, output, "")
+		return &res, nil
+	}
+
+	runArgs := cli.withServerSideApply(exec.
 		NewRunArgs("kubectl", "apply", "-f", "-").
-		WithStdIn(strings.NewReader(input))
+		WithStdIn(strings.NewReader(input)))
 
 	res, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
 	if err != nil {
@@ -183,7 +290,28 @@ func (cli *Cli) ApplyWithStdIn(ctx context.Context, input string, flags *KubeCli
 }
 
 func (cli *Cli) ApplyWithFile(ctx context.Context, filePath string, flags *KubeCliFlags) (*exec.RunResult, error) {
-	runArgs := exec.NewRunArgs("kubectl", "apply", "-f", filePath)
+	// Applied resources are only reported in the kubectl output format
+	apiClient := cli.getApiClient()
+	if apiClient != nil && apiClientSupported(flags) && (flags == nil || flags.Output == "") {
+		if !filepath.IsAbs(filePath) && cli.cwd != "" {
+			filePath = filepath.Join(cli.cwd, filePath)
+		}
+
+		manifests, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading manifest '%s', %w", filePath, err)
+		}
+
+		output, err := apiClient.Apply(ctx, string(manifests), cli.serverSideApply, flags)
+		if err != nil {
+			return nil, fmt.Errorf("failed applying manifest '%s': %w", filePath, err)
+		}
+
+		res := exec.NewRunResult(0, output, "")
+		return &res, nil
+	}
+
+	runArgs := cli.withServerSideApply(exec.NewRunArgs("kubectl", "apply", "-f", filePath))
 
 	res, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
 	if err != nil {
@@ -195,16 +323,98 @@ func (cli *Cli) ApplyWithFile(ctx context.Context, filePath string, flags *KubeC
 
 // Applies manifests from the specified input
 func (cli *Cli) Apply(ctx context.Context, path string, flags *KubeCliFlags) error {
-	if err := cli.applyTemplates(ctx, path, flags); err != nil {
+	if err := cli.applyTemplates(ctx, path, false, flags); err != nil {
 		return fmt.Errorf("failed process templates, %w", err)
 	}
 
 	return nil
 }
 
+// Applies manifests from the specified input after substituting environment variable references
+// (ex: ${SERVICE_API_IMAGE_NAME}) within all manifests with the env values available to the CLI
+func (cli *Cli) ApplyWithEnvsubst(ctx context.Context, path string, flags *KubeCliFlags) error {
+	if err := cli.applyTemplates(ctx, path, true, flags); err != nil {
+		return fmt.Errorf("failed process templates, %w", err)
+	}
+
+	return nil
+}
+
+// Reads all k8s manifests from the specified directory and returns the manifest contents as they would
+// be applied, with *.tmpl files executed as templates and environment variable references substituted when
+// envsubst is enabled.
+func (cli *Cli) ReadManifests(directoryPath string, envsubst bool) ([]string, error) {
+	files, err := cli.readManifestFiles(directoryPath, envsubst)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]string, 0, len(files))
+	for _, file := range files {
+		manifests = append(manifests, file.content)
+	}
+
+	return manifests, nil
+}
+
+// manifestFile is a rendered k8s manifest file
+type manifestFile struct {
+	path    string
+	content string
+}
+
+// readManifestFiles reads and renders the yaml manifest files within the directory and its subdirectories
+func (cli *Cli) readManifestFiles(directoryPath string, envsubst bool) ([]manifestFile, error) {
+	manifests := []manifestFile{}
+
+	entries, err := os.ReadDir(directoryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading files in path, '%s', %w", directoryPath, err)
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(directoryPath, entry.Name())
+
+		if entry.IsDir() {
+			children, err := cli.readManifestFiles(entryPath, envsubst)
+			if err != nil {
+				return nil, err
+			}
+
+			manifests = append(manifests, children...)
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		var manifest string
+		switch {
+		case strings.HasSuffix(strings.TrimSuffix(entry.Name(), ext), ".tmpl"):
+			manifest, err = cli.renderTemplate(entryPath, envsubst)
+		case envsubst:
+			manifest, err = cli.renderEnvsubst(entryPath)
+		default:
+			var content []byte
+			content, err = os.ReadFile(entryPath)
+			manifest = string(content)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, manifestFile{path: entryPath, content: manifest})
+	}
+
+	return manifests, nil
+}
+
 // Applies the manifests at the specified path using kustomize
 func (cli *Cli) ApplyWithKustomize(ctx context.Context, path string, flags *KubeCliFlags) error {
-	runArgs := exec.NewRunArgs("kubectl", "apply", "-k", path)
+	runArgs := cli.withServerSideApply(exec.NewRunArgs("kubectl", "apply", "-k", path))
 
 	_, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
 	if err != nil {
@@ -214,8 +424,51 @@ func (cli *Cli) ApplyWithKustomize(ctx context.Context, path string, flags *Kube
 	return nil
 }
 
+// Diffs the manifests from the specified input against the live state of the cluster
+// Returns the diff output and whether any differences were found
+func (cli *Cli) DiffWithStdIn(ctx context.Context, input string, flags *KubeCliFlags) (string, bool, error) {
+	runArgs := cli.withServerSideApply(exec.
+		NewRunArgs("kubectl", "diff", "-f", "-").
+		WithStdIn(strings.NewReader(input)))
+
+	return cli.diff(ctx, runArgs, flags)
+}
+
+// Diffs the manifests at the specified path using kustomize against the live state of the cluster
+// Returns the diff output and whether any differences were found
+func (cli *Cli) DiffWithKustomize(ctx context.Context, path string, flags *KubeCliFlags) (string, bool, error) {
+	runArgs := cli.withServerSideApply(exec.NewRunArgs("kubectl", "diff", "-k", path))
+
+	return cli.diff(ctx, runArgs, flags)
+}
+
+// diff runs the kubectl diff command
+// kubectl diff exits with code 1 when differences were found and with a code greater than 1 on failure
+func (cli *Cli) diff(ctx context.Context, runArgs exec.RunArgs, flags *KubeCliFlags) (string, bool, error) {
+	res, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
+	if err == nil {
+		return "", false, nil
+	}
+
+	if res.ExitCode == 1 {
+		return res.Stdout, true, nil
+	}
+
+	return "", false, fmt.Errorf("failed running kubectl diff: %w", err)
+}
+
 // Creates a new k8s namespace with the specified name
 func (cli *Cli) CreateNamespace(ctx context.Context, name string, flags *KubeCliFlags) (*exec.RunResult, error) {
+	if apiClient := cli.getApiClient(); apiClient != nil && (flags == nil || !flags.Prune) {
+		output, err := apiClient.Create(ctx, newUnstructured("v1", "Namespace", name), flags)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating namespace '%s': %w", name, err)
+		}
+
+		res := exec.NewRunResult(0, output, "")
+		return &res, nil
+	}
+
 	args := []string{"create", "namespace", name}
 
 	res, err := cli.Exec(ctx, flags, args...)
@@ -226,12 +479,88 @@ func (cli *Cli) CreateNamespace(ctx context.Context, name string, flags *KubeCli
 	return &res, nil
 }
 
+// Creates a new k8s service account with the specified name
+func (cli *Cli) CreateServiceAccount(ctx context.Context, name string, flags *KubeCliFlags) (*exec.RunResult, error) {
+	if apiClient := cli.getApiClient(); apiClient != nil && (flags == nil || !flags.Prune) {
+		output, err := apiClient.Create(ctx, newUnstructured("v1", "ServiceAccount", name), flags)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating service account '%s': %w", name, err)
+		}
+
+		res := exec.NewRunResult(0, output, "")
+		return &res, nil
+	}
+
+	res, err := cli.Exec(ctx, flags, "create", "serviceaccount", name)
+	if err != nil {
+		return nil, fmt.Errorf("kubectl create serviceaccount: %w", err)
+	}
+
+	return &res, nil
+}
+
+// Updates the k8s resource with the specified type and name using a JSON merge patch
+func (cli *Cli) Patch(
+	ctx context.Context,
+	resourceType ResourceType,
+	name string,
+	patch string,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	if apiClient := cli.getApiClient(); apiClient != nil && apiClientSupported(flags) {
+		if err := apiClient.Patch(ctx, resourceType, name, patch, flags); err != nil {
+			return nil, fmt.Errorf("failed patching %s '%s': %w", resourceType, name, err)
+		}
+
+		return &exec.RunResult{}, nil
+	}
+
+	res, err := cli.Exec(ctx, flags, "patch", string(resourceType), name, "--type", "merge", "-p", patch)
+	if err != nil {
+		return nil, fmt.Errorf("kubectl patch: %w", err)
+	}
+
+	return &res, nil
+}
+
+// Deletes the k8s resource with the specified type and name. Resources that do not exist are ignored
+func (cli *Cli) Delete(
+	ctx context.Context,
+	resourceType ResourceType,
+	name string,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	if apiClient := cli.getApiClient(); apiClient != nil && apiClientSupported(flags) {
+		if err := apiClient.Delete(ctx, resourceType, name, flags); err != nil {
+			return nil, fmt.Errorf("failed deleting %s '%s': %w", resourceType, name, err)
+		}
+
+		return &exec.RunResult{}, nil
+	}
+
+	res, err := cli.Exec(ctx, flags, "delete", string(resourceType), name, "--ignore-not-found")
+	if err != nil {
+		return nil, fmt.Errorf("kubectl delete: %w", err)
+	}
+
+	return &res, nil
+}
+
 // Gets the deployment rollout status
 func (cli *Cli) RolloutStatus(
 	ctx context.Context,
 	deploymentName string,
 	flags *KubeCliFlags,
 ) (*exec.RunResult, error) {
+	if apiClient := cli.getApiClient(); apiClient != nil && apiClientSupported(flags) {
+		if err := apiClient.RolloutStatus(ctx, deploymentName, flags); err != nil {
+			return nil, fmt.Errorf("deployment rollout failed, %w", err)
+		}
+
+		res := exec.NewRunResult(0, fmt.Sprintf("deployment %q successfully rolled out\n", deploymentName), "")
+		return &res, nil
+	}
+
 	res, err := cli.Exec(ctx, flags, "rollout", "status", fmt.Sprintf("deployment/%s", deploymentName))
 	if err != nil {
 		return nil, fmt.Errorf("deployment rollout failed, %w", err)
@@ -240,6 +569,194 @@ func (cli *Cli) RolloutStatus(
 	return &res, nil
 }
 
+// Rolls back the deployment to the previous revision
+func (cli *Cli) RolloutUndo(
+	ctx context.Context,
+	deploymentName string,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	res, err := cli.Exec(ctx, flags, "rollout", "undo", fmt.Sprintf("deployment/%s", deploymentName))
+	if err != nil {
+		return nil, fmt.Errorf("deployment rollback failed, %w", err)
+	}
+
+	return &res, nil
+}
+
+// Waits until the resources meet the condition, equivalent to 'kubectl wait <resource> --for=<condition>'
+// The resource is either a single resource, ex) deployment/api, or a resource type combined with a label selector.
+// The condition uses the format of the kubectl wait command, ex) condition=Available or jsonpath={.status.phase}=Running
+func (cli *Cli) Wait(
+	ctx context.Context,
+	resource string,
+	condition string,
+	timeout time.Duration,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	args := []string{"wait", resource, fmt.Sprintf("--for=%s", condition)}
+	if timeout > 0 {
+		args = append(args, fmt.Sprintf("--timeout=%s", timeout))
+	}
+
+	res, err := cli.Exec(ctx, flags, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for '%s' to meet condition '%s', %w", resource, condition, err)
+	}
+
+	return &res, nil
+}
+
+// Gets the logs of all containers for the specified resource, ex) job/migrate
+// Only the last number of lines specified by tail are returned
+func (cli *Cli) Logs(ctx context.Context, resource string, tail int, flags *KubeCliFlags) (string, error) {
+	res, err := cli.Exec(ctx, flags, "logs", resource, "--all-containers", fmt.Sprintf("--tail=%d", tail))
+	if err != nil {
+		return "", fmt.Errorf("failed getting logs for '%s', %w", resource, err)
+	}
+
+	return res.Stdout, nil
+}
+
+// LogsOptions are the options for streaming the logs of a pod
+type LogsOptions struct {
+	// The container to get the logs of, defaults to all containers of the pod
+	Container string
+	// Streams new log entries until the context is cancelled or the pod terminated
+	Follow bool
+	// Only returns the log entries newer than the duration
+	Since time.Duration
+	// The number of recent log entries to return, defaults to all entries
+	Tail int
+}
+
+// Streams the logs of the specified resource, ex) pod/api-5d8f7b-abcde, to the writer
+// When following the logs, blocks until the context is cancelled, which returns nil, or until the pod terminated
+func (cli *Cli) StreamLogs(
+	ctx context.Context,
+	resource string,
+	options *LogsOptions,
+	flags *KubeCliFlags,
+	writer io.Writer,
+) error {
+	if options == nil {
+		options = &LogsOptions{}
+	}
+
+	runArgs := exec.NewRunArgs("kubectl", "logs", resource)
+	if options.Container != "" {
+		runArgs = runArgs.AppendParams("-c", options.Container)
+	} else {
+		runArgs = runArgs.AppendParams("--all-containers")
+	}
+
+	if options.Follow {
+		// Commands executed through a remote runner only return their output once completed
+		if cli.remoteRunner != nil {
+			return fmt.Errorf("following the logs of '%s' is not supported when using a remote runner", resource)
+		}
+
+		runArgs = runArgs.AppendParams("--follow")
+	}
+
+	if options.Since > 0 {
+		runArgs = runArgs.AppendParams(fmt.Sprintf("--since=%s", options.Since))
+	}
+
+	if options.Tail > 0 {
+		runArgs = runArgs.AppendParams(fmt.Sprintf("--tail=%d", options.Tail))
+	}
+
+	if cli.remoteRunner == nil {
+		runArgs = runArgs.WithStdOut(writer)
+	}
+
+	res, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed getting logs for '%s', %w", resource, err)
+	}
+
+	if cli.remoteRunner != nil {
+		if _, err := io.WriteString(writer, res.Stdout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExecInPodOptions are the options for executing a command in a container of a pod
+type ExecInPodOptions struct {
+	// The container to execute the command in, defaults to the default container of the pod
+	Container string
+	// Allocates a TTY for the command, ex) for an interactive shell
+	Tty bool
+}
+
+// Executes the command in a container of the pod, equivalent to 'kubectl exec -i <pod> -- <command>'
+// The stdin, stdout and stderr of the command are attached to the console.
+func (cli *Cli) ExecInPod(
+	ctx context.Context,
+	pod string,
+	command []string,
+	options *ExecInPodOptions,
+	flags *KubeCliFlags,
+) error {
+	if options == nil {
+		options = &ExecInPodOptions{}
+	}
+
+	// Commands executed through a remote runner are not attached to the console
+	if cli.remoteRunner != nil {
+		return fmt.Errorf("executing commands in pod '%s' is not supported when using a remote runner", pod)
+	}
+
+	runArgs := exec.NewRunArgs("kubectl", "exec", "-i")
+	if options.Tty {
+		runArgs = runArgs.AppendParams("-t")
+	}
+
+	runArgs = runArgs.AppendParams(pod)
+	if options.Container != "" {
+		runArgs = runArgs.AppendParams("-c", options.Container)
+	}
+
+	if flags != nil && flags.Namespace != "" {
+		runArgs = runArgs.AppendParams("-n", flags.Namespace)
+	}
+
+	// The command must be the last argument
+	runArgs = runArgs.
+		AppendParams("--").
+		AppendParams(command...).
+		WithInteractive(true)
+
+	if _, err := cli.executeCommandWithArgs(ctx, runArgs, nil); err != nil {
+		return fmt.Errorf("failed executing command in pod '%s', %w", pod, err)
+	}
+
+	return nil
+}
+
+// Executes kubectl with the arguments as is, ex) for ad-hoc operations against the current cluster
+// The stdin, stdout and stderr of kubectl are attached to the console.
+func (cli *Cli) Passthrough(ctx context.Context, args []string) error {
+	// Commands executed through a remote runner are not attached to the console
+	if cli.remoteRunner != nil {
+		return errors.New("running kubectl commands is not supported when using a remote runner")
+	}
+
+	runArgs := exec.NewRunArgs("kubectl", args...).WithInteractive(true)
+	if _, err := cli.executeCommandWithArgs(ctx, runArgs, nil); err != nil {
+		return fmt.Errorf("failed running kubectl %s, %w", strings.Join(args, " "), err)
+	}
+
+	return nil
+}
+
 // Executes a k8s CLI command from the specified arguments and flags
 func (cli *Cli) Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error) {
 	runArgs := exec.
@@ -249,30 +766,91 @@ func (cli *Cli) Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (
 	return cli.executeCommandWithArgs(ctx, runArgs, flags)
 }
 
-func (cli *Cli) applyTemplate(ctx context.Context, filePath string, flags *KubeCliFlags) (*exec.RunResult, error) {
+func (cli *Cli) applyTemplate(
+	ctx context.Context,
+	filePath string,
+	envsubst bool,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	manifest, err := cli.renderTemplate(filePath, envsubst)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := cli.ApplyWithStdIn(ctx, manifest, flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed applying file '%s', %w", filePath, err)
+	}
+
+	return result, nil
+}
+
+// applyEnvsubst applies the file contents after substituting environment variable references
+func (cli *Cli) applyEnvsubst(ctx context.Context, filePath string, flags *KubeCliFlags) (*exec.RunResult, error) {
+	manifest, err := cli.renderEnvsubst(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := cli.ApplyWithStdIn(ctx, manifest, flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed applying file '%s', %w", filePath, err)
+	}
+
+	return result, nil
+}
+
+// renderTemplate executes the template file with the env values available to the CLI
+func (cli *Cli) renderTemplate(filePath string, envsubst bool) (string, error) {
 	k8sTemplate, err := template.ParseFiles(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed parsing template file '%s', %w", filePath, err)
+		return "", fmt.Errorf("failed parsing template file '%s', %w", filePath, err)
 	}
 
 	builder := strings.Builder{}
 	err = k8sTemplate.Execute(&builder, templateRoot{Env: cli.env})
 	if err != nil {
-		return nil, fmt.Errorf("failed executing template file '%s', %w", filePath, err)
+		return "", fmt.Errorf("failed executing template file '%s', %w", filePath, err)
+	}
+
+	manifest := builder.String()
+	if envsubst {
+		manifest, err = cli.envsubst(manifest)
+		if err != nil {
+			return "", fmt.Errorf("failed substituting environment variables in file '%s', %w", filePath, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// renderEnvsubst returns the file contents after substituting environment variable references
+func (cli *Cli) renderEnvsubst(filePath string) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed reading file '%s', %w", filePath, err)
 	}
 
-	result, err := cli.ApplyWithStdIn(ctx, builder.String(), flags)
+	manifest, err := cli.envsubst(string(content))
 	if err != nil {
-		return nil, fmt.Errorf("failed applying file '%s', %w", filePath, err)
+		return "", fmt.Errorf("failed substituting environment variables in file '%s', %w", filePath, err)
 	}
 
-	return result, nil
+	return manifest, nil
+}
+
+// envsubst replaces ${VAR} style references within the manifest with the env values available to the CLI
+func (cli *Cli) envsubst(manifest string) (string, error) {
+	return osutil.NewExpandableString(manifest).Envsubst(func(name string) string {
+		return cli.env[name]
+	})
 }
 
 // Recursively loops through the specified directory and applies all k8s manifests
 // If the file is a *.tmpl file, it will be parsed as a template to support environment injection.
+// When envsubst is enabled, environment variable references are substituted in all manifests.
 // Otherwise the actual file contents will be applied.
-func (cli *Cli) applyTemplates(ctx context.Context, directoryPath string, flags *KubeCliFlags) error {
+func (cli *Cli) applyTemplates(ctx context.Context, directoryPath string, envsubst bool, flags *KubeCliFlags) error {
 	entries, err := os.ReadDir(directoryPath)
 	if err != nil {
 		return fmt.Errorf("failed reading files in path, '%s', %w", directoryPath, err)
@@ -282,7 +860,7 @@ func (cli *Cli) applyTemplates(ctx context.Context, directoryPath string, flags
 		entryPath := filepath.Join(directoryPath, entry.Name())
 
 		if entry.IsDir() {
-			if err := cli.applyTemplates(ctx, entryPath, flags); err != nil {
+			if err := cli.applyTemplates(ctx, entryPath, envsubst, flags); err != nil {
 				return fmt.Errorf("failed applying templates at '%s', %w", entryPath, err)
 			}
 
@@ -297,9 +875,12 @@ func (cli *Cli) applyTemplates(ctx context.Context, directoryPath string, flags
 			fileNameWithoutExtension := strings.TrimSuffix(entry.Name(), ext)
 			isTemplateFile := strings.HasSuffix(fileNameWithoutExtension, ".tmpl")
 
-			if isTemplateFile {
-				_, err = cli.applyTemplate(ctx, entryPath, flags)
-			} else {
+			switch {
+			case isTemplateFile:
+				_, err = cli.applyTemplate(ctx, entryPath, envsubst, flags)
+			case envsubst:
+				_, err = cli.applyEnvsubst(ctx, entryPath, flags)
+			default:
 				_, err = cli.ApplyWithFile(ctx, entryPath, flags)
 			}
 		default: // Ignore all other files
@@ -314,6 +895,51 @@ func (cli *Cli) applyTemplates(ctx context.Context, directoryPath string, flags
 	return nil
 }
 
+// withServerSideApply appends the server-side apply flags to the apply or diff command when configured
+func (cli *Cli) withServerSideApply(runArgs exec.RunArgs) exec.RunArgs {
+	if cli.serverSideApply == nil {
+		return runArgs
+	}
+
+	return runArgs.AppendParams(cli.serverSideApply.args()...)
+}
+
+// getApiClient returns the API client for the current kube config, or nil when kubectl should be executed instead
+func (cli *Cli) getApiClient() *ApiClient {
+	if !cli.useApiClient || cli.remoteRunner != nil {
+		return nil
+	}
+
+	kubeConfigPaths := []string{}
+	if cli.kubeConfigPath != "" {
+		kubeConfigPaths = append(kubeConfigPaths, cli.kubeConfigPath)
+	} else if kubeConfig := cli.env[KubeConfigEnvVarName]; kubeConfig != "" {
+		kubeConfigPaths = filepath.SplitList(kubeConfig)
+	}
+
+	kubeConfig := strings.Join(kubeConfigPaths, string(filepath.ListSeparator))
+	if cli.apiClient != nil && cli.apiClientKubeConfig == kubeConfig {
+		return cli.apiClient
+	}
+
+	apiClient, err := cli.newApiClient(kubeConfigPaths...)
+	if err != nil {
+		log.Printf("failed creating k8s API client, falling back to kubectl: %v", err)
+		return nil
+	}
+
+	cli.apiClient = apiClient
+	cli.apiClientKubeConfig = kubeConfig
+
+	return apiClient
+}
+
+// apiClientSupported returns true when the command flags can be handled by the API client
+// Client dry-runs and pruning are only supported by kubectl
+func apiClientSupported(flags *KubeCliFlags) bool {
+	return flags == nil || (flags.DryRun != DryRunTypeClient && !flags.Prune)
+}
+
 func (cli *Cli) executeCommandWithArgs(
 	ctx context.Context,
 	args exec.RunArgs,
@@ -335,9 +961,65 @@ func (cli *Cli) executeCommandWithArgs(
 		if flags.Output != "" {
 			args = args.AppendParams("-o", string(flags.Output))
 		}
+		if flags.Prune {
+			args = args.AppendParams("--prune")
+		}
+		if flags.LabelSelector != "" {
+			args = args.AppendParams("-l", flags.LabelSelector)
+		}
+	}
+
+	if cli.remoteRunner != nil && !isLocalCommand(args, flags) {
+		return cli.runWithRetry(ctx, args, func(args exec.RunArgs) (exec.RunResult, error) {
+			res, err := cli.remoteRunner.Run(ctx, args)
+			return res, commandError(res, err)
+		})
+	}
+
+	if cli.kubeConfigPath != "" {
+		args = withKubectlParams(args, "--kubeconfig", cli.kubeConfigPath)
+	}
+
+	if cli.path != "" {
+		args.Cmd = cli.path
+	}
+
+	return cli.runWithRetry(ctx, args, func(args exec.RunArgs) (exec.RunResult, error) {
+		res, err := cli.commandRunner.Run(ctx, args)
+		return res, commandError(res, err)
+	})
+}
+
+// withKubectlParams appends params to kubectl itself, ahead of any '--' separator after which the arguments
+// belong to the command executed by kubectl, ex) kubectl exec <pod> -- <command>
+func withKubectlParams(args exec.RunArgs, params ...string) exec.RunArgs {
+	index := slices.Index(args.Args, "--")
+	if index < 0 {
+		return args.AppendParams(params...)
 	}
 
-	return cli.commandRunner.Run(ctx, args)
+	args.Args = slices.Concat(args.Args[:index], params, args.Args[index:])
+	return args
+}
+
+// isLocalCommand returns true for commands that do not require access to the k8s API server
+func isLocalCommand(args exec.RunArgs, flags *KubeCliFlags) bool {
+	if flags != nil && flags.DryRun == DryRunTypeClient {
+		return true
+	}
+
+	if len(args.Args) == 0 {
+		return true
+	}
+
+	switch args.Args[0] {
+	case "config":
+		return true
+	case "version":
+		return slices.Contains(args.Args, "--client=true")
+	default:
+		return false
+	}
 }
 
 func environ(values map[string]string) []string {
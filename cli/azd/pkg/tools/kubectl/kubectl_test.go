@@ -2,15 +2,19 @@ package kubectl
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
 	"github.com/azure/azure-dev/cli/azd/test/ostest"
 	"github.com/stretchr/testify/require"
 )
@@ -68,6 +72,19 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"apply-with-prune": {
+			mockCommandPredicate: "kubectl apply -f - --prune",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"apply", "-f", "-", "--prune", "-l", "azd.azure.com/service=api"},
+			testFn: func() error {
+				_, err := cli.ApplyWithStdIn(*mockContext.Context, "input", &KubeCliFlags{
+					Prune:         true,
+					LabelSelector: "azd.azure.com/service=api",
+				})
+
+				return err
+			},
+		},
 		"apply-with-file": {
 			mockCommandPredicate: "kubectl apply -f",
 			expectedCmd:          "kubectl",
@@ -100,6 +117,52 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"stream-logs": {
+			mockCommandPredicate: "kubectl logs",
+			expectedCmd:          "kubectl",
+			expectedArgs: []string{
+				"logs", "pod/api-5d8f7b-abcde", "-c", "api", "--follow", "--since=5m0s", "--tail=20", "-n", "test-namespace",
+			},
+			testFn: func() error {
+				return cli.StreamLogs(*mockContext.Context, "pod/api-5d8f7b-abcde", &LogsOptions{
+					Container: "api",
+					Follow:    true,
+					Since:     5 * time.Minute,
+					Tail:      20,
+				}, &KubeCliFlags{Namespace: "test-namespace"}, io.Discard)
+			},
+		},
+		"exec-in-pod": {
+			mockCommandPredicate: "kubectl exec",
+			expectedCmd:          "kubectl",
+			expectedArgs: []string{
+				"exec", "-i", "-t", "api-5d8f7b-abcde", "-c", "api", "-n", "test-namespace", "--", "/bin/sh", "-c", "ls",
+			},
+			testFn: func() error {
+				return cli.ExecInPod(*mockContext.Context, "api-5d8f7b-abcde", []string{"/bin/sh", "-c", "ls"},
+					&ExecInPodOptions{Container: "api", Tty: true},
+					&KubeCliFlags{Namespace: "test-namespace"},
+				)
+			},
+		},
+		"passthrough": {
+			mockCommandPredicate: "kubectl get pods",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"get", "pods", "-A", "--watch"},
+			testFn: func() error {
+				return cli.Passthrough(*mockContext.Context, []string{"get", "pods", "-A", "--watch"})
+			},
+		},
+		"port-forward": {
+			mockCommandPredicate: "kubectl port-forward",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"port-forward", "svc/api", "8080:80", "443", "-n", "test-namespace"},
+			testFn: func() error {
+				return cli.PortForward(*mockContext.Context, "svc/api", []string{"8080:80", "443"}, &KubeCliFlags{
+					Namespace: "test-namespace",
+				}, nil)
+			},
+		},
 		"create-namespace": {
 			mockCommandPredicate: "kubectl create namespace",
 			expectedCmd:          "kubectl",
@@ -113,6 +176,36 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"create-serviceaccount": {
+			mockCommandPredicate: "kubectl create serviceaccount",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"create", "serviceaccount", "api", "-n", "test-namespace"},
+			testFn: func() error {
+				_, err := cli.CreateServiceAccount(*mockContext.Context, "api", &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
+		"patch": {
+			mockCommandPredicate: "kubectl patch",
+			expectedCmd:          "kubectl",
+			expectedArgs: []string{
+				"patch", "sa", "default", "--type", "merge", "-p", `{"imagePullSecrets":[{"name":"registry"}]}`,
+			},
+			testFn: func() error {
+				_, err := cli.Patch(
+					*mockContext.Context,
+					ResourceTypeServiceAccount,
+					"default",
+					`{"imagePullSecrets":[{"name":"registry"}]}`,
+					nil,
+				)
+
+				return err
+			},
+		},
 		"rollout-status": {
 			mockCommandPredicate: "kubectl rollout status",
 			expectedCmd:          "kubectl",
@@ -125,6 +218,94 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"rollout-undo": {
+			mockCommandPredicate: "kubectl rollout undo",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"rollout", "undo", "deployment/deployment-name", "-n", "test-namespace"},
+			testFn: func() error {
+				_, err := cli.RolloutUndo(*mockContext.Context, "deployment-name", &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
+		"wait": {
+			mockCommandPredicate: "kubectl wait",
+			expectedCmd:          "kubectl",
+			expectedArgs: []string{
+				"wait", "pods", "--for=condition=Ready", "--timeout=2m0s", "-n", "test-namespace", "-l", "app=api",
+			},
+			testFn: func() error {
+				_, err := cli.Wait(*mockContext.Context, "pods", "condition=Ready", 2*time.Minute, &KubeCliFlags{
+					Namespace:     "test-namespace",
+					LabelSelector: "app=api",
+				})
+
+				return err
+			},
+		},
+		"exec-with-selector": {
+			mockCommandPredicate: "kubectl get svc",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"get", "svc", "-o", "json", "-l", "app=api"},
+			testFn: func() error {
+				_, err := cli.Exec(*mockContext.Context, &KubeCliFlags{
+					Output:        OutputTypeJson,
+					LabelSelector: "app=api",
+				}, "get", "svc")
+
+				return err
+			},
+		},
+		"delete": {
+			mockCommandPredicate: "kubectl delete",
+			expectedCmd:          "kubectl",
+			expectedArgs: []string{
+				"delete", "deployment", "deployment-name", "--ignore-not-found", "-n", "test-namespace",
+			},
+			testFn: func() error {
+				_, err := cli.Delete(*mockContext.Context, ResourceTypeDeployment, "deployment-name", &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
+		"diff-with-stdin": {
+			mockCommandPredicate: "kubectl diff -f -",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"diff", "-f", "-", "-n", "test-namespace"},
+			testFn: func() error {
+				_, _, err := cli.DiffWithStdIn(*mockContext.Context, "input", &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
+		"diff-with-kustomize": {
+			mockCommandPredicate: "kubectl diff -k",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"diff", "-k", "./overlays/dev"},
+			testFn: func() error {
+				_, _, err := cli.DiffWithKustomize(*mockContext.Context, "./overlays/dev", nil)
+
+				return err
+			},
+		},
+		"logs": {
+			mockCommandPredicate: "kubectl logs",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"logs", "job/migrate", "--all-containers", "--tail=50", "-n", "test-namespace"},
+			testFn: func() error {
+				_, err := cli.Logs(*mockContext.Context, "job/migrate", 50, &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
 		"exec": {
 			mockCommandPredicate: "kubectl get deployment",
 			expectedCmd:          "kubectl",
@@ -160,6 +341,47 @@ func Test_Command_Args(t *testing.T) {
 	}
 }
 
+func Test_Diff(t *testing.T) {
+	tests := map[string]struct {
+		exitCode      int
+		stdout        string
+		expectedDiff  string
+		expectChanges bool
+		expectErr     bool
+	}{
+		"NoChanges":   {exitCode: 0},
+		"Changes":     {exitCode: 1, stdout: "+  replicas: 3", expectedDiff: "+  replicas: 3", expectChanges: true},
+		"DiffFailure": {exitCode: 2, stdout: "error: unable to connect", expectErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl diff")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				result := exec.NewRunResult(test.exitCode, test.stdout, "")
+				if test.exitCode != 0 {
+					return result, fmt.Errorf("exit code: %d", test.exitCode)
+				}
+
+				return result, nil
+			})
+
+			cli := NewCli(mockContext.CommandRunner)
+			diff, hasChanges, err := cli.DiffWithStdIn(*mockContext.Context, "kind: Deployment", nil)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectChanges, hasChanges)
+			require.Equal(t, test.expectedDiff, diff)
+		})
+	}
+}
+
 type kubeCliTestConfig struct {
 	mockCommandPredicate string
 	mockCommandResult    string
@@ -252,4 +474,188 @@ func Test_Apply_Template(t *testing.T) {
 		require.Contains(t, yaml, "test.azureacr.io/repo/service:latest")
 		require.Contains(t, yaml, "EXAMPLE_CLIENT_ID")
 	})
+	t.Run("EnvsubstYaml", func(t *testing.T) {
+		cli := NewCli(mockContext.CommandRunner)
+		env := map[string]string{
+			"SERVICE_API_IMAGE_NAME":       "test.azureacr.io/repo/service:latest",
+			"AZURE_AKS_IDENTITY_CLIENT_ID": "EXAMPLE_CLIENT_ID",
+		}
+		cli.SetEnv(env)
+
+		flags := &KubeCliFlags{
+			Namespace: "test",
+		}
+
+		err := cli.ApplyWithEnvsubst(
+			*mockContext.Context,
+			"../../../test/testdata/k8s/apply/envsubst",
+			flags,
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"apply", "-f", "-", "-n", "test"}, runArgs.Args)
+
+		builder := strings.Builder{}
+		_, err = io.Copy(&builder, runArgs.StdIn)
+		require.NoError(t, err)
+
+		yaml := builder.String()
+		require.Contains(t, yaml, "image: test.azureacr.io/repo/service:latest")
+		require.Contains(t, yaml, "value: EXAMPLE_CLIENT_ID")
+		require.NotContains(t, yaml, "${")
+	})
+}
+
+func Test_ReadManifests(t *testing.T) {
+	cli := NewCli(mocks.NewMockContext(context.Background()).CommandRunner)
+	cli.SetEnv(map[string]string{
+		"SERVICE_API_IMAGE_NAME":       "test.azureacr.io/repo/service:latest",
+		"AZURE_AKS_IDENTITY_CLIENT_ID": "EXAMPLE_CLIENT_ID",
+	})
+
+	t.Run("Raw", func(t *testing.T) {
+		manifests, err := cli.ReadManifests("../../../test/testdata/k8s/apply/envsubst", false)
+		require.NoError(t, err)
+		require.Len(t, manifests, 1)
+		require.Contains(t, manifests[0], "image: ${SERVICE_API_IMAGE_NAME}")
+	})
+
+	t.Run("Envsubst", func(t *testing.T) {
+		manifests, err := cli.ReadManifests("../../../test/testdata/k8s/apply/envsubst", true)
+		require.NoError(t, err)
+		require.Len(t, manifests, 1)
+		require.Contains(t, manifests[0], "image: test.azureacr.io/repo/service:latest")
+		require.NotContains(t, manifests[0], "${")
+	})
+
+	t.Run("Templates", func(t *testing.T) {
+		manifests, err := cli.ReadManifests("../../../test/testdata/k8s/apply/templates", false)
+		require.NoError(t, err)
+		require.Len(t, manifests, 1)
+		require.Contains(t, manifests[0], "test.azureacr.io/repo/service:latest")
+		require.Contains(t, manifests[0], "EXAMPLE_CLIENT_ID")
+	})
+}
+
+func Test_RemoteRunner(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	localCommands := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		localCommands = append(localCommands, args.Args[0])
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	remoteRunner := mockexec.NewMockCommandRunner()
+	remoteCommands := []string{}
+	remoteRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		remoteCommands = append(remoteCommands, args.Args[0])
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.SetRemoteRunner(remoteRunner)
+
+	_, err := cli.ConfigUseContext(*mockContext.Context, "context", nil)
+	require.NoError(t, err)
+
+	_, err = cli.CreateNamespace(*mockContext.Context, "namespace", &KubeCliFlags{DryRun: DryRunTypeClient})
+	require.NoError(t, err)
+
+	_, err = cli.ApplyWithStdIn(*mockContext.Context, "yaml", nil)
+	require.NoError(t, err)
+
+	_, err = cli.Exec(*mockContext.Context, nil, "get", "deployment")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"config", "create"}, localCommands)
+	require.Equal(t, []string{"apply", "get"}, remoteCommands)
+
+	// Commands run locally again once the remote runner is cleared
+	cli.SetRemoteRunner(nil)
+	_, err = cli.Exec(*mockContext.Context, nil, "get", "deployment")
+	require.NoError(t, err)
+	require.Equal(t, []string{"config", "create", "get"}, localCommands)
+}
+
+func Test_KubeConfigPath(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	var runArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		runArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.SetKubeConfigPath("/azure/dev/.kube/config")
+
+	_, err := cli.Exec(*mockContext.Context, &KubeCliFlags{Namespace: "test"}, "get", "deployment")
+	require.NoError(t, err)
+	require.Equal(t, []string{"get", "deployment", "-n", "test", "--kubeconfig", "/azure/dev/.kube/config"}, runArgs.Args)
+
+	// The kube config is passed to kubectl ahead of the command executed in the pod
+	_, err = cli.Exec(*mockContext.Context, nil, "exec", "api", "--", "env")
+	require.NoError(t, err)
+	require.Equal(t, []string{"exec", "api", "--kubeconfig", "/azure/dev/.kube/config", "--", "env"}, runArgs.Args)
+
+	cli.SetKubeConfigPath("")
+	_, err = cli.Exec(*mockContext.Context, nil, "get", "deployment")
+	require.NoError(t, err)
+	require.Equal(t, []string{"get", "deployment"}, runArgs.Args)
+}
+
+func Test_ServerSideApply(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	var runArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		runArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.SetServerSideApply(&ServerSideApplyOptions{})
+
+	_, err := cli.ApplyWithStdIn(*mockContext.Context, "yaml", &KubeCliFlags{Namespace: "test"})
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"apply", "-f", "-", "--server-side", "--field-manager=azd", "-n", "test"},
+		runArgs.Args,
+	)
+
+	cli.SetServerSideApply(&ServerSideApplyOptions{FieldManager: "contoso", ForceConflicts: true})
+	_, _, err = cli.DiffWithStdIn(*mockContext.Context, "yaml", nil)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"diff", "-f", "-", "--server-side", "--field-manager=contoso", "--force-conflicts"},
+		runArgs.Args,
+	)
+
+	cli.SetServerSideApply(nil)
+	_, err = cli.ApplyWithFile(*mockContext.Context, "file.yaml", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"apply", "-f", "file.yaml"}, runArgs.Args)
+
+	t.Run("Conflict", func(t *testing.T) {
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl apply")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			//nolint:lll
+			stderr := `error: Apply failed with 1 conflict: conflict with "kubectl-client-side-apply" using apps/v1: .spec.replicas`
+			return exec.NewRunResult(1, "", stderr), errors.New("exit code: 1")
+		})
+
+		cli.SetServerSideApply(&ServerSideApplyOptions{})
+		_, err := cli.ApplyWithStdIn(*mockContext.Context, "yaml", nil)
+		require.ErrorIs(t, err, ErrApplyConflict)
+		require.ErrorContains(t, err, "error: Apply failed with 1 conflict")
+	})
 }
@@ -1,6 +1,7 @@
 package kubectl
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 )
@@ -8,10 +9,21 @@ import (
 type ResourceType string
 
 const (
-	ResourceTypeDeployment ResourceType = "deployment"
-	ResourceTypeIngress    ResourceType = "ing"
-	ResourceTypeService    ResourceType = "svc"
-	KubeConfigEnvVarName   string       = "KUBECONFIG"
+	ResourceTypeConfigMap      ResourceType = "configmap"
+	ResourceTypeCronJob        ResourceType = "cronjob"
+	ResourceTypeDaemonSet      ResourceType = "daemonset"
+	ResourceTypeDeployment     ResourceType = "deployment"
+	ResourceTypeGateway        ResourceType = "gateways.gateway.networking.k8s.io"
+	ResourceTypeHttpRoute      ResourceType = "httproutes.gateway.networking.k8s.io"
+	ResourceTypeIngress        ResourceType = "ing"
+	ResourceTypeJob            ResourceType = "job"
+	ResourceTypeNode           ResourceType = "nodes"
+	ResourceTypePod            ResourceType = "pods"
+	ResourceTypeSecret         ResourceType = "secret"
+	ResourceTypeService        ResourceType = "svc"
+	ResourceTypeServiceAccount ResourceType = "sa"
+	ResourceTypeStatefulSet    ResourceType = "statefulset"
+	KubeConfigEnvVarName       string       = "KUBECONFIG"
 )
 
 type Resource struct {
@@ -32,9 +44,11 @@ type ResourceWithSpec[T any, S any] struct {
 }
 
 type ResourceMetadata struct {
-	Name        string `json:"name"      yaml:"name"`
-	Namespace   string `json:"namespace" yaml:"namespace"`
+	Name        string `json:"name"                 yaml:"name"`
+	Namespace   string `json:"namespace"            yaml:"namespace"`
+	Generation  int64  `json:"generation,omitempty" yaml:"generation,omitempty"`
 	Annotations map[string]any
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 }
 
 type Deployment ResourceWithSpec[DeploymentSpec, DeploymentStatus]
@@ -50,6 +64,266 @@ type DeploymentStatus struct {
 	UpdatedReplicas   int `json:"updatedReplicas"   yaml:"updatedReplicas"`
 }
 
+// The update strategy types of stateful sets and daemon sets
+const (
+	UpdateStrategyRollingUpdate = "RollingUpdate"
+	UpdateStrategyOnDelete      = "OnDelete"
+)
+
+type StatefulSet ResourceWithSpec[StatefulSetSpec, StatefulSetStatus]
+
+type StatefulSetSpec struct {
+	Replicas       *int                      `json:"replicas"       yaml:"replicas"`
+	UpdateStrategy StatefulSetUpdateStrategy `json:"updateStrategy" yaml:"updateStrategy"`
+}
+
+type StatefulSetUpdateStrategy struct {
+	Type          string                          `json:"type"          yaml:"type"`
+	RollingUpdate *StatefulSetRollingUpdateConfig `json:"rollingUpdate" yaml:"rollingUpdate"`
+}
+
+type StatefulSetRollingUpdateConfig struct {
+	// Only pods with an ordinal greater than or equal to the partition are updated
+	Partition *int `json:"partition" yaml:"partition"`
+}
+
+type StatefulSetStatus struct {
+	ObservedGeneration int64  `json:"observedGeneration" yaml:"observedGeneration"`
+	Replicas           int    `json:"replicas"           yaml:"replicas"`
+	ReadyReplicas      int    `json:"readyReplicas"      yaml:"readyReplicas"`
+	UpdatedReplicas    int    `json:"updatedReplicas"    yaml:"updatedReplicas"`
+	CurrentRevision    string `json:"currentRevision"    yaml:"currentRevision"`
+	UpdateRevision     string `json:"updateRevision"     yaml:"updateRevision"`
+}
+
+// IsRolledOut returns whether the latest revision of the stateful set has been rolled out and all replicas are ready
+// For partitioned rolling updates only the replicas with an ordinal greater than or equal to the partition
+// are expected to be updated. Stateful sets with the 'OnDelete' strategy are not updated until their pods are deleted
+func (s *StatefulSet) IsRolledOut() bool {
+	if s.Status.ObservedGeneration < s.Metadata.Generation {
+		return false
+	}
+
+	replicas := 1
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+
+	if s.Status.ReadyReplicas < replicas {
+		return false
+	}
+
+	if s.Spec.UpdateStrategy.Type == UpdateStrategyOnDelete {
+		return true
+	}
+
+	rollingUpdate := s.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0 {
+		return s.Status.UpdatedReplicas >= replicas-*rollingUpdate.Partition
+	}
+
+	return s.Status.UpdateRevision == s.Status.CurrentRevision
+}
+
+type DaemonSet ResourceWithSpec[DaemonSetSpec, DaemonSetStatus]
+
+type DaemonSetSpec struct {
+	UpdateStrategy DaemonSetUpdateStrategy `json:"updateStrategy" yaml:"updateStrategy"`
+}
+
+type DaemonSetUpdateStrategy struct {
+	Type string `json:"type" yaml:"type"`
+}
+
+type DaemonSetStatus struct {
+	ObservedGeneration     int64 `json:"observedGeneration"     yaml:"observedGeneration"`
+	DesiredNumberScheduled int   `json:"desiredNumberScheduled" yaml:"desiredNumberScheduled"`
+	UpdatedNumberScheduled int   `json:"updatedNumberScheduled" yaml:"updatedNumberScheduled"`
+	NumberAvailable        int   `json:"numberAvailable"        yaml:"numberAvailable"`
+}
+
+// IsRolledOut returns whether the latest revision of the daemon set has been scheduled and is available on all nodes
+// Daemon sets with the 'OnDelete' strategy are not updated until their pods are deleted
+func (d *DaemonSet) IsRolledOut() bool {
+	if d.Status.ObservedGeneration < d.Metadata.Generation {
+		return false
+	}
+
+	if d.Spec.UpdateStrategy.Type != UpdateStrategyOnDelete &&
+		d.Status.UpdatedNumberScheduled < d.Status.DesiredNumberScheduled {
+		return false
+	}
+
+	return d.Status.NumberAvailable >= d.Status.DesiredNumberScheduled
+}
+
+type Job ResourceWithSpec[JobSpec, JobStatus]
+
+type JobSpec struct {
+	Completions  *int `json:"completions"  yaml:"completions"`
+	BackoffLimit *int `json:"backoffLimit" yaml:"backoffLimit"`
+}
+
+type JobStatus struct {
+	Active     int            `json:"active"     yaml:"active"`
+	Succeeded  int            `json:"succeeded"  yaml:"succeeded"`
+	Failed     int            `json:"failed"     yaml:"failed"`
+	Conditions []JobCondition `json:"conditions" yaml:"conditions"`
+}
+
+type JobCondition struct {
+	Type    string `json:"type"    yaml:"type"`
+	Status  string `json:"status"  yaml:"status"`
+	Reason  string `json:"reason"  yaml:"reason"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Condition returns the job condition of the specified type when the condition is true, otherwise nil
+// Completed jobs have a 'Complete' condition and failed jobs a 'Failed' condition
+func (j *Job) Condition(conditionType string) *JobCondition {
+	for i, condition := range j.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == "True" {
+			return &j.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+type CronJob ResourceWithSpec[CronJobSpec, CronJobStatus]
+
+type CronJobSpec struct {
+	Schedule string `json:"schedule" yaml:"schedule"`
+	Suspend  bool   `json:"suspend"  yaml:"suspend"`
+}
+
+type CronJobStatus struct {
+	LastScheduleTime   string `json:"lastScheduleTime"   yaml:"lastScheduleTime"`
+	LastSuccessfulTime string `json:"lastSuccessfulTime" yaml:"lastSuccessfulTime"`
+}
+
+// CustomResource is a resource of any kind that reports its status with conditions, ex) a cert-manager Certificate
+type CustomResource ResourceWithSpec[map[string]any, CustomResourceStatus]
+
+type CustomResourceStatus struct {
+	// The generation of the resource most recently observed by its controller
+	ObservedGeneration int64       `json:"observedGeneration" yaml:"observedGeneration"`
+	Conditions         []Condition `json:"conditions"         yaml:"conditions"`
+}
+
+// Condition returns the condition of the specified type, otherwise nil
+func (r *CustomResource) Condition(conditionType string) *Condition {
+	for i, condition := range r.Status.Conditions {
+		if condition.Type == conditionType {
+			return &r.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+type Pod ResourceWithSpec[PodSpec, PodStatus]
+
+// StatusReason returns the reason the pod is not running or ready, ex) ContainerCreating, CrashLoopBackOff or
+// Unschedulable. Defaults to the pod phase when no reason is reported
+func (p *Pod) StatusReason() string {
+	for _, container := range p.Status.ContainerStatuses {
+		if _, detail := container.State.describe(); detail != nil && detail.Reason != "" {
+			return detail.Reason
+		}
+	}
+
+	for _, condition := range p.Status.Conditions {
+		if condition.Status != "True" && condition.Reason != "" {
+			return condition.Reason
+		}
+	}
+
+	return p.Status.Phase
+}
+
+// ContainerStatus returns the status of the container or native sidecar container with the specified name
+func (p *Pod) ContainerStatus(name string) *ContainerStatus {
+	for _, statuses := range [][]ContainerStatus{p.Status.ContainerStatuses, p.Status.InitContainerStatuses} {
+		for index := range statuses {
+			if statuses[index].Name == name {
+				return &statuses[index]
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsReady returns whether the pod is running and all of its containers are ready
+func (p *Pod) IsReady() bool {
+	if p.Status.Phase != "Running" {
+		return false
+	}
+
+	for _, container := range p.Status.ContainerStatuses {
+		if !container.Ready {
+			return false
+		}
+	}
+
+	return true
+}
+
+type PodSpec struct {
+	NodeName string `json:"nodeName" yaml:"nodeName"`
+}
+
+type PodStatus struct {
+	Phase             string            `json:"phase"             yaml:"phase"`
+	Conditions        []PodCondition    `json:"conditions"        yaml:"conditions"`
+	ContainerStatuses []ContainerStatus `json:"containerStatuses" yaml:"containerStatuses"`
+	// The statuses of the init containers, including native sidecar containers, ex) istio-proxy
+	InitContainerStatuses []ContainerStatus `json:"initContainerStatuses,omitempty" yaml:"initContainerStatuses,omitempty"`
+}
+
+type PodCondition struct {
+	Type    string `json:"type"    yaml:"type"`
+	Status  string `json:"status"  yaml:"status"`
+	Reason  string `json:"reason"  yaml:"reason"`
+	Message string `json:"message" yaml:"message"`
+}
+
+type ContainerStatus struct {
+	Name         string `json:"name"         yaml:"name"`
+	Ready        bool   `json:"ready"        yaml:"ready"`
+	RestartCount int    `json:"restartCount" yaml:"restartCount"`
+	// The image the container is running, ex) contoso.azurecr.io/todo/api:azd-deploy-1700000000
+	Image string `json:"image" yaml:"image"`
+	// The resolved image reference, ex) contoso.azurecr.io/todo/api@sha256:4f2d...
+	ImageID string         `json:"imageID" yaml:"imageID"`
+	State   ContainerState `json:"state"   yaml:"state"`
+}
+
+type ContainerState struct {
+	Waiting    *ContainerStateDetail `json:"waiting"    yaml:"waiting"`
+	Terminated *ContainerStateDetail `json:"terminated" yaml:"terminated"`
+}
+
+type ContainerStateDetail struct {
+	Reason   string `json:"reason"   yaml:"reason"`
+	Message  string `json:"message"  yaml:"message"`
+	ExitCode int    `json:"exitCode" yaml:"exitCode"`
+}
+
+// describe returns the name and details of the container state when the container is not running
+func (s ContainerState) describe() (string, *ContainerStateDetail) {
+	if s.Waiting != nil {
+		return "waiting", s.Waiting
+	}
+
+	if s.Terminated != nil {
+		return "terminated", s.Terminated
+	}
+
+	return "running", nil
+}
+
 type Ingress ResourceWithSpec[IngressSpec, IngressStatus]
 
 type IngressSpec struct {
@@ -87,6 +361,95 @@ type LoadBalancer struct {
 
 type LoadBalancerIngress struct {
 	Ip string `json:"ip" yaml:"ip"`
+	// The hostname of the load balancer, ex) assigned by a cloud provider or external-dns
+	Hostname string `json:"hostname" yaml:"hostname"`
+	// The ports exposed by the load balancer when reported by the load balancer implementation
+	Ports []PortStatus `json:"ports" yaml:"ports"`
+}
+
+// Address returns the hostname of the load balancer when assigned, otherwise the IP address
+func (i LoadBalancerIngress) Address() string {
+	if i.Hostname != "" {
+		return i.Hostname
+	}
+
+	return i.Ip
+}
+
+type PortStatus struct {
+	Port     int    `json:"port"     yaml:"port"`
+	Protocol string `json:"protocol" yaml:"protocol"`
+}
+
+// Condition is the status condition of Gateway API and custom resources
+type Condition struct {
+	Type    string `json:"type"    yaml:"type"`
+	Status  string `json:"status"  yaml:"status"`
+	Reason  string `json:"reason"  yaml:"reason"`
+	Message string `json:"message" yaml:"message"`
+}
+
+type HttpRoute ResourceWithSpec[HttpRouteSpec, HttpRouteStatus]
+
+type HttpRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs" yaml:"parentRefs"`
+	Hostnames  []string          `json:"hostnames"  yaml:"hostnames"`
+}
+
+// ParentReference references the Gateway (or other parent resource) a route is attached to
+type ParentReference struct {
+	Kind        string `json:"kind"        yaml:"kind"`
+	Name        string `json:"name"        yaml:"name"`
+	Namespace   string `json:"namespace"   yaml:"namespace"`
+	SectionName string `json:"sectionName" yaml:"sectionName"`
+	Port        *int   `json:"port"        yaml:"port"`
+}
+
+type HttpRouteStatus struct {
+	Parents []RouteParentStatus `json:"parents" yaml:"parents"`
+}
+
+type RouteParentStatus struct {
+	ParentRef  ParentReference `json:"parentRef"  yaml:"parentRef"`
+	Conditions []Condition     `json:"conditions" yaml:"conditions"`
+}
+
+// IsAccepted returns whether the route has been accepted by at least one of its parents
+func (r *HttpRoute) IsAccepted() bool {
+	for _, parent := range r.Status.Parents {
+		for _, condition := range parent.Conditions {
+			if condition.Type == "Accepted" && condition.Status == "True" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type Gateway ResourceWithSpec[GatewaySpec, GatewayStatus]
+
+type GatewaySpec struct {
+	GatewayClassName string            `json:"gatewayClassName" yaml:"gatewayClassName"`
+	Listeners        []GatewayListener `json:"listeners"        yaml:"listeners"`
+}
+
+type GatewayListener struct {
+	Name     string  `json:"name"     yaml:"name"`
+	Hostname *string `json:"hostname" yaml:"hostname"`
+	Port     int     `json:"port"     yaml:"port"`
+	Protocol string  `json:"protocol" yaml:"protocol"`
+}
+
+type GatewayStatus struct {
+	Addresses  []GatewayAddress `json:"addresses"  yaml:"addresses"`
+	Conditions []Condition      `json:"conditions" yaml:"conditions"`
+}
+
+type GatewayAddress struct {
+	// The address type, ex) IPAddress or Hostname
+	Type  string `json:"type"  yaml:"type"`
+	Value string `json:"value" yaml:"value"`
 }
 
 type Service ResourceWithSpec[ServiceSpec, ServiceStatus]
@@ -116,6 +479,8 @@ type Port struct {
 	// The target port can be a valid port number or well known service name like 'redis'
 	TargetPort any    `json:"targetPort" yaml:"targetPort"`
 	Protocol   string `json:"protocol"   yaml:"protocol"`
+	// The port exposed on each node for NodePort and LoadBalancer services
+	NodePort int `json:"nodePort,omitempty" yaml:"nodePort,omitempty"`
 }
 
 func (p *Port) UnmarshalJSON(data []byte) error {
@@ -123,6 +488,7 @@ func (p *Port) UnmarshalJSON(data []byte) error {
 		Port       int    `json:"port" yaml:"port"`
 		TargetPort any    `json:"targetPort" yaml:"targetPort"`
 		Protocol   string `json:"protocol" yaml:"protocol"`
+		NodePort   int    `json:"nodePort" yaml:"nodePort"`
 	}
 
 	if err := json.Unmarshal(data, &aux); err != nil {
@@ -131,6 +497,7 @@ func (p *Port) UnmarshalJSON(data []byte) error {
 
 	p.Port = aux.Port
 	p.Protocol = aux.Protocol
+	p.NodePort = aux.NodePort
 
 	switch v := aux.TargetPort.(type) {
 	case string, int, float64:
@@ -141,6 +508,192 @@ func (p *Port) UnmarshalJSON(data []byte) error {
 	}
 }
 
+type NodeAddressType string
+
+const (
+	NodeAddressTypeExternalIp NodeAddressType = "ExternalIP"
+	NodeAddressTypeInternalIp NodeAddressType = "InternalIP"
+)
+
+type Node struct {
+	Resource
+	Status NodeStatus `json:"status" yaml:"status"`
+}
+
+type NodeStatus struct {
+	Addresses []NodeAddress `json:"addresses" yaml:"addresses"`
+}
+
+type NodeAddress struct {
+	Type    NodeAddressType `json:"type"    yaml:"type"`
+	Address string          `json:"address" yaml:"address"`
+}
+
+// Address returns the external IP address of the node, falling back to the internal IP address
+// for nodes without a public IP address, ex) AKS node pools without node public IPs
+func (n *Node) Address() string {
+	internalIp := ""
+	for _, address := range n.Status.Addresses {
+		switch address.Type {
+		case NodeAddressTypeExternalIp:
+			return address.Address
+		case NodeAddressTypeInternalIp:
+			if internalIp == "" {
+				internalIp = address.Address
+			}
+		}
+	}
+
+	return internalIp
+}
+
+type ServiceAccount struct {
+	Resource
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty" yaml:"imagePullSecrets,omitempty"`
+}
+
+// HasImagePullSecret returns whether the service account references the image pull secret with the specified name
+func (sa *ServiceAccount) HasImagePullSecret(name string) bool {
+	for _, secret := range sa.ImagePullSecrets {
+		if secret.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+type LocalObjectReference struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+const (
+	SecretTypeDockerConfigJson string = "kubernetes.io/dockerconfigjson"
+	SecretTypeOpaque           string = "Opaque"
+)
+
+type Secret struct {
+	Resource `yaml:",inline"`
+	Type     string `json:"type" yaml:"type"`
+	// The base64 encoded secret values
+	Data map[string]string `json:"data" yaml:"data"`
+}
+
+type dockerConfigJson struct {
+	Auths map[string]dockerConfigAuth `json:"auths"`
+}
+
+type dockerConfigAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// NewDockerRegistrySecret creates a docker-registry secret used by pods to pull images from the specified registry
+// This is equivalent to 'kubectl create secret docker-registry'
+func NewDockerRegistrySecret(
+	name string,
+	namespace string,
+	server string,
+	username string,
+	password string,
+) (*Secret, error) {
+	dockerConfig := dockerConfigJson{
+		Auths: map[string]dockerConfigAuth{
+			server: {
+				Username: username,
+				Password: password,
+				Auth:     base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", username, password))),
+			},
+		},
+	}
+
+	dockerConfigBytes, err := json.Marshal(dockerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshalling docker config, %w", err)
+	}
+
+	return &Secret{
+		Resource: Resource{
+			ApiVersion: "v1",
+			Kind:       "Secret",
+			Metadata: ResourceMetadata{
+				Name:      name,
+				Namespace: namespace,
+			},
+		},
+		Type: SecretTypeDockerConfigJson,
+		Data: map[string]string{
+			".dockerconfigjson": base64.StdEncoding.EncodeToString(dockerConfigBytes),
+		},
+	}, nil
+}
+
+// NewOpaqueSecret creates a generic secret with the specified values
+func NewOpaqueSecret(name string, namespace string, values map[string]string) *Secret {
+	data := map[string]string{}
+	for key, value := range values {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	return &Secret{
+		Resource: Resource{
+			ApiVersion: "v1",
+			Kind:       "Secret",
+			Metadata: ResourceMetadata{
+				Name:      name,
+				Namespace: namespace,
+			},
+		},
+		Type: SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+type ConfigMap struct {
+	Resource `yaml:",inline"`
+	Data     map[string]string `json:"data" yaml:"data"`
+}
+
+// NewConfigMap creates a config map with the specified values
+func NewConfigMap(name string, namespace string, values map[string]string) *ConfigMap {
+	return &ConfigMap{
+		Resource: Resource{
+			ApiVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata: ResourceMetadata{
+				Name:      name,
+				Namespace: namespace,
+			},
+		},
+		Data: values,
+	}
+}
+
+// The Secrets Store CSI driver SecretProviderClass used to mount external secrets, ex) Azure Key Vault
+type SecretProviderClass struct {
+	Resource `yaml:",inline"`
+	Spec     SecretProviderClassSpec `json:"spec" yaml:"spec"`
+}
+
+type SecretProviderClassSpec struct {
+	Provider      string            `json:"provider"                yaml:"provider"`
+	Parameters    map[string]string `json:"parameters"              yaml:"parameters"`
+	SecretObjects []SecretObject    `json:"secretObjects,omitempty" yaml:"secretObjects,omitempty"`
+}
+
+// The k8s Secret synced from the mounted secrets of a SecretProviderClass
+type SecretObject struct {
+	SecretName string             `json:"secretName" yaml:"secretName"`
+	Type       string             `json:"type"       yaml:"type"`
+	Data       []SecretObjectData `json:"data"       yaml:"data"`
+}
+
+type SecretObjectData struct {
+	ObjectName string `json:"objectName" yaml:"objectName"`
+	Key        string `json:"key"        yaml:"key"`
+}
+
 type KubeConfig struct {
 	ApiVersion     string          `yaml:"apiVersion"`
 	Clusters       []*KubeCluster  `yaml:"clusters"`
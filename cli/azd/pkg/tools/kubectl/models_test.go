@@ -47,6 +47,46 @@ func Test_Port_TargetPort_Unmarshalling(t *testing.T) {
 	}
 }
 
+func Test_Port_NodePort_Unmarshalling(t *testing.T) {
+	var port Port
+	err := json.Unmarshal([]byte("{ \"port\": 80, \"targetPort\": 8080, \"nodePort\": 30080 }"), &port)
+	require.NoError(t, err)
+	require.Equal(t, 30080, port.NodePort)
+}
+
+func Test_Node_Address(t *testing.T) {
+	tests := map[string]struct {
+		addresses []NodeAddress
+		expected  string
+	}{
+		"ExternalIP": {
+			addresses: []NodeAddress{
+				{Type: NodeAddressTypeInternalIp, Address: "10.224.0.4"},
+				{Type: "Hostname", Address: "aks-nodepool1-vmss000000"},
+				{Type: NodeAddressTypeExternalIp, Address: "20.10.10.10"},
+			},
+			expected: "20.10.10.10",
+		},
+		"InternalIP": {
+			addresses: []NodeAddress{
+				{Type: "Hostname", Address: "aks-nodepool1-vmss000000"},
+				{Type: NodeAddressTypeInternalIp, Address: "10.224.0.4"},
+			},
+			expected: "10.224.0.4",
+		},
+		"NoAddress": {
+			expected: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			node := Node{Status: NodeStatus{Addresses: test.addresses}}
+			require.Equal(t, test.expected, node.Address())
+		})
+	}
+}
+
 func Test_Ingress_UnMarshalling(t *testing.T) {
 	t.Run("json", func(t *testing.T) {
 		var ingressResources List[Ingress]
@@ -72,3 +112,131 @@ func Test_Ingress_UnMarshalling(t *testing.T) {
 		require.Equal(t, "myapp.centralus.cloudapp.azure.com", ingressResources.Items[0].Spec.Tls[0].Hosts[0])
 	})
 }
+
+func Test_StatefulSet_IsRolledOut(t *testing.T) {
+	replicas := 3
+	partition := 2
+
+	tests := map[string]struct {
+		strategy StatefulSetUpdateStrategy
+		status   StatefulSetStatus
+		expected bool
+	}{
+		"RolledOut": {
+			strategy: StatefulSetUpdateStrategy{Type: UpdateStrategyRollingUpdate},
+			status: StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "db-2", UpdateRevision: "db-2",
+			},
+			expected: true,
+		},
+		"UpdatingRevision": {
+			strategy: StatefulSetUpdateStrategy{Type: UpdateStrategyRollingUpdate},
+			status: StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "db-1", UpdateRevision: "db-2",
+			},
+			expected: false,
+		},
+		"NotObserved": {
+			strategy: StatefulSetUpdateStrategy{Type: UpdateStrategyRollingUpdate},
+			status: StatefulSetStatus{
+				ObservedGeneration: 1, ReadyReplicas: 3, CurrentRevision: "db-1", UpdateRevision: "db-1",
+			},
+			expected: false,
+		},
+		"NotReady": {
+			strategy: StatefulSetUpdateStrategy{Type: UpdateStrategyRollingUpdate},
+			status: StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 2, CurrentRevision: "db-2", UpdateRevision: "db-2",
+			},
+			expected: false,
+		},
+		"PartitionUpdated": {
+			strategy: StatefulSetUpdateStrategy{
+				Type:          UpdateStrategyRollingUpdate,
+				RollingUpdate: &StatefulSetRollingUpdateConfig{Partition: &partition},
+			},
+			status: StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "db-1", UpdateRevision: "db-2",
+			},
+			expected: true,
+		},
+		"PartitionUpdating": {
+			strategy: StatefulSetUpdateStrategy{
+				Type:          UpdateStrategyRollingUpdate,
+				RollingUpdate: &StatefulSetRollingUpdateConfig{Partition: &partition},
+			},
+			status: StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 0, CurrentRevision: "db-1", UpdateRevision: "db-2",
+			},
+			expected: false,
+		},
+		"OnDelete": {
+			strategy: StatefulSetUpdateStrategy{Type: UpdateStrategyOnDelete},
+			status: StatefulSetStatus{
+				ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "db-1", UpdateRevision: "db-2",
+			},
+			expected: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			statefulSet := &StatefulSet{
+				Resource: Resource{Metadata: ResourceMetadata{Name: "db", Generation: 2}},
+				Spec:     StatefulSetSpec{Replicas: &replicas, UpdateStrategy: test.strategy},
+				Status:   test.status,
+			}
+
+			require.Equal(t, test.expected, statefulSet.IsRolledOut())
+		})
+	}
+}
+
+func Test_DaemonSet_IsRolledOut(t *testing.T) {
+	tests := map[string]struct {
+		strategy string
+		status   DaemonSetStatus
+		expected bool
+	}{
+		"RolledOut": {
+			strategy: UpdateStrategyRollingUpdate,
+			status: DaemonSetStatus{
+				ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3,
+			},
+			expected: true,
+		},
+		"Updating": {
+			strategy: UpdateStrategyRollingUpdate,
+			status: DaemonSetStatus{
+				ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 1, NumberAvailable: 3,
+			},
+			expected: false,
+		},
+		"NotAvailable": {
+			strategy: UpdateStrategyRollingUpdate,
+			status: DaemonSetStatus{
+				ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2,
+			},
+			expected: false,
+		},
+		"OnDelete": {
+			strategy: UpdateStrategyOnDelete,
+			status: DaemonSetStatus{
+				ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 0, NumberAvailable: 3,
+			},
+			expected: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			daemonSet := &DaemonSet{
+				Resource: Resource{Metadata: ResourceMetadata{Name: "agent", Generation: 2}},
+				Spec:     DaemonSetSpec{UpdateStrategy: DaemonSetUpdateStrategy{Type: test.strategy}},
+				Status:   test.status,
+			}
+
+			require.Equal(t, test.expected, daemonSet.IsRolledOut())
+		})
+	}
+}
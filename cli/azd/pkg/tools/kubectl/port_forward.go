@@ -0,0 +1,50 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// CanPortForward returns whether local ports can be forwarded to the cluster
+// Commands executed through a remote runner are not executed on the local machine
+func (cli *Cli) CanPortForward() bool {
+	return cli.remoteRunner == nil
+}
+
+// PortForward forwards the local ports to the specified resource, ex) svc/api, equivalent to 'kubectl port-forward'
+// Ports are in the form [LOCAL_PORT:]REMOTE_PORT. The kubectl output, ex) 'Forwarding from 127.0.0.1:8080 -> 80',
+// is written to the writer once the port forward is established.
+// Blocks until the context is cancelled, which returns nil, or until the connection to the pod is lost.
+func (cli *Cli) PortForward(
+	ctx context.Context,
+	resource string,
+	ports []string,
+	flags *KubeCliFlags,
+	writer io.Writer,
+) error {
+	if !cli.CanPortForward() {
+		return fmt.Errorf("port forwarding to '%s' is not supported when using a remote runner", resource)
+	}
+
+	runArgs := exec.
+		NewRunArgs("kubectl", "port-forward", resource).
+		AppendParams(ports...)
+
+	if writer != nil {
+		runArgs = runArgs.WithStdOut(writer)
+	}
+
+	_, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed port forwarding to '%s', %w", resource, err)
+	}
+
+	return nil
+}
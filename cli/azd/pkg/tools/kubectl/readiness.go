@@ -0,0 +1,143 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// The conditions that must be True for a resource to be ready when the readiness rule does not specify any
+	DefaultReadyConditions = []string{"Ready"}
+	// The conditions that fail the wait as soon as they are True when the readiness rule does not specify any
+	DefaultFailureConditions = []string{"Stalled"}
+)
+
+// ReadinessRule evaluates the readiness of a resource from its status conditions, similar to kstatus
+// A resource is ready once its controller observed the latest generation, it is not reconciling
+// and all ready conditions are True. The wait fails as soon as any of the failure conditions is True.
+type ReadinessRule struct {
+	// The conditions that must be True for the resource to be ready. Defaults to 'Ready'
+	ReadyConditions []string
+	// The conditions that fail the wait when True. Defaults to 'Stalled'
+	FailureConditions []string
+}
+
+// ResourceFailedError is returned when a failure condition of a resource is True
+type ResourceFailedError struct {
+	ResourceType ResourceType
+	ResourceName string
+	Condition    Condition
+}
+
+func (e *ResourceFailedError) Error() string {
+	message := fmt.Sprintf("%s '%s' failed with condition '%s'", e.ResourceType, e.ResourceName, e.Condition.Type)
+	if e.Condition.Reason != "" {
+		message += fmt.Sprintf(", %s", e.Condition.Reason)
+	}
+
+	if e.Condition.Message != "" {
+		message += fmt.Sprintf(": %s", e.Condition.Message)
+	}
+
+	return message
+}
+
+// Evaluate returns nil when the resource is ready
+// Errors wrapping ErrResourceNotReady are returned while the resource is still being reconciled.
+func (r *ReadinessRule) Evaluate(resourceType ResourceType, resource *CustomResource) error {
+	name := resource.Metadata.Name
+
+	failureConditions := DefaultFailureConditions
+	if r != nil && len(r.FailureConditions) > 0 {
+		failureConditions = r.FailureConditions
+	}
+
+	for _, conditionType := range failureConditions {
+		if condition := resource.Condition(conditionType); condition != nil && condition.Status == "True" {
+			return &ResourceFailedError{ResourceType: resourceType, ResourceName: name, Condition: *condition}
+		}
+	}
+
+	// Resources without an observed generation do not report it, in which case the conditions are trusted
+	observed := resource.Status.ObservedGeneration
+	if observed > 0 && observed < resource.Metadata.Generation {
+		return fmt.Errorf(
+			"%s '%s' generation %d has not been observed yet, %w",
+			resourceType, name, resource.Metadata.Generation, ErrResourceNotReady,
+		)
+	}
+
+	if condition := resource.Condition("Reconciling"); condition != nil && condition.Status == "True" {
+		return fmt.Errorf("%s '%s' is reconciling, %w", resourceType, name, ErrResourceNotReady)
+	}
+
+	readyConditions := DefaultReadyConditions
+	if r != nil && len(r.ReadyConditions) > 0 {
+		readyConditions = r.ReadyConditions
+	}
+
+	for _, conditionType := range readyConditions {
+		condition := resource.Condition(conditionType)
+		if condition == nil {
+			return fmt.Errorf(
+				"%s '%s' does not report condition '%s' yet, %w", resourceType, name, conditionType, ErrResourceNotReady,
+			)
+		}
+
+		if condition.Status != "True" {
+			status := condition.Status
+			details := []string{}
+			for _, value := range []string{condition.Reason, condition.Message} {
+				if value != "" {
+					details = append(details, value)
+				}
+			}
+
+			if len(details) > 0 {
+				status = fmt.Sprintf("%s (%s)", status, strings.Join(details, ": "))
+			}
+
+			return fmt.Errorf(
+				"%s '%s' condition '%s' is %s, %w", resourceType, name, conditionType, status, ErrResourceNotReady,
+			)
+		}
+	}
+
+	return nil
+}
+
+// WaitForReadiness waits until the resource with the specified name is ready according to the readiness rule
+// A ResourceFailedError is returned as soon as a failure condition is True instead of waiting for the timeout
+func WaitForReadiness(
+	ctx context.Context,
+	cli *Cli,
+	resourceType ResourceType,
+	name string,
+	rule *ReadinessRule,
+	options *WaitOptions,
+) (*CustomResource, error) {
+	resource, err := waitForResource(ctx, cli, resourceType,
+		func(resource *CustomResource) bool {
+			return resource.Metadata.Name == name
+		},
+		func(resource *CustomResource) error {
+			return rule.Evaluate(resourceType, resource)
+		},
+		options,
+	)
+
+	// The last evaluated condition explains why the resource is not ready
+	var timeoutErr *ResourceTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return nil, fmt.Errorf("%w, %w", &ResourceTimeoutError{
+			ResourceType: resourceType,
+			ResourceName: name,
+			Timeout:      timeoutErr.Timeout,
+			Err:          timeoutErr.Err,
+		}, timeoutErr.Err)
+	}
+
+	return resource, err
+}
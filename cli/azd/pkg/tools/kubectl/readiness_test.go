@@ -0,0 +1,79 @@
+package kubectl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadinessRule_Evaluate(t *testing.T) {
+	tests := map[string]struct {
+		rule          *ReadinessRule
+		generation    int64
+		status        CustomResourceStatus
+		expectedError string
+		notReady      bool
+	}{
+		"Ready": {
+			status: CustomResourceStatus{Conditions: []Condition{{Type: "Ready", Status: "True"}}},
+		},
+		"MissingCondition": {
+			expectedError: "does not report condition 'Ready' yet",
+			notReady:      true,
+		},
+		"NotObserved": {
+			generation: 3,
+			status: CustomResourceStatus{
+				ObservedGeneration: 2,
+				Conditions:         []Condition{{Type: "Ready", Status: "True"}},
+			},
+			expectedError: "generation 3 has not been observed yet",
+			notReady:      true,
+		},
+		"Reconciling": {
+			status: CustomResourceStatus{Conditions: []Condition{
+				{Type: "Ready", Status: "True"},
+				{Type: "Reconciling", Status: "True"},
+			}},
+			expectedError: "is reconciling",
+			notReady:      true,
+		},
+		"Stalled": {
+			status: CustomResourceStatus{Conditions: []Condition{
+				{Type: "Stalled", Status: "True", Reason: "Failed", Message: "invalid trigger"},
+			}},
+			expectedError: "scaledobject 'api' failed with condition 'Stalled', Failed: invalid trigger",
+		},
+		"CustomConditions": {
+			rule: &ReadinessRule{
+				ReadyConditions:   []string{"Ready", "Active"},
+				FailureConditions: []string{"Fallback"},
+			},
+			status: CustomResourceStatus{Conditions: []Condition{
+				{Type: "Ready", Status: "True"},
+				{Type: "Active", Status: "False", Reason: "ScalerNotActive"},
+			}},
+			expectedError: "scaledobject 'api' condition 'Active' is False (ScalerNotActive)",
+			notReady:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resource := &CustomResource{
+				Resource: Resource{Metadata: ResourceMetadata{Name: "api", Generation: test.generation}},
+				Status:   test.status,
+			}
+
+			err := test.rule.Evaluate(ResourceType("scaledobject"), resource)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, test.expectedError)
+			require.Equal(t, test.notReady, errors.Is(err, ErrResourceNotReady))
+		})
+	}
+}
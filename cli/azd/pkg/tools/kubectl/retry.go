@@ -0,0 +1,110 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/sethvargo/go-retry"
+)
+
+const (
+	// The default maximum number of times a failed kubectl command is retried
+	DefaultMaxRetries = 3
+	// The default delay before the first retry, doubled for every following retry
+	DefaultRetryInitialDelay = 2 * time.Second
+	// The default maximum delay between retries
+	DefaultRetryMaxDelay = 30 * time.Second
+)
+
+// The kubectl commands that are retried on transient failures
+// The commands are idempotent, so repeating one that partially succeeded has the same result
+var retryableCommands = []string{"apply", "get", "rollout"}
+
+// RetryOptions configures how kubectl commands that fail with transient API server errors are retried
+type RetryOptions struct {
+	// The maximum number of retries. Defaults to 3
+	MaxRetries int
+	// The delay before the first retry, doubled for every following retry. Defaults to 2 seconds
+	InitialDelay time.Duration
+	// The maximum delay between retries. Defaults to 30 seconds
+	MaxDelay time.Duration
+}
+
+// Sets the retry options of the commands that fail with transient API server errors, ex) throttled requests
+// A nil value disables retries
+func (cli *Cli) SetRetry(options *RetryOptions) {
+	cli.retry = options
+}
+
+// backoff returns the exponential backoff of the retries with the defaults applied
+func (o *RetryOptions) backoff() retry.Backoff {
+	maxRetries := o.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultMaxRetries
+	}
+
+	initialDelay := o.InitialDelay
+	if initialDelay <= 0 {
+		initialDelay = DefaultRetryInitialDelay
+	}
+
+	maxDelay := o.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	backoff := retry.NewExponential(initialDelay)
+	backoff = retry.WithCappedDuration(maxDelay, backoff)
+	backoff = retry.WithJitterPercent(10, backoff)
+
+	return retry.WithMaxRetries(uint64(maxRetries), backoff)
+}
+
+// runWithRetry runs the command, retrying it with exponential backoff while it fails with transient errors
+func (cli *Cli) runWithRetry(
+	ctx context.Context,
+	args exec.RunArgs,
+	run func(args exec.RunArgs) (exec.RunResult, error),
+) (exec.RunResult, error) {
+	if cli.retry == nil || args.Interactive || len(args.Args) == 0 || !slices.Contains(retryableCommands, args.Args[0]) {
+		return run(args)
+	}
+
+	// The standard input is consumed by each attempt, ex) the manifests applied with 'kubectl apply -f -'
+	var stdIn []byte
+	if args.StdIn != nil {
+		var err error
+		if stdIn, err = io.ReadAll(args.StdIn); err != nil {
+			return exec.RunResult{}, fmt.Errorf("failed reading standard input, %w", err)
+		}
+	}
+
+	var res exec.RunResult
+	attempt := 0
+	err := retry.Do(ctx, cli.retry.backoff(), func(ctx context.Context) error {
+		attempt++
+		if stdIn != nil {
+			args = args.WithStdIn(bytes.NewReader(stdIn))
+		}
+
+		var err error
+		res, err = run(args)
+		if errors.Is(err, ErrServerBusy) {
+			log.Printf("kubectl %s failed with a transient error (attempt %d), retrying: %v",
+				strings.Join(args.Args, " "), attempt, err)
+			return retry.RetryableError(err)
+		}
+
+		return err
+	})
+
+	return res, err
+}
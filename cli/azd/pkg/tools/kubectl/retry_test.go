@@ -0,0 +1,109 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Retry(t *testing.T) {
+	throttledStderr := `Error from server (TooManyRequests): the server has received too many requests and has asked ` +
+		`us to try again later`
+
+	tests := map[string]struct {
+		retry            *RetryOptions
+		failures         int
+		stderr           string
+		expectedAttempts int
+		expectedErr      error
+	}{
+		"Throttled": {
+			retry:            &RetryOptions{InitialDelay: time.Millisecond},
+			failures:         2,
+			stderr:           throttledStderr,
+			expectedAttempts: 3,
+		},
+		"Exhausted": {
+			retry:            &RetryOptions{MaxRetries: 2, InitialDelay: time.Millisecond},
+			failures:         5,
+			stderr:           throttledStderr,
+			expectedAttempts: 3,
+			expectedErr:      ErrServerBusy,
+		},
+		"NotTransient": {
+			retry:            &RetryOptions{InitialDelay: time.Millisecond},
+			failures:         1,
+			stderr:           `Error from server (Forbidden): deployments.apps "api" is forbidden`,
+			expectedAttempts: 1,
+			expectedErr:      ErrForbidden,
+		},
+		"Disabled": {
+			failures:         1,
+			stderr:           throttledStderr,
+			expectedAttempts: 1,
+			expectedErr:      ErrServerBusy,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			stdIns := []string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				stdIn, err := io.ReadAll(args.StdIn)
+				require.NoError(t, err)
+				stdIns = append(stdIns, string(stdIn))
+
+				if len(stdIns) <= test.failures {
+					return exec.NewRunResult(1, "", test.stderr), errors.New("exit code: 1")
+				}
+
+				return exec.NewRunResult(0, "deployment.apps/api configured", ""), nil
+			})
+
+			cli := NewCli(mockContext.CommandRunner)
+			cli.SetRetry(test.retry)
+
+			_, err := cli.ApplyWithStdIn(*mockContext.Context, "kind: Deployment", nil)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// The manifests are applied again with each retry
+			require.Len(t, stdIns, test.expectedAttempts)
+			for _, stdIn := range stdIns {
+				require.Equal(t, "kind: Deployment", stdIn)
+			}
+		})
+	}
+}
+
+func Test_Retry_NotRetryableCommand(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	attempts := 0
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl delete")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		attempts++
+		return exec.NewRunResult(1, "", "Error from server (ServiceUnavailable): the server is currently unable "+
+			"to handle the request"), errors.New("exit code: 1")
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.SetRetry(&RetryOptions{InitialDelay: time.Millisecond})
+
+	_, err := cli.Exec(*mockContext.Context, nil, "delete", "deployment", "api")
+	require.ErrorIs(t, err, ErrServerBusy)
+	require.Equal(t, 1, attempts)
+}
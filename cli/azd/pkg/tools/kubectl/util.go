@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/sethvargo/go-retry"
@@ -13,9 +15,119 @@ import (
 
 var (
 	ErrResourceNotFound = errors.New("cannot find resource")
+	ErrApplyConflict    = errors.New("applied fields are owned by other field managers")
 	ErrResourceNotReady = errors.New("resource is not ready")
+	ErrJobFailed        = errors.New("job failed")
 )
 
+const (
+	// The default maximum duration to wait for a k8s resource to be ready
+	DefaultWaitTimeout = 10 * time.Minute
+	// The default interval between polling the k8s resource status
+	DefaultWaitPollInterval = 10 * time.Second
+)
+
+// WaitOptions configures how long and how often a k8s resource is polled until it is ready
+type WaitOptions struct {
+	// The maximum duration to wait for the resource to be ready. Defaults to 10 minutes
+	Timeout time.Duration
+	// The interval between polling the resource status. Defaults to 10 seconds
+	PollInterval time.Duration
+	// The namespace of the resource. Defaults to the namespace of the current context
+	Namespace string
+}
+
+// ResourceTimeoutError is returned when a k8s resource did not become ready within the wait timeout
+type ResourceTimeoutError struct {
+	ResourceType ResourceType
+	// The name of the resource when known
+	ResourceName string
+	Timeout      time.Duration
+	// The pods backing the resource as last observed, used to diagnose why the resource is not ready
+	Pods []Pod
+	Err  error
+}
+
+func (e *ResourceTimeoutError) Error() string {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("timed out after %s waiting for %s", e.Timeout, e.ResourceType))
+	if e.ResourceName != "" {
+		builder.WriteString(fmt.Sprintf(" '%s'", e.ResourceName))
+	}
+
+	builder.WriteString(" to be ready")
+
+	for _, pod := range e.Pods {
+		builder.WriteString(fmt.Sprintf("\n  pod '%s' (%s)", pod.Metadata.Name, pod.Status.Phase))
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Status == "True" {
+				continue
+			}
+
+			builder.WriteString(fmt.Sprintf("\n    condition %s=%s", condition.Type, condition.Status))
+			if condition.Reason != "" {
+				builder.WriteString(fmt.Sprintf(" (%s)", condition.Reason))
+			}
+			if condition.Message != "" {
+				builder.WriteString(fmt.Sprintf(": %s", condition.Message))
+			}
+		}
+
+		for _, container := range pod.Status.ContainerStatuses {
+			state, detail := container.State.describe()
+			if detail == nil {
+				continue
+			}
+
+			builder.WriteString(fmt.Sprintf("\n    container '%s' %s: %s", container.Name, state, detail.Reason))
+			if detail.Message != "" {
+				builder.WriteString(fmt.Sprintf(" - %s", detail.Message))
+			}
+		}
+	}
+
+	return builder.String()
+}
+
+func (e *ResourceTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// JobFailedError is returned when a k8s job failed before completing
+type JobFailedError struct {
+	JobName string
+	// The reason and message of the job 'Failed' condition
+	Reason  string
+	Message string
+	// The last observed logs of the job containers, used to diagnose why the job failed
+	Logs string
+}
+
+func (e *JobFailedError) Error() string {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("job '%s' failed", e.JobName))
+	if e.Reason != "" {
+		builder.WriteString(fmt.Sprintf(" (%s)", e.Reason))
+	}
+	if e.Message != "" {
+		builder.WriteString(fmt.Sprintf(": %s", e.Message))
+	}
+
+	if logs := strings.TrimSpace(e.Logs); logs != "" {
+		builder.WriteString("\n  logs:")
+		for _, line := range strings.Split(logs, "\n") {
+			builder.WriteString(fmt.Sprintf("\n    %s", line))
+		}
+	}
+
+	return builder.String()
+}
+
+func (e *JobFailedError) Unwrap() error {
+	return ErrJobFailed
+}
+
 func GetResource[T any](
 	ctx context.Context,
 	cli *Cli,
@@ -70,21 +182,34 @@ func GetResources[T any](
 		flags.Output = OutputTypeJson
 	}
 
-	res, err := cli.Exec(ctx, flags, "get", string(resourceType))
-	if err != nil {
-		return nil, fmt.Errorf("failed getting resources, %w", err)
+	var output string
+	apiClient := cli.getApiClient()
+	if apiClient != nil && apiClientSupported(flags) && flags.Output == OutputTypeJson {
+		jsonBytes, err := apiClient.List(ctx, resourceType, flags)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting resources, %w", err)
+		}
+
+		output = string(jsonBytes)
+	} else {
+		res, err := cli.Exec(ctx, flags, "get", string(resourceType))
+		if err != nil {
+			return nil, fmt.Errorf("failed getting resources, %w", err)
+		}
+
+		output = res.Stdout
 	}
 
 	var list List[T]
 
 	switch flags.Output {
 	case OutputTypeJson:
-		err = json.Unmarshal([]byte(res.Stdout), &list)
+		err := json.Unmarshal([]byte(output), &list)
 		if err != nil {
 			return nil, fmt.Errorf("failed unmarshalling resources JSON, %w", err)
 		}
 	case OutputTypeYaml:
-		err = yaml.Unmarshal([]byte(res.Stdout), &list)
+		err := yaml.Unmarshal([]byte(output), &list)
 		if err != nil {
 			return nil, fmt.Errorf("failed unmarshalling resources YAML, %w", err)
 		}
@@ -103,14 +228,82 @@ func WaitForResource[T comparable](
 	resourceType ResourceType,
 	resourceFilter ResourceFilterFn[T],
 	readyStatusFilter ResourceFilterFn[T],
+	options *WaitOptions,
+) (T, error) {
+	return waitForResource(ctx, cli, resourceType, resourceFilter, func(resource T) error {
+		if !readyStatusFilter(resource) {
+			return fmt.Errorf("resource '%s' is not ready, %w", resourceType, ErrResourceNotReady)
+		}
+
+		return nil
+	}, options)
+}
+
+// WaitForJob waits until the job with the specified name has completed
+// A JobFailedError is returned as soon as the job has failed instead of waiting for the timeout
+func WaitForJob(ctx context.Context, cli *Cli, jobName string, options *WaitOptions) (*Job, error) {
+	return waitForResource(ctx, cli, ResourceTypeJob,
+		func(job *Job) bool {
+			return job.Metadata.Name == jobName
+		},
+		func(job *Job) error {
+			if failed := job.Condition("Failed"); failed != nil {
+				return &JobFailedError{
+					JobName: jobName,
+					Reason:  failed.Reason,
+					Message: failed.Message,
+				}
+			}
+
+			if job.Condition("Complete") == nil {
+				return fmt.Errorf("job '%s' is not complete, %w", jobName, ErrResourceNotReady)
+			}
+
+			return nil
+		},
+		options,
+	)
+}
+
+// waitForResource waits until the status function returns nil for the resource matching the filter
+// The resources are listed once and then watched, so that status changes are detected as soon as they happen.
+// Watches closed by the API server are reconnected after a jittered delay, listing the resources again.
+// The resources are polled instead when the CLI cannot watch resources, ex) commands using a remote runner.
+// Errors wrapping ErrResourceNotReady are retried until the timeout, all other errors end the wait
+func waitForResource[T comparable](
+	ctx context.Context,
+	cli *Cli,
+	resourceType ResourceType,
+	resourceFilter ResourceFilterFn[T],
+	statusFn func(resource T) error,
+	options *WaitOptions,
 ) (T, error) {
+	timeout := DefaultWaitTimeout
+	pollInterval := DefaultWaitPollInterval
+	namespace := ""
+	if options != nil {
+		if options.Timeout > 0 {
+			timeout = options.Timeout
+		}
+
+		if options.PollInterval > 0 {
+			pollInterval = options.PollInterval
+		}
+
+		namespace = options.Namespace
+	}
+
+	// Watches are bound by the timeout while retries are only checked between attempts
+	watchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	var resource T
 	var zero T
 	err := retry.Do(
 		ctx,
-		retry.WithMaxDuration(time.Minute*10, retry.NewConstant(time.Second*10)),
+		retry.WithMaxDuration(timeout, retry.WithJitterPercent(20, retry.NewConstant(pollInterval))),
 		func(ctx context.Context) error {
-			result, err := GetResources[T](ctx, cli, resourceType, nil)
+			result, err := GetResources[T](ctx, cli, resourceType, &KubeCliFlags{Namespace: namespace})
 
 			if err != nil {
 				return fmt.Errorf("failed waiting for resource, %w", err)
@@ -127,14 +320,71 @@ func WaitForResource[T comparable](
 				return fmt.Errorf("cannot find resource for '%s', %w", resourceType, ErrResourceNotFound)
 			}
 
-			if !readyStatusFilter(resource) {
-				return retry.RetryableError(fmt.Errorf("resource '%s' is not ready, %w", resourceType, ErrResourceNotReady))
+			statusErr := statusFn(resource)
+			if statusErr == nil || !errors.Is(statusErr, ErrResourceNotReady) {
+				return statusErr
 			}
 
-			return nil
+			if !cli.CanWatch() {
+				return retry.RetryableError(statusErr)
+			}
+
+			watchFlags := &KubeCliFlags{Namespace: namespace}
+			var handlerErr error
+			err = cli.Watch(watchCtx, resourceType, watchFlags, func(event WatchEvent) (bool, error) {
+				if event.Type == WatchEventTypeDeleted {
+					return false, nil
+				}
+
+				var changed T
+				if err := json.Unmarshal(event.Object, &changed); err != nil {
+					handlerErr = fmt.Errorf("failed unmarshalling resource JSON, %w", err)
+					return true, handlerErr
+				}
+
+				if changed == zero || !resourceFilter(changed) {
+					return false, nil
+				}
+
+				resource = changed
+				statusErr = statusFn(resource)
+				if statusErr == nil {
+					return true, nil
+				}
+
+				if !errors.Is(statusErr, ErrResourceNotReady) {
+					handlerErr = statusErr
+					return true, handlerErr
+				}
+
+				return false, nil
+			})
+
+			if handlerErr != nil {
+				return handlerErr
+			}
+
+			// Failed watches are reconnected like watches closed by the API server
+			if err != nil {
+				log.Printf("reconnecting watch of '%s', %v", resourceType, err)
+			}
+
+			if statusErr != nil {
+				return retry.RetryableError(statusErr)
+			}
+
+			return statusErr
 		},
 	)
 
+	if errors.Is(err, ErrResourceNotReady) {
+		err = &ResourceTimeoutError{
+			ResourceType: resourceType,
+			Timeout:      timeout,
+			Err:          err,
+		}
+	}
+
 	if err != nil {
 		return zero, fmt.Errorf("failed waiting for resource, %w", err)
 	}
@@ -0,0 +1,276 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WaitForResource(t *testing.T) {
+	createDeployment := func(availableReplicas int) *Deployment {
+		return &Deployment{
+			Resource: Resource{Metadata: ResourceMetadata{Name: "api"}},
+			Spec:     DeploymentSpec{Replicas: 2},
+			Status:   DeploymentStatus{AvailableReplicas: availableReplicas},
+		}
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	lists := 0
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get deployment")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		lists++
+		jsonBytes, _ := json.Marshal(List[*Deployment]{Items: []*Deployment{createDeployment(0)}})
+
+		return exec.NewRunResult(0, string(jsonBytes), ""), nil
+	})
+
+	// The watch streams the status changes of the deployment
+	watches := []exec.RunArgs{}
+	watchEvents := []WatchEvent{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get deployment --watch")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		watches = append(watches, args)
+		encoder := json.NewEncoder(args.StdOut)
+		for _, event := range watchEvents {
+			if err := encoder.Encode(event); err != nil {
+				return exec.NewRunResult(1, "", ""), err
+			}
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+
+	filter := func(deployment *Deployment) bool {
+		return deployment.Metadata.Name == "api"
+	}
+	ready := func(deployment *Deployment) bool {
+		return deployment.Status.AvailableReplicas == deployment.Spec.Replicas
+	}
+
+	deploymentEvent := func(eventType WatchEventType, availableReplicas int) WatchEvent {
+		object, _ := json.Marshal(createDeployment(availableReplicas))
+		return WatchEvent{Type: eventType, Object: object}
+	}
+
+	t.Run("Ready", func(t *testing.T) {
+		lists = 0
+		watches = []exec.RunArgs{}
+		watchEvents = []WatchEvent{
+			deploymentEvent(WatchEventTypeAdded, 0),
+			deploymentEvent(WatchEventTypeModified, 1),
+			deploymentEvent(WatchEventTypeModified, 2),
+		}
+
+		deployment, err := WaitForResource(
+			*mockContext.Context, cli, ResourceTypeDeployment, filter, ready,
+			&WaitOptions{Timeout: time.Minute, PollInterval: time.Hour, Namespace: "test"},
+		)
+		require.NoError(t, err)
+		require.Equal(t, "api", deployment.Metadata.Name)
+		require.Equal(t, 2, deployment.Status.AvailableReplicas)
+
+		// The readiness is detected from the watch without listing the resources again
+		require.Equal(t, 1, lists)
+		require.Len(t, watches, 1)
+		require.Equal(
+			t,
+			[]string{"get", "deployment", "--watch", "--output-watch-events", "-n", "test", "-o", "json"},
+			watches[0].Args,
+		)
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		lists = 0
+		watches = []exec.RunArgs{}
+		watchEvents = []WatchEvent{deploymentEvent(WatchEventTypeModified, 1)}
+
+		_, err := WaitForResource(
+			*mockContext.Context, cli, ResourceTypeDeployment, filter, ready,
+			&WaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond},
+		)
+		require.ErrorIs(t, err, ErrResourceNotReady)
+
+		// Watches closed before the deployment is ready are reconnected after listing the resources again
+		require.Greater(t, len(watches), 1)
+		require.Equal(t, len(watches), lists)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		notReady := func(deployment *Deployment) bool {
+			return false
+		}
+
+		_, err := WaitForResource(
+			*mockContext.Context, cli, ResourceTypeDeployment, filter, notReady,
+			&WaitOptions{Timeout: 10 * time.Millisecond, PollInterval: time.Millisecond},
+		)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrResourceNotReady)
+
+		var timeoutErr *ResourceTimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.Equal(t, ResourceTypeDeployment, timeoutErr.ResourceType)
+		require.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	})
+
+	t.Run("RemoteRunner", func(t *testing.T) {
+		remoteLists := 0
+		remoteRunner := mockexec.NewMockCommandRunner()
+		remoteRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl get deployment")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			remoteLists++
+			jsonBytes, _ := json.Marshal(List[*Deployment]{Items: []*Deployment{createDeployment(min(remoteLists, 2))}})
+
+			return exec.NewRunResult(0, string(jsonBytes), ""), nil
+		})
+
+		watches = []exec.RunArgs{}
+		cli.SetRemoteRunner(remoteRunner)
+		defer cli.SetRemoteRunner(nil)
+
+		// Resources are polled since the remote runner does not stream the watch events
+		_, err := WaitForResource(
+			*mockContext.Context, cli, ResourceTypeDeployment, filter, ready,
+			&WaitOptions{Timeout: time.Minute, PollInterval: time.Millisecond},
+		)
+		require.NoError(t, err)
+		require.Equal(t, 2, remoteLists)
+		require.Empty(t, watches)
+	})
+}
+
+func Test_ResourceTimeoutError(t *testing.T) {
+	err := &ResourceTimeoutError{
+		ResourceType: ResourceTypeDeployment,
+		ResourceName: "api",
+		Timeout:      5 * time.Minute,
+		Pods: []Pod{
+			{
+				Resource: Resource{Metadata: ResourceMetadata{Name: "api-7d9f8b6c5-x2x4z"}},
+				Status: PodStatus{
+					Phase: "Pending",
+					Conditions: []PodCondition{
+						{Type: "PodScheduled", Status: "True"},
+						{Type: "Ready", Status: "False", Reason: "ContainersNotReady", Message: "containers not ready"},
+					},
+					ContainerStatuses: []ContainerStatus{
+						{
+							Name: "api",
+							State: ContainerState{
+								Waiting: &ContainerStateDetail{
+									Reason:  "ImagePullBackOff",
+									Message: "Back-off pulling image",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Err: ErrResourceNotReady,
+	}
+
+	expected := strings.Join([]string{
+		"timed out after 5m0s waiting for deployment 'api' to be ready",
+		"  pod 'api-7d9f8b6c5-x2x4z' (Pending)",
+		"    condition Ready=False (ContainersNotReady): containers not ready",
+		"    container 'api' waiting: ImagePullBackOff - Back-off pulling image",
+	}, "\n")
+
+	require.Equal(t, expected, err.Error())
+	require.ErrorIs(t, err, ErrResourceNotReady)
+}
+
+func Test_WaitForJob(t *testing.T) {
+	tests := map[string]struct {
+		conditions  []JobCondition
+		expectedErr error
+	}{
+		"Complete": {
+			conditions: []JobCondition{{Type: "Complete", Status: "True"}},
+		},
+		"Failed": {
+			conditions: []JobCondition{
+				{
+					Type:    "Failed",
+					Status:  "True",
+					Reason:  "BackoffLimitExceeded",
+					Message: "Job has reached the backoff limit",
+				},
+			},
+			expectedErr: ErrJobFailed,
+		},
+		"Running": {
+			conditions:  []JobCondition{{Type: "Complete", Status: "False"}},
+			expectedErr: ErrResourceNotReady,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl get job")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				list := List[*Job]{
+					Items: []*Job{
+						{
+							Resource: Resource{Metadata: ResourceMetadata{Name: "migrate"}},
+							Status:   JobStatus{Conditions: test.conditions},
+						},
+					},
+				}
+				jsonBytes, _ := json.Marshal(list)
+
+				return exec.NewRunResult(0, string(jsonBytes), ""), nil
+			})
+
+			cli := NewCli(mockContext.CommandRunner)
+			job, err := WaitForJob(
+				*mockContext.Context, cli, "migrate",
+				&WaitOptions{Timeout: 10 * time.Millisecond, PollInterval: time.Millisecond},
+			)
+
+			if test.expectedErr == nil {
+				require.NoError(t, err)
+				require.Equal(t, "migrate", job.Metadata.Name)
+				return
+			}
+
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func Test_JobFailedError(t *testing.T) {
+	err := &JobFailedError{
+		JobName: "migrate",
+		Reason:  "BackoffLimitExceeded",
+		Message: "Job has reached the specified backoff limit",
+		Logs:    "applying migration 001\nerror: relation \"users\" already exists\n",
+	}
+
+	expected := strings.Join([]string{
+		"job 'migrate' failed (BackoffLimitExceeded): Job has reached the specified backoff limit",
+		"  logs:",
+		"    applying migration 001",
+		"    error: relation \"users\" already exists",
+	}, "\n")
+
+	require.Equal(t, expected, err.Error())
+	require.ErrorIs(t, err, ErrJobFailed)
+}
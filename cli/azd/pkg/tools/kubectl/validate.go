@@ -0,0 +1,203 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestError is a validation error of a k8s manifest file
+type ManifestError struct {
+	File string
+	// The line of the document the error was found in, 0 when unknown
+	Line    int
+	Message string
+}
+
+func (e *ManifestError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", e.File, e.Message)
+}
+
+// ManifestValidationError is returned when any of the validated k8s manifests is invalid
+type ManifestValidationError struct {
+	Errors []*ManifestError
+}
+
+func (e *ManifestValidationError) Error() string {
+	builder := strings.Builder{}
+	builder.WriteString("k8s manifests are invalid")
+	for _, err := range e.Errors {
+		builder.WriteString(fmt.Sprintf("\n  %s", err))
+	}
+
+	return builder.String()
+}
+
+// ValidateManifestsOptions configures how the k8s manifests are validated
+type ValidateManifestsOptions struct {
+	// When enabled, environment variable references within the manifests are substituted before the validation
+	Envsubst bool
+	// When enabled, the manifests are also validated against the OpenAPI schema of the cluster
+	// with a server-side dry run that rejects unknown and duplicate fields
+	Server bool
+}
+
+// ValidateManifests validates all k8s manifests within the directory before any of them is applied
+// Each yaml document is parsed and verified to define the apiVersion, kind and name of the resource.
+// A ManifestValidationError is returned with the file and line of each error found.
+func (cli *Cli) ValidateManifests(
+	ctx context.Context,
+	directoryPath string,
+	options *ValidateManifestsOptions,
+	flags *KubeCliFlags,
+) error {
+	if options == nil {
+		options = &ValidateManifestsOptions{}
+	}
+
+	files, err := cli.readManifestFiles(directoryPath, options.Envsubst)
+	if err != nil {
+		return err
+	}
+
+	manifestErrors := []*ManifestError{}
+	for _, file := range files {
+		fileErrors := validateManifest(file)
+		manifestErrors = append(manifestErrors, fileErrors...)
+
+		// Manifests that cannot be parsed would only report the same errors again
+		if !options.Server || len(fileErrors) > 0 {
+			continue
+		}
+
+		if err := cli.validateWithServer(ctx, file, flags); err != nil {
+			manifestErrors = append(manifestErrors, err)
+		}
+	}
+
+	if len(manifestErrors) > 0 {
+		return &ManifestValidationError{Errors: manifestErrors}
+	}
+
+	return nil
+}
+
+// The line reported by yaml syntax errors, ex) yaml: line 12: mapping values are not allowed in this context
+var yamlErrorLineRegex = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// validateManifest parses each yaml document of the manifest and verifies it identifies a k8s resource
+func validateManifest(file manifestFile) []*ManifestError {
+	manifestErrors := []*ManifestError{}
+	decoder := yaml.NewDecoder(strings.NewReader(file.content))
+
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			manifestError := &ManifestError{File: file.path, Message: err.Error()}
+			if matches := yamlErrorLineRegex.FindStringSubmatch(err.Error()); len(matches) > 1 {
+				manifestError.Line, _ = strconv.Atoi(matches[1])
+				manifestError.Message = strings.TrimPrefix(err.Error(), matches[0])
+			}
+
+			// The decoder cannot recover from syntax errors, the remaining documents are skipped
+			return append(manifestErrors, manifestError)
+		}
+
+		if len(document.Content) == 0 {
+			continue
+		}
+
+		// Empty documents, ex) a document with only comments, are ignored like kubectl does
+		root := document.Content[0]
+		if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+			continue
+		}
+
+		if root.Kind != yaml.MappingNode {
+			manifestErrors = append(manifestErrors, &ManifestError{
+				File:    file.path,
+				Line:    root.Line,
+				Message: "document is not a k8s resource",
+			})
+			continue
+		}
+
+		var resource Resource
+		var generateName struct {
+			Metadata struct {
+				GenerateName string `yaml:"generateName"`
+			} `yaml:"metadata"`
+		}
+		if err := root.Decode(&resource); err != nil {
+			manifestErrors = append(manifestErrors, &ManifestError{File: file.path, Line: root.Line, Message: err.Error()})
+			continue
+		}
+
+		_ = root.Decode(&generateName)
+
+		missing := []string{}
+		if resource.ApiVersion == "" {
+			missing = append(missing, "apiVersion")
+		}
+
+		if resource.Kind == "" {
+			missing = append(missing, "kind")
+		}
+
+		// List resources contain their resources as items instead of a name
+		if resource.Metadata.Name == "" && generateName.Metadata.GenerateName == "" &&
+			!strings.HasSuffix(resource.Kind, "List") {
+			missing = append(missing, "metadata.name")
+		}
+
+		if len(missing) > 0 {
+			manifestErrors = append(manifestErrors, &ManifestError{
+				File:    file.path,
+				Line:    root.Line,
+				Message: fmt.Sprintf("missing required field(s) %s", strings.Join(missing, ", ")),
+			})
+		}
+	}
+
+	return manifestErrors
+}
+
+// validateWithServer validates the manifest against the OpenAPI schema of the cluster with a server-side dry run
+func (cli *Cli) validateWithServer(ctx context.Context, file manifestFile, flags *KubeCliFlags) *ManifestError {
+	runArgs := cli.withServerSideApply(exec.
+		NewRunArgs("kubectl", "apply", "-f", "-", "--validate=strict").
+		WithStdIn(strings.NewReader(file.content)))
+
+	validateFlags := &KubeCliFlags{DryRun: DryRunTypeServer}
+	if flags != nil {
+		validateFlags.Namespace = flags.Namespace
+	}
+
+	res, err := cli.executeCommandWithArgs(ctx, runArgs, validateFlags)
+	if err == nil {
+		return nil
+	}
+
+	message := strings.TrimSpace(res.Stderr)
+	if message == "" {
+		message = err.Error()
+	}
+
+	return &ManifestError{File: file.path, Message: message}
+}
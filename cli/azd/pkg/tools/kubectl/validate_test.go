@@ -0,0 +1,100 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateManifests(t *testing.T) {
+	tests := map[string]struct {
+		manifests      map[string]string
+		server         bool
+		serverStderr   string
+		expectedErrors []string
+	}{
+		"Valid": {
+			manifests: map[string]string{
+				"deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n---\n# comment only\n",
+				"job.yaml":        "apiVersion: batch/v1\nkind: Job\nmetadata:\n  generateName: migrate-\n",
+				"readme.md":       "not a manifest",
+			},
+		},
+		"SyntaxError": {
+			manifests: map[string]string{
+				"deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n  labels: app: api\n",
+			},
+			expectedErrors: []string{"deployment.yaml:5: mapping values are not allowed in this context"},
+		},
+		"MissingFields": {
+			manifests: map[string]string{
+				"service.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: api\n" +
+					"---\nkind: ConfigMap\nmetadata: {}\n",
+			},
+			expectedErrors: []string{"service.yaml:6: missing required field(s) apiVersion, metadata.name"},
+		},
+		"Server": {
+			manifests: map[string]string{
+				"deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\nspec:\n  replica: 2\n",
+			},
+			server: true,
+			serverStderr: `Error from server (BadRequest): error when creating "STDIN": Deployment in version "v1" ` +
+				`cannot be handled as a Deployment: strict decoding error: unknown field "spec.replica"`,
+			expectedErrors: []string{`deployment.yaml: Error from server (BadRequest)`, `unknown field "spec.replica"`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for filename, content := range test.manifests {
+				err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), osutil.PermissionFile)
+				require.NoError(t, err)
+			}
+
+			mockContext := mocks.NewMockContext(context.Background())
+			var serverArgs []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply -f - --validate=strict")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				serverArgs = args.Args
+				if test.serverStderr != "" {
+					return exec.NewRunResult(1, "", test.serverStderr), errors.New("exit code: 1")
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			cli := NewCli(mockContext.CommandRunner)
+			err := cli.ValidateManifests(*mockContext.Context, tempDir, &ValidateManifestsOptions{
+				Server: test.server,
+			}, nil)
+
+			if test.server {
+				require.Equal(t, []string{"apply", "-f", "-", "--validate=strict", "--dry-run=server"}, serverArgs)
+			} else {
+				require.Nil(t, serverArgs)
+			}
+
+			if len(test.expectedErrors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *ManifestValidationError
+			require.True(t, errors.As(err, &validationErr))
+			require.Len(t, validationErr.Errors, 1)
+			for _, expectedError := range test.expectedErrors {
+				require.ErrorContains(t, err, expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+type WatchEventType string
+
+const (
+	WatchEventTypeAdded    WatchEventType = "ADDED"
+	WatchEventTypeModified WatchEventType = "MODIFIED"
+	WatchEventTypeDeleted  WatchEventType = "DELETED"
+)
+
+// WatchEvent is a change to a watched k8s resource
+type WatchEvent struct {
+	Type WatchEventType `json:"type"`
+	// The JSON encoded resource
+	Object json.RawMessage `json:"object"`
+}
+
+// WatchHandlerFn handles a watch event. Returning true or an error stops the watch
+type WatchHandlerFn func(event WatchEvent) (bool, error)
+
+// CanWatch returns whether resource changes can be streamed to the client
+// Commands executed through a remote runner only return their output once completed
+func (cli *Cli) CanWatch() bool {
+	return cli.remoteRunner == nil
+}
+
+// Watch streams the changes of the resources of the specified type to the handler, starting with the current resources
+// Returns nil when the handler stopped the watch or when the watch was closed by the API server, in which case the
+// caller is expected to reconnect
+func (cli *Cli) Watch(ctx context.Context, resourceType ResourceType, flags *KubeCliFlags, handler WatchHandlerFn) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if apiClient := cli.getApiClient(); apiClient != nil && apiClientSupported(flags) {
+		return apiClient.Watch(ctx, resourceType, flags, handler)
+	}
+
+	watchFlags := &KubeCliFlags{Output: OutputTypeJson}
+	if flags != nil {
+		watchFlags.Namespace = flags.Namespace
+		watchFlags.LabelSelector = flags.LabelSelector
+	}
+
+	reader, writer := io.Pipe()
+	runArgs := exec.
+		NewRunArgs("kubectl", "get", string(resourceType), "--watch", "--output-watch-events").
+		WithStdOut(writer)
+
+	type runResult struct {
+		res exec.RunResult
+		err error
+	}
+
+	done := make(chan runResult, 1)
+	go func() {
+		res, err := cli.executeCommandWithArgs(ctx, runArgs, watchFlags)
+		writer.Close()
+		done <- runResult{res, err}
+	}()
+
+	streamed, stopped, handlerErr := decodeWatchEvents(reader, handler)
+	// Closing the reader unblocks the command output when the handler stopped the watch
+	reader.Close()
+	cancel()
+	result := <-done
+
+	if handlerErr != nil || stopped {
+		return handlerErr
+	}
+
+	if result.err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed watching resources, %w", result.err)
+	}
+
+	// Command runners that do not stream the output return all events once the command has completed
+	if !streamed && result.res.Stdout != "" {
+		_, _, handlerErr = decodeWatchEvents(strings.NewReader(result.res.Stdout), handler)
+		return handlerErr
+	}
+
+	return nil
+}
+
+// decodeWatchEvents decodes the JSON encoded watch events until the reader is closed or the handler stopped the watch
+// Returns whether any events were decoded and whether the handler stopped the watch
+func decodeWatchEvents(reader io.Reader, handler WatchHandlerFn) (bool, bool, error) {
+	decoder := json.NewDecoder(reader)
+	streamed := false
+	for {
+		var event WatchEvent
+		if err := decoder.Decode(&event); err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrClosedPipe) {
+				log.Printf("failed decoding watch event, %v", err)
+			}
+
+			return streamed, false, nil
+		}
+
+		streamed = true
+		if event.Type == "" || len(event.Object) == 0 {
+			continue
+		}
+
+		stop, err := handler(event)
+		if err != nil || stop {
+			return streamed, true, err
+		}
+	}
+}
+
+// Watch streams the changes of the resources of the specified type to the handler, equivalent to 'kubectl get -w'
+func (c *ApiClient) Watch(
+	ctx context.Context,
+	resourceType ResourceType,
+	flags *KubeCliFlags,
+	handler WatchHandlerFn,
+) error {
+	mapping, err := c.resourceMapping(resourceType)
+	if err != nil {
+		return err
+	}
+
+	listOptions := metav1.ListOptions{}
+	if flags != nil {
+		listOptions.LabelSelector = flags.LabelSelector
+	}
+
+	// Without a resource version the watch starts with ADDED events for the current resources
+	watcher, err := c.resource(mapping, "", flags).Watch(ctx, listOptions)
+	if err != nil {
+		return apiError(err)
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+
+			// The watch is restarted by the caller, ex) when the resource version is too old
+			if event.Type == watch.Error {
+				log.Printf("watch closed by the API server, %v", apierrors.FromObject(event.Object))
+				return nil
+			}
+
+			object, err := json.Marshal(event.Object)
+			if err != nil {
+				return fmt.Errorf("failed marshalling watch event, %w", err)
+			}
+
+			stop, err := handler(WatchEvent{Type: WatchEventType(event.Type), Object: object})
+			if err != nil || stop {
+				return err
+			}
+		}
+	}
+}
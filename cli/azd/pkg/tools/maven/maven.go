@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -199,6 +200,41 @@ func (cli *Cli) Package(ctx context.Context, projectPath string) error {
 	return nil
 }
 
+// The jib maven plugin invoked by its coordinates, so projects build images without configuring the plugin
+const jibMavenPlugin = "com.google.cloud.tools:jib-maven-plugin:3.4.4"
+
+// JibDockerBuild builds the image of the project with jib and loads it into the local docker daemon as imageName
+// The platform, ex) linux/amd64, defaults to the platform of the base image when empty.
+func (cli *Cli) JibDockerBuild(
+	ctx context.Context,
+	projectPath string,
+	imageName string,
+	platform string,
+	buildProgress io.Writer,
+) error {
+	mvnCmd, err := cli.mvnCmd()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"compile", jibMavenPlugin + ":dockerBuild", "-Dimage=" + imageName, "-DskipTests"}
+	if platform != "" {
+		args = append(args, "-Djib.from.platforms="+platform)
+	}
+
+	runArgs := exec.NewRunArgs(mvnCmd, args...).WithCwd(projectPath)
+	if buildProgress != nil {
+		runArgs = runArgs.WithStdOut(buildProgress).WithStdErr(buildProgress)
+	}
+
+	_, err = cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("mvn jib:dockerBuild on project '%s' failed: %w", projectPath, err)
+	}
+
+	return nil
+}
+
 func (cli *Cli) ResolveDependencies(ctx context.Context, projectPath string) error {
 	mvnCmd, err := cli.mvnCmd()
 	if err != nil {
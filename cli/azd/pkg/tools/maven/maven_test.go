@@ -155,3 +155,29 @@ func mvnwWithExt() string {
 		return "mvnw"
 	}
 }
+
+func Test_JibDockerBuild(t *testing.T) {
+	var buildArgs exec.RunArgs
+	execMock := mockexec.NewMockCommandRunner().
+		When(func(a exec.RunArgs, command string) bool { return a.Args[0] == "compile" }).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			buildArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	projectPath := t.TempDir()
+	mvn := NewCli(execMock)
+	mvn.SetPath(projectPath, projectPath)
+	placeExecutable(t, mvnwWithExt(), projectPath)
+
+	err := mvn.JibDockerBuild(context.Background(), projectPath, "todo-api", "linux/amd64", nil)
+	require.NoError(t, err)
+	require.Equal(t, projectPath, buildArgs.Cwd)
+	require.Equal(t, []string{
+		"compile",
+		"com.google.cloud.tools:jib-maven-plugin:3.4.4:dockerBuild",
+		"-Dimage=todo-api",
+		"-DskipTests",
+		"-Djib.from.platforms=linux/amd64",
+	}, buildArgs.Args)
+}
@@ -0,0 +1,112 @@
+package notation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The notation plugin that signs with keys stored in Azure Key Vault
+const AzureKeyVaultPlugin = "azure-kv"
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Cli signs container images with the notation CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// SignOptions configures the signing of a container image with a key stored in Azure Key Vault
+type SignOptions struct {
+	// The id of the signing key, ex) https://contoso.vault.azure.net/keys/signing/0b1c
+	KeyId string
+	// The path of the certificate chain of the signing certificate, empty for self-signed certificates
+	CertificateBundle string
+}
+
+// Sign signs the image reference, ex) contoso.azurecr.io/todo/api@sha256:4f2d..., and pushes the signature to the
+// registry. Returns the reference of the pushed signature, ex) contoso.azurecr.io/todo/api@sha256:9a7e...
+func (cli *Cli) Sign(ctx context.Context, reference string, options SignOptions) (string, error) {
+	if options.KeyId == "" {
+		return "", fmt.Errorf("signing image '%s': no signing key specified", reference)
+	}
+
+	args := []string{
+		"sign", reference,
+		"--signature-format", "cose",
+		"--plugin", AzureKeyVaultPlugin,
+		"--id", options.KeyId,
+	}
+
+	if options.CertificateBundle != "" {
+		args = append(args, "--plugin-config", fmt.Sprintf("ca_certs=%s", options.CertificateBundle))
+	} else {
+		args = append(args, "--plugin-config", "self_signed=true")
+	}
+
+	log.Printf("signing image '%s' with key '%s'", reference, options.KeyId)
+	if _, err := cli.executeCommand(ctx, args...); err != nil {
+		return "", fmt.Errorf("signing image '%s': %w", reference, err)
+	}
+
+	res, err := cli.executeCommand(ctx, "inspect", reference, "--output", "json")
+	if err != nil {
+		return "", fmt.Errorf("inspecting signatures of image '%s': %w", reference, err)
+	}
+
+	var inspectResult struct {
+		Signatures []struct {
+			Digest string `json:"digest"`
+		} `json:"signatures"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &inspectResult); err != nil {
+		return "", fmt.Errorf("inspecting signatures of image '%s': %w", reference, err)
+	}
+
+	if len(inspectResult.Signatures) == 0 {
+		return "", fmt.Errorf("inspecting signatures of image '%s': no signatures found", reference)
+	}
+
+	// The signature is pushed to the repository of the image, the last signature is the one that was just pushed
+	repository, _, _ := strings.Cut(reference, "@")
+	digest := inspectResult.Signatures[len(inspectResult.Signatures)-1].Digest
+
+	return fmt.Sprintf("%s@%s", repository, digest), nil
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("notation"); err != nil {
+		return err
+	}
+
+	version, err := tools.ExecuteCommand(ctx, cli.commandRunner, "notation", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("notation version: %s", version)
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://notaryproject.dev/docs/user-guides/installation/cli/"
+}
+
+func (cli *Cli) Name() string {
+	return "Notation"
+}
+
+func (cli *Cli) executeCommand(ctx context.Context, args ...string) (exec.RunResult, error) {
+	return cli.commandRunner.Run(ctx, exec.NewRunArgs("notation", args...))
+}
@@ -0,0 +1,67 @@
+package notation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Sign(t *testing.T) {
+	tests := map[string]struct {
+		certificateBundle    string
+		expectedPluginConfig string
+	}{
+		"SelfSigned": {
+			expectedPluginConfig: "self_signed=true",
+		},
+		"CertificateBundle": {
+			certificateBundle:    "/certs/ca.pem",
+			expectedPluginConfig: "ca_certs=/certs/ca.pem",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			cli := NewCli(mockContext.CommandRunner)
+
+			var signArgs exec.RunArgs
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "notation sign")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				signArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "notation inspect")
+			}).Respond(exec.NewRunResult(0, `{"signatures":[{"digest":"sha256:1b3c"},{"digest":"sha256:9a7e"}]}`, ""))
+
+			signature, err := cli.Sign(*mockContext.Context, "contoso.azurecr.io/todo/api@sha256:4f2d", SignOptions{
+				KeyId:             "https://contoso.vault.azure.net/keys/signing/0b1c",
+				CertificateBundle: test.certificateBundle,
+			})
+			require.NoError(t, err)
+			require.Equal(t, "contoso.azurecr.io/todo/api@sha256:9a7e", signature)
+			require.Equal(t, []string{
+				"sign", "contoso.azurecr.io/todo/api@sha256:4f2d",
+				"--signature-format", "cose",
+				"--plugin", "azure-kv",
+				"--id", "https://contoso.vault.azure.net/keys/signing/0b1c",
+				"--plugin-config", test.expectedPluginConfig,
+			}, signArgs.Args)
+		})
+	}
+
+	t.Run("NoKey", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		cli := NewCli(mockContext.CommandRunner)
+
+		_, err := cli.Sign(*mockContext.Context, "contoso.azurecr.io/todo/api@sha256:4f2d", SignOptions{})
+		require.ErrorContains(t, err, "no signing key specified")
+	})
+}
@@ -0,0 +1,104 @@
+package oras
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Cli pushes OCI artifacts to container registries with the oras CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// Attach pushes the file as an artifact of the artifact type, ex) application/spdx+json, that refers to the image
+// reference, ex) contoso.azurecr.io/todo/api@sha256:4f2d... Returns the reference of the pushed artifact.
+func (cli *Cli) Attach(ctx context.Context, reference string, artifactType string, filePath string) (string, error) {
+	log.Printf("attaching '%s' to image '%s'", filePath, reference)
+
+	// The file is referenced relative to its directory, so the name of the file is preserved in the artifact and
+	// absolute windows paths aren't mistaken for a media type
+	runArgs := exec.NewRunArgs(
+		"oras", "attach", reference,
+		fmt.Sprintf("%s:%s", filepath.Base(filePath), artifactType),
+		"--artifact-type", artifactType,
+		"--format", "json",
+	).WithCwd(filepath.Dir(filePath))
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf("attaching artifact to image '%s': %w", reference, err)
+	}
+
+	var attachResult struct {
+		Reference string `json:"reference"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &attachResult); err != nil {
+		return "", fmt.Errorf("attaching artifact to image '%s': %w", reference, err)
+	}
+
+	return attachResult.Reference, nil
+}
+
+// Push pushes the file as an artifact of the artifact type to the reference, ex) contoso.azurecr.io/policies/api:v1.
+// Returns the digest of the pushed artifact, ex) sha256:4f2d...
+func (cli *Cli) Push(ctx context.Context, reference string, artifactType string, filePath string) (string, error) {
+	log.Printf("pushing '%s' to '%s'", filePath, reference)
+
+	// The file is referenced relative to its directory, the same as attached artifacts
+	runArgs := exec.NewRunArgs(
+		"oras", "push", reference,
+		filepath.Base(filePath),
+		"--artifact-type", artifactType,
+		"--format", "json",
+	).WithCwd(filepath.Dir(filePath))
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf("pushing artifact '%s': %w", reference, err)
+	}
+
+	var pushResult struct {
+		Digest string `json:"digest"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &pushResult); err != nil {
+		return "", fmt.Errorf("pushing artifact '%s': %w", reference, err)
+	}
+
+	return pushResult.Digest, nil
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("oras"); err != nil {
+		return err
+	}
+
+	version, err := tools.ExecuteCommand(ctx, cli.commandRunner, "oras", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("oras version: %s", version)
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://oras.land/docs/installation"
+}
+
+func (cli *Cli) Name() string {
+	return "ORAS"
+}
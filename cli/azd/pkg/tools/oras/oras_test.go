@@ -0,0 +1,65 @@
+package oras
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Attach(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewCli(mockContext.CommandRunner)
+
+	var attachArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "oras attach")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		attachArgs = args
+		return exec.NewRunResult(0, `{"reference":"contoso.azurecr.io/todo/api@sha256:7c1a"}`, ""), nil
+	})
+
+	sbomPath := filepath.Join("sbom", "api-sbom.spdx.json")
+	reference, err := cli.Attach(
+		*mockContext.Context, "contoso.azurecr.io/todo/api@sha256:4f2d", "application/spdx+json", sbomPath)
+	require.NoError(t, err)
+	require.Equal(t, "contoso.azurecr.io/todo/api@sha256:7c1a", reference)
+	require.Equal(t, "sbom", attachArgs.Cwd)
+	require.Equal(t, []string{
+		"attach", "contoso.azurecr.io/todo/api@sha256:4f2d",
+		"api-sbom.spdx.json:application/spdx+json",
+		"--artifact-type", "application/spdx+json",
+		"--format", "json",
+	}, attachArgs.Args)
+}
+
+func Test_Push(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewCli(mockContext.CommandRunner)
+
+	var pushArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "oras push")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		pushArgs = args
+		return exec.NewRunResult(
+			0, `{"reference":"contoso.azurecr.io/policies/api@sha256:9b3e","digest":"sha256:9b3e"}`, ""), nil
+	})
+
+	digest, err := cli.Push(
+		*mockContext.Context, "contoso.azurecr.io/policies/api:v1", "application/vnd.contoso.policy",
+		filepath.Join("policies", "api.rego"))
+	require.NoError(t, err)
+	require.Equal(t, "sha256:9b3e", digest)
+	require.Equal(t, "policies", pushArgs.Cwd)
+	require.Equal(t, []string{
+		"push", "contoso.azurecr.io/policies/api:v1",
+		"api.rego",
+		"--artifact-type", "application/vnd.contoso.policy",
+		"--format", "json",
+	}, pushArgs.Args)
+}
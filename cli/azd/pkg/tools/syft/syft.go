@@ -0,0 +1,62 @@
+package syft
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Cli generates software bills of materials with the syft CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// GenerateSbom scans the image in the local docker daemon and writes its software bill of materials to the output
+// path in the format, ex) spdx-json or cyclonedx-json
+func (cli *Cli) GenerateSbom(ctx context.Context, imageName string, format string, outputPath string) error {
+	log.Printf("generating %s sbom of image '%s'", format, imageName)
+
+	// The docker: scheme scans the local image instead of pulling it from a registry
+	runArgs := exec.NewRunArgs(
+		"syft", "scan", fmt.Sprintf("docker:%s", imageName),
+		"--output", fmt.Sprintf("%s=%s", format, outputPath),
+	)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("generating sbom of image '%s': %w", imageName, err)
+	}
+
+	return nil
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("syft"); err != nil {
+		return err
+	}
+
+	version, err := tools.ExecuteCommand(ctx, cli.commandRunner, "syft", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("syft version: %s", version)
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://github.com/anchore/syft#installation"
+}
+
+func (cli *Cli) Name() string {
+	return "Syft"
+}
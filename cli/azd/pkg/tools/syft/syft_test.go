@@ -0,0 +1,30 @@
+package syft
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateSbom(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewCli(mockContext.CommandRunner)
+
+	var scanArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "syft scan")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		scanArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	err := cli.GenerateSbom(*mockContext.Context, "todo/api:azd-deploy-1", "spdx-json", "/tmp/api-sbom.spdx.json")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"scan", "docker:todo/api:azd-deploy-1", "--output", "spdx-json=/tmp/api-sbom.spdx.json",
+	}, scanArgs.Args)
+}
@@ -0,0 +1,106 @@
+package trivy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Cli scans container images for vulnerabilities with the trivy CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// Vulnerability is a known vulnerability of a package installed in the image
+type Vulnerability struct {
+	// ex) CVE-2023-5363
+	Id string
+	// ex) libssl3
+	Package          string
+	InstalledVersion string
+	// The version the vulnerability is fixed in, empty when there is no fix yet
+	FixedVersion string
+	// ex) CRITICAL, HIGH, MEDIUM, LOW or UNKNOWN
+	Severity string
+}
+
+// Scan scans the image in the local docker daemon and returns its vulnerabilities
+// Vulnerabilities without a fix are excluded when ignoreUnfixed is set.
+func (cli *Cli) Scan(ctx context.Context, imageName string, ignoreUnfixed bool) ([]Vulnerability, error) {
+	log.Printf("scanning image '%s' for vulnerabilities", imageName)
+
+	args := []string{"image", "--format", "json", "--quiet", "--image-src", "docker"}
+	if ignoreUnfixed {
+		args = append(args, "--ignore-unfixed")
+	}
+	args = append(args, imageName)
+
+	res, err := cli.commandRunner.Run(ctx, exec.NewRunArgs("trivy", args...))
+	if err != nil {
+		return nil, fmt.Errorf("scanning image '%s': %w", imageName, err)
+	}
+
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID  string
+				PkgName          string
+				InstalledVersion string
+				FixedVersion     string
+				Severity         string
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		return nil, fmt.Errorf("reading scan report of image '%s': %w", imageName, err)
+	}
+
+	vulnerabilities := []Vulnerability{}
+	for _, result := range report.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				Id:               vulnerability.VulnerabilityID,
+				Package:          vulnerability.PkgName,
+				InstalledVersion: vulnerability.InstalledVersion,
+				FixedVersion:     vulnerability.FixedVersion,
+				Severity:         vulnerability.Severity,
+			})
+		}
+	}
+
+	return vulnerabilities, nil
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("trivy"); err != nil {
+		return err
+	}
+
+	version, err := tools.ExecuteCommand(ctx, cli.commandRunner, "trivy", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("trivy version: %s", version)
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://aquasecurity.github.io/trivy/latest/getting-started/installation/"
+}
+
+func (cli *Cli) Name() string {
+	return "Trivy"
+}
@@ -0,0 +1,42 @@
+package trivy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Scan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := NewCli(mockContext.CommandRunner)
+
+	var scanArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "trivy image")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		scanArgs = args
+		report := `{"Results":[{"Target":"todo/api (debian 12.1)","Vulnerabilities":[{"VulnerabilityID":"CVE-2023-5363",` +
+			`"PkgName":"libssl3","InstalledVersion":"3.0.9-1","FixedVersion":"3.0.11-1","Severity":"HIGH"}]},` +
+			`{"Target":"app/package-lock.json"}]}`
+		return exec.NewRunResult(0, report, ""), nil
+	})
+
+	vulnerabilities, err := cli.Scan(*mockContext.Context, "todo/api:azd-deploy-1", true)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"image", "--format", "json", "--quiet", "--image-src", "docker", "--ignore-unfixed", "todo/api:azd-deploy-1",
+	}, scanArgs.Args)
+	require.Equal(t, []Vulnerability{
+		{
+			Id:               "CVE-2023-5363",
+			Package:          "libssl3",
+			InstalledVersion: "3.0.9-1",
+			FixedVersion:     "3.0.11-1",
+			Severity:         "HIGH",
+		},
+	}, vulnerabilities)
+}
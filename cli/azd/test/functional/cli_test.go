@@ -1003,6 +1003,8 @@ func assertEnvValuesStored(t *testing.T, env *environment.Environment) {
 
 func envFromAzdRoot(ctx context.Context, azdRootDir string, envName string) (*environment.Environment, error) {
 	azdCtx := azdcontext.NewAzdContextWithDirectory(azdRootDir)
-	localDataStore := environment.NewLocalFileDataStore(azdCtx, config.NewFileConfigManager(config.NewManager()))
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	localDataStore := environment.NewLocalFileDataStore(
+		azdCtx, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 	return localDataStore.Get(ctx, envName)
 }
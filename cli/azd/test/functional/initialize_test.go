@@ -54,7 +54,9 @@ func Test_CommandsAndActions_Initialize(t *testing.T) {
 	// Set environment for commands that require environment.
 	envName := "envname"
 	azdCtx := azdcontext.NewAzdContextWithDirectory(tempDir)
-	localDataStore := environment.NewLocalFileDataStore(azdCtx, config.NewFileConfigManager(config.NewManager()))
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	localDataStore := environment.NewLocalFileDataStore(
+		azdCtx, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 
 	require.NoError(t, err)
 	err = azdCtx.SetProjectState(azdcontext.ProjectState{DefaultEnvironment: envName})
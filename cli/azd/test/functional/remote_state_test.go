@@ -40,11 +40,11 @@ func Test_StorageBlobClient(t *testing.T) {
 
 			// Upload
 			reader := bytes.NewBuffer([]byte(envValues))
-			err := blobClient.Upload(*mockContext.Context, blobPath, reader)
+			_, err := blobClient.Upload(*mockContext.Context, blobPath, reader, nil)
 			require.NoError(t, err)
 
 			// Download
-			downloadReader, err := blobClient.Download(*mockContext.Context, blobPath)
+			downloadReader, _, err := blobClient.Download(*mockContext.Context, blobPath)
 			require.NoError(t, err)
 			require.NotNil(t, downloadReader)
 
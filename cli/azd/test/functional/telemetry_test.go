@@ -396,7 +396,9 @@ func attributesMap(attributes []Attribute) map[attribute.Key]interface{} {
 
 func getEnvSubscriptionId(t *testing.T, dir string, envName string) string {
 	azdCtx := azdcontext.NewAzdContextWithDirectory(dir)
-	localDataStore := environment.NewLocalFileDataStore(azdCtx, config.NewFileConfigManager(config.NewManager()))
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	localDataStore := environment.NewLocalFileDataStore(
+		azdCtx, fileConfigManager, config.NewUserConfigManager(fileConfigManager))
 	env, err := localDataStore.Get(context.Background(), envName)
 	require.NoError(t, err)
 
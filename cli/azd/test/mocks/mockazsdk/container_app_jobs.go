@@ -0,0 +1,168 @@
+package mockazsdk
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+func MockContainerAppJobGet(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	jobName string,
+	job *armappcontainers.Job,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/jobs/%s",
+				subscriptionId,
+				resourceGroup,
+				jobName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.JobsClientGetResponse{
+			Job: *job,
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
+
+func MockContainerAppJobUpdate(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	jobName string,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPatch && strings.HasSuffix(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/jobs/%s",
+				subscriptionId,
+				resourceGroup,
+				jobName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.JobsClientUpdateResponse{}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, response)
+	})
+
+	return mockRequest
+}
+
+func MockContainerAppJobSecretsList(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	jobName string,
+	secrets *armappcontainers.JobSecretsCollection,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/jobs/%s/listSecrets",
+				subscriptionId,
+				resourceGroup,
+				jobName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.JobsClientListSecretsResponse{
+			JobSecretsCollection: *secrets,
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
+
+func MockContainerAppJobStart(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	jobName string,
+	executionName string,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/jobs/%s/start",
+				subscriptionId,
+				resourceGroup,
+				jobName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.JobExecutionBase{
+			Name: &executionName,
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
+
+func MockContainerAppJobExecutionGet(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	jobName string,
+	execution *armappcontainers.JobExecution,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/jobs/%s/executions/%s",
+				subscriptionId,
+				resourceGroup,
+				jobName,
+				*execution.Name,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.ContainerAppsAPIClientJobExecutionResponse{
+			JobExecution: *execution,
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
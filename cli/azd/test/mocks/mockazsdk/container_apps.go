@@ -166,3 +166,185 @@ func MockContainerAppSecretsList(
 
 	return mockRequest
 }
+
+func MockContainerAppRevisionsList(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	revisions []*armappcontainers.Revision,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/containerApps/%s/revisions",
+				subscriptionId,
+				resourceGroup,
+				appName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.ContainerAppsRevisionsClientListRevisionsResponse{
+			RevisionCollection: armappcontainers.RevisionCollection{
+				Value: revisions,
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
+
+func MockContainerAppRevisionDeactivate(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	revisionName string,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/containerApps/%s/revisions/%s/deactivate",
+				subscriptionId,
+				resourceGroup,
+				appName,
+				revisionName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	return mockRequest
+}
+
+func MockContainerAppDaprComponentCreateOrUpdate(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	environmentName string,
+	componentName string,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/managedEnvironments/%s/daprComponents/%s",
+				subscriptionId,
+				resourceGroup,
+				environmentName,
+				componentName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.DaprComponentsClientCreateOrUpdateResponse{}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
+
+func MockContainerAppCustomHostNameAnalysis(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	analysis *armappcontainers.CustomHostnameAnalysisResult,
+) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/containerApps/%s/listCustomHostNameAnalysis",
+				subscriptionId,
+				resourceGroup,
+				appName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappcontainers.ContainerAppsClientListCustomHostNameAnalysisResponse{
+			CustomHostnameAnalysisResult: *analysis,
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+}
+
+func MockContainerAppManagedCertificatesList(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	environmentName string,
+	certificates []*armappcontainers.ManagedCertificate,
+) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/managedEnvironments/%s/managedCertificates",
+				subscriptionId,
+				resourceGroup,
+				environmentName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappcontainers.ManagedCertificatesClientListResponse{
+			ManagedCertificateCollection: armappcontainers.ManagedCertificateCollection{
+				Value: certificates,
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+}
+
+func MockContainerAppManagedCertificateCreateOrUpdate(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	environmentName string,
+	certificateName string,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	certificateId := fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/managedEnvironments/%s/managedCertificates/%s",
+		subscriptionId,
+		resourceGroup,
+		environmentName,
+		certificateName,
+	)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, certificateId)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.ManagedCertificatesClientCreateOrUpdateResponse{
+			ManagedCertificate: armappcontainers.ManagedCertificate{
+				ID: &certificateId,
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}